@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/storage"
+)
+
+// TestAdjustStock_IfMatch は If-Match を指定した在庫調整が承認待ちの場合もバージョンを確認するテスト
+func TestAdjustStock_IfMatch(t *testing.T) {
+	manager := inventory.NewManager(storage.NewMemoryStorage(), nil, zap.NewNop(), &inventory.Config{
+		AdjustmentApprovalThreshold: 1000,
+	})
+	ctx := context.Background()
+	require.NoError(t, manager.CreateItem(ctx, &inventory.Item{ID: "ITEM-001", Name: "テスト商品", UnitCost: 100}))
+	require.NoError(t, manager.CreateLocation(ctx, &inventory.Location{ID: "LOC-001", Name: "ロケーション", IsActive: true}))
+	require.NoError(t, manager.Add(ctx, "ITEM-001", "LOC-001", 100, "INIT"))
+	stock, err := manager.GetStock(ctx, "ITEM-001", "LOC-001")
+	require.NoError(t, err)
+
+	h := NewHandlers(manager, zap.NewNop())
+	tests := []struct {
+		name     string
+		ifMatch  string
+		quantity string
+		expected int
+	}{
+		{name: "古いETagで承認の閾値を超える調整", ifMatch: `"99"`, quantity: "10", expected: http.StatusPreconditionFailed},
+		{name: "古いETagで即時に反映する調整", ifMatch: `"99"`, quantity: "99", expected: http.StatusPreconditionFailed},
+		{name: "現在のETagで承認の閾値を超える調整", ifMatch: stockETag(stock), quantity: "10", expected: http.StatusAccepted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"item_id": "ITEM-001", "location_id": "LOC-001", "new_quantity": ` + tt.quantity + `, "reason_code": "damage"}`
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/inventory/adjust", strings.NewReader(body))
+			req.Header.Set("If-Match", tt.ifMatch)
+			rec := httptest.NewRecorder()
+			h.AdjustStock(rec, req)
+			assert.Equal(t, tt.expected, rec.Code, rec.Body.String())
+		})
+	}
+
+	// 承認待ちの調整は在庫を変更しない
+	stock, err = manager.GetStock(ctx, "ITEM-001", "LOC-001")
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), stock.Quantity)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/auth"
+)
+
+const testJWTSecret = "test-secret"
+
+// testJWT はテスト用に共有鍵で署名したJWTを作成する（scope が空の場合はクレームを含めない）
+func testJWT(t *testing.T, scope string) string {
+	t.Helper()
+
+	claims := map[string]interface{}{"sub": "user-1", "exp": time.Now().Add(time.Hour).Unix()}
+	if scope != "" {
+		claims["scope"] = scope
+	}
+	header, err := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, []byte(testJWTSecret))
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// TestAuthMiddleware_JWTScopes はJWTのスコープの検証のテスト
+func TestAuthMiddleware_JWTScopes(t *testing.T) {
+	verifier, err := auth.NewJWTVerifier(auth.JWTConfig{HMACSecret: testJWTSecret})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		unscoped bool
+		scope    string
+		method   string
+		path     string
+		expected int
+	}{
+		{name: "スコープのないJWTは管理APIを実行できない", method: http.MethodGet, path: "/api/v1/admin/api-keys", expected: http.StatusForbidden},
+		{name: "スコープのないJWTは照会もできない", method: http.MethodGet, path: "/api/v1/items", expected: http.StatusForbidden},
+		{name: "関係のないスコープのみのJWT", scope: "openid profile", method: http.MethodPost, path: "/api/v1/inventory/add", expected: http.StatusForbidden},
+		{name: "readスコープで照会", scope: "inventory:read", method: http.MethodGet, path: "/api/v1/items", expected: http.StatusOK},
+		{name: "readスコープで更新", scope: "inventory:read", method: http.MethodPost, path: "/api/v1/inventory/add", expected: http.StatusForbidden},
+		{name: "writeスコープで管理API", scope: "inventory:write", method: http.MethodGet, path: "/api/v1/admin/api-keys", expected: http.StatusForbidden},
+		{name: "adminスコープで管理API", scope: "inventory:admin", method: http.MethodGet, path: "/api/v1/admin/api-keys", expected: http.StatusOK},
+		{name: "writeスコープでWebhookを登録", scope: "inventory:write", method: http.MethodPost, path: "/api/v1/admin/webhooks", expected: http.StatusForbidden},
+		{name: "adminスコープでWebhookを登録", scope: "inventory:admin", method: http.MethodPost, path: "/api/v1/admin/webhooks", expected: http.StatusOK},
+		{name: "設定で許可した場合はスコープのないJWTも実行できる", unscoped: true, method: http.MethodGet, path: "/api/v1/admin/api-keys", expected: http.StatusOK},
+		{name: "設定で許可してもinventoryのスコープを含むJWTはスコープで判定", unscoped: true, scope: "inventory:read", method: http.MethodGet, path: "/api/v1/admin/api-keys", expected: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandlers(nil, zap.NewNop())
+			h.authenticator = verifier
+			h.unscopedJWT = tt.unscoped
+			handler := h.authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+testJWT(t, tt.scope))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, tt.expected, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/nemonet1337/zaiGoFramework/internal/openapi"
+)
+
+// startedAt is the time the process started, reported by the runtime stats
+// ランタイム統計で報告するプロセスの起動時刻
+var startedAt = time.Now()
+
+func init() {
+	// expvar の標準の memstats・cmdline に加え、ゴルーチン数などを /debug/vars で提供する
+	expvar.Publish("runtime", expvar.Func(func() interface{} {
+		return map[string]interface{}{
+			"go_version":     runtime.Version(),
+			"goroutines":     runtime.NumGoroutine(),
+			"gomaxprocs":     runtime.GOMAXPROCS(0),
+			"num_cpu":        runtime.NumCPU(),
+			"num_cgo_call":   runtime.NumCgoCall(),
+			"uptime_seconds": int64(time.Since(startedAt).Seconds()),
+		}
+	}))
+}
+
+// debugSpecs describes the profiling endpoints mounted when debugging is enabled
+// デバッグを有効にした場合に登録するプロファイリングの操作の説明
+var debugSpecs = map[string]openapi.Spec{
+	"GET /api/v1/admin/debug/pprof/": {Tag: "admin", Summary: "プロファイルの一覧", ContentType: "text/html"},
+	"GET /api/v1/admin/debug/pprof/profile": {
+		Tag:         "admin",
+		Summary:     "CPUプロファイルを取得",
+		Query:       []openapi.Param{{Name: "seconds", Type: "integer", Description: "計測する秒数（既定は30秒）"}},
+		ContentType: "application/octet-stream",
+	},
+	"GET /api/v1/admin/debug/pprof/trace": {
+		Tag:         "admin",
+		Summary:     "実行トレースを取得",
+		Query:       []openapi.Param{{Name: "seconds", Type: "integer", Description: "計測する秒数（既定は1秒）"}},
+		ContentType: "application/octet-stream",
+	},
+	"GET /api/v1/admin/debug/pprof/cmdline": {Tag: "admin", Summary: "起動時のコマンドライン", ContentType: "text/plain"},
+	"GET /api/v1/admin/debug/pprof/symbol":  {Tag: "admin", Summary: "プログラムカウンターのシンボルを照会", ContentType: "text/plain"},
+	"GET /api/v1/admin/debug/pprof/{profile}": {
+		Tag:         "admin",
+		Summary:     "プロファイルを取得（heap・allocs・goroutine・block・mutex・threadcreate）",
+		Query:       []openapi.Param{{Name: "debug", Type: "integer", Description: "1以上の場合はテキスト形式"}, {Name: "gc", Type: "integer", Description: "heap の取得前にGCを実行する場合は1"}},
+		ContentType: "application/octet-stream",
+	},
+	"GET /api/v1/admin/debug/vars": {Tag: "admin", Summary: "ランタイム統計（メモリ・ゴルーチン数など）", ContentType: "application/json"},
+}
+
+// mountDebug registers the pprof and runtime stats endpoints under /admin/debug
+// /admin/debug 以下にpprof・ランタイム統計のエンドポイントを登録
+//
+// 管理API（inventory:admin スコープ）として認証されます。CPUプロファイル・実行トレースは
+// 指定した秒数だけ応答を続けるため、書き込みタイムアウトを延長します。
+func (h *Handlers) mountDebug(api *mux.Router) {
+	if !h.debug {
+		return
+	}
+	api.HandleFunc("/admin/debug/pprof/", pprof.Index).Methods("GET")
+	api.HandleFunc("/admin/debug/pprof/cmdline", pprof.Cmdline).Methods("GET")
+	api.HandleFunc("/admin/debug/pprof/profile", extendWriteDeadline(pprof.Profile, 30*time.Second)).Methods("GET")
+	api.HandleFunc("/admin/debug/pprof/symbol", pprof.Symbol).Methods("GET", "POST")
+	api.HandleFunc("/admin/debug/pprof/trace", extendWriteDeadline(pprof.Trace, time.Second)).Methods("GET")
+	api.HandleFunc("/admin/debug/pprof/{profile}", func(w http.ResponseWriter, r *http.Request) {
+		pprof.Handler(mux.Vars(r)["profile"]).ServeHTTP(w, r)
+	}).Methods("GET")
+	api.Handle("/admin/debug/vars", expvar.Handler()).Methods("GET")
+}
+
+// extendWriteDeadline extends the write deadline by the seconds query parameter
+// seconds クエリパラメータの秒数だけ書き込みの期限を延長
+func extendWriteDeadline(next http.HandlerFunc, defaultDuration time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		duration := defaultDuration
+		if seconds, err := strconv.ParseFloat(r.FormValue("seconds"), 64); err == nil && seconds > 0 {
+			duration = time.Duration(seconds * float64(time.Second))
+		}
+		// 書き込みの期限を設定できないライターの場合は既定のタイムアウトのまま処理する
+		_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(duration + 10*time.Second))
+		next(w, r)
+	}
+}
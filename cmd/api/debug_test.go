@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMountDebug(t *testing.T) {
+	h := NewHandlers(nil, zap.NewNop())
+	router := mux.NewRouter()
+	h.mountDebug(router)
+
+	// 無効の場合は登録しない
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/debug/vars", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	h.debug = true
+	router = mux.NewRouter()
+	h.mountDebug(router)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/debug/vars", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var vars struct {
+		Runtime struct {
+			Goroutines int `json:"goroutines"`
+		} `json:"runtime"`
+		MemStats map[string]interface{} `json:"memstats"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&vars))
+	assert.Positive(t, vars.Runtime.Goroutines)
+	assert.Contains(t, vars.MemStats, "HeapAlloc")
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/debug/pprof/heap?debug=1", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "heap profile")
+}
+
+func TestDebugRequiresAdminScope(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/debug/pprof/heap", nil)
+	assert.Equal(t, "inventory:admin", requiredScope(req))
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// Messages of request body errors - リクエスト本文のエラーメッセージ
+const (
+	emptyBodyMessage      = "リクエスト本文がありません"
+	bodyTooLargeMessage   = "リクエスト本文が上限を超えています"
+	unknownFieldMessage   = "不明な項目が含まれています"
+	fieldTypeMessage      = "項目の型が正しくありません"
+	timeFormatMessage     = "日時はRFC3339形式で指定してください"
+	trailingDataMessage   = "リクエスト本文に複数のJSONが含まれています"
+	invalidRequestMessage = "無効なリクエスト形式です"
+)
+
+func init() {
+	inventory.RegisterMessages(inventory.LanguageEnglish, map[string]string{
+		emptyBodyMessage:    "the request body is empty",
+		bodyTooLargeMessage: "the request body is too large",
+		unknownFieldMessage: "the request contains an unknown field",
+		fieldTypeMessage:    "a field has the wrong type",
+		timeFormatMessage:   "timestamps must be RFC3339",
+		trailingDataMessage: "the request body contains more than one JSON value",
+	})
+}
+
+// requestBodyError describes why a request body was rejected
+// リクエスト本文を拒否した理由
+type requestBodyError struct {
+	status  int
+	message string
+	field   string // 原因の項目（特定できない場合は空）
+	limit   int64  // 本文の上限（413の場合）
+}
+
+func (e *requestBodyError) Error() string {
+	if e.field != "" {
+		return e.message + ": " + e.field
+	}
+	return e.message
+}
+
+// decodeStrict decodes exactly one JSON value into v and rejects fields v does not have
+// JSONの値を一つだけ v にデコードし、v にない項目を拒否する
+//
+// 項目名の誤り（quantity を qty と書くなど）がゼロ値として処理されないよう、不明な項目はエラーにします。
+// 本文が空の場合は io.EOF を、それ以外のエラーは *requestBodyError を返します。
+func decodeStrict(r io.Reader, v interface{}) error {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		if errors.Is(err, io.EOF) {
+			return io.EOF
+		}
+		return bodyError(err)
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return bodyError(err)
+		}
+		return &requestBodyError{status: http.StatusBadRequest, message: trailingDataMessage}
+	}
+	return nil
+}
+
+// bodyError converts a decoding error into the error sent to the client
+// デコードのエラーをクライアントに返すエラーに変換
+func bodyError(err error) *requestBodyError {
+	var (
+		maxBytesErr  *http.MaxBytesError
+		typeErr      *json.UnmarshalTypeError
+		timeErr      *time.ParseError
+		unknownField = "json: unknown field "
+	)
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return &requestBodyError{status: http.StatusRequestEntityTooLarge, message: bodyTooLargeMessage, limit: maxBytesErr.Limit}
+	case strings.HasPrefix(err.Error(), unknownField):
+		field, unquoteErr := strconv.Unquote(strings.TrimPrefix(err.Error(), unknownField))
+		if unquoteErr != nil {
+			field = strings.TrimPrefix(err.Error(), unknownField)
+		}
+		return &requestBodyError{status: http.StatusBadRequest, message: unknownFieldMessage, field: field}
+	case errors.As(err, &typeErr):
+		return &requestBodyError{status: http.StatusBadRequest, message: fieldTypeMessage, field: typeErr.Field}
+	case errors.As(err, &timeErr):
+		return &requestBodyError{status: http.StatusBadRequest, message: timeFormatMessage}
+	default:
+		return &requestBodyError{status: http.StatusBadRequest, message: invalidRequestMessage}
+	}
+}
+
+// decodeJSON decodes the request body into v, sending 400 or 413 when it cannot
+// リクエスト本文を v にデコードし、できない場合は400または413を送信
+func (h *Handlers) decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	err := decodeStrict(r.Body, v)
+	if errors.Is(err, io.EOF) {
+		err = &requestBodyError{status: http.StatusBadRequest, message: emptyBodyMessage}
+	}
+	return h.checkBody(w, err)
+}
+
+// decodeOptionalJSON is decodeJSON for endpoints whose body may be omitted
+// 本文を省略できるエンドポイント用の decodeJSON
+func (h *Handlers) decodeOptionalJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	err := decodeStrict(r.Body, v)
+	if errors.Is(err, io.EOF) {
+		return true
+	}
+	return h.checkBody(w, err)
+}
+
+// checkBody sends the request body error, reporting whether the body was accepted
+// リクエスト本文のエラーを送信し、本文を受け付けたかを返す
+//
+// 原因の項目は data.field、本文の上限は data.limit_bytes で返します。
+func (h *Handlers) checkBody(w http.ResponseWriter, err error) bool {
+	if err == nil {
+		return true
+	}
+	var bodyErr *requestBodyError
+	if !errors.As(err, &bodyErr) {
+		bodyErr = bodyError(err)
+	}
+	data := make(map[string]interface{})
+	if bodyErr.field != "" {
+		data["field"] = bodyErr.field
+	}
+	if bodyErr.limit > 0 {
+		data["limit_bytes"] = bodyErr.limit
+	}
+	if len(data) == 0 {
+		h.sendError(w, bodyErr.status, bodyErr.message)
+		return false
+	}
+	h.sendErrorWithData(w, bodyErr.status, bodyErr.message, data)
+	return false
+}
+
+// importPaths are the CSV import endpoints accepting uploads up to maxImportBytes
+// maxImportBytes までのアップロードを受け付けるCSV取り込みのパス
+var importPaths = map[string]bool{
+	"/import/items":            true,
+	"/import/opening-balances": true,
+}
+
+// bodyLimitMiddleware limits the size of request bodies
+// リクエスト本文のサイズを制限するミドルウェア
+//
+// 上限を超えた本文はデコード時に413になります。CSV取り込みのパス（importPaths）のみ、
+// Content-Type に関係なく上限を maxImportBytes とします（ハンドラーでも同じ上限を設定します）。
+func (h *Handlers) bodyLimitMiddleware(next http.Handler) http.Handler {
+	if h.maxBodyBytes <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit := h.maxBodyBytes
+		if importPaths[apiRelativePath(r.URL.Path)] {
+			limit = maxImportBytes
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// bodyErrorResponse is the body of a 400 or 413 response for a rejected request body
+type bodyErrorResponse struct {
+	Error     string    `json:"error"`
+	ErrorCode ErrorCode `json:"error_code"`
+	Data      struct {
+		Field      string `json:"field"`
+		LimitBytes int64  `json:"limit_bytes"`
+	} `json:"data"`
+}
+
+func TestDecodeJSONRejectsMalformedBodies(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		status  int
+		message string
+		field   string
+	}{
+		{
+			// 項目名の誤りが数量0の操作として処理されない
+			name:    "不明な項目",
+			body:    `{"item_id": "ITEM-001", "location_id": "LOC-001", "qty": 5, "reference": "REF-001"}`,
+			status:  http.StatusBadRequest,
+			message: unknownFieldMessage,
+			field:   "qty",
+		},
+		{
+			name:    "項目の型",
+			body:    `{"item_id": "ITEM-001", "location_id": "LOC-001", "quantity": "5", "reference": "REF-001"}`,
+			status:  http.StatusBadRequest,
+			message: fieldTypeMessage,
+			field:   "quantity",
+		},
+		{
+			name:    "複数のJSON",
+			body:    `{"item_id": "ITEM-001"} {"item_id": "ITEM-002"}`,
+			status:  http.StatusBadRequest,
+			message: trailingDataMessage,
+		},
+		{
+			name:    "空の本文",
+			body:    ``,
+			status:  http.StatusBadRequest,
+			message: emptyBodyMessage,
+		},
+		{
+			name:    "上限を超える本文",
+			body:    `{"item_id": "ITEM-001", "location_id": "LOC-001", "quantity": 5, "reference": "` + strings.Repeat("x", 256) + `"}`,
+			status:  http.StatusRequestEntityTooLarge,
+			message: bodyTooLargeMessage,
+		},
+	}
+
+	h := NewHandlers(nil, zap.NewNop())
+	h.maxBodyBytes = 128
+	handler := h.bodyLimitMiddleware(http.HandlerFunc(h.AddStock))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/inventory/add", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.status, rec.Code)
+			var resp bodyErrorResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, tt.message, resp.Error)
+			assert.Equal(t, tt.field, resp.Data.Field)
+			if tt.status == http.StatusRequestEntityTooLarge {
+				assert.Equal(t, ErrorCodePayloadTooLarge, resp.ErrorCode)
+				assert.Equal(t, int64(128), resp.Data.LimitBytes)
+			}
+		})
+	}
+}
+
+func TestDecodeOptionalJSON(t *testing.T) {
+	h := NewHandlers(nil, zap.NewNop())
+	var req ReplayEventsRequest
+
+	// 本文を省略できる
+	rec := httptest.NewRecorder()
+	assert.True(t, h.decodeOptionalJSON(rec, httptest.NewRequest(http.MethodPost, "/", nil), &req))
+
+	rec = httptest.NewRecorder()
+	assert.False(t, h.decodeOptionalJSON(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"from": "2024-01-01"}`)), &req))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), timeFormatMessage)
+}
+
+func TestBodyLimitImportRoutes(t *testing.T) {
+	h := NewHandlers(nil, zap.NewNop())
+	h.maxBodyBytes = 16
+
+	var limited bool
+	handler := h.bodyLimitMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf := make([]byte, 64)
+		n, _ := r.Body.Read(buf)
+		limited = n < 32
+	}))
+
+	// CSV取り込みのパスは maxImportBytes まで受け付ける
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/import/items", strings.NewReader(strings.Repeat("x", 32)))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=xyz")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.False(t, limited)
+
+	// 他のパスは Content-Type が multipart/form-data でも制限する
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/inventory/add", strings.NewReader(strings.Repeat("x", 32)))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=xyz")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.True(t, limited)
+}
+
+func TestBodyLimitIgnoresMultipartContentType(t *testing.T) {
+	h := NewHandlers(nil, zap.NewNop())
+	h.maxBodyBytes = 128
+	handler := h.bodyLimitMiddleware(http.HandlerFunc(h.AddStock))
+
+	body := `{"item_id": "ITEM-001", "location_id": "LOC-001", "quantity": 5, "reference": "` + strings.Repeat("x", 256) + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/inventory/add", strings.NewReader(body))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=xyz")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	var resp bodyErrorResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, ErrorCodePayloadTooLarge, resp.ErrorCode)
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// ErrorCode is the machine-readable code of an error response
+// エラーレスポンスの機械判読用のコード
+//
+// メッセージ（error）は日本語の説明で変更される可能性があるため、
+// クライアントは error_code で処理を分岐してください。
+type ErrorCode string
+
+const (
+	// 在庫パッケージのエラー
+	ErrorCodeItemNotFound             ErrorCode = "ITEM_NOT_FOUND"
+	ErrorCodeLocationNotFound         ErrorCode = "LOCATION_NOT_FOUND"
+	ErrorCodeStockNotFound            ErrorCode = "STOCK_NOT_FOUND"
+	ErrorCodeTransactionNotFound      ErrorCode = "TRANSACTION_NOT_FOUND"
+	ErrorCodeLotNotFound              ErrorCode = "LOT_NOT_FOUND"
+	ErrorCodeLotStockNotFound         ErrorCode = "LOT_STOCK_NOT_FOUND"
+	ErrorCodeUnitNotFound             ErrorCode = "UNIT_NOT_FOUND"
+	ErrorCodeUnitConversionNotFound   ErrorCode = "UNIT_CONVERSION_NOT_FOUND"
+	ErrorCodeUnitAlreadyExists        ErrorCode = "UNIT_ALREADY_EXISTS"
+	ErrorCodeUnitInUse                ErrorCode = "UNIT_IN_USE"
+	ErrorCodeBOMNotFound              ErrorCode = "BOM_NOT_FOUND"
+	ErrorCodeAssemblyNotFound         ErrorCode = "ASSEMBLY_NOT_FOUND"
+	ErrorCodeItemInUse                ErrorCode = "ITEM_IN_USE"
+	ErrorCodeSupplierNotFound         ErrorCode = "SUPPLIER_NOT_FOUND"
+	ErrorCodeSupplierAlreadyExists    ErrorCode = "SUPPLIER_ALREADY_EXISTS"
+	ErrorCodeSupplierItemNotFound     ErrorCode = "SUPPLIER_ITEM_NOT_FOUND"
+	ErrorCodePurchaseOrderNotFound    ErrorCode = "PURCHASE_ORDER_NOT_FOUND"
+	ErrorCodePurchaseOrderStatus      ErrorCode = "PURCHASE_ORDER_STATUS_CONFLICT"
+	ErrorCodeReceiptExceedsOrder      ErrorCode = "RECEIPT_EXCEEDS_ORDER"
+	ErrorCodeSalesOrderNotFound       ErrorCode = "SALES_ORDER_NOT_FOUND"
+	ErrorCodeSalesOrderStatus         ErrorCode = "SALES_ORDER_STATUS_CONFLICT"
+	ErrorCodeReturnNotFound           ErrorCode = "RETURN_NOT_FOUND"
+	ErrorCodeReturnStatus             ErrorCode = "RETURN_STATUS_CONFLICT"
+	ErrorCodeReturnQuantityExceeded   ErrorCode = "RETURN_QUANTITY_EXCEEDED"
+	ErrorCodeReservationNotFound      ErrorCode = "RESERVATION_NOT_FOUND"
+	ErrorCodeReservationNotActive     ErrorCode = "RESERVATION_NOT_ACTIVE"
+	ErrorCodeBackorderNotFound        ErrorCode = "BACKORDER_NOT_FOUND"
+	ErrorCodeBackorderNotPending      ErrorCode = "BACKORDER_NOT_PENDING"
+	ErrorCodeReorderPointNotFound     ErrorCode = "REORDER_POINT_NOT_FOUND"
+	ErrorCodeAlertRuleNotFound        ErrorCode = "ALERT_RULE_NOT_FOUND"
+	ErrorCodeAlertNotFound            ErrorCode = "ALERT_NOT_FOUND"
+	ErrorCodeAlertNotActive           ErrorCode = "ALERT_NOT_ACTIVE"
+	ErrorCodeAlertAlreadyAcknowledged ErrorCode = "ALERT_ALREADY_ACKNOWLEDGED"
+	ErrorCodeBatchNotFound            ErrorCode = "BATCH_NOT_FOUND"
+	ErrorCodeBatchNotCancellable      ErrorCode = "BATCH_NOT_CANCELLABLE"
+	ErrorCodeBatchQueueFull           ErrorCode = "BATCH_QUEUE_FULL"
+	ErrorCodeSnapshotNotFound         ErrorCode = "SNAPSHOT_NOT_FOUND"
+	ErrorCodeStocktakeNotFound        ErrorCode = "STOCKTAKE_NOT_FOUND"
+	ErrorCodeStocktakeStatus          ErrorCode = "STOCKTAKE_STATUS_CONFLICT"
+	ErrorCodeAdjustmentNotFound       ErrorCode = "ADJUSTMENT_NOT_FOUND"
+	ErrorCodeAdjustmentNotPending     ErrorCode = "ADJUSTMENT_NOT_PENDING"
+	ErrorCodeTransactionNotReversible ErrorCode = "TRANSACTION_NOT_REVERSIBLE"
+	ErrorCodeInspectionNotFound       ErrorCode = "INSPECTION_NOT_FOUND"
+	ErrorCodeInspectionNotQuarantined ErrorCode = "INSPECTION_NOT_QUARANTINED"
+	ErrorCodeTransactionReversed      ErrorCode = "TRANSACTION_ALREADY_REVERSED"
+	ErrorCodeItemAlreadyExists        ErrorCode = "ITEM_ALREADY_EXISTS"
+	ErrorCodeLocationAlreadyExists    ErrorCode = "LOCATION_ALREADY_EXISTS"
+	ErrorCodeInvalidQuantity          ErrorCode = "INVALID_QUANTITY"
+	ErrorCodeInvalidReference         ErrorCode = "INVALID_REFERENCE"
+	ErrorCodeInsufficientStock        ErrorCode = "INSUFFICIENT_STOCK"
+	ErrorCodeInsufficientReservation  ErrorCode = "INSUFFICIENT_RESERVATION"
+	ErrorCodeInsufficientLotStock     ErrorCode = "INSUFFICIENT_LOT_STOCK"
+	ErrorCodeInsufficientConsignment  ErrorCode = "INSUFFICIENT_CONSIGNMENT_STOCK"
+	ErrorCodeConsignmentMismatch      ErrorCode = "CONSIGNMENT_STOCK_MISMATCH"
+	ErrorCodeCapacityExceeded         ErrorCode = "LOCATION_CAPACITY_EXCEEDED"
+	ErrorCodeLotExpired               ErrorCode = "LOT_EXPIRED"
+	ErrorCodeVersionConflict          ErrorCode = "VERSION_CONFLICT"
+	ErrorCodeValidationFailed         ErrorCode = "VALIDATION_FAILED"
+	ErrorCodeBusinessRuleViolation    ErrorCode = "BUSINESS_RULE_VIOLATION"
+	ErrorCodeTimeout                  ErrorCode = "TIMEOUT"
+
+	// ステータスコードに対応する汎用のコード
+	ErrorCodeBadRequest           ErrorCode = "BAD_REQUEST"
+	ErrorCodeUnauthorized         ErrorCode = "UNAUTHORIZED"
+	ErrorCodeForbidden            ErrorCode = "FORBIDDEN"
+	ErrorCodeNotFound             ErrorCode = "NOT_FOUND"
+	ErrorCodeConflict             ErrorCode = "CONFLICT"
+	ErrorCodePreconditionFailed   ErrorCode = "PRECONDITION_FAILED"
+	ErrorCodePayloadTooLarge      ErrorCode = "PAYLOAD_TOO_LARGE"
+	ErrorCodeUnsupportedMediaType ErrorCode = "UNSUPPORTED_MEDIA_TYPE"
+	ErrorCodeUnprocessable        ErrorCode = "UNPROCESSABLE_ENTITY"
+	ErrorCodeRateLimited          ErrorCode = "RATE_LIMITED"
+	ErrorCodeInternal             ErrorCode = "INTERNAL_ERROR"
+	ErrorCodeNotImplemented       ErrorCode = "NOT_IMPLEMENTED"
+	ErrorCodeUpstreamFailed       ErrorCode = "UPSTREAM_FAILED"
+	ErrorCodeServiceUnavailable   ErrorCode = "SERVICE_UNAVAILABLE"
+	ErrorCodeGone                 ErrorCode = "GONE"
+)
+
+// sentinelErrors maps the inventory sentinel errors to HTTP statuses and error codes
+// 在庫パッケージのエラーとHTTPステータス・エラーコードの対応
+var sentinelErrors = []struct {
+	err    error
+	status int
+	code   ErrorCode
+}{
+	{inventory.ErrItemNotFound, http.StatusNotFound, ErrorCodeItemNotFound},
+	{inventory.ErrLocationNotFound, http.StatusNotFound, ErrorCodeLocationNotFound},
+	{inventory.ErrStockNotFound, http.StatusNotFound, ErrorCodeStockNotFound},
+	{inventory.ErrTransactionNotFound, http.StatusNotFound, ErrorCodeTransactionNotFound},
+	{inventory.ErrLotNotFound, http.StatusNotFound, ErrorCodeLotNotFound},
+	{inventory.ErrLotStockNotFound, http.StatusNotFound, ErrorCodeLotStockNotFound},
+	{inventory.ErrUnitNotFound, http.StatusNotFound, ErrorCodeUnitNotFound},
+	{inventory.ErrUnitConversionNotFound, http.StatusNotFound, ErrorCodeUnitConversionNotFound},
+	{inventory.ErrBOMNotFound, http.StatusNotFound, ErrorCodeBOMNotFound},
+	{inventory.ErrAssemblyNotFound, http.StatusNotFound, ErrorCodeAssemblyNotFound},
+	{inventory.ErrSupplierNotFound, http.StatusNotFound, ErrorCodeSupplierNotFound},
+	{inventory.ErrSupplierItemNotFound, http.StatusNotFound, ErrorCodeSupplierItemNotFound},
+	{inventory.ErrPurchaseOrderNotFound, http.StatusNotFound, ErrorCodePurchaseOrderNotFound},
+	{inventory.ErrSalesOrderNotFound, http.StatusNotFound, ErrorCodeSalesOrderNotFound},
+	{inventory.ErrReturnNotFound, http.StatusNotFound, ErrorCodeReturnNotFound},
+	{inventory.ErrReservationNotFound, http.StatusNotFound, ErrorCodeReservationNotFound},
+	{inventory.ErrBackorderNotFound, http.StatusNotFound, ErrorCodeBackorderNotFound},
+	{inventory.ErrReorderPointNotFound, http.StatusNotFound, ErrorCodeReorderPointNotFound},
+	{inventory.ErrAlertRuleNotFound, http.StatusNotFound, ErrorCodeAlertRuleNotFound},
+	{inventory.ErrAlertNotFound, http.StatusNotFound, ErrorCodeAlertNotFound},
+	{inventory.ErrBatchNotFound, http.StatusNotFound, ErrorCodeBatchNotFound},
+	{inventory.ErrSnapshotNotFound, http.StatusNotFound, ErrorCodeSnapshotNotFound},
+	{inventory.ErrStocktakeNotFound, http.StatusNotFound, ErrorCodeStocktakeNotFound},
+	{inventory.ErrAdjustmentNotFound, http.StatusNotFound, ErrorCodeAdjustmentNotFound},
+	{inventory.ErrInspectionNotFound, http.StatusNotFound, ErrorCodeInspectionNotFound},
+	{inventory.ErrDuplicateItem, http.StatusConflict, ErrorCodeItemAlreadyExists},
+	{inventory.ErrDuplicateLocation, http.StatusConflict, ErrorCodeLocationAlreadyExists},
+	{inventory.ErrDuplicateUnit, http.StatusConflict, ErrorCodeUnitAlreadyExists},
+	{inventory.ErrUnitInUse, http.StatusConflict, ErrorCodeUnitInUse},
+	{inventory.ErrItemInUse, http.StatusConflict, ErrorCodeItemInUse},
+	{inventory.ErrDuplicateSupplier, http.StatusConflict, ErrorCodeSupplierAlreadyExists},
+	{inventory.ErrNegativeQuantity, http.StatusBadRequest, ErrorCodeInvalidQuantity},
+	{inventory.ErrInvalidReference, http.StatusBadRequest, ErrorCodeInvalidReference},
+	{inventory.ErrInsufficientStock, http.StatusUnprocessableEntity, ErrorCodeInsufficientStock},
+	{inventory.ErrInsufficientReservation, http.StatusUnprocessableEntity, ErrorCodeInsufficientReservation},
+	{inventory.ErrInsufficientLotStock, http.StatusUnprocessableEntity, ErrorCodeInsufficientLotStock},
+	{inventory.ErrInsufficientConsignmentStock, http.StatusUnprocessableEntity, ErrorCodeInsufficientConsignment},
+	{inventory.ErrLocationCapacityExceeded, http.StatusUnprocessableEntity, ErrorCodeCapacityExceeded},
+	{inventory.ErrExpiredLot, http.StatusUnprocessableEntity, ErrorCodeLotExpired},
+	{inventory.ErrTransactionNotReversible, http.StatusUnprocessableEntity, ErrorCodeTransactionNotReversible},
+	{inventory.ErrReceiptExceedsOrder, http.StatusUnprocessableEntity, ErrorCodeReceiptExceedsOrder},
+	{inventory.ErrReturnQuantityExceeded, http.StatusUnprocessableEntity, ErrorCodeReturnQuantityExceeded},
+	{inventory.ErrPreconditionFailed, http.StatusPreconditionFailed, ErrorCodePreconditionFailed},
+	{inventory.ErrVersionMismatch, http.StatusConflict, ErrorCodeVersionConflict},
+	{inventory.ErrReservationNotActive, http.StatusConflict, ErrorCodeReservationNotActive},
+	{inventory.ErrBackorderNotPending, http.StatusConflict, ErrorCodeBackorderNotPending},
+	{inventory.ErrAlertNotActive, http.StatusConflict, ErrorCodeAlertNotActive},
+	{inventory.ErrAlertAlreadyAcknowledged, http.StatusConflict, ErrorCodeAlertAlreadyAcknowledged},
+	{inventory.ErrBatchNotCancellable, http.StatusConflict, ErrorCodeBatchNotCancellable},
+	{inventory.ErrStocktakeStatus, http.StatusConflict, ErrorCodeStocktakeStatus},
+	{inventory.ErrPurchaseOrderStatus, http.StatusConflict, ErrorCodePurchaseOrderStatus},
+	{inventory.ErrSalesOrderStatus, http.StatusConflict, ErrorCodeSalesOrderStatus},
+	{inventory.ErrReturnStatus, http.StatusConflict, ErrorCodeReturnStatus},
+	{inventory.ErrAdjustmentNotPending, http.StatusConflict, ErrorCodeAdjustmentNotPending},
+	{inventory.ErrTransactionAlreadyReversed, http.StatusConflict, ErrorCodeTransactionReversed},
+	{inventory.ErrInspectionNotQuarantined, http.StatusConflict, ErrorCodeInspectionNotQuarantined},
+	{inventory.ErrConsignmentStockMismatch, http.StatusConflict, ErrorCodeConsignmentMismatch},
+	{inventory.ErrBatchQueueFull, http.StatusServiceUnavailable, ErrorCodeBatchQueueFull},
+	{inventory.ErrBatchQueueClosed, http.StatusServiceUnavailable, ErrorCodeServiceUnavailable},
+}
+
+// errorStatus returns the HTTP status and error code for an error returned by the managers
+// マネージャーが返したエラーのHTTPステータスとエラーコードを返す
+func errorStatus(err error) (int, ErrorCode) {
+	for _, sentinel := range sentinelErrors {
+		if errors.Is(err, sentinel.err) {
+			return sentinel.status, sentinel.code
+		}
+	}
+
+	var validationErr *inventory.ValidationError
+	var businessErr *inventory.BusinessRuleError
+	var concurrencyErr *inventory.ConcurrencyError
+	switch {
+	case errors.As(err, &validationErr):
+		return http.StatusUnprocessableEntity, ErrorCodeValidationFailed
+	case errors.As(err, &businessErr):
+		return http.StatusUnprocessableEntity, ErrorCodeBusinessRuleViolation
+	case errors.As(err, &concurrencyErr):
+		return http.StatusConflict, ErrorCodeVersionConflict
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, ErrorCodeTimeout
+	default:
+		return http.StatusInternalServerError, ErrorCodeInternal
+	}
+}
+
+// statusErrorCode returns the generic error code of an HTTP status
+// HTTPステータスに対応する汎用のエラーコードを返す
+func statusErrorCode(status int) ErrorCode {
+	switch status {
+	case http.StatusBadRequest:
+		return ErrorCodeBadRequest
+	case http.StatusUnauthorized:
+		return ErrorCodeUnauthorized
+	case http.StatusForbidden:
+		return ErrorCodeForbidden
+	case http.StatusNotFound:
+		return ErrorCodeNotFound
+	case http.StatusConflict:
+		return ErrorCodeConflict
+	case http.StatusGone:
+		return ErrorCodeGone
+	case http.StatusPreconditionFailed:
+		return ErrorCodePreconditionFailed
+	case http.StatusRequestEntityTooLarge:
+		return ErrorCodePayloadTooLarge
+	case http.StatusUnsupportedMediaType:
+		return ErrorCodeUnsupportedMediaType
+	case http.StatusUnprocessableEntity:
+		return ErrorCodeUnprocessable
+	case http.StatusTooManyRequests:
+		return ErrorCodeRateLimited
+	case http.StatusNotImplemented:
+		return ErrorCodeNotImplemented
+	case http.StatusBadGateway:
+		return ErrorCodeUpstreamFailed
+	case http.StatusServiceUnavailable:
+		return ErrorCodeServiceUnavailable
+	case http.StatusGatewayTimeout:
+		return ErrorCodeTimeout
+	default:
+		return ErrorCodeInternal
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// TestErrorStatus はマネージャーのエラーとHTTPステータス・エラーコードの対応のテスト
+func TestErrorStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   ErrorCode
+	}{
+		{name: "商品なし", err: inventory.ErrItemNotFound, status: http.StatusNotFound, code: ErrorCodeItemNotFound},
+		{name: "ラップしたエラー", err: fmt.Errorf("取得に失敗しました: %w", inventory.ErrLocationNotFound), status: http.StatusNotFound, code: ErrorCodeLocationNotFound},
+		{name: "重複", err: inventory.ErrDuplicateItem, status: http.StatusConflict, code: ErrorCodeItemAlreadyExists},
+		{name: "負の数量", err: inventory.ErrNegativeQuantity, status: http.StatusBadRequest, code: ErrorCodeInvalidQuantity},
+		{name: "在庫不足", err: inventory.ErrInsufficientStock, status: http.StatusUnprocessableEntity, code: ErrorCodeInsufficientStock},
+		{name: "前提条件", err: inventory.ErrPreconditionFailed, status: http.StatusPreconditionFailed, code: ErrorCodePreconditionFailed},
+		{name: "バージョン不一致", err: inventory.ErrVersionMismatch, status: http.StatusConflict, code: ErrorCodeVersionConflict},
+		{name: "委託在庫の不整合", err: inventory.ErrConsignmentStockMismatch, status: http.StatusConflict, code: ErrorCodeConsignmentMismatch},
+		{name: "キューが満杯", err: inventory.ErrBatchQueueFull, status: http.StatusServiceUnavailable, code: ErrorCodeBatchQueueFull},
+		{name: "キューの停止", err: inventory.ErrBatchQueueClosed, status: http.StatusServiceUnavailable, code: ErrorCodeServiceUnavailable},
+		{name: "入力検証", err: inventory.NewValidationError("quantity", "数量が不正です", "-1"), status: http.StatusUnprocessableEntity, code: ErrorCodeValidationFailed},
+		{name: "業務ルール", err: inventory.NewBusinessRuleError("rule", "違反しました", ""), status: http.StatusUnprocessableEntity, code: ErrorCodeBusinessRuleViolation},
+		{name: "競合", err: inventory.NewConcurrencyError("Add", "stock", "競合しました"), status: http.StatusConflict, code: ErrorCodeVersionConflict},
+		{name: "タイムアウト", err: fmt.Errorf("在庫取得に失敗しました: %w", context.DeadlineExceeded), status: http.StatusGatewayTimeout, code: ErrorCodeTimeout},
+		{name: "その他", err: errors.New("接続が切断されました"), status: http.StatusInternalServerError, code: ErrorCodeInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, code := errorStatus(tt.err)
+			assert.Equal(t, tt.status, status)
+			assert.Equal(t, tt.code, code)
+		})
+	}
+
+	// 表の全てのエラーが先に並ぶ別のエラーに一致せず、自身のステータスとコードになること
+	for _, sentinel := range sentinelErrors {
+		status, code := errorStatus(fmt.Errorf("wrapped: %w", sentinel.err))
+		assert.Equal(t, sentinel.status, status, sentinel.err.Error())
+		assert.Equal(t, sentinel.code, code, sentinel.err.Error())
+	}
+}
+
+// TestStatusErrorCode はHTTPステータスと汎用のエラーコードの対応のテスト
+func TestStatusErrorCode(t *testing.T) {
+	tests := []struct {
+		status int
+		code   ErrorCode
+	}{
+		{http.StatusBadRequest, ErrorCodeBadRequest},
+		{http.StatusUnauthorized, ErrorCodeUnauthorized},
+		{http.StatusForbidden, ErrorCodeForbidden},
+		{http.StatusNotFound, ErrorCodeNotFound},
+		{http.StatusConflict, ErrorCodeConflict},
+		{http.StatusGone, ErrorCodeGone},
+		{http.StatusPreconditionFailed, ErrorCodePreconditionFailed},
+		{http.StatusRequestEntityTooLarge, ErrorCodePayloadTooLarge},
+		{http.StatusUnsupportedMediaType, ErrorCodeUnsupportedMediaType},
+		{http.StatusUnprocessableEntity, ErrorCodeUnprocessable},
+		{http.StatusTooManyRequests, ErrorCodeRateLimited},
+		{http.StatusNotImplemented, ErrorCodeNotImplemented},
+		{http.StatusBadGateway, ErrorCodeUpstreamFailed},
+		{http.StatusServiceUnavailable, ErrorCodeServiceUnavailable},
+		{http.StatusGatewayTimeout, ErrorCodeTimeout},
+		{http.StatusInternalServerError, ErrorCodeInternal},
+		{http.StatusTeapot, ErrorCodeInternal},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.code, statusErrorCode(tt.status), "status %d", tt.status)
+	}
+}
@@ -1,24 +1,410 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"mime"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
 
+	"github.com/nemonet1337/zaiGoFramework/internal/label"
+	"github.com/nemonet1337/zaiGoFramework/internal/mergepatch"
+	"github.com/nemonet1337/zaiGoFramework/internal/spreadsheet"
 	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/auth"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/consumer"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/csvimport"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/events"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/graphql"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/metrics"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/scheduler"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/webhooks"
 )
 
+// stockStreamFlushSize is the number of stock rows written between flushes
+// ストリーミング送信時にフラッシュする行数の間隔
+const stockStreamFlushSize = 500
+
+// maxImportBytes is the maximum size of an uploaded CSV import request
+// CSV取り込みのリクエストの最大サイズ
+const maxImportBytes = 10 << 20
+
+// historyExportPageSize is the number of transactions read per page when exporting history
+// 履歴のエクスポートで1回に読み取るトランザクション数
+const historyExportPageSize = 500
+
+var (
+	// stockExportColumns are the header of stock exports
+	// 在庫のエクスポートのヘッダー
+	stockExportColumns = []interface{}{"item_id", "location_id", "quantity", "reserved", "available", "version", "updated_at", "updated_by"}
+
+	// historyExportColumns are the header of transaction history exports
+	// 履歴のエクスポートのヘッダー
+	historyExportColumns = []interface{}{"id", "type", "item_id", "from_location", "to_location", "quantity", "unit_cost", "reference", "lot_number", "expiry_date", "metadata", "created_at", "created_by"}
+)
+
+const (
+	// liveBufferSize is the number of undelivered events buffered per live connection
+	// ライブ配信の接続ごとに保持する未送信イベント数（超えた接続は切断）
+	liveBufferSize = 256
+
+	// livePingInterval is the keepalive interval of live connections
+	// ライブ配信の接続を維持するための送信間隔
+	livePingInterval = 30 * time.Second
+
+	// liveWriteTimeout is the timeout for writing to a live connection
+	// ライブ配信の書き込みタイムアウト
+	liveWriteTimeout = 10 * time.Second
+)
+
+// WebhookRequest represents request to create or update a webhook subscription
+// Webhookサブスクリプションの作成・更新リクエストを表現
+type WebhookRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Secret     string   `json:"secret"` // 空の場合は作成時に生成、更新時は変更しない
+	Active     *bool    `json:"active"` // 省略時は有効
+}
+
+// ReservationRequest represents request to reserve stock or release a reservation
+// 在庫予約・予約解除リクエストを表現
+type ReservationRequest struct {
+	ItemID     string `json:"item_id"`
+	LocationID string `json:"location_id"`
+	Quantity   int64  `json:"quantity"`
+	Reference  string `json:"reference"`
+}
+
+// CreateReservationRequest represents request to create a reservation
+// 予約作成リクエストを表現
+type CreateReservationRequest struct {
+	ItemID     string     `json:"item_id"`
+	LocationID string     `json:"location_id"`
+	Quantity   int64      `json:"quantity"`
+	Reference  string     `json:"reference"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"` // 有効期限（省略した場合は期限なし）
+	Channel    string     `json:"channel"`              // 販売チャネル（省略した場合は共有の在庫のみから予約）
+}
+
+// FulfillReservationRequest represents request to ship the stock of a reservation
+// 予約の出庫リクエストを表現（本文は省略可能）
+type FulfillReservationRequest struct {
+	Reference string `json:"reference"` // 出庫の参照番号（省略した場合は予約の参照番号）
+}
+
+// AllocateBackordersRequest represents request to allocate stock to pending backorders
+// バックオーダー引当リクエストを表現
+type AllocateBackordersRequest struct {
+	ItemID     string `json:"item_id"`
+	LocationID string `json:"location_id"`
+}
+
+// SetUnitConversionRequest represents request to set the conversion of an item's unit
+// 商品の単位の換算の設定リクエストを表現
+type SetUnitConversionRequest struct {
+	Factor float64 `json:"factor"` // 1単位あたりの基本単位の数量（例: 1箱 = 12個 なら 12）
+}
+
+// SetBillOfMaterialsRequest represents request to set the bill of materials of a kit
+// キットの部品表の設定リクエストを表現
+type SetBillOfMaterialsRequest struct {
+	Components []inventory.BOMComponent `json:"components"` // キット1単位あたりの構成品の数量
+}
+
+// AssemblyRequest represents request to assemble or disassemble kits
+// キットの組立・分解リクエストを表現
+type AssemblyRequest struct {
+	KitItemID  string `json:"kit_item_id"`
+	LocationID string `json:"location_id"`
+	Quantity   int64  `json:"quantity"`
+	Reference  string `json:"reference"`
+}
+
+// SupplierRequest represents request to create or update a supplier
+// 仕入先の作成・更新リクエストを表現
+type SupplierRequest struct {
+	ID          string `json:"id"` // 仕入先ID（更新時はパスのIDを使用）
+	Name        string `json:"name"`
+	ContactName string `json:"contact_name"`
+	Email       string `json:"email"`
+	Phone       string `json:"phone"`
+	Address     string `json:"address"`
+	IsActive    *bool  `json:"is_active"` // 省略した場合はアクティブ
+}
+
+// supplier converts the request to a supplier
+// リクエストを仕入先に変換
+func (req *SupplierRequest) supplier(supplierID string) *inventory.Supplier {
+	supplier := &inventory.Supplier{
+		ID:          supplierID,
+		Name:        req.Name,
+		ContactName: req.ContactName,
+		Email:       req.Email,
+		Phone:       req.Phone,
+		Address:     req.Address,
+		IsActive:    true,
+	}
+	if req.IsActive != nil {
+		supplier.IsActive = *req.IsActive
+	}
+	return supplier
+}
+
+// SetSupplierItemRequest represents request to set the purchasing terms of an item from a supplier
+// 仕入先の商品の仕入条件の設定リクエストを表現
+type SetSupplierItemRequest struct {
+	SupplierSKU      string  `json:"supplier_sku"`       // 仕入先の品番
+	UnitCost         float64 `json:"unit_cost"`          // 仕入単価
+	LeadTimeDays     int     `json:"lead_time_days"`     // 発注から入荷までの日数
+	MinOrderQuantity int64   `json:"min_order_quantity"` // 最小発注数量（0の場合は制限なし）
+	IsPreferred      bool    `json:"is_preferred"`       // 優先仕入先
+}
+
+// CreatePurchaseOrderRequest represents request to create a purchase order
+// 発注の作成リクエストを表現
+type CreatePurchaseOrderRequest struct {
+	SupplierID string                        `json:"supplier_id"`
+	LocationID string                        `json:"location_id"` // 入荷先のロケーションID
+	Reference  string                        `json:"reference"`   // 発注書番号（省略した場合は発注ID）
+	ExpectedAt *time.Time                    `json:"expected_at"` // 入荷予定日
+	Note       string                        `json:"note"`
+	Lines      []inventory.PurchaseOrderLine `json:"lines"` // item_id・quantity・unit_cost（0の場合は仕入単価）
+}
+
+// purchaseOrder converts the request to a purchase order
+// リクエストを発注に変換
+func (req *CreatePurchaseOrderRequest) purchaseOrder() *inventory.PurchaseOrder {
+	return &inventory.PurchaseOrder{
+		SupplierID: req.SupplierID,
+		LocationID: req.LocationID,
+		Reference:  req.Reference,
+		ExpectedAt: req.ExpectedAt,
+		Note:       req.Note,
+		Lines:      req.Lines,
+	}
+}
+
+// ReceivePurchaseOrderRequest represents request to record stock received against a purchase order
+// 発注に対する入荷の記録リクエストを表現
+type ReceivePurchaseOrderRequest struct {
+	Reference string                       `json:"reference"` // 納品書番号など
+	Lines     []inventory.GoodsReceiptLine `json:"lines"`     // item_id・quantity・unit_cost・lot_number・expiry_date
+}
+
+// CreateSalesOrderRequest represents request to create a sales order
+// 受注の作成リクエストを表現
+type CreateSalesOrderRequest struct {
+	LocationID string                     `json:"location_id"` // 出荷元のロケーションID
+	Reference  string                     `json:"reference"`   // 注文番号（省略した場合は受注ID）
+	Customer   string                     `json:"customer"`
+	Note       string                     `json:"note"`
+	Channel    string                     `json:"channel"` // 販売チャネル（省略した場合は共有の在庫のみから引当）
+	Lines      []inventory.SalesOrderLine `json:"lines"`   // item_id・quantity
+}
+
+// salesOrder converts the request to a sales order
+// リクエストを受注に変換
+func (req *CreateSalesOrderRequest) salesOrder() *inventory.SalesOrder {
+	return &inventory.SalesOrder{
+		LocationID: req.LocationID,
+		Reference:  req.Reference,
+		Customer:   req.Customer,
+		Note:       req.Note,
+		Channel:    req.Channel,
+		Lines:      req.Lines,
+	}
+}
+
+// AllocateSalesOrderRequest represents request to allocate stock to a sales order
+// 受注の引当リクエストを表現（本文は省略可）
+type AllocateSalesOrderRequest struct {
+	AllowPartial bool `json:"allow_partial"` // 利用可能な数量だけ引き当てる
+}
+
+// ShipSalesOrderRequest represents request to confirm the shipment of a sales order
+// 受注の出荷確定リクエストを表現（本文は省略可）
+type ShipSalesOrderRequest struct {
+	Lines []inventory.ShipmentLine `json:"lines"` // 商品ごとの出荷数量（省略した場合は引当済みの数量を全て出荷）
+}
+
+// CreateReturnRequest represents request to create a return (RMA)
+// 返品（RMA）の作成リクエストを表現
+type CreateReturnRequest struct {
+	LocationID   string                 `json:"location_id"`    // 返品を受け入れるロケーションID
+	Reference    string                 `json:"reference"`      // RMA番号（省略した場合は返品ID）
+	SalesOrderID string                 `json:"sales_order_id"` // 返品元の受注ID（任意）
+	Customer     string                 `json:"customer"`
+	Reason       string                 `json:"reason"`
+	Note         string                 `json:"note"`
+	Lines        []inventory.ReturnLine `json:"lines"` // item_id・quantity
+}
+
+// returnAuthorization converts the request to a return
+// リクエストを返品に変換
+func (req *CreateReturnRequest) returnAuthorization() *inventory.ReturnAuthorization {
+	return &inventory.ReturnAuthorization{
+		LocationID:   req.LocationID,
+		Reference:    req.Reference,
+		SalesOrderID: req.SalesOrderID,
+		Customer:     req.Customer,
+		Reason:       req.Reason,
+		Note:         req.Note,
+		Lines:        req.Lines,
+	}
+}
+
+// ReceiveReturnRequest represents request to record returned units that arrived
+// 返品の到着の記録リクエストを表現
+type ReceiveReturnRequest struct {
+	Lines []inventory.ReturnReceiptLine `json:"lines"` // item_id・quantity
+}
+
+// DispositionReturnRequest represents request to disposition returned units pending inspection
+// 返品の処分リクエストを表現
+type DispositionReturnRequest struct {
+	ItemID       string                          `json:"item_id"`
+	Quantity     int64                           `json:"quantity"`
+	Disposition  inventory.ReturnDispositionType `json:"disposition"`    // restock・refurbish・scrap
+	TargetItemID string                          `json:"target_item_id"` // 再整備品の商品ID（refurbish のみ、省略時は返品の商品）
+	LocationID   string                          `json:"location_id"`    // 入庫先のロケーションID（refurbish のみ、省略時は返品先）
+	ReasonCode   inventory.AdjustmentReason      `json:"reason_code"`    // 廃棄の理由コード（scrap のみ）
+	Note         string                          `json:"note"`
+}
+
+// returnDisposition converts the request to a return disposition
+// リクエストを返品の処分に変換
+func (req *DispositionReturnRequest) returnDisposition() *inventory.ReturnDisposition {
+	return &inventory.ReturnDisposition{
+		ItemID:       req.ItemID,
+		Quantity:     req.Quantity,
+		Type:         req.Disposition,
+		TargetItemID: req.TargetItemID,
+		LocationID:   req.LocationID,
+		Reason:       req.ReasonCode,
+		Note:         req.Note,
+	}
+}
+
+// SetReorderPointRequest represents request to set the reorder point of an item at a location
+// 発注点の設定リクエストを表現
+type SetReorderPointRequest struct {
+	ReorderPoint int64 `json:"reorder_point"` // 発注点（在庫数量がこの値以下で低在庫アラート）
+	MinQty       int64 `json:"min_qty"`       // 最小在庫数
+	MaxQty       int64 `json:"max_qty"`       // 最大在庫数（0の場合は上限なし）
+}
+
+// SetChannelAllocationRequest represents request to set the stock earmarked for a sales channel
+// 販売チャネルの確保数量の設定リクエストを表現
+type SetChannelAllocationRequest struct {
+	Quantity int64 `json:"quantity"` // 確保数量（0の場合は確保を削除）
+}
+
+// AlertNoteRequest represents request to acknowledge or resolve an alert (the body is optional)
+// アラートの確認・解決リクエストを表現（本文は省略可能）
+type AlertNoteRequest struct {
+	Note string `json:"note"` // 対応内容などのメモ
+}
+
+// AlertRuleRequest represents request to create or update an alert rule
+// アラートルールの作成・更新リクエストを表現
+type AlertRuleRequest struct {
+	Name            string                    `json:"name"`
+	Metric          inventory.AlertMetric     `json:"metric"`           // quantity | available | reserved
+	Comparator      inventory.AlertComparator `json:"comparator"`       // lt | lte | gt | gte
+	Threshold       int64                     `json:"threshold"`        // 閾値
+	ItemID          string                    `json:"item_id"`          // 対象の商品ID（省略した場合は全商品）
+	LocationID      string                    `json:"location_id"`      // 対象のロケーションID（省略した場合は全ロケーション）
+	Severity        inventory.AlertSeverity   `json:"severity"`         // info | warning | critical
+	CooldownSeconds int64                     `json:"cooldown_seconds"` // アラートを再作成しない秒数
+	Enabled         *bool                     `json:"enabled"`          // 省略した場合は有効
+}
+
+// alertRule converts the request to an alert rule
+// リクエストをアラートルールに変換
+func (req *AlertRuleRequest) alertRule(ruleID string) *inventory.AlertRule {
+	rule := &inventory.AlertRule{
+		ID:              ruleID,
+		Name:            req.Name,
+		Metric:          req.Metric,
+		Comparator:      req.Comparator,
+		Threshold:       req.Threshold,
+		ItemID:          req.ItemID,
+		LocationID:      req.LocationID,
+		Severity:        req.Severity,
+		CooldownSeconds: req.CooldownSeconds,
+		Enabled:         true,
+	}
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+	return rule
+}
+
+// AdjustLotRequest represents request to adjust lot quantity
+// ロット数量調整リクエストを表現
+type AdjustLotRequest struct {
+	Delta     int64  `json:"delta"`
+	Reference string `json:"reference"`
+}
+
+// CreateStocktakeRequest represents request to create the count sheet of a location
+// 棚卸（集計表）の作成リクエストを表現
+type CreateStocktakeRequest struct {
+	LocationID string `json:"location_id"`
+	ABCClass   string `json:"abc_class"` // 対象のABCクラス A | B | C（省略した場合は全商品）
+	Reference  string `json:"reference"` // 参照番号（省略した場合は棚卸ID）
+}
+
+// RecordStocktakeCountsRequest represents request to record counted quantities on a stocktake
+// 棚卸の実数の記録リクエストを表現
+type RecordStocktakeCountsRequest struct {
+	Counts []inventory.StocktakeCount `json:"counts"`
+}
+
+// ApplyStocktakeRequest represents request to apply the approved variances of a stocktake (the body is optional)
+// 棚卸差異の調整リクエストを表現（本文は省略可能）
+type ApplyStocktakeRequest struct {
+	ItemIDs []string `json:"item_ids"` // 承認する商品ID（省略した場合は差異のある全ての商品）
+}
+
 // Handlers holds HTTP handlers for the inventory API
 // 在庫API用のHTTPハンドラーを保持
 type Handlers struct {
-	manager inventory.InventoryManager
-	logger  *zap.Logger
+	manager       inventory.InventoryManager
+	logger        *zap.Logger
+	webhooks      webhooks.Store            // Webhookサブスクリプション（未設定の場合は501）
+	eventRetry    *events.RetryingPublisher // イベント発行の再試行キュー（未設定の場合は501）
+	batches       *inventory.BatchQueue     // 非同期バッチの実行（未設定の場合は501）
+	scheduler     *scheduler.Scheduler      // 定期ジョブ（未設定の場合は501）
+	consumer      *consumer.Consumer        // 外部システムからの在庫同期（未設定の場合は501）
+	live          *events.Bus               // ライブ配信用のプロセス内イベントバス（未設定の場合は501）
+	streams       context.Context           // キャンセルされるとライブ配信の接続を終了する
+	graphql       *graphql.Handler          // GraphQLエンドポイント（未設定の場合は登録しない）
+	swaggerUI     bool                      // /docs でSwagger UIを提供する
+	authenticator auth.Authenticator        // 呼び出し元の認証（未設定の場合は認証せず anonymousUserID として記録）
+	unscopedJWT   bool                      // inventory:* のスコープを含まないJWTに全ての操作を許可する
+	apiKeys       *auth.APIKeys             // APIキーの管理（未設定の場合は501）
+	rateLimit     *rateLimiter              // クライアントごとの頻度制限（未設定の場合は制限しない）
+	metrics       *metrics.Collector        // リクエストのメトリクス（未設定の場合は記録しない）
+	tracing       string                    // トレースのサービス名（未設定の場合はリクエストのスパンを開始しない）
+	debug         bool                      // /api/v1/admin/debug でpprof・ランタイム統計を提供する
+	readiness     []dependencyCheck         // /readyz で確認する依存サービス
+	maxBodyBytes  int64                     // リクエスト本文の上限（0の場合は制限しない）
+	versions      []apiVersion              // 非推奨・提供終了日を設定したAPIのバージョン（未設定の場合は apiVersions）
 }
 
 // NewHandlers creates new HTTP handlers
@@ -27,52 +413,174 @@ func NewHandlers(manager inventory.InventoryManager, logger *zap.Logger) *Handle
 	return &Handlers{
 		manager: manager,
 		logger:  logger,
+		streams: context.Background(),
 	}
 }
 
 // APIResponse represents standard API response format
 // 標準的なAPIレスポンス形式を表現
 type APIResponse struct {
-	Success bool        `json:"success"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   string      `json:"error,omitempty"`
+	Success   bool        `json:"success"`
+	Data      interface{} `json:"data,omitempty"`
+	Error     string      `json:"error,omitempty"`
+	ErrorCode ErrorCode   `json:"error_code,omitempty"` // エラーの機械判読用のコード
 }
 
 // AddStockRequest represents request to add stock
 // 在庫追加リクエストを表現
 type AddStockRequest struct {
-	ItemID     string `json:"item_id"`
-	LocationID string `json:"location_id"`
-	Quantity   int64  `json:"quantity"`
-	Reference  string `json:"reference"`
+	ItemID     string  `json:"item_id"`
+	LocationID string  `json:"location_id"`
+	Quantity   float64 `json:"quantity"` // 数量（uom の単位、小数は単位の桁数まで）
+	UoM        string  `json:"uom"`      // 数量の単位（省略時は商品の基本単位）
+	Reference  string  `json:"reference"`
+	LotNumber  string  `json:"lot_number"` // 入荷するロットのロット番号（ない場合は作成）
+	OwnerID    string  `json:"owner_id"`   // 委託在庫の所有者の仕入先ID（省略時は自社所有）
 }
 
 // RemoveStockRequest represents request to remove stock
 // 在庫削除リクエストを表現
 type RemoveStockRequest struct {
-	ItemID     string `json:"item_id"`
-	LocationID string `json:"location_id"`
-	Quantity   int64  `json:"quantity"`
-	Reference  string `json:"reference"`
+	ItemID      string                       `json:"item_id"`
+	LocationID  string                       `json:"location_id"`
+	Quantity    float64                      `json:"quantity"` // 数量（uom の単位、小数は単位の桁数まで）
+	UoM         string                       `json:"uom"`      // 数量の単位（省略時は商品の基本単位）
+	Reference   string                       `json:"reference"`
+	PickLots    bool                         `json:"pick_lots"`    // 商品のロットを消費する（lot_strategy を指定した場合も消費）
+	LotStrategy inventory.LotPickingStrategy `json:"lot_strategy"` // ロットの消費順序（fefo・fifo・lifo、省略時は INVENTORY_LOT_PICKING_STRATEGY）
+	LotNumber   string                       `json:"lot_number"`   // 出庫するロットのロット番号（pick_lots・lot_strategy とは併用不可）
+	OwnerID     string                       `json:"owner_id"`     // 出庫する委託在庫の所有者の仕入先ID（省略時は自社所有）
 }
 
 // TransferStockRequest represents request to transfer stock
 // 在庫移動リクエストを表現
 type TransferStockRequest struct {
-	ItemID         string `json:"item_id"`
-	FromLocationID string `json:"from_location_id"`
-	ToLocationID   string `json:"to_location_id"`
-	Quantity       int64  `json:"quantity"`
-	Reference      string `json:"reference"`
+	ItemID         string  `json:"item_id"`
+	FromLocationID string  `json:"from_location_id"`
+	ToLocationID   string  `json:"to_location_id"`
+	Quantity       float64 `json:"quantity"` // 数量（uom の単位、小数は単位の桁数まで）
+	UoM            string  `json:"uom"`      // 数量の単位（省略時は商品の基本単位）
+	Reference      string  `json:"reference"`
+	LotNumber      string  `json:"lot_number"` // 移動するロットのロット番号
+	OwnerID        string  `json:"owner_id"`   // 移動する委託在庫の所有者の仕入先ID（省略時は自社所有）
 }
 
 // AdjustStockRequest represents request to adjust stock
 // 在庫調整リクエストを表現
 type AdjustStockRequest struct {
-	ItemID      string `json:"item_id"`
-	LocationID  string `json:"location_id"`
-	NewQuantity int64  `json:"new_quantity"`
-	Reference   string `json:"reference"`
+	ItemID      string                     `json:"item_id"`
+	LocationID  string                     `json:"location_id"`
+	NewQuantity int64                      `json:"new_quantity"`
+	Reference   string                     `json:"reference"`
+	ReasonCode  inventory.AdjustmentReason `json:"reason_code"` // damage | shrinkage | count_correction | expired | found | other
+	Note        string                     `json:"note"`        // メモ（reason_code が other の場合は必須）
+}
+
+// AdjustmentDecisionRequest represents request to approve or reject a stock adjustment (the body is optional)
+// 在庫調整の承認・却下リクエストを表現（本文は省略可能）
+type AdjustmentDecisionRequest struct {
+	Note string `json:"note"` // 承認・却下の理由などのメモ
+}
+
+// ReceiveForInspectionRequest represents request to receive stock into quarantine for QC inspection
+// 検品待ち（隔離）の入荷リクエストを表現
+type ReceiveForInspectionRequest struct {
+	ItemID     string `json:"item_id"`
+	LocationID string `json:"location_id"`
+	Quantity   int64  `json:"quantity"`
+	Reference  string `json:"reference"` // 参照番号（省略した場合は検品ID）
+	Note       string `json:"note"`      // 入荷時のメモ
+}
+
+// InspectionDispositionRequest represents request to pass or fail a receiving inspection
+// 入荷検品の合否判定リクエストを表現
+type InspectionDispositionRequest struct {
+	ReasonCode inventory.AdjustmentReason `json:"reason_code"` // 不合格（廃棄）の理由コード（不合格の場合は必須）
+	Note       string                     `json:"note"`        // 判定時のメモ
+}
+
+// AllocateRequest represents request to source an item across multiple locations
+// 複数ロケーションからの引当リクエストを表現
+type AllocateRequest struct {
+	ItemID               string                       `json:"item_id"`
+	Quantity             int64                        `json:"quantity"`
+	Reference            string                       `json:"reference"`
+	Strategy             inventory.AllocationStrategy `json:"strategy"`               // priority（既定）・proximity・stock_level
+	LocationIDs          []string                     `json:"location_ids"`           // 候補のロケーション（省略した場合は全て）
+	Destination          *inventory.GeoPoint          `json:"destination,omitempty"`  // 配送先（proximity の場合は必須）
+	MaxLocations         int                          `json:"max_locations"`          // 確保するロケーション数の上限（0は無制限）
+	PreferSingleLocation bool                         `json:"prefer_single_location"` // 1か所で全数を確保できる場合は分割しない
+	AllowPartial         bool                         `json:"allow_partial"`          // 不足する場合は確保できた数量のみ引き当てる
+	ExpiresAt            *time.Time                   `json:"expires_at,omitempty"`   // 予約の有効期限（省略した場合は既定の有効期間）
+	Channel              string                       `json:"channel"`                // 販売チャネル（省略した場合は共有の在庫のみから引当）
+}
+
+// allocationRequest converts the request into an allocation request of the inventory manager
+// リクエストを在庫管理の引当の要求に変換
+func (req AllocateRequest) allocationRequest() inventory.AllocationRequest {
+	return inventory.AllocationRequest{
+		ItemID:               req.ItemID,
+		Quantity:             req.Quantity,
+		Reference:            req.Reference,
+		Strategy:             req.Strategy,
+		LocationIDs:          req.LocationIDs,
+		Destination:          req.Destination,
+		MaxLocations:         req.MaxLocations,
+		PreferSingleLocation: req.PreferSingleLocation,
+		AllowPartial:         req.AllowPartial,
+		ExpiresAt:            req.ExpiresAt,
+		Channel:              req.Channel,
+	}
+}
+
+// Limits of label requests
+// ラベル印刷リクエストの上限
+const (
+	maxLabelTargets = 500  // 対象の件数の上限
+	maxLabelCopies  = 100  // 対象ごとの枚数の上限
+	maxLabels       = 2000 // 1回に印刷するラベルの枚数の上限
+)
+
+// LabelRequest represents request to render labels for items, lots and locations as PDF
+// 商品・ロット・ロケーションのラベルのPDF出力リクエストを表現
+type LabelRequest struct {
+	Template       string          `json:"template"`                  // 組み込みのテンプレート名（省略時は a4-24-code128）
+	CustomTemplate *label.Template `json:"custom_template,omitempty"` // テンプレートの定義（指定した場合は template より優先）
+	Targets        []LabelTarget   `json:"targets"`                   // 印刷する対象（指定した順に配置）
+}
+
+// LabelTarget is an item, lot or location to print labels for
+// ラベルを印刷する商品・ロット・ロケーション
+type LabelTarget struct {
+	Type   string `json:"type"`   // item・lot・location
+	ID     string `json:"id"`     // 商品ID・ロットID・ロケーションID
+	Copies int    `json:"copies"` // 枚数（省略時は1）
+}
+
+// template returns the template of the request
+// リクエストのテンプレートを返す
+func (req *LabelRequest) template() (*label.Template, bool) {
+	if req.CustomTemplate != nil {
+		return req.CustomTemplate, true
+	}
+	name := req.Template
+	if name == "" {
+		name = label.DefaultTemplate
+	}
+	tmpl, ok := label.LookupTemplate(name)
+	return &tmpl, ok
+}
+
+// ReverseTransactionRequest represents request to reverse a transaction
+// トランザクション取消リクエストを表現
+type ReverseTransactionRequest struct {
+	Reason string `json:"reason"` // 取消の理由
+}
+
+// LookupStocksRequest represents request to look up many stock records at once
+// 複数の在庫の一括照会リクエストを表現
+type LookupStocksRequest struct {
+	Keys []inventory.StockKey `json:"keys"` // 照会する商品とロケーションの組（最大500件）
 }
 
 // HealthCheck handles health check requests
@@ -92,25 +600,42 @@ func (h *Handlers) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
-// Metrics handles metrics requests (placeholder)
-// メトリクスリクエストを処理（プレースホルダー）
+// Metrics serves Prometheus metrics from the default registry
+// デフォルトレジストリのPrometheusメトリクスを返す
 func (h *Handlers) Metrics(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "text/plain")
-	w.Write([]byte("# メトリクス機能は後で実装予定\n"))
+	promhttp.Handler().ServeHTTP(w, r)
 }
 
 // AddStock handles add stock requests
 // 在庫追加リクエストを処理
 func (h *Handlers) AddStock(w http.ResponseWriter, r *http.Request) {
 	var req AddStockRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+	if !h.decodeJSON(w, r, &req) {
 		return
 	}
 
-	ctx := context.WithValue(r.Context(), "user_id", "api_user")
-	if err := h.manager.Add(ctx, req.ItemID, req.LocationID, req.Quantity, req.Reference); err != nil {
-		h.sendError(w, http.StatusInternalServerError, err.Error())
+	if !h.validateRequest(w, req.validate()...) {
+		return
+	}
+
+	ctx, quantity, ok := h.stockQuantity(w, r.Context(), req.ItemID, req.UoM, req.Quantity)
+	if !ok {
+		return
+	}
+	if req.LotNumber != "" {
+		ctx = inventory.WithLotNumber(ctx, req.LotNumber)
+	}
+	if req.OwnerID != "" {
+		ctx = inventory.WithOwner(ctx, req.OwnerID)
+	}
+	if dryRun, ok := h.dryRunRequested(w, r); !ok || dryRun {
+		if ok {
+			h.dryRunOperation(w, ctx, inventory.InventoryOperation{Type: inventory.OperationTypeAdd, ItemID: req.ItemID, LocationID: req.LocationID, Quantity: quantity, Reference: req.Reference})
+		}
+		return
+	}
+	if err := h.manager.Add(ctx, req.ItemID, req.LocationID, quantity, req.Reference); err != nil {
+		h.sendManagerError(w, err)
 		return
 	}
 
@@ -123,14 +648,41 @@ func (h *Handlers) AddStock(w http.ResponseWriter, r *http.Request) {
 // 在庫削除リクエストを処理
 func (h *Handlers) RemoveStock(w http.ResponseWriter, r *http.Request) {
 	var req RemoveStockRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+
+	if !h.validateRequest(w, req.validate()...) {
 		return
 	}
 
-	ctx := context.WithValue(r.Context(), "user_id", "api_user")
-	if err := h.manager.Remove(ctx, req.ItemID, req.LocationID, req.Quantity, req.Reference); err != nil {
-		h.sendError(w, http.StatusInternalServerError, err.Error())
+	ctx, ok := h.backorderContext(w, r)
+	if !ok {
+		return
+	}
+	ctx, quantity, ok := h.stockQuantity(w, ctx, req.ItemID, req.UoM, req.Quantity)
+	if !ok {
+		return
+	}
+	if req.PickLots || req.LotStrategy != "" {
+		ctx = inventory.WithLotPicking(ctx, req.LotStrategy)
+	}
+	if req.LotNumber != "" {
+		ctx = inventory.WithLotNumber(ctx, req.LotNumber)
+	}
+	if req.OwnerID != "" {
+		ctx = inventory.WithOwner(ctx, req.OwnerID)
+	}
+	if dryRun, ok := h.dryRunRequested(w, r); !ok || dryRun {
+		if ok {
+			h.dryRunOperation(w, ctx, inventory.InventoryOperation{Type: inventory.OperationTypeRemove, ItemID: req.ItemID, LocationID: req.LocationID, Quantity: quantity, Reference: req.Reference})
+		}
+		return
+	}
+	if err := h.manager.Remove(ctx, req.ItemID, req.LocationID, quantity, req.Reference); err != nil {
+		if !h.sendBackordered(w, err) {
+			h.sendManagerError(w, err)
+		}
 		return
 	}
 
@@ -143,14 +695,32 @@ func (h *Handlers) RemoveStock(w http.ResponseWriter, r *http.Request) {
 // 在庫移動リクエストを処理
 func (h *Handlers) TransferStock(w http.ResponseWriter, r *http.Request) {
 	var req TransferStockRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+	if !h.decodeJSON(w, r, &req) {
 		return
 	}
 
-	ctx := context.WithValue(r.Context(), "user_id", "api_user")
-	if err := h.manager.Transfer(ctx, req.ItemID, req.FromLocationID, req.ToLocationID, req.Quantity, req.Reference); err != nil {
-		h.sendError(w, http.StatusInternalServerError, err.Error())
+	if !h.validateRequest(w, req.validate()...) {
+		return
+	}
+
+	ctx, quantity, ok := h.stockQuantity(w, r.Context(), req.ItemID, req.UoM, req.Quantity)
+	if !ok {
+		return
+	}
+	if req.LotNumber != "" {
+		ctx = inventory.WithLotNumber(ctx, req.LotNumber)
+	}
+	if req.OwnerID != "" {
+		ctx = inventory.WithOwner(ctx, req.OwnerID)
+	}
+	if dryRun, ok := h.dryRunRequested(w, r); !ok || dryRun {
+		if ok {
+			h.dryRunOperation(w, ctx, inventory.InventoryOperation{Type: inventory.OperationTypeTransfer, ItemID: req.ItemID, LocationID: req.FromLocationID, ToLocationID: &req.ToLocationID, Quantity: quantity, Reference: req.Reference})
+		}
+		return
+	}
+	if err := h.manager.Transfer(ctx, req.ItemID, req.FromLocationID, req.ToLocationID, quantity, req.Reference); err != nil {
+		h.sendManagerError(w, err)
 		return
 	}
 
@@ -163,963 +733,5624 @@ func (h *Handlers) TransferStock(w http.ResponseWriter, r *http.Request) {
 // 在庫調整リクエストを処理
 func (h *Handlers) AdjustStock(w http.ResponseWriter, r *http.Request) {
 	var req AdjustStockRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
-		return
-	}
-
-	ctx := context.WithValue(r.Context(), "user_id", "api_user")
-	if err := h.manager.Adjust(ctx, req.ItemID, req.LocationID, req.NewQuantity, req.Reference); err != nil {
-		h.sendError(w, http.StatusInternalServerError, err.Error())
+	if !h.decodeJSON(w, r, &req) {
 		return
 	}
 
-	h.sendSuccess(w, map[string]string{
-		"message": "在庫調整が完了しました",
-	})
-}
-
-// BatchOperation handles batch operations
-// バッチ操作を処理
-func (h *Handlers) BatchOperation(w http.ResponseWriter, r *http.Request) {
-	var operations []inventory.InventoryOperation
-	if err := json.NewDecoder(r.Body).Decode(&operations); err != nil {
-		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+	if !h.validateRequest(w, req.validate()...) {
 		return
 	}
 
-	ctx := context.WithValue(r.Context(), "user_id", "api_user")
-	batch, err := h.manager.ExecuteBatch(ctx, operations)
+	// If-Match で在庫の ETag（バージョン）を指定した場合は、そのバージョンの在庫のみを調整する
+	ctx := r.Context()
+	version, ok, err := ifMatchVersion(r)
 	if err != nil {
-		h.sendError(w, http.StatusInternalServerError, err.Error())
+		h.sendError(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	if ok {
+		ctx = inventory.WithExpectedVersion(ctx, version)
+	}
 
-	h.sendSuccess(w, batch)
-}
-
-// GetStock handles get stock requests
-// 在庫取得リクエストを処理
-func (h *Handlers) GetStock(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	itemID := vars["itemId"]
-	locationID := vars["locationId"]
-
-	stock, err := h.manager.GetStock(r.Context(), itemID, locationID)
-	if err != nil {
-		if err == inventory.ErrStockNotFound {
-			h.sendError(w, http.StatusNotFound, "在庫が見つかりません")
-		} else {
-			h.sendError(w, http.StatusInternalServerError, err.Error())
+	if dryRun, ok := h.dryRunRequested(w, r); !ok || dryRun {
+		if ok {
+			h.dryRunOperation(w, ctx, inventory.InventoryOperation{Type: inventory.OperationTypeAdjust, ItemID: req.ItemID, LocationID: req.LocationID, Quantity: req.NewQuantity, Reference: req.Reference})
 		}
 		return
 	}
 
-	h.sendSuccess(w, stock)
-}
-
-// GetTotalStock handles get total stock requests
-// 総在庫取得リクエストを処理
-func (h *Handlers) GetTotalStock(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	itemID := vars["itemId"]
+	adjustmentManager, ok := h.manager.(inventory.AdjustmentManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "在庫調整の承認機能がサポートされていません")
+		return
+	}
 
-	total, err := h.manager.GetTotalStock(r.Context(), itemID)
-	if err != nil {
-		h.sendError(w, http.StatusInternalServerError, err.Error())
+	adjustment := &inventory.Adjustment{
+		ItemID:      req.ItemID,
+		LocationID:  req.LocationID,
+		NewQuantity: req.NewQuantity,
+		Reason:      req.ReasonCode,
+		Note:        req.Note,
+		Reference:   req.Reference,
+	}
+	if err := adjustmentManager.RequestAdjustment(ctx, adjustment); err != nil {
+		h.sendManagerError(w, err)
 		return
 	}
 
-	h.sendSuccess(w, map[string]int64{
-		"total_quantity": total,
+	// 承認の閾値を超えた調整は在庫を変更せずに承認待ちとして受け付ける
+	if adjustment.Status == inventory.AdjustmentStatusPending {
+		h.sendAccepted(w, map[string]interface{}{
+			"message":    "在庫調整は承認待ちです",
+			"adjustment": adjustment,
+		})
+		return
+	}
+	h.sendSuccess(w, map[string]interface{}{
+		"message":    "在庫調整が完了しました",
+		"adjustment": adjustment,
 	})
 }
 
-// GetStockByLocation handles get stock by location requests
-// ロケーション別在庫取得リクエストを処理
-func (h *Handlers) GetStockByLocation(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	locationID := vars["locationId"]
+// dryRunRequested reports whether the request asks for a dry run (?dry_run=true)
+// ドライラン（?dry_run=true）が指定されたかを返す（値が無効な場合は400を送信し、ok は false）
+func (h *Handlers) dryRunRequested(w http.ResponseWriter, r *http.Request) (dryRun, ok bool) {
+	dryRunStr := r.URL.Query().Get("dry_run")
+	if dryRunStr == "" {
+		return false, true
+	}
+	dryRun, err := strconv.ParseBool(dryRunStr)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "dry_runパラメータが無効です")
+		return false, false
+	}
+	return dryRun, true
+}
 
-	stocks, err := h.manager.GetStockByLocation(r.Context(), locationID)
+// backorderContext returns the request context, requesting a backorder on insufficient stock when ?backorder=true
+// ?backorder=true の場合は在庫不足でバックオーダーを作成するコンテキストを返す（値が無効な場合は400を送信し、ok は false）
+func (h *Handlers) backorderContext(w http.ResponseWriter, r *http.Request) (context.Context, bool) {
+	backorderStr := r.URL.Query().Get("backorder")
+	if backorderStr == "" {
+		return r.Context(), true
+	}
+	backorder, err := strconv.ParseBool(backorderStr)
 	if err != nil {
-		h.sendError(w, http.StatusInternalServerError, err.Error())
-		return
+		h.sendError(w, http.StatusBadRequest, "backorderパラメータが無効です")
+		return nil, false
 	}
+	if !backorder {
+		return r.Context(), true
+	}
+	return inventory.WithBackorder(r.Context()), true
+}
 
-	h.sendSuccess(w, stocks)
+// stockQuantity resolves the quantity of a stock request given in a unit
+// 単位で指定された在庫リクエストの数量を解釈
+//
+// 単位機能がある場合は数量を単位の桁数の整数に変換し、uom を指定した場合は基本単位に換算する
+// コンテキストを返します。単位機能がない場合は uom を指定しない整数の数量のみ受け付けます。
+func (h *Handlers) stockQuantity(w http.ResponseWriter, ctx context.Context, itemID, uom string, quantity float64) (context.Context, int64, bool) {
+	// 最短の10進数の表記は本文に記述された数量と一致する
+	value := strconv.FormatFloat(quantity, 'f', -1, 64)
+
+	var parsed int64
+	if unitManager, ok := h.manager.(inventory.UnitManager); ok {
+		var err error
+		if parsed, err = unitManager.ParseQuantity(ctx, itemID, uom, value); err != nil {
+			h.sendManagerError(w, err)
+			return ctx, 0, false
+		}
+		if uom != "" {
+			ctx = inventory.WithUoM(ctx, uom)
+		}
+	} else {
+		if uom != "" {
+			h.sendError(w, http.StatusNotImplemented, "単位機能がサポートされていません")
+			return ctx, 0, false
+		}
+		if quantity != math.Trunc(quantity) {
+			h.validateRequest(w, inventory.NewValidationError("quantity", "数量は整数で指定してください", value))
+			return ctx, 0, false
+		}
+		parsed = int64(quantity)
+	}
+
+	if !h.validateRequest(w, validatePositiveQuantity(parsed)) {
+		return ctx, 0, false
+	}
+	return ctx, parsed, true
 }
 
-// GetHistory handles get history requests
-// 履歴取得リクエストを処理
-func (h *Handlers) GetHistory(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	itemID := vars["itemId"]
+// sendBackordered sends 202 with the backorder when an operation was backordered, reporting whether it did
+// 操作がバックオーダーになった場合はバックオーダーを202で送信し、送信したかを返す
+func (h *Handlers) sendBackordered(w http.ResponseWriter, err error) bool {
+	var backordered *inventory.BackorderedError
+	if !errors.As(err, &backordered) {
+		return false
+	}
+	h.sendAccepted(w, map[string]interface{}{
+		"message":   "在庫不足のためバックオーダーを作成しました",
+		"backorder": backordered.Backorder,
+	})
+	return true
+}
 
-	// limitパラメータの取得
-	limit := 50 // デフォルト
-	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
-			limit = parsedLimit
-		}
+// dryRunOperation validates an operation without applying it and sends the stock changes
+// 操作を適用せずに検証し、在庫の変化を送信
+//
+// 操作が失敗する場合は実際の操作と同じステータス・エラーコードを返します。
+func (h *Handlers) dryRunOperation(w http.ResponseWriter, ctx context.Context, op inventory.InventoryOperation) {
+	dryRunner, ok := h.manager.(inventory.DryRunner)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "ドライランがサポートされていません")
+		return
 	}
 
-	history, err := h.manager.GetHistory(r.Context(), itemID, limit)
+	result, err := dryRunner.DryRunOperation(ctx, op)
 	if err != nil {
-		h.sendError(w, http.StatusInternalServerError, err.Error())
+		h.sendManagerError(w, err)
 		return
 	}
 
-	h.sendSuccess(w, history)
+	h.sendSuccess(w, result)
 }
 
-// GetAlerts handles get alerts requests
-// アラート取得リクエストを処理
-func (h *Handlers) GetAlerts(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	locationID := vars["locationId"]
+// dryRunBatch validates a batch without applying it and sends the result of each operation
+// バッチを適用せずに検証し、操作ごとの結果を送信
+//
+// 失敗する操作がある場合も200で返し、valid・results で判別します。
+func (h *Handlers) dryRunBatch(w http.ResponseWriter, ctx context.Context, operations []inventory.InventoryOperation, atomic bool) {
+	dryRunner, ok := h.manager.(inventory.DryRunner)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "ドライランがサポートされていません")
+		return
+	}
 
-	alerts, err := h.manager.GetAlerts(r.Context(), locationID)
+	result, err := dryRunner.DryRunBatch(ctx, operations, atomic)
 	if err != nil {
-		h.sendError(w, http.StatusInternalServerError, err.Error())
+		h.sendManagerError(w, err)
 		return
 	}
 
-	h.sendSuccess(w, alerts)
+	h.sendSuccess(w, result)
 }
 
-// ResolveAlert handles resolve alert requests
-// アラート解決リクエストを処理
-func (h *Handlers) ResolveAlert(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	alertID := vars["alertId"]
-
-	if err := h.manager.ResolveAlert(r.Context(), alertID); err != nil {
-		h.sendError(w, http.StatusInternalServerError, err.Error())
+// BatchOperation handles batch operations
+// バッチ操作を処理
+//
+// クエリパラメータ atomic=true を指定すると、全操作を単一トランザクションで実行します。
+func (h *Handlers) BatchOperation(w http.ResponseWriter, r *http.Request) {
+	var operations []inventory.InventoryOperation
+	if !h.decodeJSON(w, r, &operations) {
 		return
 	}
-
-	h.sendSuccess(w, map[string]string{
-		"message": "アラートが解決されました",
-	})
-}
-
-// CreateItem handles create item requests
-// 商品作成リクエストを処理
-func (h *Handlers) CreateItem(w http.ResponseWriter, r *http.Request) {
-	var item inventory.Item
-	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
-		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+	if !h.validateRequest(w, validateOperations(operations)...) {
 		return
 	}
 
-	// タイムスタンプ設定
-	now := time.Now()
-	item.CreatedAt = now
-	item.UpdatedAt = now
-
-	// ID生成（もしIDが指定されていない場合）
-	if item.ID == "" {
-		item.ID = inventory.NewTransactionID()
+	atomic := false
+	if atomicStr := r.URL.Query().Get("atomic"); atomicStr != "" {
+		parsedAtomic, err := strconv.ParseBool(atomicStr)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "atomicパラメータが無効です")
+			return
+		}
+		atomic = parsedAtomic
 	}
 
-	// ItemManagerを使用して商品を作成
-	if itemManager, ok := h.manager.(inventory.ItemManager); ok {
-		if err := itemManager.CreateItem(r.Context(), &item); err != nil {
-			h.sendError(w, http.StatusInternalServerError, err.Error())
-			return
+	ctx := r.Context()
+	if dryRun, ok := h.dryRunRequested(w, r); !ok || dryRun {
+		if ok {
+			h.dryRunBatch(w, ctx, operations, atomic)
 		}
-	} else {
-		h.sendError(w, http.StatusNotImplemented, "商品管理機能がサポートされていません")
 		return
 	}
-
-	h.sendSuccess(w, map[string]interface{}{
-		"message": "商品が作成されました",
-		"item":    item,
-	})
-}
-
-// GetItem handles get item requests
-// 商品取得リクエストを処理
-func (h *Handlers) GetItem(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	itemID := vars["itemId"]
-
-	// ItemManagerを使用して商品を取得
-	if itemManager, ok := h.manager.(inventory.ItemManager); ok {
-		item, err := itemManager.GetItem(r.Context(), itemID)
+	if asyncStr := r.URL.Query().Get("async"); asyncStr != "" {
+		async, err := strconv.ParseBool(asyncStr)
 		if err != nil {
-			if err == inventory.ErrItemNotFound {
-				h.sendError(w, http.StatusNotFound, "商品が見つかりません")
-			} else {
-				h.sendError(w, http.StatusInternalServerError, err.Error())
+			h.sendError(w, http.StatusBadRequest, "asyncパラメータが無効です")
+			return
+		}
+		if async {
+			if h.batches == nil {
+				h.sendError(w, http.StatusNotImplemented, "非同期バッチが設定されていません")
+				return
+			}
+			batch, err := h.batches.Submit(ctx, operations, atomic)
+			if err != nil {
+				h.sendManagerError(w, err)
+				return
 			}
+			// 実行結果は GET /inventory/batch/{batchId}/status で確認する
+			h.sendAccepted(w, batch)
 			return
 		}
-		h.sendSuccess(w, item)
+	}
+
+	var batch *inventory.BatchOperation
+	var err error
+	if atomic {
+		batch, err = h.manager.ExecuteBatchAtomic(ctx, operations)
 	} else {
-		h.sendError(w, http.StatusNotImplemented, "商品管理機能がサポートされていません")
+		batch, err = h.manager.ExecuteBatch(ctx, operations)
+	}
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
 	}
+
+	h.sendSuccess(w, batch)
 }
 
-// UpdateItem handles update item requests
-// 商品更新リクエストを処理
-func (h *Handlers) UpdateItem(w http.ResponseWriter, r *http.Request) {
+// GetStock handles get stock requests
+// 在庫取得リクエストを処理
+func (h *Handlers) GetStock(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	itemID := vars["itemId"]
+	locationID := vars["locationId"]
 
-	var item inventory.Item
-	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
-		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+	stock, err := h.manager.GetStock(r.Context(), itemID, locationID)
+	if err != nil {
+		h.sendManagerError(w, err)
 		return
 	}
 
-	item.ID = itemID
-	item.UpdatedAt = time.Now()
+	w.Header().Set("ETag", stockETag(stock))
+	h.sendSuccess(w, stock)
+}
 
-	// ItemManagerを使用して商品を更新
-	if itemManager, ok := h.manager.(inventory.ItemManager); ok {
-		if err := itemManager.UpdateItem(r.Context(), &item); err != nil {
-			if err == inventory.ErrItemNotFound {
-				h.sendError(w, http.StatusNotFound, "商品が見つかりません")
-			} else {
-				h.sendError(w, http.StatusInternalServerError, err.Error())
-			}
-			return
-		}
-		h.sendSuccess(w, map[string]interface{}{
-			"message": "商品が更新されました",
-			"item":    item,
-		})
-	} else {
-		h.sendError(w, http.StatusNotImplemented, "商品管理機能がサポートされていません")
+// LookupStocks handles bulk stock lookup requests
+// 在庫の一括照会リクエストを処理
+//
+// 在庫記録が存在しない組は missing に含めます（在庫数0として扱えます）。
+func (h *Handlers) LookupStocks(w http.ResponseWriter, r *http.Request) {
+	reader, ok := h.manager.(inventory.StockBatchReader)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "在庫の一括照会がサポートされていません")
+		return
 	}
-}
 
-// CreateLocation handles create location requests
-// ロケーション作成リクエストを処理
-func (h *Handlers) CreateLocation(w http.ResponseWriter, r *http.Request) {
-	var location inventory.Location
-	if err := json.NewDecoder(r.Body).Decode(&location); err != nil {
-		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+	var req LookupStocksRequest
+	if !h.decodeJSON(w, r, &req) {
 		return
 	}
 
-	// タイムスタンプ設定
-	now := time.Now()
-	location.CreatedAt = now
-	location.UpdatedAt = now
+	if !h.validateRequest(w, req.validate()...) {
+		return
+	}
 
-	// ID生成（もしIDが指定されていない場合）
-	if location.ID == "" {
-		location.ID = inventory.NewTransactionID()
+	stocks, err := reader.GetStocks(r.Context(), req.Keys)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
 	}
 
-	// LocationManagerを使用してロケーションを作成
-	if locationManager, ok := h.manager.(inventory.LocationManager); ok {
-		if err := locationManager.CreateLocation(r.Context(), &location); err != nil {
-			h.sendError(w, http.StatusInternalServerError, err.Error())
-			return
+	found := make(map[inventory.StockKey]bool, len(stocks))
+	for _, stock := range stocks {
+		found[inventory.StockKey{ItemID: stock.ItemID, LocationID: stock.LocationID}] = true
+	}
+	missing := make([]inventory.StockKey, 0)
+	for _, key := range req.Keys {
+		if !found[key] {
+			found[key] = true // 重複して指定された組を1回だけ含める
+			missing = append(missing, key)
 		}
-	} else {
-		h.sendError(w, http.StatusNotImplemented, "ロケーション管理機能がサポートされていません")
-		return
 	}
 
 	h.sendSuccess(w, map[string]interface{}{
-		"message":  "ロケーションが作成されました",
-		"location": location,
+		"stocks":  stocks,
+		"missing": missing,
 	})
 }
 
-// GetLocation handles get location requests
-// ロケーション取得リクエストを処理
-func (h *Handlers) GetLocation(w http.ResponseWriter, r *http.Request) {
+// GetTotalStock handles get total stock requests
+// 総在庫取得リクエストを処理
+func (h *Handlers) GetTotalStock(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	locationID := vars["locationId"]
+	itemID := vars["itemId"]
 
-	// LocationManagerを使用してロケーションを取得
-	if locationManager, ok := h.manager.(inventory.LocationManager); ok {
-		location, err := locationManager.GetLocation(r.Context(), locationID)
-		if err != nil {
-			if err == inventory.ErrLocationNotFound {
-				h.sendError(w, http.StatusNotFound, "ロケーションが見つかりません")
-			} else {
-				h.sendError(w, http.StatusInternalServerError, err.Error())
-			}
-			return
-		}
-		h.sendSuccess(w, location)
-	} else {
-		h.sendError(w, http.StatusNotImplemented, "ロケーション管理機能がサポートされていません")
+	total, err := h.manager.GetTotalStock(r.Context(), itemID)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
 	}
+
+	h.sendSuccess(w, map[string]int64{
+		"total_quantity": total,
+	})
 }
 
-// DeleteItem handles delete item requests
-// 商品削除リクエストを処理
-func (h *Handlers) DeleteItem(w http.ResponseWriter, r *http.Request) {
+// GetStockByLocation handles get stock by location requests
+// ロケーション別在庫取得リクエストを処理
+func (h *Handlers) GetStockByLocation(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	itemID := vars["itemId"]
+	locationID := vars["locationId"]
 
-	// ItemManagerを使用して商品を削除
-	if itemManager, ok := h.manager.(inventory.ItemManager); ok {
-		if err := itemManager.DeleteItem(r.Context(), itemID); err != nil {
-			if err == inventory.ErrItemNotFound {
-				h.sendError(w, http.StatusNotFound, "商品が見つかりません")
-			} else {
-				h.sendError(w, http.StatusInternalServerError, err.Error())
-			}
-			return
-		}
-		h.sendSuccess(w, map[string]string{
-			"message": "商品が削除されました",
-		})
-	} else {
-		h.sendError(w, http.StatusNotImplemented, "商品管理機能がサポートされていません")
+	if r.URL.Query().Get("stream") == "true" {
+		h.streamStockByLocation(w, r, locationID)
+		return
+	}
+
+	stocks, err := h.manager.GetStockByLocation(r.Context(), locationID)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
 	}
+
+	h.sendSuccess(w, stocks)
 }
 
-// ListItems handles list items requests
-// 商品一覧リクエストを処理
-func (h *Handlers) ListItems(w http.ResponseWriter, r *http.Request) {
-	// offsetとlimitのパラメータを取得
-	offset := 0
-	limit := 20 // デフォルト
+// streamStockByLocation writes stock at a location as newline-delimited JSON
+// ロケーションの在庫を改行区切りJSON（NDJSON）としてチャンク送信
+//
+// 1行に1件の在庫を書き出し、stockStreamFlushSize件ごとにフラッシュします。
+// 送信開始後にエラーが発生した場合はステータスコードを変更できないため、
+// 最終行に {"error": "..."} を書き出して終了します。
+func (h *Handlers) streamStockByLocation(w http.ResponseWriter, r *http.Request, locationID string) {
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	started := false
+	count := 0
+
+	err := h.manager.ForEachStockByLocation(r.Context(), locationID, func(stock inventory.Stock) error {
+		if !started {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+		if err := encoder.Encode(stock); err != nil {
+			return err
+		}
+		count++
+		if flusher != nil && count%stockStreamFlushSize == 0 {
+			flusher.Flush()
+		}
+		return nil
+	})
 
-	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
-		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
-			offset = parsedOffset
+	if err != nil {
+		if !started {
+			h.sendManagerError(w, err)
+			return
 		}
+		h.logger.Error("在庫ストリーミング送信に失敗しました",
+			zap.String("location_id", locationID),
+			zap.Int("sent", count),
+			zap.Error(err),
+		)
+		encoder.Encode(map[string]string{"error": err.Error()})
+		return
 	}
 
-	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 100 {
-			limit = parsedLimit
-		}
+	if !started {
+		// 在庫が0件の場合もストリーミング形式で空のレスポンスを返す
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
 	}
+}
 
-	// ItemManagerを使用して商品一覧を取得
-	if itemManager, ok := h.manager.(inventory.ItemManager); ok {
-		items, err := itemManager.ListItems(r.Context(), offset, limit)
-		if err != nil {
-			h.sendError(w, http.StatusInternalServerError, err.Error())
-			return
-		}
-		h.sendSuccess(w, map[string]interface{}{
-			"items":  items,
-			"offset": offset,
-			"limit":  limit,
-			"count":  len(items),
-		})
-	} else {
-		h.sendError(w, http.StatusNotImplemented, "商品管理機能がサポートされていません")
+// ExportStockByLocation handles stock export requests as CSV or Excel
+// ロケーションの在庫のCSV・Excelエクスポートリクエストを処理
+func (h *Handlers) ExportStockByLocation(w http.ResponseWriter, r *http.Request) {
+	locationID := mux.Vars(r)["locationId"]
+
+	export, err := h.newExport(w, r, "stock_"+locationID, "stock", stockExportColumns)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
 	}
+
+	err = h.manager.ForEachStockByLocation(r.Context(), locationID, func(stock inventory.Stock) error {
+		return export.write(stock.ItemID, stock.LocationID, stock.Quantity, stock.Reserved, stock.Available,
+			stock.Version, stock.UpdatedAt, stock.UpdatedBy)
+	})
+	export.finish(err, zap.String("location_id", locationID))
 }
 
-// SearchItems handles search items requests
-// 商品検索リクエストを処理
-func (h *Handlers) SearchItems(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query().Get("q")
-	if query == "" {
-		h.sendError(w, http.StatusBadRequest, "検索クエリが指定されていません")
+// ExportHistory handles transaction history export requests as CSV or Excel
+// 商品の履歴のCSV・Excelエクスポートリクエストを処理
+//
+// キーセットページネーションで historyExportPageSize 件ずつ読み取り、新しい順に書き出します。
+func (h *Handlers) ExportHistory(w http.ResponseWriter, r *http.Request) {
+	itemID := mux.Vars(r)["itemId"]
+
+	export, err := h.newExport(w, r, "history_"+itemID, "history", historyExportColumns)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	// ItemManagerを使用して商品を検索
-	if itemManager, ok := h.manager.(inventory.ItemManager); ok {
-		items, err := itemManager.SearchItems(r.Context(), query)
+	var cursor *inventory.HistoryCursor
+	for {
+		var page *inventory.TransactionPage
+		page, err = h.manager.GetHistoryPage(r.Context(), itemID, cursor, historyExportPageSize)
 		if err != nil {
-			h.sendError(w, http.StatusInternalServerError, err.Error())
-			return
+			break
 		}
-		h.sendSuccess(w, map[string]interface{}{
-			"items": items,
-			"query": query,
-			"count": len(items),
-		})
-	} else {
-		h.sendError(w, http.StatusNotImplemented, "商品管理機能がサポートされていません")
+		for _, tx := range page.Transactions {
+			var metadata string
+			if len(tx.Metadata) > 0 {
+				encoded, _ := json.Marshal(tx.Metadata)
+				metadata = string(encoded)
+			}
+			if err = export.write(tx.ID, string(tx.Type), tx.ItemID, tx.FromLocation, tx.ToLocation, tx.Quantity,
+				tx.UnitCost, tx.Reference, tx.LotNumber, tx.ExpiryDate, metadata, tx.CreatedAt, tx.CreatedBy); err != nil {
+				break
+			}
+		}
+		if err != nil || page.NextCursor == nil {
+			break
+		}
+		cursor = page.NextCursor
 	}
+	export.finish(err, zap.String("item_id", itemID))
 }
 
-// UpdateLocation handles update location requests
-// ロケーション更新リクエストを処理
-func (h *Handlers) UpdateLocation(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	locationID := vars["locationId"]
+// spreadsheetExport streams rows of a CSV or Excel download
+// CSV・Excelのダウンロードの行をストリーミング送信
+//
+// 最初の行を書き出すまでレスポンスを開始しないため、読み取り開始前のエラーは
+// 通常のエラーレスポンスで返せます。
+type spreadsheetExport struct {
+	h        *Handlers
+	w        http.ResponseWriter
+	format   spreadsheet.Format
+	filename string
+	sheet    string
+	header   []interface{}
+	started  bool
+	writer   spreadsheet.Writer
+	rows     int
+}
 
-	var location inventory.Location
-	if err := json.NewDecoder(r.Body).Decode(&location); err != nil {
-		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
-		return
+// newExport prepares an export in the format of the format query parameter
+// formatクエリパラメータの形式でエクスポートを準備
+func (h *Handlers) newExport(w http.ResponseWriter, r *http.Request, filename, sheet string, header []interface{}) (*spreadsheetExport, error) {
+	format, err := spreadsheet.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		return nil, err
 	}
+	return &spreadsheetExport{h: h, w: w, format: format, filename: filename, sheet: sheet, header: header}, nil
+}
 
-	location.ID = locationID
-	location.UpdatedAt = time.Now()
-
-	// LocationManagerを使用してロケーションを更新
-	if locationManager, ok := h.manager.(inventory.LocationManager); ok {
-		if err := locationManager.UpdateLocation(r.Context(), &location); err != nil {
-			if err == inventory.ErrLocationNotFound {
-				h.sendError(w, http.StatusNotFound, "ロケーションが見つかりません")
-			} else {
-				h.sendError(w, http.StatusInternalServerError, err.Error())
-			}
-			return
+// write writes a row, starting the response on the first row
+// 1行を書き出す（最初の行でレスポンスを開始）
+func (e *spreadsheetExport) write(values ...interface{}) error {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+	if err := e.writer.WriteRow(values...); err != nil {
+		return err
+	}
+	e.rows++
+	if e.rows%stockStreamFlushSize == 0 {
+		if err := e.writer.Flush(); err != nil {
+			return err
+		}
+		if flusher, ok := e.w.(http.Flusher); ok {
+			flusher.Flush()
 		}
-		h.sendSuccess(w, map[string]interface{}{
-			"message":  "ロケーションが更新されました",
-			"location": location,
-		})
-	} else {
-		h.sendError(w, http.StatusNotImplemented, "ロケーション管理機能がサポートされていません")
 	}
+	return nil
+}
+
+// start sends the response headers and the header row
+// レスポンスヘッダーとヘッダー行を送信
+func (e *spreadsheetExport) start() error {
+	e.started = true
+	filename := fmt.Sprintf("%s_%s.%s", e.filename, time.Now().Format("20060102"), e.format)
+	e.w.Header().Set("Content-Type", e.format.ContentType())
+	e.w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	e.w.WriteHeader(http.StatusOK)
+
+	writer, err := spreadsheet.NewWriter(e.format, e.w, e.sheet)
+	if err != nil {
+		return err
+	}
+	e.writer = writer
+	return e.writer.WriteRow(e.header...)
+}
+
+// finish completes the file, or reports err
+// ファイルを完成させる、またはerrを通知する
+//
+// 送信開始後はステータスコードを変更できず、CSV・Excelにはエラーを書き込めないため、
+// 接続を中断して不完全なファイルを正常なダウンロードとして扱われないようにします。
+func (e *spreadsheetExport) finish(err error, fields ...zap.Field) {
+	if err == nil && !e.started {
+		err = e.start() // 0件の場合もヘッダー行のみのファイルを返す
+	}
+	if err == nil {
+		err = e.writer.Close()
+	}
+	if err == nil {
+		return
+	}
+
+	if !e.started {
+		e.h.sendManagerError(e.w, err)
+		return
+	}
+	e.h.logger.Error("エクスポートの送信に失敗しました",
+		append(fields, zap.Int("sent", e.rows), zap.Error(err))...,
+	)
+	panic(http.ErrAbortHandler)
+}
+
+// GetHistory handles get history requests
+// 履歴取得リクエストを処理
+func (h *Handlers) GetHistory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	itemID := vars["itemId"]
+
+	// limitパラメータの取得
+	limit := 50 // デフォルト
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	// キーセットページネーション（paginate=true またはカーソル指定時）
+	query := r.URL.Query()
+	afterID := query.Get("after_id")
+	afterCreatedAt := query.Get("after_created_at")
+	if query.Get("paginate") == "true" || afterID != "" || afterCreatedAt != "" {
+		var cursor *inventory.HistoryCursor
+		if afterID != "" || afterCreatedAt != "" {
+			createdAt, err := time.Parse(time.RFC3339Nano, afterCreatedAt)
+			if afterID == "" || err != nil {
+				h.sendError(w, http.StatusBadRequest, "after_idとafter_created_at（RFC3339形式）の両方を指定してください")
+				return
+			}
+			cursor = &inventory.HistoryCursor{AfterCreatedAt: createdAt, AfterID: afterID}
+		}
+
+		page, err := h.manager.GetHistoryPage(r.Context(), itemID, cursor, limit)
+		if err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+
+		h.sendSuccess(w, page)
+		return
+	}
+
+	history, err := h.manager.GetHistory(r.Context(), itemID, limit)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, history)
+}
+
+// ListAlerts handles list alerts requests across all locations
+// 全ロケーションのアラート一覧取得リクエストを処理
+func (h *Handlers) ListAlerts(w http.ResponseWriter, r *http.Request) {
+	filter, err := alertFilter(r)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	alertWorkflow, ok := h.manager.(inventory.AlertWorkflowManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "アラート一覧機能がサポートされていません")
+		return
+	}
+
+	alerts, err := alertWorkflow.ListAlerts(r.Context(), filter)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"alerts": alerts,
+		"count":  len(alerts),
+		"offset": filter.Offset,
+		"limit":  filter.Limit,
+	})
+}
+
+// alertFilter parses the filter and pagination parameters of the alert list
+// アラート一覧の絞り込み・ページネーションのパラメータを解析
+func alertFilter(r *http.Request) (inventory.AlertFilter, error) {
+	q := r.URL.Query()
+	filter := inventory.AlertFilter{
+		Type:       inventory.AlertType(q.Get("type")),
+		Severity:   inventory.AlertSeverity(q.Get("severity")),
+		ItemID:     q.Get("item_id"),
+		LocationID: q.Get("location_id"),
+		Limit:      listLimit(r),
+	}
+	if offsetStr := q.Get("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			filter.Offset = parsedOffset
+		}
+	}
+	switch q.Get("status") {
+	case "":
+	case "active":
+		active := true
+		filter.Active = &active
+	case "resolved":
+		active := false
+		filter.Active = &active
+	default:
+		return filter, errors.New("statusパラメータは active または resolved を指定してください")
+	}
+	if acknowledgedStr := q.Get("acknowledged"); acknowledgedStr != "" {
+		acknowledged, err := strconv.ParseBool(acknowledgedStr)
+		if err != nil {
+			return filter, errors.New("acknowledgedパラメータが無効です")
+		}
+		filter.Acknowledged = &acknowledged
+	}
+	if fromStr := q.Get("from"); fromStr != "" {
+		from, err := parseTimeParam(fromStr, false)
+		if err != nil {
+			return filter, errors.New("無効なfrom日時形式です（形式：2006-01-02 または RFC3339）")
+		}
+		filter.CreatedAfter = from
+	}
+	if toStr := q.Get("to"); toStr != "" {
+		to, err := parseTimeParam(toStr, true)
+		if err != nil {
+			return filter, errors.New("無効なto日時形式です（形式：2006-01-02 または RFC3339）")
+		}
+		filter.CreatedBefore = to
+	}
+	return filter, nil
+}
+
+// parseTimeParam parses an RFC 3339 timestamp or a date; with endOfDay a date means the start of the next day
+// RFC 3339 の日時または日付を解析（endOfDay の場合、日付はその日の終わり＝翌日の0時として扱う）
+func parseTimeParam(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		date = date.AddDate(0, 0, 1)
+	}
+	return date, nil
+}
+
+// GetAlerts handles get alerts requests
+// アラート取得リクエストを処理
+func (h *Handlers) GetAlerts(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	locationID := vars["locationId"]
+
+	query := r.URL.Query()
+	severity := inventory.AlertSeverity(query.Get("severity"))
+	if severity != "" && !h.validateRequest(w, inventory.ValidateAlertSeverity(severity)) {
+		return
+	}
+	var acknowledged *bool
+	if acknowledgedStr := query.Get("acknowledged"); acknowledgedStr != "" {
+		parsed, err := strconv.ParseBool(acknowledgedStr)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "acknowledgedパラメータが無効です")
+			return
+		}
+		acknowledged = &parsed
+	}
+
+	alerts, err := h.manager.GetAlerts(r.Context(), locationID)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	// 重要度・確認状態で絞り込む（アクティブなアラートのみのため件数は限られる）
+	filtered := make([]inventory.StockAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		if severity != "" && alert.Severity != severity {
+			continue
+		}
+		if acknowledged != nil && (alert.AcknowledgedAt != nil) != *acknowledged {
+			continue
+		}
+		filtered = append(filtered, alert)
+	}
+
+	h.sendSuccess(w, filtered)
+}
+
+// AcknowledgeAlert handles acknowledge alert requests
+// アラート確認リクエストを処理
+func (h *Handlers) AcknowledgeAlert(w http.ResponseWriter, r *http.Request) {
+	alertID := mux.Vars(r)["alertId"]
+
+	var req AlertNoteRequest
+	if !h.decodeOptionalJSON(w, r, &req) {
+		return
+	}
+	if !h.validateRequest(w, req.validate()...) {
+		return
+	}
+
+	alertWorkflow, ok := h.manager.(inventory.AlertWorkflowManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "アラートの確認機能がサポートされていません")
+		return
+	}
+
+	alert, err := alertWorkflow.AcknowledgeAlert(r.Context(), alertID, req.Note)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message": "アラートが確認されました",
+		"alert":   alert,
+	})
+}
+
+// ResolveAlert handles resolve alert requests
+// アラート解決リクエストを処理
+func (h *Handlers) ResolveAlert(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	alertID := vars["alertId"]
+
+	var req AlertNoteRequest
+	if !h.decodeOptionalJSON(w, r, &req) {
+		return
+	}
+	if !h.validateRequest(w, req.validate()...) {
+		return
+	}
+
+	alertWorkflow, ok := h.manager.(inventory.AlertWorkflowManager)
+	if !ok {
+		// 解決した記録を返せないマネージャーではメモを保存できない
+		if req.Note != "" {
+			h.sendError(w, http.StatusNotImplemented, "アラートの確認機能がサポートされていません")
+			return
+		}
+		if err := h.manager.ResolveAlert(r.Context(), alertID); err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+		h.sendSuccess(w, map[string]string{
+			"message": "アラートが解決されました",
+		})
+		return
+	}
+
+	alert, err := alertWorkflow.ResolveAlertWithNote(r.Context(), alertID, req.Note)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message": "アラートが解決されました",
+		"alert":   alert,
+	})
+}
+
+// CreateItem handles create item requests
+// 商品作成リクエストを処理
+func (h *Handlers) CreateItem(w http.ResponseWriter, r *http.Request) {
+	var item inventory.Item
+	if !h.decodeJSON(w, r, &item) {
+		return
+	}
+
+	// タイムスタンプ設定
+	now := time.Now()
+	item.CreatedAt = now
+	item.UpdatedAt = now
+
+	// ID生成（もしIDが指定されていない場合）
+	if item.ID == "" {
+		item.ID = inventory.NewTransactionID()
+	}
+	if !h.validateRequest(w, validateItem(&item)...) {
+		return
+	}
+
+	// ItemManagerを使用して商品を作成
+	if itemManager, ok := h.manager.(inventory.ItemManager); ok {
+		if err := itemManager.CreateItem(r.Context(), &item); err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "商品管理機能がサポートされていません")
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message": "商品が作成されました",
+		"item":    item,
+	})
+}
+
+// GetItem handles get item requests
+// 商品取得リクエストを処理
+func (h *Handlers) GetItem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	itemID := vars["itemId"]
+
+	// ItemManagerを使用して商品を取得
+	if itemManager, ok := h.manager.(inventory.ItemManager); ok {
+		item, err := itemManager.GetItem(r.Context(), itemID)
+		if err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+		w.Header().Set("ETag", updatedETag(item.UpdatedAt))
+		h.sendSuccess(w, item)
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "商品管理機能がサポートされていません")
+	}
+}
+
+// UpdateItem handles update item requests
+// 商品更新リクエストを処理
+func (h *Handlers) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	itemID := vars["itemId"]
+
+	var item inventory.Item
+	if !h.decodeJSON(w, r, &item) {
+		return
+	}
+
+	item.ID = itemID
+	item.UpdatedAt = time.Now().Truncate(time.Microsecond)
+	if !h.validateRequest(w, validateItem(&item)...) {
+		return
+	}
+
+	// ItemManagerを使用して商品を更新
+	if itemManager, ok := h.manager.(inventory.ItemManager); ok {
+		ctx := r.Context()
+		if r.Header.Get("If-Match") != "" {
+			current, err := itemManager.GetItem(r.Context(), itemID)
+			if err != nil {
+				h.sendManagerError(w, err)
+				return
+			}
+			if !h.checkIfMatch(w, r, updatedETag(current.UpdatedAt)) {
+				return
+			}
+			item.CreatedAt = current.CreatedAt
+			ctx = ifMatchContext(r, current.UpdatedAt)
+		}
+		if err := itemManager.UpdateItem(ctx, &item); err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+		w.Header().Set("ETag", updatedETag(item.UpdatedAt))
+		h.sendSuccess(w, map[string]interface{}{
+			"message": "商品が更新されました",
+			"item":    item,
+		})
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "商品管理機能がサポートされていません")
+	}
+}
+
+// PatchItem handles partial update item requests (JSON merge patch)
+// 商品の部分更新リクエストを処理（JSONマージパッチ）
+//
+// 指定されたフィールドのみを更新し、null を指定したフィールドはゼロ値にします。
+// ID・作成日時は変更できません。
+func (h *Handlers) PatchItem(w http.ResponseWriter, r *http.Request) {
+	itemID := mux.Vars(r)["itemId"]
+
+	itemManager, ok := h.manager.(inventory.ItemManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "商品管理機能がサポートされていません")
+		return
+	}
+
+	current, err := itemManager.GetItem(r.Context(), itemID)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	if !h.checkIfMatch(w, r, updatedETag(current.UpdatedAt)) {
+		return
+	}
+
+	item, status, err := applyMergePatch(r, current)
+	if err != nil {
+		h.sendPatchError(w, status, err)
+		return
+	}
+	item.ID = current.ID
+	item.CreatedAt = current.CreatedAt
+	item.UpdatedAt = time.Now().Truncate(time.Microsecond)
+	if !h.validateRequest(w, validateItem(item)...) {
+		return
+	}
+
+	if err := itemManager.UpdateItem(ifMatchContext(r, current.UpdatedAt), item); err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	w.Header().Set("ETag", updatedETag(item.UpdatedAt))
+	h.sendSuccess(w, map[string]interface{}{
+		"message": "商品が更新されました",
+		"item":    item,
+	})
+}
+
+// CreateLocation handles create location requests
+// ロケーション作成リクエストを処理
+func (h *Handlers) CreateLocation(w http.ResponseWriter, r *http.Request) {
+	var location inventory.Location
+	if !h.decodeJSON(w, r, &location) {
+		return
+	}
+
+	// タイムスタンプ設定
+	now := time.Now()
+	location.CreatedAt = now
+	location.UpdatedAt = now
+
+	// ID生成（もしIDが指定されていない場合）
+	if location.ID == "" {
+		location.ID = inventory.NewTransactionID()
+	}
+	if !h.validateRequest(w, validateLocation(&location)...) {
+		return
+	}
+
+	// LocationManagerを使用してロケーションを作成
+	if locationManager, ok := h.manager.(inventory.LocationManager); ok {
+		if err := locationManager.CreateLocation(r.Context(), &location); err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "ロケーション管理機能がサポートされていません")
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":  "ロケーションが作成されました",
+		"location": location,
+	})
+}
+
+// GetLocation handles get location requests
+// ロケーション取得リクエストを処理
+func (h *Handlers) GetLocation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	locationID := vars["locationId"]
+
+	// LocationManagerを使用してロケーションを取得
+	if locationManager, ok := h.manager.(inventory.LocationManager); ok {
+		location, err := locationManager.GetLocation(r.Context(), locationID)
+		if err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+		w.Header().Set("ETag", updatedETag(location.UpdatedAt))
+		h.sendSuccess(w, location)
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "ロケーション管理機能がサポートされていません")
+	}
+}
+
+// DeleteItem handles delete item requests
+// 商品削除リクエストを処理
+func (h *Handlers) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	itemID := vars["itemId"]
+
+	// ItemManagerを使用して商品を削除
+	if itemManager, ok := h.manager.(inventory.ItemManager); ok {
+		if err := itemManager.DeleteItem(r.Context(), itemID); err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+		h.sendSuccess(w, map[string]string{
+			"message": "商品が削除されました",
+		})
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "商品管理機能がサポートされていません")
+	}
+}
+
+// ListItems handles list items requests
+// 商品一覧リクエストを処理
+//
+// cursor を指定した場合（offset を指定しない場合）はキーセットページネーションで取得し、
+// 次ページがある場合はレスポンスの next_cursor を次のリクエストの cursor に指定します。
+// offset はページの取得中に追加・削除があるとずれるため非推奨です。
+func (h *Handlers) ListItems(w http.ResponseWriter, r *http.Request) {
+	// offsetとlimitのパラメータを取得
+	offset := 0
+	limit := listLimit(r)
+
+	filter, filtered, err := itemFilter(r)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if filtered {
+			h.sendError(w, http.StatusBadRequest, "絞り込み・並び替えは offset と併用できません（cursor を使用してください）")
+			return
+		}
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			offset = parsedOffset
+		}
+	} else if pager, ok := h.manager.(inventory.ListPager); ok {
+		cursor, ok := h.pageCursor(w, r)
+		if !ok {
+			return
+		}
+		page, err := pager.ListItemsPage(r.Context(), filter, cursor, limit)
+		if err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+		h.sendSuccess(w, pageResponse("items", page.Items, len(page.Items), limit, page.NextCursor))
+		return
+	}
+
+	if filtered {
+		// offset との併用は拒否済みのため、カーソルでのページングに対応していない場合
+		h.sendError(w, http.StatusNotImplemented, "商品一覧の絞り込みがサポートされていません")
+		return
+	}
+
+	// ItemManagerを使用して商品一覧を取得
+	if itemManager, ok := h.manager.(inventory.ItemManager); ok {
+		items, err := itemManager.ListItems(r.Context(), offset, limit)
+		if err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+		h.sendSuccess(w, map[string]interface{}{
+			"items":  items,
+			"offset": offset,
+			"limit":  limit,
+			"count":  len(items),
+		})
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "商品管理機能がサポートされていません")
+	}
+}
+
+// SearchItems handles search items requests
+// 商品検索リクエストを処理
+func (h *Handlers) SearchItems(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		h.sendError(w, http.StatusBadRequest, "検索クエリが指定されていません")
+		return
+	}
+
+	// ItemManagerを使用して商品を検索
+	if itemManager, ok := h.manager.(inventory.ItemManager); ok {
+		items, err := itemManager.SearchItems(r.Context(), query)
+		if err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+		h.sendSuccess(w, map[string]interface{}{
+			"items": items,
+			"query": query,
+			"count": len(items),
+		})
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "商品管理機能がサポートされていません")
+	}
+}
+
+// UpdateLocation handles update location requests
+// ロケーション更新リクエストを処理
+func (h *Handlers) UpdateLocation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	locationID := vars["locationId"]
+
+	var location inventory.Location
+	if !h.decodeJSON(w, r, &location) {
+		return
+	}
+
+	location.ID = locationID
+	location.UpdatedAt = time.Now().Truncate(time.Microsecond)
+	if !h.validateRequest(w, validateLocation(&location)...) {
+		return
+	}
+
+	// LocationManagerを使用してロケーションを更新
+	if locationManager, ok := h.manager.(inventory.LocationManager); ok {
+		ctx := r.Context()
+		if r.Header.Get("If-Match") != "" {
+			current, err := locationManager.GetLocation(r.Context(), locationID)
+			if err != nil {
+				h.sendManagerError(w, err)
+				return
+			}
+			if !h.checkIfMatch(w, r, updatedETag(current.UpdatedAt)) {
+				return
+			}
+			location.CreatedAt = current.CreatedAt
+			ctx = ifMatchContext(r, current.UpdatedAt)
+		}
+		if err := locationManager.UpdateLocation(ctx, &location); err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+		w.Header().Set("ETag", updatedETag(location.UpdatedAt))
+		h.sendSuccess(w, map[string]interface{}{
+			"message":  "ロケーションが更新されました",
+			"location": location,
+		})
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "ロケーション管理機能がサポートされていません")
+	}
+}
+
+// PatchLocation handles partial update location requests (JSON merge patch)
+// ロケーションの部分更新リクエストを処理（JSONマージパッチ）
+//
+// 指定されたフィールドのみを更新し、null を指定したフィールドはゼロ値にします。
+// ID・作成日時は変更できません。
+func (h *Handlers) PatchLocation(w http.ResponseWriter, r *http.Request) {
+	locationID := mux.Vars(r)["locationId"]
+
+	locationManager, ok := h.manager.(inventory.LocationManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "ロケーション管理機能がサポートされていません")
+		return
+	}
+
+	current, err := locationManager.GetLocation(r.Context(), locationID)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	if !h.checkIfMatch(w, r, updatedETag(current.UpdatedAt)) {
+		return
+	}
+
+	location, status, err := applyMergePatch(r, current)
+	if err != nil {
+		h.sendPatchError(w, status, err)
+		return
+	}
+	location.ID = current.ID
+	location.CreatedAt = current.CreatedAt
+	location.UpdatedAt = time.Now().Truncate(time.Microsecond)
+	if !h.validateRequest(w, validateLocation(location)...) {
+		return
+	}
+
+	if err := locationManager.UpdateLocation(ifMatchContext(r, current.UpdatedAt), location); err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	w.Header().Set("ETag", updatedETag(location.UpdatedAt))
+	h.sendSuccess(w, map[string]interface{}{
+		"message":  "ロケーションが更新されました",
+		"location": location,
+	})
+}
+
+// applyMergePatch applies the JSON merge patch of a request body to a record
+// リクエスト本文のJSONマージパッチをレコードに適用し、適用後のレコードを返す
+//
+// エラーの場合は送信するステータスコードを返します。
+func applyMergePatch[T any](r *http.Request, current *T) (*T, int, error) {
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (mediaType != mergepatch.ContentType && mediaType != "application/json") {
+			return nil, http.StatusUnsupportedMediaType, fmt.Errorf("Content-Type は %s を指定してください", mergepatch.ContentType)
+		}
+	}
+
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		if bodyErr := bodyError(err); bodyErr.status == http.StatusRequestEntityTooLarge {
+			return nil, bodyErr.status, bodyErr
+		}
+		return nil, http.StatusBadRequest, fmt.Errorf("リクエスト本文の読み込みに失敗しました")
+	}
+	document, err := json.Marshal(current)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	patched, err := mergepatch.Apply(document, patch)
+	if err != nil {
+		if errors.Is(err, mergepatch.ErrNotObject) {
+			return nil, http.StatusBadRequest, err
+		}
+		return nil, http.StatusBadRequest, fmt.Errorf("無効なリクエスト形式です")
+	}
+
+	// 削除したフィールドをゼロ値にするため、現在の値ではなく新しい値にデコードする
+	record := new(T)
+	if err := decodeStrict(bytes.NewReader(patched), record); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	return record, 0, nil
+}
+
+// sendPatchError sends an error returned by applyMergePatch
+// applyMergePatch が返したエラーを送信
+func (h *Handlers) sendPatchError(w http.ResponseWriter, status int, err error) {
+	var bodyErr *requestBodyError
+	if errors.As(err, &bodyErr) {
+		h.checkBody(w, bodyErr)
+		return
+	}
+	h.sendError(w, status, err.Error())
+}
+
+// stockETag returns the entity tag of a stock record (its version)
+// 在庫記録のエンティティタグ（バージョン）を返す
+func stockETag(stock *inventory.Stock) string {
+	return strconv.Quote(strconv.FormatInt(stock.Version, 10))
+}
+
+// updatedETag returns the entity tag of an item or location by its update time
+// 商品・ロケーションの更新日時によるエンティティタグを返す
+//
+// PostgreSQLのタイムスタンプの精度に合わせてマイクロ秒単位とします。
+func updatedETag(updatedAt time.Time) string {
+	return strconv.Quote(strconv.FormatInt(updatedAt.UnixMicro(), 10))
+}
+
+// checkIfMatch compares the If-Match header with the current entity tag, sending 412 on mismatch
+// If-Match ヘッダーを現在のエンティティタグと比較（一致しない場合は412を送信してfalseを返す）
+//
+// If-Match が指定されていない場合は常に一致とします。弱いエンティティタグ（W/）は一致しません。
+func (h *Handlers) checkIfMatch(w http.ResponseWriter, r *http.Request, current string) bool {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return true
+	}
+	for _, tag := range strings.Split(header, ",") {
+		if tag = strings.TrimSpace(tag); tag == "*" || tag == current {
+			return true
+		}
+	}
+	w.Header().Set("ETag", current)
+	h.sendError(w, http.StatusPreconditionFailed, "他のユーザーによって更新されています。最新の状態を取得してから再度更新してください")
+	return false
+}
+
+// ifMatchContext returns a context that makes an update conditional on the update time If-Match was checked against
+// If-Match を確認した更新日時の場合のみ更新するコンテキストを返す（If-Match がない・* の場合は条件なし）
+//
+// 確認から更新までの間に他のクライアントが更新した場合は、ストレージが ErrPreconditionFailed（412）を返します。
+func ifMatchContext(r *http.Request, updatedAt time.Time) context.Context {
+	if header := strings.TrimSpace(r.Header.Get("If-Match")); header == "" || header == "*" {
+		return r.Context()
+	}
+	return inventory.WithExpectedUpdatedAt(r.Context(), updatedAt)
+}
+
+// ifMatchVersion returns the stock version given by the If-Match header
+// If-Match ヘッダーで指定された在庫のバージョンを返す（指定がない・* の場合はfalse）
+func ifMatchVersion(r *http.Request) (int64, bool, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return 0, false, nil
+	}
+	if strings.HasPrefix(header, "W/") {
+		// 弱いエンティティタグはどのバージョンとも一致しない
+		return -1, true, nil
+	}
+	if len(header) < 2 || header[0] != '"' || header[len(header)-1] != '"' || strings.Contains(header[1:len(header)-1], `"`) {
+		return 0, false, fmt.Errorf("If-Match には在庫の ETag を1つ指定してください")
+	}
+	version, err := strconv.ParseInt(header[1:len(header)-1], 10, 64)
+	if err != nil {
+		// 在庫の ETag ではないため、どのバージョンとも一致しない
+		return -1, true, nil
+	}
+	return version, true, nil
+}
+
+// DeleteLocation handles delete location requests
+// ロケーション削除リクエストを処理
+func (h *Handlers) DeleteLocation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	locationID := vars["locationId"]
+
+	// LocationManagerを使用してロケーションを削除
+	if locationManager, ok := h.manager.(inventory.LocationManager); ok {
+		if err := locationManager.DeleteLocation(r.Context(), locationID); err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+		h.sendSuccess(w, map[string]string{
+			"message": "ロケーションが削除されました",
+		})
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "ロケーション管理機能がサポートされていません")
+	}
+}
+
+// ListLocations handles list locations requests
+// ロケーション一覧リクエストを処理
+//
+// ページネーションは ListItems と同じく cursor（推奨）または offset で指定します。
+func (h *Handlers) ListLocations(w http.ResponseWriter, r *http.Request) {
+	// offsetとlimitのパラメータを取得
+	offset := 0
+	limit := listLimit(r)
+
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			offset = parsedOffset
+		}
+	} else if pager, ok := h.manager.(inventory.ListPager); ok {
+		cursor, ok := h.pageCursor(w, r)
+		if !ok {
+			return
+		}
+		page, err := pager.ListLocationsPage(r.Context(), cursor, limit)
+		if err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+		h.sendSuccess(w, pageResponse("locations", page.Locations, len(page.Locations), limit, page.NextCursor))
+		return
+	}
+
+	// LocationManagerを使用してロケーション一覧を取得
+	if locationManager, ok := h.manager.(inventory.LocationManager); ok {
+		locations, err := locationManager.ListLocations(r.Context(), offset, limit)
+		if err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+		h.sendSuccess(w, map[string]interface{}{
+			"locations": locations,
+			"offset":    offset,
+			"limit":     limit,
+			"count":     len(locations),
+		})
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "ロケーション管理機能がサポートされていません")
+	}
+}
+
+// listLimit returns the page size of a list request (default 20, at most 100)
+// 一覧リクエストの1ページあたりの件数を返す（デフォルト20、最大100）
+func listLimit(r *http.Request) int {
+	limit := 20 // デフォルト
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 100 {
+			limit = parsedLimit
+		}
+	}
+	return limit
+}
+
+// itemFilter parses the filter and sort parameters of the item list
+// 商品一覧の絞り込み・並び替えのパラメータを解析（いずれかが指定された場合はtrueを返す）
+func itemFilter(r *http.Request) (inventory.ItemFilter, bool, error) {
+	q := r.URL.Query()
+	filter := inventory.ItemFilter{
+		Category:  q.Get("category"),
+		SKUPrefix: q.Get("sku_prefix"),
+		SortBy:    inventory.ItemSortField(q.Get("sort")),
+	}
+	for name, target := range map[string]**float64{"min_cost": &filter.MinCost, "max_cost": &filter.MaxCost} {
+		if value := q.Get(name); value != "" {
+			cost, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return filter, false, fmt.Errorf("%sは数値で指定してください", name)
+			}
+			*target = &cost
+		}
+	}
+	switch q.Get("order") {
+	case "", "desc":
+	case "asc":
+		filter.Ascending = true
+	default:
+		return filter, false, fmt.Errorf("orderは asc または desc を指定してください")
+	}
+	if err := filter.Validate(); err != nil {
+		return filter, false, err
+	}
+
+	filtered := filter != (inventory.ItemFilter{}) || q.Get("order") != ""
+	return filter, filtered, nil
+}
+
+// pageCursor parses the cursor parameter, sending 400 when it is malformed
+// cursorパラメータを解析（不正な場合は400を送信してfalseを返す）
+func (h *Handlers) pageCursor(w http.ResponseWriter, r *http.Request) (*inventory.PageCursor, bool) {
+	value := r.URL.Query().Get("cursor")
+	if value == "" {
+		return nil, true
+	}
+	cursor, err := inventory.DecodePageCursor(value)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	return cursor, true
+}
+
+// pageResponse builds the response of a page of a list
+// 一覧の1ページのレスポンスを作成（最終ページの場合は next_cursor を含めない）
+func pageResponse(key string, records interface{}, count, limit int, next *inventory.PageCursor) map[string]interface{} {
+	response := map[string]interface{}{
+		key:     records,
+		"limit": limit,
+		"count": count,
+	}
+	if next != nil {
+		response["next_cursor"] = next.Encode()
+	}
+	return response
+}
+
+// ロット管理ハンドラー
+
+// CreateLot handles create lot requests
+// ロット作成リクエストを処理
+func (h *Handlers) CreateLot(w http.ResponseWriter, r *http.Request) {
+	var lot inventory.Lot
+	if !h.decodeJSON(w, r, &lot) {
+		return
+	}
+
+	// タイムスタンプ設定
+	lot.CreatedAt = time.Now()
+
+	// ID生成（もしIDが指定されていない場合）
+	if lot.ID == "" {
+		lot.ID = inventory.NewTransactionID()
+	}
+	if !h.validateRequest(w, validateLot(&lot)...) {
+		return
+	}
+
+	// LotManagerを使用してロットを作成
+	if lotManager, ok := h.manager.(inventory.LotManager); ok {
+		if err := lotManager.CreateLot(r.Context(), &lot); err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "ロット管理機能がサポートされていません")
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message": "ロットが作成されました",
+		"lot":     lot,
+	})
+}
+
+// GetLot handles get lot requests
+// ロット取得リクエストを処理
+func (h *Handlers) GetLot(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	lotID := vars["lotId"]
+
+	// LotManagerを使用してロットを取得
+	if lotManager, ok := h.manager.(inventory.LotManager); ok {
+		lot, err := lotManager.GetLot(r.Context(), lotID)
+		if err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+		h.sendSuccess(w, lot)
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "ロット管理機能がサポートされていません")
+	}
+}
+
+// UpdateLot handles update lot requests
+// ロット更新リクエストを処理
+func (h *Handlers) UpdateLot(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	lotID := vars["lotId"]
+
+	var lot inventory.Lot
+	if !h.decodeJSON(w, r, &lot) {
+		return
+	}
+
+	lot.ID = lotID
+
+	// LotManagerを使用してロットを更新
+	if lotManager, ok := h.manager.(inventory.LotManager); ok {
+		if err := lotManager.UpdateLot(r.Context(), &lot); err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+		h.sendSuccess(w, map[string]interface{}{
+			"message": "ロットが更新されました",
+			"lot":     lot,
+		})
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "ロット管理機能がサポートされていません")
+	}
+}
+
+// DeleteLot handles delete lot requests
+// ロット削除リクエストを処理
+func (h *Handlers) DeleteLot(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	lotID := vars["lotId"]
+
+	// LotManagerを使用してロットを削除
+	if lotManager, ok := h.manager.(inventory.LotManager); ok {
+		if err := lotManager.DeleteLot(r.Context(), lotID); err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+		h.sendSuccess(w, map[string]string{
+			"message": "ロットが削除されました",
+		})
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "ロット管理機能がサポートされていません")
+	}
+}
+
+// AdjustLot handles lot quantity adjustment requests
+// ロット数量調整リクエストを処理
+func (h *Handlers) AdjustLot(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	lotID := vars["lotId"]
+
+	var req AdjustLotRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+
+	if !h.validateRequest(w, req.validate()...) {
+		return
+	}
+
+	ctx := r.Context()
+
+	// LotManagerを使用してロット数量を調整
+	if lotManager, ok := h.manager.(inventory.LotManager); ok {
+		lot, err := lotManager.AdjustLotQuantity(ctx, lotID, req.Delta, req.Reference)
+		if err != nil {
+			if errors.Is(err, inventory.ErrInsufficientStock) {
+				h.sendErrorCode(w, http.StatusUnprocessableEntity, ErrorCodeInsufficientStock, "ロット数量が不足しています")
+				return
+			}
+			h.sendManagerError(w, err)
+			return
+		}
+		h.sendSuccess(w, map[string]interface{}{
+			"message": "ロット数量が調整されました",
+			"lot":     lot,
+		})
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "ロット管理機能がサポートされていません")
+	}
+}
+
+// GetLotsByItem handles get lots by item requests
+// 商品別ロット取得リクエストを処理
+func (h *Handlers) GetLotsByItem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	itemID := vars["itemId"]
+
+	// LotManagerを使用して商品のロット一覧を取得
+	if lotManager, ok := h.manager.(inventory.LotManager); ok {
+		lots, err := lotManager.GetLotsByItem(r.Context(), itemID)
+		if err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+		h.sendSuccess(w, map[string]interface{}{
+			"lots":    lots,
+			"item_id": itemID,
+			"count":   len(lots),
+		})
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "ロット管理機能がサポートされていません")
+	}
+}
+
+// GetExpiringLots handles get expiring lots requests
+// 期限切れ間近ロット取得リクエストを処理
+func (h *Handlers) GetExpiringLots(w http.ResponseWriter, r *http.Request) {
+	// within パラメータを取得（日数）
+	withinDays := 7 // デフォルト7日
+	if withinStr := r.URL.Query().Get("within_days"); withinStr != "" {
+		if parsedDays, err := strconv.Atoi(withinStr); err == nil && parsedDays > 0 {
+			withinDays = parsedDays
+		}
+	}
+
+	within := time.Duration(withinDays) * 24 * time.Hour
+
+	// LotManagerを使用して期限切れ間近ロットを取得
+	if lotManager, ok := h.manager.(inventory.LotManager); ok {
+		lots, err := lotManager.GetExpiringLots(r.Context(), within)
+		if err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+		h.sendSuccess(w, map[string]interface{}{
+			"lots":        lots,
+			"within_days": withinDays,
+			"count":       len(lots),
+		})
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "ロット管理機能がサポートされていません")
+	}
+}
+
+// NotifyExpiringLots handles expiring lot notification requests
+// 期限切れ間近ロットの通知リクエストを処理
+func (h *Handlers) NotifyExpiringLots(w http.ResponseWriter, r *http.Request) {
+	withinDays := 7 // デフォルト7日
+	if withinStr := r.URL.Query().Get("within_days"); withinStr != "" {
+		if parsedDays, err := strconv.Atoi(withinStr); err == nil && parsedDays > 0 {
+			withinDays = parsedDays
+		}
+	}
+
+	within := time.Duration(withinDays) * 24 * time.Hour
+
+	if lotManager, ok := h.manager.(inventory.LotManager); ok {
+		count, err := lotManager.NotifyExpiringLots(r.Context(), within)
+		if err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+		h.sendSuccess(w, map[string]interface{}{
+			"within_days": withinDays,
+			"notified":    count,
+		})
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "ロット管理機能がサポートされていません")
+	}
+}
+
+// GetExpiredLots handles get expired lots requests
+// 期限切れロット取得リクエストを処理
+func (h *Handlers) GetExpiredLots(w http.ResponseWriter, r *http.Request) {
+	// LotManagerを使用して期限切れロットを取得
+	if lotManager, ok := h.manager.(inventory.LotManager); ok {
+		lots, err := lotManager.GetExpiredLots(r.Context())
+		if err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+		h.sendSuccess(w, map[string]interface{}{
+			"lots":  lots,
+			"count": len(lots),
+		})
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "ロット管理機能がサポートされていません")
+	}
+}
+
+// ListLotStocks handles lot stock list requests
+// ロット在庫一覧取得リクエストを処理
+func (h *Handlers) ListLotStocks(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := inventory.LotStockFilter{
+		ItemID:     query.Get("item_id"),
+		LotID:      query.Get("lot_id"),
+		LocationID: query.Get("location_id"),
+		Limit:      listLimit(r),
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			filter.Offset = parsedOffset
+		}
+	}
+	if includeEmptyStr := query.Get("include_empty"); includeEmptyStr != "" {
+		includeEmpty, err := strconv.ParseBool(includeEmptyStr)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "include_emptyパラメータが無効です")
+			return
+		}
+		filter.IncludeEmpty = includeEmpty
+	}
+
+	lotStockManager, ok := h.manager.(inventory.LotStockManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "ロット在庫機能がサポートされていません")
+		return
+	}
+
+	lotStocks, err := lotStockManager.ListLotStocks(r.Context(), filter)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"lot_stocks": lotStocks,
+		"count":      len(lotStocks),
+		"offset":     filter.Offset,
+		"limit":      filter.Limit,
+	})
+}
+
+// GetLotStock handles requests for the quantity of a lot held at a location
+// ロットのロケーションの在庫取得リクエストを処理
+func (h *Handlers) GetLotStock(w http.ResponseWriter, r *http.Request) {
+	lotStockManager, ok := h.manager.(inventory.LotStockManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "ロット在庫機能がサポートされていません")
+		return
+	}
+
+	vars := mux.Vars(r)
+	lotStock, err := lotStockManager.GetLotStock(r.Context(), vars["lotId"], vars["locationId"])
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, lotStock)
+}
+
+// 単位管理ハンドラー
+
+// CreateUnit handles create unit of measure requests
+// 単位作成リクエストを処理
+func (h *Handlers) CreateUnit(w http.ResponseWriter, r *http.Request) {
+	var unit inventory.UnitOfMeasure
+	if !h.decodeJSON(w, r, &unit) {
+		return
+	}
+	if !h.validateRequest(w, inventory.ValidateUnitOfMeasure(&unit)) {
+		return
+	}
+
+	unitManager, ok := h.manager.(inventory.UnitManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "単位機能がサポートされていません")
+		return
+	}
+
+	if err := unitManager.CreateUnit(r.Context(), &unit); err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, unit)
+}
+
+// ListUnits handles list units of measure requests
+// 単位一覧取得リクエストを処理
+func (h *Handlers) ListUnits(w http.ResponseWriter, r *http.Request) {
+	unitManager, ok := h.manager.(inventory.UnitManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "単位機能がサポートされていません")
+		return
+	}
+
+	units, err := unitManager.ListUnits(r.Context())
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, map[string]interface{}{
+		"units": units,
+		"count": len(units),
+	})
+}
+
+// GetUnit handles get unit of measure requests
+// 単位取得リクエストを処理
+func (h *Handlers) GetUnit(w http.ResponseWriter, r *http.Request) {
+	unitManager, ok := h.manager.(inventory.UnitManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "単位機能がサポートされていません")
+		return
+	}
+
+	unit, err := unitManager.GetUnit(r.Context(), mux.Vars(r)["unitCode"])
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, unit)
+}
+
+// DeleteUnit handles delete unit of measure requests
+// 単位削除リクエストを処理
+func (h *Handlers) DeleteUnit(w http.ResponseWriter, r *http.Request) {
+	unitManager, ok := h.manager.(inventory.UnitManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "単位機能がサポートされていません")
+		return
+	}
+
+	if err := unitManager.DeleteUnit(r.Context(), mux.Vars(r)["unitCode"]); err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, map[string]string{
+		"message": "単位が削除されました",
+	})
+}
+
+// ListUnitConversions handles requests for the unit conversions of an item
+// 商品の単位の換算一覧取得リクエストを処理
+func (h *Handlers) ListUnitConversions(w http.ResponseWriter, r *http.Request) {
+	unitManager, ok := h.manager.(inventory.UnitManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "単位機能がサポートされていません")
+		return
+	}
+
+	conversions, err := unitManager.ListUnitConversions(r.Context(), mux.Vars(r)["itemId"])
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, map[string]interface{}{
+		"conversions": conversions,
+		"count":       len(conversions),
+	})
+}
+
+// SetUnitConversion handles set unit conversion requests
+// 商品の単位の換算設定リクエストを処理
+func (h *Handlers) SetUnitConversion(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var req SetUnitConversionRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	conversion := &inventory.UnitConversion{
+		ItemID: vars["itemId"],
+		UoM:    vars["uom"],
+		Factor: req.Factor,
+	}
+	if !h.validateRequest(w, inventory.ValidateUnitConversion(conversion)) {
+		return
+	}
+
+	unitManager, ok := h.manager.(inventory.UnitManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "単位機能がサポートされていません")
+		return
+	}
+
+	if err := unitManager.SetUnitConversion(r.Context(), conversion); err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, conversion)
+}
+
+// DeleteUnitConversion handles delete unit conversion requests
+// 商品の単位の換算削除リクエストを処理
+func (h *Handlers) DeleteUnitConversion(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	unitManager, ok := h.manager.(inventory.UnitManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "単位機能がサポートされていません")
+		return
+	}
+
+	if err := unitManager.DeleteUnitConversion(r.Context(), vars["itemId"], vars["uom"]); err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, map[string]string{
+		"message": "単位の換算が削除されました",
+	})
+}
+
+// キット管理ハンドラー
+
+// ListBillsOfMaterials handles list bills of materials requests
+// 部品表一覧取得リクエストを処理
+func (h *Handlers) ListBillsOfMaterials(w http.ResponseWriter, r *http.Request) {
+	limit := listLimit(r)
+	offset := 0
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			offset = parsedOffset
+		}
+	}
+
+	kitManager, ok := h.manager.(inventory.KitManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "キット機能がサポートされていません")
+		return
+	}
+
+	boms, err := kitManager.ListBillsOfMaterials(r.Context(), offset, limit)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, map[string]interface{}{
+		"boms":   boms,
+		"count":  len(boms),
+		"offset": offset,
+		"limit":  limit,
+	})
+}
+
+// GetBillOfMaterials handles get bill of materials requests
+// 部品表取得リクエストを処理
+func (h *Handlers) GetBillOfMaterials(w http.ResponseWriter, r *http.Request) {
+	kitManager, ok := h.manager.(inventory.KitManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "キット機能がサポートされていません")
+		return
+	}
+
+	bom, err := kitManager.GetBillOfMaterials(r.Context(), mux.Vars(r)["itemId"])
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, bom)
+}
+
+// SetBillOfMaterials handles set bill of materials requests
+// 部品表設定リクエストを処理
+func (h *Handlers) SetBillOfMaterials(w http.ResponseWriter, r *http.Request) {
+	var req SetBillOfMaterialsRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	bom := &inventory.BillOfMaterials{
+		KitItemID:  mux.Vars(r)["itemId"],
+		Components: req.Components,
+	}
+	if !h.validateRequest(w, inventory.ValidateBillOfMaterials(bom)) {
+		return
+	}
+
+	kitManager, ok := h.manager.(inventory.KitManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "キット機能がサポートされていません")
+		return
+	}
+
+	if err := kitManager.SetBillOfMaterials(r.Context(), bom); err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	// 構成品は商品IDの昇順で返す
+	saved, err := kitManager.GetBillOfMaterials(r.Context(), bom.KitItemID)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, saved)
+}
+
+// DeleteBillOfMaterials handles delete bill of materials requests
+// 部品表削除リクエストを処理
+func (h *Handlers) DeleteBillOfMaterials(w http.ResponseWriter, r *http.Request) {
+	kitManager, ok := h.manager.(inventory.KitManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "キット機能がサポートされていません")
+		return
+	}
+
+	if err := kitManager.DeleteBillOfMaterials(r.Context(), mux.Vars(r)["itemId"]); err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, map[string]string{
+		"message": "部品表が削除されました",
+	})
+}
+
+// AssembleKit handles kit assembly requests
+// キット組立リクエストを処理
+func (h *Handlers) AssembleKit(w http.ResponseWriter, r *http.Request) {
+	h.runAssembly(w, r, inventory.AssemblyTypeAssemble)
+}
+
+// DisassembleKit handles kit disassembly requests
+// キット分解リクエストを処理
+func (h *Handlers) DisassembleKit(w http.ResponseWriter, r *http.Request) {
+	h.runAssembly(w, r, inventory.AssemblyTypeDisassemble)
+}
+
+// runAssembly assembles or disassembles the kits of a request
+// リクエストのキットを組立・分解
+func (h *Handlers) runAssembly(w http.ResponseWriter, r *http.Request, assemblyType inventory.AssemblyType) {
+	var req AssemblyRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	if !h.validateRequest(w, req.validate()...) {
+		return
+	}
+
+	kitManager, ok := h.manager.(inventory.KitManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "キット機能がサポートされていません")
+		return
+	}
+
+	run := kitManager.Assemble
+	if assemblyType == inventory.AssemblyTypeDisassemble {
+		run = kitManager.Disassemble
+	}
+	assembly, err := run(r.Context(), req.KitItemID, req.LocationID, req.Quantity, req.Reference)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, assembly)
+}
+
+// ListAssemblies handles list kit assemblies requests
+// キットの組立・分解一覧取得リクエストを処理
+func (h *Handlers) ListAssemblies(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := inventory.AssemblyFilter{
+		KitItemID:  query.Get("kit_item_id"),
+		LocationID: query.Get("location_id"),
+		Limit:      listLimit(r),
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			filter.Offset = parsedOffset
+		}
+	}
+
+	kitManager, ok := h.manager.(inventory.KitManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "キット機能がサポートされていません")
+		return
+	}
+
+	assemblies, err := kitManager.ListAssemblies(r.Context(), filter)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, map[string]interface{}{
+		"assemblies": assemblies,
+		"count":      len(assemblies),
+		"offset":     filter.Offset,
+		"limit":      filter.Limit,
+	})
+}
+
+// GetAssembly handles get kit assembly requests
+// キットの組立・分解取得リクエストを処理
+func (h *Handlers) GetAssembly(w http.ResponseWriter, r *http.Request) {
+	kitManager, ok := h.manager.(inventory.KitManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "キット機能がサポートされていません")
+		return
+	}
+
+	assembly, err := kitManager.GetAssembly(r.Context(), mux.Vars(r)["assemblyId"])
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, assembly)
+}
+
+// 仕入先管理ハンドラー
+
+// ListSuppliers handles list suppliers requests
+// 仕入先一覧取得リクエストを処理
+func (h *Handlers) ListSuppliers(w http.ResponseWriter, r *http.Request) {
+	limit := listLimit(r)
+	offset := 0
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			offset = parsedOffset
+		}
+	}
+
+	supplierManager, ok := h.manager.(inventory.SupplierManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "仕入先管理機能がサポートされていません")
+		return
+	}
+
+	suppliers, err := supplierManager.ListSuppliers(r.Context(), offset, limit)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, map[string]interface{}{
+		"suppliers": suppliers,
+		"count":     len(suppliers),
+		"offset":    offset,
+		"limit":     limit,
+	})
+}
+
+// CreateSupplier handles create supplier requests
+// 仕入先作成リクエストを処理
+func (h *Handlers) CreateSupplier(w http.ResponseWriter, r *http.Request) {
+	var req SupplierRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	supplier := req.supplier(req.ID)
+	if !h.validateRequest(w, inventory.ValidateSupplier(supplier)) {
+		return
+	}
+
+	supplierManager, ok := h.manager.(inventory.SupplierManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "仕入先管理機能がサポートされていません")
+		return
+	}
+
+	if err := supplierManager.CreateSupplier(r.Context(), supplier); err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, supplier)
+}
+
+// GetSupplier handles get supplier requests
+// 仕入先取得リクエストを処理
+func (h *Handlers) GetSupplier(w http.ResponseWriter, r *http.Request) {
+	supplierManager, ok := h.manager.(inventory.SupplierManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "仕入先管理機能がサポートされていません")
+		return
+	}
+
+	supplier, err := supplierManager.GetSupplier(r.Context(), mux.Vars(r)["supplierId"])
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, supplier)
+}
+
+// UpdateSupplier handles update supplier requests
+// 仕入先更新リクエストを処理
+func (h *Handlers) UpdateSupplier(w http.ResponseWriter, r *http.Request) {
+	var req SupplierRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	supplier := req.supplier(mux.Vars(r)["supplierId"])
+	if !h.validateRequest(w, inventory.ValidateSupplier(supplier)) {
+		return
+	}
+
+	supplierManager, ok := h.manager.(inventory.SupplierManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "仕入先管理機能がサポートされていません")
+		return
+	}
+
+	if err := supplierManager.UpdateSupplier(r.Context(), supplier); err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, supplier)
+}
+
+// DeleteSupplier handles delete supplier requests
+// 仕入先削除リクエストを処理
+func (h *Handlers) DeleteSupplier(w http.ResponseWriter, r *http.Request) {
+	supplierManager, ok := h.manager.(inventory.SupplierManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "仕入先管理機能がサポートされていません")
+		return
+	}
+
+	if err := supplierManager.DeleteSupplier(r.Context(), mux.Vars(r)["supplierId"]); err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, map[string]string{
+		"message": "仕入先が削除されました",
+	})
+}
+
+// ListSupplierItems handles requests for the items a supplier supplies
+// 仕入先の商品の仕入条件一覧取得リクエストを処理
+func (h *Handlers) ListSupplierItems(w http.ResponseWriter, r *http.Request) {
+	h.listSupplierItems(w, r, inventory.SupplierItemFilter{SupplierID: mux.Vars(r)["supplierId"]})
+}
+
+// ListItemSuppliers handles requests for the suppliers of an item
+// 商品の仕入先一覧取得リクエストを処理
+func (h *Handlers) ListItemSuppliers(w http.ResponseWriter, r *http.Request) {
+	h.listSupplierItems(w, r, inventory.SupplierItemFilter{ItemID: mux.Vars(r)["itemId"]})
+}
+
+// listSupplierItems lists the purchasing terms matching a filter with the offset and limit of a request
+// リクエストのオフセット・取得件数で条件に一致する仕入条件を取得
+func (h *Handlers) listSupplierItems(w http.ResponseWriter, r *http.Request, filter inventory.SupplierItemFilter) {
+	filter.Limit = listLimit(r)
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			filter.Offset = parsedOffset
+		}
+	}
+
+	supplierManager, ok := h.manager.(inventory.SupplierManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "仕入先管理機能がサポートされていません")
+		return
+	}
+
+	supplierItems, err := supplierManager.ListSupplierItems(r.Context(), filter)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, map[string]interface{}{
+		"supplier_items": supplierItems,
+		"count":          len(supplierItems),
+		"offset":         filter.Offset,
+		"limit":          filter.Limit,
+	})
+}
+
+// GetSupplierItem handles requests for the purchasing terms of an item from a supplier
+// 仕入先の商品の仕入条件取得リクエストを処理
+func (h *Handlers) GetSupplierItem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	supplierManager, ok := h.manager.(inventory.SupplierManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "仕入先管理機能がサポートされていません")
+		return
+	}
+
+	supplierItem, err := supplierManager.GetSupplierItem(r.Context(), vars["supplierId"], vars["itemId"])
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, supplierItem)
+}
+
+// SetSupplierItem handles requests to set the purchasing terms of an item from a supplier
+// 仕入先の商品の仕入条件設定リクエストを処理
+func (h *Handlers) SetSupplierItem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var req SetSupplierItemRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	supplierItem := &inventory.SupplierItem{
+		SupplierID:       vars["supplierId"],
+		ItemID:           vars["itemId"],
+		SupplierSKU:      req.SupplierSKU,
+		UnitCost:         req.UnitCost,
+		LeadTimeDays:     req.LeadTimeDays,
+		MinOrderQuantity: req.MinOrderQuantity,
+		IsPreferred:      req.IsPreferred,
+	}
+	if !h.validateRequest(w, inventory.ValidateSupplierItem(supplierItem)) {
+		return
+	}
+
+	supplierManager, ok := h.manager.(inventory.SupplierManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "仕入先管理機能がサポートされていません")
+		return
+	}
+
+	if err := supplierManager.SetSupplierItem(r.Context(), supplierItem); err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, supplierItem)
+}
+
+// DeleteSupplierItem handles requests to delete the purchasing terms of an item from a supplier
+// 仕入先の商品の仕入条件削除リクエストを処理
+func (h *Handlers) DeleteSupplierItem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	supplierManager, ok := h.manager.(inventory.SupplierManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "仕入先管理機能がサポートされていません")
+		return
+	}
+
+	if err := supplierManager.DeleteSupplierItem(r.Context(), vars["supplierId"], vars["itemId"]); err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, map[string]string{
+		"message": "仕入条件が削除されました",
+	})
+}
+
+// 発注管理ハンドラー
+
+// ListPurchaseOrders handles purchase order list requests
+// 発注一覧取得リクエストを処理
+func (h *Handlers) ListPurchaseOrders(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := inventory.PurchaseOrderFilter{
+		SupplierID: query.Get("supplier_id"),
+		LocationID: query.Get("location_id"),
+		Status:     inventory.PurchaseOrderStatus(query.Get("status")),
+		Limit:      listLimit(r),
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			filter.Offset = parsedOffset
+		}
+	}
+
+	purchaseOrderManager, ok := h.manager.(inventory.PurchaseOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "発注機能がサポートされていません")
+		return
+	}
+
+	orders, err := purchaseOrderManager.ListPurchaseOrders(r.Context(), filter)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"purchase_orders": orders,
+		"count":           len(orders),
+		"offset":          filter.Offset,
+		"limit":           filter.Limit,
+	})
+}
+
+// CreatePurchaseOrder handles create purchase order requests
+// 発注作成リクエストを処理
+func (h *Handlers) CreatePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	var req CreatePurchaseOrderRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	order := req.purchaseOrder()
+	if !h.validateRequest(w, inventory.ValidatePurchaseOrder(order)) {
+		return
+	}
+
+	purchaseOrderManager, ok := h.manager.(inventory.PurchaseOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "発注機能がサポートされていません")
+		return
+	}
+
+	if err := purchaseOrderManager.CreatePurchaseOrder(r.Context(), order); err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":        "発注が作成されました",
+		"purchase_order": order,
+	})
+}
+
+// GetPurchaseOrder handles get purchase order requests
+// 発注取得リクエストを処理
+func (h *Handlers) GetPurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	purchaseOrderManager, ok := h.manager.(inventory.PurchaseOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "発注機能がサポートされていません")
+		return
+	}
+
+	order, err := purchaseOrderManager.GetPurchaseOrder(r.Context(), mux.Vars(r)["purchaseOrderId"])
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, order)
+}
+
+// ApprovePurchaseOrder handles approve purchase order requests
+// 発注承認リクエストを処理
+func (h *Handlers) ApprovePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	purchaseOrderManager, ok := h.manager.(inventory.PurchaseOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "発注機能がサポートされていません")
+		return
+	}
+
+	order, err := purchaseOrderManager.ApprovePurchaseOrder(r.Context(), mux.Vars(r)["purchaseOrderId"])
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":        "発注が承認されました",
+		"purchase_order": order,
+	})
+}
+
+// SendPurchaseOrder handles requests to mark a purchase order as sent to its supplier
+// 発注送付リクエストを処理
+func (h *Handlers) SendPurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	purchaseOrderManager, ok := h.manager.(inventory.PurchaseOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "発注機能がサポートされていません")
+		return
+	}
+
+	order, err := purchaseOrderManager.SendPurchaseOrder(r.Context(), mux.Vars(r)["purchaseOrderId"])
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":        "発注が送付済みになりました",
+		"purchase_order": order,
+	})
+}
+
+// CancelPurchaseOrder handles cancel purchase order requests
+// 発注取消リクエストを処理
+func (h *Handlers) CancelPurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	purchaseOrderManager, ok := h.manager.(inventory.PurchaseOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "発注機能がサポートされていません")
+		return
+	}
+
+	order, err := purchaseOrderManager.CancelPurchaseOrder(r.Context(), mux.Vars(r)["purchaseOrderId"])
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":        "発注が取り消されました",
+		"purchase_order": order,
+	})
+}
+
+// ListGoodsReceipts handles requests for the goods receipts of a purchase order
+// 発注に対する入荷一覧取得リクエストを処理
+func (h *Handlers) ListGoodsReceipts(w http.ResponseWriter, r *http.Request) {
+	purchaseOrderManager, ok := h.manager.(inventory.PurchaseOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "発注機能がサポートされていません")
+		return
+	}
+
+	receipts, err := purchaseOrderManager.ListGoodsReceipts(r.Context(), mux.Vars(r)["purchaseOrderId"])
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"goods_receipts": receipts,
+		"count":          len(receipts),
+	})
+}
+
+// ReceivePurchaseOrder handles requests to record stock received against a purchase order
+// 発注に対する入荷の記録リクエストを処理
+func (h *Handlers) ReceivePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	var req ReceivePurchaseOrderRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	receipt := &inventory.GoodsReceipt{
+		Reference: req.Reference,
+		Lines:     req.Lines,
+	}
+	if !h.validateRequest(w, inventory.ValidateGoodsReceipt(receipt)) {
+		return
+	}
+
+	purchaseOrderManager, ok := h.manager.(inventory.PurchaseOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "発注機能がサポートされていません")
+		return
+	}
+
+	receipt, err := purchaseOrderManager.ReceivePurchaseOrder(r.Context(), mux.Vars(r)["purchaseOrderId"], receipt)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":       "入荷が記録されました",
+		"goods_receipt": receipt,
+	})
+}
+
+// 受注管理ハンドラー
+
+// ListSalesOrders handles sales order list requests
+// 受注一覧取得リクエストを処理
+func (h *Handlers) ListSalesOrders(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := inventory.SalesOrderFilter{
+		LocationID: query.Get("location_id"),
+		Reference:  query.Get("reference"),
+		Status:     inventory.SalesOrderStatus(query.Get("status")),
+		Limit:      listLimit(r),
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			filter.Offset = parsedOffset
+		}
+	}
+
+	salesOrderManager, ok := h.manager.(inventory.SalesOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "受注機能がサポートされていません")
+		return
+	}
+
+	orders, err := salesOrderManager.ListSalesOrders(r.Context(), filter)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"sales_orders": orders,
+		"count":        len(orders),
+		"offset":       filter.Offset,
+		"limit":        filter.Limit,
+	})
+}
+
+// CreateSalesOrder handles create sales order requests
+// 受注作成リクエストを処理
+func (h *Handlers) CreateSalesOrder(w http.ResponseWriter, r *http.Request) {
+	var req CreateSalesOrderRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	order := req.salesOrder()
+	if !h.validateRequest(w, inventory.ValidateSalesOrder(order)) {
+		return
+	}
+
+	salesOrderManager, ok := h.manager.(inventory.SalesOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "受注機能がサポートされていません")
+		return
+	}
+
+	if err := salesOrderManager.CreateSalesOrder(r.Context(), order); err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":     "受注が作成されました",
+		"sales_order": order,
+	})
+}
+
+// GetSalesOrder handles get sales order requests
+// 受注取得リクエストを処理
+func (h *Handlers) GetSalesOrder(w http.ResponseWriter, r *http.Request) {
+	salesOrderManager, ok := h.manager.(inventory.SalesOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "受注機能がサポートされていません")
+		return
+	}
+
+	order, err := salesOrderManager.GetSalesOrder(r.Context(), mux.Vars(r)["salesOrderId"])
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, order)
+}
+
+// AllocateSalesOrder handles requests to allocate stock to a sales order
+// 受注の引当リクエストを処理
+func (h *Handlers) AllocateSalesOrder(w http.ResponseWriter, r *http.Request) {
+	var req AllocateSalesOrderRequest
+	if !h.decodeOptionalJSON(w, r, &req) {
+		return
+	}
+
+	salesOrderManager, ok := h.manager.(inventory.SalesOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "受注機能がサポートされていません")
+		return
+	}
+
+	order, err := salesOrderManager.AllocateSalesOrder(r.Context(), mux.Vars(r)["salesOrderId"], req.AllowPartial)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":     "受注に在庫が引き当てられました",
+		"sales_order": order,
+	})
+}
+
+// ShipSalesOrder handles requests to confirm the shipment of a sales order
+// 受注の出荷確定リクエストを処理
+func (h *Handlers) ShipSalesOrder(w http.ResponseWriter, r *http.Request) {
+	var req ShipSalesOrderRequest
+	if !h.decodeOptionalJSON(w, r, &req) {
+		return
+	}
+	if !h.validateRequest(w, inventory.ValidateShipmentLines(req.Lines)) {
+		return
+	}
+
+	salesOrderManager, ok := h.manager.(inventory.SalesOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "受注機能がサポートされていません")
+		return
+	}
+
+	order, err := salesOrderManager.ShipSalesOrder(r.Context(), mux.Vars(r)["salesOrderId"], req.Lines)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":     "受注の出荷が確定されました",
+		"sales_order": order,
+	})
+}
+
+// CancelSalesOrder handles cancel sales order requests
+// 受注取消リクエストを処理
+func (h *Handlers) CancelSalesOrder(w http.ResponseWriter, r *http.Request) {
+	salesOrderManager, ok := h.manager.(inventory.SalesOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "受注機能がサポートされていません")
+		return
+	}
+
+	order, err := salesOrderManager.CancelSalesOrder(r.Context(), mux.Vars(r)["salesOrderId"])
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":     "受注が取り消されました",
+		"sales_order": order,
+	})
+}
+
+// GetSalesOrderStockStatus handles requests for the stock status of a sales order
+// 受注の在庫状況取得リクエストを処理
+func (h *Handlers) GetSalesOrderStockStatus(w http.ResponseWriter, r *http.Request) {
+	salesOrderManager, ok := h.manager.(inventory.SalesOrderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "受注機能がサポートされていません")
+		return
+	}
+
+	status, err := salesOrderManager.GetSalesOrderStockStatus(r.Context(), mux.Vars(r)["salesOrderId"])
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, status)
+}
+
+// 返品管理ハンドラー
+
+// ListReturns handles return list requests
+// 返品一覧取得リクエストを処理
+func (h *Handlers) ListReturns(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := inventory.ReturnFilter{
+		SalesOrderID: query.Get("sales_order_id"),
+		LocationID:   query.Get("location_id"),
+		Status:       inventory.ReturnStatus(query.Get("status")),
+		Limit:        listLimit(r),
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			filter.Offset = parsedOffset
+		}
+	}
+
+	returnManager, ok := h.manager.(inventory.ReturnManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "返品機能がサポートされていません")
+		return
+	}
+
+	returns, err := returnManager.ListReturns(r.Context(), filter)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"returns": returns,
+		"count":   len(returns),
+		"offset":  filter.Offset,
+		"limit":   filter.Limit,
+	})
+}
+
+// CreateReturn handles create return requests
+// 返品作成リクエストを処理
+func (h *Handlers) CreateReturn(w http.ResponseWriter, r *http.Request) {
+	var req CreateReturnRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	rma := req.returnAuthorization()
+	if !h.validateRequest(w, inventory.ValidateReturn(rma)) {
+		return
+	}
+
+	returnManager, ok := h.manager.(inventory.ReturnManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "返品機能がサポートされていません")
+		return
+	}
+
+	if err := returnManager.CreateReturn(r.Context(), rma); err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message": "返品が作成されました",
+		"return":  rma,
+	})
+}
+
+// GetReturn handles get return requests
+// 返品取得リクエストを処理
+func (h *Handlers) GetReturn(w http.ResponseWriter, r *http.Request) {
+	returnManager, ok := h.manager.(inventory.ReturnManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "返品機能がサポートされていません")
+		return
+	}
+
+	rma, err := returnManager.GetReturn(r.Context(), mux.Vars(r)["returnId"])
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, rma)
+}
+
+// ReceiveReturn handles requests to record returned units that arrived
+// 返品の到着の記録リクエストを処理
+func (h *Handlers) ReceiveReturn(w http.ResponseWriter, r *http.Request) {
+	var req ReceiveReturnRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	if !h.validateRequest(w, inventory.ValidateReturnReceiptLines(req.Lines)) {
+		return
+	}
+
+	returnManager, ok := h.manager.(inventory.ReturnManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "返品機能がサポートされていません")
+		return
+	}
+
+	rma, err := returnManager.ReceiveReturn(r.Context(), mux.Vars(r)["returnId"], req.Lines)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message": "返品の到着が記録されました",
+		"return":  rma,
+	})
+}
+
+// ListReturnDispositions handles requests for the dispositions of a return
+// 返品の処分一覧取得リクエストを処理
+func (h *Handlers) ListReturnDispositions(w http.ResponseWriter, r *http.Request) {
+	returnManager, ok := h.manager.(inventory.ReturnManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "返品機能がサポートされていません")
+		return
+	}
+
+	dispositions, err := returnManager.ListReturnDispositions(r.Context(), mux.Vars(r)["returnId"])
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"dispositions": dispositions,
+		"count":        len(dispositions),
+	})
+}
+
+// DispositionReturn handles requests to restock, refurbish or scrap returned units
+// 返品の処分（再入庫・再整備・廃棄）リクエストを処理
+func (h *Handlers) DispositionReturn(w http.ResponseWriter, r *http.Request) {
+	var req DispositionReturnRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	disposition := req.returnDisposition()
+	if !h.validateRequest(w, inventory.ValidateReturnDisposition(disposition)) {
+		return
+	}
+
+	returnManager, ok := h.manager.(inventory.ReturnManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "返品機能がサポートされていません")
+		return
+	}
+
+	disposition, err := returnManager.DispositionReturn(r.Context(), mux.Vars(r)["returnId"], disposition)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":     "返品が処分されました",
+		"disposition": disposition,
+	})
+}
+
+// CancelReturn handles cancel return requests
+// 返品取消リクエストを処理
+func (h *Handlers) CancelReturn(w http.ResponseWriter, r *http.Request) {
+	returnManager, ok := h.manager.(inventory.ReturnManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "返品機能がサポートされていません")
+		return
+	}
+
+	rma, err := returnManager.CancelReturn(r.Context(), mux.Vars(r)["returnId"])
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message": "返品が取り消されました",
+		"return":  rma,
+	})
+}
+
+// 委託在庫ハンドラー
+
+// ListConsignmentStocks handles consignment stock list requests
+// 委託在庫一覧取得リクエストを処理
+func (h *Handlers) ListConsignmentStocks(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := inventory.ConsignmentStockFilter{
+		OwnerID:    query.Get("owner_id"),
+		ItemID:     query.Get("item_id"),
+		LocationID: query.Get("location_id"),
+		Limit:      listLimit(r),
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			filter.Offset = parsedOffset
+		}
+	}
+	if includeEmptyStr := query.Get("include_empty"); includeEmptyStr != "" {
+		includeEmpty, err := strconv.ParseBool(includeEmptyStr)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "include_emptyパラメータが無効です")
+			return
+		}
+		filter.IncludeEmpty = includeEmpty
+	}
+
+	consignmentManager, ok := h.manager.(inventory.ConsignmentManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "委託在庫機能がサポートされていません")
+		return
+	}
+
+	consignments, err := consignmentManager.ListConsignmentStocks(r.Context(), filter)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"consignment_stocks": consignments,
+		"count":              len(consignments),
+		"offset":             filter.Offset,
+		"limit":              filter.Limit,
+	})
+}
+
+// GetStockOwnership handles requests for the owned and consignment breakdown of a stock
+// 在庫の自社所有・委託在庫の内訳取得リクエストを処理
+func (h *Handlers) GetStockOwnership(w http.ResponseWriter, r *http.Request) {
+	consignmentManager, ok := h.manager.(inventory.ConsignmentManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "委託在庫機能がサポートされていません")
+		return
+	}
+
+	vars := mux.Vars(r)
+	ownership, err := consignmentManager.GetStockOwnership(r.Context(), vars["itemId"], vars["locationId"])
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, ownership)
+}
+
+// ラベル印刷ハンドラー
+
+// RenderLabels handles requests to render printable labels as PDF
+// ラベルのPDF出力リクエストを処理
+//
+// 対象ごとに商品・ロット・ロケーションを取得してからPDFを生成するため、存在しない対象や
+// バーコードにできないIDがある場合は何も出力せずにエラーを返します。
+func (h *Handlers) RenderLabels(w http.ResponseWriter, r *http.Request) {
+	var req LabelRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	if !h.validateRequest(w, req.validate()...) {
+		return
+	}
+	tmpl, _ := req.template()
+
+	labels := make([]label.Label, 0, len(req.Targets))
+	for _, target := range req.Targets {
+		lbl, status, err := h.labelFor(r.Context(), target)
+		if status != 0 {
+			h.sendError(w, status, err.Error())
+			return
+		}
+		if err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+		copies := target.Copies
+		if copies == 0 {
+			copies = 1
+		}
+		for i := 0; i < copies; i++ {
+			labels = append(labels, *lbl)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := label.Render(&buf, tmpl, labels); err != nil {
+		h.validateRequest(w, inventory.NewValidationError("targets", err.Error(), ""))
+		return
+	}
+
+	filename := fmt.Sprintf("labels_%s.pdf", time.Now().Format("20060102"))
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": filename}))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		h.logger.Error("ラベルの送信に失敗しました", zap.Error(err))
+	}
+}
+
+// labelFor fetches the object of a target and builds its label
+// 対象の商品・ロット・ロケーションを取得してラベルを作成
+//
+// マネージャーが対象の種類の管理機能を持たない場合は 501 のステータスとメッセージを返します。
+func (h *Handlers) labelFor(ctx context.Context, target LabelTarget) (*label.Label, int, error) {
+	switch target.Type {
+	case label.KindItem:
+		itemManager, ok := h.manager.(inventory.ItemManager)
+		if !ok {
+			return nil, http.StatusNotImplemented, errors.New("商品管理機能がサポートされていません")
+		}
+		item, err := itemManager.GetItem(ctx, target.ID)
+		if err != nil {
+			return nil, 0, err
+		}
+		return &label.Label{
+			Kind:   label.KindItem,
+			Code:   item.ID,
+			Name:   item.Name,
+			Detail: item.SKU,
+			Fields: map[string]string{"sku": item.SKU, "category": item.Category, "base_uom": item.BaseUoM},
+		}, 0, nil
+
+	case label.KindLot:
+		lotManager, ok := h.manager.(inventory.LotManager)
+		if !ok {
+			return nil, http.StatusNotImplemented, errors.New("ロット管理機能がサポートされていません")
+		}
+		lot, err := lotManager.GetLot(ctx, target.ID)
+		if err != nil {
+			return nil, 0, err
+		}
+		lbl := &label.Label{
+			Kind:   label.KindLot,
+			Code:   lot.ID,
+			Name:   lot.Number,
+			Detail: lot.Number,
+			Fields: map[string]string{"number": lot.Number, "item_id": lot.ItemID},
+		}
+		// 商品名を表示し、ロット番号と有効期限を補足とする
+		if itemManager, ok := h.manager.(inventory.ItemManager); ok {
+			item, err := itemManager.GetItem(ctx, lot.ItemID)
+			if err != nil {
+				return nil, 0, err
+			}
+			lbl.Name = item.Name
+			lbl.Fields["item_name"] = item.Name
+		}
+		if lot.ExpiryDate != nil {
+			expiry := lot.ExpiryDate.Format("2006-01-02")
+			lbl.Detail += " EXP " + expiry
+			lbl.Fields["expiry_date"] = expiry
+		}
+		return lbl, 0, nil
+
+	default:
+		locationManager, ok := h.manager.(inventory.LocationManager)
+		if !ok {
+			return nil, http.StatusNotImplemented, errors.New("ロケーション管理機能がサポートされていません")
+		}
+		location, err := locationManager.GetLocation(ctx, target.ID)
+		if err != nil {
+			return nil, 0, err
+		}
+		return &label.Label{
+			Kind:   label.KindLocation,
+			Code:   location.ID,
+			Name:   location.Name,
+			Detail: location.Type,
+			Fields: map[string]string{"type": location.Type, "address": location.Address},
+		}, 0, nil
+	}
+}
+
+// ListLabelTemplates handles requests for the built-in label templates
+// 組み込みのラベルのテンプレート一覧の取得リクエストを処理
+func (h *Handlers) ListLabelTemplates(w http.ResponseWriter, r *http.Request) {
+	templates := label.Templates()
+	h.sendSuccess(w, map[string]interface{}{
+		"templates": templates,
+		"count":     len(templates),
+	})
+}
+
+// 販売チャネルハンドラー
+
+// SetChannelAllocation handles requests to set the stock of an item at a location earmarked for a sales channel
+// 販売チャネルの確保数量の設定リクエストを処理
+func (h *Handlers) SetChannelAllocation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var req SetChannelAllocationRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	allocation := &inventory.ChannelAllocation{
+		ItemID:     vars["itemId"],
+		LocationID: vars["locationId"],
+		Channel:    vars["channel"],
+		Quantity:   req.Quantity,
+	}
+	if !h.validateRequest(w, inventory.ValidateChannelAllocation(allocation)) {
+		return
+	}
+
+	channelManager, ok := h.manager.(inventory.ChannelManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "販売チャネル機能がサポートされていません")
+		return
+	}
+
+	if err := channelManager.SetChannelAllocation(r.Context(), allocation); err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, allocation)
+}
+
+// GetChannelAvailability handles requests for the available stock of an item at a location by sales channel
+// 販売チャネルごとの利用可能な在庫の取得リクエストを処理
+func (h *Handlers) GetChannelAvailability(w http.ResponseWriter, r *http.Request) {
+	channelManager, ok := h.manager.(inventory.ChannelManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "販売チャネル機能がサポートされていません")
+		return
+	}
+
+	vars := mux.Vars(r)
+	channels, err := channelManager.GetChannelAvailability(r.Context(), vars["itemId"], vars["locationId"])
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, channels)
+}
+
+// 予約管理ハンドラー
+
+// ReserveStock handles reserve stock requests
+// 在庫予約リクエストを処理
+func (h *Handlers) ReserveStock(w http.ResponseWriter, r *http.Request) {
+	var req ReservationRequest
+
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+
+	if !h.validateRequest(w, req.validate()...) {
+		return
+	}
+
+	ctx, ok := h.backorderContext(w, r)
+	if !ok {
+		return
+	}
+	if err := h.manager.Reserve(ctx, req.ItemID, req.LocationID, req.Quantity, req.Reference); err != nil {
+		if !h.sendBackordered(w, err) {
+			h.sendManagerError(w, err)
+		}
+		return
+	}
+
+	h.sendSuccess(w, map[string]string{
+		"message": "在庫が予約されました",
+	})
+}
+
+// ReleaseReservation handles release reservation requests
+// 予約解除リクエストを処理
+func (h *Handlers) ReleaseReservation(w http.ResponseWriter, r *http.Request) {
+	var req ReservationRequest
+
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+
+	if !h.validateRequest(w, req.validate()...) {
+		return
+	}
+
+	ctx := r.Context()
+	if err := h.manager.ReleaseReservation(ctx, req.ItemID, req.LocationID, req.Quantity, req.Reference); err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]string{
+		"message": "予約が解除されました",
+	})
+}
+
+// CreateReservation handles create reservation requests
+// 予約作成リクエストを処理
+func (h *Handlers) CreateReservation(w http.ResponseWriter, r *http.Request) {
+	var req CreateReservationRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+
+	if !h.validateRequest(w, req.validate()...) {
+		return
+	}
+
+	reservationManager, ok := h.manager.(inventory.ReservationManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "予約管理機能がサポートされていません")
+		return
+	}
+
+	reservation := &inventory.Reservation{
+		ItemID:     req.ItemID,
+		LocationID: req.LocationID,
+		Quantity:   req.Quantity,
+		Reference:  req.Reference,
+		ExpiresAt:  req.ExpiresAt,
+		Channel:    req.Channel,
+	}
+	if err := reservationManager.CreateReservation(r.Context(), reservation); err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":     "在庫が予約されました",
+		"reservation": reservation,
+	})
+}
+
+// ListReservations handles list reservations requests
+// 予約一覧取得リクエストを処理
+func (h *Handlers) ListReservations(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := inventory.ReservationFilter{
+		ItemID:     query.Get("item_id"),
+		LocationID: query.Get("location_id"),
+		Status:     inventory.ReservationStatus(query.Get("status")),
+		Reference:  query.Get("reference"),
+		Channel:    query.Get("channel"),
+		Limit:      listLimit(r),
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			filter.Offset = parsedOffset
+		}
+	}
+
+	reservationManager, ok := h.manager.(inventory.ReservationManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "予約管理機能がサポートされていません")
+		return
+	}
+
+	reservations, err := reservationManager.ListReservations(r.Context(), filter)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"reservations": reservations,
+		"count":        len(reservations),
+		"offset":       filter.Offset,
+		"limit":        filter.Limit,
+	})
+}
+
+// GetReservation handles get reservation requests
+// 予約取得リクエストを処理
+func (h *Handlers) GetReservation(w http.ResponseWriter, r *http.Request) {
+	reservationID := mux.Vars(r)["reservationId"]
+
+	reservationManager, ok := h.manager.(inventory.ReservationManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "予約管理機能がサポートされていません")
+		return
+	}
+
+	reservation, err := reservationManager.GetReservation(r.Context(), reservationID)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, reservation)
+}
+
+// GetReservationsByReference handles get reservations by reference requests
+// 参照番号別の予約取得リクエストを処理
+func (h *Handlers) GetReservationsByReference(w http.ResponseWriter, r *http.Request) {
+	reference := mux.Vars(r)["ref"]
+
+	reservationManager, ok := h.manager.(inventory.ReservationManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "予約管理機能がサポートされていません")
+		return
+	}
+
+	reservations, err := reservationManager.GetReservationsByReference(r.Context(), reference)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"reservations": reservations,
+		"reference":    reference,
+		"count":        len(reservations),
+	})
+}
+
+// ReleaseReservationsByReference handles release requests for the reservations of a reference
+// 参照番号別の予約解除リクエストを処理
+func (h *Handlers) ReleaseReservationsByReference(w http.ResponseWriter, r *http.Request) {
+	reference := mux.Vars(r)["ref"]
+
+	reservationManager, ok := h.manager.(inventory.ReservationManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "予約管理機能がサポートされていません")
+		return
+	}
+
+	reservations, err := reservationManager.ReleaseReservationsByReference(r.Context(), reference)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"reservations": reservations,
+		"reference":    reference,
+		"count":        len(reservations),
+	})
+}
+
+// ReleaseReservationByID handles release requests for a single reservation
+// 予約IDを指定した予約解除リクエストを処理
+func (h *Handlers) ReleaseReservationByID(w http.ResponseWriter, r *http.Request) {
+	reservationID := mux.Vars(r)["reservationId"]
+
+	reservationManager, ok := h.manager.(inventory.ReservationManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "予約管理機能がサポートされていません")
+		return
+	}
+
+	reservation, err := reservationManager.ReleaseReservationByID(r.Context(), reservationID)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":     "予約が解除されました",
+		"reservation": reservation,
+	})
+}
+
+// FulfillReservation handles requests to ship the stock of a reservation
+// 予約の出庫リクエストを処理
+func (h *Handlers) FulfillReservation(w http.ResponseWriter, r *http.Request) {
+	reservationID := mux.Vars(r)["reservationId"]
+
+	var req FulfillReservationRequest
+	if !h.decodeOptionalJSON(w, r, &req) {
+		return
+	}
+	if !h.validateRequest(w, req.validate()...) {
+		return
+	}
+
+	reservationManager, ok := h.manager.(inventory.ReservationManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "予約管理機能がサポートされていません")
+		return
+	}
+
+	tx, err := reservationManager.FulfillReservation(r.Context(), reservationID, req.Reference)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":        "予約の在庫を出庫しました",
+		"reservation_id": reservationID,
+		"transaction":    tx,
+	})
+}
+
+// バックオーダー管理ハンドラー
+
+// ListBackorders handles list backorders requests
+// バックオーダー一覧取得リクエストを処理
+func (h *Handlers) ListBackorders(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := inventory.BackorderFilter{
+		ItemID:     query.Get("item_id"),
+		LocationID: query.Get("location_id"),
+		Status:     inventory.BackorderStatus(query.Get("status")),
+		Reference:  query.Get("reference"),
+		Limit:      listLimit(r),
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			filter.Offset = parsedOffset
+		}
+	}
+
+	backorderManager, ok := h.manager.(inventory.BackorderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "バックオーダー機能がサポートされていません")
+		return
+	}
+
+	backorders, err := backorderManager.ListBackorders(r.Context(), filter)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"backorders": backorders,
+		"count":      len(backorders),
+		"offset":     filter.Offset,
+		"limit":      filter.Limit,
+	})
+}
+
+// GetBackorder handles get backorder requests
+// バックオーダー取得リクエストを処理
+func (h *Handlers) GetBackorder(w http.ResponseWriter, r *http.Request) {
+	backorderID := mux.Vars(r)["backorderId"]
+
+	backorderManager, ok := h.manager.(inventory.BackorderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "バックオーダー機能がサポートされていません")
+		return
+	}
+
+	backorder, err := backorderManager.GetBackorder(r.Context(), backorderID)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, backorder)
+}
+
+// CancelBackorder handles cancel backorder requests
+// バックオーダー取消リクエストを処理
+func (h *Handlers) CancelBackorder(w http.ResponseWriter, r *http.Request) {
+	backorderID := mux.Vars(r)["backorderId"]
+
+	backorderManager, ok := h.manager.(inventory.BackorderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "バックオーダー機能がサポートされていません")
+		return
+	}
+
+	backorder, err := backorderManager.CancelBackorder(r.Context(), backorderID)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":   "バックオーダーを取り消しました",
+		"backorder": backorder,
+	})
+}
+
+// AllocateBackorders handles allocate backorders requests
+// バックオーダー引当リクエストを処理
+func (h *Handlers) AllocateBackorders(w http.ResponseWriter, r *http.Request) {
+	var req AllocateBackordersRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	if !h.validateRequest(w, req.validate()...) {
+		return
+	}
+
+	backorderManager, ok := h.manager.(inventory.BackorderManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "バックオーダー機能がサポートされていません")
+		return
+	}
+
+	allocated, err := backorderManager.AllocateBackorders(r.Context(), req.ItemID, req.LocationID)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"backorders": allocated,
+		"count":      len(allocated),
+	})
+}
+
+// 発注点管理ハンドラー
+
+// ListReorderPoints handles list reorder points requests
+// 発注点一覧取得リクエストを処理
+func (h *Handlers) ListReorderPoints(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := inventory.ReorderPointFilter{
+		ItemID:     query.Get("item_id"),
+		LocationID: query.Get("location_id"),
+		Limit:      listLimit(r),
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			filter.Offset = parsedOffset
+		}
+	}
+
+	reorderPointManager, ok := h.manager.(inventory.ReorderPointManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "発注点管理機能がサポートされていません")
+		return
+	}
+
+	reorderPoints, err := reorderPointManager.ListReorderPoints(r.Context(), filter)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"reorder_points": reorderPoints,
+		"count":          len(reorderPoints),
+		"offset":         filter.Offset,
+		"limit":          filter.Limit,
+	})
+}
+
+// GetReorderPoint handles get reorder point requests
+// 発注点取得リクエストを処理
+func (h *Handlers) GetReorderPoint(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	reorderPointManager, ok := h.manager.(inventory.ReorderPointManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "発注点管理機能がサポートされていません")
+		return
+	}
+
+	reorderPoint, err := reorderPointManager.GetReorderPoint(r.Context(), vars["itemId"], vars["locationId"])
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, reorderPoint)
+}
+
+// SetReorderPoint handles set reorder point requests
+// 発注点設定リクエストを処理
+func (h *Handlers) SetReorderPoint(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var req SetReorderPointRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	reorderPoint := &inventory.ReorderPoint{
+		ItemID:          vars["itemId"],
+		LocationID:      vars["locationId"],
+		ReorderPointQty: req.ReorderPoint,
+		MinQty:          req.MinQty,
+		MaxQty:          req.MaxQty,
+	}
+	if !h.validateRequest(w, inventory.ValidateReorderPoint(reorderPoint)) {
+		return
+	}
+
+	reorderPointManager, ok := h.manager.(inventory.ReorderPointManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "発注点管理機能がサポートされていません")
+		return
+	}
+
+	if err := reorderPointManager.SetReorderPoint(r.Context(), reorderPoint); err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, reorderPoint)
+}
+
+// DeleteReorderPoint handles delete reorder point requests
+// 発注点削除リクエストを処理
+func (h *Handlers) DeleteReorderPoint(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	reorderPointManager, ok := h.manager.(inventory.ReorderPointManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "発注点管理機能がサポートされていません")
+		return
+	}
+
+	if err := reorderPointManager.DeleteReorderPoint(r.Context(), vars["itemId"], vars["locationId"]); err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, map[string]string{
+		"message": "発注点が削除されました",
+	})
+}
+
+// アラートルール管理ハンドラー
+
+// ListAlertRules handles list alert rules requests
+// アラートルール一覧取得リクエストを処理
+func (h *Handlers) ListAlertRules(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := inventory.AlertRuleFilter{
+		ItemID:     query.Get("item_id"),
+		LocationID: query.Get("location_id"),
+		Limit:      listLimit(r),
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			filter.Offset = parsedOffset
+		}
+	}
+	if enabledStr := query.Get("enabled"); enabledStr != "" {
+		enabledOnly, err := strconv.ParseBool(enabledStr)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "enabledパラメータが無効です")
+			return
+		}
+		filter.EnabledOnly = enabledOnly
+	}
+
+	alertRuleManager, ok := h.manager.(inventory.AlertRuleManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "アラートルール管理機能がサポートされていません")
+		return
+	}
+
+	rules, err := alertRuleManager.ListAlertRules(r.Context(), filter)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"alert_rules": rules,
+		"count":       len(rules),
+		"offset":      filter.Offset,
+		"limit":       filter.Limit,
+	})
+}
+
+// CreateAlertRule handles create alert rule requests
+// アラートルール作成リクエストを処理
+func (h *Handlers) CreateAlertRule(w http.ResponseWriter, r *http.Request) {
+	var req AlertRuleRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	rule := req.alertRule("")
+	if !h.validateRequest(w, inventory.ValidateAlertRule(rule)) {
+		return
+	}
+
+	alertRuleManager, ok := h.manager.(inventory.AlertRuleManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "アラートルール管理機能がサポートされていません")
+		return
+	}
+
+	if err := alertRuleManager.CreateAlertRule(r.Context(), rule); err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, rule)
+}
+
+// GetAlertRule handles get alert rule requests
+// アラートルール取得リクエストを処理
+func (h *Handlers) GetAlertRule(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	alertRuleManager, ok := h.manager.(inventory.AlertRuleManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "アラートルール管理機能がサポートされていません")
+		return
+	}
+
+	rule, err := alertRuleManager.GetAlertRule(r.Context(), vars["ruleId"])
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, rule)
+}
+
+// UpdateAlertRule handles update alert rule requests
+// アラートルール更新リクエストを処理
+func (h *Handlers) UpdateAlertRule(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var req AlertRuleRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	rule := req.alertRule(vars["ruleId"])
+	if !h.validateRequest(w, inventory.ValidateAlertRule(rule)) {
+		return
+	}
+
+	alertRuleManager, ok := h.manager.(inventory.AlertRuleManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "アラートルール管理機能がサポートされていません")
+		return
+	}
+
+	if err := alertRuleManager.UpdateAlertRule(r.Context(), rule); err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, rule)
+}
+
+// DeleteAlertRule handles delete alert rule requests
+// アラートルール削除リクエストを処理
+func (h *Handlers) DeleteAlertRule(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	alertRuleManager, ok := h.manager.(inventory.AlertRuleManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "アラートルール管理機能がサポートされていません")
+		return
+	}
+
+	if err := alertRuleManager.DeleteAlertRule(r.Context(), vars["ruleId"]); err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, map[string]string{
+		"message": "アラートルールが削除されました",
+	})
+}
+
+// EvaluateAlertRules handles requests to evaluate every enabled alert rule immediately
+// 有効な全てのアラートルールの即時評価リクエストを処理
+func (h *Handlers) EvaluateAlertRules(w http.ResponseWriter, r *http.Request) {
+	alertRuleManager, ok := h.manager.(inventory.AlertRuleManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "アラートルール管理機能がサポートされていません")
+		return
+	}
+
+	created, err := alertRuleManager.EvaluateAlertRules(r.Context())
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, map[string]interface{}{
+		"alerts_created": created,
+	})
+}
+
+// 履歴管理の追加ハンドラー
+
+// GetHistoryByLocation handles get history by location requests
+// ロケーション別履歴取得リクエストを処理
+func (h *Handlers) GetHistoryByLocation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	locationID := vars["locationId"]
+
+	// limitパラメータの取得
+	limit := 50 // デフォルト
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	history, err := h.manager.GetHistoryByLocation(r.Context(), locationID, limit)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"history":     history,
+		"location_id": locationID,
+		"limit":       limit,
+		"count":       len(history),
+	})
+}
+
+// GetTransaction handles get transaction requests
+// トランザクション取得リクエストを処理
+func (h *Handlers) GetTransaction(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	txID := vars["txId"]
+
+	tx, err := h.manager.GetTransaction(r.Context(), txID)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, tx)
+}
+
+// ReverseTransaction handles requests to reverse a transaction with a compensating movement
+// 打ち消しの在庫移動によるトランザクション取消リクエストを処理
+func (h *Handlers) ReverseTransaction(w http.ResponseWriter, r *http.Request) {
+	var req ReverseTransactionRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	if !h.validateRequest(w, req.validate()...) {
+		return
+	}
+
+	reverser, ok := h.manager.(inventory.TransactionReverser)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "トランザクションの取消機能がサポートされていません")
+		return
+	}
+
+	tx, err := reverser.ReverseTransaction(r.Context(), mux.Vars(r)["txId"], req.Reason)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":     "トランザクションが取り消されました",
+		"transaction": tx,
+	})
+}
+
+// GetHistoryByReference handles get history by reference requests
+// 参照番号別履歴取得リクエストを処理
+func (h *Handlers) GetHistoryByReference(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	reference := vars["ref"]
+
+	history, err := h.manager.GetHistoryByReference(r.Context(), reference)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"history":   history,
+		"reference": reference,
+		"count":     len(history),
+	})
+}
+
+// SearchHistoryByMetadata handles history search by metadata requests
+// メタデータによる履歴検索リクエストを処理
+func (h *Handlers) SearchHistoryByMetadata(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	key := query.Get("key")
+	value := query.Get("value")
+	if key == "" {
+		h.sendError(w, http.StatusBadRequest, "keyパラメータを指定してください")
+		return
+	}
+
+	// limitパラメータの取得
+	limit := 50 // デフォルト
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	history, err := h.manager.SearchHistoryByMetadata(r.Context(), key, value, limit)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"history": history,
+		"key":     key,
+		"value":   value,
+		"limit":   limit,
+		"count":   len(history),
+	})
+}
+
+// GetHistoryByDateRange handles get history by date range requests
+// 日付範囲別履歴取得リクエストを処理
+func (h *Handlers) GetHistoryByDateRange(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	itemID := vars["itemId"]
+
+	// 日付パラメータを取得
+	fromStr := r.URL.Query().Get("from")
+	toStr := r.URL.Query().Get("to")
+
+	if fromStr == "" || toStr == "" {
+		h.sendError(w, http.StatusBadRequest, "from及びtoパラメータが必要です（形式：2006-01-02）")
+		return
+	}
+
+	from, err := time.Parse("2006-01-02", fromStr)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なfrom日付形式です（形式：2006-01-02）")
+		return
+	}
+
+	to, err := time.Parse("2006-01-02", toStr)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "無効なto日付形式です（形式：2006-01-02）")
+		return
+	}
+
+	// 終了日を23:59:59に設定
+	to = to.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
+
+	history, err := h.manager.GetHistoryByDateRange(r.Context(), itemID, from, to)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"history": history,
+		"item_id": itemID,
+		"from":    fromStr,
+		"to":      toStr,
+		"count":   len(history),
+	})
+}
+
+// バッチ管理の追加ハンドラー
+
+// GetBatchStatus handles get batch status requests
+// バッチステータス取得リクエストを処理
+func (h *Handlers) GetBatchStatus(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	batchID := vars["batchId"]
+
+	batch, err := h.manager.GetBatchStatus(r.Context(), batchID)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, batch)
+}
+
+// CancelBatch handles cancel asynchronous batch requests
+// 非同期バッチの取り消しリクエストを処理
+func (h *Handlers) CancelBatch(w http.ResponseWriter, r *http.Request) {
+	if h.batches == nil {
+		h.sendError(w, http.StatusNotImplemented, "非同期バッチが設定されていません")
+		return
+	}
+
+	batch, err := h.batches.Cancel(r.Context(), mux.Vars(r)["batchId"])
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, batch)
+}
+
+// 在庫評価エンジンハンドラー
+
+// CalculateValue handles calculate inventory value requests
+// 在庫評価計算リクエストを処理
+func (h *Handlers) CalculateValue(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	itemID := vars["itemId"]
+	locationID := vars["locationId"]
+
+	// 評価方法を取得
+	methodStr := r.URL.Query().Get("method")
+	if methodStr == "" {
+		methodStr = string(inventory.ValuationMethodFIFO) // デフォルト
+	}
+
+	method := inventory.ValuationMethod(methodStr)
+
+	// ValuationEngineを使用して在庫評価を計算
+	if valuationEngine, ok := h.manager.(inventory.ValuationEngine); ok {
+		value, err := valuationEngine.CalculateValue(r.Context(), itemID, locationID, method)
+		if err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+		h.sendSuccess(w, map[string]interface{}{
+			"value":       value,
+			"item_id":    itemID,
+			"location_id": locationID,
+			"method":      method,
+		})
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "在庫評価機能がサポートされていません")
+	}
+}
+
+// CalculateTotalValue handles calculate total inventory value requests
+// 全体在庫評価計算リクエストを処理
+func (h *Handlers) CalculateTotalValue(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	locationID := vars["locationId"]
+
+	// 評価方法を取得
+	methodStr := r.URL.Query().Get("method")
+	if methodStr == "" {
+		methodStr = string(inventory.ValuationMethodFIFO) // デフォルト
+	}
+
+	method := inventory.ValuationMethod(methodStr)
+
+	// ValuationEngineを使用して全体在庫評価を計算
+	if valuationEngine, ok := h.manager.(inventory.ValuationEngine); ok {
+		totalValue, err := valuationEngine.CalculateTotalValue(r.Context(), locationID, method)
+		if err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+		h.sendSuccess(w, map[string]interface{}{
+			"total_value": totalValue,
+			"location_id": locationID,
+			"method":      method,
+		})
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "在庫評価機能がサポートされていません")
+	}
+}
+
+// GetAverageCost handles get average cost requests
+// 平均原価取得リクエストを処理
+func (h *Handlers) GetAverageCost(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	itemID := vars["itemId"]
+
+	// ValuationEngineを使用して平均原価を取得
+	if valuationEngine, ok := h.manager.(inventory.ValuationEngine); ok {
+		avgCost, err := valuationEngine.GetAverageCost(r.Context(), itemID)
+		if err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+		h.sendSuccess(w, map[string]interface{}{
+			"average_cost": avgCost,
+			"item_id":      itemID,
+		})
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "在庫評価機能がサポートされていません")
+	}
+}
+
+// 在庫分析エンジンハンドラー
+
+// CalculateABCClassification handles ABC classification requests
+// ABC分析リクエストを処理
+func (h *Handlers) CalculateABCClassification(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	locationID := vars["locationId"]
+
+	// AnalyticsEngineを使用してABC分析を実行
+	if analyticsEngine, ok := h.manager.(inventory.AnalyticsEngine); ok {
+		classification, err := analyticsEngine.CalculateABCClassification(r.Context(), locationID)
+		if err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+		h.sendSuccess(w, map[string]interface{}{
+			"classification": classification,
+			"location_id":    locationID,
+			"count":          len(classification),
+		})
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "在庫分析機能がサポートされていません")
+	}
+}
+
+// GetTurnoverRate handles turnover rate requests
+// 回転率取得リクエストを処理
+func (h *Handlers) GetTurnoverRate(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	itemID := vars["itemId"]
+
+	// 期間パラメータを取得（日数）
+	periodDays := 30 // デフォルト30日
+	if periodStr := r.URL.Query().Get("period_days"); periodStr != "" {
+		if parsedDays, err := strconv.Atoi(periodStr); err == nil && parsedDays > 0 {
+			periodDays = parsedDays
+		}
+	}
+
+	period := time.Duration(periodDays) * 24 * time.Hour
+
+	// AnalyticsEngineを使用して回転率を取得
+	if analyticsEngine, ok := h.manager.(inventory.AnalyticsEngine); ok {
+		turnoverRate, err := analyticsEngine.GetTurnoverRate(r.Context(), itemID, period)
+		if err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+		h.sendSuccess(w, map[string]interface{}{
+			"turnover_rate": turnoverRate,
+			"item_id":       itemID,
+			"period_days":   periodDays,
+		})
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "在庫分析機能がサポートされていません")
+	}
+}
+
+// GetSlowMovingItems handles slow moving items requests
+// 低回転商品取得リクエストを処理
+func (h *Handlers) GetSlowMovingItems(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	locationID := vars["locationId"]
+
+	// 闾値パラメータを取得（日数）
+	thresholdDays := 90 // デフォルト90日
+	if thresholdStr := r.URL.Query().Get("threshold_days"); thresholdStr != "" {
+		if parsedDays, err := strconv.Atoi(thresholdStr); err == nil && parsedDays > 0 {
+			thresholdDays = parsedDays
+		}
+	}
+
+	threshold := time.Duration(thresholdDays) * 24 * time.Hour
+
+	// AnalyticsEngineを使用して低回転商品を取得
+	if analyticsEngine, ok := h.manager.(inventory.AnalyticsEngine); ok {
+		slowMovingItems, err := analyticsEngine.GetSlowMovingItems(r.Context(), locationID, threshold)
+		if err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+		h.sendSuccess(w, map[string]interface{}{
+			"slow_moving_items": slowMovingItems,
+			"location_id":       locationID,
+			"threshold_days":    thresholdDays,
+			"count":             len(slowMovingItems),
+		})
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "在庫分析機能がサポートされていません")
+	}
+}
+
+// GenerateStockReport handles stock report generation requests
+// 在庫レポート生成リクエストを処理
+func (h *Handlers) GenerateStockReport(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	locationID := vars["locationId"]
+
+	// レポートタイプを取得
+	reportTypeStr := r.URL.Query().Get("type")
+	if reportTypeStr == "" {
+		reportTypeStr = string(inventory.ReportTypeStock) // デフォルト
+	}
+
+	reportType := inventory.ReportType(reportTypeStr)
+
+	// AnalyticsEngineを使用してレポートを生成
+	if analyticsEngine, ok := h.manager.(inventory.AnalyticsEngine); ok {
+		reportData, err := analyticsEngine.GenerateStockReport(r.Context(), locationID, reportType)
+		if err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+
+		// レポートをバイナリデータとして返す
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=stock_report_%s_%s.pdf", locationID, reportType))
+		w.WriteHeader(http.StatusOK)
+		w.Write(reportData)
+	} else {
+		h.sendError(w, http.StatusNotImplemented, "在庫分析機能がサポートされていません")
+	}
+}
+
+// ImportItems handles item CSV import requests
+// 商品のCSV取り込みリクエストを処理
+func (h *Handlers) ImportItems(w http.ResponseWriter, r *http.Request) {
+	importer, ok := h.manager.(inventory.Importer)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "CSV取り込みがサポートされていません")
+		return
+	}
+	importCSV(h, w, r, csvimport.ReadItems, importer.ImportItems)
+}
+
+// ImportOpeningBalances handles opening stock balance CSV import requests
+// 期首残高のCSV取り込みリクエストを処理
+func (h *Handlers) ImportOpeningBalances(w http.ResponseWriter, r *http.Request) {
+	importer, ok := h.manager.(inventory.Importer)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "CSV取り込みがサポートされていません")
+		return
+	}
+	importCSV(h, w, r, csvimport.ReadOpeningBalances, importer.ImportOpeningBalances)
+}
+
+// importCSV reads the CSV file of a multipart request and imports its rows
+// multipartリクエストのCSVファイルを読み取り、各行を取り込む
+//
+// CSVとして読み取れない行がある場合は残りの行の検証のみ行います。1行でもエラーがある場合は
+// 何も取り込まず、422 と行ごとのエラーを含む取り込み結果を返します。
+func importCSV[T any](h *Handlers, w http.ResponseWriter, r *http.Request,
+	read func(io.Reader) ([]T, []inventory.ImportRowError, error),
+	load func(context.Context, []T, bool) (*inventory.ImportResult, error)) {
+	dryRun, ok := h.dryRunRequested(w, r)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.sendError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("ファイルサイズが上限（%dMB）を超えています", maxImportBytes>>20))
+			return
+		}
+		h.sendError(w, http.StatusBadRequest, "CSVファイルを multipart/form-data の file フィールドで指定してください")
+		return
+	}
+	defer file.Close()
+
+	rows, rowErrors, err := read(file)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := load(r.Context(), rows, dryRun || len(rowErrors) > 0)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	if len(rowErrors) > 0 {
+		result.Rows += len(rowErrors)
+		result.DryRun = dryRun
+		result.Errors = append(result.Errors, rowErrors...)
+		sort.SliceStable(result.Errors, func(i, j int) bool {
+			return result.Errors[i].Line < result.Errors[j].Line
+		})
+	}
+	if len(result.Errors) > 0 {
+		h.sendErrorWithData(w, http.StatusUnprocessableEntity,
+			fmt.Sprintf("%d行にエラーがあるため取り込みませんでした", len(result.Errors)), result)
+		return
+	}
+
+	h.sendSuccess(w, result)
+}
+
+// GetSummary handles inventory dashboard summary requests
+// 在庫ダッシュボードの集計リクエストを処理
+func (h *Handlers) GetSummary(w http.ResponseWriter, r *http.Request) {
+	reader, ok := h.manager.(inventory.SummaryReader)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "在庫の集計がサポートされていません")
+		return
+	}
+
+	summary, err := reader.GetSummary(r.Context())
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, summary)
+}
+
+// CreateWebhook handles webhook subscription creation requests
+// Webhookサブスクリプション作成リクエストを処理
+func (h *Handlers) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	if h.webhooks == nil {
+		h.sendError(w, http.StatusNotImplemented, "Webhook機能がサポートされていません")
+		return
+	}
+
+	var req WebhookRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+
+	now := time.Now()
+	sub := webhooks.Subscription{
+		ID:         inventory.NewTransactionID(),
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		Secret:     req.Secret,
+		Active:     req.Active == nil || *req.Active,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := sub.Validate(); err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// シークレット生成（もし指定されていない場合）
+	if sub.Secret == "" {
+		secret, err := webhooks.NewSecret()
+		if err != nil {
+			h.sendManagerError(w, err)
+			return
+		}
+		sub.Secret = secret
+	}
+
+	if err := h.webhooks.CreateSubscription(r.Context(), &sub); err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	// シークレットは作成時のみ返却する
+	h.sendSuccess(w, map[string]interface{}{
+		"message": "Webhookが作成されました",
+		"webhook": sub,
+	})
+}
+
+// ListWebhooks handles webhook subscription list requests
+// Webhookサブスクリプション一覧リクエストを処理
+func (h *Handlers) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	if h.webhooks == nil {
+		h.sendError(w, http.StatusNotImplemented, "Webhook機能がサポートされていません")
+		return
+	}
+
+	subs, err := h.webhooks.ListSubscriptions(r.Context())
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	for i := range subs {
+		subs[i].Secret = ""
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"webhooks": subs,
+		"count":    len(subs),
+	})
+}
+
+// GetWebhook handles get webhook subscription requests
+// Webhookサブスクリプション取得リクエストを処理
+func (h *Handlers) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	if h.webhooks == nil {
+		h.sendError(w, http.StatusNotImplemented, "Webhook機能がサポートされていません")
+		return
+	}
+
+	sub, err := h.webhooks.GetSubscription(r.Context(), mux.Vars(r)["webhookId"])
+	if err != nil {
+		h.sendWebhookError(w, err)
+		return
+	}
+	sub.Secret = ""
+
+	h.sendSuccess(w, sub)
+}
+
+// UpdateWebhook handles webhook subscription update requests
+// Webhookサブスクリプション更新リクエストを処理
+func (h *Handlers) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	if h.webhooks == nil {
+		h.sendError(w, http.StatusNotImplemented, "Webhook機能がサポートされていません")
+		return
+	}
+
+	var req WebhookRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+
+	sub := webhooks.Subscription{
+		ID:         mux.Vars(r)["webhookId"],
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		Secret:     req.Secret,
+		Active:     req.Active == nil || *req.Active,
+		UpdatedAt:  time.Now(),
+	}
+	if err := sub.Validate(); err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.webhooks.UpdateSubscription(r.Context(), &sub); err != nil {
+		h.sendWebhookError(w, err)
+		return
+	}
+
+	updated, err := h.webhooks.GetSubscription(r.Context(), sub.ID)
+	if err != nil {
+		h.sendWebhookError(w, err)
+		return
+	}
+	updated.Secret = ""
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message": "Webhookが更新されました",
+		"webhook": updated,
+	})
+}
+
+// DeleteWebhook handles webhook subscription deletion requests
+// Webhookサブスクリプション削除リクエストを処理
+func (h *Handlers) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if h.webhooks == nil {
+		h.sendError(w, http.StatusNotImplemented, "Webhook機能がサポートされていません")
+		return
+	}
+
+	if err := h.webhooks.DeleteSubscription(r.Context(), mux.Vars(r)["webhookId"]); err != nil {
+		h.sendWebhookError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]string{
+		"message": "Webhookが削除されました",
+	})
+}
+
+// ListWebhookDeliveries handles webhook delivery log requests
+// Webhook配信ログ取得リクエストを処理
+func (h *Handlers) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	if h.webhooks == nil {
+		h.sendError(w, http.StatusNotImplemented, "Webhook機能がサポートされていません")
+		return
+	}
+
+	webhookID := mux.Vars(r)["webhookId"]
+	if _, err := h.webhooks.GetSubscription(r.Context(), webhookID); err != nil {
+		h.sendWebhookError(w, err)
+		return
+	}
+
+	// デフォルトの制限
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 500 {
+			limit = parsedLimit
+		}
+	}
+
+	deliveries, err := h.webhooks.ListDeliveries(r.Context(), webhookID, limit)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"webhook_id": webhookID,
+		"deliveries": deliveries,
+		"count":      len(deliveries),
+	})
+}
+
+// sendWebhookError maps webhook store errors to HTTP responses
+// WebhookストアのエラーをHTTPレスポンスに変換
+func (h *Handlers) sendWebhookError(w http.ResponseWriter, err error) {
+	if err == webhooks.ErrSubscriptionNotFound {
+		h.sendError(w, http.StatusNotFound, "Webhookが見つかりません")
+		return
+	}
+	h.sendManagerError(w, err)
+}
+
+// ListDeadLetters handles dead-lettered event list requests
+// デッドレターのイベント一覧リクエストを処理
+func (h *Handlers) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	if h.eventRetry == nil {
+		h.sendError(w, http.StatusNotImplemented, "イベント再試行機能がサポートされていません")
+		return
+	}
+
+	// デフォルトの制限
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 500 {
+			limit = parsedLimit
+		}
+	}
+
+	deadLetters, err := h.eventRetry.ListDead(r.Context(), limit)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"dead_letters": deadLetters,
+		"count":        len(deadLetters),
+	})
+}
+
+// ReplayDeadLetter handles dead-lettered event replay requests
+// デッドレターのイベント再発行リクエストを処理
+func (h *Handlers) ReplayDeadLetter(w http.ResponseWriter, r *http.Request) {
+	if h.eventRetry == nil {
+		h.sendError(w, http.StatusNotImplemented, "イベント再試行機能がサポートされていません")
+		return
+	}
+
+	if err := h.eventRetry.ReplayDead(r.Context(), mux.Vars(r)["eventId"]); err != nil {
+		if err == events.ErrFailedEventNotFound {
+			h.sendError(w, http.StatusNotFound, "デッドレターのイベントが見つかりません")
+		} else {
+			h.sendError(w, http.StatusBadGateway, err.Error())
+		}
+		return
+	}
+
+	h.sendSuccess(w, map[string]string{
+		"message": "イベントが再発行されました",
+	})
+}
+
+// DeleteDeadLetter handles dead-lettered event deletion requests
+// デッドレターのイベント削除リクエストを処理
+func (h *Handlers) DeleteDeadLetter(w http.ResponseWriter, r *http.Request) {
+	if h.eventRetry == nil {
+		h.sendError(w, http.StatusNotImplemented, "イベント再試行機能がサポートされていません")
+		return
+	}
+
+	if err := h.eventRetry.DeleteDead(r.Context(), mux.Vars(r)["eventId"]); err != nil {
+		if err == events.ErrFailedEventNotFound {
+			h.sendError(w, http.StatusNotFound, "デッドレターのイベントが見つかりません")
+		} else {
+			h.sendManagerError(w, err)
+		}
+		return
+	}
+
+	h.sendSuccess(w, map[string]string{
+		"message": "イベントが削除されました",
+	})
+}
+
+// ReplayEventsRequest represents request to replay the transaction ledger as events
+// トランザクション台帳のイベント再生リクエストを表現
+type ReplayEventsRequest struct {
+	From time.Time `json:"from"` // 省略時は台帳の最初から
+	To   time.Time `json:"to"`   // 省略時は現在まで
+}
+
+// ReplayEvents handles event replay requests
+// イベント再生リクエストを処理
+//
+// 大量のトランザクションを再生する場合はタイムアウトを避けるため cmd/replay を使用してください。
+func (h *Handlers) ReplayEvents(w http.ResponseWriter, r *http.Request) {
+	replayer, ok := h.manager.(inventory.EventReplayer)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "イベント再生機能がサポートされていません")
+		return
+	}
+
+	var req ReplayEventsRequest
+	if !h.decodeOptionalJSON(w, r, &req) {
+		return
+	}
+
+	result, err := replayer.ReplayEvents(r.Context(), req.From, req.To)
+	if err != nil {
+		var validationErr *inventory.ValidationError
+		var businessErr *inventory.BusinessRuleError
+		switch {
+		case errors.As(err, &validationErr):
+			h.sendError(w, http.StatusBadRequest, err.Error())
+		case errors.As(err, &businessErr):
+			h.sendError(w, http.StatusConflict, err.Error())
+		default:
+			// 途中まで発行済みのため、再開位置を含めて返す
+			h.sendError(w, http.StatusBadGateway, fmt.Sprintf("%s（%d件発行済み、再開位置: %s）",
+				err.Error(), result.Events, result.LastCreatedAt.Format(time.RFC3339Nano)))
+		}
+		return
+	}
+
+	h.sendSuccess(w, result)
+}
+
+// CheckStockConsistencyRequest represents request to check stock records against the transaction ledger
+// 在庫記録とトランザクション台帳の整合性チェックリクエストを表現
+type CheckStockConsistencyRequest struct {
+	Repair bool `json:"repair"` // trueの場合は不一致の在庫記録を台帳の数量に修復
+}
+
+// CheckStockConsistency handles stock consistency check requests
+// 在庫整合性チェックリクエストを処理
+func (h *Handlers) CheckStockConsistency(w http.ResponseWriter, r *http.Request) {
+	checker, ok := h.manager.(inventory.ConsistencyChecker)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "在庫整合性チェック機能がサポートされていません")
+		return
+	}
+
+	var req CheckStockConsistencyRequest
+	if !h.decodeOptionalJSON(w, r, &req) {
+		return
+	}
+
+	report, err := checker.CheckStockConsistency(r.Context(), req.Repair)
+	if err != nil {
+		if report == nil {
+			h.logger.Error("在庫整合性チェックに失敗しました", zap.Error(err))
+			h.sendError(w, http.StatusInternalServerError, "在庫整合性チェックに失敗しました")
+			return
+		}
+		// 一部の修復に失敗した場合も結果を返す（失敗理由は各不一致のrepair_errorに含まれる）
+		h.logger.Error("在庫の修復に失敗しました", zap.Error(err))
+	}
+
+	h.sendSuccess(w, report)
+}
+
+// ListStockSnapshots handles stock snapshot list requests
+// 在庫スナップショット一覧リクエストを処理
+func (h *Handlers) ListStockSnapshots(w http.ResponseWriter, r *http.Request) {
+	snapshotManager, ok := h.manager.(inventory.SnapshotManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "在庫スナップショット機能がサポートされていません")
+		return
+	}
+
+	offset := 0
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			offset = parsedOffset
+		}
+	}
+	limit := listLimit(r)
+
+	snapshots, err := snapshotManager.ListStockSnapshots(r.Context(), offset, limit)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"snapshots": snapshots,
+		"count":     len(snapshots),
+		"offset":    offset,
+		"limit":     limit,
+	})
+}
+
+// GetStockSnapshotLines handles requests for the stock records of a snapshot
+// 在庫スナップショットの在庫記録の取得リクエストを処理
+func (h *Handlers) GetStockSnapshotLines(w http.ResponseWriter, r *http.Request) {
+	snapshotManager, ok := h.manager.(inventory.SnapshotManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "在庫スナップショット機能がサポートされていません")
+		return
+	}
+
+	snapshotID := mux.Vars(r)["snapshotId"]
+	lines, err := snapshotManager.GetStockSnapshotLines(r.Context(), snapshotID, r.URL.Query().Get("location_id"))
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"snapshot_id": snapshotID,
+		"stocks":      lines,
+		"count":       len(lines),
+	})
+}
+
+// CreateStocktake handles requests to create the count sheet of a location
+// 棚卸（集計表）の作成リクエストを処理
+func (h *Handlers) CreateStocktake(w http.ResponseWriter, r *http.Request) {
+	var req CreateStocktakeRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	if !h.validateRequest(w, req.validate()...) {
+		return
+	}
+
+	stocktakeManager, ok := h.manager.(inventory.StocktakeManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "棚卸機能がサポートされていません")
+		return
+	}
+
+	stocktake := &inventory.Stocktake{
+		LocationID: req.LocationID,
+		ABCClass:   req.ABCClass,
+		Reference:  req.Reference,
+	}
+	if err := stocktakeManager.CreateStocktake(r.Context(), stocktake); err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":   "棚卸が作成されました",
+		"stocktake": stocktake,
+	})
+}
+
+// ListStocktakes handles stocktake list requests
+// 棚卸一覧取得リクエストを処理
+func (h *Handlers) ListStocktakes(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := inventory.StocktakeFilter{
+		LocationID: query.Get("location_id"),
+		Status:     inventory.StocktakeStatus(query.Get("status")),
+		Limit:      listLimit(r),
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			filter.Offset = parsedOffset
+		}
+	}
+
+	stocktakeManager, ok := h.manager.(inventory.StocktakeManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "棚卸機能がサポートされていません")
+		return
+	}
+
+	stocktakes, err := stocktakeManager.ListStocktakes(r.Context(), filter)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"stocktakes": stocktakes,
+		"count":      len(stocktakes),
+		"offset":     filter.Offset,
+		"limit":      filter.Limit,
+	})
+}
+
+// GetStocktake handles get stocktake requests
+// 棚卸取得リクエストを処理
+func (h *Handlers) GetStocktake(w http.ResponseWriter, r *http.Request) {
+	stocktakeManager, ok := h.manager.(inventory.StocktakeManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "棚卸機能がサポートされていません")
+		return
+	}
+
+	stocktake, err := stocktakeManager.GetStocktake(r.Context(), mux.Vars(r)["stocktakeId"])
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, stocktake)
+}
+
+// RecordStocktakeCounts handles requests to record counted quantities on a stocktake
+// 棚卸の実数の記録リクエストを処理
+func (h *Handlers) RecordStocktakeCounts(w http.ResponseWriter, r *http.Request) {
+	var req RecordStocktakeCountsRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	if !h.validateRequest(w, req.validate()...) {
+		return
+	}
+
+	stocktakeManager, ok := h.manager.(inventory.StocktakeManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "棚卸機能がサポートされていません")
+		return
+	}
+
+	stocktake, err := stocktakeManager.RecordStocktakeCounts(r.Context(), mux.Vars(r)["stocktakeId"], req.Counts)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":   "棚卸の実数が記録されました",
+		"stocktake": stocktake,
+	})
+}
+
+// SubmitStocktake handles requests to fix the variances of a stocktake
+// 棚卸の差異確定リクエストを処理
+func (h *Handlers) SubmitStocktake(w http.ResponseWriter, r *http.Request) {
+	stocktakeManager, ok := h.manager.(inventory.StocktakeManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "棚卸機能がサポートされていません")
+		return
+	}
+
+	stocktake, err := stocktakeManager.SubmitStocktake(r.Context(), mux.Vars(r)["stocktakeId"])
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":   "棚卸の差異が確定されました",
+		"stocktake": stocktake,
+	})
+}
+
+// ApplyStocktake handles requests to adjust the stock by the approved variances of a stocktake
+// 棚卸差異の調整リクエストを処理
+func (h *Handlers) ApplyStocktake(w http.ResponseWriter, r *http.Request) {
+	var req ApplyStocktakeRequest
+	if !h.decodeOptionalJSON(w, r, &req) {
+		return
+	}
+	if !h.validateRequest(w, req.validate()...) {
+		return
+	}
+
+	stocktakeManager, ok := h.manager.(inventory.StocktakeManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "棚卸機能がサポートされていません")
+		return
+	}
+
+	stocktake, err := stocktakeManager.ApplyStocktake(r.Context(), mux.Vars(r)["stocktakeId"], req.ItemIDs)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":   "棚卸差異が在庫に反映されました",
+		"stocktake": stocktake,
+	})
+}
+
+// CancelStocktake handles cancel stocktake requests
+// 棚卸取消リクエストを処理
+func (h *Handlers) CancelStocktake(w http.ResponseWriter, r *http.Request) {
+	stocktakeManager, ok := h.manager.(inventory.StocktakeManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "棚卸機能がサポートされていません")
+		return
+	}
+
+	stocktake, err := stocktakeManager.CancelStocktake(r.Context(), mux.Vars(r)["stocktakeId"])
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":   "棚卸が取り消されました",
+		"stocktake": stocktake,
+	})
+}
+
+// ListAdjustments handles stock adjustment list requests
+// 在庫調整一覧取得リクエストを処理
+func (h *Handlers) ListAdjustments(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := inventory.AdjustmentFilter{
+		ItemID:     query.Get("item_id"),
+		LocationID: query.Get("location_id"),
+		Status:     inventory.AdjustmentStatus(query.Get("status")),
+		Reason:     inventory.AdjustmentReason(query.Get("reason_code")),
+		Limit:      listLimit(r),
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			filter.Offset = parsedOffset
+		}
+	}
+
+	adjustmentManager, ok := h.manager.(inventory.AdjustmentManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "在庫調整の承認機能がサポートされていません")
+		return
+	}
+
+	adjustments, err := adjustmentManager.ListAdjustments(r.Context(), filter)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"adjustments": adjustments,
+		"count":       len(adjustments),
+		"offset":      filter.Offset,
+		"limit":       filter.Limit,
+	})
+}
+
+// GetAdjustment handles get stock adjustment requests
+// 在庫調整取得リクエストを処理
+func (h *Handlers) GetAdjustment(w http.ResponseWriter, r *http.Request) {
+	adjustmentManager, ok := h.manager.(inventory.AdjustmentManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "在庫調整の承認機能がサポートされていません")
+		return
+	}
+
+	adjustment, err := adjustmentManager.GetAdjustment(r.Context(), mux.Vars(r)["adjustmentId"])
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+	h.sendSuccess(w, adjustment)
+}
+
+// ApproveAdjustment handles requests to approve a pending stock adjustment
+// 承認待ちの在庫調整の承認リクエストを処理
+func (h *Handlers) ApproveAdjustment(w http.ResponseWriter, r *http.Request) {
+	var req AdjustmentDecisionRequest
+	if !h.decodeOptionalJSON(w, r, &req) {
+		return
+	}
+	if !h.validateRequest(w, req.validate()...) {
+		return
+	}
+
+	adjustmentManager, ok := h.manager.(inventory.AdjustmentManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "在庫調整の承認機能がサポートされていません")
+		return
+	}
+
+	adjustment, err := adjustmentManager.ApproveAdjustment(r.Context(), mux.Vars(r)["adjustmentId"], req.Note)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":    "在庫調整が承認されました",
+		"adjustment": adjustment,
+	})
+}
+
+// RejectAdjustment handles requests to reject a pending stock adjustment
+// 承認待ちの在庫調整の却下リクエストを処理
+func (h *Handlers) RejectAdjustment(w http.ResponseWriter, r *http.Request) {
+	var req AdjustmentDecisionRequest
+	if !h.decodeOptionalJSON(w, r, &req) {
+		return
+	}
+	if !h.validateRequest(w, req.validate()...) {
+		return
+	}
+
+	adjustmentManager, ok := h.manager.(inventory.AdjustmentManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "在庫調整の承認機能がサポートされていません")
+		return
+	}
+
+	adjustment, err := adjustmentManager.RejectAdjustment(r.Context(), mux.Vars(r)["adjustmentId"], req.Note)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":    "在庫調整が却下されました",
+		"adjustment": adjustment,
+	})
 }
 
-// DeleteLocation handles delete location requests
-// ロケーション削除リクエストを処理
-func (h *Handlers) DeleteLocation(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	locationID := vars["locationId"]
+// ReceiveForInspection handles requests to receive stock into quarantine for QC inspection
+// 検品待ち（隔離）の入荷リクエストを処理
+func (h *Handlers) ReceiveForInspection(w http.ResponseWriter, r *http.Request) {
+	var req ReceiveForInspectionRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	if !h.validateRequest(w, req.validate()...) {
+		return
+	}
 
-	// LocationManagerを使用してロケーションを削除
-	if locationManager, ok := h.manager.(inventory.LocationManager); ok {
-		if err := locationManager.DeleteLocation(r.Context(), locationID); err != nil {
-			if err == inventory.ErrLocationNotFound {
-				h.sendError(w, http.StatusNotFound, "ロケーションが見つかりません")
-			} else {
-				h.sendError(w, http.StatusInternalServerError, err.Error())
-			}
-			return
-		}
-		h.sendSuccess(w, map[string]string{
-			"message": "ロケーションが削除されました",
-		})
-	} else {
-		h.sendError(w, http.StatusNotImplemented, "ロケーション管理機能がサポートされていません")
+	inspectionManager, ok := h.manager.(inventory.InspectionManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "入荷検品機能がサポートされていません")
+		return
 	}
-}
 
-// ListLocations handles list locations requests
-// ロケーション一覧リクエストを処理
-func (h *Handlers) ListLocations(w http.ResponseWriter, r *http.Request) {
-	// offsetとlimitのパラメータを取得
-	offset := 0
-	limit := 20 // デフォルト
+	inspection := &inventory.Inspection{
+		ItemID:     req.ItemID,
+		LocationID: req.LocationID,
+		Quantity:   req.Quantity,
+		Reference:  req.Reference,
+		Note:       req.Note,
+	}
+	if err := inspectionManager.ReceiveForInspection(r.Context(), inspection); err != nil {
+		h.sendManagerError(w, err)
+		return
+	}
 
-	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+	h.sendSuccess(w, map[string]interface{}{
+		"message":    "入荷が検品待ちとして記録されました",
+		"inspection": inspection,
+	})
+}
+
+// ListInspections handles receiving inspection list requests
+// 入荷検品一覧取得リクエストを処理
+func (h *Handlers) ListInspections(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := inventory.InspectionFilter{
+		ItemID:     query.Get("item_id"),
+		LocationID: query.Get("location_id"),
+		Status:     inventory.InspectionStatus(query.Get("status")),
+		Limit:      listLimit(r),
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
 		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
-			offset = parsedOffset
+			filter.Offset = parsedOffset
 		}
 	}
 
-	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 100 {
-			limit = parsedLimit
-		}
+	inspectionManager, ok := h.manager.(inventory.InspectionManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "入荷検品機能がサポートされていません")
+		return
 	}
 
-	// LocationManagerを使用してロケーション一覧を取得
-	if locationManager, ok := h.manager.(inventory.LocationManager); ok {
-		locations, err := locationManager.ListLocations(r.Context(), offset, limit)
-		if err != nil {
-			h.sendError(w, http.StatusInternalServerError, err.Error())
-			return
-		}
-		h.sendSuccess(w, map[string]interface{}{
-			"locations": locations,
-			"offset":    offset,
-			"limit":     limit,
-			"count":     len(locations),
-		})
-	} else {
-		h.sendError(w, http.StatusNotImplemented, "ロケーション管理機能がサポートされていません")
+	inspections, err := inspectionManager.ListInspections(r.Context(), filter)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
 	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"inspections": inspections,
+		"count":       len(inspections),
+		"offset":      filter.Offset,
+		"limit":       filter.Limit,
+	})
 }
 
-// ロット管理ハンドラー
+// GetInspection handles get receiving inspection requests
+// 入荷検品取得リクエストを処理
+func (h *Handlers) GetInspection(w http.ResponseWriter, r *http.Request) {
+	inspectionManager, ok := h.manager.(inventory.InspectionManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "入荷検品機能がサポートされていません")
+		return
+	}
 
-// CreateLot handles create lot requests
-// ロット作成リクエストを処理
-func (h *Handlers) CreateLot(w http.ResponseWriter, r *http.Request) {
-	var lot inventory.Lot
-	if err := json.NewDecoder(r.Body).Decode(&lot); err != nil {
-		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+	inspection, err := inspectionManager.GetInspection(r.Context(), mux.Vars(r)["inspectionId"])
+	if err != nil {
+		h.sendManagerError(w, err)
 		return
 	}
+	h.sendSuccess(w, inspection)
+}
 
-	// タイムスタンプ設定
-	lot.CreatedAt = time.Now()
+// PassInspection handles requests to release quarantined stock that passed QC into available stock
+// 入荷検品の合格リクエストを処理
+func (h *Handlers) PassInspection(w http.ResponseWriter, r *http.Request) {
+	var req InspectionDispositionRequest
+	if !h.decodeOptionalJSON(w, r, &req) {
+		return
+	}
+	if !h.validateRequest(w, inventory.ValidateAlertNote(req.Note)) {
+		return
+	}
 
-	// ID生成（もしIDが指定されていない場合）
-	if lot.ID == "" {
-		lot.ID = inventory.NewTransactionID()
+	inspectionManager, ok := h.manager.(inventory.InspectionManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "入荷検品機能がサポートされていません")
+		return
 	}
 
-	// LotManagerを使用してロットを作成
-	if lotManager, ok := h.manager.(inventory.LotManager); ok {
-		if err := lotManager.CreateLot(r.Context(), &lot); err != nil {
-			h.sendError(w, http.StatusInternalServerError, err.Error())
-			return
-		}
-	} else {
-		h.sendError(w, http.StatusNotImplemented, "ロット管理機能がサポートされていません")
+	inspection, err := inspectionManager.PassInspection(r.Context(), mux.Vars(r)["inspectionId"], req.Note)
+	if err != nil {
+		h.sendManagerError(w, err)
 		return
 	}
 
 	h.sendSuccess(w, map[string]interface{}{
-		"message": "ロットが作成されました",
-		"lot":     lot,
+		"message":    "入荷検品が合格し、在庫に入庫されました",
+		"inspection": inspection,
 	})
 }
 
-// GetLot handles get lot requests
-// ロット取得リクエストを処理
-func (h *Handlers) GetLot(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	lotID := vars["lotId"]
-
-	// LotManagerを使用してロットを取得
-	if lotManager, ok := h.manager.(inventory.LotManager); ok {
-		lot, err := lotManager.GetLot(r.Context(), lotID)
-		if err != nil {
-			h.sendError(w, http.StatusNotFound, "ロットが見つかりません")
-			return
-		}
-		h.sendSuccess(w, lot)
-	} else {
-		h.sendError(w, http.StatusNotImplemented, "ロット管理機能がサポートされていません")
+// FailInspection handles requests to write off quarantined stock that failed QC
+// 入荷検品の不合格（廃棄）リクエストを処理
+func (h *Handlers) FailInspection(w http.ResponseWriter, r *http.Request) {
+	var req InspectionDispositionRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	if !h.validateRequest(w, req.validate()...) {
+		return
 	}
-}
 
-// GetLotsByItem handles get lots by item requests
-// 商品別ロット取得リクエストを処理
-func (h *Handlers) GetLotsByItem(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	itemID := vars["itemId"]
+	inspectionManager, ok := h.manager.(inventory.InspectionManager)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "入荷検品機能がサポートされていません")
+		return
+	}
 
-	// LotManagerを使用して商品のロット一覧を取得
-	if lotManager, ok := h.manager.(inventory.LotManager); ok {
-		lots, err := lotManager.GetLotsByItem(r.Context(), itemID)
-		if err != nil {
-			h.sendError(w, http.StatusInternalServerError, err.Error())
-			return
-		}
-		h.sendSuccess(w, map[string]interface{}{
-			"lots":    lots,
-			"item_id": itemID,
-			"count":   len(lots),
-		})
-	} else {
-		h.sendError(w, http.StatusNotImplemented, "ロット管理機能がサポートされていません")
+	inspection, err := inspectionManager.FailInspection(r.Context(), mux.Vars(r)["inspectionId"], req.ReasonCode, req.Note)
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
 	}
+
+	h.sendSuccess(w, map[string]interface{}{
+		"message":    "入荷検品が不合格となり、廃棄されました",
+		"inspection": inspection,
+	})
 }
 
-// GetExpiringLots handles get expiring lots requests
-// 期限切れ間近ロット取得リクエストを処理
-func (h *Handlers) GetExpiringLots(w http.ResponseWriter, r *http.Request) {
-	// within パラメータを取得（日数）
-	withinDays := 7 // デフォルト7日
-	if withinStr := r.URL.Query().Get("within_days"); withinStr != "" {
-		if parsedDays, err := strconv.Atoi(withinStr); err == nil && parsedDays > 0 {
-			withinDays = parsedDays
-		}
+// Allocate handles requests to source an item across multiple locations and reserve it
+// 複数ロケーションからの引当リクエストを処理（?dry_run=true の場合は予約せず計画のみ返す）
+func (h *Handlers) Allocate(w http.ResponseWriter, r *http.Request) {
+	var req AllocateRequest
+	if !h.decodeJSON(w, r, &req) {
+		return
+	}
+	if !h.validateRequest(w, req.validate()...) {
+		return
+	}
+	dryRun, ok := h.dryRunRequested(w, r)
+	if !ok {
+		return
 	}
 
-	within := time.Duration(withinDays) * 24 * time.Hour
+	allocator, ok := h.manager.(inventory.Allocator)
+	if !ok {
+		h.sendError(w, http.StatusNotImplemented, "引当機能がサポートされていません")
+		return
+	}
 
-	// LotManagerを使用して期限切れ間近ロットを取得
-	if lotManager, ok := h.manager.(inventory.LotManager); ok {
-		lots, err := lotManager.GetExpiringLots(r.Context(), within)
-		if err != nil {
-			h.sendError(w, http.StatusInternalServerError, err.Error())
-			return
-		}
-		h.sendSuccess(w, map[string]interface{}{
-			"lots":        lots,
-			"within_days": withinDays,
-			"count":       len(lots),
-		})
+	var (
+		allocation *inventory.Allocation
+		err        error
+	)
+	if dryRun {
+		allocation, err = allocator.PlanAllocation(r.Context(), req.allocationRequest())
 	} else {
-		h.sendError(w, http.StatusNotImplemented, "ロット管理機能がサポートされていません")
+		allocation, err = allocator.Allocate(r.Context(), req.allocationRequest())
+	}
+	if err != nil {
+		h.sendManagerError(w, err)
+		return
 	}
-}
 
-// GetExpiredLots handles get expired lots requests
-// 期限切れロット取得リクエストを処理
-func (h *Handlers) GetExpiredLots(w http.ResponseWriter, r *http.Request) {
-	// LotManagerを使用して期限切れロットを取得
-	if lotManager, ok := h.manager.(inventory.LotManager); ok {
-		lots, err := lotManager.GetExpiredLots(r.Context())
-		if err != nil {
-			h.sendError(w, http.StatusInternalServerError, err.Error())
-			return
-		}
-		h.sendSuccess(w, map[string]interface{}{
-			"lots":  lots,
-			"count": len(lots),
-		})
-	} else {
-		h.sendError(w, http.StatusNotImplemented, "ロット管理機能がサポートされていません")
+	message := "引当が完了しました"
+	if dryRun {
+		message = "引当の計画を作成しました（予約は作成されていません）"
 	}
+	h.sendSuccess(w, map[string]interface{}{
+		"message":    message,
+		"dry_run":    dryRun,
+		"allocation": allocation,
+	})
 }
 
-// 予約管理ハンドラー
-
-// ReserveStock handles reserve stock requests
-// 在庫予約リクエストを処理
-func (h *Handlers) ReserveStock(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		ItemID     string `json:"item_id"`
-		LocationID string `json:"location_id"`
-		Quantity   int64  `json:"quantity"`
-		Reference  string `json:"reference"`
+// ListJobs handles scheduled job status requests
+// 定期ジョブの実行状況の取得リクエストを処理
+func (h *Handlers) ListJobs(w http.ResponseWriter, r *http.Request) {
+	if h.scheduler == nil {
+		h.sendError(w, http.StatusNotImplemented, "定期ジョブ機能がサポートされていません")
+		return
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+	jobs := h.scheduler.Status()
+	h.sendSuccess(w, map[string]interface{}{
+		"jobs":  jobs,
+		"count": len(jobs),
+	})
+}
+
+// RunJob handles requests to run a scheduled job now
+// 定期ジョブの即時実行リクエストを処理（実行の完了は待たない）
+func (h *Handlers) RunJob(w http.ResponseWriter, r *http.Request) {
+	if h.scheduler == nil {
+		h.sendError(w, http.StatusNotImplemented, "定期ジョブ機能がサポートされていません")
 		return
 	}
 
-	ctx := context.WithValue(r.Context(), "user_id", "api_user")
-	if err := h.manager.Reserve(ctx, req.ItemID, req.LocationID, req.Quantity, req.Reference); err != nil {
-		h.sendError(w, http.StatusInternalServerError, err.Error())
+	name := mux.Vars(r)["name"]
+	if err := h.scheduler.Trigger(name); err != nil {
+		switch {
+		case errors.Is(err, scheduler.ErrJobNotFound):
+			h.sendError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, scheduler.ErrJobRunning):
+			h.sendError(w, http.StatusConflict, err.Error())
+		default:
+			h.sendError(w, http.StatusServiceUnavailable, err.Error())
+		}
 		return
 	}
 
-	h.sendSuccess(w, map[string]string{
-		"message": "在庫が予約されました",
+	h.sendAccepted(w, map[string]string{
+		"job":     name,
+		"message": "ジョブの実行を開始しました",
 	})
 }
 
-// ReleaseReservation handles release reservation requests
-// 予約解除リクエストを処理
-func (h *Handlers) ReleaseReservation(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		ItemID     string `json:"item_id"`
-		LocationID string `json:"location_id"`
-		Quantity   int64  `json:"quantity"`
-		Reference  string `json:"reference"`
+// APIKeyRequest represents request to issue an API key
+// APIキー発行リクエストを表現
+type APIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`     // inventory:read・inventory:write・inventory:admin
+	ExpiresAt *time.Time `json:"expires_at"` // 省略時は無期限
+}
+
+// RotateAPIKeyRequest represents request to rotate an API key
+// APIキーのローテーションリクエストを表現
+type RotateAPIKeyRequest struct {
+	GracePeriod string `json:"grace_period"` // 旧キーを引き続き使用できる期間（例: 24h、省略時は直ちに無効化）
+}
+
+// CreateAPIKey handles API key issue requests
+// APIキー発行リクエストを処理
+func (h *Handlers) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	if h.apiKeys == nil {
+		h.sendError(w, http.StatusNotImplemented, "APIキー機能がサポートされていません")
+		return
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.sendError(w, http.StatusBadRequest, "無効なリクエスト形式です")
+	var req APIKeyRequest
+	if !h.decodeJSON(w, r, &req) {
 		return
 	}
 
-	ctx := context.WithValue(r.Context(), "user_id", "api_user")
-	if err := h.manager.ReleaseReservation(ctx, req.ItemID, req.LocationID, req.Quantity, req.Reference); err != nil {
-		h.sendError(w, http.StatusInternalServerError, err.Error())
+	key, plaintext, err := h.apiKeys.Issue(r.Context(), req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	h.logger.Info("APIキーを発行しました", zap.String("api_key_id", key.ID), zap.Strings("scopes", key.Scopes))
 
-	h.sendSuccess(w, map[string]string{
-		"message": "予約が解除されました",
+	// キーは保存しないため発行時のみ返却する
+	h.sendSuccess(w, map[string]interface{}{
+		"message": "APIキーが発行されました",
+		"api_key": key,
+		"key":     plaintext,
 	})
 }
 
-// 履歴管理の追加ハンドラー
-
-// GetHistoryByLocation handles get history by location requests
-// ロケーション別履歴取得リクエストを処理
-func (h *Handlers) GetHistoryByLocation(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	locationID := vars["locationId"]
-
-	// limitパラメータの取得
-	limit := 50 // デフォルト
-	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
-			limit = parsedLimit
-		}
+// ListAPIKeys handles API key list requests
+// APIキー一覧リクエストを処理
+func (h *Handlers) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	if h.apiKeys == nil {
+		h.sendError(w, http.StatusNotImplemented, "APIキー機能がサポートされていません")
+		return
 	}
 
-	history, err := h.manager.GetHistoryByLocation(r.Context(), locationID, limit)
+	keys, err := h.apiKeys.List(r.Context())
 	if err != nil {
-		h.sendError(w, http.StatusInternalServerError, err.Error())
+		h.sendManagerError(w, err)
 		return
 	}
 
 	h.sendSuccess(w, map[string]interface{}{
-		"history":     history,
-		"location_id": locationID,
-		"limit":       limit,
-		"count":       len(history),
+		"api_keys": keys,
+		"count":    len(keys),
 	})
 }
 
-// GetHistoryByDateRange handles get history by date range requests
-// 日付範囲別履歴取得リクエストを処理
-func (h *Handlers) GetHistoryByDateRange(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	itemID := vars["itemId"]
-
-	// 日付パラメータを取得
-	fromStr := r.URL.Query().Get("from")
-	toStr := r.URL.Query().Get("to")
-
-	if fromStr == "" || toStr == "" {
-		h.sendError(w, http.StatusBadRequest, "from及びtoパラメータが必要です（形式：2006-01-02）")
+// GetAPIKey handles API key retrieval requests
+// APIキー取得リクエストを処理
+func (h *Handlers) GetAPIKey(w http.ResponseWriter, r *http.Request) {
+	if h.apiKeys == nil {
+		h.sendError(w, http.StatusNotImplemented, "APIキー機能がサポートされていません")
 		return
 	}
 
-	from, err := time.Parse("2006-01-02", fromStr)
+	key, err := h.apiKeys.Get(r.Context(), mux.Vars(r)["keyId"])
 	if err != nil {
-		h.sendError(w, http.StatusBadRequest, "無効なfrom日付形式です（形式：2006-01-02）")
+		h.sendAPIKeyError(w, err)
 		return
 	}
 
-	to, err := time.Parse("2006-01-02", toStr)
-	if err != nil {
-		h.sendError(w, http.StatusBadRequest, "無効なto日付形式です（形式：2006-01-02）")
+	h.sendSuccess(w, key)
+}
+
+// RotateAPIKey handles API key rotation requests
+// APIキーのローテーションリクエストを処理
+func (h *Handlers) RotateAPIKey(w http.ResponseWriter, r *http.Request) {
+	if h.apiKeys == nil {
+		h.sendError(w, http.StatusNotImplemented, "APIキー機能がサポートされていません")
+		return
+	}
+
+	var req RotateAPIKeyRequest
+	if !h.decodeOptionalJSON(w, r, &req) {
 		return
 	}
+	var gracePeriod time.Duration
+	if req.GracePeriod != "" {
+		parsed, err := time.ParseDuration(req.GracePeriod)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "無効な猶予期間です（例: 24h）")
+			return
+		}
+		gracePeriod = parsed
+	}
 
-	// 終了日を23:59:59に設定
-	to = to.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
-
-	history, err := h.manager.GetHistoryByDateRange(r.Context(), itemID, from, to)
+	key, plaintext, err := h.apiKeys.Rotate(r.Context(), mux.Vars(r)["keyId"], gracePeriod)
 	if err != nil {
-		h.sendError(w, http.StatusInternalServerError, err.Error())
+		h.sendAPIKeyError(w, err)
 		return
 	}
+	h.logger.Info("APIキーをローテーションしました",
+		zap.String("api_key_id", mux.Vars(r)["keyId"]),
+		zap.String("rotated_to", key.ID),
+		zap.Duration("grace_period", gracePeriod),
+	)
 
 	h.sendSuccess(w, map[string]interface{}{
-		"history": history,
-		"item_id": itemID,
-		"from":    fromStr,
-		"to":      toStr,
-		"count":   len(history),
+		"message": "APIキーがローテーションされました",
+		"api_key": key,
+		"key":     plaintext,
 	})
 }
 
-// バッチ管理の追加ハンドラー
-
-// GetBatchStatus handles get batch status requests
-// バッチステータス取得リクエストを処理
-func (h *Handlers) GetBatchStatus(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	batchID := vars["batchId"]
+// RevokeAPIKey handles API key revocation requests
+// APIキー無効化リクエストを処理
+func (h *Handlers) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	if h.apiKeys == nil {
+		h.sendError(w, http.StatusNotImplemented, "APIキー機能がサポートされていません")
+		return
+	}
 
-	batch, err := h.manager.GetBatchStatus(r.Context(), batchID)
+	key, err := h.apiKeys.Revoke(r.Context(), mux.Vars(r)["keyId"])
 	if err != nil {
-		h.sendError(w, http.StatusInternalServerError, err.Error())
+		h.sendAPIKeyError(w, err)
 		return
 	}
+	h.logger.Info("APIキーを無効化しました", zap.String("api_key_id", key.ID))
 
-	h.sendSuccess(w, batch)
+	h.sendSuccess(w, map[string]string{
+		"message": "APIキーが無効化されました",
+	})
 }
 
-// 在庫評価エンジンハンドラー
-
-// CalculateValue handles calculate inventory value requests
-// 在庫評価計算リクエストを処理
-func (h *Handlers) CalculateValue(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	itemID := vars["itemId"]
-	locationID := vars["locationId"]
+// sendAPIKeyError sends an API key error with the matching status
+// APIキーのエラーを対応するステータスで送信
+func (h *Handlers) sendAPIKeyError(w http.ResponseWriter, err error) {
+	if errors.Is(err, auth.ErrAPIKeyNotFound) {
+		h.sendError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	h.sendError(w, http.StatusBadRequest, err.Error())
+}
 
-	// 評価方法を取得
-	methodStr := r.URL.Query().Get("method")
-	if methodStr == "" {
-		methodStr = string(inventory.ValuationMethodFIFO) // デフォルト
+// SyncStock handles stock sync requests from external systems
+// 外部システムからの在庫同期リクエストを処理
+//
+// Idempotency-Keyヘッダーを指定した場合は本文のidempotency_keyより優先されます。
+// 同じキーのリクエストは一度だけ適用され、2回目以降は result: duplicate を返します。
+func (h *Handlers) SyncStock(w http.ResponseWriter, r *http.Request) {
+	if h.consumer == nil {
+		h.sendError(w, http.StatusNotImplemented, "在庫同期機能がサポートされていません")
+		return
 	}
 
-	method := inventory.ValuationMethod(methodStr)
+	var msg consumer.Message
+	if !h.decodeJSON(w, r, &msg) {
+		return
+	}
+	if key := r.Header.Get("Idempotency-Key"); key != "" {
+		msg.IdempotencyKey = key
+	}
 
-	// ValuationEngineを使用して在庫評価を計算
-	if valuationEngine, ok := h.manager.(inventory.ValuationEngine); ok {
-		value, err := valuationEngine.CalculateValue(r.Context(), itemID, locationID, method)
-		if err != nil {
-			h.sendError(w, http.StatusInternalServerError, err.Error())
-			return
+	result, err := h.consumer.Handle(r.Context(), msg)
+	if err != nil {
+		switch {
+		case errors.Is(err, consumer.ErrInvalidMessage):
+			h.sendError(w, http.StatusBadRequest, err.Error())
+		case consumer.IsPermanent(err):
+			h.sendError(w, http.StatusUnprocessableEntity, err.Error())
+		default:
+			h.sendManagerError(w, err)
 		}
-		h.sendSuccess(w, map[string]interface{}{
-			"value":       value,
-			"item_id":    itemID,
-			"location_id": locationID,
-			"method":      method,
-		})
-	} else {
-		h.sendError(w, http.StatusNotImplemented, "在庫評価機能がサポートされていません")
+		return
 	}
-}
 
-// CalculateTotalValue handles calculate total inventory value requests
-// 全体在庫評価計算リクエストを処理
-func (h *Handlers) CalculateTotalValue(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	locationID := vars["locationId"]
+	h.sendSuccess(w, map[string]string{
+		"result":          string(result),
+		"idempotency_key": msg.Key(),
+	})
+}
 
-	// 評価方法を取得
-	methodStr := r.URL.Query().Get("method")
-	if methodStr == "" {
-		methodStr = string(inventory.ValuationMethodFIFO) // デフォルト
+// StreamStock pushes stock changed events at a location over WebSocket
+// ロケーションの在庫変更イベントをWebSocketで配信
+//
+// 接続後、location_idの在庫が変更されるたびにStockChangedEventをJSONのテキストメッセージで送信します。
+// 受信が追いつかずバッファが溢れた接続は切断するため、クライアントは再接続後に
+// GetStockByLocationで現在の在庫を取得し直してください。
+func (h *Handlers) StreamStock(w http.ResponseWriter, r *http.Request) {
+	if h.live == nil {
+		h.sendError(w, http.StatusNotImplemented, "ライブ配信機能がサポートされていません")
+		return
 	}
 
-	method := inventory.ValuationMethod(methodStr)
-
-	// ValuationEngineを使用して全体在庫評価を計算
-	if valuationEngine, ok := h.manager.(inventory.ValuationEngine); ok {
-		totalValue, err := valuationEngine.CalculateTotalValue(r.Context(), locationID, method)
-		if err != nil {
-			h.sendError(w, http.StatusInternalServerError, err.Error())
-			return
-		}
-		h.sendSuccess(w, map[string]interface{}{
-			"total_value": totalValue,
-			"location_id": locationID,
-			"method":      method,
-		})
-	} else {
-		h.sendError(w, http.StatusNotImplemented, "在庫評価機能がサポートされていません")
+	locationID := r.URL.Query().Get("location_id")
+	if locationID == "" {
+		h.sendError(w, http.StatusBadRequest, "location_idパラメータが必要です")
+		return
 	}
-}
-
-// GetAverageCost handles get average cost requests
-// 平均原価取得リクエストを処理
-func (h *Handlers) GetAverageCost(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	itemID := vars["itemId"]
 
-	// ValuationEngineを使用して平均原価を取得
-	if valuationEngine, ok := h.manager.(inventory.ValuationEngine); ok {
-		avgCost, err := valuationEngine.GetAverageCost(r.Context(), itemID)
-		if err != nil {
-			h.sendError(w, http.StatusInternalServerError, err.Error())
-			return
-		}
-		h.sendSuccess(w, map[string]interface{}{
-			"average_cost": avgCost,
-			"item_id":      itemID,
-		})
-	} else {
-		h.sendError(w, http.StatusNotImplemented, "在庫評価機能がサポートされていません")
+	// CORS設定と同様に全てのオリジンからの接続を許可する（Handshake未設定）
+	server := websocket.Server{
+		Handler: func(ws *websocket.Conn) {
+			h.streamStock(ws, locationID)
+		},
 	}
+	server.ServeHTTP(w, r)
 }
 
-// 在庫分析エンジンハンドラー
-
-// CalculateABCClassification handles ABC classification requests
-// ABC分析リクエストを処理
-func (h *Handlers) CalculateABCClassification(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	locationID := vars["locationId"]
-
-	// AnalyticsEngineを使用してABC分析を実行
-	if analyticsEngine, ok := h.manager.(inventory.AnalyticsEngine); ok {
-		classification, err := analyticsEngine.CalculateABCClassification(r.Context(), locationID)
-		if err != nil {
-			h.sendError(w, http.StatusInternalServerError, err.Error())
+// streamStock writes stock changed events at locationID to ws until the connection ends
+// 接続が終了するまでlocationIDの在庫変更イベントをwsへ送信
+func (h *Handlers) streamStock(ws *websocket.Conn, locationID string) {
+	defer ws.Close()
+
+	// サーバーのReadTimeout/WriteTimeoutによる期限を解除する
+	ws.SetDeadline(time.Time{})
+
+	sub := h.subscribeLive(locationID, events.TypeStockChanged)
+	defer sub.close()
+
+	// クライアントからの切断を検知する（受信したメッセージは破棄）
+	disconnected := make(chan struct{})
+	go func() {
+		defer close(disconnected)
+		var discard []byte
+		for {
+			if err := websocket.Message.Receive(ws, &discard); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(livePingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case event := <-sub.events:
+			ws.SetWriteDeadline(time.Now().Add(liveWriteTimeout))
+			if err := websocket.JSON.Send(ws, event.Payload); err != nil {
+				h.logger.Warn("在庫変更の配信に失敗しました", zap.String("location_id", locationID), zap.Error(err))
+				return
+			}
+		case <-ping.C:
+			ws.SetWriteDeadline(time.Now().Add(liveWriteTimeout))
+			ws.PayloadType = websocket.PingFrame
+			if _, err := ws.Write(nil); err != nil {
+				return
+			}
+		case <-sub.overflow:
+			h.logger.Warn("受信が追いつかないため在庫変更の配信を切断します", zap.String("location_id", locationID))
+			return
+		case <-disconnected:
+			return
+		case <-h.streams.Done():
 			return
 		}
-		h.sendSuccess(w, map[string]interface{}{
-			"classification": classification,
-			"location_id":    locationID,
-			"count":          len(classification),
-		})
-	} else {
-		h.sendError(w, http.StatusNotImplemented, "在庫分析機能がサポートされていません")
 	}
 }
 
-// GetTurnoverRate handles turnover rate requests
-// 回転率取得リクエストを処理
-func (h *Handlers) GetTurnoverRate(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	itemID := vars["itemId"]
-
-	// 期間パラメータを取得（日数）
-	periodDays := 30 // デフォルト30日
-	if periodStr := r.URL.Query().Get("period_days"); periodStr != "" {
-		if parsedDays, err := strconv.Atoi(periodStr); err == nil && parsedDays > 0 {
-			periodDays = parsedDays
-		}
+// StreamAlerts streams created, acknowledged and resolved alerts at a location as Server-Sent Events
+// ロケーションのアラートの作成・確認・解決をServer-Sent Eventsで配信
+//
+// イベント名はalert.created（データはアラート）、alert.acknowledged（データはAlertAcknowledgedEvent）、
+// alert.resolved（データはAlertResolvedEvent）です。
+// 受信が追いつかずバッファが溢れた接続は切断するため、クライアントは再接続後に
+// GetAlertsでアクティブなアラートを取得し直してください。
+func (h *Handlers) StreamAlerts(w http.ResponseWriter, r *http.Request) {
+	if h.live == nil {
+		h.sendError(w, http.StatusNotImplemented, "ライブ配信機能がサポートされていません")
+		return
 	}
 
-	period := time.Duration(periodDays) * 24 * time.Hour
+	locationID := r.URL.Query().Get("location_id")
+	if locationID == "" {
+		h.sendError(w, http.StatusBadRequest, "location_idパラメータが必要です")
+		return
+	}
 
-	// AnalyticsEngineを使用して回転率を取得
-	if analyticsEngine, ok := h.manager.(inventory.AnalyticsEngine); ok {
-		turnoverRate, err := analyticsEngine.GetTurnoverRate(r.Context(), itemID, period)
-		if err != nil {
-			h.sendError(w, http.StatusInternalServerError, err.Error())
-			return
-		}
-		h.sendSuccess(w, map[string]interface{}{
-			"turnover_rate": turnoverRate,
-			"item_id":       itemID,
-			"period_days":   periodDays,
-		})
-	} else {
-		h.sendError(w, http.StatusNotImplemented, "在庫分析機能がサポートされていません")
+	// サーバーのWriteTimeoutによる期限を解除する
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		h.sendError(w, http.StatusInternalServerError, "ストリーミングがサポートされていません")
+		return
 	}
-}
 
-// GetSlowMovingItems handles slow moving items requests
-// 低回転商品取得リクエストを処理
-func (h *Handlers) GetSlowMovingItems(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	locationID := vars["locationId"]
+	sub := h.subscribeLive(locationID, inventory.EventTypeAlertCreated, inventory.EventTypeAlertAcknowledged, inventory.EventTypeAlertResolved)
+	defer sub.close()
 
-	// 闾値パラメータを取得（日数）
-	thresholdDays := 90 // デフォルト90日
-	if thresholdStr := r.URL.Query().Get("threshold_days"); thresholdStr != "" {
-		if parsedDays, err := strconv.Atoi(thresholdStr); err == nil && parsedDays > 0 {
-			thresholdDays = parsedDays
-		}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // リバースプロキシのバッファリングを無効化
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return
 	}
 
-	threshold := time.Duration(thresholdDays) * 24 * time.Hour
-
-	// AnalyticsEngineを使用して低回転商品を取得
-	if analyticsEngine, ok := h.manager.(inventory.AnalyticsEngine); ok {
-		slowMovingItems, err := analyticsEngine.GetSlowMovingItems(r.Context(), locationID, threshold)
-		if err != nil {
-			h.sendError(w, http.StatusInternalServerError, err.Error())
+	ping := time.NewTicker(livePingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case event := <-sub.events:
+			domainEvent := event.Payload.(inventory.DomainEvent)
+			data, err := json.Marshal(domainEvent.Data)
+			if err != nil {
+				h.logger.Error("アラートのエンコードに失敗しました", zap.String("event_id", event.ID), zap.Error(err))
+				continue
+			}
+			rc.SetWriteDeadline(time.Now().Add(liveWriteTimeout))
+			if _, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data); err != nil {
+				return
+			}
+		case <-ping.C:
+			// コメント行で接続を維持する
+			rc.SetWriteDeadline(time.Now().Add(liveWriteTimeout))
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case <-sub.overflow:
+			h.logger.Warn("受信が追いつかないためアラートの配信を切断します", zap.String("location_id", locationID))
+			return
+		case <-r.Context().Done():
+			return
+		case <-h.streams.Done():
+			return
+		}
+		if err := rc.Flush(); err != nil {
 			return
 		}
-		h.sendSuccess(w, map[string]interface{}{
-			"slow_moving_items": slowMovingItems,
-			"location_id":       locationID,
-			"threshold_days":    thresholdDays,
-			"count":             len(slowMovingItems),
-		})
-	} else {
-		h.sendError(w, http.StatusNotImplemented, "在庫分析機能がサポートされていません")
 	}
 }
 
-// GenerateStockReport handles stock report generation requests
-// 在庫レポート生成リクエストを処理
-func (h *Handlers) GenerateStockReport(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	locationID := vars["locationId"]
+// liveSubscription buffers the live events of one connection
+// ライブ配信の接続ごとにイベントを保持する購読
+type liveSubscription struct {
+	events       chan events.Event
+	overflow     chan struct{} // バッファが溢れると閉じられる
+	overflowOnce sync.Once
+	unsubscribes []func()
+}
 
-	// レポートタイプを取得
-	reportTypeStr := r.URL.Query().Get("type")
-	if reportTypeStr == "" {
-		reportTypeStr = string(inventory.ReportTypeStock) // デフォルト
+// subscribeLive subscribes to eventTypes at locationID on the live bus
+// ライブ配信用のバスでlocationIDのイベントを購読
+func (h *Handlers) subscribeLive(locationID string, eventTypes ...string) *liveSubscription {
+	sub := &liveSubscription{
+		events:   make(chan events.Event, liveBufferSize),
+		overflow: make(chan struct{}),
 	}
-
-	reportType := inventory.ReportType(reportTypeStr)
-
-	// AnalyticsEngineを使用してレポートを生成
-	if analyticsEngine, ok := h.manager.(inventory.AnalyticsEngine); ok {
-		reportData, err := analyticsEngine.GenerateStockReport(r.Context(), locationID, reportType)
-		if err != nil {
-			h.sendError(w, http.StatusInternalServerError, err.Error())
-			return
+	handler := func(ctx context.Context, event events.Event) error {
+		if event.LocationID != locationID {
+			return nil
+		}
+		select {
+		case sub.events <- event:
+		default:
+			// 遅いクライアントが他の接続への配信を妨げないよう切断する
+			sub.overflowOnce.Do(func() { close(sub.overflow) })
 		}
+		return nil
+	}
+	for _, eventType := range eventTypes {
+		sub.unsubscribes = append(sub.unsubscribes, h.live.Subscribe(eventType, handler))
+	}
+	return sub
+}
 
-		// レポートをバイナリデータとして返す
-		w.Header().Set("Content-Type", "application/octet-stream")
-		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=stock_report_%s_%s.pdf", locationID, reportType))
-		w.WriteHeader(http.StatusOK)
-		w.Write(reportData)
-	} else {
-		h.sendError(w, http.StatusNotImplemented, "在庫分析機能がサポートされていません")
+// close unsubscribes from the live bus
+// ライブ配信用のバスの購読を解除
+func (s *liveSubscription) close() {
+	for _, unsubscribe := range s.unsubscribes {
+		unsubscribe()
 	}
 }
 
@@ -1141,17 +6372,71 @@ func (h *Handlers) sendSuccess(w http.ResponseWriter, data interface{}) {
 	}
 }
 
-// sendError sends an error API response
-// エラーAPIレスポンスを送信
+// sendAccepted sends a successful API response for a request processed in the background
+// バックグラウンドで処理するリクエストの成功APIレスポンス（202）を送信
+func (h *Handlers) sendAccepted(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+
+	if err := json.NewEncoder(w).Encode(APIResponse{Success: true, Data: data}); err != nil {
+		h.logger.Error("レスポンス送信に失敗しました", zap.Error(err))
+	}
+}
+
+// sendError sends an error API response with the generic error code of the status
+// ステータスに対応する汎用のエラーコードでエラーAPIレスポンスを送信
 func (h *Handlers) sendError(w http.ResponseWriter, statusCode int, message string) {
+	h.sendErrorCode(w, statusCode, statusErrorCode(statusCode), message)
+}
+
+// sendErrorCode sends an error API response with an error code
+// エラーコードを指定してエラーAPIレスポンスを送信
+func (h *Handlers) sendErrorCode(w http.ResponseWriter, statusCode int, code ErrorCode, message string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
-	
+
 	response := APIResponse{
-		Success: false,
-		Error:   message,
+		Success:   false,
+		Error:     inventory.Translate(message, responseLanguage(w)),
+		ErrorCode: code,
 	}
-	
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("エラーレスポンス送信に失敗しました", zap.Error(err))
+	}
+}
+
+// sendManagerError sends an error returned by the managers with the matching status and error code
+// マネージャーが返したエラーを対応するステータス・エラーコードで送信
+//
+// 在庫パッケージのエラーに該当しない場合は500を返し、エラーをログに記録します。
+func (h *Handlers) sendManagerError(w http.ResponseWriter, err error) {
+	status, code := errorStatus(err)
+	if status == http.StatusInternalServerError {
+		h.logger.Error("リクエストの処理に失敗しました", zap.String("request_id", responseRequestID(w)), zap.Error(err))
+	}
+	h.sendErrorCode(w, status, code, inventory.LocalizeError(err, responseLanguage(w)))
+}
+
+// sendErrorWithData sends an error API response with details in data
+// data に詳細を含むエラーAPIレスポンスを送信
+func (h *Handlers) sendErrorWithData(w http.ResponseWriter, statusCode int, message string, data interface{}) {
+	h.sendErrorCodeWithData(w, statusCode, statusErrorCode(statusCode), message, data)
+}
+
+// sendErrorCodeWithData sends an error API response with an error code and details in data
+// エラーコードを指定して data に詳細を含むエラーAPIレスポンスを送信
+func (h *Handlers) sendErrorCodeWithData(w http.ResponseWriter, statusCode int, code ErrorCode, message string, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := APIResponse{
+		Success:   false,
+		Data:      data,
+		Error:     inventory.Translate(message, responseLanguage(w)),
+		ErrorCode: code,
+	}
+
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		h.logger.Error("エラーレスポンス送信に失敗しました", zap.Error(err))
 	}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// readinessTimeout bounds the time spent checking each dependency
+// 依存サービスごとの確認にかける時間の上限
+const readinessTimeout = 2 * time.Second
+
+// notReadyMessage is the message of readiness responses when a dependency is unavailable
+// 依存サービスが利用できない場合のレディネスのメッセージ
+const notReadyMessage = "依存サービスが利用できません"
+
+func init() {
+	inventory.RegisterMessages(inventory.LanguageEnglish, map[string]string{
+		notReadyMessage: "a dependency is unavailable",
+	})
+}
+
+// dependencyCheck checks whether a dependency required to serve requests is available
+// リクエストの処理に必要な依存サービスが利用できるかを確認する
+type dependencyCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// Liveness reports that the process is running
+// プロセスが動作していることを返す（Kubernetes の livenessProbe 用）
+//
+// 依存サービスを確認しないため、データベースの障害でコンテナが再起動されることはありません。
+func (h *Handlers) Liveness(w http.ResponseWriter, r *http.Request) {
+	h.sendSuccess(w, HealthResponse{Status: "alive", Timestamp: time.Now(), Service: "zaiGoFramework"})
+}
+
+// Readiness checks the storage and the event publisher and reports the status of each
+// ストレージ・イベント発行者を確認し、依存サービスごとの状態を返す（Kubernetes の readinessProbe 用）
+//
+// いずれかが利用できない場合は503を返し、ロードバランサーの振り分け対象から外れます。
+func (h *Handlers) Readiness(w http.ResponseWriter, r *http.Request) {
+	response := ReadinessResponse{
+		Status:       "ready",
+		Timestamp:    time.Now(),
+		Dependencies: make(map[string]DependencyStatus, len(h.readiness)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, dependency := range h.readiness {
+		wg.Add(1)
+		go func(dependency dependencyCheck) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := dependency.check(ctx)
+			status := DependencyStatus{Status: "ok", LatencyMS: time.Since(start).Milliseconds()}
+			if err != nil {
+				status.Status = "unavailable"
+				status.Error = err.Error()
+				h.logger.Warn("依存サービスが利用できません", zap.String("dependency", dependency.name), zap.Error(err))
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			response.Dependencies[dependency.name] = status
+			if err != nil {
+				response.Status = "not_ready"
+			}
+		}(dependency)
+	}
+	wg.Wait()
+
+	if response.Status != "ready" {
+		h.sendErrorWithData(w, http.StatusServiceUnavailable, notReadyMessage, response)
+		return
+	}
+	h.sendSuccess(w, response)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestReadiness(t *testing.T) {
+	h := NewHandlers(nil, zap.NewNop())
+	h.readiness = []dependencyCheck{
+		{name: "database", check: func(ctx context.Context) error { return nil }},
+		{name: "events", check: func(ctx context.Context) error { return errors.New("RabbitMQとの接続が切断されています") }},
+	}
+
+	rec := httptest.NewRecorder()
+	h.Readiness(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	// 一つでも利用できない場合は503で依存サービスごとの状態を返す
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	var resp struct {
+		ErrorCode ErrorCode         `json:"error_code"`
+		Data      ReadinessResponse `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, ErrorCodeServiceUnavailable, resp.ErrorCode)
+	assert.Equal(t, "not_ready", resp.Data.Status)
+	assert.Equal(t, "ok", resp.Data.Dependencies["database"].Status)
+	assert.Equal(t, "unavailable", resp.Data.Dependencies["events"].Status)
+	assert.Equal(t, "RabbitMQとの接続が切断されています", resp.Data.Dependencies["events"].Error)
+
+	h.readiness = h.readiness[:1]
+	rec = httptest.NewRecorder()
+	h.Readiness(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestLiveness(t *testing.T) {
+	// 依存サービスの状態にかかわらず200を返す
+	h := NewHandlers(nil, zap.NewNop())
+	h.readiness = []dependencyCheck{{name: "database", check: func(ctx context.Context) error { return errors.New("down") }}}
+
+	rec := httptest.NewRecorder()
+	h.Liveness(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/events"
+)
+
+// TestStreamStock はWebSocketによる在庫変更のライブ配信のテスト
+func TestStreamStock(t *testing.T) {
+	t.Run("ライブ配信が未設定の場合は501", func(t *testing.T) {
+		h := NewHandlers(nil, zap.NewNop())
+		rec := httptest.NewRecorder()
+		h.StreamStock(rec, httptest.NewRequest(http.MethodGet, "/api/v1/ws/stock?location_id=LOC-A", nil))
+		assert.Equal(t, http.StatusNotImplemented, rec.Code)
+	})
+
+	t.Run("location_idは必須", func(t *testing.T) {
+		h := NewHandlers(nil, zap.NewNop())
+		h.live = events.NewBus(events.BusConfig{}, zap.NewNop())
+		rec := httptest.NewRecorder()
+		h.StreamStock(rec, httptest.NewRequest(http.MethodGet, "/api/v1/ws/stock", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("指定したロケーションの変更のみ配信し、シャットダウンで切断する", func(t *testing.T) {
+		bus := events.NewBus(events.BusConfig{}, zap.NewNop())
+		defer bus.Close()
+		streams, stopStreams := context.WithCancel(context.Background())
+		defer stopStreams()
+
+		h := NewHandlers(nil, zap.NewNop())
+		h.live = bus
+		h.streams = streams
+		server := httptest.NewServer(http.HandlerFunc(h.StreamStock))
+		defer server.Close()
+
+		url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/ws/stock?location_id=LOC-A"
+		ws, err := websocket.Dial(url, "", server.URL)
+		require.NoError(t, err)
+		defer ws.Close()
+
+		// 購読の開始を待つ間は繰り返し発行する（別のロケーションの変更を先に発行する）
+		received := make(chan inventory.StockChangedEvent, 1)
+		go func() {
+			var event inventory.StockChangedEvent
+			ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+			if err := websocket.JSON.Receive(ws, &event); err == nil {
+				received <- event
+			}
+			close(received)
+		}()
+		ctx := context.Background()
+		var event inventory.StockChangedEvent
+		var ok bool
+	publish:
+		for {
+			require.NoError(t, bus.PublishStockChanged(ctx, inventory.StockChangedEvent{ItemID: "ITEM-1", LocationID: "LOC-B", NewQuantity: 1, TransactionID: "TX-B"}))
+			require.NoError(t, bus.PublishStockChanged(ctx, inventory.StockChangedEvent{ItemID: "ITEM-1", LocationID: "LOC-A", NewQuantity: 2, TransactionID: "TX-A"}))
+			select {
+			case event, ok = <-received:
+				break publish
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+		require.True(t, ok, "在庫変更を受信できませんでした")
+		assert.Equal(t, "LOC-A", event.LocationID)
+		assert.Equal(t, "TX-A", event.TransactionID)
+		assert.Equal(t, int64(2), event.NewQuantity)
+
+		// シャットダウン時は接続を終了する
+		stopStreams()
+		ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+		for {
+			var discard inventory.StockChangedEvent
+			if err := websocket.JSON.Receive(ws, &discard); err != nil {
+				assert.NotContains(t, err.Error(), "timeout")
+				break
+			}
+		}
+	})
+}
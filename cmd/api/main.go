@@ -2,20 +2,35 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"log"
+	"math"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/gorilla/mux"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
 	"go.uber.org/zap"
 
 	"github.com/nemonet1337/zaiGoFramework/internal/config"
+	"github.com/nemonet1337/zaiGoFramework/internal/ratelimit"
 	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/auth"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/consumer"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/events"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/graphql"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/metrics"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/notify"
 	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/storage"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/webhooks"
 )
 
 func main() {
@@ -41,25 +56,342 @@ func main() {
 		cfg.Database.DBName,
 	)
 
-	storage, err := storage.NewPostgreSQLStorage(dsn, logger)
+	poolConfig := storage.PoolConfig{
+		MaxOpenConns:    cfg.Database.MaxOpenConns,
+		MaxIdleConns:    cfg.Database.MaxIdleConns,
+		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
+	}
+
+	// 読み取りレプリカが設定されている場合は照会・履歴・分析系の読み取りをレプリカに振り分ける
+	pgStorage, err := storage.NewPostgreSQLStorageWithReplicas(dsn, cfg.ReplicaDSNs(), logger)
 	if err != nil {
 		logger.Fatal("データベース接続に失敗しました", zap.Error(err))
 	}
-	defer storage.Close()
+	defer pgStorage.Close()
+	pgStorage.ConfigurePool(poolConfig)
+
+	// 今月から3か月先までのトランザクションパーティションを作成（以降は定期ジョブで作成する）
+	if err := pgStorage.EnsureTransactionPartitions(context.Background(), time.Now(), transactionPartitionMonths); err != nil {
+		logger.Error("トランザクションパーティションの作成に失敗しました", zap.Error(err))
+	}
 
 	// 在庫マネージャー初期化
 	inventoryConfig := &inventory.Config{
-		AllowNegativeStock: cfg.Inventory.AllowNegativeStock,
-		DefaultLocation:    cfg.Inventory.DefaultLocation,
-		AuditEnabled:       cfg.Inventory.AuditEnabled,
-		LowStockThreshold:  cfg.Inventory.LowStockThreshold,
-		AlertTimeout:       time.Duration(cfg.Inventory.AlertTimeoutHours) * time.Hour,
+		AllowNegativeStock:          cfg.Inventory.AllowNegativeStock,
+		DefaultLocation:             cfg.Inventory.DefaultLocation,
+		AuditEnabled:                cfg.Inventory.AuditEnabled,
+		LowStockThreshold:           cfg.Inventory.LowStockThreshold,
+		AlertTimeout:                time.Duration(cfg.Inventory.AlertTimeoutHours) * time.Hour,
+		LockingStrategy:             inventory.LockingStrategy(cfg.Inventory.LockingStrategy),
+		RetryMaxAttempts:            cfg.Inventory.RetryMaxAttempts,
+		RetryBaseDelay:              cfg.Inventory.RetryBaseDelay,
+		RetryMaxDelay:               cfg.Inventory.RetryMaxDelay,
+		RetryJitter:                 cfg.Inventory.RetryJitter,
+		RetentionMonths:             cfg.Inventory.RetentionMonths,
+		ReservationTTL:              cfg.Inventory.ReservationTTL,
+		EnableBackorders:            cfg.Inventory.EnableBackorders,
+		CapacityPolicy:              inventory.CapacityPolicy(cfg.Inventory.CapacityPolicy),
+		DisableLowStockCheck:        cfg.Inventory.DisableLowStockCheck,
+		AdjustmentApprovalThreshold: cfg.Inventory.AdjustmentApprovalThreshold,
+		LotPickingStrategy:          inventory.LotPickingStrategy(cfg.Inventory.LotPickingStrategy),
+	}
+
+	// Webhookサブスクリプションはプライマリの接続プールを共有する
+	webhookStore := webhooks.NewPostgresStore(pgStorage.DB())
+
+	// イベント発行者初期化（ドライバー未設定の場合はイベントを発行しない）
+	var publisher inventory.EventPublisher
+	eventTargets := make([]events.TargetConfig, 0, len(cfg.Events.Targets))
+	for _, target := range cfg.Events.Targets {
+		eventTargets = append(eventTargets, events.TargetConfig{
+			Name:        target.Name,
+			Driver:      target.Driver,
+			EventTypes:  target.EventTypes,
+			LocationIDs: target.LocationIDs,
+			Routes:      eventRoutes(target.Routes),
+		})
+	}
+	// 共有のブローカーで単価などが他チームに見えないよう、イベント本体を暗号化する
+	var eventKeys events.KeyProvider
+	if cfg.Events.Encryption.Enabled {
+		key, err := base64.StdEncoding.DecodeString(cfg.Events.Encryption.Key)
+		if err != nil {
+			logger.Fatal("イベント暗号鍵のデコードに失敗しました", zap.Error(err))
+		}
+		eventKeys, err = events.NewStaticKeyProvider(cfg.Events.Encryption.KeyID, key)
+		if err != nil {
+			logger.Fatal("イベント暗号鍵の初期化に失敗しました", zap.Error(err))
+		}
+	}
+	eventPublisher, err := events.Open(events.Config{
+		Driver: cfg.Events.Driver,
+		Format: cfg.Events.Format,
+		Source: cfg.Events.Source,
+		Schema: cfg.Events.SchemaVersion,
+		RabbitMQ: events.RabbitMQConfig{
+			URL:             cfg.Events.RabbitMQ.URL,
+			Exchange:        cfg.Events.RabbitMQ.Exchange,
+			ExchangeType:    cfg.Events.RabbitMQ.ExchangeType,
+			DeclareExchange: cfg.Events.RabbitMQ.DeclareExchange,
+			RoutingKey:      cfg.Events.RabbitMQ.RoutingKey,
+			Confirm:         cfg.Events.RabbitMQ.Confirm,
+			ConfirmTimeout:  cfg.Events.RabbitMQ.ConfirmTimeout,
+		},
+		PubSub: events.PubSubConfig{
+			ProjectID:       cfg.Events.PubSub.ProjectID,
+			Mode:            cfg.Events.PubSub.Mode,
+			Topic:           cfg.Events.PubSub.Topic,
+			TopicTemplate:   cfg.Events.PubSub.TopicTemplate,
+			Ordering:        cfg.Events.PubSub.Ordering,
+			CredentialsFile: cfg.Events.PubSub.CredentialsFile,
+		},
+		MQTT: events.MQTTConfig{
+			URL:            cfg.Events.MQTT.URL,
+			ClientID:       cfg.Events.MQTT.ClientID,
+			Username:       cfg.Events.MQTT.Username,
+			Password:       cfg.Events.MQTT.Password,
+			Topic:          cfg.Events.MQTT.Topic,
+			QoS:            byte(cfg.Events.MQTT.QoS),
+			Retain:         cfg.Events.MQTT.Retain,
+			KeepAlive:      cfg.Events.MQTT.KeepAlive,
+			ConnectTimeout: cfg.Events.MQTT.ConnectTimeout,
+			AckTimeout:     cfg.Events.MQTT.AckTimeout,
+		},
+		Kinesis: events.KinesisConfig{
+			Region:          cfg.Events.Kinesis.Region,
+			Stream:          cfg.Events.Kinesis.Stream,
+			Endpoint:        cfg.Events.Kinesis.Endpoint,
+			AccessKeyID:     cfg.Events.Kinesis.AccessKeyID,
+			SecretAccessKey: cfg.Events.Kinesis.SecretAccessKey,
+			SessionToken:    cfg.Events.Kinesis.SessionToken,
+			Timeout:         cfg.Events.Kinesis.Timeout,
+		},
+		Targets: eventTargets,
+		Routes:  eventRoutes(cfg.Events.Routes),
+		Keys:    eventKeys,
+		Drivers: map[string]events.SenderFactory{
+			// webhooksパッケージはeventsに依存するため、呼び出し側から提供する
+			"webhook": func() (events.Sender, error) {
+				return webhooks.NewDispatcher(webhookStore, webhooks.DispatcherConfig{
+					Workers:              cfg.Events.Webhook.Workers,
+					QueueSize:            cfg.Events.Webhook.QueueSize,
+					MaxAttempts:          cfg.Events.Webhook.MaxAttempts,
+					BaseDelay:            cfg.Events.Webhook.BaseDelay,
+					MaxDelay:             cfg.Events.Webhook.MaxDelay,
+					Timeout:              cfg.Events.Webhook.Timeout,
+					AllowPrivateNetworks: cfg.Events.Webhook.AllowPrivateNetworks,
+				}, logger), nil
+			},
+		},
+	}, logger)
+	if err != nil {
+		logger.Fatal("イベント発行者の初期化に失敗しました", zap.Error(err))
+	}
+	// 在庫操作がブローカーの応答を待たないよう、イベントをバッファに積んでバックグラウンドで配信する
+	// （バッファが満杯の場合は下の再試行キューに保存される）
+	if eventPublisher != nil && cfg.Events.Buffer.Enabled {
+		buffered, err := events.NewBufferedPublisher(eventPublisher, events.BufferConfig{
+			Capacity:     cfg.Events.Buffer.Capacity,
+			Path:         cfg.Events.Buffer.Path,
+			BaseDelay:    cfg.Events.Buffer.BaseDelay,
+			MaxDelay:     cfg.Events.Buffer.MaxDelay,
+			Timeout:      cfg.Events.Buffer.Timeout,
+			DrainTimeout: cfg.Events.Buffer.DrainTimeout,
+		}, nil, logger)
+		if err != nil {
+			logger.Fatal("イベントバッファの初期化に失敗しました", zap.Error(err))
+		}
+		eventPublisher = buffered
+	}
+	// 発行に失敗したイベントは再試行キューに保存し、バックグラウンドで再試行する
+	var eventRetry *events.RetryingPublisher
+	if eventPublisher != nil && cfg.Events.Retry.Enabled {
+		eventRetry, err = events.NewRetryingPublisher(eventPublisher, events.NewPostgresRetryStore(pgStorage.DB()), events.RetryConfig{
+			MaxAttempts: cfg.Events.Retry.MaxAttempts,
+			BaseDelay:   cfg.Events.Retry.BaseDelay,
+			MaxDelay:    cfg.Events.Retry.MaxDelay,
+			Interval:    cfg.Events.Retry.Interval,
+			BatchSize:   cfg.Events.Retry.BatchSize,
+		}, nil, logger)
+		if err != nil {
+			logger.Fatal("イベント再試行の初期化に失敗しました", zap.Error(err))
+		}
+		eventPublisher = eventRetry
+	}
+	// ダッシュボード向けのライブ配信はプロセス内イベントバスから購読する
+	// （配信順序を保つためワーカーは1つ）
+	liveBus := events.NewBus(events.BusConfig{Workers: 1, QueueSize: 10000}, logger)
+	defer liveBus.Close()
+	publishTargets := []events.Target{{Name: "live", Publisher: liveBus}}
+	if eventPublisher != nil {
+		publishTargets = append([]events.Target{{Name: cfg.Events.Driver, Publisher: eventPublisher}}, publishTargets...)
+		defer eventPublisher.Close()
 	}
 
-	manager := inventory.NewManager(storage, nil, logger, inventoryConfig)
+	// Prometheusメトリクス（在庫操作の結果・在庫変動・アラート・接続プール）
+	var collector *metrics.Collector
+	if cfg.API.EnableMetrics {
+		collector, err = metrics.New(nil)
+		if err != nil {
+			logger.Fatal("メトリクスの初期化に失敗しました", zap.Error(err))
+		}
+		if err := metrics.RegisterDBStats(nil, pgStorage.DB(), "primary"); err != nil {
+			logger.Fatal("接続プールのメトリクスの登録に失敗しました", zap.Error(err))
+		}
+		inventoryConfig.Observer = collector
+		publishTargets = append(publishTargets, events.Target{Name: "metrics", Publisher: collector})
+	}
+	// 作成されたアラートをメール・Slack・Webhookで通知する
+	notifier, err := alertNotifier(cfg.Notifications, logger)
+	if err != nil {
+		logger.Fatal("アラート通知の初期化に失敗しました", zap.Error(err))
+	}
+	if notifier != nil {
+		defer notifier.Close()
+		publishTargets = append(publishTargets, events.Target{
+			Name:      "notifications",
+			Publisher: notifier,
+			Filter:    events.Filter{EventTypes: []string{inventory.EventTypeAlertCreated}},
+		})
+	}
+	publisher = events.NewFanout(publishTargets...)
+
+	// HTTP → マネージャー → ストレージを一つのトレースとして記録する
+	var managerStorage inventory.Storage = pgStorage
+	if cfg.Tracing.Enabled {
+		shutdownTracing, err := setupTracing(cfg.Tracing)
+		if err != nil {
+			logger.Fatal("トレースの初期化に失敗しました", zap.Error(err))
+		}
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := shutdownTracing(ctx); err != nil {
+				logger.Warn("未送信のスパンの送信に失敗しました", zap.Error(err))
+			}
+		}()
+		managerStorage = storage.NewTracingStorage(managerStorage, nil)
+	}
+
+	manager := inventory.NewManager(managerStorage, publisher, logger, inventoryConfig)
+
+	// 非同期バッチ（?async=true）はストレージ・イベント発行者より先に停止する
+	batchQueue := inventory.NewBatchQueue(manager, inventory.BatchQueueConfig{
+		Workers:          cfg.Inventory.BatchWorkers,
+		QueueSize:        cfg.Inventory.BatchQueueSize,
+		ProgressInterval: cfg.Inventory.BatchProgressInterval,
+	})
+	defer batchQueue.Close()
 
 	// HTTPハンドラー設定
 	handlers := NewHandlers(manager, logger)
+
+	// 予約の期限切れ・アラートルールの評価・低在庫の検出・スナップショットなどの定期ジョブ
+	var stopJobs func()
+	if cfg.Scheduler.Enabled {
+		jobs, err := newScheduler(cfg, manager, pgStorage, logger)
+		if err != nil {
+			logger.Fatal("定期ジョブの設定に失敗しました", zap.Error(err))
+		}
+		jobs.Start()
+		stopJobs = jobs.Stop
+		handlers.scheduler = jobs
+	} else {
+		// 定期ジョブを実行しないプロセスでも、予約の期限切れとアラートルールの評価は従来どおり実行する
+		jobCtx, cancelJobs := context.WithCancel(context.Background())
+		stopJobs = cancelJobs
+		go manager.RunReservationExpiry(jobCtx, cfg.Inventory.ReservationExpiryInterval)
+		go manager.RunAlertRuleEvaluation(jobCtx, cfg.Inventory.AlertRuleInterval)
+	}
+	defer stopJobs()
+	handlers.webhooks = webhookStore
+	handlers.eventRetry = eventRetry
+	handlers.batches = batchQueue
+	handlers.metrics = collector
+
+	// 外部システムからの在庫同期（冪等性キーはプライマリの接続プールに記録する）
+	stockSync := consumer.NewConsumer(manager, consumer.NewPostgresStore(pgStorage.DB()), consumer.Config{
+		ClaimTTL: cfg.Consumer.ClaimTTL,
+	}, logger)
+	handlers.consumer = stockSync
+	handlers.live = liveBus
+
+	// フロントエンド向けのGraphQLエンドポイント（照会のみ）
+	if cfg.GraphQL.Enabled {
+		handlers.graphql = graphql.NewHandler(manager, graphql.Config{MaxDepth: cfg.GraphQL.MaxDepth}, logger)
+	}
+	handlers.swaggerUI = cfg.API.EnableSwaggerUI
+	handlers.debug = cfg.API.EnableDebug
+	handlers.maxBodyBytes = cfg.API.MaxBodyBytes
+	handlers.versions, err = configureVersions(apiVersions, cfg.API.Versions)
+	if err != nil {
+		logger.Fatal("APIバージョンの設定に失敗しました", zap.Error(err))
+	}
+	handlers.readiness = []dependencyCheck{{name: "database", check: pgStorage.Ping}}
+	if eventPublisher != nil {
+		handlers.readiness = append(handlers.readiness, dependencyCheck{name: "events", check: func(ctx context.Context) error {
+			return events.Ping(ctx, eventPublisher)
+		}})
+	}
+	if cfg.Tracing.Enabled {
+		handlers.tracing = cfg.Tracing.ServiceName
+	}
+
+	// APIキー（システム間連携）・JWTベアラートークンによる認証
+	if cfg.API.EnableAuth {
+		var authenticators []auth.Authenticator
+		if cfg.API.Auth.APIKeysEnabled {
+			handlers.apiKeys = auth.NewAPIKeys(auth.NewPostgresAPIKeyStore(pgStorage.DB()))
+			authenticators = append(authenticators, handlers.apiKeys)
+		}
+		if cfg.API.Auth.JWKSURL != "" || cfg.API.Auth.HMACSecret != "" {
+			verifier, err := auth.NewJWTVerifier(auth.JWTConfig{
+				Issuer:          cfg.API.Auth.Issuer,
+				Audience:        cfg.API.Auth.Audience,
+				JWKSURL:         cfg.API.Auth.JWKSURL,
+				HMACSecret:      cfg.API.Auth.HMACSecret,
+				UserClaim:       cfg.API.Auth.UserClaim,
+				Leeway:          cfg.API.Auth.Leeway,
+				RefreshInterval: cfg.API.Auth.JWKSRefreshInterval,
+			})
+			if err != nil {
+				logger.Fatal("認証の初期化に失敗しました", zap.Error(err))
+			}
+			authenticators = append(authenticators, verifier)
+		}
+		handlers.authenticator = auth.Any(authenticators...)
+		handlers.unscopedJWT = cfg.API.Auth.JWTAllowUnscoped
+		if cfg.API.Auth.JWTAllowUnscoped {
+			logger.Warn("スコープを含まないJWTに全ての操作を許可しています（AUTH_JWT_ALLOW_UNSCOPED）")
+		}
+	}
+
+	// 一部の連携先からの大量のリクエストでDB接続プールが枯渇しないよう、クライアントごとに頻度を制限する
+	if cfg.API.RateLimit.Enabled {
+		handlers.rateLimit = newRateLimiter(cfg.API.RateLimit)
+	}
+
+	// シャットダウン時にライブ配信の接続を終了する
+	streamCtx, stopStreams := context.WithCancel(context.Background())
+	defer stopStreams()
+	handlers.streams = streamCtx
+
+	consumeCtx, stopConsume := context.WithCancel(context.Background())
+	defer stopConsume()
+	if cfg.Consumer.Enabled {
+		go func() {
+			err := stockSync.ConsumeRabbitMQ(consumeCtx, consumer.RabbitMQConfig{
+				URL:          cfg.Consumer.RabbitMQ.URL,
+				Queue:        cfg.Consumer.RabbitMQ.Queue,
+				DeclareQueue: cfg.Consumer.RabbitMQ.DeclareQueue,
+				Prefetch:     cfg.Consumer.RabbitMQ.Prefetch,
+				RequeueDelay: cfg.Consumer.RabbitMQ.RequeueDelay,
+			})
+			if err != nil {
+				logger.Error("同期メッセージの受信が停止しました", zap.Error(err))
+			}
+		}()
+	}
 	router := setupRouter(handlers)
 
 	// HTTPサーバー設定
@@ -70,11 +402,32 @@ func main() {
 		WriteTimeout: 30 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
+	server.RegisterOnShutdown(stopStreams)
+
+	// 証明書を指定した場合はプロキシを介さずHTTPSで提供する
+	if cfg.API.TLS.Enabled() {
+		tlsConfig, err := newTLSConfig(cfg.API.TLS)
+		if err != nil {
+			logger.Fatal("TLSの設定に失敗しました", zap.Error(err))
+		}
+		server.TLSConfig = tlsConfig
+	}
 
 	// グレースフルシャットダウン設定
 	go func() {
-		logger.Info("在庫管理APIサーバーを開始します", zap.Int("port", cfg.API.Port))
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		logger.Info("在庫管理APIサーバーを開始します",
+			zap.Int("port", cfg.API.Port),
+			zap.Bool("tls", server.TLSConfig != nil),
+			zap.Bool("client_auth", cfg.API.TLS.ClientCAFile != ""),
+		)
+		var err error
+		if server.TLSConfig != nil {
+			// 証明書は TLSConfig に読み込み済み
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			logger.Fatal("サーバー開始に失敗しました", zap.Error(err))
 		}
 	}()
@@ -85,6 +438,8 @@ func main() {
 	<-quit
 
 	logger.Info("サーバーをシャットダウンしています...")
+	stopConsume()
+	stopJobs()
 
 	// グレースフルシャットダウン
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -104,28 +459,97 @@ func setupRouter(handlers *Handlers) *mux.Router {
 
 	// ヘルスチェック
 	router.HandleFunc("/health", handlers.HealthCheck).Methods("GET")
+	router.HandleFunc("/healthz", handlers.Liveness).Methods("GET")
+	router.HandleFunc("/readyz", handlers.Readiness).Methods("GET")
 	router.HandleFunc("/metrics", handlers.Metrics).Methods("GET")
 
-	// API v1ルート
-	api := router.PathPrefix("/api/v1").Subrouter()
+	// APIルート（/api/v1・/api/v2）
+	handlers.mountVersions(router)
+
+	// OpenAPIドキュメント・Swagger UI（全てのルートを登録した後に生成する）
+	handlers.mountOpenAPI(router)
+
+	// CORS設定（開発用）
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, Idempotency-Key, If-Match, Accept-Language, X-Request-ID")
+			w.Header().Set("Access-Control-Expose-Headers", "ETag, Content-Language, X-Request-ID, API-Version, Deprecation, Sunset, Link")
+
+			if r.Method == "OPTIONS" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	})
+
+	// リクエストID（全てのログ・エラーレスポンスに含めるため最初に実行）
+	router.Use(requestIDMiddleware)
+
+	// リクエスト本文の上限
+	router.Use(handlers.bodyLimitMiddleware)
+
+	// トレース（呼び出し元の traceparent を引き継ぎ、リクエストのスパンを開始する）
+	if handlers.tracing != "" {
+		router.Use(otelmux.Middleware(handlers.tracing))
+	}
 
+	// メトリクス（認証・頻度制限で拒否したリクエストも記録するため外側で実行）
+	router.Use(handlers.metricsMiddleware)
+
+	// ログ機能
+	router.Use(loggingMiddleware(handlers.logger))
+
+	// エラーメッセージの言語（認証・頻度制限のエラーも翻訳するため先に実行）
+	router.Use(languageMiddleware)
+
+	// IPアドレスごとの頻度制限（未認証の大量のリクエストや認証情報の総当たりを制限するため認証の前に実行）
+	router.Use(handlers.ipRateLimitMiddleware)
+
+	// 認証（認証失敗もログに記録するためログ機能の内側で実行）
+	router.Use(handlers.authMiddleware)
+
+	// 頻度制限（APIキー・ユーザーごとに制限するため認証の内側で実行）
+	router.Use(handlers.rateLimitMiddleware)
+
+	return router
+}
+
+// registerV1Routes registers the routes of API v1
+// API v1のルートを登録
+func registerV1Routes(api *mux.Router, handlers *Handlers) {
 	// 在庫操作
 	api.HandleFunc("/inventory/add", handlers.AddStock).Methods("POST")
 	api.HandleFunc("/inventory/remove", handlers.RemoveStock).Methods("POST")
 	api.HandleFunc("/inventory/transfer", handlers.TransferStock).Methods("POST")
 	api.HandleFunc("/inventory/adjust", handlers.AdjustStock).Methods("POST")
 	api.HandleFunc("/inventory/batch", handlers.BatchOperation).Methods("POST")
+	api.HandleFunc("/inventory/lookup", handlers.LookupStocks).Methods("POST")
+
+	// メタデータ検索（/inventory/{itemId}/{locationId} に一致しないよう先に登録）
+	api.HandleFunc("/inventory/history/metadata", handlers.SearchHistoryByMetadata).Methods("GET")
 
 	// 在庫照会
 	api.HandleFunc("/inventory/{itemId}/{locationId}", handlers.GetStock).Methods("GET")
+	api.HandleFunc("/inventory/{itemId}/{locationId}/ownership", handlers.GetStockOwnership).Methods("GET")
+	api.HandleFunc("/inventory/{itemId}/{locationId}/channels", handlers.GetChannelAvailability).Methods("GET")
+	api.HandleFunc("/inventory/{itemId}/{locationId}/channels/{channel}", handlers.SetChannelAllocation).Methods("PUT")
 	api.HandleFunc("/inventory/{itemId}/total", handlers.GetTotalStock).Methods("GET")
 	api.HandleFunc("/inventory/location/{locationId}", handlers.GetStockByLocation).Methods("GET")
+	api.HandleFunc("/inventory/location/{locationId}/export", handlers.ExportStockByLocation).Methods("GET")
 
 	// 履歴
 	api.HandleFunc("/inventory/{itemId}/history", handlers.GetHistory).Methods("GET")
+	api.HandleFunc("/inventory/{itemId}/history/export", handlers.ExportHistory).Methods("GET")
 
 	// アラート
+	api.HandleFunc("/alerts", handlers.ListAlerts).Methods("GET")
+	api.HandleFunc("/alerts/stream", handlers.StreamAlerts).Methods("GET")
 	api.HandleFunc("/alerts/{locationId}", handlers.GetAlerts).Methods("GET")
+	api.HandleFunc("/alerts/{alertId}/acknowledge", handlers.AcknowledgeAlert).Methods("POST")
 	api.HandleFunc("/alerts/{alertId}/resolve", handlers.ResolveAlert).Methods("POST")
 
 	// 商品管理
@@ -134,6 +558,7 @@ func setupRouter(handlers *Handlers) *mux.Router {
 	api.HandleFunc("/items/search", handlers.SearchItems).Methods("GET")
 	api.HandleFunc("/items/{itemId}", handlers.GetItem).Methods("GET")
 	api.HandleFunc("/items/{itemId}", handlers.UpdateItem).Methods("PUT")
+	api.HandleFunc("/items/{itemId}", handlers.PatchItem).Methods("PATCH")
 	api.HandleFunc("/items/{itemId}", handlers.DeleteItem).Methods("DELETE")
 
 	// ロケーション管理
@@ -141,25 +566,133 @@ func setupRouter(handlers *Handlers) *mux.Router {
 	api.HandleFunc("/locations", handlers.ListLocations).Methods("GET")
 	api.HandleFunc("/locations/{locationId}", handlers.GetLocation).Methods("GET")
 	api.HandleFunc("/locations/{locationId}", handlers.UpdateLocation).Methods("PUT")
+	api.HandleFunc("/locations/{locationId}", handlers.PatchLocation).Methods("PATCH")
 	api.HandleFunc("/locations/{locationId}", handlers.DeleteLocation).Methods("DELETE")
 
+	// CSV取り込み
+	api.HandleFunc("/import/items", handlers.ImportItems).Methods("POST")
+	api.HandleFunc("/import/opening-balances", handlers.ImportOpeningBalances).Methods("POST")
+
 	// ロット管理
 	api.HandleFunc("/lots", handlers.CreateLot).Methods("POST")
 	api.HandleFunc("/lots/{lotId}", handlers.GetLot).Methods("GET")
+	api.HandleFunc("/lots/{lotId}", handlers.UpdateLot).Methods("PUT")
+	api.HandleFunc("/lots/{lotId}", handlers.DeleteLot).Methods("DELETE")
+	api.HandleFunc("/lots/{lotId}/adjust", handlers.AdjustLot).Methods("POST")
 	api.HandleFunc("/lots/item/{itemId}", handlers.GetLotsByItem).Methods("GET")
 	api.HandleFunc("/lots/expiring", handlers.GetExpiringLots).Methods("GET")
+	api.HandleFunc("/lots/expiring/notify", handlers.NotifyExpiringLots).Methods("POST")
 	api.HandleFunc("/lots/expired", handlers.GetExpiredLots).Methods("GET")
+	api.HandleFunc("/lots/{lotId}/stocks/{locationId}", handlers.GetLotStock).Methods("GET")
+	api.HandleFunc("/lot-stocks", handlers.ListLotStocks).Methods("GET")
+
+	// 単位管理
+	api.HandleFunc("/units", handlers.CreateUnit).Methods("POST")
+	api.HandleFunc("/units", handlers.ListUnits).Methods("GET")
+	api.HandleFunc("/units/{unitCode}", handlers.GetUnit).Methods("GET")
+	api.HandleFunc("/units/{unitCode}", handlers.DeleteUnit).Methods("DELETE")
+	api.HandleFunc("/items/{itemId}/units", handlers.ListUnitConversions).Methods("GET")
+	api.HandleFunc("/items/{itemId}/units/{uom}", handlers.SetUnitConversion).Methods("PUT")
+	api.HandleFunc("/items/{itemId}/units/{uom}", handlers.DeleteUnitConversion).Methods("DELETE")
+
+	// キット管理
+	api.HandleFunc("/boms", handlers.ListBillsOfMaterials).Methods("GET")
+	api.HandleFunc("/boms/{itemId}", handlers.GetBillOfMaterials).Methods("GET")
+	api.HandleFunc("/boms/{itemId}", handlers.SetBillOfMaterials).Methods("PUT")
+	api.HandleFunc("/boms/{itemId}", handlers.DeleteBillOfMaterials).Methods("DELETE")
+	api.HandleFunc("/inventory/assemble", handlers.AssembleKit).Methods("POST")
+	api.HandleFunc("/inventory/disassemble", handlers.DisassembleKit).Methods("POST")
+	api.HandleFunc("/assemblies", handlers.ListAssemblies).Methods("GET")
+	api.HandleFunc("/assemblies/{assemblyId}", handlers.GetAssembly).Methods("GET")
+
+	// 仕入先管理
+	api.HandleFunc("/suppliers", handlers.ListSuppliers).Methods("GET")
+	api.HandleFunc("/suppliers", handlers.CreateSupplier).Methods("POST")
+	api.HandleFunc("/suppliers/{supplierId}", handlers.GetSupplier).Methods("GET")
+	api.HandleFunc("/suppliers/{supplierId}", handlers.UpdateSupplier).Methods("PUT")
+	api.HandleFunc("/suppliers/{supplierId}", handlers.DeleteSupplier).Methods("DELETE")
+	api.HandleFunc("/suppliers/{supplierId}/items", handlers.ListSupplierItems).Methods("GET")
+	api.HandleFunc("/suppliers/{supplierId}/items/{itemId}", handlers.GetSupplierItem).Methods("GET")
+	api.HandleFunc("/suppliers/{supplierId}/items/{itemId}", handlers.SetSupplierItem).Methods("PUT")
+	api.HandleFunc("/suppliers/{supplierId}/items/{itemId}", handlers.DeleteSupplierItem).Methods("DELETE")
+	api.HandleFunc("/items/{itemId}/suppliers", handlers.ListItemSuppliers).Methods("GET")
+
+	// 発注管理
+	api.HandleFunc("/purchase-orders", handlers.ListPurchaseOrders).Methods("GET")
+	api.HandleFunc("/purchase-orders", handlers.CreatePurchaseOrder).Methods("POST")
+	api.HandleFunc("/purchase-orders/{purchaseOrderId}", handlers.GetPurchaseOrder).Methods("GET")
+	api.HandleFunc("/purchase-orders/{purchaseOrderId}/approve", handlers.ApprovePurchaseOrder).Methods("POST")
+	api.HandleFunc("/purchase-orders/{purchaseOrderId}/send", handlers.SendPurchaseOrder).Methods("POST")
+	api.HandleFunc("/purchase-orders/{purchaseOrderId}/cancel", handlers.CancelPurchaseOrder).Methods("POST")
+	api.HandleFunc("/purchase-orders/{purchaseOrderId}/receipts", handlers.ListGoodsReceipts).Methods("GET")
+	api.HandleFunc("/purchase-orders/{purchaseOrderId}/receipts", handlers.ReceivePurchaseOrder).Methods("POST")
+
+	// 受注管理
+	api.HandleFunc("/sales-orders", handlers.ListSalesOrders).Methods("GET")
+	api.HandleFunc("/sales-orders", handlers.CreateSalesOrder).Methods("POST")
+	api.HandleFunc("/sales-orders/{salesOrderId}", handlers.GetSalesOrder).Methods("GET")
+	api.HandleFunc("/sales-orders/{salesOrderId}/allocate", handlers.AllocateSalesOrder).Methods("POST")
+	api.HandleFunc("/sales-orders/{salesOrderId}/ship", handlers.ShipSalesOrder).Methods("POST")
+	api.HandleFunc("/sales-orders/{salesOrderId}/cancel", handlers.CancelSalesOrder).Methods("POST")
+	api.HandleFunc("/sales-orders/{salesOrderId}/stock-status", handlers.GetSalesOrderStockStatus).Methods("GET")
+
+	// 返品管理
+	api.HandleFunc("/returns", handlers.ListReturns).Methods("GET")
+	api.HandleFunc("/returns", handlers.CreateReturn).Methods("POST")
+	api.HandleFunc("/returns/{returnId}", handlers.GetReturn).Methods("GET")
+	api.HandleFunc("/returns/{returnId}/receive", handlers.ReceiveReturn).Methods("POST")
+	api.HandleFunc("/returns/{returnId}/dispositions", handlers.ListReturnDispositions).Methods("GET")
+	api.HandleFunc("/returns/{returnId}/dispositions", handlers.DispositionReturn).Methods("POST")
+	api.HandleFunc("/returns/{returnId}/cancel", handlers.CancelReturn).Methods("POST")
+
+	// 委託在庫
+	api.HandleFunc("/consignment-stocks", handlers.ListConsignmentStocks).Methods("GET")
+
+	// ラベル印刷
+	api.HandleFunc("/labels", handlers.RenderLabels).Methods("POST")
+	api.HandleFunc("/labels/templates", handlers.ListLabelTemplates).Methods("GET")
 
 	// 予約管理
 	api.HandleFunc("/inventory/reserve", handlers.ReserveStock).Methods("POST")
 	api.HandleFunc("/inventory/release-reservation", handlers.ReleaseReservation).Methods("POST")
+	api.HandleFunc("/reservations", handlers.CreateReservation).Methods("POST")
+	api.HandleFunc("/reservations", handlers.ListReservations).Methods("GET")
+	api.HandleFunc("/reservations/{reservationId}", handlers.GetReservation).Methods("GET")
+	api.HandleFunc("/reservations/{reservationId}/release", handlers.ReleaseReservationByID).Methods("POST")
+	api.HandleFunc("/reservations/{reservationId}/fulfill", handlers.FulfillReservation).Methods("POST")
+	api.HandleFunc("/reservations/reference/{ref}", handlers.GetReservationsByReference).Methods("GET")
+	api.HandleFunc("/reservations/reference/{ref}/release", handlers.ReleaseReservationsByReference).Methods("POST")
+
+	// 発注点管理
+	api.HandleFunc("/reorder-points", handlers.ListReorderPoints).Methods("GET")
+	api.HandleFunc("/reorder-points/{itemId}/{locationId}", handlers.GetReorderPoint).Methods("GET")
+	api.HandleFunc("/reorder-points/{itemId}/{locationId}", handlers.SetReorderPoint).Methods("PUT")
+	api.HandleFunc("/reorder-points/{itemId}/{locationId}", handlers.DeleteReorderPoint).Methods("DELETE")
+
+	// アラートルール管理
+	api.HandleFunc("/alert-rules", handlers.ListAlertRules).Methods("GET")
+	api.HandleFunc("/alert-rules", handlers.CreateAlertRule).Methods("POST")
+	api.HandleFunc("/alert-rules/evaluate", handlers.EvaluateAlertRules).Methods("POST")
+	api.HandleFunc("/alert-rules/{ruleId}", handlers.GetAlertRule).Methods("GET")
+	api.HandleFunc("/alert-rules/{ruleId}", handlers.UpdateAlertRule).Methods("PUT")
+	api.HandleFunc("/alert-rules/{ruleId}", handlers.DeleteAlertRule).Methods("DELETE")
+
+	// バックオーダー管理
+	api.HandleFunc("/backorders", handlers.ListBackorders).Methods("GET")
+	api.HandleFunc("/backorders/allocate", handlers.AllocateBackorders).Methods("POST")
+	api.HandleFunc("/backorders/{backorderId}", handlers.GetBackorder).Methods("GET")
+	api.HandleFunc("/backorders/{backorderId}/cancel", handlers.CancelBackorder).Methods("POST")
 
 	// 履歴管理（追加）
 	api.HandleFunc("/inventory/history/location/{locationId}", handlers.GetHistoryByLocation).Methods("GET")
+	api.HandleFunc("/inventory/history/reference/{ref}", handlers.GetHistoryByReference).Methods("GET")
+	api.HandleFunc("/transactions/{txId}", handlers.GetTransaction).Methods("GET")
+	api.HandleFunc("/transactions/{txId}/reverse", handlers.ReverseTransaction).Methods("POST")
 	api.HandleFunc("/inventory/{itemId}/history/date-range", handlers.GetHistoryByDateRange).Methods("GET")
 
 	// バッチ管理（追加）
 	api.HandleFunc("/inventory/batch/{batchId}/status", handlers.GetBatchStatus).Methods("GET")
+	api.HandleFunc("/inventory/batch/{batchId}/cancel", handlers.CancelBatch).Methods("POST")
 
 	// 在庫評価エンジン
 	api.HandleFunc("/valuation/{itemId}/{locationId}", handlers.CalculateValue).Methods("GET")
@@ -172,26 +705,78 @@ func setupRouter(handlers *Handlers) *mux.Router {
 	api.HandleFunc("/analytics/slow-moving/{locationId}", handlers.GetSlowMovingItems).Methods("GET")
 	api.HandleFunc("/analytics/report/{locationId}", handlers.GenerateStockReport).Methods("GET")
 
-	// CORS設定（開発用）
-	router.Use(func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Set("Access-Control-Allow-Origin", "*")
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	// ダッシュボード
+	api.HandleFunc("/summary", handlers.GetSummary).Methods("GET")
 
-			if r.Method == "OPTIONS" {
-				w.WriteHeader(http.StatusOK)
-				return
-			}
+	// 在庫スナップショット
+	api.HandleFunc("/snapshots", handlers.ListStockSnapshots).Methods("GET")
+	api.HandleFunc("/snapshots/{snapshotId}/stocks", handlers.GetStockSnapshotLines).Methods("GET")
 
-			next.ServeHTTP(w, r)
-		})
-	})
+	// 棚卸
+	api.HandleFunc("/stocktakes", handlers.CreateStocktake).Methods("POST")
+	api.HandleFunc("/stocktakes", handlers.ListStocktakes).Methods("GET")
+	api.HandleFunc("/stocktakes/{stocktakeId}", handlers.GetStocktake).Methods("GET")
+	api.HandleFunc("/stocktakes/{stocktakeId}/counts", handlers.RecordStocktakeCounts).Methods("PUT")
+	api.HandleFunc("/stocktakes/{stocktakeId}/submit", handlers.SubmitStocktake).Methods("POST")
+	api.HandleFunc("/stocktakes/{stocktakeId}/apply", handlers.ApplyStocktake).Methods("POST")
+	api.HandleFunc("/stocktakes/{stocktakeId}/cancel", handlers.CancelStocktake).Methods("POST")
 
-	// ログ機能
-	router.Use(loggingMiddleware(handlers.logger))
+	// 在庫調整の承認（承認・却下は管理API）
+	api.HandleFunc("/adjustments", handlers.ListAdjustments).Methods("GET")
+	api.HandleFunc("/adjustments/{adjustmentId}", handlers.GetAdjustment).Methods("GET")
+	api.HandleFunc("/admin/adjustments/{adjustmentId}/approve", handlers.ApproveAdjustment).Methods("POST")
+	api.HandleFunc("/admin/adjustments/{adjustmentId}/reject", handlers.RejectAdjustment).Methods("POST")
 
-	return router
+	// 入荷検品（隔離・品質検査）
+	api.HandleFunc("/inspections", handlers.ReceiveForInspection).Methods("POST")
+	api.HandleFunc("/inspections", handlers.ListInspections).Methods("GET")
+	api.HandleFunc("/inspections/{inspectionId}", handlers.GetInspection).Methods("GET")
+	api.HandleFunc("/inspections/{inspectionId}/pass", handlers.PassInspection).Methods("POST")
+	api.HandleFunc("/inspections/{inspectionId}/fail", handlers.FailInspection).Methods("POST")
+	api.HandleFunc("/allocations", handlers.Allocate).Methods("POST")
+
+	// Webhookサブスクリプション（配信先と全てのイベントの内容を管理するため管理APIとする）
+	api.HandleFunc("/admin/webhooks", handlers.CreateWebhook).Methods("POST")
+	api.HandleFunc("/admin/webhooks", handlers.ListWebhooks).Methods("GET")
+	api.HandleFunc("/admin/webhooks/{webhookId}", handlers.GetWebhook).Methods("GET")
+	api.HandleFunc("/admin/webhooks/{webhookId}", handlers.UpdateWebhook).Methods("PUT")
+	api.HandleFunc("/admin/webhooks/{webhookId}", handlers.DeleteWebhook).Methods("DELETE")
+	api.HandleFunc("/admin/webhooks/{webhookId}/deliveries", handlers.ListWebhookDeliveries).Methods("GET")
+
+	// イベントのデッドレター管理
+	api.HandleFunc("/admin/events/dead-letters", handlers.ListDeadLetters).Methods("GET")
+	api.HandleFunc("/admin/events/dead-letters/{eventId}/replay", handlers.ReplayDeadLetter).Methods("POST")
+	api.HandleFunc("/admin/events/dead-letters/{eventId}", handlers.DeleteDeadLetter).Methods("DELETE")
+	api.HandleFunc("/admin/events/replay", handlers.ReplayEvents).Methods("POST")
+
+	// 在庫整合性チェック
+	api.HandleFunc("/admin/stock/consistency", handlers.CheckStockConsistency).Methods("POST")
+
+	// 定期ジョブ
+	api.HandleFunc("/admin/jobs", handlers.ListJobs).Methods("GET")
+	api.HandleFunc("/admin/jobs/{name}/run", handlers.RunJob).Methods("POST")
+
+	// APIキー管理
+	api.HandleFunc("/admin/api-keys", handlers.CreateAPIKey).Methods("POST")
+	api.HandleFunc("/admin/api-keys", handlers.ListAPIKeys).Methods("GET")
+	api.HandleFunc("/admin/api-keys/{keyId}", handlers.GetAPIKey).Methods("GET")
+	api.HandleFunc("/admin/api-keys/{keyId}/rotate", handlers.RotateAPIKey).Methods("POST")
+	api.HandleFunc("/admin/api-keys/{keyId}", handlers.RevokeAPIKey).Methods("DELETE")
+
+	// プロファイリング（pprof）・ランタイム統計
+	handlers.mountDebug(api)
+
+	// 外部システムからの在庫同期
+	api.HandleFunc("/sync/stock", handlers.SyncStock).Methods("POST")
+
+	// ライブ配信
+	api.HandleFunc("/ws/stock", handlers.StreamStock).Methods("GET")
+
+	// GraphQL
+	if handlers.graphql != nil {
+		api.Handle("/graphql", handlers.graphql).Methods("GET", "POST")
+		api.HandleFunc("/graphql/schema", handlers.graphql.ServeSDL).Methods("GET")
+	}
 }
 
 // loggingMiddleware logs HTTP requests
@@ -209,8 +794,296 @@ func loggingMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
 				zap.String("method", r.Method),
 				zap.String("url", r.URL.Path),
 				zap.String("remote_addr", r.RemoteAddr),
+				zap.String("request_id", responseRequestID(w)),
 				zap.Duration("duration", time.Since(start)),
 			)
 		})
 	}
 }
+
+// anonymousUserID is recorded as the operator when authentication is disabled
+// 認証を無効にしている場合に操作者として記録するユーザーID
+const anonymousUserID = "api_user"
+
+// publicPaths are served without authentication
+// 認証せずに提供するパス
+var publicPaths = map[string]bool{
+	"/health":       true,
+	"/healthz":      true,
+	"/readyz":       true,
+	"/metrics":      true,
+	"/openapi.json": true,
+	"/docs":         true,
+}
+
+// readOnlyPosts are POST endpoints that only read (the request body carries the query)
+// 照会のみを行うPOSTのパス（本文で照会条件を指定する）
+var readOnlyPosts = map[string]bool{
+	"/inventory/lookup": true,
+	"/labels":           true,
+}
+
+// isRead reports whether a request only reads
+// リクエストが照会のみかを返す
+func isRead(r *http.Request) bool {
+	return r.Method == http.MethodGet || r.Method == http.MethodHead ||
+		(r.Method == http.MethodPost && readOnlyPosts[apiRelativePath(r.URL.Path)])
+}
+
+// requiredScope returns the API key scope required for a request
+// リクエストに必要なAPIキーのスコープを返す
+//
+// 管理API（/api/{version}/admin/）は inventory:admin、照会（GET・readOnlyPosts）は inventory:read、
+// それ以外の更新系の操作は inventory:write を必要とします。
+func requiredScope(r *http.Request) string {
+	switch {
+	case strings.HasPrefix(apiRelativePath(r.URL.Path), "/admin/"):
+		return auth.ScopeAdmin
+	case isRead(r):
+		return auth.ScopeRead
+	default:
+		return auth.ScopeWrite
+	}
+}
+
+// authMiddleware authenticates the caller and carries it in the request context
+// 呼び出し元を認証し、リクエストのコンテキストに設定するミドルウェア
+//
+// 認証に失敗した場合は401、APIキー・JWTのスコープが不足している場合は403を返します。
+// 認証を無効にしている場合は全てのリクエストを anonymousUserID の操作として扱います。
+func (h *Handlers) authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.authenticator == nil {
+			next.ServeHTTP(w, r.WithContext(auth.WithUser(r.Context(), &auth.User{ID: anonymousUserID})))
+			return
+		}
+		if publicPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		user, err := h.authenticator.Authenticate(r)
+		if err != nil {
+			if errors.Is(err, auth.ErrNoCredentials) {
+				w.Header().Set("WWW-Authenticate", `Bearer`)
+				h.sendError(w, http.StatusUnauthorized, err.Error())
+				return
+			}
+			h.logger.Warn("認証に失敗しました",
+				zap.String("url", r.URL.Path),
+				zap.String("remote_addr", r.RemoteAddr),
+				zap.Error(err),
+			)
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			h.sendError(w, http.StatusUnauthorized, auth.ErrInvalidToken.Error())
+			return
+		}
+		if scope := requiredScope(r); !h.allowsScope(user, scope) {
+			h.logger.Warn("スコープが不足しています",
+				zap.String("method", user.Method),
+				zap.String("user_id", user.ID),
+				zap.String("url", r.URL.Path),
+				zap.String("required_scope", scope),
+			)
+			h.sendError(w, http.StatusForbidden, fmt.Sprintf("この操作には %s スコープが必要です", scope))
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(auth.WithUser(r.Context(), user)))
+	})
+}
+
+// allowsScope reports whether an authenticated user may perform an operation requiring scope
+// 認証済みのユーザーがスコープを要求する操作を実行できるかを返す
+//
+// inventory:* のスコープを含まないJWTは、AUTH_JWT_ALLOW_UNSCOPED を有効にした場合のみ全ての操作を許可します。
+func (h *Handlers) allowsScope(user *auth.User, scope string) bool {
+	return user.Permits(scope, h.unscopedJWT)
+}
+
+// rateLimiter limits request rates per client
+// クライアントごとのリクエスト頻度の制限
+type rateLimiter struct {
+	requests          *ratelimit.Limiter // 全てのリクエストの制限
+	writes            *ratelimit.Limiter // 更新系のリクエストの制限（未設定の場合は requests で制限）
+	addresses         *ratelimit.Limiter // 認証の前のIPアドレスごとの制限（未設定の場合は制限しない）
+	trustForwardedFor bool
+	trustedProxies    int // X-Forwarded-For にアドレスを追加する信頼できるリバースプロキシの段数
+}
+
+// newRateLimiter creates a rate limiter from the settings
+// 設定から頻度制限を作成
+func newRateLimiter(cfg config.RateLimitConfig) *rateLimiter {
+	limiter := &rateLimiter{
+		requests: ratelimit.New(ratelimit.Config{
+			Rate:    cfg.RequestsPerSecond,
+			Burst:   cfg.Burst,
+			IdleTTL: cfg.IdleTTL,
+		}),
+		trustForwardedFor: cfg.TrustForwardedFor,
+		trustedProxies:    cfg.TrustedProxies,
+	}
+	if cfg.IPRequestsPerSecond > 0 {
+		limiter.addresses = ratelimit.New(ratelimit.Config{
+			Rate:    cfg.IPRequestsPerSecond,
+			Burst:   cfg.IPBurst,
+			IdleTTL: cfg.IdleTTL,
+		})
+	}
+	if cfg.WriteRequestsPerSecond > 0 {
+		limiter.writes = ratelimit.New(ratelimit.Config{
+			Rate:    cfg.WriteRequestsPerSecond,
+			Burst:   cfg.WriteBurst,
+			IdleTTL: cfg.IdleTTL,
+		})
+	}
+	return limiter
+}
+
+// clientKey identifies the client of a request
+// リクエストのクライアントを識別するキーを返す
+//
+// 認証済みの場合はユーザーID（APIキーの場合は "apikey:<キーID>"）、それ以外はIPアドレスで識別します。
+func (l *rateLimiter) clientKey(r *http.Request) string {
+	if user, ok := auth.UserFromContext(r.Context()); ok && user.Method != "" {
+		return "user:" + user.ID
+	}
+	return "ip:" + l.clientIP(r)
+}
+
+// clientIP returns the IP address of the client of a request
+// リクエストのクライアントのIPアドレスを返す
+//
+// X-Forwarded-For を信頼する場合は、信頼できるリバースプロキシが追加した末尾から trustedProxies 番目の
+// アドレスを使用します。先頭側のアドレスはクライアントが任意に指定できるため使用しません。
+// アドレスの数が段数より少ない場合は接続元のアドレスを使用します。
+func (l *rateLimiter) clientIP(r *http.Request) string {
+	if l.trustForwardedFor && l.trustedProxies > 0 {
+		var addresses []string
+		for _, header := range r.Header.Values("X-Forwarded-For") {
+			for _, address := range strings.Split(header, ",") {
+				addresses = append(addresses, strings.TrimSpace(address))
+			}
+		}
+		if len(addresses) >= l.trustedProxies {
+			if client := addresses[len(addresses)-l.trustedProxies]; client != "" {
+				return client
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return host
+}
+
+// ipRateLimitMiddleware rejects requests exceeding the rate limit of their IP address with 429 before authentication
+// 認証の前にIPアドレスごとの頻度制限を超えたリクエストを429で拒否するミドルウェア
+//
+// 認証に失敗するリクエストや認証情報のないリクエストも制限し、認証情報の総当たりを防ぎます。
+func (h *Handlers) ipRateLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.rateLimit == nil || h.rateLimit.addresses == nil || publicPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if h.allowRequest(w, r, h.rateLimit.addresses, "ip:"+h.rateLimit.clientIP(r)) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// rateLimitMiddleware rejects requests exceeding the client's rate limit with 429
+// クライアントの頻度制限を超えたリクエストを429で拒否するミドルウェア
+func (h *Handlers) rateLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.rateLimit == nil || publicPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		limiter := h.rateLimit.requests
+		if h.rateLimit.writes != nil && !isRead(r) {
+			limiter = h.rateLimit.writes
+		}
+		if h.allowRequest(w, r, limiter, h.rateLimit.clientKey(r)) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// allowRequest takes a token of the client from a limiter, sending 429 and returning false when the limit is exceeded
+// 制限からクライアントのトークンを取得（制限を超えた場合は429を送信してfalseを返す）
+func (h *Handlers) allowRequest(w http.ResponseWriter, r *http.Request, limiter *ratelimit.Limiter, client string) bool {
+	result := limiter.Allow(client)
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	if result.Allowed {
+		return true
+	}
+	// 制限が続く間のログは最初の1回のみ出力する
+	if result.FirstRejection {
+		h.logger.Warn("リクエスト頻度の制限を超えました",
+			zap.String("client", client),
+			zap.String("method", r.Method),
+			zap.String("url", r.URL.Path),
+		)
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+	h.sendError(w, http.StatusTooManyRequests, "リクエストが多すぎます。しばらく待ってから再試行してください")
+	return false
+}
+
+// alertNotifier creates the alert notifier from the notification settings (nil when no channel is configured)
+// 通知設定からアラート通知者を作成（通知先が設定されていない場合はnil）
+func alertNotifier(cfg config.NotificationsConfig, logger *zap.Logger) (*notify.Notifier, error) {
+	if len(cfg.Channels) == 0 {
+		return nil, nil
+	}
+	targets := make([]notify.Target, 0, len(cfg.Channels))
+	for _, channel := range cfg.Channels {
+		target, err := notify.NewTarget(notify.TargetConfig{
+			Name:       channel.Name,
+			Type:       channel.Type,
+			AlertTypes: channel.AlertTypes,
+			Severities: channel.Severities,
+			Subject:    channel.Subject,
+			Body:       channel.Body,
+			URL:        channel.URL,
+			Headers:    channel.Headers,
+			Email: notify.EmailConfig{
+				Host:     channel.SMTP.Host,
+				Port:     channel.SMTP.Port,
+				Username: channel.SMTP.Username,
+				Password: channel.SMTP.Password,
+				From:     channel.SMTP.From,
+				To:       channel.SMTP.To,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+	return notify.New(targets, notify.Config{
+		Workers:     cfg.Workers,
+		QueueSize:   cfg.QueueSize,
+		MaxAttempts: cfg.MaxAttempts,
+		BaseDelay:   cfg.BaseDelay,
+		MaxDelay:    cfg.MaxDelay,
+		Timeout:     cfg.Timeout,
+	}, logger)
+}
+
+// eventRoutes converts route settings to event routing rules
+// ルーティング設定をイベントのルーティングルールに変換
+func eventRoutes(routes []config.EventRouteConfig) []events.Route {
+	converted := make([]events.Route, 0, len(routes))
+	for _, route := range routes {
+		converted = append(converted, events.Route{
+			LocationIDs: route.LocationIDs,
+			Destination: route.Destination,
+		})
+	}
+	return converted
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// handlerMessages are the English translations of the messages sent by the handlers
+// ハンドラーが送信するメッセージの英語の翻訳
+var handlerMessages = map[string]string{
+	"無効なリクエスト形式です":                                         "invalid request format",
+	"商品管理機能がサポートされていません":                                   "item management is not supported",
+	"ロケーション管理機能がサポートされていません":                               "location management is not supported",
+	"ロット管理機能がサポートされていません":                                  "lot management is not supported",
+	"予約管理機能がサポートされていません":                                   "reservation management is not supported",
+	"バックオーダー機能がサポートされていません":                                "backorders are not supported",
+	"発注点管理機能がサポートされていません":                                  "reorder point management is not supported",
+	"アラートの確認機能がサポートされていません":                                "alert acknowledgement is not supported",
+	"アラートルール管理機能がサポートされていません":                              "alert rule management is not supported",
+	"在庫評価機能がサポートされていません":                                   "inventory valuation is not supported",
+	"在庫分析機能がサポートされていません":                                   "inventory analytics is not supported",
+	"Webhook機能がサポートされていません":                                "webhooks are not supported",
+	"APIキー機能がサポートされていません":                                  "API keys are not supported",
+	"イベント再試行機能がサポートされていません":                                "event retry is not supported",
+	"イベント再生機能がサポートされていません":                                 "event replay is not supported",
+	"ライブ配信機能がサポートされていません":                                  "live streaming is not supported",
+	"CSV取り込みがサポートされていません":                                  "CSV import is not supported",
+	"在庫整合性チェック機能がサポートされていません":                              "stock consistency checks are not supported",
+	"在庫同期機能がサポートされていません":                                   "stock sync is not supported",
+	"在庫スナップショット機能がサポートされていません":                             "stock snapshots are not supported",
+	"在庫調整の承認機能がサポートされていません":                                "stock adjustment approval is not supported",
+	"トランザクションの取消機能がサポートされていません":                            "transaction reversal is not supported",
+	"引当機能がサポートされていません":                                     "allocation is not supported",
+	"ロット在庫機能がサポートされていません":                                  "lot stock is not supported",
+	"委託在庫機能がサポートされていません":                                   "consignment stock is not supported",
+	"販売チャネル機能がサポートされていません":                                 "sales channels are not supported",
+	"単位機能がサポートされていません":                                     "units of measure are not supported",
+	"数量は整数で指定してください":                                       "quantity must be an integer",
+	"キット機能がサポートされていません":                                    "kits are not supported",
+	"仕入先管理機能がサポートされていません":                                  "supplier management is not supported",
+	"発注機能がサポートされていません":                                     "purchase orders are not supported",
+	"受注機能がサポートされていません":                                     "sales orders are not supported",
+	"返品機能がサポートされていません":                                     "returns are not supported",
+	"入荷検品機能がサポートされていません":                                   "receiving inspections are not supported",
+	"棚卸機能がサポートされていません":                                     "stocktakes are not supported",
+	"定期ジョブ機能がサポートされていません":                                  "scheduled jobs are not supported",
+	"ジョブが見つかりません":                                          "job not found",
+	"ジョブは実行中です":                                            "job is already running",
+	"スケジューラーは停止しています":                                      "the scheduler is stopped",
+	"在庫の集計がサポートされていません":                                    "inventory summaries are not supported",
+	"在庫の一括照会がサポートされていません":                                  "bulk stock lookup is not supported",
+	"商品一覧の絞り込みがサポートされていません":                                "item list filtering is not supported",
+	"ストリーミングがサポートされていません":                                  "streaming is not supported",
+	"在庫整合性チェックに失敗しました":                                     "stock consistency check failed",
+	"OpenAPIドキュメントを生成できませんでした":                             "failed to generate the OpenAPI document",
+	"Webhookが見つかりません":                                      "webhook not found",
+	"デッドレターのイベントが見つかりません":                                  "dead-lettered event not found",
+	"ロット数量が不足しています":                                        "insufficient lot quantity",
+	"検索クエリが指定されていません":                                      "search query is required",
+	"location_idパラメータが必要です":                                "location_id parameter is required",
+	"keyパラメータを指定してください":                                    "key parameter is required",
+	"atomicパラメータが無効です":                                     "invalid atomic parameter",
+	"asyncパラメータが無効です":                                      "invalid async parameter",
+	"非同期バッチが設定されていません":                                     "asynchronous batches are not configured",
+	"dry_runパラメータが無効です":                                    "invalid dry_run parameter",
+	"backorderパラメータが無効です":                                  "invalid backorder parameter",
+	"アラート一覧機能がサポートされていません":                                 "alert listing is not supported",
+	"statusパラメータは active または resolved を指定してください":           "status must be active or resolved",
+	"無効なfrom日時形式です（形式：2006-01-02 または RFC3339）":             "invalid from time (format: 2006-01-02 or RFC 3339)",
+	"無効なto日時形式です（形式：2006-01-02 または RFC3339）":               "invalid to time (format: 2006-01-02 or RFC 3339)",
+	"acknowledgedパラメータが無効です":                               "invalid acknowledged parameter",
+	"include_emptyパラメータが無効です":                              "invalid include_empty parameter",
+	"enabledパラメータが無効です":                                    "invalid enabled parameter",
+	"ドライランがサポートされていません":                                    "dry runs are not supported",
+	"無効な猶予期間です（例: 24h）":                                    "invalid grace period (e.g. 24h)",
+	"from及びtoパラメータが必要です（形式：2006-01-02）":                    "from and to parameters are required (format: 2006-01-02)",
+	"無効なfrom日付形式です（形式：2006-01-02）":                         "invalid from date (format: 2006-01-02)",
+	"無効なto日付形式です（形式：2006-01-02）":                           "invalid to date (format: 2006-01-02)",
+	"after_idとafter_created_at（RFC3339形式）の両方を指定してください":     "both after_id and after_created_at (RFC3339) are required",
+	"絞り込み・並び替えは offset と併用できません（cursor を使用してください）":         "filters and sorting cannot be combined with offset (use cursor)",
+	"CSVファイルを multipart/form-data の file フィールドで指定してください":   "send the CSV file in the file field of multipart/form-data",
+	"他のユーザーによって更新されています。最新の状態を取得してから再度更新してください":            "the resource was updated by another user; fetch the latest state and retry",
+	"リクエストが多すぎます。しばらく待ってから再試行してください":                       "too many requests; wait a moment and retry",
+	"ラベルのテンプレートが見つかりません":                                   "label template not found",
+	"印刷するラベルが指定されていません":                                    "no labels to print",
+	"ラベルの対象は500件までです":                                      "up to 500 label targets are allowed",
+	"ロットIDが指定されていません":                                      "lot ID is required",
+	"ラベルの対象の種類が正しくありません（item・lot・location のいずれかを指定してください）": "invalid label target type (use item, lot or location)",
+	"ラベルの枚数は1〜100で指定してください":                                "label copies must be between 1 and 100",
+	"ラベルの枚数の合計は2000枚までです":                                  "up to 2000 labels can be printed at once",
+	"サポートされていないバーコードの種類です（code128 または qr を指定してください）":       "unsupported barcode symbology (use code128 or qr)",
+	"ラベルの幅と高さは0より大きく1000mm以下である必要があります":                    "label width and height must be greater than 0 and at most 1000 mm",
+	"ラベルの余白・間隔・列数・行数が正しくありません":                             "invalid label margins, gaps, columns or rows",
+	"ラベルが用紙に収まりません":                                        "the labels do not fit the page",
+	"文字サイズは0以上72pt以下である必要があります":                            "font size must be between 0 and 72 pt",
+	"ラベルの文字列は10行までです":                                      "up to 10 label lines are allowed",
+	"ラベルの文字列のテンプレートが正しくありません":                              "invalid label line template",
+	"文字列とバーコードがラベルに収まりません":                                 "the text and barcode do not fit the label",
+	"バーコードがラベルの幅に収まりません":                                   "the barcode does not fit the label width",
+	"ラベルのコードが空です":                                          "label code is empty",
+	"ラベルのページ数が上限（1000ページ）を超えています":                          "labels exceed the limit of 1000 pages",
+	"Code128で表せない文字が含まれています（ASCIIの印字可能文字のみ使用できます）":         "the value has characters Code128 cannot encode (printable ASCII only)",
+	"QRコードに収まらない長さの値です":                                    "the value is too long for a QR code",
+}
+
+func init() {
+	inventory.RegisterMessages(inventory.LanguageEnglish, handlerMessages)
+}
+
+// languageMiddleware selects the language of error messages from Accept-Language
+// Accept-Language からエラーメッセージの言語を選択するミドルウェア
+//
+// 選択した言語は Content-Language ヘッダーで返し、エラーレスポンスの送信時に参照します。
+func languageMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Language", string(inventory.ParseAcceptLanguage(r.Header.Get("Accept-Language"))))
+		w.Header().Add("Vary", "Accept-Language")
+		next.ServeHTTP(w, r)
+	})
+}
+
+// responseLanguage returns the language selected for the response
+// レスポンスに選択された言語を返す
+func responseLanguage(w http.ResponseWriter) inventory.Language {
+	if lang := w.Header().Get("Content-Language"); lang != "" {
+		return inventory.Language(lang)
+	}
+	return inventory.DefaultLanguage
+}
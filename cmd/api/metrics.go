@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// statusRecorder records the status code written by a handler
+// ハンドラーが書き込んだステータスコードを記録
+//
+// ライブ配信（WebSocket）・ストリーミングで使用するため、Hijack・Flush を元のライターに委譲します。
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code
+// ステータスコードを記録
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write records 200 when the handler writes without WriteHeader
+// WriteHeaderを呼ばずに書き込んだ場合は200を記録
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// Flush flushes the underlying writer
+// 元のライターをフラッシュ
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack takes over the connection of the underlying writer
+// 元のライターの接続を引き継ぐ
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("接続の引き継ぎがサポートされていません")
+	}
+	if r.status == 0 {
+		r.status = http.StatusSwitchingProtocols
+	}
+	return hijacker.Hijack()
+}
+
+// Unwrap returns the underlying writer for http.ResponseController
+// http.ResponseController 用に元のライターを返す
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// statusCode returns the recorded status, 200 when nothing was written
+// 記録したステータスを返す（何も書き込まれていない場合は200）
+func (r *statusRecorder) statusCode() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
+
+// metricsMiddleware records the count and latency of requests per route
+// ルートごとのリクエスト数・処理時間を記録するミドルウェア
+//
+// ルートのラベルはパステンプレート（/api/v1/items/{itemId} など）で、IDごとに系列が増えません。
+func (h *Handlers) metricsMiddleware(next http.Handler) http.Handler {
+	if h.metrics == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w}
+
+		next.ServeHTTP(recorder, r)
+
+		route := "unmatched"
+		if current := mux.CurrentRoute(r); current != nil {
+			if template, err := current.GetPathTemplate(); err == nil {
+				route = template
+			}
+		}
+		h.metrics.ObserveRequest(r.Method, route, recorder.statusCode(), time.Since(start))
+	})
+}
@@ -0,0 +1,1647 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/internal/label"
+	"github.com/nemonet1337/zaiGoFramework/internal/mergepatch"
+	"github.com/nemonet1337/zaiGoFramework/internal/openapi"
+	"github.com/nemonet1337/zaiGoFramework/internal/spreadsheet"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/auth"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/consumer"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/events"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/scheduler"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/webhooks"
+)
+
+// swaggerUIVersion is the version of swagger-ui-dist loaded by /docs
+// /docs で読み込む swagger-ui-dist のバージョン
+const swaggerUIVersion = "5.17.14"
+
+// 以下はハンドラーがマップで返すレスポンスの形式をドキュメントに記述するための型です。
+// ハンドラーのレスポンスのキーを変更した場合はこちらも合わせて変更してください。
+
+// ErrorResponse is the response of a failed request
+// 失敗したリクエストのレスポンス
+type ErrorResponse struct {
+	Success   bool      `json:"success"`
+	Error     string    `json:"error"`
+	ErrorCode ErrorCode `json:"error_code"`
+}
+
+// MessageResponse is the response of an operation that returns only a message
+// メッセージのみを返す操作のレスポンス
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+// AlertResponse is the response of acknowledging or resolving an alert
+// アラートの確認・解決のレスポンス
+type AlertResponse struct {
+	Message string               `json:"message"`
+	Alert   inventory.StockAlert `json:"alert"`
+}
+
+// HealthResponse is the response of the health check
+// ヘルスチェックのレスポンス
+type HealthResponse struct {
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+	Service   string    `json:"service"`
+}
+
+// ReadinessResponse is the response of the readiness check
+// レディネスチェックのレスポンス
+type ReadinessResponse struct {
+	Status       string                      `json:"status"` // ready | not_ready
+	Timestamp    time.Time                   `json:"timestamp"`
+	Dependencies map[string]DependencyStatus `json:"dependencies"`
+}
+
+// DependencyStatus is the status of a dependency in the readiness check
+// レディネスチェックでの依存サービスの状態
+type DependencyStatus struct {
+	Status    string `json:"status"` // ok | unavailable
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// TotalStockResponse is the response of the total stock of an item
+// 商品の総在庫数のレスポンス
+type TotalStockResponse struct {
+	TotalQuantity int64 `json:"total_quantity"`
+}
+
+// ImportRequest is the multipart request body of CSV imports
+// CSV取り込みのmultipartリクエスト本文
+type ImportRequest struct {
+	File openapi.File `json:"file"` // CSVファイル（1行目は列名のヘッダー）
+}
+
+// StockLookupResponse is the response of a bulk stock lookup
+// 在庫の一括照会のレスポンス
+type StockLookupResponse struct {
+	Stocks  []inventory.Stock    `json:"stocks"`  // 在庫（指定した順序）
+	Missing []inventory.StockKey `json:"missing"` // 在庫記録が存在しない組
+}
+
+// ItemResponse is the response of creating or updating an item
+// 商品の作成・更新のレスポンス
+type ItemResponse struct {
+	Message string         `json:"message"`
+	Item    inventory.Item `json:"item"`
+}
+
+// ItemListResponse is the response of listing items
+// 商品一覧のレスポンス
+type ItemListResponse struct {
+	Items      []inventory.Item `json:"items"`
+	Offset     int              `json:"offset,omitempty"` // offset を指定した場合のみ
+	Limit      int              `json:"limit"`
+	Count      int              `json:"count"`
+	NextCursor string           `json:"next_cursor,omitempty"` // 次ページのカーソル（最終ページ・offset指定時は省略）
+}
+
+// ItemSearchResponse is the response of searching items
+// 商品検索のレスポンス
+type ItemSearchResponse struct {
+	Items []inventory.Item `json:"items"`
+	Query string           `json:"query"`
+	Count int              `json:"count"`
+}
+
+// LocationResponse is the response of creating or updating a location
+// ロケーションの作成・更新のレスポンス
+type LocationResponse struct {
+	Message  string             `json:"message"`
+	Location inventory.Location `json:"location"`
+}
+
+// LocationListResponse is the response of listing locations
+// ロケーション一覧のレスポンス
+type LocationListResponse struct {
+	Locations  []inventory.Location `json:"locations"`
+	Offset     int                  `json:"offset,omitempty"` // offset を指定した場合のみ
+	Limit      int                  `json:"limit"`
+	Count      int                  `json:"count"`
+	NextCursor string               `json:"next_cursor,omitempty"` // 次ページのカーソル（最終ページ・offset指定時は省略）
+}
+
+// LotResponse is the response of creating, updating or adjusting a lot
+// ロットの作成・更新・数量調整のレスポンス
+type LotResponse struct {
+	Message string        `json:"message"`
+	Lot     inventory.Lot `json:"lot"`
+}
+
+// LotListResponse is the response of listing lots
+// ロット一覧のレスポンス（item_id・within_days は該当する照会の場合のみ）
+type LotListResponse struct {
+	Lots       []inventory.Lot `json:"lots"`
+	ItemID     string          `json:"item_id,omitempty"`
+	WithinDays int             `json:"within_days,omitempty"`
+	Count      int             `json:"count"`
+}
+
+// LotStockListResponse is the response of listing lot stocks
+// ロット在庫一覧のレスポンス
+type LotStockListResponse struct {
+	LotStocks []inventory.LotStock `json:"lot_stocks"`
+	Count     int                  `json:"count"`
+	Offset    int                  `json:"offset"`
+	Limit     int                  `json:"limit"`
+}
+
+// UnitListResponse is the response of listing units of measure
+// 単位一覧のレスポンス
+type UnitListResponse struct {
+	Units []inventory.UnitOfMeasure `json:"units"`
+	Count int                       `json:"count"`
+}
+
+// UnitConversionListResponse is the response of listing the unit conversions of an item
+// 商品の単位の換算一覧のレスポンス
+type UnitConversionListResponse struct {
+	Conversions []inventory.UnitConversion `json:"conversions"`
+	Count       int                        `json:"count"`
+}
+
+// BOMListResponse is the response of listing bills of materials
+// 部品表一覧のレスポンス
+type BOMListResponse struct {
+	BOMs   []inventory.BillOfMaterials `json:"boms"`
+	Count  int                         `json:"count"`
+	Offset int                         `json:"offset"`
+	Limit  int                         `json:"limit"`
+}
+
+// AssemblyListResponse is the response of listing kit assemblies
+// キットの組立・分解一覧のレスポンス
+type AssemblyListResponse struct {
+	Assemblies []inventory.Assembly `json:"assemblies"`
+	Count      int                  `json:"count"`
+	Offset     int                  `json:"offset"`
+	Limit      int                  `json:"limit"`
+}
+
+// SupplierListResponse is the response of listing suppliers
+// 仕入先一覧のレスポンス
+type SupplierListResponse struct {
+	Suppliers []inventory.Supplier `json:"suppliers"`
+	Count     int                  `json:"count"`
+	Offset    int                  `json:"offset"`
+	Limit     int                  `json:"limit"`
+}
+
+// SupplierItemListResponse is the response of listing purchasing terms
+// 仕入条件一覧のレスポンス
+type SupplierItemListResponse struct {
+	SupplierItems []inventory.SupplierItem `json:"supplier_items"`
+	Count         int                      `json:"count"`
+	Offset        int                      `json:"offset"`
+	Limit         int                      `json:"limit"`
+}
+
+// PurchaseOrderResponse is the response of creating or changing the status of a purchase order
+// 発注の作成・状態変更のレスポンス
+type PurchaseOrderResponse struct {
+	Message       string                  `json:"message"`
+	PurchaseOrder inventory.PurchaseOrder `json:"purchase_order"`
+}
+
+// PurchaseOrderListResponse is the response of listing purchase orders
+// 発注一覧のレスポンス
+type PurchaseOrderListResponse struct {
+	PurchaseOrders []inventory.PurchaseOrder `json:"purchase_orders"`
+	Count          int                       `json:"count"`
+	Offset         int                       `json:"offset"`
+	Limit          int                       `json:"limit"`
+}
+
+// GoodsReceiptResponse is the response of recording stock received against a purchase order
+// 発注に対する入荷の記録のレスポンス
+type GoodsReceiptResponse struct {
+	Message      string                 `json:"message"`
+	GoodsReceipt inventory.GoodsReceipt `json:"goods_receipt"`
+}
+
+// GoodsReceiptListResponse is the response of listing the goods receipts of a purchase order
+// 発注に対する入荷一覧のレスポンス
+type GoodsReceiptListResponse struct {
+	GoodsReceipts []inventory.GoodsReceipt `json:"goods_receipts"`
+	Count         int                      `json:"count"`
+}
+
+// SalesOrderResponse is the response of creating, allocating, shipping or cancelling a sales order
+// 受注の作成・引当・出荷確定・取消のレスポンス
+type SalesOrderResponse struct {
+	Message    string               `json:"message"`
+	SalesOrder inventory.SalesOrder `json:"sales_order"`
+}
+
+// SalesOrderListResponse is the response of listing sales orders
+// 受注一覧のレスポンス
+type SalesOrderListResponse struct {
+	SalesOrders []inventory.SalesOrder `json:"sales_orders"`
+	Count       int                    `json:"count"`
+	Offset      int                    `json:"offset"`
+	Limit       int                    `json:"limit"`
+}
+
+// ReturnResponse is the response of creating, receiving or cancelling a return
+// 返品の作成・到着の記録・取消のレスポンス
+type ReturnResponse struct {
+	Message string                        `json:"message"`
+	Return  inventory.ReturnAuthorization `json:"return"`
+}
+
+// ReturnListResponse is the response of listing returns
+// 返品一覧のレスポンス
+type ReturnListResponse struct {
+	Returns []inventory.ReturnAuthorization `json:"returns"`
+	Count   int                             `json:"count"`
+	Offset  int                             `json:"offset"`
+	Limit   int                             `json:"limit"`
+}
+
+// ReturnDispositionResponse is the response of dispositioning returned units
+// 返品の処分のレスポンス
+type ReturnDispositionResponse struct {
+	Message     string                      `json:"message"`
+	Disposition inventory.ReturnDisposition `json:"disposition"`
+}
+
+// ReturnDispositionListResponse is the response of listing the dispositions of a return
+// 返品の処分一覧のレスポンス
+type ReturnDispositionListResponse struct {
+	Dispositions []inventory.ReturnDisposition `json:"dispositions"`
+	Count        int                           `json:"count"`
+}
+
+// ConsignmentStockListResponse is the response of listing consignment stocks
+// 委託在庫一覧のレスポンス
+type ConsignmentStockListResponse struct {
+	ConsignmentStocks []inventory.ConsignmentStock `json:"consignment_stocks"`
+	Count             int                          `json:"count"`
+	Offset            int                          `json:"offset"`
+	Limit             int                          `json:"limit"`
+}
+
+// LabelTemplateListResponse is the response of listing the built-in label templates
+// 組み込みのラベルのテンプレート一覧のレスポンス
+type LabelTemplateListResponse struct {
+	Templates []label.Template `json:"templates"`
+	Count     int              `json:"count"`
+}
+
+// ReservationResponse is the response of creating or releasing a reservation
+// 予約の作成・解除のレスポンス
+type ReservationResponse struct {
+	Message     string                `json:"message"`
+	Reservation inventory.Reservation `json:"reservation"`
+}
+
+// FulfillReservationResponse is the response of shipping the stock of a reservation
+// 予約の出庫のレスポンス
+type FulfillReservationResponse struct {
+	Message       string                `json:"message"`
+	ReservationID string                `json:"reservation_id"`
+	Transaction   inventory.Transaction `json:"transaction"`
+}
+
+// ReorderPointListResponse is the response of listing reorder points
+// 発注点一覧のレスポンス
+type ReorderPointListResponse struct {
+	ReorderPoints []inventory.ReorderPoint `json:"reorder_points"`
+	Count         int                      `json:"count"`
+	Offset        int                      `json:"offset"`
+	Limit         int                      `json:"limit"`
+}
+
+// AlertRuleListResponse is the response of listing alert rules
+// アラートルール一覧のレスポンス
+type AlertRuleListResponse struct {
+	AlertRules []inventory.AlertRule `json:"alert_rules"`
+	Count      int                   `json:"count"`
+	Offset     int                   `json:"offset"`
+	Limit      int                   `json:"limit"`
+}
+
+// AlertListResponse is the response of listing alerts across all locations
+// 全ロケーションのアラート一覧のレスポンス
+type AlertListResponse struct {
+	Alerts []inventory.StockAlert `json:"alerts"`
+	Count  int                    `json:"count"`
+	Offset int                    `json:"offset"`
+	Limit  int                    `json:"limit"`
+}
+
+// EvaluateAlertRulesResponse is the response of evaluating alert rules
+// アラートルール評価のレスポンス
+type EvaluateAlertRulesResponse struct {
+	AlertsCreated int `json:"alerts_created"`
+}
+
+// BackorderResponse is the response of cancelling or creating a backorder
+// バックオーダーの取消・作成（在庫不足の削除・予約の202）のレスポンス
+type BackorderResponse struct {
+	Message   string              `json:"message"`
+	Backorder inventory.Backorder `json:"backorder"`
+}
+
+// BackorderListResponse is the response of listing backorders
+// バックオーダー一覧のレスポンス
+type BackorderListResponse struct {
+	Backorders []inventory.Backorder `json:"backorders"`
+	Count      int                   `json:"count"`
+	Offset     int                   `json:"offset"`
+	Limit      int                   `json:"limit"`
+}
+
+// AllocatedBackordersResponse is the response of allocating backorders
+// バックオーダーの引当のレスポンス
+type AllocatedBackordersResponse struct {
+	Backorders []inventory.Backorder `json:"backorders"`
+	Count      int                   `json:"count"`
+}
+
+// ReservationListResponse is the response of listing reservations
+// 予約一覧のレスポンス
+type ReservationListResponse struct {
+	Reservations []inventory.Reservation `json:"reservations"`
+	Count        int                     `json:"count"`
+	Offset       int                     `json:"offset"`
+	Limit        int                     `json:"limit"`
+}
+
+// ReservationReferenceResponse is the response of querying or releasing the reservations of a reference
+// 参照番号別の予約の取得・解除のレスポンス
+type ReservationReferenceResponse struct {
+	Reservations []inventory.Reservation `json:"reservations"`
+	Reference    string                  `json:"reference"`
+	Count        int                     `json:"count"`
+}
+
+// NotifyExpiringLotsResponse is the response of notifying expiring lots
+// 期限切れ間近のロット通知のレスポンス
+type NotifyExpiringLotsResponse struct {
+	WithinDays int `json:"within_days"`
+	Notified   int `json:"notified"`
+}
+
+// HistoryResponse is the response of searching the transaction history
+// トランザクション履歴の検索のレスポンス（検索条件のキーは照会によって異なる）
+type HistoryResponse struct {
+	History    []inventory.Transaction `json:"history"`
+	LocationID string                  `json:"location_id,omitempty"`
+	Reference  string                  `json:"reference,omitempty"`
+	Key        string                  `json:"key,omitempty"`
+	Value      string                  `json:"value,omitempty"`
+	ItemID     string                  `json:"item_id,omitempty"`
+	From       string                  `json:"from,omitempty"`
+	To         string                  `json:"to,omitempty"`
+	Limit      int                     `json:"limit,omitempty"`
+	Count      int                     `json:"count"`
+}
+
+// ValueResponse is the response of the value of an item at a location
+// ロケーションの商品の在庫評価額のレスポンス
+type ValueResponse struct {
+	Value      float64                   `json:"value"`
+	ItemID     string                    `json:"item_id"`
+	LocationID string                    `json:"location_id"`
+	Method     inventory.ValuationMethod `json:"method"`
+}
+
+// TotalValueResponse is the response of the total value at a location
+// ロケーションの在庫評価額合計のレスポンス
+type TotalValueResponse struct {
+	TotalValue float64                   `json:"total_value"`
+	LocationID string                    `json:"location_id"`
+	Method     inventory.ValuationMethod `json:"method"`
+}
+
+// AverageCostResponse is the response of the average cost of an item
+// 商品の平均原価のレスポンス
+type AverageCostResponse struct {
+	AverageCost float64 `json:"average_cost"`
+	ItemID      string  `json:"item_id"`
+}
+
+// ABCClassificationResponse is the response of the ABC classification
+// ABC分析のレスポンス（classification は商品IDとランクの対応）
+type ABCClassificationResponse struct {
+	Classification map[string]string `json:"classification"`
+	LocationID     string            `json:"location_id"`
+	Count          int               `json:"count"`
+}
+
+// TurnoverRateResponse is the response of the turnover rate of an item
+// 商品の在庫回転率のレスポンス
+type TurnoverRateResponse struct {
+	TurnoverRate float64 `json:"turnover_rate"`
+	ItemID       string  `json:"item_id"`
+	PeriodDays   int     `json:"period_days"`
+}
+
+// SlowMovingItemsResponse is the response of the slow moving items
+// 滞留在庫のレスポンス
+type SlowMovingItemsResponse struct {
+	SlowMovingItems []string `json:"slow_moving_items"`
+	LocationID      string   `json:"location_id"`
+	ThresholdDays   int      `json:"threshold_days"`
+	Count           int      `json:"count"`
+}
+
+// WebhookResponse is the response of creating or updating a webhook
+// Webhookの作成・更新のレスポンス（secret は作成時のみ）
+type WebhookResponse struct {
+	Message string                `json:"message"`
+	Webhook webhooks.Subscription `json:"webhook"`
+}
+
+// WebhookListResponse is the response of listing webhooks
+// Webhook一覧のレスポンス
+type WebhookListResponse struct {
+	Webhooks []webhooks.Subscription `json:"webhooks"`
+	Count    int                     `json:"count"`
+}
+
+// WebhookDeliveryListResponse is the response of listing webhook deliveries
+// Webhook配信ログ一覧のレスポンス
+type WebhookDeliveryListResponse struct {
+	WebhookID  string              `json:"webhook_id"`
+	Deliveries []webhooks.Delivery `json:"deliveries"`
+	Count      int                 `json:"count"`
+}
+
+// DeadLetterListResponse is the response of listing dead-lettered events
+// デッドレターのイベント一覧のレスポンス
+type DeadLetterListResponse struct {
+	DeadLetters []events.FailedEvent `json:"dead_letters"`
+	Count       int                  `json:"count"`
+}
+
+// StockSnapshotListResponse is the response of listing stock snapshots
+// 在庫スナップショット一覧のレスポンス
+type StockSnapshotListResponse struct {
+	Snapshots []inventory.StockSnapshot `json:"snapshots"`
+	Count     int                       `json:"count"`
+	Offset    int                       `json:"offset"`
+	Limit     int                       `json:"limit"`
+}
+
+// StockSnapshotLinesResponse is the response of getting the stock records of a snapshot
+// 在庫スナップショットの在庫記録のレスポンス
+type StockSnapshotLinesResponse struct {
+	SnapshotID string                        `json:"snapshot_id"`
+	Stocks     []inventory.StockSnapshotLine `json:"stocks"`
+	Count      int                           `json:"count"`
+}
+
+// StocktakeResponse is the response of creating or updating a stocktake
+// 棚卸の作成・更新のレスポンス
+type StocktakeResponse struct {
+	Message   string              `json:"message"`
+	Stocktake inventory.Stocktake `json:"stocktake"`
+}
+
+// StocktakeListResponse is the response of listing stocktakes
+// 棚卸一覧のレスポンス
+type StocktakeListResponse struct {
+	Stocktakes []inventory.Stocktake `json:"stocktakes"`
+	Count      int                   `json:"count"`
+	Offset     int                   `json:"offset"`
+	Limit      int                   `json:"limit"`
+}
+
+// ReverseTransactionResponse is the response of reversing a transaction
+// トランザクション取消のレスポンス
+type ReverseTransactionResponse struct {
+	Message     string                `json:"message"`
+	Transaction inventory.Transaction `json:"transaction"`
+}
+
+// AdjustmentResponse is the response of requesting, approving or rejecting a stock adjustment
+// 在庫調整の申請・承認・却下のレスポンス
+type AdjustmentResponse struct {
+	Message    string               `json:"message"`
+	Adjustment inventory.Adjustment `json:"adjustment"`
+}
+
+// AdjustmentListResponse is the response of listing stock adjustments
+// 在庫調整一覧のレスポンス
+type AdjustmentListResponse struct {
+	Adjustments []inventory.Adjustment `json:"adjustments"`
+	Count       int                    `json:"count"`
+	Offset      int                    `json:"offset"`
+	Limit       int                    `json:"limit"`
+}
+
+// InspectionResponse is the response of receiving, passing or failing a receiving inspection
+// 入荷検品の入荷・合格・不合格のレスポンス
+type InspectionResponse struct {
+	Message    string               `json:"message"`
+	Inspection inventory.Inspection `json:"inspection"`
+}
+
+// AllocationResponse is the response of allocating an item across multiple locations
+// 複数ロケーションからの引当のレスポンス
+type AllocationResponse struct {
+	Message    string               `json:"message"`
+	DryRun     bool                 `json:"dry_run"`
+	Allocation inventory.Allocation `json:"allocation"`
+}
+
+// InspectionListResponse is the response of listing receiving inspections
+// 入荷検品一覧のレスポンス
+type InspectionListResponse struct {
+	Inspections []inventory.Inspection `json:"inspections"`
+	Count       int                    `json:"count"`
+	Offset      int                    `json:"offset"`
+	Limit       int                    `json:"limit"`
+}
+
+// JobListResponse is the response of listing scheduled jobs
+// 定期ジョブの実行状況のレスポンス
+type JobListResponse struct {
+	Jobs  []scheduler.JobStatus `json:"jobs"`
+	Count int                   `json:"count"`
+}
+
+// RunJobResponse is the response of running a scheduled job now
+// 定期ジョブの即時実行のレスポンス
+type RunJobResponse struct {
+	Job     string `json:"job"`
+	Message string `json:"message"`
+}
+
+// APIKeyIssuedResponse is the response of issuing or rotating an API key
+// APIキーの発行・ローテーションのレスポンス
+type APIKeyIssuedResponse struct {
+	Message string      `json:"message"`
+	APIKey  auth.APIKey `json:"api_key"`
+	Key     string      `json:"key"` // 平文のキー（このレスポンスでのみ取得可能）
+}
+
+// APIKeyListResponse is the response of listing API keys
+// APIキー一覧のレスポンス
+type APIKeyListResponse struct {
+	APIKeys []auth.APIKey `json:"api_keys"`
+	Count   int           `json:"count"`
+}
+
+// SyncStockResponse is the response of a stock sync request
+// 在庫同期のレスポンス
+type SyncStockResponse struct {
+	Result         string `json:"result"`
+	IdempotencyKey string `json:"idempotency_key"`
+}
+
+// GraphQLRequest is the body of a GraphQL request
+// GraphQLリクエストの本文
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// クエリパラメーターの定義
+var (
+	limitParam        = openapi.Param{Name: "limit", Type: "integer", Description: "取得件数の上限（デフォルト50）"}
+	offsetParam       = openapi.Param{Name: "offset", Type: "integer", Description: "取得開始位置（非推奨。cursor を使用してください）"}
+	cursorParam       = openapi.Param{Name: "cursor", Description: "前のレスポンスの next_cursor（省略時は先頭から）"}
+	ifMatchParam      = openapi.Param{Name: "If-Match", Description: "取得時の ETag。一致しない場合は更新せずに412を返す"}
+	withinDaysParam   = openapi.Param{Name: "within_days", Type: "integer", Description: "期限までの日数（デフォルト7）"}
+	exportFormatParam = openapi.Param{Name: "format", Description: "ファイル形式（デフォルトcsv）", Enum: []string{string(spreadsheet.FormatCSV), string(spreadsheet.FormatXLSX)}}
+	dryRunParam       = openapi.Param{Name: "dry_run", Type: "boolean", Description: "trueの場合は検証のみ行い、取り込まない"}
+	backorderParam    = openapi.Param{Name: "backorder", Type: "boolean", Description: "trueの場合は在庫不足でバックオーダーを作成し、202で BackorderResponse を返す（enable_backorders が有効な場合のみ）"}
+	dryRunOpParam     = openapi.Param{Name: "dry_run", Type: "boolean", Description: "trueの場合は適用せずに検証し、在庫の変化（DryRunResult）を返す"}
+	locationIDParam   = openapi.Param{Name: "location_id", Required: true, Description: "配信するロケーションID"}
+	valuationMethods  = openapi.Param{Name: "method", Description: "評価方法（デフォルトFIFO）", Enum: []string{
+		string(inventory.ValuationMethodFIFO),
+		string(inventory.ValuationMethodLIFO),
+		string(inventory.ValuationMethodAverage),
+		string(inventory.ValuationMethodStandard),
+	}}
+)
+
+// apiSpecs describes the operations of the REST API keyed by "METHOD /path"
+// REST APIの操作の説明（キーは「METHOD /path」）
+//
+// ルートを追加・変更した場合はここも更新してください。ルーターに存在しない操作の説明があると
+// ドキュメントの生成に失敗します。
+var apiSpecs = map[string]openapi.Spec{
+	"GET /health":  {Tag: "system", Summary: "ヘルスチェック", Response: HealthResponse{}, Public: true},
+	"GET /healthz": {Tag: "system", Summary: "ライブネスチェック（プロセスの動作確認）", Response: HealthResponse{}, Public: true},
+	"GET /readyz": {
+		Tag:         "system",
+		Summary:     "レディネスチェック（データベース・イベント発行者の確認）",
+		Description: "いずれかの依存サービスが利用できない場合は503を返し、data に依存サービスごとの状態を含めます。",
+		Response:    ReadinessResponse{},
+		Public:      true,
+	},
+	"GET /metrics": {Tag: "system", Summary: "Prometheusメトリクス", ContentType: "text/plain", Public: true},
+
+	// 在庫操作
+	"POST /api/v1/inventory/add":      {Tag: "inventory", Summary: "在庫を追加", Description: "ロケーションの容量を超える場合、capacity_policy が enforce では422（LOCATION_CAPACITY_EXCEEDED）を返し、warn では追加した上で過剰在庫アラートを作成します。lot_number を指定した場合は商品のロット（ない場合は作成）の数量とロケーションのロット在庫も加算します。owner_id を指定した場合は仕入先所有の委託在庫として加算し、トランザクションの metadata.owner_id に所有者を記録します（存在しない仕入先の場合は404（SUPPLIER_NOT_FOUND））。quantity は uom（省略時は商品の基本単位）の数量で、単位の桁数までの小数を指定できます。uom が基本単位と異なる場合は商品の換算で基本単位に換算し（換算がない場合は404（UNIT_CONVERSION_NOT_FOUND））、指定した単位と数量をトランザクションの metadata.uom・metadata.uom_quantity に記録します。", Query: []openapi.Param{dryRunOpParam}, Request: AddStockRequest{}, Response: MessageResponse{}},
+	"POST /api/v1/inventory/remove":   {Tag: "inventory", Summary: "在庫を削除", Description: "pick_lots が true または lot_strategy を指定した場合は、商品の有効期限内のロットを lot_strategy（fefo・fifo・lifo）の順に消費し、消費したロットをトランザクションの metadata.lot_picks に記録します。ロットの数量が不足する場合は422（INSUFFICIENT_LOT_STOCK）を返します。lot_number を指定した場合はそのロットの数量とロケーションのロット在庫を減算し、ロット在庫が不足する場合は422（INSUFFICIENT_LOT_STOCK）を返します。owner_id を指定した場合は仕入先所有の委託在庫を減算し、委託在庫が不足する場合は422（INSUFFICIENT_CONSIGNMENT_STOCK）を返します。quantity は uom（省略時は商品の基本単位）の数量で、単位の桁数までの小数を指定できます。uom が基本単位と異なる場合は商品の換算で基本単位に換算し（換算がない場合は404（UNIT_CONVERSION_NOT_FOUND））、指定した単位と数量をトランザクションの metadata.uom・metadata.uom_quantity に記録します。", Query: []openapi.Param{dryRunOpParam, backorderParam}, Request: RemoveStockRequest{}, Response: MessageResponse{}},
+	"POST /api/v1/inventory/transfer": {Tag: "inventory", Summary: "在庫を移動", Description: "移動先の容量を超える場合、capacity_policy が enforce では422（LOCATION_CAPACITY_EXCEEDED）を返し、warn では移動した上で過剰在庫アラートを作成します。lot_number を指定した場合はロットのロット在庫も移動し、移動元のロット在庫が不足する場合は422（INSUFFICIENT_LOT_STOCK）を返します。owner_id を指定した場合は仕入先所有の委託在庫を移動し、移動元の委託在庫が不足する場合は422（INSUFFICIENT_CONSIGNMENT_STOCK）を返します。quantity は uom（省略時は商品の基本単位）の数量で、単位の桁数までの小数を指定できます。uom が基本単位と異なる場合は商品の換算で基本単位に換算し（換算がない場合は404（UNIT_CONVERSION_NOT_FOUND））、指定した単位と数量をトランザクションの metadata.uom・metadata.uom_quantity に記録します。", Query: []openapi.Param{dryRunOpParam}, Request: TransferStockRequest{}, Response: MessageResponse{}},
+	"POST /api/v1/inventory/adjust":   {Tag: "inventory", Summary: "在庫を調整", Description: "reason_code は必須です。調整額（調整数量の絶対値 × 単価）が INVENTORY_ADJUSTMENT_APPROVAL_THRESHOLD を超える調整は在庫を変更せずに承認待ち（pending）として記録し、202を返します。", Headers: []openapi.Param{ifMatchParam}, Query: []openapi.Param{dryRunOpParam}, Request: AdjustStockRequest{}, Response: AdjustmentResponse{}},
+	"POST /api/v1/inventory/batch": {
+		Tag:     "inventory",
+		Summary: "在庫操作を一括実行",
+		Query: []openapi.Param{
+			{Name: "atomic", Type: "boolean", Description: "trueの場合は1件でも失敗すると全操作をロールバック"},
+			{Name: "async", Type: "boolean", Description: "trueの場合はバックグラウンドで実行し、202で登録時点の状態を返す"},
+			dryRunOpParam,
+		},
+		Request:  []inventory.InventoryOperation{},
+		Response: inventory.BatchOperation{},
+	},
+	"GET /api/v1/inventory/batch/{batchId}/status":  {Tag: "inventory", Summary: "一括処理の状態を取得", Response: inventory.BatchOperation{}},
+	"POST /api/v1/inventory/batch/{batchId}/cancel": {Tag: "inventory", Summary: "非同期の一括処理を取り消し", Response: inventory.BatchOperation{}},
+	"POST /api/v1/inventory/reserve":                {Tag: "inventory", Summary: "在庫を予約", Query: []openapi.Param{backorderParam}, Request: ReservationRequest{}, Response: MessageResponse{}},
+	"POST /api/v1/inventory/release-reservation":    {Tag: "inventory", Summary: "予約を解除", Request: ReservationRequest{}, Response: MessageResponse{}},
+
+	// 予約管理
+	"POST /api/v1/reservations": {
+		Tag:         "reservations",
+		Summary:     "予約を作成",
+		Description: "利用可能数から数量を確保し、予約IDを返します。expires_at を過ぎた予約は自動で期限切れ（expired）になり、確保した在庫が解放されます。",
+		Request:     CreateReservationRequest{},
+		Response:    ReservationResponse{},
+	},
+	"GET /api/v1/reservations": {
+		Tag:     "reservations",
+		Summary: "予約一覧を取得（作成日時の降順）",
+		Query: []openapi.Param{
+			{Name: "item_id", Description: "商品IDで絞り込む"},
+			{Name: "location_id", Description: "ロケーションIDで絞り込む"},
+			{Name: "reference", Description: "参照番号で絞り込む"},
+			{Name: "channel", Description: "販売チャネルで絞り込む"},
+			{Name: "status", Description: "状態で絞り込む", Enum: []string{string(inventory.ReservationStatusActive), string(inventory.ReservationStatusReleased), string(inventory.ReservationStatusExpired), string(inventory.ReservationStatusFulfilled)}},
+			{Name: "limit", Type: "integer", Description: "取得件数の上限（デフォルト20、最大100）"},
+			{Name: "offset", Type: "integer", Description: "取得開始位置"},
+		},
+		Response: ReservationListResponse{},
+	},
+	"GET /api/v1/reservations/{reservationId}":          {Tag: "reservations", Summary: "予約を取得", Response: inventory.Reservation{}},
+	"POST /api/v1/reservations/{reservationId}/release": {Tag: "reservations", Summary: "予約を解除", Description: "解除済み・期限切れの予約は409（RESERVATION_NOT_ACTIVE）を返します。", Response: ReservationResponse{}},
+	"POST /api/v1/reservations/{reservationId}/fulfill": {
+		Tag:         "reservations",
+		Summary:     "予約の在庫を出庫",
+		Description: "在庫数量と予約数量を予約の数量だけ減らし、出庫トランザクションを一件記録します。本文は省略でき、reference を省略した場合は予約の参照番号を記録します。有効でない予約は409（RESERVATION_NOT_ACTIVE）を返します。",
+		Request:     FulfillReservationRequest{},
+		Response:    FulfillReservationResponse{},
+	},
+	"GET /api/v1/reservations/reference/{ref}": {Tag: "reservations", Summary: "参照番号の予約を取得（作成日時の降順）", Response: ReservationReferenceResponse{}},
+	"POST /api/v1/reservations/reference/{ref}/release": {
+		Tag:         "reservations",
+		Summary:     "参照番号の予約を全て解除",
+		Description: "注文のキャンセル時などに、参照番号の有効な予約を一つのトランザクションで全て解除し、解除した予約を返します。有効な予約がない場合は空の一覧を返します。",
+		Response:    ReservationReferenceResponse{},
+	},
+
+	// 発注点管理
+	"GET /api/v1/reorder-points": {
+		Tag:     "reorder-points",
+		Summary: "発注点一覧を取得（商品ID・ロケーションIDの昇順）",
+		Query: []openapi.Param{
+			{Name: "item_id", Description: "商品IDで絞り込む"},
+			{Name: "location_id", Description: "ロケーションIDで絞り込む"},
+			{Name: "limit", Type: "integer", Description: "取得件数の上限（デフォルト20、最大100）"},
+			{Name: "offset", Type: "integer", Description: "取得開始位置"},
+		},
+		Response: ReorderPointListResponse{},
+	},
+	"GET /api/v1/reorder-points/{itemId}/{locationId}": {Tag: "reorder-points", Summary: "発注点を取得", Description: "設定されていない場合は404（REORDER_POINT_NOT_FOUND）を返します。", Response: inventory.ReorderPoint{}},
+	"PUT /api/v1/reorder-points/{itemId}/{locationId}": {
+		Tag:         "reorder-points",
+		Summary:     "発注点を設定",
+		Description: "商品・ロケーションの発注点・最小/最大在庫数を作成または上書きします。設定した在庫は low_stock_threshold の代わりに発注点以下で低在庫アラートを発生させます。max_qty は0（上限なし）または min_qty・reorder_point 以上で指定してください。",
+		Request:     SetReorderPointRequest{},
+		Response:    inventory.ReorderPoint{},
+	},
+	"DELETE /api/v1/reorder-points/{itemId}/{locationId}": {Tag: "reorder-points", Summary: "発注点を削除", Description: "削除後は low_stock_threshold で低在庫を判定します。", Response: MessageResponse{}},
+
+	// アラートルール管理
+	"GET /api/v1/alert-rules": {
+		Tag:     "alert-rules",
+		Summary: "アラートルール一覧を取得（作成日時の昇順）",
+		Query: []openapi.Param{
+			{Name: "item_id", Description: "対象の商品IDで絞り込む"},
+			{Name: "location_id", Description: "対象のロケーションIDで絞り込む"},
+			{Name: "enabled", Type: "boolean", Description: "true の場合は有効なルールのみ取得"},
+			{Name: "limit", Type: "integer", Description: "取得件数の上限（デフォルト20、最大100）"},
+			{Name: "offset", Type: "integer", Description: "取得開始位置"},
+		},
+		Response: AlertRuleListResponse{},
+	},
+	"POST /api/v1/alert-rules": {
+		Tag:         "alert-rules",
+		Summary:     "アラートルールを作成",
+		Description: "在庫の値（metric）を閾値と比較（comparator）し、条件を満たした在庫記録にアラートを作成するルールです。item_id・location_id を省略した場合は全商品・全ロケーションが対象です。在庫の変更時と定期的な評価で判定し、アクティブなアラートがある在庫記録やクールダウン中（cooldown_seconds）の在庫記録にはアラートを作成しません。",
+		Request:     AlertRuleRequest{},
+		Response:    inventory.AlertRule{},
+	},
+	"POST /api/v1/alert-rules/evaluate": {
+		Tag:         "alert-rules",
+		Summary:     "アラートルールを即時評価",
+		Description: "有効な全てのアラートルールを対象範囲の在庫記録に対して評価し、作成したアラート数を返します。",
+		Response:    EvaluateAlertRulesResponse{},
+	},
+	"GET /api/v1/alert-rules/{ruleId}": {Tag: "alert-rules", Summary: "アラートルールを取得", Response: inventory.AlertRule{}},
+	"PUT /api/v1/alert-rules/{ruleId}": {
+		Tag:         "alert-rules",
+		Summary:     "アラートルールを更新",
+		Description: "条件・対象範囲・有効状態を置き換えます。作成済みのアラートは変更しません。",
+		Request:     AlertRuleRequest{},
+		Response:    inventory.AlertRule{},
+	},
+	"DELETE /api/v1/alert-rules/{ruleId}": {Tag: "alert-rules", Summary: "アラートルールを削除", Description: "ルールで作成済みのアラートは残ります。", Response: MessageResponse{}},
+
+	// バックオーダー管理
+	"GET /api/v1/backorders": {
+		Tag:     "backorders",
+		Summary: "バックオーダー一覧を取得（作成日時の昇順）",
+		Query: []openapi.Param{
+			{Name: "item_id", Description: "商品IDで絞り込む"},
+			{Name: "location_id", Description: "ロケーションIDで絞り込む"},
+			{Name: "reference", Description: "参照番号で絞り込む"},
+			{Name: "status", Description: "状態で絞り込む", Enum: []string{string(inventory.BackorderStatusPending), string(inventory.BackorderStatusAllocated), string(inventory.BackorderStatusCancelled)}},
+			{Name: "limit", Type: "integer", Description: "取得件数の上限（デフォルト20、最大100）"},
+			{Name: "offset", Type: "integer", Description: "取得開始位置"},
+		},
+		Response: BackorderListResponse{},
+	},
+	"GET /api/v1/backorders/{backorderId}": {Tag: "backorders", Summary: "バックオーダーを取得", Response: inventory.Backorder{}},
+	"POST /api/v1/backorders/{backorderId}/cancel": {
+		Tag:         "backorders",
+		Summary:     "バックオーダーを取消",
+		Description: "入荷待ち（pending）のバックオーダーのみ取り消せます。引当済み・取消済みのバックオーダーは409（BACKORDER_NOT_PENDING）を返します。",
+		Response:    BackorderResponse{},
+	},
+	"POST /api/v1/backorders/allocate": {
+		Tag:         "backorders",
+		Summary:     "利用可能な在庫をバックオーダーに引当",
+		Description: "入荷待ちのバックオーダーを古い順に予約へ変換し、引き当てたバックオーダーを返します。利用可能数が次のバックオーダーに満たない場合はそこで終了します。enable_backorders が有効な場合は在庫追加時に自動で実行されます。",
+		Request:     AllocateBackordersRequest{},
+		Response:    AllocatedBackordersResponse{},
+	},
+
+	// 在庫照会
+	"POST /api/v1/inventory/lookup": {
+		Tag:         "inventory",
+		Summary:     "複数の在庫を一括で取得",
+		Description: "指定した商品とロケーションの組（最大500件）の在庫を指定した順序で返します。在庫記録が存在しない組は missing に含めます。照会のみのため inventory:read スコープで実行できます。",
+		Request:     LookupStocksRequest{},
+		Response:    StockLookupResponse{},
+	},
+	"GET /api/v1/inventory/{itemId}/{locationId}":                    {Tag: "inventory", Summary: "在庫を取得", Description: "ETag ヘッダーで在庫のバージョンを返します。", Response: inventory.Stock{}},
+	"GET /api/v1/inventory/{itemId}/{locationId}/ownership":          {Tag: "consignment", Summary: "在庫の自社所有・委託在庫の内訳を取得", Description: "owned_quantity は在庫数量から仕入先所有の委託在庫を除いた数量で、在庫評価の対象です。在庫がない場合は数量0を返します。", Response: inventory.StockOwnership{}},
+	"GET /api/v1/inventory/{itemId}/{locationId}/channels":           {Tag: "channels", Summary: "販売チャネルごとの利用可能な在庫を取得", Description: "shared_available はどのチャネルにも確保されていない利用可能数量で、チャネルを指定しない予約が使用できる数量です。各チャネルの available は確保数量の未予約分と shared_available の合計（利用可能数量が上限）です。", Response: inventory.StockChannels{}},
+	"PUT /api/v1/inventory/{itemId}/{locationId}/channels/{channel}": {Tag: "channels", Summary: "販売チャネルの確保数量を設定", Description: "確保数量のうちチャネルの予約で使用していない数量は、他のチャネルとチャネルを指定しない予約では予約できません（不足する場合は422）。quantity が0の場合は確保を削除します。", Request: SetChannelAllocationRequest{}, Response: inventory.ChannelAllocation{}},
+	"GET /api/v1/inventory/{itemId}/total":                           {Tag: "inventory", Summary: "商品の総在庫数を取得", Response: TotalStockResponse{}},
+	"GET /api/v1/inventory/location/{locationId}": {
+		Tag:         "inventory",
+		Summary:     "ロケーションの在庫一覧を取得",
+		Description: "stream=true の場合は封筒形式を使わずにJSONの配列を逐次送信します。",
+		Query:       []openapi.Param{{Name: "stream", Type: "boolean", Description: "trueの場合はストリーミングで送信"}},
+		Response:    []inventory.Stock{},
+	},
+	"GET /api/v1/inventory/location/{locationId}/export": {
+		Tag:         "inventory",
+		Summary:     "ロケーションの在庫をCSV・Excelでエクスポート",
+		Description: "1行目は列名のヘッダーです。全件をメモリに展開せずにストリーミングで送信します。",
+		Query:       []openapi.Param{exportFormatParam},
+		ContentType: "application/octet-stream",
+	},
+
+	// 履歴
+	"GET /api/v1/inventory/{itemId}/history": {
+		Tag:         "history",
+		Summary:     "商品の履歴を取得",
+		Description: "paginate=true またはカーソルを指定した場合は TransactionPage を返します。",
+		Query: []openapi.Param{
+			{Name: "limit", Type: "integer", Description: "取得件数の上限（デフォルト50）"},
+			{Name: "paginate", Type: "boolean", Description: "trueの場合はキーセットページネーション"},
+			{Name: "after_id", Description: "前のページの next_cursor.after_id"},
+			{Name: "after_created_at", Format: "date-time", Description: "前のページの next_cursor.after_created_at"},
+		},
+		Response: []inventory.Transaction{},
+	},
+	"GET /api/v1/inventory/{itemId}/history/export": {
+		Tag:         "history",
+		Summary:     "商品の履歴をCSV・Excelでエクスポート",
+		Description: "すべての履歴を新しい順に書き出します。1行目は列名のヘッダーで、metadata はJSON文字列です。",
+		Query:       []openapi.Param{exportFormatParam},
+		ContentType: "application/octet-stream",
+	},
+	"GET /api/v1/inventory/{itemId}/history/date-range": {
+		Tag:     "history",
+		Summary: "日付範囲で商品の履歴を取得",
+		Query: []openapi.Param{
+			{Name: "from", Format: "date", Required: true, Description: "開始日（2006-01-02）"},
+			{Name: "to", Format: "date", Required: true, Description: "終了日（2006-01-02、当日を含む）"},
+		},
+		Response: HistoryResponse{},
+	},
+	"GET /api/v1/inventory/history/location/{locationId}": {Tag: "history", Summary: "ロケーションの履歴を取得", Query: []openapi.Param{limitParam}, Response: HistoryResponse{}},
+	"GET /api/v1/inventory/history/reference/{ref}":       {Tag: "history", Summary: "参照番号で履歴を取得", Response: HistoryResponse{}},
+	"GET /api/v1/inventory/history/metadata": {
+		Tag:     "history",
+		Summary: "メタデータで履歴を検索",
+		Query: []openapi.Param{
+			{Name: "key", Required: true, Description: "メタデータのキー"},
+			{Name: "value", Description: "メタデータの値"},
+			limitParam,
+		},
+		Response: HistoryResponse{},
+	},
+	"GET /api/v1/transactions/{txId}": {Tag: "history", Summary: "トランザクションを取得", Response: inventory.Transaction{}},
+	"POST /api/v1/transactions/{txId}/reverse": {
+		Tag:         "history",
+		Summary:     "トランザクションを取消",
+		Description: "入庫・出庫・移動・調整・棚卸のトランザクションを打ち消す在庫移動を記録し、メタデータ reversal_of に元のトランザクションIDを記録します。予約・予約解除・取消自体のトランザクションは422（TRANSACTION_NOT_REVERSIBLE）、既に取り消されたトランザクションは409（TRANSACTION_ALREADY_REVERSED）を返します。",
+		Request:     ReverseTransactionRequest{},
+		Response:    ReverseTransactionResponse{},
+	},
+
+	// アラート
+	"GET /api/v1/alerts": {
+		Tag:         "alerts",
+		Summary:     "全ロケーションのアラート一覧を取得（作成日時の新しい順）",
+		Description: "解決済みのアラートを含みます。from・to に日付を指定した場合、to はその日の終わりまでを含みます。",
+		Query: []openapi.Param{
+			{Name: "type", Description: "アラートタイプで絞り込む", Enum: []string{string(inventory.AlertTypeLowStock), string(inventory.AlertTypeOverStock), string(inventory.AlertTypeExpiring), string(inventory.AlertTypeExpired), string(inventory.AlertTypeDiscrepancy)}},
+			{Name: "severity", Description: "重要度で絞り込む", Enum: []string{string(inventory.AlertSeverityInfo), string(inventory.AlertSeverityWarning), string(inventory.AlertSeverityCritical)}},
+			{Name: "status", Description: "アクティブ・解決済みのアラートのみ取得", Enum: []string{"active", "resolved"}},
+			{Name: "acknowledged", Type: "boolean", Description: "true の場合は確認済み、false の場合は未確認のアラートのみ取得"},
+			{Name: "item_id", Description: "商品IDで絞り込む"},
+			{Name: "location_id", Description: "ロケーションIDで絞り込む"},
+			{Name: "from", Description: "この日時以降に作成したアラートのみ取得（2006-01-02 または RFC 3339）"},
+			{Name: "to", Description: "この日時より前に作成したアラートのみ取得（2006-01-02 または RFC 3339）"},
+			{Name: "limit", Type: "integer", Description: "取得件数の上限（デフォルト20、最大100）"},
+			{Name: "offset", Type: "integer", Description: "取得開始位置"},
+		},
+		Response: AlertListResponse{},
+	},
+	"GET /api/v1/alerts/{locationId}": {
+		Tag:     "alerts",
+		Summary: "アクティブなアラートを取得",
+		Query: []openapi.Param{
+			{Name: "severity", Description: "重要度で絞り込む", Enum: []string{string(inventory.AlertSeverityInfo), string(inventory.AlertSeverityWarning), string(inventory.AlertSeverityCritical)}},
+			{Name: "acknowledged", Type: "boolean", Description: "true の場合は確認済み、false の場合は未確認のアラートのみ取得"},
+		},
+		Response: []inventory.StockAlert{},
+	},
+	"POST /api/v1/alerts/{alertId}/acknowledge": {
+		Tag:         "alerts",
+		Summary:     "アラートを確認",
+		Description: "対応中であることを記録します（確認したユーザー・日時・メモ）。アラートは解決しません。解決済みの場合は409（ALERT_NOT_ACTIVE）、確認済みの場合は409（ALERT_ALREADY_ACKNOWLEDGED）を返します。本文は省略可能です。",
+		Request:     AlertNoteRequest{},
+		Response:    AlertResponse{},
+	},
+	"POST /api/v1/alerts/{alertId}/resolve": {
+		Tag:         "alerts",
+		Summary:     "アラートを解決",
+		Description: "解決したユーザー・日時・メモを記録します。確認していないアラートも解決できます。解決済みの場合は409（ALERT_NOT_ACTIVE）を返します。本文は省略可能です。",
+		Request:     AlertNoteRequest{},
+		Response:    AlertResponse{},
+	},
+	"GET /api/v1/alerts/stream": {
+		Tag:         "alerts",
+		Summary:     "アラートをServer-Sent Eventsで配信",
+		Description: "イベント名は alert.created・alert.acknowledged・alert.resolved です。",
+		Query:       []openapi.Param{locationIDParam},
+		ContentType: "text/event-stream",
+	},
+
+	// 商品管理
+	"POST /api/v1/items": {Tag: "items", Summary: "商品を作成", Request: inventory.Item{}, Response: ItemResponse{}},
+	"GET /api/v1/items": {
+		Tag:     "items",
+		Summary: "商品一覧を取得",
+		Query: []openapi.Param{
+			cursorParam, offsetParam, {Name: "limit", Type: "integer", Description: "取得件数の上限（デフォルト20、最大100）"},
+			{Name: "category", Description: "カテゴリ（完全一致）"},
+			{Name: "sku_prefix", Description: "SKUの前方一致"},
+			{Name: "min_cost", Type: "number", Description: "単価の下限（この値を含む）"},
+			{Name: "max_cost", Type: "number", Description: "単価の上限（この値を含む）"},
+			{Name: "sort", Description: "並び替えの項目（デフォルトcreated_at）", Enum: []string{
+				string(inventory.ItemSortCreatedAt),
+				string(inventory.ItemSortName),
+				string(inventory.ItemSortSKU),
+				string(inventory.ItemSortUnitCost),
+			}},
+			{Name: "order", Description: "並び替えの方向（デフォルトdesc）", Enum: []string{"asc", "desc"}},
+		},
+		Response: ItemListResponse{},
+	},
+	"GET /api/v1/items/search": {
+		Tag:      "items",
+		Summary:  "商品を検索",
+		Query:    []openapi.Param{{Name: "q", Required: true, Description: "検索文字列"}},
+		Response: ItemSearchResponse{},
+	},
+	"GET /api/v1/items/{itemId}":    {Tag: "items", Summary: "商品を取得", Description: "ETag ヘッダーで更新日時によるタグを返します。", Response: inventory.Item{}},
+	"PUT /api/v1/items/{itemId}":    {Tag: "items", Summary: "商品を更新", Headers: []openapi.Param{ifMatchParam}, Request: inventory.Item{}, Response: ItemResponse{}},
+	"DELETE /api/v1/items/{itemId}": {Tag: "items", Summary: "商品を削除", Description: "部品表の構成品として使用中の商品は削除できず、409（ITEM_IN_USE）を返します。キットの部品表は商品とともに削除します。", Response: MessageResponse{}},
+	"PATCH /api/v1/items/{itemId}": {
+		Tag:         "items",
+		Summary:     "商品を部分更新",
+		Description: "JSONマージパッチ（RFC 7396）で指定したフィールドのみを更新します。null を指定したフィールドはゼロ値になります。",
+		Headers:     []openapi.Param{ifMatchParam},
+		Request:     inventory.Item{},
+		RequestType: mergepatch.ContentType,
+		Response:    ItemResponse{},
+	},
+
+	// ロケーション管理
+	"POST /api/v1/locations": {Tag: "locations", Summary: "ロケーションを作成", Request: inventory.Location{}, Response: LocationResponse{}},
+	"GET /api/v1/locations": {
+		Tag:      "locations",
+		Summary:  "ロケーション一覧を取得",
+		Query:    []openapi.Param{cursorParam, offsetParam, {Name: "limit", Type: "integer", Description: "取得件数の上限（デフォルト20、最大100）"}},
+		Response: LocationListResponse{},
+	},
+	"GET /api/v1/locations/{locationId}":    {Tag: "locations", Summary: "ロケーションを取得", Description: "ETag ヘッダーで更新日時によるタグを返します。", Response: inventory.Location{}},
+	"PUT /api/v1/locations/{locationId}":    {Tag: "locations", Summary: "ロケーションを更新", Headers: []openapi.Param{ifMatchParam}, Request: inventory.Location{}, Response: LocationResponse{}},
+	"DELETE /api/v1/locations/{locationId}": {Tag: "locations", Summary: "ロケーションを削除", Response: MessageResponse{}},
+	"PATCH /api/v1/locations/{locationId}": {
+		Tag:         "locations",
+		Summary:     "ロケーションを部分更新",
+		Description: "JSONマージパッチ（RFC 7396）で指定したフィールドのみを更新します。null を指定したフィールドはゼロ値になります。",
+		Headers:     []openapi.Param{ifMatchParam},
+		Request:     inventory.Location{},
+		RequestType: mergepatch.ContentType,
+		Response:    LocationResponse{},
+	},
+
+	// CSV取り込み
+	"POST /api/v1/import/items": {
+		Tag:         "import",
+		Summary:     "商品をCSVから取り込み",
+		Description: "列は id・name（必須）、sku・description・category・unit_cost（任意）です。1行でもエラーがある場合は何も取り込まず、422 と行ごとのエラーを返します。",
+		Query:       []openapi.Param{dryRunParam},
+		Request:     ImportRequest{},
+		RequestType: "multipart/form-data",
+		Response:    inventory.ImportResult{},
+	},
+	"POST /api/v1/import/opening-balances": {
+		Tag:         "import",
+		Summary:     "期首残高をCSVから取り込み",
+		Description: "列は item_id・location_id・quantity（必須）、unit_cost（任意）です。在庫記録が存在しない組のみ取り込め、数量が正の行は参照番号 OPENING-BALANCE の入庫トランザクションを記録します。1行でもエラーがある場合は何も取り込まず、422 と行ごとのエラーを返します。",
+		Query:       []openapi.Param{dryRunParam},
+		Request:     ImportRequest{},
+		RequestType: "multipart/form-data",
+		Response:    inventory.ImportResult{},
+	},
+
+	// ロット管理
+	"POST /api/v1/lots":                            {Tag: "lots", Summary: "ロットを作成", Request: inventory.Lot{}, Response: LotResponse{}},
+	"GET /api/v1/lots/{lotId}":                     {Tag: "lots", Summary: "ロットを取得", Response: inventory.Lot{}},
+	"PUT /api/v1/lots/{lotId}":                     {Tag: "lots", Summary: "ロットを更新", Request: inventory.Lot{}, Response: LotResponse{}},
+	"DELETE /api/v1/lots/{lotId}":                  {Tag: "lots", Summary: "ロットを削除", Response: MessageResponse{}},
+	"POST /api/v1/lots/{lotId}/adjust":             {Tag: "lots", Summary: "ロット数量を調整", Request: AdjustLotRequest{}, Response: LotResponse{}},
+	"GET /api/v1/lots/item/{itemId}":               {Tag: "lots", Summary: "商品のロット一覧を取得", Response: LotListResponse{}},
+	"GET /api/v1/lots/expiring":                    {Tag: "lots", Summary: "期限切れ間近のロットを取得", Query: []openapi.Param{withinDaysParam}, Response: LotListResponse{}},
+	"POST /api/v1/lots/expiring/notify":            {Tag: "lots", Summary: "期限切れ間近のロットを通知", Query: []openapi.Param{withinDaysParam}, Response: NotifyExpiringLotsResponse{}},
+	"GET /api/v1/lots/expired":                     {Tag: "lots", Summary: "期限切れのロットを取得", Response: LotListResponse{}},
+	"GET /api/v1/lots/{lotId}/stocks/{locationId}": {Tag: "lots", Summary: "ロットのロケーションの在庫を取得", Description: "ロケーションにロットの在庫がない場合は数量0を返します。存在しないロットの場合は404（LOT_NOT_FOUND）を返します。", Response: inventory.LotStock{}},
+	"GET /api/v1/lot-stocks": {
+		Tag:     "lots",
+		Summary: "ロット在庫一覧を取得（ロット番号・ロケーションIDの順）",
+		Query: []openapi.Param{
+			{Name: "item_id", Description: "商品IDで絞り込む"},
+			{Name: "lot_id", Description: "ロットIDで絞り込む"},
+			{Name: "location_id", Description: "ロケーションIDで絞り込む"},
+			{Name: "include_empty", Type: "boolean", Description: "数量0のロット在庫も含める"},
+			{Name: "limit", Type: "integer", Description: "取得件数の上限（デフォルト20、最大100）"},
+			{Name: "offset", Type: "integer", Description: "取得開始位置"},
+		},
+		Response: LotStockListResponse{},
+	},
+
+	// 単位管理
+	"POST /api/v1/units":                        {Tag: "units", Summary: "単位を作成", Description: "decimals は数量の小数点以下の桁数（0〜6）です。既に存在するコードの場合は409（UNIT_ALREADY_EXISTS）を返します。", Request: inventory.UnitOfMeasure{}, Response: inventory.UnitOfMeasure{}},
+	"GET /api/v1/units":                         {Tag: "units", Summary: "単位一覧を取得（コード順）", Response: UnitListResponse{}},
+	"GET /api/v1/units/{unitCode}":              {Tag: "units", Summary: "単位を取得", Response: inventory.UnitOfMeasure{}},
+	"DELETE /api/v1/units/{unitCode}":           {Tag: "units", Summary: "単位を削除", Description: "商品の基本単位または換算に使用されている単位は削除できず、409（UNIT_IN_USE）を返します。", Response: MessageResponse{}},
+	"GET /api/v1/items/{itemId}/units":          {Tag: "units", Summary: "商品の単位の換算一覧を取得（単位コード順）", Response: UnitConversionListResponse{}},
+	"PUT /api/v1/items/{itemId}/units/{uom}":    {Tag: "units", Summary: "商品の単位の換算を設定", Description: "factor は1単位あたりの基本単位の数量です（例: 1箱 = 12個 なら 12）。基本単位の換算は設定できません。", Request: SetUnitConversionRequest{}, Response: inventory.UnitConversion{}},
+	"DELETE /api/v1/items/{itemId}/units/{uom}": {Tag: "units", Summary: "商品の単位の換算を削除", Description: "設定されていない場合は404（UNIT_CONVERSION_NOT_FOUND）を返します。", Response: MessageResponse{}},
+
+	// キット管理
+	"GET /api/v1/boms": {
+		Tag:     "kits",
+		Summary: "部品表一覧を取得（キットの商品IDの昇順）",
+		Query: []openapi.Param{
+			{Name: "limit", Type: "integer", Description: "取得件数の上限（デフォルト20、最大100）"},
+			{Name: "offset", Type: "integer", Description: "取得開始位置"},
+		},
+		Response: BOMListResponse{},
+	},
+	"GET /api/v1/boms/{itemId}":    {Tag: "kits", Summary: "キットの部品表を取得", Description: "構成品は商品IDの昇順です。登録されていない場合は404（BOM_NOT_FOUND）を返します。", Response: inventory.BillOfMaterials{}},
+	"PUT /api/v1/boms/{itemId}":    {Tag: "kits", Summary: "キットの部品表を設定", Description: "構成品の quantity はキット1単位あたりの構成品の基本単位の数量です。登録済みの場合は構成品を置き換えます。キット自身・重複した構成品は指定できず、構成品は100件までです。", Request: SetBillOfMaterialsRequest{}, Response: inventory.BillOfMaterials{}},
+	"DELETE /api/v1/boms/{itemId}": {Tag: "kits", Summary: "キットの部品表を削除", Description: "記録済みの組立・分解と在庫は変わりません。", Response: MessageResponse{}},
+	"POST /api/v1/inventory/assemble": {
+		Tag:         "kits",
+		Summary:     "キットを組み立てる",
+		Description: "部品表に従って構成品（構成品の数量 × quantity）を消費し、キットを quantity 作成します。いずれかの構成品の利用可能数が不足する場合は422（INSUFFICIENT_STOCK）を返し、在庫は変わりません。構成品とキットの増減は metadata.assembly_id を持つ assembly のトランザクションに差分で記録します。",
+		Request:     AssemblyRequest{},
+		Response:    inventory.Assembly{},
+	},
+	"POST /api/v1/inventory/disassemble": {
+		Tag:         "kits",
+		Summary:     "キットを分解する",
+		Description: "キットを quantity 消費し、部品表に従って構成品を在庫に戻します。キットの利用可能数が不足する場合は422（INSUFFICIENT_STOCK）を返します。組立の取消にも使用します（assembly のトランザクションは取消できません）。",
+		Request:     AssemblyRequest{},
+		Response:    inventory.Assembly{},
+	},
+	"GET /api/v1/assemblies": {
+		Tag:     "kits",
+		Summary: "キットの組立・分解一覧を取得（作成日時の新しい順）",
+		Query: []openapi.Param{
+			{Name: "kit_item_id", Description: "キットの商品IDで絞り込む"},
+			{Name: "location_id", Description: "ロケーションIDで絞り込む"},
+			{Name: "limit", Type: "integer", Description: "取得件数の上限（デフォルト20、最大100）"},
+			{Name: "offset", Type: "integer", Description: "取得開始位置"},
+		},
+		Response: AssemblyListResponse{},
+	},
+	"GET /api/v1/assemblies/{assemblyId}": {Tag: "kits", Summary: "キットの組立・分解を取得", Description: "記録したトランザクション（商品IDの昇順）を含みます。存在しない場合は404（ASSEMBLY_NOT_FOUND）を返します。", Response: inventory.Assembly{}},
+
+	// 仕入先管理
+	"GET /api/v1/suppliers": {
+		Tag:     "suppliers",
+		Summary: "仕入先一覧を取得（仕入先IDの昇順）",
+		Query: []openapi.Param{
+			{Name: "limit", Type: "integer", Description: "取得件数の上限（デフォルト20、最大100）"},
+			{Name: "offset", Type: "integer", Description: "取得開始位置"},
+		},
+		Response: SupplierListResponse{},
+	},
+	"POST /api/v1/suppliers":                {Tag: "suppliers", Summary: "仕入先を作成", Description: "is_active を省略した場合はアクティブです。既に存在するIDの場合は409（SUPPLIER_ALREADY_EXISTS）を返します。", Request: SupplierRequest{}, Response: inventory.Supplier{}},
+	"GET /api/v1/suppliers/{supplierId}":    {Tag: "suppliers", Summary: "仕入先を取得", Description: "存在しない場合は404（SUPPLIER_NOT_FOUND）を返します。", Response: inventory.Supplier{}},
+	"PUT /api/v1/suppliers/{supplierId}":    {Tag: "suppliers", Summary: "仕入先を更新", Description: "本文の id は無視し、パスの仕入先IDを使用します。", Request: SupplierRequest{}, Response: inventory.Supplier{}},
+	"DELETE /api/v1/suppliers/{supplierId}": {Tag: "suppliers", Summary: "仕入先を削除", Description: "仕入先の仕入条件も削除します。", Response: MessageResponse{}},
+	"GET /api/v1/suppliers/{supplierId}/items": {
+		Tag:     "suppliers",
+		Summary: "仕入先の商品の仕入条件一覧を取得（商品IDの昇順）",
+		Query: []openapi.Param{
+			{Name: "limit", Type: "integer", Description: "取得件数の上限（デフォルト20、最大100）"},
+			{Name: "offset", Type: "integer", Description: "取得開始位置"},
+		},
+		Response: SupplierItemListResponse{},
+	},
+	"GET /api/v1/suppliers/{supplierId}/items/{itemId}":    {Tag: "suppliers", Summary: "仕入先の商品の仕入条件を取得", Description: "設定されていない場合は404（SUPPLIER_ITEM_NOT_FOUND）を返します。", Response: inventory.SupplierItem{}},
+	"PUT /api/v1/suppliers/{supplierId}/items/{itemId}":    {Tag: "suppliers", Summary: "仕入先の商品の仕入条件を設定", Description: "設定済みの場合は置き換えます。lead_time_days は発注から入荷までの日数（0〜365）、min_order_quantity は最小発注数量（0の場合は制限なし）です。is_preferred を true にすると、同じ商品の他の仕入先の優先指定を解除します。", Request: SetSupplierItemRequest{}, Response: inventory.SupplierItem{}},
+	"DELETE /api/v1/suppliers/{supplierId}/items/{itemId}": {Tag: "suppliers", Summary: "仕入先の商品の仕入条件を削除", Description: "設定されていない場合は404（SUPPLIER_ITEM_NOT_FOUND）を返します。", Response: MessageResponse{}},
+	"GET /api/v1/items/{itemId}/suppliers": {
+		Tag:     "suppliers",
+		Summary: "商品の仕入先の仕入条件一覧を取得（仕入先IDの昇順）",
+		Query: []openapi.Param{
+			{Name: "limit", Type: "integer", Description: "取得件数の上限（デフォルト20、最大100）"},
+			{Name: "offset", Type: "integer", Description: "取得開始位置"},
+		},
+		Response: SupplierItemListResponse{},
+	},
+
+	// 発注管理
+	"GET /api/v1/purchase-orders": {
+		Tag:     "purchase-orders",
+		Summary: "発注一覧を取得（作成日時の新しい順、行は含まない）",
+		Query: []openapi.Param{
+			{Name: "supplier_id", Description: "仕入先IDで絞り込む"},
+			{Name: "location_id", Description: "入荷先のロケーションIDで絞り込む"},
+			{Name: "status", Description: "状態で絞り込む", Enum: []string{string(inventory.PurchaseOrderStatusDraft), string(inventory.PurchaseOrderStatusApproved), string(inventory.PurchaseOrderStatusSent), string(inventory.PurchaseOrderStatusPartiallyReceived), string(inventory.PurchaseOrderStatusReceived), string(inventory.PurchaseOrderStatusCancelled)}},
+			{Name: "limit", Type: "integer", Description: "取得件数の上限（デフォルト20、最大100）"},
+			{Name: "offset", Type: "integer", Description: "取得開始位置"},
+		},
+		Response: PurchaseOrderListResponse{},
+	},
+	"POST /api/v1/purchase-orders": {
+		Tag:         "purchase-orders",
+		Summary:     "発注を作成",
+		Description: "作成済み（draft）の発注を作成します。仕入先はアクティブである必要があります。行の unit_cost を省略した場合は仕入条件の仕入単価（仕入条件がない場合は商品の単価）を設定し、仕入条件の最小発注数量を下回る行は422を返します。reference を省略した場合は発注IDを設定します。",
+		Request:     CreatePurchaseOrderRequest{},
+		Response:    PurchaseOrderResponse{},
+	},
+	"GET /api/v1/purchase-orders/{purchaseOrderId}":          {Tag: "purchase-orders", Summary: "発注を行とともに取得", Description: "存在しない発注の場合は404（PURCHASE_ORDER_NOT_FOUND）を返します。", Response: inventory.PurchaseOrder{}},
+	"POST /api/v1/purchase-orders/{purchaseOrderId}/approve": {Tag: "purchase-orders", Summary: "発注を承認", Description: "作成済み（draft）でない発注は409（PURCHASE_ORDER_STATUS_CONFLICT）を返します。", Response: PurchaseOrderResponse{}},
+	"POST /api/v1/purchase-orders/{purchaseOrderId}/send":    {Tag: "purchase-orders", Summary: "発注を送付済みにする", Description: "承認済み（approved）でない発注は409（PURCHASE_ORDER_STATUS_CONFLICT）を返します。送付済みの発注に対して入荷を記録できます。", Response: PurchaseOrderResponse{}},
+	"POST /api/v1/purchase-orders/{purchaseOrderId}/cancel":  {Tag: "purchase-orders", Summary: "発注を取消", Description: "全数入荷・取消済みでない発注を取り消します。一部入荷の発注の入荷済みの在庫は変わりません。", Response: PurchaseOrderResponse{}},
+	"GET /api/v1/purchase-orders/{purchaseOrderId}/receipts": {Tag: "purchase-orders", Summary: "発注に対する入荷一覧を取得（入荷日時の古い順）", Response: GoodsReceiptListResponse{}},
+	"POST /api/v1/purchase-orders/{purchaseOrderId}/receipts": {
+		Tag:         "purchase-orders",
+		Summary:     "発注に対する入荷を記録",
+		Description: "行ごとに発注の入荷先へ入庫（inbound）し、仕入単価（省略した場合は発注単価）・ロット番号・有効期限と発注ID・入荷IDをトランザクションに記録します。同じ商品をロットごとに複数の行で入荷できます。全ての行を入荷すると received、それ以外は partially_received になります。送付済み・一部入荷でない発注は409（PURCHASE_ORDER_STATUS_CONFLICT）、発注残を超える場合は422（RECEIPT_EXCEEDS_ORDER）を返します。",
+		Request:     ReceivePurchaseOrderRequest{},
+		Response:    GoodsReceiptResponse{},
+	},
+
+	// 受注管理
+	"GET /api/v1/sales-orders": {
+		Tag:     "sales-orders",
+		Summary: "受注一覧を取得（受付日時の新しい順、行は含まない）",
+		Query: []openapi.Param{
+			{Name: "location_id", Description: "出荷元のロケーションIDで絞り込む"},
+			{Name: "reference", Description: "注文番号で絞り込む"},
+			{Name: "status", Description: "状態で絞り込む", Enum: []string{string(inventory.SalesOrderStatusOpen), string(inventory.SalesOrderStatusAllocated), string(inventory.SalesOrderStatusShipped), string(inventory.SalesOrderStatusCancelled)}},
+			{Name: "limit", Type: "integer", Description: "取得件数の上限（デフォルト20、最大100）"},
+			{Name: "offset", Type: "integer", Description: "取得開始位置"},
+		},
+		Response: SalesOrderListResponse{},
+	},
+	"POST /api/v1/sales-orders": {
+		Tag:         "sales-orders",
+		Summary:     "受注を作成",
+		Description: "受付済み（open）の受注を作成します。在庫は引き当てません。reference を省略した場合は受注IDを設定します。",
+		Request:     CreateSalesOrderRequest{},
+		Response:    SalesOrderResponse{},
+	},
+	"GET /api/v1/sales-orders/{salesOrderId}": {Tag: "sales-orders", Summary: "受注を行とともに取得", Description: "存在しない受注の場合は404（SALES_ORDER_NOT_FOUND）を返します。", Response: inventory.SalesOrder{}},
+	"POST /api/v1/sales-orders/{salesOrderId}/allocate": {
+		Tag:         "sales-orders",
+		Summary:     "受注に在庫を引き当て",
+		Description: "各行の未引当の数量を出荷元の在庫から予約（参照番号は受注ID）します。利用可能数が不足する場合、allow_partial が false なら422（INSUFFICIENT_STOCK）を返して何も引き当てず、true なら利用可能な数量だけ引き当てます。全ての行を引き当てると allocated になります。本文は省略できます。",
+		Request:     AllocateSalesOrderRequest{},
+		Response:    SalesOrderResponse{},
+	},
+	"POST /api/v1/sales-orders/{salesOrderId}/ship": {
+		Tag:         "sales-orders",
+		Summary:     "受注の出荷を確定",
+		Description: "引当の予約を出庫（outbound、参照番号は注文番号、metadata.sales_order_id に受注ID）に変換し、出荷しなかった数量は欠品として予約を解除します。lines を省略した場合は引当済みの数量を全て出荷します。出荷数量が引当数量を超える場合は422（INSUFFICIENT_RESERVATION）、出荷確定済み・取消済みの受注は409（SALES_ORDER_STATUS_CONFLICT）を返します。",
+		Request:     ShipSalesOrderRequest{},
+		Response:    SalesOrderResponse{},
+	},
+	"POST /api/v1/sales-orders/{salesOrderId}/cancel":      {Tag: "sales-orders", Summary: "受注を取消", Description: "出荷確定していない受注を取り消し、引当の予約を解除します。", Response: SalesOrderResponse{}},
+	"GET /api/v1/sales-orders/{salesOrderId}/stock-status": {Tag: "sales-orders", Summary: "受注の在庫状況を取得", Description: "行ごとの引当・出荷・欠品・未引当の数量と出荷元の利用可能数、全ての行の引当済み（fully_allocated）と未引当の数量を現在引き当てられるか（fulfillable）を返します。", Response: inventory.SalesOrderStockStatus{}},
+
+	// 返品管理
+	"GET /api/v1/returns": {
+		Tag:     "returns",
+		Summary: "返品一覧を取得（受付日時の新しい順、行は含まない）",
+		Query: []openapi.Param{
+			{Name: "sales_order_id", Description: "返品元の受注IDで絞り込む"},
+			{Name: "location_id", Description: "返品を受け入れるロケーションIDで絞り込む"},
+			{Name: "status", Description: "状態で絞り込む", Enum: []string{string(inventory.ReturnStatusAuthorized), string(inventory.ReturnStatusReceived), string(inventory.ReturnStatusCompleted), string(inventory.ReturnStatusCancelled)}},
+			{Name: "limit", Type: "integer", Description: "取得件数の上限（デフォルト20、最大100）"},
+			{Name: "offset", Type: "integer", Description: "取得開始位置"},
+		},
+		Response: ReturnListResponse{},
+	},
+	"POST /api/v1/returns": {
+		Tag:         "returns",
+		Summary:     "返品（RMA）を作成",
+		Description: "返品受付済み（authorized）の返品を作成します。sales_order_id を指定した場合は出荷確定済みの受注の出荷数量まで返品できます。reference を省略した場合は返品IDを設定します。",
+		Request:     CreateReturnRequest{},
+		Response:    ReturnResponse{},
+	},
+	"GET /api/v1/returns/{returnId}": {Tag: "returns", Summary: "返品を行とともに取得", Description: "存在しない返品の場合は404（RETURN_NOT_FOUND）を返します。", Response: inventory.ReturnAuthorization{}},
+	"POST /api/v1/returns/{returnId}/receive": {
+		Tag:         "returns",
+		Summary:     "返品の到着を記録",
+		Description: "到着した数量を検品待ちとして記録します（在庫には含めません）。到着数量の合計が返品許可数量を超える場合は422（RETURN_QUANTITY_EXCEEDED）、返品受付済み以外の返品は409（RETURN_STATUS_CONFLICT）を返します。全ての行が到着すると received になります。",
+		Request:     ReceiveReturnRequest{},
+		Response:    ReturnResponse{},
+	},
+	"GET /api/v1/returns/{returnId}/dispositions": {Tag: "returns", Summary: "返品の処分一覧を取得（処分日時の古い順）", Response: ReturnDispositionListResponse{}},
+	"POST /api/v1/returns/{returnId}/dispositions": {
+		Tag:         "returns",
+		Summary:     "検品待ちの返品を処分",
+		Description: "restock は返品先のロケーションに、refurbish は target_item_id・location_id（省略時は返品の商品・返品先）に入庫（inbound、参照番号はRMA番号、metadata.return_id に返品ID）します。scrap は在庫を変更せず reason_code を記録します。処分数量が検品待ちの数量を超える場合は422（RETURN_QUANTITY_EXCEEDED）を返します。全ての行が到着して処分済みになると completed になります。",
+		Request:     DispositionReturnRequest{},
+		Response:    ReturnDispositionResponse{},
+	},
+	"POST /api/v1/returns/{returnId}/cancel": {Tag: "returns", Summary: "返品を取消", Description: "検品待ちの数量がない返品を取り消します（未到着の数量は受け付けません）。検品待ちの数量がある返品は409（RETURN_STATUS_CONFLICT）を返します。", Response: ReturnResponse{}},
+
+	// 委託在庫
+	"GET /api/v1/consignment-stocks": {
+		Tag:     "consignment",
+		Summary: "委託在庫一覧を取得（所有者ID・商品ID・ロケーションIDの順）",
+		Query: []openapi.Param{
+			{Name: "owner_id", Description: "所有者の仕入先IDで絞り込む"},
+			{Name: "item_id", Description: "商品IDで絞り込む"},
+			{Name: "location_id", Description: "ロケーションIDで絞り込む"},
+			{Name: "include_empty", Type: "boolean", Description: "数量0の委託在庫も含める"},
+			{Name: "limit", Type: "integer", Description: "取得件数の上限（デフォルト20、最大100）"},
+			{Name: "offset", Type: "integer", Description: "取得開始位置"},
+		},
+		Response: ConsignmentStockListResponse{},
+	},
+
+	// ラベル印刷
+	"POST /api/v1/labels": {
+		Tag:         "labels",
+		Summary:     "商品・ロット・ロケーションのラベルをPDFで出力",
+		Description: "targets の順に、テンプレートの配置でラベルを並べたPDFを返します。custom_template を指定した場合は template より優先します。バーコードには商品ID・ロットID・ロケーションIDを符号化します。存在しない対象は404、バーコードにできないIDやラベルに収まらないバーコードは422で、何も出力しません。照会のみのため inventory:read スコープで実行できます。",
+		Request:     LabelRequest{},
+		ContentType: "application/pdf",
+	},
+	"GET /api/v1/labels/templates": {Tag: "labels", Summary: "組み込みのラベルのテンプレート一覧を取得", Response: LabelTemplateListResponse{}},
+
+	// 在庫評価
+	"GET /api/v1/valuation/{itemId}/{locationId}": {Tag: "valuation", Summary: "在庫評価額を計算", Description: "仕入先所有の委託在庫は評価対象から除外します。", Query: []openapi.Param{valuationMethods}, Response: ValueResponse{}},
+	"GET /api/v1/valuation/total/{locationId}":    {Tag: "valuation", Summary: "ロケーションの在庫評価額合計を計算", Query: []openapi.Param{valuationMethods}, Response: TotalValueResponse{}},
+	"GET /api/v1/valuation/average-cost/{itemId}": {Tag: "valuation", Summary: "平均原価を取得", Response: AverageCostResponse{}},
+
+	// 在庫分析
+	"GET /api/v1/analytics/abc/{locationId}": {Tag: "analytics", Summary: "ABC分析", Response: ABCClassificationResponse{}},
+	"GET /api/v1/analytics/turnover/{itemId}": {
+		Tag:      "analytics",
+		Summary:  "在庫回転率を計算",
+		Query:    []openapi.Param{{Name: "period_days", Type: "integer", Description: "対象期間の日数（デフォルト30）"}},
+		Response: TurnoverRateResponse{},
+	},
+	"GET /api/v1/analytics/slow-moving/{locationId}": {
+		Tag:      "analytics",
+		Summary:  "滞留在庫を取得",
+		Query:    []openapi.Param{{Name: "threshold_days", Type: "integer", Description: "滞留とみなす日数（デフォルト90）"}},
+		Response: SlowMovingItemsResponse{},
+	},
+	"GET /api/v1/analytics/report/{locationId}": {
+		Tag:     "analytics",
+		Summary: "在庫レポートを生成",
+		Query: []openapi.Param{{Name: "type", Description: "レポートの種類（デフォルトstock）", Enum: []string{
+			string(inventory.ReportTypeStock),
+			string(inventory.ReportTypeMovement),
+			string(inventory.ReportTypeValuation),
+			string(inventory.ReportTypeABC),
+			string(inventory.ReportTypeTurnover),
+		}}},
+		ContentType: "application/octet-stream",
+	},
+	"GET /api/v1/summary": {
+		Tag:         "analytics",
+		Summary:     "在庫全体の集計を取得",
+		Description: "商品数・総在庫数・総評価額（在庫数×単価）・アクティブなアラート数と、ロケーション別の低在庫（数量が低在庫閾値以下）の商品数を返します。",
+		Response:    inventory.InventorySummary{},
+	},
+
+	// 在庫スナップショット
+	"GET /api/v1/snapshots": {
+		Tag:         "snapshots",
+		Summary:     "在庫スナップショット一覧を取得（取得日時の新しい順）",
+		Description: "定期ジョブ stock_snapshot が取得した全ての在庫記録のスナップショットを返します。",
+		Query: []openapi.Param{
+			{Name: "limit", Type: "integer", Description: "取得件数の上限（デフォルト20、最大100）"},
+			{Name: "offset", Type: "integer", Description: "取得開始位置"},
+		},
+		Response: StockSnapshotListResponse{},
+	},
+	"GET /api/v1/snapshots/{snapshotId}/stocks": {
+		Tag:         "snapshots",
+		Summary:     "在庫スナップショットの在庫記録を取得（商品ID・ロケーションIDの昇順）",
+		Description: "存在しないスナップショットの場合は404（SNAPSHOT_NOT_FOUND）を返します。",
+		Query:       []openapi.Param{{Name: "location_id", Description: "ロケーションIDで絞り込む"}},
+		Response:    StockSnapshotLinesResponse{},
+	},
+
+	// 棚卸
+	"POST /api/v1/stocktakes": {
+		Tag:         "stocktakes",
+		Summary:     "棚卸（集計表）を作成",
+		Description: "ロケーションの在庫記録を行とし、帳簿在庫数量に現在の在庫数量を設定します。abc_class を指定した場合はABC分析でそのクラスに分類した商品のみを対象にします。",
+		Request:     CreateStocktakeRequest{},
+		Response:    StocktakeResponse{},
+	},
+	"GET /api/v1/stocktakes": {
+		Tag:     "stocktakes",
+		Summary: "棚卸一覧を取得（作成日時の新しい順、行は含まない）",
+		Query: []openapi.Param{
+			{Name: "location_id", Description: "ロケーションIDで絞り込む"},
+			{Name: "status", Description: "状態で絞り込む", Enum: []string{string(inventory.StocktakeStatusOpen), string(inventory.StocktakeStatusSubmitted), string(inventory.StocktakeStatusApplied), string(inventory.StocktakeStatusCancelled)}},
+			{Name: "limit", Type: "integer", Description: "取得件数の上限（デフォルト20、最大100）"},
+			{Name: "offset", Type: "integer", Description: "取得開始位置"},
+		},
+		Response: StocktakeListResponse{},
+	},
+	"GET /api/v1/stocktakes/{stocktakeId}": {Tag: "stocktakes", Summary: "棚卸を行とともに取得", Description: "存在しない棚卸の場合は404（STOCKTAKE_NOT_FOUND）を返します。", Response: inventory.Stocktake{}},
+	"PUT /api/v1/stocktakes/{stocktakeId}/counts": {
+		Tag:         "stocktakes",
+		Summary:     "棚卸の実数を記録",
+		Description: "帳簿在庫数量を記録時点の在庫数量に更新し、差異（実数 - 帳簿在庫数量）を計算します。集計表にない商品は行を追加します。集計中（open）でない棚卸は409（STOCKTAKE_STATUS_CONFLICT）を返します。",
+		Request:     RecordStocktakeCountsRequest{},
+		Response:    StocktakeResponse{},
+	},
+	"POST /api/v1/stocktakes/{stocktakeId}/submit": {
+		Tag:         "stocktakes",
+		Summary:     "棚卸の差異を確定",
+		Description: "実数を記録した差異のある行ごとに棚卸差異（discrepancy）のアラートを作成します。集計中（open）でない棚卸は409（STOCKTAKE_STATUS_CONFLICT）を返します。",
+		Response:    StocktakeResponse{},
+	},
+	"POST /api/v1/stocktakes/{stocktakeId}/apply": {
+		Tag:         "stocktakes",
+		Summary:     "承認した棚卸差異を在庫に反映",
+		Description: "承認した行の差異を単一トランザクションで在庫数量に加算し、棚卸調整（stocktake）のトランザクションを記録します。本文は省略でき、item_ids を省略した場合は差異のある全ての行を承認します。反映した商品の棚卸差異アラートは解決します。差異確定済み（submitted）でない棚卸は409（STOCKTAKE_STATUS_CONFLICT）を返します。",
+		Request:     ApplyStocktakeRequest{},
+		Response:    StocktakeResponse{},
+	},
+	"POST /api/v1/stocktakes/{stocktakeId}/cancel": {Tag: "stocktakes", Summary: "棚卸を取消", Description: "集計中・差異確定済みの棚卸を在庫を調整せずに取り消します。", Response: StocktakeResponse{}},
+
+	// 在庫調整の承認
+	"GET /api/v1/adjustments": {
+		Tag:     "adjustments",
+		Summary: "在庫調整一覧を取得（申請日時の新しい順）",
+		Query: []openapi.Param{
+			{Name: "item_id", Description: "商品IDで絞り込む"},
+			{Name: "location_id", Description: "ロケーションIDで絞り込む"},
+			{Name: "status", Description: "状態で絞り込む", Enum: []string{string(inventory.AdjustmentStatusPending), string(inventory.AdjustmentStatusApplied), string(inventory.AdjustmentStatusRejected)}},
+			{Name: "reason_code", Description: "理由コードで絞り込む", Enum: []string{string(inventory.AdjustmentReasonDamage), string(inventory.AdjustmentReasonShrinkage), string(inventory.AdjustmentReasonCountCorrection), string(inventory.AdjustmentReasonExpired), string(inventory.AdjustmentReasonFound), string(inventory.AdjustmentReasonOther)}},
+			{Name: "limit", Type: "integer", Description: "取得件数の上限（デフォルト20、最大100）"},
+			{Name: "offset", Type: "integer", Description: "取得開始位置"},
+		},
+		Response: AdjustmentListResponse{},
+	},
+	"GET /api/v1/adjustments/{adjustmentId}": {Tag: "adjustments", Summary: "在庫調整を取得", Description: "存在しない在庫調整の場合は404（ADJUSTMENT_NOT_FOUND）を返します。", Response: inventory.Adjustment{}},
+	"POST /api/v1/admin/adjustments/{adjustmentId}/approve": {
+		Tag:         "adjustments",
+		Summary:     "承認待ちの在庫調整を承認",
+		Description: "申請時の調整数量を現在の在庫数量に加算し、理由コードを記録した調整（adjust）のトランザクションを記録します。本文は省略できます。承認待ち（pending）でない在庫調整は409（ADJUSTMENT_NOT_PENDING）を返します。",
+		Request:     AdjustmentDecisionRequest{},
+		Response:    AdjustmentResponse{},
+	},
+	"POST /api/v1/admin/adjustments/{adjustmentId}/reject": {
+		Tag:         "adjustments",
+		Summary:     "承認待ちの在庫調整を却下",
+		Description: "在庫を変更せずに却下します。本文は省略できます。承認待ち（pending）でない在庫調整は409（ADJUSTMENT_NOT_PENDING）を返します。",
+		Request:     AdjustmentDecisionRequest{},
+		Response:    AdjustmentResponse{},
+	},
+
+	// 入荷検品
+	"POST /api/v1/inspections": {
+		Tag:         "inspections",
+		Summary:     "入荷を検品待ち（隔離）として記録",
+		Description: "在庫は変更せず、検品待ちの数量は利用可能数・引当の対象になりません。",
+		Request:     ReceiveForInspectionRequest{},
+		Response:    InspectionResponse{},
+	},
+	"GET /api/v1/inspections": {
+		Tag:     "inspections",
+		Summary: "入荷検品一覧を取得（入荷日時の新しい順）",
+		Query: []openapi.Param{
+			{Name: "item_id", Description: "商品IDで絞り込む"},
+			{Name: "location_id", Description: "ロケーションIDで絞り込む"},
+			{Name: "status", Description: "状態で絞り込む", Enum: []string{string(inventory.InspectionStatusQuarantined), string(inventory.InspectionStatusPassed), string(inventory.InspectionStatusFailed)}},
+			{Name: "limit", Type: "integer", Description: "取得件数の上限（デフォルト20、最大100）"},
+			{Name: "offset", Type: "integer", Description: "取得開始位置"},
+		},
+		Response: InspectionListResponse{},
+	},
+	"GET /api/v1/inspections/{inspectionId}": {Tag: "inspections", Summary: "入荷検品を取得", Description: "存在しない入荷検品の場合は404（INSPECTION_NOT_FOUND）を返します。", Response: inventory.Inspection{}},
+	"POST /api/v1/inspections/{inspectionId}/pass": {
+		Tag:         "inspections",
+		Summary:     "入荷検品を合格にして在庫に入庫",
+		Description: "入荷数量を入庫（inbound）のトランザクションで在庫に加算し、metadata.inspection_id に検品IDを記録します。本文（{\"note\"}）は省略できます。検品待ち（quarantined）でない入荷検品は409（INSPECTION_NOT_QUARANTINED）を返します。",
+		Request:     InspectionDispositionRequest{},
+		Response:    InspectionResponse{},
+	},
+	"POST /api/v1/inspections/{inspectionId}/fail": {
+		Tag:         "inspections",
+		Summary:     "入荷検品を不合格にして廃棄",
+		Description: "理由コード（reason_code）を記録して廃棄します。在庫は変更しません。検品待ち（quarantined）でない入荷検品は409（INSPECTION_NOT_QUARANTINED）を返します。",
+		Request:     InspectionDispositionRequest{},
+		Response:    InspectionResponse{},
+	},
+
+	// 引当
+	"POST /api/v1/allocations": {
+		Tag:         "allocations",
+		Summary:     "商品を複数のロケーションから引当",
+		Description: "利用可能数のあるアクティブなロケーションを方式（strategy）で順位付けし、上位から順に数量を割り当ててロケーションごとに予約を作成します。priority はロケーションの優先順位、proximity は配送先（destination）からの距離、stock_level は利用可能数の多い順です。不足する場合は allow_partial が false なら422（INSUFFICIENT_STOCK）を返します。",
+		Query:       []openapi.Param{{Name: "dry_run", Type: "boolean", Description: "trueの場合は予約を作成せず、引当の計画のみ返す"}},
+		Request:     AllocateRequest{},
+		Response:    AllocationResponse{},
+	},
+
+	// Webhook
+	"POST /api/v1/admin/webhooks":                       {Tag: "webhooks", Summary: "Webhookを作成", Request: WebhookRequest{}, Response: WebhookResponse{}},
+	"GET /api/v1/admin/webhooks":                        {Tag: "webhooks", Summary: "Webhook一覧を取得", Response: WebhookListResponse{}},
+	"GET /api/v1/admin/webhooks/{webhookId}":            {Tag: "webhooks", Summary: "Webhookを取得", Response: webhooks.Subscription{}},
+	"PUT /api/v1/admin/webhooks/{webhookId}":            {Tag: "webhooks", Summary: "Webhookを更新", Request: WebhookRequest{}, Response: WebhookResponse{}},
+	"DELETE /api/v1/admin/webhooks/{webhookId}":         {Tag: "webhooks", Summary: "Webhookを削除", Response: MessageResponse{}},
+	"GET /api/v1/admin/webhooks/{webhookId}/deliveries": {Tag: "webhooks", Summary: "Webhookの配信ログを取得", Query: []openapi.Param{limitParam}, Response: WebhookDeliveryListResponse{}},
+
+	// 管理
+	"GET /api/v1/admin/events/dead-letters": {
+		Tag:      "admin",
+		Summary:  "デッドレターのイベント一覧を取得",
+		Query:    []openapi.Param{{Name: "limit", Type: "integer", Description: "取得件数の上限（デフォルト50、最大500）"}},
+		Response: DeadLetterListResponse{},
+	},
+	"POST /api/v1/admin/events/dead-letters/{eventId}/replay": {Tag: "admin", Summary: "デッドレターのイベントを再発行", Response: MessageResponse{}},
+	"DELETE /api/v1/admin/events/dead-letters/{eventId}":      {Tag: "admin", Summary: "デッドレターのイベントを削除", Response: MessageResponse{}},
+	"POST /api/v1/admin/events/replay":                        {Tag: "admin", Summary: "台帳からイベントを再生", Request: ReplayEventsRequest{}, Response: inventory.ReplayResult{}},
+	"POST /api/v1/admin/stock/consistency":                    {Tag: "admin", Summary: "在庫整合性チェック", Request: CheckStockConsistencyRequest{}, Response: inventory.ConsistencyReport{}},
+	"GET /api/v1/admin/jobs": {
+		Tag:         "admin",
+		Summary:     "定期ジョブの実行状況を取得",
+		Description: "スケジュール・次回の実行予定日時・前回の実行結果をジョブの登録順に返します。定期ジョブを実行しないプロセス（scheduler.enabled: false）では501を返します。",
+		Response:    JobListResponse{},
+	},
+	"POST /api/v1/admin/jobs/{name}/run": {
+		Tag:         "admin",
+		Summary:     "定期ジョブを今すぐ実行",
+		Description: "スケジュールを変更せずにジョブを実行し、完了を待たずに202を返します。結果は GET /api/v1/admin/jobs で確認します。実行中のジョブの場合は409を返します。",
+		Response:    RunJobResponse{},
+	},
+	"POST /api/v1/admin/api-keys": {
+		Tag:         "admin",
+		Summary:     "APIキーを発行",
+		Description: "平文のキーはこのレスポンスでのみ返却されます。",
+		Request:     APIKeyRequest{},
+		Response:    APIKeyIssuedResponse{},
+	},
+	"GET /api/v1/admin/api-keys":         {Tag: "admin", Summary: "APIキー一覧を取得", Response: APIKeyListResponse{}},
+	"GET /api/v1/admin/api-keys/{keyId}": {Tag: "admin", Summary: "APIキーを取得", Response: auth.APIKey{}},
+	"POST /api/v1/admin/api-keys/{keyId}/rotate": {
+		Tag:         "admin",
+		Summary:     "APIキーをローテーション",
+		Description: "同じ名前・スコープの新しいキーを発行します。旧キーは grace_period の間は引き続き使用できます。",
+		Request:     RotateAPIKeyRequest{},
+		Response:    APIKeyIssuedResponse{},
+	},
+	"DELETE /api/v1/admin/api-keys/{keyId}": {Tag: "admin", Summary: "APIキーを無効化", Response: MessageResponse{}},
+
+	// 外部システム連携・ライブ配信
+	"POST /api/v1/sync/stock": {
+		Tag:         "sync",
+		Summary:     "外部システムからの在庫同期",
+		Description: "Idempotency-Key ヘッダーを指定した場合は本文の idempotency_key より優先されます。",
+		Request:     consumer.Message{},
+		Response:    SyncStockResponse{},
+	},
+	"GET /api/v1/ws/stock": {
+		Tag:         "live",
+		Summary:     "在庫変更をWebSocketで配信",
+		Description: "WebSocketにアップグレードし、StockChangedEvent をJSONのテキストメッセージで送信します。",
+		Query:       []openapi.Param{locationIDParam},
+	},
+
+	// ドキュメント
+	"GET /openapi.json": {Tag: "system", Summary: "OpenAPIドキュメント", ContentType: "application/json", Public: true},
+}
+
+// graphQLSpecs describes the GraphQL routes registered when GraphQL is enabled
+// GraphQLが有効な場合に登録するルートの説明
+var graphQLSpecs = map[string]openapi.Spec{
+	"GET /api/v1/graphql": {
+		Tag:         "graphql",
+		Summary:     "GraphQLクエリを実行",
+		Description: "レスポンスは封筒形式ではなくGraphQLのレスポンス形式です。",
+		Query: []openapi.Param{
+			{Name: "query", Required: true, Description: "GraphQLクエリ"},
+			{Name: "operationName", Description: "実行する操作の名前"},
+			{Name: "variables", Description: "変数（JSON）"},
+		},
+		ContentType: "application/json",
+	},
+	"POST /api/v1/graphql": {
+		Tag:         "graphql",
+		Summary:     "GraphQLクエリを実行",
+		Description: "レスポンスは封筒形式ではなくGraphQLのレスポンス形式です。",
+		Request:     GraphQLRequest{},
+		ContentType: "application/json",
+	},
+	"GET /api/v1/graphql/schema": {Tag: "graphql", Summary: "GraphQLスキーマ（SDL）", ContentType: "text/plain"},
+}
+
+// buildOpenAPI generates the OpenAPI document of the routes registered on router
+// ルーターに登録したルートのOpenAPIドキュメントを生成
+func (h *Handlers) buildOpenAPI(router *mux.Router) (*openapi.Document, error) {
+	specs := make(map[string]openapi.Spec, len(apiSpecs)+len(graphQLSpecs))
+	for key, spec := range apiSpecs {
+		specs[key] = spec
+	}
+	if h.graphql != nil {
+		for key, spec := range graphQLSpecs {
+			specs[key] = spec
+		}
+	}
+	if h.debug {
+		for key, spec := range debugSpecs {
+			specs[key] = spec
+		}
+	}
+	// 説明は v1 のパスで記述し、各バージョンに同じ説明を適用する
+	versioned := make(map[string]openapi.Spec)
+	for key, spec := range specs {
+		if strings.Contains(key, " /api/v1/") {
+			versioned[key] = spec
+		}
+	}
+	for _, version := range h.apiVersions() {
+		for key, spec := range versioned {
+			spec.Deprecated = !version.deprecation.IsZero()
+			specs[strings.Replace(key, " /api/v1/", " /api/"+version.name+"/", 1)] = spec
+		}
+	}
+	if h.swaggerUI {
+		specs["GET /docs"] = openapi.Spec{Tag: "system", Summary: "Swagger UI", ContentType: "text/html", Public: true}
+	}
+
+	info := openapi.Info{
+		Title:       "zaiGoFramework 在庫管理API",
+		Description: "成功時は {\"success\": true, \"data\": ...}、失敗時は {\"success\": false, \"error\": ...} の形式で応答します。",
+		Version:     "1.0.0",
+	}
+	doc, err := openapi.Build(info, router, specs, openapi.Envelope{
+		Data: func(data *openapi.Schema) *openapi.Schema {
+			envelope := &openapi.Schema{
+				Type:       "object",
+				Properties: map[string]*openapi.Schema{"success": {Type: "boolean"}},
+				Required:   []string{"success"},
+			}
+			if data != nil {
+				envelope.Properties["data"] = data
+			}
+			return envelope
+		},
+		ErrorSchema: ErrorResponse{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if h.authenticator != nil {
+		doc.Components.SecuritySchemes = map[string]*openapi.SecurityScheme{
+			"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+		}
+		doc.Security = []openapi.SecurityRequirement{{"bearerAuth": {}}}
+		if h.apiKeys != nil {
+			doc.Components.SecuritySchemes["apiKeyAuth"] = &openapi.SecurityScheme{
+				Type:        "apiKey",
+				In:          "header",
+				Name:        auth.APIKeyHeader,
+				Description: "システム間連携用のAPIキー（照会は inventory:read、更新は inventory:write、/api/v1/admin/ は inventory:admin が必要）",
+			}
+			doc.Security = append(doc.Security, openapi.SecurityRequirement{"apiKeyAuth": {}})
+		}
+	}
+	return doc, nil
+}
+
+// mountOpenAPI registers /openapi.json and, when enabled, the Swagger UI at /docs
+// /openapi.json と、有効な場合は /docs のSwagger UIを登録
+//
+// ドキュメントは全てのルートを登録した後に生成するため、setupRouter の最後に呼び出してください。
+func (h *Handlers) mountOpenAPI(router *mux.Router) {
+	var spec []byte
+	router.HandleFunc("/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		if spec == nil {
+			h.sendError(w, http.StatusInternalServerError, "OpenAPIドキュメントを生成できませんでした")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(spec)
+	}).Methods("GET")
+	if h.swaggerUI {
+		router.HandleFunc("/docs", serveSwaggerUI).Methods("GET")
+	}
+
+	doc, err := h.buildOpenAPI(router)
+	if err != nil {
+		h.logger.Error("OpenAPIドキュメントの生成に失敗しました", zap.Error(err))
+		return
+	}
+	spec, err = json.Marshal(doc)
+	if err != nil {
+		h.logger.Error("OpenAPIドキュメントのJSON変換に失敗しました", zap.Error(err))
+		spec = nil
+	}
+}
+
+// serveSwaggerUI serves a Swagger UI page that loads /openapi.json
+// /openapi.json を表示するSwagger UIのページを返す
+//
+// swagger-ui-dist はCDN（unpkg）から読み込むため、ブラウザからインターネットに接続できる必要があります。
+func serveSwaggerUI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, `<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+<title>zaiGoFramework API</title>
+<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@%[1]s/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist@%[1]s/swagger-ui-bundle.js"></script>
+<script>
+window.ui = SwaggerUIBundle({url: "/openapi.json", dom_id: "#swagger-ui"});
+</script>
+</body>
+</html>
+`, swaggerUIVersion)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/internal/config"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/auth"
+)
+
+// TestRateLimiter_ClientIP はX-Forwarded-Forからのクライアントの特定のテスト
+func TestRateLimiter_ClientIP(t *testing.T) {
+	tests := []struct {
+		name      string
+		trust     bool
+		proxies   int
+		forwarded []string
+		expected  string
+	}{
+		{name: "信頼しない場合は接続元", forwarded: []string{"203.0.113.1"}, expected: "192.0.2.10"},
+		{name: "プロキシ1段は末尾のアドレス", trust: true, proxies: 1, forwarded: []string{"198.51.100.7"}, expected: "198.51.100.7"},
+		{name: "クライアントが先頭に付けたアドレスは使用しない", trust: true, proxies: 1, forwarded: []string{"203.0.113.1, 198.51.100.7"}, expected: "198.51.100.7"},
+		{name: "プロキシ2段は末尾から2番目", trust: true, proxies: 2, forwarded: []string{"203.0.113.1, 198.51.100.7, 10.0.0.2"}, expected: "198.51.100.7"},
+		{name: "複数のヘッダーは連結して数える", trust: true, proxies: 2, forwarded: []string{"203.0.113.1", "198.51.100.7", "10.0.0.2"}, expected: "198.51.100.7"},
+		{name: "アドレスが段数より少ない場合は接続元", trust: true, proxies: 2, forwarded: []string{"198.51.100.7"}, expected: "192.0.2.10"},
+		{name: "ヘッダーがない場合は接続元", trust: true, proxies: 1, expected: "192.0.2.10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := &rateLimiter{trustForwardedFor: tt.trust, trustedProxies: tt.proxies}
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/items", nil)
+			req.RemoteAddr = "192.0.2.10:54321"
+			for _, forwarded := range tt.forwarded {
+				req.Header.Add("X-Forwarded-For", forwarded)
+			}
+			assert.Equal(t, tt.expected, limiter.clientIP(req))
+			assert.Equal(t, "ip:"+tt.expected, limiter.clientKey(req))
+		})
+	}
+}
+
+// TestIPRateLimitMiddleware_BeforeAuth は認証に失敗するリクエストもIPアドレスごとに制限されることのテスト
+func TestIPRateLimitMiddleware_BeforeAuth(t *testing.T) {
+	verifier, err := auth.NewJWTVerifier(auth.JWTConfig{HMACSecret: testJWTSecret})
+	require.NoError(t, err)
+
+	h := NewHandlers(nil, zap.NewNop())
+	h.authenticator = verifier
+	h.rateLimit = newRateLimiter(config.RateLimitConfig{
+		Enabled:             true,
+		RequestsPerSecond:   100,
+		Burst:               100,
+		TrustForwardedFor:   true,
+		TrustedProxies:      1,
+		IPRequestsPerSecond: 0.001,
+		IPBurst:             3,
+		IdleTTL:             time.Minute,
+	})
+	handler := h.ipRateLimitMiddleware(h.authMiddleware(h.rateLimitMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))))
+
+	send := func(forwarded string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/items", nil)
+		req.RemoteAddr = "10.0.0.2:443"
+		req.Header.Set("Authorization", "Bearer invalid")
+		// 先頭のアドレスを毎回変えても制限を回避できない
+		req.Header.Set("X-Forwarded-For", forwarded+", 198.51.100.7")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	for i, forwarded := range []string{"203.0.113.1", "203.0.113.2", "203.0.113.3"} {
+		assert.Equal(t, http.StatusUnauthorized, send(forwarded), "request %d", i)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, send("203.0.113.4"))
+
+	// 別のクライアントは制限されない
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/items", nil)
+	req.Header.Set("Authorization", "Bearer invalid")
+	req.Header.Set("X-Forwarded-For", "198.51.100.8")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// requestIDHeader is the header that carries the request ID
+// リクエストIDを伝えるヘッダー
+const requestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength is the longest request ID accepted from callers
+// 呼び出し元から受け付けるリクエストIDの最大長
+const maxRequestIDLength = 128
+
+// validRequestID reports whether a caller-supplied request ID can be logged and stored as is
+// 呼び出し元が指定したリクエストIDをそのままログ・履歴に記録できるかを判定
+//
+// ログの改ざんを防ぐため、英数字と - _ . : のみを受け付けます。
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// requestIDMiddleware assigns each request an ID and returns it in the X-Request-ID header
+// リクエストごとにIDを割り当て、X-Request-ID ヘッダーで返すミドルウェア
+//
+// 呼び出し元が有効な X-Request-ID を指定した場合はそれを使用し、ない場合は UUID を生成します。
+// IDはコンテキスト（inventory.WithRequestID）でマネージャーに渡され、ログと在庫移動の履歴の
+// メタデータ（request_id）に記録されます。
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+		w.Header().Set(requestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(inventory.WithRequestID(r.Context(), id)))
+	})
+}
+
+// responseRequestID returns the request ID assigned to the response
+// レスポンスに割り当てたリクエストIDを返す
+func responseRequestID(w http.ResponseWriter) string {
+	return w.Header().Get(requestIDHeader)
+}
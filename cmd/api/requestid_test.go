@@ -0,0 +1,50 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var seen string
+	handler := requestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = inventory.RequestIDFromContext(r.Context())
+	}))
+
+	tests := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{name: "呼び出し元のIDを使用", header: "req-20240101.abc:1", expected: "req-20240101.abc:1"},
+		{name: "未指定の場合は生成"},
+		{name: "改行を含む場合は生成", header: "abc\nfake log line"},
+		{name: "長すぎる場合は生成", header: strings.Repeat("a", maxRequestIDLength+1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/items", nil)
+			if tt.header != "" {
+				req.Header.Set(requestIDHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			// レスポンスヘッダーとコンテキストのIDは一致する
+			id := rec.Header().Get(requestIDHeader)
+			assert.Equal(t, id, seen)
+			if tt.expected != "" {
+				assert.Equal(t, tt.expected, id)
+			} else {
+				assert.Len(t, id, 36)
+				assert.NotEqual(t, tt.header, id)
+			}
+		})
+	}
+}
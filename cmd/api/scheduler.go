@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/internal/config"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/scheduler"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/storage"
+)
+
+// schedulerUserID is the user recorded on the changes made by scheduled jobs
+// 定期ジョブによる変更に記録するユーザー
+const schedulerUserID = "scheduler"
+
+// transactionPartitionMonths is the number of monthly transaction partitions kept ahead, including the current month
+// 事前に作成しておくトランザクションの月次パーティションの数（今月を含む）
+const transactionPartitionMonths = 4
+
+// newScheduler registers the periodic jobs of the configuration (jobs with an empty schedule are skipped)
+// 設定に従って定期ジョブを登録したスケジューラーを作成（スケジュールが空のジョブは登録しない）
+func newScheduler(cfg *config.Config, manager *inventory.Manager, db *storage.PostgreSQLStorage, logger *zap.Logger) (*scheduler.Scheduler, error) {
+	reservationExpiry := cfg.Inventory.ReservationExpiryInterval
+	if reservationExpiry <= 0 {
+		reservationExpiry = time.Minute
+	}
+	alertRules := cfg.Inventory.AlertRuleInterval
+	if alertRules <= 0 {
+		alertRules = 5 * time.Minute
+	}
+	expiryWindow := cfg.Scheduler.ExpiryWindow
+
+	jobs := []struct {
+		name string
+		spec string
+		run  scheduler.JobFunc
+	}{
+		// 有効期限を過ぎた予約を期限切れにし、確保した在庫を解放する
+		{"reservation_expiry", fmt.Sprintf("@every %s", reservationExpiry), manager.ExpireReservations},
+		// 在庫の変更を伴わないアラートルールの条件を検出する
+		{"alert_rules", fmt.Sprintf("@every %s", alertRules), manager.EvaluateAlertRules},
+		{"expiry_scan", cfg.Scheduler.ExpiryScan, func(ctx context.Context) (int, error) {
+			return manager.NotifyExpiringLots(ctx, expiryWindow)
+		}},
+		{"low_stock_sweep", cfg.Scheduler.LowStockSweep, manager.SweepLowStock},
+		{"alert_timeout", cfg.Scheduler.AlertTimeout, manager.ResolveTimedOutAlerts},
+		{"stock_snapshot", cfg.Scheduler.StockSnapshot, func(ctx context.Context) (int, error) {
+			snapshot, err := manager.TakeStockSnapshot(ctx)
+			if err != nil {
+				return 0, err
+			}
+			return snapshot.StockCount, nil
+		}},
+		// 長期間再起動しなくても先の月のパーティションを作成し、デフォルトパーティションに入った行を検出する
+		{"transaction_partitions", cfg.Scheduler.TransactionPartitions, func(ctx context.Context) (int, error) {
+			if err := db.EnsureTransactionPartitions(ctx, time.Now(), transactionPartitionMonths); err != nil {
+				return 0, err
+			}
+			return transactionPartitionMonths, nil
+		}},
+	}
+
+	s := scheduler.New(logger)
+	for _, j := range jobs {
+		if j.spec == "" {
+			continue
+		}
+		run := j.run
+		if err := s.Add(j.name, j.spec, func(ctx context.Context) (int, error) {
+			return run(context.WithValue(ctx, "user_id", schedulerUserID))
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
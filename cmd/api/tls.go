@@ -0,0 +1,64 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/nemonet1337/zaiGoFramework/internal/config"
+)
+
+// tlsVersions maps the configured minimum version to the crypto/tls constant
+// 設定の最小バージョンと crypto/tls の定数の対応
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// newTLSConfig builds the server TLS configuration from the API settings
+// API設定からサーバーのTLS設定を作成
+//
+// TLS 1.2 では前方秘匿性のある AEAD の暗号スイートのみを使用します（TLS 1.3 の暗号スイートは固定）。
+// クライアントCAを指定した場合は、そのCAが発行したクライアント証明書を要求します。
+func newTLSConfig(cfg config.TLSConfig) (*tls.Config, error) {
+	minVersion, ok := tlsVersions[cfg.MinVersion]
+	if !ok {
+		return nil, fmt.Errorf("無効なTLSの最小バージョン: %s", cfg.MinVersion)
+	}
+
+	certificate, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("サーバー証明書の読み込みに失敗しました: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates:     []tls.Certificate{certificate},
+		MinVersion:       minVersion,
+		CurvePreferences: []tls.CurveID{tls.X25519, tls.CurveP256},
+		CipherSuites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+		},
+	}
+
+	if cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("クライアントCAの読み込みに失敗しました: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("クライアントCAのファイルに証明書が含まれていません")
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nemonet1337/zaiGoFramework/internal/config"
+)
+
+// testCertificate は署名元（nilの場合は自己署名）で発行した証明書と秘密鍵
+type testCertificate struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+func issueCertificate(t *testing.T, name string, isCA bool, parent *testCertificate) *testCertificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCertificate{cert: cert, key: key, der: der}
+}
+
+// writePEM は証明書と秘密鍵をPEMファイルに書き出してパスを返す
+func (c *testCertificate) writePEM(t *testing.T, dir, name string) (certFile, keyFile string) {
+	t.Helper()
+	certFile = filepath.Join(dir, name+".crt")
+	keyFile = filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der}), 0o600))
+	keyDER, err := x509.MarshalECPrivateKey(c.key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+func (c *testCertificate) tlsCertificate() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.der}, PrivateKey: c.key}
+}
+
+func TestNewTLSConfigClientCertificate(t *testing.T) {
+	dir := t.TempDir()
+	ca := issueCertificate(t, "test-ca", true, nil)
+	caFile, _ := ca.writePEM(t, dir, "ca")
+	server := issueCertificate(t, "127.0.0.1", false, ca)
+	certFile, keyFile := server.writePEM(t, dir, "server")
+	client := issueCertificate(t, "warehouse-client", false, ca)
+
+	tlsConfig, err := newTLSConfig(config.TLSConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile, MinVersion: "1.2"})
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), tlsConfig.MinVersion)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	srv.TLS = tlsConfig
+	srv.StartTLS()
+	defer srv.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	newClient := func(certificates ...tls.Certificate) *http.Client {
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots, Certificates: certificates}}}
+	}
+
+	// クライアント証明書がない場合はハンドシェイクで拒否する
+	_, err = newClient().Get(srv.URL)
+	assert.Error(t, err)
+
+	resp, err := newClient(client.tlsCertificate()).Get(srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "warehouse-client", string(body))
+}
+
+func TestNewTLSConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := issueCertificate(t, "127.0.0.1", false, nil).writePEM(t, dir, "server")
+	notCA := filepath.Join(dir, "empty.pem")
+	require.NoError(t, os.WriteFile(notCA, []byte("not a certificate"), 0o600))
+
+	_, err := newTLSConfig(config.TLSConfig{CertFile: certFile, KeyFile: keyFile, MinVersion: "1.0"})
+	assert.Error(t, err)
+	_, err = newTLSConfig(config.TLSConfig{CertFile: filepath.Join(dir, "missing.crt"), KeyFile: keyFile, MinVersion: "1.2"})
+	assert.Error(t, err)
+	_, err = newTLSConfig(config.TLSConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: notCA, MinVersion: "1.3"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/zipkin"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+
+	"github.com/nemonet1337/zaiGoFramework/internal/config"
+)
+
+// setupTracing installs the global TracerProvider that sends spans to the configured endpoint
+// 設定された送信先へスパンを送信するグローバルのTracerProviderを設定
+//
+// 呼び出し元の traceparent ヘッダーを引き継ぐため、W3C Trace Context のプロパゲーターも設定します。
+// 返す関数はシャットダウン時に未送信のスパンを送信します。
+func setupTracing(cfg config.TracingConfig) (func(context.Context) error, error) {
+	exporter, err := zipkin.New(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("トレースの送信先の初期化に失敗しました: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(cfg.ServiceName))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return provider.Shutdown, nil
+}
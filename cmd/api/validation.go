@@ -0,0 +1,432 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/nemonet1337/zaiGoFramework/internal/label"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// pathValidators validate the path variables of the routes
+// ルートのパス変数のバリデーション
+var pathValidators = map[string]func(string) error{
+	"itemId":     inventory.ValidateItemID,
+	"locationId": inventory.ValidateLocationID,
+	"unitCode":   inventory.ValidateUnitCode,
+	"uom":        inventory.ValidateUnitCode,
+	"supplierId": inventory.ValidateSupplierID,
+}
+
+// validationFailedMessage is the message of responses with field-level validation errors
+// 項目ごとのバリデーションエラーを返すレスポンスのメッセージ
+const validationFailedMessage = "入力内容に誤りがあります"
+
+func init() {
+	inventory.RegisterMessages(inventory.LanguageEnglish, map[string]string{
+		validationFailedMessage: "the request has invalid fields",
+	})
+}
+
+// validatePathMiddleware rejects requests whose item or location ID in the path is malformed
+// パスの商品ID・ロケーションIDの形式が正しくないリクエストを422で拒否するミドルウェア
+//
+// 形式の正しくないIDがデータベースに到達しないよう、ハンドラーの前に検証します。
+func (h *Handlers) validatePathMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		var errs []error
+		for name, validate := range pathValidators {
+			if value, ok := vars[name]; ok {
+				errs = append(errs, validate(value))
+			}
+		}
+		if !h.validateRequest(w, errs...) {
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validateRequest sends 422 with the field-level errors when any of errs is not nil
+// いずれかのバリデーションエラーがある場合に項目ごとのエラーを422で送信
+//
+// エラーがない場合は true を返します。
+func (h *Handlers) validateRequest(w http.ResponseWriter, errs ...error) bool {
+	fieldErrors := make([]inventory.ValidationError, 0)
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		var validationErr *inventory.ValidationError
+		if !errors.As(err, &validationErr) {
+			validationErr = inventory.NewValidationError("", err.Error(), "")
+		}
+		fieldErrors = append(fieldErrors, *validationErr)
+	}
+	if len(fieldErrors) == 0 {
+		return true
+	}
+
+	lang := responseLanguage(w)
+	for i := range fieldErrors {
+		fieldErrors[i].Message = inventory.Translate(fieldErrors[i].Message, lang)
+	}
+	h.sendErrorCodeWithData(w, http.StatusUnprocessableEntity, ErrorCodeValidationFailed, validationFailedMessage, map[string]interface{}{
+		"errors": fieldErrors,
+	})
+	return false
+}
+
+// validatePositiveQuantity validates the quantity of add, remove, transfer and reserve operations
+// 追加・削除・移動・予約の数量をバリデーション
+func validatePositiveQuantity(quantity int64) error {
+	if quantity <= 0 {
+		return inventory.NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", quantity))
+	}
+	return inventory.ValidateQuantity(quantity, false)
+}
+
+// validateDecimalQuantity 小数を含む数量が正の値であることを確認（桁数と範囲は単位が決まってから確認する）
+func validateDecimalQuantity(quantity float64) error {
+	if quantity <= 0 {
+		return inventory.NewValidationError("quantity", "数量は正の値である必要があります", strconv.FormatFloat(quantity, 'f', -1, 64))
+	}
+	return nil
+}
+
+// withFieldPrefix prefixes the field of a validation error (e.g. operations[0].item_id)
+// バリデーションエラーの項目名に接頭辞を付ける（例: operations[0].item_id）
+func withFieldPrefix(prefix string, err error) error {
+	var validationErr *inventory.ValidationError
+	if err == nil || !errors.As(err, &validationErr) {
+		return err
+	}
+	return inventory.NewValidationError(prefix+"."+validationErr.Field, validationErr.Message, validationErr.Value)
+}
+
+func (req AddStockRequest) validate() []error {
+	errs := []error{
+		inventory.ValidateItemID(req.ItemID),
+		inventory.ValidateLocationID(req.LocationID),
+		validateDecimalQuantity(req.Quantity),
+		inventory.ValidateReference(req.Reference),
+	}
+	if req.LotNumber != "" {
+		errs = append(errs, inventory.ValidateLotNumber(req.LotNumber))
+	}
+	if req.UoM != "" {
+		errs = append(errs, inventory.ValidateUnitCode(req.UoM))
+	}
+	return errs
+}
+
+func (req RemoveStockRequest) validate() []error {
+	errs := []error{
+		inventory.ValidateItemID(req.ItemID),
+		inventory.ValidateLocationID(req.LocationID),
+		validateDecimalQuantity(req.Quantity),
+		inventory.ValidateReference(req.Reference),
+	}
+	if req.LotStrategy != "" {
+		errs = append(errs, inventory.ValidateLotPickingStrategy(req.LotStrategy))
+	}
+	if req.LotNumber != "" {
+		errs = append(errs, inventory.ValidateLotNumber(req.LotNumber))
+		if req.PickLots || req.LotStrategy != "" {
+			errs = append(errs, inventory.NewValidationError("lot_number", "ロット番号とロットの消費順序は同時に指定できません", req.LotNumber))
+		}
+	}
+	if req.UoM != "" {
+		errs = append(errs, inventory.ValidateUnitCode(req.UoM))
+	}
+	return errs
+}
+
+func (req TransferStockRequest) validate() []error {
+	errs := []error{
+		inventory.ValidateItemID(req.ItemID),
+		withFieldPrefix("from", inventory.ValidateLocationID(req.FromLocationID)),
+		withFieldPrefix("to", inventory.ValidateLocationID(req.ToLocationID)),
+		validateDecimalQuantity(req.Quantity),
+		inventory.ValidateReference(req.Reference),
+	}
+	if req.FromLocationID != "" && req.FromLocationID == req.ToLocationID {
+		errs = append(errs, inventory.NewValidationError("to_location_id", "移動元と移動先が同じです", req.ToLocationID))
+	}
+	if req.LotNumber != "" {
+		errs = append(errs, inventory.ValidateLotNumber(req.LotNumber))
+	}
+	if req.UoM != "" {
+		errs = append(errs, inventory.ValidateUnitCode(req.UoM))
+	}
+	return errs
+}
+
+func (req AdjustStockRequest) validate() []error {
+	// 負の在庫を許可するかはマネージャーの設定で判定するため、ここでは範囲のみを確認する
+	return []error{
+		inventory.ValidateItemID(req.ItemID),
+		inventory.ValidateLocationID(req.LocationID),
+		withFieldPrefix("new", inventory.ValidateQuantity(req.NewQuantity, true)),
+		inventory.ValidateReference(req.Reference),
+		inventory.ValidateAdjustmentReason(req.ReasonCode),
+		validateAdjustmentNote(req.ReasonCode, req.Note),
+	}
+}
+
+// validateAdjustmentNote requires a note for the reason code other
+// 理由コードが other の場合のメモの指定と、メモの長さをバリデーション
+func validateAdjustmentNote(reason inventory.AdjustmentReason, note string) error {
+	if reason == inventory.AdjustmentReasonOther && strings.TrimSpace(note) == "" {
+		return inventory.NewValidationError("note", "理由コードが other の場合はメモが必要です", "")
+	}
+	return inventory.ValidateAlertNote(note)
+}
+
+func (req AdjustmentDecisionRequest) validate() []error {
+	return []error{
+		inventory.ValidateAlertNote(req.Note),
+	}
+}
+
+func (req ReceiveForInspectionRequest) validate() []error {
+	return []error{
+		inventory.ValidateItemID(req.ItemID),
+		inventory.ValidateLocationID(req.LocationID),
+		validatePositiveQuantity(req.Quantity),
+		inventory.ValidateReference(req.Reference),
+		inventory.ValidateAlertNote(req.Note),
+	}
+}
+
+func (req InspectionDispositionRequest) validate() []error {
+	return []error{
+		inventory.ValidateAdjustmentReason(req.ReasonCode),
+		validateAdjustmentNote(req.ReasonCode, req.Note),
+	}
+}
+
+func (req AllocateRequest) validate() []error {
+	request := req.allocationRequest()
+	return []error{inventory.ValidateAllocationRequest(&request)}
+}
+
+func (req ReverseTransactionRequest) validate() []error {
+	if strings.TrimSpace(req.Reason) == "" {
+		return []error{inventory.NewValidationError("reason", "取消の理由が指定されていません", "")}
+	}
+	return nil
+}
+
+func (req ReservationRequest) validate() []error {
+	return []error{
+		inventory.ValidateItemID(req.ItemID),
+		inventory.ValidateLocationID(req.LocationID),
+		validatePositiveQuantity(req.Quantity),
+		inventory.ValidateReference(req.Reference),
+	}
+}
+
+func (req AssemblyRequest) validate() []error {
+	return []error{
+		inventory.ValidateItemID(req.KitItemID),
+		inventory.ValidateLocationID(req.LocationID),
+		validatePositiveQuantity(req.Quantity),
+		inventory.ValidateReference(req.Reference),
+	}
+}
+
+func (req CreateReservationRequest) validate() []error {
+	return []error{
+		inventory.ValidateItemID(req.ItemID),
+		inventory.ValidateLocationID(req.LocationID),
+		validatePositiveQuantity(req.Quantity),
+		inventory.ValidateReference(req.Reference),
+	}
+}
+
+func (req FulfillReservationRequest) validate() []error {
+	return []error{inventory.ValidateReference(req.Reference)}
+}
+
+func (req AlertNoteRequest) validate() []error {
+	return []error{inventory.ValidateAlertNote(req.Note)}
+}
+
+func (req AllocateBackordersRequest) validate() []error {
+	return []error{
+		inventory.ValidateItemID(req.ItemID),
+		inventory.ValidateLocationID(req.LocationID),
+	}
+}
+
+func (req AdjustLotRequest) validate() []error {
+	errs := []error{inventory.ValidateReference(req.Reference)}
+	if req.Delta == 0 {
+		errs = append(errs, inventory.NewValidationError("delta", "調整数量は0以外である必要があります", "0"))
+	}
+	return errs
+}
+
+func (req CreateStocktakeRequest) validate() []error {
+	errs := []error{
+		inventory.ValidateLocationID(req.LocationID),
+		inventory.ValidateReference(req.Reference),
+	}
+	switch req.ABCClass {
+	case "", "A", "B", "C":
+	default:
+		errs = append(errs, inventory.NewValidationError("abc_class", "ABCクラスは A・B・C のいずれかを指定してください", req.ABCClass))
+	}
+	return errs
+}
+
+func (req RecordStocktakeCountsRequest) validate() []error {
+	if len(req.Counts) == 0 {
+		return []error{inventory.NewValidationError("counts", "実数が指定されていません", "")}
+	}
+	var errs []error
+	for i, count := range req.Counts {
+		prefix := fmt.Sprintf("counts[%d]", i)
+		errs = append(errs, withFieldPrefix(prefix, inventory.ValidateItemID(count.ItemID)))
+		if count.CountedQty < 0 {
+			errs = append(errs, inventory.NewValidationError(prefix+".counted_qty", "実数は0以上で指定してください", fmt.Sprintf("%d", count.CountedQty)))
+		}
+	}
+	return errs
+}
+
+func (req ApplyStocktakeRequest) validate() []error {
+	var errs []error
+	for i, itemID := range req.ItemIDs {
+		errs = append(errs, withFieldPrefix(fmt.Sprintf("item_ids[%d]", i), inventory.ValidateItemID(itemID)))
+	}
+	return errs
+}
+
+func (req LabelRequest) validate() []error {
+	var errs []error
+	if tmpl, ok := req.template(); !ok {
+		errs = append(errs, inventory.NewValidationError("template", "ラベルのテンプレートが見つかりません", req.Template))
+	} else if err := tmpl.Validate(); err != nil {
+		errs = append(errs, inventory.NewValidationError("custom_template", err.Error(), ""))
+	}
+
+	switch {
+	case len(req.Targets) == 0:
+		return append(errs, inventory.NewValidationError("targets", "印刷するラベルが指定されていません", ""))
+	case len(req.Targets) > maxLabelTargets:
+		return append(errs, inventory.NewValidationError("targets", "ラベルの対象は500件までです", fmt.Sprintf("%d", len(req.Targets))))
+	}
+	total := 0
+	for i, target := range req.Targets {
+		prefix := fmt.Sprintf("targets[%d]", i)
+		switch target.Type {
+		case label.KindItem:
+			errs = append(errs, withFieldPrefix(prefix, inventory.ValidateItemID(target.ID)))
+		case label.KindLocation:
+			errs = append(errs, withFieldPrefix(prefix, inventory.ValidateLocationID(target.ID)))
+		case label.KindLot:
+			if strings.TrimSpace(target.ID) == "" {
+				errs = append(errs, inventory.NewValidationError(prefix+".id", "ロットIDが指定されていません", ""))
+			}
+		default:
+			errs = append(errs, inventory.NewValidationError(prefix+".type", "ラベルの対象の種類が正しくありません（item・lot・location のいずれかを指定してください）", target.Type))
+		}
+		if target.Copies < 0 || target.Copies > maxLabelCopies {
+			errs = append(errs, inventory.NewValidationError(prefix+".copies", "ラベルの枚数は1〜100で指定してください", fmt.Sprintf("%d", target.Copies)))
+		}
+		if target.Copies == 0 {
+			total++
+		} else {
+			total += target.Copies
+		}
+	}
+	if total > maxLabels {
+		errs = append(errs, inventory.NewValidationError("targets", "ラベルの枚数の合計は2000枚までです", fmt.Sprintf("%d", total)))
+	}
+	return errs
+}
+
+func (req LookupStocksRequest) validate() []error {
+	var errs []error
+	for i, key := range req.Keys {
+		prefix := fmt.Sprintf("keys[%d]", i)
+		errs = append(errs,
+			withFieldPrefix(prefix, inventory.ValidateItemID(key.ItemID)),
+			withFieldPrefix(prefix, inventory.ValidateLocationID(key.LocationID)),
+		)
+	}
+	return errs
+}
+
+// validateOperations validates each operation of a batch
+// バッチの各操作をバリデーション
+func validateOperations(operations []inventory.InventoryOperation) []error {
+	var errs []error
+	for i, op := range operations {
+		prefix := fmt.Sprintf("operations[%d]", i)
+		errs = append(errs,
+			withFieldPrefix(prefix, inventory.ValidateOperationType(op.Type)),
+			withFieldPrefix(prefix, inventory.ValidateItemID(op.ItemID)),
+			withFieldPrefix(prefix, inventory.ValidateLocationID(op.LocationID)),
+			withFieldPrefix(prefix, inventory.ValidateReference(op.Reference)),
+		)
+		switch op.Type {
+		case inventory.OperationTypeAdjust:
+			errs = append(errs, withFieldPrefix(prefix, inventory.ValidateQuantity(op.Quantity, true)))
+		default:
+			errs = append(errs, withFieldPrefix(prefix, validatePositiveQuantity(op.Quantity)))
+		}
+		if op.Type == inventory.OperationTypeTransfer {
+			if op.ToLocationID == nil {
+				errs = append(errs, inventory.NewValidationError(prefix+".to_location_id", "ロケーションIDが指定されていません", ""))
+			} else {
+				errs = append(errs, withFieldPrefix(prefix+".to", inventory.ValidateLocationID(*op.ToLocationID)))
+			}
+		}
+	}
+	return errs
+}
+
+// validateItem validates every field of an item
+// 商品の全ての項目をバリデーション
+func validateItem(item *inventory.Item) []error {
+	return []error{
+		inventory.ValidateItemID(item.ID),
+		inventory.ValidateItemName(item.Name),
+		inventory.ValidateSKU(item.SKU),
+		inventory.ValidateCategory(item.Category),
+		inventory.ValidateDescription(item.Description),
+		inventory.ValidateUnitCost(item.UnitCost),
+	}
+}
+
+// validateLocation validates every field of a location
+// ロケーションの全ての項目をバリデーション
+func validateLocation(location *inventory.Location) []error {
+	return []error{
+		inventory.ValidateLocationID(location.ID),
+		inventory.ValidateLocationName(location.Name),
+		inventory.ValidateCapacity(location.Capacity),
+		inventory.ValidateLocationPriority(location.Priority),
+		inventory.ValidateCoordinates(location.Latitude, location.Longitude),
+	}
+}
+
+// validateLot validates every field of a lot
+// ロットの全ての項目をバリデーション
+func validateLot(lot *inventory.Lot) []error {
+	return []error{
+		inventory.ValidateItemID(lot.ItemID),
+		inventory.ValidateLotNumber(lot.Number),
+		inventory.ValidateQuantity(lot.Quantity, false),
+		inventory.ValidateUnitCost(lot.UnitCost),
+	}
+}
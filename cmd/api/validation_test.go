@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// validationResponse is the body of a 422 response with field-level errors
+type validationResponse struct {
+	Error     string    `json:"error"`
+	ErrorCode ErrorCode `json:"error_code"`
+	Data      struct {
+		Errors []struct {
+			Field   string `json:"field"`
+			Message string `json:"message"`
+			Value   string `json:"value"`
+		} `json:"errors"`
+	} `json:"data"`
+}
+
+func TestAddStockValidation(t *testing.T) {
+	// バリデーションに失敗した場合はマネージャーを呼び出さない
+	h := NewHandlers(nil, zap.NewNop())
+
+	body := `{"item_id": "ITEM 001", "location_id": "", "quantity": -5, "reference": "REF-001"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/inventory/add", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.AddStock(rec, req)
+
+	require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	var resp validationResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, ErrorCodeValidationFailed, resp.ErrorCode)
+
+	fields := make([]string, 0, len(resp.Data.Errors))
+	for _, fieldErr := range resp.Data.Errors {
+		fields = append(fields, fieldErr.Field)
+	}
+	assert.Equal(t, []string{"item_id", "location_id", "quantity"}, fields)
+	assert.Equal(t, "商品IDに無効な文字が含まれています", resp.Data.Errors[0].Message)
+}
+
+func TestValidationMessagesLanguage(t *testing.T) {
+	h := NewHandlers(nil, zap.NewNop())
+
+	body := `{"item_id": "ITEM-001", "from_location_id": "A", "to_location_id": "A", "quantity": 1, "reference": "REF"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/inventory/transfer", strings.NewReader(body))
+	req.Header.Set("Accept-Language", "en")
+	rec := httptest.NewRecorder()
+	languageMiddleware(http.HandlerFunc(h.TransferStock)).ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	var resp validationResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "the request has invalid fields", resp.Error)
+	require.Len(t, resp.Data.Errors, 1)
+	assert.Equal(t, "to_location_id", resp.Data.Errors[0].Field)
+	assert.Equal(t, "source and destination locations are the same", resp.Data.Errors[0].Message)
+}
+
+func TestValidatePathMiddleware(t *testing.T) {
+	h := NewHandlers(nil, zap.NewNop())
+	router := mux.NewRouter()
+	router.Use(h.validatePathMiddleware)
+	router.HandleFunc("/items/{itemId}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items/ITEM-001", nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items/ITEM%2A001", nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
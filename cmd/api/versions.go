@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/nemonet1337/zaiGoFramework/internal/config"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// apiVersion is a version of the API mounted under /api/{name}
+// /api/{name} 以下に登録するAPIのバージョン
+//
+// レスポンスの形式を変更する場合は新しいバージョンで変更後のハンドラーを登録し、
+// 既存のバージョンは非推奨（deprecation）・提供終了日（sunset）を設定して段階的に移行します。
+type apiVersion struct {
+	name        string                                    // パスのバージョン（v1 など）
+	register    func(api *mux.Router, handlers *Handlers) // このバージョンのルートを登録する
+	deprecation time.Time                                 // 非推奨にした日時（ゼロ値の場合は非推奨ではない）
+	sunset      time.Time                                 // 提供を終了する日時（以降は410を返す）
+	successor   string                                    // 移行先のバージョン
+}
+
+// apiVersions are the API versions served by the server, oldest first
+// サーバーが提供するAPIのバージョン（古い順）
+var apiVersions = []apiVersion{
+	{name: "v1", register: registerV1Routes},
+	{name: "v2", register: registerV2Routes},
+}
+
+// registerV2Routes registers the routes of API v2
+// API v2のルートを登録
+//
+// v2 で形式を変更する操作はここで先に登録します（同じパス・メソッドは先に登録したルートが優先されます）。
+// 変更していない操作は v1 と同じハンドラーを使用します。
+func registerV2Routes(api *mux.Router, handlers *Handlers) {
+	registerV1Routes(api, handlers)
+}
+
+// sunsetMessage is the message of requests to a version that is no longer served
+// 提供を終了したバージョンへのリクエストのメッセージ
+const sunsetMessage = "このAPIバージョンは提供を終了しました"
+
+func init() {
+	inventory.RegisterMessages(inventory.LanguageEnglish, map[string]string{
+		sunsetMessage: "this API version is no longer available",
+	})
+}
+
+// configureVersions applies the deprecation and sunset dates of the configuration
+// 設定の非推奨・提供終了日をAPIのバージョンに適用
+func configureVersions(versions []apiVersion, configs []config.APIVersionConfig) ([]apiVersion, error) {
+	configured := make([]apiVersion, len(versions))
+	copy(configured, versions)
+
+	index := make(map[string]int, len(versions))
+	for i, version := range versions {
+		index[version.name] = i
+	}
+	for _, cfg := range configs {
+		i, ok := index[cfg.Name]
+		if !ok {
+			return nil, fmt.Errorf("存在しないAPIバージョンです: %s", cfg.Name)
+		}
+		if _, ok := index[cfg.Successor]; cfg.Successor != "" && !ok {
+			return nil, fmt.Errorf("APIバージョン %s の移行先が存在しません: %s", cfg.Name, cfg.Successor)
+		}
+		configured[i].deprecation = cfg.Deprecation
+		configured[i].sunset = cfg.Sunset
+		configured[i].successor = cfg.Successor
+	}
+	return configured, nil
+}
+
+// mountVersions registers the routes of every API version
+// 全てのAPIバージョンのルートを登録
+func (h *Handlers) mountVersions(router *mux.Router) {
+	for _, version := range h.apiVersions() {
+		api := router.PathPrefix("/api/" + version.name).Subrouter()
+		// バージョン・非推奨のヘッダーを返す（提供終了後は410）
+		api.Use(h.versionMiddleware(version))
+		// パスの商品ID・ロケーションIDの形式を検証（認証・頻度制限の後に実行）
+		api.Use(h.validatePathMiddleware)
+		version.register(api, h)
+	}
+}
+
+// apiVersions returns the configured API versions
+// 設定を適用したAPIのバージョンを返す
+func (h *Handlers) apiVersions() []apiVersion {
+	if h.versions != nil {
+		return h.versions
+	}
+	return apiVersions
+}
+
+// versionMiddleware returns the version and deprecation headers of a version
+// バージョン・非推奨のヘッダーを返すミドルウェア
+//
+// API-Version でバージョンを、非推奨のバージョンは Deprecation（RFC 9745）・Sunset（RFC 8594）・
+// 移行先の Link（rel="successor-version"）で移行を促します。提供終了日以降は410を返します。
+func (h *Handlers) versionMiddleware(version apiVersion) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := w.Header()
+			header.Set("API-Version", version.name)
+			if !version.deprecation.IsZero() {
+				header.Set("Deprecation", "@"+strconv.FormatInt(version.deprecation.Unix(), 10))
+			}
+			if !version.sunset.IsZero() {
+				header.Set("Sunset", version.sunset.UTC().Format(http.TimeFormat))
+			}
+			if version.successor != "" {
+				header.Add("Link", fmt.Sprintf(`</api/%s>; rel="successor-version"`, version.successor))
+			}
+
+			if !version.sunset.IsZero() && !time.Now().Before(version.sunset) {
+				data := map[string]interface{}{"version": version.name}
+				if version.successor != "" {
+					data["successor"] = "/api/" + version.successor
+				}
+				h.sendErrorWithData(w, http.StatusGone, sunsetMessage, data)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// apiRelativePath returns the path below /api/{version}, or path itself for other paths
+// /api/{version} 以下のパスを返す（それ以外のパスはそのまま返す）
+//
+// バージョンに依存しない判定（管理APIのスコープなど）に使用します。
+func apiRelativePath(path string) string {
+	rest, ok := strings.CutPrefix(path, "/api/")
+	if !ok {
+		return path
+	}
+	version, relative, ok := strings.Cut(rest, "/")
+	if !ok || len(version) < 2 || version[0] != 'v' {
+		return path
+	}
+	if _, err := strconv.Atoi(version[1:]); err != nil {
+		return path
+	}
+	return "/" + relative
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/internal/config"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/auth"
+)
+
+func TestVersionMiddlewareDeprecation(t *testing.T) {
+	deprecation := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	versions, err := configureVersions(apiVersions, []config.APIVersionConfig{
+		{Name: "v1", Deprecation: deprecation, Sunset: time.Now().Add(24 * time.Hour), Successor: "v2"},
+	})
+	require.NoError(t, err)
+
+	h := NewHandlers(nil, zap.NewNop())
+	h.versions = versions
+	router := setupRouter(h)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/items", nil))
+	assert.Equal(t, "v1", rec.Header().Get("API-Version"))
+	assert.Equal(t, "@1704067200", rec.Header().Get("Deprecation"))
+	assert.NotEmpty(t, rec.Header().Get("Sunset"))
+	assert.Equal(t, `</api/v2>; rel="successor-version"`, rec.Header().Get("Link"))
+
+	// 移行先のバージョンは同じ操作を非推奨のヘッダーなしで提供する
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v2/items", nil))
+	assert.Equal(t, "v2", rec.Header().Get("API-Version"))
+	assert.Empty(t, rec.Header().Get("Deprecation"))
+	assert.NotEqual(t, http.StatusNotFound, rec.Code)
+
+	// OpenAPIドキュメントでは非推奨のバージョンの操作に deprecated を付ける
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var doc struct {
+		Paths map[string]map[string]struct {
+			Deprecated bool `json:"deprecated"`
+		} `json:"paths"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&doc))
+	assert.True(t, doc.Paths["/api/v1/items"]["get"].Deprecated)
+	assert.False(t, doc.Paths["/api/v2/items"]["get"].Deprecated)
+}
+
+func TestVersionMiddlewareSunset(t *testing.T) {
+	h := NewHandlers(nil, zap.NewNop())
+	version := apiVersion{name: "v1", deprecation: time.Now().Add(-48 * time.Hour), sunset: time.Now().Add(-time.Hour), successor: "v2"}
+	handler := h.versionMiddleware(version)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("提供終了後のバージョンのハンドラーが呼び出されました")
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/items", nil))
+
+	require.Equal(t, http.StatusGone, rec.Code)
+	var resp struct {
+		ErrorCode ErrorCode         `json:"error_code"`
+		Data      map[string]string `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, ErrorCodeGone, resp.ErrorCode)
+	assert.Equal(t, "/api/v2", resp.Data["successor"])
+}
+
+func TestConfigureVersionsErrors(t *testing.T) {
+	_, err := configureVersions(apiVersions, []config.APIVersionConfig{{Name: "v9"}})
+	assert.Error(t, err)
+	_, err = configureVersions(apiVersions, []config.APIVersionConfig{{Name: "v1", Successor: "v9"}})
+	assert.Error(t, err)
+}
+
+func TestScopesAcrossVersions(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		scope  string
+	}{
+		{http.MethodGet, "/api/v2/admin/api-keys", auth.ScopeAdmin},
+		{http.MethodPost, "/api/v2/inventory/lookup", auth.ScopeRead},
+		{http.MethodPost, "/api/v1/inventory/lookup", auth.ScopeRead},
+		{http.MethodPost, "/api/v1/labels", auth.ScopeRead},
+		{http.MethodPost, "/api/v2/inventory/add", auth.ScopeWrite},
+		{http.MethodPost, "/api/vx/admin/api-keys", auth.ScopeWrite},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.scope, requiredScope(httptest.NewRequest(tt.method, tt.path, nil)), tt.path)
+	}
+}
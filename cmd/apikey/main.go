@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/internal/config"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/auth"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/storage"
+)
+
+const usage = `使い方:
+  apikey issue -name <名前> [-scopes inventory:read,inventory:write] [-expires 720h]
+  apikey list
+  apikey rotate -id <キーID> [-grace 24h]
+  apikey revoke -id <キーID>`
+
+func main() {
+	log.Println("zaiGoFramework APIキー管理ツール")
+
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	command := os.Args[1]
+
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	name := flags.String("name", "", "APIキーの名前（issue）")
+	scopes := flags.String("scopes", auth.ScopeRead, "カンマ区切りのスコープ（issue）")
+	expires := flags.Duration("expires", 0, "有効期間（issue、0の場合は無期限）")
+	id := flags.String("id", "", "APIキーのID（rotate・revoke）")
+	grace := flags.Duration("grace", 0, "旧キーを引き続き使用できる期間（rotate、0の場合は直ちに無効化）")
+	flags.Parse(os.Args[2:])
+
+	// 設定読み込み
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("設定読み込みに失敗しました:", err)
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatal("ログ初期化に失敗しました:", err)
+	}
+	defer logger.Sync()
+
+	log.Printf("データベースに接続中: %s:%d/%s", cfg.Database.Host, cfg.Database.Port, cfg.Database.DBName)
+
+	store, err := storage.NewPostgreSQLStorage(cfg.DSN(), logger)
+	if err != nil {
+		log.Fatal("データベース接続に失敗しました:", err)
+	}
+	defer store.Close()
+
+	keys := auth.NewAPIKeys(auth.NewPostgresAPIKeyStore(store.DB()))
+	ctx := context.Background()
+
+	switch command {
+	case "issue":
+		var expiresAt *time.Time
+		if *expires > 0 {
+			t := time.Now().Add(*expires)
+			expiresAt = &t
+		}
+		key, plaintext, err := keys.Issue(ctx, *name, strings.Split(*scopes, ","), expiresAt)
+		if err != nil {
+			log.Fatal("APIキーの発行に失敗しました:", err)
+		}
+		log.Printf("APIキーを発行しました: ID %s スコープ %s", key.ID, strings.Join(key.Scopes, ","))
+		// キーは保存しないため、ここで表示したキーを安全な場所に保管する
+		fmt.Println(plaintext)
+
+	case "list":
+		list, err := keys.List(ctx)
+		if err != nil {
+			log.Fatal("APIキーの取得に失敗しました:", err)
+		}
+		now := time.Now()
+		for _, key := range list {
+			status := "有効"
+			switch {
+			case key.RevokedAt != nil:
+				status = "無効化済み"
+			case !key.Active(now):
+				status = "期限切れ"
+			case key.ExpiresAt != nil:
+				status = "有効（期限 " + key.ExpiresAt.Format(time.RFC3339) + "）"
+			}
+			fmt.Printf("%s\t%s\t%s…\t%s\t%s\n", key.ID, key.Name, key.Prefix, strings.Join(key.Scopes, ","), status)
+		}
+		log.Printf("APIキー %d 件", len(list))
+
+	case "rotate":
+		key, plaintext, err := keys.Rotate(ctx, *id, *grace)
+		if err != nil {
+			log.Fatal("APIキーのローテーションに失敗しました:", err)
+		}
+		log.Printf("APIキーをローテーションしました: 新しいID %s（旧キーの猶予期間 %s）", key.ID, *grace)
+		fmt.Println(plaintext)
+
+	case "revoke":
+		if _, err := keys.Revoke(ctx, *id); err != nil {
+			log.Fatal("APIキーの無効化に失敗しました:", err)
+		}
+		log.Printf("APIキーを無効化しました: ID %s", *id)
+
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/internal/config"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/storage"
+)
+
+func main() {
+	log.Println("zaiGoFramework トランザクションアーカイブツール")
+
+	before := flag.String("before", "", "この日付（YYYY-MM-DD）より前のトランザクションをアーカイブ")
+	months := flag.Int("months", 0, "保持月数（指定時は設定ファイルの retention_months より優先）")
+	flag.Parse()
+
+	// 設定読み込み
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("設定読み込みに失敗しました:", err)
+	}
+
+	// アーカイブ基準日時の決定（-before > -months > 設定ファイル）
+	var cutoff time.Time
+	switch {
+	case *before != "":
+		cutoff, err = time.ParseInLocation("2006-01-02", *before, time.Local)
+		if err != nil {
+			log.Fatal("-before の形式が不正です（YYYY-MM-DD）:", err)
+		}
+	case *months > 0:
+		cutoff = inventory.RetentionCutoff(time.Now(), *months)
+	case cfg.Inventory.RetentionMonths > 0:
+		cutoff = inventory.RetentionCutoff(time.Now(), cfg.Inventory.RetentionMonths)
+	default:
+		log.Fatal("保持期間が設定されていません。-before、-months または INVENTORY_RETENTION_MONTHS を指定してください")
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatal("ログ初期化に失敗しました:", err)
+	}
+	defer logger.Sync()
+
+	log.Printf("データベースに接続中: %s:%d/%s", cfg.Database.Host, cfg.Database.Port, cfg.Database.DBName)
+
+	store, err := storage.NewPostgreSQLStorage(cfg.DSN(), logger)
+	if err != nil {
+		log.Fatal("データベース接続に失敗しました:", err)
+	}
+	defer store.Close()
+
+	manager := inventory.NewManager(store, nil, logger, nil)
+
+	log.Printf("アーカイブ実行中: %s より前のトランザクション", cutoff.Format("2006-01-02"))
+
+	archived, err := manager.ArchiveTransactions(context.Background(), cutoff)
+	if err != nil {
+		log.Fatal("アーカイブに失敗しました:", err)
+	}
+
+	log.Printf("アーカイブが完了しました: %d 件", archived)
+}
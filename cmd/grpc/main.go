@@ -0,0 +1,395 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	"github.com/nemonet1337/zaiGoFramework/internal/config"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/auth"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/events"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/notify"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/rpc"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/storage"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/webhooks"
+)
+
+func main() {
+	// ログ設定
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatal("ログ初期化に失敗しました:", err)
+	}
+	defer logger.Sync()
+
+	// 設定読み込み
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Fatal("設定読み込みに失敗しました", zap.Error(err))
+	}
+
+	// データベース接続（読み取りレプリカが設定されている場合は照会系をレプリカに振り分ける）
+	store, err := storage.NewPostgreSQLStorageWithReplicas(cfg.DSN(), cfg.ReplicaDSNs(), logger)
+	if err != nil {
+		logger.Fatal("データベース接続に失敗しました", zap.Error(err))
+	}
+	defer store.Close()
+	store.ConfigurePool(storage.PoolConfig{
+		MaxOpenConns:    cfg.Database.MaxOpenConns,
+		MaxIdleConns:    cfg.Database.MaxIdleConns,
+		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
+	})
+
+	// 在庫マネージャー初期化
+	inventoryConfig := &inventory.Config{
+		AllowNegativeStock:          cfg.Inventory.AllowNegativeStock,
+		DefaultLocation:             cfg.Inventory.DefaultLocation,
+		AuditEnabled:                cfg.Inventory.AuditEnabled,
+		LowStockThreshold:           cfg.Inventory.LowStockThreshold,
+		AlertTimeout:                time.Duration(cfg.Inventory.AlertTimeoutHours) * time.Hour,
+		LockingStrategy:             inventory.LockingStrategy(cfg.Inventory.LockingStrategy),
+		RetryMaxAttempts:            cfg.Inventory.RetryMaxAttempts,
+		RetryBaseDelay:              cfg.Inventory.RetryBaseDelay,
+		RetryMaxDelay:               cfg.Inventory.RetryMaxDelay,
+		RetryJitter:                 cfg.Inventory.RetryJitter,
+		RetentionMonths:             cfg.Inventory.RetentionMonths,
+		ReservationTTL:              cfg.Inventory.ReservationTTL,
+		EnableBackorders:            cfg.Inventory.EnableBackorders,
+		CapacityPolicy:              inventory.CapacityPolicy(cfg.Inventory.CapacityPolicy),
+		DisableLowStockCheck:        cfg.Inventory.DisableLowStockCheck,
+		AdjustmentApprovalThreshold: cfg.Inventory.AdjustmentApprovalThreshold,
+		LotPickingStrategy:          inventory.LotPickingStrategy(cfg.Inventory.LotPickingStrategy),
+	}
+
+	// イベント発行者初期化（ドライバー未設定の場合はイベントを発行しない）
+	// APIサーバーと同じ発行先に発行する。ライブ配信はAPIサーバーのプロセス内でのみ行う
+	webhookStore := webhooks.NewPostgresStore(store.DB())
+	eventTargets := make([]events.TargetConfig, 0, len(cfg.Events.Targets))
+	for _, target := range cfg.Events.Targets {
+		eventTargets = append(eventTargets, events.TargetConfig{
+			Name:        target.Name,
+			Driver:      target.Driver,
+			EventTypes:  target.EventTypes,
+			LocationIDs: target.LocationIDs,
+			Routes:      eventRoutes(target.Routes),
+		})
+	}
+	var eventKeys events.KeyProvider
+	if cfg.Events.Encryption.Enabled {
+		key, err := base64.StdEncoding.DecodeString(cfg.Events.Encryption.Key)
+		if err != nil {
+			logger.Fatal("イベント暗号鍵のデコードに失敗しました", zap.Error(err))
+		}
+		eventKeys, err = events.NewStaticKeyProvider(cfg.Events.Encryption.KeyID, key)
+		if err != nil {
+			logger.Fatal("イベント暗号鍵の初期化に失敗しました", zap.Error(err))
+		}
+	}
+	eventPublisher, err := events.Open(events.Config{
+		Driver: cfg.Events.Driver,
+		Format: cfg.Events.Format,
+		Source: cfg.Events.Source,
+		Schema: cfg.Events.SchemaVersion,
+		RabbitMQ: events.RabbitMQConfig{
+			URL:             cfg.Events.RabbitMQ.URL,
+			Exchange:        cfg.Events.RabbitMQ.Exchange,
+			ExchangeType:    cfg.Events.RabbitMQ.ExchangeType,
+			DeclareExchange: cfg.Events.RabbitMQ.DeclareExchange,
+			RoutingKey:      cfg.Events.RabbitMQ.RoutingKey,
+			Confirm:         cfg.Events.RabbitMQ.Confirm,
+			ConfirmTimeout:  cfg.Events.RabbitMQ.ConfirmTimeout,
+		},
+		PubSub: events.PubSubConfig{
+			ProjectID:       cfg.Events.PubSub.ProjectID,
+			Mode:            cfg.Events.PubSub.Mode,
+			Topic:           cfg.Events.PubSub.Topic,
+			TopicTemplate:   cfg.Events.PubSub.TopicTemplate,
+			Ordering:        cfg.Events.PubSub.Ordering,
+			CredentialsFile: cfg.Events.PubSub.CredentialsFile,
+		},
+		MQTT: events.MQTTConfig{
+			URL:            cfg.Events.MQTT.URL,
+			ClientID:       cfg.Events.MQTT.ClientID,
+			Username:       cfg.Events.MQTT.Username,
+			Password:       cfg.Events.MQTT.Password,
+			Topic:          cfg.Events.MQTT.Topic,
+			QoS:            byte(cfg.Events.MQTT.QoS),
+			Retain:         cfg.Events.MQTT.Retain,
+			KeepAlive:      cfg.Events.MQTT.KeepAlive,
+			ConnectTimeout: cfg.Events.MQTT.ConnectTimeout,
+			AckTimeout:     cfg.Events.MQTT.AckTimeout,
+		},
+		Kinesis: events.KinesisConfig{
+			Region:          cfg.Events.Kinesis.Region,
+			Stream:          cfg.Events.Kinesis.Stream,
+			Endpoint:        cfg.Events.Kinesis.Endpoint,
+			AccessKeyID:     cfg.Events.Kinesis.AccessKeyID,
+			SecretAccessKey: cfg.Events.Kinesis.SecretAccessKey,
+			SessionToken:    cfg.Events.Kinesis.SessionToken,
+			Timeout:         cfg.Events.Kinesis.Timeout,
+		},
+		Targets: eventTargets,
+		Routes:  eventRoutes(cfg.Events.Routes),
+		Keys:    eventKeys,
+		Drivers: map[string]events.SenderFactory{
+			"webhook": func() (events.Sender, error) {
+				return webhooks.NewDispatcher(webhookStore, webhooks.DispatcherConfig{
+					Workers:              cfg.Events.Webhook.Workers,
+					QueueSize:            cfg.Events.Webhook.QueueSize,
+					MaxAttempts:          cfg.Events.Webhook.MaxAttempts,
+					BaseDelay:            cfg.Events.Webhook.BaseDelay,
+					MaxDelay:             cfg.Events.Webhook.MaxDelay,
+					Timeout:              cfg.Events.Webhook.Timeout,
+					AllowPrivateNetworks: cfg.Events.Webhook.AllowPrivateNetworks,
+				}, logger), nil
+			},
+		},
+	}, logger)
+	if err != nil {
+		logger.Fatal("イベント発行者の初期化に失敗しました", zap.Error(err))
+	}
+	// 発行に失敗したイベントはAPIサーバーと共有の再試行キューに保存する
+	if eventPublisher != nil && cfg.Events.Retry.Enabled {
+		eventPublisher, err = events.NewRetryingPublisher(eventPublisher, events.NewPostgresRetryStore(store.DB()), events.RetryConfig{
+			MaxAttempts: cfg.Events.Retry.MaxAttempts,
+			BaseDelay:   cfg.Events.Retry.BaseDelay,
+			MaxDelay:    cfg.Events.Retry.MaxDelay,
+			Interval:    cfg.Events.Retry.Interval,
+			BatchSize:   cfg.Events.Retry.BatchSize,
+		}, nil, logger)
+		if err != nil {
+			logger.Fatal("イベント再試行の初期化に失敗しました", zap.Error(err))
+		}
+	}
+	var publisher inventory.EventPublisher
+	if eventPublisher != nil {
+		publisher = eventPublisher
+		defer eventPublisher.Close()
+	}
+	// gRPCの操作で作成されたアラートもAPIサーバーと同じ通知先へ通知する
+	notifier, err := alertNotifier(cfg.Notifications, logger)
+	if err != nil {
+		logger.Fatal("アラート通知の初期化に失敗しました", zap.Error(err))
+	}
+	if notifier != nil {
+		defer notifier.Close()
+		publishTargets := []events.Target{{
+			Name:      "notifications",
+			Publisher: notifier,
+			Filter:    events.Filter{EventTypes: []string{inventory.EventTypeAlertCreated}},
+		}}
+		if eventPublisher != nil {
+			publishTargets = append([]events.Target{{Name: cfg.Events.Driver, Publisher: eventPublisher}}, publishTargets...)
+		}
+		publisher = events.NewFanout(publishTargets...)
+	}
+
+	manager := inventory.NewManager(store, publisher, logger, inventoryConfig)
+
+	// REST APIと同じJWTベアラートークン・APIキーの認証
+	authentication, err := authConfig(cfg, store)
+	if err != nil {
+		logger.Fatal("認証の初期化に失敗しました", zap.Error(err))
+	}
+	if cfg.API.EnableAuth && authentication.Authenticator == nil {
+		logger.Fatal("認証が有効ですがgRPCの認証を設定できません")
+	}
+
+	// gRPCサーバー設定
+	options := append(rpc.NewServerOptions(),
+		grpc.ChainUnaryInterceptor(unaryLoggingInterceptor(logger), rpc.UnaryAuthInterceptor(authentication, logger)),
+		grpc.ChainStreamInterceptor(streamLoggingInterceptor(logger), rpc.StreamAuthInterceptor(authentication, logger)),
+	)
+	if cfg.GRPC.TLSCertFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(cfg.GRPC.TLSCertFile, cfg.GRPC.TLSKeyFile)
+		if err != nil {
+			logger.Fatal("TLSの設定に失敗しました", zap.Error(err))
+		}
+		options = append(options, grpc.Creds(creds))
+	}
+	server := grpc.NewServer(options...)
+	if err := rpc.NewServer(manager, logger).Register(server); err != nil {
+		logger.Fatal("gRPCサービスの登録に失敗しました", zap.Error(err))
+	}
+
+	// ヘルスチェック（grpc.health.v1）
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	// grpcurl などからサービス定義を参照できるようにする
+	if cfg.GRPC.Reflection {
+		reflection.Register(server)
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPC.Port))
+	if err != nil {
+		logger.Fatal("ポートのリッスンに失敗しました", zap.Int("port", cfg.GRPC.Port), zap.Error(err))
+	}
+
+	go func() {
+		logger.Info("在庫管理gRPCサーバーを開始します",
+			zap.Int("port", cfg.GRPC.Port),
+			zap.Bool("tls", cfg.GRPC.TLSCertFile != ""),
+			zap.Bool("auth", authentication.Authenticator != nil),
+		)
+		if err := server.Serve(listener); err != nil {
+			logger.Fatal("サーバー開始に失敗しました", zap.Error(err))
+		}
+	}()
+
+	// シャットダウンシグナル待機
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	logger.Info("サーバーをシャットダウンしています...")
+	healthServer.Shutdown()
+
+	// グレースフルシャットダウン（30秒以内に完了しない場合は強制停止）
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(30 * time.Second):
+		logger.Error("グレースフルシャットダウンがタイムアウトしました")
+		server.Stop()
+	}
+
+	logger.Info("サーバーが正常に停止しました")
+}
+
+// unaryLoggingInterceptor logs unary RPCs
+// 単項RPCをログ出力するインターセプター
+func unaryLoggingInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logger.Info("gRPCリクエスト",
+			zap.String("method", info.FullMethod),
+			zap.String("code", status.Code(err).String()),
+			zap.Duration("duration", time.Since(start)),
+		)
+		return resp, err
+	}
+}
+
+// streamLoggingInterceptor logs streaming RPCs
+// ストリーミングRPCをログ出力するインターセプター
+func streamLoggingInterceptor(logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, stream)
+		logger.Info("gRPCリクエスト",
+			zap.String("method", info.FullMethod),
+			zap.String("code", status.Code(err).String()),
+			zap.Duration("duration", time.Since(start)),
+		)
+		return err
+	}
+}
+
+// authConfig creates the authentication of gRPC callers from the API authentication settings
+// REST APIの認証設定からgRPCの呼び出し元の認証を作成（認証を無効にしている場合は認証しない）
+func authConfig(cfg *config.Config, store *storage.PostgreSQLStorage) (rpc.AuthConfig, error) {
+	if !cfg.API.EnableAuth {
+		return rpc.AuthConfig{}, nil
+	}
+	var authenticators []auth.Authenticator
+	if cfg.API.Auth.APIKeysEnabled {
+		authenticators = append(authenticators, auth.NewAPIKeys(auth.NewPostgresAPIKeyStore(store.DB())))
+	}
+	if cfg.API.Auth.JWKSURL != "" || cfg.API.Auth.HMACSecret != "" {
+		verifier, err := auth.NewJWTVerifier(auth.JWTConfig{
+			Issuer:          cfg.API.Auth.Issuer,
+			Audience:        cfg.API.Auth.Audience,
+			JWKSURL:         cfg.API.Auth.JWKSURL,
+			HMACSecret:      cfg.API.Auth.HMACSecret,
+			UserClaim:       cfg.API.Auth.UserClaim,
+			Leeway:          cfg.API.Auth.Leeway,
+			RefreshInterval: cfg.API.Auth.JWKSRefreshInterval,
+		})
+		if err != nil {
+			return rpc.AuthConfig{}, err
+		}
+		authenticators = append(authenticators, verifier)
+	}
+	if len(authenticators) == 0 {
+		return rpc.AuthConfig{}, nil
+	}
+	return rpc.AuthConfig{
+		Authenticator:    auth.Any(authenticators...),
+		AllowUnscopedJWT: cfg.API.Auth.JWTAllowUnscoped,
+	}, nil
+}
+
+// alertNotifier creates the alert notifier from the notification settings (nil when no channel is configured)
+// 通知設定からアラート通知者を作成（通知先が設定されていない場合はnil）
+func alertNotifier(cfg config.NotificationsConfig, logger *zap.Logger) (*notify.Notifier, error) {
+	if len(cfg.Channels) == 0 {
+		return nil, nil
+	}
+	targets := make([]notify.Target, 0, len(cfg.Channels))
+	for _, channel := range cfg.Channels {
+		target, err := notify.NewTarget(notify.TargetConfig{
+			Name:       channel.Name,
+			Type:       channel.Type,
+			AlertTypes: channel.AlertTypes,
+			Severities: channel.Severities,
+			Subject:    channel.Subject,
+			Body:       channel.Body,
+			URL:        channel.URL,
+			Headers:    channel.Headers,
+			Email: notify.EmailConfig{
+				Host:     channel.SMTP.Host,
+				Port:     channel.SMTP.Port,
+				Username: channel.SMTP.Username,
+				Password: channel.SMTP.Password,
+				From:     channel.SMTP.From,
+				To:       channel.SMTP.To,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+	return notify.New(targets, notify.Config{
+		Workers:     cfg.Workers,
+		QueueSize:   cfg.QueueSize,
+		MaxAttempts: cfg.MaxAttempts,
+		BaseDelay:   cfg.BaseDelay,
+		MaxDelay:    cfg.MaxDelay,
+		Timeout:     cfg.Timeout,
+	}, logger)
+}
+
+// eventRoutes converts route settings to event routing rules
+// ルーティング設定をイベントのルーティングルールに変換
+func eventRoutes(routes []config.EventRouteConfig) []events.Route {
+	converted := make([]events.Route, 0, len(routes))
+	for _, route := range routes {
+		converted = append(converted, events.Route{
+			LocationIDs: route.LocationIDs,
+			Destination: route.Destination,
+		})
+	}
+	return converted
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/internal/config"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/events"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/storage"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/webhooks"
+)
+
+func main() {
+	log.Println("zaiGoFramework イベント再生ツール")
+
+	from := flag.String("from", "", "この日時以降のトランザクションを再生（YYYY-MM-DD または RFC3339、省略時は最初から）")
+	to := flag.String("to", "", "この日時より前のトランザクションを再生（YYYY-MM-DD または RFC3339、省略時は現在まで）")
+	driver := flag.String("driver", "", "発行先ドライバー（rabbitmq | pubsub | webhook、省略時は EVENTS_DRIVER）")
+	target := flag.String("target", "", "events.targets のうち再生先とする発行先の名前（-driver より優先）")
+	flag.Parse()
+
+	// 設定読み込み
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("設定読み込みに失敗しました:", err)
+	}
+
+	fromTime, err := parseTime(*from)
+	if err != nil {
+		log.Fatal("-from の形式が不正です（YYYY-MM-DD または RFC3339）:", err)
+	}
+	toTime, err := parseTime(*to)
+	if err != nil {
+		log.Fatal("-to の形式が不正です（YYYY-MM-DD または RFC3339）:", err)
+	}
+
+	// 再生先の決定（-target > -driver > 設定ファイル）
+	eventsDriver := cfg.Events.Driver
+	var eventTargets []events.TargetConfig
+	switch {
+	case *target != "":
+		for _, t := range cfg.Events.Targets {
+			if t.Name == *target {
+				// フィルタ条件は再生時も適用する
+				eventTargets = append(eventTargets, events.TargetConfig{
+					Name:        t.Name,
+					Driver:      t.Driver,
+					EventTypes:  t.EventTypes,
+					LocationIDs: t.LocationIDs,
+				})
+			}
+		}
+		if len(eventTargets) == 0 {
+			log.Fatalf("発行先 %s が events.targets に見つかりません", *target)
+		}
+		eventsDriver = "fanout"
+	case *driver != "":
+		eventsDriver = *driver
+	}
+	if eventsDriver == "" || eventsDriver == "none" {
+		log.Fatal("再生先が指定されていません。-driver、-target または EVENTS_DRIVER を指定してください")
+	}
+	if eventsDriver == "fanout" && eventTargets == nil {
+		for _, t := range cfg.Events.Targets {
+			eventTargets = append(eventTargets, events.TargetConfig{
+				Name:        t.Name,
+				Driver:      t.Driver,
+				EventTypes:  t.EventTypes,
+				LocationIDs: t.LocationIDs,
+			})
+		}
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatal("ログ初期化に失敗しました:", err)
+	}
+	defer logger.Sync()
+
+	log.Printf("データベースに接続中: %s:%d/%s", cfg.Database.Host, cfg.Database.Port, cfg.Database.DBName)
+
+	store, err := storage.NewPostgreSQLStorage(cfg.DSN(), logger)
+	if err != nil {
+		log.Fatal("データベース接続に失敗しました:", err)
+	}
+	defer store.Close()
+
+	webhookStore := webhooks.NewPostgresStore(store.DB())
+	publisher, err := events.Open(events.Config{
+		Driver: eventsDriver,
+		Format: cfg.Events.Format,
+		Source: cfg.Events.Source,
+		Schema: cfg.Events.SchemaVersion,
+		RabbitMQ: events.RabbitMQConfig{
+			URL:             cfg.Events.RabbitMQ.URL,
+			Exchange:        cfg.Events.RabbitMQ.Exchange,
+			ExchangeType:    cfg.Events.RabbitMQ.ExchangeType,
+			DeclareExchange: cfg.Events.RabbitMQ.DeclareExchange,
+			RoutingKey:      cfg.Events.RabbitMQ.RoutingKey,
+			Confirm:         cfg.Events.RabbitMQ.Confirm,
+			ConfirmTimeout:  cfg.Events.RabbitMQ.ConfirmTimeout,
+		},
+		PubSub: events.PubSubConfig{
+			ProjectID:       cfg.Events.PubSub.ProjectID,
+			Mode:            cfg.Events.PubSub.Mode,
+			Topic:           cfg.Events.PubSub.Topic,
+			TopicTemplate:   cfg.Events.PubSub.TopicTemplate,
+			Ordering:        cfg.Events.PubSub.Ordering,
+			CredentialsFile: cfg.Events.PubSub.CredentialsFile,
+		},
+		Targets: eventTargets,
+		Drivers: map[string]events.SenderFactory{
+			"webhook": func() (events.Sender, error) {
+				return webhooks.NewDispatcher(webhookStore, webhooks.DispatcherConfig{
+					Workers:              cfg.Events.Webhook.Workers,
+					QueueSize:            cfg.Events.Webhook.QueueSize,
+					MaxAttempts:          cfg.Events.Webhook.MaxAttempts,
+					BaseDelay:            cfg.Events.Webhook.BaseDelay,
+					MaxDelay:             cfg.Events.Webhook.MaxDelay,
+					Timeout:              cfg.Events.Webhook.Timeout,
+					AllowPrivateNetworks: cfg.Events.Webhook.AllowPrivateNetworks,
+				}, logger), nil
+			},
+		},
+	}, logger)
+	if err != nil {
+		log.Fatal("イベント発行者の初期化に失敗しました:", err)
+	}
+
+	manager := inventory.NewManager(store, publisher, logger, nil)
+
+	log.Printf("イベント再生中: %s から %s まで（再生先: %s）", describe(fromTime, "最初"), describe(toTime, "現在"), eventsDriver)
+
+	result, err := manager.ReplayEvents(context.Background(), fromTime, toTime)
+
+	// Webhookなど非同期の発行者はCloseで送信完了を待つ
+	if closeErr := publisher.Close(); closeErr != nil {
+		log.Println("イベント発行者のクローズに失敗しました:", closeErr)
+	}
+
+	if err != nil {
+		if result != nil && result.LastTransactionID != "" {
+			log.Printf("%d 件のイベントを発行済みです。-from %s で再開できます", result.Events, result.LastCreatedAt.Format(time.RFC3339Nano))
+		}
+		log.Fatal("イベント再生に失敗しました:", err)
+	}
+
+	log.Printf("イベント再生が完了しました: トランザクション %d 件、イベント %d 件（スキップ %d 件）",
+		result.Transactions, result.Events, result.Skipped)
+}
+
+// parseTime parses YYYY-MM-DD (local time) or RFC3339, returning the zero time for an empty value
+// YYYY-MM-DD（ローカル時刻）またはRFC3339形式の日時を解析（空の場合はゼロ値）
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
+
+// describe formats t for logging, using fallback for the zero time
+// ログ出力用に日時を整形（ゼロ値の場合はfallback）
+func describe(t time.Time, fallback string) string {
+	if t.IsZero() {
+		return fallback
+	}
+	return t.Format(time.RFC3339)
+}
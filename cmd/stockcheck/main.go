@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/internal/config"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/storage"
+)
+
+func main() {
+	log.Println("zaiGoFramework 在庫整合性チェックツール")
+
+	repair := flag.Bool("repair", false, "不一致の在庫記録をトランザクション台帳の数量に修復")
+	flag.Parse()
+
+	// 設定読み込み
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("設定読み込みに失敗しました:", err)
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatal("ログ初期化に失敗しました:", err)
+	}
+	defer logger.Sync()
+
+	log.Printf("データベースに接続中: %s:%d/%s", cfg.Database.Host, cfg.Database.Port, cfg.Database.DBName)
+
+	store, err := storage.NewPostgreSQLStorage(cfg.DSN(), logger)
+	if err != nil {
+		log.Fatal("データベース接続に失敗しました:", err)
+	}
+	defer store.Close()
+
+	// 修復時のロック方式はAPIサーバーと揃える
+	manager := inventory.NewManager(store, nil, logger, &inventory.Config{
+		LockingStrategy:  inventory.LockingStrategy(cfg.Inventory.LockingStrategy),
+		RetryMaxAttempts: cfg.Inventory.RetryMaxAttempts,
+		RetryBaseDelay:   cfg.Inventory.RetryBaseDelay,
+		RetryMaxDelay:    cfg.Inventory.RetryMaxDelay,
+		RetryJitter:      cfg.Inventory.RetryJitter,
+	})
+
+	if *repair {
+		log.Println("在庫整合性チェック中（不一致は修復します）")
+	} else {
+		log.Println("在庫整合性チェック中")
+	}
+
+	report, err := manager.CheckStockConsistency(context.Background(), *repair)
+	if report == nil {
+		log.Fatal("在庫整合性チェックに失敗しました:", err)
+	}
+
+	for _, d := range report.Divergences {
+		status := ""
+		switch {
+		case d.Repaired:
+			status = "（修復済み）"
+		case d.RepairError != "":
+			status = "（修復失敗: " + d.RepairError + "）"
+		case d.MissingStock:
+			status = "（在庫記録なし）"
+		}
+		log.Printf("不一致: 商品 %s / ロケーション %s 台帳 %d 在庫 %d 差 %+d%s",
+			d.ItemID, d.LocationID, d.LedgerQuantity, d.StockQuantity, d.Difference, status)
+	}
+
+	log.Printf("在庫整合性チェックが完了しました: トランザクション %d 件、在庫 %d 件（スキップ %d 件）、不一致 %d 件、修復 %d 件",
+		report.Transactions, report.Stocks, report.Skipped, len(report.Divergences), report.Repaired)
+
+	if err != nil {
+		log.Fatal("一部の在庫の修復に失敗しました:", err)
+	}
+	// 未修復の不一致がある場合は監視ジョブで検知できるよう終了コード2で終了する
+	if len(report.Divergences) > report.Repaired {
+		os.Exit(2)
+	}
+}
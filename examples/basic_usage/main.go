@@ -0,0 +1,97 @@
+// basic_usage はインメモリストレージを使った在庫管理の基本的な使用例です。
+// PostgreSQLなしでそのまま実行できます:
+//
+//	go run ./examples/basic_usage
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/storage"
+)
+
+func main() {
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		log.Fatal("ログ初期化に失敗しました:", err)
+	}
+	defer logger.Sync()
+
+	ctx := context.Background()
+
+	// インメモリストレージと在庫マネージャーを初期化
+	store := storage.NewMemoryStorage()
+	manager := inventory.NewManager(store, nil, logger, nil)
+
+	// マスターデータを登録
+	now := time.Now()
+	if err := manager.CreateItem(ctx, &inventory.Item{
+		ID:        "ITEM001",
+		Name:      "サンプル商品A",
+		SKU:       "SKU-001",
+		Category:  "sample",
+		UnitCost:  100,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}); err != nil {
+		log.Fatal("商品作成エラー:", err)
+	}
+
+	for _, id := range []string{"LOC001", "LOC002"} {
+		if err := manager.CreateLocation(ctx, &inventory.Location{
+			ID:        id,
+			Name:      "倉庫 " + id,
+			Type:      "warehouse",
+			Capacity:  10000,
+			IsActive:  true,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}); err != nil {
+			log.Fatal("ロケーション作成エラー:", err)
+		}
+	}
+
+	// 入庫
+	if err := manager.Add(ctx, "ITEM001", "LOC001", 100, "PO-2024-001"); err != nil {
+		log.Fatal("入庫エラー:", err)
+	}
+
+	// 出庫
+	if err := manager.Remove(ctx, "ITEM001", "LOC001", 30, "SO-2024-001"); err != nil {
+		log.Fatal("出庫エラー:", err)
+	}
+
+	// ロケーション間移動
+	if err := manager.Transfer(ctx, "ITEM001", "LOC001", "LOC002", 20, "TR-2024-001"); err != nil {
+		log.Fatal("移動エラー:", err)
+	}
+
+	// 在庫確認
+	for _, locationID := range []string{"LOC001", "LOC002"} {
+		stock, err := manager.GetStock(ctx, "ITEM001", locationID)
+		if err != nil {
+			log.Fatal("在庫取得エラー:", err)
+		}
+		log.Printf("%s の在庫: %d個 (予約: %d, 利用可能: %d)", locationID, stock.Quantity, stock.Reserved, stock.Available)
+	}
+
+	total, err := manager.GetTotalStock(ctx, "ITEM001")
+	if err != nil {
+		log.Fatal("合計在庫取得エラー:", err)
+	}
+	log.Printf("合計在庫: %d個", total)
+
+	// 履歴確認
+	history, err := manager.GetHistory(ctx, "ITEM001", 10)
+	if err != nil {
+		log.Fatal("履歴取得エラー:", err)
+	}
+	for _, tx := range history {
+		log.Printf("履歴: %s %d個 (参照: %s)", tx.Type, tx.Quantity, tx.Reference)
+	}
+}
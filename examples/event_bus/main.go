@@ -0,0 +1,71 @@
+// event_bus はプロセス内イベントバスで在庫イベントを受け取る使用例です。
+// メッセージブローカーなしでそのまま実行できます:
+//
+//	go run ./examples/event_bus
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/events"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/storage"
+)
+
+func main() {
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		log.Fatal("ログ初期化に失敗しました:", err)
+	}
+	defer logger.Sync()
+
+	ctx := context.Background()
+
+	// Workers: 0 は発行元のゴルーチンで同期的に配信する（ハンドラーのエラーは発行エラーになる）
+	bus := events.NewBus(events.BusConfig{Workers: 0}, logger)
+	defer bus.Close()
+
+	// 在庫変更を購読
+	bus.Subscribe(events.TypeStockChanged, func(ctx context.Context, event events.Event) error {
+		changed := event.Payload.(inventory.StockChangedEvent)
+		log.Printf("在庫変更: %s@%s %d → %d (%s)", changed.ItemID, changed.LocationID, changed.OldQuantity, changed.NewQuantity, changed.ChangeType)
+		return nil
+	})
+
+	// 低在庫アラートを購読
+	bus.Subscribe(events.TypeLowStockAlert, func(ctx context.Context, event events.Event) error {
+		alert := event.Payload.(inventory.LowStockAlertEvent)
+		log.Printf("低在庫アラート: %s@%s 現在 %d (閾値 %d)", alert.ItemID, alert.LocationID, alert.CurrentQty, alert.Threshold)
+		return nil
+	})
+
+	// 全てのイベントのタイプを記録
+	bus.Subscribe(events.AllEvents, func(ctx context.Context, event events.Event) error {
+		log.Printf("イベント: %s", event.Type)
+		return nil
+	})
+
+	// バスをイベント発行者としてマネージャーに渡す
+	store := storage.NewMemoryStorage()
+	manager := inventory.NewManager(store, bus, logger, nil)
+
+	now := time.Now()
+	if err := manager.CreateItem(ctx, &inventory.Item{ID: "ITEM001", Name: "サンプル商品A", CreatedAt: now, UpdatedAt: now}); err != nil {
+		log.Fatal("商品作成エラー:", err)
+	}
+	if err := manager.CreateLocation(ctx, &inventory.Location{ID: "LOC001", Name: "倉庫 LOC001", IsActive: true, CreatedAt: now, UpdatedAt: now}); err != nil {
+		log.Fatal("ロケーション作成エラー:", err)
+	}
+
+	if err := manager.Add(ctx, "ITEM001", "LOC001", 20, "PO-2024-001"); err != nil {
+		log.Fatal("入庫エラー:", err)
+	}
+	// 閾値（既定10）を下回ると低在庫アラートが発行される
+	if err := manager.Remove(ctx, "ITEM001", "LOC001", 15, "SO-2024-001"); err != nil {
+		log.Fatal("出庫エラー:", err)
+	}
+}
@@ -3,8 +3,8 @@ package config
 import (
 	"fmt"
 	"io/ioutil"
+	"net"
 	"os"
-	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
@@ -15,10 +15,17 @@ import (
 
 // Config システム全体の設定構造体
 type Config struct {
-	Database  DatabaseConfig  `yaml:"database"`
-	API       APIConfig       `yaml:"api"`
-	Inventory InventoryConfig `yaml:"inventory"`
-	Log       LogConfig       `yaml:"log"`
+	Database      DatabaseConfig      `yaml:"database"`
+	API           APIConfig           `yaml:"api"`
+	GRPC          GRPCConfig          `yaml:"grpc"`
+	GraphQL       GraphQLConfig       `yaml:"graphql"`
+	Tracing       TracingConfig       `yaml:"tracing"`
+	Inventory     InventoryConfig     `yaml:"inventory"`
+	Events        EventsConfig        `yaml:"events"`
+	Notifications NotificationsConfig `yaml:"notifications"`
+	Scheduler     SchedulerConfig     `yaml:"scheduler"`
+	Consumer      ConsumerConfig      `yaml:"consumer"`
+	Log           LogConfig           `yaml:"log"`
 }
 
 // DatabaseConfig データベース接続設定
@@ -28,16 +35,139 @@ type DatabaseConfig struct {
 	User     string `yaml:"user" env:"DB_USER"`
 	Password string `yaml:"password" env:"DB_PASSWORD"`
 	DBName   string `yaml:"dbname" env:"DB_NAME"`
+	// 読み取りレプリカのホスト一覧（"host" または "host:port"、環境変数ではカンマ区切り）
+	ReplicaHosts []string `yaml:"replica_hosts" env:"DB_REPLICA_HOSTS"`
+	// 接続プール設定（プライマリと各レプリカにそれぞれ適用）
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME"`
 }
 
 // APIConfig API サーバー設定
 type APIConfig struct {
-	Port            int           `yaml:"port" env:"API_PORT"`
-	ReadTimeout     time.Duration `yaml:"read_timeout"`
-	WriteTimeout    time.Duration `yaml:"write_timeout"`
-	IdleTimeout     time.Duration `yaml:"idle_timeout"`
-	EnableCORS      bool          `yaml:"enable_cors"`
-	EnableAuth      bool          `yaml:"enable_auth"`
+	Port         int           `yaml:"port" env:"API_PORT"`
+	ReadTimeout  time.Duration `yaml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+	EnableCORS   bool          `yaml:"enable_cors"`
+	EnableAuth   bool          `yaml:"enable_auth" env:"API_ENABLE_AUTH"`
+	// /metrics でリクエスト・在庫操作・在庫変動・アラート・接続プールのメトリクスを記録する
+	EnableMetrics bool `yaml:"enable_metrics" env:"API_ENABLE_METRICS"`
+	// /docs でSwagger UIを提供する（/openapi.json は常に提供）
+	EnableSwaggerUI bool `yaml:"enable_swagger_ui" env:"API_ENABLE_SWAGGER_UI"`
+	// /api/v1/admin/debug でpprof・ランタイム統計を提供する（EnableAuth が必要）
+	EnableDebug bool `yaml:"enable_debug" env:"API_ENABLE_DEBUG"`
+	// リクエスト本文の上限（バイト、0の場合は制限しない。CSV取り込みは別に10MB）
+	MaxBodyBytes int64 `yaml:"max_body_bytes" env:"API_MAX_BODY_BYTES"`
+	// EnableAuth が true の場合のJWTベアラートークン・APIキーの認証設定
+	Auth AuthConfig `yaml:"auth"`
+	// クライアント（APIキー・ユーザー・IPアドレス）ごとのリクエスト頻度の制限
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+	// 証明書を指定した場合はHTTPSで提供する
+	TLS TLSConfig `yaml:"tls"`
+	// APIバージョン（v1・v2）ごとの非推奨・提供終了日
+	Versions []APIVersionConfig `yaml:"versions"`
+}
+
+// APIVersionConfig APIバージョンの非推奨・提供終了の設定
+type APIVersionConfig struct {
+	Name string `yaml:"name"` // v1 など
+	// 非推奨にした日時（Deprecation ヘッダーで通知する）
+	Deprecation time.Time `yaml:"deprecation"`
+	// 提供を終了する日時（Sunset ヘッダーで通知し、以降は410を返す）
+	Sunset time.Time `yaml:"sunset"`
+	// 移行先のバージョン（Link ヘッダーで通知する）
+	Successor string `yaml:"successor"`
+}
+
+// TLSConfig HTTPSの証明書とクライアント証明書認証（mTLS）の設定
+type TLSConfig struct {
+	// サーバー証明書（中間証明書を含むPEM）と秘密鍵のファイル
+	CertFile string `yaml:"cert_file" env:"API_TLS_CERT_FILE"`
+	KeyFile  string `yaml:"key_file" env:"API_TLS_KEY_FILE"`
+	// 指定した場合はこのCAが発行したクライアント証明書を要求する（mTLS）
+	ClientCAFile string `yaml:"client_ca_file" env:"API_TLS_CLIENT_CA_FILE"`
+	// 受け付ける最小のTLSバージョン（1.2 または 1.3）
+	MinVersion string `yaml:"min_version" env:"API_TLS_MIN_VERSION"`
+}
+
+// Enabled reports whether the API is served over HTTPS
+// APIをHTTPSで提供するかを返す
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != ""
+}
+
+// RateLimitConfig クライアントごとのトークンバケットによる頻度制限の設定
+type RateLimitConfig struct {
+	Enabled bool `yaml:"enabled" env:"RATE_LIMIT_ENABLED"`
+	// 1秒あたりに許可するリクエスト数（トークンの補充速度）
+	RequestsPerSecond float64 `yaml:"requests_per_second" env:"RATE_LIMIT_RPS"`
+	// 連続して許可するリクエスト数（バケットの容量）
+	Burst int `yaml:"burst" env:"RATE_LIMIT_BURST"`
+	// 更新系（GET以外）のリクエストに別の制限を設ける場合の1秒あたりのリクエスト数（0の場合は共通の制限）
+	WriteRequestsPerSecond float64 `yaml:"write_requests_per_second" env:"RATE_LIMIT_WRITE_RPS"`
+	// 更新系のリクエストを連続して許可する数
+	WriteBurst int `yaml:"write_burst" env:"RATE_LIMIT_WRITE_BURST"`
+	// X-Forwarded-For のアドレスをクライアントのIPアドレスとする（リバースプロキシ配下の場合のみ有効にする）
+	TrustForwardedFor bool `yaml:"trust_forwarded_for" env:"RATE_LIMIT_TRUST_FORWARDED_FOR"`
+	// X-Forwarded-For にアドレスを追加する信頼できるリバースプロキシの段数（末尾からこの数番目のアドレスをクライアントとする）
+	TrustedProxies int `yaml:"trusted_proxies" env:"RATE_LIMIT_TRUSTED_PROXIES"`
+	// 認証の前にIPアドレスごとに許可する1秒あたりのリクエスト数（認証失敗の繰り返しや未認証の大量のリクエストを制限する。0の場合は制限しない）
+	IPRequestsPerSecond float64 `yaml:"ip_requests_per_second" env:"RATE_LIMIT_IP_RPS"`
+	// 認証の前のIPアドレスごとの制限で連続して許可するリクエスト数
+	IPBurst int `yaml:"ip_burst" env:"RATE_LIMIT_IP_BURST"`
+	// この期間リクエストのないクライアントの状態を破棄する
+	IdleTTL time.Duration `yaml:"idle_ttl" env:"RATE_LIMIT_IDLE_TTL"`
+}
+
+// AuthConfig JWT・APIキー認証設定（api.enable_auth が true の場合に使用）
+type AuthConfig struct {
+	// iss クレームに要求する発行者（空の場合は検証しない）
+	Issuer string `yaml:"issuer" env:"AUTH_ISSUER"`
+	// aud クレームに要求する対象者（空の場合は検証しない）
+	Audience string `yaml:"audience" env:"AUTH_AUDIENCE"`
+	// 署名鍵を公開するJWKSのURL（RS256・ES256 など）
+	JWKSURL string `yaml:"jwks_url" env:"AUTH_JWKS_URL"`
+	// HS256 などの共有鍵署名の鍵（JWKSURL と併用可）
+	HMACSecret string `yaml:"hmac_secret" env:"AUTH_HMAC_SECRET"`
+	// 操作者のユーザーIDとするクレーム
+	UserClaim string `yaml:"user_claim" env:"AUTH_USER_CLAIM"`
+	// exp・nbf の時刻のずれの許容範囲
+	Leeway time.Duration `yaml:"leeway" env:"AUTH_LEEWAY"`
+	// JWKSの再取得間隔（鍵のローテーションに追従する）
+	JWKSRefreshInterval time.Duration `yaml:"jwks_refresh_interval" env:"AUTH_JWKS_REFRESH_INTERVAL"`
+	// X-API-Key ヘッダーによるAPIキー認証を有効にする（キーは api_keys テーブルに保存）
+	APIKeysEnabled bool `yaml:"api_keys_enabled" env:"AUTH_API_KEYS_ENABLED"`
+	// inventory:* のスコープを含まないJWTに全ての操作を許可する（スコープを発行できない認証基盤からの移行用）
+	JWTAllowUnscoped bool `yaml:"jwt_allow_unscoped" env:"AUTH_JWT_ALLOW_UNSCOPED"`
+}
+
+// GRPCConfig gRPC サーバー設定（cmd/grpc）
+type GRPCConfig struct {
+	Port int `yaml:"port" env:"GRPC_PORT"`
+	// サーバーリフレクションを有効にする（grpcurl などからスキーマを参照できる）
+	Reflection bool `yaml:"reflection" env:"GRPC_REFLECTION"`
+	// 証明書を指定した場合はTLSで提供する（サーバー証明書（中間証明書を含むPEM）と秘密鍵のファイル）
+	TLSCertFile string `yaml:"tls_cert_file" env:"GRPC_TLS_CERT_FILE"`
+	TLSKeyFile  string `yaml:"tls_key_file" env:"GRPC_TLS_KEY_FILE"`
+}
+
+// GraphQLConfig GraphQL エンドポイント設定（/api/v1/graphql）
+type GraphQLConfig struct {
+	Enabled bool `yaml:"enabled" env:"GRAPHQL_ENABLED"`
+	// クエリの選択セットの入れ子の上限（循環する参照による過大なクエリを防ぐ）
+	MaxDepth int `yaml:"max_depth" env:"GRAPHQL_MAX_DEPTH"`
+}
+
+// TracingConfig OpenTelemetry トレース設定（Zipkin 形式で Jaeger・Tempo などに送信）
+type TracingConfig struct {
+	Enabled bool `yaml:"enabled" env:"TRACING_ENABLED"`
+	// スパンの送信先（Zipkin v2 の JSON API、Jaeger・Tempo の Zipkin 受信口）
+	Endpoint string `yaml:"endpoint" env:"TRACING_ENDPOINT"`
+	// トレースに記録するサービス名
+	ServiceName string `yaml:"service_name" env:"TRACING_SERVICE_NAME"`
+	// 記録するトレースの割合（0〜1、呼び出し元がトレースしている場合はその判断に従う）
+	SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO"`
 }
 
 // InventoryConfig 在庫管理設定
@@ -47,6 +177,231 @@ type InventoryConfig struct {
 	AuditEnabled        bool   `yaml:"audit_enabled"`
 	LowStockThreshold   int64  `yaml:"low_stock_threshold"`
 	AlertTimeoutHours   int    `yaml:"alert_timeout_hours"`
+	LockingStrategy     string `yaml:"locking_strategy" env:"INVENTORY_LOCKING_STRATEGY"`
+	RetryMaxAttempts    int           `yaml:"retry_max_attempts" env:"INVENTORY_RETRY_MAX_ATTEMPTS"`
+	RetryBaseDelay      time.Duration `yaml:"retry_base_delay" env:"INVENTORY_RETRY_BASE_DELAY"`
+	RetryMaxDelay       time.Duration `yaml:"retry_max_delay" env:"INVENTORY_RETRY_MAX_DELAY"`
+	RetryJitter         float64       `yaml:"retry_jitter" env:"INVENTORY_RETRY_JITTER"`
+	RetentionMonths     int           `yaml:"retention_months" env:"INVENTORY_RETENTION_MONTHS"`
+	// 非同期バッチ（?async=true）の並行実行数・待ちキューの上限・進捗を保存する操作数の間隔
+	BatchWorkers          int `yaml:"batch_workers" env:"INVENTORY_BATCH_WORKERS"`
+	BatchQueueSize        int `yaml:"batch_queue_size" env:"INVENTORY_BATCH_QUEUE_SIZE"`
+	BatchProgressInterval int `yaml:"batch_progress_interval" env:"INVENTORY_BATCH_PROGRESS_INTERVAL"`
+	// Reserve で作成する予約の有効期間（0で期限なし）・期限切れの予約を解放する間隔
+	ReservationTTL            time.Duration `yaml:"reservation_ttl" env:"INVENTORY_RESERVATION_TTL"`
+	ReservationExpiryInterval time.Duration `yaml:"reservation_expiry_interval" env:"INVENTORY_RESERVATION_EXPIRY_INTERVAL"`
+	// 在庫不足の削除・予約（?backorder=true）でバックオーダーを作成し、入荷時に自動で引き当てるか
+	EnableBackorders bool `yaml:"enable_backorders" env:"INVENTORY_BACKORDERS_ENABLED"`
+	// 入荷・移動でロケーションの容量を超える場合の扱い（warn | enforce | ignore）
+	CapacityPolicy string `yaml:"capacity_policy" env:"INVENTORY_CAPACITY_POLICY"`
+	// アラートルールを定期的に評価する間隔・組み込みの低在庫判定を無効にしてアラートルールのみで判定するか
+	AlertRuleInterval    time.Duration `yaml:"alert_rule_interval" env:"INVENTORY_ALERT_RULE_INTERVAL"`
+	DisableLowStockCheck bool          `yaml:"disable_low_stock_check" env:"INVENTORY_DISABLE_LOW_STOCK_CHECK"`
+	// 調整額（調整数量の絶対値 × 単価）がこの値を超える在庫調整を承認待ちにする閾値（0で承認なし）
+	AdjustmentApprovalThreshold float64 `yaml:"adjustment_approval_threshold" env:"INVENTORY_ADJUSTMENT_APPROVAL_THRESHOLD"`
+	// ロットを消費する出庫（lot_strategy 省略時）の既定の順序（fefo | fifo | lifo）
+	LotPickingStrategy string `yaml:"lot_picking_strategy" env:"INVENTORY_LOT_PICKING_STRATEGY"`
+}
+
+// EventsConfig イベント発行設定
+type EventsConfig struct {
+	// 使用するドライバー（空または "none" でイベントを発行しない）
+	Driver string `yaml:"driver" env:"EVENTS_DRIVER"`
+	// エンコード形式（json | cloudevents）とCloudEventsのsource属性
+	Format string `yaml:"format" env:"EVENTS_FORMAT"`
+	Source string `yaml:"source" env:"EVENTS_CLOUDEVENTS_SOURCE"`
+	// イベント本体のスキーマバージョン（旧バージョンのコンシューマー向けに下げる場合に指定）
+	SchemaVersion int              `yaml:"schema_version" env:"EVENTS_SCHEMA_VERSION"`
+	RabbitMQ      RabbitMQConfig   `yaml:"rabbitmq"`
+	PubSub        PubSubConfig     `yaml:"pubsub"`
+	MQTT          MQTTConfig       `yaml:"mqtt"`
+	Kinesis       KinesisConfig    `yaml:"kinesis"`
+	Webhook       WebhookConfig    `yaml:"webhook"`
+	Retry         RetryConfig      `yaml:"retry"`
+	Buffer        BufferConfig     `yaml:"buffer"`
+	Encryption    EncryptionConfig `yaml:"encryption"`
+	// driverが "fanout" の場合の発行先（YAMLでのみ設定可能）
+	Targets []EventTargetConfig `yaml:"targets"`
+	// ロケーションごとの発行先（YAMLでのみ設定可能。fanoutの場合は発行先ごとに指定）
+	Routes []EventRouteConfig `yaml:"routes"`
+}
+
+// EventTargetConfig fanoutドライバーの発行先設定
+type EventTargetConfig struct {
+	Name        string             `yaml:"name"`
+	Driver      string             `yaml:"driver"`
+	EventTypes  []string           `yaml:"event_types"`
+	LocationIDs []string           `yaml:"location_ids"`
+	Routes      []EventRouteConfig `yaml:"routes"`
+}
+
+// EventRouteConfig ロケーションごとの発行先設定
+type EventRouteConfig struct {
+	LocationIDs []string `yaml:"location_ids"`
+	Destination string   `yaml:"destination"`
+}
+
+// RabbitMQConfig RabbitMQ発行設定
+type RabbitMQConfig struct {
+	URL             string        `yaml:"url" env:"EVENTS_RABBITMQ_URL"`
+	Exchange        string        `yaml:"exchange" env:"EVENTS_RABBITMQ_EXCHANGE"`
+	ExchangeType    string        `yaml:"exchange_type" env:"EVENTS_RABBITMQ_EXCHANGE_TYPE"`
+	DeclareExchange bool          `yaml:"declare_exchange" env:"EVENTS_RABBITMQ_DECLARE_EXCHANGE"`
+	RoutingKey      string        `yaml:"routing_key" env:"EVENTS_RABBITMQ_ROUTING_KEY"`
+	Confirm         bool          `yaml:"confirm" env:"EVENTS_RABBITMQ_CONFIRM"`
+	ConfirmTimeout  time.Duration `yaml:"confirm_timeout" env:"EVENTS_RABBITMQ_CONFIRM_TIMEOUT"`
+}
+
+// PubSubConfig Google Cloud Pub/Sub発行設定
+type PubSubConfig struct {
+	ProjectID       string `yaml:"project_id" env:"EVENTS_PUBSUB_PROJECT_ID"`
+	Mode            string `yaml:"mode" env:"EVENTS_PUBSUB_MODE"`
+	Topic           string `yaml:"topic" env:"EVENTS_PUBSUB_TOPIC"`
+	TopicTemplate   string `yaml:"topic_template" env:"EVENTS_PUBSUB_TOPIC_TEMPLATE"`
+	Ordering        bool   `yaml:"ordering" env:"EVENTS_PUBSUB_ORDERING"`
+	CredentialsFile string `yaml:"credentials_file" env:"EVENTS_PUBSUB_CREDENTIALS_FILE"`
+}
+
+// MQTTConfig MQTT発行設定
+type MQTTConfig struct {
+	URL            string        `yaml:"url" env:"EVENTS_MQTT_URL"`
+	ClientID       string        `yaml:"client_id" env:"EVENTS_MQTT_CLIENT_ID"`
+	Username       string        `yaml:"username" env:"EVENTS_MQTT_USERNAME"`
+	Password       string        `yaml:"password" env:"EVENTS_MQTT_PASSWORD"`
+	Topic          string        `yaml:"topic" env:"EVENTS_MQTT_TOPIC"`
+	QoS            int           `yaml:"qos" env:"EVENTS_MQTT_QOS"`
+	Retain         bool          `yaml:"retain" env:"EVENTS_MQTT_RETAIN"`
+	KeepAlive      time.Duration `yaml:"keep_alive" env:"EVENTS_MQTT_KEEP_ALIVE"`
+	ConnectTimeout time.Duration `yaml:"connect_timeout" env:"EVENTS_MQTT_CONNECT_TIMEOUT"`
+	AckTimeout     time.Duration `yaml:"ack_timeout" env:"EVENTS_MQTT_ACK_TIMEOUT"`
+}
+
+// KinesisConfig AWS Kinesis Data Streams発行設定
+type KinesisConfig struct {
+	Region          string        `yaml:"region" env:"EVENTS_KINESIS_REGION"`
+	Stream          string        `yaml:"stream" env:"EVENTS_KINESIS_STREAM"`
+	Endpoint        string        `yaml:"endpoint" env:"EVENTS_KINESIS_ENDPOINT"`
+	AccessKeyID     string        `yaml:"access_key_id" env:"EVENTS_KINESIS_ACCESS_KEY_ID"`
+	SecretAccessKey string        `yaml:"secret_access_key" env:"EVENTS_KINESIS_SECRET_ACCESS_KEY"`
+	SessionToken    string        `yaml:"session_token" env:"EVENTS_KINESIS_SESSION_TOKEN"`
+	Timeout         time.Duration `yaml:"timeout" env:"EVENTS_KINESIS_TIMEOUT"`
+}
+
+// WebhookConfig Webhook配信設定
+type WebhookConfig struct {
+	Workers     int           `yaml:"workers" env:"EVENTS_WEBHOOK_WORKERS"`
+	QueueSize   int           `yaml:"queue_size" env:"EVENTS_WEBHOOK_QUEUE_SIZE"`
+	MaxAttempts int           `yaml:"max_attempts" env:"EVENTS_WEBHOOK_MAX_ATTEMPTS"`
+	BaseDelay   time.Duration `yaml:"base_delay" env:"EVENTS_WEBHOOK_BASE_DELAY"`
+	MaxDelay    time.Duration `yaml:"max_delay" env:"EVENTS_WEBHOOK_MAX_DELAY"`
+	Timeout     time.Duration `yaml:"timeout" env:"EVENTS_WEBHOOK_TIMEOUT"`
+	// ループバック・リンクローカル・プライベートアドレスへの配信を許可する（社内の受信先の場合のみ）
+	AllowPrivateNetworks bool `yaml:"allow_private_networks" env:"EVENTS_WEBHOOK_ALLOW_PRIVATE_NETWORKS"`
+}
+
+// RetryConfig イベント発行失敗時の再試行設定
+type RetryConfig struct {
+	Enabled     bool          `yaml:"enabled" env:"EVENTS_RETRY_ENABLED"`
+	MaxAttempts int           `yaml:"max_attempts" env:"EVENTS_RETRY_MAX_ATTEMPTS"`
+	BaseDelay   time.Duration `yaml:"base_delay" env:"EVENTS_RETRY_BASE_DELAY"`
+	MaxDelay    time.Duration `yaml:"max_delay" env:"EVENTS_RETRY_MAX_DELAY"`
+	Interval    time.Duration `yaml:"interval" env:"EVENTS_RETRY_INTERVAL"`
+	BatchSize   int           `yaml:"batch_size" env:"EVENTS_RETRY_BATCH_SIZE"`
+}
+
+// BufferConfig イベント発行のバッファ設定
+type BufferConfig struct {
+	Enabled      bool          `yaml:"enabled" env:"EVENTS_BUFFER_ENABLED"`
+	Capacity     int           `yaml:"capacity" env:"EVENTS_BUFFER_CAPACITY"`
+	Path         string        `yaml:"path" env:"EVENTS_BUFFER_PATH"`
+	BaseDelay    time.Duration `yaml:"base_delay" env:"EVENTS_BUFFER_BASE_DELAY"`
+	MaxDelay     time.Duration `yaml:"max_delay" env:"EVENTS_BUFFER_MAX_DELAY"`
+	Timeout      time.Duration `yaml:"timeout" env:"EVENTS_BUFFER_TIMEOUT"`
+	DrainTimeout time.Duration `yaml:"drain_timeout" env:"EVENTS_BUFFER_DRAIN_TIMEOUT"`
+}
+
+// EncryptionConfig イベント本体の暗号化設定
+type EncryptionConfig struct {
+	Enabled bool   `yaml:"enabled" env:"EVENTS_ENCRYPTION_ENABLED"`
+	KeyID   string `yaml:"key_id" env:"EVENTS_ENCRYPTION_KEY_ID"`
+	// Base64エンコードした32バイトの鍵暗号化鍵
+	Key string `yaml:"key" env:"EVENTS_ENCRYPTION_KEY"`
+}
+
+// NotificationsConfig アラート通知設定
+type NotificationsConfig struct {
+	Workers     int           `yaml:"workers" env:"NOTIFICATIONS_WORKERS"`
+	QueueSize   int           `yaml:"queue_size" env:"NOTIFICATIONS_QUEUE_SIZE"`
+	MaxAttempts int           `yaml:"max_attempts" env:"NOTIFICATIONS_MAX_ATTEMPTS"`
+	BaseDelay   time.Duration `yaml:"base_delay" env:"NOTIFICATIONS_BASE_DELAY"`
+	MaxDelay    time.Duration `yaml:"max_delay" env:"NOTIFICATIONS_MAX_DELAY"`
+	Timeout     time.Duration `yaml:"timeout" env:"NOTIFICATIONS_TIMEOUT"`
+	// 作成されたアラートの通知先（YAMLでのみ設定可能。未指定の場合は通知しない）
+	Channels []NotificationChannelConfig `yaml:"channels"`
+}
+
+// NotificationChannelConfig アラートの通知先設定
+type NotificationChannelConfig struct {
+	Name string `yaml:"name"`
+	// 通知方法（email | slack | webhook）
+	Type string `yaml:"type"`
+	// 通知するアラートタイプ・重要度（空の場合は全て）
+	AlertTypes []string `yaml:"alert_types"`
+	Severities []string `yaml:"severities"`
+	// 件名・本文のテンプレート（text/template、空の場合はデフォルト）
+	Subject string `yaml:"subject"`
+	Body    string `yaml:"body"`
+	// slack・webhook の送信先URLと webhook の追加のヘッダー
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+	SMTP    SMTPConfig        `yaml:"smtp"`
+}
+
+// SMTPConfig メール通知のSMTP設定
+type SMTPConfig struct {
+	Host     string   `yaml:"host"`
+	Port     int      `yaml:"port"`
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+}
+
+// SchedulerConfig 定期ジョブ設定
+//
+// スケジュールは "@every 15m"・"@hourly"・"@daily" または5項目のcron式（分 時 日 月 曜日）で指定し、空の場合はそのジョブを実行しない。
+type SchedulerConfig struct {
+	// このプロセスで定期ジョブを実行するか（複数のAPIサーバーを起動する場合は1台のみ有効にする）
+	Enabled bool `yaml:"enabled" env:"SCHEDULER_ENABLED"`
+	// 期限切れ間近ロットの通知（lot.expiring イベント）と通知対象の期間
+	ExpiryScan   string        `yaml:"expiry_scan" env:"SCHEDULER_EXPIRY_SCAN"`
+	ExpiryWindow time.Duration `yaml:"expiry_window" env:"SCHEDULER_EXPIRY_WINDOW"`
+	// アクティブなアラートのない低在庫の検出
+	LowStockSweep string `yaml:"low_stock_sweep" env:"SCHEDULER_LOW_STOCK_SWEEP"`
+	// 作成から inventory.alert_timeout_hours を過ぎたアラートの自動解決
+	AlertTimeout string `yaml:"alert_timeout" env:"SCHEDULER_ALERT_TIMEOUT"`
+	// 全ての在庫記録のスナップショット
+	StockSnapshot string `yaml:"stock_snapshot" env:"SCHEDULER_STOCK_SNAPSHOT"`
+	// 先の月のトランザクションパーティションの作成とデフォルトパーティションの確認
+	TransactionPartitions string `yaml:"transaction_partitions" env:"SCHEDULER_TRANSACTION_PARTITIONS"`
+}
+
+// ConsumerConfig 外部システムからの在庫同期メッセージの受信設定
+type ConsumerConfig struct {
+	// RabbitMQのキューから受信するか（HTTPの同期エンドポイントは常に有効）
+	Enabled bool `yaml:"enabled" env:"CONSUMER_ENABLED"`
+	// 処理中の冪等性キーを他の配信から保護する時間
+	ClaimTTL time.Duration          `yaml:"claim_ttl" env:"CONSUMER_CLAIM_TTL"`
+	RabbitMQ ConsumerRabbitMQConfig `yaml:"rabbitmq"`
+}
+
+// ConsumerRabbitMQConfig 同期メッセージを受信するRabbitMQの設定
+type ConsumerRabbitMQConfig struct {
+	URL          string        `yaml:"url" env:"CONSUMER_RABBITMQ_URL"`
+	Queue        string        `yaml:"queue" env:"CONSUMER_RABBITMQ_QUEUE"`
+	DeclareQueue bool          `yaml:"declare_queue" env:"CONSUMER_RABBITMQ_DECLARE_QUEUE"`
+	Prefetch     int           `yaml:"prefetch" env:"CONSUMER_RABBITMQ_PREFETCH"`
+	RequeueDelay time.Duration `yaml:"requeue_delay" env:"CONSUMER_RABBITMQ_REQUEUE_DELAY"`
 }
 
 // LogConfig ログ設定
@@ -65,14 +420,51 @@ func Load() (*Config, error) {
 			Port:   5432,
 			User:   "postgres",
 			DBName: "inventory",
+
+			MaxOpenConns:    25,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: 5 * time.Minute,
 		},
 		API: APIConfig{
-			Port:         8080,
-			ReadTimeout:  30 * time.Second,
-			WriteTimeout: 30 * time.Second,
-			IdleTimeout:  60 * time.Second,
-			EnableCORS:   true,
-			EnableAuth:   false,
+			Port:          8080,
+			ReadTimeout:   30 * time.Second,
+			WriteTimeout:  30 * time.Second,
+			IdleTimeout:   60 * time.Second,
+			EnableCORS:    true,
+			EnableAuth:    false,
+			EnableMetrics: true,
+			MaxBodyBytes:  1 << 20,
+			Auth: AuthConfig{
+				UserClaim:           "sub",
+				Leeway:              time.Minute,
+				JWKSRefreshInterval: time.Hour,
+			},
+			RateLimit: RateLimitConfig{
+				RequestsPerSecond:      50,
+				Burst:                  100,
+				WriteRequestsPerSecond: 20,
+				WriteBurst:             40,
+				TrustedProxies:         1,
+				IPRequestsPerSecond:    100,
+				IPBurst:                200,
+				IdleTTL:                10 * time.Minute,
+			},
+			TLS: TLSConfig{
+				MinVersion: "1.2",
+			},
+		},
+		GRPC: GRPCConfig{
+			Port:       9090,
+			Reflection: true,
+		},
+		GraphQL: GraphQLConfig{
+			Enabled:  true,
+			MaxDepth: 10,
+		},
+		Tracing: TracingConfig{
+			Endpoint:    "http://localhost:9411/api/v2/spans",
+			ServiceName: "zai-inventory-api",
+			SampleRatio: 1,
 		},
 		Inventory: InventoryConfig{
 			AllowNegativeStock: false,
@@ -80,6 +472,98 @@ func Load() (*Config, error) {
 			AuditEnabled:       true,
 			LowStockThreshold:  10,
 			AlertTimeoutHours:  24,
+			LockingStrategy:    "optimistic",
+			RetryMaxAttempts:   3,
+			RetryBaseDelay:     10 * time.Millisecond,
+			RetryMaxDelay:      200 * time.Millisecond,
+			RetryJitter:        0.5,
+			BatchWorkers:          2,
+			BatchQueueSize:        100,
+			BatchProgressInterval: 100,
+
+			ReservationExpiryInterval: time.Minute,
+			CapacityPolicy:            "warn",
+			AlertRuleInterval:         5 * time.Minute,
+			LotPickingStrategy:        "fefo",
+		},
+		Events: EventsConfig{
+			Format:        "json",
+			Source:        "/zaigoframework/inventory",
+			SchemaVersion: 3,
+			RabbitMQ: RabbitMQConfig{
+				Exchange:        "inventory",
+				ExchangeType:    "topic",
+				DeclareExchange: true,
+				RoutingKey:      "inventory.{type}",
+				Confirm:         true,
+				ConfirmTimeout:  5 * time.Second,
+			},
+			PubSub: PubSubConfig{
+				Mode:          "single",
+				Topic:         "inventory-events",
+				TopicTemplate: "inventory-{type}",
+				Ordering:      true,
+			},
+			MQTT: MQTTConfig{
+				Topic:          "inventory/{location}/{item}/{type}",
+				QoS:            1,
+				KeepAlive:      60 * time.Second,
+				ConnectTimeout: 10 * time.Second,
+				AckTimeout:     5 * time.Second,
+			},
+			Kinesis: KinesisConfig{
+				Stream:  "inventory-events",
+				Timeout: 10 * time.Second,
+			},
+			Webhook: WebhookConfig{
+				Workers:     4,
+				QueueSize:   1000,
+				MaxAttempts: 5,
+				BaseDelay:   time.Second,
+				MaxDelay:    time.Minute,
+				Timeout:     10 * time.Second,
+			},
+			Retry: RetryConfig{
+				Enabled:     true,
+				MaxAttempts: 10,
+				BaseDelay:   5 * time.Second,
+				MaxDelay:    10 * time.Minute,
+				Interval:    5 * time.Second,
+				BatchSize:   100,
+			},
+			Buffer: BufferConfig{
+				Capacity:     10000,
+				BaseDelay:    500 * time.Millisecond,
+				MaxDelay:     30 * time.Second,
+				Timeout:      10 * time.Second,
+				DrainTimeout: 5 * time.Second,
+			},
+		},
+		Consumer: ConsumerConfig{
+			ClaimTTL: 5 * time.Minute,
+			RabbitMQ: ConsumerRabbitMQConfig{
+				Queue:        "inventory-sync",
+				DeclareQueue: true,
+				Prefetch:     10,
+				RequeueDelay: 5 * time.Second,
+			},
+		},
+		Scheduler: SchedulerConfig{
+			Enabled:               true,
+			ExpiryScan:            "0 6 * * *",
+			ExpiryWindow:          7 * 24 * time.Hour,
+			LowStockSweep:         "@every 15m",
+			AlertTimeout:          "@every 1h",
+			StockSnapshot:         "@daily",
+			TransactionPartitions: "@daily",
+		},
+		Notifications: NotificationsConfig{
+			Workers:     2,
+			QueueSize:   1000,
+			MaxAttempts: 3,
+			BaseDelay:   time.Second,
+			MaxDelay:    30 * time.Second,
+			Timeout:     10 * time.Second,
 		},
 		Log: LogConfig{
 			Level:      "info",
@@ -199,12 +683,29 @@ func setFieldValue(field reflect.Value, value string) error {
 			}
 			field.SetInt(intVal)
 		}
+	case reflect.Float32, reflect.Float64:
+		floatVal, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(floatVal)
 	case reflect.Bool:
 		boolVal, err := strconv.ParseBool(value)
 		if err != nil {
 			return err
 		}
 		field.SetBool(boolVal)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("サポートされていない型: %s", field.Type())
+		}
+		var values []string
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		field.Set(reflect.ValueOf(values))
 	default:
 		return fmt.Errorf("サポートされていない型: %s", field.Kind())
 	}
@@ -227,11 +728,76 @@ func (c *Config) validate() error {
 	if c.Database.DBName == "" {
 		return fmt.Errorf("データベース名が指定されていません")
 	}
+	if c.Database.MaxOpenConns < 0 {
+		return fmt.Errorf("最大接続数は0以上である必要があります")
+	}
+	if c.Database.MaxIdleConns < 0 {
+		return fmt.Errorf("最大アイドル接続数は0以上である必要があります")
+	}
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		return fmt.Errorf("最大アイドル接続数は最大接続数以下である必要があります")
+	}
+	if c.Database.ConnMaxLifetime < 0 {
+		return fmt.Errorf("接続の最大存続時間は0以上である必要があります")
+	}
 
 	// API設定チェック
 	if c.API.Port <= 0 || c.API.Port > 65535 {
 		return fmt.Errorf("無効なAPIポート: %d", c.API.Port)
 	}
+	if c.API.EnableAuth && c.API.Auth.JWKSURL == "" && c.API.Auth.HMACSecret == "" && !c.API.Auth.APIKeysEnabled {
+		return fmt.Errorf("認証を有効にする場合はJWKSのURL・HMACの鍵・APIキー認証のいずれかを指定してください")
+	}
+	for _, version := range c.API.Versions {
+		if !version.Deprecation.IsZero() && !version.Sunset.IsZero() && version.Sunset.Before(version.Deprecation) {
+			return fmt.Errorf("APIバージョン %s の提供終了日が非推奨にした日より前です", version.Name)
+		}
+	}
+	if c.API.MaxBodyBytes < 0 {
+		return fmt.Errorf("リクエスト本文の上限は0以上である必要があります")
+	}
+	if c.API.EnableDebug && !c.API.EnableAuth {
+		return fmt.Errorf("デバッグ用のエンドポイントを有効にする場合は認証を有効にしてください")
+	}
+	if c.API.EnableAuth && c.API.Auth.UserClaim == "" {
+		return fmt.Errorf("ユーザーIDとするクレームが指定されていません")
+	}
+	if c.API.RateLimit.Enabled && (c.API.RateLimit.RequestsPerSecond <= 0 || c.API.RateLimit.Burst <= 0) {
+		return fmt.Errorf("頻度制限のリクエスト数とバースト数は正の値を指定してください")
+	}
+	if c.API.RateLimit.Enabled && c.API.RateLimit.WriteRequestsPerSecond > 0 && c.API.RateLimit.WriteBurst <= 0 {
+		return fmt.Errorf("更新系の頻度制限のバースト数は正の値を指定してください")
+	}
+	if c.API.RateLimit.Enabled && c.API.RateLimit.IPRequestsPerSecond > 0 && c.API.RateLimit.IPBurst <= 0 {
+		return fmt.Errorf("IPアドレスごとの頻度制限のバースト数は正の値を指定してください")
+	}
+	if c.API.RateLimit.Enabled && c.API.RateLimit.TrustForwardedFor && c.API.RateLimit.TrustedProxies < 1 {
+		return fmt.Errorf("X-Forwarded-For を信頼する場合はリバースプロキシの段数に1以上を指定してください")
+	}
+	if (c.API.TLS.CertFile == "") != (c.API.TLS.KeyFile == "") {
+		return fmt.Errorf("HTTPSの証明書と秘密鍵は両方指定してください")
+	}
+	if c.API.TLS.ClientCAFile != "" && !c.API.TLS.Enabled() {
+		return fmt.Errorf("クライアント証明書認証にはHTTPSの証明書の指定が必要です")
+	}
+	if c.API.TLS.MinVersion != "1.2" && c.API.TLS.MinVersion != "1.3" {
+		return fmt.Errorf("無効なTLSの最小バージョン: %s（1.2 または 1.3 を指定してください）", c.API.TLS.MinVersion)
+	}
+	if c.GRPC.Port <= 0 || c.GRPC.Port > 65535 {
+		return fmt.Errorf("無効なgRPCポート: %d", c.GRPC.Port)
+	}
+	if (c.GRPC.TLSCertFile == "") != (c.GRPC.TLSKeyFile == "") {
+		return fmt.Errorf("gRPCのTLSの証明書と秘密鍵は両方指定してください")
+	}
+	if c.GraphQL.Enabled && c.GraphQL.MaxDepth <= 0 {
+		return fmt.Errorf("GraphQLクエリの深さの上限は1以上である必要があります")
+	}
+	if c.Tracing.Enabled && c.Tracing.Endpoint == "" {
+		return fmt.Errorf("トレースの送信先が指定されていません")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		return fmt.Errorf("トレースの記録割合は0から1の範囲で指定してください: %g", c.Tracing.SampleRatio)
+	}
 
 	// 在庫設定チェック
 	if c.Inventory.DefaultLocation == "" {
@@ -240,6 +806,142 @@ func (c *Config) validate() error {
 	if c.Inventory.LowStockThreshold < 0 {
 		return fmt.Errorf("低在庫閾値は0以上である必要があります")
 	}
+	validLockingStrategies := map[string]bool{
+		"optimistic": true, "pessimistic": true,
+	}
+	if !validLockingStrategies[c.Inventory.LockingStrategy] {
+		return fmt.Errorf("無効なロック方式: %s", c.Inventory.LockingStrategy)
+	}
+	validCapacityPolicies := map[string]bool{
+		"warn": true, "enforce": true, "ignore": true,
+	}
+	if !validCapacityPolicies[c.Inventory.CapacityPolicy] {
+		return fmt.Errorf("無効な容量超過時の扱い: %s", c.Inventory.CapacityPolicy)
+	}
+	validLotPickingStrategies := map[string]bool{
+		"fefo": true, "fifo": true, "lifo": true,
+	}
+	if !validLotPickingStrategies[c.Inventory.LotPickingStrategy] {
+		return fmt.Errorf("無効なロットの消費順序: %s", c.Inventory.LotPickingStrategy)
+	}
+	if c.Inventory.RetryMaxAttempts < 0 {
+		return fmt.Errorf("リトライ回数は0以上である必要があります")
+	}
+	if c.Inventory.RetryJitter < 0 || c.Inventory.RetryJitter > 1 {
+		return fmt.Errorf("リトライのゆらぎ率は0から1の範囲である必要があります")
+	}
+	if c.Inventory.AdjustmentApprovalThreshold < 0 {
+		return fmt.Errorf("在庫調整の承認閾値は0以上である必要があります")
+	}
+	if c.Inventory.RetentionMonths < 0 {
+		return fmt.Errorf("トランザクション保持月数は0以上である必要があります")
+	}
+	if c.Inventory.BatchWorkers <= 0 || c.Inventory.BatchQueueSize <= 0 || c.Inventory.BatchProgressInterval <= 0 {
+		return fmt.Errorf("非同期バッチの並行数・キューの上限・進捗の間隔は1以上である必要があります")
+	}
+	if c.Inventory.ReservationTTL < 0 {
+		return fmt.Errorf("予約の有効期間は0以上である必要があります")
+	}
+	if c.Inventory.ReservationExpiryInterval <= 0 {
+		return fmt.Errorf("期限切れ予約の解放間隔は正の値である必要があります")
+	}
+	if c.Inventory.AlertRuleInterval <= 0 {
+		return fmt.Errorf("アラートルールの評価間隔は正の値である必要があります")
+	}
+
+	// イベント設定チェック
+	validEventDrivers := map[string]bool{
+		"": true, "none": true, "rabbitmq": true, "pubsub": true, "mqtt": true, "kinesis": true, "webhook": true, "fanout": true,
+	}
+	if !validEventDrivers[c.Events.Driver] {
+		return fmt.Errorf("無効なイベントドライバー: %s", c.Events.Driver)
+	}
+	if c.Events.Driver == "fanout" && len(c.Events.Targets) == 0 {
+		return fmt.Errorf("fanoutドライバーには発行先（events.targets）の指定が必要です")
+	}
+	if err := validateEventRoutes(c.Events.Routes); err != nil {
+		return err
+	}
+	validTargetDrivers := map[string]bool{
+		"rabbitmq": true, "pubsub": true, "mqtt": true, "kinesis": true, "webhook": true,
+	}
+	validEventTypes := map[string]bool{
+		"stock.changed": true, "stock.low_alert": true, "item.transferred": true,
+		"reservation.created": true, "reservation.released": true,
+		"reservation.expired": true, "reservation.fulfilled": true,
+		"backorder.created": true, "backorder.allocated": true, "backorder.cancelled": true,
+		"alert.created": true, "alert.acknowledged": true, "alert.resolved": true,
+		"item.created": true, "item.updated": true, "item.deleted": true,
+		"location.created": true, "location.updated": true, "location.deleted": true,
+		"lot.created": true, "lot.expiring": true, "batch.completed": true,
+		"adjustment.pending": true, "adjustment.approved": true, "adjustment.rejected": true,
+		"stock.quarantined": true, "inspection.passed": true, "inspection.failed": true,
+		"return.received": true, "return.dispositioned": true,
+	}
+	for _, target := range c.Events.Targets {
+		if !validTargetDrivers[target.Driver] {
+			return fmt.Errorf("無効な発行先ドライバー: %s", target.Driver)
+		}
+		if err := validateEventRoutes(target.Routes); err != nil {
+			return err
+		}
+		for _, eventType := range target.EventTypes {
+			if !validEventTypes[eventType] {
+				return fmt.Errorf("無効なイベントタイプ: %s", eventType)
+			}
+		}
+	}
+	validEventFormats := map[string]bool{
+		"json": true, "cloudevents": true,
+	}
+	if !validEventFormats[c.Events.Format] {
+		return fmt.Errorf("無効なイベント形式: %s", c.Events.Format)
+	}
+	if c.Events.SchemaVersion < 1 || c.Events.SchemaVersion > 3 {
+		return fmt.Errorf("無効なイベントスキーマバージョン: %d", c.Events.SchemaVersion)
+	}
+	validPubSubModes := map[string]bool{
+		"single": true, "per_type": true,
+	}
+	if !validPubSubModes[c.Events.PubSub.Mode] {
+		return fmt.Errorf("無効なPub/Subトピック構成: %s", c.Events.PubSub.Mode)
+	}
+	if c.Events.MQTT.QoS < 0 || c.Events.MQTT.QoS > 2 {
+		return fmt.Errorf("無効なMQTTのQoS: %d", c.Events.MQTT.QoS)
+	}
+	if c.Events.Webhook.Workers <= 0 || c.Events.Webhook.QueueSize <= 0 || c.Events.Webhook.MaxAttempts <= 0 {
+		return fmt.Errorf("Webhookのワーカー数・キュー長・試行回数は1以上である必要があります")
+	}
+	if c.Events.Retry.MaxAttempts <= 0 || c.Events.Retry.BatchSize <= 0 {
+		return fmt.Errorf("イベント再試行の試行回数・件数は1以上である必要があります")
+	}
+	if c.Events.Buffer.Enabled && c.Events.Buffer.Capacity <= 0 {
+		return fmt.Errorf("イベントバッファの容量は1以上である必要があります")
+	}
+	if c.Events.Encryption.Enabled && (c.Events.Encryption.KeyID == "" || c.Events.Encryption.Key == "") {
+		return fmt.Errorf("イベントの暗号化には鍵IDと鍵の指定が必要です")
+	}
+
+	// アラート通知設定チェック
+	if err := c.Notifications.validate(); err != nil {
+		return err
+	}
+
+	// 定期ジョブ設定チェック（スケジュールの形式は起動時に検証する）
+	if c.Scheduler.ExpiryScan != "" && c.Scheduler.ExpiryWindow <= 0 {
+		return fmt.Errorf("期限切れ間近ロットの通知期間は正の値である必要があります")
+	}
+	if c.Inventory.AlertTimeoutHours < 0 {
+		return fmt.Errorf("アラートのタイムアウトは0以上である必要があります")
+	}
+
+	// 同期メッセージ受信設定チェック
+	if c.Consumer.Enabled && (c.Consumer.RabbitMQ.URL == "" || c.Consumer.RabbitMQ.Queue == "") {
+		return fmt.Errorf("同期メッセージの受信にはRabbitMQの接続URLとキューが必要です")
+	}
+	if c.Consumer.ClaimTTL <= 0 {
+		return fmt.Errorf("冪等性キーの保護時間は正の値である必要があります")
+	}
 
 	// ログ設定チェック
 	validLogLevels := map[string]bool{
@@ -271,8 +973,84 @@ func (c *Config) DSN() string {
 	)
 }
 
+// ReplicaDSNs generates Data Source Names for the read replicas
+// 読み取りレプリカのデータソース名を生成
+//
+// レプリカのユーザー・パスワード・データベース名はプライマリと同じものを使用します。
+// ポートを省略したホストにはプライマリのポートを使用します。
+func (c *Config) ReplicaDSNs() []string {
+	dsns := make([]string, 0, len(c.Database.ReplicaHosts))
+	for _, replica := range c.Database.ReplicaHosts {
+		host, port := replica, strconv.Itoa(c.Database.Port)
+		if h, p, err := net.SplitHostPort(replica); err == nil {
+			host, port = h, p
+		}
+		dsns = append(dsns, fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+			host,
+			port,
+			c.Database.User,
+			c.Database.Password,
+			c.Database.DBName,
+		))
+	}
+	return dsns
+}
+
 // ヘルパー関数
 
+// validate checks the delivery settings and every notification channel
+// アラート通知の配信設定と通知先を確認
+func (n NotificationsConfig) validate() error {
+	if n.Workers <= 0 || n.QueueSize <= 0 || n.MaxAttempts <= 0 {
+		return fmt.Errorf("アラート通知のワーカー数・キュー長・試行回数は1以上である必要があります")
+	}
+	if n.Timeout <= 0 {
+		return fmt.Errorf("アラート通知のタイムアウトは正の値である必要があります")
+	}
+	validAlertTypes := map[string]bool{
+		"low_stock": true, "over_stock": true, "expiring": true, "expired": true, "discrepancy": true,
+	}
+	validSeverities := map[string]bool{
+		"info": true, "warning": true, "critical": true,
+	}
+	for _, channel := range n.Channels {
+		switch channel.Type {
+		case "email":
+			if channel.SMTP.Host == "" || channel.SMTP.From == "" || len(channel.SMTP.To) == 0 {
+				return fmt.Errorf("通知先 %s にはSMTPサーバー・送信元・宛先（smtp.host・smtp.from・smtp.to）の指定が必要です", channel.Name)
+			}
+		case "slack", "webhook":
+			if channel.URL == "" {
+				return fmt.Errorf("通知先 %s には送信先URL（url）の指定が必要です", channel.Name)
+			}
+		default:
+			return fmt.Errorf("無効な通知方法: %s", channel.Type)
+		}
+		for _, alertType := range channel.AlertTypes {
+			if !validAlertTypes[alertType] {
+				return fmt.Errorf("無効なアラートタイプ: %s", alertType)
+			}
+		}
+		for _, severity := range channel.Severities {
+			if !validSeverities[severity] {
+				return fmt.Errorf("無効な重要度: %s", severity)
+			}
+		}
+	}
+	return nil
+}
+
+// validateEventRoutes checks that every route has locations and a destination
+// ルーティングルールにロケーションと発行先が指定されているかを確認
+func validateEventRoutes(routes []EventRouteConfig) error {
+	for i, route := range routes {
+		if len(route.LocationIDs) == 0 || route.Destination == "" {
+			return fmt.Errorf("ルーティングルール %d にはロケーションID（location_ids）と発行先（destination）の指定が必要です", i+1)
+		}
+	}
+	return nil
+}
+
 // getEnv gets environment variable with default value
 // デフォルト値付きで環境変数を取得
 func getEnv(key, defaultValue string) string {
@@ -0,0 +1,123 @@
+package label
+
+import (
+	"errors"
+)
+
+// ErrCode128Character is returned when a value has a character Code128 code set B cannot encode
+// Code128（コードセットB）で表せない文字を含む値のエラー
+var ErrCode128Character = errors.New("Code128で表せない文字が含まれています（ASCIIの印字可能文字のみ使用できます）")
+
+// Code128 symbol values of the start, code set switch and stop characters
+// Code128のスタート・コードセット切り替え・ストップのシンボル値
+const (
+	code128CodeC  = 99
+	code128CodeB  = 100
+	code128StartB = 104
+	code128StartC = 105
+	code128Stop   = 106
+)
+
+// code128Patterns are the bar and space widths of the Code128 symbol values (the stop character has 7 elements)
+// Code128のシンボル値ごとのバー・スペースの幅（ストップキャラクタは7要素）
+var code128Patterns = []string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+// Code128 is the module widths of a Code128 symbol, alternating bars and spaces starting with a bar
+// Code128のシンボルのモジュール幅（バーから始まりバーとスペースが交互）
+type Code128 []int
+
+// Modules returns the width of the symbol in modules, without quiet zones
+// シンボルのモジュール数を返す（クワイエットゾーンを除く）
+func (c Code128) Modules() int {
+	total := 0
+	for _, width := range c {
+		total += width
+	}
+	return total
+}
+
+// EncodeCode128 encodes a value as Code128
+// 値をCode128に符号化
+//
+// 印字可能なASCII文字をコードセットBで符号化し、4桁以上（値の途中は6桁以上）続く数字は
+// コードセットCで2桁ずつ符号化してシンボルを短くします。
+func EncodeCode128(value string) (Code128, error) {
+	if value == "" {
+		return nil, ErrEmptyCode
+	}
+	for i := 0; i < len(value); i++ {
+		if value[i] < 32 || value[i] > 126 {
+			return nil, ErrCode128Character
+		}
+	}
+
+	var symbols []int
+	set := 0 // 0: 未開始、'B'・'C': 現在のコードセット
+	useSet := func(next int) {
+		if set == next {
+			return
+		}
+		switch {
+		case set == 0 && next == 'B':
+			symbols = append(symbols, code128StartB)
+		case set == 0:
+			symbols = append(symbols, code128StartC)
+		case next == 'B':
+			symbols = append(symbols, code128CodeB)
+		default:
+			symbols = append(symbols, code128CodeC)
+		}
+		set = next
+	}
+
+	for i := 0; i < len(value); {
+		digits := 0
+		for i+digits < len(value) && value[i+digits] >= '0' && value[i+digits] <= '9' {
+			digits++
+		}
+		edge := i == 0 || i+digits == len(value)
+		if digits >= 6 || (edge && digits >= 4) {
+			if digits%2 == 1 {
+				// 奇数桁は先頭の1桁をコードセットBで符号化
+				useSet('B')
+				symbols = append(symbols, int(value[i])-32)
+				i++
+				digits--
+			}
+			useSet('C')
+			for end := i + digits; i < end; i += 2 {
+				symbols = append(symbols, int(value[i]-'0')*10+int(value[i+1]-'0'))
+			}
+			continue
+		}
+		useSet('B')
+		symbols = append(symbols, int(value[i])-32)
+		i++
+	}
+
+	checksum := symbols[0]
+	for i, symbol := range symbols[1:] {
+		checksum += symbol * (i + 1)
+	}
+	symbols = append(symbols, checksum%103, code128Stop)
+
+	var widths Code128
+	for _, symbol := range symbols {
+		for _, width := range code128Patterns[symbol] {
+			widths = append(widths, int(width-'0'))
+		}
+	}
+	return widths, nil
+}
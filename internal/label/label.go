@@ -0,0 +1,378 @@
+// Package label renders printable barcode and QR code labels as PDF
+// バーコード・QRコードの印刷用ラベルをPDFとして出力するパッケージ
+//
+// 用紙上のラベルの配置・バーコードの種類・表示する文字列をテンプレートで指定します。
+// 1枚のラベルを1ページとするラベルプリンター用と、A4の用紙に複数のラベルを並べるシート用の
+// どちらにも使用できます。外部ライブラリを使用せずにCode128・QRコード（誤り訂正レベルM）を
+// 符号化し、PDFを生成します。
+package label
+
+import (
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Symbology is the kind of barcode printed on a label
+// ラベルに印刷するバーコードの種類
+type Symbology string
+
+const (
+	SymbologyCode128 Symbology = "code128" // Code128（1次元バーコード）
+	SymbologyQR      Symbology = "qr"      // QRコード
+)
+
+// Kinds of the objects labels are printed for
+// ラベルを印刷する対象の種類
+const (
+	KindItem     = "item"     // 商品
+	KindLot      = "lot"      // ロット
+	KindLocation = "location" // ロケーション（棚番）
+)
+
+// Layout limits of templates
+// テンプレートの配置の上限
+const (
+	maxLabelSize   = 1000.0 // ラベル・用紙の幅と高さの上限（mm）
+	maxGridSize    = 50     // 列数・行数の上限
+	maxLines       = 10     // 文字列の行数の上限
+	maxFontSize    = 72.0   // 文字サイズの上限（pt）
+	maxPages       = 1000   // 1つの文書のページ数の上限
+	lineSpacing    = 1.2    // 行の高さの文字サイズに対する比率
+	minModuleWidth = 0.19   // バーコードの最小モジュール幅（mm）
+	pointsPerMM    = 72 / 25.4
+)
+
+// DefaultFontSize is the font size used when a template does not specify one
+// テンプレートで指定しない場合の文字サイズ（pt）
+const DefaultFontSize = 8.0
+
+// Errors of templates and labels
+// テンプレート・ラベルのエラー
+var (
+	ErrInvalidSymbology = errors.New("サポートされていないバーコードの種類です（code128 または qr を指定してください）")
+	ErrInvalidSize      = errors.New("ラベルの幅と高さは0より大きく1000mm以下である必要があります")
+	ErrInvalidSpacing   = errors.New("ラベルの余白・間隔・列数・行数が正しくありません")
+	ErrLabelsOverflow   = errors.New("ラベルが用紙に収まりません")
+	ErrInvalidFontSize  = errors.New("文字サイズは0以上72pt以下である必要があります")
+	ErrTooManyLines     = errors.New("ラベルの文字列は10行までです")
+	ErrInvalidLine      = errors.New("ラベルの文字列のテンプレートが正しくありません")
+	ErrTextOverflow     = errors.New("文字列とバーコードがラベルに収まりません")
+	ErrBarcodeTooDense  = errors.New("バーコードがラベルの幅に収まりません")
+	ErrEmptyCode        = errors.New("ラベルのコードが空です")
+	ErrNoLabels         = errors.New("印刷するラベルが指定されていません")
+	ErrTooManyPages     = errors.New("ラベルのページ数が上限（1000ページ）を超えています")
+)
+
+// Template describes the layout of labels on a page (lengths are in millimetres)
+// 用紙上のラベルの配置を表現（長さの単位はmm）
+//
+// ラベルは用紙の左上から行ごとに並べます。PageWidth・PageHeight を省略した場合は
+// ラベルの配置と余白から用紙の大きさを決めます（ラベルプリンター用）。Lines は text/template の
+// 書式で、Label の項目（{{.Code}}・{{.Name}}・{{.Detail}}・{{index .Fields "sku"}} など）を参照できます。
+type Template struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Symbology   Symbology `json:"symbology"`      // code128・qr
+	PageWidth   float64   `json:"page_width_mm"`  // 用紙の幅（0の場合はラベルの配置から計算）
+	PageHeight  float64   `json:"page_height_mm"` // 用紙の高さ（0の場合はラベルの配置から計算）
+	LabelWidth  float64   `json:"label_width_mm"`
+	LabelHeight float64   `json:"label_height_mm"`
+	Columns     int       `json:"columns"`        // 1ページの列数（0の場合は1）
+	Rows        int       `json:"rows"`           // 1ページの行数（0の場合は1）
+	MarginLeft  float64   `json:"margin_left_mm"` // 用紙の左端から最初の列までの余白
+	MarginTop   float64   `json:"margin_top_mm"`  // 用紙の上端から最初の行までの余白
+	GapX        float64   `json:"gap_x_mm"`       // 列の間隔
+	GapY        float64   `json:"gap_y_mm"`       // 行の間隔
+	Padding     float64   `json:"padding_mm"`     // ラベルの内側の余白
+	FontSize    float64   `json:"font_size_pt"`   // 文字サイズ（0の場合は DefaultFontSize）
+	Lines       []string  `json:"lines"`          // 表示する文字列（1行ずつ）
+}
+
+// Label is the data printed on a label
+// ラベルに印刷するデータ
+type Label struct {
+	Kind   string            `json:"kind"`   // item・lot・location
+	Code   string            `json:"code"`   // バーコードに符号化する値（商品ID・ロットID・ロケーションID）
+	Name   string            `json:"name"`   // 名称
+	Detail string            `json:"detail"` // 補足（SKU・ロット番号と有効期限・ロケーションのタイプ）
+	Fields map[string]string `json:"fields"` // 種類ごとの項目
+}
+
+// DefaultTemplate is the name of the template used when a request does not name one
+// テンプレートを指定しない場合に使用するテンプレートの名前
+const DefaultTemplate = "a4-24-code128"
+
+// builtinTemplates are the templates available by name
+// 名前で指定できる組み込みのテンプレート
+var builtinTemplates = map[string]Template{
+	"a4-24-code128": {
+		Name: "a4-24-code128", Description: "A4用紙に24面（3列×8行、70×37mm）のCode128ラベル",
+		Symbology: SymbologyCode128, PageWidth: 210, PageHeight: 297, LabelWidth: 70, LabelHeight: 37,
+		Columns: 3, Rows: 8, MarginTop: 0.5, Padding: 3, FontSize: 8,
+		Lines: []string{"{{.Code}}", "{{.Name}}", "{{.Detail}}"},
+	},
+	"a4-24-qr": {
+		Name: "a4-24-qr", Description: "A4用紙に24面（3列×8行、70×37mm）のQRコードラベル",
+		Symbology: SymbologyQR, PageWidth: 210, PageHeight: 297, LabelWidth: 70, LabelHeight: 37,
+		Columns: 3, Rows: 8, MarginTop: 0.5, Padding: 3, FontSize: 8,
+		Lines: []string{"{{.Code}}", "{{.Name}}", "{{.Detail}}"},
+	},
+	"bin-qr": {
+		Name: "bin-qr", Description: "ラベルプリンター用の棚番ラベル（100×50mm、QRコード）",
+		Symbology: SymbologyQR, LabelWidth: 100, LabelHeight: 50, Padding: 3, FontSize: 14,
+		Lines: []string{"{{.Code}}", "{{.Name}}", "{{.Detail}}"},
+	},
+	"lot-code128": {
+		Name: "lot-code128", Description: "ラベルプリンター用のロットラベル（62×29mm、Code128）",
+		Symbology: SymbologyCode128, LabelWidth: 62, LabelHeight: 29, Padding: 2, FontSize: 7,
+		Lines: []string{"{{.Code}}", "{{.Name}} {{.Detail}}"},
+	},
+}
+
+// LookupTemplate returns a built-in template by name
+// 名前で組み込みのテンプレートを取得
+func LookupTemplate(name string) (Template, bool) {
+	t, ok := builtinTemplates[name]
+	if ok {
+		t.Lines = append([]string(nil), t.Lines...)
+	}
+	return t, ok
+}
+
+// Templates returns the built-in templates ordered by name
+// 組み込みのテンプレートを名前の昇順で返す
+func Templates() []Template {
+	templates := make([]Template, 0, len(builtinTemplates))
+	for name := range builtinTemplates {
+		t, _ := LookupTemplate(name)
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates
+}
+
+// layout is a template resolved to points with its defaults applied
+// 既定値を適用してポイント単位に変換したテンプレート
+type layout struct {
+	symbology               Symbology
+	pageWidth, pageHeight   float64
+	labelWidth, labelHeight float64
+	columns, rows           int
+	marginLeft, marginTop   float64
+	gapX, gapY              float64
+	padding                 float64
+	fontSize                float64
+	lines                   []*template.Template
+}
+
+// Validate checks that a template is complete and that its labels fit the page
+// テンプレートの項目が揃っており、ラベルが用紙に収まることを確認
+func (t *Template) Validate() error {
+	_, err := t.layout()
+	return err
+}
+
+// layout validates the template and resolves it to points
+// テンプレートを検証してポイント単位に変換
+func (t *Template) layout() (*layout, error) {
+	if t.Symbology != SymbologyCode128 && t.Symbology != SymbologyQR {
+		return nil, ErrInvalidSymbology
+	}
+	if t.LabelWidth <= 0 || t.LabelHeight <= 0 || t.LabelWidth > maxLabelSize || t.LabelHeight > maxLabelSize ||
+		t.PageWidth < 0 || t.PageHeight < 0 || t.PageWidth > maxLabelSize || t.PageHeight > maxLabelSize {
+		return nil, ErrInvalidSize
+	}
+	columns, rows := t.Columns, t.Rows
+	if columns == 0 {
+		columns = 1
+	}
+	if rows == 0 {
+		rows = 1
+	}
+	if columns < 0 || rows < 0 || columns > maxGridSize || rows > maxGridSize ||
+		t.MarginLeft < 0 || t.MarginTop < 0 || t.GapX < 0 || t.GapY < 0 || t.Padding < 0 ||
+		2*t.Padding >= t.LabelWidth || 2*t.Padding >= t.LabelHeight {
+		return nil, ErrInvalidSpacing
+	}
+
+	// 用紙を省略した場合は左右・上下の余白を同じ大きさとして計算
+	usedWidth := t.MarginLeft + float64(columns)*t.LabelWidth + float64(columns-1)*t.GapX
+	usedHeight := t.MarginTop + float64(rows)*t.LabelHeight + float64(rows-1)*t.GapY
+	pageWidth, pageHeight := t.PageWidth, t.PageHeight
+	if pageWidth == 0 {
+		pageWidth = usedWidth + t.MarginLeft
+	}
+	if pageHeight == 0 {
+		pageHeight = usedHeight + t.MarginTop
+	}
+	const tolerance = 0.001
+	if usedWidth > pageWidth+tolerance || usedHeight > pageHeight+tolerance || pageWidth > maxLabelSize || pageHeight > maxLabelSize {
+		return nil, ErrLabelsOverflow
+	}
+
+	if t.FontSize < 0 || t.FontSize > maxFontSize {
+		return nil, ErrInvalidFontSize
+	}
+	fontSize := t.FontSize
+	if fontSize == 0 {
+		fontSize = DefaultFontSize
+	}
+	if len(t.Lines) > maxLines {
+		return nil, ErrTooManyLines
+	}
+	lines := make([]*template.Template, len(t.Lines))
+	for i, line := range t.Lines {
+		parsed, err := template.New("line").Option("missingkey=zero").Parse(line)
+		if err != nil {
+			return nil, ErrInvalidLine
+		}
+		lines[i] = parsed
+	}
+
+	l := &layout{
+		symbology:   t.Symbology,
+		pageWidth:   pageWidth * pointsPerMM,
+		pageHeight:  pageHeight * pointsPerMM,
+		labelWidth:  t.LabelWidth * pointsPerMM,
+		labelHeight: t.LabelHeight * pointsPerMM,
+		columns:     columns,
+		rows:        rows,
+		marginLeft:  t.MarginLeft * pointsPerMM,
+		marginTop:   t.MarginTop * pointsPerMM,
+		gapX:        t.GapX * pointsPerMM,
+		gapY:        t.GapY * pointsPerMM,
+		padding:     t.Padding * pointsPerMM,
+		fontSize:    fontSize,
+		lines:       lines,
+	}
+
+	// Code128 は文字列の下に最低でも内側の高さの3割のバーが必要
+	textHeight := float64(len(lines)) * fontSize * lineSpacing
+	innerHeight := l.labelHeight - 2*l.padding
+	if (t.Symbology == SymbologyCode128 && textHeight > innerHeight*0.7) || textHeight > innerHeight {
+		return nil, ErrTextOverflow
+	}
+	return l, nil
+}
+
+// Render writes labels as a PDF document laid out by a template
+// テンプレートの配置でラベルをPDF文書として書き出す
+//
+// 全てのラベルを符号化してから書き出すため、値をバーコードにできない場合などは
+// 何も書き出さずにエラーを返します。
+func Render(w io.Writer, t *Template, labels []Label) error {
+	l, err := t.layout()
+	if err != nil {
+		return err
+	}
+	if len(labels) == 0 {
+		return ErrNoLabels
+	}
+	perPage := l.columns * l.rows
+	if (len(labels)+perPage-1)/perPage > maxPages {
+		return ErrTooManyPages
+	}
+
+	var pages []*canvas
+	for i, lbl := range labels {
+		slot := i % perPage
+		if slot == 0 {
+			pages = append(pages, &canvas{})
+		}
+		column, row := slot%l.columns, slot/l.columns
+		x := l.marginLeft + float64(column)*(l.labelWidth+l.gapX)
+		top := l.pageHeight - l.marginTop - float64(row)*(l.labelHeight+l.gapY)
+		if err := l.draw(pages[len(pages)-1], x, top-l.labelHeight, lbl); err != nil {
+			return err
+		}
+	}
+	return writePDF(w, l.pageWidth, l.pageHeight, pages)
+}
+
+// draw draws a label with its bottom-left corner at x, y
+// 左下の角を x, y としてラベルを描画
+func (l *layout) draw(c *canvas, x, y float64, lbl Label) error {
+	if lbl.Code == "" {
+		return ErrEmptyCode
+	}
+	texts := make([]string, len(l.lines))
+	for i, line := range l.lines {
+		var buf strings.Builder
+		if err := line.Execute(&buf, lbl); err != nil {
+			return ErrInvalidLine
+		}
+		texts[i] = strings.Join(strings.Fields(buf.String()), " ")
+	}
+
+	innerX, innerY := x+l.padding, y+l.padding
+	innerWidth, innerHeight := l.labelWidth-2*l.padding, l.labelHeight-2*l.padding
+	lineHeight := l.fontSize * lineSpacing
+
+	switch l.symbology {
+	case SymbologyQR:
+		qr, err := EncodeQR(lbl.Code)
+		if err != nil {
+			return err
+		}
+		// 文字列がある場合は左側の正方形（幅の半分まで）にQRコード、右側に文字列を配置
+		side := innerHeight
+		if len(texts) > 0 && side > innerWidth/2 {
+			side = innerWidth / 2
+		} else if side > innerWidth {
+			side = innerWidth
+		}
+		const quietZone = 4
+		module := side / float64(len(qr)+2*quietZone)
+		if module < minModuleWidth*pointsPerMM {
+			return ErrBarcodeTooDense
+		}
+		top := innerY + innerHeight
+		for row, modules := range qr {
+			for column := 0; column < len(modules); {
+				if !modules[column] {
+					column++
+					continue
+				}
+				start := column
+				for column < len(modules) && modules[column] {
+					column++
+				}
+				c.rect(innerX+float64(quietZone+start)*module, top-float64(quietZone+row+1)*module,
+					float64(column-start)*module, module)
+			}
+		}
+		textX, textWidthLimit := innerX+side, innerWidth-side
+		for i, text := range texts {
+			baseline := top - float64(i)*lineHeight - l.fontSize
+			c.text(textX, baseline, l.fontSize, fitText(text, l.fontSize, textWidthLimit))
+		}
+
+	case SymbologyCode128:
+		barcode, err := EncodeCode128(lbl.Code)
+		if err != nil {
+			return err
+		}
+		// 上部にバーコード（左右に10モジュールのクワイエットゾーン）、下部に文字列を配置
+		const quietZone = 10
+		module := innerWidth / float64(barcode.Modules()+2*quietZone)
+		if module < minModuleWidth*pointsPerMM {
+			return ErrBarcodeTooDense
+		}
+		textHeight := float64(len(texts)) * lineHeight
+		barX, barY, barHeight := innerX+quietZone*module, innerY+textHeight, innerHeight-textHeight
+		for i, width := range barcode {
+			if i%2 == 0 {
+				c.rect(barX, barY, float64(width)*module, barHeight)
+			}
+			barX += float64(width) * module
+		}
+		for i, text := range texts {
+			baseline := innerY + textHeight - float64(i)*lineHeight - l.fontSize
+			c.text(innerX, baseline, l.fontSize, fitText(text, l.fontSize, innerWidth))
+		}
+	}
+	return nil
+}
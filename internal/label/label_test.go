@@ -0,0 +1,218 @@
+package label
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRSRemainder はリード・ソロモン符号の誤り訂正コード語のテスト（型番1-Mの「HELLO WORLD」）
+func TestRSRemainder(t *testing.T) {
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	assert.Equal(t, []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}, rsRemainder(data, rsGenerator(10)))
+}
+
+// TestEncodeQR はQRコードの符号化のテスト
+func TestEncodeQR(t *testing.T) {
+	for _, block := range qrVersions {
+		assert.Greater(t, block.dataCodewords(), 0)
+	}
+
+	// 型番ごとのコード語数はデータ領域のモジュール数と一致する
+	for version := 1; version <= len(qrVersions); version++ {
+		m := newQRMatrix(version)
+		free := 0
+		for y, row := range m.function {
+			for x := range row {
+				if !m.function[y][x] {
+					free++
+				}
+			}
+		}
+		blocks := qrVersions[version-1]
+		total := blocks.dataCodewords() + (blocks.group1+blocks.group2)*blocks.ecPerBlock
+		assert.Equal(t, free/8, total, "version %d", version)
+	}
+
+	qr, err := EncodeQR("LOC-A-01-02")
+	require.NoError(t, err)
+	assert.Len(t, qr, 21)
+
+	// 形式情報（誤り訂正レベルM）の2つの写しは一致する
+	size := len(qr)
+	var first, second []bool
+	for i := 0; i <= 5; i++ {
+		first = append(first, qr[i][8])
+	}
+	first = append(first, qr[7][8], qr[8][8], qr[8][7])
+	for i := 9; i < 15; i++ {
+		first = append(first, qr[8][14-i])
+	}
+	for i := 0; i < 8; i++ {
+		second = append(second, qr[8][size-1-i])
+	}
+	for i := 8; i < 15; i++ {
+		second = append(second, qr[size-15+i][8])
+	}
+	assert.Equal(t, first, second)
+	assert.True(t, qr[size-8][8])
+
+	// マスク0の形式情報は 101010000010010
+	m := newQRMatrix(1)
+	m.drawFormat(0)
+	bits := ""
+	for i := 14; i >= 9; i-- {
+		bits += map[bool]string{true: "1", false: "0"}[m.modules[8][14-i]]
+	}
+	assert.Equal(t, "101010", bits)
+
+	// 255バイトのIDは型番13までに収まる
+	qr, err = EncodeQR(string(bytes.Repeat([]byte("A"), 255)))
+	require.NoError(t, err)
+	assert.Len(t, qr, 17+4*12)
+	_, err = EncodeQR(string(bytes.Repeat([]byte("A"), 400)))
+	assert.ErrorIs(t, err, ErrQRTooLong)
+}
+
+// TestEncodeCode128 はCode128の符号化のテスト
+func TestEncodeCode128(t *testing.T) {
+	require.Len(t, code128Patterns, 107)
+	for i, pattern := range code128Patterns {
+		sum := 0
+		for _, width := range pattern {
+			sum += int(width - '0')
+		}
+		if i == code128Stop {
+			assert.Equal(t, 13, sum)
+		} else {
+			assert.Equal(t, 11, sum, "symbol %d", i)
+		}
+	}
+
+	// スタートB・文字・チェックキャラクタ・ストップ
+	barcode, err := EncodeCode128("PJJ123C")
+	require.NoError(t, err)
+	assert.Equal(t, 11*(1+7+1)+13, barcode.Modules())
+	// チェックキャラクタ = (104 + 48×1 + 42×2 + 42×3 + 17×4 + 18×5 + 19×6 + 35×7) mod 103 = 55
+	assert.Equal(t, code128Patterns[55], widthsString(barcode[len(barcode)-13:len(barcode)-7]))
+
+	// 数字の連続はコードセットCで2桁ずつ符号化する
+	barcode, err = EncodeCode128("12345678")
+	require.NoError(t, err)
+	assert.Equal(t, 11*(1+4+1)+13, barcode.Modules())
+	assert.Equal(t, code128Patterns[code128StartC], widthsString(barcode[:6]))
+
+	_, err = EncodeCode128("棚A")
+	assert.ErrorIs(t, err, ErrCode128Character)
+	_, err = EncodeCode128("")
+	assert.ErrorIs(t, err, ErrEmptyCode)
+}
+
+func widthsString(widths []int) string {
+	s := ""
+	for _, width := range widths {
+		s += strconv.Itoa(width)
+	}
+	return s
+}
+
+// TestRender はテンプレートによるPDFの出力のテスト
+func TestRender(t *testing.T) {
+	labels := []Label{
+		{Kind: KindLocation, Code: "LOC-A-01", Name: "第1倉庫 A-01", Detail: "warehouse"},
+		{Kind: KindItem, Code: "ITEM-1", Name: "ボルト", Detail: "SKU-1", Fields: map[string]string{"sku": "SKU-1"}},
+	}
+	for _, tmpl := range Templates() {
+		require.NoError(t, tmpl.Validate(), tmpl.Name)
+		var buf bytes.Buffer
+		require.NoError(t, Render(&buf, &tmpl, labels), tmpl.Name)
+
+		pdf := buf.String()
+		assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-1.4")))
+		assert.Contains(t, pdf, "/HeiseiKakuGo-W5")
+		assert.Contains(t, pdf, "%%EOF")
+
+		// 相互参照表の位置とオブジェクトの位置が一致する
+		startxref := regexp.MustCompile(`startxref\n(\d+)`).FindStringSubmatch(pdf)
+		require.Len(t, startxref, 2)
+		offset, err := strconv.Atoi(startxref[1])
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(buf.Bytes()[offset:], []byte("xref\n")))
+		for _, entry := range regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(pdf, -1) {
+			offset, err := strconv.Atoi(entry[1])
+			require.NoError(t, err)
+			assert.Regexp(t, `^\d+ 0 obj\n`, pdf[offset:offset+12])
+		}
+	}
+
+	// 1ラベル1ページのテンプレートはラベルごとにページを作成し、文字列は日本語を含めて出力する
+	tmpl, ok := LookupTemplate("bin-qr")
+	require.True(t, ok)
+	var buf bytes.Buffer
+	require.NoError(t, Render(&buf, &tmpl, labels))
+	assert.Contains(t, buf.String(), "/Count 2")
+	assert.Contains(t, buf.String(), "/MediaBox [0 0 283.46 141.73]")
+	content := firstStream(t, buf.Bytes())
+	assert.Contains(t, content, pdfString("第1倉庫 A-01"))
+
+	// カスタムのテンプレートは text/template で項目を参照できる
+	custom := Template{Symbology: SymbologyCode128, LabelWidth: 80, LabelHeight: 30, Padding: 2, Lines: []string{`SKU: {{index .Fields "sku"}}`}}
+	buf.Reset()
+	require.NoError(t, Render(&buf, &custom, labels[1:]))
+	assert.Contains(t, firstStream(t, buf.Bytes()), pdfString("SKU: SKU-1"))
+}
+
+// TestTemplateValidate はテンプレートの検証のテスト
+func TestTemplateValidate(t *testing.T) {
+	base := Template{Symbology: SymbologyQR, PageWidth: 210, PageHeight: 297, LabelWidth: 70, LabelHeight: 37, Columns: 3, Rows: 8}
+	require.NoError(t, base.Validate())
+
+	cases := []struct {
+		name   string
+		modify func(*Template)
+		err    error
+	}{
+		{"symbology", func(t *Template) { t.Symbology = "ean13" }, ErrInvalidSymbology},
+		{"size", func(t *Template) { t.LabelWidth = 0 }, ErrInvalidSize},
+		{"columns", func(t *Template) { t.Columns = -1 }, ErrInvalidSpacing},
+		{"padding", func(t *Template) { t.Padding = 20 }, ErrInvalidSpacing},
+		{"overflow", func(t *Template) { t.Columns = 4 }, ErrLabelsOverflow},
+		{"font", func(t *Template) { t.FontSize = 100 }, ErrInvalidFontSize},
+		{"lines", func(t *Template) { t.Lines = make([]string, 11) }, ErrTooManyLines},
+		{"template", func(t *Template) { t.Lines = []string{"{{.Code"} }, ErrInvalidLine},
+		{"text", func(t *Template) { t.FontSize = 30; t.Lines = []string{"a", "b", "c"} }, ErrTextOverflow},
+	}
+	for _, tc := range cases {
+		tmpl := base
+		tc.modify(&tmpl)
+		assert.ErrorIs(t, tmpl.Validate(), tc.err, tc.name)
+	}
+
+	var buf bytes.Buffer
+	assert.ErrorIs(t, Render(&buf, &base, nil), ErrNoLabels)
+	assert.ErrorIs(t, Render(&buf, &base, []Label{{Code: ""}}), ErrEmptyCode)
+	code128 := base
+	code128.Symbology = SymbologyCode128
+	assert.ErrorIs(t, Render(&buf, &code128, []Label{{Code: string(bytes.Repeat([]byte("A"), 100))}}), ErrBarcodeTooDense)
+	assert.Zero(t, buf.Len())
+}
+
+// firstStream returns the decompressed content stream of the first page
+// 最初のページの内容ストリームを展開して返す
+func firstStream(t *testing.T, pdf []byte) string {
+	t.Helper()
+	start := bytes.Index(pdf, []byte("stream\n"))
+	end := bytes.Index(pdf, []byte("\nendstream"))
+	require.True(t, start >= 0 && end > start)
+	reader, err := zlib.NewReader(bytes.NewReader(pdf[start+len("stream\n") : end]))
+	require.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return string(content)
+}
@@ -0,0 +1,198 @@
+package label
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strconv"
+	"unicode/utf8"
+)
+
+// PDF objects written before the pages: the catalog, the page tree and the font
+// ページより前に書き出すPDFオブジェクト（カタログ・ページツリー・フォント）
+//
+// 文字は日本語を含めて表示できるよう、埋め込み不要の定義済みの日本語フォント（平成角ゴシック）と
+// 定義済みのCMap UniJIS-UCS2-HW-H で表示します。ASCII文字は半角（幅500）のグリフで表示されます。
+const (
+	pdfCatalogObject    = 1
+	pdfPagesObject      = 2
+	pdfFontObject       = 3
+	pdfCIDFontObject    = 4
+	pdfDescriptorObject = 5
+	pdfFirstPageObject  = 6 // ページごとにページオブジェクトと内容ストリームの2つを使用
+
+	pdfFontName = "HeiseiKakuGo-W5"
+)
+
+// canvas accumulates the drawing operators of a page in points from the bottom-left corner
+// ページの描画命令を蓄積する（座標は左下を原点とするポイント）
+type canvas struct {
+	buf bytes.Buffer
+}
+
+// rect fills a rectangle
+// 矩形を塗りつぶす
+func (c *canvas) rect(x, y, width, height float64) {
+	fmt.Fprintf(&c.buf, "%s %s %s %s re f\n", pdfNumber(x), pdfNumber(y), pdfNumber(width), pdfNumber(height))
+}
+
+// text draws a line of text with its baseline at y
+// ベースラインをyとして1行の文字列を描画
+func (c *canvas) text(x, y, size float64, s string) {
+	if s == "" {
+		return
+	}
+	fmt.Fprintf(&c.buf, "BT /F1 %s Tf %s %s Td %s Tj ET\n", pdfNumber(size), pdfNumber(x), pdfNumber(y), pdfString(s))
+}
+
+// pdfNumber formats a number with at most two decimal places
+// 数値を小数点以下2桁までの文字列に変換
+func pdfNumber(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 2, 64)
+	for s[len(s)-1] == '0' {
+		s = s[:len(s)-1]
+	}
+	if s[len(s)-1] == '.' {
+		s = s[:len(s)-1]
+	}
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+// pdfString encodes text as a UTF-16BE hex string for the UniJIS-UCS2-HW-H CMap
+// 文字列を UniJIS-UCS2-HW-H 用のUTF-16BEの16進文字列に変換
+//
+// 基本多言語面以外の文字は「?」に置き換え、制御文字は除きます。
+func pdfString(s string) string {
+	var buf bytes.Buffer
+	buf.WriteByte('<')
+	for _, r := range s {
+		switch {
+		case r < 0x20 || r == 0x7F:
+			continue
+		case r > 0xFFFF || r == utf8.RuneError:
+			r = '?'
+		}
+		fmt.Fprintf(&buf, "%04X", r)
+	}
+	buf.WriteByte('>')
+	return buf.String()
+}
+
+// textWidth returns the approximate width of text in points
+// 文字列のおおよその幅（ポイント）を返す
+//
+// ASCII文字と半角カナは文字サイズの半分、それ以外は文字サイズと同じ幅として計算します。
+func textWidth(s string, size float64) float64 {
+	width := 0.0
+	for _, r := range s {
+		width += runeWidth(r) * size
+	}
+	return width
+}
+
+func runeWidth(r rune) float64 {
+	if r < 0x80 || (r >= 0xFF61 && r <= 0xFF9F) {
+		return 0.5
+	}
+	return 1
+}
+
+// fitText cuts text to the longest prefix that fits a width
+// 幅に収まる最長の先頭部分に文字列を切り詰める
+func fitText(s string, size, width float64) string {
+	used := 0.0
+	for i, r := range s {
+		used += runeWidth(r) * size
+		if used > width {
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// writePDF writes a document of pages of the same size
+// 同じ大きさのページからなる文書を書き出す
+func writePDF(w io.Writer, width, height float64, pages []*canvas) error {
+	out := &offsetWriter{w: bufio.NewWriter(w)}
+	objects := pdfFirstPageObject + 2*len(pages) - 1
+	offsets := make([]int64, objects+1)
+	object := func(number int, body string) {
+		offsets[number] = out.n
+		fmt.Fprintf(out, "%d 0 obj\n%s\nendobj\n", number, body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+	object(pdfCatalogObject, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pdfPagesObject))
+
+	var kids bytes.Buffer
+	for i := range pages {
+		fmt.Fprintf(&kids, "%d 0 R ", pdfFirstPageObject+2*i)
+	}
+	object(pdfPagesObject, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", bytes.TrimSpace(kids.Bytes()), len(pages)))
+
+	object(pdfFontObject, fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /UniJIS-UCS2-HW-H /DescendantFonts [%d 0 R] >>",
+		pdfFontName, pdfCIDFontObject))
+	object(pdfCIDFontObject, fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType0 /BaseFont /%s "+
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (Japan1) /Supplement 2 >> /FontDescriptor %d 0 R /DW 1000 /W [231 389 500] >>",
+		pdfFontName, pdfDescriptorObject))
+	object(pdfDescriptorObject, fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 4 /FontBBox [-92 -250 1010 922] "+
+		"/ItalicAngle 0 /Ascent 752 /Descent -221 /CapHeight 737 /StemV 114 >>", pdfFontName))
+
+	for i, page := range pages {
+		pageObject := pdfFirstPageObject + 2*i
+		object(pageObject, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 %d 0 R >> >> /Contents %d 0 R >>",
+			pdfPagesObject, pdfNumber(width), pdfNumber(height), pdfFontObject, pageObject+1))
+
+		var content bytes.Buffer
+		zw := zlib.NewWriter(&content)
+		if _, err := zw.Write(page.buf.Bytes()); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		offsets[pageObject+1] = out.n
+		fmt.Fprintf(out, "%d 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", pageObject+1, content.Len())
+		out.Write(content.Bytes())
+		out.WriteString("\nendstream\nendobj\n")
+	}
+
+	xref := out.n
+	fmt.Fprintf(out, "xref\n0 %d\n0000000000 65535 f \n", objects+1)
+	for _, offset := range offsets[1:] {
+		fmt.Fprintf(out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(out, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", objects+1, pdfCatalogObject, xref)
+
+	if out.err != nil {
+		return out.err
+	}
+	return out.w.Flush()
+}
+
+// offsetWriter counts the bytes written for the cross-reference table and keeps the first error
+// 相互参照表のために書き出したバイト数を数え、最初のエラーを保持する
+type offsetWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (o *offsetWriter) Write(p []byte) (int, error) {
+	if o.err != nil {
+		return 0, o.err
+	}
+	n, err := o.w.Write(p)
+	o.n += int64(n)
+	o.err = err
+	return n, err
+}
+
+func (o *offsetWriter) WriteString(s string) {
+	o.Write([]byte(s))
+}
@@ -0,0 +1,511 @@
+package label
+
+import (
+	"errors"
+)
+
+// ErrQRTooLong is returned when a value does not fit the largest supported QR code
+// 値がサポートする最大のQRコードに収まらない場合のエラー
+var ErrQRTooLong = errors.New("QRコードに収まらない長さの値です")
+
+// qrBlocks is the error correction block structure of a QR code version at level M
+// QRコードの型番の誤り訂正レベルMのブロック構成
+type qrBlocks struct {
+	ecPerBlock int // ブロックごとの誤り訂正コード語数
+	group1     int // 1つ目のグループのブロック数
+	data1      int // 1つ目のグループのブロックごとのデータコード語数
+	group2     int // 2つ目のグループのブロック数（データコード語数は data1+1）
+}
+
+// dataCodewords returns the number of data codewords of the version
+// 型番のデータコード語数を返す
+func (b qrBlocks) dataCodewords() int {
+	return b.group1*b.data1 + b.group2*(b.data1+1)
+}
+
+// qrVersions are the block structures of versions 1 to 13 at level M (index 0 is version 1)
+// 型番1〜13の誤り訂正レベルMのブロック構成（添字0が型番1）
+//
+// 商品・ロット・ロケーションのID（最大255バイト）は型番13までに収まります。
+var qrVersions = []qrBlocks{
+	{10, 1, 16, 0},
+	{16, 1, 28, 0},
+	{26, 1, 44, 0},
+	{18, 2, 32, 0},
+	{24, 2, 43, 0},
+	{16, 4, 27, 0},
+	{18, 4, 31, 0},
+	{22, 2, 38, 2},
+	{22, 3, 36, 2},
+	{26, 4, 43, 1},
+	{30, 1, 50, 4},
+	{22, 6, 36, 2},
+	{22, 8, 37, 1},
+}
+
+// qrAlignments are the alignment pattern centers of versions 1 to 13 (index 0 is version 1)
+// 型番1〜13の位置合わせパターンの中心座標（添字0が型番1）
+var qrAlignments = [][]int{
+	{},
+	{6, 18},
+	{6, 22},
+	{6, 26},
+	{6, 30},
+	{6, 34},
+	{6, 22, 38},
+	{6, 24, 42},
+	{6, 26, 46},
+	{6, 28, 50},
+	{6, 30, 54},
+	{6, 32, 58},
+	{6, 34, 62},
+}
+
+// QR is the module matrix of a QR code, indexed by row then column (true is dark)
+// QRコードのモジュールの行列（行・列の順、true が暗モジュール）
+type QR [][]bool
+
+// EncodeQR encodes a value in byte mode at error correction level M
+// 値をバイトモード・誤り訂正レベルMのQRコードに符号化
+//
+// 値が収まる最小の型番を選択し、8種類のマスクのうち失点の最も小さいマスクを適用します。
+func EncodeQR(value string) (QR, error) {
+	data := []byte(value)
+	version := 0
+	for v := 1; v <= len(qrVersions); v++ {
+		if 4+qrCountBits(v)+8*len(data) <= 8*qrVersions[v-1].dataCodewords() {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrQRTooLong
+	}
+
+	codewords := qrCodewords(version, data)
+	base := newQRMatrix(version)
+	base.placeData(codewords)
+
+	var best QR
+	bestPenalty := -1
+	for mask := 0; mask < 8; mask++ {
+		m := base.clone()
+		m.applyMask(mask)
+		m.drawFormat(mask)
+		if penalty := m.modules.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			best, bestPenalty = m.modules, penalty
+		}
+	}
+	return best, nil
+}
+
+// qrCountBits returns the length of the character count indicator of byte mode
+// バイトモードの文字数指示子のビット数を返す
+func qrCountBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+// qrCodewords builds the interleaved data and error correction codewords
+// データと誤り訂正のコード語を生成しインターリーブする
+func qrCodewords(version int, data []byte) []byte {
+	blocks := qrVersions[version-1]
+	capacity := blocks.dataCodewords()
+
+	// モード指示子（0100）・文字数指示子・データ・終端パターン・埋め草
+	var bits bitBuffer
+	bits.append(0x4, 4)
+	bits.append(len(data), qrCountBits(version))
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+	if terminator := 8*capacity - bits.len(); terminator > 0 {
+		if terminator > 4 {
+			terminator = 4
+		}
+		bits.append(0, terminator)
+	}
+	if rem := bits.len() % 8; rem != 0 {
+		bits.append(0, 8-rem)
+	}
+	payload := bits.bytes()
+	for pad := byte(0xEC); len(payload) < capacity; pad ^= 0xEC ^ 0x11 {
+		payload = append(payload, pad)
+	}
+
+	// ブロックに分割して誤り訂正コード語を計算
+	generator := rsGenerator(blocks.ecPerBlock)
+	var dataBlocks, ecBlocks [][]byte
+	offset := 0
+	for i := 0; i < blocks.group1+blocks.group2; i++ {
+		size := blocks.data1
+		if i >= blocks.group1 {
+			size++
+		}
+		block := payload[offset : offset+size]
+		offset += size
+		dataBlocks = append(dataBlocks, block)
+		ecBlocks = append(ecBlocks, rsRemainder(block, generator))
+	}
+
+	result := make([]byte, 0, capacity+len(ecBlocks)*blocks.ecPerBlock)
+	for i := 0; i <= blocks.data1; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := 0; i < blocks.ecPerBlock; i++ {
+		for _, block := range ecBlocks {
+			result = append(result, block[i])
+		}
+	}
+	return result
+}
+
+// bitBuffer accumulates bits most significant bit first
+// 上位ビットから順にビットを蓄積する
+type bitBuffer []bool
+
+func (b *bitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		*b = append(*b, (value>>uint(i))&1 == 1)
+	}
+}
+
+func (b bitBuffer) len() int {
+	return len(b)
+}
+
+func (b bitBuffer) bytes() []byte {
+	result := make([]byte, (len(b)+7)/8)
+	for i, bit := range b {
+		if bit {
+			result[i/8] |= 0x80 >> uint(i%8)
+		}
+	}
+	return result
+}
+
+// gfExp and gfLog are the exponent and logarithm tables of GF(256) with the polynomial 0x11D
+// 原始多項式 0x11D のGF(256)の指数表と対数表
+var gfExp, gfLog = func() ([512]byte, [256]byte) {
+	var exp [512]byte
+	var log [256]byte
+	x := 1
+	for i := 0; i < 255; i++ {
+		exp[i] = byte(x)
+		log[x] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11D
+		}
+	}
+	for i := 255; i < 512; i++ {
+		exp[i] = exp[i-255]
+	}
+	return exp, log
+}()
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+int(gfLog[b])]
+}
+
+// rsGenerator returns the Reed-Solomon generator polynomial of degree (highest coefficient first, without the leading 1)
+// 次数 degree のリード・ソロモン符号の生成多項式を返す（最高次の係数1を除き高次から）
+func rsGenerator(degree int) []byte {
+	generator := make([]byte, degree)
+	generator[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := 0; j < degree; j++ {
+			generator[j] = gfMul(generator[j], root)
+			if j+1 < degree {
+				generator[j] ^= generator[j+1]
+			}
+		}
+		root = gfMul(root, 0x02)
+	}
+	return generator
+}
+
+// rsRemainder returns the error correction codewords of data
+// データの誤り訂正コード語を返す
+func rsRemainder(data, generator []byte) []byte {
+	remainder := make([]byte, len(generator))
+	for _, b := range data {
+		factor := b ^ remainder[0]
+		copy(remainder, remainder[1:])
+		remainder[len(remainder)-1] = 0
+		for i, coefficient := range generator {
+			remainder[i] ^= gfMul(coefficient, factor)
+		}
+	}
+	return remainder
+}
+
+// qrMatrix is a QR code being built with its function pattern modules
+// 機能パターンの位置とともに作成中のQRコード
+type qrMatrix struct {
+	version  int
+	modules  QR
+	function [][]bool
+}
+
+// newQRMatrix draws the function patterns of a version
+// 型番の機能パターンを描画
+func newQRMatrix(version int) *qrMatrix {
+	size := 17 + 4*version
+	m := &qrMatrix{version: version, modules: make(QR, size), function: make([][]bool, size)}
+	for i := range m.modules {
+		m.modules[i] = make([]bool, size)
+		m.function[i] = make([]bool, size)
+	}
+
+	// タイミングパターン
+	for i := 0; i < size; i++ {
+		m.set(6, i, i%2 == 0)
+		m.set(i, 6, i%2 == 0)
+	}
+
+	// 位置検出パターン（分離パターンを含む）
+	for _, corner := range [][2]int{{3, 3}, {size - 4, 3}, {3, size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := corner[0]+dx, corner[1]+dy
+				if x < 0 || x >= size || y < 0 || y >= size {
+					continue
+				}
+				distance := maxInt(absInt(dx), absInt(dy))
+				m.set(x, y, distance != 2 && distance != 4)
+			}
+		}
+	}
+
+	// 位置合わせパターン（位置検出パターンと重なる3か所を除く）
+	positions := qrAlignments[version-1]
+	last := len(positions) - 1
+	for i, cy := range positions {
+		for j, cx := range positions {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					m.set(cx+dx, cy+dy, maxInt(absInt(dx), absInt(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	// 形式情報の領域を予約し、型番7以上は型番情報を描画
+	m.drawFormat(0)
+	if version >= 7 {
+		remainder := version
+		for i := 0; i < 12; i++ {
+			remainder = (remainder << 1) ^ ((remainder >> 11) * 0x1F25)
+		}
+		bits := version<<12 | remainder
+		for i := 0; i < 18; i++ {
+			dark := (bits>>uint(i))&1 == 1
+			a, b := size-11+i%3, i/3
+			m.set(a, b, dark)
+			m.set(b, a, dark)
+		}
+	}
+	return m
+}
+
+// set sets a function pattern module at column x and row y
+// 列x・行yの機能パターンのモジュールを設定
+func (m *qrMatrix) set(x, y int, dark bool) {
+	m.modules[y][x] = dark
+	m.function[y][x] = true
+}
+
+// drawFormat draws both copies of the format information of level M and a mask
+// 誤り訂正レベルMとマスクの形式情報を2か所に描画
+func (m *qrMatrix) drawFormat(mask int) {
+	data := mask // 誤り訂正レベルMの指示子は00
+	remainder := data
+	for i := 0; i < 10; i++ {
+		remainder = (remainder << 1) ^ ((remainder >> 9) * 0x537)
+	}
+	bits := (data<<10 | remainder) ^ 0x5412
+	bit := func(i int) bool { return (bits>>uint(i))&1 == 1 }
+
+	size := len(m.modules)
+	for i := 0; i <= 5; i++ {
+		m.set(8, i, bit(i))
+	}
+	m.set(8, 7, bit(6))
+	m.set(8, 8, bit(7))
+	m.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		m.set(14-i, 8, bit(i))
+	}
+	for i := 0; i < 8; i++ {
+		m.set(size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		m.set(8, size-15+i, bit(i))
+	}
+	m.set(8, size-8, true) // 常に暗モジュール
+}
+
+// placeData places codewords in the zigzag order, leaving remainder bits light
+// コード語をジグザグの順に配置（残余ビットは明モジュール）
+func (m *qrMatrix) placeData(codewords []byte) {
+	size := len(m.modules)
+	i := 0
+	for right := size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // 縦のタイミングパターンを飛ばす
+		}
+		upward := (right+1)&2 == 0
+		for vertical := 0; vertical < size; vertical++ {
+			y := vertical
+			if upward {
+				y = size - 1 - vertical
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if m.function[y][x] || i >= len(codewords)*8 {
+					continue
+				}
+				m.modules[y][x] = (codewords[i/8]>>uint(7-i%8))&1 == 1
+				i++
+			}
+		}
+	}
+}
+
+// applyMask inverts the data modules selected by a mask pattern
+// マスクパターンが選択するデータのモジュールを反転
+func (m *qrMatrix) applyMask(mask int) {
+	for y, row := range m.modules {
+		for x := range row {
+			if m.function[y][x] {
+				continue
+			}
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert {
+				row[x] = !row[x]
+			}
+		}
+	}
+}
+
+// clone returns a copy of the matrix
+// 行列の複製を返す
+func (m *qrMatrix) clone() *qrMatrix {
+	c := &qrMatrix{version: m.version, modules: make(QR, len(m.modules)), function: m.function}
+	for i, row := range m.modules {
+		c.modules[i] = append([]bool(nil), row...)
+	}
+	return c
+}
+
+// penalty scores a masked symbol by the four penalty rules of the specification
+// マスク後のシンボルを規格の4つの失点規則で評価
+func (q QR) penalty() int {
+	size := len(q)
+	penalty := 0
+	finderLike := [][]bool{
+		{true, false, true, true, true, false, true, false, false, false, false},
+		{false, false, false, false, true, false, true, true, true, false, true},
+	}
+	at := func(horizontal bool, line, i int) bool {
+		if horizontal {
+			return q[line][i]
+		}
+		return q[i][line]
+	}
+	for _, horizontal := range []bool{true, false} {
+		for line := 0; line < size; line++ {
+			// 規則1: 同色の5モジュール以上の連続
+			run := 1
+			for i := 1; i <= size; i++ {
+				if i < size && at(horizontal, line, i) == at(horizontal, line, i-1) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					penalty += 3 + run - 5
+				}
+				run = 1
+			}
+			// 規則3: 位置検出パターンに似た 1:1:3:1:1 の並び
+			for i := 0; i+11 <= size; i++ {
+				for _, pattern := range finderLike {
+					match := true
+					for k, dark := range pattern {
+						if at(horizontal, line, i+k) != dark {
+							match = false
+							break
+						}
+					}
+					if match {
+						penalty += 40
+					}
+				}
+			}
+		}
+	}
+
+	// 規則2: 同色の2×2のブロック
+	dark := 0
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if q[y][x] {
+				dark++
+			}
+			if x+1 < size && y+1 < size && q[y][x] == q[y][x+1] && q[y][x] == q[y+1][x] && q[y][x] == q[y+1][x+1] {
+				penalty += 3
+			}
+		}
+	}
+
+	// 規則4: 暗モジュールの割合の50%からの偏り
+	percent := dark * 100 / (size * size)
+	penalty += absInt(percent-50) / 5 * 10
+	return penalty
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,70 @@
+// Package mergepatch applies JSON merge patches (RFC 7396)
+// JSONマージパッチ（RFC 7396）を適用するパッケージ
+//
+// パッチに含まれるメンバーのみを変更し、null のメンバーは削除します。
+// オブジェクトは再帰的にマージし、配列やその他の値は置き換えます。
+package mergepatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// ContentType is the media type of a JSON merge patch
+// JSONマージパッチのメディアタイプ
+const ContentType = "application/merge-patch+json"
+
+// ErrNotObject is returned when a patch is not a JSON object
+// パッチがJSONオブジェクトでない場合のエラー
+var ErrNotObject = errors.New("マージパッチはJSONオブジェクトで指定してください")
+
+// Apply applies a patch to a JSON document and returns the patched document
+// JSONドキュメントにパッチを適用し、適用後のドキュメントを返す
+//
+// RFC 7396 ではオブジェクト以外のパッチはドキュメント全体の置き換えになりますが、
+// リソースの部分更新に使用するためオブジェクト以外のパッチは ErrNotObject を返します。
+func Apply(document, patch []byte) ([]byte, error) {
+	var p interface{}
+	if err := decode(patch, &p); err != nil {
+		return nil, err
+	}
+	patchObject, ok := p.(map[string]interface{})
+	if !ok {
+		return nil, ErrNotObject
+	}
+
+	var target interface{}
+	if err := decode(document, &target); err != nil {
+		return nil, err
+	}
+	return json.Marshal(merge(target, patchObject))
+}
+
+// merge merges a patch object into a target value
+// パッチのオブジェクトを対象の値にマージ
+func merge(target interface{}, patch map[string]interface{}) map[string]interface{} {
+	object, ok := target.(map[string]interface{})
+	if !ok {
+		object = make(map[string]interface{}, len(patch))
+	}
+	for name, value := range patch {
+		switch value := value.(type) {
+		case nil:
+			delete(object, name)
+		case map[string]interface{}:
+			object[name] = merge(object[name], value)
+		default:
+			object[name] = value
+		}
+	}
+	return object
+}
+
+// decode decodes JSON keeping numbers as json.Number so that they are not rounded
+// 数値を丸めないよう json.Number のままJSONをデコード
+func decode(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
@@ -0,0 +1,38 @@
+package mergepatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestApply は RFC 7396 の例に基づくマージのテスト
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		patch    string
+		want     string
+	}{
+		{"値の置き換え", `{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{"メンバーの追加", `{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{"nullで削除", `{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{"配列は置き換え", `{"a":["b"]}`, `{"a":["c","d"]}`, `{"a":["c","d"]}`},
+		{"入れ子のマージ", `{"a":{"b":"c","d":"e"}}`, `{"a":{"b":"x","d":null}}`, `{"a":{"b":"x"}}`},
+		{"オブジェクト以外をオブジェクトに", `{"a":"b"}`, `{"a":{"c":null,"d":1}}`, `{"a":{"d":1}}`},
+		{"空のパッチ", `{"a":1.2345678901234567}`, `{}`, `{"a":1.2345678901234567}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(tt.document), []byte(tt.patch))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+
+	_, err := Apply([]byte(`{"a":"b"}`), []byte(`["c"]`))
+	assert.ErrorIs(t, err, ErrNotObject)
+	_, err = Apply([]byte(`{"a":"b"}`), []byte(`{"a":`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,465 @@
+// Package openapi generates OpenAPI 3 documents from gorilla/mux routes
+// gorilla/muxのルートからOpenAPI 3ドキュメントを生成するパッケージ
+//
+// パスとメソッドはルーターから取得し、リクエスト・レスポンスのスキーマは
+// 操作ごとに指定したGoの型からJSONタグに従って生成します。
+package openapi
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Version is the OpenAPI version of generated documents
+// 生成するドキュメントのOpenAPIバージョン
+const Version = "3.0.3"
+
+// Document is an OpenAPI document
+// OpenAPIドキュメント
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Tags       []Tag                 `json:"tags,omitempty"`
+	Paths      map[string]*PathItem  `json:"paths"`
+	Components Components            `json:"components"`
+	Security   []SecurityRequirement `json:"security,omitempty"`
+}
+
+// Info is the metadata of the API
+// APIのメタデータ
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Tag groups operations
+// 操作のグループ
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// PathItem holds the operations of a path keyed by lower-case method
+// パスの操作（小文字のメソッド名がキー）
+type PathItem map[string]*Operation
+
+// Operation is an API operation
+// APIの操作
+type Operation struct {
+	Tags        []string               `json:"tags,omitempty"`
+	Summary     string                 `json:"summary,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	OperationID string                 `json:"operationId"`
+	Parameters  []Parameter            `json:"parameters,omitempty"`
+	RequestBody *RequestBody           `json:"requestBody,omitempty"`
+	Responses   map[string]*Response   `json:"responses"`
+	Security    *[]SecurityRequirement `json:"security,omitempty"` // 空の場合は認証不要
+	Deprecated  bool                   `json:"deprecated,omitempty"`
+}
+
+// Parameter is a path or query parameter
+// パスまたはクエリのパラメーター
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody is the body of a request
+// リクエスト本文
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response is a response of an operation
+// 操作のレスポンス
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType is the schema of a content type
+// コンテンツタイプのスキーマ
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the reusable schemas
+// 再利用するスキーマ
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme is an authentication method of the API
+// APIの認証方式
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	In           string `json:"in,omitempty"`   // type が apiKey の場合の指定場所（header など）
+	Name         string `json:"name,omitempty"` // type が apiKey の場合のヘッダー名など
+	Description  string `json:"description,omitempty"`
+}
+
+// SecurityRequirement maps security scheme names to required scopes
+// 認証方式の名前と要求するスコープの対応
+type SecurityRequirement map[string][]string
+
+// Schema is a JSON schema as used by OpenAPI 3.0
+// OpenAPI 3.0のJSONスキーマ
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// Param describes a query or header parameter of an operation
+// 操作のクエリパラメーター・ヘッダーの定義
+type Param struct {
+	Name        string
+	Type        string // string・integer・number・boolean（省略時は string）
+	Format      string // date-time など
+	Description string
+	Required    bool
+	Enum        []string
+}
+
+// parameter returns the parameter object of the param in a location
+// 指定した場所（query・header）のパラメーターを返す
+func (p Param) parameter(in string) Parameter {
+	typ := p.Type
+	if typ == "" {
+		typ = "string"
+	}
+	return Parameter{
+		Name:        p.Name,
+		In:          in,
+		Description: p.Description,
+		Required:    p.Required,
+		Schema:      &Schema{Type: typ, Format: p.Format, Enum: p.Enum},
+	}
+}
+
+// Spec describes an operation that cannot be derived from the route
+// ルートから取得できない操作の説明
+type Spec struct {
+	Tag         string
+	Summary     string
+	Description string
+	Query       []Param
+	Headers     []Param     // リクエストヘッダー（If-Match など）
+	Request     interface{} // リクエスト本文の型のゼロ値（nilの場合は本文なし）
+	RequestType string      // リクエスト本文のコンテンツタイプ（省略時は application/json）
+	Response    interface{} // 成功時の data の型のゼロ値（nilの場合は data なし）
+	ContentType string      // 成功時のコンテンツタイプ（省略時は JSON の封筒形式）
+	Public      bool        // 認証不要の操作（ドキュメント全体の security を適用しない）
+	Deprecated  bool        // 非推奨の操作
+}
+
+// Envelope describes the common response envelope of the API
+// APIに共通するレスポンスの封筒形式
+//
+// Build は成功時のレスポンスを Envelope(data) のスキーマで、エラー時を ErrorSchema で記述します。
+type Envelope struct {
+	Data        func(data *Schema) *Schema
+	ErrorSchema interface{} // エラーレスポンスの型のゼロ値
+}
+
+// pathVariable matches a mux path variable such as {itemId} or {id:[0-9]+}
+// muxのパス変数（{itemId} や {id:[0-9]+}）
+var pathVariable = regexp.MustCompile(`\{([^}:]+)(?::[^}]*)?\}`)
+
+// Build generates a document from the routes of router and the specs keyed by "METHOD /path"
+// ルーターのルートと「METHOD /path」をキーとする操作の説明からドキュメントを生成
+//
+// 説明のない操作もパスとパス変数のみで出力されます。ルーターに存在しない操作の説明がある場合は
+// ルートの変更に説明が追従していないためエラーを返します。
+func Build(info Info, router *mux.Router, specs map[string]Spec, envelope Envelope) (*Document, error) {
+	g := &generator{schemas: make(map[string]*Schema), names: make(map[reflect.Type]string)}
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]*PathItem),
+	}
+
+	used := make(map[string]bool, len(specs))
+	tags := make(map[string]bool)
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			// メソッドを指定していないルート（サブルーターなど）は対象外
+			return nil
+		}
+		openAPIPath := pathVariable.ReplaceAllString(path, "{$1}")
+
+		for _, method := range methods {
+			key := method + " " + path
+			spec, ok := specs[key]
+			used[key] = ok
+			if method == "OPTIONS" || method == "HEAD" {
+				continue
+			}
+
+			op := &Operation{
+				Summary:     spec.Summary,
+				Description: spec.Description,
+				OperationID: operationID(method, path),
+				Responses:   make(map[string]*Response),
+				Deprecated:  spec.Deprecated,
+			}
+			if spec.Public {
+				op.Security = &[]SecurityRequirement{}
+			}
+			if spec.Tag != "" {
+				op.Tags = []string{spec.Tag}
+				tags[spec.Tag] = true
+			}
+			for _, match := range pathVariable.FindAllStringSubmatch(path, -1) {
+				op.Parameters = append(op.Parameters, Parameter{Name: match[1], In: "path", Required: true, Schema: &Schema{Type: "string"}})
+			}
+			for _, p := range spec.Query {
+				op.Parameters = append(op.Parameters, p.parameter("query"))
+			}
+			for _, p := range spec.Headers {
+				op.Parameters = append(op.Parameters, p.parameter("header"))
+			}
+			if spec.Request != nil {
+				requestType := spec.RequestType
+				if requestType == "" {
+					requestType = "application/json"
+				}
+				op.RequestBody = &RequestBody{
+					Required: true,
+					Content:  map[string]MediaType{requestType: {Schema: g.schemaFor(reflect.TypeOf(spec.Request))}},
+				}
+			}
+
+			switch {
+			case spec.ContentType != "":
+				op.Responses["200"] = &Response{Description: "成功", Content: map[string]MediaType{spec.ContentType: {Schema: &Schema{Type: "string"}}}}
+			default:
+				var data *Schema
+				if spec.Response != nil {
+					data = g.schemaFor(reflect.TypeOf(spec.Response))
+				}
+				success := data
+				if envelope.Data != nil {
+					success = envelope.Data(data)
+				}
+				response := &Response{Description: "成功"}
+				if success != nil {
+					response.Content = map[string]MediaType{"application/json": {Schema: success}}
+				}
+				op.Responses["200"] = response
+			}
+			if envelope.ErrorSchema != nil {
+				op.Responses["default"] = &Response{
+					Description: "エラー",
+					Content:     map[string]MediaType{"application/json": {Schema: g.schemaFor(reflect.TypeOf(envelope.ErrorSchema))}},
+				}
+			}
+
+			item, ok := doc.Paths[openAPIPath]
+			if !ok {
+				item = &PathItem{}
+				doc.Paths[openAPIPath] = item
+			}
+			(*item)[strings.ToLower(method)] = op
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var stale []string
+	for key := range specs {
+		if !used[key] {
+			stale = append(stale, key)
+		}
+	}
+	if len(stale) > 0 {
+		sort.Strings(stale)
+		return nil, fmt.Errorf("ルーターに存在しない操作の説明があります: %s", strings.Join(stale, ", "))
+	}
+
+	names := make([]string, 0, len(tags))
+	for name := range tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		doc.Tags = append(doc.Tags, Tag{Name: name})
+	}
+	doc.Components.Schemas = g.schemas
+	return doc, nil
+}
+
+// operationID derives a unique operation ID from the method and path template
+// メソッドとパステンプレートから一意な操作IDを作成（例: GET /api/v1/items/{itemId} → getApiV1ItemsByItemId）
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" {
+			continue
+		}
+		if match := pathVariable.FindStringSubmatch(segment); match != nil {
+			b.WriteString("By")
+			segment = match[1]
+		}
+		for _, word := range strings.FieldsFunc(segment, func(r rune) bool { return r == '-' || r == '_' || r == '.' }) {
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return b.String()
+}
+
+// File is a file part of a multipart/form-data request body
+// multipart/form-data のリクエスト本文のファイル
+//
+// RequestType が multipart/form-data の Request の構造体のフィールドに使用すると、
+// format: binary の文字列として出力します。
+type File []byte
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+	fileType     = reflect.TypeOf(File(nil))
+)
+
+// generator builds schemas from Go types and collects named structs as components
+// Goの型からスキーマを作成し、名前付きの構造体をコンポーネントとして集める
+type generator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+// schemaFor returns the schema of a Go type as encoded by encoding/json
+// encoding/jsonでエンコードした場合のGoの型のスキーマを返す
+func (g *generator) schemaFor(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case durationType:
+		return &Schema{Type: "integer", Format: "int64", Description: "ナノ秒"}
+	case fileType:
+		return &Schema{Type: "string", Format: "binary"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		s := g.schemaFor(t.Elem())
+		if s.Ref != "" {
+			// OpenAPI 3.0 では $ref と nullable を並べられないため参照のまま返す
+			return s
+		}
+		s.Nullable = true
+		return s
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + g.componentName(t)}
+	default:
+		// interface{} などは任意の値
+		return &Schema{}
+	}
+}
+
+// componentName registers a named struct as a component and returns its name
+// 名前付きの構造体をコンポーネントとして登録し、その名前を返す
+//
+// 別のパッケージに同名の型がある場合はパッケージ名を前に付けます（例: eventsMessage）。
+func (g *generator) componentName(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+	name := t.Name()
+	if _, taken := g.schemas[name]; taken {
+		pkg := t.PkgPath()
+		pkg = pkg[strings.LastIndex(pkg, "/")+1:]
+		name = pkg + name
+	}
+	g.names[t] = name
+	g.schemas[name] = &Schema{} // 再帰する型のために先に登録する
+	*g.schemas[name] = *g.structSchema(t)
+	return name
+}
+
+// structSchema returns the object schema of a struct, flattening embedded structs
+// 構造体のオブジェクトスキーマを返す（埋め込みの構造体は展開する）
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "-" && options == "" {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for propName, prop := range g.structSchema(embedded).Properties {
+					s.Properties[propName] = prop
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		s.Properties[name] = g.schemaFor(f.Type)
+	}
+	return s
+}
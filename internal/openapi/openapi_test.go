@@ -0,0 +1,150 @@
+package openapi
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testBase struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type testItem struct {
+	testBase
+	Name     string            `json:"name"`
+	Price    *float64          `json:"price,omitempty"`
+	Tags     []string          `json:"tags"`
+	Attrs    map[string]int    `json:"attrs"`
+	Parent   *testItem         `json:"parent"`
+	Payload  []byte            `json:"payload"`
+	Upload   File              `json:"upload"`
+	Timeout  time.Duration     `json:"timeout"`
+	Extra    interface{}       `json:"extra"`
+	Labels   map[string]string `json:"-"`
+	NoTag    bool
+	internal string
+}
+
+type testError struct {
+	Error string `json:"error"`
+}
+
+func newTestRouter() *mux.Router {
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+	router := mux.NewRouter()
+	router.HandleFunc("/health", noop).Methods("GET")
+	api := router.PathPrefix("/api/v1").Subrouter()
+	api.HandleFunc("/items", noop).Methods("POST")
+	api.HandleFunc("/items/{itemId}", noop).Methods("GET", "PUT", "PATCH")
+	api.HandleFunc("/items/{itemId}/history/{txId:[0-9]+}", noop).Methods("GET")
+	return router
+}
+
+// TestBuild_Paths はルートからパス・操作・パラメーターを生成するテスト
+func TestBuild_Paths(t *testing.T) {
+	doc, err := Build(Info{Title: "test", Version: "1"}, newTestRouter(), map[string]Spec{
+		"POST /api/v1/items": {Tag: "items", Summary: "作成", Request: testItem{}, Response: testItem{}},
+		"GET /api/v1/items/{itemId}": {
+			Tag:      "items",
+			Query:    []Param{{Name: "verbose", Type: "boolean"}, {Name: "fields"}},
+			Response: []testItem{},
+		},
+		"PATCH /api/v1/items/{itemId}": {Tag: "items", Request: testItem{}, RequestType: "application/merge-patch+json", Headers: []Param{{Name: "If-Match"}}},
+		"GET /health":                  {Tag: "system", ContentType: "text/plain", Public: true},
+	}, Envelope{ErrorSchema: testError{}})
+	require.NoError(t, err)
+
+	assert.Equal(t, Version, doc.OpenAPI)
+	assert.Equal(t, []Tag{{Name: "items"}, {Name: "system"}}, doc.Tags)
+	require.Len(t, doc.Paths, 4)
+
+	item := *doc.Paths["/api/v1/items/{itemId}"]
+	require.Contains(t, item, "get")
+	require.Contains(t, item, "put")
+	get := item["get"]
+	assert.Equal(t, "getApiV1ItemsByItemId", get.OperationID)
+	require.Len(t, get.Parameters, 3)
+	assert.Equal(t, Parameter{Name: "itemId", In: "path", Required: true, Schema: &Schema{Type: "string"}}, get.Parameters[0])
+	assert.Equal(t, "boolean", get.Parameters[1].Schema.Type)
+	assert.Equal(t, "string", get.Parameters[2].Schema.Type)
+	assert.Equal(t, &Schema{Type: "array", Items: &Schema{Ref: "#/components/schemas/testItem"}}, get.Responses["200"].Content["application/json"].Schema)
+	assert.Equal(t, "#/components/schemas/testError", get.Responses["default"].Content["application/json"].Schema.Ref)
+
+	// 説明のないルートもパス変数のみで出力する（正規表現は除く）
+	history := *doc.Paths["/api/v1/items/{itemId}/history/{txId}"]
+	assert.Len(t, history["get"].Parameters, 2)
+	assert.Nil(t, (*doc.Paths["/api/v1/items/{itemId}"])["put"].RequestBody)
+
+	assert.Contains(t, (*doc.Paths["/health"])["get"].Responses["200"].Content, "text/plain")
+	assert.Equal(t, &[]SecurityRequirement{}, (*doc.Paths["/health"])["get"].Security, "認証不要の操作は空の security")
+	assert.Nil(t, get.Security)
+	assert.Contains(t, (*doc.Paths["/api/v1/items"])["post"].RequestBody.Content, "application/json")
+	assert.Contains(t, item["patch"].RequestBody.Content, "application/merge-patch+json")
+	assert.Equal(t, Parameter{Name: "If-Match", In: "header", Schema: &Schema{Type: "string"}}, item["patch"].Parameters[1])
+}
+
+// TestBuild_Schemas はGoの型からのスキーマ生成のテスト
+func TestBuild_Schemas(t *testing.T) {
+	doc, err := Build(Info{}, newTestRouter(), map[string]Spec{
+		"POST /api/v1/items": {Request: testItem{}},
+	}, Envelope{})
+	require.NoError(t, err)
+
+	schema := doc.Components.Schemas["testItem"]
+	require.NotNil(t, schema)
+	props := schema.Properties
+	assert.Equal(t, &Schema{Type: "string"}, props["id"], "埋め込みの構造体は展開する")
+	assert.Equal(t, &Schema{Type: "string", Format: "date-time"}, props["created_at"])
+	assert.Equal(t, &Schema{Type: "number", Format: "double", Nullable: true}, props["price"])
+	assert.Equal(t, &Schema{Type: "array", Items: &Schema{Type: "string"}}, props["tags"])
+	assert.Equal(t, &Schema{Type: "object", AdditionalProperties: &Schema{Type: "integer", Format: "int64"}}, props["attrs"])
+	assert.Equal(t, &Schema{Ref: "#/components/schemas/testItem"}, props["parent"], "再帰する型は参照にする")
+	assert.Equal(t, &Schema{Type: "string", Format: "byte"}, props["payload"])
+	assert.Equal(t, &Schema{Type: "string", Format: "binary"}, props["upload"])
+	assert.Equal(t, "integer", props["timeout"].Type)
+	assert.Equal(t, &Schema{}, props["extra"])
+	assert.Contains(t, props, "NoTag")
+	assert.NotContains(t, props, "Labels")
+	assert.NotContains(t, props, "internal")
+	assert.NotContains(t, props, "testBase")
+}
+
+// TestBuild_Envelope は成功時のレスポンスを封筒形式で包むテスト
+func TestBuild_Envelope(t *testing.T) {
+	wrap := func(data *Schema) *Schema {
+		s := &Schema{Type: "object", Properties: map[string]*Schema{"success": {Type: "boolean"}}}
+		if data != nil {
+			s.Properties["data"] = data
+		}
+		return s
+	}
+	doc, err := Build(Info{}, newTestRouter(), map[string]Spec{
+		"GET /api/v1/items/{itemId}": {Response: testItem{}},
+	}, Envelope{Data: wrap})
+	require.NoError(t, err)
+
+	get := (*doc.Paths["/api/v1/items/{itemId}"])["get"]
+	schema := get.Responses["200"].Content["application/json"].Schema
+	assert.Equal(t, "#/components/schemas/testItem", schema.Properties["data"].Ref)
+
+	put := (*doc.Paths["/api/v1/items/{itemId}"])["put"]
+	assert.NotContains(t, put.Responses["200"].Content["application/json"].Schema.Properties, "data")
+	assert.NotContains(t, put.Responses, "default")
+}
+
+// TestBuild_StaleSpec はルーターに存在しない操作の説明をエラーにするテスト
+func TestBuild_StaleSpec(t *testing.T) {
+	_, err := Build(Info{}, newTestRouter(), map[string]Spec{
+		"GET /api/v1/items":          {},
+		"DELETE /api/v1/items/{id}":  {},
+		"GET /api/v1/items/{itemId}": {},
+	}, Envelope{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "DELETE /api/v1/items/{id}, GET /api/v1/items")
+}
@@ -0,0 +1,130 @@
+// Package ratelimit limits request rates per client with token buckets
+// トークンバケットでクライアントごとのリクエスト頻度を制限するパッケージ
+//
+// クライアントごとに容量 Burst のバケットを持ち、毎秒 Rate 個のトークンを補充します。
+// リクエストごとにトークンを1つ消費し、トークンがない場合は拒否します。
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Config configures a limiter
+// リミッターの設定
+type Config struct {
+	Rate    float64       // 1秒あたりに補充するトークン数（定常的に許可するリクエスト数）
+	Burst   int           // バケットの容量（連続して許可するリクエスト数）
+	IdleTTL time.Duration // この期間使用されていないクライアントのバケットを破棄する（省略時は10分）
+}
+
+// Result is the outcome of a rate limit check
+// 頻度制限の判定結果
+type Result struct {
+	Allowed    bool          // リクエストを許可するか
+	Limit      int           // バケットの容量
+	Remaining  int           // 判定後に残っているトークン数
+	RetryAfter time.Duration // 拒否した場合に次のトークンが補充されるまでの時間
+	// FirstRejection はクライアントが許可された状態から拒否に転じたことを示す
+	// （拒否が続く間のログ出力を1回に抑えるために使用）
+	FirstRejection bool
+}
+
+// Limiter keeps a token bucket per client key
+// クライアントのキーごとにトークンバケットを保持するリミッター
+type Limiter struct {
+	config Config
+	now    func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// bucket is the token bucket of a client
+// クライアントのトークンバケット
+type bucket struct {
+	tokens   float64
+	updated  time.Time
+	rejected bool // 直前のリクエストを拒否したか
+}
+
+// New creates a limiter
+// リミッターを作成
+func New(config Config) *Limiter {
+	if config.Burst < 1 {
+		config.Burst = 1
+	}
+	if config.IdleTTL <= 0 {
+		config.IdleTTL = 10 * time.Minute
+	}
+	return &Limiter{
+		config:    config,
+		now:       time.Now,
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// Allow consumes a token of the client and reports whether the request is allowed
+// クライアントのトークンを1つ消費し、リクエストを許可するかを返す
+func (l *Limiter) Allow(key string) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, exists := l.buckets[key]
+	if !exists {
+		b = &bucket{tokens: float64(l.config.Burst), updated: now}
+		l.buckets[key] = b
+	} else if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens = math.Min(float64(l.config.Burst), b.tokens+elapsed.Seconds()*l.config.Rate)
+		b.updated = now
+	}
+
+	result := Result{Limit: l.config.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		b.rejected = false
+		result.Allowed = true
+		result.Remaining = int(b.tokens)
+		return result
+	}
+
+	result.FirstRejection = !b.rejected
+	b.rejected = true
+	if l.config.Rate > 0 {
+		result.RetryAfter = time.Duration((1 - b.tokens) / l.config.Rate * float64(time.Second))
+	} else {
+		result.RetryAfter = l.config.IdleTTL
+	}
+	return result
+}
+
+// Len returns the number of tracked clients
+// 保持しているクライアント数を返す
+func (l *Limiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return len(l.buckets)
+}
+
+// sweep discards buckets idle for longer than IdleTTL
+// IdleTTLを超えて使用されていないバケットを破棄
+//
+// 呼び出し元でロックを取得していること。走査はIdleTTLごとに1回のみ行います。
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.config.IdleTTL {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if now.Sub(b.updated) >= l.config.IdleTTL {
+			delete(l.buckets, key)
+		}
+	}
+}
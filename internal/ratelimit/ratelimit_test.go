@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLimiter_Allow はトークンの消費と補充のテスト
+func TestLimiter_Allow(t *testing.T) {
+	limiter := New(Config{Rate: 2, Burst: 3})
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		result := limiter.Allow("a")
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 3, result.Limit)
+		assert.Equal(t, 2-i, result.Remaining)
+	}
+
+	result := limiter.Allow("a")
+	assert.False(t, result.Allowed)
+	assert.True(t, result.FirstRejection)
+	assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+	// 拒否が続く間は最初の拒否のみ通知する
+	assert.False(t, limiter.Allow("a").FirstRejection)
+
+	// 他のクライアントは影響を受けない
+	assert.True(t, limiter.Allow("b").Allowed)
+
+	// 0.5秒で1トークン補充される
+	now = now.Add(500 * time.Millisecond)
+	assert.True(t, limiter.Allow("a").Allowed)
+	assert.False(t, limiter.Allow("a").Allowed)
+
+	// 補充はバケットの容量まで
+	now = now.Add(time.Hour)
+	result = limiter.Allow("a")
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 2, result.Remaining)
+}
+
+// TestLimiter_Sweep は使用されていないバケットの破棄のテスト
+func TestLimiter_Sweep(t *testing.T) {
+	limiter := New(Config{Rate: 1, Burst: 1, IdleTTL: time.Minute})
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	limiter.Allow("a")
+	limiter.Allow("b")
+	assert.Equal(t, 2, limiter.Len())
+
+	now = now.Add(30 * time.Second)
+	limiter.Allow("b")
+	now = now.Add(45 * time.Second)
+	limiter.Allow("c")
+	assert.Equal(t, 2, limiter.Len(), "aのバケットのみ破棄される")
+}
@@ -0,0 +1,60 @@
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// csvWriter writes rows as CSV
+// 行をCSVとして書き出す
+type csvWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+// newCSVWriter writes the UTF-8 BOM so that Excel detects the encoding
+// ExcelがUTF-8と判別できるようBOMを書き出してからCSVのWriterを返す
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return nil, err
+	}
+	return &csvWriter{w: csv.NewWriter(w)}, nil
+}
+
+func (c *csvWriter) WriteRow(values ...interface{}) error {
+	c.record = c.record[:0]
+	for _, value := range values {
+		switch value.(type) {
+		case string, *string:
+			c.record = append(c.record, neutralizeFormula(text(value)))
+		default:
+			c.record = append(c.record, text(value))
+		}
+	}
+	return c.w.Write(c.record)
+}
+
+// neutralizeFormula prefixes text cells that a spreadsheet would evaluate as a formula with a quote
+// 表計算ソフトで数式として評価される文字列のセルの先頭に ' を付ける
+//
+// 品目名などの利用者が登録した値で CSV インジェクションが起きないよう、=・+・-・@・タブ・CR で
+// 始まる文字列を無害化します。数値のセルは負の値を文字列にしないよう対象外です。
+func neutralizeFormula(value string) string {
+	if value == "" {
+		return value
+	}
+	switch value[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + value
+	}
+	return value
+}
+
+func (c *csvWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) Close() error {
+	return c.Flush()
+}
@@ -0,0 +1,103 @@
+// Package spreadsheet writes rows as CSV or Excel (XLSX) files
+// 行をCSVまたはExcel（XLSX）ファイルとして書き出すパッケージ
+//
+// どちらの形式も行を受け取るたびに書き出すため、全件をメモリに展開せずに
+// HTTPレスポンスへストリーミングできます。
+package spreadsheet
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Format is the file format of a spreadsheet
+// スプレッドシートのファイル形式
+type Format string
+
+const (
+	FormatCSV  Format = "csv"  // CSV（UTF-8、BOM付き）
+	FormatXLSX Format = "xlsx" // Excel ブック
+)
+
+// ParseFormat parses a format name (an empty name is CSV)
+// 形式名を解析（空の場合はCSV）
+func ParseFormat(name string) (Format, error) {
+	switch Format(name) {
+	case "", FormatCSV:
+		return FormatCSV, nil
+	case FormatXLSX:
+		return FormatXLSX, nil
+	}
+	return "", fmt.Errorf("サポートされていない形式です: %s（csv または xlsx を指定してください）", name)
+}
+
+// ContentType returns the media type of the format
+// 形式のメディアタイプを返す
+func (f Format) ContentType() string {
+	if f == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Writer writes the rows of a spreadsheet
+// スプレッドシートの行を書き出す
+//
+// 値は string、int64、float64、time.Time とそれらのポインタを受け付け、nil は空のセルになります。
+type Writer interface {
+	// WriteRow writes a row / 1行を書き出す
+	WriteRow(values ...interface{}) error
+	// Flush writes buffered rows to the underlying writer / バッファ済みの行を書き出す
+	Flush() error
+	// Close finishes the file without closing the underlying writer / ファイルを完成させる（下位のWriterは閉じない）
+	Close() error
+}
+
+// NewWriter returns a writer of the format with the sheet name used by XLSX
+// 指定した形式のWriterを返す（シート名はXLSXで使用）
+func NewWriter(format Format, w io.Writer, sheet string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatXLSX:
+		return newXLSXWriter(w, sheet)
+	}
+	return nil, fmt.Errorf("サポートされていない形式です: %s", format)
+}
+
+// text formats a value as text
+// 値を文字列に変換
+func text(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case *string:
+		if v == nil {
+			return ""
+		}
+		return *v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case *float64:
+		if v == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*v, 'f', -1, 64)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,98 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseFormat は形式名の解析のテスト
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("")
+	require.NoError(t, err)
+	assert.Equal(t, FormatCSV, format)
+	format, err = ParseFormat("xlsx")
+	require.NoError(t, err)
+	assert.Equal(t, FormatXLSX, format)
+	_, err = ParseFormat("pdf")
+	assert.Error(t, err)
+}
+
+// TestCSVWriter はCSVの書き出しのテスト
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatCSV, &buf, "")
+	require.NoError(t, err)
+
+	cost := 12.5
+	createdAt := time.Date(2024, 4, 1, 9, 30, 0, 0, time.UTC)
+	require.NoError(t, w.WriteRow("id", "quantity", "unit_cost", "created_at", "lot"))
+	require.NoError(t, w.WriteRow("ITEM,1", int64(10), &cost, createdAt, (*string)(nil)))
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, "\ufeffid,quantity,unit_cost,created_at,lot\n\"ITEM,1\",10,12.5,2024-04-01T09:30:00Z,\n", buf.String())
+}
+
+// TestCSVWriter_FormulaInjection は数式として評価される文字列の無害化のテスト
+func TestCSVWriter_FormulaInjection(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatCSV, &buf, "")
+	require.NoError(t, err)
+
+	name := "@SUM(A1:A2)"
+	require.NoError(t, w.WriteRow("=HYPERLINK(\"http://example.com\")", "+1", "-1+2", &name, "\tTAB", "\rCR"))
+	require.NoError(t, w.WriteRow("A=1", "", int64(-5), -1.5))
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, "\ufeff\"'=HYPERLINK(\"\"http://example.com\"\")\",'+1,'-1+2,'@SUM(A1:A2),'\tTAB,\"'\rCR\"\n"+
+		"A=1,,-5,-1.5\n", buf.String())
+}
+
+// TestXLSXWriter はExcelブックの書き出しのテスト
+func TestXLSXWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatXLSX, &buf, "在庫/LOC-A")
+	require.NoError(t, err)
+
+	createdAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, w.WriteRow("id", "quantity", "created_at"))
+	require.NoError(t, w.WriteRow("A&B <1>", int64(10), createdAt))
+	require.NoError(t, w.WriteRow("", nil, 1.5))
+	require.NoError(t, w.Close())
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	files := make(map[string]string)
+	for _, f := range reader.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		files[f.Name] = string(content)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml"} {
+		assert.Contains(t, files, name)
+	}
+	assert.Contains(t, files["xl/workbook.xml"], `<sheet name="在庫_LOC-A"`, "使用できない文字は置き換える")
+
+	sheet := files["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<row><c t="inlineStr"><is><t xml:space="preserve">id</t></is></c>`)
+	assert.Contains(t, sheet, `<t xml:space="preserve">A&amp;B &lt;1&gt;</t>`)
+	assert.Contains(t, sheet, `<c><v>10</v></c><c s="1"><v>45292.5</v></c></row>`)
+	assert.Contains(t, sheet, `<row><c/><c/><c><v>1.5</v></c></row>`)
+	assert.True(t, len(sheet) > 0 && sheet[len(sheet)-len(sheetEndXML):] == sheetEndXML)
+}
+
+// TestSheetName はシート名の調整のテスト
+func TestSheetName(t *testing.T) {
+	assert.Equal(t, "Sheet1", sheetName(""))
+	assert.Equal(t, "a_b_c", sheetName("a[b]c"))
+	assert.Len(t, []rune(sheetName("ロケーションロケーションロケーションロケーションロケーションロケーション")), maxSheetNameLength)
+}
@@ -0,0 +1,222 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// xlsxWriter writes rows as a single-sheet Excel workbook
+// 行を1シートのExcelブックとして書き出す
+//
+// 共有文字列テーブルを使用せずインライン文字列で書き出すため、行をストリーミングできます。
+// 日時は日付の書式を設定したシリアル値のセルとして書き出します。
+type xlsxWriter struct {
+	zip   *zip.Writer
+	sheet *bufio.Writer
+}
+
+// maxSheetNameLength is the maximum length of an Excel sheet name
+// Excelのシート名の最大長
+const maxSheetNameLength = 31
+
+// dateStyle is the index of the cell style with a date format in styles.xml
+// styles.xml の日時の書式のセルスタイルの番号
+const dateStyle = 1
+
+// excelEpoch is the day before the first serial date of Excel (the 1900 leap year bug included)
+// Excelのシリアル値の起点（1900年のうるう年の不具合を含む）
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+const (
+	xmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+	contentTypesXML = xmlHeader + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`</Types>`
+
+	rootRelsXML = xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	workbookRelsXML = xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`
+
+	workbookXML = xmlHeader + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+	// cellXfs の1番目（dateStyle）は yyyy-mm-dd hh:mm:ss の日時
+	stylesXML = xmlHeader + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm:ss"/></numFmts>` +
+		`<fonts count="1"><font><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+		`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/></cellXfs>` +
+		`</styleSheet>`
+
+	sheetStartXML = xmlHeader + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	sheetEndXML   = `</sheetData></worksheet>`
+)
+
+// newXLSXWriter writes the workbook parts and starts the worksheet
+// ブックの構成ファイルを書き出し、ワークシートを開始する
+func newXLSXWriter(w io.Writer, sheet string) (*xlsxWriter, error) {
+	zw := zip.NewWriter(w)
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", contentTypesXML},
+		{"_rels/.rels", rootRelsXML},
+		{"xl/workbook.xml", fmt.Sprintf(workbookXML, escape(sheetName(sheet)))},
+		{"xl/_rels/workbook.xml.rels", workbookRelsXML},
+		{"xl/styles.xml", stylesXML},
+	}
+	for _, part := range parts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	// ワークシートは最後のエントリとし、行を受け取るたびに書き出す
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	x := &xlsxWriter{zip: zw, sheet: bufio.NewWriter(f)}
+	if _, err := x.sheet.WriteString(sheetStartXML); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+func (x *xlsxWriter) WriteRow(values ...interface{}) error {
+	x.sheet.WriteString("<row>")
+	for _, value := range values {
+		x.writeCell(value)
+	}
+	_, err := x.sheet.WriteString("</row>")
+	return err
+}
+
+// writeCell writes a cell as a number, a date or an inline string
+// セルを数値・日時・インライン文字列のいずれかとして書き出す
+func (x *xlsxWriter) writeCell(value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		x.sheet.WriteString("<c/>")
+		return
+	case int64:
+		x.number(strconv.FormatInt(v, 10), 0)
+		return
+	case int:
+		x.number(strconv.Itoa(v), 0)
+		return
+	case float64:
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			x.number(strconv.FormatFloat(v, 'f', -1, 64), 0)
+			return
+		}
+	case *float64:
+		if v == nil {
+			x.sheet.WriteString("<c/>")
+			return
+		}
+		x.writeCell(*v)
+		return
+	case time.Time:
+		x.number(strconv.FormatFloat(serial(v), 'f', -1, 64), dateStyle)
+		return
+	case *time.Time:
+		if v == nil {
+			x.sheet.WriteString("<c/>")
+			return
+		}
+		x.writeCell(*v)
+		return
+	}
+
+	s := text(value)
+	if s == "" {
+		x.sheet.WriteString("<c/>")
+		return
+	}
+	x.sheet.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+	xml.EscapeText(x.sheet, []byte(s))
+	x.sheet.WriteString("</t></is></c>")
+}
+
+// number writes a numeric cell with a style
+// スタイル付きで数値のセルを書き出す
+func (x *xlsxWriter) number(v string, style int) {
+	if style != 0 {
+		fmt.Fprintf(x.sheet, `<c s="%d"><v>%s</v></c>`, style, v)
+		return
+	}
+	x.sheet.WriteString("<c><v>" + v + "</v></c>")
+}
+
+func (x *xlsxWriter) Flush() error {
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.zip.Flush()
+}
+
+func (x *xlsxWriter) Close() error {
+	if _, err := x.sheet.WriteString(sheetEndXML); err != nil {
+		return err
+	}
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.zip.Close()
+}
+
+// serial converts a time into an Excel serial date of its wall clock
+// 日時を壁時計の時刻のExcelシリアル値に変換
+func serial(t time.Time) float64 {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	return wall.Sub(excelEpoch).Hours() / 24
+}
+
+// sheetName removes the characters that Excel does not allow in sheet names
+// Excelのシート名に使用できない文字を取り除く
+func sheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	if name == "" {
+		name = "Sheet1"
+	}
+	if runes := []rune(name); len(runes) > maxSheetNameLength {
+		name = string(runes[:maxSheetNameLength])
+	}
+	return name
+}
+
+// escape escapes text for XML attributes
+// XMLの属性値として文字列をエスケープ
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,5 @@
+-- トランザクション履歴のキーセットページネーション用インデックス
+-- Index supporting keyset pagination of transaction history
+
+-- 商品別履歴を (created_at, id) の降順で辿るための複合インデックス
+CREATE INDEX idx_transactions_item_created_at_id ON transactions(item_id, created_at DESC, id DESC);
@@ -0,0 +1,5 @@
+-- トランザクションメタデータ検索用のGINインデックス
+-- GIN index supporting transaction metadata queries
+
+-- SearchTransactionsByMetadata の包含検索（metadata @> '{"key": "value"}'）で使用
+CREATE INDEX idx_transactions_metadata ON transactions USING GIN (metadata jsonb_path_ops);
@@ -0,0 +1,5 @@
+-- 参照番号によるトランザクション検索用インデックス
+-- Index supporting transaction lookup by reference number
+
+-- GetTransactionsByReference（発注書番号などでの全移動の取得）で使用
+CREATE INDEX idx_transactions_reference ON transactions(reference);
@@ -0,0 +1,85 @@
+-- トランザクションテーブルの月次パーティショニング
+-- Monthly range partitioning of the transactions table by created_at
+
+-- 既存テーブルを退避（インデックス名の衝突を避けるため先にリネーム）
+ALTER TABLE transactions RENAME TO transactions_legacy;
+ALTER INDEX transactions_pkey RENAME TO transactions_legacy_pkey;
+ALTER INDEX idx_transactions_item_id RENAME TO idx_transactions_legacy_item_id;
+ALTER INDEX idx_transactions_created_at RENAME TO idx_transactions_legacy_created_at;
+ALTER INDEX idx_transactions_type RENAME TO idx_transactions_legacy_type;
+ALTER INDEX idx_transactions_item_created_at_id RENAME TO idx_transactions_legacy_item_created_at_id;
+ALTER INDEX idx_transactions_metadata RENAME TO idx_transactions_legacy_metadata;
+ALTER INDEX idx_transactions_reference RENAME TO idx_transactions_legacy_reference;
+
+-- パーティションテーブル（主キーにはパーティションキーを含める必要がある）
+CREATE TABLE transactions (
+    id VARCHAR(255) NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    item_id VARCHAR(255) NOT NULL,
+    from_location VARCHAR(255),
+    to_location VARCHAR(255),
+    quantity BIGINT NOT NULL,
+    unit_cost DECIMAL(12,4),
+    reference VARCHAR(500),
+    lot_number VARCHAR(255),
+    expiry_date TIMESTAMP,
+    metadata JSONB,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(255) NOT NULL DEFAULT 'system',
+    PRIMARY KEY (id, created_at),
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+    FOREIGN KEY (from_location) REFERENCES locations(id) ON DELETE SET NULL,
+    FOREIGN KEY (to_location) REFERENCES locations(id) ON DELETE SET NULL
+) PARTITION BY RANGE (created_at);
+
+-- 月次パーティション作成関数（transactions_YYYY_MM、既に存在する場合は何もしない）
+CREATE OR REPLACE FUNCTION create_transactions_partition(p_month DATE)
+RETURNS VOID AS $$
+DECLARE
+    start_date DATE := date_trunc('month', p_month)::DATE;
+    end_date DATE := (date_trunc('month', p_month) + INTERVAL '1 month')::DATE;
+    partition_name TEXT := 'transactions_' || to_char(start_date, 'YYYY_MM');
+BEGIN
+    EXECUTE format(
+        'CREATE TABLE IF NOT EXISTS %I PARTITION OF transactions FOR VALUES FROM (%L) TO (%L)',
+        partition_name, start_date, end_date
+    );
+END;
+$$ LANGUAGE plpgsql;
+
+-- 既存データの期間と今後3か月分のパーティションを作成
+DO $$
+DECLARE
+    first_month DATE;
+    m DATE;
+BEGIN
+    SELECT COALESCE(date_trunc('month', MIN(created_at)), date_trunc('month', NOW()))::DATE
+    INTO first_month
+    FROM transactions_legacy;
+
+    FOR m IN
+        SELECT generate_series(first_month, (date_trunc('month', NOW()) + INTERVAL '3 months')::DATE, INTERVAL '1 month')::DATE
+    LOOP
+        PERFORM create_transactions_partition(m);
+    END LOOP;
+END;
+$$;
+
+-- 事前作成されていない月のデータを受け止めるデフォルトパーティション
+CREATE TABLE transactions_default PARTITION OF transactions DEFAULT;
+
+-- 既存データを移行
+INSERT INTO transactions
+SELECT id, type, item_id, from_location, to_location, quantity, unit_cost, reference,
+       lot_number, expiry_date, metadata, created_at, created_by
+FROM transactions_legacy;
+
+DROP TABLE transactions_legacy;
+
+-- インデックス（各パーティションに自動的に作成される）
+CREATE INDEX idx_transactions_item_id ON transactions(item_id);
+CREATE INDEX idx_transactions_created_at ON transactions(created_at DESC);
+CREATE INDEX idx_transactions_type ON transactions(type);
+CREATE INDEX idx_transactions_item_created_at_id ON transactions(item_id, created_at DESC, id DESC);
+CREATE INDEX idx_transactions_metadata ON transactions USING GIN (metadata jsonb_path_ops);
+CREATE INDEX idx_transactions_reference ON transactions(reference);
@@ -0,0 +1,23 @@
+-- トランザクションのアーカイブテーブル
+-- Archive table for transactions moved out of the hot table by the retention policy
+
+-- 保持期間を過ぎたトランザクションの移動先（ArchiveTransactions / cmd/archive で使用）
+CREATE TABLE transactions_archive (
+    id VARCHAR(255) PRIMARY KEY,
+    type VARCHAR(50) NOT NULL,
+    item_id VARCHAR(255) NOT NULL,
+    from_location VARCHAR(255),
+    to_location VARCHAR(255),
+    quantity BIGINT NOT NULL,
+    unit_cost DECIMAL(12,4),
+    reference VARCHAR(500),
+    lot_number VARCHAR(255),
+    expiry_date TIMESTAMP,
+    metadata JSONB,
+    created_at TIMESTAMP NOT NULL,
+    created_by VARCHAR(255) NOT NULL,
+    archived_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_transactions_archive_item_created_at ON transactions_archive(item_id, created_at DESC);
+CREATE INDEX idx_transactions_archive_reference ON transactions_archive(reference);
@@ -0,0 +1,19 @@
+-- イベント発行の再試行キューとデッドレター
+-- Retry queue and dead letters for failed event publishes
+
+-- 発行に失敗したイベント（status: pending は再試行待ち、dead はデッドレター）
+CREATE TABLE event_retry_queue (
+    id VARCHAR(255) PRIMARY KEY,
+    type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 1,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_event_retry_queue_status CHECK (status IN ('pending', 'dead'))
+);
+
+CREATE INDEX idx_event_retry_queue_due ON event_retry_queue(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_event_retry_queue_dead ON event_retry_queue(created_at DESC) WHERE status = 'dead';
@@ -0,0 +1,13 @@
+-- 外部システムからの同期メッセージの冪等性キー
+-- Idempotency keys of stock sync messages received from external systems
+
+-- 処理済み・処理中のメッセージ（status: processing は適用中、completed は適用済み）
+CREATE TABLE processed_messages (
+    idempotency_key VARCHAR(255) PRIMARY KEY,
+    status VARCHAR(20) NOT NULL DEFAULT 'processing',
+    claimed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP,
+    CONSTRAINT chk_processed_messages_status CHECK (status IN ('processing', 'completed'))
+);
+
+CREATE INDEX idx_processed_messages_completed_at ON processed_messages(completed_at);
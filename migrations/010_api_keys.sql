@@ -0,0 +1,18 @@
+-- システム間連携用のAPIキー
+-- API keys for machine-to-machine callers
+
+-- 発行したAPIキー（キー自体は保存せず、SHA-256のハッシュのみを保存）
+CREATE TABLE api_keys (
+    id VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    prefix VARCHAR(20) NOT NULL,
+    key_hash CHAR(64) NOT NULL,
+    scopes TEXT[] NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    rotated_to VARCHAR(255) NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX idx_api_keys_hash ON api_keys(key_hash);
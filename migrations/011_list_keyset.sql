@@ -0,0 +1,6 @@
+-- 商品・ロケーション一覧のカーソルページネーション用インデックス
+-- Indexes supporting cursor pagination of item and location lists
+
+-- 一覧を (created_at, id) の降順で辿るための複合インデックス
+CREATE INDEX idx_items_created_at_id ON items(created_at DESC, id DESC);
+CREATE INDEX idx_locations_created_at_id ON locations(created_at DESC, id DESC);
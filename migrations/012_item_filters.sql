@@ -0,0 +1,13 @@
+-- 商品一覧の絞り込み・並び替え用インデックス
+-- Indexes supporting filtering and sorting of the item list
+
+-- カテゴリで絞り込み、作成日時の降順で辿るための複合インデックス
+CREATE INDEX idx_items_category_created_at_id ON items(category, created_at DESC, id DESC);
+
+-- SKUの前方一致（LIKE 'prefix%'）はロケールに依存せずインデックスを使用できるようパターン演算子クラスで作成
+CREATE INDEX idx_items_sku_pattern ON items(sku varchar_pattern_ops);
+
+-- 並び替えのキーセットページネーション用（逆方向の走査で降順にも使用）
+CREATE INDEX idx_items_name_id ON items(name, id);
+CREATE INDEX idx_items_sku_id ON items(sku, id);
+CREATE INDEX idx_items_unit_cost_id ON items(unit_cost, id);
@@ -0,0 +1,19 @@
+-- バッチ操作の記録
+-- Records of batch inventory operations
+
+-- GetBatchStatus で照会できるよう、実行開始時に pending で作成し、完了時に結果で上書きする
+CREATE TABLE batch_operations (
+    id VARCHAR(255) PRIMARY KEY,
+    status VARCHAR(20) NOT NULL,
+    atomic BOOLEAN NOT NULL DEFAULT false,
+    operations JSONB NOT NULL,
+    results JSONB NOT NULL DEFAULT '[]',
+    errors JSONB NOT NULL DEFAULT '[]',
+    success_count INTEGER NOT NULL DEFAULT 0,
+    failure_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP
+);
+
+-- 古い記録の削除用
+CREATE INDEX idx_batch_operations_created_at ON batch_operations(created_at);
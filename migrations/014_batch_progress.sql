@@ -0,0 +1,9 @@
+-- 非同期バッチの進捗・取り消し
+-- Progress and cancellation of asynchronous batches
+
+ALTER TABLE batch_operations
+    ADD COLUMN cancelled_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN progress INTEGER NOT NULL DEFAULT 0;
+
+-- 実行待ち・実行中のバッチの確認用
+CREATE INDEX idx_batch_operations_status ON batch_operations(status) WHERE status IN ('pending', 'running');
@@ -0,0 +1,24 @@
+-- 在庫の予約
+-- Stock reservations with IDs and expiry
+
+-- 予約ごとに確保した数量・参照・有効期限を記録する（stock.reserved は有効な予約の合計）
+CREATE TABLE reservations (
+    id VARCHAR(255) PRIMARY KEY,
+    item_id VARCHAR(255) NOT NULL,
+    location_id VARCHAR(255) NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    reference VARCHAR(255),
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(255),
+    released_at TIMESTAMP,
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+    FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE CASCADE
+);
+
+-- 商品・ロケーション・状態による一覧・解除用
+CREATE INDEX idx_reservations_item_location ON reservations(item_id, location_id, status, created_at);
+
+-- 期限切れの予約の検索用（有効な予約のみ）
+CREATE INDEX idx_reservations_expires_at ON reservations(expires_at) WHERE status = 'active';
@@ -0,0 +1,5 @@
+-- 参照番号による予約検索用インデックス
+-- Index supporting reservation lookup by reference number
+
+-- GetReservationsByReference・ReleaseReservationsByReference（注文番号での予約の取得・解除）で使用
+CREATE INDEX idx_reservations_reference ON reservations(reference, status);
@@ -0,0 +1,24 @@
+-- バックオーダー（在庫不足のため受け付けた取り寄せ）
+-- Backorders of stock that was not available
+
+-- 入荷時に作成日時の順で予約として引き当てる（reservation_id は引当で作成した予約）
+CREATE TABLE backorders (
+    id VARCHAR(255) PRIMARY KEY,
+    item_id VARCHAR(255) NOT NULL,
+    location_id VARCHAR(255) NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    reference VARCHAR(255),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reservation_id VARCHAR(255) REFERENCES reservations(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(255),
+    closed_at TIMESTAMP,
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+    FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE CASCADE
+);
+
+-- 入荷時の引当用（入荷待ちのバックオーダーのみ）
+CREATE INDEX idx_backorders_pending ON backorders(item_id, location_id, created_at) WHERE status = 'pending';
+
+-- 参照番号による照会用
+CREATE INDEX idx_backorders_reference ON backorders(reference);
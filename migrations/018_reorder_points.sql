@@ -0,0 +1,19 @@
+-- 商品・ロケーションごとの発注点
+-- Reorder points of item-location pairs
+
+-- 設定した在庫は在庫設定の low_stock_threshold の代わりに reorder_point を低在庫アラートの閾値に使用する
+CREATE TABLE reorder_points (
+    item_id VARCHAR(255) NOT NULL,
+    location_id VARCHAR(255) NOT NULL,
+    reorder_point BIGINT NOT NULL CHECK (reorder_point >= 0),
+    min_qty BIGINT NOT NULL DEFAULT 0 CHECK (min_qty >= 0),
+    max_qty BIGINT NOT NULL DEFAULT 0 CHECK (max_qty >= 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_by VARCHAR(255),
+    PRIMARY KEY (item_id, location_id),
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+    FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE CASCADE
+);
+
+-- ロケーション別の照会・低在庫の集計用
+CREATE INDEX idx_reorder_points_location ON reorder_points(location_id);
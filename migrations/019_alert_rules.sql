@@ -0,0 +1,31 @@
+-- 設定可能なアラートルール
+-- Configurable alert rules
+
+-- item_id・location_id が NULL の場合は全商品・全ロケーションが対象
+CREATE TABLE alert_rules (
+    id VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    metric VARCHAR(20) NOT NULL CHECK (metric IN ('quantity', 'available', 'reserved')),
+    comparator VARCHAR(10) NOT NULL CHECK (comparator IN ('lt', 'lte', 'gt', 'gte')),
+    threshold BIGINT NOT NULL CHECK (threshold >= 0),
+    item_id VARCHAR(255),
+    location_id VARCHAR(255),
+    severity VARCHAR(20) NOT NULL CHECK (severity IN ('info', 'warning', 'critical')),
+    cooldown_seconds BIGINT NOT NULL DEFAULT 0 CHECK (cooldown_seconds >= 0),
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_by VARCHAR(255),
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+    FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE CASCADE
+);
+
+-- 在庫の更新時に対象ルールを取得するため
+CREATE INDEX idx_alert_rules_scope ON alert_rules(item_id, location_id) WHERE enabled = true;
+
+-- アラートを発生させたルールと重大度
+ALTER TABLE stock_alerts ADD COLUMN rule_id VARCHAR(255);
+ALTER TABLE stock_alerts ADD COLUMN severity VARCHAR(20);
+
+-- ルールごとの重複・クールダウンの判定用
+CREATE INDEX idx_stock_alerts_rule ON stock_alerts(rule_id, item_id, location_id, created_at DESC) WHERE rule_id IS NOT NULL;
@@ -0,0 +1,17 @@
+-- アラートの重要度と確認・解決の記録
+-- Alert severity and acknowledgement workflow
+
+-- 確認（対応中であることの表明）は解決とは別に記録する
+ALTER TABLE stock_alerts ADD COLUMN acknowledged_at TIMESTAMP;
+ALTER TABLE stock_alerts ADD COLUMN acknowledged_by VARCHAR(255);
+ALTER TABLE stock_alerts ADD COLUMN acknowledge_note TEXT;
+ALTER TABLE stock_alerts ADD COLUMN resolved_by VARCHAR(255);
+ALTER TABLE stock_alerts ADD COLUMN resolve_note TEXT;
+
+-- 重要度のない既存のアラートは組み込みの判定と同じ重要度にする（在庫切れは critical）
+UPDATE stock_alerts
+SET severity = CASE WHEN type = 'low_stock' AND current_qty <= 0 THEN 'critical' ELSE 'warning' END
+WHERE severity IS NULL;
+ALTER TABLE stock_alerts ALTER COLUMN severity SET DEFAULT 'warning';
+ALTER TABLE stock_alerts ALTER COLUMN severity SET NOT NULL;
+ALTER TABLE stock_alerts ADD CONSTRAINT stock_alerts_severity_check CHECK (severity IN ('info', 'warning', 'critical'));
@@ -0,0 +1,8 @@
+-- 全ロケーションのアラート一覧用インデックス
+-- Indexes supporting the alert listing across all locations
+
+-- ListAlerts（作成日時の新しい順のページネーション、作成日時の範囲指定）で使用
+CREATE INDEX idx_stock_alerts_created_at ON stock_alerts(created_at DESC, id DESC);
+
+-- 重要度・アクティブ状態での絞り込み（ダッシュボードの未解決の重大アラートなど）で使用
+CREATE INDEX idx_stock_alerts_severity ON stock_alerts(severity, is_active, created_at DESC);
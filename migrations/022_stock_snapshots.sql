@@ -0,0 +1,28 @@
+-- 在庫スナップショット（全ての在庫記録のある時点の複製）
+-- Point-in-time copies of the stock records
+
+-- 定期ジョブ（stock_snapshot）または取得APIで作成し、月末在庫や在庫推移の集計に使用する
+CREATE TABLE stock_snapshots (
+    id VARCHAR(255) PRIMARY KEY,
+    taken_at TIMESTAMP NOT NULL,
+    taken_by VARCHAR(255) NOT NULL,
+    stock_count INTEGER NOT NULL DEFAULT 0,
+    total_quantity BIGINT NOT NULL DEFAULT 0,
+    total_reserved BIGINT NOT NULL DEFAULT 0
+);
+
+-- 一覧の取得日時の降順
+CREATE INDEX idx_stock_snapshots_taken_at ON stock_snapshots(taken_at DESC, id DESC);
+
+-- 商品・ロケーションは削除される可能性があるため外部キーを設定しない
+CREATE TABLE stock_snapshot_lines (
+    snapshot_id VARCHAR(255) NOT NULL REFERENCES stock_snapshots(id) ON DELETE CASCADE,
+    item_id VARCHAR(255) NOT NULL,
+    location_id VARCHAR(255) NOT NULL,
+    quantity BIGINT NOT NULL,
+    reserved BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (snapshot_id, item_id, location_id)
+);
+
+-- ロケーション別の照会用
+CREATE INDEX idx_stock_snapshot_lines_location ON stock_snapshot_lines(snapshot_id, location_id);
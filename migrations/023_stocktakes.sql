@@ -0,0 +1,34 @@
+-- 棚卸（実地棚卸の集計表・実数・差異）
+-- Cycle counts: count sheets, counted quantities and variances
+
+-- open（集計中）→ submitted（差異確定）→ applied（調整済み）、または cancelled（取消）
+CREATE TABLE stocktakes (
+    id VARCHAR(255) PRIMARY KEY,
+    location_id VARCHAR(255) NOT NULL REFERENCES locations(id) ON DELETE CASCADE,
+    abc_class VARCHAR(1) CHECK (abc_class IN ('A', 'B', 'C')),
+    reference VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'submitted', 'applied', 'cancelled')),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(255) NOT NULL,
+    submitted_at TIMESTAMP,
+    submitted_by VARCHAR(255),
+    closed_at TIMESTAMP,
+    closed_by VARCHAR(255)
+);
+
+-- ロケーション・状態での一覧の作成日時の降順
+CREATE INDEX idx_stocktakes_location ON stocktakes(location_id, created_at DESC, id DESC);
+CREATE INDEX idx_stocktakes_status ON stocktakes(status, created_at DESC, id DESC);
+
+-- 商品は集計中に削除される可能性があるため外部キーを設定しない
+CREATE TABLE stocktake_lines (
+    stocktake_id VARCHAR(255) NOT NULL REFERENCES stocktakes(id) ON DELETE CASCADE,
+    item_id VARCHAR(255) NOT NULL,
+    expected_qty BIGINT NOT NULL,
+    counted_qty BIGINT,
+    variance BIGINT NOT NULL DEFAULT 0,
+    counted_at TIMESTAMP,
+    counted_by VARCHAR(255),
+    approved BOOLEAN NOT NULL DEFAULT false,
+    PRIMARY KEY (stocktake_id, item_id)
+);
@@ -0,0 +1,27 @@
+-- 在庫調整（理由コード・調整額による承認）
+-- Stock adjustments with reason codes and value-based approval
+
+-- pending（承認待ち）→ applied（反映済み）または rejected（却下）。閾値以下の調整は applied で作成される
+CREATE TABLE adjustments (
+    id VARCHAR(255) PRIMARY KEY,
+    item_id VARCHAR(255) NOT NULL,
+    location_id VARCHAR(255) NOT NULL,
+    old_quantity BIGINT NOT NULL,
+    new_quantity BIGINT NOT NULL,
+    delta BIGINT NOT NULL,
+    value DECIMAL(15,2) NOT NULL DEFAULT 0,
+    reason_code VARCHAR(50) NOT NULL CHECK (reason_code IN ('damage', 'shrinkage', 'count_correction', 'expired', 'found', 'other')),
+    note TEXT NOT NULL DEFAULT '',
+    reference VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'applied', 'rejected')),
+    transaction_id VARCHAR(255),
+    requested_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    requested_by VARCHAR(255) NOT NULL,
+    decided_at TIMESTAMP,
+    decided_by VARCHAR(255),
+    decision_note TEXT NOT NULL DEFAULT ''
+);
+
+-- 状態・在庫記録での一覧の申請日時の降順
+CREATE INDEX idx_adjustments_status ON adjustments(status, requested_at DESC, id DESC);
+CREATE INDEX idx_adjustments_stock ON adjustments(item_id, location_id, requested_at DESC, id DESC);
@@ -0,0 +1,24 @@
+-- 入荷検品（隔離・品質検査）
+-- Receiving inspections holding received stock in quarantine until QC pass/fail
+
+-- quarantined（検品待ち）→ passed（合格、在庫に入庫）または failed（不合格、廃棄）。検品待ちの数量は stocks に含めない
+CREATE TABLE inspections (
+    id VARCHAR(255) PRIMARY KEY,
+    item_id VARCHAR(255) NOT NULL,
+    location_id VARCHAR(255) NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    reference VARCHAR(255) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'quarantined' CHECK (status IN ('quarantined', 'passed', 'failed')),
+    reason_code VARCHAR(50) CHECK (reason_code IN ('damage', 'shrinkage', 'count_correction', 'expired', 'found', 'other')),
+    transaction_id VARCHAR(255),
+    received_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    received_by VARCHAR(255) NOT NULL,
+    inspected_at TIMESTAMP,
+    inspected_by VARCHAR(255),
+    inspection_note TEXT NOT NULL DEFAULT ''
+);
+
+-- 状態・在庫記録での一覧の入荷日時の降順
+CREATE INDEX idx_inspections_status ON inspections(status, received_at DESC, id DESC);
+CREATE INDEX idx_inspections_stock ON inspections(item_id, location_id, received_at DESC, id DESC);
@@ -0,0 +1,8 @@
+-- ロケーションの引当の優先順位と位置
+-- Location priority and coordinates used by the multi-location allocation engine
+
+-- priority は小さいほど優先。latitude・longitude は近さ（proximity）による引当に使用し、両方を指定するか両方を省略する
+ALTER TABLE locations ADD COLUMN priority INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE locations ADD COLUMN latitude DOUBLE PRECISION CHECK (latitude BETWEEN -90 AND 90);
+ALTER TABLE locations ADD COLUMN longitude DOUBLE PRECISION CHECK (longitude BETWEEN -180 AND 180);
+ALTER TABLE locations ADD CONSTRAINT locations_coordinates_pair CHECK ((latitude IS NULL) = (longitude IS NULL));
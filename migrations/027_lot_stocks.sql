@@ -0,0 +1,17 @@
+-- ロットのロケーション別在庫
+-- Lot stock per location, kept in sync by add/remove/transfer when a lot number is supplied
+
+CREATE TABLE lot_stocks (
+    lot_id VARCHAR(255) NOT NULL REFERENCES lots(id) ON DELETE CASCADE,
+    item_id VARCHAR(255) NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    location_id VARCHAR(255) NOT NULL REFERENCES locations(id) ON DELETE CASCADE,
+    quantity BIGINT NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (lot_id, location_id)
+);
+
+CREATE INDEX idx_lot_stocks_item_id ON lot_stocks(item_id);
+CREATE INDEX idx_lot_stocks_location_id ON lot_stocks(location_id);
+
+-- ロット番号によるロットの検索
+CREATE INDEX idx_lots_item_id_number ON lots(item_id, number);
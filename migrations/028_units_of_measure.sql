@@ -0,0 +1,31 @@
+-- 単位と商品の単位の換算
+-- Units of measure, per-item base units and conversion factors
+
+-- decimals は数量の小数点以下の桁数。数量は 10^-decimals 単位の整数で保持する
+CREATE TABLE units_of_measure (
+    code VARCHAR(32) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    decimals INTEGER NOT NULL DEFAULT 0 CHECK (decimals BETWEEN 0 AND 6),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO units_of_measure (code, name, decimals) VALUES
+    ('each', '個', 0),
+    ('box', '箱', 0),
+    ('kg', 'キログラム', 3),
+    ('liter', 'リットル', 3);
+
+-- 既存の商品の基本単位は each（既存の在庫数量の意味は変わらない）
+ALTER TABLE items ADD COLUMN base_uom VARCHAR(32) NOT NULL DEFAULT 'each'
+    REFERENCES units_of_measure(code) ON DELETE RESTRICT;
+
+-- factor は換算元の単位1単位あたりの基本単位の数量
+CREATE TABLE item_unit_conversions (
+    item_id VARCHAR(255) NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    uom VARCHAR(32) NOT NULL REFERENCES units_of_measure(code) ON DELETE RESTRICT,
+    factor NUMERIC(18, 6) NOT NULL CHECK (factor > 0),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (item_id, uom)
+);
+
+CREATE INDEX idx_item_unit_conversions_uom ON item_unit_conversions(uom);
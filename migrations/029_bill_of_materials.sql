@@ -0,0 +1,36 @@
+-- 部品表（BOM）とキットの組立・分解
+-- Bills of materials and kit assembly / disassembly records
+
+-- キット1単位あたりの構成品の数量。キットを削除すると部品表も削除する
+CREATE TABLE bills_of_materials (
+    kit_item_id VARCHAR(255) PRIMARY KEY REFERENCES items(id) ON DELETE CASCADE,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- 構成品として使用中の商品は削除できない（ON DELETE RESTRICT）
+CREATE TABLE bom_components (
+    kit_item_id VARCHAR(255) NOT NULL REFERENCES bills_of_materials(kit_item_id) ON DELETE CASCADE,
+    component_item_id VARCHAR(255) NOT NULL REFERENCES items(id) ON DELETE RESTRICT,
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (kit_item_id, component_item_id),
+    CHECK (component_item_id <> kit_item_id)
+);
+
+CREATE INDEX idx_bom_components_component ON bom_components(component_item_id);
+
+-- 組立（assemble）・分解（disassemble）の記録。components は構成品ごとの合計数量（JSONB）
+-- 在庫の増減は metadata.assembly_id を持つ assembly のトランザクションに記録する
+CREATE TABLE assemblies (
+    id VARCHAR(255) PRIMARY KEY,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('assemble', 'disassemble')),
+    kit_item_id VARCHAR(255) NOT NULL,
+    location_id VARCHAR(255) NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    reference VARCHAR(255) NOT NULL DEFAULT '',
+    components JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(255) NOT NULL
+);
+
+CREATE INDEX idx_assemblies_kit ON assemblies(kit_item_id, created_at DESC, id DESC);
+CREATE INDEX idx_assemblies_location ON assemblies(location_id, created_at DESC, id DESC);
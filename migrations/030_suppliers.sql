@@ -0,0 +1,33 @@
+-- 仕入先と仕入先ごとの商品の仕入条件
+-- Suppliers and the purchasing terms of the items they supply
+
+CREATE TABLE suppliers (
+    id VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(500) NOT NULL,
+    contact_name VARCHAR(255) NOT NULL DEFAULT '',
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    phone VARCHAR(50) NOT NULL DEFAULT '',
+    address TEXT NOT NULL DEFAULT '',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- 仕入先・商品ごとの仕入先の品番・仕入単価・リードタイム・最小発注数量（MOQ）
+-- 仕入先または商品を削除すると仕入条件も削除する
+CREATE TABLE supplier_items (
+    supplier_id VARCHAR(255) NOT NULL REFERENCES suppliers(id) ON DELETE CASCADE,
+    item_id VARCHAR(255) NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    supplier_sku VARCHAR(255) NOT NULL DEFAULT '',
+    unit_cost DECIMAL(12,4) NOT NULL DEFAULT 0 CHECK (unit_cost >= 0),
+    lead_time_days INTEGER NOT NULL DEFAULT 0 CHECK (lead_time_days BETWEEN 0 AND 365),
+    min_order_quantity BIGINT NOT NULL DEFAULT 0 CHECK (min_order_quantity >= 0),
+    is_preferred BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (supplier_id, item_id)
+);
+
+CREATE INDEX idx_supplier_items_item ON supplier_items(item_id);
+
+-- 優先仕入先は商品ごとに1件
+CREATE UNIQUE INDEX idx_supplier_items_preferred ON supplier_items(item_id) WHERE is_preferred;
@@ -0,0 +1,51 @@
+-- 発注と発注に対する入荷
+-- Purchase orders and the goods received against them
+
+-- 発注のヘッダー
+-- 仕入先・ロケーションを削除しても発注の履歴を残すため外部キーは設定しない
+CREATE TABLE purchase_orders (
+    id VARCHAR(255) PRIMARY KEY,
+    supplier_id VARCHAR(255) NOT NULL,
+    location_id VARCHAR(255) NOT NULL,
+    reference VARCHAR(500) NOT NULL,
+    status VARCHAR(30) NOT NULL CHECK (status IN ('draft', 'approved', 'sent', 'partially_received', 'received', 'cancelled')),
+    expected_at TIMESTAMP WITH TIME ZONE,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(255) NOT NULL,
+    approved_at TIMESTAMP WITH TIME ZONE,
+    approved_by VARCHAR(255),
+    sent_at TIMESTAMP WITH TIME ZONE,
+    sent_by VARCHAR(255),
+    closed_at TIMESTAMP WITH TIME ZONE,
+    closed_by VARCHAR(255)
+);
+
+CREATE INDEX idx_purchase_orders_supplier ON purchase_orders(supplier_id, created_at DESC, id DESC);
+CREATE INDEX idx_purchase_orders_location ON purchase_orders(location_id, created_at DESC, id DESC);
+CREATE INDEX idx_purchase_orders_status ON purchase_orders(status, created_at DESC, id DESC);
+
+-- 発注の行（商品ごとの発注数量・入荷済み数量・発注単価）
+-- 入荷済み数量は発注数量を超えない
+CREATE TABLE purchase_order_lines (
+    purchase_order_id VARCHAR(255) NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    item_id VARCHAR(255) NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    received_quantity BIGINT NOT NULL DEFAULT 0 CHECK (received_quantity >= 0),
+    unit_cost DECIMAL(12,4) NOT NULL DEFAULT 0 CHECK (unit_cost >= 0),
+    PRIMARY KEY (purchase_order_id, item_id),
+    CHECK (received_quantity <= quantity)
+);
+
+-- 発注に対する入荷（行は入庫のトランザクションIDとともにJSONBで保存）
+CREATE TABLE goods_receipts (
+    id VARCHAR(255) PRIMARY KEY,
+    purchase_order_id VARCHAR(255) NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    location_id VARCHAR(255) NOT NULL,
+    reference VARCHAR(500) NOT NULL DEFAULT '',
+    lines JSONB NOT NULL,
+    received_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    received_by VARCHAR(255) NOT NULL
+);
+
+CREATE INDEX idx_goods_receipts_purchase_order ON goods_receipts(purchase_order_id, received_at, id);
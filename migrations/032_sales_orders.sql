@@ -0,0 +1,36 @@
+-- 受注と受注の行
+-- Sales orders and their lines
+
+-- 受注のヘッダー
+-- 引当は受注IDを参照番号とする予約（reservations）として記録する
+-- ロケーションを削除しても受注の履歴を残すため外部キーは設定しない
+CREATE TABLE sales_orders (
+    id VARCHAR(255) PRIMARY KEY,
+    reference VARCHAR(500) NOT NULL,
+    location_id VARCHAR(255) NOT NULL,
+    customer VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(30) NOT NULL CHECK (status IN ('open', 'allocated', 'shipped', 'cancelled')),
+    note TEXT NOT NULL DEFAULT '',
+    version BIGINT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(255) NOT NULL,
+    closed_at TIMESTAMP WITH TIME ZONE,
+    closed_by VARCHAR(255)
+);
+
+CREATE INDEX idx_sales_orders_location ON sales_orders(location_id, created_at DESC, id DESC);
+CREATE INDEX idx_sales_orders_reference ON sales_orders(reference);
+CREATE INDEX idx_sales_orders_status ON sales_orders(status, created_at DESC, id DESC);
+
+-- 受注の行（商品ごとの受注数量・引当数量・出荷数量・欠品数量）
+CREATE TABLE sales_order_lines (
+    sales_order_id VARCHAR(255) NOT NULL REFERENCES sales_orders(id) ON DELETE CASCADE,
+    item_id VARCHAR(255) NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    allocated_quantity BIGINT NOT NULL DEFAULT 0 CHECK (allocated_quantity >= 0),
+    shipped_quantity BIGINT NOT NULL DEFAULT 0 CHECK (shipped_quantity >= 0),
+    short_quantity BIGINT NOT NULL DEFAULT 0 CHECK (short_quantity >= 0),
+    PRIMARY KEY (sales_order_id, item_id),
+    CHECK (allocated_quantity <= quantity),
+    CHECK (shipped_quantity + short_quantity <= quantity)
+);
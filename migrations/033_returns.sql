@@ -0,0 +1,57 @@
+-- 返品（RMA）と返品の処分
+-- Returns (RMA) and the disposition of returned units
+
+-- 返品のヘッダー
+-- 受注・ロケーションを削除しても返品の履歴を残すため外部キーは設定しない
+CREATE TABLE returns (
+    id VARCHAR(255) PRIMARY KEY,
+    reference VARCHAR(500) NOT NULL,
+    sales_order_id VARCHAR(255),
+    customer VARCHAR(255) NOT NULL DEFAULT '',
+    location_id VARCHAR(255) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    status VARCHAR(30) NOT NULL CHECK (status IN ('authorized', 'received', 'completed', 'cancelled')),
+    note TEXT NOT NULL DEFAULT '',
+    version BIGINT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(255) NOT NULL,
+    closed_at TIMESTAMP WITH TIME ZONE,
+    closed_by VARCHAR(255)
+);
+
+CREATE INDEX idx_returns_sales_order ON returns(sales_order_id) WHERE sales_order_id IS NOT NULL;
+CREATE INDEX idx_returns_location ON returns(location_id, created_at DESC, id DESC);
+CREATE INDEX idx_returns_status ON returns(status, created_at DESC, id DESC);
+
+-- 返品の行（商品ごとの返品許可数量・到着数量・処分数量）
+-- 到着して処分していない数量（received - restocked - refurbished - scrapped）は検品待ちで在庫に含めない
+CREATE TABLE return_lines (
+    return_id VARCHAR(255) NOT NULL REFERENCES returns(id) ON DELETE CASCADE,
+    item_id VARCHAR(255) NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    received_quantity BIGINT NOT NULL DEFAULT 0 CHECK (received_quantity >= 0),
+    restocked_quantity BIGINT NOT NULL DEFAULT 0 CHECK (restocked_quantity >= 0),
+    refurbished_quantity BIGINT NOT NULL DEFAULT 0 CHECK (refurbished_quantity >= 0),
+    scrapped_quantity BIGINT NOT NULL DEFAULT 0 CHECK (scrapped_quantity >= 0),
+    PRIMARY KEY (return_id, item_id),
+    CHECK (received_quantity <= quantity),
+    CHECK (restocked_quantity + refurbished_quantity + scrapped_quantity <= received_quantity)
+);
+
+-- 返品の処分（restock・refurbish は入庫のトランザクションIDを記録する）
+CREATE TABLE return_dispositions (
+    id VARCHAR(255) PRIMARY KEY,
+    return_id VARCHAR(255) NOT NULL REFERENCES returns(id) ON DELETE CASCADE,
+    item_id VARCHAR(255) NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    disposition VARCHAR(30) NOT NULL CHECK (disposition IN ('restock', 'refurbish', 'scrap')),
+    target_item_id VARCHAR(255),
+    location_id VARCHAR(255),
+    reason_code VARCHAR(30),
+    note TEXT NOT NULL DEFAULT '',
+    transaction_id VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(255) NOT NULL
+);
+
+CREATE INDEX idx_return_dispositions_return ON return_dispositions(return_id, created_at, id);
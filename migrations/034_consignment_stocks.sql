@@ -0,0 +1,16 @@
+-- 仕入先所有の委託在庫
+-- Supplier-owned consignment stock per location, kept in sync by add/remove/transfer when an owner is supplied
+
+-- 在庫（stocks）の数量は自社所有と委託在庫の合計で、委託在庫を除いた数量が自社所有の在庫
+-- 仕入先を削除しても保管中の委託在庫の記録は残すため、owner_id には外部キーを設定しない
+CREATE TABLE consignment_stocks (
+    owner_id VARCHAR(255) NOT NULL,
+    item_id VARCHAR(255) NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    location_id VARCHAR(255) NOT NULL REFERENCES locations(id) ON DELETE CASCADE,
+    quantity BIGINT NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (owner_id, item_id, location_id)
+);
+
+CREATE INDEX idx_consignment_stocks_item_location ON consignment_stocks(item_id, location_id);
+CREATE INDEX idx_consignment_stocks_location_id ON consignment_stocks(location_id);
@@ -0,0 +1,22 @@
+-- 販売チャネルごとの在庫の確保と予約・受注の販売チャネル
+-- Stock earmarked per sales channel, and the sales channel of reservations and sales orders
+
+-- 商品・ロケーションの在庫を販売チャネル（web・retail・wholesale など）向けに確保する数量
+-- 確保数量のうちチャネルの有効な予約で使用していない数量は、他のチャネルとチャネルを指定しない予約では使用できない
+CREATE TABLE channel_allocations (
+    item_id VARCHAR(255) NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    location_id VARCHAR(255) NOT NULL REFERENCES locations(id) ON DELETE CASCADE,
+    channel VARCHAR(64) NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_by VARCHAR(255) NOT NULL DEFAULT '',
+    PRIMARY KEY (item_id, location_id, channel)
+);
+
+CREATE INDEX idx_channel_allocations_location_id ON channel_allocations(location_id);
+
+-- 予約・受注の販売チャネル（空の場合は共有の在庫のみから予約）
+ALTER TABLE reservations ADD COLUMN channel VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE sales_orders ADD COLUMN channel VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_reservations_channel ON reservations(channel) WHERE channel <> '';
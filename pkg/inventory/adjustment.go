@@ -0,0 +1,322 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var _ AdjustmentManager = (*Manager)(nil)
+
+// RequestAdjustment adjusts a stock record to a new quantity with a reason code
+// 理由コードを指定して在庫を指定数量に調整
+//
+// ItemID・LocationID・NewQuantity・Reason・Note（Reason が other の場合は必須）・Reference（任意）を指定します。
+// 調整額（調整数量の絶対値 × 商品の単価）が Config.AdjustmentApprovalThreshold を超える場合は
+// 在庫を変更せずに承認待ち（pending）として記録し、それ以外は調整（adjust）のトランザクションに
+// 理由コードと在庫調整IDをメタデータとして記録して即時に反映（applied）します。
+// ID・旧数量・調整数量・調整額・状態・申請日時・申請者は設定されます。
+// WithExpectedVersion のコンテキストでは、承認待ちとする場合も在庫が想定したバージョンでなければ ErrPreconditionFailed を返します。
+func (m *Manager) RequestAdjustment(ctx context.Context, adjustment *Adjustment) (err error) {
+	ctx, finish := m.startOperation(ctx, "request_adjustment", attrItemID.String(adjustment.ItemID), attrLocationID.String(adjustment.LocationID), attrQuantity.Int64(adjustment.NewQuantity), attrReference.String(adjustment.Reference))
+	defer finish(&err)
+
+	if err := ValidateAdjustment(adjustment, m.config.AllowNegativeStock); err != nil {
+		return err
+	}
+	item, _, err := m.validateItemAndLocation(ctx, adjustment.ItemID, adjustment.LocationID)
+	if err != nil {
+		return err
+	}
+
+	adjustment.ID = NewAdjustmentID()
+	adjustment.RequestedAt = time.Now()
+	adjustment.RequestedBy = m.getUserFromContext(ctx)
+	adjustment.TransactionID = ""
+	adjustment.DecidedAt, adjustment.DecidedBy, adjustment.DecisionNote = nil, "", ""
+	if adjustment.Reference == "" {
+		adjustment.Reference = adjustment.ID
+	}
+
+	var (
+		events *bufferedPublisher
+		stock  *Stock
+	)
+	err = m.retryOnConflict(ctx, func() error {
+		events = &bufferedPublisher{}
+		stock = nil
+		return m.storage.WithinTx(ctx, func(txStorage Storage) error {
+			txManager := m.withStorage(txStorage, events)
+
+			var current int64
+			existing, err := txManager.getStockForWrite(ctx, adjustment.ItemID, adjustment.LocationID)
+			switch {
+			case err == nil:
+				current = existing.Quantity
+			case !errors.Is(err, ErrStockNotFound):
+				return NewStorageError("get_stock", "在庫取得に失敗しました", err)
+			}
+			// 承認待ちとして記録する場合も、在庫が想定したバージョン（If-Match）であることを確認する
+			if expected, ok := expectedVersion(ctx); ok && (existing == nil || existing.Version != expected) {
+				return ErrPreconditionFailed
+			}
+			adjustment.OldQuantity = current
+			adjustment.Delta = adjustment.NewQuantity - current
+			adjustment.Value = adjustmentValue(adjustment.Delta, item.UnitCost)
+
+			if m.requiresApproval(adjustment) {
+				adjustment.Status = AdjustmentStatusPending
+				adjustment.TransactionID = ""
+				if err := txStorage.CreateAdjustment(ctx, adjustment); err != nil {
+					return NewStorageError("create_adjustment", "在庫調整の記録に失敗しました", err)
+				}
+				txManager.publishEvent(ctx, EventTypeAdjustmentPending, adjustment.ItemID, adjustment.LocationID, *adjustment)
+				return nil
+			}
+
+			var tx *Transaction
+			stock, tx, err = txManager.applyStockDelta(ctx, item, adjustment.LocationID, adjustment.Delta, TransactionTypeAdjust, "adjust", adjustment.Reference, adjustmentMetadata(adjustment))
+			if err != nil {
+				return err
+			}
+			adjustment.Status = AdjustmentStatusApplied
+			adjustment.TransactionID = tx.ID
+			if err := txStorage.CreateAdjustment(ctx, adjustment); err != nil {
+				return NewStorageError("create_adjustment", "在庫調整の記録に失敗しました", err)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	if m.publisher != nil {
+		events.flush(ctx, m.publisher, m.logger)
+	}
+	if stock != nil {
+		m.evaluateAlertRules(ctx, stock)
+	}
+
+	m.log(ctx).Info("在庫調整の申請完了",
+		zap.String("adjustment_id", adjustment.ID),
+		zap.String("item_id", adjustment.ItemID),
+		zap.String("location_id", adjustment.LocationID),
+		zap.Int64("delta", adjustment.Delta),
+		zap.Float64("value", adjustment.Value),
+		zap.String("reason_code", string(adjustment.Reason)),
+		zap.String("status", string(adjustment.Status)),
+	)
+	return nil
+}
+
+// GetAdjustment retrieves a stock adjustment
+// 在庫調整を取得
+func (m *Manager) GetAdjustment(ctx context.Context, adjustmentID string) (*Adjustment, error) {
+	if adjustmentID == "" {
+		return nil, NewValidationError("adjustment_id", "在庫調整IDが指定されていません", "")
+	}
+
+	adjustment, err := m.storage.GetAdjustment(ctx, adjustmentID)
+	if err != nil {
+		if errors.Is(err, ErrAdjustmentNotFound) {
+			return nil, ErrAdjustmentNotFound
+		}
+		return nil, NewStorageError("get_adjustment", "在庫調整の取得に失敗しました", err)
+	}
+	return adjustment, nil
+}
+
+// ListAdjustments lists stock adjustments matching a filter, newest first
+// 条件に一致する在庫調整を申請日時の新しい順に取得
+func (m *Manager) ListAdjustments(ctx context.Context, filter AdjustmentFilter) ([]Adjustment, error) {
+	if err := validateOffsetLimit(filter.Offset, filter.Limit); err != nil {
+		return nil, err
+	}
+	switch filter.Status {
+	case "", AdjustmentStatusPending, AdjustmentStatusApplied, AdjustmentStatusRejected:
+	default:
+		return nil, NewValidationError("status", "在庫調整の状態が正しくありません", string(filter.Status))
+	}
+	if filter.Reason != "" {
+		if err := ValidateAdjustmentReason(filter.Reason); err != nil {
+			return nil, err
+		}
+	}
+
+	adjustments, err := m.storage.ListAdjustments(ctx, filter)
+	if err != nil {
+		return nil, NewStorageError("list_adjustments", "在庫調整一覧の取得に失敗しました", err)
+	}
+	return adjustments, nil
+}
+
+// ApproveAdjustment applies a pending stock adjustment
+// 承認待ちの在庫調整を承認して在庫に反映
+//
+// 申請後に在庫が変動している場合も申請時の調整数量（Delta）を現在の在庫数量に加算し、
+// 調整後の在庫数量（NewQuantity）を反映時の数量に更新します。
+func (m *Manager) ApproveAdjustment(ctx context.Context, adjustmentID, note string) (_ *Adjustment, err error) {
+	ctx, finish := m.startOperation(ctx, "approve_adjustment", attrAdjustmentID.String(adjustmentID))
+	defer finish(&err)
+
+	if err := validateAdjustmentDecision(adjustmentID, note); err != nil {
+		return nil, err
+	}
+	adjustment, err := m.GetAdjustment(ctx, adjustmentID)
+	if err != nil {
+		return nil, err
+	}
+	if adjustment.Status != AdjustmentStatusPending {
+		return nil, ErrAdjustmentNotPending
+	}
+	item, err := m.storage.GetItem(ctx, adjustment.ItemID)
+	if err != nil {
+		if errors.Is(err, ErrItemNotFound) {
+			return nil, ErrItemNotFound
+		}
+		return nil, NewStorageError("get_item", "商品取得に失敗しました", err)
+	}
+
+	var (
+		events *bufferedPublisher
+		stock  *Stock
+	)
+	err = m.retryOnConflict(ctx, func() error {
+		events = &bufferedPublisher{}
+		return m.storage.WithinTx(ctx, func(txStorage Storage) error {
+			txManager := m.withStorage(txStorage, events)
+
+			var (
+				tx  *Transaction
+				err error
+			)
+			stock, tx, err = txManager.applyStockDelta(ctx, item, adjustment.LocationID, adjustment.Delta, TransactionTypeAdjust, "adjust", adjustment.Reference, adjustmentMetadata(adjustment))
+			if err != nil {
+				return err
+			}
+
+			now := time.Now()
+			update := *adjustment
+			update.Status = AdjustmentStatusApplied
+			update.NewQuantity = stock.Quantity
+			update.TransactionID = tx.ID
+			update.DecidedAt = &now
+			update.DecidedBy = m.getUserFromContext(ctx)
+			update.DecisionNote = note
+			return txManager.updateAdjustment(ctx, &update, AdjustmentStatusPending)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if m.publisher != nil {
+		events.flush(ctx, m.publisher, m.logger)
+	}
+	m.evaluateAlertRules(ctx, stock)
+
+	approved, err := m.GetAdjustment(ctx, adjustment.ID)
+	if err != nil {
+		return nil, err
+	}
+	m.log(ctx).Info("在庫調整の承認完了",
+		zap.String("adjustment_id", approved.ID),
+		zap.String("item_id", approved.ItemID),
+		zap.String("location_id", approved.LocationID),
+		zap.Int64("delta", approved.Delta),
+		zap.String("approved_by", approved.DecidedBy),
+	)
+	m.publishEvent(ctx, EventTypeAdjustmentApproved, approved.ItemID, approved.LocationID, *approved)
+	return approved, nil
+}
+
+// RejectAdjustment rejects a pending stock adjustment without changing the stock
+// 承認待ちの在庫調整を在庫を変更せずに却下
+func (m *Manager) RejectAdjustment(ctx context.Context, adjustmentID, note string) (_ *Adjustment, err error) {
+	ctx, finish := m.startOperation(ctx, "reject_adjustment", attrAdjustmentID.String(adjustmentID))
+	defer finish(&err)
+
+	if err := validateAdjustmentDecision(adjustmentID, note); err != nil {
+		return nil, err
+	}
+	adjustment, err := m.GetAdjustment(ctx, adjustmentID)
+	if err != nil {
+		return nil, err
+	}
+	if adjustment.Status != AdjustmentStatusPending {
+		return nil, ErrAdjustmentNotPending
+	}
+
+	now := time.Now()
+	update := *adjustment
+	update.Status = AdjustmentStatusRejected
+	update.DecidedAt = &now
+	update.DecidedBy = m.getUserFromContext(ctx)
+	update.DecisionNote = note
+	if err := m.updateAdjustment(ctx, &update, AdjustmentStatusPending); err != nil {
+		return nil, err
+	}
+
+	rejected, err := m.GetAdjustment(ctx, adjustment.ID)
+	if err != nil {
+		return nil, err
+	}
+	m.log(ctx).Info("在庫調整の却下完了",
+		zap.String("adjustment_id", rejected.ID),
+		zap.String("rejected_by", rejected.DecidedBy),
+	)
+	m.publishEvent(ctx, EventTypeAdjustmentRejected, rejected.ItemID, rejected.LocationID, *rejected)
+	return rejected, nil
+}
+
+// requiresApproval reports whether the value of an adjustment exceeds the approval threshold
+// 在庫調整の調整額が承認の閾値を超えるかどうかを判定（閾値が0の場合は承認不要）
+func (m *Manager) requiresApproval(adjustment *Adjustment) bool {
+	threshold := m.config.AdjustmentApprovalThreshold
+	return threshold > 0 && adjustment.Value > threshold
+}
+
+// updateAdjustment updates a stock adjustment expected to be in status from
+// 状態がfromの在庫調整を更新
+func (m *Manager) updateAdjustment(ctx context.Context, adjustment *Adjustment, from AdjustmentStatus) error {
+	if err := m.storage.UpdateAdjustment(ctx, adjustment, from); err != nil {
+		if errors.Is(err, ErrAdjustmentNotFound) || errors.Is(err, ErrAdjustmentNotPending) {
+			return err
+		}
+		return NewStorageError("update_adjustment", "在庫調整の更新に失敗しました", err)
+	}
+	return nil
+}
+
+// validateAdjustmentDecision validates the ID and note of an approval or rejection
+// 承認・却下する在庫調整のIDとメモをバリデーション
+func validateAdjustmentDecision(adjustmentID, note string) error {
+	if adjustmentID == "" {
+		return NewValidationError("adjustment_id", "在庫調整IDが指定されていません", "")
+	}
+	if len(note) > 2000 {
+		return NewValidationError("note", "メモが長すぎます", note)
+	}
+	return nil
+}
+
+// adjustmentValue returns the absolute value of a quantity change at a unit cost
+// 調整数量の絶対値 × 単価の調整額を返す
+func adjustmentValue(delta int64, unitCost float64) float64 {
+	if delta < 0 {
+		delta = -delta
+	}
+	return float64(delta) * unitCost
+}
+
+// adjustmentMetadata returns the transaction metadata recorded for an adjustment
+// 在庫調整のトランザクションに記録するメタデータを返す
+func adjustmentMetadata(adjustment *Adjustment) map[string]string {
+	return map[string]string{
+		MetadataReasonCode:   string(adjustment.Reason),
+		MetadataAdjustmentID: adjustment.ID,
+	}
+}
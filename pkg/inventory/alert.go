@@ -0,0 +1,211 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var _ AlertWorkflowManager = (*Manager)(nil)
+
+// alertSweepPageSize is the number of alerts read at a time by the periodic alert jobs
+// 定期ジョブで一度に読み込むアラート数
+const alertSweepPageSize = 500
+
+// alertTimeoutNote is the resolve note of alerts resolved by ResolveTimedOutAlerts
+// ResolveTimedOutAlerts で解決したアラートの解決メモ
+const alertTimeoutNote = "タイムアウトにより自動的に解決しました"
+
+// GetAlert retrieves an alert by ID, including resolved alerts
+// IDでアラートを取得（解決済みを含む）
+func (m *Manager) GetAlert(ctx context.Context, alertID string) (*StockAlert, error) {
+	if alertID == "" {
+		return nil, NewValidationError("alert_id", "アラートIDが指定されていません", "")
+	}
+
+	alert, err := m.storage.GetAlert(ctx, alertID)
+	if err != nil {
+		if errors.Is(err, ErrAlertNotFound) {
+			return nil, ErrAlertNotFound
+		}
+		return nil, NewStorageError("get_alert", "アラート取得に失敗しました", err)
+	}
+	return alert, nil
+}
+
+// ListAlerts lists alerts of all locations matching a filter, newest first
+// 条件に一致するアラートを全ロケーションから作成日時の新しい順に取得（解決済みを含む）
+func (m *Manager) ListAlerts(ctx context.Context, filter AlertFilter) ([]StockAlert, error) {
+	if err := ValidateAlertFilter(filter); err != nil {
+		return nil, err
+	}
+
+	alerts, err := m.storage.ListAlerts(ctx, filter)
+	if err != nil {
+		return nil, NewStorageError("list_alerts", "アラート一覧の取得に失敗しました", err)
+	}
+	return alerts, nil
+}
+
+// AcknowledgeAlert records that an operator has seen an active alert and is handling it
+// アクティブなアラートを確認済みにし、確認したユーザー・日時・メモを記録
+//
+// 確認はアラートを解決しません。在庫が回復するか対応が完了した時点で ResolveAlertWithNote で解決してください。
+// 解決済みのアラートは ErrAlertNotActive、確認済みのアラートは ErrAlertAlreadyAcknowledged を返します。
+func (m *Manager) AcknowledgeAlert(ctx context.Context, alertID, note string) (_ *StockAlert, err error) {
+	ctx, finish := m.startOperation(ctx, "acknowledge_alert")
+	defer finish(&err)
+
+	if alertID == "" {
+		return nil, NewValidationError("alert_id", "アラートIDが指定されていません", "")
+	}
+	if err := ValidateAlertNote(note); err != nil {
+		return nil, err
+	}
+
+	userID := m.getUserFromContext(ctx)
+	if err := m.storage.AcknowledgeAlert(ctx, alertID, userID, note); err != nil {
+		if errors.Is(err, ErrAlertNotFound) || errors.Is(err, ErrAlertNotActive) || errors.Is(err, ErrAlertAlreadyAcknowledged) {
+			return nil, err
+		}
+		return nil, NewStorageError("acknowledge_alert", "アラートの確認に失敗しました", err)
+	}
+	alert, err := m.GetAlert(ctx, alertID)
+	if err != nil {
+		return nil, err
+	}
+
+	m.log(ctx).Info("アラート確認完了",
+		zap.String("alert_id", alertID),
+		zap.String("severity", string(alert.Severity)),
+		zap.String("acknowledged_by", userID),
+	)
+	m.publishEvent(ctx, EventTypeAlertAcknowledged, alert.ItemID, alert.LocationID, AlertAcknowledgedEvent{
+		AlertID:        alert.ID,
+		ItemID:         alert.ItemID,
+		LocationID:     alert.LocationID,
+		Severity:       alert.Severity,
+		AcknowledgedAt: alert.AcknowledgedAt,
+		AcknowledgedBy: alert.AcknowledgedBy,
+		Note:           note,
+	})
+	return alert, nil
+}
+
+// ResolveAlert resolves an alert
+// アラートを解決
+func (m *Manager) ResolveAlert(ctx context.Context, alertID string) error {
+	_, err := m.ResolveAlertWithNote(ctx, alertID, "")
+	return err
+}
+
+// ResolveAlertWithNote resolves an active alert, recording who resolved it and a note
+// アクティブなアラートを解決し、解決したユーザー・メモを記録
+//
+// 確認していないアラートも解決できます。解決済みのアラートは ErrAlertNotActive を返します。
+func (m *Manager) ResolveAlertWithNote(ctx context.Context, alertID, note string) (_ *StockAlert, err error) {
+	ctx, finish := m.startOperation(ctx, "resolve_alert")
+	defer finish(&err)
+
+	if alertID == "" {
+		return nil, NewValidationError("alert_id", "アラートIDが指定されていません", "")
+	}
+	if err := ValidateAlertNote(note); err != nil {
+		return nil, err
+	}
+
+	userID := m.getUserFromContext(ctx)
+	if err := m.storage.ResolveAlert(ctx, alertID, userID, note); err != nil {
+		if errors.Is(err, ErrAlertNotFound) || errors.Is(err, ErrAlertNotActive) {
+			return nil, err
+		}
+		return nil, NewStorageError("resolve_alert", "アラート解決に失敗しました", err)
+	}
+	alert, err := m.GetAlert(ctx, alertID)
+	if err != nil {
+		return nil, err
+	}
+
+	m.log(ctx).Info("アラート解決完了",
+		zap.String("alert_id", alertID),
+		zap.String("severity", string(alert.Severity)),
+		zap.String("resolved_by", userID),
+	)
+	// ロケーションごとに購読できるよう、解決したアラートの商品・ロケーションを含める
+	m.publishEvent(ctx, EventTypeAlertResolved, alert.ItemID, alert.LocationID, AlertResolvedEvent{
+		AlertID:    alert.ID,
+		ItemID:     alert.ItemID,
+		LocationID: alert.LocationID,
+		Severity:   alert.Severity,
+		ResolvedAt: alert.ResolvedAt,
+		ResolvedBy: alert.ResolvedBy,
+		Note:       note,
+	})
+	return alert, nil
+}
+
+// ResolveTimedOutAlerts resolves active alerts created more than Config.AlertTimeout ago
+// 作成から Config.AlertTimeout を過ぎたアクティブなアラートを解決し、解決したアラート数を返す
+//
+// 放置されたアラートが残り続けないよう定期的に実行します。低在庫が続いている在庫には
+// SweepLowStock が新しいアラートを作成します。AlertTimeout が0以下の場合は何もしません。
+func (m *Manager) ResolveTimedOutAlerts(ctx context.Context) (resolved int, err error) {
+	ctx, finish := m.startOperation(ctx, "resolve_timed_out_alerts")
+	defer finish(&err)
+
+	if m.config.AlertTimeout <= 0 {
+		return 0, nil
+	}
+	active := true
+	filter := AlertFilter{Active: &active, CreatedBefore: time.Now().Add(-m.config.AlertTimeout), Limit: alertSweepPageSize}
+
+	// 解決したアラートは条件に一致しなくなるため、常に先頭から取得する
+	for {
+		alerts, err := m.storage.ListAlerts(ctx, filter)
+		if err != nil {
+			return resolved, NewStorageError("list_alerts", "アラート一覧の取得に失敗しました", err)
+		}
+		for _, alert := range alerts {
+			if _, err := m.ResolveAlertWithNote(ctx, alert.ID, alertTimeoutNote); err != nil {
+				// 取得後に他の操作で解決された場合
+				if errors.Is(err, ErrAlertNotActive) {
+					continue
+				}
+				return resolved, err
+			}
+			resolved++
+		}
+		if len(alerts) < alertSweepPageSize {
+			break
+		}
+	}
+
+	if resolved > 0 {
+		m.log(ctx).Info("タイムアウトしたアラートの解決完了",
+			zap.Duration("timeout", m.config.AlertTimeout),
+			zap.Int("alerts", resolved),
+		)
+	}
+	return resolved, nil
+}
+
+// activeAlertKeys returns the stock records that have an active alert of a type
+// 指定したタイプのアクティブなアラートがある在庫記録を返す
+func (m *Manager) activeAlertKeys(ctx context.Context, alertType AlertType) (map[StockKey]bool, error) {
+	active := true
+	keys := make(map[StockKey]bool)
+	for offset := 0; ; offset += alertSweepPageSize {
+		alerts, err := m.storage.ListAlerts(ctx, AlertFilter{Type: alertType, Active: &active, Offset: offset, Limit: alertSweepPageSize})
+		if err != nil {
+			return nil, NewStorageError("list_alerts", "アラート一覧の取得に失敗しました", err)
+		}
+		for _, alert := range alerts {
+			keys[StockKey{ItemID: alert.ItemID, LocationID: alert.LocationID}] = true
+		}
+		if len(alerts) < alertSweepPageSize {
+			return keys, nil
+		}
+	}
+}
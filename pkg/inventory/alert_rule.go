@@ -0,0 +1,358 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var _ AlertRuleManager = (*Manager)(nil)
+
+// alertRuleLocationPageSize is the number of locations read at a time when evaluating rules of all locations
+// 全ロケーションを対象とするルールの評価時に一度に読み取るロケーションの件数
+const alertRuleLocationPageSize = 100
+
+// CreateAlertRule creates an alert rule
+// アラートルールを作成
+//
+// 作成したルールは以降の在庫の変更と定期的な評価（EvaluateAlertRules）で評価します。
+func (m *Manager) CreateAlertRule(ctx context.Context, rule *AlertRule) (err error) {
+	ctx, finish := m.startOperation(ctx, "create_alert_rule")
+	defer finish(&err)
+
+	if err := m.validateAlertRule(ctx, rule); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	rule.ID = NewAlertRuleID()
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+	rule.UpdatedBy = m.getUserFromContext(ctx)
+	if err := m.storage.CreateAlertRule(ctx, rule); err != nil {
+		return NewStorageError("create_alert_rule", "アラートルールの作成に失敗しました", err)
+	}
+
+	m.log(ctx).Info("アラートルール作成完了",
+		zap.String("rule_id", rule.ID),
+		zap.String("name", rule.Name),
+		zap.String("metric", string(rule.Metric)),
+		zap.String("comparator", string(rule.Comparator)),
+		zap.Int64("threshold", rule.Threshold),
+	)
+	return nil
+}
+
+// GetAlertRule retrieves an alert rule by ID
+// IDでアラートルールを取得
+func (m *Manager) GetAlertRule(ctx context.Context, ruleID string) (*AlertRule, error) {
+	if ruleID == "" {
+		return nil, NewValidationError("rule_id", "アラートルールIDが指定されていません", "")
+	}
+
+	rule, err := m.storage.GetAlertRule(ctx, ruleID)
+	if err != nil {
+		if errors.Is(err, ErrAlertRuleNotFound) {
+			return nil, ErrAlertRuleNotFound
+		}
+		return nil, NewStorageError("get_alert_rule", "アラートルールの取得に失敗しました", err)
+	}
+	return rule, nil
+}
+
+// UpdateAlertRule replaces the condition, scope and state of an alert rule
+// アラートルールの条件・対象範囲・有効状態を更新
+//
+// 作成済みのアラートは変更しません。クールダウンは更新後の値で判定します。
+func (m *Manager) UpdateAlertRule(ctx context.Context, rule *AlertRule) (err error) {
+	ctx, finish := m.startOperation(ctx, "update_alert_rule")
+	defer finish(&err)
+
+	if err := m.validateAlertRule(ctx, rule); err != nil {
+		return err
+	}
+	existing, err := m.GetAlertRule(ctx, rule.ID)
+	if err != nil {
+		return err
+	}
+
+	rule.CreatedAt = existing.CreatedAt
+	rule.UpdatedAt = time.Now()
+	rule.UpdatedBy = m.getUserFromContext(ctx)
+	if err := m.storage.UpdateAlertRule(ctx, rule); err != nil {
+		if errors.Is(err, ErrAlertRuleNotFound) {
+			return ErrAlertRuleNotFound
+		}
+		return NewStorageError("update_alert_rule", "アラートルールの更新に失敗しました", err)
+	}
+
+	m.log(ctx).Info("アラートルール更新完了",
+		zap.String("rule_id", rule.ID),
+		zap.String("name", rule.Name),
+		zap.Bool("enabled", rule.Enabled),
+	)
+	return nil
+}
+
+// DeleteAlertRule deletes an alert rule, keeping the alerts it raised
+// アラートルールを削除（作成済みのアラートは残す）
+func (m *Manager) DeleteAlertRule(ctx context.Context, ruleID string) (err error) {
+	ctx, finish := m.startOperation(ctx, "delete_alert_rule")
+	defer finish(&err)
+
+	if ruleID == "" {
+		return NewValidationError("rule_id", "アラートルールIDが指定されていません", "")
+	}
+	if err := m.storage.DeleteAlertRule(ctx, ruleID); err != nil {
+		if errors.Is(err, ErrAlertRuleNotFound) {
+			return ErrAlertRuleNotFound
+		}
+		return NewStorageError("delete_alert_rule", "アラートルールの削除に失敗しました", err)
+	}
+
+	m.log(ctx).Info("アラートルール削除完了", zap.String("rule_id", ruleID))
+	return nil
+}
+
+// ListAlertRules lists alert rules matching a filter, oldest first
+// 条件に一致するアラートルールを作成日時の昇順で取得
+func (m *Manager) ListAlertRules(ctx context.Context, filter AlertRuleFilter) ([]AlertRule, error) {
+	if err := validateOffsetLimit(filter.Offset, filter.Limit); err != nil {
+		return nil, err
+	}
+
+	rules, err := m.storage.ListAlertRules(ctx, filter)
+	if err != nil {
+		return nil, NewStorageError("list_alert_rules", "アラートルール一覧の取得に失敗しました", err)
+	}
+	return rules, nil
+}
+
+// EvaluateAlertRules evaluates every enabled alert rule against the stock records in its scope
+// 有効な全てのアラートルールを対象範囲の在庫記録に対して評価し、作成したアラート数を返す
+//
+// 在庫の変更を伴わない条件（長期間入荷のない在庫など）やルールの作成前からの在庫を検出するため、
+// 定期的に実行します（RunAlertRuleEvaluation）。アクティブなアラートやクールダウン中の在庫記録には
+// アラートを作成しません。
+func (m *Manager) EvaluateAlertRules(ctx context.Context) (_ int, err error) {
+	ctx, finish := m.startOperation(ctx, "evaluate_alert_rules")
+	defer finish(&err)
+
+	rules, err := m.storage.ListAlertRules(ctx, AlertRuleFilter{EnabledOnly: true})
+	if err != nil {
+		return 0, NewStorageError("list_alert_rules", "アラートルール一覧の取得に失敗しました", err)
+	}
+	if len(rules) == 0 {
+		return 0, nil
+	}
+	locationIDs, err := m.alertRuleLocations(ctx, rules)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, locationID := range locationIDs {
+		if err := ctx.Err(); err != nil {
+			return created, err
+		}
+
+		// 走査中の行を保持したまま書き込まないよう、条件を満たす在庫記録を集めてからアラートを作成する
+		type match struct {
+			rule  *AlertRule
+			stock Stock
+		}
+		var matches []match
+		err := m.storage.ForEachStockByLocation(ctx, locationID, func(stock Stock) error {
+			for i := range rules {
+				if rules[i].Covers(stock) && rules[i].Triggered(stock) {
+					matches = append(matches, match{rule: &rules[i], stock: stock})
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return created, NewStorageError("get_stock_by_location", "ロケーション在庫取得に失敗しました", err)
+		}
+
+		for _, match := range matches {
+			if m.raiseRuleAlert(ctx, match.rule, match.stock) {
+				created++
+			}
+		}
+	}
+
+	if created > 0 {
+		m.log(ctx).Info("アラートルールの評価完了", zap.Int("rules", len(rules)), zap.Int("alerts", created))
+	}
+	return created, nil
+}
+
+// RunAlertRuleEvaluation runs EvaluateAlertRules every interval until ctx is cancelled
+// ctxがキャンセルされるまで interval ごとに EvaluateAlertRules を実行
+func (m *Manager) RunAlertRuleEvaluation(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := m.EvaluateAlertRules(ctx); err != nil && ctx.Err() == nil {
+			m.log(ctx).Warn("アラートルールの評価に失敗しました", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// validateAlertRule validates a rule and checks that the item and location of its scope exist
+// ルールを検証し、対象範囲の商品・ロケーションの存在を確認
+func (m *Manager) validateAlertRule(ctx context.Context, rule *AlertRule) error {
+	if err := ValidateAlertRule(rule); err != nil {
+		return err
+	}
+	if rule.ItemID != "" {
+		if _, err := m.storage.GetItem(ctx, rule.ItemID); err != nil {
+			if errors.Is(err, ErrItemNotFound) {
+				return ErrItemNotFound
+			}
+			return NewStorageError("get_item", "商品取得に失敗しました", err)
+		}
+	}
+	if rule.LocationID != "" {
+		if _, err := m.storage.GetLocation(ctx, rule.LocationID); err != nil {
+			if errors.Is(err, ErrLocationNotFound) {
+				return ErrLocationNotFound
+			}
+			return NewStorageError("get_location", "ロケーション取得に失敗しました", err)
+		}
+	}
+	return nil
+}
+
+// alertRuleLocations returns the locations whose stock records the rules cover
+// ルールの対象範囲に含まれる在庫記録のロケーションを返す
+//
+// ロケーションを指定していないルールがある場合は全てのロケーションを返します。
+func (m *Manager) alertRuleLocations(ctx context.Context, rules []AlertRule) ([]string, error) {
+	scoped := make(map[string]bool)
+	for _, rule := range rules {
+		if rule.LocationID == "" {
+			return m.allLocationIDs(ctx)
+		}
+		scoped[rule.LocationID] = true
+	}
+
+	locationIDs := make([]string, 0, len(scoped))
+	for locationID := range scoped {
+		locationIDs = append(locationIDs, locationID)
+	}
+	sort.Strings(locationIDs)
+	return locationIDs, nil
+}
+
+// allLocationIDs returns the IDs of every location
+// 全てのロケーションのIDを返す
+func (m *Manager) allLocationIDs(ctx context.Context) ([]string, error) {
+	var locationIDs []string
+	for offset := 0; ; offset += alertRuleLocationPageSize {
+		locations, err := m.storage.ListLocations(ctx, offset, alertRuleLocationPageSize)
+		if err != nil {
+			return nil, NewStorageError("list_locations", "ロケーション一覧の取得に失敗しました", err)
+		}
+		for _, location := range locations {
+			locationIDs = append(locationIDs, location.ID)
+		}
+		if len(locations) < alertRuleLocationPageSize {
+			return locationIDs, nil
+		}
+	}
+}
+
+// evaluateAlertRules evaluates the enabled alert rules covering a stock record after it changed
+// 在庫記録の変更後に、その在庫記録を対象範囲に含む有効なアラートルールを評価
+//
+// 在庫操作は完了しているため、評価の失敗はログに記録するのみです。
+func (m *Manager) evaluateAlertRules(ctx context.Context, stock *Stock) {
+	rules, err := m.storage.GetAlertRulesForStock(ctx, stock.ItemID, stock.LocationID)
+	if err != nil {
+		m.log(ctx).Warn("アラートルールの取得に失敗しました",
+			zap.String("item_id", stock.ItemID),
+			zap.String("location_id", stock.LocationID),
+			zap.Error(err),
+		)
+		return
+	}
+	for i := range rules {
+		if rules[i].Triggered(*stock) {
+			m.raiseRuleAlert(ctx, &rules[i], *stock)
+		}
+	}
+}
+
+// raiseRuleAlert creates the alert of a triggered rule unless one is active or the rule is cooling down
+// 条件を満たしたルールのアラートを作成（アクティブなアラートがある場合・クールダウン中は作成しない）
+//
+// アラートを作成した場合に true を返します。
+func (m *Manager) raiseRuleAlert(ctx context.Context, rule *AlertRule, stock Stock) bool {
+	latest, err := m.storage.GetLatestRuleAlert(ctx, rule.ID, stock.ItemID, stock.LocationID)
+	switch {
+	case err == nil:
+		if latest.IsActive || time.Since(latest.CreatedAt) < rule.Cooldown() {
+			return false
+		}
+	case !errors.Is(err, ErrAlertNotFound):
+		m.log(ctx).Warn("アラートルールの前回のアラートの取得に失敗しました",
+			zap.String("rule_id", rule.ID),
+			zap.String("item_id", stock.ItemID),
+			zap.String("location_id", stock.LocationID),
+			zap.Error(err),
+		)
+		return false
+	}
+
+	value := rule.Value(stock)
+	alert := &StockAlert{
+		ID:         NewTransactionID(),
+		Type:       rule.AlertType(),
+		ItemID:     stock.ItemID,
+		LocationID: stock.LocationID,
+		CurrentQty: value,
+		Threshold:  rule.Threshold,
+		Message:    fmt.Sprintf("アラートルール「%s」: 商品 %s のロケーション %s の%s (%d) が閾値 %d %s", rule.Name, stock.ItemID, stock.LocationID, alertMetricLabels[rule.Metric], value, rule.Threshold, alertComparatorLabels[rule.Comparator]),
+		IsActive:   true,
+		CreatedAt:  time.Now(),
+		RuleID:     rule.ID,
+		Severity:   rule.Severity,
+	}
+	if err := m.storage.CreateAlert(ctx, alert); err != nil {
+		m.log(ctx).Error("アラート作成に失敗しました", zap.String("rule_id", rule.ID), zap.Error(err))
+		return false
+	}
+	m.publishEvent(ctx, EventTypeAlertCreated, stock.ItemID, stock.LocationID, alert)
+	return true
+}
+
+// alertMetricLabels are the names of the metrics used in alert messages
+// アラートのメッセージで使用する判定値の名称
+var alertMetricLabels = map[AlertMetric]string{
+	AlertMetricQuantity:  "在庫数量",
+	AlertMetricAvailable: "利用可能数",
+	AlertMetricReserved:  "予約済み数量",
+}
+
+// alertComparatorLabels describe the comparators in alert messages
+// アラートのメッセージで使用する比較方法の表現
+var alertComparatorLabels = map[AlertComparator]string{
+	AlertComparatorLT:  "未満です",
+	AlertComparatorLTE: "以下です",
+	AlertComparatorGT:  "を超えています",
+	AlertComparatorGTE: "以上です",
+}
@@ -0,0 +1,306 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var _ Allocator = (*Manager)(nil)
+
+// earthRadiusKm is the mean radius of the earth used for great-circle distances
+// 大圏距離の計算に使用する地球の平均半径（km）
+const earthRadiusKm = 6371.0
+
+// allocationCandidate is a location the allocation engine can source from
+// 引当で在庫を確保できるロケーションの候補
+type allocationCandidate struct {
+	location   *Location
+	available  int64
+	distanceKm *float64
+}
+
+// PlanAllocation decides how to source a quantity of an item across locations without reserving it
+// 商品の数量をどのロケーションから確保するかを決定（予約は作成しない）
+//
+// 利用可能数のあるアクティブなロケーションを方式（Strategy）で順位付けし、上位から順に
+// 数量を割り当てます。不足する場合は AllowPartial が false なら ErrInsufficientStock を返します。
+func (m *Manager) PlanAllocation(ctx context.Context, request AllocationRequest) (*Allocation, error) {
+	if err := ValidateAllocationRequest(&request); err != nil {
+		return nil, err
+	}
+	return m.planAllocation(ctx, request)
+}
+
+// Allocate sources a quantity of an item across locations and reserves it at each of them
+// 商品の数量を複数のロケーションから確保し、ロケーションごとに予約を作成
+//
+// PlanAllocation と同じ方法で決定した各ロケーションの数量を1つのトランザクションで予約し、
+// 作成した予約のIDを各行に設定します。予約には参照番号と有効期限（ExpiresAt、
+// 未指定の場合は ReservationTTL）を記録します。
+func (m *Manager) Allocate(ctx context.Context, request AllocationRequest) (_ *Allocation, err error) {
+	ctx, finish := m.startOperation(ctx, "allocate", attrItemID.String(request.ItemID), attrQuantity.Int64(request.Quantity), attrReference.String(request.Reference))
+	defer finish(&err)
+
+	if err := ValidateAllocationRequest(&request); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	expiresAt := request.ExpiresAt
+	if expiresAt == nil && m.config.ReservationTTL > 0 {
+		ttl := now.Add(m.config.ReservationTTL)
+		expiresAt = &ttl
+	}
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, NewValidationError("expires_at", "有効期限は未来の日時で指定してください", expiresAt.Format(time.RFC3339))
+	}
+
+	var (
+		allocation   *Allocation
+		reservations []*Reservation
+		stocks       []*Stock
+		txs          []*Transaction
+	)
+	err = m.withReservationTx(ctx, func(lm *Manager) error {
+		reservations, stocks, txs = nil, nil, nil
+
+		// 悲観的ロックの場合、他の引当とのデッドロックを避けるためロケーションID順に行ロックを取得
+		if m.config.LockingStrategy == LockingStrategyPessimistic {
+			current, err := lm.storage.ListStockByItem(ctx, request.ItemID)
+			if err != nil {
+				return NewStorageError("list_stock", "在庫一覧の取得に失敗しました", err)
+			}
+			sort.Slice(current, func(i, j int) bool { return current[i].LocationID < current[j].LocationID })
+			for _, stock := range current {
+				if _, err := lm.storage.GetStockForUpdate(ctx, stock.ItemID, stock.LocationID); err != nil && !errors.Is(err, ErrStockNotFound) {
+					return NewStorageError("get_stock", "在庫取得に失敗しました", err)
+				}
+			}
+		}
+
+		var err error
+		allocation, err = lm.planAllocation(ctx, request)
+		if err != nil {
+			return err
+		}
+		createdBy := m.getUserFromContext(ctx)
+		for i := range allocation.Lines {
+			line := &allocation.Lines[i]
+			reservation := &Reservation{
+				ID:         NewReservationID(),
+				ItemID:     request.ItemID,
+				LocationID: line.LocationID,
+				Quantity:   line.Quantity,
+				Reference:  request.Reference,
+				Status:     ReservationStatusActive,
+				ExpiresAt:  expiresAt,
+				CreatedAt:  now,
+				CreatedBy:  createdBy,
+				Channel:    request.Channel,
+			}
+			stock, tx, err := lm.holdReservation(ctx, reservation, now)
+			if err != nil {
+				return err
+			}
+			line.ReservationID = reservation.ID
+			reservations = append(reservations, reservation)
+			stocks = append(stocks, stock)
+			txs = append(txs, tx)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, reservation := range reservations {
+		m.publishReservationCreated(ctx, reservation, stocks[i], txs[i])
+	}
+	m.log(ctx).Info("引当完了",
+		zap.String("item_id", allocation.ItemID),
+		zap.String("reference", allocation.Reference),
+		zap.String("strategy", string(allocation.Strategy)),
+		zap.Int64("requested", allocation.Requested),
+		zap.Int64("allocated", allocation.Allocated),
+		zap.Int("locations", len(allocation.Lines)),
+	)
+	return allocation, nil
+}
+
+// planAllocation ranks the candidate locations and assigns the requested quantity to them
+// 候補のロケーションを順位付けし、要求された数量を割り当て
+func (m *Manager) planAllocation(ctx context.Context, request AllocationRequest) (*Allocation, error) {
+	strategy := request.Strategy
+	if strategy == "" {
+		strategy = AllocationStrategyPriority
+	}
+	if _, err := m.storage.GetItem(ctx, request.ItemID); err != nil {
+		if errors.Is(err, ErrItemNotFound) {
+			return nil, ErrItemNotFound
+		}
+		return nil, NewStorageError("get_item", "商品取得に失敗しました", err)
+	}
+
+	candidates, err := m.allocationCandidates(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	sortAllocationCandidates(candidates, strategy)
+
+	// 全数を確保できるロケーションがあれば、順位が最も高いものだけから確保
+	if request.PreferSingleLocation {
+		for _, candidate := range candidates {
+			if candidate.available >= request.Quantity {
+				candidates = []allocationCandidate{candidate}
+				break
+			}
+		}
+	}
+
+	allocation := &Allocation{
+		ItemID:    request.ItemID,
+		Reference: request.Reference,
+		Strategy:  strategy,
+		Requested: request.Quantity,
+		Lines:     []AllocationLine{},
+	}
+	remaining := request.Quantity
+	for _, candidate := range candidates {
+		if remaining == 0 || (request.MaxLocations > 0 && len(allocation.Lines) >= request.MaxLocations) {
+			break
+		}
+		quantity := candidate.available
+		if quantity > remaining {
+			quantity = remaining
+		}
+		allocation.Lines = append(allocation.Lines, AllocationLine{
+			LocationID: candidate.location.ID,
+			Quantity:   quantity,
+			Available:  candidate.available,
+			Priority:   candidate.location.Priority,
+			DistanceKm: candidate.distanceKm,
+		})
+		allocation.Allocated += quantity
+		remaining -= quantity
+	}
+	allocation.Shortfall = remaining
+
+	if allocation.Allocated == 0 || (remaining > 0 && !request.AllowPartial) {
+		return nil, ErrInsufficientStock
+	}
+	return allocation, nil
+}
+
+// allocationCandidates lists the active locations holding available stock of the requested item
+// 要求された商品の利用可能数があるアクティブなロケーションを取得
+func (m *Manager) allocationCandidates(ctx context.Context, request AllocationRequest) ([]allocationCandidate, error) {
+	var allowed map[string]bool
+	if len(request.LocationIDs) > 0 {
+		allowed = make(map[string]bool, len(request.LocationIDs))
+		for _, locationID := range request.LocationIDs {
+			allowed[locationID] = true
+		}
+	}
+
+	stocks, err := m.storage.ListStockByItem(ctx, request.ItemID)
+	if err != nil {
+		return nil, NewStorageError("list_stock", "在庫一覧の取得に失敗しました", err)
+	}
+	candidates := make([]allocationCandidate, 0, len(stocks))
+	for _, stock := range stocks {
+		if stock.Available <= 0 || (allowed != nil && !allowed[stock.LocationID]) {
+			continue
+		}
+		location, err := m.storage.GetLocation(ctx, stock.LocationID)
+		if err != nil {
+			if errors.Is(err, ErrLocationNotFound) {
+				continue
+			}
+			return nil, NewStorageError("get_location", "ロケーション取得に失敗しました", err)
+		}
+		if !location.IsActive {
+			continue
+		}
+		// 販売チャネルが予約できる数量（他のチャネルに確保された数量を除く）
+		available, err := m.reservableQuantity(ctx, &stock, request.Channel)
+		if err != nil {
+			return nil, err
+		}
+		if available <= 0 {
+			continue
+		}
+		candidate := allocationCandidate{location: location, available: available}
+		if request.Destination != nil && location.Latitude != nil && location.Longitude != nil {
+			distance := haversineKm(*request.Destination, GeoPoint{Latitude: *location.Latitude, Longitude: *location.Longitude})
+			candidate.distanceKm = &distance
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates, nil
+}
+
+// sortAllocationCandidates orders the candidate locations by strategy
+// 候補のロケーションを方式に従って並び替え
+//
+// 同順位の場合は優先順位、配送先からの距離、利用可能数の降順、ロケーションIDの順で決定します。
+func sortAllocationCandidates(candidates []allocationCandidate, strategy AllocationStrategy) {
+	byPriority := func(a, b allocationCandidate) int {
+		return a.location.Priority - b.location.Priority
+	}
+	byDistance := func(a, b allocationCandidate) int {
+		switch {
+		case a.distanceKm == nil && b.distanceKm == nil:
+			return 0
+		case a.distanceKm == nil:
+			return 1
+		case b.distanceKm == nil:
+			return -1
+		case *a.distanceKm < *b.distanceKm:
+			return -1
+		case *a.distanceKm > *b.distanceKm:
+			return 1
+		}
+		return 0
+	}
+	byAvailable := func(a, b allocationCandidate) int {
+		switch {
+		case a.available > b.available:
+			return -1
+		case a.available < b.available:
+			return 1
+		}
+		return 0
+	}
+
+	var order []func(a, b allocationCandidate) int
+	switch strategy {
+	case AllocationStrategyProximity:
+		order = []func(a, b allocationCandidate) int{byDistance, byPriority, byAvailable}
+	case AllocationStrategyStockLevel:
+		order = []func(a, b allocationCandidate) int{byAvailable, byPriority, byDistance}
+	default:
+		order = []func(a, b allocationCandidate) int{byPriority, byDistance, byAvailable}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		for _, compare := range order {
+			if c := compare(candidates[i], candidates[j]); c != 0 {
+				return c < 0
+			}
+		}
+		return candidates[i].location.ID < candidates[j].location.ID
+	})
+}
+
+// haversineKm returns the great-circle distance between two points in kilometres
+// 2地点間の大圏距離（km）を計算
+func haversineKm(a, b GeoPoint) float64 {
+	lat1, lat2 := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLng := (b.Longitude - a.Longitude) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
@@ -0,0 +1,284 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// MemoryAPIKeyStore implements APIKeyStore with an in-memory map
+// インメモリのマップを使用したAPIKeyStoreの実装
+//
+// プロセスの再起動で失われるため、単体テストやデモ用途を想定しています。
+type MemoryAPIKeyStore struct {
+	mu   sync.Mutex
+	keys map[string]APIKey
+}
+
+var _ APIKeyStore = (*MemoryAPIKeyStore)(nil)
+
+// NewMemoryAPIKeyStore creates a new in-memory API key store
+// 新しいインメモリAPIキーストアを作成
+func NewMemoryAPIKeyStore() *MemoryAPIKeyStore {
+	return &MemoryAPIKeyStore{keys: make(map[string]APIKey)}
+}
+
+// CreateAPIKey creates a new key
+// 新しいキーを作成
+func (s *MemoryAPIKeyStore) CreateAPIKey(ctx context.Context, key *APIKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.keys {
+		if existing.ID == key.ID || existing.Hash == key.Hash {
+			return fmt.Errorf("APIキーが重複しています: %s", key.ID)
+		}
+	}
+	s.keys[key.ID] = *key
+	return nil
+}
+
+// GetAPIKey retrieves a key by ID
+// IDでキーを取得
+func (s *MemoryAPIKeyStore) GetAPIKey(ctx context.Context, id string) (*APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, exists := s.keys[id]
+	if !exists {
+		return nil, ErrAPIKeyNotFound
+	}
+	return &key, nil
+}
+
+// GetAPIKeyByHash retrieves a key by the hash of the key
+// キーのハッシュでキーを取得
+func (s *MemoryAPIKeyStore) GetAPIKeyByHash(ctx context.Context, hash string) (*APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range s.keys {
+		if key.Hash == hash {
+			return &key, nil
+		}
+	}
+	return nil, ErrAPIKeyNotFound
+}
+
+// ListAPIKeys retrieves all keys in issue order
+// 全てのキーを発行順に取得
+func (s *MemoryAPIKeyStore) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]APIKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+	return keys, nil
+}
+
+// UpdateAPIKey updates the expiry, revocation time and successor of a key
+// 有効期限・無効化日時・後継のキーIDを更新
+func (s *MemoryAPIKeyStore) UpdateAPIKey(ctx context.Context, key *APIKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, exists := s.keys[key.ID]
+	if !exists {
+		return ErrAPIKeyNotFound
+	}
+	existing.ExpiresAt = key.ExpiresAt
+	existing.RevokedAt = key.RevokedAt
+	existing.RotatedTo = key.RotatedTo
+	s.keys[key.ID] = existing
+	return nil
+}
+
+// TouchAPIKey updates the last-used time of a key
+// キーの最終使用日時を更新
+func (s *MemoryAPIKeyStore) TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, exists := s.keys[id]
+	if !exists {
+		return ErrAPIKeyNotFound
+	}
+	key.LastUsedAt = &usedAt
+	s.keys[id] = key
+	return nil
+}
+
+// PostgresAPIKeyStore implements APIKeyStore using the api_keys table
+// api_keysテーブルを使用したAPIKeyStoreの実装
+type PostgresAPIKeyStore struct {
+	db *sql.DB
+}
+
+var _ APIKeyStore = (*PostgresAPIKeyStore)(nil)
+
+// NewPostgresAPIKeyStore creates an API key store on an existing connection pool
+// 既存の接続プールを使用するAPIキーストアを作成
+func NewPostgresAPIKeyStore(db *sql.DB) *PostgresAPIKeyStore {
+	return &PostgresAPIKeyStore{db: db}
+}
+
+// CreateAPIKey creates a new key
+// 新しいキーを作成
+func (s *PostgresAPIKeyStore) CreateAPIKey(ctx context.Context, key *APIKey) error {
+	query := `
+		INSERT INTO api_keys (id, name, prefix, key_hash, scopes, created_at, expires_at, revoked_at, last_used_at, rotated_to)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	_, err := s.db.ExecContext(ctx, query,
+		key.ID,
+		key.Name,
+		key.Prefix,
+		key.Hash,
+		pq.Array(key.Scopes),
+		key.CreatedAt,
+		key.ExpiresAt,
+		key.RevokedAt,
+		key.LastUsedAt,
+		key.RotatedTo,
+	)
+	if err != nil {
+		return fmt.Errorf("APIキーの保存に失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// GetAPIKey retrieves a key by ID
+// IDでキーを取得
+func (s *PostgresAPIKeyStore) GetAPIKey(ctx context.Context, id string) (*APIKey, error) {
+	return s.get(ctx, `WHERE id = $1`, id)
+}
+
+// GetAPIKeyByHash retrieves a key by the hash of the key
+// キーのハッシュでキーを取得
+func (s *PostgresAPIKeyStore) GetAPIKeyByHash(ctx context.Context, hash string) (*APIKey, error) {
+	return s.get(ctx, `WHERE key_hash = $1`, hash)
+}
+
+// ListAPIKeys retrieves all keys in issue order
+// 全てのキーを発行順に取得
+func (s *PostgresAPIKeyStore) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	return s.query(ctx, `ORDER BY created_at`)
+}
+
+// UpdateAPIKey updates the expiry, revocation time and successor of a key
+// 有効期限・無効化日時・後継のキーIDを更新
+func (s *PostgresAPIKeyStore) UpdateAPIKey(ctx context.Context, key *APIKey) error {
+	query := `
+		UPDATE api_keys
+		SET expires_at = $2, revoked_at = $3, rotated_to = $4
+		WHERE id = $1`
+
+	result, err := s.db.ExecContext(ctx, query, key.ID, key.ExpiresAt, key.RevokedAt, key.RotatedTo)
+	if err != nil {
+		return fmt.Errorf("APIキーの更新に失敗しました: %w", err)
+	}
+
+	return checkAffected(result)
+}
+
+// TouchAPIKey updates the last-used time of a key
+// キーの最終使用日時を更新
+func (s *PostgresAPIKeyStore) TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error {
+	result, err := s.db.ExecContext(ctx, `UPDATE api_keys SET last_used_at = $2 WHERE id = $1`, id, usedAt)
+	if err != nil {
+		return fmt.Errorf("APIキーの最終使用日時の更新に失敗しました: %w", err)
+	}
+
+	return checkAffected(result)
+}
+
+// get retrieves a single key matching the condition
+// 条件に一致するキーを1件取得
+func (s *PostgresAPIKeyStore) get(ctx context.Context, condition string, args ...interface{}) (*APIKey, error) {
+	keys, err := s.query(ctx, condition, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, ErrAPIKeyNotFound
+	}
+	return &keys[0], nil
+}
+
+// query runs a SELECT over api_keys and scans the rows
+// api_keysのSELECTを実行して結果を読み取る
+func (s *PostgresAPIKeyStore) query(ctx context.Context, condition string, args ...interface{}) ([]APIKey, error) {
+	query := `
+		SELECT id, name, prefix, key_hash, scopes, created_at, expires_at, revoked_at, last_used_at, rotated_to
+		FROM api_keys ` + condition
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("APIキーの取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []APIKey
+	for rows.Next() {
+		var (
+			key                              APIKey
+			expiresAt, revokedAt, lastUsedAt sql.NullTime
+		)
+		if err := rows.Scan(
+			&key.ID,
+			&key.Name,
+			&key.Prefix,
+			&key.Hash,
+			pq.Array(&key.Scopes),
+			&key.CreatedAt,
+			&expiresAt,
+			&revokedAt,
+			&lastUsedAt,
+			&key.RotatedTo,
+		); err != nil {
+			return nil, fmt.Errorf("APIキーの読み取りに失敗しました: %w", err)
+		}
+		key.ExpiresAt = nullTime(expiresAt)
+		key.RevokedAt = nullTime(revokedAt)
+		key.LastUsedAt = nullTime(lastUsedAt)
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("APIキーの読み取りに失敗しました: %w", err)
+	}
+
+	return keys, nil
+}
+
+// nullTime converts a nullable column to a time pointer
+// NULL許容の列を時刻のポインタに変換
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// checkAffected returns ErrAPIKeyNotFound when no row was affected
+// 対象行がない場合にErrAPIKeyNotFoundを返す
+func checkAffected(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
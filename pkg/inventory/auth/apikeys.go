@@ -0,0 +1,262 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyHeader is the header carrying an API key
+// APIキーを指定するヘッダー
+const APIKeyHeader = "X-API-Key"
+
+// Scopes granted to API keys
+// APIキーに付与するスコープ
+//
+// 上位のスコープは下位のスコープを含みます（inventory:admin ⊃ inventory:write ⊃ inventory:read）。
+const (
+	ScopeRead  = "inventory:read"  // 照会のみ
+	ScopeWrite = "inventory:write" // 在庫操作・マスタ更新を含む
+	ScopeAdmin = "inventory:admin" // APIキー管理などの管理操作を含む
+)
+
+// apiKeyPrefix marks API keys so that leaked keys can be detected by secret scanners
+// 漏洩したキーをシークレットスキャナーで検出できるようにするための接頭辞
+const apiKeyPrefix = "zai_"
+
+// touchInterval limits how often the last-used time of a key is written
+// 最終使用日時を書き込む最短間隔（リクエストごとの書き込みを避ける）
+const touchInterval = time.Minute
+
+// ErrAPIKeyNotFound is returned when an API key does not exist
+// APIキーが存在しない場合のエラー
+var ErrAPIKeyNotFound = errors.New("APIキーが見つかりません")
+
+// APIKey is an API key issued to a machine-to-machine caller
+// システム間連携の呼び出し元に発行したAPIキー
+type APIKey struct {
+	ID         string     `json:"id"`                     // キーID（操作者は "apikey:<ID>" として記録）
+	Name       string     `json:"name"`                   // 用途などの名前
+	Prefix     string     `json:"prefix"`                 // キーの先頭部分（識別用）
+	Hash       string     `json:"-"`                      // キーのSHA-256（キー自体は保存しない）
+	Scopes     []string   `json:"scopes"`                 // 許可されたスコープ
+	CreatedAt  time.Time  `json:"created_at"`             // 発行日時
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`   // 有効期限（ローテーション後の猶予期間を含む）
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`   // 無効化日時
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"` // 最終使用日時（1分単位）
+	RotatedTo  string     `json:"rotated_to,omitempty"`   // ローテーションで発行した後継のキーID
+}
+
+// Active reports whether the key can be used at the time
+// 指定時刻にキーが使用できるかを返す
+func (k *APIKey) Active(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// APIKeyStore persists API keys
+// APIキーを永続化
+type APIKeyStore interface {
+	// 新しいキーを作成します
+	CreateAPIKey(ctx context.Context, key *APIKey) error
+	// 指定されたIDのキーを取得します。存在しない場合はErrAPIKeyNotFoundを返します
+	GetAPIKey(ctx context.Context, id string) (*APIKey, error)
+	// キーのハッシュでキーを取得します。存在しない場合はErrAPIKeyNotFoundを返します
+	GetAPIKeyByHash(ctx context.Context, hash string) (*APIKey, error)
+	// 全てのキーを発行順に取得します
+	ListAPIKeys(ctx context.Context) ([]APIKey, error)
+	// 有効期限・無効化日時・後継のキーIDを更新します
+	UpdateAPIKey(ctx context.Context, key *APIKey) error
+	// 最終使用日時を更新します
+	TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error
+}
+
+// APIKeys issues, rotates, revokes and authenticates API keys
+// APIキーの発行・ローテーション・無効化・認証
+type APIKeys struct {
+	store APIKeyStore
+	now   func() time.Time
+}
+
+var _ Authenticator = (*APIKeys)(nil)
+
+// NewAPIKeys creates an API key manager over a store
+// ストアを使用するAPIキー管理を作成
+func NewAPIKeys(store APIKeyStore) *APIKeys {
+	return &APIKeys{store: store, now: time.Now}
+}
+
+// Issue creates a new key and returns it with the plaintext key
+// 新しいキーを発行し、平文のキーとともに返す
+//
+// 平文のキーは保存しないため、発行時にのみ取得できます。
+func (a *APIKeys) Issue(ctx context.Context, name string, scopes []string, expiresAt *time.Time) (*APIKey, string, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, "", fmt.Errorf("APIキーの名前を指定してください")
+	}
+	if err := ValidateScopes(scopes); err != nil {
+		return nil, "", err
+	}
+	now := a.now()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, "", fmt.Errorf("有効期限は現在より後の日時を指定してください")
+	}
+
+	plaintext, err := newAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+	key := &APIKey{
+		ID:        uuid.New().String(),
+		Name:      name,
+		Prefix:    plaintext[:len(apiKeyPrefix)+6],
+		Hash:      HashAPIKey(plaintext),
+		Scopes:    append([]string(nil), scopes...),
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	}
+	if err := a.store.CreateAPIKey(ctx, key); err != nil {
+		return nil, "", err
+	}
+	return key, plaintext, nil
+}
+
+// Rotate issues a successor of a key with the same name and scopes
+// 同じ名前・スコープの後継のキーを発行
+//
+// 旧キーは呼び出し元が新しいキーに切り替えられるよう、gracePeriod の間は有効なままにします
+// （0の場合は直ちに無効化します）。後継のキーは旧キーの有効期限を引き継ぎません。
+func (a *APIKeys) Rotate(ctx context.Context, id string, gracePeriod time.Duration) (*APIKey, string, error) {
+	if gracePeriod < 0 {
+		return nil, "", fmt.Errorf("猶予期間は0以上を指定してください")
+	}
+	old, err := a.store.GetAPIKey(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if !old.Active(a.now()) {
+		return nil, "", fmt.Errorf("無効化または期限切れのAPIキーはローテーションできません")
+	}
+
+	key, plaintext, err := a.Issue(ctx, old.Name, old.Scopes, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := a.now()
+	if gracePeriod == 0 {
+		old.RevokedAt = &now
+	} else if expiresAt := now.Add(gracePeriod); old.ExpiresAt == nil || expiresAt.Before(*old.ExpiresAt) {
+		old.ExpiresAt = &expiresAt
+	}
+	old.RotatedTo = key.ID
+	if err := a.store.UpdateAPIKey(ctx, old); err != nil {
+		return nil, "", fmt.Errorf("旧APIキーの更新に失敗しました（後継のキー %s は発行済み）: %w", key.ID, err)
+	}
+	return key, plaintext, nil
+}
+
+// Revoke disables a key immediately
+// キーを直ちに無効化
+func (a *APIKeys) Revoke(ctx context.Context, id string) (*APIKey, error) {
+	key, err := a.store.GetAPIKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if key.RevokedAt == nil {
+		now := a.now()
+		key.RevokedAt = &now
+		if err := a.store.UpdateAPIKey(ctx, key); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// Get retrieves a key by ID
+// IDでキーを取得
+func (a *APIKeys) Get(ctx context.Context, id string) (*APIKey, error) {
+	return a.store.GetAPIKey(ctx, id)
+}
+
+// List retrieves all keys in issue order
+// 全てのキーを発行順に取得
+func (a *APIKeys) List(ctx context.Context) ([]APIKey, error) {
+	return a.store.ListAPIKeys(ctx)
+}
+
+// Authenticate authenticates a request by the X-API-Key header
+// X-API-Keyヘッダーでリクエストを認証
+func (a *APIKeys) Authenticate(r *http.Request) (*User, error) {
+	plaintext := strings.TrimSpace(r.Header.Get(APIKeyHeader))
+	if plaintext == "" {
+		return nil, ErrNoCredentials
+	}
+	if !strings.HasPrefix(plaintext, apiKeyPrefix) {
+		return nil, fmt.Errorf("%w: APIキーの形式ではありません", ErrInvalidToken)
+	}
+
+	key, err := a.store.GetAPIKeyByHash(r.Context(), HashAPIKey(plaintext))
+	if err != nil {
+		if errors.Is(err, ErrAPIKeyNotFound) {
+			return nil, fmt.Errorf("%w: APIキーが登録されていません", ErrInvalidToken)
+		}
+		return nil, err
+	}
+	now := a.now()
+	if !key.Active(now) {
+		return nil, fmt.Errorf("%w: APIキー %s は無効化または期限切れです", ErrInvalidToken, key.ID)
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= touchInterval {
+		// 最終使用日時の記録に失敗しても認証は成功させる
+		_ = a.store.TouchAPIKey(r.Context(), key.ID, now)
+	}
+	return &User{ID: "apikey:" + key.ID, Scopes: key.Scopes, Method: MethodAPIKey}, nil
+}
+
+// HashAPIKey returns the stored hash of a plaintext key
+// 平文のキーから保存するハッシュを計算
+//
+// キーは十分なエントロピーを持つ乱数のため、ソルトなしのSHA-256で保存します。
+func HashAPIKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+// ValidateScopes checks that scopes are known and not empty
+// スコープが既知で空でないことを検証
+func ValidateScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return fmt.Errorf("スコープを1つ以上指定してください")
+	}
+	for _, scope := range scopes {
+		switch scope {
+		case ScopeRead, ScopeWrite, ScopeAdmin:
+		default:
+			return fmt.Errorf("不明なスコープです: %s", scope)
+		}
+	}
+	return nil
+}
+
+// newAPIKey generates a random plaintext key
+// ランダムな平文のキーを生成
+func newAPIKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("APIキーの生成に失敗しました: %w", err)
+	}
+	return apiKeyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// apiKeyRequest はX-API-Keyヘッダー付きのリクエストを作成する
+func apiKeyRequest(key string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if key != "" {
+		r.Header.Set(APIKeyHeader, key)
+	}
+	return r
+}
+
+// TestAPIKeys_IssueAndAuthenticate はAPIキーの発行と認証のテスト
+func TestAPIKeys_IssueAndAuthenticate(t *testing.T) {
+	store := NewMemoryAPIKeyStore()
+	keys := NewAPIKeys(store)
+	ctx := context.Background()
+
+	key, plaintext, err := keys.Issue(ctx, "batch", []string{ScopeRead}, nil)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(plaintext, "zai_"))
+	assert.True(t, strings.HasPrefix(plaintext, key.Prefix))
+	assert.Equal(t, HashAPIKey(plaintext), key.Hash)
+	assert.NotContains(t, key.Hash, plaintext, "平文のキーは保存しない")
+
+	user, err := keys.Authenticate(apiKeyRequest(plaintext))
+	require.NoError(t, err)
+	assert.Equal(t, "apikey:"+key.ID, user.ID)
+	assert.Equal(t, MethodAPIKey, user.Method)
+	assert.True(t, user.Allows(ScopeRead))
+	assert.False(t, user.Allows(ScopeWrite))
+
+	stored, err := keys.Get(ctx, key.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.LastUsedAt)
+
+	_, err = keys.Authenticate(apiKeyRequest(""))
+	assert.ErrorIs(t, err, ErrNoCredentials)
+	_, err = keys.Authenticate(apiKeyRequest("zai_unknown"))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = keys.Authenticate(apiKeyRequest("not-a-key"))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, _, err = keys.Issue(ctx, "batch", []string{"inventory:delete"}, nil)
+	assert.Error(t, err)
+	_, _, err = keys.Issue(ctx, "batch", nil, nil)
+	assert.Error(t, err)
+	past := time.Now().Add(-time.Hour)
+	_, _, err = keys.Issue(ctx, "batch", []string{ScopeRead}, &past)
+	assert.Error(t, err)
+}
+
+// TestAPIKeys_RotateAndRevoke はAPIキーのローテーションと無効化のテスト
+func TestAPIKeys_RotateAndRevoke(t *testing.T) {
+	keys := NewAPIKeys(NewMemoryAPIKeyStore())
+	ctx := context.Background()
+	now := time.Now()
+	keys.now = func() time.Time { return now }
+
+	old, oldPlaintext, err := keys.Issue(ctx, "erp", []string{ScopeWrite}, nil)
+	require.NoError(t, err)
+
+	key, plaintext, err := keys.Rotate(ctx, old.ID, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, old.Name, key.Name)
+	assert.Equal(t, old.Scopes, key.Scopes)
+	assert.NotEqual(t, oldPlaintext, plaintext)
+
+	// 猶予期間中は旧キーも使用できる
+	_, err = keys.Authenticate(apiKeyRequest(oldPlaintext))
+	assert.NoError(t, err)
+	_, err = keys.Authenticate(apiKeyRequest(plaintext))
+	assert.NoError(t, err)
+
+	stored, err := keys.Get(ctx, old.ID)
+	require.NoError(t, err)
+	assert.Equal(t, key.ID, stored.RotatedTo)
+
+	now = now.Add(time.Hour)
+	_, err = keys.Authenticate(apiKeyRequest(oldPlaintext))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, _, err = keys.Rotate(ctx, old.ID, 0)
+	assert.Error(t, err, "期限切れのキーはローテーションできない")
+
+	// 猶予期間0のローテーションは旧キーを直ちに無効化する
+	_, _, err = keys.Rotate(ctx, key.ID, 0)
+	require.NoError(t, err)
+	_, err = keys.Authenticate(apiKeyRequest(plaintext))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	revoked, err := keys.Revoke(ctx, old.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, revoked.RevokedAt)
+
+	_, err = keys.Revoke(ctx, "missing")
+	assert.ErrorIs(t, err, ErrAPIKeyNotFound)
+
+	list, err := keys.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, list, 3)
+}
+
+// TestAny は複数の認証方式を順に試すテスト
+func TestAny(t *testing.T) {
+	keys := NewAPIKeys(NewMemoryAPIKeyStore())
+	_, plaintext, err := keys.Issue(context.Background(), "batch", []string{ScopeAdmin}, nil)
+	require.NoError(t, err)
+	verifier, err := NewJWTVerifier(JWTConfig{HMACSecret: "secret"})
+	require.NoError(t, err)
+	authenticator := Any(keys, verifier)
+
+	user, err := authenticator.Authenticate(apiKeyRequest(plaintext))
+	require.NoError(t, err)
+	assert.Equal(t, MethodAPIKey, user.Method)
+	assert.True(t, user.Allows(ScopeWrite))
+
+	r := apiKeyRequest("")
+	r.Header.Set("Authorization", "Bearer "+signToken(t, map[string]interface{}{"alg": "HS256"}, validClaims(), hs256("secret")))
+	user, err = authenticator.Authenticate(r)
+	require.NoError(t, err)
+	assert.Equal(t, MethodJWT, user.Method)
+
+	_, err = authenticator.Authenticate(apiKeyRequest(""))
+	assert.ErrorIs(t, err, ErrNoCredentials)
+	// 不正なAPIキーは他の認証方式を試さない
+	r = apiKeyRequest("zai_unknown")
+	r.Header.Set("Authorization", "Bearer "+signToken(t, map[string]interface{}{"alg": "HS256"}, validClaims(), hs256("secret")))
+	_, err = authenticator.Authenticate(r)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
@@ -0,0 +1,131 @@
+// Package auth authenticates API callers and carries the caller in the request context
+// APIの呼び出し元を認証し、リクエストのコンテキストで呼び出し元を受け渡すパッケージ
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Errors returned by authenticators
+// 認証で返すエラー
+var (
+	ErrNoCredentials = errors.New("認証情報が指定されていません")
+	ErrInvalidToken  = errors.New("トークンが無効です")
+)
+
+// Authentication methods of a user
+// ユーザーの認証方式
+const (
+	MethodJWT    = "jwt"     // JWTベアラートークン
+	MethodAPIKey = "api_key" // APIキー
+)
+
+// User is an authenticated caller
+// 認証済みの呼び出し元
+type User struct {
+	ID     string                 // 操作者として記録するID（トランザクションのuser_idなど）
+	Scopes []string               // 許可されたスコープ
+	Method string                 // 認証方式（認証を無効にしている場合は空）
+	Claims map[string]interface{} // トークンのクレーム（JWTの場合）
+}
+
+// HasScope reports whether the user was granted a scope
+// スコープが許可されているかを返す
+func (u *User) HasScope(scope string) bool {
+	for _, s := range u.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows reports whether the user was granted a scope or a scope including it
+// スコープ、またはそれを含む上位のスコープが許可されているかを返す
+func (u *User) Allows(scope string) bool {
+	switch scope {
+	case ScopeRead:
+		return u.HasScope(ScopeRead) || u.HasScope(ScopeWrite) || u.HasScope(ScopeAdmin)
+	case ScopeWrite:
+		return u.HasScope(ScopeWrite) || u.HasScope(ScopeAdmin)
+	default:
+		return u.HasScope(scope)
+	}
+}
+
+// Permits reports whether the user may perform an operation requiring scope
+// ユーザーがスコープを要求する操作を実行できるかを返す
+//
+// APIキーはキーのスコープ、JWTは scope・scp クレームのスコープで判定します。inventory:* のスコープを
+// 含まないJWTは、allowUnscopedJWT が true の場合のみ全ての操作を許可します。
+func (u *User) Permits(scope string, allowUnscopedJWT bool) bool {
+	if u.Method == MethodJWT && allowUnscopedJWT &&
+		!u.HasScope(ScopeRead) && !u.HasScope(ScopeWrite) && !u.HasScope(ScopeAdmin) {
+		return true
+	}
+	return u.Allows(scope)
+}
+
+// Authenticator authenticates the caller of an HTTP request
+// HTTPリクエストの呼び出し元を認証
+type Authenticator interface {
+	// 認証情報がない場合はErrNoCredentials、検証に失敗した場合はErrInvalidTokenをラップしたエラーを返します
+	Authenticate(r *http.Request) (*User, error)
+}
+
+// Any returns an authenticator trying each authenticator in order
+// 各認証を順に試す認証を返す
+//
+// 認証情報が指定されていない（ErrNoCredentials）場合のみ次の認証を試します。
+func Any(authenticators ...Authenticator) Authenticator {
+	return anyAuthenticator(authenticators)
+}
+
+type anyAuthenticator []Authenticator
+
+func (a anyAuthenticator) Authenticate(r *http.Request) (*User, error) {
+	for _, authenticator := range a {
+		user, err := authenticator.Authenticate(r)
+		if !errors.Is(err, ErrNoCredentials) {
+			return user, err
+		}
+	}
+	return nil, ErrNoCredentials
+}
+
+// userKey is the context key of the authenticated user
+// 認証済みユーザーのコンテキストキー
+type userKey struct{}
+
+// WithUser returns a context carrying the user
+// ユーザーを保持するコンテキストを返す
+//
+// 在庫マネージャーが操作者として記録できるよう、ユーザーIDを "user_id" キーにも設定します。
+func WithUser(ctx context.Context, user *User) context.Context {
+	ctx = context.WithValue(ctx, userKey{}, user)
+	return context.WithValue(ctx, "user_id", user.ID)
+}
+
+// UserFromContext returns the user carried by the context
+// コンテキストが保持するユーザーを返す
+func UserFromContext(ctx context.Context) (*User, bool) {
+	user, ok := ctx.Value(userKey{}).(*User)
+	return user, ok
+}
+
+// BearerToken returns the token of an "Authorization: Bearer" header
+// Authorization: Bearer ヘッダーのトークンを返す
+func BearerToken(r *http.Request) (string, error) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return "", ErrNoCredentials
+	}
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", ErrNoCredentials
+	}
+	return strings.TrimSpace(token), nil
+}
@@ -0,0 +1,157 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// minRefreshInterval limits refetching the JWKS for unknown key IDs
+// 未知の鍵IDによるJWKSの再取得の最短間隔（不正なトークンで発行元に負荷をかけないため）
+const minRefreshInterval = time.Minute
+
+// jwks caches the public keys of a JSON Web Key Set
+// JSON Web Key Set の公開鍵をキャッシュする
+type jwks struct {
+	url             string
+	client          *http.Client
+	refreshInterval time.Duration
+
+	mu        sync.Mutex
+	keys      map[string]jsonWebKey
+	checkedAt time.Time // 最後に取得を試みた時刻（失敗した場合を含む）
+}
+
+// jsonWebKey is a parsed public key with its metadata
+// 解析済みの公開鍵とそのメタデータ
+type jsonWebKey struct {
+	alg string
+	key crypto.PublicKey
+}
+
+func newJWKS(url string, client *http.Client, refreshInterval time.Duration) *jwks {
+	return &jwks{url: url, client: client, refreshInterval: refreshInterval}
+}
+
+// key returns the public key for a key ID, refetching the set when it is stale or the ID is unknown
+// 鍵IDの公開鍵を返す（キャッシュが古い場合や未知の鍵IDの場合はJWKSを再取得する）
+func (s *jwks) key(ctx context.Context, kid, alg string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	age := time.Since(s.checkedAt)
+	k, found := s.lookup(kid)
+	if s.keys == nil || age > s.refreshInterval || (!found && age > minRefreshInterval) {
+		err := s.fetch(ctx)
+		if s.keys == nil {
+			return nil, err
+		}
+		// 取得に失敗した場合もキャッシュ済みの鍵で検証を続け、次の取得まで間隔を空ける
+		s.checkedAt = time.Now()
+		k, found = s.lookup(kid)
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: 鍵 %q が見つかりません", ErrInvalidToken, kid)
+	}
+	if k.alg != "" && k.alg != alg {
+		return nil, fmt.Errorf("%w: 鍵のアルゴリズムが一致しません", ErrInvalidToken)
+	}
+	return k.key, nil
+}
+
+// lookup returns the key for a key ID; an empty ID matches only a set with a single key
+// 鍵IDの鍵を返す（鍵IDが空の場合は鍵が1つだけのときのみ一致）
+func (s *jwks) lookup(kid string) (jsonWebKey, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, k := range s.keys {
+			return k, true
+		}
+	}
+	k, ok := s.keys[kid]
+	return k, ok
+}
+
+// fetch downloads and parses the key set
+// JWKSを取得して解析
+func (s *jwks) fetch(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return fmt.Errorf("JWKSのリクエスト作成に失敗しました: %w", err)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("JWKSの取得に失敗しました: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("JWKSの取得に失敗しました: ステータス %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			Alg string `json:"alg"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			Crv string `json:"crv"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&set); err != nil {
+		return fmt.Errorf("JWKSの解析に失敗しました: %w", err)
+	}
+
+	keys := make(map[string]jsonWebKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		var key crypto.PublicKey
+		switch k.Kty {
+		case "RSA":
+			n, errN := base64.RawURLEncoding.DecodeString(k.N)
+			e, errE := base64.RawURLEncoding.DecodeString(k.E)
+			if errN != nil || errE != nil || len(e) == 0 || len(e) > 4 {
+				continue
+			}
+			key = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		case "EC":
+			curve := curves[k.Crv]
+			x, errX := base64.RawURLEncoding.DecodeString(k.X)
+			y, errY := base64.RawURLEncoding.DecodeString(k.Y)
+			if curve == nil || errX != nil || errY != nil {
+				continue
+			}
+			pub := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+			if !curve.IsOnCurve(pub.X, pub.Y) {
+				continue
+			}
+			key = pub
+		default:
+			continue
+		}
+		keys[k.Kid] = jsonWebKey{alg: k.Alg, key: key}
+	}
+
+	s.keys = keys
+	return nil
+}
+
+var curves = map[string]elliptic.Curve{
+	"P-256": elliptic.P256(),
+	"P-384": elliptic.P384(),
+	"P-521": elliptic.P521(),
+}
@@ -0,0 +1,287 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	// 署名の検証に使用するハッシュ関数を登録する
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+)
+
+// JWTConfig configures JWT bearer-token validation
+// JWTベアラートークンの検証設定
+type JWTConfig struct {
+	Issuer   string // iss クレームに要求する発行者（空の場合は検証しない）
+	Audience string // aud クレームに要求する対象者（空の場合は検証しない）
+
+	// JWKSURL は署名鍵を公開するJWKSのURL（RS256・ES256 などの公開鍵署名）
+	JWKSURL string
+	// HMACSecret は HS256 などの共有鍵署名の鍵（JWKSURL と併用可）
+	HMACSecret string
+
+	UserClaim       string        // ユーザーIDとするクレーム（省略時は sub）
+	Leeway          time.Duration // exp・nbf の時刻のずれの許容範囲
+	RefreshInterval time.Duration // JWKSの再取得間隔（省略時は1時間）
+	HTTPClient      *http.Client  // JWKSの取得に使用するクライアント（省略時はタイムアウト10秒）
+}
+
+// JWTVerifier authenticates requests by JWT bearer tokens
+// JWTベアラートークンでリクエストを認証
+type JWTVerifier struct {
+	config JWTConfig
+	keys   *jwks
+}
+
+// NewJWTVerifier creates a JWT verifier
+// JWT検証器を作成
+func NewJWTVerifier(config JWTConfig) (*JWTVerifier, error) {
+	if config.JWKSURL == "" && config.HMACSecret == "" {
+		return nil, fmt.Errorf("JWKSのURLまたはHMACの鍵を指定してください")
+	}
+	if config.UserClaim == "" {
+		config.UserClaim = "sub"
+	}
+	if config.RefreshInterval <= 0 {
+		config.RefreshInterval = time.Hour
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	v := &JWTVerifier{config: config}
+	if config.JWKSURL != "" {
+		v.keys = newJWKS(config.JWKSURL, config.HTTPClient, config.RefreshInterval)
+	}
+	return v, nil
+}
+
+// Authenticate verifies the bearer token of a request
+// リクエストのベアラートークンを検証
+func (v *JWTVerifier) Authenticate(r *http.Request) (*User, error) {
+	token, err := BearerToken(r)
+	if err != nil {
+		return nil, err
+	}
+	return v.Verify(r.Context(), token)
+}
+
+// jwtHeader is the JOSE header of a token
+// トークンのJOSEヘッダー
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// Verify verifies a token and returns the user it identifies
+// トークンを検証し、トークンが示すユーザーを返す
+//
+// 署名・有効期限（exp）・有効開始時刻（nbf）・発行者（iss）・対象者（aud）を検証します。
+func (v *JWTVerifier) Verify(ctx context.Context, token string) (*User, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: JWTの形式ではありません", ErrInvalidToken)
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: ヘッダーを解析できません", ErrInvalidToken)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: 署名を解析できません", ErrInvalidToken)
+	}
+	if err := v.verifySignature(ctx, header, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	claims := make(map[string]interface{})
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: クレームを解析できません", ErrInvalidToken)
+	}
+	if err := v.verifyClaims(claims); err != nil {
+		return nil, err
+	}
+
+	id, _ := claims[v.config.UserClaim].(string)
+	if id == "" {
+		return nil, fmt.Errorf("%w: クレーム %s がありません", ErrInvalidToken, v.config.UserClaim)
+	}
+	return &User{ID: id, Scopes: scopes(claims), Method: MethodJWT, Claims: claims}, nil
+}
+
+// verifySignature verifies the signature with the key for the algorithm
+// アルゴリズムに対応する鍵で署名を検証
+func (v *JWTVerifier) verifySignature(ctx context.Context, header jwtHeader, signed string, signature []byte) error {
+	alg, ok := algorithms[header.Alg]
+	if !ok {
+		return fmt.Errorf("%w: サポートされていないアルゴリズムです: %s", ErrInvalidToken, header.Alg)
+	}
+	if alg.kind == "HS" {
+		if v.config.HMACSecret == "" {
+			return fmt.Errorf("%w: %s の鍵が設定されていません", ErrInvalidToken, header.Alg)
+		}
+		mac := hmac.New(alg.hash.New, []byte(v.config.HMACSecret))
+		mac.Write([]byte(signed))
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return fmt.Errorf("%w: 署名が一致しません", ErrInvalidToken)
+		}
+		return nil
+	}
+
+	if v.keys == nil {
+		return fmt.Errorf("%w: %s の鍵が設定されていません", ErrInvalidToken, header.Alg)
+	}
+	key, err := v.keys.key(ctx, header.Kid, header.Alg)
+	if err != nil {
+		return err
+	}
+	h := alg.hash.New()
+	h.Write([]byte(signed))
+	digest := h.Sum(nil)
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		if alg.kind != "RS" || rsa.VerifyPKCS1v15(pub, alg.hash, digest, signature) != nil {
+			return fmt.Errorf("%w: 署名が一致しません", ErrInvalidToken)
+		}
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if alg.kind != "ES" || len(signature) != 2*size {
+			return fmt.Errorf("%w: 署名が一致しません", ErrInvalidToken)
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return fmt.Errorf("%w: 署名が一致しません", ErrInvalidToken)
+		}
+	default:
+		return fmt.Errorf("%w: 鍵の種類がアルゴリズムと一致しません", ErrInvalidToken)
+	}
+	return nil
+}
+
+// verifyClaims checks the registered claims
+// 登録済みクレームを検証
+func (v *JWTVerifier) verifyClaims(claims map[string]interface{}) error {
+	now := time.Now()
+	exp, ok, err := numericDate(claims, "exp")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: 有効期限（exp）がありません", ErrInvalidToken)
+	}
+	if now.After(exp.Add(v.config.Leeway)) {
+		return fmt.Errorf("%w: 有効期限が切れています", ErrInvalidToken)
+	}
+	nbf, ok, err := numericDate(claims, "nbf")
+	if err != nil {
+		return err
+	}
+	if ok && now.Add(v.config.Leeway).Before(nbf) {
+		return fmt.Errorf("%w: まだ有効になっていません", ErrInvalidToken)
+	}
+
+	if v.config.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != v.config.Issuer {
+			return fmt.Errorf("%w: 発行者が一致しません", ErrInvalidToken)
+		}
+	}
+	if v.config.Audience != "" && !hasAudience(claims["aud"], v.config.Audience) {
+		return fmt.Errorf("%w: 対象者が一致しません", ErrInvalidToken)
+	}
+	return nil
+}
+
+// algorithm is a supported signing algorithm
+// サポートする署名アルゴリズム
+type algorithm struct {
+	kind string // HS・RS・ES
+	hash crypto.Hash
+}
+
+var algorithms = map[string]algorithm{
+	"HS256": {"HS", crypto.SHA256},
+	"HS384": {"HS", crypto.SHA384},
+	"HS512": {"HS", crypto.SHA512},
+	"RS256": {"RS", crypto.SHA256},
+	"RS384": {"RS", crypto.SHA384},
+	"RS512": {"RS", crypto.SHA512},
+	"ES256": {"ES", crypto.SHA256},
+	"ES384": {"ES", crypto.SHA384},
+	"ES512": {"ES", crypto.SHA512},
+}
+
+// decodeSegment decodes a base64url JSON segment keeping numbers as json.Number
+// base64urlのJSONセグメントを数値をjson.Numberのままデコード
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// numericDate returns a NumericDate claim as a time
+// NumericDate形式のクレームを時刻として返す
+func numericDate(claims map[string]interface{}, name string) (time.Time, bool, error) {
+	raw, ok := claims[name]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	number, ok := raw.(json.Number)
+	if !ok {
+		return time.Time{}, false, fmt.Errorf("%w: %s が数値ではありません", ErrInvalidToken, name)
+	}
+	seconds, err := number.Float64()
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%w: %s が数値ではありません", ErrInvalidToken, name)
+	}
+	return time.Unix(0, int64(seconds*float64(time.Second))), true, nil
+}
+
+// hasAudience reports whether the aud claim (a string or an array) contains the audience
+// aud クレーム（文字列または配列）が対象者を含むかを返す
+func hasAudience(aud interface{}, audience string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == audience
+	case []interface{}:
+		for _, a := range aud {
+			if a == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// scopes returns the scopes of the scope (space separated) or scp (array) claim
+// scope（空白区切り）または scp（配列）クレームのスコープを返す
+func scopes(claims map[string]interface{}) []string {
+	if scope, ok := claims["scope"].(string); ok {
+		return strings.Fields(scope)
+	}
+	var result []string
+	if scp, ok := claims["scp"].([]interface{}); ok {
+		for _, s := range scp {
+			if s, ok := s.(string); ok {
+				result = append(result, s)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,208 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// signToken はテスト用にJWTを作成する
+func signToken(t *testing.T, header, claims map[string]interface{}, sign func(signed []byte) []byte) string {
+	t.Helper()
+
+	h, err := json.Marshal(header)
+	require.NoError(t, err)
+	c, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signed := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sign([]byte(signed)))
+}
+
+func hs256(secret string) func([]byte) []byte {
+	return func(signed []byte) []byte {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(signed)
+		return mac.Sum(nil)
+	}
+}
+
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"sub":   "user-1",
+		"iss":   "https://issuer.example.com/",
+		"aud":   []string{"inventory-api", "other"},
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": "inventory:read inventory:write",
+	}
+}
+
+// TestJWTVerifier_HMAC は共有鍵で署名したトークンのクレームの検証のテスト
+func TestJWTVerifier_HMAC(t *testing.T) {
+	verifier, err := NewJWTVerifier(JWTConfig{
+		Issuer:     "https://issuer.example.com/",
+		Audience:   "inventory-api",
+		HMACSecret: "secret",
+		Leeway:     time.Second,
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+	header := map[string]interface{}{"alg": "HS256", "typ": "JWT"}
+
+	user, err := verifier.Verify(ctx, signToken(t, header, validClaims(), hs256("secret")))
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", user.ID)
+	assert.Equal(t, []string{"inventory:read", "inventory:write"}, user.Scopes)
+	assert.True(t, user.HasScope("inventory:write"))
+	assert.False(t, user.HasScope("admin"))
+
+	tests := []struct {
+		name   string
+		header map[string]interface{}
+		modify func(claims map[string]interface{})
+		secret string
+	}{
+		{"署名の不一致", header, func(map[string]interface{}) {}, "other"},
+		{"alg none", map[string]interface{}{"alg": "none"}, func(map[string]interface{}) {}, "secret"},
+		{"有効期限切れ", header, func(c map[string]interface{}) { c["exp"] = time.Now().Add(-time.Minute).Unix() }, "secret"},
+		{"有効期限なし", header, func(c map[string]interface{}) { delete(c, "exp") }, "secret"},
+		{"有効開始前", header, func(c map[string]interface{}) { c["nbf"] = time.Now().Add(time.Minute).Unix() }, "secret"},
+		{"発行者の不一致", header, func(c map[string]interface{}) { c["iss"] = "https://evil.example.com/" }, "secret"},
+		{"対象者の不一致", header, func(c map[string]interface{}) { c["aud"] = "other" }, "secret"},
+		{"ユーザーIDなし", header, func(c map[string]interface{}) { delete(c, "sub") }, "secret"},
+		{"公開鍵のアルゴリズム", map[string]interface{}{"alg": "RS256"}, func(map[string]interface{}) {}, "secret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims()
+			tt.modify(claims)
+			_, err := verifier.Verify(ctx, signToken(t, tt.header, claims, hs256(tt.secret)))
+			assert.ErrorIs(t, err, ErrInvalidToken)
+		})
+	}
+
+	_, err = verifier.Verify(ctx, "not-a-token")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+// TestJWTVerifier_JWKS はJWKSの公開鍵で署名を検証するテスト
+func TestJWTVerifier_JWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	var fetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]interface{}{
+			{
+				"kty": "RSA", "kid": "rsa-1", "use": "sig", "alg": "RS256",
+				"n": base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+				"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()),
+			},
+			{
+				"kty": "EC", "kid": "ec-1", "crv": "P-256",
+				"x": base64.RawURLEncoding.EncodeToString(ecKey.X.FillBytes(make([]byte, 32))),
+				"y": base64.RawURLEncoding.EncodeToString(ecKey.Y.FillBytes(make([]byte, 32))),
+			},
+			{"kty": "RSA", "kid": "enc-1", "use": "enc", "n": "AQAB", "e": "AQAB"},
+		}})
+	}))
+	defer server.Close()
+
+	verifier, err := NewJWTVerifier(JWTConfig{JWKSURL: server.URL, UserClaim: "email"})
+	require.NoError(t, err)
+	ctx := context.Background()
+	claims := validClaims()
+	claims["email"] = "user@example.com"
+
+	signRSA := func(signed []byte) []byte {
+		digest := sha256.Sum256(signed)
+		sig, err := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+		return sig
+	}
+	signEC := func(signed []byte) []byte {
+		digest := sha256.Sum256(signed)
+		r, s, err := ecdsa.Sign(rand.Reader, ecKey, digest[:])
+		require.NoError(t, err)
+		return append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+
+	user, err := verifier.Verify(ctx, signToken(t, map[string]interface{}{"alg": "RS256", "kid": "rsa-1"}, claims, signRSA))
+	require.NoError(t, err)
+	assert.Equal(t, "user@example.com", user.ID)
+
+	user, err = verifier.Verify(ctx, signToken(t, map[string]interface{}{"alg": "ES256", "kid": "ec-1"}, claims, signEC))
+	require.NoError(t, err)
+	assert.Equal(t, "user@example.com", user.ID)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches), "JWKSはキャッシュする")
+
+	// 鍵とアルゴリズムの組み合わせの誤り
+	_, err = verifier.Verify(ctx, signToken(t, map[string]interface{}{"alg": "ES256", "kid": "rsa-1"}, claims, signEC))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = verifier.Verify(ctx, signToken(t, map[string]interface{}{"alg": "RS256", "kid": "ec-1"}, claims, signRSA))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	// 共有鍵が設定されていない場合のHS256
+	_, err = verifier.Verify(ctx, signToken(t, map[string]interface{}{"alg": "HS256", "kid": "rsa-1"}, claims, hs256("")))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	// 未知の鍵IDは直前に取得した場合は再取得しない
+	_, err = verifier.Verify(ctx, signToken(t, map[string]interface{}{"alg": "RS256", "kid": "unknown"}, claims, signRSA))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	// 暗号化用の鍵は使用しない
+	_, err = verifier.Verify(ctx, signToken(t, map[string]interface{}{"alg": "RS256", "kid": "enc-1"}, claims, signRSA))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+}
+
+// TestBearerToken はAuthorizationヘッダーの解析のテスト
+func TestBearerToken(t *testing.T) {
+	for header, want := range map[string]string{
+		"Bearer abc.def.ghi": "abc.def.ghi",
+		"bearer  token ":     "token",
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Authorization", header)
+		token, err := BearerToken(r)
+		require.NoError(t, err, header)
+		assert.Equal(t, want, token)
+	}
+
+	for _, header := range []string{"", "Basic dXNlcjpwYXNz", "Bearer", "Bearer  "} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Authorization", header)
+		_, err := BearerToken(r)
+		assert.ErrorIs(t, err, ErrNoCredentials, header)
+	}
+}
+
+// TestWithUser はコンテキストでのユーザーの受け渡しのテスト
+func TestWithUser(t *testing.T) {
+	ctx := WithUser(context.Background(), &User{ID: "user-1"})
+
+	user, ok := UserFromContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, "user-1", user.ID)
+	// 在庫マネージャーが操作者として参照するキー
+	assert.Equal(t, "user-1", ctx.Value("user_id"))
+
+	_, ok = UserFromContext(context.Background())
+	assert.False(t, ok)
+}
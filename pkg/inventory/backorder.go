@@ -0,0 +1,304 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var _ BackorderManager = (*Manager)(nil)
+
+// backorderPageSize is the number of pending backorders read at a time when allocating
+// 引当時に一度に読み取る入荷待ちのバックオーダーの件数
+const backorderPageSize = 100
+
+// backorderKey is the context key that requests a backorder on insufficient stock
+// 在庫不足の場合にバックオーダーの作成を要求するコンテキストキー
+type backorderKey struct{}
+
+// WithBackorder returns a context that makes Remove and Reserve create a backorder when stock is insufficient
+// 在庫不足の場合に Remove・Reserve がバックオーダーを作成するコンテキストを返す
+//
+// Config.EnableBackorders が true の場合のみ有効です。バックオーダーを作成した場合、操作は
+// *BackorderedError（errors.Is(err, ErrInsufficientStock) は true）を返します。
+func WithBackorder(ctx context.Context) context.Context {
+	return context.WithValue(ctx, backorderKey{}, true)
+}
+
+// backorderRequested reports whether the context requests a backorder on insufficient stock
+// コンテキストがバックオーダーの作成を要求しているか
+func backorderRequested(ctx context.Context) bool {
+	requested, _ := ctx.Value(backorderKey{}).(bool)
+	return requested
+}
+
+// GetBackorder retrieves a backorder by ID
+// IDでバックオーダーを取得
+func (m *Manager) GetBackorder(ctx context.Context, backorderID string) (*Backorder, error) {
+	if backorderID == "" {
+		return nil, NewValidationError("backorder_id", "バックオーダーIDが指定されていません", "")
+	}
+
+	backorder, err := m.storage.GetBackorder(ctx, backorderID)
+	if err != nil {
+		if errors.Is(err, ErrBackorderNotFound) {
+			return nil, ErrBackorderNotFound
+		}
+		return nil, NewStorageError("get_backorder", "バックオーダーの取得に失敗しました", err)
+	}
+	return backorder, nil
+}
+
+// ListBackorders lists backorders matching a filter, oldest first
+// 条件に一致するバックオーダーを作成日時の昇順（引当の順）で取得
+func (m *Manager) ListBackorders(ctx context.Context, filter BackorderFilter) ([]Backorder, error) {
+	if err := validateOffsetLimit(filter.Offset, filter.Limit); err != nil {
+		return nil, err
+	}
+	switch filter.Status {
+	case "", BackorderStatusPending, BackorderStatusAllocated, BackorderStatusCancelled:
+	default:
+		return nil, NewValidationError("status", "バックオーダーの状態が正しくありません", string(filter.Status))
+	}
+
+	backorders, err := m.storage.ListBackorders(ctx, filter)
+	if err != nil {
+		return nil, NewStorageError("list_backorders", "バックオーダー一覧の取得に失敗しました", err)
+	}
+	return backorders, nil
+}
+
+// CancelBackorder cancels a pending backorder
+// 入荷待ちのバックオーダーを取り消す
+//
+// 引当済み・取消済みのバックオーダーは ErrBackorderNotPending を返します。
+// 引当済みのバックオーダーを取り消す場合は、作成された予約を解除してください。
+func (m *Manager) CancelBackorder(ctx context.Context, backorderID string) (_ *Backorder, err error) {
+	ctx, finish := m.startOperation(ctx, "cancel_backorder", attrBackorderID.String(backorderID))
+	defer finish(&err)
+
+	backorder, err := m.GetBackorder(ctx, backorderID)
+	if err != nil {
+		return nil, err
+	}
+	if backorder.Status != BackorderStatusPending {
+		return nil, ErrBackorderNotPending
+	}
+
+	now := time.Now()
+	backorder.Status = BackorderStatusCancelled
+	backorder.ClosedAt = &now
+	if err := m.storage.UpdateBackorder(ctx, backorder); err != nil {
+		if errors.Is(err, ErrBackorderNotPending) {
+			return nil, ErrBackorderNotPending
+		}
+		return nil, NewStorageError("update_backorder", "バックオーダーの更新に失敗しました", err)
+	}
+
+	m.log(ctx).Info("バックオーダー取消完了",
+		zap.String("backorder_id", backorder.ID),
+		zap.String("item_id", backorder.ItemID),
+		zap.String("location_id", backorder.LocationID),
+		zap.Int64("quantity", backorder.Quantity),
+	)
+	m.publishEvent(ctx, EventTypeBackorderCancelled, backorder.ItemID, backorder.LocationID, *backorder)
+
+	return backorder, nil
+}
+
+// AllocateBackorders allocates available stock to the pending backorders of a stock record
+// 在庫記録の利用可能数を入荷待ちのバックオーダーに引き当て、引き当てたバックオーダーを返す
+//
+// 古いバックオーダーから順に、数量の全てを予約として確保します。利用可能数が次のバックオーダーの数量に
+// 満たない場合は、順番を守るためそこで引当を終了します（後続の小さなバックオーダーも引き当てません）。
+// Config.EnableBackorders が true の場合、Add は入荷後にこの処理を自動で実行します。
+func (m *Manager) AllocateBackorders(ctx context.Context, itemID, locationID string) (_ []Backorder, err error) {
+	ctx, finish := m.startOperation(ctx, "allocate_backorders", attrItemID.String(itemID), attrLocationID.String(locationID))
+	defer finish(&err)
+
+	allocated := make([]Backorder, 0)
+	for {
+		pending, err := m.storage.ListBackorders(ctx, BackorderFilter{
+			ItemID:     itemID,
+			LocationID: locationID,
+			Status:     BackorderStatusPending,
+			Limit:      backorderPageSize,
+		})
+		if err != nil {
+			return allocated, NewStorageError("list_backorders", "バックオーダー一覧の取得に失敗しました", err)
+		}
+
+		for i := range pending {
+			ok, err := m.allocateBackorder(ctx, &pending[i])
+			if err != nil {
+				return allocated, err
+			}
+			if !ok {
+				return allocated, nil
+			}
+			allocated = append(allocated, pending[i])
+		}
+		if len(pending) < backorderPageSize {
+			return allocated, nil
+		}
+	}
+}
+
+// allocateBackorder reserves the stock of a pending backorder, reporting false when stock is insufficient
+// 入荷待ちのバックオーダーの数量を予約として確保（利用可能数が不足する場合は false を返す）
+//
+// 予約の作成とバックオーダーの更新は一つのトランザクションで行います。
+// 同時に引当・取消されたバックオーダーはスキップします（true を返し、backorder は更新しません）。
+func (m *Manager) allocateBackorder(ctx context.Context, backorder *Backorder) (bool, error) {
+	now := time.Now()
+	reservation := &Reservation{
+		ID:         NewReservationID(),
+		ItemID:     backorder.ItemID,
+		LocationID: backorder.LocationID,
+		Quantity:   backorder.Quantity,
+		Reference:  backorder.Reference,
+		Status:     ReservationStatusActive,
+		CreatedAt:  now,
+		CreatedBy:  m.getUserFromContext(ctx),
+	}
+
+	var (
+		stock   *Stock
+		tx      *Transaction
+		skipped bool
+		updated Backorder
+	)
+	err := m.withReservationTx(ctx, func(lm *Manager) (err error) {
+		skipped = false
+		stock, tx, err = lm.holdReservation(ctx, reservation, now)
+		if err != nil {
+			return err
+		}
+
+		updated = *backorder
+		updated.Status = BackorderStatusAllocated
+		updated.ReservationID = reservation.ID
+		updated.ClosedAt = &now
+		if err := lm.storage.UpdateBackorder(ctx, &updated); err != nil {
+			if errors.Is(err, ErrBackorderNotPending) {
+				// 予約を作成しないようロールバックする
+				skipped = true
+				return ErrBackorderNotPending
+			}
+			return NewStorageError("update_backorder", "バックオーダーの更新に失敗しました", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrInsufficientStock) || errors.Is(err, ErrStockNotFound) {
+			return false, nil
+		}
+		if skipped {
+			return true, nil
+		}
+		return false, err
+	}
+	*backorder = updated
+
+	m.publishReservationCreated(ctx, reservation, stock, tx)
+	m.log(ctx).Info("バックオーダー引当完了",
+		zap.String("backorder_id", backorder.ID),
+		zap.String("reservation_id", reservation.ID),
+		zap.String("item_id", backorder.ItemID),
+		zap.String("location_id", backorder.LocationID),
+		zap.Int64("quantity", backorder.Quantity),
+	)
+	m.publishEvent(ctx, EventTypeBackorderAllocated, backorder.ItemID, backorder.LocationID, *backorder)
+
+	return true, nil
+}
+
+// allocateArrivedStock allocates stock that arrived through Add to pending backorders
+// Add で入荷した在庫を入荷待ちのバックオーダーに引き当てる
+//
+// 入荷は完了しているため、引当の失敗はログに記録するのみで Add の結果には影響しません。
+func (m *Manager) allocateArrivedStock(ctx context.Context, itemID, locationID string) {
+	if !m.config.EnableBackorders {
+		return
+	}
+	if _, err := m.AllocateBackorders(ctx, itemID, locationID); err != nil {
+		m.log(ctx).Error("バックオーダーの引当に失敗しました",
+			zap.String("item_id", itemID),
+			zap.String("location_id", locationID),
+			zap.Error(err),
+		)
+	}
+}
+
+// allocateBatchArrivals allocates the stock added by an atomic batch to pending backorders after it commits
+// アトミックなバッチで追加した在庫を、コミット後に入荷待ちのバックオーダーに引き当てる
+func (m *Manager) allocateBatchArrivals(ctx context.Context, operations []InventoryOperation) {
+	if !m.config.EnableBackorders {
+		return
+	}
+	allocated := make(map[string]bool)
+	for _, op := range operations {
+		if op.Type != OperationTypeAdd {
+			continue
+		}
+		key := op.ItemID + "\x00" + op.LocationID
+		if allocated[key] {
+			continue
+		}
+		allocated[key] = true
+		m.allocateArrivedStock(ctx, op.ItemID, op.LocationID)
+	}
+}
+
+// withoutBackorders returns a copy of the manager that neither creates nor allocates backorders
+// バックオーダーの作成・引当を行わないマネージャーのコピーを返す
+//
+// 単一トランザクションで実行する操作（アトミックなバッチ・ドライラン）に使用します。
+// 引当はトランザクションのコミット後に行う必要があり、作成したバックオーダーはロールバックで失われるためです。
+func (m *Manager) withoutBackorders() *Manager {
+	if !m.config.EnableBackorders {
+		return m
+	}
+	scoped := *m
+	config := *m.config
+	config.EnableBackorders = false
+	scoped.config = &config
+	return &scoped
+}
+
+// backorderOnShortage creates a backorder when an operation failed for lack of stock and one was requested
+// 在庫不足で操作が失敗し、バックオーダーが要求されている場合にバックオーダーを作成
+//
+// バックオーダーを作成した場合は *BackorderedError を、それ以外は opErr をそのまま返します。
+func (m *Manager) backorderOnShortage(ctx context.Context, itemID, locationID string, quantity int64, reference string, opErr error) error {
+	if !m.config.EnableBackorders || !backorderRequested(ctx) || !errors.Is(opErr, ErrInsufficientStock) {
+		return opErr
+	}
+
+	backorder := &Backorder{
+		ID:         NewBackorderID(),
+		ItemID:     itemID,
+		LocationID: locationID,
+		Quantity:   quantity,
+		Reference:  reference,
+		Status:     BackorderStatusPending,
+		CreatedAt:  time.Now(),
+		CreatedBy:  m.getUserFromContext(ctx),
+	}
+	if err := m.storage.CreateBackorder(ctx, backorder); err != nil {
+		return NewStorageError("create_backorder", "バックオーダーの作成に失敗しました", err)
+	}
+
+	m.log(ctx).Info("在庫不足のためバックオーダーを作成しました",
+		zap.String("backorder_id", backorder.ID),
+		zap.String("item_id", itemID),
+		zap.String("location_id", locationID),
+		zap.Int64("quantity", quantity),
+		zap.String("reference", reference),
+	)
+	m.publishEvent(ctx, EventTypeBackorderCreated, itemID, locationID, *backorder)
+
+	return &BackorderedError{Backorder: backorder, Cause: opErr}
+}
@@ -0,0 +1,251 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Errors of the asynchronous batch queue - 非同期バッチのキューのエラー
+var (
+	// ErrBatchQueueFull is returned when the queue has no room for another batch
+	// 待ちキューが満杯でバッチを受け付けられない場合のエラー
+	ErrBatchQueueFull = errors.New("バッチの待ちキューが満杯です")
+
+	// ErrBatchQueueClosed is returned when submitting to a closed queue
+	// 停止済みのキューにバッチを登録した場合のエラー
+	ErrBatchQueueClosed = errors.New("バッチのキューは停止しています")
+
+	// ErrBatchNotCancellable is returned when a batch has finished or is not run by this queue
+	// 完了済み、またはこのキューで実行していないバッチを取り消そうとした場合のエラー
+	ErrBatchNotCancellable = errors.New("バッチ操作を取り消せません（完了済み、または他のインスタンスで実行中）")
+)
+
+// BatchQueueConfig holds settings for the asynchronous batch queue
+// 非同期バッチのキューの設定
+type BatchQueueConfig struct {
+	Workers          int // 並行して実行するバッチ数（0以下の場合は2）
+	QueueSize        int // 実行待ちのバッチ数の上限（0以下の場合は100、超えた場合は ErrBatchQueueFull）
+	ProgressInterval int // 進捗を保存する操作数の間隔（0以下の場合は100）
+}
+
+// batchJob is a batch submitted to the queue
+// キューに登録されたバッチ
+type batchJob struct {
+	batch   *BatchOperation
+	ctx     context.Context
+	cancel  context.CancelFunc
+	started bool          // ワーカーが実行を開始したか（mu で保護）
+	done    chan struct{} // 実行が終了したら閉じる
+}
+
+// BatchQueue executes batches of inventory operations in the background
+// 在庫操作のバッチをバックグラウンドで実行するキュー
+//
+// 数千件の操作を含むバッチを HTTP リクエストの時間内に実行せずに済むよう、Submit はバッチを
+// pending として保存して直ちに返し、ワーカーが実行します。実行中は ProgressInterval 件ごとに
+// 進捗（Progress・操作ごとの結果）を保存するため、GetBatchStatus で状況を確認できます。
+// アトミックなバッチは単一トランザクションで実行するため、進捗は完了時にのみ更新されます。
+//
+// 実行待ち・実行中のバッチはプロセス内で管理するため、取り消しは実行しているインスタンスでのみ行えます。
+type BatchQueue struct {
+	manager *Manager
+	cfg     BatchQueueConfig
+
+	mu     sync.Mutex
+	jobs   map[string]*batchJob // 実行待ち・実行中のバッチ
+	closed bool
+	queue  chan *batchJob
+	wg     sync.WaitGroup
+}
+
+// NewBatchQueue creates a batch queue and starts its workers
+// 非同期バッチのキューを作成し、ワーカーを起動
+func NewBatchQueue(manager *Manager, cfg BatchQueueConfig) *BatchQueue {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 2
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 100
+	}
+	if cfg.ProgressInterval <= 0 {
+		cfg.ProgressInterval = 100
+	}
+
+	q := &BatchQueue{
+		manager: manager,
+		cfg:     cfg,
+		jobs:    make(map[string]*batchJob),
+		queue:   make(chan *batchJob, cfg.QueueSize),
+	}
+	for i := 0; i < cfg.Workers; i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+	return q
+}
+
+// Submit saves a pending batch and queues it for execution
+// バッチを pending として保存し、実行待ちのキューに登録
+//
+// 返却するバッチは登録時点の状態です。結果は GetBatchStatus で取得してください。
+func (q *BatchQueue) Submit(ctx context.Context, operations []InventoryOperation, atomic bool) (*BatchOperation, error) {
+	batch := newBatchOperation(operations, atomic)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return nil, ErrBatchQueueClosed
+	}
+	if len(q.queue) == cap(q.queue) {
+		return nil, ErrBatchQueueFull
+	}
+	if err := q.manager.storage.SaveBatchOperation(ctx, batch); err != nil {
+		return nil, NewStorageError("save_batch_operation", "バッチ操作の保存に失敗しました", err)
+	}
+
+	// 登録元のリクエストが終了しても実行を継続する（リクエストIDなどの値は引き継ぐ）
+	jobCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	job := &batchJob{batch: copyBatchOperation(batch), ctx: jobCtx, cancel: cancel, done: make(chan struct{})}
+	q.jobs[batch.ID] = job
+	// 満杯でないことを確認済みで、送信はロック内で行うため待機しない
+	q.queue <- job
+
+	q.manager.log(ctx).Info("非同期バッチを登録しました",
+		zap.String("batch_id", batch.ID),
+		zap.Int("operations", len(operations)),
+		zap.Bool("atomic", atomic),
+	)
+	return batch, nil
+}
+
+// Cancel stops a queued or running batch and returns its final state
+// 実行待ち・実行中のバッチを取り消し、最終状態を返す
+//
+// 実行中の操作は完了を待ち、残りの操作を cancelled として記録します（実行済みの操作は取り消しません）。
+// アトミックなバッチはトランザクションをロールバックします。
+// 完了済み、または他のインスタンスで実行中のバッチは ErrBatchNotCancellable を返します。
+func (q *BatchQueue) Cancel(ctx context.Context, batchID string) (*BatchOperation, error) {
+	q.mu.Lock()
+	job, ok := q.jobs[batchID]
+	if !ok {
+		q.mu.Unlock()
+		if _, err := q.manager.GetBatchStatus(ctx, batchID); err != nil {
+			return nil, err
+		}
+		return nil, ErrBatchNotCancellable
+	}
+	job.cancel()
+	if !job.started {
+		// 実行前のバッチはワーカーを待たずに取り消す
+		delete(q.jobs, batchID)
+		q.mu.Unlock()
+		job.batch.cancelFrom(0)
+		q.manager.finishBatch(ctx, job.batch)
+		close(job.done)
+		return copyBatchOperation(job.batch), nil
+	}
+	q.mu.Unlock()
+
+	select {
+	case <-job.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return q.manager.GetBatchStatus(ctx, batchID)
+}
+
+// Close stops accepting batches, cancels the queued and running ones and waits for the workers
+// バッチの受付を停止し、実行待ち・実行中のバッチを取り消してワーカーの終了を待機
+func (q *BatchQueue) Close() error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.queue)
+		for _, job := range q.jobs {
+			job.cancel()
+		}
+	}
+	q.mu.Unlock()
+
+	q.wg.Wait()
+	return nil
+}
+
+// work executes queued batches until the queue is closed
+// キューが閉じられるまで実行待ちのバッチを実行
+func (q *BatchQueue) work() {
+	defer q.wg.Done()
+
+	for job := range q.queue {
+		q.mu.Lock()
+		if _, ok := q.jobs[job.batch.ID]; !ok {
+			// 実行前に取り消された
+			q.mu.Unlock()
+			continue
+		}
+		job.started = true
+		q.mu.Unlock()
+
+		q.run(job)
+
+		q.mu.Lock()
+		delete(q.jobs, job.batch.ID)
+		q.mu.Unlock()
+		close(job.done)
+	}
+}
+
+// run executes a batch, saving its progress as operations finish
+// バッチを実行し、操作の終了に応じて進捗を保存
+func (q *BatchQueue) run(job *batchJob) {
+	ctx, batch, m := job.ctx, job.batch, q.manager
+	defer job.cancel()
+
+	batch.Status = BatchStatusRunning
+	m.saveBatchProgress(ctx, batch)
+
+	if batch.Atomic {
+		if err := m.runBatchAtomic(ctx, batch); err != nil {
+			m.log(ctx).Error("非同期バッチの実行に失敗しました", zap.String("batch_id", batch.ID), zap.Error(err))
+			m.saveCompletedBatch(context.WithoutCancel(ctx), batch)
+			return
+		}
+	} else {
+		finished := 0
+		m.runBatch(ctx, batch, func(batch *BatchOperation) {
+			if finished++; finished%q.cfg.ProgressInterval == 0 {
+				m.saveBatchProgress(ctx, batch)
+			}
+		})
+	}
+	m.finishBatch(ctx, batch)
+
+	m.log(ctx).Info("非同期バッチ実行完了",
+		zap.String("batch_id", batch.ID),
+		zap.String("status", string(batch.Status)),
+		zap.Int("progress", batch.Progress),
+	)
+}
+
+// saveBatchProgress saves the state of a running batch
+// 実行中のバッチの状態を保存
+//
+// 進捗の保存に失敗しても実行は継続します（完了時に最終状態を保存します）。
+func (m *Manager) saveBatchProgress(ctx context.Context, batch *BatchOperation) {
+	if err := m.storage.SaveBatchOperation(context.WithoutCancel(ctx), batch); err != nil {
+		m.log(ctx).Warn("バッチ操作の進捗の保存に失敗しました", zap.String("batch_id", batch.ID), zap.Error(err))
+	}
+}
+
+// copyBatchOperation copies a batch so the caller and the worker do not share its slices
+// 呼び出し元とワーカーがスライスを共有しないようバッチをコピー
+func copyBatchOperation(batch *BatchOperation) *BatchOperation {
+	clone := *batch
+	clone.Operations = append([]InventoryOperation(nil), batch.Operations...)
+	clone.Errors = append(make([]BatchOperationError, 0, len(batch.Errors)), batch.Errors...)
+	clone.Results = append(make([]BatchOperationResult, 0, len(batch.Results)), batch.Results...)
+	return &clone
+}
@@ -0,0 +1,107 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// capacityPolicy returns the configured capacity policy, defaulting to warn
+// 設定された容量超過時の扱いを返す（未設定の場合は warn）
+func (m *Manager) capacityPolicy() CapacityPolicy {
+	if m.config.CapacityPolicy == "" {
+		return CapacityPolicyWarn
+	}
+	return m.config.CapacityPolicy
+}
+
+// enforceCapacity rejects adding quantity to a location when it would exceed the location's capacity
+// ロケーションに数量を追加すると容量を超える場合に ErrLocationCapacityExceeded を返す
+//
+// CapacityPolicyEnforce の場合のみ確認します。容量が0のロケーションは無制限として扱います。
+func (m *Manager) enforceCapacity(ctx context.Context, location *Location, quantity int64) error {
+	if m.capacityPolicy() != CapacityPolicyEnforce || location.Capacity <= 0 {
+		return nil
+	}
+
+	// レプリカの遅延で容量を超えないようプライマリから読み取る
+	total, err := m.storage.GetTotalStockByLocation(WithPrimaryRead(ctx), location.ID)
+	if err != nil {
+		return NewStorageError("get_total_stock_by_location", "ロケーション合計在庫数の取得に失敗しました", err)
+	}
+	if total+quantity > location.Capacity {
+		m.log(ctx).Warn("ロケーションの容量を超えるため操作を拒否しました",
+			zap.String("location_id", location.ID),
+			zap.Int64("capacity", location.Capacity),
+			zap.Int64("current", total),
+			zap.Int64("quantity", quantity),
+		)
+		return ErrLocationCapacityExceeded
+	}
+	return nil
+}
+
+// checkOverStock triggers over stock alerts after stock arrived at a location
+// 入荷・移動後に過剰在庫アラートを発生
+//
+// 在庫数量が発注点の最大在庫数（MaxQty）を超えた場合と、CapacityPolicyWarn でロケーションの合計在庫数が
+// 容量を超えた場合にアラートを作成します。在庫操作は完了しているため、確認の失敗はログに記録するのみです。
+func (m *Manager) checkOverStock(ctx context.Context, location *Location, stock *Stock) {
+	reorderPoint, err := m.storage.GetReorderPoint(ctx, stock.ItemID, stock.LocationID)
+	switch {
+	case err == nil:
+		if reorderPoint.MaxQty > 0 && stock.Quantity > reorderPoint.MaxQty {
+			message := fmt.Sprintf("商品 %s のロケーション %s での在庫が最大在庫数を超えています (現在: %d, 最大在庫数: %d)",
+				stock.ItemID, stock.LocationID, stock.Quantity, reorderPoint.MaxQty)
+			m.triggerOverStockAlert(ctx, stock.ItemID, stock.LocationID, stock.Quantity, reorderPoint.MaxQty, message)
+		}
+	case !errors.Is(err, ErrReorderPointNotFound):
+		m.log(ctx).Warn("発注点の取得に失敗しました",
+			zap.String("item_id", stock.ItemID),
+			zap.String("location_id", stock.LocationID),
+			zap.Error(err),
+		)
+	}
+
+	if m.capacityPolicy() != CapacityPolicyWarn || location.Capacity <= 0 {
+		return
+	}
+	total, err := m.storage.GetTotalStockByLocation(ctx, location.ID)
+	if err != nil {
+		m.log(ctx).Warn("ロケーション合計在庫数の取得に失敗しました",
+			zap.String("location_id", location.ID),
+			zap.Error(err),
+		)
+		return
+	}
+	if total > location.Capacity {
+		message := fmt.Sprintf("ロケーション %s の在庫が容量を超えています (現在: %d, 容量: %d)", location.ID, total, location.Capacity)
+		m.triggerOverStockAlert(ctx, stock.ItemID, location.ID, total, location.Capacity, message)
+	}
+}
+
+// triggerOverStockAlert creates an over stock alert and publishes its event
+// 過剰在庫アラートを作成し、イベントを発行
+func (m *Manager) triggerOverStockAlert(ctx context.Context, itemID, locationID string, currentQty, threshold int64, message string) {
+	alert := &StockAlert{
+		ID:         NewTransactionID(),
+		Type:       AlertTypeOverStock,
+		ItemID:     itemID,
+		LocationID: locationID,
+		CurrentQty: currentQty,
+		Threshold:  threshold,
+		Message:    message,
+		IsActive:   true,
+		CreatedAt:  time.Now(),
+		Severity:   AlertSeverityWarning,
+	}
+
+	if err := m.storage.CreateAlert(ctx, alert); err != nil {
+		m.log(ctx).Error("アラート作成に失敗しました", zap.Error(err))
+		return
+	}
+	m.publishEvent(ctx, EventTypeAlertCreated, itemID, locationID, alert)
+}
@@ -0,0 +1,209 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var _ ChannelManager = (*Manager)(nil)
+
+// SetChannelAllocation sets the quantity of the stock of an item at a location earmarked for a sales channel
+// 商品のロケーションの在庫を販売チャネル向けに確保する数量を設定
+//
+// ItemID・LocationID・Channel・Quantity を指定します。数量が0の場合は確保を削除します。
+// 確保数量は在庫数量を超えて設定できますが、チャネルが予約できる数量は利用可能数量までです。
+func (m *Manager) SetChannelAllocation(ctx context.Context, allocation *ChannelAllocation) (err error) {
+	ctx, finish := m.startOperation(ctx, "set_channel_allocation", attrItemID.String(allocation.ItemID), attrLocationID.String(allocation.LocationID), attrQuantity.Int64(allocation.Quantity))
+	defer finish(&err)
+
+	if err := ValidateChannelAllocation(allocation); err != nil {
+		return err
+	}
+	if _, _, err := m.validateItemAndLocation(ctx, allocation.ItemID, allocation.LocationID); err != nil {
+		return err
+	}
+
+	allocation.UpdatedAt = time.Now()
+	allocation.UpdatedBy = m.getUserFromContext(ctx)
+	if err := m.storage.SetChannelAllocation(ctx, allocation); err != nil {
+		return NewStorageError("set_channel_allocation", "販売チャネルの確保の設定に失敗しました", err)
+	}
+
+	m.log(ctx).Info("販売チャネルの確保を設定しました",
+		zap.String("item_id", allocation.ItemID),
+		zap.String("location_id", allocation.LocationID),
+		zap.String("channel", allocation.Channel),
+		zap.Int64("quantity", allocation.Quantity),
+	)
+	return nil
+}
+
+// ListChannelAllocations lists the sales channel allocations of an item at a location ordered by channel
+// 商品のロケーションの販売チャネルの確保をチャネル名の昇順で取得
+func (m *Manager) ListChannelAllocations(ctx context.Context, itemID, locationID string) ([]ChannelAllocation, error) {
+	if err := ValidateItemID(itemID); err != nil {
+		return nil, err
+	}
+	if err := ValidateLocationID(locationID); err != nil {
+		return nil, err
+	}
+
+	allocations, err := m.storage.ListChannelAllocations(ctx, itemID, locationID)
+	if err != nil {
+		return nil, NewStorageError("list_channel_allocations", "販売チャネルの確保の取得に失敗しました", err)
+	}
+	return allocations, nil
+}
+
+// GetChannelAvailability splits the available stock of an item at a location by sales channel
+// 商品のロケーションの利用可能な在庫を販売チャネルごとに分けて取得
+//
+// 在庫の記録がない場合は数量0の内訳を返します。
+func (m *Manager) GetChannelAvailability(ctx context.Context, itemID, locationID string) (*StockChannels, error) {
+	if err := ValidateItemID(itemID); err != nil {
+		return nil, err
+	}
+	if err := ValidateLocationID(locationID); err != nil {
+		return nil, err
+	}
+
+	stock, err := m.storage.GetStock(ctx, itemID, locationID)
+	switch {
+	case errors.Is(err, ErrStockNotFound):
+		stock = &Stock{ItemID: itemID, LocationID: locationID}
+	case err != nil:
+		return nil, NewStorageError("get_stock", "在庫取得に失敗しました", err)
+	}
+	return m.stockChannels(ctx, stock)
+}
+
+// stockChannels splits the available quantity of a stock record by sales channel
+// 在庫記録の利用可能数量を販売チャネルごとに分ける
+//
+// チャネルの確保数量のうち、チャネルの有効な予約で使用していない数量は他の予約では使用できません。
+// 利用可能数量からその合計を除いた数量が共有の利用可能数量で、各チャネルは確保数量の残りと共有の利用可能数量を予約できます。
+func (m *Manager) stockChannels(ctx context.Context, stock *Stock) (*StockChannels, error) {
+	channels := &StockChannels{
+		ItemID:          stock.ItemID,
+		LocationID:      stock.LocationID,
+		Quantity:        stock.Quantity,
+		Reserved:        stock.Reserved,
+		Available:       stock.Available,
+		SharedAvailable: nonNegative(stock.Available),
+		Channels:        []ChannelAvailability{},
+	}
+
+	allocations, err := m.storage.ListChannelAllocations(ctx, stock.ItemID, stock.LocationID)
+	if err != nil {
+		return nil, NewStorageError("list_channel_allocations", "販売チャネルの確保の取得に失敗しました", err)
+	}
+	reservations, err := m.storage.ListReservations(ctx, ReservationFilter{ItemID: stock.ItemID, LocationID: stock.LocationID, Status: ReservationStatusActive})
+	if err != nil {
+		return nil, NewStorageError("list_reservations", "予約一覧の取得に失敗しました", err)
+	}
+
+	byChannel := make(map[string]*ChannelAvailability, len(allocations))
+	for _, allocation := range allocations {
+		byChannel[allocation.Channel] = &ChannelAvailability{Channel: allocation.Channel, Allocated: allocation.Quantity}
+	}
+	for _, reservation := range reservations {
+		if reservation.Channel == "" {
+			continue
+		}
+		channel, ok := byChannel[reservation.Channel]
+		if !ok {
+			channel = &ChannelAvailability{Channel: reservation.Channel}
+			byChannel[reservation.Channel] = channel
+		}
+		channel.Reserved += reservation.Quantity
+	}
+
+	// 予約で使用していない確保数量の合計を共有の利用可能数量から除く
+	var earmarked int64
+	for _, channel := range byChannel {
+		earmarked += nonNegative(channel.Allocated - channel.Reserved)
+	}
+	channels.SharedAvailable = nonNegative(stock.Available - earmarked)
+
+	for _, channel := range byChannel {
+		channel.Available = channels.SharedAvailable + nonNegative(channel.Allocated-channel.Reserved)
+		if channel.Available > stock.Available {
+			channel.Available = nonNegative(stock.Available)
+		}
+		channels.Channels = append(channels.Channels, *channel)
+	}
+	sort.Slice(channels.Channels, func(i, j int) bool {
+		return channels.Channels[i].Channel < channels.Channels[j].Channel
+	})
+	return channels, nil
+}
+
+// reservableQuantity returns the quantity of a stock record a reservation for channel can hold
+// 販売チャネルの予約が在庫記録から確保できる数量を返す（チャネルが空の場合は共有の利用可能数量）
+func (m *Manager) reservableQuantity(ctx context.Context, stock *Stock, channel string) (int64, error) {
+	channels, err := m.stockChannels(ctx, stock)
+	if err != nil {
+		return 0, err
+	}
+	if channel != "" {
+		for _, availability := range channels.Channels {
+			if availability.Channel == channel {
+				return availability.Available, nil
+			}
+		}
+	}
+	return channels.SharedAvailable, nil
+}
+
+// checkUnearmarkedStock verifies that decreasing a stock record by quantity leaves its sales channel allocations covered
+// 在庫記録をquantity分減らしても販売チャネルに確保された数量を使用しないことを確認
+//
+// チャネルの確保がある在庫では、出庫・移動・調整で減らせるのは共有の利用可能数量までで、
+// 超える場合は ErrInsufficientStock を返します（確保数量を減らしてから操作してください）。
+func (m *Manager) checkUnearmarkedStock(ctx context.Context, stock *Stock, quantity int64) error {
+	if quantity <= 0 {
+		return nil
+	}
+	allocations, err := m.storage.ListChannelAllocations(ctx, stock.ItemID, stock.LocationID)
+	if err != nil {
+		return NewStorageError("list_channel_allocations", "販売チャネルの確保の取得に失敗しました", err)
+	}
+	if len(allocations) == 0 {
+		return nil
+	}
+
+	channels, err := m.stockChannels(ctx, stock)
+	if err != nil {
+		return err
+	}
+	if channels.SharedAvailable < nonNegative(stock.Available) && channels.SharedAvailable < quantity {
+		return ErrInsufficientStock
+	}
+	return nil
+}
+
+// channelMetadata adds the sales channel to transaction metadata
+// 販売チャネルをトランザクションのメタデータに追加（チャネルが空の場合は追加しない）
+func channelMetadata(channel string, metadata map[string]string) map[string]string {
+	if channel == "" {
+		return metadata
+	}
+	if metadata == nil {
+		metadata = make(map[string]string, 1)
+	}
+	metadata[MetadataChannel] = channel
+	return metadata
+}
+
+// nonNegative returns quantity, or 0 when it is negative
+// 数量を返す（負の場合は0）
+func nonNegative(quantity int64) int64 {
+	if quantity < 0 {
+		return 0
+	}
+	return quantity
+}
@@ -0,0 +1,162 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+)
+
+var _ ConsignmentManager = (*Manager)(nil)
+
+// ownerKey is the context key that names the owner of the stock an add, remove or transfer moves
+// 在庫追加・削除・移動の対象の在庫の所有者を指定するコンテキストキー
+type ownerKey struct{}
+
+// WithOwner returns a context that makes Add, Remove and Transfer move consignment stock owned by a supplier
+// Add・Remove・Transfer が仕入先所有の委託在庫を移動するコンテキストを返す
+//
+// ownerID は所有者の仕入先IDです。Add は在庫数量とともに所有者の委託在庫を加算し、Remove は減算し、
+// 委託在庫が不足する場合は ErrInsufficientConsignmentStock を返します。Transfer は委託在庫を移動元から移動先に移します。
+// いずれもトランザクションの metadata.owner_id に所有者を記録します。所有者を指定しない操作は自社所有の在庫の操作です。
+func WithOwner(ctx context.Context, ownerID string) context.Context {
+	return context.WithValue(ctx, ownerKey{}, ownerID)
+}
+
+// stockOwner returns the owner requested by the context, or an empty string for owned stock
+// コンテキストが指定する在庫の所有者を返す（自社所有の場合は空文字列）
+func stockOwner(ctx context.Context) string {
+	ownerID, _ := ctx.Value(ownerKey{}).(string)
+	return ownerID
+}
+
+// ownerMetadata adds the owner requested by the context to transaction metadata
+// コンテキストが指定する在庫の所有者をトランザクションのメタデータに追加
+func ownerMetadata(ctx context.Context, metadata map[string]string) map[string]string {
+	ownerID := stockOwner(ctx)
+	if ownerID == "" {
+		return metadata
+	}
+	if metadata == nil {
+		metadata = make(map[string]string, 1)
+	}
+	metadata[MetadataOwnerID] = ownerID
+	return metadata
+}
+
+// validateOwner verifies that the owner requested by the context is a registered supplier
+// コンテキストが指定する在庫の所有者が登録済みの仕入先であることを確認
+func (m *Manager) validateOwner(ctx context.Context) error {
+	ownerID := stockOwner(ctx)
+	if ownerID == "" {
+		return nil
+	}
+	if err := ValidateSupplierID(ownerID); err != nil {
+		return NewValidationError("owner_id", "所有者の仕入先IDが正しくありません", ownerID)
+	}
+	if _, err := m.storage.GetSupplier(ctx, ownerID); err != nil {
+		if errors.Is(err, ErrSupplierNotFound) {
+			return ErrSupplierNotFound
+		}
+		return NewStorageError("get_supplier", "仕入先の取得に失敗しました", err)
+	}
+	return nil
+}
+
+// GetStockOwnership splits the stock of an item at a location into owned and consignment stock
+// 商品のロケーションの在庫を自社所有と委託在庫に分けて取得
+//
+// 在庫の記録がない場合は数量0の内訳を返します。
+func (m *Manager) GetStockOwnership(ctx context.Context, itemID, locationID string) (*StockOwnership, error) {
+	if err := ValidateItemID(itemID); err != nil {
+		return nil, err
+	}
+	if err := ValidateLocationID(locationID); err != nil {
+		return nil, err
+	}
+
+	ownership := &StockOwnership{ItemID: itemID, LocationID: locationID}
+	stock, err := m.storage.GetStock(ctx, itemID, locationID)
+	switch {
+	case errors.Is(err, ErrStockNotFound):
+	case err != nil:
+		return nil, NewStorageError("get_stock", "在庫取得に失敗しました", err)
+	default:
+		ownership.Quantity = stock.Quantity
+	}
+
+	consignments, err := m.storage.ListConsignmentStocks(ctx, ConsignmentStockFilter{ItemID: itemID, LocationID: locationID})
+	if err != nil {
+		return nil, NewStorageError("list_consignment_stocks", "委託在庫一覧の取得に失敗しました", err)
+	}
+	ownership.Consignments = consignments
+	for _, consignment := range consignments {
+		ownership.ConsignedQuantity += consignment.Quantity
+	}
+	if ownership.ConsignedQuantity > ownership.Quantity {
+		return nil, ErrConsignmentStockMismatch
+	}
+	ownership.OwnedQuantity = ownership.Quantity - ownership.ConsignedQuantity
+	return ownership, nil
+}
+
+// ListConsignmentStocks lists consignment stocks matching a filter
+// 条件に一致する委託在庫を取得
+func (m *Manager) ListConsignmentStocks(ctx context.Context, filter ConsignmentStockFilter) ([]ConsignmentStock, error) {
+	if err := validateOffsetLimit(filter.Offset, filter.Limit); err != nil {
+		return nil, err
+	}
+	consignments, err := m.storage.ListConsignmentStocks(ctx, filter)
+	if err != nil {
+		return nil, NewStorageError("list_consignment_stocks", "委託在庫一覧の取得に失敗しました", err)
+	}
+	return consignments, nil
+}
+
+// adjustConsignment adds delta to the consignment stock of the owner requested by the context
+// コンテキストが指定する所有者の委託在庫をdelta分だけ増減（トランザクション内で呼び出すこと）
+func (m *Manager) adjustConsignment(ctx context.Context, itemID, locationID string, delta int64) error {
+	if _, err := m.storage.AdjustConsignmentStock(ctx, stockOwner(ctx), itemID, locationID, delta); err != nil {
+		if errors.Is(err, ErrInsufficientConsignmentStock) {
+			return ErrInsufficientConsignmentStock
+		}
+		return NewStorageError("adjust_consignment_stock", "委託在庫の更新に失敗しました", err)
+	}
+	return nil
+}
+
+// ownedQuantity returns the quantity of stock that is not supplier-owned consignment stock
+// 在庫数量から委託在庫を除いた自社所有の数量を返す
+//
+// 委託在庫の合計が在庫数量を超える場合は0に丸めず ErrConsignmentStockMismatch を返します。
+func ownedQuantity(ctx context.Context, storage Storage, stock *Stock) (int64, error) {
+	consignments, err := storage.ListConsignmentStocks(ctx, ConsignmentStockFilter{ItemID: stock.ItemID, LocationID: stock.LocationID})
+	if err != nil {
+		return 0, NewStorageError("list_consignment_stocks", "委託在庫一覧の取得に失敗しました", err)
+	}
+	quantity := stock.Quantity
+	for _, consignment := range consignments {
+		quantity -= consignment.Quantity
+	}
+	if quantity < 0 {
+		return 0, ErrConsignmentStockMismatch
+	}
+	return quantity, nil
+}
+
+// checkOwnedStock verifies that decreasing a stock record by quantity without an owner leaves its consignment stock in place
+// 所有者を指定せずに在庫記録をquantity分減らしても委託在庫が減らないことを確認
+//
+// 所有者を指定しない出庫・移動・調整は自社所有の在庫のみを減らせ、不足する場合は ErrInsufficientStock を返します。
+// 所有者を指定した操作の委託在庫は adjustConsignment が同じトランザクションで確認します。
+func (m *Manager) checkOwnedStock(ctx context.Context, stock *Stock, quantity int64) error {
+	if stockOwner(ctx) != "" || quantity <= 0 {
+		return nil
+	}
+	owned, err := ownedQuantity(ctx, m.storage, stock)
+	if err != nil {
+		return err
+	}
+	if owned < quantity {
+		return ErrInsufficientStock
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// consistencyLocationPageSize is the number of locations read per page while checking
+// 整合性チェックでロケーションを読み取る1ページあたりの件数
+const consistencyLocationPageSize = 100
+
+// errStockChangedDuringCheck is returned when a stock record changed after it was checked
+// チェック後に在庫記録が更新されていた場合のエラー
+var errStockChangedDuringCheck = errors.New("チェック後に在庫が更新されたため修復しませんでした")
+
+// StockDivergence is a stock record that disagrees with the transaction ledger
+// トランザクション台帳と一致しない在庫記録
+type StockDivergence struct {
+	ItemID         string `json:"item_id"`
+	LocationID     string `json:"location_id"`
+	LedgerQuantity int64  `json:"ledger_quantity"`        // 台帳から算出した数量
+	StockQuantity  int64  `json:"stock_quantity"`         // 在庫記録の数量（記録がない場合は0）
+	Difference     int64  `json:"difference"`             // StockQuantity - LedgerQuantity
+	MissingStock   bool   `json:"missing_stock"`          // 台帳に残高があるが在庫記録がない
+	Repaired       bool   `json:"repaired"`               // 在庫記録を台帳の数量に修復した
+	RepairError    string `json:"repair_error,omitempty"` // 修復できなかった理由
+	version        int64  // チェック時の在庫記録のバージョン（修復時の競合検出に使用）
+}
+
+// ConsistencyReport is the result of comparing stock records with the transaction ledger
+// 在庫記録とトランザクション台帳の比較結果
+type ConsistencyReport struct {
+	CheckedAt    time.Time         `json:"checked_at"`   // チェック開始日時
+	Transactions int               `json:"transactions"` // 集計したトランザクション数
+	Stocks       int               `json:"stocks"`       // 比較した在庫記録数
+	Skipped      int               `json:"skipped"`      // チェック中に更新されたため比較しなかった在庫記録数
+	Divergences  []StockDivergence `json:"divergences"`  // 台帳と一致しない在庫
+	Repaired     int               `json:"repaired"`     // 修復した在庫数
+}
+
+// CheckStockConsistency recomputes stock levels from the transaction ledger and compares them with stock records
+// トランザクション台帳から在庫数を再計算し、在庫記録と比較
+//
+// repairがtrueの場合は一致しない在庫記録を台帳の数量に更新します（台帳にトランザクションは追加しません）。
+// チェック開始後に更新された在庫記録は比較せず、チェック後に更新された在庫記録は修復しません。
+// アーカイブ済みのトランザクションやトランザクションを伴わない在庫の投入（BulkImport、
+// CreateOrUpdateStocks）がある場合、その分は不一致として報告されます。
+func (m *Manager) CheckStockConsistency(ctx context.Context, repair bool) (*ConsistencyReport, error) {
+	report := &ConsistencyReport{CheckedAt: time.Now()}
+	// 読み取りレプリカの遅延による誤検出を避けるためプライマリから読み取る
+	readCtx := WithPrimaryRead(ctx)
+
+	balances := make(map[balanceKey]int64)
+	err := m.storage.ForEachTransactionInRange(readCtx, time.Time{}, time.Time{}, func(tx Transaction) error {
+		report.Transactions++
+		return applyLedgerTransaction(tx, func(locationID string, delta int64, changeType string) {
+			balances[balanceKey{itemID: tx.ItemID, locationID: locationID}] += delta
+		})
+	})
+	if err != nil {
+		return nil, NewStorageError("for_each_transaction", "トランザクション台帳の読み取りに失敗しました", err)
+	}
+
+	// 在庫記録と比較し、比較した残高は台帳側から取り除く
+	compare := func(stock Stock) error {
+		key := balanceKey{itemID: stock.ItemID, locationID: stock.LocationID}
+		ledger := balances[key]
+		delete(balances, key)
+
+		if !stock.UpdatedAt.Before(report.CheckedAt) {
+			report.Skipped++
+			return nil
+		}
+		report.Stocks++
+		if stock.Quantity != ledger {
+			report.Divergences = append(report.Divergences, StockDivergence{
+				ItemID:         stock.ItemID,
+				LocationID:     stock.LocationID,
+				LedgerQuantity: ledger,
+				StockQuantity:  stock.Quantity,
+				Difference:     stock.Quantity - ledger,
+				version:        stock.Version,
+			})
+		}
+		return nil
+	}
+	// チェック中のロケーション追加でページがずれても同じロケーションを二重に比較しない
+	seen := make(map[string]bool)
+	for offset := 0; ; offset += consistencyLocationPageSize {
+		locations, err := m.storage.ListLocations(readCtx, offset, consistencyLocationPageSize)
+		if err != nil {
+			return nil, NewStorageError("list_locations", "ロケーション一覧の取得に失敗しました", err)
+		}
+		for _, location := range locations {
+			if seen[location.ID] {
+				continue
+			}
+			seen[location.ID] = true
+			if err := m.storage.ForEachStockByLocation(readCtx, location.ID, compare); err != nil {
+				return nil, NewStorageError("for_each_stock", "在庫記録の読み取りに失敗しました", err)
+			}
+		}
+		if len(locations) < consistencyLocationPageSize {
+			break
+		}
+	}
+
+	// 在庫記録がないまま台帳に残高が残っているもの
+	for key, ledger := range balances {
+		if ledger == 0 {
+			continue
+		}
+		report.Divergences = append(report.Divergences, StockDivergence{
+			ItemID:         key.itemID,
+			LocationID:     key.locationID,
+			LedgerQuantity: ledger,
+			Difference:     -ledger,
+			MissingStock:   true,
+		})
+	}
+	sort.Slice(report.Divergences, func(i, j int) bool {
+		a, b := report.Divergences[i], report.Divergences[j]
+		if a.LocationID != b.LocationID {
+			return a.LocationID < b.LocationID
+		}
+		return a.ItemID < b.ItemID
+	})
+
+	var repairErrs []error
+	if repair {
+		for i := range report.Divergences {
+			divergence := &report.Divergences[i]
+			if err := m.repairStock(ctx, divergence); err != nil {
+				divergence.RepairError = err.Error()
+				if !errors.Is(err, errStockChangedDuringCheck) {
+					repairErrs = append(repairErrs, fmt.Errorf("%s@%s: %w", divergence.ItemID, divergence.LocationID, err))
+				}
+				continue
+			}
+			divergence.Repaired = true
+			report.Repaired++
+		}
+	}
+
+	m.logger.Info("在庫整合性チェック完了",
+		zap.Int("transactions", report.Transactions),
+		zap.Int("stocks", report.Stocks),
+		zap.Int("skipped", report.Skipped),
+		zap.Int("divergences", len(report.Divergences)),
+		zap.Int("repaired", report.Repaired),
+	)
+
+	if len(repairErrs) > 0 {
+		return report, errors.Join(repairErrs...)
+	}
+	return report, nil
+}
+
+// repairStock sets the stock record of divergence to the ledger quantity
+// 不一致の在庫記録を台帳の数量に更新
+func (m *Manager) repairStock(ctx context.Context, divergence *StockDivergence) error {
+	var oldQuantity int64
+	var repaired *Stock
+	err := m.withStockLock(ctx, func(lm *Manager) error {
+		stock, err := lm.getStockForWrite(ctx, divergence.ItemID, divergence.LocationID)
+		if err != nil && err != ErrStockNotFound {
+			return NewStorageError("get_stock", "在庫取得に失敗しました", err)
+		}
+
+		if stock == nil {
+			if !divergence.MissingStock {
+				return errStockChangedDuringCheck
+			}
+			stock = &Stock{
+				ItemID:     divergence.ItemID,
+				LocationID: divergence.LocationID,
+				Quantity:   divergence.LedgerQuantity,
+				Version:    1,
+				UpdatedAt:  time.Now(),
+				UpdatedBy:  lm.getUserFromContext(ctx),
+			}
+			stock.CalculateAvailable()
+			if err := lm.storage.CreateStock(ctx, stock); err != nil {
+				return NewStorageError("create_stock", "在庫作成に失敗しました", err)
+			}
+			oldQuantity, repaired = 0, stock
+			return nil
+		}
+
+		if divergence.MissingStock || stock.Version != divergence.version {
+			return errStockChangedDuringCheck
+		}
+		oldQuantity = stock.Quantity
+		stock.Quantity = divergence.LedgerQuantity
+		stock.Version++
+		stock.UpdatedAt = time.Now()
+		stock.UpdatedBy = lm.getUserFromContext(ctx)
+		stock.CalculateAvailable()
+		if err := lm.storage.UpdateStock(ctx, stock); err != nil {
+			return NewStorageError("update_stock", "在庫更新に失敗しました", err)
+		}
+		repaired = stock
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	m.logger.Warn("在庫を台帳の数量に修復しました",
+		zap.String("item_id", divergence.ItemID),
+		zap.String("location_id", divergence.LocationID),
+		zap.Int64("old_quantity", oldQuantity),
+		zap.Int64("new_quantity", repaired.Quantity),
+	)
+
+	// 下流システムの在庫数も揃うよう在庫変更イベントを発行する（台帳には記録しない）
+	if m.publisher != nil {
+		event := m.newStockChangedEvent(ctx, repaired, oldQuantity, 0, "repair", "consistency-check", NewTransactionID())
+		if err := m.publisher.PublishStockChanged(ctx, event); err != nil {
+			m.logger.Error("修復イベント発行に失敗しました", zap.Error(err))
+		}
+	}
+
+	return nil
+}
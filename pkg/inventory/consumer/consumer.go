@@ -0,0 +1,215 @@
+// Package consumer applies stock changes received from external systems
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// ErrInvalidMessage is returned when a message cannot be decoded or is incomplete
+// メッセージがデコードできない、または必須項目が不足している場合に返される
+var ErrInvalidMessage = errors.New("無効な同期メッセージです")
+
+// Message is a stock change received from an external system
+// 外部システムから受信した在庫変更
+//
+// IdempotencyKeyは送信元で一意なキー（ERPの入荷伝票番号など）で、
+// 同じキーのメッセージは再配信されても一度だけ適用されます。
+type Message struct {
+	IdempotencyKey string `json:"idempotency_key"`  // 冪等性キー
+	Source         string `json:"source,omitempty"` // 送信元システム（ログ・キーの名前空間に使用）
+	inventory.InventoryOperation
+}
+
+// Key returns the idempotency key namespaced by source
+// 送信元で名前空間を分けた冪等性キーを返す
+func (m Message) Key() string {
+	if m.Source == "" {
+		return m.IdempotencyKey
+	}
+	return m.Source + ":" + m.IdempotencyKey
+}
+
+// Validate checks that the message can be applied
+// メッセージが適用可能かを検証
+func (m Message) Validate() error {
+	if m.IdempotencyKey == "" {
+		return fmt.Errorf("%w: idempotency_keyが指定されていません", ErrInvalidMessage)
+	}
+	if m.ItemID == "" || m.LocationID == "" {
+		return fmt.Errorf("%w: item_idとlocation_idは必須です", ErrInvalidMessage)
+	}
+	switch m.Type {
+	case inventory.OperationTypeAdd, inventory.OperationTypeRemove, inventory.OperationTypeAdjust:
+	case inventory.OperationTypeTransfer:
+		if m.ToLocationID == nil || *m.ToLocationID == "" {
+			return fmt.Errorf("%w: 移動先ロケーションが指定されていません", ErrInvalidMessage)
+		}
+	default:
+		return fmt.Errorf("%w: 未知の操作タイプ: %s", ErrInvalidMessage, m.Type)
+	}
+	return nil
+}
+
+// Decode decodes a JSON message
+// JSONのメッセージをデコード
+func Decode(data []byte) (Message, error) {
+	var msg Message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return Message{}, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+	return msg, nil
+}
+
+// Result is the outcome of handling a message
+// メッセージ処理の結果
+type Result string
+
+const (
+	ResultApplied   Result = "applied"   // 在庫に適用した
+	ResultDuplicate Result = "duplicate" // 処理済み（または処理中）のため適用しなかった
+)
+
+// IsPermanent reports whether err will occur again if the same message is redelivered
+// 同じメッセージを再配信しても再び発生するエラーかどうかを判定
+//
+// 恒久的なエラーのメッセージは再試行せず、デッドレターなどに送ることを想定しています。
+func IsPermanent(err error) bool {
+	var validationErr *inventory.ValidationError
+	var businessErr *inventory.BusinessRuleError
+	switch {
+	case errors.Is(err, ErrInvalidMessage),
+		errors.As(err, &validationErr),
+		errors.As(err, &businessErr),
+		errors.Is(err, inventory.ErrItemNotFound),
+		errors.Is(err, inventory.ErrLocationNotFound),
+		errors.Is(err, inventory.ErrInsufficientStock):
+		return true
+	}
+	return false
+}
+
+// Config holds settings for the consumer
+// コンシューマーの設定
+type Config struct {
+	ClaimTTL time.Duration // 処理中のキーを他の配信から保護する時間（プロセス停止時の再処理までの待ち時間）
+}
+
+// DefaultConfig returns the default consumer settings
+// コンシューマーの既定設定を返す
+func DefaultConfig() Config {
+	return Config{ClaimTTL: 5 * time.Minute}
+}
+
+// Consumer applies messages through an InventoryManager exactly once per idempotency key
+// 冪等性キーごとに一度だけInventoryManager経由でメッセージを適用
+//
+// キーは適用前に確保され、適用に失敗した場合は解放されるため、再配信時に再試行されます。
+// 適用後の完了記録の前にプロセスが停止した場合は、ClaimTTL経過後の再配信で二重に適用される可能性があります。
+type Consumer struct {
+	manager inventory.InventoryManager
+	store   IdempotencyStore
+	cfg     Config
+	logger  *zap.Logger
+}
+
+// NewConsumer creates a consumer that applies messages through manager
+// managerを通じてメッセージを適用するコンシューマーを作成
+func NewConsumer(manager inventory.InventoryManager, store IdempotencyStore, cfg Config, logger *zap.Logger) *Consumer {
+	if cfg.ClaimTTL <= 0 {
+		cfg.ClaimTTL = DefaultConfig().ClaimTTL
+	}
+	return &Consumer{
+		manager: manager,
+		store:   store,
+		cfg:     cfg,
+		logger:  logger,
+	}
+}
+
+// HandleJSON decodes data and handles the message
+// dataをデコードしてメッセージを処理
+func (c *Consumer) HandleJSON(ctx context.Context, data []byte) (Result, error) {
+	msg, err := Decode(data)
+	if err != nil {
+		return "", err
+	}
+	return c.Handle(ctx, msg)
+}
+
+// Handle applies msg unless a message with the same key was already handled
+// 同じキーのメッセージが処理済みでなければmsgを適用
+func (c *Consumer) Handle(ctx context.Context, msg Message) (Result, error) {
+	if err := msg.Validate(); err != nil {
+		return "", err
+	}
+
+	key := msg.Key()
+	claimed, err := c.store.Claim(ctx, key, c.cfg.ClaimTTL)
+	if err != nil {
+		return "", fmt.Errorf("冪等性キーの確保に失敗しました: %w", err)
+	}
+	if !claimed {
+		c.logger.Info("処理済みの同期メッセージをスキップしました",
+			zap.String("idempotency_key", key),
+		)
+		return ResultDuplicate, nil
+	}
+
+	if err := c.apply(ctx, msg); err != nil {
+		// 再配信時に再試行できるようにキーを解放する
+		if releaseErr := c.store.Release(context.WithoutCancel(ctx), key); releaseErr != nil {
+			c.logger.Error("冪等性キーの解放に失敗しました",
+				zap.String("idempotency_key", key),
+				zap.Error(releaseErr),
+			)
+		}
+		return "", err
+	}
+
+	if err := c.store.Complete(context.WithoutCancel(ctx), key); err != nil {
+		// 在庫には適用済みのため成功として扱う（ClaimTTLの間は重複が防止される）
+		c.logger.Error("冪等性キーの完了記録に失敗しました",
+			zap.String("idempotency_key", key),
+			zap.Error(err),
+		)
+	}
+
+	c.logger.Info("同期メッセージを適用しました",
+		zap.String("idempotency_key", key),
+		zap.String("type", string(msg.Type)),
+		zap.String("item_id", msg.ItemID),
+		zap.String("location_id", msg.LocationID),
+		zap.Int64("quantity", msg.Quantity),
+	)
+
+	return ResultApplied, nil
+}
+
+// apply executes the operation of msg
+// msgの操作を実行
+func (c *Consumer) apply(ctx context.Context, msg Message) error {
+	reference := msg.Reference
+	if reference == "" {
+		reference = msg.IdempotencyKey
+	}
+
+	switch msg.Type {
+	case inventory.OperationTypeAdd:
+		return c.manager.Add(ctx, msg.ItemID, msg.LocationID, msg.Quantity, reference)
+	case inventory.OperationTypeRemove:
+		return c.manager.Remove(ctx, msg.ItemID, msg.LocationID, msg.Quantity, reference)
+	case inventory.OperationTypeTransfer:
+		return c.manager.Transfer(ctx, msg.ItemID, msg.LocationID, *msg.ToLocationID, msg.Quantity, reference)
+	case inventory.OperationTypeAdjust:
+		return c.manager.Adjust(ctx, msg.ItemID, msg.LocationID, msg.Quantity, reference)
+	}
+	return fmt.Errorf("%w: 未知の操作タイプ: %s", ErrInvalidMessage, msg.Type)
+}
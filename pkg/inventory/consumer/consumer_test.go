@@ -0,0 +1,120 @@
+package consumer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/storage"
+)
+
+// newTestConsumer はテスト用の商品とロケーションを登録済みのメモリストレージを使うコンシューマーを作成
+func newTestConsumer(t *testing.T) (*Consumer, *inventory.Manager) {
+	t.Helper()
+
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+	now := time.Now()
+	require.NoError(t, store.CreateItem(ctx, &inventory.Item{ID: "TEST-ITEM", Name: "テスト商品", CreatedAt: now, UpdatedAt: now}))
+	require.NoError(t, store.CreateLocation(ctx, &inventory.Location{ID: "LOC-A", Name: "ロケーションA", CreatedAt: now, UpdatedAt: now}))
+
+	manager := inventory.NewManager(store, nil, zap.NewNop(), nil)
+	return NewConsumer(manager, NewMemoryStore(), DefaultConfig(), zap.NewNop()), manager
+}
+
+// TestConsumer_AppliesOnce は同じ冪等性キーのメッセージが一度だけ適用されることのテスト
+func TestConsumer_AppliesOnce(t *testing.T) {
+	c, manager := newTestConsumer(t)
+	ctx := context.Background()
+
+	data := []byte(`{"idempotency_key":"RCPT-1","source":"erp","type":"add","item_id":"TEST-ITEM","location_id":"LOC-A","quantity":10}`)
+
+	result, err := c.HandleJSON(ctx, data)
+	require.NoError(t, err)
+	assert.Equal(t, ResultApplied, result)
+
+	// 再配信は適用されない
+	result, err = c.HandleJSON(ctx, data)
+	require.NoError(t, err)
+	assert.Equal(t, ResultDuplicate, result)
+
+	stock, err := manager.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), stock.Quantity)
+
+	// 参照番号を省略した場合は冪等性キーが記録される
+	history, err := manager.GetHistoryByReference(ctx, "RCPT-1")
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+}
+
+// TestConsumer_ReleasesOnFailure は適用に失敗したキーが再処理できることのテスト
+func TestConsumer_ReleasesOnFailure(t *testing.T) {
+	c, manager := newTestConsumer(t)
+	ctx := context.Background()
+
+	msg := Message{
+		IdempotencyKey: "SHIP-1",
+		InventoryOperation: inventory.InventoryOperation{
+			Type: inventory.OperationTypeRemove, ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 5,
+		},
+	}
+
+	_, err := c.Handle(ctx, msg)
+	require.Error(t, err)
+	assert.True(t, IsPermanent(err))
+
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 5, "SETUP"))
+	result, err := c.Handle(ctx, msg)
+	require.NoError(t, err)
+	assert.Equal(t, ResultApplied, result)
+}
+
+// TestMessage_Validate はメッセージ検証のテスト
+func TestMessage_Validate(t *testing.T) {
+	valid := Message{
+		IdempotencyKey:     "KEY-1",
+		InventoryOperation: inventory.InventoryOperation{Type: inventory.OperationTypeAdd, ItemID: "ITEM", LocationID: "LOC", Quantity: 1},
+	}
+	require.NoError(t, valid.Validate())
+
+	missingKey := valid
+	missingKey.IdempotencyKey = ""
+	assert.ErrorIs(t, missingKey.Validate(), ErrInvalidMessage)
+
+	transfer := valid
+	transfer.Type = inventory.OperationTypeTransfer
+	assert.ErrorIs(t, transfer.Validate(), ErrInvalidMessage)
+
+	_, err := Decode([]byte(`{`))
+	assert.ErrorIs(t, err, ErrInvalidMessage)
+
+	assert.Equal(t, "erp:KEY-1", Message{IdempotencyKey: "KEY-1", Source: "erp"}.Key())
+}
+
+// TestMemoryStore_ClaimTTL は処理中キーの期限のテスト
+func TestMemoryStore_ClaimTTL(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	claimed, err := store.Claim(ctx, "KEY", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+
+	claimed, _ = store.Claim(ctx, "KEY", time.Hour)
+	assert.False(t, claimed)
+
+	// 期限切れの処理中キーは再確保できる
+	claimed, _ = store.Claim(ctx, "KEY", 0)
+	assert.True(t, claimed)
+
+	require.NoError(t, store.Complete(ctx, "KEY"))
+	require.NoError(t, store.Release(ctx, "KEY"))
+	claimed, _ = store.Claim(ctx, "KEY", 0)
+	assert.False(t, claimed)
+}
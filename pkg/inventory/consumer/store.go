@@ -0,0 +1,150 @@
+package consumer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// IdempotencyStore records which idempotency keys have been handled
+// 処理済みの冪等性キーを記録するストア
+type IdempotencyStore interface {
+	// Claim marks key as in progress and reports whether the caller should handle it
+	// キーを処理中にし、呼び出し元が処理すべきかを返す
+	//
+	// 完了済みのキーと、ttl以内に確保された処理中のキーに対してはfalseを返します。
+	Claim(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Complete marks key as handled
+	// キーを処理済みにする
+	Complete(ctx context.Context, key string) error
+	// Release removes an in-progress claim so the key can be handled again
+	// 処理中の確保を取り消し、再処理できるようにする
+	Release(ctx context.Context, key string) error
+}
+
+// claim is the state of one key in MemoryStore
+// MemoryStoreのキーごとの状態
+type claim struct {
+	completed bool
+	claimedAt time.Time
+}
+
+// MemoryStore implements IdempotencyStore with an in-memory map
+// インメモリのマップを使用したIdempotencyStoreの実装
+//
+// プロセスの再起動で記録が失われるため、単体テストやデモ用途を想定しています。
+type MemoryStore struct {
+	mu     sync.Mutex
+	claims map[string]claim
+}
+
+var _ IdempotencyStore = (*MemoryStore)(nil)
+
+// NewMemoryStore creates a new in-memory store
+// 新しいインメモリストアを作成
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{claims: make(map[string]claim)}
+}
+
+// Claim marks key as in progress
+// キーを処理中にする
+func (s *MemoryStore) Claim(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if c, exists := s.claims[key]; exists && (c.completed || now.Sub(c.claimedAt) < ttl) {
+		return false, nil
+	}
+	s.claims[key] = claim{claimedAt: now}
+	return true, nil
+}
+
+// Complete marks key as handled
+// キーを処理済みにする
+func (s *MemoryStore) Complete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.claims[key]
+	c.completed = true
+	s.claims[key] = c
+	return nil
+}
+
+// Release removes an in-progress claim
+// 処理中の確保を取り消す
+func (s *MemoryStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c, exists := s.claims[key]; exists && !c.completed {
+		delete(s.claims, key)
+	}
+	return nil
+}
+
+// PostgresStore implements IdempotencyStore using the processed_messages table
+// processed_messagesテーブルを使用したIdempotencyStoreの実装
+type PostgresStore struct {
+	db *sql.DB
+}
+
+var _ IdempotencyStore = (*PostgresStore)(nil)
+
+// NewPostgresStore creates a store on an existing connection pool
+// 既存の接続プールを使用するストアを作成
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Claim marks key as in progress
+// キーを処理中にする
+//
+// 期限切れの処理中キーは同じ文で再確保するため、複数のコンシューマーが同時に確保することはありません。
+func (s *PostgresStore) Claim(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	query := `
+		INSERT INTO processed_messages (idempotency_key, status, claimed_at)
+		VALUES ($1, 'processing', NOW())
+		ON CONFLICT (idempotency_key) DO UPDATE SET claimed_at = NOW()
+		WHERE processed_messages.status = 'processing'
+		  AND processed_messages.claimed_at < NOW() - make_interval(secs => $2)
+		RETURNING idempotency_key`
+
+	var claimed string
+	err := s.db.QueryRowContext(ctx, query, key, ttl.Seconds()).Scan(&claimed)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("冪等性キーの確保に失敗しました: %w", err)
+	}
+	return true, nil
+}
+
+// Complete marks key as handled
+// キーを処理済みにする
+func (s *PostgresStore) Complete(ctx context.Context, key string) error {
+	query := `
+		UPDATE processed_messages
+		SET status = 'completed', completed_at = NOW()
+		WHERE idempotency_key = $1`
+
+	if _, err := s.db.ExecContext(ctx, query, key); err != nil {
+		return fmt.Errorf("冪等性キーの完了記録に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// Release removes an in-progress claim
+// 処理中の確保を取り消す
+func (s *PostgresStore) Release(ctx context.Context, key string) error {
+	query := `DELETE FROM processed_messages WHERE idempotency_key = $1 AND status = 'processing'`
+
+	if _, err := s.db.ExecContext(ctx, query, key); err != nil {
+		return fmt.Errorf("冪等性キーの解放に失敗しました: %w", err)
+	}
+	return nil
+}
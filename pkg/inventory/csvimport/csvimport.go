@@ -0,0 +1,191 @@
+// Package csvimport reads items and opening stock balances from CSV files
+// CSVファイルから商品と期首残高を読み取るパッケージ
+//
+// 1行目は列名のヘッダーで、列の順序は任意です。値の形式が不正な行は
+// inventory.ImportRowError として返し、残りの行の読み取りを続けます。
+package csvimport
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// itemColumns are the columns of an item CSV (id and name are required)
+// 商品CSVの列（id と name は必須）
+var itemColumns = []string{"id", "name", "sku", "description", "category", "unit_cost"}
+
+// openingBalanceColumns are the columns of an opening balance CSV (item_id, location_id and quantity are required)
+// 期首残高CSVの列（item_id、location_id、quantity は必須）
+var openingBalanceColumns = []string{"item_id", "location_id", "quantity", "unit_cost"}
+
+// ReadItems reads items from a CSV file
+// CSVファイルから商品を読み取る
+//
+// ヘッダーが不正な場合やCSVとして読み取れない場合はエラーを返します。
+func ReadItems(r io.Reader) ([]inventory.ItemImportRow, []inventory.ImportRowError, error) {
+	var rows []inventory.ItemImportRow
+	rowErrors, err := read(r, itemColumns, []string{"id", "name"}, func(line int, record row) error {
+		unitCost, err := record.float("unit_cost")
+		if err != nil {
+			return err
+		}
+		rows = append(rows, inventory.ItemImportRow{
+			Line: line,
+			Item: inventory.Item{
+				ID:          record.get("id"),
+				Name:        record.get("name"),
+				SKU:         record.get("sku"),
+				Description: record.get("description"),
+				Category:    record.get("category"),
+				UnitCost:    unitCost,
+			},
+		})
+		return nil
+	})
+	return rows, rowErrors, err
+}
+
+// ReadOpeningBalances reads opening stock balances from a CSV file
+// CSVファイルから期首残高を読み取る
+//
+// ヘッダーが不正な場合やCSVとして読み取れない場合はエラーを返します。
+func ReadOpeningBalances(r io.Reader) ([]inventory.OpeningBalanceRow, []inventory.ImportRowError, error) {
+	var rows []inventory.OpeningBalanceRow
+	rowErrors, err := read(r, openingBalanceColumns, []string{"item_id", "location_id", "quantity"}, func(line int, record row) error {
+		quantity, err := strconv.ParseInt(record.get("quantity"), 10, 64)
+		if err != nil {
+			return inventory.NewValidationError("quantity", "数量は整数で指定してください", record.get("quantity"))
+		}
+		balance := inventory.OpeningBalanceRow{
+			Line:       line,
+			ItemID:     record.get("item_id"),
+			LocationID: record.get("location_id"),
+			Quantity:   quantity,
+		}
+		if record.get("unit_cost") != "" {
+			unitCost, err := record.float("unit_cost")
+			if err != nil {
+				return err
+			}
+			balance.UnitCost = &unitCost
+		}
+		rows = append(rows, balance)
+		return nil
+	})
+	return rows, rowErrors, err
+}
+
+// row is a CSV record with its header
+// ヘッダー付きのCSVレコード
+type row struct {
+	columns map[string]int
+	values  []string
+}
+
+// get returns the trimmed value of a column, or an empty string if the column is absent
+// 列の値を前後の空白を除いて返す（列がない場合は空文字列）
+func (r row) get(column string) string {
+	i, ok := r.columns[column]
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(r.values[i])
+}
+
+// float parses the value of a column as a number (an empty value is 0)
+// 列の値を数値として解析（空の場合は0）
+func (r row) float(column string) (float64, error) {
+	value := r.get(column)
+	if value == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, inventory.NewValidationError(column, "数値で指定してください", value)
+	}
+	return f, nil
+}
+
+// read reads the header and passes each record to fn
+// ヘッダーを読み取り、各レコードをfnに渡す
+//
+// fnのエラーとフィールド数が不正なレコードは行のエラーとして集め、読み取りを続けます。
+func read(r io.Reader, allowed, required []string, fn func(line int, record row) error) ([]inventory.ImportRowError, error) {
+	reader := csv.NewReader(r)
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("CSVファイルが空です")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("CSVのヘッダーを読み取れません: %w", err)
+	}
+	columns, err := parseHeader(header, allowed, required)
+	if err != nil {
+		return nil, err
+	}
+
+	rowErrors := make([]inventory.ImportRowError, 0)
+	for {
+		values, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) && errors.Is(parseErr.Err, csv.ErrFieldCount) {
+				rowErrors = append(rowErrors, inventory.ImportRowError{
+					Line:    parseErr.StartLine,
+					Message: fmt.Sprintf("列の数がヘッダーと一致しません（%d列）", len(values)),
+				})
+				continue
+			}
+			return nil, fmt.Errorf("CSVを読み取れません: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		if err := fn(line, row{columns: columns, values: values}); err != nil {
+			rowErrors = append(rowErrors, inventory.NewImportRowError(line, err))
+		}
+	}
+
+	return rowErrors, nil
+}
+
+// parseHeader maps column names to their index
+// 列名を列の位置に対応付ける
+//
+// 列名は大文字小文字と前後の空白を区別せず、先頭のUTF-8 BOM（Excelの出力など）は無視します。
+func parseHeader(header, allowed, required []string) (map[string]int, error) {
+	known := make(map[string]bool, len(allowed))
+	for _, column := range allowed {
+		known[column] = true
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !known[name] {
+			return nil, fmt.Errorf("不明な列です: %q（使用できる列: %s）", name, strings.Join(allowed, ", "))
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("列が重複しています: %q", name)
+		}
+		columns[name] = i
+	}
+
+	for _, column := range required {
+		if _, ok := columns[column]; !ok {
+			return nil, fmt.Errorf("必須の列がありません: %q", column)
+		}
+	}
+	return columns, nil
+}
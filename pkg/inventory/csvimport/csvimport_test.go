@@ -0,0 +1,70 @@
+package csvimport
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReadItems は商品CSVの読み取りのテスト
+func TestReadItems(t *testing.T) {
+	data := "\ufeffID, Name ,sku,unit_cost\n" +
+		"ITEM-1,商品1,SKU-1,120.5\n" +
+		"ITEM-2,\"商品2\n（改行あり）\",,\n" +
+		"ITEM-3,商品3,SKU-3,abc\n" +
+		"ITEM-4,商品4\n"
+
+	rows, rowErrors, err := ReadItems(strings.NewReader(data))
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, 2, rows[0].Line)
+	assert.Equal(t, "ITEM-1", rows[0].Item.ID)
+	assert.Equal(t, "SKU-1", rows[0].Item.SKU)
+	assert.Equal(t, 120.5, rows[0].Item.UnitCost)
+	assert.Equal(t, 3, rows[1].Line)
+	assert.Equal(t, "商品2\n（改行あり）", rows[1].Item.Name)
+	assert.Zero(t, rows[1].Item.UnitCost)
+
+	// 行番号は複数行のフィールドを考慮する
+	require.Len(t, rowErrors, 2)
+	assert.Equal(t, 5, rowErrors[0].Line)
+	assert.Equal(t, "unit_cost", rowErrors[0].Field)
+	assert.Equal(t, 6, rowErrors[1].Line)
+}
+
+// TestReadOpeningBalances は期首残高CSVの読み取りのテスト
+func TestReadOpeningBalances(t *testing.T) {
+	data := "item_id,location_id,quantity,unit_cost\n" +
+		"ITEM-1,LOC-A,10,\n" +
+		"ITEM-1,LOC-B,5,99.9\n" +
+		"ITEM-2,LOC-A,1.5,\n"
+
+	rows, rowErrors, err := ReadOpeningBalances(strings.NewReader(data))
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, int64(10), rows[0].Quantity)
+	assert.Nil(t, rows[0].UnitCost)
+	require.NotNil(t, rows[1].UnitCost)
+	assert.Equal(t, 99.9, *rows[1].UnitCost)
+
+	require.Len(t, rowErrors, 1)
+	assert.Equal(t, 4, rowErrors[0].Line)
+	assert.Equal(t, "quantity", rowErrors[0].Field)
+}
+
+// TestReadHeaderErrors はヘッダーが不正なCSVのテスト
+func TestReadHeaderErrors(t *testing.T) {
+	for name, data := range map[string]string{
+		"空のファイル":  "",
+		"必須の列がない": "id,sku\nITEM-1,SKU-1\n",
+		"不明な列":    "id,name,price\nITEM-1,商品1,100\n",
+		"列の重複":    "id,name,name\nITEM-1,商品1,商品1\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := ReadItems(strings.NewReader(data))
+			assert.Error(t, err)
+		})
+	}
+}
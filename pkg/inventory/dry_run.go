@@ -0,0 +1,199 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+)
+
+// errDryRunRollback discards the changes made by a dry run
+// ドライランで行った変更を破棄するためのエラー
+var errDryRunRollback = errors.New("ドライランのため変更を破棄します")
+
+var _ DryRunner = (*Manager)(nil)
+
+// DryRunResult reports what a set of inventory operations would do
+// 在庫操作を適用した場合の結果
+type DryRunResult struct {
+	Valid   bool                   `json:"valid"`   // 全ての操作を適用できるか
+	Atomic  bool                   `json:"atomic"`  // 単一トランザクションとして検証したか
+	Results []BatchOperationResult `json:"results"` // 操作ごとの結果（succeeded・failed・アトミックで適用されない rolled_back）
+	Errors  []BatchOperationError  `json:"errors"`  // 失敗する操作のエラー
+	Changes []StockChange          `json:"changes"` // 適用した場合の在庫の変化（操作順）
+}
+
+// StockChange is the change an operation would make to a stock record
+// 操作による在庫記録の変化
+type StockChange struct {
+	OperationIndex int    `json:"operation_index"` // 操作のインデックス
+	ItemID         string `json:"item_id"`         // 商品ID
+	LocationID     string `json:"location_id"`     // ロケーションID
+	OldQuantity    int64  `json:"old_quantity"`    // 変更前の数量
+	NewQuantity    int64  `json:"new_quantity"`    // 変更後の数量
+	Available      int64  `json:"available"`       // 変更後の利用可能数
+	LowStock       bool   `json:"low_stock"`       // 低在庫アラートが発生するか
+}
+
+// DryRunOperation validates an inventory operation without applying it
+// 在庫操作を適用せずに検証し、適用した場合の在庫の変化を返す
+//
+// 商品・ロケーションの存在や在庫の不足などで操作が失敗する場合は、実際の操作と同じエラーを返します。
+func (m *Manager) DryRunOperation(ctx context.Context, op InventoryOperation) (_ *DryRunResult, err error) {
+	ctx, finish := m.startOperation(ctx, "dry_run", attrItemID.String(op.ItemID), attrLocationID.String(op.LocationID), attrQuantity.Int64(op.Quantity))
+	defer finish(&err)
+
+	result, opErr, err := m.dryRun(ctx, []InventoryOperation{op}, true)
+	if err != nil {
+		return nil, err
+	}
+	if opErr != nil {
+		return nil, opErr
+	}
+	return result, nil
+}
+
+// DryRunBatch validates a batch of inventory operations without applying them
+// バッチ在庫操作を適用せずに検証し、操作ごとの結果と在庫の変化を返す
+//
+// 操作は先行する操作を適用した状態に対して順に検証します。atomic が true の場合は ExecuteBatchAtomic と同様に
+// 最初の失敗で中断し、全ての操作を適用されないものとして返します。
+func (m *Manager) DryRunBatch(ctx context.Context, operations []InventoryOperation, atomic bool) (_ *DryRunResult, err error) {
+	ctx, finish := m.startOperation(ctx, "dry_run_batch", attrOperations.Int(len(operations)))
+	defer finish(&err)
+
+	result, _, err := m.dryRun(ctx, operations, atomic)
+	return result, err
+}
+
+// dryRun executes operations in a transaction that is always rolled back
+// 常にロールバックするトランザクション内で操作を実行
+//
+// 実際の操作と同じ処理で検証するため、検証の漏れや差異が生じません。イベント・ログ・メトリクスは記録しません。
+// 最初に失敗した操作のエラーを opErr で、ストレージなど検証自体の失敗を err で返します。
+func (m *Manager) dryRun(ctx context.Context, operations []InventoryOperation, atomic bool) (result *DryRunResult, opErr, err error) {
+	result = &DryRunResult{
+		Atomic:  atomic,
+		Results: make([]BatchOperationResult, len(operations)),
+		Errors:  make([]BatchOperationError, 0),
+		Changes: make([]StockChange, 0),
+	}
+	for i := range result.Results {
+		result.Results[i] = BatchOperationResult{OperationIndex: i, Status: OperationResultRolledBack}
+	}
+
+	recorder := &dryRunRecorder{}
+	err = m.storage.WithinTx(ctx, func(txStorage Storage) error {
+		txManager := m.dryRunManager(txStorage, recorder)
+		for i, op := range operations {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			recorder.index = i
+			applied := len(recorder.changes)
+			if err := txManager.executeOperation(ctx, op); err != nil {
+				var storageErr *StorageError
+				if errors.As(err, &storageErr) {
+					return err
+				}
+				recorder.changes = recorder.changes[:applied]
+				result.Results[i] = BatchOperationResult{OperationIndex: i, Status: OperationResultFailed, Error: err.Error()}
+				result.Errors = append(result.Errors, newBatchOperationError(i, op, err))
+				if opErr == nil {
+					opErr = err
+				}
+				if atomic {
+					break
+				}
+				continue
+			}
+			result.Results[i].Status = OperationResultSucceeded
+		}
+		return errDryRunRollback
+	})
+	if !errors.Is(err, errDryRunRollback) {
+		var storageErr *StorageError
+		if errors.As(err, &storageErr) || errors.Is(err, ctx.Err()) {
+			return nil, nil, err
+		}
+		return nil, nil, NewStorageError("dry_run", "ドライランの実行に失敗しました", err)
+	}
+
+	result.Valid = opErr == nil
+	if atomic && !result.Valid {
+		// ロールバックされるため、失敗した操作以外も適用されない
+		for i := range result.Results {
+			if result.Results[i].Status == OperationResultSucceeded {
+				result.Results[i].Status = OperationResultRolledBack
+			}
+		}
+	} else {
+		result.Changes = recorder.changes
+	}
+
+	m.log(ctx).Debug("ドライラン完了",
+		zap.Int("operations", len(operations)),
+		zap.Bool("atomic", atomic),
+		zap.Bool("valid", result.Valid),
+	)
+	return result, opErr, nil
+}
+
+// dryRunManager returns a manager that records stock changes instead of publishing events
+// イベントを発行せずに在庫の変化を記録するマネージャーを返す
+func (m *Manager) dryRunManager(storage Storage, recorder *dryRunRecorder) *Manager {
+	scoped := *m.withoutBackorders()
+	scoped.storage = storage
+	scoped.publisher = recorder
+	scoped.logger = zap.NewNop()
+
+	// 適用しない操作をメトリクスに記録しない
+	config := *scoped.config
+	config.Observer = nil
+	scoped.config = &config
+	return &scoped
+}
+
+// dryRunRecorder collects the stock changes of a dry run from the events of the operations
+// 操作のイベントからドライランの在庫の変化を収集する
+type dryRunRecorder struct {
+	index   int // 実行中の操作のインデックス
+	changes []StockChange
+}
+
+var _ EventPublisher = (*dryRunRecorder)(nil)
+
+// PublishStockChanged records the change of a stock record
+// 在庫記録の変化を記録
+func (r *dryRunRecorder) PublishStockChanged(ctx context.Context, event StockChangedEvent) error {
+	change := StockChange{
+		OperationIndex: r.index,
+		ItemID:         event.ItemID,
+		LocationID:     event.LocationID,
+		OldQuantity:    event.OldQuantity,
+		NewQuantity:    event.NewQuantity,
+	}
+	if event.Available != nil {
+		change.Available = *event.Available
+	}
+	r.changes = append(r.changes, change)
+	return nil
+}
+
+// PublishLowStockAlert marks the change of the current operation that triggers the alert
+// 実行中の操作でアラートが発生する在庫の変化を記録
+func (r *dryRunRecorder) PublishLowStockAlert(ctx context.Context, event LowStockAlertEvent) error {
+	for i := len(r.changes) - 1; i >= 0 && r.changes[i].OperationIndex == r.index; i-- {
+		if r.changes[i].ItemID == event.ItemID && r.changes[i].LocationID == event.LocationID {
+			r.changes[i].LowStock = true
+			break
+		}
+	}
+	return nil
+}
+
+// PublishItemTransferred ignores transfer events (both legs are recorded as stock changes)
+// 移動イベントは記録しない（移動元・移動先とも在庫の変化として記録済み）
+func (r *dryRunRecorder) PublishItemTransferred(ctx context.Context, event ItemTransferredEvent) error {
+	return nil
+}
@@ -49,6 +49,10 @@ var (
 	// トランザクション失敗時のエラー
 	ErrTransactionFailed = errors.New("トランザクションが失敗しました")
 
+	// ErrTransactionNotFound is returned when a transaction record doesn't exist
+	// トランザクション記録が存在しない場合のエラー
+	ErrTransactionNotFound = errors.New("トランザクション記録が見つかりません")
+
 	// ErrLotNotFound is returned when a lot doesn't exist
 	// ロットが存在しない場合のエラー
 	ErrLotNotFound = errors.New("ロットが見つかりません")
@@ -61,9 +65,177 @@ var (
 	// 予約が存在しない場合のエラー
 	ErrReservationNotFound = errors.New("予約が見つかりません")
 
+	// ErrReservationNotActive is returned when releasing a reservation that was already released or expired
+	// 解除済み・期限切れの予約を解除しようとした場合のエラー
+	ErrReservationNotActive = errors.New("予約は解除済みまたは期限切れです")
+
 	// ErrInsufficientReservation is returned when trying to release more than reserved
 	// 予約量を超えて解除しようとした場合のエラー
 	ErrInsufficientReservation = errors.New("予約量が不足しています")
+
+	// ErrBackorderNotFound is returned when a backorder doesn't exist
+	// バックオーダーが存在しない場合のエラー
+	ErrBackorderNotFound = errors.New("バックオーダーが見つかりません")
+
+	// ErrBackorderNotPending is returned when updating a backorder that was already allocated or cancelled
+	// 引当済み・取消済みのバックオーダーを更新しようとした場合のエラー
+	ErrBackorderNotPending = errors.New("バックオーダーは引当済みまたは取消済みです")
+
+	// ErrReorderPointNotFound is returned when no reorder point is set for a stock record
+	// 在庫記録に発注点が設定されていない場合のエラー
+	ErrReorderPointNotFound = errors.New("発注点が設定されていません")
+
+	// ErrLocationCapacityExceeded is returned when an operation would exceed the capacity of a location
+	// ロケーションの容量（Location.Capacity）を超える入荷・移動の場合のエラー
+	ErrLocationCapacityExceeded = errors.New("ロケーションの容量を超えています")
+
+	// ErrAlertNotFound is returned when an alert doesn't exist
+	// アラートが存在しない場合のエラー
+	ErrAlertNotFound = errors.New("アラートが見つかりません")
+
+	// ErrAlertNotActive is returned when acknowledging or resolving an alert that was already resolved
+	// 解決済みのアラートを確認・解決しようとした場合のエラー
+	ErrAlertNotActive = errors.New("アラートは解決済みです")
+
+	// ErrAlertAlreadyAcknowledged is returned when acknowledging an alert that was already acknowledged
+	// 確認済みのアラートを確認しようとした場合のエラー
+	ErrAlertAlreadyAcknowledged = errors.New("アラートは確認済みです")
+
+	// ErrAlertRuleNotFound is returned when an alert rule doesn't exist
+	// アラートルールが存在しない場合のエラー
+	ErrAlertRuleNotFound = errors.New("アラートルールが見つかりません")
+
+	// ErrBatchNotFound is returned when a batch operation doesn't exist
+	// バッチ操作が存在しない場合のエラー
+	ErrBatchNotFound = errors.New("バッチ操作が見つかりません")
+
+	// ErrSnapshotNotFound is returned when a stock snapshot doesn't exist
+	// 在庫スナップショットが存在しない場合のエラー
+	ErrSnapshotNotFound = errors.New("在庫スナップショットが見つかりません")
+
+	// ErrStocktakeNotFound is returned when a stocktake doesn't exist
+	// 棚卸が存在しない場合のエラー
+	ErrStocktakeNotFound = errors.New("棚卸が見つかりません")
+
+	// ErrStocktakeStatus is returned when an operation does not apply to the current status of a stocktake
+	// 棚卸の現在の状態では実行できない操作（差異確定後の実数の記録など）の場合のエラー
+	ErrStocktakeStatus = errors.New("棚卸の状態ではこの操作を実行できません")
+
+	// ErrAdjustmentNotFound is returned when a stock adjustment doesn't exist
+	// 在庫調整が存在しない場合のエラー
+	ErrAdjustmentNotFound = errors.New("在庫調整が見つかりません")
+
+	// ErrAdjustmentNotPending is returned when approving or rejecting an adjustment that is no longer pending
+	// 承認待ちでない（反映済み・却下済みの）在庫調整を承認・却下しようとした場合のエラー
+	ErrAdjustmentNotPending = errors.New("在庫調整は承認待ちではありません")
+
+	// ErrInspectionNotFound is returned when a receiving inspection doesn't exist
+	// 入荷検品が存在しない場合のエラー
+	ErrInspectionNotFound = errors.New("入荷検品が見つかりません")
+
+	// ErrInspectionNotQuarantined is returned when passing or failing an inspection that is no longer quarantined
+	// 検品待ちでない入荷検品の合否を判定しようとした場合のエラー
+	ErrInspectionNotQuarantined = errors.New("入荷検品は検品待ちではありません")
+
+	// ErrTransactionNotReversible is returned when a transaction cannot be reversed
+	// 取り消せないトランザクション（予約・予約解除・取消のトランザクション、数量が0の調整など）の場合のエラー
+	ErrTransactionNotReversible = errors.New("このトランザクションは取り消せません")
+
+	// ErrTransactionAlreadyReversed is returned when a transaction has already been reversed
+	// トランザクションが既に取り消されている場合のエラー
+	ErrTransactionAlreadyReversed = errors.New("トランザクションは既に取り消されています")
+
+	// ErrInsufficientLotStock is returned when the lots of an item cannot cover a remove or transfer
+	// ロットを消費する出庫で有効期限内のロットの数量が不足する場合、またはロットのロケーションの在庫が不足する場合のエラー
+	ErrInsufficientLotStock = errors.New("ロットの在庫が不足しています")
+
+	// ErrInsufficientConsignmentStock is returned when an owner's consignment stock cannot cover a remove or transfer
+	// 所有者（仕入先）を指定した出庫・移動で、所有者のロケーションの委託在庫が不足する場合のエラー
+	ErrInsufficientConsignmentStock = errors.New("委託在庫が不足しています")
+
+	// ErrConsignmentStockMismatch is returned when the consignment stocks of a stock record exceed its quantity
+	// 委託在庫の数量の合計が在庫数量を超えている（記録が不整合な）場合のエラー
+	ErrConsignmentStockMismatch = errors.New("委託在庫の数量が在庫数量を超えています")
+
+	// ErrLotStockNotFound is returned when a lot has no stock record at a location
+	// ロットのロケーションの在庫記録が存在しない場合のエラー（ストレージ内部で使用）
+	ErrLotStockNotFound = errors.New("ロットのロケーション在庫が見つかりません")
+
+	// ErrUnitNotFound is returned when a unit of measure is not registered
+	// 単位が登録されていない場合のエラー
+	ErrUnitNotFound = errors.New("単位が見つかりません")
+
+	// ErrDuplicateUnit is returned when trying to register a unit of measure that already exists
+	// 既に登録されている単位を登録しようとした場合のエラー
+	ErrDuplicateUnit = errors.New("単位は既に存在します")
+
+	// ErrUnitInUse is returned when deleting a unit of measure that items or conversions still use
+	// 商品の基本単位や換算で使用中の単位を削除しようとした場合のエラー
+	ErrUnitInUse = errors.New("単位は使用中です")
+
+	// ErrUnitConversionNotFound is returned when an item has no conversion for a unit of measure
+	// 商品に単位の換算が登録されていない場合のエラー
+	ErrUnitConversionNotFound = errors.New("単位の換算が見つかりません")
+
+	// ErrBOMNotFound is returned when a kit has no bill of materials
+	// キットに部品表が登録されていない場合のエラー
+	ErrBOMNotFound = errors.New("部品表が見つかりません")
+
+	// ErrAssemblyNotFound is returned when a kit assembly or disassembly does not exist
+	// キットの組立・分解の記録が存在しない場合のエラー
+	ErrAssemblyNotFound = errors.New("組立の記録が見つかりません")
+
+	// ErrItemInUse is returned when deleting an item that a bill of materials uses as a component
+	// 部品表の構成品として使用中の商品を削除しようとした場合のエラー
+	ErrItemInUse = errors.New("商品は部品表の構成品として使用中です")
+
+	// ErrSupplierNotFound is returned when a supplier doesn't exist
+	// 仕入先が存在しない場合のエラー
+	ErrSupplierNotFound = errors.New("仕入先が見つかりません")
+
+	// ErrDuplicateSupplier is returned when trying to create a supplier that already exists
+	// 既に存在する仕入先を作成しようとした場合のエラー
+	ErrDuplicateSupplier = errors.New("仕入先は既に存在します")
+
+	// ErrSupplierItemNotFound is returned when a supplier has no purchasing terms for an item
+	// 仕入先に商品の仕入条件が登録されていない場合のエラー
+	ErrSupplierItemNotFound = errors.New("仕入先の商品が見つかりません")
+
+	// ErrPurchaseOrderNotFound is returned when a purchase order doesn't exist
+	// 発注が存在しない場合のエラー
+	ErrPurchaseOrderNotFound = errors.New("発注が見つかりません")
+
+	// ErrPurchaseOrderStatus is returned when an operation does not apply to the current status of a purchase order
+	// 発注の現在の状態では実行できない操作（送付前の入荷の記録など）の場合のエラー
+	ErrPurchaseOrderStatus = errors.New("発注の状態ではこの操作を実行できません")
+
+	// ErrReceiptExceedsOrder is returned when a goods receipt exceeds the outstanding quantity of a purchase order line
+	// 入荷数量が発注の行の発注残を超える場合のエラー
+	ErrReceiptExceedsOrder = errors.New("入荷数量が発注残を超えています")
+
+	// ErrSalesOrderNotFound is returned when a sales order doesn't exist
+	// 受注が存在しない場合のエラー
+	ErrSalesOrderNotFound = errors.New("受注が見つかりません")
+
+	// ErrSalesOrderStatus is returned when an operation does not apply to the current status of a sales order
+	// 受注の現在の状態では実行できない操作（出荷確定後の引当など）の場合のエラー
+	ErrSalesOrderStatus = errors.New("受注の状態ではこの操作を実行できません")
+
+	// ErrReturnNotFound is returned when a return (RMA) doesn't exist
+	// 返品（RMA）が存在しない場合のエラー
+	ErrReturnNotFound = errors.New("返品が見つかりません")
+
+	// ErrReturnStatus is returned when an operation does not apply to the current status of a return
+	// 返品の現在の状態では実行できない操作（取消済みの返品の到着の記録など）の場合のエラー
+	ErrReturnStatus = errors.New("返品の状態ではこの操作を実行できません")
+
+	// ErrReturnQuantityExceeded is returned when a received or dispositioned quantity exceeds what the return allows
+	// 到着数量が返品許可数量の残り、または処分数量が検品待ちの数量を超える場合のエラー
+	ErrReturnQuantityExceeded = errors.New("返品の数量が返品許可数量または検品待ちの数量を超えています")
+
+	// ErrPreconditionFailed is returned when a record no longer has the expected version
+	// 更新対象が想定したバージョンでない場合のエラー（再試行しない）
+	ErrPreconditionFailed = errors.New("更新対象が想定したバージョンではありません。他のユーザーによって更新されています")
 )
 
 // ValidationError represents a validation error with details
@@ -121,6 +293,23 @@ func (e StorageError) Unwrap() error {
 	return e.Cause
 }
 
+// BackorderedError is returned when an operation failed for lack of stock and a backorder was created
+// 在庫不足で操作が失敗し、バックオーダーを作成した場合のエラー
+//
+// errors.Is(err, ErrInsufficientStock) は true を返します。
+type BackorderedError struct {
+	Backorder *Backorder // 作成したバックオーダー
+	Cause     error      // 操作が失敗した原因
+}
+
+func (e *BackorderedError) Error() string {
+	return fmt.Sprintf("%v（バックオーダー %s を作成しました）", e.Cause, e.Backorder.ID)
+}
+
+func (e *BackorderedError) Unwrap() error {
+	return e.Cause
+}
+
 // NewValidationError creates a new validation error
 // 新しいバリデーションエラーを作成
 func NewValidationError(field, message, value string) *ValidationError {
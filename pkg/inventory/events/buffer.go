@@ -0,0 +1,599 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// Errors returned by BufferedPublisher
+// BufferedPublisherが返すエラー
+var (
+	ErrBufferFull   = errors.New("イベントバッファが満杯です")
+	ErrBufferClosed = errors.New("イベントバッファは停止しています")
+)
+
+// BufferConfig holds settings for buffered publishing
+// バッファ付き発行の設定
+type BufferConfig struct {
+	Capacity     int           // バッファに保持できる未配信イベント数（満杯の場合は発行エラー）
+	Path         string        // ジャーナルファイルのパス（空の場合はメモリのみ）
+	BaseDelay    time.Duration // 配信失敗時の初回待機時間（試行ごとに倍増）
+	MaxDelay     time.Duration // 配信失敗時の最大待機時間
+	Timeout      time.Duration // 1回の配信のタイムアウト
+	DrainTimeout time.Duration // Close時に未配信のイベントを配信し続ける最大時間
+}
+
+// DefaultBufferConfig returns the settings used for zero values
+// 未設定の項目に使用するバッファ設定を返す
+func DefaultBufferConfig() BufferConfig {
+	return BufferConfig{
+		Capacity:     10000,
+		BaseDelay:    500 * time.Millisecond,
+		MaxDelay:     30 * time.Second,
+		Timeout:      10 * time.Second,
+		DrainTimeout: 5 * time.Second,
+	}
+}
+
+// bufferedEvent is an event waiting in the buffer
+// バッファで配信を待っているイベント
+type bufferedEvent struct {
+	Seq     uint64          `json:"seq"`     // バッファ内の通し番号（配信順序と確認に使用）
+	Type    string          `json:"type"`    // イベントタイプ（TypeStockChangedなど）
+	Payload json.RawMessage `json:"payload"` // EncodeEventでエンコードしたイベント本体
+}
+
+// bufferMetrics holds the Prometheus collectors for buffered publishing
+// バッファ付き発行用のPrometheusコレクター
+type bufferMetrics struct {
+	pending  prometheus.Gauge
+	rejected *prometheus.CounterVec
+	failures *prometheus.CounterVec
+}
+
+// BufferedPublisher queues events in memory and delivers them to the wrapped publisher in the background
+// イベントをメモリ上のバッファに積み、バックグラウンドでラップした発行者へ配信する
+//
+// 発行は積んだ時点で完了するため、ブローカーの遅延や短時間の停止が在庫操作の応答時間に影響しません。
+// イベントはラップした発行者が成功を返した（確認した）時点でバッファから取り除かれ、
+// 失敗した場合は指数バックオフで同じイベントを再試行します（最低1回の配信・発生順を維持）。
+// Pathを指定するとイベントと確認をジャーナルファイルに追記し、プロセスの再起動後に未確認のイベントから再開します。
+//
+// 記録するメトリクス:
+//   - zai_inventory_events_buffer_pending: バッファ内の未配信イベント数
+//   - zai_inventory_events_buffer_rejected_total: バッファが満杯のため受け付けなかったイベント数
+//   - zai_inventory_events_buffer_delivery_failures_total: バッファからの配信に失敗した回数
+type BufferedPublisher struct {
+	next    ClosablePublisher
+	cfg     BufferConfig
+	metrics *bufferMetrics
+	logger  *zap.Logger
+
+	mu      sync.Mutex
+	pending []bufferedEvent
+	nextSeq uint64
+	journal *bufferJournal
+	closed  bool
+
+	notify    chan struct{}
+	stop      chan struct{}
+	ctx       context.Context // 配信に使用するコンテキスト（Closeで未配信分を待ちきれない場合にキャンセル）
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+var (
+	_ ClosablePublisher              = (*BufferedPublisher)(nil)
+	_ inventory.DomainEventPublisher = (*BufferedPublisher)(nil)
+)
+
+// NewBufferedPublisher creates a buffered publisher and starts the delivery loop
+// バッファ付きの発行者を作成し、配信ループを開始
+//
+// cfg.Pathのジャーナルに未確認のイベントが残っている場合は、それらから配信を再開します。
+// registererがnilの場合はprometheus.DefaultRegistererに登録します。
+func NewBufferedPublisher(next ClosablePublisher, cfg BufferConfig, registerer prometheus.Registerer, logger *zap.Logger) (*BufferedPublisher, error) {
+	defaults := DefaultBufferConfig()
+	if cfg.Capacity <= 0 {
+		cfg.Capacity = defaults.Capacity
+	}
+	if cfg.BaseDelay <= 0 {
+		cfg.BaseDelay = defaults.BaseDelay
+	}
+	if cfg.MaxDelay <= 0 {
+		cfg.MaxDelay = defaults.MaxDelay
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaults.Timeout
+	}
+	if cfg.DrainTimeout <= 0 {
+		cfg.DrainTimeout = defaults.DrainTimeout
+	}
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+
+	metrics := &bufferMetrics{
+		pending: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "zai_inventory",
+			Subsystem: "events",
+			Name:      "buffer_pending",
+			Help:      "バッファ内の未配信イベント数",
+		}),
+		rejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "zai_inventory",
+			Subsystem: "events",
+			Name:      "buffer_rejected_total",
+			Help:      "バッファが満杯のため受け付けなかったイベント数",
+		}, []string{"type"}),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "zai_inventory",
+			Subsystem: "events",
+			Name:      "buffer_delivery_failures_total",
+			Help:      "バッファからの配信に失敗した回数",
+		}, []string{"type"}),
+	}
+
+	var err error
+	if metrics.pending, err = registerCollector(registerer, metrics.pending); err != nil {
+		return nil, err
+	}
+	if metrics.rejected, err = registerCollector(registerer, metrics.rejected); err != nil {
+		return nil, err
+	}
+	if metrics.failures, err = registerCollector(registerer, metrics.failures); err != nil {
+		return nil, err
+	}
+
+	p := &BufferedPublisher{
+		next:    next,
+		cfg:     cfg,
+		metrics: metrics,
+		logger:  logger,
+		notify:  make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+	}
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+
+	if cfg.Path != "" {
+		journal, restored, err := openBufferJournal(cfg.Path, logger)
+		if err != nil {
+			p.cancel()
+			return nil, err
+		}
+		p.journal = journal
+		p.pending = restored
+		if len(restored) > 0 {
+			p.nextSeq = restored[len(restored)-1].Seq
+			logger.Info("ジャーナルから未配信のイベントを復元しました",
+				zap.String("path", cfg.Path),
+				zap.Int("events", len(restored)),
+			)
+		}
+	}
+	p.metrics.pending.Set(float64(len(p.pending)))
+
+	p.wg.Add(1)
+	go p.run()
+
+	return p, nil
+}
+
+// PublishStockChanged queues a stock changed event
+// 在庫変更イベントをバッファに積む
+func (p *BufferedPublisher) PublishStockChanged(ctx context.Context, event inventory.StockChangedEvent) error {
+	return p.enqueue(TypeStockChanged, event)
+}
+
+// PublishLowStockAlert queues a low stock alert event
+// 低在庫アラートイベントをバッファに積む
+func (p *BufferedPublisher) PublishLowStockAlert(ctx context.Context, event inventory.LowStockAlertEvent) error {
+	return p.enqueue(TypeLowStockAlert, event)
+}
+
+// PublishItemTransferred queues an item transferred event
+// 商品移動イベントをバッファに積む
+func (p *BufferedPublisher) PublishItemTransferred(ctx context.Context, event inventory.ItemTransferredEvent) error {
+	return p.enqueue(TypeItemTransferred, event)
+}
+
+// PublishEvent queues a domain event
+// ドメインイベントをバッファに積む
+//
+// ラップした発行者がDomainEventPublisherを実装していない場合は何もしません。
+func (p *BufferedPublisher) PublishEvent(ctx context.Context, event inventory.DomainEvent) error {
+	if _, ok := p.next.(inventory.DomainEventPublisher); !ok {
+		return nil
+	}
+	return p.enqueue(event.Type, event)
+}
+
+// Pending returns the number of events not yet acknowledged by the wrapped publisher
+// ラップした発行者がまだ確認していないイベント数を返す
+func (p *BufferedPublisher) Pending() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.pending)
+}
+
+// Ping checks the broker connection of the wrapped publisher and reports an error when the buffer is full
+// ラップした発行者のブローカーとの接続を確認し、バッファが満杯の場合はエラーを返す
+func (p *BufferedPublisher) Ping(ctx context.Context) error {
+	if pending := p.Pending(); pending >= p.cfg.Capacity {
+		return fmt.Errorf("イベントバッファが満杯です（%d件）", pending)
+	}
+	return Ping(ctx, p.next)
+}
+
+// Close stops accepting events, delivers the remaining ones for up to DrainTimeout and closes the wrapped publisher
+// 受け付けを停止し、最大DrainTimeoutの間残りのイベントを配信してから、ラップした発行者を閉じる
+//
+// 配信しきれなかったイベントは、ジャーナルを使用している場合は次回起動時に配信されます。
+func (p *BufferedPublisher) Close() error {
+	p.closeOnce.Do(func() {
+		p.mu.Lock()
+		p.closed = true
+		p.mu.Unlock()
+		close(p.stop)
+
+		done := make(chan struct{})
+		go func() {
+			p.wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(p.cfg.DrainTimeout):
+			p.cancel()
+			<-done
+		}
+		p.cancel()
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if len(p.pending) > 0 {
+			if p.journal != nil {
+				p.logger.Warn("未配信のイベントをジャーナルに残して停止します（次回起動時に配信します）",
+					zap.Int("events", len(p.pending)))
+			} else {
+				p.logger.Error("未配信のイベントを破棄して停止します", zap.Int("events", len(p.pending)))
+			}
+		}
+		if p.journal != nil {
+			if err := p.journal.close(); err != nil {
+				p.logger.Error("イベントジャーナルのクローズに失敗しました", zap.Error(err))
+			}
+		}
+	})
+	return p.next.Close()
+}
+
+// enqueue encodes event and appends it to the buffer
+// イベントをエンコードしてバッファに追加
+func (p *BufferedPublisher) enqueue(eventType string, event interface{}) error {
+	// 再起動後にデコードできるよう最新のスキーマバージョンで保存する
+	payload, err := EncodeEvent(event, CurrentSchemaVersion)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return ErrBufferClosed
+	}
+	if len(p.pending) >= p.cfg.Capacity {
+		p.metrics.rejected.WithLabelValues(eventType).Inc()
+		return fmt.Errorf("%w（%d 件）", ErrBufferFull, p.cfg.Capacity)
+	}
+
+	entry := bufferedEvent{Seq: p.nextSeq + 1, Type: eventType, Payload: payload}
+	if p.journal != nil {
+		if err := p.journal.append(entry); err != nil {
+			return fmt.Errorf("イベントジャーナルへの書き込みに失敗しました: %w", err)
+		}
+	}
+	p.nextSeq = entry.Seq
+	p.pending = append(p.pending, entry)
+	p.metrics.pending.Set(float64(len(p.pending)))
+
+	// 配信ループが待機中の場合に起こす（既に通知済みなら何もしない）
+	select {
+	case p.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// run delivers buffered events in order until Close is called and the buffer is drained
+// Closeが呼ばれてバッファが空になるまで、バッファのイベントを順番に配信
+func (p *BufferedPublisher) run() {
+	defer p.wg.Done()
+
+	attempts := 0
+	for {
+		entry, ok := p.peek()
+		if !ok {
+			select {
+			case <-p.notify:
+				continue
+			case <-p.stop:
+				return
+			}
+		}
+
+		err := p.deliver(entry)
+		if err == nil {
+			attempts = 0
+			p.ack(entry.Seq)
+			continue
+		}
+		if p.ctx.Err() != nil {
+			return
+		}
+
+		attempts++
+		p.metrics.failures.WithLabelValues(entry.Type).Inc()
+		p.logger.Warn("バッファしたイベントの配信に失敗したため再試行します",
+			zap.Uint64("seq", entry.Seq),
+			zap.String("type", entry.Type),
+			zap.Int("attempts", attempts),
+			zap.Error(err),
+		)
+
+		timer := time.NewTimer(p.backoff(attempts))
+		select {
+		case <-timer.C:
+		case <-p.ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// peek returns the oldest unacknowledged event
+// 最も古い未確認のイベントを返す
+func (p *BufferedPublisher) peek() (bufferedEvent, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.pending) == 0 {
+		return bufferedEvent{}, false
+	}
+	return p.pending[0], true
+}
+
+// deliver publishes entry through the wrapped publisher
+// イベントをラップした発行者から発行
+func (p *BufferedPublisher) deliver(entry bufferedEvent) error {
+	ctx, cancel := context.WithTimeout(p.ctx, p.cfg.Timeout)
+	defer cancel()
+	return publishEncoded(ctx, p.next, entry.Type, entry.Payload)
+}
+
+// ack removes the delivered event from the buffer and records the acknowledgement
+// 配信したイベントをバッファから取り除き、確認を記録
+func (p *BufferedPublisher) ack(seq uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.pending) == 0 || p.pending[0].Seq != seq {
+		return
+	}
+	p.pending[0] = bufferedEvent{}
+	p.pending = p.pending[1:]
+	p.metrics.pending.Set(float64(len(p.pending)))
+
+	if p.journal == nil {
+		return
+	}
+	// 確認の記録に失敗した場合は再起動後に再送される（最低1回の配信は保たれる）
+	if err := p.journal.ack(seq); err != nil {
+		p.logger.Error("イベントジャーナルへの確認の記録に失敗しました", zap.Uint64("seq", seq), zap.Error(err))
+		return
+	}
+	// 確認済みの記録が溜まったら未確認のイベントだけに書き直す
+	if p.journal.acked >= p.cfg.Capacity {
+		if err := p.journal.compact(p.pending); err != nil {
+			p.logger.Error("イベントジャーナルの圧縮に失敗しました", zap.Error(err))
+		}
+	}
+}
+
+// backoff returns the wait before the next attempt after attempts failures
+// attempts回失敗した後の次回までの待機時間を返す
+func (p *BufferedPublisher) backoff(attempts int) time.Duration {
+	delay := p.cfg.BaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= p.cfg.MaxDelay {
+			return p.cfg.MaxDelay
+		}
+	}
+	return delay
+}
+
+// bufferJournalRecord is one line of the journal file
+// ジャーナルファイルの1行
+//
+// イベントの追加はSeq・Type・Payload、確認はAckのみを持ちます。
+type bufferJournalRecord struct {
+	Seq     uint64          `json:"seq,omitempty"`
+	Type    string          `json:"type,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+	Ack     uint64          `json:"ack,omitempty"`
+}
+
+// bufferJournal is an append-only file of buffered events and acknowledgements
+// バッファしたイベントと確認を追記するジャーナルファイル
+//
+// 書き込みごとのfsyncは行わないため、プロセスの異常終了には耐えますが、
+// OSのクラッシュ時には直近の書き込みが失われる場合があります。
+type bufferJournal struct {
+	path  string
+	file  *os.File
+	acked int // 前回の圧縮以降に記録した確認の数
+}
+
+// openBufferJournal reads the journal at path and returns the unacknowledged events in order
+// ジャーナルを読み込み、未確認のイベントを順番に返す
+//
+// 読み込み後、未確認のイベントだけを残すようにジャーナルを書き直します。
+func openBufferJournal(path string, logger *zap.Logger) (*bufferJournal, []bufferedEvent, error) {
+	var restored []bufferedEvent
+	file, err := os.Open(path)
+	switch {
+	case err == nil:
+		restored, err = readBufferJournal(file, logger)
+		file.Close()
+		if err != nil {
+			return nil, nil, fmt.Errorf("イベントジャーナルの読み込みに失敗しました: %w", err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, nil, fmt.Errorf("イベントジャーナルを開けませんでした: %w", err)
+	}
+
+	journal := &bufferJournal{path: path}
+	if err := journal.compact(restored); err != nil {
+		return nil, nil, err
+	}
+	return journal, restored, nil
+}
+
+// readBufferJournal replays the journal records and returns the unacknowledged events
+// ジャーナルの記録を再生し、未確認のイベントを返す
+func readBufferJournal(r io.Reader, logger *zap.Logger) ([]bufferedEvent, error) {
+	var events []bufferedEvent
+	acked := make(map[uint64]bool)
+
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var record bufferJournalRecord
+			switch {
+			case json.Unmarshal(line, &record) != nil:
+				// 書き込み途中で停止した行などは読み飛ばす
+				logger.Warn("イベントジャーナルの不正な行を読み飛ばしました", zap.Int("bytes", len(line)))
+			case record.Ack != 0:
+				acked[record.Ack] = true
+			case record.Seq != 0 && IsKnownType(record.Type):
+				events = append(events, bufferedEvent{Seq: record.Seq, Type: record.Type, Payload: record.Payload})
+			default:
+				logger.Warn("イベントジャーナルの不明なイベントを読み飛ばしました",
+					zap.Uint64("seq", record.Seq), zap.String("type", record.Type))
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pending := events[:0]
+	for _, event := range events {
+		if !acked[event.Seq] {
+			pending = append(pending, event)
+		}
+	}
+	return pending, nil
+}
+
+// append records a buffered event
+// バッファしたイベントを記録
+func (j *bufferJournal) append(entry bufferedEvent) error {
+	return j.write(bufferJournalRecord{Seq: entry.Seq, Type: entry.Type, Payload: entry.Payload})
+}
+
+// ack records that the event with seq was delivered
+// 通し番号seqのイベントが配信されたことを記録
+func (j *bufferJournal) ack(seq uint64) error {
+	if err := j.write(bufferJournalRecord{Ack: seq}); err != nil {
+		return err
+	}
+	j.acked++
+	return nil
+}
+
+// write appends one record as a JSON line
+// 記録を1行のJSONとして追記
+func (j *bufferJournal) write(record bufferJournalRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = j.file.Write(append(line, '\n'))
+	return err
+}
+
+// compact rewrites the journal so that it holds only pending events
+// 未確認のイベントだけを持つようにジャーナルを書き直す
+//
+// 一時ファイルに書き出してから置き換えるため、途中で停止しても元のジャーナルは残ります。
+func (j *bufferJournal) compact(pending []bufferedEvent) error {
+	tmpPath := j.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("イベントジャーナルの書き直しに失敗しました: %w", err)
+	}
+
+	writer := bufio.NewWriter(tmp)
+	for _, entry := range pending {
+		line, err := json.Marshal(bufferJournalRecord{Seq: entry.Seq, Type: entry.Type, Payload: entry.Payload})
+		if err == nil {
+			writer.Write(line)
+			err = writer.WriteByte('\n')
+		}
+		if err != nil {
+			tmp.Close()
+			return fmt.Errorf("イベントジャーナルの書き直しに失敗しました: %w", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("イベントジャーナルの書き直しに失敗しました: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("イベントジャーナルの書き直しに失敗しました: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("イベントジャーナルの書き直しに失敗しました: %w", err)
+	}
+	if err := os.Rename(tmpPath, j.path); err != nil {
+		return fmt.Errorf("イベントジャーナルの置き換えに失敗しました: %w", err)
+	}
+
+	file, err := os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("イベントジャーナルを開けませんでした: %w", err)
+	}
+	if j.file != nil {
+		j.file.Close()
+	}
+	j.file = file
+	j.acked = 0
+	return nil
+}
+
+// close closes the journal file
+// ジャーナルファイルを閉じる
+func (j *bufferJournal) close() error {
+	return j.file.Close()
+}
@@ -0,0 +1,137 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// flakySender は指定回数だけ送信に失敗するテスト用Sender（配信ループから並行に呼ばれる）
+type flakySender struct {
+	mu       sync.Mutex
+	failures int // 残りの失敗回数（負の場合は常に失敗）
+	messages []Message
+}
+
+func (s *flakySender) Send(ctx context.Context, msg Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failures != 0 {
+		if s.failures > 0 {
+			s.failures--
+		}
+		return errors.New("broker unavailable")
+	}
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+func (s *flakySender) Close() error { return nil }
+
+// ids returns the IDs of the sent messages
+func (s *flakySender) ids() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, 0, len(s.messages))
+	for _, msg := range s.messages {
+		ids = append(ids, msg.ID)
+	}
+	return ids
+}
+
+// newTestBufferedPublisher は待機時間を短くしたバッファ付き発行者を作成
+func newTestBufferedPublisher(t *testing.T, sender Sender, cfg BufferConfig) *BufferedPublisher {
+	t.Helper()
+	if cfg.BaseDelay == 0 {
+		cfg.BaseDelay = time.Millisecond
+		cfg.MaxDelay = time.Millisecond
+	}
+	publisher, err := NewBufferedPublisher(NewPublisher(sender), cfg, prometheus.NewRegistry(), zap.NewNop())
+	require.NoError(t, err)
+	return publisher
+}
+
+// TestBufferedPublisher_DeliversInOrderAfterFailures は配信失敗後も発生順に配信されるテスト
+func TestBufferedPublisher_DeliversInOrderAfterFailures(t *testing.T) {
+	sender := &flakySender{failures: 3}
+	publisher := newTestBufferedPublisher(t, sender, BufferConfig{})
+	ctx := context.Background()
+
+	// ブローカーが失敗していても発行はすぐに完了する
+	for _, id := range []string{"TX-1", "TX-2", "TX-3"} {
+		require.NoError(t, publisher.PublishStockChanged(ctx, inventory.StockChangedEvent{ItemID: "ITEM-1", TransactionID: id}))
+	}
+
+	require.Eventually(t, func() bool { return publisher.Pending() == 0 }, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"TX-1", "TX-2", "TX-3"}, sender.ids())
+	assert.Equal(t, float64(3), testutil.ToFloat64(publisher.metrics.failures.WithLabelValues(TypeStockChanged)))
+	assert.Equal(t, float64(0), testutil.ToFloat64(publisher.metrics.pending))
+
+	require.NoError(t, publisher.Close())
+	assert.ErrorIs(t, publisher.PublishStockChanged(ctx, inventory.StockChangedEvent{ItemID: "ITEM-1"}), ErrBufferClosed)
+}
+
+// TestBufferedPublisher_Full はバッファが満杯の場合に発行エラーになるテスト
+func TestBufferedPublisher_Full(t *testing.T) {
+	sender := &flakySender{failures: -1}
+	publisher := newTestBufferedPublisher(t, sender, BufferConfig{
+		Capacity:     2,
+		BaseDelay:    time.Hour,
+		MaxDelay:     time.Hour,
+		DrainTimeout: time.Millisecond,
+	})
+	defer publisher.Close()
+	ctx := context.Background()
+
+	require.NoError(t, publisher.PublishLowStockAlert(ctx, inventory.LowStockAlertEvent{ItemID: "ITEM-1"}))
+	require.NoError(t, publisher.PublishLowStockAlert(ctx, inventory.LowStockAlertEvent{ItemID: "ITEM-2"}))
+
+	err := publisher.PublishLowStockAlert(ctx, inventory.LowStockAlertEvent{ItemID: "ITEM-3"})
+	assert.ErrorIs(t, err, ErrBufferFull)
+	assert.Equal(t, 2, publisher.Pending())
+	assert.Equal(t, float64(1), testutil.ToFloat64(publisher.metrics.rejected.WithLabelValues(TypeLowStockAlert)))
+}
+
+// TestBufferedPublisher_JournalRestoresPending は再起動後にジャーナルから未配信のイベントを配信するテスト
+func TestBufferedPublisher_JournalRestoresPending(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.journal")
+	ctx := context.Background()
+
+	// ブローカーが停止したままプロセスを停止する
+	down := &flakySender{failures: -1}
+	publisher := newTestBufferedPublisher(t, down, BufferConfig{Path: path, DrainTimeout: 10 * time.Millisecond})
+	require.NoError(t, publisher.PublishStockChanged(ctx, inventory.StockChangedEvent{ItemID: "ITEM-1", TransactionID: "TX-1"}))
+	require.NoError(t, publisher.PublishItemTransferred(ctx, inventory.ItemTransferredEvent{ItemID: "ITEM-1", TransactionID: "TX-2"}))
+	require.NoError(t, publisher.Close())
+	assert.Empty(t, down.ids())
+
+	// 再起動後は未確認のイベントから配信を再開する
+	up := &flakySender{}
+	publisher = newTestBufferedPublisher(t, up, BufferConfig{Path: path})
+	require.Eventually(t, func() bool { return publisher.Pending() == 0 }, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"TX-1", "TX-2"}, up.ids())
+
+	require.NoError(t, publisher.PublishStockChanged(ctx, inventory.StockChangedEvent{ItemID: "ITEM-1", TransactionID: "TX-3"}))
+	require.Eventually(t, func() bool { return publisher.Pending() == 0 }, time.Second, time.Millisecond)
+	require.NoError(t, publisher.Close())
+
+	// 確認済みのイベントは再送されない
+	again := &flakySender{}
+	publisher = newTestBufferedPublisher(t, again, BufferConfig{Path: path})
+	assert.Equal(t, 0, publisher.Pending())
+	require.NoError(t, publisher.Close())
+	assert.Empty(t, again.ids())
+}
@@ -0,0 +1,279 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// AllEvents subscribes a handler to every event type
+// 全てのイベントタイプを購読する場合に指定するタイプ
+const AllEvents = "*"
+
+// ErrBusClosed is returned when publishing to a closed bus
+// 停止済みのバスに発行した場合に返される
+var ErrBusClosed = errors.New("イベントバスは停止しています")
+
+// Event is an event delivered by the in-process bus
+// プロセス内イベントバスが配信するイベント
+//
+// Payloadは発行されたイベントの構造体そのもの（inventory.StockChangedEventなど）です。
+// ドメインイベントの場合はinventory.DomainEventが入ります。
+type Event struct {
+	ID         string      // イベントID（在庫変更・移動ではトランザクションID）
+	Type       string      // イベントタイプ（TypeStockChangedなど）
+	ItemID     string      // 商品ID
+	LocationID string      // ロケーションID（移動イベントでは移動元）
+	Payload    interface{} // イベント本体
+	Timestamp  time.Time   // イベント発生日時
+}
+
+// Handler handles an event delivered by the bus
+// バスから配信されたイベントを処理するハンドラー
+type Handler func(ctx context.Context, event Event) error
+
+// BusConfig holds settings for the in-process bus
+// プロセス内イベントバスの設定
+type BusConfig struct {
+	Workers   int // 非同期配信のワーカー数（0の場合は発行元のゴルーチンで同期的に配信）
+	QueueSize int // 非同期配信の待ちキューの長さ（満杯の場合は発行エラー）
+}
+
+// subscription is a handler registered for an event type
+// イベントタイプに登録されたハンドラー
+type subscription struct {
+	id        uint64
+	eventType string
+	handler   Handler
+}
+
+// busJob is a queued asynchronous delivery
+// 非同期配信のジョブ
+type busJob struct {
+	ctx      context.Context
+	event    Event
+	handlers []subscription
+}
+
+// Bus delivers published events to handlers subscribed in the same process
+// 同じプロセス内で購読しているハンドラーへイベントを配信するバス
+//
+// メッセージブローカーを使わないモノリス構成で、在庫変更などのイベントをアプリケーションコードから
+// 受け取るために使用します。同期モードではハンドラーのエラーが発行元に返されます。
+// 非同期モードではワーカーが配信し、ハンドラーのエラーはログに記録されます。
+// 複数のワーカーを使用する場合、イベントの配信順序は保証されません（順序が必要な場合はWorkersを1にします）。
+type Bus struct {
+	mu            sync.RWMutex
+	subscriptions []subscription
+	nextID        uint64
+
+	cfg    BusConfig
+	queue  chan busJob
+	wg     sync.WaitGroup
+	closed bool
+	logger *zap.Logger
+}
+
+var (
+	_ ClosablePublisher              = (*Bus)(nil)
+	_ inventory.DomainEventPublisher = (*Bus)(nil)
+)
+
+// NewBus creates an in-process bus and starts its workers
+// プロセス内イベントバスを作成し、ワーカーを起動
+func NewBus(cfg BusConfig, logger *zap.Logger) *Bus {
+	if cfg.Workers > 0 && cfg.QueueSize <= 0 {
+		cfg.QueueSize = 1000
+	}
+
+	b := &Bus{cfg: cfg, logger: logger}
+	if cfg.Workers > 0 {
+		b.queue = make(chan busJob, cfg.QueueSize)
+		for i := 0; i < cfg.Workers; i++ {
+			b.wg.Add(1)
+			go b.work()
+		}
+	}
+	return b
+}
+
+// Subscribe registers handler for eventType and returns a function that unregisters it
+// eventTypeのハンドラーを登録し、登録を解除する関数を返す
+//
+// AllEventsを指定すると全てのイベントを受け取ります。
+func (b *Bus) Subscribe(eventType string, handler Handler) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	id := b.nextID
+	b.subscriptions = append(b.subscriptions, subscription{id: id, eventType: eventType, handler: handler})
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { b.unsubscribe(id) })
+	}
+}
+
+// unsubscribe removes the subscription with id
+// 指定IDの購読を解除
+func (b *Bus) unsubscribe(id uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i, sub := range b.subscriptions {
+		if sub.id == id {
+			// 配信中のスナップショットを壊さないよう新しいスライスを作る
+			subscriptions := make([]subscription, 0, len(b.subscriptions)-1)
+			subscriptions = append(subscriptions, b.subscriptions[:i]...)
+			b.subscriptions = append(subscriptions, b.subscriptions[i+1:]...)
+			return
+		}
+	}
+}
+
+// PublishStockChanged delivers a stock changed event
+// 在庫変更イベントを配信
+func (b *Bus) PublishStockChanged(ctx context.Context, event inventory.StockChangedEvent) error {
+	return b.publish(ctx, Event{
+		ID:         event.TransactionID,
+		Type:       TypeStockChanged,
+		ItemID:     event.ItemID,
+		LocationID: event.LocationID,
+		Payload:    event,
+		Timestamp:  event.Timestamp,
+	})
+}
+
+// PublishLowStockAlert delivers a low stock alert event
+// 低在庫アラートイベントを配信
+func (b *Bus) PublishLowStockAlert(ctx context.Context, event inventory.LowStockAlertEvent) error {
+	return b.publish(ctx, Event{
+		ID:         inventory.NewTransactionID(),
+		Type:       TypeLowStockAlert,
+		ItemID:     event.ItemID,
+		LocationID: event.LocationID,
+		Payload:    event,
+		Timestamp:  event.Timestamp,
+	})
+}
+
+// PublishItemTransferred delivers an item transferred event
+// 商品移動イベントを配信
+func (b *Bus) PublishItemTransferred(ctx context.Context, event inventory.ItemTransferredEvent) error {
+	return b.publish(ctx, Event{
+		ID:         event.TransactionID,
+		Type:       TypeItemTransferred,
+		ItemID:     event.ItemID,
+		LocationID: event.FromLocationID,
+		Payload:    event,
+		Timestamp:  event.Timestamp,
+	})
+}
+
+// PublishEvent delivers a domain event
+// ドメインイベントを配信
+func (b *Bus) PublishEvent(ctx context.Context, event inventory.DomainEvent) error {
+	return b.publish(ctx, Event{
+		ID:         event.ID,
+		Type:       event.Type,
+		ItemID:     event.ItemID,
+		LocationID: event.LocationID,
+		Payload:    event,
+		Timestamp:  event.Timestamp,
+	})
+}
+
+// Close stops accepting events and waits for queued deliveries to finish
+// 新しいイベントの受付を停止し、キュー内の配信の完了を待機
+func (b *Bus) Close() error {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		if b.queue != nil {
+			close(b.queue)
+		}
+	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+	return nil
+}
+
+// publish delivers event to matching handlers synchronously or through the queue
+// 一致するハンドラーへ同期的に、またはキュー経由でイベントを配信
+func (b *Bus) publish(ctx context.Context, event Event) error {
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return ErrBusClosed
+	}
+
+	var handlers []subscription
+	for _, sub := range b.subscriptions {
+		if sub.eventType == AllEvents || sub.eventType == event.Type {
+			handlers = append(handlers, sub)
+		}
+	}
+	if len(handlers) == 0 || b.queue == nil {
+		// ハンドラー内から購読・解除できるようロックを解放してから配信する
+		b.mu.RUnlock()
+		return b.deliver(ctx, event, handlers)
+	}
+	// キューへの送信はCloseと競合しないようロックを保持したまま行う
+	defer b.mu.RUnlock()
+
+	// 発行元のリクエストが終了しても配信が中断されないようにする
+	job := busJob{ctx: context.WithoutCancel(ctx), event: event, handlers: handlers}
+	select {
+	case b.queue <- job:
+		return nil
+	default:
+		return fmt.Errorf("イベントバスのキューが満杯です（%s）", event.Type)
+	}
+}
+
+// work delivers queued events until the queue is closed
+// キューが閉じられるまでキュー内のイベントを配信
+func (b *Bus) work() {
+	defer b.wg.Done()
+
+	for job := range b.queue {
+		if err := b.deliver(job.ctx, job.event, job.handlers); err != nil {
+			b.logger.Error("イベントバスのハンドラーが失敗しました",
+				zap.String("event_id", job.event.ID),
+				zap.String("type", job.event.Type),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// deliver calls every handler, converting panics into errors
+// 全てのハンドラーを呼び出す（パニックはエラーに変換）
+func (b *Bus) deliver(ctx context.Context, event Event, handlers []subscription) error {
+	var errs []error
+	for _, sub := range handlers {
+		if err := callHandler(ctx, sub.handler, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// callHandler calls handler and recovers from a panic
+// ハンドラーを呼び出し、パニックから回復する
+func callHandler(ctx context.Context, handler Handler, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("イベントハンドラーでパニックが発生しました: %v", r)
+		}
+	}()
+	return handler(ctx, event)
+}
@@ -0,0 +1,97 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// TestBus_SyncDelivery は同期配信・タイプ別購読・購読解除のテスト
+func TestBus_SyncDelivery(t *testing.T) {
+	bus := NewBus(BusConfig{}, zap.NewNop())
+	defer bus.Close()
+	ctx := context.Background()
+
+	var changed, all []Event
+	unsubscribe := bus.Subscribe(TypeStockChanged, func(ctx context.Context, event Event) error {
+		changed = append(changed, event)
+		return nil
+	})
+	bus.Subscribe(AllEvents, func(ctx context.Context, event Event) error {
+		all = append(all, event)
+		return nil
+	})
+
+	require.NoError(t, bus.PublishStockChanged(ctx, inventory.StockChangedEvent{ItemID: "ITEM-1", LocationID: "LOC-1", TransactionID: "TX-1", NewQuantity: 5}))
+	require.NoError(t, bus.PublishEvent(ctx, inventory.DomainEvent{ID: "EV-1", Type: inventory.EventTypeItemCreated, ItemID: "ITEM-1"}))
+
+	require.Len(t, changed, 1)
+	assert.Equal(t, "TX-1", changed[0].ID)
+	assert.Equal(t, "LOC-1", changed[0].LocationID)
+	assert.Equal(t, int64(5), changed[0].Payload.(inventory.StockChangedEvent).NewQuantity)
+	require.Len(t, all, 2)
+	assert.Equal(t, inventory.EventTypeItemCreated, all[1].Type)
+
+	unsubscribe()
+	unsubscribe()
+	require.NoError(t, bus.PublishStockChanged(ctx, inventory.StockChangedEvent{ItemID: "ITEM-1"}))
+	assert.Len(t, changed, 1)
+	assert.Len(t, all, 3)
+}
+
+// TestBus_SyncHandlerErrors は同期配信でのハンドラーのエラーとパニックのテスト
+func TestBus_SyncHandlerErrors(t *testing.T) {
+	bus := NewBus(BusConfig{}, zap.NewNop())
+	ctx := context.Background()
+
+	handlerErr := errors.New("handler failed")
+	called := false
+	bus.Subscribe(TypeLowStockAlert, func(ctx context.Context, event Event) error { return handlerErr })
+	bus.Subscribe(TypeLowStockAlert, func(ctx context.Context, event Event) error { panic("boom") })
+	bus.Subscribe(TypeLowStockAlert, func(ctx context.Context, event Event) error {
+		called = true
+		return nil
+	})
+
+	err := bus.PublishLowStockAlert(ctx, inventory.LowStockAlertEvent{ItemID: "ITEM-1"})
+	assert.ErrorIs(t, err, handlerErr)
+	assert.Contains(t, err.Error(), "boom")
+	assert.True(t, called)
+
+	require.NoError(t, bus.Close())
+	assert.ErrorIs(t, bus.PublishLowStockAlert(ctx, inventory.LowStockAlertEvent{}), ErrBusClosed)
+}
+
+// TestBus_AsyncDelivery はワーカーによる非同期配信のテスト
+func TestBus_AsyncDelivery(t *testing.T) {
+	bus := NewBus(BusConfig{Workers: 2, QueueSize: 10}, zap.NewNop())
+
+	var mu sync.Mutex
+	var received []string
+	bus.Subscribe(TypeItemTransferred, func(ctx context.Context, event Event) error {
+		// 発行元のコンテキストがキャンセルされても配信される
+		assert.NoError(t, ctx.Err())
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, event.ID)
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	for _, id := range []string{"TX-1", "TX-2", "TX-3"} {
+		require.NoError(t, bus.PublishItemTransferred(ctx, inventory.ItemTransferredEvent{TransactionID: id, Timestamp: time.Now()}))
+	}
+	cancel()
+
+	// Closeはキュー内の配信の完了を待つ
+	require.NoError(t, bus.Close())
+	assert.ElementsMatch(t, []string{"TX-1", "TX-2", "TX-3"}, received)
+}
@@ -0,0 +1,45 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CloudEvents attributes
+// CloudEventsの属性値
+const (
+	CloudEventsSpecVersion   = "1.0"
+	CloudEventsTypePrefix    = "com.zaigoframework.inventory." // type属性の接頭辞（例: com.zaigoframework.inventory.stock.changed）
+	DefaultCloudEventsSource = "/zaigoframework/inventory"     // source属性の既定値
+)
+
+// CloudEvent is a CloudEvents 1.0 envelope in structured content mode
+// 構造化モードのCloudEvents 1.0エンベロープ
+//
+// Knative EventingやAmazon EventBridgeなど、CloudEventsに対応した基盤へそのまま流せる形式です。
+// subjectには商品IDが入ります。
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// newCloudEvent wraps an encoded event in a CloudEvents envelope
+// エンコード済みイベントをCloudEventsのエンベロープで包む
+func newCloudEvent(id, source, eventType, itemID string, timestamp time.Time, data []byte) CloudEvent {
+	return CloudEvent{
+		SpecVersion:     CloudEventsSpecVersion,
+		ID:              id,
+		Source:          source,
+		Type:            CloudEventsTypePrefix + eventType,
+		Subject:         itemID,
+		Time:            timestamp,
+		DataContentType: ContentTypeJSON,
+		Data:            data,
+	}
+}
@@ -0,0 +1,259 @@
+package events
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Encryption algorithm and content type of encrypted messages
+// 暗号化メッセージの暗号方式とContent-Type
+const (
+	EncryptionAlgorithm  = "AES-256-GCM"
+	ContentTypeEncrypted = "application/vnd.zaigoframework.encrypted+json"
+)
+
+// dataKeySize is the size of the per-message data key (AES-256)
+// メッセージごとのデータ鍵のサイズ（AES-256）
+const dataKeySize = 32
+
+// ErrUnknownKey is returned when a key provider does not know the requested key
+// 鍵プロバイダーが指定された鍵を持っていない場合のエラー
+var ErrUnknownKey = errors.New("暗号鍵が見つかりません")
+
+// KeyProvider wraps and unwraps per-message data keys with a key encryption key
+// メッセージごとのデータ鍵を鍵暗号化鍵で暗号化・復号する
+//
+// AWS KMSやCloud KMSなど外部の鍵管理サービスを使用する場合は、このインターフェースを実装します。
+type KeyProvider interface {
+	// データ鍵を現在の鍵暗号化鍵で暗号化し、鍵IDと暗号化したデータ鍵を返します
+	WrapKey(ctx context.Context, dataKey []byte) (keyID string, wrapped []byte, err error)
+	// 鍵IDの鍵暗号化鍵でデータ鍵を復号します。鍵がない場合はErrUnknownKeyを返します
+	UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+// EncryptedEnvelope is the body of an encrypted message
+// 暗号化メッセージの本体
+//
+// 本体はメッセージごとに生成したデータ鍵でAES-256-GCMにより暗号化され、
+// データ鍵はKeyProviderの鍵暗号化鍵で暗号化されて同梱されます（エンベロープ暗号化）。
+type EncryptedEnvelope struct {
+	Algorithm    string `json:"alg"`           // 暗号方式（EncryptionAlgorithm）
+	KeyID        string `json:"kid"`           // データ鍵を暗号化した鍵暗号化鍵のID
+	EncryptedKey []byte `json:"encrypted_key"` // 暗号化したデータ鍵
+	Nonce        []byte `json:"nonce"`         // 本体の暗号化に使用したnonce
+	ContentType  string `json:"content_type"`  // 復号後の本体のContent-Type
+	Ciphertext   []byte `json:"ciphertext"`    // 暗号化した本体
+}
+
+// EncryptingSender encrypts message bodies before handing them to the wrapped sender
+// メッセージ本体を暗号化してからラップしたSenderへ渡す
+//
+// メッセージID・イベントタイプ・商品ID・ロケーションIDはルーティングに使用するため暗号化しません。
+// 単価などを含むイベント本体は、鍵を持つコンシューマーだけがDecryptMessageで復号できます。
+type EncryptingSender struct {
+	next Sender
+	keys KeyProvider
+}
+
+var _ Sender = (*EncryptingSender)(nil)
+
+// NewEncryptingSender creates a sender that encrypts bodies with keys from keys
+// keysの鍵で本体を暗号化するSenderを作成
+func NewEncryptingSender(next Sender, keys KeyProvider) *EncryptingSender {
+	return &EncryptingSender{next: next, keys: keys}
+}
+
+// Send encrypts msg.Body and delivers the envelope through the wrapped sender
+// メッセージ本体を暗号化し、エンベロープをラップしたSenderから送信
+func (s *EncryptingSender) Send(ctx context.Context, msg Message) error {
+	body, err := EncryptMessage(ctx, s.keys, msg.Body, msg.ContentType)
+	if err != nil {
+		return err
+	}
+	msg.Body = body
+	msg.ContentType = ContentTypeEncrypted
+	return s.next.Send(ctx, msg)
+}
+
+// Close closes the wrapped sender
+// ラップしたSenderを閉じる
+func (s *EncryptingSender) Close() error {
+	return s.next.Close()
+}
+
+// Ping checks the broker connection of the wrapped sender
+// ラップしたSenderのブローカーとの接続を確認
+func (s *EncryptingSender) Ping(ctx context.Context) error {
+	return Ping(ctx, s.next)
+}
+
+// EncryptMessage encrypts body with a new data key and returns the JSON encoded envelope
+// 新しいデータ鍵で本体を暗号化し、JSONエンコードしたエンベロープを返す
+func EncryptMessage(ctx context.Context, keys KeyProvider, body []byte, contentType string) ([]byte, error) {
+	if contentType == "" {
+		contentType = ContentTypeJSON
+	}
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, fmt.Errorf("データ鍵の生成に失敗しました: %w", err)
+	}
+	nonce, ciphertext, err := sealAESGCM(dataKey, body)
+	if err != nil {
+		return nil, err
+	}
+
+	keyID, wrapped, err := keys.WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("データ鍵の暗号化に失敗しました: %w", err)
+	}
+
+	envelope, err := json.Marshal(EncryptedEnvelope{
+		Algorithm:    EncryptionAlgorithm,
+		KeyID:        keyID,
+		EncryptedKey: wrapped,
+		Nonce:        nonce,
+		ContentType:  contentType,
+		Ciphertext:   ciphertext,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("暗号化エンベロープのJSON変換に失敗しました: %w", err)
+	}
+	return envelope, nil
+}
+
+// DecryptMessage decrypts an envelope produced by EncryptMessage and returns the body and its content type
+// EncryptMessageで作成したエンベロープを復号し、本体とそのContent-Typeを返す
+func DecryptMessage(ctx context.Context, keys KeyProvider, envelope []byte) ([]byte, string, error) {
+	var e EncryptedEnvelope
+	if err := json.Unmarshal(envelope, &e); err != nil {
+		return nil, "", fmt.Errorf("暗号化エンベロープの解析に失敗しました: %w", err)
+	}
+	if e.Algorithm != EncryptionAlgorithm {
+		return nil, "", fmt.Errorf("サポートされていない暗号方式: %s", e.Algorithm)
+	}
+
+	dataKey, err := keys.UnwrapKey(ctx, e.KeyID, e.EncryptedKey)
+	if err != nil {
+		return nil, "", fmt.Errorf("データ鍵の復号に失敗しました: %w", err)
+	}
+	body, err := openAESGCM(dataKey, e.Nonce, e.Ciphertext)
+	if err != nil {
+		return nil, "", err
+	}
+	return body, e.ContentType, nil
+}
+
+// StaticKeyProvider wraps data keys with AES-256 keys held in memory
+// メモリ上のAES-256鍵でデータ鍵を暗号化するKeyProvider
+//
+// 新しい鍵は現在の鍵で暗号化し、鍵のローテーション後も以前の鍵で暗号化されたメッセージを復号できるよう
+// 過去の鍵をAddKeyで登録できます。
+type StaticKeyProvider struct {
+	currentID string
+	keys      map[string][]byte
+}
+
+var _ KeyProvider = (*StaticKeyProvider)(nil)
+
+// NewStaticKeyProvider creates a key provider whose current key is key with ID keyID
+// 鍵IDがkeyIDの鍵を現在の鍵とするKeyProviderを作成
+func NewStaticKeyProvider(keyID string, key []byte) (*StaticKeyProvider, error) {
+	if keyID == "" {
+		return nil, fmt.Errorf("暗号鍵のIDが指定されていません")
+	}
+	p := &StaticKeyProvider{currentID: keyID, keys: make(map[string][]byte)}
+	if err := p.AddKey(keyID, key); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// AddKey registers a key that can unwrap data keys, such as one used before rotation
+// データ鍵の復号に使用できる鍵（ローテーション前の鍵など）を登録
+func (p *StaticKeyProvider) AddKey(keyID string, key []byte) error {
+	if len(key) != dataKeySize {
+		return fmt.Errorf("暗号鍵 %s は32バイト（AES-256）である必要があります: %d バイト", keyID, len(key))
+	}
+	p.keys[keyID] = append([]byte(nil), key...)
+	return nil
+}
+
+// WrapKey encrypts dataKey with the current key
+// データ鍵を現在の鍵で暗号化
+func (p *StaticKeyProvider) WrapKey(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	nonce, ciphertext, err := sealAESGCM(p.keys[p.currentID], dataKey)
+	if err != nil {
+		return "", nil, err
+	}
+	return p.currentID, append(nonce, ciphertext...), nil
+}
+
+// UnwrapKey decrypts a data key wrapped with the key keyID
+// 鍵IDの鍵で暗号化されたデータ鍵を復号
+func (p *StaticKeyProvider) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	key, exists := p.keys[keyID]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+
+	gcm, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < gcm.NonceSize() {
+		return nil, fmt.Errorf("暗号化されたデータ鍵が不正です")
+	}
+	return openAESGCM(key, wrapped[:gcm.NonceSize()], wrapped[gcm.NonceSize():])
+}
+
+// newAESGCM creates an AES-GCM cipher for key
+// 鍵のAES-GCM暗号を作成
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("暗号の初期化に失敗しました: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("暗号の初期化に失敗しました: %w", err)
+	}
+	return gcm, nil
+}
+
+// sealAESGCM encrypts plaintext with key and a random nonce
+// 鍵とランダムなnonceで平文を暗号化
+func sealAESGCM(key, plaintext []byte) ([]byte, []byte, error) {
+	gcm, err := newAESGCM(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, nil, fmt.Errorf("nonceの生成に失敗しました: %w", err)
+	}
+	return nonce, gcm.Seal(nil, nonce, plaintext, nil), nil
+}
+
+// openAESGCM decrypts ciphertext with key and nonce
+// 鍵とnonceで暗号文を復号
+func openAESGCM(key, nonce, ciphertext []byte) ([]byte, error) {
+	gcm, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("nonceの長さが不正です")
+	}
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("復号に失敗しました（鍵が異なるか改ざんされています）: %w", err)
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,100 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// newTestKeyProvider は固定の鍵を持つKeyProviderを作成
+func newTestKeyProvider(t *testing.T, keyID string, fill byte) *StaticKeyProvider {
+	t.Helper()
+	keys, err := NewStaticKeyProvider(keyID, bytes.Repeat([]byte{fill}, 32))
+	require.NoError(t, err)
+	return keys
+}
+
+// TestEncryptingSender_RoundTrip は暗号化した本体を鍵を持つコンシューマーが復号できるテスト
+func TestEncryptingSender_RoundTrip(t *testing.T) {
+	keys := newTestKeyProvider(t, "key-1", 0x01)
+	sender := &recordingSender{}
+	publisher := NewPublisher(NewEncryptingSender(sender, keys))
+	ctx := context.Background()
+
+	unitCost := 1234.5
+	require.NoError(t, publisher.PublishStockChanged(ctx, inventory.StockChangedEvent{
+		ItemID:        "ITEM-1",
+		LocationID:    "LOC-A",
+		TransactionID: "TX-1",
+		UnitCost:      &unitCost,
+	}))
+
+	require.Len(t, sender.messages, 1)
+	msg := sender.messages[0]
+	// ルーティングに使用する属性は暗号化しない
+	assert.Equal(t, "TX-1", msg.ID)
+	assert.Equal(t, TypeStockChanged, msg.Type)
+	assert.Equal(t, "ITEM-1", msg.ItemID)
+	assert.Equal(t, ContentTypeEncrypted, msg.ContentType)
+	assert.NotContains(t, string(msg.Body), "unit_cost")
+	assert.NotContains(t, string(msg.Body), "1234.5")
+
+	body, contentType, err := DecryptMessage(ctx, keys, msg.Body)
+	require.NoError(t, err)
+	assert.Equal(t, ContentTypeJSON, contentType)
+	event, err := DecodeStockChangedEvent(body)
+	require.NoError(t, err)
+	require.NotNil(t, event.UnitCost)
+	assert.Equal(t, 1234.5, *event.UnitCost)
+}
+
+// TestStaticKeyProvider_Rotation は鍵のローテーション後も以前の鍵のメッセージを復号できるテスト
+func TestStaticKeyProvider_Rotation(t *testing.T) {
+	ctx := context.Background()
+	oldKeys := newTestKeyProvider(t, "key-1", 0x01)
+	envelope, err := EncryptMessage(ctx, oldKeys, []byte(`{"quantity":1}`), "")
+	require.NoError(t, err)
+
+	// 新しい鍵だけでは復号できない
+	newKeys := newTestKeyProvider(t, "key-2", 0x02)
+	_, _, err = DecryptMessage(ctx, newKeys, envelope)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+
+	require.NoError(t, newKeys.AddKey("key-1", bytes.Repeat([]byte{0x01}, 32)))
+	body, _, err := DecryptMessage(ctx, newKeys, envelope)
+	require.NoError(t, err)
+	assert.Equal(t, `{"quantity":1}`, string(body))
+
+	// 同じ鍵IDでも鍵が異なる場合は復号に失敗する
+	wrongKeys := newTestKeyProvider(t, "key-1", 0x03)
+	_, _, err = DecryptMessage(ctx, wrongKeys, envelope)
+	assert.Error(t, err)
+
+	_, err = NewStaticKeyProvider("key-3", []byte("short"))
+	assert.Error(t, err)
+}
+
+// TestOpen_Encryption はKeysを指定した場合にドライバーのSenderが暗号化されるテスト
+func TestOpen_Encryption(t *testing.T) {
+	keys := newTestKeyProvider(t, "key-1", 0x01)
+	sender := &recordingSender{}
+	publisher, err := Open(Config{
+		Driver:  "custom",
+		Keys:    keys,
+		Drivers: map[string]SenderFactory{"custom": func() (Sender, error) { return sender, nil }},
+	}, zap.NewNop())
+	require.NoError(t, err)
+
+	require.NoError(t, publisher.PublishLowStockAlert(context.Background(), inventory.LowStockAlertEvent{ItemID: "ITEM-1"}))
+	require.Len(t, sender.messages, 1)
+	assert.Equal(t, ContentTypeEncrypted, sender.messages[0].ContentType)
+
+	require.NoError(t, publisher.Close())
+	assert.True(t, sender.closed)
+}
@@ -0,0 +1,354 @@
+// Package events provides EventPublisher implementations backed by message brokers
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// Event type names used for routing and message attributes
+// ルーティングやメッセージ属性に使用するイベントタイプ名
+const (
+	TypeStockChanged    = "stock.changed"    // 在庫変更
+	TypeLowStockAlert   = "stock.low_alert"  // 低在庫アラート
+	TypeItemTransferred = "item.transferred" // 商品移動
+)
+
+// IsKnownType reports whether eventType is one of the published event types
+// 発行されるイベントタイプかどうかを判定
+//
+// 在庫変更などのイベントに加えて、inventory.DomainEventTypesのドメインイベントも含みます。
+func IsKnownType(eventType string) bool {
+	switch eventType {
+	case TypeStockChanged, TypeLowStockAlert, TypeItemTransferred:
+		return true
+	}
+	return inventory.IsDomainEventType(eventType)
+}
+
+// Event encodings
+// イベントのエンコード形式
+const (
+	FormatJSON        = "json"        // イベント本体をそのままJSONにする（既定）
+	FormatCloudEvents = "cloudevents" // CloudEvents 1.0 の構造化モードのエンベロープで包む
+)
+
+// Content types of encoded messages
+// エンコード済みメッセージのContent-Type
+const (
+	ContentTypeJSON        = "application/json"
+	ContentTypeCloudEvents = "application/cloudevents+json"
+)
+
+// Message is a broker-agnostic encoded event
+// ブローカーに依存しないエンコード済みイベント
+type Message struct {
+	ID            string    // メッセージID（重複排除用）
+	Type          string    // イベントタイプ（TypeStockChangedなど）
+	ItemID        string    // 商品ID（順序保証・パーティションキーに使用）
+	LocationID    string    // ロケーションID（移動イベントでは移動元）
+	Body          []byte    // JSONエンコードしたイベント本体
+	ContentType   string    // 本体のContent-Type（ContentTypeJSONなど）
+	SchemaVersion int       // 本体のスキーマバージョン
+	Timestamp     time.Time // イベント発生日時
+	Destination   string    // ルーティングルールで選択した発行先のテンプレート（空の場合は各ドライバーの既定）
+}
+
+// Sender delivers encoded messages to a broker
+// エンコード済みメッセージをブローカーへ送信
+//
+// Publisherが各イベントをMessageに変換してSendを呼び出すため、
+// ブローカーごとの実装は送信処理だけを提供します。
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+	Close() error
+}
+
+// Publisher implements inventory.EventPublisher on top of a Sender
+// Senderを使用したinventory.EventPublisherの実装
+type Publisher struct {
+	sender        Sender
+	format        string // エンコード形式（FormatJSON | FormatCloudEvents）
+	source        string // CloudEventsのsource属性
+	schemaVersion int    // イベント本体のスキーマバージョン
+}
+
+var (
+	_ inventory.EventPublisher       = (*Publisher)(nil)
+	_ inventory.DomainEventPublisher = (*Publisher)(nil)
+)
+
+// NewPublisher creates a publisher that encodes events as JSON and hands them to sender
+// イベントをJSONにエンコードしてsenderへ渡すPublisherを作成
+func NewPublisher(sender Sender) *Publisher {
+	return &Publisher{sender: sender, format: FormatJSON, schemaVersion: CurrentSchemaVersion}
+}
+
+// NewCloudEventsPublisher creates a publisher that wraps events in CloudEvents 1.0 envelopes
+// イベントをCloudEvents 1.0のエンベロープで包んでsenderへ渡すPublisherを作成
+//
+// sourceはCloudEventsのsource属性（URI参照）です。空の場合はDefaultCloudEventsSourceを使用します。
+func NewCloudEventsPublisher(sender Sender, source string) *Publisher {
+	if source == "" {
+		source = DefaultCloudEventsSource
+	}
+	return &Publisher{sender: sender, format: FormatCloudEvents, source: source, schemaVersion: CurrentSchemaVersion}
+}
+
+// SetSchemaVersion selects the schema version of published payloads
+// 発行するイベント本体のスキーマバージョンを指定
+//
+// 新しいフィールドに対応していないコンシューマーのために、古いバージョンで発行する場合に使用します。
+func (p *Publisher) SetSchemaVersion(version int) error {
+	if !ValidSchemaVersion(version) {
+		return fmt.Errorf("サポートされていないスキーマバージョン: %d", version)
+	}
+	p.schemaVersion = version
+	return nil
+}
+
+// PublishStockChanged publishes a stock changed event
+// 在庫変更イベントを発行
+func (p *Publisher) PublishStockChanged(ctx context.Context, event inventory.StockChangedEvent) error {
+	return p.send(ctx, TypeStockChanged, event.TransactionID, event.ItemID, event.LocationID, event.Timestamp, event)
+}
+
+// PublishLowStockAlert publishes a low stock alert event
+// 低在庫アラートイベントを発行
+func (p *Publisher) PublishLowStockAlert(ctx context.Context, event inventory.LowStockAlertEvent) error {
+	return p.send(ctx, TypeLowStockAlert, "", event.ItemID, event.LocationID, event.Timestamp, event)
+}
+
+// PublishItemTransferred publishes an item transferred event
+// 商品移動イベントを発行
+func (p *Publisher) PublishItemTransferred(ctx context.Context, event inventory.ItemTransferredEvent) error {
+	return p.send(ctx, TypeItemTransferred, event.TransactionID, event.ItemID, event.FromLocationID, event.Timestamp, event)
+}
+
+// PublishEvent publishes a domain event
+// ドメインイベントを発行
+func (p *Publisher) PublishEvent(ctx context.Context, event inventory.DomainEvent) error {
+	return p.send(ctx, event.Type, event.ID, event.ItemID, event.LocationID, event.Timestamp, event)
+}
+
+// Close releases the underlying broker connection
+// ブローカーとの接続を解放
+func (p *Publisher) Close() error {
+	return p.sender.Close()
+}
+
+// Ping checks the broker connection of the sender
+// Senderのブローカーとの接続を確認
+func (p *Publisher) Ping(ctx context.Context) error {
+	return Ping(ctx, p.sender)
+}
+
+// send encodes event and delivers it through the sender
+// イベントをエンコードしてsenderから送信
+func (p *Publisher) send(ctx context.Context, eventType, id, itemID, locationID string, timestamp time.Time, event interface{}) error {
+	body, err := EncodeEvent(event, p.schemaVersion)
+	if err != nil {
+		return err
+	}
+
+	// 低在庫アラートなどトランザクションIDを持たないイベントには新しいIDを割り当てる
+	if id == "" {
+		id = inventory.NewTransactionID()
+	}
+
+	contentType := ContentTypeJSON
+	if p.format == FormatCloudEvents {
+		body, err = json.Marshal(newCloudEvent(id, p.source, eventType, itemID, timestamp, body))
+		if err != nil {
+			return fmt.Errorf("CloudEventsエンベロープのJSON変換に失敗しました: %w", err)
+		}
+		contentType = ContentTypeCloudEvents
+	}
+
+	msg := Message{
+		ID:            id,
+		Type:          eventType,
+		ItemID:        itemID,
+		LocationID:    locationID,
+		Body:          body,
+		ContentType:   contentType,
+		SchemaVersion: p.schemaVersion,
+		Timestamp:     timestamp,
+	}
+
+	if err := p.sender.Send(ctx, msg); err != nil {
+		return fmt.Errorf("イベント送信に失敗しました（%s）: %w", eventType, err)
+	}
+
+	return nil
+}
+
+// ExpandTemplate replaces {type}, {item} and {location} in template with values from msg
+// テンプレート中の {type}、{item}、{location} をメッセージの値で置換
+//
+// ルーティングキーやトピック名をイベントごとに組み立てるために使用します。
+func ExpandTemplate(template string, msg Message) string {
+	return strings.NewReplacer(
+		"{type}", msg.Type,
+		"{item}", msg.ItemID,
+		"{location}", msg.LocationID,
+	).Replace(template)
+}
+
+// SenderFactory creates a sender for a driver provided by the caller
+// 呼び出し側が提供するドライバーのSenderを作成
+type SenderFactory func() (Sender, error)
+
+// TargetConfig declares one destination of the "fanout" driver
+// "fanout" ドライバーの発行先の宣言
+type TargetConfig struct {
+	Name        string   // 発行先の名前（空の場合はドライバー名）
+	Driver      string   // 使用するドライバー名（rabbitmq | pubsub | mqtt | kinesis | Driversに登録した名前）
+	EventTypes  []string // 発行するイベントタイプ（空の場合は全て）
+	LocationIDs []string // 発行するロケーションID（空の場合は全て）
+	Routes      []Route  // この発行先のロケーションごとのルーティングルール
+}
+
+// Config selects and configures the event publisher
+// イベント発行者の選択と設定
+type Config struct {
+	Driver   string                   // 使用するドライバー名（空の場合はイベントを発行しない）
+	Format   string                   // エンコード形式（json | cloudevents、空の場合はjson）
+	Source   string                   // Format が "cloudevents" の場合のsource属性
+	Schema   int                      // イベント本体のスキーマバージョン（0の場合はCurrentSchemaVersion）
+	RabbitMQ RabbitMQConfig           // Driver が "rabbitmq" の場合の設定
+	PubSub   PubSubConfig             // Driver が "pubsub" の場合の設定
+	MQTT     MQTTConfig               // Driver が "mqtt" の場合の設定
+	Kinesis  KinesisConfig            // Driver が "kinesis" の場合の設定
+	Targets  []TargetConfig           // Driver が "fanout" の場合の発行先
+	Keys     KeyProvider              // 指定した場合はイベント本体を暗号化して発行（nilの場合は暗号化しない）
+	Routes   []Route                  // ロケーションごとのルーティングルール（fanoutの場合は発行先ごとにTargetConfig.Routesを使用）
+	Drivers  map[string]SenderFactory // このパッケージに含まれないドライバー（webhookなど）
+}
+
+// Open creates the publisher selected by cfg.Driver
+// cfg.Driverで指定された発行者を作成
+//
+// Driverが空または "none" の場合は nil, nil を返します（イベントを発行しない）。
+// "fanout" の場合はTargetsの発行先ごとに発行者を作成し、Fanoutにまとめます。
+func Open(cfg Config, logger *zap.Logger) (ClosablePublisher, error) {
+	switch cfg.Driver {
+	case "", "none":
+		return nil, nil
+	case "fanout":
+		return openFanout(cfg, logger)
+	}
+
+	sender, err := openSender(cfg, cfg.Driver, cfg.Routes, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg.newPublisher(sender)
+}
+
+// newPublisher creates a publisher for sender using the configured format
+// 設定されたエンコード形式でsenderのPublisherを作成
+func (cfg Config) newPublisher(sender Sender) (*Publisher, error) {
+	var publisher *Publisher
+	switch cfg.Format {
+	case "", FormatJSON:
+		publisher = NewPublisher(sender)
+	case FormatCloudEvents:
+		publisher = NewCloudEventsPublisher(sender, cfg.Source)
+	default:
+		sender.Close()
+		return nil, fmt.Errorf("サポートされていないイベント形式: %s", cfg.Format)
+	}
+
+	if cfg.Schema != 0 {
+		if err := publisher.SetSchemaVersion(cfg.Schema); err != nil {
+			sender.Close()
+			return nil, err
+		}
+	}
+	return publisher, nil
+}
+
+// openSender creates the sender for driver, applying routes and encrypting bodies when cfg.Keys is set
+// ドライバーに対応するSenderを作成（ルーティングルールを適用し、cfg.Keysが指定されている場合は本体を暗号化する）
+func openSender(cfg Config, driver string, routes []Route, logger *zap.Logger) (Sender, error) {
+	sender, err := openDriverSender(cfg, driver, logger)
+	if err != nil {
+		return nil, err
+	}
+	if len(routes) > 0 {
+		sender = NewRoutingSender(sender, routes)
+	}
+	if cfg.Keys != nil {
+		sender = NewEncryptingSender(sender, cfg.Keys)
+	}
+	return sender, nil
+}
+
+// openDriverSender creates the broker sender for driver
+// ドライバーに対応するブローカーのSenderを作成
+func openDriverSender(cfg Config, driver string, logger *zap.Logger) (Sender, error) {
+	switch driver {
+	case "rabbitmq":
+		return NewRabbitMQSender(cfg.RabbitMQ, logger)
+	case "pubsub":
+		return NewPubSubSender(context.Background(), cfg.PubSub, logger)
+	case "mqtt":
+		return NewMQTTSender(context.Background(), cfg.MQTT, logger)
+	case "kinesis":
+		return NewKinesisSender(cfg.Kinesis, logger)
+	}
+
+	if factory, exists := cfg.Drivers[driver]; exists {
+		return factory()
+	}
+	return nil, fmt.Errorf("サポートされていないイベントドライバー: %s", driver)
+}
+
+// openFanout creates a publisher for every target and combines them
+// 発行先ごとに発行者を作成してまとめる
+func openFanout(cfg Config, logger *zap.Logger) (ClosablePublisher, error) {
+	if len(cfg.Targets) == 0 {
+		return nil, fmt.Errorf("fanoutドライバーの発行先が指定されていません")
+	}
+
+	targets := make([]Target, 0, len(cfg.Targets))
+	for _, targetCfg := range cfg.Targets {
+		name := targetCfg.Name
+		if name == "" {
+			name = targetCfg.Driver
+		}
+
+		sender, err := openSender(cfg, targetCfg.Driver, targetCfg.Routes, logger)
+		if err != nil {
+			// 作成済みの発行先を閉じる
+			NewFanout(targets...).Close()
+			return nil, fmt.Errorf("発行先 %s の作成に失敗しました: %w", name, err)
+		}
+
+		publisher, err := cfg.newPublisher(sender)
+		if err != nil {
+			NewFanout(targets...).Close()
+			return nil, err
+		}
+
+		targets = append(targets, Target{
+			Name:      name,
+			Publisher: publisher,
+			Filter: Filter{
+				EventTypes:  targetCfg.EventTypes,
+				LocationIDs: targetCfg.LocationIDs,
+			},
+		})
+	}
+
+	return NewFanout(targets...), nil
+}
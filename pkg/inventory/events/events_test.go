@@ -0,0 +1,171 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// recordingSender は送信されたメッセージを記録するテスト用Sender
+type recordingSender struct {
+	messages []Message
+	err      error
+	closed   bool
+}
+
+func (s *recordingSender) Send(ctx context.Context, msg Message) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+func (s *recordingSender) Close() error {
+	s.closed = true
+	return nil
+}
+
+// TestPublisher_EncodesEvents はイベントのエンコードのテスト
+func TestPublisher_EncodesEvents(t *testing.T) {
+	sender := &recordingSender{}
+	publisher := NewPublisher(sender)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	require.NoError(t, publisher.PublishStockChanged(ctx, inventory.StockChangedEvent{
+		ItemID: "ITEM-1", LocationID: "LOC-A", OldQuantity: 10, NewQuantity: 15, TransactionID: "TX-1", Timestamp: now,
+	}))
+	require.NoError(t, publisher.PublishLowStockAlert(ctx, inventory.LowStockAlertEvent{
+		ItemID: "ITEM-1", LocationID: "LOC-A", CurrentQty: 3, Threshold: 10, Timestamp: now,
+	}))
+	require.NoError(t, publisher.PublishItemTransferred(ctx, inventory.ItemTransferredEvent{
+		ItemID: "ITEM-1", FromLocationID: "LOC-A", ToLocationID: "LOC-B", Quantity: 5, TransactionID: "TX-2", Timestamp: now,
+	}))
+
+	require.Len(t, sender.messages, 3)
+
+	changed := sender.messages[0]
+	assert.Equal(t, TypeStockChanged, changed.Type)
+	assert.Equal(t, "TX-1", changed.ID)
+	assert.Equal(t, "ITEM-1", changed.ItemID)
+	assert.Equal(t, "LOC-A", changed.LocationID)
+	assert.Equal(t, now, changed.Timestamp)
+
+	var decoded inventory.StockChangedEvent
+	require.NoError(t, json.Unmarshal(changed.Body, &decoded))
+	assert.Equal(t, int64(15), decoded.NewQuantity)
+
+	// トランザクションIDを持たないイベントにもIDが割り当てられる
+	assert.Equal(t, TypeLowStockAlert, sender.messages[1].Type)
+	assert.NotEmpty(t, sender.messages[1].ID)
+
+	// 移動イベントのロケーションは移動元
+	assert.Equal(t, TypeItemTransferred, sender.messages[2].Type)
+	assert.Equal(t, "LOC-A", sender.messages[2].LocationID)
+
+	require.NoError(t, publisher.Close())
+	assert.True(t, sender.closed)
+}
+
+// TestPublisher_SendError は送信エラーのテスト
+func TestPublisher_SendError(t *testing.T) {
+	sendErr := errors.New("broker unavailable")
+	publisher := NewPublisher(&recordingSender{err: sendErr})
+
+	err := publisher.PublishStockChanged(context.Background(), inventory.StockChangedEvent{ItemID: "ITEM-1"})
+	assert.ErrorIs(t, err, sendErr)
+}
+
+// TestPublisher_PublishEvent はドメインイベントの発行とデコードのテスト
+func TestPublisher_PublishEvent(t *testing.T) {
+	sender := &recordingSender{}
+	publisher := NewPublisher(sender)
+	ctx := context.Background()
+
+	event := inventory.DomainEvent{
+		ID:         "EV-1",
+		Type:       inventory.EventTypeReservationCreated,
+		ItemID:     "ITEM-1",
+		LocationID: "LOC-1",
+		Data:       inventory.ReservationEvent{ItemID: "ITEM-1", LocationID: "LOC-1", Quantity: 5},
+		Timestamp:  time.Now(),
+	}
+	require.NoError(t, publisher.PublishEvent(ctx, event))
+	require.Len(t, sender.messages, 1)
+
+	msg := sender.messages[0]
+	assert.Equal(t, "EV-1", msg.ID)
+	assert.Equal(t, inventory.EventTypeReservationCreated, msg.Type)
+	assert.Equal(t, "LOC-1", msg.LocationID)
+	assert.True(t, IsKnownType(msg.Type))
+
+	decoded, err := DecodeDomainEvent(msg.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "ITEM-1", decoded.ItemID)
+	assert.Equal(t, float64(5), decoded.Data.(map[string]interface{})["quantity"])
+}
+
+// TestCloudEventsPublisher はCloudEventsエンベロープのテスト
+func TestCloudEventsPublisher(t *testing.T) {
+	sender := &recordingSender{}
+	publisher := NewCloudEventsPublisher(sender, "")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	require.NoError(t, publisher.PublishStockChanged(context.Background(), inventory.StockChangedEvent{
+		ItemID: "ITEM-1", LocationID: "LOC-A", NewQuantity: 15, TransactionID: "TX-1", Timestamp: now,
+	}))
+	require.Len(t, sender.messages, 1)
+
+	msg := sender.messages[0]
+	assert.Equal(t, ContentTypeCloudEvents, msg.ContentType)
+	assert.Equal(t, TypeStockChanged, msg.Type)
+
+	var envelope CloudEvent
+	require.NoError(t, json.Unmarshal(msg.Body, &envelope))
+	assert.Equal(t, "1.0", envelope.SpecVersion)
+	assert.Equal(t, "TX-1", envelope.ID)
+	assert.Equal(t, DefaultCloudEventsSource, envelope.Source)
+	assert.Equal(t, "com.zaigoframework.inventory.stock.changed", envelope.Type)
+	assert.Equal(t, "ITEM-1", envelope.Subject)
+	assert.True(t, now.Equal(envelope.Time))
+
+	var data inventory.StockChangedEvent
+	require.NoError(t, json.Unmarshal(envelope.Data, &data))
+	assert.Equal(t, int64(15), data.NewQuantity)
+}
+
+// TestExpandTemplate はルーティングキーテンプレートのテスト
+func TestExpandTemplate(t *testing.T) {
+	msg := Message{Type: TypeStockChanged, ItemID: "ITEM-1", LocationID: "LOC-A"}
+
+	assert.Equal(t, "inventory.stock.changed", ExpandTemplate("inventory.{type}", msg))
+	assert.Equal(t, "inventory/LOC-A/ITEM-1/stock", ExpandTemplate("inventory/{location}/{item}/stock", msg))
+}
+
+// TestOpen はドライバー選択のテスト
+func TestOpen(t *testing.T) {
+	publisher, err := Open(Config{}, zap.NewNop())
+	require.NoError(t, err)
+	assert.Nil(t, publisher)
+
+	_, err = Open(Config{Driver: "unknown"}, zap.NewNop())
+	assert.Error(t, err)
+
+	sender := &recordingSender{}
+	_, err = Open(Config{
+		Driver:  "custom",
+		Format:  "xml",
+		Drivers: map[string]SenderFactory{"custom": func() (Sender, error) { return sender, nil }},
+	}, zap.NewNop())
+	assert.Error(t, err)
+	assert.True(t, sender.closed)
+}
@@ -0,0 +1,157 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// ClosablePublisher is an EventPublisher that holds broker connections
+// ブローカーとの接続を保持するEventPublisher
+type ClosablePublisher interface {
+	inventory.EventPublisher
+	Close() error
+}
+
+// Filter selects the events a fan-out target receives
+// ファンアウト先が受け取るイベントの条件
+//
+// 空のリストは全てに一致します。移動イベントは移動元・移動先のどちらかが一致すれば対象になります。
+type Filter struct {
+	EventTypes  []string // 対象のイベントタイプ（TypeStockChangedなど）
+	LocationIDs []string // 対象のロケーションID
+}
+
+// Allows reports whether an event of eventType at any of locationIDs passes the filter
+// イベントタイプとロケーションがフィルタ条件に一致するかを判定
+func (f Filter) Allows(eventType string, locationIDs ...string) bool {
+	if len(f.EventTypes) > 0 && !contains(f.EventTypes, eventType) {
+		return false
+	}
+	if len(f.LocationIDs) == 0 {
+		return true
+	}
+	for _, locationID := range locationIDs {
+		if contains(f.LocationIDs, locationID) {
+			return true
+		}
+	}
+	return false
+}
+
+// Target is a publisher registered in a Fanout
+// Fanoutに登録する発行先
+type Target struct {
+	Name      string            // 発行先の名前（エラーメッセージに使用）
+	Publisher ClosablePublisher // 発行者
+	Filter    Filter            // 発行するイベントの条件
+}
+
+// Fanout publishes each event to every target whose filter matches
+// フィルタ条件に一致する全ての発行先へイベントを発行
+//
+// 発行先は登録順に呼び出され、いずれかが失敗しても残りの発行先への発行は継続します。
+// 失敗した発行先のエラーはまとめて返されます。
+type Fanout struct {
+	targets []Target
+}
+
+var (
+	_ ClosablePublisher              = (*Fanout)(nil)
+	_ inventory.DomainEventPublisher = (*Fanout)(nil)
+)
+
+// NewFanout creates a fan-out publisher over targets
+// 発行先をまとめたファンアウト発行者を作成
+func NewFanout(targets ...Target) *Fanout {
+	return &Fanout{targets: targets}
+}
+
+// PublishStockChanged publishes a stock changed event to matching targets
+// 在庫変更イベントを条件に一致する発行先へ発行
+func (f *Fanout) PublishStockChanged(ctx context.Context, event inventory.StockChangedEvent) error {
+	return f.each(TypeStockChanged, []string{event.LocationID}, func(p inventory.EventPublisher) error {
+		return p.PublishStockChanged(ctx, event)
+	})
+}
+
+// PublishLowStockAlert publishes a low stock alert event to matching targets
+// 低在庫アラートイベントを条件に一致する発行先へ発行
+func (f *Fanout) PublishLowStockAlert(ctx context.Context, event inventory.LowStockAlertEvent) error {
+	return f.each(TypeLowStockAlert, []string{event.LocationID}, func(p inventory.EventPublisher) error {
+		return p.PublishLowStockAlert(ctx, event)
+	})
+}
+
+// PublishItemTransferred publishes an item transferred event to matching targets
+// 商品移動イベントを条件に一致する発行先へ発行
+func (f *Fanout) PublishItemTransferred(ctx context.Context, event inventory.ItemTransferredEvent) error {
+	return f.each(TypeItemTransferred, []string{event.FromLocationID, event.ToLocationID}, func(p inventory.EventPublisher) error {
+		return p.PublishItemTransferred(ctx, event)
+	})
+}
+
+// PublishEvent publishes a domain event to matching targets
+// ドメインイベントを条件に一致する発行先へ発行
+//
+// DomainEventPublisherを実装していない発行先はスキップします。
+func (f *Fanout) PublishEvent(ctx context.Context, event inventory.DomainEvent) error {
+	return f.each(event.Type, []string{event.LocationID}, func(p inventory.EventPublisher) error {
+		if domainPublisher, ok := p.(inventory.DomainEventPublisher); ok {
+			return domainPublisher.PublishEvent(ctx, event)
+		}
+		return nil
+	})
+}
+
+// Close closes every target
+// 全ての発行先を閉じる
+func (f *Fanout) Close() error {
+	var errs []error
+	for _, target := range f.targets {
+		if err := target.Publisher.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("発行先 %s のクローズに失敗しました: %w", target.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Ping checks the broker connection of every target
+// 全ての発行先のブローカーとの接続を確認
+func (f *Fanout) Ping(ctx context.Context) error {
+	var errs []error
+	for _, target := range f.targets {
+		if err := Ping(ctx, target.Publisher); err != nil {
+			errs = append(errs, fmt.Errorf("発行先 %s: %w", target.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// each calls publish for every target whose filter matches
+// フィルタ条件に一致する発行先ごとにpublishを呼び出す
+func (f *Fanout) each(eventType string, locationIDs []string, publish func(inventory.EventPublisher) error) error {
+	var errs []error
+	for _, target := range f.targets {
+		if !target.Filter.Allows(eventType, locationIDs...) {
+			continue
+		}
+		if err := publish(target.Publisher); err != nil {
+			errs = append(errs, fmt.Errorf("発行先 %s: %w", target.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// contains reports whether values includes value
+// valuesにvalueが含まれるかを判定
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// TestFilter_Allows はフィルタ条件のテスト
+func TestFilter_Allows(t *testing.T) {
+	assert.True(t, Filter{}.Allows(TypeStockChanged, "LOC-A"))
+
+	filter := Filter{EventTypes: []string{TypeLowStockAlert}, LocationIDs: []string{"LOC-A"}}
+	assert.True(t, filter.Allows(TypeLowStockAlert, "LOC-A"))
+	assert.False(t, filter.Allows(TypeStockChanged, "LOC-A"))
+	assert.False(t, filter.Allows(TypeLowStockAlert, "LOC-B"))
+
+	// 移動イベントは移動元・移動先のどちらかが一致すればよい
+	assert.True(t, Filter{LocationIDs: []string{"LOC-B"}}.Allows(TypeItemTransferred, "LOC-A", "LOC-B"))
+}
+
+// TestFanout_RoutesByFilter はフィルタによる振り分けのテスト
+func TestFanout_RoutesByFilter(t *testing.T) {
+	all := &recordingSender{}
+	alerts := &recordingSender{}
+	failing := &recordingSender{err: errors.New("broker unavailable")}
+
+	fanout := NewFanout(
+		Target{Name: "all", Publisher: NewPublisher(all)},
+		Target{Name: "failing", Publisher: NewPublisher(failing), Filter: Filter{LocationIDs: []string{"LOC-B"}}},
+		Target{Name: "alerts", Publisher: NewPublisher(alerts), Filter: Filter{
+			EventTypes:  []string{TypeLowStockAlert},
+			LocationIDs: []string{"LOC-A"},
+		}},
+	)
+	ctx := context.Background()
+
+	require.NoError(t, fanout.PublishStockChanged(ctx, inventory.StockChangedEvent{ItemID: "ITEM-1", LocationID: "LOC-A"}))
+	require.NoError(t, fanout.PublishLowStockAlert(ctx, inventory.LowStockAlertEvent{ItemID: "ITEM-1", LocationID: "LOC-A"}))
+
+	// 失敗した発行先があっても他の発行先には発行され、エラーに発行先名が含まれる
+	err := fanout.PublishItemTransferred(ctx, inventory.ItemTransferredEvent{ItemID: "ITEM-1", FromLocationID: "LOC-A", ToLocationID: "LOC-B"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failing")
+
+	assert.Len(t, all.messages, 3)
+	require.Len(t, alerts.messages, 1)
+	assert.Equal(t, TypeLowStockAlert, alerts.messages[0].Type)
+
+	require.NoError(t, fanout.Close())
+	assert.True(t, all.closed)
+	assert.True(t, alerts.closed)
+	assert.True(t, failing.closed)
+}
+
+// TestOpen_Fanout はfanoutドライバーの作成のテスト
+func TestOpen_Fanout(t *testing.T) {
+	sender := &recordingSender{}
+	cfg := Config{
+		Driver: "fanout",
+		Targets: []TargetConfig{
+			{Driver: "custom", EventTypes: []string{TypeStockChanged}},
+		},
+		Drivers: map[string]SenderFactory{
+			"custom": func() (Sender, error) { return sender, nil },
+		},
+	}
+
+	publisher, err := Open(cfg, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, publisher.PublishStockChanged(context.Background(), inventory.StockChangedEvent{ItemID: "ITEM-1"}))
+	require.NoError(t, publisher.PublishLowStockAlert(context.Background(), inventory.LowStockAlertEvent{ItemID: "ITEM-1"}))
+	assert.Len(t, sender.messages, 1)
+
+	_, err = Open(Config{Driver: "fanout"}, zap.NewNop())
+	assert.Error(t, err)
+
+	_, err = Open(Config{Driver: "fanout", Targets: []TargetConfig{{Driver: "unknown"}}}, zap.NewNop())
+	assert.Error(t, err)
+}
+
+// pingingSender は接続確認の結果を返すテスト用のSender
+type pingingSender struct {
+	recordingSender
+	pingErr error
+}
+
+func (s *pingingSender) Ping(ctx context.Context) error {
+	return s.pingErr
+}
+
+// TestFanout_Ping は全ての発行先の接続確認のテスト
+func TestFanout_Ping(t *testing.T) {
+	ctx := context.Background()
+	healthy := &pingingSender{}
+	down := &pingingSender{pingErr: errors.New("connection refused")}
+
+	// 接続を確認できないSenderは利用可能とみなす
+	require.NoError(t, Ping(ctx, NewFanout(Target{Name: "plain", Publisher: NewPublisher(&recordingSender{})})))
+	require.NoError(t, Ping(ctx, NewFanout(Target{Name: "healthy", Publisher: NewPublisher(healthy)})))
+
+	fanout := NewFanout(
+		Target{Name: "healthy", Publisher: NewPublisher(healthy)},
+		Target{Name: "down", Publisher: NewPublisher(NewRoutingSender(down, nil))},
+	)
+	err := Ping(ctx, fanout)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "down")
+	assert.NotContains(t, err.Error(), "healthy")
+}
@@ -0,0 +1,22 @@
+package events
+
+import (
+	"context"
+)
+
+// Pinger is implemented by publishers and senders that can check the broker connection
+// ブローカーとの接続を確認できる発行者・Senderが実装するインターフェース
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Ping checks the broker connection of v when it implements Pinger
+// v が Pinger を実装している場合にブローカーとの接続を確認
+//
+// 接続を確認できない発行者（HTTPで都度送信する Kinesis など）は nil を返します。
+func Ping(ctx context.Context, v interface{}) error {
+	if pinger, ok := v.(Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// kinesisTargetPutRecord is the X-Amz-Target of the PutRecord API
+// PutRecord APIのX-Amz-Target
+const kinesisTargetPutRecord = "Kinesis_20131202.PutRecord"
+
+// KinesisConfig holds settings for the AWS Kinesis Data Streams publisher
+// AWS Kinesis Data Streams発行者の設定
+type KinesisConfig struct {
+	Region          string        // AWSリージョン（空の場合は環境変数 AWS_REGION）
+	Stream          string        // 発行先ストリーム名のテンプレート（既定は inventory-events）
+	Endpoint        string        // エンドポイントURL（空の場合は https://kinesis.<region>.amazonaws.com、LocalStackなどに使用）
+	AccessKeyID     string        // アクセスキーID（空の場合は環境変数 AWS_ACCESS_KEY_ID）
+	SecretAccessKey string        // シークレットアクセスキー（空の場合は環境変数 AWS_SECRET_ACCESS_KEY）
+	SessionToken    string        // セッショントークン（空の場合は環境変数 AWS_SESSION_TOKEN）
+	Timeout         time.Duration // 1回のリクエストのタイムアウト（既定は10秒）
+}
+
+// KinesisSender puts messages into a Kinesis data stream
+// Kinesisのデータストリームへメッセージを書き込む
+//
+// パーティションキーは商品IDのため、同じ商品のイベントは同じシャードに発生順に書き込まれます。
+// 商品IDを持たないドメインイベントはロケーションID、それもない場合はメッセージIDをキーにします。
+// Kinesisのレコードには属性がないため、レコードのデータはメッセージ本体のみです。
+// 認証情報は設定または環境変数の静的な認証情報を使用します（インスタンスプロファイルなどには対応していません）。
+type KinesisSender struct {
+	cfg      KinesisConfig
+	endpoint *url.URL
+	client   *http.Client
+	logger   *zap.Logger
+}
+
+var _ Sender = (*KinesisSender)(nil)
+
+// kinesisPutRecordInput is the request body of PutRecord
+// PutRecordのリクエスト本体
+type kinesisPutRecordInput struct {
+	StreamName   string `json:"StreamName"`
+	PartitionKey string `json:"PartitionKey"`
+	Data         []byte `json:"Data"` // Base64でエンコードされる
+}
+
+// kinesisError is the error body returned by Kinesis
+// Kinesisが返すエラー本体
+type kinesisError struct {
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+}
+
+// NewKinesisSender creates a sender for the configured region and stream
+// 設定されたリージョン・ストリームのSenderを作成
+func NewKinesisSender(cfg KinesisConfig, logger *zap.Logger) (*KinesisSender, error) {
+	if cfg.Region == "" {
+		cfg.Region = os.Getenv("AWS_REGION")
+	}
+	if cfg.AccessKeyID == "" && cfg.SecretAccessKey == "" {
+		cfg.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+		cfg.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		if cfg.SessionToken == "" {
+			cfg.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+		}
+	}
+	if cfg.Region == "" {
+		return nil, fmt.Errorf("KinesisのAWSリージョンが指定されていません")
+	}
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, fmt.Errorf("KinesisのAWS認証情報が指定されていません")
+	}
+	if cfg.Stream == "" {
+		cfg.Stream = "inventory-events"
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://kinesis." + cfg.Region + ".amazonaws.com"
+	}
+
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("Kinesisのエンドポイントが不正です: %s", cfg.Endpoint)
+	}
+	if endpoint.Path == "" {
+		endpoint.Path = "/"
+	}
+
+	return &KinesisSender{
+		cfg:      cfg,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: cfg.Timeout},
+		logger:   logger,
+	}, nil
+}
+
+// Send puts msg into the stream with the item ID as partition key
+// 商品IDをパーティションキーとしてメッセージをストリームに書き込む
+func (s *KinesisSender) Send(ctx context.Context, msg Message) error {
+	stream := Destination(s.cfg.Stream, msg)
+	partitionKey := msg.ItemID
+	if partitionKey == "" {
+		partitionKey = msg.LocationID
+	}
+	if partitionKey == "" {
+		partitionKey = msg.ID
+	}
+
+	body, err := json.Marshal(kinesisPutRecordInput{
+		StreamName:   stream,
+		PartitionKey: partitionKey,
+		Data:         msg.Body,
+	})
+	if err != nil {
+		return fmt.Errorf("KinesisのリクエストのJSON変換に失敗しました: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("Kinesisのリクエスト作成に失敗しました: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", kinesisTargetPutRecord)
+	s.sign(req, body, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Kinesisへの書き込みに失敗しました（ストリーム %s）: %w", stream, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode != http.StatusOK {
+		var apiErr kinesisError
+		json.Unmarshal(respBody, &apiErr)
+		// __type は "com.amazonaws...#ResourceNotFoundException" の形式の場合がある
+		if i := strings.LastIndex(apiErr.Type, "#"); i >= 0 {
+			apiErr.Type = apiErr.Type[i+1:]
+		}
+		return fmt.Errorf("Kinesisへの書き込みに失敗しました（ストリーム %s、HTTP %d %s）: %s",
+			stream, resp.StatusCode, apiErr.Type, apiErr.Message)
+	}
+
+	return nil
+}
+
+// Close releases idle HTTP connections
+// 待機中のHTTP接続を解放
+func (s *KinesisSender) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// sign adds AWS Signature Version 4 headers to req
+// リクエストにAWS署名バージョン4のヘッダーを付加
+func (s *KinesisSender) sign(req *http.Request, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("Host", req.URL.Host)
+	req.Header.Set("X-Amz-Date", amzDate)
+	if s.cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.cfg.SessionToken)
+	}
+
+	// 署名対象のヘッダーを小文字の名前順に並べる
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, strings.ToLower(name))
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(req.Header.Get(name)) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.cfg.Region + "/kinesis/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := deriveSigningKey(s.cfg.SecretAccessKey, date, s.cfg.Region, "kinesis")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// deriveSigningKey derives the Signature Version 4 signing key
+// 署名バージョン4の署名鍵を導出
+func deriveSigningKey(secret, date, region, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secret), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	return hmacSHA256(key, "aws4_request")
+}
+
+// hmacSHA256 returns HMAC-SHA256 of data with key
+// 鍵によるデータのHMAC-SHA256を返す
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// sha256Hex returns the hex encoded SHA-256 of data
+// データのSHA-256を16進数で返す
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
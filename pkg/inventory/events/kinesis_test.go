@@ -0,0 +1,115 @@
+package events
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newTestKinesisSender はフェイクのエンドポイントに接続したKinesisSenderを作成
+func newTestKinesisSender(t *testing.T, handler http.HandlerFunc) *KinesisSender {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	sender, err := NewKinesisSender(KinesisConfig{
+		Region:          "ap-northeast-1",
+		Stream:          "inventory-{type}",
+		Endpoint:        srv.URL,
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+		SessionToken:    "token",
+	}, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() { sender.Close() })
+	return sender
+}
+
+// TestKinesisSender_PutRecord は商品IDをパーティションキーにした書き込みのテスト
+func TestKinesisSender_PutRecord(t *testing.T) {
+	var got kinesisPutRecordInput
+	var header http.Header
+	sender := newTestKinesisSender(t, func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.Write([]byte(`{"ShardId":"shardId-000000000000","SequenceNumber":"1"}`))
+	})
+
+	msg := Message{ID: "TX-1", Type: TypeStockChanged, ItemID: "ITEM-1", LocationID: "LOC-A", Body: []byte(`{"new_quantity":5}`)}
+	require.NoError(t, sender.Send(context.Background(), msg))
+
+	assert.Equal(t, "inventory-stock.changed", got.StreamName)
+	assert.Equal(t, "ITEM-1", got.PartitionKey)
+	assert.Equal(t, `{"new_quantity":5}`, string(got.Data))
+
+	assert.Equal(t, kinesisTargetPutRecord, header.Get("X-Amz-Target"))
+	assert.Equal(t, "application/x-amz-json-1.1", header.Get("Content-Type"))
+	assert.Equal(t, "token", header.Get("X-Amz-Security-Token"))
+	authorization := header.Get("Authorization")
+	assert.True(t, strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/"), authorization)
+	assert.Contains(t, authorization, "/ap-northeast-1/kinesis/aws4_request")
+	assert.Contains(t, authorization, "SignedHeaders=content-type;host;x-amz-date;x-amz-security-token;x-amz-target")
+}
+
+// TestKinesisSender_PartitionKeyFallback は商品IDを持たないイベントのパーティションキーのテスト
+func TestKinesisSender_PartitionKeyFallback(t *testing.T) {
+	var keys []string
+	sender := newTestKinesisSender(t, func(w http.ResponseWriter, r *http.Request) {
+		var input kinesisPutRecordInput
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+		keys = append(keys, input.PartitionKey)
+	})
+	ctx := context.Background()
+
+	require.NoError(t, sender.Send(ctx, Message{ID: "EV-1", Type: "location.created", LocationID: "LOC-A"}))
+	require.NoError(t, sender.Send(ctx, Message{ID: "EV-2", Type: "batch.completed"}))
+	assert.Equal(t, []string{"LOC-A", "EV-2"}, keys)
+}
+
+// TestKinesisSender_Error はKinesisのエラー応答のテスト
+func TestKinesisSender_Error(t *testing.T) {
+	sender := newTestKinesisSender(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"__type":"com.amazonaws.kinesis#ResourceNotFoundException","message":"Stream inventory-stock.changed not found"}`))
+	})
+
+	err := sender.Send(context.Background(), Message{Type: TypeStockChanged, ItemID: "ITEM-1"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ResourceNotFoundException")
+	assert.Contains(t, err.Error(), "not found")
+}
+
+// TestNewKinesisSender_InvalidConfig は設定不備のテスト
+func TestNewKinesisSender_InvalidConfig(t *testing.T) {
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+
+	_, err := NewKinesisSender(KinesisConfig{AccessKeyID: "AKID", SecretAccessKey: "secret"}, zap.NewNop())
+	assert.Error(t, err)
+
+	_, err = NewKinesisSender(KinesisConfig{Region: "ap-northeast-1"}, zap.NewNop())
+	assert.Error(t, err)
+
+	// 環境変数の認証情報を使用する
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	sender, err := NewKinesisSender(KinesisConfig{Region: "ap-northeast-1"}, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, "https://kinesis.ap-northeast-1.amazonaws.com/", sender.endpoint.String())
+}
+
+// TestDeriveSigningKey はAWSのドキュメントの例による署名鍵導出のテスト
+func TestDeriveSigningKey(t *testing.T) {
+	key := deriveSigningKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam")
+	assert.Equal(t, "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d", hex.EncodeToString(key))
+}
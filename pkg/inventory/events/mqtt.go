@@ -0,0 +1,574 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// MQTTConfig holds settings for the MQTT 3.1.1 publisher
+// MQTT 3.1.1 発行者の設定
+type MQTTConfig struct {
+	URL            string        // ブローカーのURL（tcp://host:1883 | ssl://host:8883、mqtt:// と mqtts:// も可）
+	ClientID       string        // クライアントID（空の場合はランダムに生成）
+	Username       string        // ユーザー名（空の場合は認証しない）
+	Password       string        // パスワード
+	Topic          string        // トピックのテンプレート（既定は inventory/{location}/{item}/{type}）
+	QoS            byte          // 配信品質（0: 最大1回 | 1: 最低1回 | 2: 正確に1回）
+	Retain         bool          // 最新のイベントをブローカーに保持させるか（後から接続した端末が直近の在庫を受け取れる）
+	KeepAlive      time.Duration // キープアライブ間隔（既定は60秒）
+	ConnectTimeout time.Duration // 接続のタイムアウト（既定は10秒）
+	AckTimeout     time.Duration // QoS 1・2 の確認待ちのタイムアウト（0の場合はctxの期限のみ）
+}
+
+// MQTT control packet types (MQTT 3.1.1 section 2.2.1)
+// MQTTの制御パケットタイプ
+const (
+	mqttConnect    byte = 1
+	mqttConnAck    byte = 2
+	mqttPublish    byte = 3
+	mqttPubAck     byte = 4
+	mqttPubRec     byte = 5
+	mqttPubRel     byte = 6
+	mqttPubComp    byte = 7
+	mqttPingReq    byte = 12
+	mqttPingResp   byte = 13
+	mqttDisconnect byte = 14
+)
+
+// mqttMaxRemainingLength is the largest remaining length a packet can declare
+// パケットが宣言できる残りの長さの最大値
+const mqttMaxRemainingLength = 268435455
+
+// errMQTTConnectionClosed is returned for acknowledgements pending when the connection closes
+// 確認待ちの間に接続が切断された場合のエラー
+var errMQTTConnectionClosed = errors.New("MQTTブローカーとの接続が切断されました")
+
+// MQTTSender publishes messages to an MQTT broker
+// MQTTブローカーへメッセージを発行
+//
+// 棚札や現場のスキャナーなどエッジ端末向けに、イベントをトピックテンプレートで組み立てたトピックへ発行します。
+// MQTT 3.1.1にはメッセージ属性がないため、メッセージ本体のみを送信します（イベントタイプはトピックで区別します）。
+// 接続が切断された場合は次の送信時に再接続します。クリーンセッションで接続するため、
+// 切断時に確認待ちだったメッセージはエラーとして返し、再送は呼び出し側（RetryingPublisherなど）に任せます。
+type MQTTSender struct {
+	cfg    MQTTConfig
+	logger *zap.Logger
+
+	mu     sync.Mutex
+	conn   *mqttConn
+	nextID uint16
+	closed bool
+}
+
+var _ Sender = (*MQTTSender)(nil)
+
+// NewMQTTSender connects to the MQTT broker
+// MQTTブローカーに接続
+func NewMQTTSender(ctx context.Context, cfg MQTTConfig, logger *zap.Logger) (*MQTTSender, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("MQTTブローカーのURLが指定されていません")
+	}
+	if cfg.QoS > 2 {
+		return nil, fmt.Errorf("無効なMQTTのQoS: %d", cfg.QoS)
+	}
+	if cfg.Topic == "" {
+		cfg.Topic = "inventory/{location}/{item}/{type}"
+	}
+	if cfg.KeepAlive <= 0 {
+		cfg.KeepAlive = 60 * time.Second
+	}
+	if cfg.ConnectTimeout <= 0 {
+		cfg.ConnectTimeout = 10 * time.Second
+	}
+	if cfg.ClientID == "" {
+		suffix := make([]byte, 8)
+		if _, err := rand.Read(suffix); err != nil {
+			return nil, fmt.Errorf("MQTTクライアントIDの生成に失敗しました: %w", err)
+		}
+		// MQTT 3.1.1で全てのブローカーが受け付ける23バイト以内に収める
+		cfg.ClientID = "zaigo-" + hex.EncodeToString(suffix)
+	}
+
+	s := &MQTTSender{cfg: cfg, logger: logger}
+	// 設定の誤りを起動時に検出するため最初の接続はここで行う
+	if _, err := s.connection(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Send publishes msg to the topic built from the template and waits for the acknowledgement required by the QoS
+// テンプレートから組み立てたトピックへメッセージを発行し、QoSに応じた確認を待つ
+func (s *MQTTSender) Send(ctx context.Context, msg Message) error {
+	topic := Destination(s.cfg.Topic, msg)
+	if topic == "" || strings.ContainsAny(topic, "+#") {
+		return fmt.Errorf("無効なMQTTトピック: %q", topic)
+	}
+
+	conn, err := s.connection(ctx)
+	if err != nil {
+		return err
+	}
+
+	var packetID uint16
+	var acked chan struct{}
+	if s.cfg.QoS > 0 {
+		packetID = s.allocatePacketID()
+		acked = conn.expect(packetID)
+		defer conn.forget(packetID)
+	}
+
+	if err := conn.write(encodeMQTTPublish(topic, msg.Body, s.cfg.QoS, s.cfg.Retain, packetID)); err != nil {
+		return fmt.Errorf("MQTTへの発行に失敗しました: %w", err)
+	}
+	if acked == nil {
+		return nil
+	}
+
+	if s.cfg.AckTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.AckTimeout)
+		defer cancel()
+	}
+
+	select {
+	case <-acked:
+		return nil
+	case <-conn.done:
+		return fmt.Errorf("MQTTの確認待ちに失敗しました: %w", conn.err())
+	case <-ctx.Done():
+		return fmt.Errorf("MQTTの確認待ちに失敗しました: %w", ctx.Err())
+	}
+}
+
+// Close disconnects from the broker
+// ブローカーから切断
+func (s *MQTTSender) Close() error {
+	s.mu.Lock()
+	conn := s.conn
+	s.conn = nil
+	s.closed = true
+	s.mu.Unlock()
+
+	if conn == nil {
+		return nil
+	}
+	return conn.disconnect()
+}
+
+// Ping checks the broker connection, reconnecting if it was lost
+// ブローカーとの接続を確認（切断されている場合は再接続する）
+func (s *MQTTSender) Ping(ctx context.Context) error {
+	_, err := s.connection(ctx)
+	return err
+}
+
+// connection returns the current connection, reconnecting if it was lost
+// 現在の接続を返す（切断されている場合は再接続する）
+func (s *MQTTSender) connection(ctx context.Context) (*mqttConn, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, fmt.Errorf("MQTT発行者は既に閉じられています")
+	}
+	if s.conn != nil {
+		select {
+		case <-s.conn.done:
+			s.logger.Warn("MQTTブローカーとの接続が切断されたため再接続します", zap.Error(s.conn.err()))
+			s.conn = nil
+		default:
+			return s.conn, nil
+		}
+	}
+
+	conn, err := dialMQTT(ctx, s.cfg, s.logger)
+	if err != nil {
+		return nil, err
+	}
+	s.conn = conn
+	return conn, nil
+}
+
+// allocatePacketID returns the next non-zero packet identifier
+// 次のパケット識別子を返す（0は使用しない）
+func (s *MQTTSender) allocatePacketID() uint16 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	if s.nextID == 0 {
+		s.nextID = 1
+	}
+	return s.nextID
+}
+
+// mqttConn is one network connection to the broker
+// ブローカーとの1つのネットワーク接続
+//
+// 受信は専用のゴルーチンで行い、PUBACK・PUBCOMPを確認待ちの送信へ通知します。
+type mqttConn struct {
+	netConn   net.Conn
+	keepAlive time.Duration
+	logger    *zap.Logger
+
+	writeMu sync.Mutex
+
+	mu       sync.Mutex
+	pending  map[uint16]chan struct{} // パケット識別子ごとの確認待ち
+	closeErr error
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// dialMQTT opens a connection to the broker and completes the CONNECT handshake
+// ブローカーに接続し、CONNECTのハンドシェイクを完了する
+func dialMQTT(ctx context.Context, cfg MQTTConfig, logger *zap.Logger) (*mqttConn, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("MQTTブローカーのURLが不正です: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.ConnectTimeout)
+	defer cancel()
+
+	var netConn net.Conn
+	switch u.Scheme {
+	case "tcp", "mqtt":
+		var dialer net.Dialer
+		netConn, err = dialer.DialContext(ctx, "tcp", hostWithDefaultPort(u, "1883"))
+	case "ssl", "tls", "mqtts":
+		dialer := tls.Dialer{Config: &tls.Config{ServerName: u.Hostname(), MinVersion: tls.VersionTLS12}}
+		netConn, err = dialer.DialContext(ctx, "tcp", hostWithDefaultPort(u, "8883"))
+	default:
+		return nil, fmt.Errorf("サポートされていないMQTTのスキーム: %s", u.Scheme)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("MQTTブローカーへの接続に失敗しました: %w", err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		netConn.SetDeadline(deadline)
+	}
+	reader := bufio.NewReader(netConn)
+	if err := writeMQTTPacket(netConn, encodeMQTTConnect(cfg)); err != nil {
+		netConn.Close()
+		return nil, fmt.Errorf("MQTTのCONNECT送信に失敗しました: %w", err)
+	}
+	packetType, _, body, err := readMQTTPacket(reader)
+	if err != nil {
+		netConn.Close()
+		return nil, fmt.Errorf("MQTTのCONNACK受信に失敗しました: %w", err)
+	}
+	if packetType != mqttConnAck || len(body) != 2 {
+		netConn.Close()
+		return nil, fmt.Errorf("MQTTブローカーから不正な応答を受信しました（パケットタイプ %d）", packetType)
+	}
+	if code := body[1]; code != 0 {
+		netConn.Close()
+		return nil, fmt.Errorf("MQTTブローカーが接続を拒否しました: %s", mqttConnAckReason(code))
+	}
+	netConn.SetDeadline(time.Time{})
+
+	conn := &mqttConn{
+		netConn:   netConn,
+		keepAlive: cfg.KeepAlive,
+		logger:    logger,
+		pending:   make(map[uint16]chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go conn.readLoop(reader)
+	go conn.pingLoop()
+
+	logger.Info("MQTTブローカーに接続しました", zap.String("host", u.Host), zap.String("client_id", cfg.ClientID))
+	return conn, nil
+}
+
+// hostWithDefaultPort returns host:port of u, using port when u has none
+// URLのhost:portを返す（ポートがない場合はportを使用）
+func hostWithDefaultPort(u *url.URL, port string) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// write sends one encoded packet
+// エンコード済みのパケットを1つ送信
+func (c *mqttConn) write(packet []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	c.netConn.SetWriteDeadline(time.Now().Add(c.keepAlive))
+	if err := writeMQTTPacket(c.netConn, packet); err != nil {
+		c.close(err)
+		return err
+	}
+	return nil
+}
+
+// expect registers a pending acknowledgement for packetID
+// パケット識別子の確認待ちを登録
+func (c *mqttConn) expect(packetID uint16) chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	acked := make(chan struct{})
+	c.pending[packetID] = acked
+	return acked
+}
+
+// forget removes the pending acknowledgement for packetID
+// パケット識別子の確認待ちを解除
+func (c *mqttConn) forget(packetID uint16) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.pending, packetID)
+}
+
+// acknowledge notifies the sender waiting for packetID
+// パケット識別子の確認を待っている送信へ通知
+func (c *mqttConn) acknowledge(packetID uint16) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if acked, exists := c.pending[packetID]; exists {
+		close(acked)
+		delete(c.pending, packetID)
+	}
+}
+
+// readLoop receives packets from the broker until the connection closes
+// 接続が閉じられるまでブローカーからのパケットを受信
+func (c *mqttConn) readLoop(reader *bufio.Reader) {
+	for {
+		// キープアライブの1.5倍の間なにも受信しない場合は切断とみなす
+		c.netConn.SetReadDeadline(time.Now().Add(c.keepAlive * 3 / 2))
+		packetType, _, body, err := readMQTTPacket(reader)
+		if err != nil {
+			c.close(err)
+			return
+		}
+
+		switch packetType {
+		case mqttPubAck, mqttPubComp:
+			if len(body) == 2 {
+				c.acknowledge(binary.BigEndian.Uint16(body))
+			}
+		case mqttPubRec:
+			// QoS 2 はPUBRELを返してPUBCOMPを待つ
+			if len(body) == 2 {
+				if err := c.write(encodeMQTTAck(mqttPubRel, binary.BigEndian.Uint16(body))); err != nil {
+					return
+				}
+			}
+		case mqttPingResp:
+		default:
+			c.close(fmt.Errorf("MQTTブローカーから想定外のパケットを受信しました（パケットタイプ %d）", packetType))
+			return
+		}
+	}
+}
+
+// pingLoop sends PINGREQ so the broker keeps the connection open
+// ブローカーが接続を維持するようPINGREQを送信
+func (c *mqttConn) pingLoop() {
+	ticker := time.NewTicker(c.keepAlive / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			if err := c.write([]byte{mqttPingReq << 4, 0}); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// disconnect sends DISCONNECT and closes the connection
+// DISCONNECTを送信して接続を閉じる
+func (c *mqttConn) disconnect() error {
+	select {
+	case <-c.done:
+		return nil
+	default:
+	}
+
+	err := c.write([]byte{mqttDisconnect << 4, 0})
+	c.close(errMQTTConnectionClosed)
+	return err
+}
+
+// close closes the connection once and records why
+// 接続を一度だけ閉じ、その理由を記録
+func (c *mqttConn) close(err error) {
+	c.closeOnce.Do(func() {
+		c.mu.Lock()
+		c.closeErr = err
+		c.mu.Unlock()
+		c.netConn.Close()
+		close(c.done)
+	})
+}
+
+// err returns why the connection was closed
+// 接続が閉じられた理由を返す
+func (c *mqttConn) err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closeErr == nil || errors.Is(c.closeErr, io.EOF) {
+		return errMQTTConnectionClosed
+	}
+	return c.closeErr
+}
+
+// encodeMQTTConnect encodes a CONNECT packet with a clean session
+// クリーンセッションのCONNECTパケットをエンコード
+func encodeMQTTConnect(cfg MQTTConfig) []byte {
+	var flags byte = 0x02 // クリーンセッション
+	payload := appendMQTTString(nil, cfg.ClientID)
+	if cfg.Username != "" {
+		flags |= 0x80
+		payload = appendMQTTString(payload, cfg.Username)
+		if cfg.Password != "" {
+			flags |= 0x40
+			payload = appendMQTTString(payload, cfg.Password)
+		}
+	}
+
+	body := appendMQTTString(nil, "MQTT")
+	body = append(body, 4, flags) // プロトコルレベル4（MQTT 3.1.1）
+	body = binary.BigEndian.AppendUint16(body, uint16(cfg.KeepAlive/time.Second))
+	body = append(body, payload...)
+	return encodeMQTTPacket(mqttConnect<<4, body)
+}
+
+// encodeMQTTPublish encodes a PUBLISH packet
+// PUBLISHパケットをエンコード
+func encodeMQTTPublish(topic string, payload []byte, qos byte, retain bool, packetID uint16) []byte {
+	header := mqttPublish<<4 | qos<<1
+	if retain {
+		header |= 0x01
+	}
+
+	body := appendMQTTString(nil, topic)
+	if qos > 0 {
+		body = binary.BigEndian.AppendUint16(body, packetID)
+	}
+	body = append(body, payload...)
+	return encodeMQTTPacket(header, body)
+}
+
+// encodeMQTTAck encodes an acknowledgement packet carrying only a packet identifier
+// パケット識別子のみを持つ確認パケットをエンコード
+func encodeMQTTAck(packetType byte, packetID uint16) []byte {
+	header := packetType << 4
+	if packetType == mqttPubRel {
+		header |= 0x02 // PUBRELの予約フラグ
+	}
+	return encodeMQTTPacket(header, binary.BigEndian.AppendUint16(nil, packetID))
+}
+
+// encodeMQTTPacket prefixes body with the fixed header
+// 本体の前に固定ヘッダーを付加
+func encodeMQTTPacket(header byte, body []byte) []byte {
+	packet := []byte{header}
+	length := len(body)
+	for {
+		b := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			b |= 0x80
+		}
+		packet = append(packet, b)
+		if length == 0 {
+			break
+		}
+	}
+	return append(packet, body...)
+}
+
+// appendMQTTString appends a length-prefixed UTF-8 string
+// 長さ付きのUTF-8文字列を追加
+func appendMQTTString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
+
+// writeMQTTPacket writes an encoded packet to w
+// エンコード済みのパケットを書き込む
+func writeMQTTPacket(w io.Writer, packet []byte) error {
+	if len(packet) > mqttMaxRemainingLength {
+		return fmt.Errorf("MQTTのパケットが大きすぎます: %d バイト", len(packet))
+	}
+	_, err := w.Write(packet)
+	return err
+}
+
+// readMQTTPacket reads one packet and returns its type, flags and body
+// パケットを1つ読み取り、タイプ・フラグ・本体を返す
+func readMQTTPacket(r *bufio.Reader) (byte, byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	length, multiplier := 0, 1
+	for i := 0; ; i++ {
+		if i == 4 {
+			return 0, 0, nil, fmt.Errorf("MQTTの残りの長さが不正です")
+		}
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, 0, nil, err
+		}
+		length += int(b&0x7f) * multiplier
+		if b&0x80 == 0 {
+			break
+		}
+		multiplier *= 128
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, 0, nil, err
+	}
+	return header >> 4, header & 0x0f, body, nil
+}
+
+// mqttConnAckReason describes a CONNACK return code
+// CONNACKのリターンコードの説明
+func mqttConnAckReason(code byte) string {
+	switch code {
+	case 1:
+		return "サポートされていないプロトコルバージョン"
+	case 2:
+		return "クライアントIDが拒否されました"
+	case 3:
+		return "サーバーが利用できません"
+	case 4:
+		return "ユーザー名またはパスワードが不正です"
+	case 5:
+		return "認可されていません"
+	}
+	return fmt.Sprintf("リターンコード %d", code)
+}
@@ -0,0 +1,234 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// mqttPublished is a PUBLISH packet received by the fake broker
+// フェイクブローカーが受信したPUBLISHパケット
+type mqttPublished struct {
+	topic   string
+	payload []byte
+	qos     byte
+	retain  bool
+}
+
+// fakeMQTTBroker is a minimal MQTT 3.1.1 broker for tests
+// テスト用の最小限のMQTT 3.1.1ブローカー
+type fakeMQTTBroker struct {
+	listener   net.Listener
+	returnCode byte // CONNACKのリターンコード
+
+	mu        sync.Mutex
+	connects  int
+	clientIDs []string
+	published []mqttPublished
+	conns     []net.Conn
+}
+
+// newFakeMQTTBroker starts a fake broker on a local port
+// ローカルポートでフェイクブローカーを起動
+func newFakeMQTTBroker(t *testing.T, returnCode byte) *fakeMQTTBroker {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	broker := &fakeMQTTBroker{listener: listener, returnCode: returnCode}
+	t.Cleanup(func() {
+		listener.Close()
+		broker.dropConnections()
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			broker.mu.Lock()
+			broker.conns = append(broker.conns, conn)
+			broker.mu.Unlock()
+			go broker.serve(conn)
+		}
+	}()
+	return broker
+}
+
+// url returns the broker URL
+// ブローカーのURLを返す
+func (b *fakeMQTTBroker) url() string {
+	return "tcp://" + b.listener.Addr().String()
+}
+
+// serve handles one client connection
+// クライアント接続を1つ処理
+func (b *fakeMQTTBroker) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	for {
+		packetType, flags, body, err := readMQTTPacket(reader)
+		if err != nil {
+			return
+		}
+
+		switch packetType {
+		case mqttConnect:
+			// プロトコル名(6) + レベル(1) + フラグ(1) + キープアライブ(2) の後にクライアントID
+			idLen := binary.BigEndian.Uint16(body[10:12])
+			b.mu.Lock()
+			b.connects++
+			b.clientIDs = append(b.clientIDs, string(body[12:12+idLen]))
+			b.mu.Unlock()
+			conn.Write(encodeMQTTPacket(mqttConnAck<<4, []byte{0, b.returnCode}))
+		case mqttPublish:
+			qos := flags >> 1 & 0x03
+			topicLen := int(binary.BigEndian.Uint16(body))
+			offset := 2 + topicLen
+			var packetID uint16
+			if qos > 0 {
+				packetID = binary.BigEndian.Uint16(body[offset:])
+				offset += 2
+			}
+			b.mu.Lock()
+			b.published = append(b.published, mqttPublished{
+				topic:   string(body[2 : 2+topicLen]),
+				payload: body[offset:],
+				qos:     qos,
+				retain:  flags&0x01 != 0,
+			})
+			b.mu.Unlock()
+			switch qos {
+			case 1:
+				conn.Write(encodeMQTTAck(mqttPubAck, packetID))
+			case 2:
+				conn.Write(encodeMQTTAck(mqttPubRec, packetID))
+			}
+		case mqttPubRel:
+			conn.Write(encodeMQTTAck(mqttPubComp, binary.BigEndian.Uint16(body)))
+		case mqttPingReq:
+			conn.Write([]byte{mqttPingResp << 4, 0})
+		case mqttDisconnect:
+			return
+		}
+	}
+}
+
+// dropConnections closes every client connection
+// 全てのクライアント接続を切断
+func (b *fakeMQTTBroker) dropConnections() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, conn := range b.conns {
+		conn.Close()
+	}
+	b.conns = nil
+}
+
+// messages returns the received PUBLISH packets
+// 受信したPUBLISHパケットを返す
+func (b *fakeMQTTBroker) messages() []mqttPublished {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]mqttPublished(nil), b.published...)
+}
+
+// TestMQTTSender_PublishesWithTopicTemplate はトピックテンプレートとQoSごとの発行のテスト
+func TestMQTTSender_PublishesWithTopicTemplate(t *testing.T) {
+	for _, qos := range []byte{0, 1, 2} {
+		broker := newFakeMQTTBroker(t, 0)
+		sender, err := NewMQTTSender(context.Background(), MQTTConfig{
+			URL:    broker.url(),
+			Topic:  "inventory/{location}/{item}/stock",
+			QoS:    qos,
+			Retain: true,
+		}, zap.NewNop())
+		require.NoError(t, err)
+
+		msg := Message{ID: "TX-1", Type: TypeStockChanged, ItemID: "ITEM-1", LocationID: "LOC-A", Body: []byte(`{"new_quantity":5}`)}
+		require.NoError(t, sender.Send(context.Background(), msg))
+		require.NoError(t, sender.Close())
+
+		// QoS 0 は確認を待たないため、ブローカーの受信を待つ
+		require.Eventually(t, func() bool { return len(broker.messages()) == 1 }, time.Second, 10*time.Millisecond)
+		published := broker.messages()[0]
+		assert.Equal(t, "inventory/LOC-A/ITEM-1/stock", published.topic)
+		assert.Equal(t, `{"new_quantity":5}`, string(published.payload))
+		assert.Equal(t, qos, published.qos)
+		assert.True(t, published.retain)
+		assert.LessOrEqual(t, len(broker.clientIDs[0]), 23)
+	}
+}
+
+// TestMQTTSender_Reconnects は切断後の再接続のテスト
+func TestMQTTSender_Reconnects(t *testing.T) {
+	broker := newFakeMQTTBroker(t, 0)
+	sender, err := NewMQTTSender(context.Background(), MQTTConfig{URL: broker.url(), QoS: 1, ClientID: "scanner-1"}, zap.NewNop())
+	require.NoError(t, err)
+	defer sender.Close()
+
+	ctx := context.Background()
+	require.NoError(t, sender.Send(ctx, Message{Type: TypeStockChanged, ItemID: "ITEM-1", LocationID: "LOC-A"}))
+
+	// 接続の切断を検知するまで待ってから送信する
+	broker.dropConnections()
+	sender.mu.Lock()
+	conn := sender.conn
+	sender.mu.Unlock()
+	select {
+	case <-conn.done:
+	case <-time.After(time.Second):
+		t.Fatal("切断が検知されませんでした")
+	}
+
+	require.NoError(t, sender.Send(ctx, Message{Type: TypeLowStockAlert, ItemID: "ITEM-1", LocationID: "LOC-A"}))
+
+	messages := broker.messages()
+	require.Len(t, messages, 2)
+	assert.Equal(t, "inventory/LOC-A/ITEM-1/stock.changed", messages[0].topic)
+	assert.Equal(t, "inventory/LOC-A/ITEM-1/stock.low_alert", messages[1].topic)
+	assert.Equal(t, 2, broker.connects)
+	assert.Equal(t, []string{"scanner-1", "scanner-1"}, broker.clientIDs)
+}
+
+// TestMQTTSender_InvalidTopic はワイルドカードを含むトピックへの発行を拒否するテスト
+func TestMQTTSender_InvalidTopic(t *testing.T) {
+	broker := newFakeMQTTBroker(t, 0)
+	sender, err := NewMQTTSender(context.Background(), MQTTConfig{URL: broker.url(), Topic: "inventory/{item}"}, zap.NewNop())
+	require.NoError(t, err)
+	defer sender.Close()
+
+	assert.Error(t, sender.Send(context.Background(), Message{Type: TypeStockChanged, ItemID: "ITEM+1"}))
+	assert.Empty(t, broker.messages())
+}
+
+// TestNewMQTTSender_InvalidConfig は接続設定の誤りのテスト
+func TestNewMQTTSender_InvalidConfig(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := NewMQTTSender(ctx, MQTTConfig{}, zap.NewNop())
+	assert.Error(t, err)
+
+	_, err = NewMQTTSender(ctx, MQTTConfig{URL: "tcp://localhost:1883", QoS: 3}, zap.NewNop())
+	assert.Error(t, err)
+
+	_, err = NewMQTTSender(ctx, MQTTConfig{URL: "http://localhost:1883"}, zap.NewNop())
+	assert.Error(t, err)
+
+	// ブローカーが接続を拒否した場合（リターンコード4: 認証エラー）
+	broker := newFakeMQTTBroker(t, 4)
+	_, err = NewMQTTSender(ctx, MQTTConfig{URL: broker.url(), Username: "user", Password: "wrong"}, zap.NewNop())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ユーザー名またはパスワードが不正です")
+}
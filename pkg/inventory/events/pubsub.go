@@ -0,0 +1,155 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"cloud.google.com/go/pubsub"
+	"go.uber.org/zap"
+	"google.golang.org/api/option"
+)
+
+// Pub/Sub topic modes
+// Pub/Subのトピック構成
+const (
+	PubSubModeSingle  = "single"   // 全イベントを1つのトピックに発行
+	PubSubModePerType = "per_type" // イベントタイプごとのトピックに発行
+)
+
+// PubSubConfig holds settings for the Google Cloud Pub/Sub publisher
+// Google Cloud Pub/Sub発行者の設定
+type PubSubConfig struct {
+	ProjectID       string // GCPプロジェクトID
+	Mode            string // トピック構成（single | per_type、既定はsingle）
+	Topic           string // singleモードの発行先トピックID（既定は inventory-events）
+	TopicTemplate   string // per_typeモードのトピックIDテンプレート（既定は inventory-{type}）
+	Ordering        bool   // 商品IDを順序キーとしてメッセージ順序を保証するか
+	CredentialsFile string // サービスアカウントキーのパス（空の場合はデフォルト認証情報）
+}
+
+// PubSubSender publishes messages to Google Cloud Pub/Sub topics
+// Google Cloud Pub/Subのトピックへメッセージを発行
+//
+// Orderingが有効な場合は商品IDを順序キーに設定するため、同じ商品のイベントは発生順に配信されます。
+// 発行に失敗した順序キーは、後続のイベントが順序を崩さないよう一時停止されるため、
+// 失敗時にResumePublishで再開します。
+type PubSubSender struct {
+	client *pubsub.Client
+	cfg    PubSubConfig
+	logger *zap.Logger
+
+	mu     sync.Mutex
+	topics map[string]*pubsub.Topic // トピックIDごとのハンドル（発行のバッファを共有するため再利用）
+}
+
+var _ Sender = (*PubSubSender)(nil)
+
+// NewPubSubSender creates a Pub/Sub client for the configured project
+// 設定されたプロジェクトのPub/Subクライアントを作成
+func NewPubSubSender(ctx context.Context, cfg PubSubConfig, logger *zap.Logger) (*PubSubSender, error) {
+	if cfg.ProjectID == "" {
+		return nil, fmt.Errorf("Pub/SubのプロジェクトIDが指定されていません")
+	}
+	switch cfg.Mode {
+	case "":
+		cfg.Mode = PubSubModeSingle
+	case PubSubModeSingle, PubSubModePerType:
+	default:
+		return nil, fmt.Errorf("無効なPub/Subトピック構成: %s", cfg.Mode)
+	}
+	if cfg.Topic == "" {
+		cfg.Topic = "inventory-events"
+	}
+	if cfg.TopicTemplate == "" {
+		cfg.TopicTemplate = "inventory-{type}"
+	}
+
+	var opts []option.ClientOption
+	if cfg.CredentialsFile != "" {
+		opts = append(opts, option.WithCredentialsFile(cfg.CredentialsFile))
+	}
+
+	client, err := pubsub.NewClient(ctx, cfg.ProjectID, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("Pub/Subクライアントの作成に失敗しました: %w", err)
+	}
+
+	return &PubSubSender{
+		client: client,
+		cfg:    cfg,
+		logger: logger,
+		topics: make(map[string]*pubsub.Topic),
+	}, nil
+}
+
+// Send publishes msg and waits until the server has accepted it
+// メッセージを発行し、サーバーに受け付けられるまで待機
+func (s *PubSubSender) Send(ctx context.Context, msg Message) error {
+	topic := s.topic(msg)
+
+	message := &pubsub.Message{
+		Data: msg.Body,
+		Attributes: map[string]string{
+			"event_id":       msg.ID,
+			"event_type":     msg.Type,
+			"item_id":        msg.ItemID,
+			"location_id":    msg.LocationID,
+			"schema_version": strconv.Itoa(msg.SchemaVersion),
+		},
+	}
+	// CloudEventsのPub/Subバインディング（構造化モード）ではcontent-type属性で形式を示す
+	if msg.ContentType == ContentTypeCloudEvents {
+		message.Attributes["content-type"] = msg.ContentType
+	}
+	if s.cfg.Ordering {
+		message.OrderingKey = msg.ItemID
+	}
+
+	if _, err := topic.Publish(ctx, message).Get(ctx); err != nil {
+		if s.cfg.Ordering {
+			topic.ResumePublish(msg.ItemID)
+		}
+		return fmt.Errorf("Pub/Subへの発行に失敗しました（トピック %s）: %w", topic.ID(), err)
+	}
+
+	return nil
+}
+
+// topic returns the topic handle for msg according to the configured mode
+// トピック構成に従ってメッセージの発行先トピックを返す
+//
+// ルーティングルールで発行先が指定されている場合は、トピック構成に関わらずそのトピックに発行します。
+func (s *PubSubSender) topic(msg Message) *pubsub.Topic {
+	id := s.cfg.Topic
+	if s.cfg.Mode == PubSubModePerType {
+		id = ExpandTemplate(s.cfg.TopicTemplate, msg)
+	}
+	if msg.Destination != "" {
+		id = Destination(id, msg)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	topic, exists := s.topics[id]
+	if !exists {
+		topic = s.client.Topic(id)
+		topic.EnableMessageOrdering = s.cfg.Ordering
+		s.topics[id] = topic
+	}
+	return topic
+}
+
+// Close flushes pending messages and closes the client
+// 未送信のメッセージを送信してクライアントを閉じる
+func (s *PubSubSender) Close() error {
+	s.mu.Lock()
+	for _, topic := range s.topics {
+		topic.Stop()
+	}
+	s.mu.Unlock()
+
+	return s.client.Close()
+}
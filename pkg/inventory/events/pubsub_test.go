@@ -0,0 +1,89 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"cloud.google.com/go/pubsub/pstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newTestPubSubSender はフェイクサーバーに接続したPubSubSenderを作成
+func newTestPubSubSender(t *testing.T, cfg PubSubConfig, topics ...string) (*PubSubSender, *pstest.Server) {
+	t.Helper()
+
+	srv := pstest.NewServer()
+	t.Cleanup(func() { srv.Close() })
+	t.Setenv("PUBSUB_EMULATOR_HOST", srv.Addr)
+
+	ctx := context.Background()
+	cfg.ProjectID = "test-project"
+	sender, err := NewPubSubSender(ctx, cfg, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() { sender.Close() })
+
+	for _, topic := range topics {
+		_, err := sender.client.CreateTopic(ctx, topic)
+		require.NoError(t, err)
+	}
+
+	return sender, srv
+}
+
+// TestPubSubSender_SingleTopic は単一トピックへの順序付き発行のテスト
+func TestPubSubSender_SingleTopic(t *testing.T) {
+	sender, srv := newTestPubSubSender(t, PubSubConfig{Topic: "inventory-events", Ordering: true}, "inventory-events")
+
+	msg := Message{ID: "TX-1", Type: TypeStockChanged, ItemID: "ITEM-1", LocationID: "LOC-A", Body: []byte(`{}`)}
+	require.NoError(t, sender.Send(context.Background(), msg))
+
+	messages := srv.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "ITEM-1", messages[0].OrderingKey)
+	assert.Equal(t, TypeStockChanged, messages[0].Attributes["event_type"])
+	assert.Equal(t, "TX-1", messages[0].Attributes["event_id"])
+}
+
+// TestPubSubSender_PerTypeTopics はイベントタイプごとのトピックへの発行のテスト
+func TestPubSubSender_PerTypeTopics(t *testing.T) {
+	sender, srv := newTestPubSubSender(t, PubSubConfig{Mode: PubSubModePerType}, "inventory-stock.changed")
+	ctx := context.Background()
+
+	require.NoError(t, sender.Send(ctx, Message{ID: "TX-1", Type: TypeStockChanged, ItemID: "ITEM-1"}))
+
+	// トピックが存在しないイベントタイプは発行に失敗する
+	assert.Error(t, sender.Send(ctx, Message{ID: "TX-2", Type: TypeItemTransferred, ItemID: "ITEM-1"}))
+
+	messages := srv.Messages()
+	require.Len(t, messages, 1)
+	assert.Empty(t, messages[0].OrderingKey)
+}
+
+// TestPubSubSender_RouteDestination はルーティングルールの発行先トピックへの発行のテスト
+func TestPubSubSender_RouteDestination(t *testing.T) {
+	sender, srv := newTestPubSubSender(t, PubSubConfig{Topic: "inventory-events"}, "inventory-tokyo")
+	routed := NewRoutingSender(sender, []Route{{LocationIDs: []string{"WH-TOKYO"}, Destination: "inventory-tokyo"}})
+	ctx := context.Background()
+
+	require.NoError(t, routed.Send(ctx, Message{ID: "TX-1", Type: TypeStockChanged, ItemID: "ITEM-1", LocationID: "WH-TOKYO"}))
+
+	// ルールに一致しないロケーションは既定のトピック（存在しない）に発行される
+	assert.Error(t, routed.Send(ctx, Message{ID: "TX-2", Type: TypeStockChanged, ItemID: "ITEM-1", LocationID: "WH-OSAKA"}))
+
+	messages := srv.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "TX-1", messages[0].Attributes["event_id"])
+}
+
+// TestNewPubSubSender_InvalidConfig は設定不備のテスト
+func TestNewPubSubSender_InvalidConfig(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := NewPubSubSender(ctx, PubSubConfig{}, zap.NewNop())
+	assert.Error(t, err)
+
+	_, err = NewPubSubSender(ctx, PubSubConfig{ProjectID: "test-project", Mode: "fanout"}, zap.NewNop())
+	assert.Error(t, err)
+}
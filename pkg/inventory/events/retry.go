@@ -0,0 +1,462 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// Failed event states
+// 発行に失敗したイベントの状態
+const (
+	FailedEventPending = "pending" // 再試行待ち
+	FailedEventDead    = "dead"    // 最大試行回数に達した（デッドレター）
+)
+
+// Errors returned by RetryStore implementations
+// RetryStore実装が返すエラー
+var (
+	ErrFailedEventNotFound = errors.New("失敗イベントが見つかりません")
+)
+
+// FailedEvent is an event whose publish failed and is kept for retry
+// 発行に失敗し、再試行のために保持されているイベント
+type FailedEvent struct {
+	ID            string          `json:"id"`              // 失敗イベントID
+	Type          string          `json:"type"`            // イベントタイプ（TypeStockChangedなど）
+	Payload       json.RawMessage `json:"payload"`         // JSONエンコードしたイベント本体
+	Status        string          `json:"status"`          // 状態（pending | dead）
+	Attempts      int             `json:"attempts"`        // 発行を試みた回数（初回を含む）
+	LastError     string          `json:"last_error"`      // 最後のエラー内容
+	NextAttemptAt time.Time       `json:"next_attempt_at"` // 次回の再試行日時
+	CreatedAt     time.Time       `json:"created_at"`      // 最初に失敗した日時
+	UpdatedAt     time.Time       `json:"updated_at"`      // 更新日時
+}
+
+// RetryStore persists failed events
+// 発行に失敗したイベントを永続化
+type RetryStore interface {
+	// 失敗イベントを追加します
+	Enqueue(ctx context.Context, event *FailedEvent) error
+	// 再試行時刻を過ぎたpendingのイベントを古い順に取得します
+	ListDue(ctx context.Context, now time.Time, limit int) ([]FailedEvent, error)
+	// 試行回数・状態・次回の再試行日時・エラー内容を更新します
+	Update(ctx context.Context, event *FailedEvent) error
+	// 失敗イベントを削除します。存在しない場合はErrFailedEventNotFoundを返します
+	Delete(ctx context.Context, id string) error
+	// 指定されたIDの失敗イベントを取得します。存在しない場合はErrFailedEventNotFoundを返します
+	Get(ctx context.Context, id string) (*FailedEvent, error)
+	// デッドレターのイベントを新しい順に取得します
+	ListDead(ctx context.Context, limit int) ([]FailedEvent, error)
+}
+
+// RetryConfig holds retry settings for failed publishes
+// 発行失敗時の再試行設定
+type RetryConfig struct {
+	MaxAttempts int           // 最大試行回数（初回を含む、到達するとデッドレター）
+	BaseDelay   time.Duration // 再試行の初回待機時間（試行ごとに倍増）
+	MaxDelay    time.Duration // 再試行の最大待機時間
+	Interval    time.Duration // 再試行キューを確認する間隔
+	BatchSize   int           // 1回の確認で再試行する最大件数
+}
+
+// DefaultRetryConfig returns the settings used for zero values
+// 未設定の項目に使用する再試行設定を返す
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts: 10,
+		BaseDelay:   5 * time.Second,
+		MaxDelay:    10 * time.Minute,
+		Interval:    5 * time.Second,
+		BatchSize:   100,
+	}
+}
+
+// retryMetrics holds the Prometheus collectors for failed publishes
+// 発行失敗用のPrometheusコレクター
+type retryMetrics struct {
+	failures     *prometheus.CounterVec
+	retries      *prometheus.CounterVec
+	deadLettered *prometheus.CounterVec
+}
+
+// RetryingPublisher wraps a publisher and retries failed publishes from a persistent queue
+// 発行者をラップし、失敗した発行を永続キューから再試行する
+//
+// 発行に失敗したイベントはRetryStoreに保存され、バックグラウンドで指数バックオフにより再試行されます。
+// MaxAttemptsに達したイベントはデッドレターとなり、ReplayDeadで手動再送できます。
+//
+// 記録するメトリクス:
+//   - zai_inventory_events_publish_failures_total: 発行に失敗してキューに保存したイベント数
+//   - zai_inventory_events_retries_total: 再試行の回数（result=success|failure）
+//   - zai_inventory_events_dead_lettered_total: デッドレターになったイベント数
+type RetryingPublisher struct {
+	next    ClosablePublisher
+	store   RetryStore
+	cfg     RetryConfig
+	metrics *retryMetrics
+	logger  *zap.Logger
+
+	stop      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+var (
+	_ ClosablePublisher              = (*RetryingPublisher)(nil)
+	_ inventory.DomainEventPublisher = (*RetryingPublisher)(nil)
+)
+
+// NewRetryingPublisher creates a retrying publisher and starts the retry loop
+// 再試行付きの発行者を作成し、再試行ループを開始
+//
+// registererがnilの場合はprometheus.DefaultRegistererに登録します。
+func NewRetryingPublisher(next ClosablePublisher, store RetryStore, cfg RetryConfig, registerer prometheus.Registerer, logger *zap.Logger) (*RetryingPublisher, error) {
+	defaults := DefaultRetryConfig()
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaults.MaxAttempts
+	}
+	if cfg.BaseDelay <= 0 {
+		cfg.BaseDelay = defaults.BaseDelay
+	}
+	if cfg.MaxDelay <= 0 {
+		cfg.MaxDelay = defaults.MaxDelay
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaults.Interval
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaults.BatchSize
+	}
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+
+	metrics := &retryMetrics{
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "zai_inventory",
+			Subsystem: "events",
+			Name:      "publish_failures_total",
+			Help:      "発行に失敗して再試行キューに保存したイベント数",
+		}, []string{"type"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "zai_inventory",
+			Subsystem: "events",
+			Name:      "retries_total",
+			Help:      "イベント発行の再試行回数",
+		}, []string{"type", "result"}),
+		deadLettered: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "zai_inventory",
+			Subsystem: "events",
+			Name:      "dead_lettered_total",
+			Help:      "デッドレターになったイベント数",
+		}, []string{"type"}),
+	}
+
+	var err error
+	if metrics.failures, err = registerCollector(registerer, metrics.failures); err != nil {
+		return nil, err
+	}
+	if metrics.retries, err = registerCollector(registerer, metrics.retries); err != nil {
+		return nil, err
+	}
+	if metrics.deadLettered, err = registerCollector(registerer, metrics.deadLettered); err != nil {
+		return nil, err
+	}
+
+	p := &RetryingPublisher{
+		next:    next,
+		store:   store,
+		cfg:     cfg,
+		metrics: metrics,
+		logger:  logger,
+		stop:    make(chan struct{}),
+	}
+
+	p.wg.Add(1)
+	go p.loop()
+
+	return p, nil
+}
+
+// registerCollector registers a collector, reusing an existing one if already registered
+// コレクターを登録（登録済みの場合は既存のコレクターを返す）
+func registerCollector[T prometheus.Collector](registerer prometheus.Registerer, collector T) (T, error) {
+	if err := registerer.Register(collector); err != nil {
+		var already prometheus.AlreadyRegisteredError
+		if errors.As(err, &already) {
+			if existing, ok := already.ExistingCollector.(T); ok {
+				return existing, nil
+			}
+		}
+		return collector, err
+	}
+	return collector, nil
+}
+
+// PublishStockChanged publishes a stock changed event, queueing it for retry on failure
+// 在庫変更イベントを発行（失敗時は再試行キューに保存）
+func (p *RetryingPublisher) PublishStockChanged(ctx context.Context, event inventory.StockChangedEvent) error {
+	return p.publish(ctx, TypeStockChanged, event, p.next.PublishStockChanged(ctx, event))
+}
+
+// PublishLowStockAlert publishes a low stock alert event, queueing it for retry on failure
+// 低在庫アラートイベントを発行（失敗時は再試行キューに保存）
+func (p *RetryingPublisher) PublishLowStockAlert(ctx context.Context, event inventory.LowStockAlertEvent) error {
+	return p.publish(ctx, TypeLowStockAlert, event, p.next.PublishLowStockAlert(ctx, event))
+}
+
+// PublishItemTransferred publishes an item transferred event, queueing it for retry on failure
+// 商品移動イベントを発行（失敗時は再試行キューに保存）
+func (p *RetryingPublisher) PublishItemTransferred(ctx context.Context, event inventory.ItemTransferredEvent) error {
+	return p.publish(ctx, TypeItemTransferred, event, p.next.PublishItemTransferred(ctx, event))
+}
+
+// PublishEvent publishes a domain event, queueing it for retry on failure
+// ドメインイベントを発行（失敗時は再試行キューに保存）
+//
+// ラップした発行者がDomainEventPublisherを実装していない場合は何もしません。
+func (p *RetryingPublisher) PublishEvent(ctx context.Context, event inventory.DomainEvent) error {
+	next, ok := p.next.(inventory.DomainEventPublisher)
+	if !ok {
+		return nil
+	}
+	return p.publish(ctx, event.Type, event, next.PublishEvent(ctx, event))
+}
+
+// publish stores event in the retry queue when publishErr is not nil
+// 発行エラーがある場合にイベントを再試行キューへ保存
+//
+// キューへの保存に成功した場合はイベントが失われないためnilを返します。
+func (p *RetryingPublisher) publish(ctx context.Context, eventType string, event interface{}, publishErr error) error {
+	if publishErr == nil {
+		return nil
+	}
+
+	// 再試行時にデコードできるよう最新のスキーマバージョンで保存する
+	payload, err := EncodeEvent(event, CurrentSchemaVersion)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	failed := &FailedEvent{
+		ID:            inventory.NewTransactionID(),
+		Type:          eventType,
+		Payload:       payload,
+		Status:        FailedEventPending,
+		Attempts:      1,
+		LastError:     publishErr.Error(),
+		NextAttemptAt: now.Add(p.cfg.BaseDelay),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if p.cfg.MaxAttempts <= 1 {
+		failed.Status = FailedEventDead
+	}
+
+	// 呼び出し元のキャンセルで保存が中断されないようにする
+	if err := p.store.Enqueue(context.WithoutCancel(ctx), failed); err != nil {
+		return errors.Join(publishErr, fmt.Errorf("再試行キューへの保存に失敗しました: %w", err))
+	}
+
+	p.metrics.failures.WithLabelValues(eventType).Inc()
+	if failed.Status == FailedEventDead {
+		p.metrics.deadLettered.WithLabelValues(eventType).Inc()
+	}
+	p.logger.Warn("イベント発行に失敗したため再試行キューに保存しました",
+		zap.String("failed_event_id", failed.ID),
+		zap.String("type", eventType),
+		zap.Error(publishErr),
+	)
+
+	return nil
+}
+
+// loop retries due events every Interval until Close is called
+// Closeが呼ばれるまでInterval間隔で再試行時刻を過ぎたイベントを再試行
+func (p *RetryingPublisher) loop() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			if err := p.RetryDue(context.Background()); err != nil {
+				p.logger.Error("イベントの再試行に失敗しました", zap.Error(err))
+			}
+		}
+	}
+}
+
+// RetryDue retries pending events whose next attempt time has passed
+// 再試行時刻を過ぎたpendingのイベントを再試行
+func (p *RetryingPublisher) RetryDue(ctx context.Context) error {
+	due, err := p.store.ListDue(ctx, time.Now(), p.cfg.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	for i := range due {
+		failed := &due[i]
+		publishErr := p.republish(ctx, failed)
+		if publishErr == nil {
+			p.metrics.retries.WithLabelValues(failed.Type, "success").Inc()
+			if err := p.store.Delete(ctx, failed.ID); err != nil {
+				return err
+			}
+			continue
+		}
+
+		p.metrics.retries.WithLabelValues(failed.Type, "failure").Inc()
+		failed.Attempts++
+		failed.LastError = publishErr.Error()
+		failed.UpdatedAt = time.Now()
+		if failed.Attempts >= p.cfg.MaxAttempts {
+			failed.Status = FailedEventDead
+			p.metrics.deadLettered.WithLabelValues(failed.Type).Inc()
+			p.logger.Error("イベントがデッドレターになりました",
+				zap.String("failed_event_id", failed.ID),
+				zap.String("type", failed.Type),
+				zap.Int("attempts", failed.Attempts),
+				zap.Error(publishErr),
+			)
+		} else {
+			failed.NextAttemptAt = failed.UpdatedAt.Add(p.backoff(failed.Attempts))
+		}
+		if err := p.store.Update(ctx, failed); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReplayDead publishes a dead-lettered event again and removes it on success
+// デッドレターのイベントを再発行し、成功した場合は削除
+//
+// 失敗した場合はエラー内容を更新してデッドレターのまま残します。
+func (p *RetryingPublisher) ReplayDead(ctx context.Context, id string) error {
+	failed, err := p.store.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if failed.Status != FailedEventDead {
+		return ErrFailedEventNotFound
+	}
+
+	if publishErr := p.republish(ctx, failed); publishErr != nil {
+		failed.Attempts++
+		failed.LastError = publishErr.Error()
+		failed.UpdatedAt = time.Now()
+		if err := p.store.Update(ctx, failed); err != nil {
+			return err
+		}
+		return fmt.Errorf("イベントの再発行に失敗しました: %w", publishErr)
+	}
+
+	return p.store.Delete(ctx, id)
+}
+
+// ListDead returns dead-lettered events, newest first
+// デッドレターのイベントを新しい順に取得
+func (p *RetryingPublisher) ListDead(ctx context.Context, limit int) ([]FailedEvent, error) {
+	return p.store.ListDead(ctx, limit)
+}
+
+// DeleteDead discards a dead-lettered event
+// デッドレターのイベントを破棄
+func (p *RetryingPublisher) DeleteDead(ctx context.Context, id string) error {
+	failed, err := p.store.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if failed.Status != FailedEventDead {
+		return ErrFailedEventNotFound
+	}
+	return p.store.Delete(ctx, id)
+}
+
+// Close stops the retry loop and closes the wrapped publisher
+// 再試行ループを停止し、ラップした発行者を閉じる
+func (p *RetryingPublisher) Close() error {
+	p.closeOnce.Do(func() {
+		close(p.stop)
+	})
+	p.wg.Wait()
+	return p.next.Close()
+}
+
+// Ping checks the broker connection of the wrapped publisher
+// ラップした発行者のブローカーとの接続を確認
+func (p *RetryingPublisher) Ping(ctx context.Context) error {
+	return Ping(ctx, p.next)
+}
+
+// republish decodes a failed event and publishes it through the wrapped publisher
+// 失敗イベントをデコードし、ラップした発行者から発行
+func (p *RetryingPublisher) republish(ctx context.Context, failed *FailedEvent) error {
+	return publishEncoded(ctx, p.next, failed.Type, failed.Payload)
+}
+
+// publishEncoded decodes an event encoded with EncodeEvent and publishes it through publisher
+// EncodeEventでエンコードしたイベントをデコードし、発行者から発行
+func publishEncoded(ctx context.Context, publisher inventory.EventPublisher, eventType string, payload json.RawMessage) error {
+	switch eventType {
+	case TypeStockChanged:
+		event, err := DecodeStockChangedEvent(payload)
+		if err != nil {
+			return err
+		}
+		return publisher.PublishStockChanged(ctx, event)
+	case TypeLowStockAlert:
+		event, err := DecodeLowStockAlertEvent(payload)
+		if err != nil {
+			return err
+		}
+		return publisher.PublishLowStockAlert(ctx, event)
+	case TypeItemTransferred:
+		event, err := DecodeItemTransferredEvent(payload)
+		if err != nil {
+			return err
+		}
+		return publisher.PublishItemTransferred(ctx, event)
+	}
+	if inventory.IsDomainEventType(eventType) {
+		event, err := DecodeDomainEvent(payload)
+		if err != nil {
+			return err
+		}
+		next, ok := publisher.(inventory.DomainEventPublisher)
+		if !ok {
+			return fmt.Errorf("発行者がドメインイベントに対応していません: %s", eventType)
+		}
+		return next.PublishEvent(ctx, event)
+	}
+	return fmt.Errorf("不明なイベントタイプです: %s", eventType)
+}
+
+// backoff returns the wait before the next attempt after attempts failures
+// attempts回失敗した後の次回までの待機時間を返す
+func (p *RetryingPublisher) backoff(attempts int) time.Duration {
+	delay := p.cfg.BaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= p.cfg.MaxDelay {
+			return p.cfg.MaxDelay
+		}
+	}
+	return delay
+}
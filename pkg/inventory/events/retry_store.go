@@ -0,0 +1,284 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryRetryStore implements RetryStore with an in-memory map
+// インメモリのマップを使用したRetryStoreの実装
+//
+// プロセスの再起動で失われるため、単体テストやデモ用途を想定しています。
+type MemoryRetryStore struct {
+	mu     sync.Mutex
+	events map[string]FailedEvent
+}
+
+var _ RetryStore = (*MemoryRetryStore)(nil)
+
+// NewMemoryRetryStore creates a new in-memory retry store
+// 新しいインメモリ再試行ストアを作成
+func NewMemoryRetryStore() *MemoryRetryStore {
+	return &MemoryRetryStore{events: make(map[string]FailedEvent)}
+}
+
+// Enqueue adds a failed event
+// 失敗イベントを追加
+func (s *MemoryRetryStore) Enqueue(ctx context.Context, event *FailedEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events[event.ID] = *event
+	return nil
+}
+
+// ListDue retrieves pending events whose next attempt time has passed, oldest first
+// 再試行時刻を過ぎたpendingのイベントを古い順に取得
+func (s *MemoryRetryStore) ListDue(ctx context.Context, now time.Time, limit int) ([]FailedEvent, error) {
+	return s.list(limit, false, func(event FailedEvent) bool {
+		return event.Status == FailedEventPending && !event.NextAttemptAt.After(now)
+	}), nil
+}
+
+// Update updates attempts, status, next attempt time and last error
+// 試行回数・状態・次回の再試行日時・エラー内容を更新
+func (s *MemoryRetryStore) Update(ctx context.Context, event *FailedEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.events[event.ID]; !exists {
+		return ErrFailedEventNotFound
+	}
+	s.events[event.ID] = *event
+	return nil
+}
+
+// Delete removes a failed event
+// 失敗イベントを削除
+func (s *MemoryRetryStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.events[id]; !exists {
+		return ErrFailedEventNotFound
+	}
+	delete(s.events, id)
+	return nil
+}
+
+// Get retrieves a failed event by ID
+// IDで失敗イベントを取得
+func (s *MemoryRetryStore) Get(ctx context.Context, id string) (*FailedEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, exists := s.events[id]
+	if !exists {
+		return nil, ErrFailedEventNotFound
+	}
+	return &event, nil
+}
+
+// ListDead retrieves dead-lettered events, newest first
+// デッドレターのイベントを新しい順に取得
+func (s *MemoryRetryStore) ListDead(ctx context.Context, limit int) ([]FailedEvent, error) {
+	return s.list(limit, true, func(event FailedEvent) bool {
+		return event.Status == FailedEventDead
+	}), nil
+}
+
+// list returns up to limit events matching match ordered by creation time
+// 条件に一致するイベントを作成日時順に最大limit件返す
+func (s *MemoryRetryStore) list(limit int, newestFirst bool, match func(FailedEvent) bool) []FailedEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var events []FailedEvent
+	for _, event := range s.events {
+		if match(event) {
+			events = append(events, event)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if newestFirst {
+			return events[i].CreatedAt.After(events[j].CreatedAt)
+		}
+		return events[i].CreatedAt.Before(events[j].CreatedAt)
+	})
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	return events
+}
+
+// PostgresRetryStore implements RetryStore using the event_retry_queue table
+// event_retry_queueテーブルを使用したRetryStoreの実装
+type PostgresRetryStore struct {
+	db *sql.DB
+}
+
+var _ RetryStore = (*PostgresRetryStore)(nil)
+
+// NewPostgresRetryStore creates a retry store on an existing connection pool
+// 既存の接続プールを使用する再試行ストアを作成
+func NewPostgresRetryStore(db *sql.DB) *PostgresRetryStore {
+	return &PostgresRetryStore{db: db}
+}
+
+// Enqueue adds a failed event
+// 失敗イベントを追加
+func (s *PostgresRetryStore) Enqueue(ctx context.Context, event *FailedEvent) error {
+	query := `
+		INSERT INTO event_retry_queue (id, type, payload, status, attempts, last_error, next_attempt_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	_, err := s.db.ExecContext(ctx, query,
+		event.ID,
+		event.Type,
+		[]byte(event.Payload),
+		event.Status,
+		event.Attempts,
+		event.LastError,
+		event.NextAttemptAt,
+		event.CreatedAt,
+		event.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("失敗イベントの保存に失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// ListDue retrieves pending events whose next attempt time has passed, oldest first
+// 再試行時刻を過ぎたpendingのイベントを古い順に取得
+func (s *PostgresRetryStore) ListDue(ctx context.Context, now time.Time, limit int) ([]FailedEvent, error) {
+	query := `
+		SELECT id, type, payload, status, attempts, last_error, next_attempt_at, created_at, updated_at
+		FROM event_retry_queue
+		WHERE status = $1 AND next_attempt_at <= $2
+		ORDER BY created_at
+		LIMIT $3`
+
+	return s.query(ctx, query, FailedEventPending, now, limit)
+}
+
+// Update updates attempts, status, next attempt time and last error
+// 試行回数・状態・次回の再試行日時・エラー内容を更新
+func (s *PostgresRetryStore) Update(ctx context.Context, event *FailedEvent) error {
+	query := `
+		UPDATE event_retry_queue
+		SET status = $2, attempts = $3, last_error = $4, next_attempt_at = $5, updated_at = $6
+		WHERE id = $1`
+
+	result, err := s.db.ExecContext(ctx, query,
+		event.ID,
+		event.Status,
+		event.Attempts,
+		event.LastError,
+		event.NextAttemptAt,
+		event.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("失敗イベントの更新に失敗しました: %w", err)
+	}
+
+	return checkAffected(result)
+}
+
+// Delete removes a failed event
+// 失敗イベントを削除
+func (s *PostgresRetryStore) Delete(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM event_retry_queue WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("失敗イベントの削除に失敗しました: %w", err)
+	}
+
+	return checkAffected(result)
+}
+
+// Get retrieves a failed event by ID
+// IDで失敗イベントを取得
+func (s *PostgresRetryStore) Get(ctx context.Context, id string) (*FailedEvent, error) {
+	query := `
+		SELECT id, type, payload, status, attempts, last_error, next_attempt_at, created_at, updated_at
+		FROM event_retry_queue
+		WHERE id = $1`
+
+	events, err := s.query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, ErrFailedEventNotFound
+	}
+	return &events[0], nil
+}
+
+// ListDead retrieves dead-lettered events, newest first
+// デッドレターのイベントを新しい順に取得
+func (s *PostgresRetryStore) ListDead(ctx context.Context, limit int) ([]FailedEvent, error) {
+	query := `
+		SELECT id, type, payload, status, attempts, last_error, next_attempt_at, created_at, updated_at
+		FROM event_retry_queue
+		WHERE status = $1
+		ORDER BY created_at DESC
+		LIMIT $2`
+
+	return s.query(ctx, query, FailedEventDead, limit)
+}
+
+// query runs a SELECT over event_retry_queue and scans the rows
+// event_retry_queueのSELECTを実行して結果を読み取る
+func (s *PostgresRetryStore) query(ctx context.Context, query string, args ...interface{}) ([]FailedEvent, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("失敗イベントの取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var events []FailedEvent
+	for rows.Next() {
+		var (
+			event   FailedEvent
+			payload []byte
+		)
+		if err := rows.Scan(
+			&event.ID,
+			&event.Type,
+			&payload,
+			&event.Status,
+			&event.Attempts,
+			&event.LastError,
+			&event.NextAttemptAt,
+			&event.CreatedAt,
+			&event.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("失敗イベントの読み取りに失敗しました: %w", err)
+		}
+		event.Payload = payload
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("失敗イベントの読み取りに失敗しました: %w", err)
+	}
+
+	return events, nil
+}
+
+// checkAffected returns ErrFailedEventNotFound when no row was affected
+// 対象行がない場合にErrFailedEventNotFoundを返す
+func checkAffected(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrFailedEventNotFound
+	}
+	return nil
+}
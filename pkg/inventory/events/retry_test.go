@@ -0,0 +1,91 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// newTestRetryingPublisher は再試行ループが自動で動かない設定の発行者を作成
+func newTestRetryingPublisher(t *testing.T, sender *recordingSender, maxAttempts int) (*RetryingPublisher, *MemoryRetryStore) {
+	t.Helper()
+	store := NewMemoryRetryStore()
+	publisher, err := NewRetryingPublisher(NewPublisher(sender), store, RetryConfig{
+		MaxAttempts: maxAttempts,
+		BaseDelay:   time.Nanosecond,
+		MaxDelay:    time.Nanosecond,
+		Interval:    time.Hour,
+	}, prometheus.NewRegistry(), zap.NewNop())
+	require.NoError(t, err)
+	return publisher, store
+}
+
+// TestRetryingPublisher_RetriesUntilSuccess は失敗イベントの再試行のテスト
+func TestRetryingPublisher_RetriesUntilSuccess(t *testing.T) {
+	sender := &recordingSender{err: errors.New("broker unavailable")}
+	publisher, store := newTestRetryingPublisher(t, sender, 3)
+	ctx := context.Background()
+
+	// 発行に失敗しても再試行キューに保存されるためエラーにならない
+	require.NoError(t, publisher.PublishStockChanged(ctx, inventory.StockChangedEvent{ItemID: "ITEM-1", TransactionID: "TX-1"}))
+	assert.Equal(t, float64(1), testutil.ToFloat64(publisher.metrics.failures.WithLabelValues(TypeStockChanged)))
+
+	due, err := store.ListDue(ctx, time.Now(), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+
+	// ブローカー復旧後の再試行で発行され、キューから削除される
+	sender.err = nil
+	require.NoError(t, publisher.RetryDue(ctx))
+	require.Len(t, sender.messages, 1)
+	assert.Equal(t, "TX-1", sender.messages[0].ID)
+
+	_, err = store.Get(ctx, due[0].ID)
+	assert.ErrorIs(t, err, ErrFailedEventNotFound)
+	assert.Equal(t, float64(1), testutil.ToFloat64(publisher.metrics.retries.WithLabelValues(TypeStockChanged, "success")))
+
+	require.NoError(t, publisher.Close())
+}
+
+// TestRetryingPublisher_DeadLetterAndReplay はデッドレターと再発行のテスト
+func TestRetryingPublisher_DeadLetterAndReplay(t *testing.T) {
+	sender := &recordingSender{err: errors.New("broker unavailable")}
+	publisher, _ := newTestRetryingPublisher(t, sender, 2)
+	ctx := context.Background()
+
+	require.NoError(t, publisher.PublishLowStockAlert(ctx, inventory.LowStockAlertEvent{ItemID: "ITEM-1", LocationID: "LOC-A"}))
+	require.NoError(t, publisher.RetryDue(ctx))
+
+	dead, err := publisher.ListDead(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	assert.Equal(t, 2, dead[0].Attempts)
+	assert.Contains(t, dead[0].LastError, "broker unavailable")
+	assert.Equal(t, float64(1), testutil.ToFloat64(publisher.metrics.deadLettered.WithLabelValues(TypeLowStockAlert)))
+
+	// デッドレターは自動では再試行されない
+	require.NoError(t, publisher.RetryDue(ctx))
+	assert.Empty(t, sender.messages)
+
+	// 失敗した再発行はデッドレターのまま残る
+	assert.Error(t, publisher.ReplayDead(ctx, dead[0].ID))
+
+	sender.err = nil
+	require.NoError(t, publisher.ReplayDead(ctx, dead[0].ID))
+	require.Len(t, sender.messages, 1)
+	assert.Equal(t, TypeLowStockAlert, sender.messages[0].Type)
+
+	dead, err = publisher.ListDead(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, dead)
+	assert.ErrorIs(t, publisher.ReplayDead(ctx, "missing"), ErrFailedEventNotFound)
+}
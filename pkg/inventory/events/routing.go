@@ -0,0 +1,91 @@
+package events
+
+import (
+	"context"
+	"strings"
+)
+
+// Route maps locations to a destination of their own
+// ロケーションを専用の発行先に対応付けるルーティングルール
+//
+// 地域ごとの倉庫にそれぞれのコンシューマーがいる場合に、ロケーションごとにトピックやキューを分けるために使用します。
+type Route struct {
+	LocationIDs []string // 対象のロケーションID（末尾が * の場合は前方一致）
+	Destination string   // 発行先のテンプレート（RabbitMQはルーティングキー、Pub/Sub・MQTTはトピック。{type} {item} {location} を置換）
+}
+
+// Matches reports whether the route applies to locationID
+// ルールがロケーションに一致するかを判定
+func (r Route) Matches(locationID string) bool {
+	for _, pattern := range r.LocationIDs {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(locationID, prefix) {
+				return true
+			}
+			continue
+		}
+		if pattern == locationID {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchRoute returns the first route that applies to locationID
+// ロケーションに一致する最初のルールを返す
+func MatchRoute(routes []Route, locationID string) (Route, bool) {
+	for _, route := range routes {
+		if route.Matches(locationID) {
+			return route, true
+		}
+	}
+	return Route{}, false
+}
+
+// Destination returns the destination of msg, expanding msg.Destination if a route set it and template otherwise
+// メッセージの発行先を返す（ルールで発行先が指定されている場合はそれを、ない場合はtemplateを展開する）
+func Destination(template string, msg Message) string {
+	if msg.Destination != "" {
+		template = msg.Destination
+	}
+	return ExpandTemplate(template, msg)
+}
+
+// RoutingSender sets the destination of each message from location routing rules
+// ロケーションのルーティングルールから各メッセージの発行先を設定する
+//
+// ルールはMessage.LocationID（商品移動イベントでは移動元）で選択され、一致しない場合は各ドライバーの既定の発行先を使用します。
+// 商品移動では移動先の在庫変更イベントも発行されるため、移動先の地域のコンシューマーは在庫変更イベントで変化を受け取れます。
+type RoutingSender struct {
+	next   Sender
+	routes []Route
+}
+
+var _ Sender = (*RoutingSender)(nil)
+
+// NewRoutingSender creates a sender that routes messages by location before handing them to next
+// ロケーションで発行先を選択してからnextへ渡すSenderを作成
+func NewRoutingSender(next Sender, routes []Route) *RoutingSender {
+	return &RoutingSender{next: next, routes: routes}
+}
+
+// Send sets msg.Destination from the matching route and delivers msg through the wrapped sender
+// 一致するルールの発行先を設定し、ラップしたSenderから送信
+func (s *RoutingSender) Send(ctx context.Context, msg Message) error {
+	if route, ok := MatchRoute(s.routes, msg.LocationID); ok {
+		msg.Destination = route.Destination
+	}
+	return s.next.Send(ctx, msg)
+}
+
+// Close closes the wrapped sender
+// ラップしたSenderを閉じる
+func (s *RoutingSender) Close() error {
+	return s.next.Close()
+}
+
+// Ping checks the broker connection of the wrapped sender
+// ラップしたSenderのブローカーとの接続を確認
+func (s *RoutingSender) Ping(ctx context.Context) error {
+	return Ping(ctx, s.next)
+}
@@ -0,0 +1,74 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// TestMatchRoute はロケーションIDの完全一致・前方一致のテスト
+func TestMatchRoute(t *testing.T) {
+	routes := []Route{
+		{LocationIDs: []string{"WH-TOKYO"}, Destination: "inventory.east.{type}"},
+		{LocationIDs: []string{"WH-OSAKA-*", "WH-KOBE"}, Destination: "inventory.west.{type}"},
+	}
+
+	route, ok := MatchRoute(routes, "WH-TOKYO")
+	require.True(t, ok)
+	assert.Equal(t, "inventory.east.{type}", route.Destination)
+
+	route, ok = MatchRoute(routes, "WH-OSAKA-2")
+	require.True(t, ok)
+	assert.Equal(t, "inventory.west.{type}", route.Destination)
+
+	_, ok = MatchRoute(routes, "WH-TOKYO-2")
+	assert.False(t, ok)
+	_, ok = MatchRoute(nil, "WH-TOKYO")
+	assert.False(t, ok)
+}
+
+// TestDestination はルールの発行先とドライバーの既定の発行先の選択のテスト
+func TestDestination(t *testing.T) {
+	msg := Message{Type: TypeStockChanged, ItemID: "ITEM-1", LocationID: "WH-TOKYO"}
+	assert.Equal(t, "inventory.stock.changed", Destination("inventory.{type}", msg))
+
+	msg.Destination = "east/{location}/{item}"
+	assert.Equal(t, "east/WH-TOKYO/ITEM-1", Destination("inventory.{type}", msg))
+}
+
+// TestOpen_Routes は発行先ごとのルーティングルールのテスト
+func TestOpen_Routes(t *testing.T) {
+	east := &recordingSender{}
+	all := &recordingSender{}
+	publisher, err := Open(Config{
+		Driver: "fanout",
+		Targets: []TargetConfig{
+			{Name: "east", Driver: "east", Routes: []Route{
+				{LocationIDs: []string{"WH-TOKYO"}, Destination: "inventory.tokyo.{type}"},
+			}},
+			{Name: "all", Driver: "all"},
+		},
+		Drivers: map[string]SenderFactory{
+			"east": func() (Sender, error) { return east, nil },
+			"all":  func() (Sender, error) { return all, nil },
+		},
+	}, zap.NewNop())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, publisher.PublishStockChanged(ctx, inventory.StockChangedEvent{ItemID: "ITEM-1", LocationID: "WH-TOKYO"}))
+	require.NoError(t, publisher.PublishStockChanged(ctx, inventory.StockChangedEvent{ItemID: "ITEM-1", LocationID: "WH-OSAKA"}))
+
+	require.Len(t, east.messages, 2)
+	assert.Equal(t, "inventory.tokyo.{type}", east.messages[0].Destination)
+	assert.Empty(t, east.messages[1].Destination)
+
+	// ルールを持たない発行先には影響しない
+	require.Len(t, all.messages, 2)
+	assert.Empty(t, all.messages[0].Destination)
+}
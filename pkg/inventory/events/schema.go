@@ -0,0 +1,207 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// Event payload schema versions
+// イベント本体のスキーマバージョン
+//
+// フィールドを追加するときは新しいバージョンを定義し、upgradersとdowngradersに変換を登録します。
+const (
+	SchemaVersion1 = 1 // 初期の形式（schema_versionフィールドなし）
+	SchemaVersion2 = 2 // schema_versionとロット情報（lot_id, lot_number）を追加
+	SchemaVersion3 = 3 // 在庫変更イベントに変更数量（delta）・利用可能数量（available）・単価（unit_cost）を追加
+
+	CurrentSchemaVersion = SchemaVersion3 // 発行時の既定バージョン
+)
+
+// schemaConverter rewrites the fields of one payload in place
+// イベント本体のフィールドを書き換える変換
+type schemaConverter func(fields map[string]json.RawMessage)
+
+// upgraders[v] converts a payload of version v to v+1
+// upgraders[v] はバージョンvの本体をv+1に変換
+var upgraders = map[int]schemaConverter{
+	SchemaVersion1: func(fields map[string]json.RawMessage) {
+		// v1にはロット情報がないため、追加フィールドは未設定のまま
+	},
+	SchemaVersion2: func(fields map[string]json.RawMessage) {
+		// 変更数量は変更前後の数量から求められる（利用可能数量と単価は未設定のまま）
+		var oldQuantity, newQuantity int64
+		oldRaw, hasOld := fields["old_quantity"]
+		newRaw, hasNew := fields["new_quantity"]
+		if !hasOld || !hasNew {
+			return
+		}
+		if json.Unmarshal(oldRaw, &oldQuantity) != nil || json.Unmarshal(newRaw, &newQuantity) != nil {
+			return
+		}
+		fields["delta"] = json.RawMessage(strconv.FormatInt(newQuantity-oldQuantity, 10))
+	},
+}
+
+// downgraders[v] converts a payload of version v to v-1
+// downgraders[v] はバージョンvの本体をv-1に変換
+var downgraders = map[int]schemaConverter{
+	SchemaVersion2: func(fields map[string]json.RawMessage) {
+		delete(fields, "lot_id")
+		delete(fields, "lot_number")
+	},
+	SchemaVersion3: func(fields map[string]json.RawMessage) {
+		delete(fields, "delta")
+		delete(fields, "available")
+		delete(fields, "unit_cost")
+	},
+}
+
+// ValidSchemaVersion reports whether version can be encoded and decoded
+// エンコード・デコードできるスキーマバージョンかどうかを判定
+func ValidSchemaVersion(version int) bool {
+	return version >= SchemaVersion1 && version <= CurrentSchemaVersion
+}
+
+// EncodeEvent encodes event as JSON in the given schema version
+// イベントを指定されたスキーマバージョンのJSONにエンコード
+func EncodeEvent(event interface{}, version int) ([]byte, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("イベントのJSON変換に失敗しました: %w", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("イベントのJSON変換に失敗しました: %w", err)
+	}
+
+	// 構造体は常に最新バージョンの形式
+	return convertFields(fields, CurrentSchemaVersion, version)
+}
+
+// ConvertSchema converts an encoded event to the given schema version
+// エンコード済みイベントを指定されたスキーマバージョンに変換
+//
+// schema_versionがない本体はバージョン1として扱います。
+func ConvertSchema(data []byte, version int) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("イベントのデコードに失敗しました: %w", err)
+	}
+
+	from, err := schemaVersionOf(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertFields(fields, from, version)
+}
+
+// SchemaVersionOf returns the schema version of an encoded event
+// エンコード済みイベントのスキーマバージョンを返す
+func SchemaVersionOf(data []byte) (int, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return 0, fmt.Errorf("イベントのデコードに失敗しました: %w", err)
+	}
+	return schemaVersionOf(fields)
+}
+
+// DecodeStockChangedEvent decodes a stock changed event of any supported version
+// 対応するいずれかのバージョンの在庫変更イベントをデコード
+func DecodeStockChangedEvent(data []byte) (inventory.StockChangedEvent, error) {
+	var event inventory.StockChangedEvent
+	err := decodeEvent(data, &event)
+	return event, err
+}
+
+// DecodeLowStockAlertEvent decodes a low stock alert event of any supported version
+// 対応するいずれかのバージョンの低在庫アラートイベントをデコード
+func DecodeLowStockAlertEvent(data []byte) (inventory.LowStockAlertEvent, error) {
+	var event inventory.LowStockAlertEvent
+	err := decodeEvent(data, &event)
+	return event, err
+}
+
+// DecodeItemTransferredEvent decodes an item transferred event of any supported version
+// 対応するいずれかのバージョンの商品移動イベントをデコード
+func DecodeItemTransferredEvent(data []byte) (inventory.ItemTransferredEvent, error) {
+	var event inventory.ItemTransferredEvent
+	err := decodeEvent(data, &event)
+	return event, err
+}
+
+// DecodeDomainEvent decodes a domain event of any supported version
+// 対応するいずれかのバージョンのドメインイベントをデコード
+//
+// Dataは型情報を持たないため、JSONオブジェクトはmap[string]interface{}としてデコードされます。
+func DecodeDomainEvent(data []byte) (inventory.DomainEvent, error) {
+	var event inventory.DomainEvent
+	err := decodeEvent(data, &event)
+	return event, err
+}
+
+// decodeEvent upgrades data to the current version and unmarshals it into event
+// 最新バージョンに変換してからeventにデコード
+func decodeEvent(data []byte, event interface{}) error {
+	current, err := ConvertSchema(data, CurrentSchemaVersion)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(current, event); err != nil {
+		return fmt.Errorf("イベントのデコードに失敗しました: %w", err)
+	}
+	return nil
+}
+
+// schemaVersionOf reads schema_version, treating a missing field as version 1
+// schema_versionを読み取る（フィールドがない場合はバージョン1）
+func schemaVersionOf(fields map[string]json.RawMessage) (int, error) {
+	raw, exists := fields["schema_version"]
+	if !exists {
+		return SchemaVersion1, nil
+	}
+
+	var version int
+	if err := json.Unmarshal(raw, &version); err != nil {
+		return 0, fmt.Errorf("schema_versionが不正です: %s", raw)
+	}
+	// 0はバージョン設定前の構造体をそのままエンコードしたもの
+	if version == 0 {
+		return CurrentSchemaVersion, nil
+	}
+	if !ValidSchemaVersion(version) {
+		return 0, fmt.Errorf("サポートされていないスキーマバージョン: %d", version)
+	}
+	return version, nil
+}
+
+// convertFields applies the converters between from and to and sets schema_version
+// fromからtoまでの変換を順に適用し、schema_versionを設定
+func convertFields(fields map[string]json.RawMessage, from, to int) ([]byte, error) {
+	if !ValidSchemaVersion(to) {
+		return nil, fmt.Errorf("サポートされていないスキーマバージョン: %d", to)
+	}
+
+	for v := from; v < to; v++ {
+		upgraders[v](fields)
+	}
+	for v := from; v > to; v-- {
+		downgraders[v](fields)
+	}
+
+	if to == SchemaVersion1 {
+		delete(fields, "schema_version")
+	} else {
+		fields["schema_version"] = json.RawMessage(strconv.Itoa(to))
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("イベントのJSON変換に失敗しました: %w", err)
+	}
+	return data, nil
+}
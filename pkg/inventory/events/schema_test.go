@@ -0,0 +1,84 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// TestEncodeEvent_SchemaVersions はバージョンごとのエンコードのテスト
+func TestEncodeEvent_SchemaVersions(t *testing.T) {
+	unitCost := 120.0
+	event := inventory.StockChangedEvent{ItemID: "ITEM-1", NewQuantity: 15, LotID: "LOT-1", LotNumber: "L-001", Delta: 15, UnitCost: &unitCost}
+
+	current, err := EncodeEvent(event, CurrentSchemaVersion)
+	require.NoError(t, err)
+	version, err := SchemaVersionOf(current)
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion3, version)
+
+	v2, err := EncodeEvent(event, SchemaVersion2)
+	require.NoError(t, err)
+	var v2Fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(v2, &v2Fields))
+	assert.Equal(t, "LOT-1", v2Fields["lot_id"])
+	assert.NotContains(t, v2Fields, "delta")
+	assert.NotContains(t, v2Fields, "unit_cost")
+
+	legacy, err := EncodeEvent(event, SchemaVersion1)
+	require.NoError(t, err)
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(legacy, &fields))
+	assert.NotContains(t, fields, "schema_version")
+	assert.NotContains(t, fields, "lot_id")
+	assert.Equal(t, "ITEM-1", fields["item_id"])
+
+	_, err = EncodeEvent(event, 99)
+	assert.Error(t, err)
+}
+
+// TestDecodeEvent_UpgradesOldVersions は旧バージョンの本体のデコードのテスト
+func TestDecodeEvent_UpgradesOldVersions(t *testing.T) {
+	v1 := []byte(`{"item_id":"ITEM-1","from_location_id":"LOC-A","to_location_id":"LOC-B","quantity":5}`)
+
+	event, err := DecodeItemTransferredEvent(v1)
+	require.NoError(t, err)
+	assert.Equal(t, CurrentSchemaVersion, event.SchemaVersion)
+	assert.Equal(t, int64(5), event.Quantity)
+	assert.Empty(t, event.LotID)
+
+	// 最新→旧→最新で値が保たれる（旧バージョンにないフィールドは除く）
+	upgraded, err := ConvertSchema(v1, SchemaVersion2)
+	require.NoError(t, err)
+	downgraded, err := ConvertSchema(upgraded, SchemaVersion1)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(v1), string(downgraded))
+
+	// v3で追加した変更数量は変更前後の数量から補完される
+	changed, err := DecodeStockChangedEvent([]byte(`{"schema_version":2,"item_id":"ITEM-1","old_quantity":20,"new_quantity":15}`))
+	require.NoError(t, err)
+	assert.Equal(t, int64(-5), changed.Delta)
+	assert.Nil(t, changed.Available)
+	assert.Nil(t, changed.UnitCost)
+
+	_, err = DecodeStockChangedEvent([]byte(`{"schema_version":99}`))
+	assert.Error(t, err)
+}
+
+// TestPublisher_SchemaVersion は発行時のスキーマバージョン指定のテスト
+func TestPublisher_SchemaVersion(t *testing.T) {
+	sender := &recordingSender{}
+	publisher := NewPublisher(sender)
+	require.NoError(t, publisher.SetSchemaVersion(SchemaVersion1))
+	assert.Error(t, publisher.SetSchemaVersion(0))
+
+	require.NoError(t, publisher.PublishStockChanged(context.Background(), inventory.StockChangedEvent{ItemID: "ITEM-1", LotID: "LOT-1"}))
+	require.Len(t, sender.messages, 1)
+	assert.Equal(t, SchemaVersion1, sender.messages[0].SchemaVersion)
+	assert.NotContains(t, string(sender.messages[0].Body), "lot_id")
+}
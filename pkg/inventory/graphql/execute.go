@@ -0,0 +1,488 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// request is a GraphQL request
+// GraphQLリクエスト
+type request struct {
+	Query         string                 `json:"query"`         // クエリドキュメント
+	OperationName string                 `json:"operationName"` // 実行する操作の名前（複数の操作がある場合に必要）
+	Variables     map[string]interface{} `json:"variables"`     // 変数の値
+}
+
+// response is a GraphQL response
+// GraphQLレスポンス
+//
+// 実行を開始した場合は data を必ず含み（エラーで null になる場合を含む）、
+// 構文エラーや検証エラーで実行しなかった場合は errors のみを含みます。
+type response struct {
+	Data     *orderedMap // 実行結果
+	Errors   []*Error    // エラー
+	executed bool
+}
+
+// MarshalJSON encodes the response in the GraphQL response format
+// GraphQLレスポンス形式でエンコード
+func (r *response) MarshalJSON() ([]byte, error) {
+	out := newOrderedMap()
+	if r.executed {
+		out.set("data", r.Data)
+	}
+	if len(r.Errors) > 0 {
+		out.set("errors", r.Errors)
+	}
+	return json.Marshal(out)
+}
+
+// requestError returns a response that was not executed
+// 実行しなかったレスポンスを返す
+func requestError(errs ...*Error) *response {
+	return &response{Errors: errs}
+}
+
+// orderedMap is a JSON object that keeps the order of its keys
+// キーの順序を保持するJSONオブジェクト（レスポンスのフィールドはクエリの順に並ぶ）
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newOrderedMap() *orderedMap {
+	return &orderedMap{values: make(map[string]interface{})}
+}
+
+func (m *orderedMap) set(key string, v interface{}) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = v
+}
+
+// get returns the value of a key
+// キーの値を返す
+func (m *orderedMap) get(key string) interface{} {
+	return m.values[key]
+}
+
+// MarshalJSON encodes the object with its keys in insertion order
+// 追加した順にキーを並べてエンコード
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// execute parses, validates and executes a request against the schema
+// リクエストを解析・検証し、スキーマに対して実行する
+func execute(ctx context.Context, s *schema, req request, maxDepth int) *response {
+	doc, err := parse(req.Query)
+	if err != nil {
+		return requestError(err.(*Error))
+	}
+	if errs := validate(s, doc, maxDepth); len(errs) > 0 {
+		return requestError(errs...)
+	}
+
+	op, gqlErr := selectOperation(doc, req.OperationName)
+	if gqlErr != nil {
+		return requestError(gqlErr)
+	}
+	variables, errs := coerceVariables(op, req.Variables)
+	if len(errs) > 0 {
+		return requestError(errs...)
+	}
+
+	e := &executor{schema: s, fragments: doc.fragments, variables: variables}
+	data, ok := e.executeSelections(ctx, s.query, nil, op.selections, nil)
+	if !ok {
+		data = nil
+	}
+	return &response{Data: data, Errors: e.errors, executed: true}
+}
+
+// selectOperation returns the operation to execute
+// 実行する操作を返す
+func selectOperation(doc *document, name string) (*operation, *Error) {
+	if name == "" {
+		if len(doc.operations) > 1 {
+			return nil, &Error{Message: "複数の操作が定義されています。operationName を指定してください"}
+		}
+		return doc.operations[0], nil
+	}
+	for _, op := range doc.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, &Error{Message: fmt.Sprintf("操作 %s が定義されていません", name)}
+}
+
+// coerceVariables coerces the request variables to the declared types
+// リクエストの変数を宣言した型に変換
+func coerceVariables(op *operation, values map[string]interface{}) (map[string]interface{}, []*Error) {
+	coerced := make(map[string]interface{}, len(op.variables))
+	var errs []*Error
+	for _, def := range op.variables {
+		v, present := values[def.name]
+		if !present {
+			if def.defaultValue != nil {
+				literal, err := literalValue(def.defaultValue, nil)
+				if err == nil {
+					v, err = coerceInput(def.typ, literal)
+				}
+				if err != nil {
+					errs = append(errs, newError(def.pos, "変数 $%s の既定値が不正です: %v", def.name, err))
+					continue
+				}
+				coerced[def.name] = v
+				continue
+			}
+			if def.typ.nonNull {
+				errs = append(errs, newError(def.pos, "変数 $%s が指定されていません", def.name))
+			}
+			continue
+		}
+		c, err := coerceInput(def.typ, v)
+		if err != nil {
+			errs = append(errs, newError(def.pos, "変数 $%s の値が不正です: %v", def.name, err))
+			continue
+		}
+		coerced[def.name] = c
+	}
+	return coerced, errs
+}
+
+// literalValue converts a literal to a Go value, substituting variables
+// リテラルをGoの値に変換（変数は値に置き換える）
+func literalValue(v *value, variables map[string]interface{}) (interface{}, error) {
+	switch v.kind {
+	case valueVariable:
+		return variables[v.raw], nil
+	case valueInt:
+		n, err := strconv.ParseInt(v.raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("整数の範囲外の値です: %s", v.raw)
+		}
+		return n, nil
+	case valueFloat:
+		return strconv.ParseFloat(v.raw, 64)
+	case valueString:
+		return v.raw, nil
+	case valueBoolean:
+		return v.raw == "true", nil
+	case valueNull:
+		return nil, nil
+	case valueList:
+		list := make([]interface{}, 0, len(v.list))
+		for _, item := range v.list {
+			c, err := literalValue(item, variables)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, c)
+		}
+		return list, nil
+	default:
+		return nil, fmt.Errorf("この値は指定できません")
+	}
+}
+
+// coerceInput coerces an argument or variable value to an input type
+// 引数・変数の値を入力型に変換
+func coerceInput(t *typeRef, v interface{}) (interface{}, error) {
+	if v == nil {
+		if t.nonNull {
+			return nil, fmt.Errorf("%s に null は指定できません", t)
+		}
+		return nil, nil
+	}
+	if t.elem != nil {
+		items, ok := v.([]interface{})
+		if !ok {
+			items = []interface{}{v}
+		}
+		list := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			c, err := coerceInput(t.elem, item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, c)
+		}
+		return list, nil
+	}
+	sc, ok := scalars[t.name]
+	if !ok {
+		return nil, fmt.Errorf("入力に使用できない型です: %s", t.name)
+	}
+	return sc.parse(v)
+}
+
+// executor executes the selections of one operation
+// 1つの操作の選択を実行する
+type executor struct {
+	schema    *schema
+	fragments map[string]*fragment
+	variables map[string]interface{}
+	errors    []*Error
+}
+
+// fieldError records an error for a field
+// フィールドのエラーを記録
+func (e *executor) fieldError(node *fieldNode, path []interface{}, err error) {
+	e.errors = append(e.errors, &Error{
+		Message:   err.Error(),
+		Locations: []SourceLocation{node.pos},
+		Path:      append([]interface{}(nil), path...),
+	})
+}
+
+// collectedField is a response key with the field nodes merged into it
+// レスポンスのキーとそのキーにまとめられたフィールド
+type collectedField struct {
+	key   string
+	nodes []*fieldNode
+}
+
+// collectFields flattens fragments and applies @skip/@include for an object type
+// オブジェクト型についてフラグメントを展開し、@skip・@include を適用する
+func (e *executor) collectFields(obj *object, selections []selection, fields []*collectedField, visited map[string]bool) []*collectedField {
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *fieldNode:
+			if !e.included(sel.directives) {
+				continue
+			}
+			key := sel.responseKey()
+			merged := false
+			for _, f := range fields {
+				if f.key == key {
+					f.nodes = append(f.nodes, sel)
+					merged = true
+					break
+				}
+			}
+			if !merged {
+				fields = append(fields, &collectedField{key: key, nodes: []*fieldNode{sel}})
+			}
+		case *fragmentSpread:
+			if !e.included(sel.directives) || visited[sel.name] {
+				continue
+			}
+			visited[sel.name] = true
+			frag := e.fragments[sel.name]
+			if frag == nil || frag.typeCondition != obj.name {
+				continue
+			}
+			fields = e.collectFields(obj, frag.selections, fields, visited)
+		case *inlineFragment:
+			if !e.included(sel.directives) || (sel.typeCondition != "" && sel.typeCondition != obj.name) {
+				continue
+			}
+			fields = e.collectFields(obj, sel.selections, fields, visited)
+		}
+	}
+	return fields
+}
+
+// included evaluates the @skip and @include directives
+// @skip・@include ディレクティブを評価
+func (e *executor) included(directives []*directive) bool {
+	for _, d := range directives {
+		if d.name != "skip" && d.name != "include" {
+			continue
+		}
+		var condition bool
+		for _, a := range d.arguments {
+			if a.name == "if" {
+				v, _ := literalValue(a.value, e.variables)
+				condition, _ = v.(bool)
+			}
+		}
+		if (d.name == "skip") == condition {
+			return false
+		}
+	}
+	return true
+}
+
+// executeSelections executes a selection set on a value of an object type
+// オブジェクト型の値に対して選択セットを実行する
+//
+// null を返せないフィールドが null になった場合は false を返し、親に null を伝搬させます。
+func (e *executor) executeSelections(ctx context.Context, obj *object, source interface{}, selections []selection, path []interface{}) (*orderedMap, bool) {
+	result := newOrderedMap()
+	for _, cf := range e.collectFields(obj, selections, nil, make(map[string]bool)) {
+		node := cf.nodes[0]
+		fieldPath := append(path[:len(path):len(path)], cf.key)
+		if node.name == "__typename" {
+			result.set(cf.key, obj.name)
+			continue
+		}
+		f := obj.byName[node.name]
+
+		args, err := e.coerceArguments(f, node)
+		var resolved interface{}
+		if err == nil {
+			resolved, err = f.resolve(ctx, source, args)
+		}
+		if err != nil {
+			e.fieldError(node, fieldPath, err)
+			if f.typ.nonNull {
+				return nil, false
+			}
+			result.set(cf.key, nil)
+			continue
+		}
+
+		v, ok := e.completeValue(ctx, f.typ, cf.nodes, resolved, fieldPath)
+		if !ok {
+			return nil, false
+		}
+		result.set(cf.key, v)
+	}
+	return result, true
+}
+
+// coerceArguments returns the arguments of a field with defaults applied
+// 既定値を適用したフィールドの引数を返す
+func (e *executor) coerceArguments(f *field, node *fieldNode) (map[string]interface{}, error) {
+	args := make(map[string]interface{}, len(f.args))
+	for _, def := range f.args {
+		var literal *argument
+		for _, a := range node.arguments {
+			if a.name == def.name {
+				literal = a
+			}
+		}
+
+		var v interface{}
+		present := literal != nil
+		if present {
+			if literal.value.kind == valueVariable {
+				v, present = e.variables[literal.value.raw]
+			} else {
+				var err error
+				if v, err = literalValue(literal.value, e.variables); err != nil {
+					return nil, fmt.Errorf("引数 %s: %v", def.name, err)
+				}
+			}
+		}
+		if !present {
+			if def.defaultValue != nil {
+				args[def.name] = def.defaultValue
+				continue
+			}
+			if def.typ.nonNull {
+				return nil, fmt.Errorf("引数 %s が指定されていません", def.name)
+			}
+			continue
+		}
+		c, err := coerceInput(def.typ, v)
+		if err != nil {
+			return nil, fmt.Errorf("引数 %s: %v", def.name, err)
+		}
+		args[def.name] = c
+	}
+	return args, nil
+}
+
+// completeValue converts a resolved value to the response value of type t
+// 解決した値を型tのレスポンスの値に変換
+//
+// 値が null を返せない位置で null になった場合は false を返し、親に null を伝搬させます。
+func (e *executor) completeValue(ctx context.Context, t *typeRef, nodes []*fieldNode, v interface{}, path []interface{}) (interface{}, bool) {
+	if !t.nonNull {
+		c, ok := e.completeNullable(ctx, t, nodes, v, path)
+		if !ok {
+			return nil, true
+		}
+		return c, true
+	}
+
+	nullable := *t
+	nullable.nonNull = false
+	c, ok := e.completeNullable(ctx, &nullable, nodes, v, path)
+	if !ok {
+		return nil, false
+	}
+	if c == nil {
+		e.fieldError(nodes[0], path, fmt.Errorf("null を返せないフィールドが null になりました"))
+		return nil, false
+	}
+	return c, true
+}
+
+// completeNullable converts a value of a nullable type, returning false if it must become null due to an error
+// null を許容する型の値を変換（エラーで null になる場合は false を返す）
+func (e *executor) completeNullable(ctx context.Context, t *typeRef, nodes []*fieldNode, v interface{}, path []interface{}) (interface{}, bool) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, true
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil, true
+	}
+
+	if t.elem != nil {
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			e.fieldError(nodes[0], path, fmt.Errorf("リストではない値です: %s", rv.Type()))
+			return nil, false
+		}
+		list := make([]interface{}, rv.Len())
+		for i := range list {
+			c, ok := e.completeValue(ctx, t.elem, nodes, rv.Index(i).Interface(), append(path[:len(path):len(path)], i))
+			if !ok {
+				return nil, false
+			}
+			list[i] = c
+		}
+		return list, true
+	}
+
+	if sc, ok := scalars[t.name]; ok {
+		c, err := sc.serialize(rv)
+		if err != nil {
+			e.fieldError(nodes[0], path, err)
+			return nil, false
+		}
+		return c, true
+	}
+
+	var selections []selection
+	for _, node := range nodes {
+		selections = append(selections, node.selections...)
+	}
+	m, ok := e.executeSelections(ctx, e.schema.objects[t.name], rv.Interface(), selections, path)
+	if !ok {
+		return nil, false
+	}
+	return m, true
+}
@@ -0,0 +1,129 @@
+package graphql
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// maxRequestBytes is the maximum size of a request body
+// リクエスト本文の最大サイズ
+const maxRequestBytes = 1 << 20
+
+// Config configures the GraphQL handler
+// GraphQLハンドラーの設定
+type Config struct {
+	// MaxDepth はクエリの選択セットの入れ子の上限（0以下の場合は10）
+	MaxDepth int
+}
+
+// Handler serves GraphQL queries over HTTP
+// HTTPでGraphQLのクエリを処理するハンドラー
+//
+// POST の場合は本文の JSON（{"query", "operationName", "variables"}）、GET の場合はクエリパラメーター
+// （query・operationName・variables）でリクエストを受け付け、GraphQLのレスポンス形式で応答します。
+type Handler struct {
+	manager  Manager
+	schema   *schema
+	maxDepth int
+	logger   *zap.Logger
+}
+
+// NewHandler creates a GraphQL handler over the managers
+// マネージャーを照会するGraphQLハンドラーを作成
+func NewHandler(manager Manager, config Config, logger *zap.Logger) *Handler {
+	if config.MaxDepth <= 0 {
+		config.MaxDepth = 10
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &Handler{
+		manager:  manager,
+		schema:   newInventorySchema(),
+		maxDepth: config.MaxDepth,
+		logger:   logger,
+	}
+}
+
+// ServeHTTP executes a GraphQL request
+// GraphQLリクエストを実行
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req request
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		req.Query = query.Get("query")
+		req.OperationName = query.Get("operationName")
+		if variables := query.Get("variables"); variables != "" {
+			if err := decodeJSON(strings.NewReader(variables), &req.Variables); err != nil {
+				h.writeResponse(w, http.StatusBadRequest, requestError(&Error{Message: "variables の形式が不正です"}))
+				return
+			}
+		}
+	case http.MethodPost:
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if mediaType != "application/json" {
+			h.writeResponse(w, http.StatusUnsupportedMediaType, requestError(&Error{Message: "Content-Type は application/json を指定してください"}))
+			return
+		}
+		if err := decodeJSON(http.MaxBytesReader(w, r.Body, maxRequestBytes), &req); err != nil {
+			h.writeResponse(w, http.StatusBadRequest, requestError(&Error{Message: "無効なリクエスト形式です"}))
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		h.writeResponse(w, http.StatusMethodNotAllowed, requestError(&Error{Message: "GET または POST でリクエストしてください"}))
+		return
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		h.writeResponse(w, http.StatusBadRequest, requestError(&Error{Message: "query が指定されていません"}))
+		return
+	}
+
+	resp := execute(withLoader(r.Context(), h.manager), h.schema, req, h.maxDepth)
+	status := http.StatusOK
+	if !resp.executed {
+		// 構文エラー・検証エラーなど実行しなかった場合
+		status = http.StatusBadRequest
+	}
+	for _, err := range resp.Errors {
+		h.logger.Warn("GraphQLクエリでエラーが発生しました",
+			zap.String("operation", req.OperationName),
+			zap.String("message", err.Message),
+			zap.Any("path", err.Path),
+		)
+	}
+	h.writeResponse(w, status, resp)
+}
+
+// ServeSDL writes the schema in the GraphQL schema definition language
+// スキーマをGraphQLのスキーマ定義言語（SDL）で返す
+//
+// フロントエンドのコード生成ツールなどにスキーマを渡す場合に使用します。
+func (h *Handler) ServeSDL(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	io.WriteString(w, h.schema.sdl())
+}
+
+// writeResponse writes a GraphQL response as JSON
+// GraphQLレスポンスをJSONで書き出す
+func (h *Handler) writeResponse(w http.ResponseWriter, status int, resp *response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Error("GraphQLレスポンスの書き込みに失敗しました", zap.Error(err))
+	}
+}
+
+// decodeJSON decodes JSON keeping numbers as json.Number so that integers are not rounded
+// 整数が丸められないよう数値をjson.Numberのままデコード
+func decodeJSON(r io.Reader, v interface{}) error {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
@@ -0,0 +1,219 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/storage"
+)
+
+// graphqlResult はテストで使用するレスポンスの形式
+type graphqlResult struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []Error                `json:"errors"`
+}
+
+// newTestHandler は商品1件を2つのロケーションに在庫したマネージャーのハンドラーを作成
+func newTestHandler(t *testing.T) *Handler {
+	t.Helper()
+
+	manager := inventory.NewManager(storage.NewMemoryStorage(), nil, zap.NewNop(), nil)
+	ctx := context.Background()
+	require.NoError(t, manager.CreateItem(ctx, &inventory.Item{ID: "ITEM-1", Name: "テスト商品", SKU: "SKU-1", UnitCost: 120.5}))
+	require.NoError(t, manager.CreateItem(ctx, &inventory.Item{ID: "ITEM-2", Name: "在庫なし商品"}))
+	require.NoError(t, manager.CreateLocation(ctx, &inventory.Location{ID: "LOC-A", Name: "倉庫A", IsActive: true}))
+	require.NoError(t, manager.CreateLocation(ctx, &inventory.Location{ID: "LOC-B", Name: "倉庫B", IsActive: true}))
+	require.NoError(t, manager.Add(ctx, "ITEM-1", "LOC-A", 10, "PO-1"))
+	require.NoError(t, manager.Transfer(ctx, "ITEM-1", "LOC-A", "LOC-B", 4, "TR-1"))
+
+	return NewHandler(manager, Config{MaxDepth: 4}, zap.NewNop())
+}
+
+// post はクエリをPOSTしてステータスとレスポンスを返す
+func post(t *testing.T, handler http.Handler, query string, variables map[string]interface{}) (int, graphqlResult) {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	var result graphqlResult
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	return rec.Code, result
+}
+
+// TestHandler_NestedQuery は商品とロケーションごとの在庫、履歴を1回のクエリで取得するテスト
+func TestHandler_NestedQuery(t *testing.T) {
+	handler := newTestHandler(t)
+
+	status, result := post(t, handler, `
+		query Item($id: ID!, $withLots: Boolean = false) {
+			item(id: $id) {
+				...itemFields
+				total: totalStock
+				stocks { locationId quantity location { name } }
+				history(limit: 5) { type quantity fromLocation { id } toLocationId }
+				lots @include(if: $withLots) { id }
+				__typename
+			}
+			missing: item(id: "NONE") { id }
+		}
+		fragment itemFields on Item { id name unitCost }`, map[string]interface{}{"id": "ITEM-1"})
+	require.Equal(t, http.StatusOK, status)
+	require.Empty(t, result.Errors)
+
+	item := result.Data["item"].(map[string]interface{})
+	assert.Equal(t, "ITEM-1", item["id"])
+	assert.Equal(t, "テスト商品", item["name"])
+	assert.Equal(t, 120.5, item["unitCost"])
+	assert.Equal(t, float64(10), item["total"])
+	assert.Equal(t, "Item", item["__typename"])
+	assert.NotContains(t, item, "lots")
+	assert.Nil(t, result.Data["missing"])
+
+	stocks := item["stocks"].([]interface{})
+	require.Len(t, stocks, 2)
+	quantities := map[string]interface{}{}
+	for _, s := range stocks {
+		stock := s.(map[string]interface{})
+		quantities[stock["locationId"].(string)] = stock["quantity"]
+		assert.NotEmpty(t, stock["location"].(map[string]interface{})["name"])
+	}
+	assert.Equal(t, map[string]interface{}{"LOC-A": float64(6), "LOC-B": float64(4)}, quantities)
+
+	history := item["history"].([]interface{})
+	require.Len(t, history, 2)
+	for _, h := range history {
+		tx := h.(map[string]interface{})
+		if tx["type"] == string(inventory.TransactionTypeTransfer) {
+			assert.Equal(t, "LOC-A", tx["fromLocation"].(map[string]interface{})["id"])
+			assert.Equal(t, "LOC-B", tx["toLocationId"])
+		}
+	}
+}
+
+// TestHandler_FieldOrder はレスポンスのフィールドがクエリの順に並ぶテスト
+func TestHandler_FieldOrder(t *testing.T) {
+	handler := newTestHandler(t)
+
+	body := `{"query": "{ location(id: \"LOC-B\") { name id stocks { quantity itemId } } }"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/graphql", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.JSONEq(t, `{"data":{"location":{"name":"倉庫B","id":"LOC-B","stocks":[{"quantity":4,"itemId":"ITEM-1"}]}}}`, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `{"name":"倉庫B","id":"LOC-B",`)
+}
+
+// TestHandler_GET はクエリパラメーターでのリクエストのテスト
+func TestHandler_GET(t *testing.T) {
+	handler := newTestHandler(t)
+
+	query := url.Values{
+		"query":     {`query($limit: Int) { items(limit: $limit) { id } }`},
+		"variables": {`{"limit": 1}`},
+	}
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/graphql?"+query.Encode(), nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var result graphqlResult
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	assert.Len(t, result.Data["items"], 1)
+}
+
+// TestHandler_Errors は構文・検証エラーとフィールドのエラーのテスト
+func TestHandler_Errors(t *testing.T) {
+	handler := newTestHandler(t)
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"構文エラー", `{ item(id: "ITEM-1") { id `},
+		{"存在しないフィールド", `{ item(id: "ITEM-1") { price } }`},
+		{"必須引数の不足", `{ item { id } }`},
+		{"選択セットの不足", `{ item(id: "ITEM-1") }`},
+		{"未定義の変数", `{ item(id: $id) { id } }`},
+		{"未定義のフラグメント", `{ item(id: "ITEM-1") { ...missing } }`},
+		{"フラグメントの循環参照", `{ item(id: "ITEM-1") { ...a } } fragment a on Item { ...b } fragment b on Item { ...a }`},
+		{"深さの上限", `{ item(id: "ITEM-1") { stocks { item { stocks { item { id } } } } } }`},
+		{"mutation", `mutation { item(id: "ITEM-1") { id } }`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, result := post(t, handler, tt.query, nil)
+			assert.Equal(t, http.StatusBadRequest, status)
+			assert.Nil(t, result.Data)
+			require.NotEmpty(t, result.Errors)
+			assert.NotEmpty(t, result.Errors[0].Locations)
+		})
+	}
+
+	// 実行時のエラーは該当フィールドを null にして他のフィールドは返す
+	status, result := post(t, handler, `{ item(id: "ITEM-1") { id history(limit: 1000) { id } } location(id: "LOC-A") { id } }`, nil)
+	assert.Equal(t, http.StatusOK, status)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, []interface{}{"item", "history"}, result.Errors[0].Path)
+	// history は null を返せないため item が null になる
+	assert.Nil(t, result.Data["item"])
+	assert.Equal(t, "LOC-A", result.Data["location"].(map[string]interface{})["id"])
+
+	// 変数の型の誤り
+	status, result = post(t, handler, `query($limit: Int) { items(limit: $limit) { id } }`, map[string]interface{}{"limit": "many"})
+	assert.Equal(t, http.StatusBadRequest, status)
+	require.Len(t, result.Errors, 1)
+}
+
+// TestHandler_ServeSDL はスキーマのSDLのテスト
+func TestHandler_ServeSDL(t *testing.T) {
+	handler := newTestHandler(t)
+
+	rec := httptest.NewRecorder()
+	handler.ServeSDL(rec, httptest.NewRequest(http.MethodGet, "/api/v1/graphql/schema", nil))
+
+	sdl := rec.Body.String()
+	assert.Contains(t, sdl, "scalar Time")
+	assert.Contains(t, sdl, "type Query {")
+	assert.Contains(t, sdl, "  item(id: ID!): Item\n")
+	assert.Contains(t, sdl, "  history(limit: Int = 20): [Transaction!]!\n")
+
+	// SDLの型表記は解析して元に戻せる
+	for _, obj := range handler.schema.objects {
+		for _, f := range obj.fields {
+			assert.Equal(t, f.typ.String(), mustParseType(f.typ.String()).String())
+		}
+	}
+}
+
+// TestParse_Values は文字列・数値リテラルの解析のテスト
+func TestParse_Values(t *testing.T) {
+	doc, err := parse(`{ searchItems(query: "a\"bあ") { id } lot(id: """
+		ブロック
+		  文字列
+	""") { id } }`)
+	require.NoError(t, err)
+	fields := doc.operations[0].selections
+	assert.Equal(t, "a\"bあ", fields[0].(*fieldNode).arguments[0].value.raw)
+	assert.Equal(t, "ブロック\n  文字列", fields[1].(*fieldNode).arguments[0].value.raw)
+
+	for _, src := range []string{`{ a(x: 01) }`, `{ a(x: 1.) }`, `{ a(x: "abc) }`, `{ a(x: 1e) }`, `{ a }}`, `{ a . b }`} {
+		_, err := parse(src)
+		assert.Error(t, err, src)
+	}
+}
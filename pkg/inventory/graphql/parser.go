@@ -0,0 +1,786 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// tokenKind is the kind of a lexical token
+// 字句トークンの種類
+type tokenKind int
+
+const (
+	tokenEOF    tokenKind = iota // 終端
+	tokenPunct                   // 区切り記号（! $ ( ) ... : = @ [ ] { | }）
+	tokenName                    // 名前
+	tokenInt                     // 整数
+	tokenFloat                   // 浮動小数点数
+	tokenString                  // 文字列（エスケープ解除済み）
+)
+
+// token is a lexical token of a GraphQL document
+// GraphQLドキュメントの字句トークン
+type token struct {
+	kind  tokenKind
+	value string
+	pos   SourceLocation
+}
+
+// tokenize splits a GraphQL document into tokens
+// GraphQLドキュメントをトークンに分割
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	line, column := 1, 1
+	i := 0
+	advance := func(n int) {
+		for _, r := range src[i : i+n] {
+			if r == '\n' {
+				line++
+				column = 1
+			} else {
+				column++
+			}
+		}
+		i += n
+	}
+
+	for i < len(src) {
+		pos := SourceLocation{Line: line, Column: column}
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == ',':
+			advance(1)
+		case strings.HasPrefix(src[i:], "\ufeff"):
+			advance(len("\ufeff"))
+		case c == '#':
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			advance(end)
+		case strings.IndexByte("!$&()=:@[]{}|", c) >= 0:
+			tokens = append(tokens, token{kind: tokenPunct, value: string(c), pos: pos})
+			advance(1)
+		case c == '.':
+			if !strings.HasPrefix(src[i:], "...") {
+				return nil, newError(pos, "予期しない文字です: %q", ".")
+			}
+			tokens = append(tokens, token{kind: tokenPunct, value: "...", pos: pos})
+			advance(3)
+		case isNameStart(c):
+			end := i + 1
+			for end < len(src) && (isNameStart(src[end]) || isDigit(src[end])) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenName, value: src[i:end], pos: pos})
+			advance(end - i)
+		case c == '-' || isDigit(c):
+			kind, end, err := scanNumber(src, i)
+			if err != nil {
+				return nil, newError(pos, "%v", err)
+			}
+			tokens = append(tokens, token{kind: kind, value: src[i:end], pos: pos})
+			advance(end - i)
+		case c == '"':
+			value, end, err := scanString(src, i)
+			if err != nil {
+				return nil, newError(pos, "%v", err)
+			}
+			tokens = append(tokens, token{kind: tokenString, value: value, pos: pos})
+			advance(end - i)
+		default:
+			r, _ := utf8.DecodeRuneInString(src[i:])
+			return nil, newError(pos, "予期しない文字です: %q", r)
+		}
+	}
+	tokens = append(tokens, token{kind: tokenEOF, pos: SourceLocation{Line: line, Column: column}})
+	return tokens, nil
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// scanNumber scans an IntValue or FloatValue starting at src[start]
+// src[start]から始まる整数または浮動小数点数を読み取る
+func scanNumber(src string, start int) (tokenKind, int, error) {
+	i := start
+	if src[i] == '-' {
+		i++
+	}
+	digits := func() int {
+		begin := i
+		for i < len(src) && isDigit(src[i]) {
+			i++
+		}
+		return i - begin
+	}
+	n := digits()
+	if n == 0 {
+		return 0, 0, fmt.Errorf("数値の形式が不正です")
+	}
+	if n > 1 && src[i-n] == '0' {
+		return 0, 0, fmt.Errorf("数値の先頭に0は指定できません")
+	}
+
+	kind := tokenInt
+	if i < len(src) && src[i] == '.' {
+		i++
+		if digits() == 0 {
+			return 0, 0, fmt.Errorf("小数部がありません")
+		}
+		kind = tokenFloat
+	}
+	if i < len(src) && (src[i] == 'e' || src[i] == 'E') {
+		i++
+		if i < len(src) && (src[i] == '+' || src[i] == '-') {
+			i++
+		}
+		if digits() == 0 {
+			return 0, 0, fmt.Errorf("指数部がありません")
+		}
+		kind = tokenFloat
+	}
+	if i < len(src) && (isNameStart(src[i]) || src[i] == '.') {
+		return 0, 0, fmt.Errorf("数値の形式が不正です")
+	}
+	return kind, i, nil
+}
+
+// scanString scans a string or block string starting at src[start] and returns its value
+// src[start]から始まる文字列（ブロック文字列を含む）を読み取り、値を返す
+func scanString(src string, start int) (string, int, error) {
+	if strings.HasPrefix(src[start:], `"""`) {
+		var raw strings.Builder
+		i := start + 3
+		for i < len(src) {
+			switch {
+			case strings.HasPrefix(src[i:], `\"""`):
+				raw.WriteString(`"""`)
+				i += 4
+			case strings.HasPrefix(src[i:], `"""`):
+				return blockStringValue(raw.String()), i + 3, nil
+			default:
+				raw.WriteByte(src[i])
+				i++
+			}
+		}
+		return "", 0, fmt.Errorf("ブロック文字列が閉じられていません")
+	}
+
+	var value strings.Builder
+	i := start + 1
+	for i < len(src) {
+		c := src[i]
+		switch c {
+		case '"':
+			return value.String(), i + 1, nil
+		case '\n', '\r':
+			return "", 0, fmt.Errorf("文字列が閉じられていません")
+		case '\\':
+			if i+1 >= len(src) {
+				return "", 0, fmt.Errorf("文字列が閉じられていません")
+			}
+			switch src[i+1] {
+			case '"', '\\', '/':
+				value.WriteByte(src[i+1])
+			case 'b':
+				value.WriteByte('\b')
+			case 'f':
+				value.WriteByte('\f')
+			case 'n':
+				value.WriteByte('\n')
+			case 'r':
+				value.WriteByte('\r')
+			case 't':
+				value.WriteByte('\t')
+			case 'u':
+				if i+6 > len(src) {
+					return "", 0, fmt.Errorf("不正なエスケープシーケンスです")
+				}
+				code, err := strconv.ParseUint(src[i+2:i+6], 16, 32)
+				if err != nil {
+					return "", 0, fmt.Errorf("不正なエスケープシーケンスです: \\u%s", src[i+2:i+6])
+				}
+				value.WriteRune(rune(code))
+				i += 4
+			default:
+				return "", 0, fmt.Errorf("不正なエスケープシーケンスです: \\%c", src[i+1])
+			}
+			i += 2
+		default:
+			value.WriteByte(c)
+			i++
+		}
+	}
+	return "", 0, fmt.Errorf("文字列が閉じられていません")
+}
+
+// blockStringValue removes the common indentation and surrounding blank lines of a block string
+// ブロック文字列の共通インデントと前後の空行を取り除く
+func blockStringValue(raw string) string {
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+	indent := -1
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" {
+			continue
+		}
+		if n := len(line) - len(trimmed); indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	if indent > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) >= indent {
+				lines[i] = lines[i][indent:]
+			} else {
+				lines[i] = ""
+			}
+		}
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// document is a parsed executable GraphQL document
+// 解析済みの実行可能なGraphQLドキュメント
+type document struct {
+	operations []*operation
+	fragments  map[string]*fragment
+}
+
+// operation is an operation definition (query, mutation or subscription)
+// 操作定義（query・mutation・subscription）
+type operation struct {
+	kind       string
+	name       string
+	variables  []*variableDefinition
+	directives []*directive
+	selections []selection
+	pos        SourceLocation
+}
+
+// variableDefinition declares a variable of an operation
+// 操作の変数宣言
+type variableDefinition struct {
+	name         string
+	typ          *typeRef
+	defaultValue *value
+	pos          SourceLocation
+}
+
+// fragment is a named fragment definition
+// 名前付きフラグメント定義
+type fragment struct {
+	name          string
+	typeCondition string
+	directives    []*directive
+	selections    []selection
+	pos           SourceLocation
+}
+
+// selection is one of *fieldNode, *fragmentSpread or *inlineFragment
+// 選択（*fieldNode・*fragmentSpread・*inlineFragment のいずれか）
+type selection interface{}
+
+// fieldNode is a field selection
+// フィールドの選択
+type fieldNode struct {
+	alias      string
+	name       string
+	arguments  []*argument
+	directives []*directive
+	selections []selection
+	pos        SourceLocation
+}
+
+// responseKey returns the key of the field in the response
+// レスポンスでのフィールドのキーを返す
+func (f *fieldNode) responseKey() string {
+	if f.alias != "" {
+		return f.alias
+	}
+	return f.name
+}
+
+// fragmentSpread is a spread of a named fragment
+// 名前付きフラグメントの展開
+type fragmentSpread struct {
+	name       string
+	directives []*directive
+	pos        SourceLocation
+}
+
+// inlineFragment is an inline fragment with an optional type condition
+// 型条件を省略できるインラインフラグメント
+type inlineFragment struct {
+	typeCondition string
+	directives    []*directive
+	selections    []selection
+	pos           SourceLocation
+}
+
+// directive is a directive such as @skip or @include
+// @skip や @include などのディレクティブ
+type directive struct {
+	name      string
+	arguments []*argument
+	pos       SourceLocation
+}
+
+// argument is an argument of a field or directive
+// フィールドまたはディレクティブの引数
+type argument struct {
+	name  string
+	value *value
+	pos   SourceLocation
+}
+
+// valueKind is the kind of an input value literal
+// 入力値リテラルの種類
+type valueKind int
+
+const (
+	valueVariable valueKind = iota
+	valueInt
+	valueFloat
+	valueString
+	valueBoolean
+	valueNull
+	valueEnum
+	valueList
+	valueObject
+)
+
+// value is an input value literal
+// 入力値リテラル
+type value struct {
+	kind   valueKind
+	raw    string
+	list   []*value
+	fields []*objectField
+	pos    SourceLocation
+}
+
+// objectField is a field of an input object literal
+// 入力オブジェクトリテラルのフィールド
+type objectField struct {
+	name  string
+	value *value
+}
+
+// parser is a recursive descent parser for executable documents
+// 実行可能ドキュメントの再帰下降パーサー
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// parse parses a GraphQL query document
+// GraphQLのクエリドキュメントを解析
+func parse(src string) (*document, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	return p.parseDocument()
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) peekPunct(s string) bool {
+	tok := p.peek()
+	return tok.kind == tokenPunct && tok.value == s
+}
+
+func (p *parser) peekKeyword(s string) bool {
+	tok := p.peek()
+	return tok.kind == tokenName && tok.value == s
+}
+
+func (p *parser) expectPunct(s string) (token, error) {
+	tok := p.next()
+	if tok.kind != tokenPunct || tok.value != s {
+		return tok, p.unexpected(tok, fmt.Sprintf("%q", s))
+	}
+	return tok, nil
+}
+
+func (p *parser) expectName() (token, error) {
+	tok := p.next()
+	if tok.kind != tokenName {
+		return tok, p.unexpected(tok, "名前")
+	}
+	return tok, nil
+}
+
+// unexpected returns a syntax error for tok
+// tokに対する構文エラーを返す
+func (p *parser) unexpected(tok token, expected string) error {
+	if tok.kind == tokenEOF {
+		return newError(tok.pos, "構文エラー: %s が必要ですが、ドキュメントが終了しました", expected)
+	}
+	return newError(tok.pos, "構文エラー: %s が必要ですが、%q がありました", expected, tok.value)
+}
+
+func (p *parser) parseDocument() (*document, error) {
+	doc := &document{fragments: make(map[string]*fragment)}
+	for p.peek().kind != tokenEOF {
+		tok := p.peek()
+		switch {
+		case p.peekPunct("{"):
+			selections, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, &operation{kind: "query", selections: selections, pos: tok.pos})
+		case p.peekKeyword("query"), p.peekKeyword("mutation"), p.peekKeyword("subscription"):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, op)
+		case p.peekKeyword("fragment"):
+			frag, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := doc.fragments[frag.name]; ok {
+				return nil, newError(frag.pos, "フラグメント %s が重複しています", frag.name)
+			}
+			doc.fragments[frag.name] = frag
+		default:
+			return nil, p.unexpected(tok, "操作またはフラグメントの定義")
+		}
+	}
+	if len(doc.operations) == 0 {
+		return nil, newError(p.peek().pos, "操作が定義されていません")
+	}
+	return doc, nil
+}
+
+func (p *parser) parseOperation() (*operation, error) {
+	tok := p.next()
+	op := &operation{kind: tok.value, pos: tok.pos}
+	if p.peek().kind == tokenName {
+		op.name = p.next().value
+	}
+	if p.peekPunct("(") {
+		p.next()
+		for !p.peekPunct(")") {
+			def, err := p.parseVariableDefinition()
+			if err != nil {
+				return nil, err
+			}
+			op.variables = append(op.variables, def)
+		}
+		p.next()
+		if len(op.variables) == 0 {
+			return nil, newError(tok.pos, "構文エラー: 変数の定義が空です")
+		}
+	}
+	var err error
+	if op.directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if op.selections, err = p.parseSelectionSet(); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+func (p *parser) parseVariableDefinition() (*variableDefinition, error) {
+	dollar, err := p.expectPunct("$")
+	if err != nil {
+		return nil, err
+	}
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.expectPunct(":"); err != nil {
+		return nil, err
+	}
+	typ, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	def := &variableDefinition{name: name.value, typ: typ, pos: dollar.pos}
+	if p.peekPunct("=") {
+		p.next()
+		if def.defaultValue, err = p.parseValue(true); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	return def, nil
+}
+
+// parseType parses a type reference such as [String!]!
+// [String!]! などの型参照を解析
+func (p *parser) parseType() (*typeRef, error) {
+	var t *typeRef
+	if p.peekPunct("[") {
+		p.next()
+		elem, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expectPunct("]"); err != nil {
+			return nil, err
+		}
+		t = &typeRef{elem: elem}
+	} else {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		t = &typeRef{name: name.value}
+	}
+	if p.peekPunct("!") {
+		p.next()
+		t.nonNull = true
+	}
+	return t, nil
+}
+
+func (p *parser) parseFragment() (*fragment, error) {
+	tok := p.next()
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if name.value == "on" {
+		return nil, newError(name.pos, "構文エラー: フラグメント名に on は使用できません")
+	}
+	if tok := p.next(); tok.kind != tokenName || tok.value != "on" {
+		return nil, p.unexpected(tok, `"on"`)
+	}
+	typeCondition, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	frag := &fragment{name: name.value, typeCondition: typeCondition.value, pos: tok.pos}
+	if frag.directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if frag.selections, err = p.parseSelectionSet(); err != nil {
+		return nil, err
+	}
+	return frag, nil
+}
+
+func (p *parser) parseSelectionSet() ([]selection, error) {
+	open, err := p.expectPunct("{")
+	if err != nil {
+		return nil, err
+	}
+	var selections []selection
+	for !p.peekPunct("}") {
+		if p.peek().kind == tokenEOF {
+			return nil, p.unexpected(p.peek(), `"}"`)
+		}
+		sel, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, sel)
+	}
+	p.next()
+	if len(selections) == 0 {
+		return nil, newError(open.pos, "構文エラー: 選択セットが空です")
+	}
+	return selections, nil
+}
+
+func (p *parser) parseSelection() (selection, error) {
+	if p.peekPunct("...") {
+		spread := p.next()
+		if p.peek().kind == tokenName && !p.peekKeyword("on") {
+			name := p.next()
+			directives, err := p.parseDirectives()
+			if err != nil {
+				return nil, err
+			}
+			return &fragmentSpread{name: name.value, directives: directives, pos: spread.pos}, nil
+		}
+
+		inline := &inlineFragment{pos: spread.pos}
+		if p.peekKeyword("on") {
+			p.next()
+			typeCondition, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+			inline.typeCondition = typeCondition.value
+		}
+		var err error
+		if inline.directives, err = p.parseDirectives(); err != nil {
+			return nil, err
+		}
+		if inline.selections, err = p.parseSelectionSet(); err != nil {
+			return nil, err
+		}
+		return inline, nil
+	}
+
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	field := &fieldNode{name: name.value, pos: name.pos}
+	if p.peekPunct(":") {
+		p.next()
+		actual, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		field.alias, field.name = name.value, actual.value
+	}
+	if field.arguments, err = p.parseArguments(); err != nil {
+		return nil, err
+	}
+	if field.directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if p.peekPunct("{") {
+		if field.selections, err = p.parseSelectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return field, nil
+}
+
+func (p *parser) parseArguments() ([]*argument, error) {
+	if !p.peekPunct("(") {
+		return nil, nil
+	}
+	open := p.next()
+	var arguments []*argument
+	for !p.peekPunct(")") {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expectPunct(":"); err != nil {
+			return nil, err
+		}
+		v, err := p.parseValue(false)
+		if err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, &argument{name: name.value, value: v, pos: name.pos})
+	}
+	p.next()
+	if len(arguments) == 0 {
+		return nil, newError(open.pos, "構文エラー: 引数が空です")
+	}
+	return arguments, nil
+}
+
+func (p *parser) parseDirectives() ([]*directive, error) {
+	var directives []*directive
+	for p.peekPunct("@") {
+		at := p.next()
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		arguments, err := p.parseArguments()
+		if err != nil {
+			return nil, err
+		}
+		directives = append(directives, &directive{name: name.value, arguments: arguments, pos: at.pos})
+	}
+	return directives, nil
+}
+
+// parseValue parses an input value; variables are rejected when constant is true
+// 入力値を解析（constantがtrueの場合は変数を受け付けない）
+func (p *parser) parseValue(constant bool) (*value, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenInt:
+		return &value{kind: valueInt, raw: tok.value, pos: tok.pos}, nil
+	case tokenFloat:
+		return &value{kind: valueFloat, raw: tok.value, pos: tok.pos}, nil
+	case tokenString:
+		return &value{kind: valueString, raw: tok.value, pos: tok.pos}, nil
+	case tokenName:
+		switch tok.value {
+		case "true", "false":
+			return &value{kind: valueBoolean, raw: tok.value, pos: tok.pos}, nil
+		case "null":
+			return &value{kind: valueNull, pos: tok.pos}, nil
+		default:
+			return &value{kind: valueEnum, raw: tok.value, pos: tok.pos}, nil
+		}
+	case tokenPunct:
+		switch tok.value {
+		case "$":
+			if constant {
+				return nil, newError(tok.pos, "構文エラー: ここでは変数を使用できません")
+			}
+			name, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+			return &value{kind: valueVariable, raw: name.value, pos: tok.pos}, nil
+		case "[":
+			list := &value{kind: valueList, pos: tok.pos}
+			for !p.peekPunct("]") {
+				item, err := p.parseValue(constant)
+				if err != nil {
+					return nil, err
+				}
+				list.list = append(list.list, item)
+			}
+			p.next()
+			return list, nil
+		case "{":
+			object := &value{kind: valueObject, pos: tok.pos}
+			for !p.peekPunct("}") {
+				name, err := p.expectName()
+				if err != nil {
+					return nil, err
+				}
+				if _, err := p.expectPunct(":"); err != nil {
+					return nil, err
+				}
+				v, err := p.parseValue(constant)
+				if err != nil {
+					return nil, err
+				}
+				object.fields = append(object.fields, &objectField{name: name.value, value: v})
+			}
+			p.next()
+			return object, nil
+		}
+	}
+	return nil, p.unexpected(tok, "値")
+}
@@ -0,0 +1,404 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// Manager is the set of managers queried by the GraphQL API
+// GraphQL APIで照会するマネージャーの集合
+type Manager interface {
+	inventory.InventoryManager
+	inventory.ItemManager
+	inventory.LocationManager
+	inventory.LotManager
+}
+
+var _ Manager = (*inventory.Manager)(nil)
+
+// maxListLimit is the upper bound of the limit arguments, same as the REST API
+// limit引数の上限（REST APIと同じ）
+const maxListLimit = 100
+
+// metadataEntry is a key/value pair of transaction metadata
+// トランザクションのメタデータのキーと値
+type metadataEntry struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// loader caches items and locations for the duration of one request
+// 1つのリクエストの間、商品とロケーションをキャッシュする
+//
+// 在庫一覧の各要素から商品やロケーションを解決する場合に、同じIDの照会を繰り返さないようにします。
+type loader struct {
+	manager      Manager
+	items        map[string]cached[*inventory.Item]
+	locations    map[string]cached[*inventory.Location]
+	allLocations *cached[[]inventory.Location]
+}
+
+// cached is a cached result of a manager call
+// マネージャー呼び出しのキャッシュされた結果
+type cached[T any] struct {
+	value T
+	err   error
+}
+
+type loaderKey struct{}
+
+// withLoader returns a context carrying a new request-scoped loader
+// リクエスト単位の新しいローダーを持つコンテキストを返す
+func withLoader(ctx context.Context, manager Manager) context.Context {
+	return context.WithValue(ctx, loaderKey{}, &loader{
+		manager:   manager,
+		items:     make(map[string]cached[*inventory.Item]),
+		locations: make(map[string]cached[*inventory.Location]),
+	})
+}
+
+// loaderFrom returns the loader of the request
+// リクエストのローダーを返す
+func loaderFrom(ctx context.Context) *loader {
+	return ctx.Value(loaderKey{}).(*loader)
+}
+
+// item returns an item, or nil if it does not exist
+// 商品を返す（存在しない場合はnil）
+func (l *loader) item(ctx context.Context, itemID string) (*inventory.Item, error) {
+	if c, ok := l.items[itemID]; ok {
+		return c.value, c.err
+	}
+	item, err := l.manager.GetItem(ctx, itemID)
+	if errors.Is(err, inventory.ErrItemNotFound) {
+		item, err = nil, nil
+	}
+	l.items[itemID] = cached[*inventory.Item]{value: item, err: err}
+	return item, err
+}
+
+// location returns a location, or nil if it does not exist
+// ロケーションを返す（存在しない場合はnil）
+func (l *loader) location(ctx context.Context, locationID string) (*inventory.Location, error) {
+	if c, ok := l.locations[locationID]; ok {
+		return c.value, c.err
+	}
+	location, err := l.manager.GetLocation(ctx, locationID)
+	if errors.Is(err, inventory.ErrLocationNotFound) {
+		location, err = nil, nil
+	}
+	l.locations[locationID] = cached[*inventory.Location]{value: location, err: err}
+	return location, err
+}
+
+// everyLocation returns all locations, paging through ListLocations once per request
+// すべてのロケーションを返す（ListLocations のページ送りはリクエストごとに1度だけ行う）
+func (l *loader) everyLocation(ctx context.Context) ([]inventory.Location, error) {
+	if l.allLocations != nil {
+		return l.allLocations.value, l.allLocations.err
+	}
+	var all []inventory.Location
+	var err error
+	for offset := 0; ; offset += maxListLimit {
+		var page []inventory.Location
+		page, err = l.manager.ListLocations(ctx, offset, maxListLimit)
+		if err != nil {
+			all = nil
+			break
+		}
+		all = append(all, page...)
+		if len(page) < maxListLimit {
+			break
+		}
+	}
+	l.allLocations = &cached[[]inventory.Location]{value: all, err: err}
+	for i := range all {
+		l.locations[all[i].ID] = cached[*inventory.Location]{value: &all[i]}
+	}
+	return all, err
+}
+
+// newInventorySchema builds the GraphQL schema over the managers
+// マネージャーを照会するGraphQLスキーマを構築
+func newInventorySchema() *schema {
+	query := newObject("Query", "在庫の照会",
+		newField("item", "Item", "商品（存在しない場合は null）", func(ctx context.Context, _ interface{}, args map[string]interface{}) (interface{}, error) {
+			return loaderFrom(ctx).item(ctx, args["id"].(string))
+		}, arg("id", "ID!", nil)),
+		newField("items", "[Item!]!", "商品の一覧", func(ctx context.Context, _ interface{}, args map[string]interface{}) (interface{}, error) {
+			offset, limit, err := pageArgs(args)
+			if err != nil {
+				return nil, err
+			}
+			items, err := loaderFrom(ctx).manager.ListItems(ctx, offset, limit)
+			if err != nil {
+				return nil, err
+			}
+			l := loaderFrom(ctx)
+			for i := range items {
+				l.items[items[i].ID] = cached[*inventory.Item]{value: &items[i]}
+			}
+			return items, nil
+		}, arg("offset", "Int", 0), arg("limit", "Int", 20)),
+		newField("searchItems", "[Item!]!", "商品の検索", func(ctx context.Context, _ interface{}, args map[string]interface{}) (interface{}, error) {
+			return loaderFrom(ctx).manager.SearchItems(ctx, args["query"].(string))
+		}, arg("query", "String!", nil)),
+		newField("location", "Location", "ロケーション（存在しない場合は null）", func(ctx context.Context, _ interface{}, args map[string]interface{}) (interface{}, error) {
+			return loaderFrom(ctx).location(ctx, args["id"].(string))
+		}, arg("id", "ID!", nil)),
+		newField("locations", "[Location!]!", "ロケーションの一覧", func(ctx context.Context, _ interface{}, args map[string]interface{}) (interface{}, error) {
+			offset, limit, err := pageArgs(args)
+			if err != nil {
+				return nil, err
+			}
+			locations, err := loaderFrom(ctx).manager.ListLocations(ctx, offset, limit)
+			if err != nil {
+				return nil, err
+			}
+			l := loaderFrom(ctx)
+			for i := range locations {
+				l.locations[locations[i].ID] = cached[*inventory.Location]{value: &locations[i]}
+			}
+			return locations, nil
+		}, arg("offset", "Int", 0), arg("limit", "Int", 20)),
+		newField("stock", "Stock", "ロケーションの商品の在庫（存在しない場合は null）", func(ctx context.Context, _ interface{}, args map[string]interface{}) (interface{}, error) {
+			stock, err := loaderFrom(ctx).manager.GetStock(ctx, args["itemId"].(string), args["locationId"].(string))
+			return orNil(stock, err, inventory.ErrStockNotFound)
+		}, arg("itemId", "ID!", nil), arg("locationId", "ID!", nil)),
+		newField("transaction", "Transaction", "トランザクション（存在しない場合は null）", func(ctx context.Context, _ interface{}, args map[string]interface{}) (interface{}, error) {
+			tx, err := loaderFrom(ctx).manager.GetTransaction(ctx, args["id"].(string))
+			return orNil(tx, err, inventory.ErrTransactionNotFound)
+		}, arg("id", "ID!", nil)),
+		newField("transactionsByReference", "[Transaction!]!", "参照番号のトランザクション", func(ctx context.Context, _ interface{}, args map[string]interface{}) (interface{}, error) {
+			return loaderFrom(ctx).manager.GetHistoryByReference(ctx, args["reference"].(string))
+		}, arg("reference", "String!", nil)),
+		newField("lot", "Lot", "ロット（存在しない場合は null）", func(ctx context.Context, _ interface{}, args map[string]interface{}) (interface{}, error) {
+			lot, err := loaderFrom(ctx).manager.GetLot(ctx, args["id"].(string))
+			return orNil(lot, err, inventory.ErrLotNotFound)
+		}, arg("id", "ID!", nil)),
+		newField("expiringLots", "[Lot!]!", "指定した時間以内に期限切れになるロット", func(ctx context.Context, _ interface{}, args map[string]interface{}) (interface{}, error) {
+			return loaderFrom(ctx).manager.GetExpiringLots(ctx, time.Duration(args["withinHours"].(int))*time.Hour)
+		}, arg("withinHours", "Int!", nil)),
+		newField("alerts", "[StockAlert!]!", "ロケーションのアクティブなアラート", func(ctx context.Context, _ interface{}, args map[string]interface{}) (interface{}, error) {
+			return loaderFrom(ctx).manager.GetAlerts(ctx, args["locationId"].(string))
+		}, arg("locationId", "ID!", nil)),
+	)
+
+	item := newObject("Item", "商品",
+		newField("id", "ID!", "", nil),
+		newField("name", "String!", "", nil),
+		newField("sku", "String!", "", nil),
+		newField("description", "String!", "", nil),
+		newField("category", "String!", "", nil),
+		newField("unitCost", "Float!", "単価", nil),
+		newField("createdAt", "Time!", "", nil),
+		newField("updatedAt", "Time!", "", nil),
+		newField("totalStock", "Int!", "全ロケーションの合計在庫", func(ctx context.Context, source interface{}, _ map[string]interface{}) (interface{}, error) {
+			return loaderFrom(ctx).manager.GetTotalStock(ctx, source.(inventory.Item).ID)
+		}),
+		newField("stocks", "[Stock!]!", "在庫があるロケーションごとの在庫", func(ctx context.Context, source interface{}, _ map[string]interface{}) (interface{}, error) {
+			l := loaderFrom(ctx)
+			locations, err := l.everyLocation(ctx)
+			if err != nil {
+				return nil, err
+			}
+			stocks := make([]*inventory.Stock, 0)
+			for _, location := range locations {
+				stock, err := l.manager.GetStock(ctx, source.(inventory.Item).ID, location.ID)
+				if errors.Is(err, inventory.ErrStockNotFound) {
+					continue
+				}
+				if err != nil {
+					return nil, err
+				}
+				stocks = append(stocks, stock)
+			}
+			return stocks, nil
+		}),
+		newField("stock", "Stock", "ロケーションの在庫（存在しない場合は null）", func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			stock, err := loaderFrom(ctx).manager.GetStock(ctx, source.(inventory.Item).ID, args["locationId"].(string))
+			return orNil(stock, err, inventory.ErrStockNotFound)
+		}, arg("locationId", "ID!", nil)),
+		newField("history", "[Transaction!]!", "新しい順のトランザクション履歴", func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			limit, err := limitArg(args)
+			if err != nil {
+				return nil, err
+			}
+			return loaderFrom(ctx).manager.GetHistory(ctx, source.(inventory.Item).ID, limit)
+		}, arg("limit", "Int", 20)),
+		newField("lots", "[Lot!]!", "商品のロット", func(ctx context.Context, source interface{}, _ map[string]interface{}) (interface{}, error) {
+			return loaderFrom(ctx).manager.GetLotsByItem(ctx, source.(inventory.Item).ID)
+		}),
+	)
+
+	location := newObject("Location", "ロケーション（倉庫・店舗など）",
+		newField("id", "ID!", "", nil),
+		newField("name", "String!", "", nil),
+		newField("type", "String!", "", nil),
+		newField("address", "String!", "", nil),
+		newField("capacity", "Int!", "最大収容量", nil),
+		newField("isActive", "Boolean!", "", nil),
+		newField("priority", "Int!", "引当の優先順位（小さいほど優先）", nil),
+		newField("latitude", "Float", "緯度", nil),
+		newField("longitude", "Float", "経度", nil),
+		newField("createdAt", "Time!", "", nil),
+		newField("updatedAt", "Time!", "", nil),
+		newField("stocks", "[Stock!]!", "ロケーションの在庫", func(ctx context.Context, source interface{}, _ map[string]interface{}) (interface{}, error) {
+			return loaderFrom(ctx).manager.GetStockByLocation(ctx, source.(inventory.Location).ID)
+		}),
+		newField("history", "[Transaction!]!", "新しい順のトランザクション履歴", func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			limit, err := limitArg(args)
+			if err != nil {
+				return nil, err
+			}
+			return loaderFrom(ctx).manager.GetHistoryByLocation(ctx, source.(inventory.Location).ID, limit)
+		}, arg("limit", "Int", 20)),
+		newField("alerts", "[StockAlert!]!", "アクティブなアラート", func(ctx context.Context, source interface{}, _ map[string]interface{}) (interface{}, error) {
+			return loaderFrom(ctx).manager.GetAlerts(ctx, source.(inventory.Location).ID)
+		}),
+	)
+
+	stock := newObject("Stock", "ロケーションの商品の在庫",
+		newField("itemId", "ID!", "", nil),
+		newField("locationId", "ID!", "", nil),
+		newField("quantity", "Int!", "在庫数量", nil),
+		newField("reserved", "Int!", "予約済み数量", nil),
+		newField("available", "Int!", "利用可能数量", nil),
+		newField("version", "Int!", "", nil),
+		newField("updatedAt", "Time!", "", nil),
+		newField("updatedBy", "String!", "", nil),
+		newField("item", "Item", "", func(ctx context.Context, source interface{}, _ map[string]interface{}) (interface{}, error) {
+			return loaderFrom(ctx).item(ctx, source.(inventory.Stock).ItemID)
+		}),
+		newField("location", "Location", "", func(ctx context.Context, source interface{}, _ map[string]interface{}) (interface{}, error) {
+			return loaderFrom(ctx).location(ctx, source.(inventory.Stock).LocationID)
+		}),
+	)
+
+	transaction := newObject("Transaction", "在庫移動の記録",
+		newField("id", "ID!", "", nil),
+		newField("type", "String!", "inbound・outbound・transfer・adjust・reserve・release のいずれか", nil),
+		newField("itemId", "ID!", "", nil),
+		newField("fromLocationId", "ID", "移動元（入庫の場合は null）", structField("from_location")),
+		newField("toLocationId", "ID", "移動先（出庫の場合は null）", structField("to_location")),
+		newField("quantity", "Int!", "", nil),
+		newField("unitCost", "Float", "", nil),
+		newField("reference", "String!", "参照番号", nil),
+		newField("lotNumber", "String", "", nil),
+		newField("expiryDate", "Time", "", nil),
+		newField("metadata", "[MetadataEntry!]!", "キーの順の追加メタデータ", func(_ context.Context, source interface{}, _ map[string]interface{}) (interface{}, error) {
+			metadata := source.(inventory.Transaction).Metadata
+			entries := make([]metadataEntry, 0, len(metadata))
+			for key, value := range metadata {
+				entries = append(entries, metadataEntry{Key: key, Value: value})
+			}
+			sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+			return entries, nil
+		}),
+		newField("createdAt", "Time!", "", nil),
+		newField("createdBy", "String!", "", nil),
+		newField("item", "Item", "", func(ctx context.Context, source interface{}, _ map[string]interface{}) (interface{}, error) {
+			return loaderFrom(ctx).item(ctx, source.(inventory.Transaction).ItemID)
+		}),
+		newField("fromLocation", "Location", "", func(ctx context.Context, source interface{}, _ map[string]interface{}) (interface{}, error) {
+			if id := source.(inventory.Transaction).FromLocation; id != nil {
+				return loaderFrom(ctx).location(ctx, *id)
+			}
+			return nil, nil
+		}),
+		newField("toLocation", "Location", "", func(ctx context.Context, source interface{}, _ map[string]interface{}) (interface{}, error) {
+			if id := source.(inventory.Transaction).ToLocation; id != nil {
+				return loaderFrom(ctx).location(ctx, *id)
+			}
+			return nil, nil
+		}),
+	)
+
+	metadata := newObject("MetadataEntry", "トランザクションのメタデータ",
+		newField("key", "String!", "", nil),
+		newField("value", "String!", "", nil),
+	)
+
+	lot := newObject("Lot", "ロット",
+		newField("id", "ID!", "", nil),
+		newField("number", "String!", "ロット番号", nil),
+		newField("itemId", "ID!", "", nil),
+		newField("quantity", "Int!", "", nil),
+		newField("unitCost", "Float!", "", nil),
+		newField("expiryDate", "Time", "", nil),
+		newField("createdAt", "Time!", "", nil),
+		newField("item", "Item", "", func(ctx context.Context, source interface{}, _ map[string]interface{}) (interface{}, error) {
+			return loaderFrom(ctx).item(ctx, source.(inventory.Lot).ItemID)
+		}),
+	)
+
+	alert := newObject("StockAlert", "在庫アラート",
+		newField("id", "ID!", "", nil),
+		newField("type", "String!", "low_stock・over_stock・expiring・expired・discrepancy のいずれか", nil),
+		newField("itemId", "ID!", "", nil),
+		newField("locationId", "ID!", "", nil),
+		newField("currentQty", "Int!", "", nil),
+		newField("threshold", "Int!", "", nil),
+		newField("message", "String!", "", nil),
+		newField("isActive", "Boolean!", "", nil),
+		newField("createdAt", "Time!", "", nil),
+		newField("resolvedAt", "Time", "", nil),
+		newField("severity", "String!", "info・warning・critical のいずれか", nil),
+		newField("ruleId", "String", "作成したアラートルールのID", nil),
+		newField("acknowledgedAt", "Time", "確認日時（未確認の場合はnull）", nil),
+		newField("acknowledgedBy", "String", "", nil),
+		newField("acknowledgeNote", "String", "", nil),
+		newField("resolvedBy", "String", "", nil),
+		newField("resolveNote", "String", "", nil),
+		newField("item", "Item", "", func(ctx context.Context, source interface{}, _ map[string]interface{}) (interface{}, error) {
+			return loaderFrom(ctx).item(ctx, source.(inventory.StockAlert).ItemID)
+		}),
+		newField("location", "Location", "", func(ctx context.Context, source interface{}, _ map[string]interface{}) (interface{}, error) {
+			return loaderFrom(ctx).location(ctx, source.(inventory.StockAlert).LocationID)
+		}),
+	)
+
+	s, err := newSchema(query, item, location, stock, transaction, metadata, lot, alert)
+	if err != nil {
+		panic(fmt.Sprintf("GraphQLスキーマの構築に失敗しました: %v", err))
+	}
+	return s
+}
+
+// orNil returns nil instead of the not found error
+// 見つからないエラーの場合はnilを返す
+func orNil[T any](value *T, err error, notFound error) (interface{}, error) {
+	if errors.Is(err, notFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// limitArg returns the limit argument, checking it is between 1 and maxListLimit
+// limit引数を返す（1からmaxListLimitの範囲であることを確認）
+func limitArg(args map[string]interface{}) (int, error) {
+	limit, _ := args["limit"].(int)
+	if limit <= 0 || limit > maxListLimit {
+		return 0, fmt.Errorf("limit は1から%dの範囲で指定してください", maxListLimit)
+	}
+	return limit, nil
+}
+
+// pageArgs returns the offset and limit arguments
+// offset引数とlimit引数を返す
+func pageArgs(args map[string]interface{}) (int, int, error) {
+	offset, _ := args["offset"].(int)
+	if offset < 0 {
+		return 0, 0, fmt.Errorf("offset は0以上で指定してください")
+	}
+	limit, err := limitArg(args)
+	return offset, limit, err
+}
@@ -0,0 +1,450 @@
+// Package graphql provides a read-only GraphQL API over the inventory managers
+// 在庫マネージャーを照会する読み取り専用のGraphQL APIを提供するパッケージ
+//
+// 商品・ロケーション・在庫・トランザクション・ロット・アラートを入れ子で解決できるため、
+// 商品とロケーションごとの在庫、最近の履歴を1回のクエリで取得できます。
+// スキーマはSDLとして Handler.ServeSDL から取得できます。
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// SourceLocation is a position in a GraphQL document
+// GraphQLドキュメント上の位置
+type SourceLocation struct {
+	Line   int `json:"line"`   // 行（1始まり）
+	Column int `json:"column"` // 列（1始まり）
+}
+
+// Error is an error in a GraphQL response
+// GraphQLレスポンスのエラー
+type Error struct {
+	Message   string           `json:"message"`             // メッセージ
+	Locations []SourceLocation `json:"locations,omitempty"` // ドキュメント上の位置
+	Path      []interface{}    `json:"path,omitempty"`      // エラーが発生したフィールドのパス
+}
+
+// Error returns the message of the error
+// エラーメッセージを返す
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// newError creates an error at a position in the document
+// ドキュメント上の位置のエラーを作成
+func newError(pos SourceLocation, format string, args ...interface{}) *Error {
+	return &Error{Message: fmt.Sprintf(format, args...), Locations: []SourceLocation{pos}}
+}
+
+// typeRef is a reference to a named, list or non-null type
+// 名前付き型・リスト型・非null型への参照
+type typeRef struct {
+	name    string   // 名前付き型の名前（リスト型の場合は空）
+	elem    *typeRef // リスト型の要素
+	nonNull bool     // nullを許容しない
+}
+
+// String returns the type in SDL notation
+// SDLの表記で型を返す
+func (t *typeRef) String() string {
+	s := t.name
+	if t.elem != nil {
+		s = "[" + t.elem.String() + "]"
+	}
+	if t.nonNull {
+		s += "!"
+	}
+	return s
+}
+
+// namedType returns the innermost named type
+// 最も内側の名前付き型を返す
+func (t *typeRef) namedType() string {
+	for t.elem != nil {
+		t = t.elem
+	}
+	return t.name
+}
+
+// mustParseType parses a type in SDL notation such as [Stock!]!
+// [Stock!]! などのSDL表記の型を解析（スキーマ定義の誤りはpanic）
+func mustParseType(s string) *typeRef {
+	tokens, err := tokenize(s)
+	if err != nil {
+		panic(fmt.Sprintf("型 %q を解析できません: %v", s, err))
+	}
+	p := &parser{tokens: tokens}
+	t, err := p.parseType()
+	if err != nil || p.peek().kind != tokenEOF {
+		panic(fmt.Sprintf("型 %q を解析できません", s))
+	}
+	return t
+}
+
+// resolveFunc resolves the value of a field from its parent value and arguments
+// 親の値と引数からフィールドの値を解決する関数
+type resolveFunc func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error)
+
+// object is an object type of the schema
+// スキーマのオブジェクト型
+type object struct {
+	name        string
+	description string
+	fields      []*field
+	byName      map[string]*field
+}
+
+// field is a field of an object type
+// オブジェクト型のフィールド
+type field struct {
+	name        string
+	description string
+	typ         *typeRef
+	args        []*argumentDef
+	resolve     resolveFunc
+}
+
+// argumentDef is an argument definition of a field
+// フィールドの引数定義
+type argumentDef struct {
+	name         string
+	typ          *typeRef
+	defaultValue interface{} // 既定値（nilの場合はなし）
+}
+
+// newObject creates an object type
+// オブジェクト型を作成
+func newObject(name, description string, fields ...*field) *object {
+	obj := &object{name: name, description: description, fields: fields, byName: make(map[string]*field, len(fields))}
+	for _, f := range fields {
+		obj.byName[f.name] = f
+	}
+	return obj
+}
+
+// newField creates a field; fields without a resolver read the struct field with the snake_case JSON name
+// フィールドを作成（resolveがnilの場合は同名のsnake_caseのJSONタグを持つ構造体フィールドを返す）
+func newField(name, typ, description string, resolve resolveFunc, args ...*argumentDef) *field {
+	if resolve == nil {
+		resolve = structField(snakeCase(name))
+	}
+	return &field{name: name, description: description, typ: mustParseType(typ), args: args, resolve: resolve}
+}
+
+// arg creates an argument definition
+// 引数定義を作成
+func arg(name, typ string, defaultValue interface{}) *argumentDef {
+	return &argumentDef{name: name, typ: mustParseType(typ), defaultValue: defaultValue}
+}
+
+// snakeCase converts a camelCase field name to the snake_case JSON name
+// camelCaseのフィールド名をsnake_caseのJSON名に変換
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// structField returns a resolver that reads the struct field tagged with the JSON name
+// 指定したJSONタグの構造体フィールドを返すリゾルバーを作成
+func structField(jsonName string) resolveFunc {
+	return func(_ context.Context, source interface{}, _ map[string]interface{}) (interface{}, error) {
+		rv := reflect.ValueOf(source)
+		for rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return nil, nil
+			}
+			rv = rv.Elem()
+		}
+		if rv.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("%s を取得できない値です: %T", jsonName, source)
+		}
+		for i := 0; i < rv.NumField(); i++ {
+			name, _, _ := strings.Cut(rv.Type().Field(i).Tag.Get("json"), ",")
+			if name == jsonName {
+				return rv.Field(i).Interface(), nil
+			}
+		}
+		return nil, fmt.Errorf("%s を取得できない値です: %T", jsonName, source)
+	}
+}
+
+// scalar is a scalar type of the schema
+// スキーマのスカラー型
+type scalar struct {
+	name        string
+	description string
+	serialize   func(v reflect.Value) (interface{}, error) // 結果の値をJSONの値に変換
+	parse       func(v interface{}) (interface{}, error)   // 引数・変数の値をGoの値に変換（変換済みの値も受け付ける）
+}
+
+// scalars are the scalar types available in the schema
+// スキーマで使用できるスカラー型
+var scalars = map[string]*scalar{
+	"Int": {
+		name: "Int",
+		serialize: func(v reflect.Value) (interface{}, error) {
+			var n int64
+			switch v.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				n = v.Int()
+			default:
+				return nil, fmt.Errorf("Int に変換できない値です: %s", v.Type())
+			}
+			if n < math.MinInt32 || n > math.MaxInt32 {
+				return nil, fmt.Errorf("Int の範囲外の値です: %d", n)
+			}
+			return n, nil
+		},
+		parse: func(v interface{}) (interface{}, error) {
+			var n int64
+			switch v := v.(type) {
+			case int:
+				n = int64(v)
+			case int64:
+				n = v
+			case json.Number:
+				parsed, err := v.Int64()
+				if err != nil {
+					return nil, fmt.Errorf("Int に変換できない値です: %s", v)
+				}
+				n = parsed
+			default:
+				return nil, fmt.Errorf("Int に変換できない値です: %v", v)
+			}
+			if n < math.MinInt32 || n > math.MaxInt32 {
+				return nil, fmt.Errorf("Int の範囲外の値です: %d", n)
+			}
+			return int(n), nil
+		},
+	},
+	"Float": {
+		name: "Float",
+		serialize: func(v reflect.Value) (interface{}, error) {
+			switch v.Kind() {
+			case reflect.Float32, reflect.Float64:
+				return v.Float(), nil
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				return float64(v.Int()), nil
+			}
+			return nil, fmt.Errorf("Float に変換できない値です: %s", v.Type())
+		},
+		parse: func(v interface{}) (interface{}, error) {
+			switch v := v.(type) {
+			case float64:
+				return v, nil
+			case int:
+				return float64(v), nil
+			case int64:
+				return float64(v), nil
+			case json.Number:
+				f, err := v.Float64()
+				if err != nil {
+					return nil, fmt.Errorf("Float に変換できない値です: %s", v)
+				}
+				return f, nil
+			}
+			return nil, fmt.Errorf("Float に変換できない値です: %v", v)
+		},
+	},
+	"String": {
+		name:      "String",
+		serialize: serializeString("String"),
+		parse: func(v interface{}) (interface{}, error) {
+			if s, ok := v.(string); ok {
+				return s, nil
+			}
+			return nil, fmt.Errorf("String に変換できない値です: %v", v)
+		},
+	},
+	"ID": {
+		name:      "ID",
+		serialize: serializeString("ID"),
+		parse: func(v interface{}) (interface{}, error) {
+			switch v := v.(type) {
+			case string:
+				return v, nil
+			case int64:
+				return strconv.FormatInt(v, 10), nil
+			case json.Number:
+				if _, err := v.Int64(); err == nil {
+					return v.String(), nil
+				}
+			}
+			return nil, fmt.Errorf("ID に変換できない値です: %v", v)
+		},
+	},
+	"Boolean": {
+		name: "Boolean",
+		serialize: func(v reflect.Value) (interface{}, error) {
+			if v.Kind() == reflect.Bool {
+				return v.Bool(), nil
+			}
+			return nil, fmt.Errorf("Boolean に変換できない値です: %s", v.Type())
+		},
+		parse: func(v interface{}) (interface{}, error) {
+			if b, ok := v.(bool); ok {
+				return b, nil
+			}
+			return nil, fmt.Errorf("Boolean に変換できない値です: %v", v)
+		},
+	},
+	"Time": {
+		name:        "Time",
+		description: "RFC 3339 形式の日時",
+		serialize: func(v reflect.Value) (interface{}, error) {
+			if t, ok := v.Interface().(time.Time); ok {
+				return t.Format(time.RFC3339Nano), nil
+			}
+			return nil, fmt.Errorf("Time に変換できない値です: %s", v.Type())
+		},
+		parse: func(v interface{}) (interface{}, error) {
+			if t, ok := v.(time.Time); ok {
+				return t, nil
+			}
+			if s, ok := v.(string); ok {
+				if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+					return t, nil
+				}
+			}
+			return nil, fmt.Errorf("Time に変換できない値です（RFC 3339 形式で指定してください）: %v", v)
+		},
+	},
+}
+
+// serializeString returns a serializer for string kinds, including named string types
+// 文字列型（名前付きの文字列型を含む）のシリアライザーを返す
+func serializeString(name string) func(v reflect.Value) (interface{}, error) {
+	return func(v reflect.Value) (interface{}, error) {
+		if v.Kind() == reflect.String {
+			return v.String(), nil
+		}
+		return nil, fmt.Errorf("%s に変換できない値です: %s", name, v.Type())
+	}
+}
+
+// schema is an executable schema with a query root type
+// クエリのルート型を持つ実行可能なスキーマ
+type schema struct {
+	query   *object
+	objects map[string]*object
+}
+
+// newSchema creates a schema and checks that every referenced type is defined
+// スキーマを作成し、参照しているすべての型が定義されていることを確認
+func newSchema(query *object, objects ...*object) (*schema, error) {
+	s := &schema{query: query, objects: map[string]*object{query.name: query}}
+	for _, obj := range objects {
+		if _, ok := s.objects[obj.name]; ok {
+			return nil, fmt.Errorf("型 %s が重複しています", obj.name)
+		}
+		if _, ok := scalars[obj.name]; ok {
+			return nil, fmt.Errorf("型 %s はスカラー型と重複しています", obj.name)
+		}
+		s.objects[obj.name] = obj
+	}
+	for _, obj := range s.objects {
+		for _, f := range obj.fields {
+			if !s.isOutputType(f.typ.namedType()) {
+				return nil, fmt.Errorf("%s.%s の型 %s が定義されていません", obj.name, f.name, f.typ)
+			}
+			for _, a := range f.args {
+				if _, ok := scalars[a.typ.namedType()]; !ok {
+					return nil, fmt.Errorf("%s.%s の引数 %s の型 %s はスカラー型ではありません", obj.name, f.name, a.name, a.typ)
+				}
+			}
+		}
+	}
+	return s, nil
+}
+
+// isOutputType reports whether name is a scalar or object type of the schema
+// スカラー型またはスキーマのオブジェクト型かどうか
+func (s *schema) isOutputType(name string) bool {
+	if _, ok := scalars[name]; ok {
+		return true
+	}
+	_, ok := s.objects[name]
+	return ok
+}
+
+// sdl returns the schema in the GraphQL schema definition language
+// スキーマをGraphQLのスキーマ定義言語（SDL）で返す
+func (s *schema) sdl() string {
+	var b strings.Builder
+
+	// 組み込み以外のスカラー型
+	names := make([]string, 0, len(scalars))
+	for name, sc := range scalars {
+		if sc.description != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		writeDescription(&b, "", scalars[name].description)
+		fmt.Fprintf(&b, "scalar %s\n\n", name)
+	}
+
+	fmt.Fprintf(&b, "schema {\n  query: %s\n}\n", s.query.name)
+
+	names = names[:0]
+	for name := range s.objects {
+		if name != s.query.name {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range append([]string{s.query.name}, names...) {
+		obj := s.objects[name]
+		b.WriteString("\n")
+		writeDescription(&b, "", obj.description)
+		fmt.Fprintf(&b, "type %s {\n", obj.name)
+		for _, f := range obj.fields {
+			writeDescription(&b, "  ", f.description)
+			b.WriteString("  " + f.name)
+			if len(f.args) > 0 {
+				args := make([]string, 0, len(f.args))
+				for _, a := range f.args {
+					s := a.name + ": " + a.typ.String()
+					if a.defaultValue != nil {
+						s += fmt.Sprintf(" = %v", a.defaultValue)
+					}
+					args = append(args, s)
+				}
+				b.WriteString("(" + strings.Join(args, ", ") + ")")
+			}
+			b.WriteString(": " + f.typ.String() + "\n")
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+// writeDescription writes a description string before a definition
+// 定義の前に説明文を書き出す
+func writeDescription(b *strings.Builder, indent, description string) {
+	if description == "" {
+		return
+	}
+	quoted, _ := json.Marshal(description)
+	b.WriteString(indent + string(quoted) + "\n")
+}
@@ -0,0 +1,240 @@
+package graphql
+
+import "fmt"
+
+// validator checks a document against the schema before execution
+// 実行前にドキュメントをスキーマに対して検証する
+type validator struct {
+	schema    *schema
+	doc       *document
+	maxDepth  int
+	errors    []*Error
+	reported  map[string]bool
+	variables map[string]bool // 検証中の操作で使用された変数
+}
+
+// validate returns the validation errors of a document
+// ドキュメントの検証エラーを返す
+//
+// 存在しないフィールドや引数、必須引数の不足、選択セットの誤り、未定義のフラグメント・変数、
+// フラグメントの循環参照、深さの上限（maxDepth、0の場合は無制限）を検証します。
+func validate(s *schema, doc *document, maxDepth int) []*Error {
+	v := &validator{schema: s, doc: doc, maxDepth: maxDepth, reported: make(map[string]bool)}
+
+	names := make(map[string]bool)
+	for _, op := range doc.operations {
+		if op.name == "" && len(doc.operations) > 1 {
+			v.report(op.pos, "名前のない操作は他の操作と同時に定義できません")
+		}
+		if op.name != "" {
+			if names[op.name] {
+				v.report(op.pos, "操作 %s が重複しています", op.name)
+			}
+			names[op.name] = true
+		}
+
+		root := v.rootType(op)
+		if root == nil {
+			continue
+		}
+		defined := make(map[string]bool, len(op.variables))
+		for _, def := range op.variables {
+			if defined[def.name] {
+				v.report(def.pos, "変数 $%s が重複しています", def.name)
+			}
+			defined[def.name] = true
+			if _, ok := scalars[def.typ.namedType()]; !ok {
+				v.report(def.pos, "変数 $%s の型 %s は入力に使用できません", def.name, def.typ)
+			}
+		}
+
+		v.variables = make(map[string]bool)
+		v.selections(root, op.selections, 1, make(map[string]bool))
+		for name := range v.variables {
+			if !defined[name] {
+				v.report(op.pos, "変数 $%s が定義されていません", name)
+			}
+		}
+		for _, def := range op.variables {
+			if !v.variables[def.name] {
+				v.report(def.pos, "変数 $%s が使用されていません", def.name)
+			}
+		}
+	}
+
+	for _, frag := range v.doc.fragments {
+		if _, ok := s.objects[frag.typeCondition]; !ok {
+			v.report(frag.pos, "フラグメント %s の型 %s が定義されていません", frag.name, frag.typeCondition)
+		}
+	}
+	return v.errors
+}
+
+// rootType returns the root object type of an operation
+// 操作のルートのオブジェクト型を返す（照会以外はサポートしない）
+func (v *validator) rootType(op *operation) *object {
+	if op.kind != "query" {
+		v.report(op.pos, "%s はサポートされていません（照会のみ実行できます）", op.kind)
+		return nil
+	}
+	return v.schema.query
+}
+
+// report records an error once per message and position
+// エラーを記録（同じメッセージと位置のエラーは1度だけ）
+func (v *validator) report(pos SourceLocation, format string, args ...interface{}) {
+	err := newError(pos, format, args...)
+	key := fmt.Sprintf("%d:%d:%s", pos.Line, pos.Column, err.Message)
+	if v.reported[key] {
+		return
+	}
+	v.reported[key] = true
+	v.errors = append(v.errors, err)
+}
+
+// selections validates a selection set on an object type at a nesting depth
+// オブジェクト型に対する選択セットを検証（depthは入れ子の深さ）
+func (v *validator) selections(obj *object, selections []selection, depth int, spreading map[string]bool) {
+	if v.maxDepth > 0 && depth > v.maxDepth {
+		if len(selections) > 0 {
+			v.report(selectionPos(selections[0]), "クエリの深さが上限（%d）を超えています", v.maxDepth)
+		}
+		return
+	}
+
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *fieldNode:
+			v.directives(sel.directives)
+			v.field(obj, sel, depth, spreading)
+		case *fragmentSpread:
+			v.directives(sel.directives)
+			frag, ok := v.doc.fragments[sel.name]
+			if !ok {
+				v.report(sel.pos, "フラグメント %s が定義されていません", sel.name)
+				continue
+			}
+			if spreading[sel.name] {
+				v.report(sel.pos, "フラグメント %s が循環参照しています", sel.name)
+				continue
+			}
+			if frag.typeCondition != obj.name {
+				if _, ok := v.schema.objects[frag.typeCondition]; ok {
+					v.report(sel.pos, "フラグメント %s（%s）は型 %s に展開できません", sel.name, frag.typeCondition, obj.name)
+				}
+				continue
+			}
+			v.directives(frag.directives)
+			spreading[sel.name] = true
+			v.selections(obj, frag.selections, depth, spreading)
+			delete(spreading, sel.name)
+		case *inlineFragment:
+			v.directives(sel.directives)
+			if sel.typeCondition != "" && sel.typeCondition != obj.name {
+				if _, ok := v.schema.objects[sel.typeCondition]; !ok {
+					v.report(sel.pos, "型 %s が定義されていません", sel.typeCondition)
+				} else {
+					v.report(sel.pos, "型 %s のフラグメントは型 %s に展開できません", sel.typeCondition, obj.name)
+				}
+				continue
+			}
+			v.selections(obj, sel.selections, depth, spreading)
+		}
+	}
+}
+
+// field validates a field selection and its sub-selections
+// フィールドの選択とその選択セットを検証
+func (v *validator) field(obj *object, node *fieldNode, depth int, spreading map[string]bool) {
+	if node.name == "__typename" {
+		if len(node.arguments) > 0 || len(node.selections) > 0 {
+			v.report(node.pos, "__typename には引数や選択セットを指定できません")
+		}
+		return
+	}
+	f, ok := obj.byName[node.name]
+	if !ok {
+		v.report(node.pos, "フィールド %s は型 %s に存在しません", node.name, obj.name)
+		return
+	}
+
+	given := make(map[string]bool, len(node.arguments))
+	for _, a := range node.arguments {
+		if given[a.name] {
+			v.report(a.pos, "引数 %s が重複しています", a.name)
+		}
+		given[a.name] = true
+		found := false
+		for _, def := range f.args {
+			if def.name == a.name {
+				found = true
+			}
+		}
+		if !found {
+			v.report(a.pos, "フィールド %s.%s に引数 %s はありません", obj.name, f.name, a.name)
+		}
+		v.value(a.value)
+	}
+	for _, def := range f.args {
+		if def.typ.nonNull && def.defaultValue == nil && !given[def.name] {
+			v.report(node.pos, "フィールド %s.%s の引数 %s が指定されていません", obj.name, f.name, def.name)
+		}
+	}
+
+	child, isObject := v.schema.objects[f.typ.namedType()]
+	switch {
+	case isObject && len(node.selections) == 0:
+		v.report(node.pos, "フィールド %s（%s）には選択セットが必要です", f.name, f.typ)
+	case !isObject && len(node.selections) > 0:
+		v.report(node.pos, "フィールド %s（%s）には選択セットを指定できません", f.name, f.typ)
+	case isObject:
+		v.selections(child, node.selections, depth+1, spreading)
+	}
+}
+
+// directives validates directives; only @skip and @include are supported
+// ディレクティブを検証（@skip と @include のみ対応）
+func (v *validator) directives(directives []*directive) {
+	for _, d := range directives {
+		if d.name != "skip" && d.name != "include" {
+			v.report(d.pos, "ディレクティブ @%s はサポートされていません", d.name)
+			continue
+		}
+		if len(d.arguments) != 1 || d.arguments[0].name != "if" {
+			v.report(d.pos, "@%s には引数 if を指定してください", d.name)
+			continue
+		}
+		v.value(d.arguments[0].value)
+	}
+}
+
+// value records the variables used in a value
+// 値で使用している変数を記録
+func (v *validator) value(val *value) {
+	switch val.kind {
+	case valueVariable:
+		v.variables[val.raw] = true
+	case valueList:
+		for _, item := range val.list {
+			v.value(item)
+		}
+	case valueObject:
+		for _, f := range val.fields {
+			v.value(f.value)
+		}
+	}
+}
+
+// selectionPos returns the position of a selection
+// 選択の位置を返す
+func selectionPos(sel selection) SourceLocation {
+	switch sel := sel.(type) {
+	case *fieldNode:
+		return sel.pos
+	case *fragmentSpread:
+		return sel.pos
+	case *inlineFragment:
+		return sel.pos
+	}
+	return SourceLocation{}
+}
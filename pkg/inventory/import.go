@@ -0,0 +1,301 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// maxImportRows is the maximum number of rows imported in one request
+// 一度に取り込める最大行数
+const maxImportRows = 10000
+
+// OpeningBalanceReference is the reference of transactions recorded by opening balance imports
+// 期首残高の取り込みで記録するトランザクションの参照番号
+const OpeningBalanceReference = "OPENING-BALANCE"
+
+// ItemImportRow is an item read from a row of an import file
+// 取り込みファイルの1行から読み取った商品
+type ItemImportRow struct {
+	Line int  // ファイル上の行番号
+	Item Item // 商品
+}
+
+// OpeningBalanceRow is an opening stock balance read from a row of an import file
+// 取り込みファイルの1行から読み取った期首残高
+type OpeningBalanceRow struct {
+	Line       int      // ファイル上の行番号
+	ItemID     string   // 商品ID
+	LocationID string   // ロケーションID
+	Quantity   int64    // 数量
+	UnitCost   *float64 // 単価（任意）
+}
+
+// ImportRowError is the validation error of a row of an import file
+// 取り込みファイルの行ごとのバリデーションエラー
+type ImportRowError struct {
+	Line    int    `json:"line"`            // ファイル上の行番号
+	Field   string `json:"field,omitempty"` // エラーフィールド
+	Message string `json:"message"`         // エラーメッセージ
+}
+
+// ImportResult is the result of an import
+// 取り込み結果
+//
+// 1行でもエラーがある場合は何も取り込まず、Errors にすべての行のエラーを返します。
+type ImportResult struct {
+	Rows     int              `json:"rows"`     // 読み取った行数
+	Imported int              `json:"imported"` // 取り込んだ行数
+	DryRun   bool             `json:"dry_run"`  // 検証のみ行ったかどうか
+	Errors   []ImportRowError `json:"errors"`   // 行ごとのエラー
+}
+
+// NewImportRowError converts an error into the error of a row
+// エラーを行のエラーに変換
+//
+// ValidationError の場合はフィールド名とメッセージを使用します。
+func NewImportRowError(line int, err error) ImportRowError {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return ImportRowError{Line: line, Field: validationErr.Field, Message: validationErr.Message}
+	}
+	return ImportRowError{Line: line, Message: err.Error()}
+}
+
+// checkImportRows checks the number of rows of an import
+// 取り込む行数をチェック
+func checkImportRows(n int) error {
+	if n > maxImportRows {
+		return NewValidationError("rows", fmt.Sprintf("一度に取り込める行は%d行までです", maxImportRows), fmt.Sprintf("%d", n))
+	}
+	return nil
+}
+
+// ImportItems validates and imports items in one bulk import
+// 商品を検証し、一括取り込みで登録
+//
+// 既存の商品IDやファイル内で重複するIDとSKUは行のエラーとして返します。
+// 1行でもエラーがある場合や dryRun が true の場合は何も登録しません。
+func (m *Manager) ImportItems(ctx context.Context, rows []ItemImportRow, dryRun bool) (*ImportResult, error) {
+	if err := checkImportRows(len(rows)); err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{Rows: len(rows), DryRun: dryRun, Errors: make([]ImportRowError, 0)}
+	now := time.Now()
+	ids := make(map[string]int, len(rows))
+	skus := make(map[string]int, len(rows))
+	items := make([]Item, 0, len(rows))
+	for _, row := range rows {
+		item := row.Item
+		if err := ValidateItem(&item); err != nil {
+			result.Errors = append(result.Errors, NewImportRowError(row.Line, err))
+			continue
+		}
+		if line, ok := ids[item.ID]; ok {
+			result.Errors = append(result.Errors, ImportRowError{Line: row.Line, Field: "id", Message: fmt.Sprintf("商品IDが%d行目と重複しています", line)})
+			continue
+		}
+		ids[item.ID] = row.Line
+		if item.SKU != "" {
+			if line, ok := skus[item.SKU]; ok {
+				result.Errors = append(result.Errors, ImportRowError{Line: row.Line, Field: "sku", Message: fmt.Sprintf("SKUが%d行目と重複しています", line)})
+				continue
+			}
+			skus[item.SKU] = row.Line
+		}
+
+		_, err := m.storage.GetItem(ctx, item.ID)
+		if err == nil {
+			result.Errors = append(result.Errors, ImportRowError{Line: row.Line, Field: "id", Message: "商品は既に登録されています"})
+			continue
+		}
+		if !errors.Is(err, ErrItemNotFound) {
+			return nil, NewStorageError("get_item", "商品の取得に失敗しました", err)
+		}
+
+		item.CreatedAt = now
+		item.UpdatedAt = now
+		items = append(items, item)
+	}
+
+	if len(result.Errors) > 0 || dryRun {
+		return result, nil
+	}
+
+	if err := m.storage.BulkImport(ctx, &BulkImportData{Items: items}); err != nil {
+		return nil, NewStorageError("bulk_import", "商品の取り込みに失敗しました", err)
+	}
+	result.Imported = len(items)
+
+	for i := range items {
+		m.publishEvent(ctx, EventTypeItemCreated, items[i].ID, "", &items[i])
+	}
+	m.logger.Info("商品取り込み完了", zap.Int("items", len(items)))
+
+	return result, nil
+}
+
+// ImportOpeningBalances validates and imports opening stock balances in one bulk import
+// 期首残高を検証し、一括取り込みで登録
+//
+// 在庫記録が存在しない商品とロケーションの組のみ取り込めます。
+// 数量が正の行は参照番号 OpeningBalanceReference の入庫トランザクションも記録するため、
+// トランザクション台帳との整合性チェックで不一致になりません。
+// 1行でもエラーがある場合や dryRun が true の場合は何も登録しません。
+func (m *Manager) ImportOpeningBalances(ctx context.Context, rows []OpeningBalanceRow, dryRun bool) (*ImportResult, error) {
+	if err := checkImportRows(len(rows)); err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{Rows: len(rows), DryRun: dryRun, Errors: make([]ImportRowError, 0)}
+	items := make(map[string]bool)
+	locations := make(map[string]bool)
+	lines := make(map[StockKey]int, len(rows))
+	valid := make([]OpeningBalanceRow, 0, len(rows))
+	for _, row := range rows {
+		if err := m.validateOpeningBalance(ctx, row, items, locations); err != nil {
+			var storageErr *StorageError
+			if errors.As(err, &storageErr) {
+				return nil, err
+			}
+			result.Errors = append(result.Errors, NewImportRowError(row.Line, err))
+			continue
+		}
+		key := StockKey{ItemID: row.ItemID, LocationID: row.LocationID}
+		if line, ok := lines[key]; ok {
+			result.Errors = append(result.Errors, ImportRowError{Line: row.Line, Message: fmt.Sprintf("商品とロケーションの組が%d行目と重複しています", line)})
+			continue
+		}
+		lines[key] = row.Line
+		valid = append(valid, row)
+	}
+
+	// 既存の在庫記録は上書きしない
+	keys := make([]StockKey, 0, len(valid))
+	for _, row := range valid {
+		keys = append(keys, StockKey{ItemID: row.ItemID, LocationID: row.LocationID})
+	}
+	if len(keys) > 0 {
+		existing, err := m.storage.GetStocks(ctx, keys)
+		if err != nil {
+			return nil, NewStorageError("get_stocks", "在庫取得に失敗しました", err)
+		}
+		for _, stock := range existing {
+			line := lines[StockKey{ItemID: stock.ItemID, LocationID: stock.LocationID}]
+			result.Errors = append(result.Errors, ImportRowError{Line: line, Message: fmt.Sprintf("在庫記録が既に存在します（現在の数量: %d）", stock.Quantity)})
+		}
+	}
+
+	if len(result.Errors) > 0 || dryRun {
+		return result, nil
+	}
+
+	now := time.Now()
+	user := m.getUserFromContext(ctx)
+	data := &BulkImportData{Stocks: make([]Stock, 0, len(valid))}
+	txIDs := make([]string, len(valid))
+	for i, row := range valid {
+		data.Stocks = append(data.Stocks, Stock{
+			ItemID:     row.ItemID,
+			LocationID: row.LocationID,
+			Quantity:   row.Quantity,
+			Available:  row.Quantity,
+			Version:    1,
+			UpdatedAt:  now,
+			UpdatedBy:  user,
+		})
+		if row.Quantity == 0 {
+			continue
+		}
+		locationID := row.LocationID
+		txIDs[i] = NewTransactionID()
+		data.Transactions = append(data.Transactions, Transaction{
+			ID:         txIDs[i],
+			Type:       TransactionTypeInbound,
+			ItemID:     row.ItemID,
+			ToLocation: &locationID,
+			Quantity:   row.Quantity,
+			UnitCost:   row.UnitCost,
+			Reference:  OpeningBalanceReference,
+			CreatedAt:  now,
+			CreatedBy:  user,
+		})
+	}
+
+	if err := m.storage.BulkImport(ctx, data); err != nil {
+		return nil, NewStorageError("bulk_import", "期首残高の取り込みに失敗しました", err)
+	}
+	result.Imported = len(valid)
+
+	if m.publisher != nil {
+		for i, row := range valid {
+			var unitCost float64
+			if row.UnitCost != nil {
+				unitCost = *row.UnitCost
+			}
+			event := m.newStockChangedEvent(ctx, &data.Stocks[i], 0, unitCost, "add", OpeningBalanceReference, txIDs[i])
+			if err := m.publisher.PublishStockChanged(ctx, event); err != nil {
+				m.logger.Error("イベント発行に失敗しました", zap.Error(err))
+			}
+		}
+	}
+	m.logger.Info("期首残高取り込み完了",
+		zap.Int("stocks", len(data.Stocks)),
+		zap.Int("transactions", len(data.Transactions)),
+	)
+
+	return result, nil
+}
+
+// validateOpeningBalance validates a row of opening balances
+// 期首残高の行をバリデーション
+//
+// 商品とロケーションの存在確認結果は items と locations にキャッシュします。
+func (m *Manager) validateOpeningBalance(ctx context.Context, row OpeningBalanceRow, items, locations map[string]bool) error {
+	if err := ValidateItemID(row.ItemID); err != nil {
+		return err
+	}
+	if err := ValidateLocationID(row.LocationID); err != nil {
+		return err
+	}
+	if err := ValidateQuantity(row.Quantity, false); err != nil {
+		return err
+	}
+	if row.UnitCost != nil {
+		if err := ValidateUnitCost(*row.UnitCost); err != nil {
+			return err
+		}
+	}
+
+	exists, checked := items[row.ItemID]
+	if !checked {
+		_, err := m.storage.GetItem(ctx, row.ItemID)
+		if err != nil && !errors.Is(err, ErrItemNotFound) {
+			return NewStorageError("get_item", "商品の取得に失敗しました", err)
+		}
+		exists = err == nil
+		items[row.ItemID] = exists
+	}
+	if !exists {
+		return NewValidationError("item_id", "商品が見つかりません", row.ItemID)
+	}
+
+	exists, checked = locations[row.LocationID]
+	if !checked {
+		_, err := m.storage.GetLocation(ctx, row.LocationID)
+		if err != nil && !errors.Is(err, ErrLocationNotFound) {
+			return NewStorageError("get_location", "ロケーションの取得に失敗しました", err)
+		}
+		exists = err == nil
+		locations[row.LocationID] = exists
+	}
+	if !exists {
+		return NewValidationError("location_id", "ロケーションが見つかりません", row.LocationID)
+	}
+
+	return nil
+}
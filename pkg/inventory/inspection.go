@@ -0,0 +1,270 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var _ InspectionManager = (*Manager)(nil)
+
+// ReceiveForInspection records received stock in quarantine until it passes QC inspection
+// 入荷した在庫を品質検査（QC）の合否が決まるまで隔離（検品待ち）として記録
+//
+// ItemID・LocationID・Quantity・Reference（任意）・Note（任意）を指定します。在庫（Stock）は変更せず、
+// 検品待ちの数量は利用可能数・引当の対象になりません。ID・状態・入荷日時・入荷者は設定されます。
+func (m *Manager) ReceiveForInspection(ctx context.Context, inspection *Inspection) (err error) {
+	ctx, finish := m.startOperation(ctx, "receive_for_inspection", attrItemID.String(inspection.ItemID), attrLocationID.String(inspection.LocationID), attrQuantity.Int64(inspection.Quantity), attrReference.String(inspection.Reference))
+	defer finish(&err)
+
+	if err := ValidateInspection(inspection); err != nil {
+		return err
+	}
+	if _, _, err := m.validateItemAndLocation(ctx, inspection.ItemID, inspection.LocationID); err != nil {
+		return err
+	}
+
+	inspection.ID = NewInspectionID()
+	inspection.Status = InspectionStatusQuarantined
+	inspection.Reason = ""
+	inspection.TransactionID = ""
+	inspection.ReceivedAt = time.Now()
+	inspection.ReceivedBy = m.getUserFromContext(ctx)
+	inspection.InspectedAt, inspection.InspectedBy, inspection.InspectionNote = nil, "", ""
+	if inspection.Reference == "" {
+		inspection.Reference = inspection.ID
+	}
+
+	if err := m.storage.CreateInspection(ctx, inspection); err != nil {
+		return NewStorageError("create_inspection", "入荷検品の記録に失敗しました", err)
+	}
+
+	m.log(ctx).Info("検品待ちの入荷を記録",
+		zap.String("inspection_id", inspection.ID),
+		zap.String("item_id", inspection.ItemID),
+		zap.String("location_id", inspection.LocationID),
+		zap.Int64("quantity", inspection.Quantity),
+		zap.String("reference", inspection.Reference),
+	)
+	m.publishEvent(ctx, EventTypeStockQuarantined, inspection.ItemID, inspection.LocationID, *inspection)
+	return nil
+}
+
+// GetInspection retrieves a receiving inspection
+// 入荷検品を取得
+func (m *Manager) GetInspection(ctx context.Context, inspectionID string) (*Inspection, error) {
+	if inspectionID == "" {
+		return nil, NewValidationError("inspection_id", "検品IDが指定されていません", "")
+	}
+
+	inspection, err := m.storage.GetInspection(ctx, inspectionID)
+	if err != nil {
+		if errors.Is(err, ErrInspectionNotFound) {
+			return nil, ErrInspectionNotFound
+		}
+		return nil, NewStorageError("get_inspection", "入荷検品の取得に失敗しました", err)
+	}
+	return inspection, nil
+}
+
+// ListInspections lists receiving inspections matching a filter, newest first
+// 条件に一致する入荷検品を入荷日時の新しい順に取得
+func (m *Manager) ListInspections(ctx context.Context, filter InspectionFilter) ([]Inspection, error) {
+	if err := validateOffsetLimit(filter.Offset, filter.Limit); err != nil {
+		return nil, err
+	}
+	switch filter.Status {
+	case "", InspectionStatusQuarantined, InspectionStatusPassed, InspectionStatusFailed:
+	default:
+		return nil, NewValidationError("status", "入荷検品の状態が正しくありません", string(filter.Status))
+	}
+
+	inspections, err := m.storage.ListInspections(ctx, filter)
+	if err != nil {
+		return nil, NewStorageError("list_inspections", "入荷検品一覧の取得に失敗しました", err)
+	}
+	return inspections, nil
+}
+
+// PassInspection releases quarantined stock that passed QC inspection into available stock
+// 品質検査に合格した検品待ちの入荷を在庫に入庫
+//
+// 入荷数量を入庫（inbound）のトランザクションで在庫に加算し、トランザクションの
+// メタデータに検品IDを記録します。
+func (m *Manager) PassInspection(ctx context.Context, inspectionID, note string) (_ *Inspection, err error) {
+	ctx, finish := m.startOperation(ctx, "pass_inspection", attrInspectionID.String(inspectionID))
+	defer finish(&err)
+
+	if err := validateInspectionDisposition(inspectionID, note); err != nil {
+		return nil, err
+	}
+	inspection, err := m.GetInspection(ctx, inspectionID)
+	if err != nil {
+		return nil, err
+	}
+	if inspection.Status != InspectionStatusQuarantined {
+		return nil, ErrInspectionNotQuarantined
+	}
+	item, location, err := m.validateItemAndLocation(ctx, inspection.ItemID, inspection.LocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		events *bufferedPublisher
+		stock  *Stock
+	)
+	err = m.retryOnConflict(ctx, func() error {
+		events = &bufferedPublisher{}
+		return m.storage.WithinTx(ctx, func(txStorage Storage) error {
+			txManager := m.withStorage(txStorage, events)
+
+			if m.config.LockingStrategy == LockingStrategyPessimistic {
+				if _, err := txStorage.GetStockForUpdate(ctx, inspection.ItemID, inspection.LocationID); err != nil && !errors.Is(err, ErrStockNotFound) {
+					return NewStorageError("get_stock", "在庫取得に失敗しました", err)
+				}
+			}
+			if err := txManager.enforceCapacity(ctx, location, inspection.Quantity); err != nil {
+				return err
+			}
+
+			var (
+				oldQuantity int64
+				err         error
+			)
+			oldQuantity, stock, err = txManager.increaseStock(ctx, inspection.ItemID, inspection.LocationID, inspection.Quantity)
+			if err != nil {
+				return err
+			}
+
+			locationID := inspection.LocationID
+			tx := &Transaction{
+				ID:         NewTransactionID(),
+				Type:       TransactionTypeInbound,
+				ItemID:     inspection.ItemID,
+				ToLocation: &locationID,
+				Quantity:   inspection.Quantity,
+				Reference:  inspection.Reference,
+				Metadata:   transactionMetadata(ctx, map[string]string{MetadataInspectionID: inspection.ID}),
+				CreatedAt:  time.Now(),
+				CreatedBy:  m.getUserFromContext(ctx),
+			}
+			if err := txStorage.CreateTransaction(ctx, tx); err != nil {
+				return NewStorageError("create_transaction", "トランザクション記録に失敗しました", err)
+			}
+			event := txManager.newStockChangedEvent(ctx, stock, oldQuantity, item.UnitCost, "inspection_passed", inspection.Reference, tx.ID)
+			if err := events.PublishStockChanged(ctx, event); err != nil {
+				m.log(ctx).Error("イベント発行に失敗しました", zap.Error(err))
+			}
+
+			now := time.Now()
+			update := *inspection
+			update.Status = InspectionStatusPassed
+			update.TransactionID = tx.ID
+			update.InspectedAt = &now
+			update.InspectedBy = m.getUserFromContext(ctx)
+			update.InspectionNote = note
+			return txManager.updateInspection(ctx, &update, InspectionStatusQuarantined)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if m.publisher != nil {
+		events.flush(ctx, m.publisher, m.logger)
+	}
+	m.checkOverStock(ctx, location, stock)
+	m.evaluateAlertRules(ctx, stock)
+	m.allocateArrivedStock(ctx, inspection.ItemID, inspection.LocationID)
+
+	passed, err := m.GetInspection(ctx, inspection.ID)
+	if err != nil {
+		return nil, err
+	}
+	m.log(ctx).Info("入荷検品の合格",
+		zap.String("inspection_id", passed.ID),
+		zap.String("item_id", passed.ItemID),
+		zap.String("location_id", passed.LocationID),
+		zap.Int64("quantity", passed.Quantity),
+		zap.String("inspected_by", passed.InspectedBy),
+	)
+	m.publishEvent(ctx, EventTypeInspectionPassed, passed.ItemID, passed.LocationID, *passed)
+	return passed, nil
+}
+
+// FailInspection writes off quarantined stock that failed QC inspection
+// 品質検査に不合格の検品待ちの入荷を理由コードを記録して廃棄
+//
+// 検品待ちの数量は在庫に含まれないため、在庫は変更しません。理由コードが other の場合はメモが必要です。
+func (m *Manager) FailInspection(ctx context.Context, inspectionID string, reason AdjustmentReason, note string) (_ *Inspection, err error) {
+	ctx, finish := m.startOperation(ctx, "fail_inspection", attrInspectionID.String(inspectionID))
+	defer finish(&err)
+
+	if err := validateInspectionDisposition(inspectionID, note); err != nil {
+		return nil, err
+	}
+	if err := ValidateAdjustmentReason(reason); err != nil {
+		return nil, err
+	}
+	if reason == AdjustmentReasonOther && strings.TrimSpace(note) == "" {
+		return nil, NewValidationError("note", "理由コードが other の場合はメモが必要です", "")
+	}
+	inspection, err := m.GetInspection(ctx, inspectionID)
+	if err != nil {
+		return nil, err
+	}
+	if inspection.Status != InspectionStatusQuarantined {
+		return nil, ErrInspectionNotQuarantined
+	}
+
+	now := time.Now()
+	update := *inspection
+	update.Status = InspectionStatusFailed
+	update.Reason = reason
+	update.InspectedAt = &now
+	update.InspectedBy = m.getUserFromContext(ctx)
+	update.InspectionNote = note
+	if err := m.updateInspection(ctx, &update, InspectionStatusQuarantined); err != nil {
+		return nil, err
+	}
+
+	failed, err := m.GetInspection(ctx, inspection.ID)
+	if err != nil {
+		return nil, err
+	}
+	m.log(ctx).Info("入荷検品の不合格",
+		zap.String("inspection_id", failed.ID),
+		zap.String("item_id", failed.ItemID),
+		zap.String("location_id", failed.LocationID),
+		zap.Int64("quantity", failed.Quantity),
+		zap.String("reason_code", string(failed.Reason)),
+		zap.String("inspected_by", failed.InspectedBy),
+	)
+	m.publishEvent(ctx, EventTypeInspectionFailed, failed.ItemID, failed.LocationID, *failed)
+	return failed, nil
+}
+
+// updateInspection updates a receiving inspection expected to be in status from
+// 状態がfromの入荷検品を更新
+func (m *Manager) updateInspection(ctx context.Context, inspection *Inspection, from InspectionStatus) error {
+	if err := m.storage.UpdateInspection(ctx, inspection, from); err != nil {
+		if errors.Is(err, ErrInspectionNotFound) || errors.Is(err, ErrInspectionNotQuarantined) {
+			return err
+		}
+		return NewStorageError("update_inspection", "入荷検品の更新に失敗しました", err)
+	}
+	return nil
+}
+
+// validateInspectionDisposition validates the ID and note of a QC pass or fail
+// 合否を判定する入荷検品のIDとメモをバリデーション
+func validateInspectionDisposition(inspectionID, note string) error {
+	if inspectionID == "" {
+		return NewValidationError("inspection_id", "検品IDが指定されていません", "")
+	}
+	return ValidateAlertNote(note)
+}
@@ -18,14 +18,21 @@ type InventoryManager interface {
 	GetStock(ctx context.Context, itemID, locationID string) (*Stock, error)
 	GetTotalStock(ctx context.Context, itemID string) (int64, error)
 	GetStockByLocation(ctx context.Context, locationID string) ([]Stock, error)
+	ForEachStockByLocation(ctx context.Context, locationID string, fn func(stock Stock) error) error
 
 	// 履歴管理 - History management
+	GetTransaction(ctx context.Context, txID string) (*Transaction, error)
 	GetHistory(ctx context.Context, itemID string, limit int) ([]Transaction, error)
+	GetHistoryPage(ctx context.Context, itemID string, after *HistoryCursor, limit int) (*TransactionPage, error)
 	GetHistoryByLocation(ctx context.Context, locationID string, limit int) ([]Transaction, error)
 	GetHistoryByDateRange(ctx context.Context, itemID string, from, to time.Time) ([]Transaction, error)
+	GetHistoryByReference(ctx context.Context, reference string) ([]Transaction, error)
+	SearchHistoryByMetadata(ctx context.Context, key, value string, limit int) ([]Transaction, error)
+	ArchiveTransactions(ctx context.Context, before time.Time) (int64, error)
 
 	// バッチ処理 - Batch operations
 	ExecuteBatch(ctx context.Context, operations []InventoryOperation) (*BatchOperation, error)
+	ExecuteBatchAtomic(ctx context.Context, operations []InventoryOperation) (*BatchOperation, error)
 	GetBatchStatus(ctx context.Context, batchID string) (*BatchOperation, error)
 
 	// 予約管理 - Reservation management
@@ -63,11 +70,264 @@ type LocationManager interface {
 type LotManager interface {
 	CreateLot(ctx context.Context, lot *Lot) error
 	GetLot(ctx context.Context, lotID string) (*Lot, error)
+	UpdateLot(ctx context.Context, lot *Lot) error
+	DeleteLot(ctx context.Context, lotID string) error
+	AdjustLotQuantity(ctx context.Context, lotID string, delta int64, reference string) (*Lot, error)
 	GetLotsByItem(ctx context.Context, itemID string) ([]Lot, error)
 	GetExpiringLots(ctx context.Context, within time.Duration) ([]Lot, error)
+	NotifyExpiringLots(ctx context.Context, within time.Duration) (int, error)
 	GetExpiredLots(ctx context.Context) ([]Lot, error)
 }
 
+// EventReplayer replays the transaction ledger as events
+// トランザクション台帳をイベントとして再生するインターフェース
+type EventReplayer interface {
+	ReplayEvents(ctx context.Context, from, to time.Time) (*ReplayResult, error)
+}
+
+// ConsistencyChecker compares stock records with the transaction ledger
+// 在庫記録とトランザクション台帳の整合性をチェックするインターフェース
+type ConsistencyChecker interface {
+	CheckStockConsistency(ctx context.Context, repair bool) (*ConsistencyReport, error)
+}
+
+// StockBatchReader retrieves many stock records in one call
+// 複数の在庫記録を一度に取得するインターフェース
+type StockBatchReader interface {
+	GetStocks(ctx context.Context, keys []StockKey) ([]Stock, error)
+}
+
+// Importer imports items and opening stock balances read from files
+// ファイルから読み取った商品と期首残高を取り込むインターフェース
+type Importer interface {
+	ImportItems(ctx context.Context, rows []ItemImportRow, dryRun bool) (*ImportResult, error)
+	ImportOpeningBalances(ctx context.Context, rows []OpeningBalanceRow, dryRun bool) (*ImportResult, error)
+}
+
+// DryRunner validates inventory operations without applying them
+// 在庫操作を適用せずに検証するインターフェース
+type DryRunner interface {
+	DryRunOperation(ctx context.Context, op InventoryOperation) (*DryRunResult, error)
+	DryRunBatch(ctx context.Context, operations []InventoryOperation, atomic bool) (*DryRunResult, error)
+}
+
+// ReservationManager manages reservation records by ID
+// 予約をIDで管理するインターフェース
+type ReservationManager interface {
+	CreateReservation(ctx context.Context, reservation *Reservation) error
+	GetReservation(ctx context.Context, reservationID string) (*Reservation, error)
+	ListReservations(ctx context.Context, filter ReservationFilter) ([]Reservation, error)
+	ReleaseReservationByID(ctx context.Context, reservationID string) (*Reservation, error)
+	GetReservationsByReference(ctx context.Context, reference string) ([]Reservation, error)
+	ReleaseReservationsByReference(ctx context.Context, reference string) ([]Reservation, error)
+	FulfillReservation(ctx context.Context, reservationID, reference string) (*Transaction, error)
+	ExpireReservations(ctx context.Context) (int, error)
+}
+
+// ChannelManager earmarks stock for sales channels and reports availability per channel
+// 在庫を販売チャネル向けに確保し、チャネルごとの利用可能数を照会するインターフェース
+type ChannelManager interface {
+	SetChannelAllocation(ctx context.Context, allocation *ChannelAllocation) error
+	ListChannelAllocations(ctx context.Context, itemID, locationID string) ([]ChannelAllocation, error)
+	GetChannelAvailability(ctx context.Context, itemID, locationID string) (*StockChannels, error)
+}
+
+// BackorderManager manages backorders of stock that was not available
+// 在庫不足のため受け付けたバックオーダーを管理するインターフェース
+type BackorderManager interface {
+	GetBackorder(ctx context.Context, backorderID string) (*Backorder, error)
+	ListBackorders(ctx context.Context, filter BackorderFilter) ([]Backorder, error)
+	CancelBackorder(ctx context.Context, backorderID string) (*Backorder, error)
+	AllocateBackorders(ctx context.Context, itemID, locationID string) ([]Backorder, error)
+}
+
+// ReorderPointManager manages the reorder points of item-location pairs
+// 商品・ロケーションごとの発注点を管理するインターフェース
+type ReorderPointManager interface {
+	SetReorderPoint(ctx context.Context, reorderPoint *ReorderPoint) error
+	GetReorderPoint(ctx context.Context, itemID, locationID string) (*ReorderPoint, error)
+	DeleteReorderPoint(ctx context.Context, itemID, locationID string) error
+	ListReorderPoints(ctx context.Context, filter ReorderPointFilter) ([]ReorderPoint, error)
+}
+
+// AlertRuleManager manages configurable alert rules
+// 設定可能なアラートルールを管理するインターフェース
+type AlertRuleManager interface {
+	CreateAlertRule(ctx context.Context, rule *AlertRule) error
+	GetAlertRule(ctx context.Context, ruleID string) (*AlertRule, error)
+	UpdateAlertRule(ctx context.Context, rule *AlertRule) error
+	DeleteAlertRule(ctx context.Context, ruleID string) error
+	ListAlertRules(ctx context.Context, filter AlertRuleFilter) ([]AlertRule, error)
+	EvaluateAlertRules(ctx context.Context) (int, error)
+}
+
+// AlertWorkflowManager manages the lifecycle of alerts: listing, acknowledgement and resolution with notes
+// アラートの一覧取得（全ロケーション）・確認・解決（メモ付き）を管理するインターフェース
+type AlertWorkflowManager interface {
+	GetAlert(ctx context.Context, alertID string) (*StockAlert, error)
+	ListAlerts(ctx context.Context, filter AlertFilter) ([]StockAlert, error)
+	AcknowledgeAlert(ctx context.Context, alertID, note string) (*StockAlert, error)
+	ResolveAlertWithNote(ctx context.Context, alertID, note string) (*StockAlert, error)
+}
+
+// SnapshotManager takes and reads point-in-time copies of the stock records
+// 在庫記録のある時点の複製（スナップショット）の取得・照会を行うインターフェース
+type SnapshotManager interface {
+	TakeStockSnapshot(ctx context.Context) (*StockSnapshot, error)
+	ListStockSnapshots(ctx context.Context, offset, limit int) ([]StockSnapshot, error)
+	GetStockSnapshotLines(ctx context.Context, snapshotID, locationID string) ([]StockSnapshotLine, error)
+}
+
+// StocktakeManager runs cycle counts: count sheets, variances and their approval
+// 棚卸（集計表の作成・実数の記録・差異の確定・承認した差異の調整）を管理するインターフェース
+type StocktakeManager interface {
+	CreateStocktake(ctx context.Context, stocktake *Stocktake) error
+	GetStocktake(ctx context.Context, stocktakeID string) (*Stocktake, error)
+	ListStocktakes(ctx context.Context, filter StocktakeFilter) ([]Stocktake, error)
+	RecordStocktakeCounts(ctx context.Context, stocktakeID string, counts []StocktakeCount) (*Stocktake, error)
+	SubmitStocktake(ctx context.Context, stocktakeID string) (*Stocktake, error)
+	ApplyStocktake(ctx context.Context, stocktakeID string, itemIDs []string) (*Stocktake, error)
+	CancelStocktake(ctx context.Context, stocktakeID string) (*Stocktake, error)
+}
+
+// AdjustmentManager records reason-coded stock adjustments and routes large ones through approval
+// 理由コード付きの在庫調整を記録し、調整額の大きい調整を承認に回すインターフェース
+type AdjustmentManager interface {
+	RequestAdjustment(ctx context.Context, adjustment *Adjustment) error
+	GetAdjustment(ctx context.Context, adjustmentID string) (*Adjustment, error)
+	ListAdjustments(ctx context.Context, filter AdjustmentFilter) ([]Adjustment, error)
+	ApproveAdjustment(ctx context.Context, adjustmentID, note string) (*Adjustment, error)
+	RejectAdjustment(ctx context.Context, adjustmentID, note string) (*Adjustment, error)
+}
+
+// Allocator sources a quantity of an item across multiple locations and reserves it
+// 商品の数量を複数のロケーションから確保して予約するインターフェース
+type Allocator interface {
+	PlanAllocation(ctx context.Context, request AllocationRequest) (*Allocation, error)
+	Allocate(ctx context.Context, request AllocationRequest) (*Allocation, error)
+}
+
+// LotStockManager answers how much of a lot is held at each location
+// ロットのロケーションごとの在庫数量を照会するインターフェース
+type LotStockManager interface {
+	GetLotStock(ctx context.Context, lotID, locationID string) (*LotStock, error)
+	ListLotStocks(ctx context.Context, filter LotStockFilter) ([]LotStock, error)
+}
+
+// ConsignmentManager reports the ownership of stock: owned stock and supplier-owned consignment stock
+// 在庫の所有（自社所有・仕入先所有の委託在庫）を照会するインターフェース
+type ConsignmentManager interface {
+	GetStockOwnership(ctx context.Context, itemID, locationID string) (*StockOwnership, error)
+	ListConsignmentStocks(ctx context.Context, filter ConsignmentStockFilter) ([]ConsignmentStock, error)
+}
+
+// UnitManager manages units of measure and converts quantities between an item's units
+// 単位を管理し、商品の単位間で数量を換算するインターフェース
+type UnitManager interface {
+	CreateUnit(ctx context.Context, unit *UnitOfMeasure) error
+	GetUnit(ctx context.Context, code string) (*UnitOfMeasure, error)
+	ListUnits(ctx context.Context) ([]UnitOfMeasure, error)
+	DeleteUnit(ctx context.Context, code string) error
+	SetUnitConversion(ctx context.Context, conversion *UnitConversion) error
+	ListUnitConversions(ctx context.Context, itemID string) ([]UnitConversion, error)
+	DeleteUnitConversion(ctx context.Context, itemID, uom string) error
+	ParseQuantity(ctx context.Context, itemID, uom, value string) (int64, error)
+	ConvertQuantity(ctx context.Context, itemID, uom string, quantity int64) (int64, error)
+}
+
+// KitManager manages bills of materials and assembles or disassembles kits
+// 部品表を管理し、キットを組立・分解するインターフェース
+type KitManager interface {
+	SetBillOfMaterials(ctx context.Context, bom *BillOfMaterials) error
+	GetBillOfMaterials(ctx context.Context, kitItemID string) (*BillOfMaterials, error)
+	ListBillsOfMaterials(ctx context.Context, offset, limit int) ([]BillOfMaterials, error)
+	DeleteBillOfMaterials(ctx context.Context, kitItemID string) error
+	Assemble(ctx context.Context, kitItemID, locationID string, quantity int64, reference string) (*Assembly, error)
+	Disassemble(ctx context.Context, kitItemID, locationID string, quantity int64, reference string) (*Assembly, error)
+	GetAssembly(ctx context.Context, assemblyID string) (*Assembly, error)
+	ListAssemblies(ctx context.Context, filter AssemblyFilter) ([]Assembly, error)
+}
+
+// SupplierManager manages suppliers and the purchasing terms of the items they supply
+// 仕入先と、仕入先ごとの商品の仕入条件を管理するインターフェース
+type SupplierManager interface {
+	CreateSupplier(ctx context.Context, supplier *Supplier) error
+	GetSupplier(ctx context.Context, supplierID string) (*Supplier, error)
+	UpdateSupplier(ctx context.Context, supplier *Supplier) error
+	DeleteSupplier(ctx context.Context, supplierID string) error
+	ListSuppliers(ctx context.Context, offset, limit int) ([]Supplier, error)
+	SetSupplierItem(ctx context.Context, supplierItem *SupplierItem) error
+	GetSupplierItem(ctx context.Context, supplierID, itemID string) (*SupplierItem, error)
+	DeleteSupplierItem(ctx context.Context, supplierID, itemID string) error
+	ListSupplierItems(ctx context.Context, filter SupplierItemFilter) ([]SupplierItem, error)
+}
+
+// PurchaseOrderManager manages purchase orders and receives stock against them
+// 発注（作成・承認・送付・取消）を管理し、発注に対する入荷を記録するインターフェース
+type PurchaseOrderManager interface {
+	CreatePurchaseOrder(ctx context.Context, order *PurchaseOrder) error
+	GetPurchaseOrder(ctx context.Context, orderID string) (*PurchaseOrder, error)
+	ListPurchaseOrders(ctx context.Context, filter PurchaseOrderFilter) ([]PurchaseOrder, error)
+	ApprovePurchaseOrder(ctx context.Context, orderID string) (*PurchaseOrder, error)
+	SendPurchaseOrder(ctx context.Context, orderID string) (*PurchaseOrder, error)
+	CancelPurchaseOrder(ctx context.Context, orderID string) (*PurchaseOrder, error)
+	ReceivePurchaseOrder(ctx context.Context, orderID string, receipt *GoodsReceipt) (*GoodsReceipt, error)
+	ListGoodsReceipts(ctx context.Context, orderID string) ([]GoodsReceipt, error)
+}
+
+// SalesOrderManager allocates stock to sales orders and confirms their shipment
+// 受注に在庫を引き当て（予約）、出荷を確定するインターフェース
+type SalesOrderManager interface {
+	CreateSalesOrder(ctx context.Context, order *SalesOrder) error
+	GetSalesOrder(ctx context.Context, orderID string) (*SalesOrder, error)
+	ListSalesOrders(ctx context.Context, filter SalesOrderFilter) ([]SalesOrder, error)
+	AllocateSalesOrder(ctx context.Context, orderID string, allowPartial bool) (*SalesOrder, error)
+	ShipSalesOrder(ctx context.Context, orderID string, lines []ShipmentLine) (*SalesOrder, error)
+	CancelSalesOrder(ctx context.Context, orderID string) (*SalesOrder, error)
+	GetSalesOrderStockStatus(ctx context.Context, orderID string) (*SalesOrderStockStatus, error)
+}
+
+// ReturnManager manages returns (RMA): receiving returned units for inspection and dispositioning them
+// 返品（RMA）を受け付け、返送された商品を検品待ちとして受け入れて再入庫・再整備・廃棄に処分するインターフェース
+type ReturnManager interface {
+	CreateReturn(ctx context.Context, rma *ReturnAuthorization) error
+	GetReturn(ctx context.Context, returnID string) (*ReturnAuthorization, error)
+	ListReturns(ctx context.Context, filter ReturnFilter) ([]ReturnAuthorization, error)
+	ReceiveReturn(ctx context.Context, returnID string, lines []ReturnReceiptLine) (*ReturnAuthorization, error)
+	DispositionReturn(ctx context.Context, returnID string, disposition *ReturnDisposition) (*ReturnDisposition, error)
+	CancelReturn(ctx context.Context, returnID string) (*ReturnAuthorization, error)
+	ListReturnDispositions(ctx context.Context, returnID string) ([]ReturnDisposition, error)
+}
+
+// InspectionManager holds received stock in quarantine until it passes or fails QC inspection
+// 入荷した在庫を品質検査（QC）の合否が決まるまで隔離するインターフェース
+type InspectionManager interface {
+	ReceiveForInspection(ctx context.Context, inspection *Inspection) error
+	GetInspection(ctx context.Context, inspectionID string) (*Inspection, error)
+	ListInspections(ctx context.Context, filter InspectionFilter) ([]Inspection, error)
+	PassInspection(ctx context.Context, inspectionID, note string) (*Inspection, error)
+	FailInspection(ctx context.Context, inspectionID string, reason AdjustmentReason, note string) (*Inspection, error)
+}
+
+// TransactionReverser reverses recorded stock movements with compensating transactions
+// 記録済みの在庫移動を打ち消すトランザクションで取り消すインターフェース
+type TransactionReverser interface {
+	ReverseTransaction(ctx context.Context, txID, reason string) (*Transaction, error)
+}
+
+// SummaryReader aggregates the whole inventory for dashboards
+// ダッシュボード向けに在庫全体を集計するインターフェース
+type SummaryReader interface {
+	GetSummary(ctx context.Context) (*InventorySummary, error)
+}
+
+// ListPager lists items and locations using keyset pagination
+// 商品・ロケーション一覧をキーセットページネーションで取得するインターフェース
+type ListPager interface {
+	ListItemsPage(ctx context.Context, filter ItemFilter, after *PageCursor, limit int) (*ItemPage, error)
+	ListLocationsPage(ctx context.Context, after *PageCursor, limit int) (*LocationPage, error)
+}
+
 // ValuationEngine defines interface for inventory valuation
 // 在庫評価エンジンのインターフェースを定義
 type ValuationEngine interface {
@@ -114,32 +374,81 @@ const (
 // このインターフェースは在庫管理システムのデータ永続化を抽象化し、
 // PostgreSQL、MySQL、その他のデータベースシステムに対応できる設計となっています。
 // 全てのメソッドはコンテキストを受け取り、適切なタイムアウトとキャンセレーション処理を行います。
+//
+// 別のバックエンドを実装する場合、マネージャーが判定に使用する以下のエラーを返す必要があります:
+//   - 存在しない在庫・商品・ロケーション・ロット: ErrStockNotFound / ErrItemNotFound / ErrLocationNotFound / ErrLotNotFound
+//   - 存在しないトランザクション記録: ErrTransactionNotFound
+//   - 存在しないバッチ操作: ErrBatchNotFound
+//   - 存在しない予約: ErrReservationNotFound、有効でない予約の更新: ErrReservationNotActive
+//   - 存在しないバックオーダー: ErrBackorderNotFound、入荷待ちでないバックオーダーの更新: ErrBackorderNotPending
+//   - 設定されていない発注点: ErrReorderPointNotFound
+//   - 存在しない棚卸: ErrStocktakeNotFound、想定した状態でない棚卸の更新: ErrStocktakeStatus
+//   - 存在しないアラートルール: ErrAlertRuleNotFound、ルールで作成したアラートがない場合の GetLatestRuleAlert: ErrAlertNotFound
+//   - 重複する商品・ロケーション: ErrDuplicateItem / ErrDuplicateLocation
+//   - UpdateStockでのバージョン不一致: ErrVersionMismatch
+//
+// PostgreSQLStorageとMemoryStorageはこの契約を満たす参照実装です。
 type Storage interface {
 	// Transaction management - トランザクション管理
-	// データベーストランザクションを開始し、ACID特性を保証します
-	Begin(ctx context.Context) (Transaction, error)
+	// 単一のデータベーストランザクション内でfnを実行し、ACID特性を保証します
+	// fnに渡されるtxStorageへの操作はすべて同一トランザクションで実行され、
+	// fnがエラーを返した場合はロールバック、nilを返した場合はコミットされます
+	WithinTx(ctx context.Context, fn func(txStorage Storage) error) error
 	
 	// Stock operations - 在庫操作
 	// 新しい在庫記録を作成します。既存の記録がある場合はエラーを返します
 	CreateStock(ctx context.Context, stock *Stock) error
 	// 既存の在庫記録を更新します。楽観的ロックによる同時実行制御を行います
 	UpdateStock(ctx context.Context, stock *Stock) error
+	// 複数の在庫記録を一括で作成または更新します（期首残高の一括投入など）
+	// 既存の記録は数量・予約数が上書きされ、バージョンが1つ進みます
+	CreateOrUpdateStocks(ctx context.Context, stocks []Stock) error
 	// 指定された商品とロケーションの在庫情報を取得します
 	GetStock(ctx context.Context, itemID, locationID string) (*Stock, error)
+	// 指定された商品とロケーションの組の在庫情報を一度に取得します（順序は不定）
+	// 在庫記録が存在しない組は結果に含めません
+	GetStocks(ctx context.Context, keys []StockKey) ([]Stock, error)
+	// 在庫情報を行ロック付きで取得します（SELECT ... FOR UPDATE）
+	// WithinTxのスコープ内で呼び出す必要があり、ロックはトランザクション終了まで保持されます
+	GetStockForUpdate(ctx context.Context, itemID, locationID string) (*Stock, error)
 	// 指定されたロケーションの全ての在庫情報を取得します
 	ListStockByLocation(ctx context.Context, locationID string) ([]Stock, error)
+	// 指定された商品の全ロケーションの在庫情報を取得します（ロケーションID順）
+	ListStockByItem(ctx context.Context, itemID string) ([]Stock, error)
+	// 指定されたロケーションの在庫情報を1件ずつfnに渡します（商品ID順）
+	// 全件をメモリに展開しないため、大量の在庫を持つロケーションの走査に使用します
+	// fnがエラーを返した場合は走査を中断し、そのエラーを返します
+	ForEachStockByLocation(ctx context.Context, locationID string, fn func(stock Stock) error) error
 	// 指定された商品の全ロケーションでの合計在庫数を取得します
 	GetTotalStockByItem(ctx context.Context, itemID string) (int64, error)
+	// 指定されたロケーションの全商品の合計在庫数を取得します（容量の確認に使用）
+	GetTotalStockByLocation(ctx context.Context, locationID string) (int64, error)
 	
 	// Transaction history - トランザクション履歴
 	// 新しいトランザクション記録を作成します（監査証跡として使用）
 	CreateTransaction(ctx context.Context, tx *Transaction) error
+	// 指定されたIDのトランザクション記録を取得します。存在しない場合はErrTransactionNotFoundを返します
+	GetTransactionByID(ctx context.Context, txID string) (*Transaction, error)
 	// 指定された商品のトランザクション履歴を取得します（最新順）
 	GetTransactionHistory(ctx context.Context, itemID string, limit int) ([]Transaction, error)
+	// 指定された商品のトランザクション履歴のうちカーソル位置より古いものを新しい順に取得します（キーセットページネーション）
+	// afterがnilの場合は最新のレコードから取得します
+	GetTransactionHistoryAfter(ctx context.Context, itemID string, after *HistoryCursor, limit int) ([]Transaction, error)
 	// 指定されたロケーションのトランザクション履歴を取得します（最新順）
 	GetTransactionHistoryByLocation(ctx context.Context, locationID string, limit int) ([]Transaction, error)
 	// 指定された商品の指定日付範囲のトランザクション履歴を取得します
 	GetTransactionHistoryByDateRange(ctx context.Context, itemID string, from, to time.Time) ([]Transaction, error)
+	// from以上to未満に作成された全商品のトランザクションを古い順（作成日時、ID順）に1件ずつfnに渡します
+	// fromがゼロ値の場合は最初から、toがゼロ値の場合は最後まで走査します
+	// fnがエラーを返した場合は走査を中断し、そのエラーを返します
+	ForEachTransactionInRange(ctx context.Context, from, to time.Time, fn func(tx Transaction) error) error
+	// 指定された参照番号（発注書番号など）を持つ全てのトランザクションを新しい順に取得します
+	GetTransactionsByReference(ctx context.Context, reference string) ([]Transaction, error)
+	// メタデータのkeyがvalueと一致するトランザクションを新しい順に取得します（例: order_channel=web）
+	SearchTransactionsByMetadata(ctx context.Context, key, value string, limit int) ([]Transaction, error)
+	// before より前に作成されたトランザクションをアーカイブへ移動し、移動した件数を返します
+	// 移動したトランザクションは履歴系のメソッドからは参照できなくなります
+	ArchiveTransactions(ctx context.Context, before time.Time) (int64, error)
 	
 	// Item management - 商品管理
 	// 新しい商品を作成します。重複するIDの場合はエラーを返します
@@ -148,28 +457,297 @@ type Storage interface {
 	GetItem(ctx context.Context, itemID string) (*Item, error)
 	// 既存の商品情報を更新します
 	UpdateItem(ctx context.Context, item *Item) error
+	// 指定されたIDの商品を削除します（キットの部品表・仕入先の仕入条件も削除）
+	// 部品表の構成品として使用中の場合はErrItemInUseを返します
+	DeleteItem(ctx context.Context, itemID string) error
+	// ページネーション付きで商品一覧を取得します
+	ListItems(ctx context.Context, offset, limit int) ([]Item, error)
+	// 条件に一致する商品をカーソル位置より後から指定された並び順で取得します（キーセットページネーション）
+	// 並び替えの項目が同じ商品はIDで並べます。afterがnilの場合は先頭から取得します
+	ListItemsAfter(ctx context.Context, filter ItemFilter, after *PageCursor, limit int) ([]Item, error)
+	// 名前・SKU・説明・カテゴリで商品を検索します
+	SearchItems(ctx context.Context, query string) ([]Item, error)
 	
 	// Location management - ロケーション管理
 	// 新しいロケーションを作成します
 	CreateLocation(ctx context.Context, location *Location) error
 	// 指定されたIDのロケーション情報を取得します
 	GetLocation(ctx context.Context, locationID string) (*Location, error)
+	// 既存のロケーション情報を更新します
+	UpdateLocation(ctx context.Context, location *Location) error
+	// 指定されたIDのロケーションを削除します
+	DeleteLocation(ctx context.Context, locationID string) error
+	// ページネーション付きでロケーション一覧を取得します
+	ListLocations(ctx context.Context, offset, limit int) ([]Location, error)
+	// カーソル位置より古いロケーションを作成日時・IDの降順で取得します（キーセットページネーション）
+	// afterがnilの場合は最新のロケーションから取得します
+	ListLocationsAfter(ctx context.Context, after *PageCursor, limit int) ([]Location, error)
+	
+	// Unit of measure management - 単位管理
+	// 新しい単位を登録します。既に登録されている場合はErrDuplicateUnitを返します
+	CreateUnit(ctx context.Context, unit *UnitOfMeasure) error
+	// 指定されたコードの単位を取得します。登録されていない場合はErrUnitNotFoundを返します
+	GetUnit(ctx context.Context, code string) (*UnitOfMeasure, error)
+	// 全ての単位をコード順に取得します
+	ListUnits(ctx context.Context) ([]UnitOfMeasure, error)
+	// 指定されたコードの単位を削除します。商品の基本単位や換算で使用中の場合はErrUnitInUseを返します
+	DeleteUnit(ctx context.Context, code string) error
+	// 商品の単位の換算係数を登録します（登録済みの場合は更新）
+	SetUnitConversion(ctx context.Context, conversion *UnitConversion) error
+	// 商品の単位の換算を取得します。登録されていない場合はErrUnitConversionNotFoundを返します
+	GetUnitConversion(ctx context.Context, itemID, uom string) (*UnitConversion, error)
+	// 商品の全ての単位の換算を単位コード順に取得します
+	ListUnitConversions(ctx context.Context, itemID string) ([]UnitConversion, error)
+	// 商品の単位の換算を削除します。登録されていない場合はErrUnitConversionNotFoundを返します
+	DeleteUnitConversion(ctx context.Context, itemID, uom string) error
+	
+	// Bill of materials - 部品表
+	// キットの部品表を登録します（登録済みの場合は構成品を置換）
+	// キットまたは構成品の商品が存在しない場合はErrItemNotFoundを返します
+	SetBillOfMaterials(ctx context.Context, bom *BillOfMaterials) error
+	// キットの部品表を構成品の商品ID順に取得します。登録されていない場合はErrBOMNotFoundを返します
+	GetBillOfMaterials(ctx context.Context, kitItemID string) (*BillOfMaterials, error)
+	// ページネーション付きで部品表一覧をキットの商品ID順に取得します
+	ListBillsOfMaterials(ctx context.Context, offset, limit int) ([]BillOfMaterials, error)
+	// キットの部品表を削除します。登録されていない場合はErrBOMNotFoundを返します
+	DeleteBillOfMaterials(ctx context.Context, kitItemID string) error
+	// キットの組立・分解の記録を作成します
+	CreateAssembly(ctx context.Context, assembly *Assembly) error
+	// 指定されたIDの組立・分解の記録を取得します（トランザクションは含まない）
+	// 存在しない場合はErrAssemblyNotFoundを返します
+	GetAssembly(ctx context.Context, assemblyID string) (*Assembly, error)
+	// 条件に一致する組立・分解の記録を作成日時の新しい順に取得します
+	ListAssemblies(ctx context.Context, filter AssemblyFilter) ([]Assembly, error)
+	
+	// Supplier management - 仕入先管理
+	// 新しい仕入先を作成します。重複するIDの場合はErrDuplicateSupplierを返します
+	CreateSupplier(ctx context.Context, supplier *Supplier) error
+	// 指定されたIDの仕入先を取得します。存在しない場合はErrSupplierNotFoundを返します
+	GetSupplier(ctx context.Context, supplierID string) (*Supplier, error)
+	// 既存の仕入先情報を更新します
+	UpdateSupplier(ctx context.Context, supplier *Supplier) error
+	// 指定されたIDの仕入先を仕入条件とともに削除します
+	DeleteSupplier(ctx context.Context, supplierID string) error
+	// ページネーション付きで仕入先一覧をID順に取得します
+	ListSuppliers(ctx context.Context, offset, limit int) ([]Supplier, error)
+	// 仕入先の商品の仕入条件を登録します（登録済みの場合は更新）
+	// 優先仕入先に指定した場合は同じ商品の他の仕入先の優先指定を解除します
+	// 仕入先または商品が存在しない場合はErrSupplierNotFound・ErrItemNotFoundを返します
+	SetSupplierItem(ctx context.Context, supplierItem *SupplierItem) error
+	// 仕入先の商品の仕入条件を取得します。登録されていない場合はErrSupplierItemNotFoundを返します
+	GetSupplierItem(ctx context.Context, supplierID, itemID string) (*SupplierItem, error)
+	// 仕入先の商品の仕入条件を削除します。登録されていない場合はErrSupplierItemNotFoundを返します
+	DeleteSupplierItem(ctx context.Context, supplierID, itemID string) error
+	// 条件に一致する仕入条件を仕入先ID・商品ID順に取得します
+	ListSupplierItems(ctx context.Context, filter SupplierItemFilter) ([]SupplierItem, error)
+	
+	// Purchase orders - 発注
+	// 発注と行（Lines）を作成します
+	CreatePurchaseOrder(ctx context.Context, order *PurchaseOrder) error
+	// 指定されたIDの発注を行（商品IDの昇順）とともに取得します。存在しない場合はErrPurchaseOrderNotFoundを返します
+	GetPurchaseOrder(ctx context.Context, orderID string) (*PurchaseOrder, error)
+	// 条件に一致する発注を作成日時の新しい順に取得します（行は含みません）
+	ListPurchaseOrders(ctx context.Context, filter PurchaseOrderFilter) ([]PurchaseOrder, error)
+	// 状態がfromの発注の状態・承認/送付/終了の日時とユーザーを更新します（行は変更しません）
+	// 存在しない場合はErrPurchaseOrderNotFound、状態がfromでない場合（同時に更新された場合を含む）はErrPurchaseOrderStatusを返し、発注は変更しません
+	UpdatePurchaseOrder(ctx context.Context, order *PurchaseOrder, from PurchaseOrderStatus) error
+	// 発注の商品の行の入荷済み数量にquantityを加算し、更新後の行を返します
+	// 同じ発注への入荷が同時に記録されないよう、トランザクション終了まで発注をロックします
+	// 発注または行が存在しない場合はErrPurchaseOrderNotFound、発注数量を超える場合はErrReceiptExceedsOrderを返し、行は変更しません
+	ReceivePurchaseOrderLine(ctx context.Context, orderID, itemID string, quantity int64) (*PurchaseOrderLine, error)
+	// 発注に対する入荷を記録します
+	CreateGoodsReceipt(ctx context.Context, receipt *GoodsReceipt) error
+	// 発注に対する入荷を入荷日時の古い順に取得します
+	ListGoodsReceipts(ctx context.Context, orderID string) ([]GoodsReceipt, error)
+	
+	// Sales orders - 受注
+	// 受注と行（Lines）を作成します
+	CreateSalesOrder(ctx context.Context, order *SalesOrder) error
+	// 指定されたIDの受注を行（商品IDの昇順）とともに取得します。存在しない場合はErrSalesOrderNotFoundを返します
+	GetSalesOrder(ctx context.Context, orderID string) (*SalesOrder, error)
+	// 条件に一致する受注を受付日時の新しい順に取得します（行は含みません）
+	ListSalesOrders(ctx context.Context, filter SalesOrderFilter) ([]SalesOrder, error)
+	// 受注の状態・終了の日時とユーザー・行の引当/出荷/欠品数量を更新します（楽観的ロック: 保存済みのバージョンがVersion-1の場合のみ）
+	// 存在しない場合はErrSalesOrderNotFound、バージョンが一致しない場合はErrVersionMismatchを返し、受注は変更しません
+	UpdateSalesOrder(ctx context.Context, order *SalesOrder) error
+	
+	// Returns - 返品（RMA）
+	// 返品と行（Lines）を作成します
+	CreateReturn(ctx context.Context, rma *ReturnAuthorization) error
+	// 指定されたIDの返品を行（商品IDの昇順）とともに取得します。存在しない場合はErrReturnNotFoundを返します
+	GetReturn(ctx context.Context, returnID string) (*ReturnAuthorization, error)
+	// 条件に一致する返品を受付日時の新しい順に取得します（行は含みません）
+	ListReturns(ctx context.Context, filter ReturnFilter) ([]ReturnAuthorization, error)
+	// 返品の状態・終了の日時とユーザー・行の到着/処分数量を更新します（楽観的ロック: 保存済みのバージョンがVersion-1の場合のみ）
+	// 存在しない場合はErrReturnNotFound、バージョンが一致しない場合はErrVersionMismatchを返し、返品は変更しません
+	UpdateReturn(ctx context.Context, rma *ReturnAuthorization) error
+	// 返品の処分を記録します
+	CreateReturnDisposition(ctx context.Context, disposition *ReturnDisposition) error
+	// 返品の処分を処分日時の古い順に取得します
+	ListReturnDispositions(ctx context.Context, returnID string) ([]ReturnDisposition, error)
 	
 	// Lot management - ロット管理
 	// 新しいロット（バッチ）を作成します
 	CreateLot(ctx context.Context, lot *Lot) error
 	// 指定されたIDのロット情報を取得します
 	GetLot(ctx context.Context, lotID string) (*Lot, error)
+	// 既存のロット情報（ロット番号・数量・単価・有効期限）を更新します
+	UpdateLot(ctx context.Context, lot *Lot) error
+	// 指定されたIDのロットを削除します
+	DeleteLot(ctx context.Context, lotID string) error
+	// ロットの数量をdelta分だけ増減し、更新後のロットを返します
+	// 数量が負になる場合はErrInsufficientStockを返し、ロットは変更しません
+	AdjustLotQuantity(ctx context.Context, lotID string, delta int64) (*Lot, error)
 	// 指定された商品の全てのロット情報を取得します
 	GetLotsByItem(ctx context.Context, itemID string) ([]Lot, error)
+	// 指定期間内に期限切れになるロットを取得します
+	GetExpiringLots(ctx context.Context, within time.Duration) ([]Lot, error)
+	// 既に期限切れになったロットを取得します
+	GetExpiredLots(ctx context.Context) ([]Lot, error)
+	// 商品のロット番号のロットを取得します（同じ番号のロットが複数ある場合は最も古いロット）
+	// 存在しない場合はErrLotNotFoundを返します
+	GetLotByNumber(ctx context.Context, itemID, lotNumber string) (*Lot, error)
+	// ロットのロケーションの在庫数量をdelta分だけ増減し（記録がない場合は作成）、更新後のロット在庫を返します
+	// 数量が負になる場合はErrInsufficientLotStockを返し、ロット在庫は変更しません
+	AdjustLotStock(ctx context.Context, lot *Lot, locationID string, delta int64) (*LotStock, error)
+	// ロットのロケーションの在庫を取得します。記録がない場合はErrLotStockNotFoundを返します
+	GetLotStock(ctx context.Context, lotID, locationID string) (*LotStock, error)
+	// 条件に一致するロット在庫をロット番号・ロケーションIDの昇順で取得します
+	ListLotStocks(ctx context.Context, filter LotStockFilter) ([]LotStock, error)
+	// 所有者の商品のロケーションの委託在庫の数量をdelta分だけ増減し（記録がない場合は作成）、更新後の委託在庫を返します
+	// 数量が負になる場合はErrInsufficientConsignmentStockを返し、委託在庫は変更しません
+	AdjustConsignmentStock(ctx context.Context, ownerID, itemID, locationID string, delta int64) (*ConsignmentStock, error)
+	// 条件に一致する委託在庫を所有者ID・商品ID・ロケーションIDの昇順で取得します
+	ListConsignmentStocks(ctx context.Context, filter ConsignmentStockFilter) ([]ConsignmentStock, error)
+	
+	// Reservation management - 予約管理
+	// 新しい予約を作成します
+	CreateReservation(ctx context.Context, reservation *Reservation) error
+	// 指定されたIDの予約を取得します。存在しない場合はErrReservationNotFoundを返します
+	GetReservation(ctx context.Context, reservationID string) (*Reservation, error)
+	// 有効な予約の数量・状態・有効期限・解除日時を更新します
+	// 予約が有効でない場合（同時に解除された場合を含む）はErrReservationNotActiveを返し、予約は変更しません
+	UpdateReservation(ctx context.Context, reservation *Reservation) error
+	// 条件に一致する予約を作成日時の降順で取得します
+	ListReservations(ctx context.Context, filter ReservationFilter) ([]Reservation, error)
+	// 有効期限がbefore以前の有効な予約を有効期限の昇順で最大limit件取得します
+	GetExpiredReservations(ctx context.Context, before time.Time, limit int) ([]Reservation, error)
+	// 商品のロケーションの販売チャネルの確保数量を作成・更新します。数量が0の場合は確保を削除します
+	SetChannelAllocation(ctx context.Context, allocation *ChannelAllocation) error
+	// 商品のロケーションの販売チャネルの確保をチャネル名の昇順で取得します
+	ListChannelAllocations(ctx context.Context, itemID, locationID string) ([]ChannelAllocation, error)
+	
+	// Backorder management - バックオーダー管理
+	// 新しいバックオーダーを作成します
+	CreateBackorder(ctx context.Context, backorder *Backorder) error
+	// 指定されたIDのバックオーダーを取得します。存在しない場合はErrBackorderNotFoundを返します
+	GetBackorder(ctx context.Context, backorderID string) (*Backorder, error)
+	// 入荷待ちのバックオーダーの状態・予約ID・引当/取消日時を更新します
+	// 入荷待ちでない場合（同時に引当・取消された場合を含む）はErrBackorderNotPendingを返し、バックオーダーは変更しません
+	UpdateBackorder(ctx context.Context, backorder *Backorder) error
+	// 条件に一致するバックオーダーを作成日時の昇順（引当の順）で取得します
+	ListBackorders(ctx context.Context, filter BackorderFilter) ([]Backorder, error)
+	
+	// Reorder point management - 発注点管理
+	// 商品・ロケーションの発注点を作成または上書きします
+	SaveReorderPoint(ctx context.Context, reorderPoint *ReorderPoint) error
+	// 商品・ロケーションの発注点を取得します。設定されていない場合はErrReorderPointNotFoundを返します
+	GetReorderPoint(ctx context.Context, itemID, locationID string) (*ReorderPoint, error)
+	// 商品・ロケーションの発注点を削除します。設定されていない場合はErrReorderPointNotFoundを返します
+	DeleteReorderPoint(ctx context.Context, itemID, locationID string) error
+	// 条件に一致する発注点を商品ID・ロケーションIDの昇順で取得します
+	ListReorderPoints(ctx context.Context, filter ReorderPointFilter) ([]ReorderPoint, error)
 	
 	// Alert management - アラート管理
 	// 新しいアラートを作成します（低在庫、期限切れなど）
 	CreateAlert(ctx context.Context, alert *StockAlert) error
 	// 指定されたロケーションのアクティブなアラートを取得します
 	GetActiveAlerts(ctx context.Context, locationID string) ([]StockAlert, error)
-	// 指定されたアラートを解決済みとしてマークします
-	ResolveAlert(ctx context.Context, alertID string) error
+	// 指定されたアラートを取得します（解決済みを含む）
+	GetAlert(ctx context.Context, alertID string) (*StockAlert, error)
+	// 条件に一致するアラートを全ロケーションから作成日時の新しい順に取得します（解決済みを含む）
+	ListAlerts(ctx context.Context, filter AlertFilter) ([]StockAlert, error)
+	// アクティブなアラートを解決済みとしてマークし、解決したユーザー・メモを記録します
+	// 存在しない場合はErrAlertNotFound、解決済みの場合はErrAlertNotActiveを返します
+	ResolveAlert(ctx context.Context, alertID, resolvedBy, note string) error
+	// アクティブなアラートを確認済みとしてマークし、確認したユーザー・メモを記録します
+	// 存在しない場合はErrAlertNotFound、解決済みの場合はErrAlertNotActive、確認済みの場合はErrAlertAlreadyAcknowledgedを返します
+	AcknowledgeAlert(ctx context.Context, alertID, acknowledgedBy, note string) error
+	// アラートルールが在庫記録に対して最後に作成したアラートを取得します（解決済みを含む）
+	// 作成したアラートがない場合はErrAlertNotFoundを返します
+	GetLatestRuleAlert(ctx context.Context, ruleID, itemID, locationID string) (*StockAlert, error)
+	
+	// Alert rule management - アラートルール管理
+	// 新しいアラートルールを作成します
+	CreateAlertRule(ctx context.Context, rule *AlertRule) error
+	// 指定されたIDのアラートルールを取得します。存在しない場合はErrAlertRuleNotFoundを返します
+	GetAlertRule(ctx context.Context, ruleID string) (*AlertRule, error)
+	// アラートルールの条件・対象範囲・有効状態を更新します。存在しない場合はErrAlertRuleNotFoundを返します
+	UpdateAlertRule(ctx context.Context, rule *AlertRule) error
+	// アラートルールを削除します（作成済みのアラートは残ります）。存在しない場合はErrAlertRuleNotFoundを返します
+	DeleteAlertRule(ctx context.Context, ruleID string) error
+	// 条件に一致するアラートルールを作成日時の昇順で取得します
+	ListAlertRules(ctx context.Context, filter AlertRuleFilter) ([]AlertRule, error)
+	// 在庫記録を対象範囲に含む有効なアラートルールを取得します（在庫の変更時の評価に使用）
+	GetAlertRulesForStock(ctx context.Context, itemID, locationID string) ([]AlertRule, error)
+	
+	// Stock snapshots - 在庫スナップショット
+	// 全ての在庫記録を複製してスナップショットを作成し、在庫記録数・総在庫数量・総予約済み数量を設定します
+	CreateStockSnapshot(ctx context.Context, snapshot *StockSnapshot) error
+	// スナップショットを取得日時の新しい順に取得します
+	ListStockSnapshots(ctx context.Context, offset, limit int) ([]StockSnapshot, error)
+	// スナップショットの在庫記録を商品ID・ロケーションIDの昇順で取得します（locationIDが空の場合は全ロケーション）
+	// 存在しない場合はErrSnapshotNotFoundを返します
+	GetStockSnapshotLines(ctx context.Context, snapshotID, locationID string) ([]StockSnapshotLine, error)
+	
+	// Stocktakes - 棚卸
+	// 棚卸と行（Lines）を作成します
+	CreateStocktake(ctx context.Context, stocktake *Stocktake) error
+	// 指定されたIDの棚卸を行（商品IDの昇順）とともに取得します。存在しない場合はErrStocktakeNotFoundを返します
+	GetStocktake(ctx context.Context, stocktakeID string) (*Stocktake, error)
+	// 条件に一致する棚卸を作成日時の新しい順に取得します（行は含みません）
+	ListStocktakes(ctx context.Context, filter StocktakeFilter) ([]Stocktake, error)
+	// 状態がfromの棚卸の状態・確定/終了の日時とユーザーを更新し、Linesに含む行を作成または上書きします（含まない行は変更しません）
+	// 存在しない場合はErrStocktakeNotFound、状態がfromでない場合（同時に更新された場合を含む）はErrStocktakeStatusを返し、棚卸は変更しません
+	UpdateStocktake(ctx context.Context, stocktake *Stocktake, from StocktakeStatus) error
+	
+	// Stock adjustments - 在庫調整
+	// 在庫調整を作成します
+	CreateAdjustment(ctx context.Context, adjustment *Adjustment) error
+	// 指定されたIDの在庫調整を取得します。存在しない場合はErrAdjustmentNotFoundを返します
+	GetAdjustment(ctx context.Context, adjustmentID string) (*Adjustment, error)
+	// 条件に一致する在庫調整を申請日時の新しい順に取得します
+	ListAdjustments(ctx context.Context, filter AdjustmentFilter) ([]Adjustment, error)
+	// 状態がfromの在庫調整の状態・調整後の在庫数量・トランザクションID・承認/却下の日時とユーザー・メモを更新します
+	// 存在しない場合はErrAdjustmentNotFound、状態がfromでない場合（同時に更新された場合を含む）はErrAdjustmentNotPendingを返し、在庫調整は変更しません
+	UpdateAdjustment(ctx context.Context, adjustment *Adjustment, from AdjustmentStatus) error
+	
+	// Receiving inspections - 入荷検品
+	// 入荷検品を作成します
+	CreateInspection(ctx context.Context, inspection *Inspection) error
+	// 指定されたIDの入荷検品を取得します。存在しない場合はErrInspectionNotFoundを返します
+	GetInspection(ctx context.Context, inspectionID string) (*Inspection, error)
+	// 条件に一致する入荷検品を入荷日時の新しい順に取得します
+	ListInspections(ctx context.Context, filter InspectionFilter) ([]Inspection, error)
+	// 状態がfromの入荷検品の状態・理由コード・トランザクションID・判定の日時とユーザー・メモを更新します
+	// 存在しない場合はErrInspectionNotFound、状態がfromでない場合（同時に更新された場合を含む）はErrInspectionNotQuarantinedを返し、入荷検品は変更しません
+	UpdateInspection(ctx context.Context, inspection *Inspection, from InspectionStatus) error
+	
+	// Batch operations - バッチ操作
+	// バッチ操作の記録（操作・操作ごとの結果・日時）を作成または上書きします
+	SaveBatchOperation(ctx context.Context, batch *BatchOperation) error
+	// 指定されたIDのバッチ操作を取得します。存在しない場合はErrBatchNotFoundを返します
+	GetBatchOperation(ctx context.Context, batchID string) (*BatchOperation, error)
+	
+	// Summary - 集計
+	// 商品数・総在庫数・総評価額・アクティブなアラート数と、ロケーション別の低在庫数を集計します
+	// 低在庫は数量が発注点（設定していない在庫は lowStockThreshold）以下の在庫記録で、在庫のないロケーションは0件として含めます
+	GetInventorySummary(ctx context.Context, lowStockThreshold int64) (*InventorySummary, error)
+	
+	// Bulk import - 一括取り込み
+	// 商品・在庫・トランザクションを単一のトランザクション内で一括挿入します（初期ロードや夜間同期用）
+	// 既存レコードの更新は行わず、重複がある場合は全体をロールバックしてエラーを返します
+	BulkImport(ctx context.Context, data *BulkImportData) error
 	
 	// Health check - ヘルスチェック
 	// データベース接続の健全性を確認します
@@ -178,6 +756,165 @@ type Storage interface {
 	Close() error
 }
 
+// primaryReadKey is the context key that forces reads to the primary database
+// プライマリからの読み取りを強制するコンテキストキー
+type primaryReadKey struct{}
+
+// WithPrimaryRead returns a context that asks storage to read from the primary
+// ストレージにプライマリからの読み取りを要求するコンテキストを返す
+//
+// 読み取りレプリカを持つストレージ実装は、このコンテキストでの読み取りをプライマリで実行します。
+// 更新前の読み取りなど、レプリカの遅延を許容できない場合に使用します。
+func WithPrimaryRead(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryReadKey{}, true)
+}
+
+// IsPrimaryRead reports whether ctx requires reads from the primary database
+// コンテキストがプライマリからの読み取りを要求しているかを判定
+func IsPrimaryRead(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryReadKey{}).(bool)
+	return primary
+}
+
+// expectedUpdatedAtKey is the context key of the update time an item or location update expects
+// 商品・ロケーションの更新が想定する更新日時のコンテキストキー
+type expectedUpdatedAtKey struct{}
+
+// WithExpectedUpdatedAt returns a context that makes UpdateItem and UpdateLocation update only a record last updated at updatedAt
+// 最終更新日時が updatedAt の場合のみ UpdateItem・UpdateLocation が更新するコンテキストを返す
+//
+// ストレージは更新日時の確認と更新を1回の操作で行い、他の更新が先に行われていた場合は
+// 更新せずに ErrPreconditionFailed を返します。If-Match による条件付き更新で使用します。
+func WithExpectedUpdatedAt(ctx context.Context, updatedAt time.Time) context.Context {
+	return context.WithValue(ctx, expectedUpdatedAtKey{}, updatedAt)
+}
+
+// ExpectedUpdatedAt returns the update time expected by the context
+// コンテキストが想定する更新日時を返す
+func ExpectedUpdatedAt(ctx context.Context) (time.Time, bool) {
+	updatedAt, ok := ctx.Value(expectedUpdatedAtKey{}).(time.Time)
+	return updatedAt, ok
+}
+
+// MetadataRequestID is the transaction metadata key of the request ID
+// リクエストIDを記録するトランザクションのメタデータキー
+//
+// 履歴のメタデータ検索（SearchHistoryByMetadata）でリクエストごとの在庫移動を照会できます。
+const MetadataRequestID = "request_id"
+
+// MetadataReservationID is the transaction metadata key of the reservation
+// 予約・予約解除・出庫した予約のIDを記録するトランザクションのメタデータキー
+const MetadataReservationID = "reservation_id"
+
+// MetadataReasonCode is the transaction metadata key of the adjustment reason code
+// 在庫調整の理由コード（AdjustmentReason）を記録するトランザクションのメタデータキー
+const MetadataReasonCode = "reason_code"
+
+// MetadataAdjustmentID is the transaction metadata key of the stock adjustment
+// 在庫に反映した在庫調整のIDを記録するトランザクションのメタデータキー
+const MetadataAdjustmentID = "adjustment_id"
+
+// MetadataInspectionID is the transaction metadata key of the receiving inspection
+// 合格した入荷検品のIDを記録する入庫トランザクションのメタデータキー
+const MetadataInspectionID = "inspection_id"
+
+// MetadataReversalOf is the transaction metadata key of the transaction that a reversal compensates
+// 取消（ReverseTransaction）で記録したトランザクションに、取り消した元のトランザクションのIDを記録するメタデータキー
+//
+// 元のトランザクションの取消は SearchHistoryByMetadata(MetadataReversalOf, 元のID) で照会できます。
+const MetadataReversalOf = "reversal_of"
+
+// MetadataReversalReason is the transaction metadata key of the reason for a reversal
+// 取消の理由を記録するトランザクションのメタデータキー
+const MetadataReversalReason = "reversal_reason"
+
+// MetadataLotPicks is the transaction metadata key of the lots a remove consumed
+// 出庫で消費したロットを「ロット番号:数量」のカンマ区切り（消費した順）で記録するトランザクションのメタデータキー
+const MetadataLotPicks = "lot_picks"
+
+// MetadataLotStrategy is the transaction metadata key of the lot picking strategy of a remove
+// 出庫でロットを消費した順序（LotPickingStrategy）を記録するトランザクションのメタデータキー
+const MetadataLotStrategy = "lot_strategy"
+
+// MetadataAssemblyID is the transaction metadata key of the kit assembly or disassembly
+// キットの組立・分解のIDを記録するトランザクションのメタデータキー
+//
+// 組立・分解で記録した全てのトランザクションは SearchHistoryByMetadata(MetadataAssemblyID, 組立ID) で照会できます。
+const MetadataAssemblyID = "assembly_id"
+
+// MetadataAssemblyType is the transaction metadata key of whether the kit was assembled or disassembled
+// 組立（assemble）・分解（disassemble）の区別を記録するトランザクションのメタデータキー
+const MetadataAssemblyType = "assembly_type"
+
+// MetadataKitItemID is the transaction metadata key of the kit an assembly built or broke down
+// 組立・分解したキットの商品IDを記録するトランザクションのメタデータキー
+const MetadataKitItemID = "kit_item_id"
+
+// MetadataPurchaseOrderID is the transaction metadata key of the purchase order a receipt was recorded against
+// 入荷を記録した発注のIDを記録する入庫トランザクションのメタデータキー
+//
+// 発注に対する全ての入庫は SearchHistoryByMetadata(MetadataPurchaseOrderID, 発注ID) で照会できます。
+const MetadataPurchaseOrderID = "purchase_order_id"
+
+// MetadataGoodsReceiptID is the transaction metadata key of the goods receipt
+// 発注に対する入荷のIDを記録する入庫トランザクションのメタデータキー
+const MetadataGoodsReceiptID = "goods_receipt_id"
+
+// MetadataSalesOrderID is the transaction metadata key of the sales order a shipment was confirmed for
+// 出荷を確定した受注のIDを記録する出庫トランザクションのメタデータキー
+//
+// 受注の全ての出庫は SearchHistoryByMetadata(MetadataSalesOrderID, 受注ID) で照会できます。
+const MetadataSalesOrderID = "sales_order_id"
+
+// MetadataReturnID is the transaction metadata key of the return whose units were restocked or refurbished
+// 再入庫・再整備した返品のIDを記録する入庫トランザクションのメタデータキー
+const MetadataReturnID = "return_id"
+
+// MetadataReturnDisposition is the transaction metadata key of the disposition of returned units (restock or refurbish)
+// 返品の処分方法（restock・refurbish）を記録する入庫トランザクションのメタデータキー
+const MetadataReturnDisposition = "return_disposition"
+
+// MetadataOwnerID is the transaction metadata key of the owner (supplier ID) of consignment stock added, removed or transferred
+// 委託在庫を追加・削除・移動したトランザクションの所有者（仕入先ID）のメタデータキー
+//
+// 所有者の全てのトランザクションは SearchHistoryByMetadata(MetadataOwnerID, 仕入先ID) で照会できます。
+const MetadataOwnerID = "owner_id"
+
+// MetadataChannel is the transaction metadata key of the sales channel of a reservation, its release and its shipment
+// 販売チャネルの予約・予約解除・出庫のトランザクションの販売チャネルのメタデータキー
+//
+// チャネルの全てのトランザクションは SearchHistoryByMetadata(MetadataChannel, チャネル) で照会できます。
+const MetadataChannel = "sales_channel"
+
+// requestIDKey is the context key of the request ID
+// リクエストIDのコンテキストキー
+type requestIDKey struct{}
+
+// WithRequestID returns a context that carries the ID of the request being processed
+// 処理中のリクエストのIDを保持するコンテキストを返す
+//
+// マネージャーはこのコンテキストで記録するトランザクションのメタデータ（MetadataRequestID）と
+// ログにリクエストIDを含めます。
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, or "" when there is none
+// コンテキストが保持するリクエストIDを返す（ない場合は空文字列）
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// OperationObserver is notified of the result of each stock operation
+// 在庫操作ごとの結果の通知を受け取る
+//
+// operation は add・remove・transfer・adjust・reserve・release_reservation・
+// execute_batch・execute_batch_atomic のいずれかで、err は操作が返したエラーです。
+type OperationObserver interface {
+	ObserveOperation(operation string, duration time.Duration, err error)
+}
+
 // EventPublisher defines interface for publishing inventory events
 // 在庫イベント発行のインターフェースを定義
 type EventPublisher interface {
@@ -188,6 +925,8 @@ type EventPublisher interface {
 
 // Events for inventory operations
 // 在庫操作のイベント定義
+//
+// SchemaVersionは発行時にeventsパッケージが設定します（各バージョンの差分はevents.CurrentSchemaVersionを参照）。
 
 // StockChangedEvent represents a stock level change
 // 在庫レベル変更イベントを表現
@@ -201,6 +940,12 @@ type StockChangedEvent struct {
 	TransactionID string   `json:"transaction_id"`
 	Timestamp    time.Time `json:"timestamp"`
 	UserID       string    `json:"user_id"`
+	LotID        string    `json:"lot_id,omitempty"`     // スキーマv2以降
+	LotNumber    string    `json:"lot_number,omitempty"` // スキーマv2以降
+	Delta        int64     `json:"delta"`                // 符号付きの変更数量（NewQuantity - OldQuantity、スキーマv3以降）
+	Available    *int64    `json:"available,omitempty"`  // 変更後の利用可能数量（再生イベントでは未設定、スキーマv3以降）
+	UnitCost     *float64  `json:"unit_cost,omitempty"`  // 単価（未設定の商品では省略、スキーマv3以降）
+	SchemaVersion int      `json:"schema_version"`
 }
 
 // LowStockAlertEvent represents a low stock alert
@@ -211,6 +956,7 @@ type LowStockAlertEvent struct {
 	CurrentQty  int64     `json:"current_qty"`
 	Threshold   int64     `json:"threshold"`
 	Timestamp   time.Time `json:"timestamp"`
+	SchemaVersion int     `json:"schema_version"`
 }
 
 // ItemTransferredEvent represents an item transfer
@@ -224,4 +970,159 @@ type ItemTransferredEvent struct {
 	TransactionID  string    `json:"transaction_id"`
 	Timestamp      time.Time `json:"timestamp"`
 	UserID         string    `json:"user_id"`
+	LotID          string    `json:"lot_id,omitempty"`     // スキーマv2以降
+	LotNumber      string    `json:"lot_number,omitempty"` // スキーマv2以降
+	SchemaVersion  int       `json:"schema_version"`
+}
+
+// Domain event types published through DomainEventPublisher
+// DomainEventPublisherで発行するドメインイベントのタイプ
+const (
+	EventTypeReservationCreated   = "reservation.created"   // 在庫予約
+	EventTypeReservationReleased  = "reservation.released"  // 予約解除
+	EventTypeReservationExpired   = "reservation.expired"   // 予約の期限切れ
+	EventTypeReservationFulfilled = "reservation.fulfilled" // 予約の出庫
+	EventTypeBackorderCreated     = "backorder.created"     // バックオーダーの作成
+	EventTypeBackorderAllocated   = "backorder.allocated"   // バックオーダーの引当
+	EventTypeBackorderCancelled   = "backorder.cancelled"   // バックオーダーの取消
+	EventTypeAlertCreated         = "alert.created"         // アラート作成
+	EventTypeAlertAcknowledged    = "alert.acknowledged"    // アラート確認
+	EventTypeAlertResolved        = "alert.resolved"        // アラート解決
+	EventTypeItemCreated          = "item.created"          // 商品作成
+	EventTypeItemUpdated          = "item.updated"          // 商品更新
+	EventTypeItemDeleted          = "item.deleted"          // 商品削除
+	EventTypeLocationCreated      = "location.created"      // ロケーション作成
+	EventTypeLocationUpdated      = "location.updated"      // ロケーション更新
+	EventTypeLocationDeleted      = "location.deleted"      // ロケーション削除
+	EventTypeLotCreated           = "lot.created"           // ロット作成
+	EventTypeLotExpiring          = "lot.expiring"          // ロットの期限切れ間近
+	EventTypeBatchCompleted       = "batch.completed"       // バッチ操作完了
+	EventTypeAdjustmentPending    = "adjustment.pending"    // 在庫調整の承認待ち
+	EventTypeAdjustmentApproved   = "adjustment.approved"   // 在庫調整の承認
+	EventTypeAdjustmentRejected   = "adjustment.rejected"   // 在庫調整の却下
+	EventTypeStockQuarantined     = "stock.quarantined"     // 入荷した在庫の隔離（検品待ち）
+	EventTypeInspectionPassed     = "inspection.passed"     // 入荷検品の合格（在庫に入庫）
+	EventTypeInspectionFailed     = "inspection.failed"     // 入荷検品の不合格（廃棄）
+	EventTypeReturnReceived       = "return.received"       // 返品の到着（検品待ち）
+	EventTypeReturnDispositioned  = "return.dispositioned"  // 返品の処分（再入庫・再整備・廃棄）
+)
+
+// DomainEventTypes returns all domain event types
+// 全てのドメインイベントタイプを返す
+func DomainEventTypes() []string {
+	return []string{
+		EventTypeReservationCreated, EventTypeReservationReleased, EventTypeReservationExpired, EventTypeReservationFulfilled,
+		EventTypeBackorderCreated, EventTypeBackorderAllocated, EventTypeBackorderCancelled,
+		EventTypeAlertCreated, EventTypeAlertAcknowledged, EventTypeAlertResolved,
+		EventTypeItemCreated, EventTypeItemUpdated, EventTypeItemDeleted,
+		EventTypeLocationCreated, EventTypeLocationUpdated, EventTypeLocationDeleted,
+		EventTypeLotCreated, EventTypeLotExpiring,
+		EventTypeBatchCompleted,
+		EventTypeAdjustmentPending, EventTypeAdjustmentApproved, EventTypeAdjustmentRejected,
+		EventTypeStockQuarantined, EventTypeInspectionPassed, EventTypeInspectionFailed,
+		EventTypeReturnReceived, EventTypeReturnDispositioned,
+	}
+}
+
+// IsDomainEventType reports whether eventType is a domain event type
+// ドメインイベントタイプかどうかを判定
+func IsDomainEventType(eventType string) bool {
+	for _, t := range DomainEventTypes() {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// DomainEventPublisher is implemented by publishers that accept generic domain events
+// 汎用のドメインイベントを受け付けるイベント発行者のインターフェース
+//
+// EventPublisherに加えてこのインターフェースを実装した発行者にのみ、
+// 予約・アラート・マスタ変更・ロット・バッチのイベントが発行されます。
+type DomainEventPublisher interface {
+	PublishEvent(ctx context.Context, event DomainEvent) error
+}
+
+// DomainEvent represents a generic inventory domain event
+// 汎用の在庫ドメインイベントを表現
+//
+// Dataの型はイベントタイプごとに決まります:
+//   - reservation.*: ReservationEvent
+//   - backorder.*: Backorder
+//   - alert.created: StockAlert / alert.acknowledged: AlertAcknowledgedEvent / alert.resolved: AlertResolvedEvent
+//   - item.created, item.updated: Item / location.created, location.updated: Location
+//   - item.deleted, location.deleted: EntityDeletedEvent
+//   - lot.created, lot.expiring: Lot
+//   - batch.completed: BatchCompletedEvent
+type DomainEvent struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	ItemID     string      `json:"item_id,omitempty"`
+	LocationID string      `json:"location_id,omitempty"`
+	Data       interface{} `json:"data"`
+	Timestamp  time.Time   `json:"timestamp"`
+}
+
+// ReservationEvent is the payload of reservation events
+// 予約イベントの内容
+type ReservationEvent struct {
+	ReservationID string     `json:"reservation_id,omitempty"` // 予約ID（複数の予約を解除した場合は空）
+	TransactionID string     `json:"transaction_id,omitempty"` // 記録したトランザクションのID
+	ItemID        string     `json:"item_id"`
+	LocationID    string     `json:"location_id"`
+	Quantity      int64      `json:"quantity"`
+	Reserved      int64      `json:"reserved"`  // 操作後の予約数量
+	Available     int64      `json:"available"` // 操作後の利用可能数量
+	Reference     string     `json:"reference"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"` // 予約の有効期限
+	Channel       string     `json:"channel,omitempty"`    // 予約の販売チャネル
+	UserID        string     `json:"user_id"`
+}
+
+// AlertResolvedEvent is the payload of alert resolved events
+// アラート解決イベントの内容
+type AlertResolvedEvent struct {
+	AlertID    string        `json:"alert_id"`
+	ItemID     string        `json:"item_id,omitempty"`
+	LocationID string        `json:"location_id,omitempty"`
+	Severity   AlertSeverity `json:"severity,omitempty"`
+	ResolvedAt *time.Time    `json:"resolved_at,omitempty"`
+	ResolvedBy string        `json:"resolved_by,omitempty"`
+	Note       string        `json:"note,omitempty"`
+}
+
+// AlertAcknowledgedEvent is the payload of alert acknowledged events
+// アラート確認イベントの内容
+type AlertAcknowledgedEvent struct {
+	AlertID        string        `json:"alert_id"`
+	ItemID         string        `json:"item_id"`
+	LocationID     string        `json:"location_id"`
+	Severity       AlertSeverity `json:"severity"`
+	AcknowledgedAt *time.Time    `json:"acknowledged_at"`
+	AcknowledgedBy string        `json:"acknowledged_by"`
+	Note           string        `json:"note,omitempty"`
+}
+
+// EntityDeletedEvent is the payload of item and location deleted events
+// 商品・ロケーション削除イベントの内容
+type EntityDeletedEvent struct {
+	ID string `json:"id"`
+}
+
+// BatchCompletedEvent is the payload of batch completed events
+// バッチ操作完了イベントの内容
+//
+// オーケストレーターがGetBatchStatusをポーリングせずに一括処理の結果へ対応できるよう、
+// 操作の一覧は含めず、件数と失敗した操作のエラーのみを含みます。
+type BatchCompletedEvent struct {
+	BatchID      string                `json:"batch_id"`
+	Status       BatchStatus           `json:"status"`
+	Atomic       bool                  `json:"atomic"` // ExecuteBatchAtomicで実行された場合はtrue（失敗時は全操作がロールバック済み）
+	TotalCount   int                   `json:"total_count"`
+	SuccessCount int                   `json:"success_count"`
+	FailureCount int                   `json:"failure_count"`
+	Errors       []BatchOperationError `json:"errors"`
+	CreatedAt    time.Time             `json:"created_at"`
+	CompletedAt  *time.Time            `json:"completed_at,omitempty"`
 }
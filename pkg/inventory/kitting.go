@@ -0,0 +1,350 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var _ KitManager = (*Manager)(nil)
+
+// assemblyLeg is the stock change of one item in a kit assembly or disassembly
+// キットの組立・分解での1商品の在庫の増減
+type assemblyLeg struct {
+	item  *Item
+	delta int64 // 構成品・キットの増減（消費は負）
+}
+
+// SetBillOfMaterials validates and registers the bill of materials of a kit
+// キットの部品表を検証して登録（登録済みの場合は構成品を置換）
+//
+// キットと構成品の商品が存在する必要があります。構成品の数量はキット1単位あたりの構成品の基本単位の数量です。
+func (m *Manager) SetBillOfMaterials(ctx context.Context, bom *BillOfMaterials) (err error) {
+	ctx, finish := m.startOperation(ctx, "set_bill_of_materials", attrItemID.String(bom.KitItemID))
+	defer finish(&err)
+
+	if err := ValidateBillOfMaterials(bom); err != nil {
+		return err
+	}
+	if _, err := m.getKitItem(ctx, bom.KitItemID); err != nil {
+		return err
+	}
+	for _, component := range bom.Components {
+		if _, err := m.getKitItem(ctx, component.ItemID); err != nil {
+			return err
+		}
+	}
+
+	bom.UpdatedAt = time.Now().Truncate(time.Microsecond)
+	if err := m.storage.SetBillOfMaterials(ctx, bom); err != nil {
+		if errors.Is(err, ErrItemNotFound) {
+			return ErrItemNotFound
+		}
+		return NewStorageError("set_bill_of_materials", "部品表の登録に失敗しました", err)
+	}
+
+	m.log(ctx).Info("部品表登録完了",
+		zap.String("kit_item_id", bom.KitItemID),
+		zap.Int("components", len(bom.Components)),
+	)
+	return nil
+}
+
+// GetBillOfMaterials retrieves the bill of materials of a kit
+// キットの部品表を取得
+func (m *Manager) GetBillOfMaterials(ctx context.Context, kitItemID string) (*BillOfMaterials, error) {
+	if err := ValidateItemID(kitItemID); err != nil {
+		return nil, err
+	}
+
+	bom, err := m.storage.GetBillOfMaterials(ctx, kitItemID)
+	if err != nil {
+		if errors.Is(err, ErrBOMNotFound) {
+			return nil, ErrBOMNotFound
+		}
+		return nil, NewStorageError("get_bill_of_materials", "部品表の取得に失敗しました", err)
+	}
+	return bom, nil
+}
+
+// ListBillsOfMaterials lists bills of materials ordered by kit item ID
+// 部品表一覧をキットの商品ID順に取得
+func (m *Manager) ListBillsOfMaterials(ctx context.Context, offset, limit int) ([]BillOfMaterials, error) {
+	if err := validateOffsetLimit(offset, limit); err != nil {
+		return nil, err
+	}
+
+	boms, err := m.storage.ListBillsOfMaterials(ctx, offset, limit)
+	if err != nil {
+		return nil, NewStorageError("list_bills_of_materials", "部品表一覧の取得に失敗しました", err)
+	}
+	return boms, nil
+}
+
+// DeleteBillOfMaterials deletes the bill of materials of a kit
+// キットの部品表を削除
+//
+// 記録済みの組立・分解とその在庫は変わりません。
+func (m *Manager) DeleteBillOfMaterials(ctx context.Context, kitItemID string) (err error) {
+	ctx, finish := m.startOperation(ctx, "delete_bill_of_materials", attrItemID.String(kitItemID))
+	defer finish(&err)
+
+	if err := ValidateItemID(kitItemID); err != nil {
+		return err
+	}
+	if err := m.storage.DeleteBillOfMaterials(ctx, kitItemID); err != nil {
+		if errors.Is(err, ErrBOMNotFound) {
+			return ErrBOMNotFound
+		}
+		return NewStorageError("delete_bill_of_materials", "部品表の削除に失敗しました", err)
+	}
+
+	m.log(ctx).Info("部品表削除完了", zap.String("kit_item_id", kitItemID))
+	return nil
+}
+
+// Assemble consumes the components of quantity kits and produces the kits in a single transaction
+// 部品表に従って構成品を消費し、キットを作成（単一トランザクション）
+//
+// いずれかの構成品の利用可能数が不足する場合は ErrInsufficientStock を返し、在庫は変わりません。
+// 構成品の減少とキットの増加は組立IDを metadata.assembly_id に持つ assembly のトランザクションに差分で記録します。
+func (m *Manager) Assemble(ctx context.Context, kitItemID, locationID string, quantity int64, reference string) (_ *Assembly, err error) {
+	ctx, finish := m.startOperation(ctx, "assemble", attrItemID.String(kitItemID), attrLocationID.String(locationID), attrQuantity.Int64(quantity), attrReference.String(reference))
+	defer finish(&err)
+
+	return m.runAssembly(ctx, AssemblyTypeAssemble, kitItemID, locationID, quantity, reference)
+}
+
+// Disassemble breaks down quantity kits and returns their components to stock in a single transaction
+// キットを分解し、部品表に従って構成品を在庫に戻す（単一トランザクション）
+//
+// キットの利用可能数が不足する場合は ErrInsufficientStock を返します。組立の取消にも使用します
+// （assembly のトランザクションは ReverseTransaction で個別に取り消せません）。
+func (m *Manager) Disassemble(ctx context.Context, kitItemID, locationID string, quantity int64, reference string) (_ *Assembly, err error) {
+	ctx, finish := m.startOperation(ctx, "disassemble", attrItemID.String(kitItemID), attrLocationID.String(locationID), attrQuantity.Int64(quantity), attrReference.String(reference))
+	defer finish(&err)
+
+	return m.runAssembly(ctx, AssemblyTypeDisassemble, kitItemID, locationID, quantity, reference)
+}
+
+// GetAssembly retrieves a kit assembly or disassembly with the transactions it recorded
+// キットの組立・分解を記録したトランザクション（商品IDの昇順）とともに取得
+func (m *Manager) GetAssembly(ctx context.Context, assemblyID string) (*Assembly, error) {
+	if assemblyID == "" {
+		return nil, NewValidationError("assembly_id", "組立IDが指定されていません", "")
+	}
+
+	assembly, err := m.storage.GetAssembly(ctx, assemblyID)
+	if err != nil {
+		if errors.Is(err, ErrAssemblyNotFound) {
+			return nil, ErrAssemblyNotFound
+		}
+		return nil, NewStorageError("get_assembly", "組立の取得に失敗しました", err)
+	}
+
+	// 構成品ごとに1件とキットの1件
+	txs, err := m.storage.SearchTransactionsByMetadata(ctx, MetadataAssemblyID, assembly.ID, len(assembly.Components)+1)
+	if err != nil {
+		return nil, NewStorageError("search_transactions", "組立のトランザクションの取得に失敗しました", err)
+	}
+	sort.Slice(txs, func(i, j int) bool { return txs[i].ItemID < txs[j].ItemID })
+	assembly.Transactions = txs
+	return assembly, nil
+}
+
+// ListAssemblies lists kit assemblies and disassemblies matching a filter, newest first, without their transactions
+// 条件に一致するキットの組立・分解を作成日時の新しい順に取得（トランザクションは含まない）
+func (m *Manager) ListAssemblies(ctx context.Context, filter AssemblyFilter) ([]Assembly, error) {
+	if err := validateOffsetLimit(filter.Offset, filter.Limit); err != nil {
+		return nil, err
+	}
+
+	assemblies, err := m.storage.ListAssemblies(ctx, filter)
+	if err != nil {
+		return nil, NewStorageError("list_assemblies", "組立一覧の取得に失敗しました", err)
+	}
+	return assemblies, nil
+}
+
+// runAssembly applies the stock changes of a kit assembly or disassembly and records it
+// キットの組立・分解の在庫の増減を反映して記録
+func (m *Manager) runAssembly(ctx context.Context, assemblyType AssemblyType, kitItemID, locationID string, quantity int64, reference string) (*Assembly, error) {
+	if quantity <= 0 {
+		return nil, NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", quantity))
+	}
+	if err := ValidateReference(reference); err != nil {
+		return nil, err
+	}
+
+	kit, location, err := m.validateItemAndLocation(ctx, kitItemID, locationID)
+	if err != nil {
+		return nil, err
+	}
+	bom, err := m.GetBillOfMaterials(ctx, kitItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 構成品の合計数量を計算（組立は構成品を消費してキットを作成し、分解はその逆）
+	sign := int64(1)
+	if assemblyType == AssemblyTypeAssemble {
+		sign = -1
+	}
+	components := make([]BOMComponent, 0, len(bom.Components))
+	legs := make([]assemblyLeg, 0, len(bom.Components)+1)
+	var componentTotal int64
+	for _, component := range bom.Components {
+		if component.Quantity > math.MaxInt64/quantity || componentTotal > math.MaxInt64-component.Quantity*quantity {
+			return nil, NewValidationError("quantity", "数量が大きすぎます", fmt.Sprintf("%d", quantity))
+		}
+		total := component.Quantity * quantity
+		componentTotal += total
+		item, err := m.getKitItem(ctx, component.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, BOMComponent{ItemID: component.ItemID, Quantity: total})
+		legs = append(legs, assemblyLeg{item: item, delta: sign * total})
+	}
+	legs = append(legs, assemblyLeg{item: kit, delta: -sign * quantity})
+	// 悲観的ロックの場合、同時に実行する組立とのデッドロックを避けるため商品ID順に行ロックを取得
+	sort.Slice(legs, func(i, j int) bool { return legs[i].item.ID < legs[j].item.ID })
+
+	assembly := &Assembly{
+		ID:         NewAssemblyID(),
+		Type:       assemblyType,
+		KitItemID:  kitItemID,
+		LocationID: locationID,
+		Quantity:   quantity,
+		Reference:  reference,
+		Components: components,
+		CreatedAt:  time.Now(),
+		CreatedBy:  m.getUserFromContext(ctx),
+	}
+
+	// 構成品とキットの増減・トランザクション・組立の記録を単一トランザクションで実行
+	var (
+		events *bufferedPublisher
+		stocks []*Stock
+		txs    []Transaction
+	)
+	err = m.retryOnConflict(ctx, func() error {
+		events = &bufferedPublisher{}
+		stocks, txs = stocks[:0], txs[:0]
+		return m.storage.WithinTx(ctx, func(txStorage Storage) error {
+			txManager := m.withStorage(txStorage, events)
+			// ロケーションの在庫の総数が増える場合のみ容量を確認
+			if increase := sign * (componentTotal - quantity); increase > 0 {
+				if err := txManager.enforceCapacity(ctx, location, increase); err != nil {
+					return err
+				}
+			}
+			for _, leg := range legs {
+				stock, tx, err := txManager.applyAssemblyLeg(ctx, assembly, leg)
+				if err != nil {
+					return err
+				}
+				stocks = append(stocks, stock)
+				txs = append(txs, *tx)
+			}
+			if err := txStorage.CreateAssembly(ctx, assembly); err != nil {
+				return NewStorageError("create_assembly", "組立の記録に失敗しました", err)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if m.publisher != nil {
+		events.flush(ctx, m.publisher, m.logger)
+	}
+	for i, stock := range stocks {
+		if legs[i].delta < 0 {
+			m.checkLowStock(ctx, stock.ItemID, stock.LocationID, stock.Quantity)
+		} else {
+			m.checkOverStock(ctx, location, stock)
+		}
+		m.evaluateAlertRules(ctx, stock)
+	}
+
+	message := "キット組立完了"
+	if assemblyType == AssemblyTypeDisassemble {
+		message = "キット分解完了"
+	}
+	m.log(ctx).Info(message,
+		zap.String("assembly_id", assembly.ID),
+		zap.String("kit_item_id", kitItemID),
+		zap.String("location_id", locationID),
+		zap.Int64("quantity", quantity),
+		zap.String("reference", reference),
+	)
+
+	assembly.Transactions = txs
+	return assembly, nil
+}
+
+// applyAssemblyLeg changes the stock of one item of an assembly and records an assembly transaction
+// 組立・分解の1商品の在庫を増減し、assembly のトランザクションを記録
+func (m *Manager) applyAssemblyLeg(ctx context.Context, assembly *Assembly, leg assemblyLeg) (*Stock, *Transaction, error) {
+	var (
+		oldQuantity int64
+		stock       *Stock
+		err         error
+	)
+	if leg.delta < 0 {
+		oldQuantity, stock, err = m.decreaseStock(ctx, leg.item.ID, assembly.LocationID, -leg.delta)
+	} else {
+		oldQuantity, stock, err = m.increaseStock(ctx, leg.item.ID, assembly.LocationID, leg.delta)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	locationID := assembly.LocationID
+	tx := &Transaction{
+		ID:         NewTransactionID(),
+		Type:       TransactionTypeAssembly,
+		ItemID:     leg.item.ID,
+		ToLocation: &locationID,
+		Quantity:   leg.delta, // 差分を記録
+		Reference:  assembly.Reference,
+		Metadata: transactionMetadata(ctx, map[string]string{
+			MetadataAssemblyID:   assembly.ID,
+			MetadataAssemblyType: string(assembly.Type),
+			MetadataKitItemID:    assembly.KitItemID,
+		}),
+		CreatedAt: assembly.CreatedAt,
+		CreatedBy: assembly.CreatedBy,
+	}
+	if err := m.storage.CreateTransaction(ctx, tx); err != nil {
+		return nil, nil, NewStorageError("create_transaction", "組立トランザクション記録に失敗しました", err)
+	}
+
+	if m.publisher != nil {
+		event := m.newStockChangedEvent(ctx, stock, oldQuantity, leg.item.UnitCost, string(assembly.Type), assembly.Reference, tx.ID)
+		if err := m.publisher.PublishStockChanged(ctx, event); err != nil {
+			m.log(ctx).Error("組立イベント発行に失敗しました", zap.Error(err))
+		}
+	}
+	return stock, tx, nil
+}
+
+// getKitItem retrieves an item of a bill of materials
+// 部品表の商品を取得
+func (m *Manager) getKitItem(ctx context.Context, itemID string) (*Item, error) {
+	item, err := m.storage.GetItem(ctx, itemID)
+	if err != nil {
+		if errors.Is(err, ErrItemNotFound) {
+			return nil, ErrItemNotFound
+		}
+		return nil, NewStorageError("get_item", "商品取得に失敗しました", err)
+	}
+	return item, nil
+}
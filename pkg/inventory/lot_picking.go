@@ -0,0 +1,156 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// lotPickingKey is the context key that requests a remove to consume lots
+// 出庫でロットの消費を要求するコンテキストキー
+type lotPickingKey struct{}
+
+// WithLotPicking returns a context that makes Remove consume the lots of the item in the given order
+// Remove が商品のロットを指定した順序で消費するコンテキストを返す
+//
+// strategy が空の場合は Config.LotPickingStrategy（未設定の場合は LotPickingFEFO）を使用します。
+// 期限切れのロットは消費しません。消費したロットは出庫トランザクションのメタデータ
+// MetadataLotPicks に記録し、1つのロットのみ消費した場合は LotNumber にも記録します。
+// 有効期限内のロットの数量が不足する場合、Remove は ErrInsufficientLotStock を返します。
+func WithLotPicking(ctx context.Context, strategy LotPickingStrategy) context.Context {
+	return context.WithValue(ctx, lotPickingKey{}, strategy)
+}
+
+// lotPicking returns the lot picking strategy requested by the context
+// コンテキストが要求するロットの消費順序を返す（要求がない場合 ok は false）
+func (m *Manager) lotPicking(ctx context.Context) (strategy LotPickingStrategy, ok bool) {
+	strategy, ok = ctx.Value(lotPickingKey{}).(LotPickingStrategy)
+	if !ok {
+		return "", false
+	}
+	if strategy == "" {
+		strategy = m.config.LotPickingStrategy
+	}
+	if strategy == "" {
+		strategy = LotPickingFEFO
+	}
+	return strategy, true
+}
+
+// pickLots consumes quantity from the unexpired lots of an item in the order of strategy
+// 商品の有効期限内のロットから strategy の順に数量を消費（トランザクション内で呼び出すこと）
+//
+// 出庫元のロケーションに商品のロット在庫の記録がある場合は、そのロケーションのロット在庫のみから
+// 消費してロット在庫も減算します。記録がない場合は商品の全ロットから消費します。
+// 一覧の取得後に他の出庫がロットを消費した場合は ErrVersionMismatch を返し、再試行で選び直します。
+func (m *Manager) pickLots(ctx context.Context, itemID, locationID string, quantity int64, strategy LotPickingStrategy) ([]LotPick, error) {
+	lots, err := m.storage.GetLotsByItem(ctx, itemID)
+	if err != nil {
+		return nil, NewStorageError("get_lots", "ロット取得に失敗しました", err)
+	}
+	lotStocks, err := m.storage.ListLotStocks(ctx, LotStockFilter{ItemID: itemID, LocationID: locationID})
+	if err != nil {
+		return nil, NewStorageError("list_lot_stocks", "ロット在庫一覧の取得に失敗しました", err)
+	}
+	var atLocation map[string]int64
+	if len(lotStocks) > 0 {
+		atLocation = make(map[string]int64, len(lotStocks))
+		for _, lotStock := range lotStocks {
+			atLocation[lotStock.LotID] = lotStock.Quantity
+		}
+	}
+
+	now := time.Now()
+	candidates := make([]Lot, 0, len(lots))
+	var total int64
+	for _, lot := range lots {
+		if atLocation != nil {
+			held, ok := atLocation[lot.ID]
+			if !ok {
+				continue
+			}
+			if held < lot.Quantity {
+				lot.Quantity = held
+			}
+		}
+		if lot.Quantity <= 0 || (lot.ExpiryDate != nil && !lot.ExpiryDate.After(now)) {
+			continue
+		}
+		candidates = append(candidates, lot)
+		total += lot.Quantity
+	}
+	if total < quantity {
+		return nil, ErrInsufficientLotStock
+	}
+	sortLotsForPicking(candidates, strategy)
+
+	picks := make([]LotPick, 0, 1)
+	remaining := quantity
+	for _, lot := range candidates {
+		if remaining == 0 {
+			break
+		}
+		picked := lot.Quantity
+		if picked > remaining {
+			picked = remaining
+		}
+		if _, err := m.storage.AdjustLotQuantity(ctx, lot.ID, -picked); err != nil {
+			if errors.Is(err, ErrInsufficientStock) || errors.Is(err, ErrLotNotFound) {
+				return nil, ErrVersionMismatch
+			}
+			return nil, NewStorageError("adjust_lot_quantity", "ロット数量調整に失敗しました", err)
+		}
+		if atLocation != nil {
+			if _, err := m.storage.AdjustLotStock(ctx, &lot, locationID, -picked); err != nil {
+				if errors.Is(err, ErrInsufficientLotStock) {
+					return nil, ErrVersionMismatch
+				}
+				return nil, NewStorageError("adjust_lot_stock", "ロット在庫の更新に失敗しました", err)
+			}
+		}
+		picks = append(picks, LotPick{LotID: lot.ID, LotNumber: lot.Number, Quantity: picked, ExpiryDate: lot.ExpiryDate})
+		remaining -= picked
+	}
+	return picks, nil
+}
+
+// sortLotsForPicking orders lots in the order a remove consumes them
+// ロットを出庫で消費する順に並び替え
+func sortLotsForPicking(lots []Lot, strategy LotPickingStrategy) {
+	sort.SliceStable(lots, func(i, j int) bool {
+		a, b := lots[i], lots[j]
+		if strategy == LotPickingFEFO {
+			switch {
+			case a.ExpiryDate != nil && b.ExpiryDate == nil:
+				return true
+			case a.ExpiryDate == nil && b.ExpiryDate != nil:
+				return false
+			case a.ExpiryDate != nil && !a.ExpiryDate.Equal(*b.ExpiryDate):
+				return a.ExpiryDate.Before(*b.ExpiryDate)
+			}
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			if strategy == LotPickingLIFO {
+				return a.CreatedAt.After(b.CreatedAt)
+			}
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	})
+}
+
+// lotPickMetadata returns the transaction metadata recording the lots a remove consumed
+// 出庫で消費したロットを記録するトランザクションのメタデータを返す
+func lotPickMetadata(strategy LotPickingStrategy, picks []LotPick) map[string]string {
+	entries := make([]string, 0, len(picks))
+	for _, pick := range picks {
+		entries = append(entries, fmt.Sprintf("%s:%d", pick.LotNumber, pick.Quantity))
+	}
+	return map[string]string{
+		MetadataLotStrategy: string(strategy),
+		MetadataLotPicks:    strings.Join(entries, ","),
+	}
+}
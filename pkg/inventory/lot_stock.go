@@ -0,0 +1,137 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var _ LotStockManager = (*Manager)(nil)
+
+// lotNumberKey is the context key that names the lot an add, remove or transfer moves
+// 在庫追加・削除・移動の対象ロットを指定するコンテキストキー
+type lotNumberKey struct{}
+
+// WithLotNumber returns a context that makes Add, Remove and Transfer move stock of the given lot
+// Add・Remove・Transfer が指定したロット番号のロットの在庫を移動するコンテキストを返す
+//
+// Add は商品のロット番号のロットがない場合に作成し、ロットの数量と入荷先のロット在庫を加算します。
+// Remove はロットの数量と出庫元のロット在庫を減算し、ロット在庫が不足する場合は
+// ErrInsufficientLotStock を返します。Transfer はロット在庫を移動元から移動先に移します。
+// いずれもトランザクションの LotNumber にロット番号を記録します。WithLotPicking とは併用できません。
+func WithLotNumber(ctx context.Context, lotNumber string) context.Context {
+	return context.WithValue(ctx, lotNumberKey{}, lotNumber)
+}
+
+// lotNumber returns the lot number requested by the context, or an empty string
+// コンテキストが指定するロット番号を返す（指定がない場合は空文字列）
+func lotNumber(ctx context.Context) string {
+	number, _ := ctx.Value(lotNumberKey{}).(string)
+	return number
+}
+
+// GetLotStock gets the quantity of a lot held at a location
+// ロットのロケーションの在庫数量を取得
+//
+// ロケーションにロットの在庫の記録がない場合は数量0のロット在庫を返します。
+func (m *Manager) GetLotStock(ctx context.Context, lotID, locationID string) (*LotStock, error) {
+	lot, err := m.storage.GetLot(ctx, lotID)
+	if err != nil {
+		if errors.Is(err, ErrLotNotFound) {
+			return nil, ErrLotNotFound
+		}
+		return nil, NewStorageError("get_lot", "ロット取得に失敗しました", err)
+	}
+
+	lotStock, err := m.storage.GetLotStock(ctx, lotID, locationID)
+	if err != nil {
+		if errors.Is(err, ErrLotStockNotFound) {
+			return &LotStock{LotID: lot.ID, LotNumber: lot.Number, ItemID: lot.ItemID, LocationID: locationID}, nil
+		}
+		return nil, NewStorageError("get_lot_stock", "ロット在庫の取得に失敗しました", err)
+	}
+	return lotStock, nil
+}
+
+// ListLotStocks lists lot stocks matching a filter
+// 条件に一致するロット在庫を取得
+func (m *Manager) ListLotStocks(ctx context.Context, filter LotStockFilter) ([]LotStock, error) {
+	if err := validateOffsetLimit(filter.Offset, filter.Limit); err != nil {
+		return nil, err
+	}
+	lotStocks, err := m.storage.ListLotStocks(ctx, filter)
+	if err != nil {
+		return nil, NewStorageError("list_lot_stocks", "ロット在庫一覧の取得に失敗しました", err)
+	}
+	return lotStocks, nil
+}
+
+// receiveLot adds quantity to a lot and to its stock at a location, creating the lot if needed
+// ロットの数量とロケーションのロット在庫を加算（ロットがない場合は作成、トランザクション内で呼び出すこと）
+func (m *Manager) receiveLot(ctx context.Context, item *Item, locationID, number string, quantity int64) (*Lot, error) {
+	lot, err := m.storage.GetLotByNumber(ctx, item.ID, number)
+	switch {
+	case errors.Is(err, ErrLotNotFound):
+		lot = &Lot{
+			ID:        NewLotID(),
+			Number:    number,
+			ItemID:    item.ID,
+			UnitCost:  item.UnitCost,
+			CreatedAt: time.Now(),
+		}
+		if err := m.storage.CreateLot(ctx, lot); err != nil {
+			return nil, NewStorageError("create_lot", "ロット作成に失敗しました", err)
+		}
+	case err != nil:
+		return nil, NewStorageError("get_lot", "ロット取得に失敗しました", err)
+	}
+
+	if lot, err = m.storage.AdjustLotQuantity(ctx, lot.ID, quantity); err != nil {
+		return nil, NewStorageError("adjust_lot_quantity", "ロット数量調整に失敗しました", err)
+	}
+	if _, err := m.storage.AdjustLotStock(ctx, lot, locationID, quantity); err != nil {
+		return nil, NewStorageError("adjust_lot_stock", "ロット在庫の更新に失敗しました", err)
+	}
+	return lot, nil
+}
+
+// consumeLot subtracts quantity from a lot and from its stock at a location
+// ロットの数量とロケーションのロット在庫を減算（トランザクション内で呼び出すこと）
+func (m *Manager) consumeLot(ctx context.Context, itemID, locationID, number string, quantity int64) (*Lot, error) {
+	lot, err := m.storage.GetLotByNumber(ctx, itemID, number)
+	if err != nil {
+		if errors.Is(err, ErrLotNotFound) {
+			return nil, ErrLotNotFound
+		}
+		return nil, NewStorageError("get_lot", "ロット取得に失敗しました", err)
+	}
+
+	if _, err := m.storage.AdjustLotStock(ctx, lot, locationID, -quantity); err != nil {
+		if errors.Is(err, ErrInsufficientLotStock) {
+			return nil, ErrInsufficientLotStock
+		}
+		return nil, NewStorageError("adjust_lot_stock", "ロット在庫の更新に失敗しました", err)
+	}
+	if lot, err = m.storage.AdjustLotQuantity(ctx, lot.ID, -quantity); err != nil {
+		if errors.Is(err, ErrInsufficientStock) {
+			return nil, ErrInsufficientLotStock
+		}
+		return nil, NewStorageError("adjust_lot_quantity", "ロット数量調整に失敗しました", err)
+	}
+	return lot, nil
+}
+
+// moveLotStock moves quantity of a lot between locations without changing the lot's quantity
+// ロットの数量を変えずにロット在庫をロケーション間で移動（トランザクション内で呼び出すこと）
+func (m *Manager) moveLotStock(ctx context.Context, lot *Lot, fromLocationID, toLocationID string, quantity int64) error {
+	if _, err := m.storage.AdjustLotStock(ctx, lot, fromLocationID, -quantity); err != nil {
+		if errors.Is(err, ErrInsufficientLotStock) {
+			return ErrInsufficientLotStock
+		}
+		return NewStorageError("adjust_lot_stock", "ロット在庫の更新に失敗しました", err)
+	}
+	if _, err := m.storage.AdjustLotStock(ctx, lot, toLocationID, quantity); err != nil {
+		return NewStorageError("adjust_lot_stock", "ロット在庫の更新に失敗しました", err)
+	}
+	return nil
+}
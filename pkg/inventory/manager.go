@@ -2,7 +2,11 @@ package inventory
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
@@ -15,6 +19,9 @@ type Manager struct {
 	publisher EventPublisher  // イベント発行者
 	logger    *zap.Logger     // ログ
 	config    *Config         // 設定
+
+	*ValuationEngineImpl // 在庫評価（/valuation 以下のAPI）
+	*AnalyticsEngineImpl // 在庫分析（/analytics 以下のAPI）
 }
 
 // すべてのインターフェースを実装することを明示
@@ -23,6 +30,8 @@ var (
 	_ ItemManager     = (*Manager)(nil)
 	_ LocationManager = (*Manager)(nil)
 	_ LotManager      = (*Manager)(nil)
+	_ ValuationEngine = (*Manager)(nil)
+	_ AnalyticsEngine = (*Manager)(nil)
 )
 
 // Config holds configuration for the inventory manager
@@ -31,10 +40,51 @@ type Config struct {
 	AllowNegativeStock bool          `yaml:"allow_negative_stock"` // 負の在庫を許可
 	DefaultLocation    string        `yaml:"default_location"`     // デフォルトロケーション
 	AuditEnabled       bool          `yaml:"audit_enabled"`        // 監査ログ有効
-	LowStockThreshold  int64         `yaml:"low_stock_threshold"`  // 低在庫閾値
+	LowStockThreshold  int64         `yaml:"low_stock_threshold"`  // 低在庫閾値（発注点を設定していない在庫に適用）
 	AlertTimeout       time.Duration `yaml:"alert_timeout"`        // アラートタイムアウト
+	LockingStrategy    LockingStrategy `yaml:"locking_strategy"`   // 在庫更新時のロック方式
+	RetryMaxAttempts   int           `yaml:"retry_max_attempts"`   // バージョン競合時の最大試行回数（1以下でリトライなし）
+	RetryBaseDelay     time.Duration `yaml:"retry_base_delay"`     // リトライ間隔の初期値（試行ごとに倍増）
+	RetryMaxDelay      time.Duration `yaml:"retry_max_delay"`      // リトライ間隔の上限
+	RetryJitter        float64       `yaml:"retry_jitter"`         // リトライ間隔のゆらぎ率（0〜1）
+	RetentionMonths    int           `yaml:"retention_months"`     // トランザクションの保持月数（超過分はアーカイブ、0で無効）
+	ReservationTTL     time.Duration `yaml:"reservation_ttl"`      // Reserve で作成する予約の有効期間（0で期限なし）
+	EnableBackorders   bool          `yaml:"enable_backorders"`    // 在庫不足時のバックオーダー（WithBackorder）と入荷時の自動引当を有効化
+	CapacityPolicy     CapacityPolicy `yaml:"capacity_policy"`     // ロケーションの容量を超える入荷・移動の扱い（空の場合は warn）
+	DisableLowStockCheck bool        `yaml:"disable_low_stock_check"` // 組み込みの低在庫判定（LowStockThreshold・発注点）を無効化し、アラートルールのみで判定
+	AdjustmentApprovalThreshold float64 `yaml:"adjustment_approval_threshold"` // 調整額がこの値を超える在庫調整（RequestAdjustment）を承認待ちにする（0で承認なし）
+	LotPickingStrategy LotPickingStrategy `yaml:"lot_picking_strategy"` // ロットを消費する出庫（WithLotPicking）の既定の順序（空の場合は fefo）
+	Observer           OperationObserver `yaml:"-"`                 // 在庫操作の結果の通知先（メトリクス用、nilの場合は通知しない）
 }
 
+// LockingStrategy defines how concurrent stock updates are serialized
+// 在庫の同時更新を制御するロック方式を定義
+type LockingStrategy string
+
+const (
+	// LockingStrategyOptimistic はバージョン番号による楽観的ロック（デフォルト）
+	LockingStrategyOptimistic LockingStrategy = "optimistic"
+	// LockingStrategyPessimistic は行ロック（SELECT ... FOR UPDATE）による悲観的ロック
+	// 同一在庫への更新が集中する環境でバージョン競合を避けるために使用します
+	LockingStrategyPessimistic LockingStrategy = "pessimistic"
+)
+
+// CapacityPolicy defines how Add and Transfer treat a location whose capacity would be exceeded
+// 入荷・移動でロケーションの容量（Location.Capacity）を超える場合の扱いを定義
+//
+// 容量が0のロケーションは無制限として扱い、確認しません。
+type CapacityPolicy string
+
+const (
+	// CapacityPolicyWarn は操作を許可し、容量を超えたロケーションに過剰在庫アラートを発生させる（デフォルト）
+	CapacityPolicyWarn CapacityPolicy = "warn"
+	// CapacityPolicyEnforce は容量を超える操作を ErrLocationCapacityExceeded で拒否する
+	// ロケーションの合計在庫数は行ロックしないため、異なる商品の同時入荷では容量をわずかに超える場合があります
+	CapacityPolicyEnforce CapacityPolicy = "enforce"
+	// CapacityPolicyIgnore は容量を確認しない
+	CapacityPolicyIgnore CapacityPolicy = "ignore"
+)
+
 // NewManager creates a new inventory manager
 // 新しい在庫マネージャーを作成
 func NewManager(storage Storage, publisher EventPublisher, logger *zap.Logger, config *Config) *Manager {
@@ -45,6 +95,12 @@ func NewManager(storage Storage, publisher EventPublisher, logger *zap.Logger, c
 			AuditEnabled:       true,
 			LowStockThreshold:  10,
 			AlertTimeout:       time.Hour * 24,
+			LockingStrategy:    LockingStrategyOptimistic,
+			RetryMaxAttempts:   3,
+			RetryBaseDelay:     10 * time.Millisecond,
+			RetryMaxDelay:      200 * time.Millisecond,
+			RetryJitter:        0.5,
+			CapacityPolicy:     CapacityPolicyWarn,
 		}
 	}
 
@@ -53,185 +109,252 @@ func NewManager(storage Storage, publisher EventPublisher, logger *zap.Logger, c
 		publisher: publisher,
 		logger:    logger,
 		config:    config,
+
+		ValuationEngineImpl: NewValuationEngine(storage, logger),
+		AnalyticsEngineImpl: NewAnalyticsEngine(storage, logger),
 	}
 }
 
 // Add adds inventory to a specific location
 // 指定ロケーションに在庫を追加
-func (m *Manager) Add(ctx context.Context, itemID, locationID string, quantity int64, reference string) error {
+//
+// WithLotNumber のコンテキストでは、ロットの数量とロケーションのロット在庫も加算します。
+// WithOwner のコンテキストでは、所有者の委託在庫も加算します。
+func (m *Manager) Add(ctx context.Context, itemID, locationID string, quantity int64, reference string) (err error) {
+	ctx, finish := m.startOperation(ctx, "add", attrItemID.String(itemID), attrLocationID.String(locationID), attrQuantity.Int64(quantity), attrReference.String(reference))
+	defer finish(&err)
+
 	if quantity <= 0 {
 		return NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", quantity))
 	}
 
-	// 商品とロケーションの存在確認
-	if err := m.validateItemAndLocation(ctx, itemID, locationID); err != nil {
+	// 単位を指定した場合は数量を商品の基本単位に換算
+	ctx, quantity, err = m.toBaseQuantity(ctx, itemID, quantity)
+	if err != nil {
 		return err
 	}
 
-	// 現在の在庫を取得または初期化
-	stock, err := m.storage.GetStock(ctx, itemID, locationID)
-	if err != nil && err != ErrStockNotFound {
-		return NewStorageError("get_stock", "在庫取得に失敗しました", err)
+	number := lotNumber(ctx)
+	if number != "" {
+		if err := ValidateLotNumber(number); err != nil {
+			return err
+		}
 	}
 
-	oldQuantity := int64(0)
-	if stock == nil {
-		// 新しい在庫記録を作成
-		stock = &Stock{
-			ItemID:     itemID,
-			LocationID: locationID,
-			Quantity:   quantity,
-			Reserved:   0,
-			Version:    1,
-			UpdatedAt:  time.Now(),
-			UpdatedBy:  m.getUserFromContext(ctx),
-		}
-		stock.CalculateAvailable()
+	if err := m.validateOwner(ctx); err != nil {
+		return err
+	}
 
-		if err := m.storage.CreateStock(ctx, stock); err != nil {
-			return NewStorageError("create_stock", "在庫作成に失敗しました", err)
-		}
-	} else {
-		// 既存の在庫を更新
-		oldQuantity = stock.Quantity
-		stock.Quantity += quantity
-		stock.Version++
-		stock.UpdatedAt = time.Now()
-		stock.UpdatedBy = m.getUserFromContext(ctx)
-		stock.CalculateAvailable()
+	// 商品とロケーションの存在確認
+	item, location, err := m.validateItemAndLocation(ctx, itemID, locationID)
+	if err != nil {
+		return err
+	}
 
-		if err := m.storage.UpdateStock(ctx, stock); err != nil {
-			return NewStorageError("update_stock", "在庫更新に失敗しました", err)
+	// ロットや所有者を指定した場合は在庫とロット在庫・委託在庫を同じトランザクションで加算
+	lock := m.withStockLock
+	if number != "" || stockOwner(ctx) != "" {
+		lock = m.withStockTx
+	}
+	var oldQuantity int64
+	var stock *Stock
+	var lot *Lot
+	err = lock(ctx, func(lm *Manager) (err error) {
+		if err := lm.enforceCapacity(ctx, location, quantity); err != nil {
+			return err
+		}
+		oldQuantity, stock, err = lm.increaseStock(ctx, itemID, locationID, quantity)
+		if err != nil {
+			return err
 		}
+		if stockOwner(ctx) != "" {
+			if err := lm.adjustConsignment(ctx, itemID, locationID, quantity); err != nil {
+				return err
+			}
+		}
+		if number == "" {
+			return nil
+		}
+		lot, err = lm.receiveLot(ctx, item, locationID, number, quantity)
+		return err
+	})
+	if err != nil {
+		return err
 	}
 
+	// イベントとトランザクション記録で同じIDを使用し、イベントから記録を参照できるようにする
+	txID := NewTransactionID()
+
 	// イベント発行
 	if m.publisher != nil {
-		event := StockChangedEvent{
-			ItemID:        itemID,
-			LocationID:    locationID,
-			OldQuantity:   oldQuantity,
-			NewQuantity:   stock.Quantity,
-			ChangeType:    "add",
-			Reference:     reference,
-			TransactionID: NewTransactionID(),
-			Timestamp:     time.Now(),
-			UserID:        m.getUserFromContext(ctx),
+		event := m.newStockChangedEvent(ctx, stock, oldQuantity, item.UnitCost, "add", reference, txID)
+		if lot != nil {
+			event.LotID, event.LotNumber = lot.ID, lot.Number
 		}
 		if err := m.publisher.PublishStockChanged(ctx, event); err != nil {
-			m.logger.Error("イベント発行に失敗しました", zap.Error(err))
+			m.log(ctx).Error("イベント発行に失敗しました", zap.Error(err))
 		}
 	}
 
 	// トランザクション記録
 	tx := &Transaction{
-		ID:         NewTransactionID(),
+		ID:         txID,
 		Type:       TransactionTypeInbound,
 		ItemID:     itemID,
 		ToLocation: &locationID,
 		Quantity:   quantity,
 		Reference:  reference,
+		Metadata:   transactionMetadata(ctx, ownerMetadata(ctx, unitMetadata(ctx, nil))),
 		CreatedAt:  time.Now(),
 		CreatedBy:  m.getUserFromContext(ctx),
 	}
+	if lot != nil {
+		tx.LotNumber = &lot.Number
+	}
 
 	if err := m.storage.CreateTransaction(ctx, tx); err != nil {
-		m.logger.Error("トランザクション記録に失敗しました", zap.Error(err))
+		m.log(ctx).Error("トランザクション記録に失敗しました", zap.Error(err))
 	}
 
-	m.logger.Info("在庫追加完了",
+	m.log(ctx).Info("在庫追加完了",
 		zap.String("item_id", itemID),
 		zap.String("location_id", locationID),
 		zap.Int64("quantity", quantity),
 		zap.String("reference", reference),
 	)
 
+	// 過剰在庫アラート・アラートルールのチェック
+	m.checkOverStock(ctx, location, stock)
+	m.evaluateAlertRules(ctx, stock)
+
+	// 入荷した在庫を入荷待ちのバックオーダーに引き当てる
+	m.allocateArrivedStock(ctx, itemID, locationID)
+
 	return nil
 }
 
 // Remove removes inventory from a specific location
 // 指定ロケーションから在庫を削除
-func (m *Manager) Remove(ctx context.Context, itemID, locationID string, quantity int64, reference string) error {
+//
+// WithBackorder のコンテキストでは、在庫が不足する場合にバックオーダーを作成します。
+// WithLotNumber のコンテキストでは、ロットの数量とロケーションのロット在庫も減算します。
+// WithOwner のコンテキストでは、所有者の委託在庫も減算します。
+func (m *Manager) Remove(ctx context.Context, itemID, locationID string, quantity int64, reference string) (err error) {
+	ctx, finish := m.startOperation(ctx, "remove", attrItemID.String(itemID), attrLocationID.String(locationID), attrQuantity.Int64(quantity), attrReference.String(reference))
+	defer finish(&err)
+
 	if quantity <= 0 {
 		return NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", quantity))
 	}
 
-	// 商品とロケーションの存在確認
-	if err := m.validateItemAndLocation(ctx, itemID, locationID); err != nil {
+	// 単位を指定した場合は数量を商品の基本単位に換算
+	ctx, quantity, err = m.toBaseQuantity(ctx, itemID, quantity)
+	if err != nil {
 		return err
 	}
 
-	// 現在の在庫を取得
-	stock, err := m.storage.GetStock(ctx, itemID, locationID)
-	if err != nil {
-		if err == ErrStockNotFound {
-			return ErrInsufficientStock
+	strategy, pickLots := m.lotPicking(ctx)
+	if pickLots {
+		if err := ValidateLotPickingStrategy(strategy); err != nil {
+			return err
+		}
+	}
+	number := lotNumber(ctx)
+	if number != "" {
+		if pickLots {
+			return NewValidationError("lot_number", "ロット番号とロットの消費順序は同時に指定できません", number)
+		}
+		if err := ValidateLotNumber(number); err != nil {
+			return err
 		}
-		return NewStorageError("get_stock", "在庫取得に失敗しました", err)
 	}
 
-	// 在庫不足チェック
-	if stock.Available < quantity {
-		return ErrInsufficientStock
+	if err := m.validateOwner(ctx); err != nil {
+		return err
 	}
 
-	// 在庫更新
-	oldQuantity := stock.Quantity
-	stock.Quantity -= quantity
-	stock.Version++
-	stock.UpdatedAt = time.Now()
-	stock.UpdatedBy = m.getUserFromContext(ctx)
-	stock.CalculateAvailable()
+	// 商品とロケーションの存在確認
+	item, _, err := m.validateItemAndLocation(ctx, itemID, locationID)
+	if err != nil {
+		return err
+	}
 
-	// 負の在庫チェック
-	if !m.config.AllowNegativeStock && stock.Quantity < 0 {
-		return NewBusinessRuleError("negative_stock", "負の在庫は許可されていません", fmt.Sprintf("商品ID: %s, ロケーション: %s", itemID, locationID))
+	// ロットや委託在庫を消費する場合は在庫とロット・委託在庫の数量を同じトランザクションで減算
+	lock := m.withStockLock
+	if pickLots || number != "" || stockOwner(ctx) != "" {
+		lock = m.withStockTx
+	}
+	var oldQuantity int64
+	var stock *Stock
+	var picks []LotPick
+	err = lock(ctx, func(lm *Manager) (err error) {
+		oldQuantity, stock, err = lm.decreaseStock(ctx, itemID, locationID, quantity)
+		if err == nil && stockOwner(ctx) != "" {
+			err = lm.adjustConsignment(ctx, itemID, locationID, -quantity)
+		}
+		switch {
+		case err != nil:
+			return err
+		case pickLots:
+			picks, err = lm.pickLots(ctx, itemID, locationID, quantity, strategy)
+		case number != "":
+			var lot *Lot
+			if lot, err = lm.consumeLot(ctx, itemID, locationID, number, quantity); err == nil {
+				picks = []LotPick{{LotID: lot.ID, LotNumber: lot.Number, Quantity: quantity, ExpiryDate: lot.ExpiryDate}}
+			}
+		}
+		return err
+	})
+	if err != nil {
+		return m.backorderOnShortage(ctx, itemID, locationID, quantity, reference, err)
 	}
 
-	if err := m.storage.UpdateStock(ctx, stock); err != nil {
-		return NewStorageError("update_stock", "在庫更新に失敗しました", err)
+	txID := NewTransactionID()
+	var (
+		metadata  map[string]string
+		lotNumber *string
+	)
+	if pickLots {
+		metadata = lotPickMetadata(strategy, picks)
+	}
+	if len(picks) == 1 {
+		lotNumber = &picks[0].LotNumber
 	}
 
 	// イベント発行
 	if m.publisher != nil {
-		event := StockChangedEvent{
-			ItemID:        itemID,
-			LocationID:    locationID,
-			OldQuantity:   oldQuantity,
-			NewQuantity:   stock.Quantity,
-			ChangeType:    "remove",
-			Reference:     reference,
-			TransactionID: NewTransactionID(),
-			Timestamp:     time.Now(),
-			UserID:        m.getUserFromContext(ctx),
+		event := m.newStockChangedEvent(ctx, stock, oldQuantity, item.UnitCost, "remove", reference, txID)
+		if len(picks) == 1 {
+			event.LotID, event.LotNumber = picks[0].LotID, picks[0].LotNumber
 		}
 		if err := m.publisher.PublishStockChanged(ctx, event); err != nil {
-			m.logger.Error("イベント発行に失敗しました", zap.Error(err))
+			m.log(ctx).Error("イベント発行に失敗しました", zap.Error(err))
 		}
 	}
 
-	// 低在庫アラートチェック
-	if stock.Quantity <= m.config.LowStockThreshold {
-		m.triggerLowStockAlert(ctx, itemID, locationID, stock.Quantity)
-	}
+	// 低在庫アラート・アラートルールのチェック
+	m.checkLowStock(ctx, itemID, locationID, stock.Quantity)
+	m.evaluateAlertRules(ctx, stock)
 
 	// トランザクション記録
 	tx := &Transaction{
-		ID:           NewTransactionID(),
+		ID:           txID,
 		Type:         TransactionTypeOutbound,
 		ItemID:       itemID,
 		FromLocation: &locationID,
 		Quantity:     quantity,
 		Reference:    reference,
+		LotNumber:    lotNumber,
+		Metadata:     transactionMetadata(ctx, ownerMetadata(ctx, unitMetadata(ctx, metadata))),
 		CreatedAt:    time.Now(),
 		CreatedBy:    m.getUserFromContext(ctx),
 	}
 
 	if err := m.storage.CreateTransaction(ctx, tx); err != nil {
-		m.logger.Error("トランザクション記録に失敗しました", zap.Error(err))
+		m.log(ctx).Error("トランザクション記録に失敗しました", zap.Error(err))
 	}
 
-	m.logger.Info("在庫削除完了",
+	m.log(ctx).Info("在庫削除完了",
 		zap.String("item_id", itemID),
 		zap.String("location_id", locationID),
 		zap.Int64("quantity", quantity),
@@ -243,72 +366,83 @@ func (m *Manager) Remove(ctx context.Context, itemID, locationID string, quantit
 
 // Transfer moves inventory between locations
 // ロケーション間で在庫を移動
-func (m *Manager) Transfer(ctx context.Context, itemID, fromLocationID, toLocationID string, quantity int64, reference string) error {
+//
+// WithLotNumber のコンテキストでは、ロットのロット在庫も移動元から移動先に移します。
+// WithOwner のコンテキストでは、所有者の委託在庫も移動元から移動先に移します。
+func (m *Manager) Transfer(ctx context.Context, itemID, fromLocationID, toLocationID string, quantity int64, reference string) (err error) {
+	ctx, finish := m.startOperation(ctx, "transfer", attrItemID.String(itemID), attrLocationID.String(fromLocationID), attrToLocation.String(toLocationID), attrQuantity.Int64(quantity), attrReference.String(reference))
+	defer finish(&err)
+
 	if quantity <= 0 {
 		return NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", quantity))
 	}
 
+	// 単位を指定した場合は数量を商品の基本単位に換算
+	ctx, quantity, err = m.toBaseQuantity(ctx, itemID, quantity)
+	if err != nil {
+		return err
+	}
+
 	if fromLocationID == toLocationID {
 		return NewValidationError("location", "移動元と移動先が同じです", fmt.Sprintf("%s -> %s", fromLocationID, toLocationID))
 	}
 
-	// 商品とロケーションの存在確認
-	if err := m.validateItemAndLocation(ctx, itemID, fromLocationID); err != nil {
-		return err
+	number := lotNumber(ctx)
+	if number != "" {
+		if err := ValidateLotNumber(number); err != nil {
+			return err
+		}
 	}
-	if err := m.validateItemAndLocation(ctx, itemID, toLocationID); err != nil {
+
+	if err := m.validateOwner(ctx); err != nil {
 		return err
 	}
 
-	// 移動元から在庫を削除
-	if err := m.Remove(ctx, itemID, fromLocationID, quantity, reference); err != nil {
+	// 商品とロケーションの存在確認
+	item, _, err := m.validateItemAndLocation(ctx, itemID, fromLocationID)
+	if err != nil {
 		return err
 	}
-
-	// 移動先に在庫を追加
-	if err := m.Add(ctx, itemID, toLocationID, quantity, reference); err != nil {
-		// ロールバック処理（移動元に戻す）
-		if rollbackErr := m.Add(ctx, itemID, fromLocationID, quantity, reference+"_ROLLBACK"); rollbackErr != nil {
-			m.logger.Error("ロールバック失敗", zap.Error(rollbackErr))
-		}
+	_, toLocation, err := m.validateItemAndLocation(ctx, itemID, toLocationID)
+	if err != nil {
 		return err
 	}
 
-	// 移動イベント発行
-	if m.publisher != nil {
-		event := ItemTransferredEvent{
-			ItemID:         itemID,
-			FromLocationID: fromLocationID,
-			ToLocationID:   toLocationID,
-			Quantity:       quantity,
-			Reference:      reference,
-			TransactionID:  NewTransactionID(),
-			Timestamp:      time.Now(),
-			UserID:         m.getUserFromContext(ctx),
-		}
-		if err := m.publisher.PublishItemTransferred(ctx, event); err != nil {
-			m.logger.Error("移動イベント発行に失敗しました", zap.Error(err))
-		}
+	// 移動元の減算・移動先の加算・移動記録を単一トランザクションで実行
+	var events *bufferedPublisher
+	var toStock *Stock
+	err = m.retryOnConflict(ctx, func() error {
+		events = &bufferedPublisher{}
+		return m.storage.WithinTx(ctx, func(txStorage Storage) (err error) {
+			txManager := m.withStorage(txStorage, events)
+			if err := txManager.enforceCapacity(ctx, toLocation, quantity); err != nil {
+				return err
+			}
+			var lot *Lot
+			if number != "" {
+				if lot, err = txStorage.GetLotByNumber(ctx, itemID, number); err != nil {
+					if errors.Is(err, ErrLotNotFound) {
+						return ErrLotNotFound
+					}
+					return NewStorageError("get_lot", "ロット取得に失敗しました", err)
+				}
+			}
+			toStock, err = txManager.transferStock(ctx, itemID, fromLocationID, toLocationID, quantity, reference, item.UnitCost, lot)
+			return err
+		})
+	})
+	if err != nil {
+		return err
 	}
 
-	// 移動トランザクション記録
-	tx := &Transaction{
-		ID:           NewTransactionID(),
-		Type:         TransactionTypeTransfer,
-		ItemID:       itemID,
-		FromLocation: &fromLocationID,
-		ToLocation:   &toLocationID,
-		Quantity:     quantity,
-		Reference:    reference,
-		CreatedAt:    time.Now(),
-		CreatedBy:    m.getUserFromContext(ctx),
+	if m.publisher != nil {
+		events.flush(ctx, m.publisher, m.logger)
 	}
 
-	if err := m.storage.CreateTransaction(ctx, tx); err != nil {
-		m.logger.Error("移動トランザクション記録に失敗しました", zap.Error(err))
-	}
+	// 移動先の過剰在庫アラートチェック
+	m.checkOverStock(ctx, toLocation, toStock)
 
-	m.logger.Info("在庫移動完了",
+	m.log(ctx).Info("在庫移動完了",
 		zap.String("item_id", itemID),
 		zap.String("from_location", fromLocationID),
 		zap.String("to_location", toLocationID),
@@ -321,88 +455,66 @@ func (m *Manager) Transfer(ctx context.Context, itemID, fromLocationID, toLocati
 
 // Adjust adjusts inventory to a specific quantity
 // 在庫を指定数量に調整
-func (m *Manager) Adjust(ctx context.Context, itemID, locationID string, newQuantity int64, reference string) error {
+//
+// WithExpectedVersion のコンテキストでは、在庫が想定したバージョンの場合のみ調整します。
+func (m *Manager) Adjust(ctx context.Context, itemID, locationID string, newQuantity int64, reference string) (err error) {
+	ctx, finish := m.startOperation(ctx, "adjust", attrItemID.String(itemID), attrLocationID.String(locationID), attrQuantity.Int64(newQuantity), attrReference.String(reference))
+	defer finish(&err)
+
 	if newQuantity < 0 && !m.config.AllowNegativeStock {
 		return NewValidationError("quantity", "負の在庫は許可されていません", fmt.Sprintf("%d", newQuantity))
 	}
 
 	// 商品とロケーションの存在確認
-	if err := m.validateItemAndLocation(ctx, itemID, locationID); err != nil {
+	item, _, err := m.validateItemAndLocation(ctx, itemID, locationID)
+	if err != nil {
 		return err
 	}
 
-	// 現在の在庫を取得
-	stock, err := m.storage.GetStock(ctx, itemID, locationID)
-	if err != nil && err != ErrStockNotFound {
-		return NewStorageError("get_stock", "在庫取得に失敗しました", err)
-	}
-
-	oldQuantity := int64(0)
-	if stock == nil {
-		// 新しい在庫記録を作成
-		stock = &Stock{
-			ItemID:     itemID,
-			LocationID: locationID,
-			Quantity:   newQuantity,
-			Reserved:   0,
-			Version:    1,
-			UpdatedAt:  time.Now(),
-			UpdatedBy:  m.getUserFromContext(ctx),
-		}
-		stock.CalculateAvailable()
-
-		if err := m.storage.CreateStock(ctx, stock); err != nil {
-			return NewStorageError("create_stock", "在庫作成に失敗しました", err)
-		}
-	} else {
-		// 既存の在庫を調整
-		oldQuantity = stock.Quantity
-		stock.Quantity = newQuantity
-		stock.Version++
-		stock.UpdatedAt = time.Now()
-		stock.UpdatedBy = m.getUserFromContext(ctx)
-		stock.CalculateAvailable()
-
-		if err := m.storage.UpdateStock(ctx, stock); err != nil {
-			return NewStorageError("update_stock", "在庫更新に失敗しました", err)
+	var oldQuantity int64
+	var stock *Stock
+	err = m.withStockLock(ctx, func(lm *Manager) (err error) {
+		if err := lm.checkAdjustment(ctx, itemID, locationID, newQuantity); err != nil {
+			return err
 		}
+		oldQuantity, stock, err = lm.setStockQuantity(ctx, itemID, locationID, newQuantity)
+		return err
+	})
+	if err != nil {
+		return err
 	}
 
+	txID := NewTransactionID()
+
 	// 調整イベント発行
 	if m.publisher != nil {
-		event := StockChangedEvent{
-			ItemID:        itemID,
-			LocationID:    locationID,
-			OldQuantity:   oldQuantity,
-			NewQuantity:   stock.Quantity,
-			ChangeType:    "adjust",
-			Reference:     reference,
-			TransactionID: NewTransactionID(),
-			Timestamp:     time.Now(),
-			UserID:        m.getUserFromContext(ctx),
-		}
+		event := m.newStockChangedEvent(ctx, stock, oldQuantity, item.UnitCost, "adjust", reference, txID)
 		if err := m.publisher.PublishStockChanged(ctx, event); err != nil {
-			m.logger.Error("調整イベント発行に失敗しました", zap.Error(err))
+			m.log(ctx).Error("調整イベント発行に失敗しました", zap.Error(err))
 		}
 	}
 
 	// 調整トランザクション記録
 	tx := &Transaction{
-		ID:         NewTransactionID(),
+		ID:         txID,
 		Type:       TransactionTypeAdjust,
 		ItemID:     itemID,
 		ToLocation: &locationID,
 		Quantity:   newQuantity - oldQuantity, // 差分を記録
 		Reference:  reference,
+		Metadata:   transactionMetadata(ctx, nil),
 		CreatedAt:  time.Now(),
 		CreatedBy:  m.getUserFromContext(ctx),
 	}
 
 	if err := m.storage.CreateTransaction(ctx, tx); err != nil {
-		m.logger.Error("調整トランザクション記録に失敗しました", zap.Error(err))
+		m.log(ctx).Error("調整トランザクション記録に失敗しました", zap.Error(err))
 	}
 
-	m.logger.Info("在庫調整完了",
+	// アラートルールチェック
+	m.evaluateAlertRules(ctx, stock)
+
+	m.log(ctx).Info("在庫調整完了",
 		zap.String("item_id", itemID),
 		zap.String("location_id", locationID),
 		zap.Int64("old_quantity", oldQuantity),
@@ -422,6 +534,10 @@ func (m *Manager) GetStock(ctx context.Context, itemID, locationID string) (*Sto
 // GetTotalStock gets total stock across all locations for an item
 // 商品の全ロケーション合計在庫を取得
 func (m *Manager) GetTotalStock(ctx context.Context, itemID string) (int64, error) {
+	if itemID == "" {
+		return 0, NewValidationError("item_id", "商品IDが指定されていません", "")
+	}
+
 	// 商品の存在確認
 	if _, err := m.storage.GetItem(ctx, itemID); err != nil {
 		if err == ErrItemNotFound {
@@ -432,11 +548,11 @@ func (m *Manager) GetTotalStock(ctx context.Context, itemID string) (int64, erro
 
 	totalStock, err := m.storage.GetTotalStockByItem(ctx, itemID)
 	if err != nil {
-		m.logger.Error("合計在庫数取得に失敗しました", zap.String("item_id", itemID), zap.Error(err))
+		m.log(ctx).Error("合計在庫数取得に失敗しました", zap.String("item_id", itemID), zap.Error(err))
 		return 0, fmt.Errorf("合計在庫数取得に失敗しました: %w", err)
 	}
 
-	m.logger.Info("総在庫数取得完了",
+	m.log(ctx).Info("総在庫数取得完了",
 		zap.String("item_id", itemID),
 		zap.Int64("total_stock", totalStock),
 	)
@@ -444,18 +560,140 @@ func (m *Manager) GetTotalStock(ctx context.Context, itemID string) (int64, erro
 	return totalStock, nil
 }
 
+// maxStockLookupKeys is the maximum number of stock records retrieved by GetStocks at once
+// GetStocks で一度に取得できる在庫記録の上限
+const maxStockLookupKeys = 500
+
+// GetStocks gets the stock records of many item and location pairs at once
+// 複数の商品とロケーションの組の在庫を一度に取得
+//
+// 結果は指定した順序（重複を除く）で、在庫記録が存在しない組は含めません。
+// 注文画面などで多数の GetStock を呼び出す代わりに使用します。
+func (m *Manager) GetStocks(ctx context.Context, keys []StockKey) ([]Stock, error) {
+	if len(keys) > maxStockLookupKeys {
+		return nil, NewValidationError("keys", fmt.Sprintf("一度に取得できる在庫は%d件までです", maxStockLookupKeys), fmt.Sprintf("%d", len(keys)))
+	}
+
+	unique := make([]StockKey, 0, len(keys))
+	seen := make(map[StockKey]bool, len(keys))
+	for _, key := range keys {
+		if key.ItemID == "" || key.LocationID == "" {
+			return nil, NewValidationError("keys", "商品IDとロケーションIDを指定してください", key.ItemID+"/"+key.LocationID)
+		}
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, key)
+		}
+	}
+	if len(unique) == 0 {
+		return make([]Stock, 0), nil
+	}
+
+	stocks, err := m.storage.GetStocks(ctx, unique)
+	if err != nil {
+		return nil, NewStorageError("get_stocks", "在庫取得に失敗しました", err)
+	}
+
+	found := make(map[StockKey]Stock, len(stocks))
+	for _, stock := range stocks {
+		found[StockKey{ItemID: stock.ItemID, LocationID: stock.LocationID}] = stock
+	}
+	result := make([]Stock, 0, len(found))
+	for _, key := range unique {
+		if stock, ok := found[key]; ok {
+			result = append(result, stock)
+		}
+	}
+	return result, nil
+}
+
+// GetSummary aggregates the whole inventory for dashboards
+// ダッシュボード向けに在庫全体を集計
+//
+// 集計はストレージの集計クエリで行い、低在庫の判定には設定の低在庫閾値を使用します。
+func (m *Manager) GetSummary(ctx context.Context) (*InventorySummary, error) {
+	summary, err := m.storage.GetInventorySummary(ctx, m.config.LowStockThreshold)
+	if err != nil {
+		return nil, NewStorageError("get_summary", "在庫の集計に失敗しました", err)
+	}
+	summary.LowStockThreshold = m.config.LowStockThreshold
+	summary.GeneratedAt = time.Now()
+	return summary, nil
+}
+
 // GetStockByLocation gets all stock at a specific location
 // 指定ロケーションのすべての在庫を取得
 func (m *Manager) GetStockByLocation(ctx context.Context, locationID string) ([]Stock, error) {
 	return m.storage.ListStockByLocation(ctx, locationID)
 }
 
+// ForEachStockByLocation streams all stock at a specific location to fn
+// 指定ロケーションのすべての在庫を1件ずつfnに渡す
+//
+// GetStockByLocationと異なり結果をスライスに展開しないため、
+// 大量のSKUを持つロケーションでもメモリ使用量が一定に保たれます。
+func (m *Manager) ForEachStockByLocation(ctx context.Context, locationID string, fn func(stock Stock) error) error {
+	if locationID == "" {
+		return NewValidationError("location_id", "ロケーションIDが指定されていません", "")
+	}
+
+	return m.storage.ForEachStockByLocation(ctx, locationID, fn)
+}
+
+// GetTransaction gets a single transaction record by ID
+// IDでトランザクション記録を取得
+func (m *Manager) GetTransaction(ctx context.Context, txID string) (*Transaction, error) {
+	if txID == "" {
+		return nil, NewValidationError("transaction_id", "トランザクションIDが指定されていません", "")
+	}
+	return m.storage.GetTransactionByID(ctx, txID)
+}
+
 // GetHistory gets transaction history for an item
 // 商品のトランザクション履歴を取得
 func (m *Manager) GetHistory(ctx context.Context, itemID string, limit int) ([]Transaction, error) {
 	return m.storage.GetTransactionHistory(ctx, itemID, limit)
 }
 
+// GetHistoryPage gets a page of transaction history using keyset pagination
+// キーセットページネーションでトランザクション履歴の1ページを取得
+//
+// afterがnilの場合は最新のレコードから取得します。返却されたNextCursorを次の呼び出しに
+// 渡すことで、同時に書き込みがあっても重複・欠落なく全履歴を辿ることができます。
+func (m *Manager) GetHistoryPage(ctx context.Context, itemID string, after *HistoryCursor, limit int) (*TransactionPage, error) {
+	if itemID == "" {
+		return nil, NewValidationError("item_id", "商品IDが指定されていません", "")
+	}
+	if after != nil && (after.AfterID == "" || after.AfterCreatedAt.IsZero()) {
+		return nil, NewValidationError("cursor", "カーソルにはafter_idとafter_created_atの両方が必要です", after.AfterID)
+	}
+
+	if limit <= 0 {
+		limit = 100 // デフォルト値
+	}
+
+	// 次ページの有無を判定するため1件多く取得
+	transactions, err := m.storage.GetTransactionHistoryAfter(ctx, itemID, after, limit+1)
+	if err != nil {
+		return nil, NewStorageError("get_transaction_history", "トランザクション履歴取得に失敗しました", err)
+	}
+
+	page := &TransactionPage{Transactions: transactions}
+	if len(transactions) > limit {
+		page.Transactions = transactions[:limit]
+		last := page.Transactions[limit-1]
+		page.NextCursor = &HistoryCursor{
+			AfterCreatedAt: last.CreatedAt,
+			AfterID:        last.ID,
+		}
+	}
+	if page.Transactions == nil {
+		page.Transactions = make([]Transaction, 0)
+	}
+
+	return page, nil
+}
+
 // GetHistoryByLocation gets transaction history for a location
 // ロケーションのトランザクション履歴を取得
 func (m *Manager) GetHistoryByLocation(ctx context.Context, locationID string, limit int) ([]Transaction, error) {
@@ -477,11 +715,11 @@ func (m *Manager) GetHistoryByLocation(ctx context.Context, locationID string, l
 
 	transactions, err := m.storage.GetTransactionHistoryByLocation(ctx, locationID, limit)
 	if err != nil {
-		m.logger.Error("ロケーション履歴取得に失敗しました", zap.String("location_id", locationID), zap.Error(err))
+		m.log(ctx).Error("ロケーション履歴取得に失敗しました", zap.String("location_id", locationID), zap.Error(err))
 		return nil, fmt.Errorf("ロケーション履歴取得に失敗しました: %w", err)
 	}
 
-	m.logger.Info("ロケーション履歴取得完了",
+	m.log(ctx).Info("ロケーション履歴取得完了",
 		zap.String("location_id", locationID),
 		zap.Int("limit", limit),
 		zap.Int("count", len(transactions)),
@@ -490,6 +728,97 @@ func (m *Manager) GetHistoryByLocation(ctx context.Context, locationID string, l
 	return transactions, nil
 }
 
+// GetHistoryByReference gets all transactions recorded with a reference number
+// 参照番号（発注書番号など）に紐づくすべてのトランザクション履歴を取得
+func (m *Manager) GetHistoryByReference(ctx context.Context, reference string) ([]Transaction, error) {
+	if reference == "" {
+		return nil, NewValidationError("reference", "参照番号が指定されていません", "")
+	}
+
+	transactions, err := m.storage.GetTransactionsByReference(ctx, reference)
+	if err != nil {
+		m.log(ctx).Error("参照番号履歴取得に失敗しました", zap.String("reference", reference), zap.Error(err))
+		return nil, fmt.Errorf("参照番号履歴取得に失敗しました: %w", err)
+	}
+
+	m.log(ctx).Info("参照番号履歴取得完了",
+		zap.String("reference", reference),
+		zap.Int("count", len(transactions)),
+	)
+
+	return transactions, nil
+}
+
+// SearchHistoryByMetadata gets transactions tagged with the given metadata key and value
+// 指定したメタデータのキーと値を持つトランザクション履歴を取得
+func (m *Manager) SearchHistoryByMetadata(ctx context.Context, key, value string, limit int) ([]Transaction, error) {
+	if key == "" {
+		return nil, NewValidationError("key", "メタデータのキーが指定されていません", "")
+	}
+
+	if limit <= 0 {
+		limit = 100 // デフォルト値
+	}
+
+	transactions, err := m.storage.SearchTransactionsByMetadata(ctx, key, value, limit)
+	if err != nil {
+		m.log(ctx).Error("メタデータ履歴検索に失敗しました", zap.String("key", key), zap.Error(err))
+		return nil, fmt.Errorf("メタデータ履歴検索に失敗しました: %w", err)
+	}
+
+	m.log(ctx).Info("メタデータ履歴検索完了",
+		zap.String("key", key),
+		zap.String("value", value),
+		zap.Int("limit", limit),
+		zap.Int("count", len(transactions)),
+	)
+
+	return transactions, nil
+}
+
+// ArchiveTransactions moves transactions created before the cutoff out of the hot table
+// 指定日時より前のトランザクションをアーカイブへ移動
+func (m *Manager) ArchiveTransactions(ctx context.Context, before time.Time) (int64, error) {
+	if before.IsZero() {
+		return 0, NewValidationError("before", "アーカイブ基準日時が指定されていません", "")
+	}
+	if before.After(time.Now()) {
+		return 0, NewValidationError("before", "アーカイブ基準日時に未来の日時は指定できません", before.Format(time.RFC3339))
+	}
+
+	archived, err := m.storage.ArchiveTransactions(ctx, before)
+	if err != nil {
+		m.log(ctx).Error("トランザクションのアーカイブに失敗しました", zap.Time("before", before), zap.Error(err))
+		return 0, fmt.Errorf("トランザクションのアーカイブに失敗しました: %w", err)
+	}
+
+	m.log(ctx).Info("トランザクションのアーカイブ完了",
+		zap.Time("before", before),
+		zap.Int64("archived", archived),
+	)
+
+	return archived, nil
+}
+
+// ApplyRetention archives transactions older than the configured retention period
+// 設定された保持期間を過ぎたトランザクションをアーカイブ
+//
+// RetentionMonthsが0以下の場合は何もせず0を返します。
+func (m *Manager) ApplyRetention(ctx context.Context) (int64, error) {
+	if m.config.RetentionMonths <= 0 {
+		return 0, nil
+	}
+	return m.ArchiveTransactions(ctx, RetentionCutoff(time.Now(), m.config.RetentionMonths))
+}
+
+// RetentionCutoff returns the start of the month that is months before now
+// 保持月数に基づくアーカイブ基準日時（nowのmonthsか月前の月初）を返す
+//
+// 月初で区切ることで、月次パーティション単位でアーカイブされるようにします。
+func RetentionCutoff(now time.Time, months int) time.Time {
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, -months, 0)
+}
+
 // GetHistoryByDateRange gets transaction history within a date range
 // 日付範囲でトランザクション履歴を取得
 func (m *Manager) GetHistoryByDateRange(ctx context.Context, itemID string, from, to time.Time) ([]Transaction, error) {
@@ -511,11 +840,11 @@ func (m *Manager) GetHistoryByDateRange(ctx context.Context, itemID string, from
 
 	transactions, err := m.storage.GetTransactionHistoryByDateRange(ctx, itemID, from, to)
 	if err != nil {
-		m.logger.Error("日付範囲履歴取得に失敗しました", zap.String("item_id", itemID), zap.Error(err))
+		m.log(ctx).Error("日付範囲履歴取得に失敗しました", zap.String("item_id", itemID), zap.Error(err))
 		return nil, fmt.Errorf("日付範囲履歴取得に失敗しました: %w", err)
 	}
 
-	m.logger.Info("日付範囲履歴取得完了",
+	m.log(ctx).Info("日付範囲履歴取得完了",
 		zap.String("item_id", itemID),
 		zap.String("from", from.Format("2006-01-02")),
 		zap.String("to", to.Format("2006-01-02")),
@@ -527,78 +856,310 @@ func (m *Manager) GetHistoryByDateRange(ctx context.Context, itemID string, from
 
 // ExecuteBatch executes a batch of inventory operations
 // バッチ在庫操作を実行
-func (m *Manager) ExecuteBatch(ctx context.Context, operations []InventoryOperation) (*BatchOperation, error) {
-	batch := &BatchOperation{
-		ID:          NewBatchID(),
-		Operations:  operations,
-		Status:      BatchStatusPending,
-		CreatedAt:   time.Now(),
-		Errors:      make([]BatchOperationError, 0),
-	}
-
-	for i, op := range operations {
-		var err error
-		switch op.Type {
-		case OperationTypeAdd:
-			err = m.Add(ctx, op.ItemID, op.LocationID, op.Quantity, op.Reference)
-		case OperationTypeRemove:
-			err = m.Remove(ctx, op.ItemID, op.LocationID, op.Quantity, op.Reference)
-		case OperationTypeTransfer:
-			if op.ToLocationID == nil {
-				err = fmt.Errorf("移動先ロケーションが指定されていません")
-			} else {
-				err = m.Transfer(ctx, op.ItemID, op.LocationID, *op.ToLocationID, op.Quantity, op.Reference)
-			}
-		case OperationTypeAdjust:
-			err = m.Adjust(ctx, op.ItemID, op.LocationID, op.Quantity, op.Reference)
-		default:
-			err = fmt.Errorf("未知の操作タイプ: %s", op.Type)
-		}
+//
+// 各操作は個別に実行され、失敗した操作があっても残りの操作は継続されます。
+// すべてを一括で適用したい場合は ExecuteBatchAtomic を使用してください。
+// ctx がキャンセルされた場合、未実行の操作は cancelled として記録されます。
+func (m *Manager) ExecuteBatch(ctx context.Context, operations []InventoryOperation) (_ *BatchOperation, err error) {
+	ctx, finish := m.startOperation(ctx, "execute_batch", attrOperations.Int(len(operations)))
+	defer finish(&err)
 
-		if err != nil {
-			batch.Errors = append(batch.Errors, BatchOperationError{
-				OperationIndex: i,
-				Error:          err.Error(),
-			})
-			batch.FailureCount++
+	batch := newBatchOperation(operations, false)
+	if err := m.storage.SaveBatchOperation(ctx, batch); err != nil {
+		return nil, NewStorageError("save_batch_operation", "バッチ操作の保存に失敗しました", err)
+	}
+
+	m.runBatch(ctx, batch, nil)
+	m.finishBatch(ctx, batch)
+	return batch, nil
+}
+
+// ExecuteBatchAtomic executes a batch of inventory operations in a single transaction
+// 単一トランザクション内でバッチ在庫操作を実行
+//
+// いずれかの操作が失敗した場合は最初のエラーで中断し、それまでの変更もすべてロールバックされます。
+// イベントはコミットが成功した後にのみ発行されます。
+func (m *Manager) ExecuteBatchAtomic(ctx context.Context, operations []InventoryOperation) (_ *BatchOperation, err error) {
+	ctx, finish := m.startOperation(ctx, "execute_batch_atomic", attrOperations.Int(len(operations)))
+	defer finish(&err)
+
+	batch := newBatchOperation(operations, true)
+	if err := m.storage.SaveBatchOperation(ctx, batch); err != nil {
+		return nil, NewStorageError("save_batch_operation", "バッチ操作の保存に失敗しました", err)
+	}
+
+	if err := m.runBatchAtomic(ctx, batch); err != nil {
+		m.saveCompletedBatch(ctx, batch)
+		return nil, err
+	}
+	m.finishBatch(ctx, batch)
+
+	m.log(ctx).Info("アトミックバッチ実行完了",
+		zap.String("batch_id", batch.ID),
+		zap.String("status", string(batch.Status)),
+		zap.Int("operations", len(operations)),
+	)
+
+	return batch, nil
+}
+
+// runBatch executes the operations of a batch one by one
+// バッチの操作を一つずつ実行
+//
+// progress が指定された場合は各操作の後に呼び出します。ctx がキャンセルされた場合は
+// 残りの操作を cancelled として中断します。
+func (m *Manager) runBatch(ctx context.Context, batch *BatchOperation, progress func(batch *BatchOperation)) {
+	for i, op := range batch.Operations {
+		if ctx.Err() != nil {
+			batch.cancelFrom(i)
+			return
+		}
+		if err := m.executeOperation(ctx, op); err != nil {
+			batch.fail(i, op, err)
 		} else {
-			batch.SuccessCount++
+			batch.succeed(i)
+		}
+		if progress != nil {
+			progress(batch)
 		}
 	}
+}
 
+// runBatchAtomic executes the operations of a batch in a single transaction
+// バッチの操作を単一トランザクション内で実行
+//
+// 操作の失敗・キャンセルはバッチの結果に記録します。トランザクション自体の失敗（コミットの失敗など）は
+// 全ての操作を rolled_back として完了させ、StorageError を返します。
+func (m *Manager) runBatchAtomic(ctx context.Context, batch *BatchOperation) error {
+	if ctx.Err() != nil {
+		batch.cancelFrom(0)
+		return nil
+	}
+	events := &bufferedPublisher{}
+
+	failedIndex, cancelled := -1, false
+	err := m.storage.WithinTx(ctx, func(txStorage Storage) error {
+		txManager := m.withStorage(txStorage, events).withoutBackorders()
+		for i, op := range batch.Operations {
+			if err := ctx.Err(); err != nil {
+				cancelled = true
+				return err
+			}
+			if err := txManager.executeOperation(ctx, op); err != nil {
+				batch.fail(i, op, err)
+				failedIndex = i
+				return err
+			}
+		}
+		return nil
+	})
+
+	switch {
+	case cancelled:
+		// 実行済みの操作もロールバックされたため、全ての操作を取り消したものとして扱う
+		batch.cancelFrom(0)
+	case failedIndex >= 0:
+		// ロールバックされたため、失敗した操作以外も適用されなかったものとして扱う
+		batch.FailureCount = len(batch.Operations)
+		for i := range batch.Results {
+			if i != failedIndex {
+				batch.Results[i].Status = OperationResultRolledBack
+			}
+		}
+	case err != nil:
+		// コミットに失敗した場合も適用されていない
+		batch.FailureCount = len(batch.Operations)
+		for i := range batch.Results {
+			batch.Results[i].Status = OperationResultRolledBack
+		}
+		batch.complete()
+		return NewStorageError("execute_batch", "バッチトランザクションの実行に失敗しました", err)
+	default:
+		for i := range batch.Operations {
+			batch.succeed(i)
+		}
+		if m.publisher != nil {
+			events.flush(ctx, m.publisher, m.logger)
+		}
+		m.allocateBatchArrivals(ctx, batch.Operations)
+	}
+	return nil
+}
+
+// finishBatch completes a batch, saves its final state and publishes batch.completed
+// バッチを完了させて最終状態を保存し、batch.completed を発行
+func (m *Manager) finishBatch(ctx context.Context, batch *BatchOperation) {
+	batch.complete()
+	// キャンセルされた場合も結果の保存・イベントの発行は行う
+	ctx = context.WithoutCancel(ctx)
+	m.saveCompletedBatch(ctx, batch)
+	m.publishEvent(ctx, EventTypeBatchCompleted, "", "", batch.completedEvent())
+}
+
+// executeOperation executes a single batch operation
+// 単一のバッチ操作を実行
+func (m *Manager) executeOperation(ctx context.Context, op InventoryOperation) error {
+	switch op.Type {
+	case OperationTypeAdd:
+		return m.Add(ctx, op.ItemID, op.LocationID, op.Quantity, op.Reference)
+	case OperationTypeRemove:
+		return m.Remove(ctx, op.ItemID, op.LocationID, op.Quantity, op.Reference)
+	case OperationTypeTransfer:
+		if op.ToLocationID == nil {
+			return fmt.Errorf("移動先ロケーションが指定されていません")
+		}
+		return m.Transfer(ctx, op.ItemID, op.LocationID, *op.ToLocationID, op.Quantity, op.Reference)
+	case OperationTypeAdjust:
+		return m.Adjust(ctx, op.ItemID, op.LocationID, op.Quantity, op.Reference)
+	default:
+		return fmt.Errorf("未知の操作タイプ: %s", op.Type)
+	}
+}
+
+// newBatchOperation creates a pending batch for the given operations
+// 指定された操作の保留中バッチを作成
+func newBatchOperation(operations []InventoryOperation, atomic bool) *BatchOperation {
+	results := make([]BatchOperationResult, len(operations))
+	for i := range results {
+		results[i] = BatchOperationResult{OperationIndex: i, Status: OperationResultPending}
+	}
+	return &BatchOperation{
+		ID:         NewBatchID(),
+		Operations: operations,
+		Status:     BatchStatusPending,
+		Atomic:     atomic,
+		CreatedAt:  time.Now(),
+		Errors:     make([]BatchOperationError, 0),
+		Results:    results,
+	}
+}
+
+// succeed records the success of the operation at index
+// 指定インデックスの操作の成功を記録
+func (b *BatchOperation) succeed(index int) {
+	b.Results[index].Status = OperationResultSucceeded
+	b.SuccessCount++
+	b.updateProgress()
+}
+
+// fail records the failure of the operation at index
+// 指定インデックスの操作の失敗を記録
+func (b *BatchOperation) fail(index int, op InventoryOperation, err error) {
+	b.Results[index].Status = OperationResultFailed
+	b.Results[index].Error = err.Error()
+	b.Errors = append(b.Errors, newBatchOperationError(index, op, err))
+	b.FailureCount++
+	b.updateProgress()
+}
+
+// cancelFrom records the operations from index onwards as cancelled
+// 指定インデックス以降の操作を取り消したものとして記録
+func (b *BatchOperation) cancelFrom(index int) {
+	for i := index; i < len(b.Results); i++ {
+		if b.Results[i].Status == OperationResultSucceeded {
+			b.SuccessCount--
+		}
+		b.Results[i] = BatchOperationResult{OperationIndex: i, Status: OperationResultCancelled}
+	}
+	b.CancelledCount = len(b.Results) - index
+	b.updateProgress()
+}
+
+// updateProgress sets the percentage of operations that have finished
+// 終了した操作の割合（パーセント）を設定
+func (b *BatchOperation) updateProgress() {
+	if len(b.Results) == 0 {
+		b.Progress = 100
+		return
+	}
+	finished := 0
+	for _, result := range b.Results {
+		if result.Status != OperationResultPending {
+			finished++
+		}
+	}
+	b.Progress = finished * 100 / len(b.Results)
+}
+
+// saveCompletedBatch persists the final state of a batch
+// バッチの最終状態を保存
+//
+// 操作は既に適用済みのため、保存に失敗してもバッチの結果は呼び出し元に返します
+// （エラーを返すと再実行で操作が二重に適用されるため）。
+func (m *Manager) saveCompletedBatch(ctx context.Context, batch *BatchOperation) {
+	if err := m.storage.SaveBatchOperation(ctx, batch); err != nil {
+		m.log(ctx).Error("バッチ操作の結果の保存に失敗しました",
+			zap.String("batch_id", batch.ID),
+			zap.String("status", string(batch.Status)),
+			zap.Error(err),
+		)
+	}
+}
+
+// complete sets the completion time and final status of the batch
+// バッチの完了日時と最終ステータスを設定
+func (b *BatchOperation) complete() {
 	now := time.Now()
-	batch.CompletedAt = &now
-	
-	if batch.FailureCount > 0 {
-		batch.Status = BatchStatusFailed
-	} else {
-		batch.Status = BatchStatusCompleted
+	b.CompletedAt = &now
+
+	switch {
+	case b.CancelledCount > 0:
+		b.Status = BatchStatusCancelled
+	case b.FailureCount > 0:
+		b.Status = BatchStatusFailed
+	default:
+		b.Status = BatchStatusCompleted
 	}
+	b.updateProgress()
+}
 
-	return batch, nil
+// completedEvent returns the batch completed event payload for the batch
+// バッチの完了イベントの内容を返す
+func (b *BatchOperation) completedEvent() BatchCompletedEvent {
+	return BatchCompletedEvent{
+		BatchID:      b.ID,
+		Status:       b.Status,
+		Atomic:       b.Atomic,
+		TotalCount:   len(b.Operations),
+		SuccessCount: b.SuccessCount,
+		FailureCount: b.FailureCount,
+		Errors:       b.Errors,
+		CreatedAt:    b.CreatedAt,
+		CompletedAt:  b.CompletedAt,
+	}
+}
+
+// newBatchOperationError records the failure of the operation at index
+// 指定インデックスの操作の失敗を記録
+func newBatchOperationError(index int, op InventoryOperation, err error) BatchOperationError {
+	return BatchOperationError{
+		OperationIndex: index,
+		Type:           op.Type,
+		ItemID:         op.ItemID,
+		LocationID:     op.LocationID,
+		Error:          err.Error(),
+	}
 }
 
 // GetBatchStatus gets the status of a batch operation
 // バッチ操作のステータスを取得
+//
+// 実行中のバッチは pending を、完了したバッチは操作ごとの結果（一部の失敗を含む）を返します。
+// 存在しない場合は ErrBatchNotFound を返します。
 func (m *Manager) GetBatchStatus(ctx context.Context, batchID string) (*BatchOperation, error) {
 	if batchID == "" {
 		return nil, NewValidationError("batch_id", "バッチIDが指定されていません", "")
 	}
 
-	// TODO: 実際の実装では、バッチ操作の状態をストレージに永続化し、
-	// ここで取得する必要がある。現在は簡易実装として固定値を返す。
-	batch := &BatchOperation{
-		ID:           batchID,
-		Operations:   make([]InventoryOperation, 0),
-		Status:       BatchStatusCompleted,
-		SuccessCount: 0,
-		FailureCount: 0,
-		Errors:       make([]BatchOperationError, 0),
-		CreatedAt:    time.Now().Add(-time.Hour), // 1時間前に作成されたと仮定
-		CompletedAt:  &[]time.Time{time.Now()}[0],
+	batch, err := m.storage.GetBatchOperation(ctx, batchID)
+	if err != nil {
+		if errors.Is(err, ErrBatchNotFound) {
+			return nil, ErrBatchNotFound
+		}
+		return nil, NewStorageError("get_batch_operation", "バッチ操作の取得に失敗しました", err)
 	}
 
-	m.logger.Info("バッチステータス取得完了",
+	m.log(ctx).Info("バッチステータス取得完了",
 		zap.String("batch_id", batchID),
 		zap.String("status", string(batch.Status)),
 	)
@@ -608,116 +1169,907 @@ func (m *Manager) GetBatchStatus(ctx context.Context, batchID string) (*BatchOpe
 
 // Reserve reserves inventory
 // 在庫を予約
-func (m *Manager) Reserve(ctx context.Context, itemID, locationID string, quantity int64, reference string) error {
+//
+// 予約は ReservationTTL の有効期限付き（0の場合は期限なし）で記録されます。
+// WithBackorder のコンテキストでは、利用可能数が不足する場合にバックオーダーを作成します。
+func (m *Manager) Reserve(ctx context.Context, itemID, locationID string, quantity int64, reference string) (err error) {
+	ctx, finish := m.startOperation(ctx, "reserve", attrItemID.String(itemID), attrLocationID.String(locationID), attrQuantity.Int64(quantity), attrReference.String(reference))
+	defer finish(&err)
+
+	reservation := &Reservation{
+		ItemID:     itemID,
+		LocationID: locationID,
+		Quantity:   quantity,
+		Reference:  reference,
+	}
+	if m.config.ReservationTTL > 0 {
+		expiresAt := time.Now().Add(m.config.ReservationTTL)
+		reservation.ExpiresAt = &expiresAt
+	}
+	if err := m.reserve(ctx, reservation); err != nil {
+		return m.backorderOnShortage(ctx, itemID, locationID, quantity, reference, err)
+	}
+	return nil
+}
+
+// ReleaseReservation releases reserved inventory
+// 予約された在庫を解除
+//
+// 参照番号が一致する予約から順に、有効な予約の記録も解除します。
+func (m *Manager) ReleaseReservation(ctx context.Context, itemID, locationID string, quantity int64, reference string) (err error) {
+	ctx, finish := m.startOperation(ctx, "release_reservation", attrItemID.String(itemID), attrLocationID.String(locationID), attrQuantity.Int64(quantity), attrReference.String(reference))
+	defer finish(&err)
+
 	if quantity <= 0 {
 		return NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", quantity))
 	}
 
-	// 現在の在庫を取得
-	stock, err := m.storage.GetStock(ctx, itemID, locationID)
-	if err != nil {
-		return NewStorageError("get_stock", "在庫取得に失敗しました", err)
-	}
+	var (
+		updated *Stock
+		tx      *Transaction
+	)
+	err = m.withReservationTx(ctx, func(lm *Manager) error {
+		// 現在の在庫を取得
+		stock, err := lm.getStockForWrite(ctx, itemID, locationID)
+		if err != nil {
+			return NewStorageError("get_stock", "在庫取得に失敗しました", err)
+		}
 
-	// 予約可能量チェック
-	if stock.Available < quantity {
-		return ErrInsufficientStock
-	}
+		// 予約量チェック
+		if stock.Reserved < quantity {
+			return ErrInsufficientReservation
+		}
 
-	// 予約量更新
-	stock.Reserved += quantity
-	stock.Version++
-	stock.UpdatedAt = time.Now()
-	stock.UpdatedBy = m.getUserFromContext(ctx)
-	stock.CalculateAvailable()
+		// 予約量更新
+		stock.Reserved -= quantity
+		stock.Version++
+		stock.UpdatedAt = time.Now()
+		stock.UpdatedBy = lm.getUserFromContext(ctx)
+		stock.CalculateAvailable()
 
-	if err := m.storage.UpdateStock(ctx, stock); err != nil {
-		return NewStorageError("update_stock", "在庫更新に失敗しました", err)
+		if err := lm.releaseReservedQuantity(ctx, itemID, locationID, quantity, reference, stock.UpdatedAt); err != nil {
+			return err
+		}
+		if err := lm.storage.UpdateStock(ctx, stock); err != nil {
+			return NewStorageError("update_stock", "在庫更新に失敗しました", err)
+		}
+		// 複数の予約から解除する場合があるため、予約IDは記録しない
+		recorded, err := lm.recordReservationTransaction(ctx, TransactionTypeRelease, "", "", itemID, locationID, quantity, reference, stock.UpdatedAt)
+		if err != nil {
+			return err
+		}
+		updated, tx = stock, recorded
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	m.logger.Info("在庫予約完了",
+	m.log(ctx).Info("在庫予約解除完了",
 		zap.String("item_id", itemID),
 		zap.String("location_id", locationID),
 		zap.Int64("quantity", quantity),
 		zap.String("reference", reference),
+		zap.String("transaction_id", tx.ID),
 	)
 
+	m.publishReservationStockChanged(ctx, updated, "release", tx)
+	m.publishEvent(ctx, EventTypeReservationReleased, itemID, locationID, ReservationEvent{
+		TransactionID: tx.ID,
+		ItemID:        itemID,
+		LocationID:    locationID,
+		Quantity:      quantity,
+		Reserved:      updated.Reserved,
+		Available:     updated.Available,
+		Reference:     reference,
+		UserID:        updated.UpdatedBy,
+	})
+
 	return nil
 }
 
-// ReleaseReservation releases reserved inventory
-// 予約された在庫を解除
-func (m *Manager) ReleaseReservation(ctx context.Context, itemID, locationID string, quantity int64, reference string) error {
-	if quantity <= 0 {
-		return NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", quantity))
+// GetAlerts gets active alerts for a location
+// ロケーションのアクティブアラートを取得
+func (m *Manager) GetAlerts(ctx context.Context, locationID string) ([]StockAlert, error) {
+	return m.storage.GetActiveAlerts(ctx, locationID)
+}
+
+// 商品管理
+
+// CreateItem validates and creates a new item
+// 商品を検証して作成
+//
+// 作成日時・更新日時が未設定の場合は現在時刻を設定し、作成後に item.created を発行します。
+// 基本単位が未設定の場合は each とし、登録されていない単位の場合は ErrUnitNotFound を返します。
+func (m *Manager) CreateItem(ctx context.Context, item *Item) error {
+	if err := ValidateItem(item); err != nil {
+		return err
 	}
+	if item.BaseUoM == "" {
+		item.BaseUoM = UoMEach
+	}
+	if err := m.checkBaseUoM(ctx, item, nil); err != nil {
+		return err
+	}
+	stampCreated(&item.CreatedAt, &item.UpdatedAt)
+	if err := m.storage.CreateItem(ctx, item); err != nil {
+		return err
+	}
+	m.publishEvent(ctx, EventTypeItemCreated, item.ID, "", item)
+	return nil
+}
 
-	// 現在の在庫を取得
-	stock, err := m.storage.GetStock(ctx, itemID, locationID)
+// GetItem gets an item by ID
+// IDで商品を取得
+func (m *Manager) GetItem(ctx context.Context, itemID string) (*Item, error) {
+	if err := ValidateItemID(itemID); err != nil {
+		return nil, err
+	}
+	return m.storage.GetItem(ctx, itemID)
+}
+
+// UpdateItem validates and updates an existing item
+// 既存の商品を検証して更新
+//
+// 更新日時は現在時刻に更新し、更新後に item.updated を発行します。
+// 基本単位が未設定の場合は現在の基本単位を維持し、在庫がある商品の基本単位の変更は拒否します。
+func (m *Manager) UpdateItem(ctx context.Context, item *Item) error {
+	if err := ValidateItem(item); err != nil {
+		return err
+	}
+	existing, err := m.storage.GetItem(ctx, item.ID)
 	if err != nil {
-		return NewStorageError("get_stock", "在庫取得に失敗しました", err)
+		return err
+	}
+	if item.BaseUoM == "" {
+		item.BaseUoM = baseUoM(existing)
+	}
+	if err := m.checkBaseUoM(ctx, item, existing); err != nil {
+		return err
+	}
+	item.UpdatedAt = time.Now().Truncate(time.Microsecond)
+	if err := m.storage.UpdateItem(ctx, item); err != nil {
+		return err
 	}
+	m.publishEvent(ctx, EventTypeItemUpdated, item.ID, "", item)
+	return nil
+}
 
-	// 予約量チェック
-	if stock.Reserved < quantity {
-		return ErrInsufficientReservation
+// DeleteItem deletes an item
+// 商品を削除
+func (m *Manager) DeleteItem(ctx context.Context, itemID string) error {
+	if err := ValidateItemID(itemID); err != nil {
+		return err
 	}
+	if err := m.storage.DeleteItem(ctx, itemID); err != nil {
+		return err
+	}
+	m.publishEvent(ctx, EventTypeItemDeleted, itemID, "", EntityDeletedEvent{ID: itemID})
+	return nil
+}
 
-	// 予約量更新
-	stock.Reserved -= quantity
-	stock.Version++
-	stock.UpdatedAt = time.Now()
-	stock.UpdatedBy = m.getUserFromContext(ctx)
-	stock.CalculateAvailable()
+// ListItems lists items with pagination
+// ページネーション付きで商品一覧を取得
+func (m *Manager) ListItems(ctx context.Context, offset, limit int) ([]Item, error) {
+	if err := validateOffsetLimit(offset, limit); err != nil {
+		return nil, err
+	}
+	return m.storage.ListItems(ctx, offset, limit)
+}
 
-	if err := m.storage.UpdateStock(ctx, stock); err != nil {
-		return NewStorageError("update_stock", "在庫更新に失敗しました", err)
+// SearchItems searches items by query string
+// クエリ文字列で商品を検索
+func (m *Manager) SearchItems(ctx context.Context, query string) ([]Item, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, NewValidationError("query", "検索クエリが空です", query)
 	}
+	return m.storage.SearchItems(ctx, query)
+}
 
-	m.logger.Info("在庫予約解除完了",
-		zap.String("item_id", itemID),
-		zap.String("location_id", locationID),
-		zap.Int64("quantity", quantity),
+// ロケーション管理
+
+// CreateLocation validates and creates a new location
+// ロケーションを検証して作成
+//
+// 作成日時・更新日時が未設定の場合は現在時刻を設定し、作成後に location.created を発行します。
+func (m *Manager) CreateLocation(ctx context.Context, location *Location) error {
+	if err := ValidateLocation(location); err != nil {
+		return err
+	}
+	stampCreated(&location.CreatedAt, &location.UpdatedAt)
+	if err := m.storage.CreateLocation(ctx, location); err != nil {
+		return err
+	}
+	m.publishEvent(ctx, EventTypeLocationCreated, "", location.ID, location)
+	return nil
+}
+
+// GetLocation gets a location by ID
+// IDでロケーションを取得
+func (m *Manager) GetLocation(ctx context.Context, locationID string) (*Location, error) {
+	if err := ValidateLocationID(locationID); err != nil {
+		return nil, err
+	}
+	return m.storage.GetLocation(ctx, locationID)
+}
+
+// UpdateLocation validates and updates an existing location
+// 既存のロケーションを検証して更新
+//
+// 更新日時は現在時刻に更新し、更新後に location.updated を発行します。
+func (m *Manager) UpdateLocation(ctx context.Context, location *Location) error {
+	if err := ValidateLocation(location); err != nil {
+		return err
+	}
+	location.UpdatedAt = time.Now().Truncate(time.Microsecond)
+	if err := m.storage.UpdateLocation(ctx, location); err != nil {
+		return err
+	}
+	m.publishEvent(ctx, EventTypeLocationUpdated, "", location.ID, location)
+	return nil
+}
+
+// DeleteLocation deletes a location
+// ロケーションを削除
+func (m *Manager) DeleteLocation(ctx context.Context, locationID string) error {
+	if err := ValidateLocationID(locationID); err != nil {
+		return err
+	}
+	if err := m.storage.DeleteLocation(ctx, locationID); err != nil {
+		return err
+	}
+	m.publishEvent(ctx, EventTypeLocationDeleted, "", locationID, EntityDeletedEvent{ID: locationID})
+	return nil
+}
+
+// ListLocations lists locations with pagination
+// ページネーション付きでロケーション一覧を取得
+func (m *Manager) ListLocations(ctx context.Context, offset, limit int) ([]Location, error) {
+	if err := validateOffsetLimit(offset, limit); err != nil {
+		return nil, err
+	}
+	return m.storage.ListLocations(ctx, offset, limit)
+}
+
+// stampCreated sets the creation and update times of a new record when they are unset
+// 新しいレコードの作成日時・更新日時が未設定の場合に現在時刻を設定
+//
+// ETag と比較できるよう、PostgreSQL の精度（マイクロ秒）に切り捨てます。
+func stampCreated(createdAt, updatedAt *time.Time) {
+	now := time.Now().Truncate(time.Microsecond)
+	if createdAt.IsZero() {
+		*createdAt = now
+	}
+	if updatedAt.IsZero() {
+		*updatedAt = *createdAt
+	}
+}
+
+// validateOffsetLimit validates the offset and limit of a list
+// 一覧のオフセット・取得件数を検証
+func validateOffsetLimit(offset, limit int) error {
+	if offset < 0 {
+		return NewValidationError("offset", "オフセットは0以上で指定してください", strconv.Itoa(offset))
+	}
+	if limit <= 0 {
+		return NewValidationError("limit", "取得件数は1以上で指定してください", strconv.Itoa(limit))
+	}
+	return nil
+}
+
+// ロット管理
+
+// CreateLot creates a new lot
+// 新しいロットを作成
+func (m *Manager) CreateLot(ctx context.Context, lot *Lot) error {
+	if err := m.storage.CreateLot(ctx, lot); err != nil {
+		return err
+	}
+	m.publishEvent(ctx, EventTypeLotCreated, lot.ItemID, "", lot)
+	return nil
+}
+
+// GetLot gets a lot by ID
+// IDでロットを取得
+func (m *Manager) GetLot(ctx context.Context, lotID string) (*Lot, error) {
+	return m.storage.GetLot(ctx, lotID)
+}
+
+// UpdateLot updates an existing lot
+// 既存のロットを更新
+func (m *Manager) UpdateLot(ctx context.Context, lot *Lot) error {
+	if lot.Quantity < 0 {
+		return NewValidationError("quantity", "ロット数量は0以上である必要があります", fmt.Sprintf("%d", lot.Quantity))
+	}
+	return m.storage.UpdateLot(ctx, lot)
+}
+
+// DeleteLot deletes a lot
+// ロットを削除
+func (m *Manager) DeleteLot(ctx context.Context, lotID string) error {
+	return m.storage.DeleteLot(ctx, lotID)
+}
+
+// AdjustLotQuantity adds delta to a lot's quantity and records an adjustment transaction
+// ロットの数量をdelta分だけ増減し、調整トランザクションを記録
+//
+// 数量の更新とトランザクション記録は同一のストレージトランザクションで実行されます。
+func (m *Manager) AdjustLotQuantity(ctx context.Context, lotID string, delta int64, reference string) (*Lot, error) {
+	if delta == 0 {
+		return nil, NewValidationError("delta", "調整数量は0以外である必要があります", "0")
+	}
+
+	var lot *Lot
+	err := m.storage.WithinTx(ctx, func(txStorage Storage) error {
+		adjusted, err := txStorage.AdjustLotQuantity(ctx, lotID, delta)
+		if err != nil {
+			return err
+		}
+
+		lotNumber := adjusted.Number
+		tx := &Transaction{
+			ID:        NewTransactionID(),
+			Type:      TransactionTypeAdjust,
+			ItemID:    adjusted.ItemID,
+			Quantity:  delta,
+			Reference: reference,
+			LotNumber: &lotNumber,
+			Metadata:  transactionMetadata(ctx, map[string]string{"lot_id": lotID}),
+			CreatedAt: time.Now(),
+			CreatedBy: m.getUserFromContext(ctx),
+		}
+		if err := txStorage.CreateTransaction(ctx, tx); err != nil {
+			return NewStorageError("create_transaction", "ロット調整トランザクション記録に失敗しました", err)
+		}
+
+		lot = adjusted
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.log(ctx).Info("ロット数量調整完了",
+		zap.String("lot_id", lotID),
+		zap.String("item_id", lot.ItemID),
+		zap.Int64("delta", delta),
+		zap.Int64("new_quantity", lot.Quantity),
 		zap.String("reference", reference),
 	)
 
-	return nil
+	return lot, nil
 }
 
-// GetAlerts gets active alerts for a location
-// ロケーションのアクティブアラートを取得
-func (m *Manager) GetAlerts(ctx context.Context, locationID string) ([]StockAlert, error) {
-	return m.storage.GetActiveAlerts(ctx, locationID)
+// GetLotsByItem gets all lots for an item
+// 商品のすべてのロットを取得
+func (m *Manager) GetLotsByItem(ctx context.Context, itemID string) ([]Lot, error) {
+	return m.storage.GetLotsByItem(ctx, itemID)
 }
 
-// ResolveAlert resolves an alert
-// アラートを解決
-func (m *Manager) ResolveAlert(ctx context.Context, alertID string) error {
-	return m.storage.ResolveAlert(ctx, alertID)
+// GetExpiringLots gets lots expiring within the given duration
+// 指定期間内に期限切れになるロットを取得
+func (m *Manager) GetExpiringLots(ctx context.Context, within time.Duration) ([]Lot, error) {
+	if within <= 0 {
+		return nil, NewValidationError("within", "期間は正の値である必要があります", within.String())
+	}
+	return m.storage.GetExpiringLots(ctx, within)
+}
+
+// NotifyExpiringLots publishes a lot expiring event for each lot expiring within the given duration
+// 指定期間内に期限切れになるロットごとに期限切れ間近イベントを発行
+//
+// 発行したイベント数を返します。定期実行のジョブから呼び出すことを想定しています。
+func (m *Manager) NotifyExpiringLots(ctx context.Context, within time.Duration) (int, error) {
+	lots, err := m.GetExpiringLots(ctx, within)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range lots {
+		m.publishEvent(ctx, EventTypeLotExpiring, lots[i].ItemID, "", lots[i])
+	}
+
+	m.log(ctx).Info("期限切れ間近ロット通知完了",
+		zap.Duration("within", within),
+		zap.Int("count", len(lots)),
+	)
+
+	return len(lots), nil
+}
+
+// GetExpiredLots gets lots that have already expired
+// 既に期限切れのロットを取得
+func (m *Manager) GetExpiredLots(ctx context.Context) ([]Lot, error) {
+	return m.storage.GetExpiredLots(ctx)
 }
 
 // ヘルパーメソッド
 
-// validateItemAndLocation validates that item and location exist
-// 商品とロケーションの存在を確認
-func (m *Manager) validateItemAndLocation(ctx context.Context, itemID, locationID string) error {
+// transferStock moves stock between locations using the manager's current storage and returns the destination stock
+// 現在のストレージを使用してロケーション間で在庫を移動し、移動先の在庫を返す（トランザクション内で呼び出すこと）
+//
+// lot を指定した場合はロット在庫も移動し、トランザクションとイベントにロットを記録します。
+// WithOwner のコンテキストでは所有者の委託在庫も移動します。
+func (m *Manager) transferStock(ctx context.Context, itemID, fromLocationID, toLocationID string, quantity int64, reference string, unitCost float64, lot *Lot) (*Stock, error) {
+	// 悲観的ロックの場合、逆方向の移動とのデッドロックを避けるためロケーションID順に行ロックを取得
+	if m.config.LockingStrategy == LockingStrategyPessimistic {
+		first, second := fromLocationID, toLocationID
+		if second < first {
+			first, second = second, first
+		}
+		for _, locationID := range []string{first, second} {
+			if _, err := m.storage.GetStockForUpdate(ctx, itemID, locationID); err != nil && err != ErrStockNotFound {
+				return nil, NewStorageError("get_stock", "在庫取得に失敗しました", err)
+			}
+		}
+	}
+
+	fromOld, fromStock, err := m.decreaseStock(ctx, itemID, fromLocationID, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	toOld, toStock, err := m.increaseStock(ctx, itemID, toLocationID, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	if lot != nil {
+		if err := m.moveLotStock(ctx, lot, fromLocationID, toLocationID, quantity); err != nil {
+			return nil, err
+		}
+	}
+
+	if stockOwner(ctx) != "" {
+		if err := m.adjustConsignment(ctx, itemID, fromLocationID, -quantity); err != nil {
+			return nil, err
+		}
+		if err := m.adjustConsignment(ctx, itemID, toLocationID, quantity); err != nil {
+			return nil, err
+		}
+	}
+
+	// 移動トランザクション記録（記録に失敗した場合は移動全体をロールバック）
+	tx := &Transaction{
+		ID:           NewTransactionID(),
+		Type:         TransactionTypeTransfer,
+		ItemID:       itemID,
+		FromLocation: &fromLocationID,
+		ToLocation:   &toLocationID,
+		Quantity:     quantity,
+		Reference:    reference,
+		Metadata:     transactionMetadata(ctx, ownerMetadata(ctx, unitMetadata(ctx, nil))),
+		CreatedAt:    time.Now(),
+		CreatedBy:    m.getUserFromContext(ctx),
+	}
+	if lot != nil {
+		tx.LotNumber = &lot.Number
+	}
+
+	if err := m.storage.CreateTransaction(ctx, tx); err != nil {
+		return nil, NewStorageError("create_transaction", "移動トランザクション記録に失敗しました", err)
+	}
+
+	// 移動イベント発行
+	if m.publisher != nil {
+		for _, change := range []struct {
+			stock  *Stock
+			oldQty int64
+		}{
+			{fromStock, fromOld},
+			{toStock, toOld},
+		} {
+			event := m.newStockChangedEvent(ctx, change.stock, change.oldQty, unitCost, "transfer", reference, tx.ID)
+			if lot != nil {
+				event.LotID, event.LotNumber = lot.ID, lot.Number
+			}
+			if err := m.publisher.PublishStockChanged(ctx, event); err != nil {
+				m.log(ctx).Error("イベント発行に失敗しました", zap.Error(err))
+			}
+		}
+
+		event := ItemTransferredEvent{
+			ItemID:         itemID,
+			FromLocationID: fromLocationID,
+			ToLocationID:   toLocationID,
+			Quantity:       quantity,
+			Reference:      reference,
+			TransactionID:  tx.ID,
+			Timestamp:      time.Now(),
+			UserID:         m.getUserFromContext(ctx),
+		}
+		if err := m.publisher.PublishItemTransferred(ctx, event); err != nil {
+			m.log(ctx).Error("移動イベント発行に失敗しました", zap.Error(err))
+		}
+	}
+
+	// 低在庫アラート・アラートルールのチェック
+	m.checkLowStock(ctx, itemID, fromLocationID, fromStock.Quantity)
+	m.evaluateAlertRules(ctx, fromStock)
+	m.evaluateAlertRules(ctx, toStock)
+
+	return toStock, nil
+}
+
+// increaseStock adds quantity to a stock record, creating it if needed
+// 在庫記録に数量を加算（存在しない場合は作成）し、変更前の数量と更新後の在庫を返す
+func (m *Manager) increaseStock(ctx context.Context, itemID, locationID string, quantity int64) (int64, *Stock, error) {
+	stock, err := m.getStockForWrite(ctx, itemID, locationID)
+	if err != nil && err != ErrStockNotFound {
+		return 0, nil, NewStorageError("get_stock", "在庫取得に失敗しました", err)
+	}
+
+	if stock == nil {
+		// 新しい在庫記録を作成
+		stock = &Stock{
+			ItemID:     itemID,
+			LocationID: locationID,
+			Quantity:   quantity,
+			Reserved:   0,
+			Version:    1,
+			UpdatedAt:  time.Now(),
+			UpdatedBy:  m.getUserFromContext(ctx),
+		}
+		stock.CalculateAvailable()
+
+		if err := m.storage.CreateStock(ctx, stock); err != nil {
+			return 0, nil, NewStorageError("create_stock", "在庫作成に失敗しました", err)
+		}
+		return 0, stock, nil
+	}
+
+	// 既存の在庫を更新
+	oldQuantity := stock.Quantity
+	stock.Quantity += quantity
+	stock.Version++
+	stock.UpdatedAt = time.Now()
+	stock.UpdatedBy = m.getUserFromContext(ctx)
+	stock.CalculateAvailable()
+
+	if err := m.storage.UpdateStock(ctx, stock); err != nil {
+		return 0, nil, NewStorageError("update_stock", "在庫更新に失敗しました", err)
+	}
+
+	return oldQuantity, stock, nil
+}
+
+// decreaseStock subtracts quantity from a stock record after checking availability
+// 利用可能数を確認した上で在庫記録から数量を減算し、変更前の数量と更新後の在庫を返す
+func (m *Manager) decreaseStock(ctx context.Context, itemID, locationID string, quantity int64) (int64, *Stock, error) {
+	stock, err := m.getStockForWrite(ctx, itemID, locationID)
+	if err != nil {
+		if err == ErrStockNotFound {
+			return 0, nil, ErrInsufficientStock
+		}
+		return 0, nil, NewStorageError("get_stock", "在庫取得に失敗しました", err)
+	}
+
+	// 在庫不足チェック
+	if stock.Available < quantity {
+		return 0, nil, ErrInsufficientStock
+	}
+	// 所有者を指定しない場合は委託在庫を減らさない
+	if err := m.checkOwnedStock(ctx, stock, quantity); err != nil {
+		return 0, nil, err
+	}
+	// 販売チャネルに確保された数量を減らさない
+	if err := m.checkUnearmarkedStock(ctx, stock, quantity); err != nil {
+		return 0, nil, err
+	}
+
+	// 在庫更新
+	oldQuantity := stock.Quantity
+	stock.Quantity -= quantity
+	stock.Version++
+	stock.UpdatedAt = time.Now()
+	stock.UpdatedBy = m.getUserFromContext(ctx)
+	stock.CalculateAvailable()
+
+	// 負の在庫チェック
+	if !m.config.AllowNegativeStock && stock.Quantity < 0 {
+		return 0, nil, NewBusinessRuleError("negative_stock", "負の在庫は許可されていません", fmt.Sprintf("商品ID: %s, ロケーション: %s", itemID, locationID))
+	}
+
+	if err := m.storage.UpdateStock(ctx, stock); err != nil {
+		return 0, nil, NewStorageError("update_stock", "在庫更新に失敗しました", err)
+	}
+
+	return oldQuantity, stock, nil
+}
+
+// validateItemAndLocation validates that item and location exist and returns them
+// 商品とロケーションの存在を確認し、商品とロケーションを返す
+func (m *Manager) validateItemAndLocation(ctx context.Context, itemID, locationID string) (item *Item, location *Location, err error) {
+	ctx, span := startSpan(ctx, "inventory.validate", attrItemID.String(itemID), attrLocationID.String(locationID))
+	defer func() { endSpan(span, err) }()
+
 	// 商品の存在確認
-	if _, err := m.storage.GetItem(ctx, itemID); err != nil {
+	item, err = m.storage.GetItem(ctx, itemID)
+	if err != nil {
 		if err == ErrItemNotFound {
-			return ErrItemNotFound
+			return nil, nil, ErrItemNotFound
 		}
-		return NewStorageError("get_item", "商品取得に失敗しました", err)
+		return nil, nil, NewStorageError("get_item", "商品取得に失敗しました", err)
 	}
 
 	// ロケーションの存在確認
-	if _, err := m.storage.GetLocation(ctx, locationID); err != nil {
+	location, err = m.storage.GetLocation(ctx, locationID)
+	if err != nil {
 		if err == ErrLocationNotFound {
-			return ErrLocationNotFound
+			return nil, nil, ErrLocationNotFound
 		}
-		return NewStorageError("get_location", "ロケーション取得に失敗しました", err)
+		return nil, nil, NewStorageError("get_location", "ロケーション取得に失敗しました", err)
 	}
 
-	return nil
+	return item, location, nil
+}
+
+// newStockChangedEvent builds the stock changed event of an operation from the updated stock
+// 更新後の在庫から在庫操作の在庫変更イベントを作成
+//
+// 単価が0以下の商品では単価を省略します。
+func (m *Manager) newStockChangedEvent(ctx context.Context, stock *Stock, oldQuantity int64, unitCost float64, changeType, reference, txID string) StockChangedEvent {
+	available := stock.Available
+	event := StockChangedEvent{
+		ItemID:        stock.ItemID,
+		LocationID:    stock.LocationID,
+		OldQuantity:   oldQuantity,
+		NewQuantity:   stock.Quantity,
+		ChangeType:    changeType,
+		Reference:     reference,
+		TransactionID: txID,
+		Timestamp:     time.Now(),
+		UserID:        m.getUserFromContext(ctx),
+		Delta:         stock.Quantity - oldQuantity,
+		Available:     &available,
+	}
+	if unitCost > 0 {
+		event.UnitCost = &unitCost
+	}
+	return event
+}
+
+// checkAdjustment verifies that adjusting a stock record to newQuantity reduces only stock an unowned decrease may consume
+// 在庫記録を newQuantity に調整して減る数量が、所有者を指定しない減算で消費できる在庫（委託在庫・販売チャネルの確保を除く）の範囲内であることを確認
+func (m *Manager) checkAdjustment(ctx context.Context, itemID, locationID string, newQuantity int64) error {
+	stock, err := m.getStockForWrite(ctx, itemID, locationID)
+	if err != nil {
+		if err == ErrStockNotFound {
+			return nil
+		}
+		return NewStorageError("get_stock", "在庫取得に失敗しました", err)
+	}
+	if err := m.checkOwnedStock(ctx, stock, stock.Quantity-newQuantity); err != nil {
+		return err
+	}
+	return m.checkUnearmarkedStock(ctx, stock, stock.Quantity-newQuantity)
+}
+
+// setStockQuantity sets a stock record to an absolute quantity, creating it if needed
+// 在庫記録を指定数量に設定（存在しない場合は作成）し、変更前の数量と更新後の在庫を返す
+func (m *Manager) setStockQuantity(ctx context.Context, itemID, locationID string, newQuantity int64) (int64, *Stock, error) {
+	stock, err := m.getStockForWrite(ctx, itemID, locationID)
+	if err != nil && err != ErrStockNotFound {
+		return 0, nil, NewStorageError("get_stock", "在庫取得に失敗しました", err)
+	}
+
+	if stock == nil {
+		if _, ok := expectedVersion(ctx); ok {
+			// 想定したバージョンの在庫が既に存在しない
+			return 0, nil, ErrPreconditionFailed
+		}
+
+		// 新しい在庫記録を作成
+		stock = &Stock{
+			ItemID:     itemID,
+			LocationID: locationID,
+			Quantity:   newQuantity,
+			Reserved:   0,
+			Version:    1,
+			UpdatedAt:  time.Now(),
+			UpdatedBy:  m.getUserFromContext(ctx),
+		}
+		stock.CalculateAvailable()
+
+		if err := m.storage.CreateStock(ctx, stock); err != nil {
+			return 0, nil, NewStorageError("create_stock", "在庫作成に失敗しました", err)
+		}
+		return 0, stock, nil
+	}
+
+	if expected, ok := expectedVersion(ctx); ok && stock.Version != expected {
+		return 0, nil, ErrPreconditionFailed
+	}
+
+	// 既存の在庫を調整
+	oldQuantity := stock.Quantity
+	stock.Quantity = newQuantity
+	stock.Version++
+	stock.UpdatedAt = time.Now()
+	stock.UpdatedBy = m.getUserFromContext(ctx)
+	stock.CalculateAvailable()
+
+	if err := m.storage.UpdateStock(ctx, stock); err != nil {
+		return 0, nil, NewStorageError("update_stock", "在庫更新に失敗しました", err)
+	}
+
+	return oldQuantity, stock, nil
+}
+
+// withStockLock runs fn under the configured locking strategy
+// 設定されたロック方式でfnを実行
+//
+// 悲観的ロックの場合はトランザクションを開始し、その中でfnを実行します。
+// 楽観的ロックの場合はそのままfnを実行し、競合はUpdateStockのバージョン確認で検出します。
+//
+// バージョン競合が発生した場合は設定に従ってリトライします。
+func (m *Manager) withStockLock(ctx context.Context, fn func(lm *Manager) error) error {
+	return m.retryOnConflict(ctx, func() error {
+		if m.config.LockingStrategy != LockingStrategyPessimistic {
+			return fn(m)
+		}
+		return m.storage.WithinTx(ctx, func(txStorage Storage) error {
+			return fn(m.withStorage(txStorage, m.publisher))
+		})
+	})
+}
+
+// withStockTx runs fn in a storage transaction regardless of the locking strategy
+// ロック方式にかかわらずトランザクション内でfnを実行
+//
+// 在庫と他の記録（ロットなど）を一緒に更新する場合に使用します。バージョン競合が発生した場合は
+// 設定に従ってリトライします。
+func (m *Manager) withStockTx(ctx context.Context, fn func(lm *Manager) error) error {
+	return m.retryOnConflict(ctx, func() error {
+		return m.storage.WithinTx(ctx, func(txStorage Storage) error {
+			return fn(m.withStorage(txStorage, m.publisher))
+		})
+	})
+}
+
+// retryOnConflict runs fn and retries with exponential backoff on ErrVersionMismatch
+// fnを実行し、ErrVersionMismatchの場合は指数バックオフでリトライ
+func (m *Manager) retryOnConflict(ctx context.Context, fn func() error) error {
+	maxAttempts := m.config.RetryMaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || !errors.Is(err, ErrVersionMismatch) || attempt >= maxAttempts {
+			return err
+		}
+
+		delay := m.retryDelay(attempt)
+		m.log(ctx).Debug("バージョン競合のため再試行します",
+			zap.Int("attempt", attempt),
+			zap.Duration("delay", delay),
+		)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryDelay calculates the backoff delay before the next attempt
+// 次の試行までの待機時間を計算（指数バックオフ＋ゆらぎ）
+func (m *Manager) retryDelay(attempt int) time.Duration {
+	delay := m.config.RetryBaseDelay << (attempt - 1)
+	if m.config.RetryMaxDelay > 0 && (delay > m.config.RetryMaxDelay || delay <= 0) {
+		delay = m.config.RetryMaxDelay
+	}
+
+	if m.config.RetryJitter > 0 && delay > 0 {
+		jitter := m.config.RetryJitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		spread := time.Duration(float64(delay) * jitter)
+		if spread > 0 {
+			delay = delay - spread + time.Duration(rand.Int63n(int64(spread)+1))
+		}
+	}
+
+	return delay
+}
+
+// getStockForWrite reads a stock record that is about to be updated
+// 更新予定の在庫記録を取得（悲観的ロックの場合は行ロックを取得）
+func (m *Manager) getStockForWrite(ctx context.Context, itemID, locationID string) (*Stock, error) {
+	if m.config.LockingStrategy == LockingStrategyPessimistic {
+		return m.storage.GetStockForUpdate(ctx, itemID, locationID)
+	}
+	// 読み取りレプリカの遅延によるバージョン競合を避けるためプライマリから読み取る
+	return m.storage.GetStock(WithPrimaryRead(ctx), itemID, locationID)
+}
+
+// withStorage returns a copy of the manager bound to another storage and publisher
+// 別のストレージとイベント発行者に紐付けたマネージャーのコピーを返す
+//
+// トランザクションスコープ内で既存の在庫操作ロジックを再利用するために使用します。
+func (m *Manager) withStorage(storage Storage, publisher EventPublisher) *Manager {
+	scoped := *m
+	scoped.storage = storage
+	if m.publisher != nil {
+		scoped.publisher = publisher
+	}
+	return &scoped
+}
+
+// publishEvent publishes a domain event when the publisher supports DomainEventPublisher
+// イベント発行者がDomainEventPublisherを実装している場合にドメインイベントを発行
+//
+// 発行の失敗は操作自体を失敗させず、ログに記録するのみです。
+func (m *Manager) publishEvent(ctx context.Context, eventType, itemID, locationID string, data interface{}) {
+	publisher, ok := m.publisher.(DomainEventPublisher)
+	if !ok {
+		return
+	}
+
+	event := DomainEvent{
+		ID:         NewTransactionID(),
+		Type:       eventType,
+		ItemID:     itemID,
+		LocationID: locationID,
+		Data:       data,
+		Timestamp:  time.Now(),
+	}
+	if err := publisher.PublishEvent(ctx, event); err != nil {
+		m.log(ctx).Error("ドメインイベント発行に失敗しました",
+			zap.String("event_type", eventType),
+			zap.Error(err),
+		)
+	}
+}
+
+// expectedVersionKey is the context key of the expected stock version
+// 想定する在庫バージョンのコンテキストキー
+type expectedVersionKey struct{}
+
+// WithExpectedVersion returns a context that makes Adjust fail unless the stock has the version
+// 在庫が指定したバージョンでない場合に Adjust を失敗させるコンテキストを返す
+//
+// 在庫のバージョンが異なる・在庫が存在しない場合、Adjust は ErrPreconditionFailed を返します。
+// 確認は在庫の更新と同じロック（楽観的ロックのバージョン確認または行ロック）の中で行います。
+func WithExpectedVersion(ctx context.Context, version int64) context.Context {
+	return context.WithValue(ctx, expectedVersionKey{}, version)
+}
+
+// expectedVersion returns the stock version expected by the context
+// コンテキストが想定する在庫バージョンを返す
+func expectedVersion(ctx context.Context) (int64, bool) {
+	version, ok := ctx.Value(expectedVersionKey{}).(int64)
+	return version, ok
+}
+
+// log returns the logger annotated with the request ID carried by ctx
+// コンテキストのリクエストIDを付与したロガーを返す
+func (m *Manager) log(ctx context.Context) *zap.Logger {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		return m.logger.With(zap.String(MetadataRequestID, requestID))
+	}
+	return m.logger
+}
+
+// transactionMetadata returns the transaction metadata with the request ID carried by ctx
+// コンテキストのリクエストIDを含めたトランザクションのメタデータを返す
+func transactionMetadata(ctx context.Context, metadata map[string]string) map[string]string {
+	requestID := RequestIDFromContext(ctx)
+	if requestID == "" {
+		return metadata
+	}
+	if metadata == nil {
+		metadata = make(map[string]string, 1)
+	}
+	metadata[MetadataRequestID] = requestID
+	return metadata
 }
 
 // getUserFromContext extracts user ID from context
@@ -729,25 +2081,32 @@ func (m *Manager) getUserFromContext(ctx context.Context) string {
 	return "system"
 }
 
-// triggerLowStockAlert creates a low stock alert
-// 低在庫アラートを作成
-func (m *Manager) triggerLowStockAlert(ctx context.Context, itemID, locationID string, currentQty int64) {
+// triggerLowStockAlert creates a low stock alert and reports whether it was created
+// 低在庫アラートを作成（作成した場合に true を返す）
+func (m *Manager) triggerLowStockAlert(ctx context.Context, itemID, locationID string, currentQty, threshold int64, message string) bool {
+	// 在庫が無くなった場合は欠品として重大なアラートにする
+	severity := AlertSeverityWarning
+	if currentQty <= 0 {
+		severity = AlertSeverityCritical
+	}
 	alert := &StockAlert{
 		ID:         NewTransactionID(),
 		Type:       AlertTypeLowStock,
 		ItemID:     itemID,
 		LocationID: locationID,
 		CurrentQty: currentQty,
-		Threshold:  m.config.LowStockThreshold,
-		Message:    fmt.Sprintf("商品 %s のロケーション %s での在庫が低下しています (現在: %d, 閾値: %d)", itemID, locationID, currentQty, m.config.LowStockThreshold),
+		Threshold:  threshold,
+		Message:    message,
 		IsActive:   true,
 		CreatedAt:  time.Now(),
+		Severity:   severity,
 	}
 
 	if err := m.storage.CreateAlert(ctx, alert); err != nil {
-		m.logger.Error("アラート作成に失敗しました", zap.Error(err))
-		return
+		m.log(ctx).Error("アラート作成に失敗しました", zap.Error(err))
+		return false
 	}
+	m.publishEvent(ctx, EventTypeAlertCreated, itemID, locationID, alert)
 
 	// 低在庫アラートイベント発行
 	if m.publisher != nil {
@@ -755,11 +2114,12 @@ func (m *Manager) triggerLowStockAlert(ctx context.Context, itemID, locationID s
 			ItemID:     itemID,
 			LocationID: locationID,
 			CurrentQty: currentQty,
-			Threshold:  m.config.LowStockThreshold,
+			Threshold:  threshold,
 			Timestamp:  time.Now(),
 		}
 		if err := m.publisher.PublishLowStockAlert(ctx, event); err != nil {
-			m.logger.Error("低在庫アラートイベント発行に失敗しました", zap.Error(err))
+			m.log(ctx).Error("低在庫アラートイベント発行に失敗しました", zap.Error(err))
 		}
 	}
+	return true
 }
@@ -7,20 +7,23 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
+// primaryReadCtx は更新前の読み取りでプライマリ読み取りが要求されていることを検証するマッチャー
+var primaryReadCtx = mock.MatchedBy(func(ctx context.Context) bool { return IsPrimaryRead(ctx) })
+
 // MockStorage はテスト用のStorageモック
 type MockStorage struct {
 	mock.Mock
 }
 
-func (m *MockStorage) Begin(ctx context.Context) (Transaction, error) {
-	args := m.Called(ctx)
-	if args.Get(0) == nil {
-		return Transaction{}, args.Error(1)
-	}
-	return args.Get(0).(Transaction), args.Error(1)
+var _ Storage = (*MockStorage)(nil)
+
+// WithinTx はトランザクションを使わずにfnをそのまま実行
+func (m *MockStorage) WithinTx(ctx context.Context, fn func(txStorage Storage) error) error {
+	return fn(m)
 }
 
 func (m *MockStorage) CreateStock(ctx context.Context, stock *Stock) error {
@@ -33,6 +36,21 @@ func (m *MockStorage) UpdateStock(ctx context.Context, stock *Stock) error {
 	return args.Error(0)
 }
 
+func (m *MockStorage) CreateOrUpdateStocks(ctx context.Context, stocks []Stock) error {
+	args := m.Called(ctx, stocks)
+	return args.Error(0)
+}
+
+func (m *MockStorage) BulkImport(ctx context.Context, data *BulkImportData) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetStocks(ctx context.Context, keys []StockKey) ([]Stock, error) {
+	args := m.Called(ctx, keys)
+	return args.Get(0).([]Stock), args.Error(1)
+}
+
 func (m *MockStorage) GetStock(ctx context.Context, itemID, locationID string) (*Stock, error) {
 	args := m.Called(ctx, itemID, locationID)
 	if args.Get(0) == nil {
@@ -41,21 +59,95 @@ func (m *MockStorage) GetStock(ctx context.Context, itemID, locationID string) (
 	return args.Get(0).(*Stock), args.Error(1)
 }
 
+func (m *MockStorage) GetStockForUpdate(ctx context.Context, itemID, locationID string) (*Stock, error) {
+	args := m.Called(ctx, itemID, locationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Stock), args.Error(1)
+}
+
 func (m *MockStorage) ListStockByLocation(ctx context.Context, locationID string) ([]Stock, error) {
 	args := m.Called(ctx, locationID)
 	return args.Get(0).([]Stock), args.Error(1)
 }
 
+func (m *MockStorage) ListStockByItem(ctx context.Context, itemID string) ([]Stock, error) {
+	args := m.Called(ctx, itemID)
+	return args.Get(0).([]Stock), args.Error(1)
+}
+
+func (m *MockStorage) ForEachStockByLocation(ctx context.Context, locationID string, fn func(stock Stock) error) error {
+	args := m.Called(ctx, locationID, fn)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetTotalStockByItem(ctx context.Context, itemID string) (int64, error) {
+	args := m.Called(ctx, itemID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStorage) GetTotalStockByLocation(ctx context.Context, locationID string) (int64, error) {
+	args := m.Called(ctx, locationID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockStorage) CreateTransaction(ctx context.Context, tx *Transaction) error {
 	args := m.Called(ctx, tx)
 	return args.Error(0)
 }
 
+func (m *MockStorage) GetTransactionByID(ctx context.Context, txID string) (*Transaction, error) {
+	args := m.Called(ctx, txID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Transaction), args.Error(1)
+}
+
 func (m *MockStorage) GetTransactionHistory(ctx context.Context, itemID string, limit int) ([]Transaction, error) {
 	args := m.Called(ctx, itemID, limit)
 	return args.Get(0).([]Transaction), args.Error(1)
 }
 
+func (m *MockStorage) GetTransactionHistoryAfter(ctx context.Context, itemID string, after *HistoryCursor, limit int) ([]Transaction, error) {
+	args := m.Called(ctx, itemID, after, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Transaction), args.Error(1)
+}
+
+func (m *MockStorage) GetTransactionHistoryByLocation(ctx context.Context, locationID string, limit int) ([]Transaction, error) {
+	args := m.Called(ctx, locationID, limit)
+	return args.Get(0).([]Transaction), args.Error(1)
+}
+
+func (m *MockStorage) GetTransactionsByReference(ctx context.Context, reference string) ([]Transaction, error) {
+	args := m.Called(ctx, reference)
+	return args.Get(0).([]Transaction), args.Error(1)
+}
+
+func (m *MockStorage) SearchTransactionsByMetadata(ctx context.Context, key, value string, limit int) ([]Transaction, error) {
+	args := m.Called(ctx, key, value, limit)
+	return args.Get(0).([]Transaction), args.Error(1)
+}
+
+func (m *MockStorage) ForEachTransactionInRange(ctx context.Context, from, to time.Time, fn func(tx Transaction) error) error {
+	args := m.Called(ctx, from, to, fn)
+	return args.Error(0)
+}
+
+func (m *MockStorage) ArchiveTransactions(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStorage) GetTransactionHistoryByDateRange(ctx context.Context, itemID string, from, to time.Time) ([]Transaction, error) {
+	args := m.Called(ctx, itemID, from, to)
+	return args.Get(0).([]Transaction), args.Error(1)
+}
+
 func (m *MockStorage) CreateItem(ctx context.Context, item *Item) error {
 	args := m.Called(ctx, item)
 	return args.Error(0)
@@ -74,6 +166,26 @@ func (m *MockStorage) UpdateItem(ctx context.Context, item *Item) error {
 	return args.Error(0)
 }
 
+func (m *MockStorage) DeleteItem(ctx context.Context, itemID string) error {
+	args := m.Called(ctx, itemID)
+	return args.Error(0)
+}
+
+func (m *MockStorage) ListItems(ctx context.Context, offset, limit int) ([]Item, error) {
+	args := m.Called(ctx, offset, limit)
+	return args.Get(0).([]Item), args.Error(1)
+}
+
+func (m *MockStorage) ListItemsAfter(ctx context.Context, filter ItemFilter, after *PageCursor, limit int) ([]Item, error) {
+	args := m.Called(ctx, filter, after, limit)
+	return args.Get(0).([]Item), args.Error(1)
+}
+
+func (m *MockStorage) SearchItems(ctx context.Context, query string) ([]Item, error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).([]Item), args.Error(1)
+}
+
 func (m *MockStorage) CreateLocation(ctx context.Context, location *Location) error {
 	args := m.Called(ctx, location)
 	return args.Error(0)
@@ -87,6 +199,261 @@ func (m *MockStorage) GetLocation(ctx context.Context, locationID string) (*Loca
 	return args.Get(0).(*Location), args.Error(1)
 }
 
+func (m *MockStorage) UpdateLocation(ctx context.Context, location *Location) error {
+	args := m.Called(ctx, location)
+	return args.Error(0)
+}
+
+func (m *MockStorage) DeleteLocation(ctx context.Context, locationID string) error {
+	args := m.Called(ctx, locationID)
+	return args.Error(0)
+}
+
+func (m *MockStorage) ListLocations(ctx context.Context, offset, limit int) ([]Location, error) {
+	args := m.Called(ctx, offset, limit)
+	return args.Get(0).([]Location), args.Error(1)
+}
+
+func (m *MockStorage) ListLocationsAfter(ctx context.Context, after *PageCursor, limit int) ([]Location, error) {
+	args := m.Called(ctx, after, limit)
+	return args.Get(0).([]Location), args.Error(1)
+}
+
+func (m *MockStorage) CreateUnit(ctx context.Context, unit *UnitOfMeasure) error {
+	args := m.Called(ctx, unit)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetUnit(ctx context.Context, code string) (*UnitOfMeasure, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*UnitOfMeasure), args.Error(1)
+}
+
+func (m *MockStorage) ListUnits(ctx context.Context) ([]UnitOfMeasure, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]UnitOfMeasure), args.Error(1)
+}
+
+func (m *MockStorage) DeleteUnit(ctx context.Context, code string) error {
+	args := m.Called(ctx, code)
+	return args.Error(0)
+}
+
+func (m *MockStorage) SetUnitConversion(ctx context.Context, conversion *UnitConversion) error {
+	args := m.Called(ctx, conversion)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetUnitConversion(ctx context.Context, itemID, uom string) (*UnitConversion, error) {
+	args := m.Called(ctx, itemID, uom)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*UnitConversion), args.Error(1)
+}
+
+func (m *MockStorage) ListUnitConversions(ctx context.Context, itemID string) ([]UnitConversion, error) {
+	args := m.Called(ctx, itemID)
+	return args.Get(0).([]UnitConversion), args.Error(1)
+}
+
+func (m *MockStorage) DeleteUnitConversion(ctx context.Context, itemID, uom string) error {
+	args := m.Called(ctx, itemID, uom)
+	return args.Error(0)
+}
+
+func (m *MockStorage) SetBillOfMaterials(ctx context.Context, bom *BillOfMaterials) error {
+	args := m.Called(ctx, bom)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetBillOfMaterials(ctx context.Context, kitItemID string) (*BillOfMaterials, error) {
+	args := m.Called(ctx, kitItemID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*BillOfMaterials), args.Error(1)
+}
+
+func (m *MockStorage) ListBillsOfMaterials(ctx context.Context, offset, limit int) ([]BillOfMaterials, error) {
+	args := m.Called(ctx, offset, limit)
+	return args.Get(0).([]BillOfMaterials), args.Error(1)
+}
+
+func (m *MockStorage) DeleteBillOfMaterials(ctx context.Context, kitItemID string) error {
+	args := m.Called(ctx, kitItemID)
+	return args.Error(0)
+}
+
+func (m *MockStorage) CreateAssembly(ctx context.Context, assembly *Assembly) error {
+	args := m.Called(ctx, assembly)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetAssembly(ctx context.Context, assemblyID string) (*Assembly, error) {
+	args := m.Called(ctx, assemblyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Assembly), args.Error(1)
+}
+
+func (m *MockStorage) ListAssemblies(ctx context.Context, filter AssemblyFilter) ([]Assembly, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]Assembly), args.Error(1)
+}
+
+func (m *MockStorage) CreateSupplier(ctx context.Context, supplier *Supplier) error {
+	args := m.Called(ctx, supplier)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetSupplier(ctx context.Context, supplierID string) (*Supplier, error) {
+	args := m.Called(ctx, supplierID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Supplier), args.Error(1)
+}
+
+func (m *MockStorage) UpdateSupplier(ctx context.Context, supplier *Supplier) error {
+	args := m.Called(ctx, supplier)
+	return args.Error(0)
+}
+
+func (m *MockStorage) DeleteSupplier(ctx context.Context, supplierID string) error {
+	args := m.Called(ctx, supplierID)
+	return args.Error(0)
+}
+
+func (m *MockStorage) ListSuppliers(ctx context.Context, offset, limit int) ([]Supplier, error) {
+	args := m.Called(ctx, offset, limit)
+	return args.Get(0).([]Supplier), args.Error(1)
+}
+
+func (m *MockStorage) SetSupplierItem(ctx context.Context, supplierItem *SupplierItem) error {
+	args := m.Called(ctx, supplierItem)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetSupplierItem(ctx context.Context, supplierID, itemID string) (*SupplierItem, error) {
+	args := m.Called(ctx, supplierID, itemID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*SupplierItem), args.Error(1)
+}
+
+func (m *MockStorage) DeleteSupplierItem(ctx context.Context, supplierID, itemID string) error {
+	args := m.Called(ctx, supplierID, itemID)
+	return args.Error(0)
+}
+
+func (m *MockStorage) ListSupplierItems(ctx context.Context, filter SupplierItemFilter) ([]SupplierItem, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]SupplierItem), args.Error(1)
+}
+
+func (m *MockStorage) CreatePurchaseOrder(ctx context.Context, order *PurchaseOrder) error {
+	args := m.Called(ctx, order)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetPurchaseOrder(ctx context.Context, orderID string) (*PurchaseOrder, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*PurchaseOrder), args.Error(1)
+}
+
+func (m *MockStorage) ListPurchaseOrders(ctx context.Context, filter PurchaseOrderFilter) ([]PurchaseOrder, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]PurchaseOrder), args.Error(1)
+}
+
+func (m *MockStorage) UpdatePurchaseOrder(ctx context.Context, order *PurchaseOrder, from PurchaseOrderStatus) error {
+	args := m.Called(ctx, order, from)
+	return args.Error(0)
+}
+
+func (m *MockStorage) ReceivePurchaseOrderLine(ctx context.Context, orderID, itemID string, quantity int64) (*PurchaseOrderLine, error) {
+	args := m.Called(ctx, orderID, itemID, quantity)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*PurchaseOrderLine), args.Error(1)
+}
+
+func (m *MockStorage) CreateGoodsReceipt(ctx context.Context, receipt *GoodsReceipt) error {
+	args := m.Called(ctx, receipt)
+	return args.Error(0)
+}
+
+func (m *MockStorage) ListGoodsReceipts(ctx context.Context, orderID string) ([]GoodsReceipt, error) {
+	args := m.Called(ctx, orderID)
+	return args.Get(0).([]GoodsReceipt), args.Error(1)
+}
+
+func (m *MockStorage) CreateSalesOrder(ctx context.Context, order *SalesOrder) error {
+	args := m.Called(ctx, order)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetSalesOrder(ctx context.Context, orderID string) (*SalesOrder, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*SalesOrder), args.Error(1)
+}
+
+func (m *MockStorage) ListSalesOrders(ctx context.Context, filter SalesOrderFilter) ([]SalesOrder, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]SalesOrder), args.Error(1)
+}
+
+func (m *MockStorage) UpdateSalesOrder(ctx context.Context, order *SalesOrder) error {
+	args := m.Called(ctx, order)
+	return args.Error(0)
+}
+
+func (m *MockStorage) CreateReturn(ctx context.Context, rma *ReturnAuthorization) error {
+	args := m.Called(ctx, rma)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetReturn(ctx context.Context, returnID string) (*ReturnAuthorization, error) {
+	args := m.Called(ctx, returnID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ReturnAuthorization), args.Error(1)
+}
+
+func (m *MockStorage) ListReturns(ctx context.Context, filter ReturnFilter) ([]ReturnAuthorization, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]ReturnAuthorization), args.Error(1)
+}
+
+func (m *MockStorage) UpdateReturn(ctx context.Context, rma *ReturnAuthorization) error {
+	args := m.Called(ctx, rma)
+	return args.Error(0)
+}
+
+func (m *MockStorage) CreateReturnDisposition(ctx context.Context, disposition *ReturnDisposition) error {
+	args := m.Called(ctx, disposition)
+	return args.Error(0)
+}
+
+func (m *MockStorage) ListReturnDispositions(ctx context.Context, returnID string) ([]ReturnDisposition, error) {
+	args := m.Called(ctx, returnID)
+	return args.Get(0).([]ReturnDisposition), args.Error(1)
+}
+
 func (m *MockStorage) CreateLot(ctx context.Context, lot *Lot) error {
 	args := m.Called(ctx, lot)
 	return args.Error(0)
@@ -100,11 +467,81 @@ func (m *MockStorage) GetLot(ctx context.Context, lotID string) (*Lot, error) {
 	return args.Get(0).(*Lot), args.Error(1)
 }
 
+func (m *MockStorage) UpdateLot(ctx context.Context, lot *Lot) error {
+	args := m.Called(ctx, lot)
+	return args.Error(0)
+}
+
+func (m *MockStorage) DeleteLot(ctx context.Context, lotID string) error {
+	args := m.Called(ctx, lotID)
+	return args.Error(0)
+}
+
+func (m *MockStorage) AdjustLotQuantity(ctx context.Context, lotID string, delta int64) (*Lot, error) {
+	args := m.Called(ctx, lotID, delta)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Lot), args.Error(1)
+}
+
 func (m *MockStorage) GetLotsByItem(ctx context.Context, itemID string) ([]Lot, error) {
 	args := m.Called(ctx, itemID)
 	return args.Get(0).([]Lot), args.Error(1)
 }
 
+func (m *MockStorage) GetExpiringLots(ctx context.Context, within time.Duration) ([]Lot, error) {
+	args := m.Called(ctx, within)
+	return args.Get(0).([]Lot), args.Error(1)
+}
+
+func (m *MockStorage) GetExpiredLots(ctx context.Context) ([]Lot, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]Lot), args.Error(1)
+}
+
+func (m *MockStorage) GetLotByNumber(ctx context.Context, itemID, lotNumber string) (*Lot, error) {
+	args := m.Called(ctx, itemID, lotNumber)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Lot), args.Error(1)
+}
+
+func (m *MockStorage) AdjustLotStock(ctx context.Context, lot *Lot, locationID string, delta int64) (*LotStock, error) {
+	args := m.Called(ctx, lot, locationID, delta)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*LotStock), args.Error(1)
+}
+
+func (m *MockStorage) GetLotStock(ctx context.Context, lotID, locationID string) (*LotStock, error) {
+	args := m.Called(ctx, lotID, locationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*LotStock), args.Error(1)
+}
+
+func (m *MockStorage) ListLotStocks(ctx context.Context, filter LotStockFilter) ([]LotStock, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]LotStock), args.Error(1)
+}
+
+func (m *MockStorage) AdjustConsignmentStock(ctx context.Context, ownerID, itemID, locationID string, delta int64) (*ConsignmentStock, error) {
+	args := m.Called(ctx, ownerID, itemID, locationID, delta)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ConsignmentStock), args.Error(1)
+}
+
+func (m *MockStorage) ListConsignmentStocks(ctx context.Context, filter ConsignmentStockFilter) ([]ConsignmentStock, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]ConsignmentStock), args.Error(1)
+}
+
 func (m *MockStorage) CreateAlert(ctx context.Context, alert *StockAlert) error {
 	args := m.Called(ctx, alert)
 	return args.Error(0)
@@ -115,11 +552,259 @@ func (m *MockStorage) GetActiveAlerts(ctx context.Context, locationID string) ([
 	return args.Get(0).([]StockAlert), args.Error(1)
 }
 
-func (m *MockStorage) ResolveAlert(ctx context.Context, alertID string) error {
+func (m *MockStorage) CreateReservation(ctx context.Context, reservation *Reservation) error {
+	args := m.Called(ctx, reservation)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetReservation(ctx context.Context, reservationID string) (*Reservation, error) {
+	args := m.Called(ctx, reservationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Reservation), args.Error(1)
+}
+
+func (m *MockStorage) UpdateReservation(ctx context.Context, reservation *Reservation) error {
+	args := m.Called(ctx, reservation)
+	return args.Error(0)
+}
+
+func (m *MockStorage) ListReservations(ctx context.Context, filter ReservationFilter) ([]Reservation, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]Reservation), args.Error(1)
+}
+
+func (m *MockStorage) GetExpiredReservations(ctx context.Context, before time.Time, limit int) ([]Reservation, error) {
+	args := m.Called(ctx, before, limit)
+	return args.Get(0).([]Reservation), args.Error(1)
+}
+
+func (m *MockStorage) SetChannelAllocation(ctx context.Context, allocation *ChannelAllocation) error {
+	args := m.Called(ctx, allocation)
+	return args.Error(0)
+}
+
+func (m *MockStorage) ListChannelAllocations(ctx context.Context, itemID, locationID string) ([]ChannelAllocation, error) {
+	args := m.Called(ctx, itemID, locationID)
+	return args.Get(0).([]ChannelAllocation), args.Error(1)
+}
+
+func (m *MockStorage) CreateBackorder(ctx context.Context, backorder *Backorder) error {
+	args := m.Called(ctx, backorder)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetBackorder(ctx context.Context, backorderID string) (*Backorder, error) {
+	args := m.Called(ctx, backorderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Backorder), args.Error(1)
+}
+
+func (m *MockStorage) UpdateBackorder(ctx context.Context, backorder *Backorder) error {
+	args := m.Called(ctx, backorder)
+	return args.Error(0)
+}
+
+func (m *MockStorage) ListBackorders(ctx context.Context, filter BackorderFilter) ([]Backorder, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]Backorder), args.Error(1)
+}
+
+func (m *MockStorage) SaveReorderPoint(ctx context.Context, reorderPoint *ReorderPoint) error {
+	args := m.Called(ctx, reorderPoint)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetReorderPoint(ctx context.Context, itemID, locationID string) (*ReorderPoint, error) {
+	args := m.Called(ctx, itemID, locationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ReorderPoint), args.Error(1)
+}
+
+func (m *MockStorage) DeleteReorderPoint(ctx context.Context, itemID, locationID string) error {
+	args := m.Called(ctx, itemID, locationID)
+	return args.Error(0)
+}
+
+func (m *MockStorage) ListReorderPoints(ctx context.Context, filter ReorderPointFilter) ([]ReorderPoint, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]ReorderPoint), args.Error(1)
+}
+
+func (m *MockStorage) SaveBatchOperation(ctx context.Context, batch *BatchOperation) error {
+	args := m.Called(ctx, batch)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetBatchOperation(ctx context.Context, batchID string) (*BatchOperation, error) {
+	args := m.Called(ctx, batchID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*BatchOperation), args.Error(1)
+}
+
+func (m *MockStorage) GetAlert(ctx context.Context, alertID string) (*StockAlert, error) {
 	args := m.Called(ctx, alertID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*StockAlert), args.Error(1)
+}
+
+func (m *MockStorage) ListAlerts(ctx context.Context, filter AlertFilter) ([]StockAlert, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]StockAlert), args.Error(1)
+}
+
+func (m *MockStorage) ResolveAlert(ctx context.Context, alertID, resolvedBy, note string) error {
+	args := m.Called(ctx, alertID, resolvedBy, note)
+	return args.Error(0)
+}
+
+func (m *MockStorage) AcknowledgeAlert(ctx context.Context, alertID, acknowledgedBy, note string) error {
+	args := m.Called(ctx, alertID, acknowledgedBy, note)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetLatestRuleAlert(ctx context.Context, ruleID, itemID, locationID string) (*StockAlert, error) {
+	args := m.Called(ctx, ruleID, itemID, locationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*StockAlert), args.Error(1)
+}
+
+func (m *MockStorage) CreateAlertRule(ctx context.Context, rule *AlertRule) error {
+	args := m.Called(ctx, rule)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetAlertRule(ctx context.Context, ruleID string) (*AlertRule, error) {
+	args := m.Called(ctx, ruleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*AlertRule), args.Error(1)
+}
+
+func (m *MockStorage) UpdateAlertRule(ctx context.Context, rule *AlertRule) error {
+	args := m.Called(ctx, rule)
 	return args.Error(0)
 }
 
+func (m *MockStorage) DeleteAlertRule(ctx context.Context, ruleID string) error {
+	args := m.Called(ctx, ruleID)
+	return args.Error(0)
+}
+
+func (m *MockStorage) ListAlertRules(ctx context.Context, filter AlertRuleFilter) ([]AlertRule, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]AlertRule), args.Error(1)
+}
+
+func (m *MockStorage) GetAlertRulesForStock(ctx context.Context, itemID, locationID string) ([]AlertRule, error) {
+	args := m.Called(ctx, itemID, locationID)
+	return args.Get(0).([]AlertRule), args.Error(1)
+}
+
+func (m *MockStorage) CreateStockSnapshot(ctx context.Context, snapshot *StockSnapshot) error {
+	args := m.Called(ctx, snapshot)
+	return args.Error(0)
+}
+
+func (m *MockStorage) ListStockSnapshots(ctx context.Context, offset, limit int) ([]StockSnapshot, error) {
+	args := m.Called(ctx, offset, limit)
+	return args.Get(0).([]StockSnapshot), args.Error(1)
+}
+
+func (m *MockStorage) GetStockSnapshotLines(ctx context.Context, snapshotID, locationID string) ([]StockSnapshotLine, error) {
+	args := m.Called(ctx, snapshotID, locationID)
+	return args.Get(0).([]StockSnapshotLine), args.Error(1)
+}
+
+func (m *MockStorage) CreateStocktake(ctx context.Context, stocktake *Stocktake) error {
+	args := m.Called(ctx, stocktake)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetStocktake(ctx context.Context, stocktakeID string) (*Stocktake, error) {
+	args := m.Called(ctx, stocktakeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Stocktake), args.Error(1)
+}
+
+func (m *MockStorage) ListStocktakes(ctx context.Context, filter StocktakeFilter) ([]Stocktake, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]Stocktake), args.Error(1)
+}
+
+func (m *MockStorage) UpdateStocktake(ctx context.Context, stocktake *Stocktake, from StocktakeStatus) error {
+	args := m.Called(ctx, stocktake, from)
+	return args.Error(0)
+}
+
+func (m *MockStorage) CreateAdjustment(ctx context.Context, adjustment *Adjustment) error {
+	args := m.Called(ctx, adjustment)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetAdjustment(ctx context.Context, adjustmentID string) (*Adjustment, error) {
+	args := m.Called(ctx, adjustmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Adjustment), args.Error(1)
+}
+
+func (m *MockStorage) ListAdjustments(ctx context.Context, filter AdjustmentFilter) ([]Adjustment, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]Adjustment), args.Error(1)
+}
+
+func (m *MockStorage) UpdateAdjustment(ctx context.Context, adjustment *Adjustment, from AdjustmentStatus) error {
+	args := m.Called(ctx, adjustment, from)
+	return args.Error(0)
+}
+
+func (m *MockStorage) CreateInspection(ctx context.Context, inspection *Inspection) error {
+	args := m.Called(ctx, inspection)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetInspection(ctx context.Context, inspectionID string) (*Inspection, error) {
+	args := m.Called(ctx, inspectionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Inspection), args.Error(1)
+}
+
+func (m *MockStorage) ListInspections(ctx context.Context, filter InspectionFilter) ([]Inspection, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]Inspection), args.Error(1)
+}
+
+func (m *MockStorage) UpdateInspection(ctx context.Context, inspection *Inspection, from InspectionStatus) error {
+	args := m.Called(ctx, inspection, from)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetInventorySummary(ctx context.Context, lowStockThreshold int64) (*InventorySummary, error) {
+	args := m.Called(ctx, lowStockThreshold)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*InventorySummary), args.Error(1)
+}
+
 func (m *MockStorage) Ping(ctx context.Context) error {
 	args := m.Called(ctx)
 	return args.Error(0)
@@ -141,7 +826,8 @@ func TestManager_Add(t *testing.T) {
 		LowStockThreshold:  10,
 	}
 
-	manager := NewManager(mockStorage, nil, logger, config)
+	publisher := &recordingPublisher{}
+	manager := NewManager(mockStorage, publisher, logger, config)
 	ctx := context.Background()
 
 	// テスト用のサンプルデータ
@@ -156,11 +842,13 @@ func TestManager_Add(t *testing.T) {
 	}
 
 	// モックの期待値設定
-	mockStorage.On("GetItem", ctx, "TEST-ITEM").Return(item, nil)
-	mockStorage.On("GetLocation", ctx, "TEST-LOC").Return(location, nil)
-	mockStorage.On("GetStock", ctx, "TEST-ITEM", "TEST-LOC").Return(nil, ErrStockNotFound)
-	mockStorage.On("CreateStock", ctx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
-	mockStorage.On("CreateTransaction", ctx, mock.AnythingOfType("*inventory.Transaction")).Return(nil)
+	mockStorage.On("GetItem", spanCtx, "TEST-ITEM").Return(item, nil)
+	mockStorage.On("GetLocation", spanCtx, "TEST-LOC").Return(location, nil)
+	mockStorage.On("GetStock", primaryReadCtx, "TEST-ITEM", "TEST-LOC").Return(nil, ErrStockNotFound)
+	mockStorage.On("CreateStock", spanCtx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
+	mockStorage.On("CreateTransaction", spanCtx, mock.AnythingOfType("*inventory.Transaction")).Return(nil)
+	mockStorage.On("GetReorderPoint", spanCtx, "TEST-ITEM", "TEST-LOC").Return(nil, ErrReorderPointNotFound)
+	mockStorage.On("GetAlertRulesForStock", spanCtx, "TEST-ITEM", "TEST-LOC").Return([]AlertRule{}, nil)
 
 	// テスト実行
 	err := manager.Add(ctx, "TEST-ITEM", "TEST-LOC", 100, "TEST-REF")
@@ -168,9 +856,42 @@ func TestManager_Add(t *testing.T) {
 	// アサーション
 	assert.NoError(t, err)
 	mockStorage.AssertExpectations(t)
+
+	// 在庫変更イベントには変更数量・利用可能数量・単価が含まれる
+	if assert.Len(t, publisher.changes, 1) {
+		event := publisher.changes[0]
+		assert.Equal(t, int64(100), event.Delta)
+		if assert.NotNil(t, event.Available) {
+			assert.Equal(t, int64(100), *event.Available)
+		}
+		if assert.NotNil(t, event.UnitCost) {
+			assert.Equal(t, 1000.0, *event.UnitCost)
+		}
+	}
 }
 
 // TestManager_Remove は在庫削除機能のテスト
+func TestManager_RecordsRequestID(t *testing.T) {
+	mockStorage := new(MockStorage)
+	manager := NewManager(mockStorage, nil, zap.NewNop(), nil)
+	ctx := WithRequestID(context.Background(), "req-001")
+
+	mockStorage.On("GetItem", spanCtx, "TEST-ITEM").Return(&Item{ID: "TEST-ITEM"}, nil)
+	mockStorage.On("GetLocation", spanCtx, "TEST-LOC").Return(&Location{ID: "TEST-LOC"}, nil)
+	mockStorage.On("GetStock", primaryReadCtx, "TEST-ITEM", "TEST-LOC").Return(nil, ErrStockNotFound)
+	mockStorage.On("CreateStock", spanCtx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
+	mockStorage.On("CreateTransaction", spanCtx, mock.MatchedBy(func(tx *Transaction) bool {
+		return tx.Metadata[MetadataRequestID] == "req-001"
+	})).Return(nil)
+	mockStorage.On("GetReorderPoint", spanCtx, "TEST-ITEM", "TEST-LOC").Return(nil, ErrReorderPointNotFound)
+	mockStorage.On("GetAlertRulesForStock", spanCtx, "TEST-ITEM", "TEST-LOC").Return([]AlertRule{}, nil)
+
+	err := manager.Add(ctx, "TEST-ITEM", "TEST-LOC", 10, "REF-001")
+
+	assert.NoError(t, err)
+	mockStorage.AssertExpectations(t)
+}
+
 func TestManager_Remove(t *testing.T) {
 	mockStorage := new(MockStorage)
 	logger := zap.NewNop()
@@ -204,11 +925,15 @@ func TestManager_Remove(t *testing.T) {
 	}
 
 	// モックの期待値設定
-	mockStorage.On("GetItem", ctx, "TEST-ITEM").Return(item, nil)
-	mockStorage.On("GetLocation", ctx, "TEST-LOC").Return(location, nil)
-	mockStorage.On("GetStock", ctx, "TEST-ITEM", "TEST-LOC").Return(stock, nil)
-	mockStorage.On("UpdateStock", ctx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
-	mockStorage.On("CreateTransaction", ctx, mock.AnythingOfType("*inventory.Transaction")).Return(nil)
+	mockStorage.On("GetItem", spanCtx, "TEST-ITEM").Return(item, nil)
+	mockStorage.On("GetLocation", spanCtx, "TEST-LOC").Return(location, nil)
+	mockStorage.On("GetStock", primaryReadCtx, "TEST-ITEM", "TEST-LOC").Return(stock, nil)
+	mockStorage.On("ListConsignmentStocks", spanCtx, ConsignmentStockFilter{ItemID: "TEST-ITEM", LocationID: "TEST-LOC"}).Return([]ConsignmentStock{}, nil)
+	mockStorage.On("ListChannelAllocations", spanCtx, "TEST-ITEM", "TEST-LOC").Return([]ChannelAllocation{}, nil)
+	mockStorage.On("UpdateStock", spanCtx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
+	mockStorage.On("CreateTransaction", spanCtx, mock.AnythingOfType("*inventory.Transaction")).Return(nil)
+	mockStorage.On("GetReorderPoint", spanCtx, "TEST-ITEM", "TEST-LOC").Return(nil, ErrReorderPointNotFound)
+	mockStorage.On("GetAlertRulesForStock", spanCtx, "TEST-ITEM", "TEST-LOC").Return([]AlertRule{}, nil)
 
 	// テスト実行
 	err := manager.Remove(ctx, "TEST-ITEM", "TEST-LOC", 50, "TEST-REF")
@@ -219,6 +944,49 @@ func TestManager_Remove(t *testing.T) {
 }
 
 // TestManager_InsufficientStock は在庫不足エラーのテスト
+// TestManager_RetryOnVersionMismatch はバージョン競合時の自動リトライのテスト
+func TestManager_RetryOnVersionMismatch(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{
+		DefaultLocation:   "DEFAULT",
+		LowStockThreshold: 10,
+		RetryMaxAttempts:  3,
+		RetryBaseDelay:    time.Millisecond,
+	}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	item := &Item{ID: "TEST-ITEM", Name: "テスト商品"}
+	location := &Location{ID: "TEST-LOC", Name: "テストロケーション"}
+
+	mockStorage.On("GetItem", spanCtx, "TEST-ITEM").Return(item, nil)
+	mockStorage.On("GetLocation", spanCtx, "TEST-LOC").Return(location, nil)
+	stock := &Stock{ItemID: "TEST-ITEM", LocationID: "TEST-LOC", Quantity: 50, Available: 50, Version: 1}
+	mockStorage.On("GetStock", primaryReadCtx, "TEST-ITEM", "TEST-LOC").Return(stock, nil)
+	// 1回目はバージョン競合、2回目で成功
+	mockStorage.On("UpdateStock", spanCtx, mock.AnythingOfType("*inventory.Stock")).Return(ErrVersionMismatch).Once()
+	mockStorage.On("UpdateStock", spanCtx, mock.AnythingOfType("*inventory.Stock")).Return(nil).Once()
+	mockStorage.On("CreateTransaction", spanCtx, mock.AnythingOfType("*inventory.Transaction")).Return(nil)
+	mockStorage.On("GetReorderPoint", spanCtx, "TEST-ITEM", "TEST-LOC").Return(nil, ErrReorderPointNotFound)
+	mockStorage.On("GetAlertRulesForStock", spanCtx, "TEST-ITEM", "TEST-LOC").Return([]AlertRule{}, nil)
+
+	err := manager.Add(ctx, "TEST-ITEM", "TEST-LOC", 10, "TEST-REF")
+
+	assert.NoError(t, err)
+	mockStorage.AssertNumberOfCalls(t, "GetStock", 2)
+	mockStorage.AssertNumberOfCalls(t, "UpdateStock", 2)
+
+	// リトライ回数を使い切った場合は競合エラーを返す
+	mockStorage.On("UpdateStock", spanCtx, mock.AnythingOfType("*inventory.Stock")).Return(ErrVersionMismatch).Times(3)
+
+	err = manager.Add(ctx, "TEST-ITEM", "TEST-LOC", 10, "TEST-REF")
+
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+	mockStorage.AssertNumberOfCalls(t, "UpdateStock", 5)
+}
+
 func TestManager_InsufficientStock(t *testing.T) {
 	mockStorage := new(MockStorage)
 	logger := zap.NewNop()
@@ -252,9 +1020,9 @@ func TestManager_InsufficientStock(t *testing.T) {
 	}
 
 	// モックの期待値設定
-	mockStorage.On("GetItem", ctx, "TEST-ITEM").Return(item, nil)
-	mockStorage.On("GetLocation", ctx, "TEST-LOC").Return(location, nil)
-	mockStorage.On("GetStock", ctx, "TEST-ITEM", "TEST-LOC").Return(stock, nil)
+	mockStorage.On("GetItem", spanCtx, "TEST-ITEM").Return(item, nil)
+	mockStorage.On("GetLocation", spanCtx, "TEST-LOC").Return(location, nil)
+	mockStorage.On("GetStock", primaryReadCtx, "TEST-ITEM", "TEST-LOC").Return(stock, nil)
 
 	// テスト実行 - 在庫数を超える削除を試行
 	err := manager.Remove(ctx, "TEST-ITEM", "TEST-LOC", 50, "TEST-REF")
@@ -289,8 +1057,18 @@ func TestManager_Reserve(t *testing.T) {
 	}
 
 	// モックの期待値設定
-	mockStorage.On("GetStock", ctx, "TEST-ITEM", "TEST-LOC").Return(stock, nil)
-	mockStorage.On("UpdateStock", ctx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
+	mockStorage.On("GetStock", primaryReadCtx, "TEST-ITEM", "TEST-LOC").Return(stock, nil)
+	mockStorage.On("ListChannelAllocations", spanCtx, "TEST-ITEM", "TEST-LOC").Return([]ChannelAllocation{}, nil)
+	mockStorage.On("ListReservations", spanCtx, ReservationFilter{ItemID: "TEST-ITEM", LocationID: "TEST-LOC", Status: ReservationStatusActive}).Return([]Reservation{}, nil)
+	mockStorage.On("UpdateStock", spanCtx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
+	mockStorage.On("CreateReservation", spanCtx, mock.MatchedBy(func(r *Reservation) bool {
+		return r.ID != "" && r.Quantity == 30 && r.Reference == "TEST-RESERVE" && r.Status == ReservationStatusActive
+	})).Return(nil)
+	mockStorage.On("CreateTransaction", spanCtx, mock.MatchedBy(func(tx *Transaction) bool {
+		return tx.Type == TransactionTypeReserve && tx.Quantity == 30 && tx.FromLocation != nil && *tx.FromLocation == "TEST-LOC" &&
+			tx.Metadata[MetadataReservationID] != ""
+	})).Return(nil)
+	mockStorage.On("GetAlertRulesForStock", spanCtx, "TEST-ITEM", "TEST-LOC").Return([]AlertRule{}, nil)
 
 	// テスト実行
 	err := manager.Reserve(ctx, "TEST-ITEM", "TEST-LOC", 30, "TEST-RESERVE")
@@ -300,6 +1078,105 @@ func TestManager_Reserve(t *testing.T) {
 	mockStorage.AssertExpectations(t)
 }
 
+// recordingPublisher は発行された在庫変更イベントとドメインイベントを記録するテスト用の発行者
+type recordingPublisher struct {
+	changes []StockChangedEvent
+	events  []DomainEvent
+}
+
+func (p *recordingPublisher) PublishStockChanged(ctx context.Context, event StockChangedEvent) error {
+	p.changes = append(p.changes, event)
+	return nil
+}
+
+func (p *recordingPublisher) PublishLowStockAlert(ctx context.Context, event LowStockAlertEvent) error {
+	return nil
+}
+
+func (p *recordingPublisher) PublishItemTransferred(ctx context.Context, event ItemTransferredEvent) error {
+	return nil
+}
+
+func (p *recordingPublisher) PublishEvent(ctx context.Context, event DomainEvent) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+// TestManager_DomainEvents は予約・商品作成時のドメインイベント発行のテスト
+func TestManager_DomainEvents(t *testing.T) {
+	mockStorage := new(MockStorage)
+	publisher := &recordingPublisher{}
+	manager := NewManager(mockStorage, publisher, zap.NewNop(), &Config{LowStockThreshold: 10})
+	ctx := context.Background()
+
+	stock := &Stock{
+		ItemID:     "TEST-ITEM",
+		LocationID: "TEST-LOC",
+		Quantity:   100,
+		Available:  100,
+		Version:    1,
+	}
+	item := &Item{ID: "TEST-ITEM", Name: "テスト商品"}
+
+	mockStorage.On("GetStock", primaryReadCtx, "TEST-ITEM", "TEST-LOC").Return(stock, nil)
+	mockStorage.On("ListChannelAllocations", spanCtx, "TEST-ITEM", "TEST-LOC").Return([]ChannelAllocation{}, nil)
+	mockStorage.On("ListReservations", spanCtx, ReservationFilter{ItemID: "TEST-ITEM", LocationID: "TEST-LOC", Status: ReservationStatusActive}).Return([]Reservation{}, nil)
+	mockStorage.On("UpdateStock", spanCtx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
+	mockStorage.On("CreateReservation", spanCtx, mock.AnythingOfType("*inventory.Reservation")).Return(nil)
+	mockStorage.On("CreateTransaction", spanCtx, mock.AnythingOfType("*inventory.Transaction")).Return(nil)
+	mockStorage.On("GetAlertRulesForStock", spanCtx, "TEST-ITEM", "TEST-LOC").Return([]AlertRule{}, nil)
+	mockStorage.On("CreateItem", ctx, item).Return(nil)
+	mockStorage.On("GetUnit", ctx, UoMEach).Return(&UnitOfMeasure{Code: UoMEach, Name: "個"}, nil)
+	resolvedAt := time.Now()
+	mockStorage.On("ResolveAlert", spanCtx, "ALERT-1", "system", "").Return(nil)
+	mockStorage.On("GetAlert", spanCtx, "ALERT-1").Return(&StockAlert{ID: "ALERT-1", ItemID: "TEST-ITEM", LocationID: "TEST-LOC", Severity: AlertSeverityWarning, ResolvedAt: &resolvedAt, ResolvedBy: "system"}, nil)
+
+	assert.NoError(t, manager.Reserve(ctx, "TEST-ITEM", "TEST-LOC", 30, "TEST-RESERVE"))
+	assert.NoError(t, manager.CreateItem(ctx, item))
+	assert.NoError(t, manager.ResolveAlert(ctx, "ALERT-1"))
+
+	// 予約は在庫数量を変えずに利用可能数を減らす在庫変更イベントも発行する
+	if assert.Len(t, publisher.changes, 1) {
+		change := publisher.changes[0]
+		assert.Equal(t, "reserve", change.ChangeType)
+		assert.Equal(t, int64(100), change.NewQuantity)
+		assert.Equal(t, int64(0), change.Delta)
+		assert.Equal(t, int64(70), *change.Available)
+		assert.NotEmpty(t, change.TransactionID)
+	}
+
+	if assert.Len(t, publisher.events, 3) {
+		reserved := publisher.events[0]
+		assert.Equal(t, EventTypeReservationCreated, reserved.Type)
+		assert.Equal(t, "TEST-LOC", reserved.LocationID)
+		assert.NotEmpty(t, reserved.ID)
+		data, ok := reserved.Data.(ReservationEvent)
+		assert.True(t, ok)
+		assert.NotEmpty(t, data.ReservationID)
+		assert.NotEmpty(t, data.TransactionID)
+		assert.Equal(t, ReservationEvent{
+			ReservationID: data.ReservationID,
+			TransactionID: data.TransactionID,
+			ItemID:        "TEST-ITEM",
+			LocationID:    "TEST-LOC",
+			Quantity:      30,
+			Reserved:      30,
+			Available:     70,
+			Reference:     "TEST-RESERVE",
+			UserID:        "system",
+		}, reserved.Data)
+
+		assert.Equal(t, EventTypeItemCreated, publisher.events[1].Type)
+		assert.Equal(t, item, publisher.events[1].Data)
+
+		resolved := publisher.events[2]
+		assert.Equal(t, EventTypeAlertResolved, resolved.Type)
+		assert.Equal(t, "TEST-LOC", resolved.LocationID)
+		assert.Equal(t, AlertResolvedEvent{AlertID: "ALERT-1", ItemID: "TEST-ITEM", LocationID: "TEST-LOC", Severity: AlertSeverityWarning, ResolvedAt: &resolvedAt, ResolvedBy: "system"}, resolved.Data)
+	}
+	mockStorage.AssertExpectations(t)
+}
+
 // TestManager_BatchOperation はバッチ操作のテスト
 func TestManager_BatchOperation(t *testing.T) {
 	mockStorage := new(MockStorage)
@@ -337,11 +1214,14 @@ func TestManager_BatchOperation(t *testing.T) {
 	}
 
 	// モックの期待値設定
-	mockStorage.On("GetItem", ctx, "TEST-ITEM").Return(item, nil)
-	mockStorage.On("GetLocation", ctx, "TEST-LOC").Return(location, nil)
-	mockStorage.On("GetStock", ctx, "TEST-ITEM", "TEST-LOC").Return(nil, ErrStockNotFound)
-	mockStorage.On("CreateStock", ctx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
-	mockStorage.On("CreateTransaction", ctx, mock.AnythingOfType("*inventory.Transaction")).Return(nil)
+	mockStorage.On("GetItem", spanCtx, "TEST-ITEM").Return(item, nil)
+	mockStorage.On("GetLocation", spanCtx, "TEST-LOC").Return(location, nil)
+	mockStorage.On("GetStock", primaryReadCtx, "TEST-ITEM", "TEST-LOC").Return(nil, ErrStockNotFound)
+	mockStorage.On("CreateStock", spanCtx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
+	mockStorage.On("CreateTransaction", spanCtx, mock.AnythingOfType("*inventory.Transaction")).Return(nil)
+	mockStorage.On("GetReorderPoint", spanCtx, "TEST-ITEM", "TEST-LOC").Return(nil, ErrReorderPointNotFound)
+	mockStorage.On("GetAlertRulesForStock", spanCtx, "TEST-ITEM", "TEST-LOC").Return([]AlertRule{}, nil)
+	mockStorage.On("SaveBatchOperation", spanCtx, mock.AnythingOfType("*inventory.BatchOperation")).Return(nil)
 
 	// テスト実行
 	batch, err := manager.ExecuteBatch(ctx, operations)
@@ -372,13 +1252,14 @@ func TestManager_GetTotalStock(t *testing.T) {
 
 	// モックの期待値設定
 	mockStorage.On("GetItem", ctx, "TEST-ITEM").Return(item, nil)
+	mockStorage.On("GetTotalStockByItem", ctx, "TEST-ITEM").Return(int64(150), nil)
 
 	// テスト実行
 	totalStock, err := manager.GetTotalStock(ctx, "TEST-ITEM")
 
 	// アサーション
 	assert.NoError(t, err)
-	assert.Equal(t, int64(0), totalStock) // 現在の実装では0を返す
+	assert.Equal(t, int64(150), totalStock)
 	mockStorage.AssertExpectations(t)
 }
 
@@ -413,7 +1294,7 @@ func TestManager_GetHistoryByDateRange(t *testing.T) {
 
 	// モックの期待値設定
 	mockStorage.On("GetItem", ctx, "TEST-ITEM").Return(item, nil)
-	mockStorage.On("GetTransactionHistory", ctx, "TEST-ITEM", 10000).Return(transactions, nil)
+	mockStorage.On("GetTransactionHistoryByDateRange", ctx, "TEST-ITEM", from, to).Return(transactions, nil)
 
 	// テスト実行
 	result, err := manager.GetHistoryByDateRange(ctx, "TEST-ITEM", from, to)
@@ -425,6 +1306,36 @@ func TestManager_GetHistoryByDateRange(t *testing.T) {
 	mockStorage.AssertExpectations(t)
 }
 
+// TestManager_GetHistoryByReference は参照番号別履歴取得のテスト
+func TestManager_GetHistoryByReference(t *testing.T) {
+	mockStorage := new(MockStorage)
+	logger := zap.NewNop()
+	config := &Config{}
+
+	manager := NewManager(mockStorage, nil, logger, config)
+	ctx := context.Background()
+
+	transactions := []Transaction{
+		{ID: "TX-002", Type: TransactionTypeTransfer, ItemID: "TEST-ITEM", Quantity: 20, Reference: "PO-2024-001"},
+		{ID: "TX-001", Type: TransactionTypeInbound, ItemID: "TEST-ITEM", Quantity: 100, Reference: "PO-2024-001"},
+	}
+
+	// モックの期待値設定
+	mockStorage.On("GetTransactionsByReference", ctx, "PO-2024-001").Return(transactions, nil)
+
+	// テスト実行
+	result, err := manager.GetHistoryByReference(ctx, "PO-2024-001")
+
+	// アサーション
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+
+	// 参照番号が空の場合はストレージを呼び出さない
+	_, err = manager.GetHistoryByReference(ctx, "")
+	assert.Error(t, err)
+	mockStorage.AssertExpectations(t)
+}
+
 // TestValidationErrors はバリデーションエラーのテスト
 func TestValidationErrors(t *testing.T) {
 	mockStorage := new(MockStorage)
@@ -478,11 +1389,13 @@ func BenchmarkManager_Add(b *testing.B) {
 	}
 
 	// モックの期待値設定
-	mockStorage.On("GetItem", ctx, "TEST-ITEM").Return(item, nil)
-	mockStorage.On("GetLocation", ctx, "TEST-LOC").Return(location, nil)
-	mockStorage.On("GetStock", ctx, "TEST-ITEM", "TEST-LOC").Return(nil, ErrStockNotFound)
-	mockStorage.On("CreateStock", ctx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
-	mockStorage.On("CreateTransaction", ctx, mock.AnythingOfType("*inventory.Transaction")).Return(nil)
+	mockStorage.On("GetItem", spanCtx, "TEST-ITEM").Return(item, nil)
+	mockStorage.On("GetLocation", spanCtx, "TEST-LOC").Return(location, nil)
+	mockStorage.On("GetStock", primaryReadCtx, "TEST-ITEM", "TEST-LOC").Return(nil, ErrStockNotFound)
+	mockStorage.On("CreateStock", spanCtx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
+	mockStorage.On("CreateTransaction", spanCtx, mock.AnythingOfType("*inventory.Transaction")).Return(nil)
+	mockStorage.On("GetReorderPoint", spanCtx, "TEST-ITEM", "TEST-LOC").Return(nil, ErrReorderPointNotFound)
+	mockStorage.On("GetAlertRulesForStock", spanCtx, "TEST-ITEM", "TEST-LOC").Return([]AlertRule{}, nil)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -514,3 +1427,140 @@ func BenchmarkManager_GetStock(b *testing.B) {
 		manager.GetStock(ctx, "TEST-ITEM", "TEST-LOC")
 	}
 }
+
+// TestManager_ExecuteBatchCompletedEvent はバッチ完了イベントに件数と操作ごとのエラーが含まれるテスト
+func TestManager_ExecuteBatchCompletedEvent(t *testing.T) {
+	mockStorage := new(MockStorage)
+	publisher := &recordingPublisher{}
+	manager := NewManager(mockStorage, publisher, zap.NewNop(), &Config{})
+	ctx := context.Background()
+
+	item := &Item{ID: "TEST-ITEM", Name: "テスト商品"}
+	location := &Location{ID: "TEST-LOC", Name: "テストロケーション"}
+	mockStorage.On("GetItem", spanCtx, "TEST-ITEM").Return(item, nil)
+	mockStorage.On("GetLocation", spanCtx, "TEST-LOC").Return(location, nil)
+	mockStorage.On("GetStock", primaryReadCtx, "TEST-ITEM", "TEST-LOC").Return(nil, ErrStockNotFound)
+	mockStorage.On("CreateStock", spanCtx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
+	mockStorage.On("CreateTransaction", spanCtx, mock.AnythingOfType("*inventory.Transaction")).Return(nil)
+	mockStorage.On("GetReorderPoint", spanCtx, "TEST-ITEM", "TEST-LOC").Return(nil, ErrReorderPointNotFound)
+	mockStorage.On("GetAlertRulesForStock", spanCtx, "TEST-ITEM", "TEST-LOC").Return([]AlertRule{}, nil)
+	mockStorage.On("SaveBatchOperation", spanCtx, mock.AnythingOfType("*inventory.BatchOperation")).Return(nil)
+
+	batch, err := manager.ExecuteBatch(ctx, []InventoryOperation{
+		{Type: OperationTypeAdd, ItemID: "TEST-ITEM", LocationID: "TEST-LOC", Quantity: 10, Reference: "BATCH-001"},
+		{Type: OperationTypeAdd, ItemID: "TEST-ITEM", LocationID: "TEST-LOC", Quantity: -1, Reference: "BATCH-001"},
+	})
+	require.NoError(t, err)
+
+	require.NotEmpty(t, publisher.events)
+	event := publisher.events[len(publisher.events)-1]
+	assert.Equal(t, EventTypeBatchCompleted, event.Type)
+
+	data, ok := event.Data.(BatchCompletedEvent)
+	require.True(t, ok)
+	assert.Equal(t, batch.ID, data.BatchID)
+	assert.Equal(t, BatchStatusFailed, data.Status)
+	assert.False(t, data.Atomic)
+	assert.Equal(t, 2, data.TotalCount)
+	assert.Equal(t, 1, data.SuccessCount)
+	assert.Equal(t, 1, data.FailureCount)
+	require.Len(t, data.Errors, 1)
+	assert.Equal(t, 1, data.Errors[0].OperationIndex)
+	assert.Equal(t, OperationTypeAdd, data.Errors[0].Type)
+	assert.Equal(t, "TEST-ITEM", data.Errors[0].ItemID)
+	assert.NotEmpty(t, data.Errors[0].Error)
+	assert.NotNil(t, data.CompletedAt)
+}
+
+func TestManager_ValuationAndAnalytics(t *testing.T) {
+	mockStorage := new(MockStorage)
+	var manager InventoryManager = NewManager(mockStorage, nil, zap.NewNop(), &Config{})
+	ctx := context.Background()
+
+	// ハンドラーは型アサーションで評価・分析エンジンを取得する
+	valuation, ok := manager.(ValuationEngine)
+	require.True(t, ok)
+	analytics, ok := manager.(AnalyticsEngine)
+	require.True(t, ok)
+
+	location := "TEST-LOC"
+	older, newer := 100.0, 120.0
+	now := time.Now()
+	mockStorage.On("GetStock", ctx, "TEST-ITEM", location).Return(&Stock{ItemID: "TEST-ITEM", LocationID: location, Quantity: 15}, nil)
+	mockStorage.On("ListConsignmentStocks", ctx, ConsignmentStockFilter{ItemID: "TEST-ITEM", LocationID: location}).Return([]ConsignmentStock{}, nil)
+	mockStorage.On("GetTransactionHistory", ctx, "TEST-ITEM", mock.Anything).Return([]Transaction{
+		{Type: TransactionTypeInbound, ItemID: "TEST-ITEM", ToLocation: &location, Quantity: 10, UnitCost: &older, CreatedAt: now.Add(-2 * time.Hour)},
+		{Type: TransactionTypeInbound, ItemID: "TEST-ITEM", ToLocation: &location, Quantity: 10, UnitCost: &newer, CreatedAt: now.Add(-time.Hour)},
+	}, nil)
+
+	// FIFO: 古い入庫10個（100）＋新しい入庫5個（120）
+	value, err := valuation.CalculateValue(ctx, "TEST-ITEM", location, ValuationMethodFIFO)
+	require.NoError(t, err)
+	assert.InDelta(t, 1600.0, value, 0.001)
+
+	averageCost, err := valuation.GetAverageCost(ctx, "TEST-ITEM")
+	require.NoError(t, err)
+	assert.InDelta(t, 110.0, averageCost, 0.001)
+
+	mockStorage.On("GetTransactionHistory", ctx, "IDLE-ITEM", mock.Anything).Return([]Transaction{}, nil)
+	rate, err := analytics.GetTurnoverRate(ctx, "IDLE-ITEM", 30*24*time.Hour)
+	require.NoError(t, err)
+	assert.Zero(t, rate)
+}
+
+func TestManager_ItemAndLocationLifecycle(t *testing.T) {
+	mockStorage := new(MockStorage)
+	publisher := &recordingPublisher{}
+	manager := NewManager(mockStorage, publisher, zap.NewNop(), &Config{})
+	ctx := context.Background()
+
+	// 検証に失敗した場合はストレージを呼び出さない
+	var validationErr *ValidationError
+	assert.ErrorAs(t, manager.CreateItem(ctx, &Item{ID: "BAD ID", Name: "テスト商品"}), &validationErr)
+	assert.ErrorAs(t, manager.UpdateItem(ctx, &Item{ID: "TEST-ITEM"}), &validationErr)
+	assert.ErrorAs(t, manager.CreateLocation(ctx, &Location{ID: "TEST-LOC", Capacity: -1, Name: "テストロケーション"}), &validationErr)
+	_, err := manager.GetItem(ctx, "")
+	assert.ErrorAs(t, err, &validationErr)
+	_, err = manager.ListItems(ctx, -1, 10)
+	assert.ErrorAs(t, err, &validationErr)
+	_, err = manager.ListLocations(ctx, 0, 0)
+	assert.ErrorAs(t, err, &validationErr)
+	_, err = manager.SearchItems(ctx, "  ")
+	assert.ErrorAs(t, err, &validationErr)
+	mockStorage.AssertNotCalled(t, "CreateItem", mock.Anything, mock.Anything)
+	mockStorage.AssertNotCalled(t, "UpdateItem", mock.Anything, mock.Anything)
+	mockStorage.AssertNotCalled(t, "CreateLocation", mock.Anything, mock.Anything)
+	assert.Empty(t, publisher.events)
+
+	item := &Item{ID: "TEST-ITEM", Name: "テスト商品"}
+	location := &Location{ID: "TEST-LOC", Name: "テストロケーション"}
+	mockStorage.On("CreateItem", ctx, item).Return(nil)
+	mockStorage.On("GetUnit", ctx, UoMEach).Return(&UnitOfMeasure{Code: UoMEach, Name: "個"}, nil)
+	mockStorage.On("GetItem", ctx, "TEST-ITEM").Return(&Item{ID: "TEST-ITEM", Name: "テスト商品", BaseUoM: UoMEach}, nil)
+	mockStorage.On("UpdateItem", ctx, item).Return(nil)
+	mockStorage.On("DeleteItem", ctx, "TEST-ITEM").Return(nil)
+	mockStorage.On("CreateLocation", ctx, location).Return(nil)
+	mockStorage.On("UpdateLocation", ctx, location).Return(nil)
+	mockStorage.On("DeleteLocation", ctx, "TEST-LOC").Return(nil)
+
+	require.NoError(t, manager.CreateItem(ctx, item))
+	assert.False(t, item.CreatedAt.IsZero())
+	assert.Equal(t, item.CreatedAt, item.UpdatedAt)
+	createdAt := item.CreatedAt
+	time.Sleep(time.Millisecond)
+	require.NoError(t, manager.UpdateItem(ctx, item))
+	assert.True(t, item.UpdatedAt.After(createdAt))
+	require.NoError(t, manager.DeleteItem(ctx, "TEST-ITEM"))
+	require.NoError(t, manager.CreateLocation(ctx, location))
+	require.NoError(t, manager.UpdateLocation(ctx, location))
+	require.NoError(t, manager.DeleteLocation(ctx, "TEST-LOC"))
+
+	var types []string
+	for _, event := range publisher.events {
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []string{
+		EventTypeItemCreated, EventTypeItemUpdated, EventTypeItemDeleted,
+		EventTypeLocationCreated, EventTypeLocationUpdated, EventTypeLocationDeleted,
+	}, types)
+}
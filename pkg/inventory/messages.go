@@ -0,0 +1,429 @@
+package inventory
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Language identifies the language of error messages
+// エラーメッセージの言語を表現
+type Language string
+
+const (
+	LanguageJapanese Language = "ja" // 日本語（既定）
+	LanguageEnglish  Language = "en" // 英語
+)
+
+// DefaultLanguage is the language the messages in this package are written in
+// このパッケージのメッセージが記述されている言語
+const DefaultLanguage = LanguageJapanese
+
+var (
+	catalogMu sync.RWMutex
+
+	// messageCatalog maps Japanese messages to their translations
+	// 日本語のメッセージから各言語の翻訳への対応
+	messageCatalog = map[Language]map[string]string{
+		LanguageEnglish: {
+			// errors.go のエラー
+			ErrItemNotFound.Error():                 "item not found",
+			ErrLocationNotFound.Error():             "location not found",
+			ErrInsufficientStock.Error():            "insufficient stock",
+			ErrNegativeQuantity.Error():             "quantity must be positive",
+			ErrStockNotFound.Error():                "stock record not found",
+			ErrVersionMismatch.Error():              "version mismatch: the record was updated by another user",
+			ErrDuplicateItem.Error():                "item already exists",
+			ErrDuplicateLocation.Error():            "location already exists",
+			ErrInvalidReference.Error():             "invalid reference",
+			ErrTransactionFailed.Error():            "transaction failed",
+			ErrTransactionNotFound.Error():          "transaction record not found",
+			ErrLotNotFound.Error():                  "lot not found",
+			ErrExpiredLot.Error():                   "lot has expired",
+			ErrReservationNotFound.Error():          "reservation not found",
+			ErrReservationNotActive.Error():         "the reservation has already been released or has expired",
+			ErrBackorderNotFound.Error():            "backorder not found",
+			ErrBackorderNotPending.Error():          "the backorder has already been allocated or cancelled",
+			ErrReorderPointNotFound.Error():         "no reorder point is set",
+			ErrInsufficientReservation.Error():      "insufficient reserved quantity",
+			ErrLocationCapacityExceeded.Error():     "the operation exceeds the capacity of the location",
+			ErrAlertNotFound.Error():                "alert not found",
+			ErrAlertNotActive.Error():               "the alert has already been resolved",
+			ErrAlertAlreadyAcknowledged.Error():     "the alert has already been acknowledged",
+			ErrAlertRuleNotFound.Error():            "alert rule not found",
+			ErrBatchNotFound.Error():                "batch operation not found",
+			ErrBatchNotCancellable.Error():          "the batch cannot be cancelled: it has finished or runs on another instance",
+			ErrBatchQueueFull.Error():               "the batch queue is full",
+			ErrBatchQueueClosed.Error():             "the batch queue is shut down",
+			ErrSnapshotNotFound.Error():             "stock snapshot not found",
+			ErrStocktakeNotFound.Error():            "stocktake not found",
+			ErrStocktakeStatus.Error():              "the operation is not allowed in the current status of the stocktake",
+			ErrAdjustmentNotFound.Error():           "stock adjustment not found",
+			ErrAdjustmentNotPending.Error():         "the stock adjustment is not pending approval",
+			ErrInspectionNotFound.Error():           "receiving inspection not found",
+			ErrInspectionNotQuarantined.Error():     "the receiving inspection is not quarantined",
+			ErrTransactionNotReversible.Error():     "the transaction cannot be reversed",
+			ErrTransactionAlreadyReversed.Error():   "the transaction has already been reversed",
+			ErrInsufficientLotStock.Error():         "insufficient lot stock",
+			ErrInsufficientConsignmentStock.Error(): "insufficient consignment stock",
+			ErrConsignmentStockMismatch.Error():     "the consignment stock exceeds the stock quantity",
+			ErrLotStockNotFound.Error():             "lot stock not found at the location",
+			ErrUnitNotFound.Error():                 "unit of measure not found",
+			ErrDuplicateUnit.Error():                "unit of measure already exists",
+			ErrUnitInUse.Error():                    "the unit of measure is in use by items or conversions",
+			ErrUnitConversionNotFound.Error():       "no conversion is registered for the unit of measure",
+			ErrBOMNotFound.Error():                  "bill of materials not found",
+			ErrAssemblyNotFound.Error():             "assembly not found",
+			ErrItemInUse.Error():                    "the item is used as a component of a bill of materials",
+			ErrSupplierNotFound.Error():             "supplier not found",
+			ErrDuplicateSupplier.Error():            "supplier already exists",
+			ErrSupplierItemNotFound.Error():         "the supplier has no purchasing terms for the item",
+			ErrPurchaseOrderNotFound.Error():        "purchase order not found",
+			ErrPurchaseOrderStatus.Error():          "the operation is not allowed in the current status of the purchase order",
+			ErrReceiptExceedsOrder.Error():          "the received quantity exceeds the outstanding quantity of the purchase order",
+			ErrSalesOrderNotFound.Error():           "sales order not found",
+			ErrSalesOrderStatus.Error():             "the operation is not allowed in the current status of the sales order",
+			ErrReturnNotFound.Error():               "return not found",
+			ErrReturnStatus.Error():                 "the operation is not allowed in the current status of the return",
+			ErrReturnQuantityExceeded.Error():       "the quantity exceeds the authorized quantity or the quantity awaiting inspection of the return",
+			ErrPreconditionFailed.Error():           "the record is not at the expected version: it was updated by another user",
+
+			// バリデーション・ビジネスルールのメッセージ
+			"数量は正の値である必要があります":                        "quantity must be positive",
+			"負の在庫は許可されていません":                          "negative stock is not allowed",
+			"負の数量は許可されていません":                          "negative quantity is not allowed",
+			"数量が有効範囲を超えています":                          "quantity is out of range",
+			"ロット数量は0以上である必要があります":                     "lot quantity must be zero or greater",
+			"移動元と移動先が同じです":                            "source and destination locations are the same",
+			"商品IDが指定されていません":                          "item ID is required",
+			"商品IDが空です":                                "item ID is empty",
+			"商品IDが長すぎます":                              "item ID is too long",
+			"商品IDに無効な文字が含まれています":                      "item ID contains invalid characters",
+			"商品が指定されていません":                            "item is required",
+			"商品名が空です":                                 "item name is empty",
+			"商品名が長すぎます":                               "item name is too long",
+			"ロケーションIDが指定されていません":                      "location ID is required",
+			"ロケーションIDが空です":                            "location ID is empty",
+			"ロケーションIDが長すぎます":                          "location ID is too long",
+			"ロケーションIDに無効な文字が含まれています":                  "location ID contains invalid characters",
+			"ロケーションが指定されていません":                        "location is required",
+			"ロケーション名が空です":                             "location name is empty",
+			"ロケーション名が長すぎます":                           "location name is too long",
+			"SKUが長すぎます":                               "SKU is too long",
+			"SKUに無効な文字が含まれています":                       "SKU contains invalid characters",
+			"説明が長すぎます":                                "description is too long",
+			"カテゴリが長すぎます":                              "category is too long",
+			"単価は0以上である必要があります":                        "unit cost must be zero or greater",
+			"単価が有効範囲を超えています":                          "unit cost is out of range",
+			"単価の下限は上限以下を指定してください":                     "minimum unit cost must not exceed the maximum",
+			"容量は0以上である必要があります":                        "capacity must be zero or greater",
+			"容量が有効範囲を超えています":                          "capacity is out of range",
+			"参照番号が指定されていません":                          "reference is required",
+			"参照番号が長すぎます":                              "reference is too long",
+			"ロットが指定されていません":                           "lot is required",
+			"ロット番号が空です":                               "lot number is empty",
+			"ロット番号が長すぎます":                             "lot number is too long",
+			"ロット番号に無効な文字が含まれています":                     "lot number contains invalid characters",
+			"調整数量は0以外である必要があります":                      "adjustment quantity must not be zero",
+			"在庫が指定されていません":                            "stock is required",
+			"トランザクションが指定されていません":                      "transaction is required",
+			"トランザクションIDが指定されていません":                    "transaction ID is required",
+			"無効なトランザクション種別です":                         "invalid transaction type",
+			"無効なオペレーション種別です":                          "invalid operation type",
+			"アラートが指定されていません":                          "alert is required",
+			"無効なアラート種別です":                             "invalid alert type",
+			"アラートメッセージが空です":                           "alert message is empty",
+			"閾値は0以上である必要があります":                        "threshold must be zero or greater",
+			"閾値が有効範囲を超えています":                          "threshold is out of range",
+			"ユーザーIDが空です":                              "user ID is empty",
+			"ユーザーIDが長すぎます":                            "user ID is too long",
+			"バージョンは1以上である必要があります":                     "version must be 1 or greater",
+			"バッチIDが指定されていません":                         "batch ID is required",
+			"予約IDが指定されていません":                          "reservation ID is required",
+			"予約の状態が正しくありません":                          "invalid reservation status",
+			"バックオーダーIDが指定されていません":                     "backorder ID is required",
+			"スナップショットIDが指定されていません":                    "snapshot ID is required",
+			"棚卸IDが指定されていません":                          "stocktake ID is required",
+			"棚卸の状態が正しくありません":                          "invalid stocktake status",
+			"ABCクラスは A・B・C のいずれかを指定してください":            "ABC class must be A, B or C",
+			"実数が指定されていません":                            "counted quantities are required",
+			"実数は0以上で指定してください":                         "counted quantity must be zero or greater",
+			"同じ商品の実数が複数指定されています":                      "the item is counted more than once",
+			"棚卸にない商品が指定されています":                        "the item is not on the stocktake",
+			"実数を記録していない商品は承認できません":                    "items without a counted quantity cannot be approved",
+			"在庫調整IDが指定されていません":                        "adjustment ID is required",
+			"在庫調整の状態が正しくありません":                        "invalid adjustment status",
+			"在庫調整が指定されていません":                          "adjustment is required",
+			"理由コードが指定されていません":                         "reason code is required",
+			"無効な理由コードです":                              "invalid reason code",
+			"理由コードが other の場合はメモが必要です":                "a note is required when the reason code is other",
+			"入荷検品が指定されていません":                          "inspection is required",
+			"検品IDが指定されていません":                          "inspection ID is required",
+			"入荷検品の状態が正しくありません":                        "invalid inspection status",
+			"優先順位は0〜9999の範囲で指定してください":                 "priority must be between 0 and 9999",
+			"緯度と経度は両方を指定してください":                       "latitude and longitude must be specified together",
+			"緯度は-90〜90の範囲で指定してください":                   "latitude must be between -90 and 90",
+			"経度は-180〜180の範囲で指定してください":                 "longitude must be between -180 and 180",
+			"ロットの消費順序が正しくありません":                       "invalid lot picking strategy",
+			"ロット番号とロットの消費順序は同時に指定できません":               "a lot number and a lot picking strategy cannot be combined",
+			"単位コードが空です":                               "unit code is empty",
+			"単位コードが長すぎます":                             "unit code is too long",
+			"単位コードに無効な文字が含まれています":                     "unit code contains invalid characters",
+			"単位が指定されていません":                            "unit is required",
+			"単位名が空です":                                 "unit name is empty",
+			"単位名が長すぎます":                               "unit name is too long",
+			"小数点以下の桁数は0〜6で指定してください":                   "decimals must be between 0 and 6",
+			"単位の換算が指定されていません":                         "unit conversion is required",
+			"換算係数は正の値である必要があります":                      "conversion factor must be positive",
+			"基本単位の換算は設定できません":                         "a conversion cannot be set for the base unit",
+			"数量の形式が正しくありません":                          "invalid quantity format",
+			"数量の小数点以下の桁数が単位の桁数を超えています":                "quantity has more decimal places than the unit allows",
+			"数量が大きすぎます":                               "quantity is too large",
+			"換算後の数量が基本単位の桁数で表せません":                    "the converted quantity cannot be represented in the base unit",
+			"在庫がある商品の基本単位は変更できません":                    "the base unit of an item with stock cannot be changed",
+			"部品表が指定されていません":                           "bill of materials is required",
+			"構成品が指定されていません":                           "components are required",
+			"構成品の数が上限を超えています":                         "too many components",
+			"キット自身は構成品に指定できません":                       "a kit cannot be a component of itself",
+			"同じ構成品が複数指定されています":                        "duplicate component",
+			"組立IDが指定されていません":                          "assembly ID is required",
+			"仕入先IDが空です":                               "supplier ID is empty",
+			"仕入先IDが長すぎます":                             "supplier ID is too long",
+			"仕入先IDに無効な文字が含まれています":                     "supplier ID contains invalid characters",
+			"所有者の仕入先IDが正しくありません":                      "invalid owner supplier ID",
+			"仕入先が指定されていません":                           "supplier is required",
+			"仕入先名が空です":                                "supplier name is empty",
+			"仕入先名が長すぎます":                              "supplier name is too long",
+			"担当者名が長すぎます":                              "contact name is too long",
+			"メールアドレスの形式が正しくありません":                     "invalid email address",
+			"電話番号が長すぎます":                              "phone number is too long",
+			"住所が長すぎます":                                "address is too long",
+			"仕入先の商品が指定されていません":                        "supplier item is required",
+			"仕入先の品番が長すぎます":                            "supplier SKU is too long",
+			"リードタイムは0〜365日で指定してください":                  "lead time must be between 0 and 365 days",
+			"最小発注数量は0以上である必要があります":                    "minimum order quantity must be zero or greater",
+			"最小発注数量が有効範囲を超えています":                      "minimum order quantity is out of range",
+			"発注が指定されていません":                            "purchase order is required",
+			"発注IDが指定されていません":                          "purchase order ID is required",
+			"発注の行が指定されていません":                          "purchase order lines are required",
+			"発注の行の数が上限を超えています":                        "too many purchase order lines",
+			"同じ商品が複数指定されています":                         "duplicate item",
+			"発注の状態が正しくありません":                          "invalid purchase order status",
+			"無効な仕入先には発注できません":                         "cannot order from an inactive supplier",
+			"発注数量が最小発注数量を下回っています":                     "the ordered quantity is below the minimum order quantity",
+			"入荷が指定されていません":                            "goods receipt is required",
+			"入荷の行が指定されていません":                          "goods receipt lines are required",
+			"発注にない商品が指定されています":                        "the item is not on the purchase order",
+			"受注が指定されていません":                            "sales order is required",
+			"受注IDが指定されていません":                          "sales order ID is required",
+			"受注の行が指定されていません":                          "sales order lines are required",
+			"受注の行の数が上限を超えています":                        "too many sales order lines",
+			"受注の状態が正しくありません":                          "invalid sales order status",
+			"顧客が長すぎます":                                "customer is too long",
+			"受注にない商品が指定されています":                        "the item is not on the sales order",
+			"出荷数量の合計は正の値である必要があります":                   "the total shipped quantity must be positive",
+			"返品が指定されていません":                            "return is required",
+			"返品IDが指定されていません":                          "return ID is required",
+			"返品の行が指定されていません":                          "return lines are required",
+			"返品の行の数が上限を超えています":                        "too many return lines",
+			"返品の状態が正しくありません":                          "invalid return status",
+			"返品理由が長すぎます":                              "return reason is too long",
+			"返品にない商品が指定されています":                        "the item is not on the return",
+			"受注で出荷した数量を超えて返品できません":                    "cannot return more than was shipped on the sales order",
+			"出荷確定していない受注は返品できません":                     "cannot return a sales order that has not been shipped",
+			"返品の処分が指定されていません":                         "return disposition is required",
+			"返品の処分方法が正しくありません":                        "invalid return disposition",
+			"再整備以外の処分では商品・ロケーションを指定できません":             "item and location can only be specified for refurbishment",
+			"廃棄以外の処分では理由コードを指定できません":                  "reason code can only be specified for scrapping",
+			"販売チャネルが空です":                              "sales channel is empty",
+			"販売チャネルが長すぎます":                            "sales channel is too long",
+			"販売チャネルに無効な文字が含まれています":                    "sales channel contains invalid characters",
+			"販売チャネルの確保が指定されていません":                     "channel allocation is required",
+			"確保数量は0以上である必要があります":                      "allocated quantity must be zero or greater",
+			"引当の要求が指定されていません":                         "allocation request is required",
+			"引当の方式が正しくありません":                          "invalid allocation strategy",
+			"proximity の引当には配送先が必要です":                 "proximity allocation requires a destination",
+			"ロケーション数の上限は0以上で指定してください":                 "max locations must not be negative",
+			"取消の理由が指定されていません":                         "reversal reason is required",
+			"取消の理由が長すぎます":                             "reversal reason is too long",
+			"調整後の在庫が負になります":                           "the adjustment would make the stock negative",
+			"バックオーダーの状態が正しくありません":                     "invalid backorder status",
+			"発注点が指定されていません":                           "reorder point is required",
+			"最大在庫数は最小在庫数以上である必要があります":                 "max quantity must be greater than or equal to the min quantity",
+			"最大在庫数は発注点以上である必要があります":                   "max quantity must be greater than or equal to the reorder point",
+			"アラートルールが指定されていません":                       "alert rule is required",
+			"ルール名が空です":                                "rule name is empty",
+			"ルール名が長すぎます":                              "rule name is too long",
+			"無効な判定値です":                                "invalid metric",
+			"無効な比較方法です":                               "invalid comparator",
+			"無効な重要度です":                                "invalid severity",
+			"クールダウンは0以上である必要があります":                    "cooldown must be zero or greater",
+			"アラートルールIDが指定されていません":                     "alert rule ID is required",
+			"アラートIDが指定されていません":                        "alert ID is required",
+			"メモが長すぎます":                                "note is too long",
+			"有効期限は未来の日時で指定してください":                     "expiry must be in the future",
+			"メタデータのキーが指定されていません":                      "metadata key is required",
+			"商品IDとロケーションIDを指定してください":                  "item ID and location ID are required",
+			"開始日が終了日より後になっています":                       "start date is after the end date",
+			"終了日時は開始日時より後である必要があります":                  "end time must be after the start time",
+			"期間は正の値である必要があります":                        "duration must be positive",
+			"アーカイブ基準日時が指定されていません":                     "archive cutoff is required",
+			"アーカイブ基準日時に未来の日時は指定できません":                 "archive cutoff must not be in the future",
+			"カーソルの形式が正しくありません":                        "malformed cursor",
+			"カーソルの並び順が指定と一致しません":                      "cursor does not match the requested sort order",
+			"カーソルにはafter_idとafter_created_atの両方が必要です": "cursor requires both after_id and after_created_at",
+			"検索クエリが空です":                               "search query is empty",
+			"オフセットは0以上で指定してください":                      "offset must be zero or greater",
+			"取得件数は1以上で指定してください":                       "limit must be 1 or greater",
+			"イベント発行者が設定されていません":                       "no event publisher is configured",
+
+			// 一覧の並び替え
+			"並び替えの項目は created_at・name・sku・unit_cost のいずれかを指定してください": "sort must be one of created_at, name, sku or unit_cost",
+		},
+	}
+
+	// errorFormats are the formats of the typed errors per language
+	// 言語ごとの型付きエラーの書式
+	errorFormats = map[Language]struct {
+		validation, business, concurrency, storage, storageCause string
+	}{
+		LanguageEnglish: {
+			validation:   "validation error [%s]: %s (value: %s)",
+			business:     "business rule violation [%s]: %s (context: %s)",
+			concurrency:  "concurrency error [%s:%s]: %s",
+			storage:      "storage error [%s]: %s",
+			storageCause: "storage error [%s]: %s (cause: %s)",
+		},
+	}
+)
+
+// RegisterMessages adds translations of Japanese messages for a language
+// 日本語のメッセージに対する指定言語の翻訳を登録
+//
+// APIサーバーなど、このパッケージ外のメッセージを翻訳する場合に使用します。
+func RegisterMessages(lang Language, messages map[string]string) {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+
+	catalog, ok := messageCatalog[lang]
+	if !ok {
+		catalog = make(map[string]string, len(messages))
+		messageCatalog[lang] = catalog
+	}
+	for message, translation := range messages {
+		catalog[message] = translation
+	}
+}
+
+// Translate returns the translation of a Japanese message
+// 日本語のメッセージを指定言語に翻訳
+//
+// 翻訳が登録されていないメッセージはそのまま返します。
+func Translate(message string, lang Language) string {
+	if lang == DefaultLanguage || message == "" {
+		return message
+	}
+
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	if translation, ok := messageCatalog[lang][message]; ok {
+		return translation
+	}
+	return message
+}
+
+// LocalizeError returns the message of an error in the given language
+// エラーのメッセージを指定言語で返す
+//
+// 型付きのエラー（ValidationError など）は書式と項目のメッセージを翻訳します。
+// ラップされたエラーは、含まれるパッケージのエラーのメッセージのみを翻訳します。
+func LocalizeError(err error, lang Language) string {
+	if err == nil {
+		return ""
+	}
+	if lang == DefaultLanguage {
+		return err.Error()
+	}
+
+	formats, hasFormats := errorFormats[lang]
+	var validationErr *ValidationError
+	var businessErr *BusinessRuleError
+	var concurrencyErr *ConcurrencyError
+	var storageErr *StorageError
+	switch {
+	case hasFormats && errors.As(err, &validationErr) && err.Error() == validationErr.Error():
+		return fmt.Sprintf(formats.validation, validationErr.Field, Translate(validationErr.Message, lang), validationErr.Value)
+	case hasFormats && errors.As(err, &businessErr) && err.Error() == businessErr.Error():
+		return fmt.Sprintf(formats.business, businessErr.Rule, Translate(businessErr.Message, lang), businessErr.Context)
+	case hasFormats && errors.As(err, &concurrencyErr) && err.Error() == concurrencyErr.Error():
+		return fmt.Sprintf(formats.concurrency, concurrencyErr.Operation, concurrencyErr.Resource, Translate(concurrencyErr.Message, lang))
+	case hasFormats && errors.As(err, &storageErr) && err.Error() == storageErr.Error():
+		if storageErr.Cause != nil {
+			return fmt.Sprintf(formats.storageCause, storageErr.Operation, Translate(storageErr.Message, lang), LocalizeError(storageErr.Cause, lang))
+		}
+		return fmt.Sprintf(formats.storage, storageErr.Operation, Translate(storageErr.Message, lang))
+	}
+
+	message := err.Error()
+	if translation := Translate(message, lang); translation != message {
+		return translation
+	}
+	// ラップされたメッセージに含まれる翻訳可能なメッセージを置き換える（長いものから）
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	originals := make([]string, 0)
+	for original := range messageCatalog[lang] {
+		if strings.Contains(message, original) {
+			originals = append(originals, original)
+		}
+	}
+	sort.Slice(originals, func(i, j int) bool { return len(originals[i]) > len(originals[j]) })
+	for _, original := range originals {
+		message = strings.ReplaceAll(message, original, messageCatalog[lang][original])
+	}
+	return message
+}
+
+// ParseAcceptLanguage selects the supported language preferred by an Accept-Language header
+// Accept-Language ヘッダーから対応している言語のうち最も優先されるものを選択
+//
+// 対応している言語が含まれない場合は DefaultLanguage を返します。
+func ParseAcceptLanguage(header string) Language {
+	best := DefaultLanguage
+	bestQuality := -1.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		lang := Language(primary)
+		if !isSupportedLanguage(lang) || quality <= 0 {
+			continue
+		}
+		if quality > bestQuality {
+			best, bestQuality = lang, quality
+		}
+	}
+	return best
+}
+
+// isSupportedLanguage reports whether messages can be returned in lang
+// 指定言語でメッセージを返せるかを判定
+func isSupportedLanguage(lang Language) bool {
+	if lang == DefaultLanguage {
+		return true
+	}
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	_, ok := messageCatalog[lang]
+	return ok
+}
@@ -0,0 +1,54 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	assert.Equal(t, LanguageJapanese, ParseAcceptLanguage(""))
+	assert.Equal(t, LanguageEnglish, ParseAcceptLanguage("en-US,en;q=0.9"))
+	assert.Equal(t, LanguageJapanese, ParseAcceptLanguage("ja,en;q=0.5"))
+	assert.Equal(t, LanguageEnglish, ParseAcceptLanguage("fr;q=1.0, en;q=0.8, ja;q=0.3"))
+	assert.Equal(t, LanguageJapanese, ParseAcceptLanguage("fr, de"))
+	assert.Equal(t, LanguageJapanese, ParseAcceptLanguage("en;q=0"))
+}
+
+func TestLocalizeError(t *testing.T) {
+	// パッケージのエラー
+	assert.Equal(t, "item not found", LocalizeError(ErrItemNotFound, LanguageEnglish))
+	assert.Equal(t, ErrItemNotFound.Error(), LocalizeError(ErrItemNotFound, LanguageJapanese))
+	assert.Equal(t, "backorder not found", LocalizeError(ErrBackorderNotFound, LanguageEnglish))
+	assert.Equal(t, "no reorder point is set", LocalizeError(ErrReorderPointNotFound, LanguageEnglish))
+
+	// ラップされたエラーは含まれるメッセージのみを翻訳
+	wrapped := fmt.Errorf("在庫移動に失敗しました: %w", ErrInsufficientStock)
+	assert.Equal(t, "在庫移動に失敗しました: insufficient stock", LocalizeError(wrapped, LanguageEnglish))
+
+	// 型付きのエラー
+	validationErr := NewValidationError("quantity", "数量は正の値である必要があります", "-1")
+	assert.Equal(t, "validation error [quantity]: quantity must be positive (value: -1)", LocalizeError(validationErr, LanguageEnglish))
+
+	// 一覧・検索の入力検証
+	_, err := (&Manager{}).SearchItems(context.Background(), " ")
+	assert.Equal(t, "validation error [query]: search query is empty (value: )", LocalizeError(err, LanguageEnglish))
+	assert.Equal(t, "validation error [offset]: offset must be zero or greater (value: -1)", LocalizeError(validateOffsetLimit(-1, 10), LanguageEnglish))
+	assert.Equal(t, "validation error [limit]: limit must be 1 or greater (value: 0)", LocalizeError(validateOffsetLimit(0, 0), LanguageEnglish))
+	assert.Equal(t, "validation error [sort]: sort must be one of created_at, name, sku or unit_cost (value: price)", LocalizeError(ItemFilter{SortBy: "price"}.Validate(), LanguageEnglish))
+	_, err = (&Manager{}).GetStockSnapshotLines(context.Background(), "", "")
+	assert.Equal(t, "validation error [snapshot_id]: snapshot ID is required (value: )", LocalizeError(err, LanguageEnglish))
+
+	storageErr := NewStorageError("get_item", "商品取得に失敗しました", ErrItemNotFound)
+	assert.Equal(t, "storage error [get_item]: 商品取得に失敗しました (cause: item not found)", LocalizeError(storageErr, LanguageEnglish))
+}
+
+func TestRegisterMessages(t *testing.T) {
+	RegisterMessages(LanguageEnglish, map[string]string{"テスト用のメッセージ": "test message"})
+
+	assert.Equal(t, "test message", Translate("テスト用のメッセージ", LanguageEnglish))
+	assert.Equal(t, "テスト用のメッセージ", Translate("テスト用のメッセージ", LanguageJapanese))
+	assert.Equal(t, "未登録のメッセージ", Translate("未登録のメッセージ", LanguageEnglish))
+}
@@ -0,0 +1,253 @@
+// Package metrics records Prometheus metrics of the inventory API and manager
+// 在庫APIとマネージャーのPrometheusメトリクスを記録するパッケージ
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// namespace is the prefix of every metric name
+// 全てのメトリクス名の接頭辞
+const namespace = "zai_inventory"
+
+// Collector records request, operation, stock mutation and alert metrics
+// リクエスト・在庫操作・在庫変動・アラートのメトリクスを記録
+//
+// 記録するメトリクス:
+//   - zai_inventory_http_requests_total: ルート・メソッド・ステータスごとのリクエスト数
+//   - zai_inventory_http_request_duration_seconds: ルート・メソッドごとの処理時間
+//   - zai_inventory_manager_operations_total: 在庫操作ごとの成功・失敗数
+//   - zai_inventory_manager_operation_duration_seconds: 在庫操作ごとの処理時間
+//   - zai_inventory_stock_mutations_total: 変更種別ごとの在庫変動の件数
+//   - zai_inventory_stock_units_total: 入庫（in）・出庫（out）した数量の合計
+//   - zai_inventory_transfers_total: ロケーション間の移動の件数
+//   - zai_inventory_alerts_created_total: 種別ごとのアラートの作成数
+//   - zai_inventory_alerts_resolved_total: アラートの解決数
+//
+// マネージャーの inventory.Config.Observer と、イベントの発行先（events.Fanout など）に登録して使用します。
+type Collector struct {
+	requests          *prometheus.CounterVec
+	requestDuration   *prometheus.HistogramVec
+	operations        *prometheus.CounterVec
+	operationDuration *prometheus.HistogramVec
+	mutations         *prometheus.CounterVec
+	units             *prometheus.CounterVec
+	transfers         prometheus.Counter
+	alertsCreated     *prometheus.CounterVec
+	alertsResolved    prometheus.Counter
+}
+
+var (
+	_ inventory.OperationObserver    = (*Collector)(nil)
+	_ inventory.EventPublisher       = (*Collector)(nil)
+	_ inventory.DomainEventPublisher = (*Collector)(nil)
+)
+
+// New creates a Collector and registers its metrics
+// Collectorを作成し、メトリクスを登録
+//
+// registererがnilの場合はprometheus.DefaultRegistererに登録します。
+// 同じメトリクスが登録済みの場合は既存のコレクターを再利用します。
+func New(registerer prometheus.Registerer) (*Collector, error) {
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+
+	c := &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "HTTPリクエスト数",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTPリクエストの処理時間（秒）",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "manager",
+			Name:      "operations_total",
+			Help:      "在庫操作の実行数",
+		}, []string{"operation", "result"}),
+		operationDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "manager",
+			Name:      "operation_duration_seconds",
+			Help:      "在庫操作の処理時間（秒）",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation"}),
+		mutations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "stock",
+			Name:      "mutations_total",
+			Help:      "在庫変動の件数",
+		}, []string{"change_type"}),
+		units: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "stock",
+			Name:      "units_total",
+			Help:      "入庫・出庫した数量の合計",
+		}, []string{"direction"}),
+		transfers: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "transfers_total",
+			Help:      "ロケーション間の在庫移動の件数",
+		}),
+		alertsCreated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "alerts",
+			Name:      "created_total",
+			Help:      "アラートの作成数",
+		}, []string{"type"}),
+		alertsResolved: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "alerts",
+			Name:      "resolved_total",
+			Help:      "アラートの解決数",
+		}),
+	}
+
+	var err error
+	if c.requests, err = registerCollector(registerer, c.requests); err != nil {
+		return nil, err
+	}
+	if c.requestDuration, err = registerCollector(registerer, c.requestDuration); err != nil {
+		return nil, err
+	}
+	if c.operations, err = registerCollector(registerer, c.operations); err != nil {
+		return nil, err
+	}
+	if c.operationDuration, err = registerCollector(registerer, c.operationDuration); err != nil {
+		return nil, err
+	}
+	if c.mutations, err = registerCollector(registerer, c.mutations); err != nil {
+		return nil, err
+	}
+	if c.units, err = registerCollector(registerer, c.units); err != nil {
+		return nil, err
+	}
+	if c.transfers, err = registerCollector(registerer, c.transfers); err != nil {
+		return nil, err
+	}
+	if c.alertsCreated, err = registerCollector(registerer, c.alertsCreated); err != nil {
+		return nil, err
+	}
+	if c.alertsResolved, err = registerCollector(registerer, c.alertsResolved); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// RegisterDBStats registers the connection pool statistics of db
+// データベース接続プールの統計情報を登録
+//
+// go_sql_* のメトリクスとして db_name ラベル付きで公開されます（open_connections、wait_count など）。
+func RegisterDBStats(registerer prometheus.Registerer, db *sql.DB, name string) error {
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+	_, err := registerCollector(registerer, collectors.NewDBStatsCollector(db, name))
+	return err
+}
+
+// registerCollector registers a collector, returning the existing one when already registered
+// コレクターを登録し、登録済みの場合は既存のコレクターを返す
+func registerCollector[T prometheus.Collector](registerer prometheus.Registerer, collector T) (T, error) {
+	if err := registerer.Register(collector); err != nil {
+		var already prometheus.AlreadyRegisteredError
+		if errors.As(err, &already) {
+			if existing, ok := already.ExistingCollector.(T); ok {
+				return existing, nil
+			}
+		}
+		return collector, err
+	}
+	return collector, nil
+}
+
+// ObserveRequest records an HTTP request
+// HTTPリクエストを記録
+//
+// routeにはルートのパステンプレート（/api/v1/items/{itemId} など）を指定し、
+// IDごとにラベルが増えないようにします。
+func (c *Collector) ObserveRequest(method, route string, status int, duration time.Duration) {
+	c.requests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	c.requestDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+// ObserveOperation records the result of a stock operation
+// 在庫操作の結果を記録
+func (c *Collector) ObserveOperation(operation string, duration time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	c.operations.WithLabelValues(operation, result).Inc()
+	c.operationDuration.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+// PublishStockChanged records a stock mutation
+// 在庫変動を記録
+func (c *Collector) PublishStockChanged(ctx context.Context, event inventory.StockChangedEvent) error {
+	c.mutations.WithLabelValues(event.ChangeType).Inc()
+	delta := event.NewQuantity - event.OldQuantity
+	switch {
+	case delta > 0:
+		c.units.WithLabelValues("in").Add(float64(delta))
+	case delta < 0:
+		c.units.WithLabelValues("out").Add(float64(-delta))
+	}
+	return nil
+}
+
+// PublishLowStockAlert does nothing; alerts are counted from alert.created events
+// 何もしない（アラートは alert.created イベントで集計する）
+func (c *Collector) PublishLowStockAlert(ctx context.Context, event inventory.LowStockAlertEvent) error {
+	return nil
+}
+
+// PublishItemTransferred records a transfer between locations
+// ロケーション間の在庫移動を記録
+func (c *Collector) PublishItemTransferred(ctx context.Context, event inventory.ItemTransferredEvent) error {
+	c.transfers.Inc()
+	return nil
+}
+
+// PublishEvent records alerts created and resolved
+// アラートの作成・解決を記録
+func (c *Collector) PublishEvent(ctx context.Context, event inventory.DomainEvent) error {
+	switch event.Type {
+	case inventory.EventTypeAlertCreated:
+		alertType := "unknown"
+		switch alert := event.Data.(type) {
+		case *inventory.StockAlert:
+			alertType = string(alert.Type)
+		case inventory.StockAlert:
+			alertType = string(alert.Type)
+		}
+		c.alertsCreated.WithLabelValues(alertType).Inc()
+	case inventory.EventTypeAlertResolved:
+		c.alertsResolved.Inc()
+	}
+	return nil
+}
+
+// Close does nothing; it lets the Collector be registered as an event target
+// 何もしない（イベントの発行先として登録できるようにする）
+func (c *Collector) Close() error {
+	return nil
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+func TestCollectorRecordsOperationsAndRequests(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	c, err := New(registry)
+	require.NoError(t, err)
+
+	c.ObserveOperation("add", 10*time.Millisecond, nil)
+	c.ObserveOperation("add", 10*time.Millisecond, errors.New("失敗"))
+	c.ObserveRequest("POST", "/api/v1/inventory/add", 200, 20*time.Millisecond)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.operations.WithLabelValues("add", "success")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.operations.WithLabelValues("add", "error")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.requests.WithLabelValues("POST", "/api/v1/inventory/add", "200")))
+	assert.Equal(t, 1, testutil.CollectAndCount(c.operationDuration))
+	assert.Equal(t, 1, testutil.CollectAndCount(c.requestDuration))
+
+	// 登録済みの場合は既存のコレクターを再利用する
+	again, err := New(registry)
+	require.NoError(t, err)
+	assert.Same(t, c.operations, again.operations)
+}
+
+func TestCollectorRecordsStockEvents(t *testing.T) {
+	c, err := New(prometheus.NewRegistry())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, c.PublishStockChanged(ctx, inventory.StockChangedEvent{ChangeType: "add", OldQuantity: 0, NewQuantity: 30}))
+	require.NoError(t, c.PublishStockChanged(ctx, inventory.StockChangedEvent{ChangeType: "remove", OldQuantity: 30, NewQuantity: 20}))
+	require.NoError(t, c.PublishItemTransferred(ctx, inventory.ItemTransferredEvent{Quantity: 5}))
+	require.NoError(t, c.PublishEvent(ctx, inventory.DomainEvent{
+		Type: inventory.EventTypeAlertCreated,
+		Data: &inventory.StockAlert{Type: inventory.AlertTypeLowStock},
+	}))
+	require.NoError(t, c.PublishEvent(ctx, inventory.DomainEvent{Type: inventory.EventTypeAlertResolved}))
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.mutations.WithLabelValues("add")))
+	assert.Equal(t, 30.0, testutil.ToFloat64(c.units.WithLabelValues("in")))
+	assert.Equal(t, 10.0, testutil.ToFloat64(c.units.WithLabelValues("out")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.transfers))
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.alertsCreated.WithLabelValues(string(inventory.AlertTypeLowStock))))
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.alertsResolved))
+}
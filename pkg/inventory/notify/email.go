@@ -0,0 +1,114 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EmailConfig holds the SMTP settings of an email channel
+// メールチャネルのSMTP設定
+type EmailConfig struct {
+	Host     string   // SMTPサーバーのホスト名
+	Port     int      // SMTPサーバーのポート（0の場合は587）
+	Username string   // 認証ユーザー名（空の場合は認証しない）
+	Password string   // 認証パスワード
+	From     string   // 送信元アドレス
+	To       []string // 宛先アドレス
+}
+
+// sendMailFunc sends a message through an SMTP server (smtp.SendMail)
+// SMTPサーバー経由でメッセージを送信する関数（smtp.SendMail）
+type sendMailFunc func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+
+// EmailChannel sends notifications as plain text emails over SMTP
+// 通知をSMTPでテキスト形式のメールとして送信するチャネル
+//
+// SMTPサーバーが対応している場合は STARTTLS で暗号化します。認証は暗号化された接続でのみ行います。
+type EmailChannel struct {
+	cfg      EmailConfig
+	sendMail sendMailFunc
+}
+
+var _ Channel = (*EmailChannel)(nil)
+
+// NewEmailChannel creates an email channel
+// メールチャネルを作成
+func NewEmailChannel(cfg EmailConfig) (*EmailChannel, error) {
+	if cfg.Host == "" {
+		return nil, errors.New("SMTPサーバーのホスト名が指定されていません")
+	}
+	if cfg.Port == 0 {
+		cfg.Port = 587
+	}
+	if _, err := mail.ParseAddress(cfg.From); err != nil {
+		return nil, fmt.Errorf("送信元アドレスが無効です: %w", err)
+	}
+	if len(cfg.To) == 0 {
+		return nil, errors.New("宛先アドレスが指定されていません")
+	}
+	for _, to := range cfg.To {
+		if _, err := mail.ParseAddress(to); err != nil {
+			return nil, fmt.Errorf("宛先アドレス %s が無効です: %w", to, err)
+		}
+	}
+	return &EmailChannel{cfg: cfg, sendMail: smtp.SendMail}, nil
+}
+
+// Send sends the notification to every recipient
+// 通知を全ての宛先へ送信
+//
+// smtp.SendMail はコンテキストに対応していないため、タイムアウトしても送信自体は中断されません。
+func (c *EmailChannel) Send(ctx context.Context, msg Message) error {
+	var auth smtp.Auth
+	if c.cfg.Username != "" {
+		auth = smtp.PlainAuth("", c.cfg.Username, c.cfg.Password, c.cfg.Host)
+	}
+	addr := net.JoinHostPort(c.cfg.Host, strconv.Itoa(c.cfg.Port))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.sendMail(addr, auth, c.cfg.From, c.cfg.To, c.message(msg, time.Now()))
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("メールの送信に失敗しました: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("メールの送信がタイムアウトしました: %w", ctx.Err())
+	}
+}
+
+// message builds the RFC 5322 message with a UTF-8 subject and base64 body
+// UTF-8の件名とbase64の本文でRFC 5322のメッセージを作成
+func (c *EmailChannel) message(msg Message, now time.Time) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", c.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(c.cfg.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: base64\r\n")
+	b.WriteString("\r\n")
+
+	// 1行76文字で折り返す
+	encoded := base64.StdEncoding.EncodeToString([]byte(msg.Body))
+	for len(encoded) > 76 {
+		b.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	b.WriteString(encoded + "\r\n")
+	return b.Bytes()
+}
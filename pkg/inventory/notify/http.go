@@ -0,0 +1,123 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// SlackChannel posts notifications to a Slack incoming webhook
+// 通知をSlackのIncoming Webhookへ投稿するチャネル
+type SlackChannel struct {
+	url    string
+	client *http.Client
+}
+
+var _ Channel = (*SlackChannel)(nil)
+
+// slackPayload is the JSON body of a Slack incoming webhook
+// Slack Incoming Webhook のJSON本文
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+// NewSlackChannel creates a Slack channel posting to webhookURL
+// webhookURLへ投稿するSlackチャネルを作成
+func NewSlackChannel(webhookURL string) (*SlackChannel, error) {
+	if err := validateURL(webhookURL); err != nil {
+		return nil, err
+	}
+	return &SlackChannel{url: webhookURL, client: &http.Client{}}, nil
+}
+
+// Send posts the subject in bold followed by the body
+// 件名を太字で、続けて本文を投稿
+func (c *SlackChannel) Send(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(slackPayload{Text: "*" + msg.Subject + "*\n" + msg.Body})
+	if err != nil {
+		return fmt.Errorf("Slackの本文のJSON変換に失敗しました: %w", err)
+	}
+	return post(ctx, c.client, c.url, body, nil)
+}
+
+// WebhookChannel posts notifications as JSON to an HTTP endpoint
+// 通知をJSONでHTTPエンドポイントへPOSTするチャネル
+//
+// 在庫イベントの Webhook（webhooks パッケージ）とは異なり、作成されたアラートのみを通知の形式で送信します。
+type WebhookChannel struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+var _ Channel = (*WebhookChannel)(nil)
+
+// WebhookPayload is the JSON body posted by a webhook channel
+// Webhookチャネルが送信するJSON本文
+type WebhookPayload struct {
+	Subject string               `json:"subject"` // 件名
+	Body    string               `json:"body"`    // 本文
+	Alert   inventory.StockAlert `json:"alert"`   // アラート
+}
+
+// NewWebhookChannel creates a webhook channel posting to endpoint with extra headers (e.g. Authorization)
+// endpointへ追加のヘッダー（Authorization など）を付けてPOSTするWebhookチャネルを作成
+func NewWebhookChannel(endpoint string, headers map[string]string) (*WebhookChannel, error) {
+	if err := validateURL(endpoint); err != nil {
+		return nil, err
+	}
+	return &WebhookChannel{url: endpoint, headers: headers, client: &http.Client{}}, nil
+}
+
+// Send posts the notification and the alert
+// 通知とアラートをPOST
+func (c *WebhookChannel) Send(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(WebhookPayload{Subject: msg.Subject, Body: msg.Body, Alert: *msg.Alert})
+	if err != nil {
+		return fmt.Errorf("Webhookの本文のJSON変換に失敗しました: %w", err)
+	}
+	return post(ctx, c.client, c.url, body, c.headers)
+}
+
+// post sends a JSON body and treats any non-2xx response as an error
+// JSON本文をPOSTし、2xx以外の応答をエラーとして返す
+func post(ctx context.Context, client *http.Client, endpoint string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "zaiGoFramework-Notifier/1.0")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// 接続を再利用できるよう本文を読み捨てる
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("予期しないステータスコード: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// validateURL checks that a channel URL is an absolute http(s) URL
+// チャネルのURLが http(s) の絶対URLかを検証
+func validateURL(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("通知先のURLが無効です: %s", endpoint)
+	}
+	return nil
+}
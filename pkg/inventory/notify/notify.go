@@ -0,0 +1,373 @@
+// Package notify sends new inventory alerts to notification channels such as email, Slack and webhooks
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/events"
+)
+
+// ErrNotifierClosed is returned when an alert is published to a closed notifier
+// 停止済みの通知者にアラートを発行した場合に返される
+var ErrNotifierClosed = errors.New("アラート通知は停止しています")
+
+// Message is a rendered notification of an alert
+// アラートからテンプレートで作成した通知
+type Message struct {
+	Subject string                // 件名（Slack では本文の先頭行）
+	Body    string                // 本文
+	Alert   *inventory.StockAlert // 通知するアラート
+}
+
+// Channel delivers notifications to one destination
+// 通知を1つの宛先へ送信するチャネル
+type Channel interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Route selects the alerts a channel receives
+// チャネルが受け取るアラートの条件
+//
+// 空のリストは全てに一致します。
+type Route struct {
+	AlertTypes []inventory.AlertType     // 対象のアラートタイプ
+	Severities []inventory.AlertSeverity // 対象の重要度
+}
+
+// Matches reports whether an alert passes the route
+// アラートが条件に一致するかを判定
+func (r Route) Matches(alert *inventory.StockAlert) bool {
+	if len(r.AlertTypes) > 0 && !contains(r.AlertTypes, alert.Type) {
+		return false
+	}
+	if len(r.Severities) > 0 && !contains(r.Severities, alert.Severity) {
+		return false
+	}
+	return true
+}
+
+// Validate checks that the route only names known alert types and severities
+// 条件のアラートタイプ・重要度が有効かを検証
+func (r Route) Validate() error {
+	for _, alertType := range r.AlertTypes {
+		if err := inventory.ValidateAlertType(alertType); err != nil {
+			return err
+		}
+	}
+	for _, severity := range r.Severities {
+		if err := inventory.ValidateAlertSeverity(severity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Target is a channel registered in a Notifier
+// Notifierに登録する通知先
+type Target struct {
+	Name     string    // 通知先の名前（ログに使用）
+	Channel  Channel   // 送信するチャネル
+	Route    Route     // 通知するアラートの条件
+	Template *Template // 件名・本文のテンプレート（nilの場合は DefaultTemplate）
+}
+
+// TargetConfig describes a target by channel type, as written in the configuration file
+// 設定ファイルに記述する通知先の設定
+type TargetConfig struct {
+	Name       string            // 通知先の名前
+	Type       string            // 通知方法（email | slack | webhook）
+	AlertTypes []string          // 通知するアラートタイプ（空の場合は全て）
+	Severities []string          // 通知する重要度（空の場合は全て）
+	Subject    string            // 件名のテンプレート（空の場合はデフォルト）
+	Body       string            // 本文のテンプレート（空の場合はデフォルト）
+	URL        string            // slack・webhook の送信先URL
+	Headers    map[string]string // webhook の追加のヘッダー
+	Email      EmailConfig       // email のSMTP設定
+}
+
+// NewTarget creates the channel, route and template of a target from its settings
+// 設定から通知先のチャネル・条件・テンプレートを作成
+func NewTarget(cfg TargetConfig) (Target, error) {
+	target := Target{Name: cfg.Name}
+	var err error
+	switch cfg.Type {
+	case "email":
+		target.Channel, err = NewEmailChannel(cfg.Email)
+	case "slack":
+		target.Channel, err = NewSlackChannel(cfg.URL)
+	case "webhook":
+		target.Channel, err = NewWebhookChannel(cfg.URL, cfg.Headers)
+	default:
+		err = fmt.Errorf("サポートされていない通知方法: %s", cfg.Type)
+	}
+	if err != nil {
+		return Target{}, fmt.Errorf("通知先 %s: %w", cfg.Name, err)
+	}
+
+	for _, alertType := range cfg.AlertTypes {
+		target.Route.AlertTypes = append(target.Route.AlertTypes, inventory.AlertType(alertType))
+	}
+	for _, severity := range cfg.Severities {
+		target.Route.Severities = append(target.Route.Severities, inventory.AlertSeverity(severity))
+	}
+	if target.Template, err = NewTemplate(cfg.Subject, cfg.Body); err != nil {
+		return Target{}, fmt.Errorf("通知先 %s: %w", cfg.Name, err)
+	}
+	return target, nil
+}
+
+// Config holds delivery settings
+// 通知の配信設定
+type Config struct {
+	Workers     int           // 送信ワーカー数
+	QueueSize   int           // 送信待ちキューの長さ
+	MaxAttempts int           // 1件あたりの最大試行回数
+	BaseDelay   time.Duration // 再試行の初回待機時間（試行ごとに倍増）
+	MaxDelay    time.Duration // 再試行の最大待機時間
+	Timeout     time.Duration // 1回の送信のタイムアウト
+}
+
+// DefaultConfig returns the settings used for zero values
+// 未設定の項目に使用する配信設定を返す
+func DefaultConfig() Config {
+	return Config{
+		Workers:     2,
+		QueueSize:   1000,
+		MaxAttempts: 3,
+		BaseDelay:   time.Second,
+		MaxDelay:    30 * time.Second,
+		Timeout:     10 * time.Second,
+	}
+}
+
+// job is a pending notification of one alert to one target
+// 1件のアラートを1つの通知先へ送信するジョブ
+type job struct {
+	target *Target
+	msg    Message
+}
+
+// Notifier sends new alerts to the channels whose routes match them
+// 作成されたアラートを条件に一致するチャネルへ送信
+//
+// events.ClosablePublisher を実装しているため、events.Fanout の発行先として登録します。
+// alert.created イベントのアラートをテンプレートで通知にしてキューに積むだけで、在庫操作のレイテンシーには影響しません。
+// 送信の失敗は指数バックオフで再試行し、全ての試行に失敗した通知はログに記録して破棄します。
+type Notifier struct {
+	targets []*Target
+	cfg     Config
+	logger  *zap.Logger
+
+	mu     sync.RWMutex // closedとキューのクローズを保護
+	closed bool
+	queue  chan job
+	stop   chan struct{}
+	wg     sync.WaitGroup
+}
+
+var (
+	_ events.ClosablePublisher       = (*Notifier)(nil)
+	_ inventory.DomainEventPublisher = (*Notifier)(nil)
+)
+
+// New creates a notifier over targets and starts its workers
+// 通知先をまとめた通知者を作成してワーカーを開始
+func New(targets []Target, cfg Config, logger *zap.Logger) (*Notifier, error) {
+	defaults := DefaultConfig()
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaults.Workers
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaults.QueueSize
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaults.MaxAttempts
+	}
+	if cfg.BaseDelay <= 0 {
+		cfg.BaseDelay = defaults.BaseDelay
+	}
+	if cfg.MaxDelay <= 0 {
+		cfg.MaxDelay = defaults.MaxDelay
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaults.Timeout
+	}
+
+	n := &Notifier{
+		cfg:    cfg,
+		logger: logger,
+		queue:  make(chan job, cfg.QueueSize),
+		stop:   make(chan struct{}),
+	}
+	for i := range targets {
+		target := targets[i]
+		if target.Channel == nil {
+			return nil, fmt.Errorf("通知先 %s のチャネルが指定されていません", target.Name)
+		}
+		if err := target.Route.Validate(); err != nil {
+			return nil, fmt.Errorf("通知先 %s の条件が無効です: %w", target.Name, err)
+		}
+		if target.Template == nil {
+			target.Template = DefaultTemplate()
+		}
+		n.targets = append(n.targets, &target)
+	}
+
+	for i := 0; i < cfg.Workers; i++ {
+		n.wg.Add(1)
+		go n.work()
+	}
+	return n, nil
+}
+
+// PublishStockChanged ignores stock changed events
+// 在庫変更イベントは通知しない
+func (n *Notifier) PublishStockChanged(ctx context.Context, event inventory.StockChangedEvent) error {
+	return nil
+}
+
+// PublishLowStockAlert ignores low stock alert events (the alert is notified by its alert.created event)
+// 低在庫アラートイベントは通知しない（同じアラートを alert.created イベントで通知する）
+func (n *Notifier) PublishLowStockAlert(ctx context.Context, event inventory.LowStockAlertEvent) error {
+	return nil
+}
+
+// PublishItemTransferred ignores item transferred events
+// 商品移動イベントは通知しない
+func (n *Notifier) PublishItemTransferred(ctx context.Context, event inventory.ItemTransferredEvent) error {
+	return nil
+}
+
+// PublishEvent queues notifications of a created alert for every matching target
+// 作成されたアラートの通知を条件に一致する通知先ごとにキューに積む
+func (n *Notifier) PublishEvent(ctx context.Context, event inventory.DomainEvent) error {
+	if event.Type != inventory.EventTypeAlertCreated {
+		return nil
+	}
+	var alert *inventory.StockAlert
+	switch data := event.Data.(type) {
+	case *inventory.StockAlert:
+		alert = data
+	case inventory.StockAlert:
+		alert = &data
+	default:
+		return fmt.Errorf("アラートイベントの内容が不正です（%T）", event.Data)
+	}
+	return n.Notify(ctx, alert)
+}
+
+// Notify queues notifications of an alert for every matching target
+// アラートの通知を条件に一致する通知先ごとにキューに積む
+//
+// テンプレートの実行に失敗した通知先はスキップし、キューが満杯の場合と合わせてエラーを返します。
+func (n *Notifier) Notify(ctx context.Context, alert *inventory.StockAlert) error {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if n.closed {
+		return ErrNotifierClosed
+	}
+
+	// 送信中に発行元がアラートを変更しても影響しないよう複製する
+	alertCopy := *alert
+	alert = &alertCopy
+
+	var errs []error
+	for _, target := range n.targets {
+		if !target.Route.Matches(alert) {
+			continue
+		}
+		msg, err := target.Template.Render(alert)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("通知先 %s: %w", target.Name, err))
+			continue
+		}
+		select {
+		case n.queue <- job{target: target, msg: msg}:
+		default:
+			errs = append(errs, fmt.Errorf("アラート通知キューが満杯です（通知先 %s）", target.Name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close stops accepting alerts and waits for queued notifications to finish
+// 新しいアラートの受付を停止し、キュー内の通知の完了を待機
+//
+// 再試行の待機中のジョブは待機を打ち切って終了します。
+func (n *Notifier) Close() error {
+	n.mu.Lock()
+	if !n.closed {
+		n.closed = true
+		close(n.stop)
+		close(n.queue)
+	}
+	n.mu.Unlock()
+
+	n.wg.Wait()
+	return nil
+}
+
+// work processes queued notifications until the queue is closed
+// キューが閉じられるまで通知ジョブを処理
+func (n *Notifier) work() {
+	defer n.wg.Done()
+	for j := range n.queue {
+		n.deliver(j)
+	}
+}
+
+// deliver sends one notification, retrying with exponential backoff
+// 1件の通知を送信し、失敗時は指数バックオフで再試行
+func (n *Notifier) deliver(j job) {
+	delay := n.cfg.BaseDelay
+	for attempt := 1; attempt <= n.cfg.MaxAttempts; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), n.cfg.Timeout)
+		err := j.target.Channel.Send(ctx, j.msg)
+		cancel()
+		if err == nil {
+			n.logger.Debug("アラートを通知しました",
+				zap.String("target", j.target.Name),
+				zap.String("alert_id", j.msg.Alert.ID),
+			)
+			return
+		}
+
+		if attempt == n.cfg.MaxAttempts {
+			n.logger.Warn("アラートの通知を断念しました",
+				zap.String("target", j.target.Name),
+				zap.String("alert_id", j.msg.Alert.ID),
+				zap.Int("attempts", attempt),
+				zap.Error(err),
+			)
+			return
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-n.stop:
+			return
+		}
+		delay *= 2
+		if delay > n.cfg.MaxDelay {
+			delay = n.cfg.MaxDelay
+		}
+	}
+}
+
+// contains reports whether values includes value
+// valuesにvalueが含まれるかを判定
+func contains[T comparable](values []T, value T) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
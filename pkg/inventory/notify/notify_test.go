@@ -0,0 +1,289 @@
+package notify
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// recordingChannel はテスト用に送信した通知を記録するチャネル
+type recordingChannel struct {
+	mu       sync.Mutex
+	failures int // 失敗させる残りの回数
+	attempts int
+	messages []Message
+	sent     chan struct{}
+}
+
+func newRecordingChannel(failures int) *recordingChannel {
+	return &recordingChannel{failures: failures, sent: make(chan struct{}, 10)}
+}
+
+func (c *recordingChannel) Send(ctx context.Context, msg Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.attempts++
+	if c.failures > 0 {
+		c.failures--
+		return errors.New("送信失敗")
+	}
+	c.messages = append(c.messages, msg)
+	c.sent <- struct{}{}
+	return nil
+}
+
+func (c *recordingChannel) wait(t *testing.T) {
+	t.Helper()
+	select {
+	case <-c.sent:
+	case <-time.After(5 * time.Second):
+		t.Fatal("通知が送信されませんでした")
+	}
+}
+
+func testAlert() *inventory.StockAlert {
+	return &inventory.StockAlert{
+		ID:         "ALERT-1",
+		Type:       inventory.AlertTypeLowStock,
+		ItemID:     "ITEM-1",
+		LocationID: "LOC-A",
+		CurrentQty: 0,
+		Threshold:  10,
+		Message:    "在庫が低下しています",
+		IsActive:   true,
+		CreatedAt:  time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC),
+		Severity:   inventory.AlertSeverityCritical,
+	}
+}
+
+// TestRoute_Matches はアラートタイプ・重要度による通知先の判定のテスト
+func TestRoute_Matches(t *testing.T) {
+	alert := testAlert()
+
+	assert.True(t, Route{}.Matches(alert))
+	assert.True(t, Route{AlertTypes: []inventory.AlertType{inventory.AlertTypeLowStock}}.Matches(alert))
+	assert.False(t, Route{AlertTypes: []inventory.AlertType{inventory.AlertTypeOverStock}}.Matches(alert))
+	assert.True(t, Route{Severities: []inventory.AlertSeverity{inventory.AlertSeverityWarning, inventory.AlertSeverityCritical}}.Matches(alert))
+	assert.False(t, Route{
+		AlertTypes: []inventory.AlertType{inventory.AlertTypeLowStock},
+		Severities: []inventory.AlertSeverity{inventory.AlertSeverityInfo},
+	}.Matches(alert))
+
+	assert.Error(t, Route{AlertTypes: []inventory.AlertType{"unknown"}}.Validate())
+	assert.Error(t, Route{Severities: []inventory.AlertSeverity{"urgent"}}.Validate())
+}
+
+// TestTemplate_Render はテンプレートによる件名・本文の作成のテスト
+func TestTemplate_Render(t *testing.T) {
+	msg, err := DefaultTemplate().Render(testAlert())
+	require.NoError(t, err)
+	assert.Equal(t, "[critical] 在庫アラート: ITEM-1 @ LOC-A", msg.Subject)
+	assert.Contains(t, msg.Body, "在庫が低下しています")
+	assert.Contains(t, msg.Body, "現在数量: 0（閾値: 10）")
+	assert.Contains(t, msg.Body, "2024-01-10 09:00:00 UTC")
+
+	// 件名の改行はヘッダーに含めない
+	custom, err := NewTemplate("{{.ItemID}}\n{{.LocationID}}", "{{.Type}}: {{.CurrentQty}}")
+	require.NoError(t, err)
+	msg, err = custom.Render(testAlert())
+	require.NoError(t, err)
+	assert.Equal(t, "ITEM-1 LOC-A", msg.Subject)
+	assert.Equal(t, "low_stock: 0", msg.Body)
+
+	_, err = NewTemplate("{{.ItemID", "")
+	assert.Error(t, err)
+	unknown, err := NewTemplate("{{.Unknown}}", "")
+	require.NoError(t, err)
+	_, err = unknown.Render(testAlert())
+	assert.Error(t, err)
+}
+
+// TestNotifier_RoutesCreatedAlerts は作成されたアラートを条件に一致する通知先へ送信するテスト
+func TestNotifier_RoutesCreatedAlerts(t *testing.T) {
+	critical := newRecordingChannel(0)
+	overStock := newRecordingChannel(0)
+	notifier, err := New([]Target{
+		{Name: "critical", Channel: critical, Route: Route{Severities: []inventory.AlertSeverity{inventory.AlertSeverityCritical}}},
+		{Name: "over-stock", Channel: overStock, Route: Route{AlertTypes: []inventory.AlertType{inventory.AlertTypeOverStock}}},
+	}, Config{}, zap.NewNop())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	alert := testAlert()
+	require.NoError(t, notifier.PublishEvent(ctx, inventory.DomainEvent{Type: inventory.EventTypeAlertCreated, Data: alert}))
+	// 作成以外のアラートイベントは通知しない
+	require.NoError(t, notifier.PublishEvent(ctx, inventory.DomainEvent{Type: inventory.EventTypeAlertResolved, Data: inventory.AlertResolvedEvent{AlertID: alert.ID}}))
+	require.NoError(t, notifier.PublishLowStockAlert(ctx, inventory.LowStockAlertEvent{ItemID: alert.ItemID}))
+	assert.Error(t, notifier.PublishEvent(ctx, inventory.DomainEvent{Type: inventory.EventTypeAlertCreated, Data: "invalid"}))
+
+	critical.wait(t)
+	require.NoError(t, notifier.Close())
+
+	require.Len(t, critical.messages, 1)
+	assert.Equal(t, "ALERT-1", critical.messages[0].Alert.ID)
+	assert.Equal(t, "[critical] 在庫アラート: ITEM-1 @ LOC-A", critical.messages[0].Subject)
+	assert.Empty(t, overStock.messages)
+
+	assert.ErrorIs(t, notifier.Notify(ctx, alert), ErrNotifierClosed)
+}
+
+// TestNotifier_Retries は送信失敗時の再試行のテスト
+func TestNotifier_Retries(t *testing.T) {
+	flaky := newRecordingChannel(2)
+	failing := newRecordingChannel(100)
+	notifier, err := New([]Target{
+		{Name: "flaky", Channel: flaky},
+		{Name: "failing", Channel: failing},
+	}, Config{Workers: 2, MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}, zap.NewNop())
+	require.NoError(t, err)
+
+	require.NoError(t, notifier.Notify(context.Background(), testAlert()))
+	flaky.wait(t)
+	require.NoError(t, notifier.Close())
+
+	assert.Equal(t, 3, flaky.attempts)
+	assert.Len(t, flaky.messages, 1)
+	assert.Equal(t, 3, failing.attempts, "最大試行回数で断念する")
+	assert.Empty(t, failing.messages)
+}
+
+// TestHTTPChannels はSlack・Webhookチャネルの送信のテスト
+func TestHTTPChannels(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		bodies   = map[string][]byte{}
+		headers  = map[string]http.Header{}
+		statuses = map[string]int{"/slack": http.StatusOK, "/hook": http.StatusAccepted, "/down": http.StatusServiceUnavailable}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies[r.URL.Path] = body
+		headers[r.URL.Path] = r.Header.Clone()
+		mu.Unlock()
+		w.WriteHeader(statuses[r.URL.Path])
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	msg, err := DefaultTemplate().Render(testAlert())
+	require.NoError(t, err)
+
+	slack, err := NewSlackChannel(srv.URL + "/slack")
+	require.NoError(t, err)
+	require.NoError(t, slack.Send(ctx, msg))
+	var slackBody slackPayload
+	require.NoError(t, json.Unmarshal(bodies["/slack"], &slackBody))
+	assert.True(t, strings.HasPrefix(slackBody.Text, "*[critical] 在庫アラート: ITEM-1 @ LOC-A*\n"))
+
+	hook, err := NewWebhookChannel(srv.URL+"/hook", map[string]string{"Authorization": "Bearer token"})
+	require.NoError(t, err)
+	require.NoError(t, hook.Send(ctx, msg))
+	var hookBody WebhookPayload
+	require.NoError(t, json.Unmarshal(bodies["/hook"], &hookBody))
+	assert.Equal(t, msg.Subject, hookBody.Subject)
+	assert.Equal(t, "ALERT-1", hookBody.Alert.ID)
+	assert.Equal(t, "Bearer token", headers["/hook"].Get("Authorization"))
+	assert.Equal(t, "application/json", headers["/hook"].Get("Content-Type"))
+
+	down, err := NewWebhookChannel(srv.URL+"/down", nil)
+	require.NoError(t, err)
+	assert.Error(t, down.Send(ctx, msg))
+
+	_, err = NewSlackChannel("ftp://example.com")
+	assert.Error(t, err)
+}
+
+// TestEmailChannel はメールチャネルのメッセージ作成と送信のテスト
+func TestEmailChannel(t *testing.T) {
+	channel, err := NewEmailChannel(EmailConfig{
+		Host:     "smtp.example.com",
+		Username: "notifier",
+		Password: "secret",
+		From:     "inventory@example.com",
+		To:       []string{"ops@example.com", "warehouse@example.com"},
+	})
+	require.NoError(t, err)
+
+	var (
+		gotAddr string
+		gotTo   []string
+		gotMsg  []byte
+	)
+	channel.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotTo, gotMsg = addr, to, msg
+		assert.NotNil(t, a)
+		return nil
+	}
+
+	msg, err := DefaultTemplate().Render(testAlert())
+	require.NoError(t, err)
+	require.NoError(t, channel.Send(context.Background(), msg))
+
+	assert.Equal(t, "smtp.example.com:587", gotAddr)
+	assert.Equal(t, []string{"ops@example.com", "warehouse@example.com"}, gotTo)
+
+	header, body, found := strings.Cut(string(gotMsg), "\r\n\r\n")
+	require.True(t, found)
+	assert.Contains(t, header, "To: ops@example.com, warehouse@example.com\r\n")
+	assert.Contains(t, header, "Content-Type: text/plain; charset=UTF-8\r\n")
+	for _, line := range strings.Split(header, "\r\n") {
+		if subject, ok := strings.CutPrefix(line, "Subject: "); ok {
+			decoded, err := new(mime.WordDecoder).DecodeHeader(subject)
+			require.NoError(t, err)
+			assert.Equal(t, msg.Subject, decoded)
+		}
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(body, "\r\n", ""))
+	require.NoError(t, err)
+	assert.Equal(t, msg.Body, string(decoded))
+
+	_, err = NewEmailChannel(EmailConfig{Host: "smtp.example.com", From: "inventory@example.com"})
+	assert.Error(t, err, "宛先は必須")
+}
+
+// TestNewTarget は設定からの通知先の作成のテスト
+func TestNewTarget(t *testing.T) {
+	target, err := NewTarget(TargetConfig{
+		Name:       "ops",
+		Type:       "slack",
+		URL:        "https://hooks.slack.com/services/T000/B000/XXX",
+		AlertTypes: []string{"low_stock"},
+		Severities: []string{"critical"},
+		Subject:    "{{.ItemID}}",
+	})
+	require.NoError(t, err)
+	assert.IsType(t, &SlackChannel{}, target.Channel)
+	assert.Equal(t, Route{
+		AlertTypes: []inventory.AlertType{inventory.AlertTypeLowStock},
+		Severities: []inventory.AlertSeverity{inventory.AlertSeverityCritical},
+	}, target.Route)
+	msg, err := target.Template.Render(testAlert())
+	require.NoError(t, err)
+	assert.Equal(t, "ITEM-1", msg.Subject)
+
+	_, err = NewTarget(TargetConfig{Name: "sms", Type: "sms"})
+	assert.Error(t, err)
+	_, err = NewTarget(TargetConfig{Name: "hook", Type: "webhook", URL: "https://example.com", Body: "{{"})
+	assert.Error(t, err)
+
+	// 条件が無効な通知先は通知者の作成時に拒否する
+	_, err = New([]Target{{Name: "invalid", Channel: newRecordingChannel(0), Route: Route{Severities: []inventory.AlertSeverity{"urgent"}}}}, Config{}, zap.NewNop())
+	assert.Error(t, err)
+}
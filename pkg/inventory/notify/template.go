@@ -0,0 +1,80 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// Default templates of the subject and body
+// 件名・本文のデフォルトのテンプレート
+const (
+	DefaultSubjectTemplate = `[{{.Severity}}] 在庫アラート: {{.ItemID}} @ {{.LocationID}}`
+	DefaultBodyTemplate    = `{{.Message}}
+
+タイプ: {{.Type}}
+重要度: {{.Severity}}
+現在数量: {{.CurrentQty}}（閾値: {{.Threshold}}）
+アラートID: {{.ID}}
+作成日時: {{.CreatedAt.Format "2006-01-02 15:04:05 MST"}}`
+)
+
+// Template renders the subject and body of a notification from an alert
+// アラートから通知の件名・本文を作成するテンプレート
+//
+// テンプレートは text/template の構文で、inventory.StockAlert のフィールド（{{.ItemID}}・{{.Severity}} など）を参照できます。
+type Template struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+// NewTemplate parses the subject and body templates; empty strings use the defaults
+// 件名・本文のテンプレートを解析（空の場合はデフォルトを使用）
+func NewTemplate(subject, body string) (*Template, error) {
+	if subject == "" {
+		subject = DefaultSubjectTemplate
+	}
+	if body == "" {
+		body = DefaultBodyTemplate
+	}
+
+	subjectTemplate, err := template.New("subject").Option("missingkey=error").Parse(subject)
+	if err != nil {
+		return nil, fmt.Errorf("件名のテンプレートが無効です: %w", err)
+	}
+	bodyTemplate, err := template.New("body").Option("missingkey=error").Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("本文のテンプレートが無効です: %w", err)
+	}
+	return &Template{subject: subjectTemplate, body: bodyTemplate}, nil
+}
+
+// DefaultTemplate returns the template used when a target has none
+// 通知先にテンプレートを指定しない場合に使用するテンプレートを返す
+func DefaultTemplate() *Template {
+	t, err := NewTemplate("", "")
+	if err != nil {
+		panic(err) // デフォルトのテンプレートは常に解析できる
+	}
+	return t
+}
+
+// Render executes the templates for an alert
+// アラートでテンプレートを実行して通知を作成
+func (t *Template) Render(alert *inventory.StockAlert) (Message, error) {
+	var subject, body strings.Builder
+	if err := t.subject.Execute(&subject, alert); err != nil {
+		return Message{}, fmt.Errorf("件名の作成に失敗しました: %w", err)
+	}
+	if err := t.body.Execute(&body, alert); err != nil {
+		return Message{}, fmt.Errorf("本文の作成に失敗しました: %w", err)
+	}
+	// メールのヘッダーに改行を含めない
+	return Message{
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		Body:    body.String(),
+		Alert:   alert,
+	}, nil
+}
@@ -0,0 +1,245 @@
+package inventory
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// defaultPageSize is the page size used when no limit is given
+// 件数の指定がない場合の1ページあたりの件数
+const defaultPageSize = 20
+
+// PageCursor identifies a position in the item or location list for keyset pagination
+// 商品・ロケーション一覧のキーセットページネーション用の位置を表現
+//
+// 一覧は既定で (CreatedAt, ID) の降順で並ぶため、このカーソルより古いレコードが次のページになります。
+// 商品一覧を別の項目で並び替えた場合は、その項目の値とIDの順でカーソルより後のレコードが次のページになります。
+// JSONやAPIでは Encode で変換した不透明な文字列として受け渡します。
+type PageCursor struct {
+	AfterCreatedAt time.Time   // このレコードの作成日時
+	AfterID        string      // このレコードのID
+	SortKey        string      // 並び替えの項目と方向（作成日時の降順の場合は空）
+	AfterValue     interface{} // 並び替えの項目のこのレコードの値（名前・SKUは文字列、単価は数値）
+}
+
+// pageCursorData is the encoded form of a cursor
+// カーソルをエンコードする際の形式
+type pageCursorData struct {
+	CreatedAt time.Time   `json:"t"`
+	ID        string      `json:"i"`
+	SortKey   string      `json:"s,omitempty"`
+	Value     interface{} `json:"v,omitempty"`
+}
+
+// Encode returns the cursor as an opaque URL-safe string
+// カーソルをURLで使用できる不透明な文字列に変換
+func (c PageCursor) Encode() string {
+	data, _ := json.Marshal(pageCursorData{CreatedAt: c.AfterCreatedAt, ID: c.AfterID, SortKey: c.SortKey, Value: c.AfterValue})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// MarshalText encodes the cursor as an opaque string
+// カーソルを不透明な文字列としてエンコード
+func (c PageCursor) MarshalText() ([]byte, error) {
+	return []byte(c.Encode()), nil
+}
+
+// UnmarshalText decodes a cursor encoded by MarshalText
+// MarshalText でエンコードしたカーソルをデコード
+func (c *PageCursor) UnmarshalText(text []byte) error {
+	cursor, err := DecodePageCursor(string(text))
+	if err != nil {
+		return err
+	}
+	*c = *cursor
+	return nil
+}
+
+// DecodePageCursor parses a cursor returned by Encode
+// Encode で変換したカーソルを解析
+func DecodePageCursor(s string) (*PageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, NewValidationError("cursor", "カーソルの形式が正しくありません", s)
+	}
+	var data pageCursorData
+	if err := json.Unmarshal(raw, &data); err != nil || data.ID == "" || data.CreatedAt.IsZero() {
+		return nil, NewValidationError("cursor", "カーソルの形式が正しくありません", s)
+	}
+	return &PageCursor{AfterCreatedAt: data.CreatedAt, AfterID: data.ID, SortKey: data.SortKey, AfterValue: data.Value}, nil
+}
+
+// ItemSortField is a field the item list can be sorted by
+// 商品一覧の並び替えの項目
+type ItemSortField string
+
+// 商品一覧の並び替えの項目
+const (
+	ItemSortCreatedAt ItemSortField = "created_at" // 作成日時（既定）
+	ItemSortName      ItemSortField = "name"       // 商品名
+	ItemSortSKU       ItemSortField = "sku"        // SKU
+	ItemSortUnitCost  ItemSortField = "unit_cost"  // 単価
+)
+
+// ItemFilter narrows down and orders the item list
+// 商品一覧の絞り込みと並び替えの条件
+//
+// 絞り込みはストレージで行います（PostgreSQLではインデックスを使用します）。
+type ItemFilter struct {
+	Category  string        // カテゴリ（完全一致、空の場合は絞り込まない）
+	SKUPrefix string        // SKUの前方一致（空の場合は絞り込まない）
+	MinCost   *float64      // 単価の下限（この値を含む）
+	MaxCost   *float64      // 単価の上限（この値を含む）
+	SortBy    ItemSortField // 並び替えの項目（空の場合は作成日時）
+	Ascending bool          // 昇順で並べるか（既定は降順）
+}
+
+// Validate checks the filter
+// 条件を検証
+func (f ItemFilter) Validate() error {
+	switch f.SortBy {
+	case "", ItemSortCreatedAt, ItemSortName, ItemSortSKU, ItemSortUnitCost:
+	default:
+		return NewValidationError("sort", "並び替えの項目は created_at・name・sku・unit_cost のいずれかを指定してください", string(f.SortBy))
+	}
+	if f.MinCost != nil && f.MaxCost != nil && *f.MinCost > *f.MaxCost {
+		return NewValidationError("min_cost", "単価の下限は上限以下を指定してください", fmt.Sprint(*f.MinCost))
+	}
+	return nil
+}
+
+// Sort returns the sort field, defaulting to the creation time
+// 並び替えの項目を返す（指定がない場合は作成日時）
+func (f ItemFilter) Sort() ItemSortField {
+	if f.SortBy == "" {
+		return ItemSortCreatedAt
+	}
+	return f.SortBy
+}
+
+// sortKey identifies the order of the list in cursors
+// カーソルに記録する並び順（作成日時の降順の場合は空）
+func (f ItemFilter) sortKey() string {
+	if f.Sort() == ItemSortCreatedAt && !f.Ascending {
+		return ""
+	}
+	if f.Ascending {
+		return string(f.Sort()) + ":asc"
+	}
+	return string(f.Sort()) + ":desc"
+}
+
+// cursorFor returns the cursor positioned at an item
+// 商品の位置を表すカーソルを返す
+func (f ItemFilter) cursorFor(item Item) *PageCursor {
+	cursor := &PageCursor{AfterCreatedAt: item.CreatedAt, AfterID: item.ID, SortKey: f.sortKey()}
+	switch f.Sort() {
+	case ItemSortName:
+		cursor.AfterValue = item.Name
+	case ItemSortSKU:
+		cursor.AfterValue = item.SKU
+	case ItemSortUnitCost:
+		cursor.AfterValue = item.UnitCost
+	}
+	return cursor
+}
+
+// checkCursor verifies that a cursor was issued for the same order
+// カーソルが同じ並び順で発行されたものかを検証
+func (f ItemFilter) checkCursor(cursor *PageCursor) error {
+	if cursor.SortKey != f.sortKey() {
+		return NewValidationError("cursor", "カーソルの並び順が指定と一致しません", cursor.SortKey)
+	}
+	var ok bool
+	switch f.Sort() {
+	case ItemSortName, ItemSortSKU:
+		_, ok = cursor.AfterValue.(string)
+	case ItemSortUnitCost:
+		_, ok = cursor.AfterValue.(float64)
+	default:
+		ok = cursor.AfterValue == nil
+	}
+	if !ok {
+		return NewValidationError("cursor", "カーソルの形式が正しくありません", cursor.SortKey)
+	}
+	return nil
+}
+
+// ItemPage represents a page of the item list
+// 商品一覧の1ページを表現
+type ItemPage struct {
+	Items      []Item      `json:"items"`                 // 商品一覧（指定された並び順）
+	NextCursor *PageCursor `json:"next_cursor,omitempty"` // 次ページのカーソル（最終ページの場合はnil）
+}
+
+// LocationPage represents a page of the location list
+// ロケーション一覧の1ページを表現
+type LocationPage struct {
+	Locations  []Location  `json:"locations"`             // ロケーション一覧（作成日時の新しい順）
+	NextCursor *PageCursor `json:"next_cursor,omitempty"` // 次ページのカーソル（最終ページの場合はnil）
+}
+
+// ListItemsPage lists a page of items matching a filter using keyset pagination
+// キーセットページネーションで条件に一致する商品一覧の1ページを取得
+//
+// afterがnilの場合は先頭から取得します。返却されたNextCursorを同じ条件の次の呼び出しに
+// 渡すことで、同時に商品が追加・削除されても重複・欠落なく一覧を辿ることができます。
+func (m *Manager) ListItemsPage(ctx context.Context, filter ItemFilter, after *PageCursor, limit int) (*ItemPage, error) {
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	if after != nil {
+		if err := filter.checkCursor(after); err != nil {
+			return nil, err
+		}
+	}
+
+	// 次ページの有無を判定するため1件多く取得
+	items, err := m.storage.ListItemsAfter(ctx, filter, after, limit+1)
+	if err != nil {
+		return nil, NewStorageError("list_items", "商品一覧取得に失敗しました", err)
+	}
+
+	page := &ItemPage{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		page.NextCursor = filter.cursorFor(page.Items[limit-1])
+	}
+	if page.Items == nil {
+		page.Items = make([]Item, 0)
+	}
+
+	return page, nil
+}
+
+// ListLocationsPage lists a page of locations using keyset pagination
+// キーセットページネーションでロケーション一覧の1ページを取得
+func (m *Manager) ListLocationsPage(ctx context.Context, after *PageCursor, limit int) (*LocationPage, error) {
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+
+	// 次ページの有無を判定するため1件多く取得
+	locations, err := m.storage.ListLocationsAfter(ctx, after, limit+1)
+	if err != nil {
+		return nil, NewStorageError("list_locations", "ロケーション一覧取得に失敗しました", err)
+	}
+
+	page := &LocationPage{Locations: locations}
+	if len(locations) > limit {
+		page.Locations = locations[:limit]
+		last := page.Locations[limit-1]
+		page.NextCursor = &PageCursor{AfterCreatedAt: last.CreatedAt, AfterID: last.ID}
+	}
+	if page.Locations == nil {
+		page.Locations = make([]Location, 0)
+	}
+
+	return page, nil
+}
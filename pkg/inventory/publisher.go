@@ -0,0 +1,73 @@
+package inventory
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// bufferedPublisher collects events in memory until they are flushed
+// フラッシュされるまでイベントをメモリ上に保持するイベント発行者
+//
+// トランザクション内で発生したイベントをコミット後にまとめて発行するために使用します。
+// ロールバックされた場合はフラッシュせずに破棄します。
+type bufferedPublisher struct {
+	pending []func(ctx context.Context, publisher EventPublisher) error
+}
+
+var (
+	_ EventPublisher       = (*bufferedPublisher)(nil)
+	_ DomainEventPublisher = (*bufferedPublisher)(nil)
+)
+
+// PublishStockChanged buffers a stock changed event
+// 在庫変更イベントをバッファに追加
+func (p *bufferedPublisher) PublishStockChanged(ctx context.Context, event StockChangedEvent) error {
+	p.pending = append(p.pending, func(ctx context.Context, publisher EventPublisher) error {
+		return publisher.PublishStockChanged(ctx, event)
+	})
+	return nil
+}
+
+// PublishLowStockAlert buffers a low stock alert event
+// 低在庫アラートイベントをバッファに追加
+func (p *bufferedPublisher) PublishLowStockAlert(ctx context.Context, event LowStockAlertEvent) error {
+	p.pending = append(p.pending, func(ctx context.Context, publisher EventPublisher) error {
+		return publisher.PublishLowStockAlert(ctx, event)
+	})
+	return nil
+}
+
+// PublishItemTransferred buffers an item transferred event
+// 商品移動イベントをバッファに追加
+func (p *bufferedPublisher) PublishItemTransferred(ctx context.Context, event ItemTransferredEvent) error {
+	p.pending = append(p.pending, func(ctx context.Context, publisher EventPublisher) error {
+		return publisher.PublishItemTransferred(ctx, event)
+	})
+	return nil
+}
+
+// PublishEvent buffers a domain event
+// ドメインイベントをバッファに追加
+//
+// フラッシュ先の発行者がDomainEventPublisherを実装していない場合は破棄されます。
+func (p *bufferedPublisher) PublishEvent(ctx context.Context, event DomainEvent) error {
+	p.pending = append(p.pending, func(ctx context.Context, publisher EventPublisher) error {
+		if domainPublisher, ok := publisher.(DomainEventPublisher); ok {
+			return domainPublisher.PublishEvent(ctx, event)
+		}
+		return nil
+	})
+	return nil
+}
+
+// flush publishes all buffered events in order and clears the buffer
+// バッファ内のイベントを順番に発行し、バッファをクリア
+func (p *bufferedPublisher) flush(ctx context.Context, publisher EventPublisher, logger *zap.Logger) {
+	for _, publish := range p.pending {
+		if err := publish(ctx, publisher); err != nil {
+			logger.Error("イベント発行に失敗しました", zap.Error(err))
+		}
+	}
+	p.pending = nil
+}
@@ -0,0 +1,495 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var _ PurchaseOrderManager = (*Manager)(nil)
+
+// CreatePurchaseOrder validates and creates a draft purchase order
+// 発注を検証して作成済み（draft）として作成
+//
+// SupplierID・LocationID（入荷先）・Reference（任意）・ExpectedAt（任意）・Note（任意）・Lines を指定します。
+// 仕入先はアクティブである必要があり、仕入先の仕入条件がある商品は最小発注数量以上を発注する必要があります。
+// 行の UnitCost が0の場合は仕入条件の仕入単価（仕入条件がない場合は商品の単価）を設定します。
+// ID・状態・作成日時・作成者は設定され、Reference を省略した場合は発注IDを設定します。
+func (m *Manager) CreatePurchaseOrder(ctx context.Context, order *PurchaseOrder) (err error) {
+	ctx, finish := m.startOperation(ctx, "create_purchase_order", attrLocationID.String(order.LocationID), attrReference.String(order.Reference))
+	defer finish(&err)
+
+	if err := ValidatePurchaseOrder(order); err != nil {
+		return err
+	}
+	supplier, err := m.storage.GetSupplier(ctx, order.SupplierID)
+	if err != nil {
+		if errors.Is(err, ErrSupplierNotFound) {
+			return ErrSupplierNotFound
+		}
+		return NewStorageError("get_supplier", "仕入先の取得に失敗しました", err)
+	}
+	if !supplier.IsActive {
+		return NewValidationError("supplier_id", "無効な仕入先には発注できません", order.SupplierID)
+	}
+	if _, err := m.storage.GetLocation(ctx, order.LocationID); err != nil {
+		if errors.Is(err, ErrLocationNotFound) {
+			return ErrLocationNotFound
+		}
+		return NewStorageError("get_location", "ロケーション取得に失敗しました", err)
+	}
+
+	for i := range order.Lines {
+		if err := m.applySupplierTerms(ctx, order.SupplierID, &order.Lines[i]); err != nil {
+			return err
+		}
+	}
+
+	order.ID = NewPurchaseOrderID()
+	order.Status = PurchaseOrderStatusDraft
+	order.CreatedAt = time.Now()
+	order.CreatedBy = m.getUserFromContext(ctx)
+	order.ApprovedAt, order.ApprovedBy = nil, ""
+	order.SentAt, order.SentBy = nil, ""
+	order.ClosedAt, order.ClosedBy = nil, ""
+	if order.Reference == "" {
+		order.Reference = order.ID
+	}
+	for i := range order.Lines {
+		order.Lines[i].PurchaseOrderID = order.ID
+		order.Lines[i].ReceivedQuantity = 0
+	}
+
+	if err := m.storage.CreatePurchaseOrder(ctx, order); err != nil {
+		return NewStorageError("create_purchase_order", "発注の作成に失敗しました", err)
+	}
+
+	m.log(ctx).Info("発注作成完了",
+		zap.String("purchase_order_id", order.ID),
+		zap.String("supplier_id", order.SupplierID),
+		zap.String("location_id", order.LocationID),
+		zap.Int("lines", len(order.Lines)),
+	)
+	return nil
+}
+
+// GetPurchaseOrder retrieves a purchase order with its lines
+// 発注を行とともに取得
+func (m *Manager) GetPurchaseOrder(ctx context.Context, orderID string) (*PurchaseOrder, error) {
+	if orderID == "" {
+		return nil, NewValidationError("purchase_order_id", "発注IDが指定されていません", "")
+	}
+
+	order, err := m.storage.GetPurchaseOrder(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, ErrPurchaseOrderNotFound) {
+			return nil, ErrPurchaseOrderNotFound
+		}
+		return nil, NewStorageError("get_purchase_order", "発注の取得に失敗しました", err)
+	}
+	return order, nil
+}
+
+// ListPurchaseOrders lists purchase orders matching a filter, newest first, without their lines
+// 条件に一致する発注を作成日時の新しい順に取得（行は含まない）
+func (m *Manager) ListPurchaseOrders(ctx context.Context, filter PurchaseOrderFilter) ([]PurchaseOrder, error) {
+	if err := validateOffsetLimit(filter.Offset, filter.Limit); err != nil {
+		return nil, err
+	}
+	switch filter.Status {
+	case "", PurchaseOrderStatusDraft, PurchaseOrderStatusApproved, PurchaseOrderStatusSent,
+		PurchaseOrderStatusPartiallyReceived, PurchaseOrderStatusReceived, PurchaseOrderStatusCancelled:
+	default:
+		return nil, NewValidationError("status", "発注の状態が正しくありません", string(filter.Status))
+	}
+
+	orders, err := m.storage.ListPurchaseOrders(ctx, filter)
+	if err != nil {
+		return nil, NewStorageError("list_purchase_orders", "発注一覧の取得に失敗しました", err)
+	}
+	return orders, nil
+}
+
+// ApprovePurchaseOrder approves a draft purchase order
+// 作成済みの発注を承認
+func (m *Manager) ApprovePurchaseOrder(ctx context.Context, orderID string) (_ *PurchaseOrder, err error) {
+	ctx, finish := m.startOperation(ctx, "approve_purchase_order", attrPurchaseOrder.String(orderID))
+	defer finish(&err)
+
+	order, err := m.GetPurchaseOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	update := *order
+	update.Status = PurchaseOrderStatusApproved
+	update.ApprovedAt = &now
+	update.ApprovedBy = m.getUserFromContext(ctx)
+	if err := m.updatePurchaseOrderStatus(ctx, &update, PurchaseOrderStatusDraft); err != nil {
+		return nil, err
+	}
+
+	m.log(ctx).Info("発注承認完了",
+		zap.String("purchase_order_id", order.ID),
+		zap.String("approved_by", update.ApprovedBy),
+	)
+	return m.GetPurchaseOrder(ctx, order.ID)
+}
+
+// SendPurchaseOrder marks an approved purchase order as sent to its supplier
+// 承認済みの発注を仕入先に送付済みにする
+//
+// 送付済みの発注に対して入荷を記録できます。
+func (m *Manager) SendPurchaseOrder(ctx context.Context, orderID string) (_ *PurchaseOrder, err error) {
+	ctx, finish := m.startOperation(ctx, "send_purchase_order", attrPurchaseOrder.String(orderID))
+	defer finish(&err)
+
+	order, err := m.GetPurchaseOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	update := *order
+	update.Status = PurchaseOrderStatusSent
+	update.SentAt = &now
+	update.SentBy = m.getUserFromContext(ctx)
+	if err := m.updatePurchaseOrderStatus(ctx, &update, PurchaseOrderStatusApproved); err != nil {
+		return nil, err
+	}
+
+	m.log(ctx).Info("発注送付完了",
+		zap.String("purchase_order_id", order.ID),
+		zap.String("supplier_id", order.SupplierID),
+	)
+	return m.GetPurchaseOrder(ctx, order.ID)
+}
+
+// CancelPurchaseOrder cancels a purchase order that has not been fully received
+// 全数入荷していない発注を取消
+//
+// 一部入荷の発注を取り消した場合、入荷済みの在庫は変わらず、発注残は入荷されなくなります。
+func (m *Manager) CancelPurchaseOrder(ctx context.Context, orderID string) (_ *PurchaseOrder, err error) {
+	ctx, finish := m.startOperation(ctx, "cancel_purchase_order", attrPurchaseOrder.String(orderID))
+	defer finish(&err)
+
+	order, err := m.GetPurchaseOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status == PurchaseOrderStatusReceived || order.Status == PurchaseOrderStatusCancelled {
+		return nil, ErrPurchaseOrderStatus
+	}
+
+	now := time.Now()
+	update := *order
+	update.Status = PurchaseOrderStatusCancelled
+	update.ClosedAt = &now
+	update.ClosedBy = m.getUserFromContext(ctx)
+	if err := m.updatePurchaseOrderStatus(ctx, &update, order.Status); err != nil {
+		return nil, err
+	}
+
+	m.log(ctx).Info("発注取消完了", zap.String("purchase_order_id", order.ID))
+	return m.GetPurchaseOrder(ctx, order.ID)
+}
+
+// ReceivePurchaseOrder records stock received against a sent purchase order in a single transaction
+// 送付済み・一部入荷の発注に対する入荷を単一トランザクションで記録
+//
+// 行ごとに入荷数量を発注の入荷先に入庫（inbound）し、トランザクションに仕入単価（0の場合は発注単価）・
+// ロット番号・有効期限と、metadata.purchase_order_id・metadata.goods_receipt_id を記録します。
+// 参照番号は発注書番号です。ロット番号を指定した行は、ロットがない場合に仕入単価と有効期限で作成し、
+// ロットの数量とロット在庫を加算します。同じ商品を複数の行（ロット）に分けて入荷できますが、
+// 商品ごとの合計が発注残を超える場合は ErrReceiptExceedsOrder を返し、在庫は変わりません。
+// 全ての行の発注数量を入荷すると received、それ以外は partially_received になります。
+func (m *Manager) ReceivePurchaseOrder(ctx context.Context, orderID string, receipt *GoodsReceipt) (_ *GoodsReceipt, err error) {
+	ctx, finish := m.startOperation(ctx, "receive_purchase_order", attrPurchaseOrder.String(orderID))
+	defer finish(&err)
+
+	if err := ValidateGoodsReceipt(receipt); err != nil {
+		return nil, err
+	}
+	order, err := m.GetPurchaseOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !order.receivable() {
+		return nil, ErrPurchaseOrderStatus
+	}
+
+	// 商品ごとの入荷数量の合計を発注残と比較
+	ordered := make(map[string]PurchaseOrderLine, len(order.Lines))
+	for _, line := range order.Lines {
+		ordered[line.ItemID] = line
+	}
+	received := make(map[string]int64, len(receipt.Lines))
+	for _, line := range receipt.Lines {
+		orderLine, ok := ordered[line.ItemID]
+		if !ok {
+			return nil, NewValidationError("item_id", "発注にない商品が指定されています", line.ItemID)
+		}
+		received[line.ItemID] += line.Quantity
+		if received[line.ItemID] > orderLine.Outstanding() {
+			return nil, ErrReceiptExceedsOrder
+		}
+	}
+
+	items := make(map[string]*Item, len(received))
+	var (
+		location *Location
+		total    int64
+	)
+	for itemID, quantity := range received {
+		item, loc, err := m.validateItemAndLocation(ctx, itemID, order.LocationID)
+		if err != nil {
+			return nil, err
+		}
+		items[itemID], location = item, loc
+		total += quantity
+	}
+
+	receipt.ID = NewGoodsReceiptID()
+	receipt.PurchaseOrderID = order.ID
+	receipt.LocationID = order.LocationID
+	receipt.ReceivedAt = time.Now()
+	receipt.ReceivedBy = m.getUserFromContext(ctx)
+
+	// 在庫・ロットの加算、トランザクション・入荷の記録と発注の更新を単一トランザクションで実行
+	var (
+		events *bufferedPublisher
+		stocks map[string]*Stock
+	)
+	err = m.retryOnConflict(ctx, func() error {
+		events = &bufferedPublisher{}
+		stocks = make(map[string]*Stock, len(received))
+		return m.storage.WithinTx(ctx, func(txStorage Storage) error {
+			txManager := m.withStorage(txStorage, events)
+			if err := txManager.enforceCapacity(ctx, location, total); err != nil {
+				return err
+			}
+
+			for i := range receipt.Lines {
+				line := &receipt.Lines[i]
+				orderLine, err := txManager.receivePurchaseOrderLine(ctx, order.ID, line.ItemID, line.Quantity)
+				if err != nil {
+					return err
+				}
+				if line.UnitCost == 0 {
+					line.UnitCost = orderLine.UnitCost
+				}
+				stock, err := txManager.receiveGoodsReceiptLine(ctx, order, receipt, items[line.ItemID], line)
+				if err != nil {
+					return err
+				}
+				stocks[line.ItemID] = stock
+			}
+
+			// 他の操作と競合していないことを、発注のロックを取得した後の状態で確認
+			current, err := txStorage.GetPurchaseOrder(ctx, order.ID)
+			if err != nil {
+				return NewStorageError("get_purchase_order", "発注の取得に失敗しました", err)
+			}
+			if !current.receivable() {
+				return ErrPurchaseOrderStatus
+			}
+			update := *current
+			update.Status = PurchaseOrderStatusReceived
+			for _, line := range current.Lines {
+				if line.Outstanding() > 0 {
+					update.Status = PurchaseOrderStatusPartiallyReceived
+					break
+				}
+			}
+			if update.Status == PurchaseOrderStatusReceived {
+				update.ClosedAt = &receipt.ReceivedAt
+				update.ClosedBy = receipt.ReceivedBy
+			}
+			if err := txManager.updatePurchaseOrderStatus(ctx, &update, current.Status); err != nil {
+				return err
+			}
+
+			if err := txStorage.CreateGoodsReceipt(ctx, receipt); err != nil {
+				return NewStorageError("create_goods_receipt", "入荷の記録に失敗しました", err)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if m.publisher != nil {
+		events.flush(ctx, m.publisher, m.logger)
+	}
+	for itemID, stock := range stocks {
+		m.checkOverStock(ctx, location, stock)
+		m.evaluateAlertRules(ctx, stock)
+		m.allocateArrivedStock(ctx, itemID, order.LocationID)
+	}
+
+	m.log(ctx).Info("発注の入荷記録完了",
+		zap.String("goods_receipt_id", receipt.ID),
+		zap.String("purchase_order_id", order.ID),
+		zap.String("location_id", order.LocationID),
+		zap.Int("lines", len(receipt.Lines)),
+		zap.Int64("quantity", total),
+	)
+	return receipt, nil
+}
+
+// ListGoodsReceipts lists the goods receipts of a purchase order, oldest first
+// 発注に対する入荷を入荷日時の古い順に取得
+func (m *Manager) ListGoodsReceipts(ctx context.Context, orderID string) ([]GoodsReceipt, error) {
+	if _, err := m.GetPurchaseOrder(ctx, orderID); err != nil {
+		return nil, err
+	}
+
+	receipts, err := m.storage.ListGoodsReceipts(ctx, orderID)
+	if err != nil {
+		return nil, NewStorageError("list_goods_receipts", "入荷一覧の取得に失敗しました", err)
+	}
+	return receipts, nil
+}
+
+// receivable reports whether stock can be received against the purchase order
+// 発注に対して入荷を記録できるか（送付済み・一部入荷）を判定
+func (o *PurchaseOrder) receivable() bool {
+	return o.Status == PurchaseOrderStatusSent || o.Status == PurchaseOrderStatusPartiallyReceived
+}
+
+// applySupplierTerms checks a purchase order line against the supplier's terms for the item and fills its unit cost
+// 発注の行を仕入先の商品の仕入条件で確認し、発注単価を補完
+func (m *Manager) applySupplierTerms(ctx context.Context, supplierID string, line *PurchaseOrderLine) error {
+	item, err := m.storage.GetItem(ctx, line.ItemID)
+	if err != nil {
+		if errors.Is(err, ErrItemNotFound) {
+			return ErrItemNotFound
+		}
+		return NewStorageError("get_item", "商品取得に失敗しました", err)
+	}
+
+	terms, err := m.storage.GetSupplierItem(ctx, supplierID, line.ItemID)
+	switch {
+	case errors.Is(err, ErrSupplierItemNotFound):
+		if line.UnitCost == 0 {
+			line.UnitCost = item.UnitCost
+		}
+		return nil
+	case err != nil:
+		return NewStorageError("get_supplier_item", "仕入条件の取得に失敗しました", err)
+	}
+
+	if terms.MinOrderQuantity > 0 && line.Quantity < terms.MinOrderQuantity {
+		return NewValidationError("quantity", "発注数量が最小発注数量を下回っています", fmt.Sprintf("%s: %d < %d", line.ItemID, line.Quantity, terms.MinOrderQuantity))
+	}
+	if line.UnitCost == 0 {
+		line.UnitCost = terms.UnitCost
+	}
+	return nil
+}
+
+// receivePurchaseOrderLine adds a received quantity to a purchase order line
+// 発注の行の入荷済み数量を加算
+func (m *Manager) receivePurchaseOrderLine(ctx context.Context, orderID, itemID string, quantity int64) (*PurchaseOrderLine, error) {
+	line, err := m.storage.ReceivePurchaseOrderLine(ctx, orderID, itemID, quantity)
+	if err != nil {
+		if errors.Is(err, ErrPurchaseOrderNotFound) || errors.Is(err, ErrReceiptExceedsOrder) {
+			return nil, err
+		}
+		return nil, NewStorageError("receive_purchase_order_line", "発注の入荷数量の更新に失敗しました", err)
+	}
+	return line, nil
+}
+
+// receiveGoodsReceiptLine adds one received line to stock and records its inbound transaction
+// 入荷した1行を在庫（ロットを指定した場合はロットとロット在庫も）に加算し、入庫のトランザクションを記録
+func (m *Manager) receiveGoodsReceiptLine(ctx context.Context, order *PurchaseOrder, receipt *GoodsReceipt, item *Item, line *GoodsReceiptLine) (*Stock, error) {
+	oldQuantity, stock, err := m.increaseStock(ctx, item.ID, order.LocationID, line.Quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	var lot *Lot
+	if line.LotNumber != "" {
+		if lot, err = m.receivePurchasedLot(ctx, item, order.LocationID, line); err != nil {
+			return nil, err
+		}
+	}
+
+	locationID := order.LocationID
+	unitCost := line.UnitCost
+	tx := &Transaction{
+		ID:         NewTransactionID(),
+		Type:       TransactionTypeInbound,
+		ItemID:     item.ID,
+		ToLocation: &locationID,
+		Quantity:   line.Quantity,
+		UnitCost:   &unitCost,
+		Reference:  order.Reference,
+		ExpiryDate: line.ExpiryDate,
+		Metadata: transactionMetadata(ctx, map[string]string{
+			MetadataPurchaseOrderID: order.ID,
+			MetadataGoodsReceiptID:  receipt.ID,
+		}),
+		CreatedAt: receipt.ReceivedAt,
+		CreatedBy: receipt.ReceivedBy,
+	}
+	if lot != nil {
+		tx.LotNumber = &lot.Number
+	}
+	if err := m.storage.CreateTransaction(ctx, tx); err != nil {
+		return nil, NewStorageError("create_transaction", "トランザクション記録に失敗しました", err)
+	}
+	line.TransactionID = tx.ID
+
+	if m.publisher != nil {
+		event := m.newStockChangedEvent(ctx, stock, oldQuantity, unitCost, "purchase_receipt", order.Reference, tx.ID)
+		if lot != nil {
+			event.LotID, event.LotNumber = lot.ID, lot.Number
+		}
+		if err := m.publisher.PublishStockChanged(ctx, event); err != nil {
+			m.log(ctx).Error("入荷イベント発行に失敗しました", zap.Error(err))
+		}
+	}
+	return stock, nil
+}
+
+// receivePurchasedLot adds a received line to its lot, creating the lot with the receipt's cost and expiry date if needed
+// 入荷した行をロットに加算（ロットがない場合は入荷の仕入単価・有効期限で作成、トランザクション内で呼び出すこと）
+func (m *Manager) receivePurchasedLot(ctx context.Context, item *Item, locationID string, line *GoodsReceiptLine) (*Lot, error) {
+	_, err := m.storage.GetLotByNumber(ctx, item.ID, line.LotNumber)
+	switch {
+	case errors.Is(err, ErrLotNotFound):
+		lot := &Lot{
+			ID:         NewLotID(),
+			Number:     line.LotNumber,
+			ItemID:     item.ID,
+			UnitCost:   line.UnitCost,
+			ExpiryDate: line.ExpiryDate,
+			CreatedAt:  time.Now(),
+		}
+		if err := m.storage.CreateLot(ctx, lot); err != nil {
+			return nil, NewStorageError("create_lot", "ロット作成に失敗しました", err)
+		}
+	case err != nil:
+		return nil, NewStorageError("get_lot", "ロット取得に失敗しました", err)
+	}
+	return m.receiveLot(ctx, item, locationID, line.LotNumber, line.Quantity)
+}
+
+// updatePurchaseOrderStatus updates a purchase order expected to be in status from
+// 状態がfromの発注を更新
+func (m *Manager) updatePurchaseOrderStatus(ctx context.Context, order *PurchaseOrder, from PurchaseOrderStatus) error {
+	if err := m.storage.UpdatePurchaseOrder(ctx, order, from); err != nil {
+		if errors.Is(err, ErrPurchaseOrderNotFound) || errors.Is(err, ErrPurchaseOrderStatus) {
+			return err
+		}
+		return NewStorageError("update_purchase_order", "発注の更新に失敗しました", err)
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var _ ReorderPointManager = (*Manager)(nil)
+
+// SetReorderPoint creates or overwrites the reorder point of an item at a location
+// 商品・ロケーションの発注点を作成または上書き
+//
+// 発注点を設定した在庫は、Config.LowStockThreshold の代わりに発注点で低在庫アラートを判定します。
+func (m *Manager) SetReorderPoint(ctx context.Context, reorderPoint *ReorderPoint) (err error) {
+	ctx, finish := m.startOperation(ctx, "set_reorder_point", attrItemID.String(reorderPoint.ItemID), attrLocationID.String(reorderPoint.LocationID))
+	defer finish(&err)
+
+	if err := ValidateReorderPoint(reorderPoint); err != nil {
+		return err
+	}
+	if _, err := m.storage.GetItem(ctx, reorderPoint.ItemID); err != nil {
+		if errors.Is(err, ErrItemNotFound) {
+			return ErrItemNotFound
+		}
+		return NewStorageError("get_item", "商品取得に失敗しました", err)
+	}
+	if _, err := m.storage.GetLocation(ctx, reorderPoint.LocationID); err != nil {
+		if errors.Is(err, ErrLocationNotFound) {
+			return ErrLocationNotFound
+		}
+		return NewStorageError("get_location", "ロケーション取得に失敗しました", err)
+	}
+
+	reorderPoint.UpdatedAt = time.Now()
+	reorderPoint.UpdatedBy = m.getUserFromContext(ctx)
+	if err := m.storage.SaveReorderPoint(ctx, reorderPoint); err != nil {
+		return NewStorageError("save_reorder_point", "発注点の保存に失敗しました", err)
+	}
+
+	m.log(ctx).Info("発注点設定完了",
+		zap.String("item_id", reorderPoint.ItemID),
+		zap.String("location_id", reorderPoint.LocationID),
+		zap.Int64("reorder_point", reorderPoint.ReorderPointQty),
+		zap.Int64("min_qty", reorderPoint.MinQty),
+		zap.Int64("max_qty", reorderPoint.MaxQty),
+	)
+	return nil
+}
+
+// GetReorderPoint retrieves the reorder point of an item at a location
+// 商品・ロケーションの発注点を取得
+func (m *Manager) GetReorderPoint(ctx context.Context, itemID, locationID string) (*ReorderPoint, error) {
+	if itemID == "" || locationID == "" {
+		return nil, NewValidationError("item_id", "商品IDとロケーションIDを指定してください", "")
+	}
+
+	reorderPoint, err := m.storage.GetReorderPoint(ctx, itemID, locationID)
+	if err != nil {
+		if errors.Is(err, ErrReorderPointNotFound) {
+			return nil, ErrReorderPointNotFound
+		}
+		return nil, NewStorageError("get_reorder_point", "発注点の取得に失敗しました", err)
+	}
+	return reorderPoint, nil
+}
+
+// DeleteReorderPoint deletes the reorder point of an item at a location
+// 商品・ロケーションの発注点を削除（以降は Config.LowStockThreshold で判定）
+func (m *Manager) DeleteReorderPoint(ctx context.Context, itemID, locationID string) (err error) {
+	ctx, finish := m.startOperation(ctx, "delete_reorder_point", attrItemID.String(itemID), attrLocationID.String(locationID))
+	defer finish(&err)
+
+	if itemID == "" || locationID == "" {
+		return NewValidationError("item_id", "商品IDとロケーションIDを指定してください", "")
+	}
+	if err := m.storage.DeleteReorderPoint(ctx, itemID, locationID); err != nil {
+		if errors.Is(err, ErrReorderPointNotFound) {
+			return ErrReorderPointNotFound
+		}
+		return NewStorageError("delete_reorder_point", "発注点の削除に失敗しました", err)
+	}
+
+	m.log(ctx).Info("発注点削除完了",
+		zap.String("item_id", itemID),
+		zap.String("location_id", locationID),
+	)
+	return nil
+}
+
+// ListReorderPoints lists reorder points matching a filter, ordered by item and location
+// 条件に一致する発注点を商品ID・ロケーションIDの昇順で取得
+func (m *Manager) ListReorderPoints(ctx context.Context, filter ReorderPointFilter) ([]ReorderPoint, error) {
+	if err := validateOffsetLimit(filter.Offset, filter.Limit); err != nil {
+		return nil, err
+	}
+
+	reorderPoints, err := m.storage.ListReorderPoints(ctx, filter)
+	if err != nil {
+		return nil, NewStorageError("list_reorder_points", "発注点一覧の取得に失敗しました", err)
+	}
+	return reorderPoints, nil
+}
+
+// checkLowStock triggers a low stock alert when a stock is at or below its reorder point
+// 在庫数量が発注点（設定していない場合は Config.LowStockThreshold）以下の場合に低在庫アラートを発生
+//
+// 発注点の取得に失敗した場合は、アラートを取りこぼさないよう Config.LowStockThreshold で判定します。
+// Config.DisableLowStockCheck が true の場合は判定しません（アラートルールのみで判定）。
+func (m *Manager) checkLowStock(ctx context.Context, itemID, locationID string, quantity int64) {
+	if m.config.DisableLowStockCheck {
+		return
+	}
+
+	reorderPoint, err := m.storage.GetReorderPoint(ctx, itemID, locationID)
+	if err != nil && !errors.Is(err, ErrReorderPointNotFound) {
+		m.log(ctx).Warn("発注点の取得に失敗しました",
+			zap.String("item_id", itemID),
+			zap.String("location_id", locationID),
+			zap.Error(err),
+		)
+	}
+	m.raiseLowStockAlert(ctx, itemID, locationID, quantity, reorderPoint)
+}
+
+// raiseLowStockAlert creates a low stock alert when quantity is at or below the reorder point (Config.LowStockThreshold when nil)
+// 在庫数量が発注点（nilの場合は Config.LowStockThreshold）以下の場合に低在庫アラートを作成
+//
+// アラートを作成した場合に true を返します。
+func (m *Manager) raiseLowStockAlert(ctx context.Context, itemID, locationID string, quantity int64, reorderPoint *ReorderPoint) bool {
+	threshold := m.config.LowStockThreshold
+	var reorderQty int64
+	if reorderPoint != nil {
+		threshold = reorderPoint.ReorderPointQty
+		reorderQty = reorderPoint.ReorderQuantity(quantity)
+	}
+
+	if quantity > threshold {
+		return false
+	}
+	message := fmt.Sprintf("商品 %s のロケーション %s での在庫が低下しています (現在: %d, 閾値: %d)", itemID, locationID, quantity, threshold)
+	if reorderQty > 0 {
+		message += fmt.Sprintf("。最大在庫数までの発注数: %d", reorderQty)
+	}
+	return m.triggerLowStockAlert(ctx, itemID, locationID, quantity, threshold, message)
+}
+
+// SweepLowStock raises low stock alerts for every stock record at or below its threshold without an active low stock alert
+// 発注点（閾値）以下でアクティブな低在庫アラートのない全ての在庫記録に低在庫アラートを作成し、作成したアラート数を返す
+//
+// 在庫操作時の判定では検出できない在庫（発注点の引き上げ後や、解決後も低在庫が続く在庫）を検出するため、
+// 定期的に実行します。Config.DisableLowStockCheck が true の場合は何もしません。
+func (m *Manager) SweepLowStock(ctx context.Context) (created int, err error) {
+	ctx, finish := m.startOperation(ctx, "sweep_low_stock")
+	defer finish(&err)
+
+	if m.config.DisableLowStockCheck {
+		return 0, nil
+	}
+	alerted, err := m.activeAlertKeys(ctx, AlertTypeLowStock)
+	if err != nil {
+		return 0, err
+	}
+	locationIDs, err := m.allLocationIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, locationID := range locationIDs {
+		if err := ctx.Err(); err != nil {
+			return created, err
+		}
+
+		reorderPoints, err := m.storage.ListReorderPoints(ctx, ReorderPointFilter{LocationID: locationID})
+		if err != nil {
+			return created, NewStorageError("list_reorder_points", "発注点一覧の取得に失敗しました", err)
+		}
+		byItem := make(map[string]*ReorderPoint, len(reorderPoints))
+		for i := range reorderPoints {
+			byItem[reorderPoints[i].ItemID] = &reorderPoints[i]
+		}
+
+		// 走査中の行を保持したまま書き込まないよう、対象の在庫記録を集めてからアラートを作成する
+		var candidates []Stock
+		err = m.storage.ForEachStockByLocation(ctx, locationID, func(stock Stock) error {
+			if !alerted[StockKey{ItemID: stock.ItemID, LocationID: stock.LocationID}] {
+				candidates = append(candidates, stock)
+			}
+			return nil
+		})
+		if err != nil {
+			return created, NewStorageError("get_stock_by_location", "ロケーション在庫取得に失敗しました", err)
+		}
+
+		for _, stock := range candidates {
+			if m.raiseLowStockAlert(ctx, stock.ItemID, stock.LocationID, stock.Quantity, byItem[stock.ItemID]) {
+				created++
+			}
+		}
+	}
+
+	if created > 0 {
+		m.log(ctx).Info("低在庫の検出完了", zap.Int("alerts", created))
+	}
+	return created, nil
+}
@@ -0,0 +1,209 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ReplayResult summarizes an event replay
+// イベント再生の結果
+type ReplayResult struct {
+	Transactions      int       `json:"transactions"`        // 期間内で再生したトランザクション数
+	Events            int       `json:"events"`              // 発行したイベント数
+	Skipped           int       `json:"skipped"`             // ロケーションを持たないため再生しなかったトランザクション数
+	LastTransactionID string    `json:"last_transaction_id"` // 最後に再生したトランザクションID
+	LastCreatedAt     time.Time `json:"last_created_at"`     // 最後に再生したトランザクションの作成日時（中断時の再開位置）
+}
+
+// balanceKey identifies a stock balance while replaying
+// 再生中の在庫残高のキー
+type balanceKey struct {
+	itemID     string
+	locationID string
+}
+
+// ReplayEvents publishes the transactions created in [from, to) as events
+// from以上to未満に作成されたトランザクションをイベントとして発行
+//
+// 新しく接続した下流システムへのバックフィルに使用します。イベントのtransaction_idと
+// タイムスタンプは元のトランザクションのものを使用するため、受信側で重複を排除できます。
+// 変更前後の数量は台帳の最初からトランザクションを積み上げて算出するため、アーカイブ済みの
+// トランザクションやトランザクションを伴わない在庫の投入がある場合は実際の在庫数と一致しません。
+// ロケーションを持たないロットの数量調整は再生しません。
+// 発行に失敗した場合はその時点で中断し、それまでの結果とエラーを返します。
+func (m *Manager) ReplayEvents(ctx context.Context, from, to time.Time) (*ReplayResult, error) {
+	if m.publisher == nil {
+		return nil, NewBusinessRuleError("event_publisher", "イベント発行者が設定されていません", "replay")
+	}
+	if !to.IsZero() && !from.Before(to) {
+		return nil, NewValidationError("to", "終了日時は開始日時より後である必要があります", to.Format(time.RFC3339))
+	}
+
+	result := &ReplayResult{}
+	balances := make(map[balanceKey]int64)
+
+	// 期間の開始時点の残高を得るため、台帳の最初から走査する
+	err := m.storage.ForEachTransactionInRange(ctx, time.Time{}, to, func(tx Transaction) error {
+		replay := !tx.CreatedAt.Before(from)
+		events, err := m.replayTransaction(ctx, tx, balances, replay)
+		if err != nil {
+			return err
+		}
+		if !replay {
+			return nil
+		}
+
+		if tx.FromLocation == nil && tx.ToLocation == nil {
+			result.Skipped++
+		} else {
+			result.Transactions++
+		}
+		result.Events += events
+		result.LastTransactionID = tx.ID
+		result.LastCreatedAt = tx.CreatedAt
+		return nil
+	})
+	if err != nil {
+		m.logger.Error("イベント再生を中断しました",
+			zap.Int("events", result.Events),
+			zap.String("last_transaction_id", result.LastTransactionID),
+			zap.Error(err),
+		)
+		return result, err
+	}
+
+	m.logger.Info("イベント再生完了",
+		zap.Time("from", from),
+		zap.Time("to", to),
+		zap.Int("transactions", result.Transactions),
+		zap.Int("events", result.Events),
+	)
+
+	return result, nil
+}
+
+// replayTransaction updates balances for tx and publishes its events when publish is true
+// txに応じて残高を更新し、publishがtrueの場合はイベントを発行して発行数を返す
+func (m *Manager) replayTransaction(ctx context.Context, tx Transaction, balances map[balanceKey]int64, publish bool) (int, error) {
+	var changes []StockChangedEvent
+	err := applyLedgerTransaction(tx, func(locationID string, delta int64, changeType string) {
+		key := balanceKey{itemID: tx.ItemID, locationID: locationID}
+		old := balances[key]
+		balances[key] = old + delta
+		changes = append(changes, m.replayStockChanged(tx, locationID, old, old+delta, changeType))
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if !publish {
+		return 0, nil
+	}
+
+	for _, event := range changes {
+		if err := m.publisher.PublishStockChanged(ctx, event); err != nil {
+			return 0, fmt.Errorf("トランザクション %s のイベント発行に失敗しました: %w", tx.ID, err)
+		}
+	}
+	events := len(changes)
+
+	if tx.Type == TransactionTypeTransfer && len(changes) == 2 {
+		event := ItemTransferredEvent{
+			ItemID:         tx.ItemID,
+			FromLocationID: *tx.FromLocation,
+			ToLocationID:   *tx.ToLocation,
+			Quantity:       tx.Quantity,
+			Reference:      tx.Reference,
+			TransactionID:  tx.ID,
+			Timestamp:      tx.CreatedAt,
+			UserID:         tx.CreatedBy,
+			LotID:          tx.Metadata["lot_id"],
+			LotNumber:      stringValue(tx.LotNumber),
+		}
+		if err := m.publisher.PublishItemTransferred(ctx, event); err != nil {
+			return 0, fmt.Errorf("トランザクション %s の移動イベント発行に失敗しました: %w", tx.ID, err)
+		}
+		events++
+	}
+
+	return events, nil
+}
+
+// applyLedgerTransaction calls change for each location balance that tx modifies
+// txが増減させるロケーションごとの残高についてchangeを呼び出す
+//
+// ロケーションを持たないトランザクション（ロットの数量調整など）や、
+// 在庫数量を変えない予約・予約解除のトランザクションでは呼び出しません。
+func applyLedgerTransaction(tx Transaction, change func(locationID string, delta int64, changeType string)) error {
+	switch tx.Type {
+	case TransactionTypeInbound:
+		if tx.ToLocation != nil {
+			change(*tx.ToLocation, tx.Quantity, "add")
+		}
+	case TransactionTypeOutbound:
+		if tx.FromLocation != nil {
+			change(*tx.FromLocation, -tx.Quantity, "remove")
+		}
+	case TransactionTypeAdjust:
+		// 調整は差分が記録されている
+		if tx.ToLocation != nil {
+			change(*tx.ToLocation, tx.Quantity, "adjust")
+		}
+	case TransactionTypeStocktake:
+		// 棚卸差異の調整も差分が記録されている
+		if tx.ToLocation != nil {
+			change(*tx.ToLocation, tx.Quantity, "stocktake")
+		}
+	case TransactionTypeAssembly:
+		// キットの組立・分解も構成品・キットごとの差分が記録されている
+		if tx.ToLocation != nil {
+			changeType := tx.Metadata[MetadataAssemblyType]
+			if changeType == "" {
+				changeType = string(AssemblyTypeAssemble)
+			}
+			change(*tx.ToLocation, tx.Quantity, changeType)
+		}
+	case TransactionTypeTransfer:
+		if tx.FromLocation != nil && tx.ToLocation != nil {
+			change(*tx.FromLocation, -tx.Quantity, "transfer")
+			change(*tx.ToLocation, tx.Quantity, "transfer")
+		}
+	case TransactionTypeReserve, TransactionTypeRelease:
+		// 予約数量のみを変更し、在庫数量は変わらない
+	default:
+		return fmt.Errorf("未知のトランザクションタイプ: %s", tx.Type)
+	}
+	return nil
+}
+
+// replayStockChanged builds the stock changed event of a replayed transaction
+// 再生するトランザクションの在庫変更イベントを作成
+func (m *Manager) replayStockChanged(tx Transaction, locationID string, oldQuantity, newQuantity int64, changeType string) StockChangedEvent {
+	return StockChangedEvent{
+		ItemID:        tx.ItemID,
+		LocationID:    locationID,
+		OldQuantity:   oldQuantity,
+		NewQuantity:   newQuantity,
+		ChangeType:    changeType,
+		Reference:     tx.Reference,
+		TransactionID: tx.ID,
+		Timestamp:     tx.CreatedAt,
+		UserID:        tx.CreatedBy,
+		LotID:         tx.Metadata["lot_id"],
+		LotNumber:     stringValue(tx.LotNumber),
+		Delta:         newQuantity - oldQuantity,
+		UnitCost:      tx.UnitCost,
+	}
+}
+
+// stringValue returns the value of s or an empty string when s is nil
+// sの値を返す（nilの場合は空文字列）
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
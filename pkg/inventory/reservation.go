@@ -0,0 +1,669 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// expiryBatchSize is the number of expired reservations fetched at a time
+// 一度に取得する期限切れの予約の件数
+const expiryBatchSize = 100
+
+var _ ReservationManager = (*Manager)(nil)
+
+// CreateReservation reserves stock and records the hold as a reservation
+// 在庫を予約し、予約として記録
+//
+// ItemID・LocationID・Quantity・Reference・ExpiresAt・Channel を指定します。ID・状態・作成日時・作成者は設定されます。
+// 利用可能数が不足する場合は ErrInsufficientStock を返します。Channel を指定した場合はチャネルの確保数量の残りと
+// 共有の利用可能数量から、指定しない場合は共有の利用可能数量のみから予約します（SetChannelAllocation を参照）。
+func (m *Manager) CreateReservation(ctx context.Context, reservation *Reservation) (err error) {
+	ctx, finish := m.startOperation(ctx, "create_reservation", attrItemID.String(reservation.ItemID), attrLocationID.String(reservation.LocationID), attrQuantity.Int64(reservation.Quantity), attrReference.String(reservation.Reference))
+	defer finish(&err)
+
+	return m.reserve(ctx, reservation)
+}
+
+// GetReservation retrieves a reservation by ID
+// IDで予約を取得
+func (m *Manager) GetReservation(ctx context.Context, reservationID string) (*Reservation, error) {
+	if reservationID == "" {
+		return nil, NewValidationError("reservation_id", "予約IDが指定されていません", "")
+	}
+
+	reservation, err := m.storage.GetReservation(ctx, reservationID)
+	if err != nil {
+		if errors.Is(err, ErrReservationNotFound) {
+			return nil, ErrReservationNotFound
+		}
+		return nil, NewStorageError("get_reservation", "予約の取得に失敗しました", err)
+	}
+	return reservation, nil
+}
+
+// ListReservations lists reservations matching a filter, newest first
+// 条件に一致する予約を作成日時の降順で取得
+func (m *Manager) ListReservations(ctx context.Context, filter ReservationFilter) ([]Reservation, error) {
+	if err := validateOffsetLimit(filter.Offset, filter.Limit); err != nil {
+		return nil, err
+	}
+	switch filter.Status {
+	case "", ReservationStatusActive, ReservationStatusReleased, ReservationStatusExpired, ReservationStatusFulfilled:
+	default:
+		return nil, NewValidationError("status", "予約の状態が正しくありません", string(filter.Status))
+	}
+
+	reservations, err := m.storage.ListReservations(ctx, filter)
+	if err != nil {
+		return nil, NewStorageError("list_reservations", "予約一覧の取得に失敗しました", err)
+	}
+	return reservations, nil
+}
+
+// ReleaseReservationByID releases an active reservation and returns it
+// 有効な予約を解除し、解除後の予約を返す
+//
+// 解除済み・期限切れの予約は ErrReservationNotActive を返します。
+func (m *Manager) ReleaseReservationByID(ctx context.Context, reservationID string) (_ *Reservation, err error) {
+	ctx, finish := m.startOperation(ctx, "release_reservation_by_id", attrReservationID.String(reservationID))
+	defer finish(&err)
+
+	if reservationID == "" {
+		return nil, NewValidationError("reservation_id", "予約IDが指定されていません", "")
+	}
+	return m.releaseReservation(ctx, reservationID, ReservationStatusReleased)
+}
+
+// ExpireReservations releases the holds of active reservations past their expiry
+// 有効期限を過ぎた有効な予約を期限切れにし、確保した在庫を解放
+//
+// 期限切れにした予約の件数を返します。同時に解除された予約は数えません。
+func (m *Manager) ExpireReservations(ctx context.Context) (expired int, err error) {
+	ctx, finish := m.startOperation(ctx, "expire_reservations")
+	defer finish(&err)
+
+	now := time.Now()
+	for {
+		reservations, err := m.storage.GetExpiredReservations(ctx, now, expiryBatchSize)
+		if err != nil {
+			return expired, NewStorageError("get_expired_reservations", "期限切れ予約の取得に失敗しました", err)
+		}
+
+		for _, reservation := range reservations {
+			if _, err := m.releaseReservation(ctx, reservation.ID, ReservationStatusExpired); err != nil {
+				if errors.Is(err, ErrReservationNotActive) || errors.Is(err, ErrReservationNotFound) {
+					continue
+				}
+				// 失敗した予約は次回の取得に再び含まれるため、ここで中断する
+				return expired, err
+			}
+			expired++
+		}
+
+		if len(reservations) < expiryBatchSize {
+			break
+		}
+	}
+
+	if expired > 0 {
+		m.log(ctx).Info("期限切れ予約の解放完了", zap.Int("expired", expired))
+	}
+	return expired, nil
+}
+
+// RunReservationExpiry runs ExpireReservations every interval until ctx is cancelled
+// ctxがキャンセルされるまで interval ごとに ExpireReservations を実行
+func (m *Manager) RunReservationExpiry(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := m.ExpireReservations(ctx); err != nil && ctx.Err() == nil {
+			m.log(ctx).Warn("期限切れ予約の解放に失敗しました", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reserve increases the reserved quantity and creates the reservation in one transaction
+// 予約数量の加算と予約の作成を一つのトランザクションで実行
+func (m *Manager) reserve(ctx context.Context, reservation *Reservation) error {
+	if reservation.Quantity <= 0 {
+		return NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", reservation.Quantity))
+	}
+	now := time.Now()
+	if reservation.ExpiresAt != nil && !reservation.ExpiresAt.After(now) {
+		return NewValidationError("expires_at", "有効期限は未来の日時で指定してください", reservation.ExpiresAt.Format(time.RFC3339))
+	}
+	if reservation.Channel != "" {
+		if err := ValidateChannel(reservation.Channel); err != nil {
+			return err
+		}
+	}
+
+	reservation.ID = NewReservationID()
+	reservation.Status = ReservationStatusActive
+	reservation.CreatedAt = now
+	reservation.CreatedBy = m.getUserFromContext(ctx)
+	reservation.ReleasedAt = nil
+
+	var (
+		updated *Stock
+		tx      *Transaction
+	)
+	err := m.withReservationTx(ctx, func(lm *Manager) (err error) {
+		updated, tx, err = lm.holdReservation(ctx, reservation, now)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	m.publishReservationCreated(ctx, reservation, updated, tx)
+	return nil
+}
+
+// holdReservation adds a reservation to the reserved quantity and records it
+// 予約を在庫の予約数量に加算し、予約とトランザクションを記録
+//
+// トランザクション内で呼び出し、更新後の在庫と記録したトランザクションを返します。
+// 予約の販売チャネルが予約できる数量が不足する場合、ロケーションに在庫がない場合は ErrInsufficientStock を返します。
+func (m *Manager) holdReservation(ctx context.Context, reservation *Reservation, now time.Time) (*Stock, *Transaction, error) {
+	// 現在の在庫を取得
+	stock, err := m.getStockForWrite(ctx, reservation.ItemID, reservation.LocationID)
+	if err != nil {
+		if errors.Is(err, ErrStockNotFound) {
+			return nil, nil, ErrInsufficientStock
+		}
+		return nil, nil, NewStorageError("get_stock", "在庫取得に失敗しました", err)
+	}
+
+	// 予約可能量チェック（他の販売チャネルに確保された数量は予約できない）
+	if stock.Available < reservation.Quantity {
+		return nil, nil, ErrInsufficientStock
+	}
+	available, err := m.reservableQuantity(ctx, stock, reservation.Channel)
+	if err != nil {
+		return nil, nil, err
+	}
+	if available < reservation.Quantity {
+		return nil, nil, ErrInsufficientStock
+	}
+
+	// 予約量更新
+	stock.Reserved += reservation.Quantity
+	stock.Version++
+	stock.UpdatedAt = now
+	stock.UpdatedBy = reservation.CreatedBy
+	stock.CalculateAvailable()
+
+	if err := m.storage.UpdateStock(ctx, stock); err != nil {
+		return nil, nil, NewStorageError("update_stock", "在庫更新に失敗しました", err)
+	}
+	if err := m.storage.CreateReservation(ctx, reservation); err != nil {
+		return nil, nil, NewStorageError("create_reservation", "予約の作成に失敗しました", err)
+	}
+	tx, err := m.recordReservationTransaction(ctx, TransactionTypeReserve, reservation.ID, reservation.Channel,
+		reservation.ItemID, reservation.LocationID, reservation.Quantity, reservation.Reference, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	return stock, tx, nil
+}
+
+// publishReservationCreated logs and publishes the creation of a reservation and evaluates the alert rules
+// 予約の作成をログに記録してイベントを発行し、利用可能数の変化をアラートルールで評価
+func (m *Manager) publishReservationCreated(ctx context.Context, reservation *Reservation, stock *Stock, tx *Transaction) {
+	m.log(ctx).Info("在庫予約完了",
+		zap.String("reservation_id", reservation.ID),
+		zap.String("item_id", reservation.ItemID),
+		zap.String("location_id", reservation.LocationID),
+		zap.Int64("quantity", reservation.Quantity),
+		zap.String("reference", reservation.Reference),
+		zap.String("transaction_id", tx.ID),
+	)
+
+	m.publishReservationStockChanged(ctx, stock, "reserve", tx)
+	m.evaluateAlertRules(ctx, stock)
+	m.publishEvent(ctx, EventTypeReservationCreated, reservation.ItemID, reservation.LocationID, ReservationEvent{
+		ReservationID: reservation.ID,
+		TransactionID: tx.ID,
+		ItemID:        reservation.ItemID,
+		LocationID:    reservation.LocationID,
+		Quantity:      reservation.Quantity,
+		Reserved:      stock.Reserved,
+		Available:     stock.Available,
+		Reference:     reservation.Reference,
+		ExpiresAt:     reservation.ExpiresAt,
+		Channel:       reservation.Channel,
+		UserID:        reservation.CreatedBy,
+	})
+}
+
+// releaseReservation ends an active reservation with status and releases its hold
+// 有効な予約を status で終了し、確保した在庫を解放
+func (m *Manager) releaseReservation(ctx context.Context, reservationID string, status ReservationStatus) (*Reservation, error) {
+	var (
+		released *Reservation
+		updated  *Stock
+		tx       *Transaction
+	)
+	err := m.withReservationTx(ctx, func(lm *Manager) error {
+		reservation, err := lm.storage.GetReservation(ctx, reservationID)
+		if err != nil {
+			if errors.Is(err, ErrReservationNotFound) {
+				return ErrReservationNotFound
+			}
+			return NewStorageError("get_reservation", "予約の取得に失敗しました", err)
+		}
+
+		stock, recorded, err := lm.endReservation(ctx, reservation, status, time.Now())
+		if err != nil {
+			return err
+		}
+		released, updated, tx = reservation, stock, recorded
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.publishReservationEnded(ctx, released, updated, tx)
+	return released, nil
+}
+
+// ReleaseReservationsByReference releases every active reservation of a reference
+// 参照番号の有効な予約を全て解除し、解除した予約を返す
+//
+// 注文のキャンセル時に、その注文で確保した数量だけを解放するために使用します。
+// 全ての予約を一つのトランザクションで解除します。有効な予約がない場合は空のスライスを返します。
+func (m *Manager) ReleaseReservationsByReference(ctx context.Context, reference string) (_ []Reservation, err error) {
+	ctx, finish := m.startOperation(ctx, "release_reservations_by_reference", attrReference.String(reference))
+	defer finish(&err)
+
+	if reference == "" {
+		return nil, NewValidationError("reference", "参照番号が指定されていません", "")
+	}
+
+	var (
+		released []Reservation
+		updated  []*Stock
+		txs      []*Transaction
+	)
+	err = m.withReservationTx(ctx, func(lm *Manager) error {
+		reservations, err := lm.storage.ListReservations(ctx, ReservationFilter{Reference: reference, Status: ReservationStatusActive})
+		if err != nil {
+			return NewStorageError("list_reservations", "予約一覧の取得に失敗しました", err)
+		}
+
+		released, updated = make([]Reservation, 0, len(reservations)), make([]*Stock, 0, len(reservations))
+		txs = make([]*Transaction, 0, len(reservations))
+		now := time.Now()
+		for i := range reservations {
+			stock, tx, err := lm.endReservation(ctx, &reservations[i], ReservationStatusReleased, now)
+			if err != nil {
+				if errors.Is(err, ErrReservationNotActive) {
+					// 同時に解除・期限切れになった予約はリトライで除外する
+					return ErrVersionMismatch
+				}
+				return err
+			}
+			released = append(released, reservations[i])
+			updated = append(updated, stock)
+			txs = append(txs, tx)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range released {
+		m.publishReservationEnded(ctx, &released[i], updated[i], txs[i])
+	}
+	return released, nil
+}
+
+// GetReservationsByReference retrieves every reservation of a reference, newest first
+// 参照番号の全ての予約を作成日時の降順で取得
+func (m *Manager) GetReservationsByReference(ctx context.Context, reference string) ([]Reservation, error) {
+	if reference == "" {
+		return nil, NewValidationError("reference", "参照番号が指定されていません", "")
+	}
+
+	reservations, err := m.storage.ListReservations(ctx, ReservationFilter{Reference: reference})
+	if err != nil {
+		return nil, NewStorageError("list_reservations", "予約一覧の取得に失敗しました", err)
+	}
+	return reservations, nil
+}
+
+// FulfillReservation ships the stock held by a reservation
+// 予約で確保した在庫を出庫
+//
+// 在庫数量と予約数量を予約の数量だけ減算し、出庫トランザクションを一件記録して予約を fulfilled にします。
+// これらは一つのトランザクションで行います。reference を省略した場合は予約の参照番号を記録します。
+// 解除済み・期限切れ・出庫済みの予約は ErrReservationNotActive を返します。
+func (m *Manager) FulfillReservation(ctx context.Context, reservationID, reference string) (_ *Transaction, err error) {
+	ctx, finish := m.startOperation(ctx, "fulfill_reservation", attrReservationID.String(reservationID), attrReference.String(reference))
+	defer finish(&err)
+
+	if reservationID == "" {
+		return nil, NewValidationError("reservation_id", "予約IDが指定されていません", "")
+	}
+
+	var (
+		fulfilled   *Reservation
+		stock       *Stock
+		oldQuantity int64
+		tx          *Transaction
+	)
+	err = m.withReservationTx(ctx, func(lm *Manager) error {
+		reservation, err := lm.storage.GetReservation(ctx, reservationID)
+		if err != nil {
+			if errors.Is(err, ErrReservationNotFound) {
+				return ErrReservationNotFound
+			}
+			return NewStorageError("get_reservation", "予約の取得に失敗しました", err)
+		}
+		if reservation.Status != ReservationStatusActive {
+			return ErrReservationNotActive
+		}
+
+		current, err := lm.getStockForWrite(ctx, reservation.ItemID, reservation.LocationID)
+		if err != nil {
+			return NewStorageError("get_stock", "在庫取得に失敗しました", err)
+		}
+		if current.Reserved < reservation.Quantity || current.Quantity < reservation.Quantity {
+			return ErrInsufficientReservation
+		}
+
+		// 予約で確保済みのため利用可能数は変わらない
+		now := time.Now()
+		oldQuantity = current.Quantity
+		current.Quantity -= reservation.Quantity
+		current.Reserved -= reservation.Quantity
+		current.Version++
+		current.UpdatedAt = now
+		current.UpdatedBy = lm.getUserFromContext(ctx)
+		current.CalculateAvailable()
+
+		if err := lm.storage.UpdateStock(ctx, current); err != nil {
+			return NewStorageError("update_stock", "在庫更新に失敗しました", err)
+		}
+
+		reservation.Status = ReservationStatusFulfilled
+		reservation.ReleasedAt = &now
+		if err := lm.storage.UpdateReservation(ctx, reservation); err != nil {
+			if errors.Is(err, ErrReservationNotActive) {
+				return ErrReservationNotActive
+			}
+			return NewStorageError("update_reservation", "予約の更新に失敗しました", err)
+		}
+
+		txReference := reference
+		if txReference == "" {
+			txReference = reservation.Reference
+		}
+		locationID := reservation.LocationID
+		tx = &Transaction{
+			ID:           NewTransactionID(),
+			Type:         TransactionTypeOutbound,
+			ItemID:       reservation.ItemID,
+			FromLocation: &locationID,
+			Quantity:     reservation.Quantity,
+			Reference:    txReference,
+			Metadata:     transactionMetadata(ctx, channelMetadata(reservation.Channel, map[string]string{MetadataReservationID: reservation.ID})),
+			CreatedAt:    now,
+			CreatedBy:    current.UpdatedBy,
+		}
+		if err := lm.storage.CreateTransaction(ctx, tx); err != nil {
+			return NewStorageError("create_transaction", "トランザクション記録に失敗しました", err)
+		}
+
+		fulfilled, stock = reservation, current
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// イベント発行
+	if m.publisher != nil {
+		var unitCost float64
+		if item, err := m.storage.GetItem(ctx, fulfilled.ItemID); err == nil {
+			unitCost = item.UnitCost
+		}
+		event := m.newStockChangedEvent(ctx, stock, oldQuantity, unitCost, "fulfill", tx.Reference, tx.ID)
+		if err := m.publisher.PublishStockChanged(ctx, event); err != nil {
+			m.log(ctx).Error("イベント発行に失敗しました", zap.Error(err))
+		}
+	}
+
+	// 低在庫アラート・アラートルールのチェック
+	m.checkLowStock(ctx, fulfilled.ItemID, fulfilled.LocationID, stock.Quantity)
+	m.evaluateAlertRules(ctx, stock)
+
+	m.log(ctx).Info("予約の出庫完了",
+		zap.String("reservation_id", fulfilled.ID),
+		zap.String("item_id", fulfilled.ItemID),
+		zap.String("location_id", fulfilled.LocationID),
+		zap.Int64("quantity", fulfilled.Quantity),
+		zap.String("transaction_id", tx.ID),
+	)
+
+	m.publishEvent(ctx, EventTypeReservationFulfilled, fulfilled.ItemID, fulfilled.LocationID, ReservationEvent{
+		ReservationID: fulfilled.ID,
+		TransactionID: tx.ID,
+		ItemID:        fulfilled.ItemID,
+		LocationID:    fulfilled.LocationID,
+		Quantity:      fulfilled.Quantity,
+		Reserved:      stock.Reserved,
+		Available:     stock.Available,
+		Reference:     fulfilled.Reference,
+		ExpiresAt:     fulfilled.ExpiresAt,
+		Channel:       fulfilled.Channel,
+		UserID:        stock.UpdatedBy,
+	})
+
+	return tx, nil
+}
+
+// endReservation ends an active reservation with status and subtracts it from the reserved quantity
+// 有効な予約を status で終了し、在庫の予約数量から減算
+//
+// トランザクション内で呼び出し、更新後の在庫と記録した予約解除のトランザクションを返します。
+func (m *Manager) endReservation(ctx context.Context, reservation *Reservation, status ReservationStatus, now time.Time) (*Stock, *Transaction, error) {
+	if reservation.Status != ReservationStatusActive {
+		return nil, nil, ErrReservationNotActive
+	}
+
+	stock, err := m.getStockForWrite(ctx, reservation.ItemID, reservation.LocationID)
+	if err != nil {
+		return nil, nil, NewStorageError("get_stock", "在庫取得に失敗しました", err)
+	}
+
+	stock.Reserved -= reservation.Quantity
+	if stock.Reserved < 0 {
+		// 予約数量が記録と一致しない場合も予約は終了できるようにする
+		m.log(ctx).Warn("予約数量が予約の記録より少なくなっています",
+			zap.String("reservation_id", reservation.ID),
+			zap.Int64("reserved", stock.Reserved+reservation.Quantity),
+			zap.Int64("quantity", reservation.Quantity),
+		)
+		stock.Reserved = 0
+	}
+	stock.Version++
+	stock.UpdatedAt = now
+	stock.UpdatedBy = m.getUserFromContext(ctx)
+	stock.CalculateAvailable()
+
+	if err := m.storage.UpdateStock(ctx, stock); err != nil {
+		return nil, nil, NewStorageError("update_stock", "在庫更新に失敗しました", err)
+	}
+
+	reservation.Status = status
+	reservation.ReleasedAt = &now
+	if err := m.storage.UpdateReservation(ctx, reservation); err != nil {
+		if errors.Is(err, ErrReservationNotActive) {
+			return nil, nil, ErrReservationNotActive
+		}
+		return nil, nil, NewStorageError("update_reservation", "予約の更新に失敗しました", err)
+	}
+
+	tx, err := m.recordReservationTransaction(ctx, TransactionTypeRelease, reservation.ID, reservation.Channel,
+		reservation.ItemID, reservation.LocationID, reservation.Quantity, reservation.Reference, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	return stock, tx, nil
+}
+
+// recordReservationTransaction records a reserve or release transaction
+// 予約・予約解除のトランザクションを記録
+//
+// 在庫数量は変わらないため台帳の数量計算（整合性チェック・イベント再生）には影響しません。
+// 予約はロケーションを FromLocation に、予約解除は ToLocation に記録し、
+// 予約IDがある場合はメタデータの MetadataReservationID に、販売チャネルがある場合は MetadataChannel に記録します。
+func (m *Manager) recordReservationTransaction(ctx context.Context, txType TransactionType, reservationID, channel, itemID, locationID string, quantity int64, reference string, now time.Time) (*Transaction, error) {
+	tx := &Transaction{
+		ID:        NewTransactionID(),
+		Type:      txType,
+		ItemID:    itemID,
+		Quantity:  quantity,
+		Reference: reference,
+		CreatedAt: now,
+		CreatedBy: m.getUserFromContext(ctx),
+	}
+	if txType == TransactionTypeReserve {
+		tx.FromLocation = &locationID
+	} else {
+		tx.ToLocation = &locationID
+	}
+	var metadata map[string]string
+	if reservationID != "" {
+		metadata = map[string]string{MetadataReservationID: reservationID}
+	}
+	tx.Metadata = transactionMetadata(ctx, channelMetadata(channel, metadata))
+
+	if err := m.storage.CreateTransaction(ctx, tx); err != nil {
+		return nil, NewStorageError("create_transaction", "トランザクション記録に失敗しました", err)
+	}
+	return tx, nil
+}
+
+// publishReservationStockChanged publishes the stock changed event of a reservation operation
+// 予約操作の在庫変更イベントを発行（在庫数量は変わらず、利用可能数のみ変わる）
+func (m *Manager) publishReservationStockChanged(ctx context.Context, stock *Stock, changeType string, tx *Transaction) {
+	if m.publisher == nil {
+		return
+	}
+	event := m.newStockChangedEvent(ctx, stock, stock.Quantity, 0, changeType, tx.Reference, tx.ID)
+	if err := m.publisher.PublishStockChanged(ctx, event); err != nil {
+		m.log(ctx).Error("イベント発行に失敗しました", zap.Error(err))
+	}
+}
+
+// publishReservationEnded logs and publishes the release or expiry of a reservation and evaluates the alert rules
+// 予約の解除・期限切れをログに記録してイベントを発行し、利用可能数の変化をアラートルールで評価
+func (m *Manager) publishReservationEnded(ctx context.Context, reservation *Reservation, stock *Stock, tx *Transaction) {
+	eventType, message := EventTypeReservationReleased, "在庫予約解除完了"
+	if reservation.Status == ReservationStatusExpired {
+		eventType, message = EventTypeReservationExpired, "在庫予約の期限切れ"
+	}
+	m.log(ctx).Info(message,
+		zap.String("reservation_id", reservation.ID),
+		zap.String("item_id", reservation.ItemID),
+		zap.String("location_id", reservation.LocationID),
+		zap.Int64("quantity", reservation.Quantity),
+		zap.String("reference", reservation.Reference),
+		zap.String("transaction_id", tx.ID),
+	)
+
+	m.publishReservationStockChanged(ctx, stock, "release", tx)
+	m.evaluateAlertRules(ctx, stock)
+	m.publishEvent(ctx, eventType, reservation.ItemID, reservation.LocationID, ReservationEvent{
+		ReservationID: reservation.ID,
+		TransactionID: tx.ID,
+		ItemID:        reservation.ItemID,
+		LocationID:    reservation.LocationID,
+		Quantity:      reservation.Quantity,
+		Reserved:      stock.Reserved,
+		Available:     stock.Available,
+		Reference:     reservation.Reference,
+		ExpiresAt:     reservation.ExpiresAt,
+		Channel:       reservation.Channel,
+		UserID:        stock.UpdatedBy,
+	})
+}
+
+// releaseReservedQuantity releases quantity from the active reservations of a stock record
+// 在庫記録の有効な予約から quantity 分を解除
+//
+// 参照番号が一致する予約を優先し、古い予約から順に解除します（数量の一部のみ解除する予約は数量を減らします）。
+// 予約の記録がない予約数量（予約IDの導入前の予約など）は記録を変更せずに解除します。
+func (m *Manager) releaseReservedQuantity(ctx context.Context, itemID, locationID string, quantity int64, reference string, now time.Time) error {
+	reservations, err := m.storage.ListReservations(ctx, ReservationFilter{
+		ItemID:     itemID,
+		LocationID: locationID,
+		Status:     ReservationStatusActive,
+	})
+	if err != nil {
+		return NewStorageError("list_reservations", "予約一覧の取得に失敗しました", err)
+	}
+
+	sort.SliceStable(reservations, func(i, j int) bool {
+		iMatch, jMatch := reservations[i].Reference == reference, reservations[j].Reference == reference
+		if iMatch != jMatch {
+			return iMatch
+		}
+		return reservations[i].CreatedAt.Before(reservations[j].CreatedAt)
+	})
+
+	for i := range reservations {
+		if quantity == 0 {
+			break
+		}
+		reservation := &reservations[i]
+		if reservation.Quantity > quantity {
+			reservation.Quantity -= quantity
+			quantity = 0
+		} else {
+			quantity -= reservation.Quantity
+			reservation.Status = ReservationStatusReleased
+			reservation.ReleasedAt = &now
+		}
+		if err := m.storage.UpdateReservation(ctx, reservation); err != nil {
+			if errors.Is(err, ErrReservationNotActive) {
+				// 同時に解除・期限切れになった予約はリトライで除外する
+				return ErrVersionMismatch
+			}
+			return NewStorageError("update_reservation", "予約の更新に失敗しました", err)
+		}
+	}
+	return nil
+}
+
+// withReservationTx runs fn in a transaction, retrying on version conflicts
+// トランザクション内でfnを実行し、バージョン競合の場合はリトライ
+//
+// 在庫の予約数量と予約の記録を同時に更新するため、ロック方式に関わらずトランザクションを使用します。
+func (m *Manager) withReservationTx(ctx context.Context, fn func(lm *Manager) error) error {
+	return m.retryOnConflict(ctx, func() error {
+		return m.storage.WithinTx(ctx, func(txStorage Storage) error {
+			return fn(m.withStorage(txStorage, m.publisher))
+		})
+	})
+}
@@ -0,0 +1,451 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var _ ReturnManager = (*Manager)(nil)
+
+// CreateReturn validates and creates an authorized return (RMA)
+// 返品（RMA）を検証して返品受付済み（authorized）として作成
+//
+// LocationID（返品を受け入れるロケーション）・Reference（任意、RMA番号）・SalesOrderID（任意）・Customer（任意）・
+// Reason（任意）・Note（任意）・Lines を指定します。SalesOrderID を指定した場合、受注は出荷確定済みである必要があり、
+// 各行の数量は受注の同じ商品の出荷数量以下とします。Customer を省略すると受注の顧客を設定します。
+// ID・状態・バージョン・受付日時・受付者は設定され、Reference を省略した場合は返品IDを設定します。
+func (m *Manager) CreateReturn(ctx context.Context, rma *ReturnAuthorization) (err error) {
+	ctx, finish := m.startOperation(ctx, "create_return", attrLocationID.String(rma.LocationID), attrReference.String(rma.Reference))
+	defer finish(&err)
+
+	if err := ValidateReturn(rma); err != nil {
+		return err
+	}
+	if _, err := m.storage.GetLocation(ctx, rma.LocationID); err != nil {
+		if errors.Is(err, ErrLocationNotFound) {
+			return ErrLocationNotFound
+		}
+		return NewStorageError("get_location", "ロケーション取得に失敗しました", err)
+	}
+	for _, line := range rma.Lines {
+		if _, err := m.storage.GetItem(ctx, line.ItemID); err != nil {
+			if errors.Is(err, ErrItemNotFound) {
+				return ErrItemNotFound
+			}
+			return NewStorageError("get_item", "商品取得に失敗しました", err)
+		}
+	}
+	if rma.SalesOrderID != "" {
+		order, err := m.GetSalesOrder(ctx, rma.SalesOrderID)
+		if err != nil {
+			return err
+		}
+		if order.Status != SalesOrderStatusShipped {
+			return NewValidationError("sales_order_id", "出荷確定していない受注は返品できません", order.ID)
+		}
+		shipped := make(map[string]int64, len(order.Lines))
+		for _, line := range order.Lines {
+			shipped[line.ItemID] = line.ShippedQuantity
+		}
+		for _, line := range rma.Lines {
+			quantity, ok := shipped[line.ItemID]
+			if !ok {
+				return NewValidationError("item_id", "受注にない商品が指定されています", line.ItemID)
+			}
+			if line.Quantity > quantity {
+				return NewValidationError("quantity", "受注で出荷した数量を超えて返品できません", line.ItemID)
+			}
+		}
+		if rma.Customer == "" {
+			rma.Customer = order.Customer
+		}
+	}
+
+	rma.ID = NewReturnID()
+	rma.Status = ReturnStatusAuthorized
+	rma.Version = 1
+	rma.CreatedAt = time.Now()
+	rma.CreatedBy = m.getUserFromContext(ctx)
+	rma.ClosedAt, rma.ClosedBy = nil, ""
+	if rma.Reference == "" {
+		rma.Reference = rma.ID
+	}
+	for i := range rma.Lines {
+		line := &rma.Lines[i]
+		line.ReturnID = rma.ID
+		line.ReceivedQuantity, line.RestockedQuantity, line.RefurbishedQuantity, line.ScrappedQuantity = 0, 0, 0, 0
+	}
+
+	if err := m.storage.CreateReturn(ctx, rma); err != nil {
+		return NewStorageError("create_return", "返品の作成に失敗しました", err)
+	}
+
+	m.log(ctx).Info("返品作成完了",
+		zap.String("return_id", rma.ID),
+		zap.String("reference", rma.Reference),
+		zap.String("sales_order_id", rma.SalesOrderID),
+		zap.String("location_id", rma.LocationID),
+		zap.Int("lines", len(rma.Lines)),
+	)
+	return nil
+}
+
+// GetReturn retrieves a return with its lines
+// 返品を行とともに取得
+func (m *Manager) GetReturn(ctx context.Context, returnID string) (*ReturnAuthorization, error) {
+	if returnID == "" {
+		return nil, NewValidationError("return_id", "返品IDが指定されていません", "")
+	}
+
+	rma, err := m.storage.GetReturn(ctx, returnID)
+	if err != nil {
+		if errors.Is(err, ErrReturnNotFound) {
+			return nil, ErrReturnNotFound
+		}
+		return nil, NewStorageError("get_return", "返品の取得に失敗しました", err)
+	}
+	return rma, nil
+}
+
+// ListReturns lists returns matching a filter, newest first, without their lines
+// 条件に一致する返品を受付日時の新しい順に取得（行は含まない）
+func (m *Manager) ListReturns(ctx context.Context, filter ReturnFilter) ([]ReturnAuthorization, error) {
+	if err := validateOffsetLimit(filter.Offset, filter.Limit); err != nil {
+		return nil, err
+	}
+	switch filter.Status {
+	case "", ReturnStatusAuthorized, ReturnStatusReceived, ReturnStatusCompleted, ReturnStatusCancelled:
+	default:
+		return nil, NewValidationError("status", "返品の状態が正しくありません", string(filter.Status))
+	}
+
+	returns, err := m.storage.ListReturns(ctx, filter)
+	if err != nil {
+		return nil, NewStorageError("list_returns", "返品一覧の取得に失敗しました", err)
+	}
+	return returns, nil
+}
+
+// ReceiveReturn records returned units that arrived, holding them for inspection
+// 到着した返品の数量を検品待ちとして記録
+//
+// 到着した数量は在庫に含めず、DispositionReturn で処分するまで行の検品待ちの数量（Pending）になります。
+// 到着数量の合計が返品許可数量を超える場合は ErrReturnQuantityExceeded、返品受付済み以外の返品は
+// ErrReturnStatus を返します。全ての行が到着すると返品は received になります。
+func (m *Manager) ReceiveReturn(ctx context.Context, returnID string, lines []ReturnReceiptLine) (_ *ReturnAuthorization, err error) {
+	ctx, finish := m.startOperation(ctx, "receive_return", attrReturnID.String(returnID))
+	defer finish(&err)
+
+	if returnID == "" {
+		return nil, NewValidationError("return_id", "返品IDが指定されていません", "")
+	}
+	if err := ValidateReturnReceiptLines(lines); err != nil {
+		return nil, err
+	}
+
+	var received *ReturnAuthorization
+	err = m.retryOnConflict(ctx, func() error {
+		rma, err := m.GetReturn(ctx, returnID)
+		if err != nil {
+			return err
+		}
+		if rma.Status != ReturnStatusAuthorized {
+			return ErrReturnStatus
+		}
+
+		index := make(map[string]int, len(rma.Lines))
+		for i, line := range rma.Lines {
+			index[line.ItemID] = i
+		}
+		for _, receipt := range lines {
+			i, ok := index[receipt.ItemID]
+			if !ok {
+				return NewValidationError("item_id", "返品にない商品が指定されています", receipt.ItemID)
+			}
+			line := &rma.Lines[i]
+			if line.ReceivedQuantity+receipt.Quantity > line.Quantity {
+				return ErrReturnQuantityExceeded
+			}
+			line.ReceivedQuantity += receipt.Quantity
+		}
+
+		complete := true
+		for _, line := range rma.Lines {
+			if line.ReceivedQuantity < line.Quantity {
+				complete = false
+			}
+		}
+		if complete {
+			rma.Status = ReturnStatusReceived
+		}
+		if err := m.updateReturn(ctx, rma); err != nil {
+			return err
+		}
+		received = rma
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var quantity int64
+	for _, line := range lines {
+		quantity += line.Quantity
+	}
+	m.log(ctx).Info("返品の到着",
+		zap.String("return_id", received.ID),
+		zap.String("reference", received.Reference),
+		zap.String("status", string(received.Status)),
+		zap.Int64("quantity", quantity),
+	)
+	m.publishEvent(ctx, EventTypeReturnReceived, "", received.LocationID, *received)
+	return received, nil
+}
+
+// DispositionReturn restocks, refurbishes or scraps returned units that are pending inspection
+// 検品待ちの返品を再入庫・再整備・廃棄に処分
+//
+// restock は返品の商品を返品先のロケーションに、refurbish は TargetItemID（省略時は返品の商品）を
+// LocationID（省略時は返品先のロケーション）に入庫（inbound、参照番号はRMA番号、metadata.return_id に返品ID、
+// metadata.return_disposition に処分方法）します。scrap は在庫を変更せず理由コードを記録します。
+// 処分数量が行の検品待ちの数量を超える場合は ErrReturnQuantityExceeded、完了・取消済みの返品は
+// ErrReturnStatus を返します。全ての行が到着して処分済みになると返品は completed になります。
+func (m *Manager) DispositionReturn(ctx context.Context, returnID string, disposition *ReturnDisposition) (_ *ReturnDisposition, err error) {
+	ctx, finish := m.startOperation(ctx, "disposition_return", attrReturnID.String(returnID))
+	defer finish(&err)
+
+	if returnID == "" {
+		return nil, NewValidationError("return_id", "返品IDが指定されていません", "")
+	}
+	if err := ValidateReturnDisposition(disposition); err != nil {
+		return nil, err
+	}
+	rma, err := m.GetReturn(ctx, returnID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 入庫する商品・ロケーションの決定
+	if disposition.Type != ReturnDispositionScrap {
+		if disposition.TargetItemID == "" {
+			disposition.TargetItemID = disposition.ItemID
+		}
+		if disposition.LocationID == "" {
+			disposition.LocationID = rma.LocationID
+		}
+	}
+	var (
+		item     *Item
+		location *Location
+	)
+	if disposition.Type != ReturnDispositionScrap {
+		if item, location, err = m.validateItemAndLocation(ctx, disposition.TargetItemID, disposition.LocationID); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		events *bufferedPublisher
+		stock  *Stock
+	)
+	err = m.retryOnConflict(ctx, func() error {
+		events = &bufferedPublisher{}
+		stock = nil
+		return m.storage.WithinTx(ctx, func(txStorage Storage) error {
+			txManager := m.withStorage(txStorage, events)
+
+			rma, err := txManager.GetReturn(ctx, returnID)
+			if err != nil {
+				return err
+			}
+			if rma.Status != ReturnStatusAuthorized && rma.Status != ReturnStatusReceived {
+				return ErrReturnStatus
+			}
+			var line *ReturnLine
+			for i := range rma.Lines {
+				if rma.Lines[i].ItemID == disposition.ItemID {
+					line = &rma.Lines[i]
+				}
+			}
+			if line == nil {
+				return NewValidationError("item_id", "返品にない商品が指定されています", disposition.ItemID)
+			}
+			if disposition.Quantity > line.Pending() {
+				return ErrReturnQuantityExceeded
+			}
+
+			now := time.Now()
+			user := m.getUserFromContext(ctx)
+			disposition.ID = NewReturnDispositionID()
+			disposition.ReturnID = rma.ID
+			disposition.TransactionID = ""
+			disposition.CreatedAt = now
+			disposition.CreatedBy = user
+
+			if disposition.Type != ReturnDispositionScrap {
+				if m.config.LockingStrategy == LockingStrategyPessimistic {
+					if _, err := txStorage.GetStockForUpdate(ctx, disposition.TargetItemID, disposition.LocationID); err != nil && !errors.Is(err, ErrStockNotFound) {
+						return NewStorageError("get_stock", "在庫取得に失敗しました", err)
+					}
+				}
+				if err := txManager.enforceCapacity(ctx, location, disposition.Quantity); err != nil {
+					return err
+				}
+
+				var oldQuantity int64
+				oldQuantity, stock, err = txManager.increaseStock(ctx, disposition.TargetItemID, disposition.LocationID, disposition.Quantity)
+				if err != nil {
+					return err
+				}
+
+				locationID := disposition.LocationID
+				tx := &Transaction{
+					ID:         NewTransactionID(),
+					Type:       TransactionTypeInbound,
+					ItemID:     disposition.TargetItemID,
+					ToLocation: &locationID,
+					Quantity:   disposition.Quantity,
+					Reference:  rma.Reference,
+					Metadata: transactionMetadata(ctx, map[string]string{
+						MetadataReturnID:          rma.ID,
+						MetadataReturnDisposition: string(disposition.Type),
+					}),
+					CreatedAt: now,
+					CreatedBy: user,
+				}
+				if err := txStorage.CreateTransaction(ctx, tx); err != nil {
+					return NewStorageError("create_transaction", "トランザクション記録に失敗しました", err)
+				}
+				disposition.TransactionID = tx.ID
+				event := txManager.newStockChangedEvent(ctx, stock, oldQuantity, item.UnitCost, "return_"+string(disposition.Type), rma.Reference, tx.ID)
+				if err := events.PublishStockChanged(ctx, event); err != nil {
+					m.log(ctx).Error("イベント発行に失敗しました", zap.Error(err))
+				}
+			}
+
+			switch disposition.Type {
+			case ReturnDispositionRestock:
+				line.RestockedQuantity += disposition.Quantity
+			case ReturnDispositionRefurbish:
+				line.RefurbishedQuantity += disposition.Quantity
+			case ReturnDispositionScrap:
+				line.ScrappedQuantity += disposition.Quantity
+			}
+			complete := true
+			for _, l := range rma.Lines {
+				if l.ReceivedQuantity < l.Quantity || l.Pending() > 0 {
+					complete = false
+				}
+			}
+			if complete {
+				rma.Status = ReturnStatusCompleted
+				rma.ClosedAt = &now
+				rma.ClosedBy = user
+			}
+			if err := txManager.updateReturn(ctx, rma); err != nil {
+				return err
+			}
+			if err := txStorage.CreateReturnDisposition(ctx, disposition); err != nil {
+				return NewStorageError("create_return_disposition", "返品の処分の記録に失敗しました", err)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if stock != nil {
+		if m.publisher != nil {
+			events.flush(ctx, m.publisher, m.logger)
+		}
+		m.checkOverStock(ctx, location, stock)
+		m.evaluateAlertRules(ctx, stock)
+		m.allocateArrivedStock(ctx, stock.ItemID, stock.LocationID)
+	}
+
+	m.log(ctx).Info("返品の処分完了",
+		zap.String("return_id", disposition.ReturnID),
+		zap.String("item_id", disposition.ItemID),
+		zap.String("disposition", string(disposition.Type)),
+		zap.Int64("quantity", disposition.Quantity),
+		zap.String("transaction_id", disposition.TransactionID),
+	)
+	m.publishEvent(ctx, EventTypeReturnDispositioned, disposition.ItemID, disposition.LocationID, *disposition)
+	return disposition, nil
+}
+
+// CancelReturn cancels a return that has no units pending inspection
+// 検品待ちの数量がない返品を取消（未到着の数量は受け付けない）
+//
+// 完了・取消済みの返品、または到着して処分していない数量がある返品は ErrReturnStatus を返します。
+func (m *Manager) CancelReturn(ctx context.Context, returnID string) (_ *ReturnAuthorization, err error) {
+	ctx, finish := m.startOperation(ctx, "cancel_return", attrReturnID.String(returnID))
+	defer finish(&err)
+
+	if returnID == "" {
+		return nil, NewValidationError("return_id", "返品IDが指定されていません", "")
+	}
+
+	var cancelled *ReturnAuthorization
+	err = m.retryOnConflict(ctx, func() error {
+		rma, err := m.GetReturn(ctx, returnID)
+		if err != nil {
+			return err
+		}
+		if rma.Status != ReturnStatusAuthorized && rma.Status != ReturnStatusReceived {
+			return ErrReturnStatus
+		}
+		for _, line := range rma.Lines {
+			if line.Pending() > 0 {
+				return ErrReturnStatus
+			}
+		}
+
+		now := time.Now()
+		rma.Status = ReturnStatusCancelled
+		rma.ClosedAt = &now
+		rma.ClosedBy = m.getUserFromContext(ctx)
+		if err := m.updateReturn(ctx, rma); err != nil {
+			return err
+		}
+		cancelled = rma
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.log(ctx).Info("返品取消完了", zap.String("return_id", cancelled.ID))
+	return cancelled, nil
+}
+
+// ListReturnDispositions lists the dispositions of a return, oldest first
+// 返品の処分を処分日時の古い順に取得
+func (m *Manager) ListReturnDispositions(ctx context.Context, returnID string) ([]ReturnDisposition, error) {
+	if _, err := m.GetReturn(ctx, returnID); err != nil {
+		return nil, err
+	}
+
+	dispositions, err := m.storage.ListReturnDispositions(ctx, returnID)
+	if err != nil {
+		return nil, NewStorageError("list_return_dispositions", "返品の処分一覧の取得に失敗しました", err)
+	}
+	return dispositions, nil
+}
+
+// updateReturn increments the version of a return and saves it
+// 返品のバージョンを進めて保存（他の操作と競合した場合は ErrVersionMismatch でリトライ）
+func (m *Manager) updateReturn(ctx context.Context, rma *ReturnAuthorization) error {
+	rma.Version++
+	if err := m.storage.UpdateReturn(ctx, rma); err != nil {
+		if errors.Is(err, ErrReturnNotFound) || errors.Is(err, ErrVersionMismatch) {
+			return err
+		}
+		return NewStorageError("update_return", "返品の更新に失敗しました", err)
+	}
+	return nil
+}
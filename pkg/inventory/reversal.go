@@ -0,0 +1,242 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+)
+
+var _ TransactionReverser = (*Manager)(nil)
+
+// maxReversalReasonLength is the maximum length of a reversal reason in characters
+// 取消の理由の最大文字数
+const maxReversalReasonLength = 2000
+
+// reversalLeg is a single stock change of a reversal
+// 取消による1件の在庫変動
+type reversalLeg struct {
+	locationID string
+	delta      int64
+}
+
+// ReverseTransaction records a compensating transaction that undoes a stock movement
+// 在庫移動を打ち消すトランザクションを記録して元のトランザクションを取り消す
+//
+// 入庫（inbound）は同じロケーションからの出庫、出庫（outbound）は同じロケーションへの入庫、
+// 移動（transfer）は移動元と移動先を入れ替えた移動、調整（adjust）・棚卸（stocktake）は
+// 逆符号の数量の同じ種別のトランザクションで取り消します。取消のトランザクションには
+// MetadataReversalOf に元のトランザクションID、MetadataReversalReason に理由を記録します。
+// 予約・予約解除・取消自体のトランザクションは ErrTransactionNotReversible、
+// 既に取り消されたトランザクションは ErrTransactionAlreadyReversed を返します。
+// 出庫済みの在庫を戻す取消は元の予約を復元しません。
+func (m *Manager) ReverseTransaction(ctx context.Context, txID, reason string) (_ *Transaction, err error) {
+	ctx, finish := m.startOperation(ctx, "reverse_transaction", attrTransactionID.String(txID))
+	defer finish(&err)
+
+	if err := validateReversal(txID, reason); err != nil {
+		return nil, err
+	}
+	original, err := m.storage.GetTransactionByID(ctx, txID)
+	if err != nil {
+		if errors.Is(err, ErrTransactionNotFound) {
+			return nil, ErrTransactionNotFound
+		}
+		return nil, NewStorageError("get_transaction", "トランザクションの取得に失敗しました", err)
+	}
+	reversal, legs, err := newReversal(original)
+	if err != nil {
+		return nil, err
+	}
+	item, err := m.storage.GetItem(ctx, original.ItemID)
+	if err != nil {
+		if errors.Is(err, ErrItemNotFound) {
+			return nil, ErrItemNotFound
+		}
+		return nil, NewStorageError("get_item", "商品取得に失敗しました", err)
+	}
+
+	var (
+		events *bufferedPublisher
+		stocks []*Stock
+	)
+	err = m.retryOnConflict(ctx, func() error {
+		events = &bufferedPublisher{}
+		stocks = nil
+		return m.storage.WithinTx(ctx, func(txStorage Storage) error {
+			txManager := m.withStorage(txStorage, events)
+
+			// 悲観的ロックの場合、他の移動とのデッドロックを避けるためロケーションID順に行ロックを取得
+			if m.config.LockingStrategy == LockingStrategyPessimistic {
+				locationIDs := make([]string, 0, len(legs))
+				for _, leg := range legs {
+					locationIDs = append(locationIDs, leg.locationID)
+				}
+				sort.Strings(locationIDs)
+				for _, locationID := range locationIDs {
+					if _, err := txStorage.GetStockForUpdate(ctx, original.ItemID, locationID); err != nil && !errors.Is(err, ErrStockNotFound) {
+						return NewStorageError("get_stock", "在庫取得に失敗しました", err)
+					}
+				}
+			}
+
+			// 二重取消の確認（同時の取消は在庫のバージョン・行ロックで直列化される）
+			existing, err := txStorage.SearchTransactionsByMetadata(ctx, MetadataReversalOf, original.ID, 1)
+			if err != nil {
+				return NewStorageError("search_transactions", "トランザクションの検索に失敗しました", err)
+			}
+			if len(existing) > 0 {
+				return ErrTransactionAlreadyReversed
+			}
+
+			reversal.ID = NewTransactionID()
+			reversal.Metadata = transactionMetadata(ctx, map[string]string{
+				MetadataReversalOf:     original.ID,
+				MetadataReversalReason: reason,
+			})
+			reversal.CreatedAt = time.Now()
+			reversal.CreatedBy = m.getUserFromContext(ctx)
+
+			olds := make([]int64, 0, len(legs))
+			for _, leg := range legs {
+				var (
+					old   int64
+					stock *Stock
+					err   error
+				)
+				if leg.delta > 0 {
+					old, stock, err = txManager.increaseStock(ctx, original.ItemID, leg.locationID, leg.delta)
+				} else {
+					old, stock, err = txManager.decreaseStock(ctx, original.ItemID, leg.locationID, -leg.delta)
+				}
+				if err != nil {
+					return err
+				}
+				olds = append(olds, old)
+				stocks = append(stocks, stock)
+			}
+
+			if err := txStorage.CreateTransaction(ctx, reversal); err != nil {
+				return NewStorageError("create_transaction", "取消トランザクション記録に失敗しました", err)
+			}
+
+			for i, stock := range stocks {
+				event := txManager.newStockChangedEvent(ctx, stock, olds[i], item.UnitCost, "reversal", reversal.Reference, reversal.ID)
+				if err := events.PublishStockChanged(ctx, event); err != nil {
+					m.log(ctx).Error("イベント発行に失敗しました", zap.Error(err))
+				}
+			}
+			if reversal.Type == TransactionTypeTransfer {
+				event := ItemTransferredEvent{
+					ItemID:         reversal.ItemID,
+					FromLocationID: *reversal.FromLocation,
+					ToLocationID:   *reversal.ToLocation,
+					Quantity:       reversal.Quantity,
+					Reference:      reversal.Reference,
+					TransactionID:  reversal.ID,
+					Timestamp:      time.Now(),
+					UserID:         m.getUserFromContext(ctx),
+				}
+				if err := events.PublishItemTransferred(ctx, event); err != nil {
+					m.log(ctx).Error("移動イベント発行に失敗しました", zap.Error(err))
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if m.publisher != nil {
+		events.flush(ctx, m.publisher, m.logger)
+	}
+	for _, stock := range stocks {
+		m.checkLowStock(ctx, stock.ItemID, stock.LocationID, stock.Quantity)
+		m.evaluateAlertRules(ctx, stock)
+	}
+
+	m.log(ctx).Info("トランザクション取消完了",
+		zap.String("transaction_id", reversal.ID),
+		zap.String("reversal_of", original.ID),
+		zap.String("type", string(reversal.Type)),
+		zap.String("item_id", reversal.ItemID),
+		zap.Int64("quantity", reversal.Quantity),
+	)
+	return reversal, nil
+}
+
+// newReversal builds the compensating transaction of a transaction and the stock changes it applies
+// トランザクションを打ち消すトランザクションと、その在庫変動を作成
+func newReversal(original *Transaction) (*Transaction, []reversalLeg, error) {
+	if original.Metadata[MetadataReversalOf] != "" || original.Quantity == 0 {
+		return nil, nil, ErrTransactionNotReversible
+	}
+
+	reversal := &Transaction{
+		ItemID:    original.ItemID,
+		Quantity:  original.Quantity,
+		UnitCost:  original.UnitCost,
+		Reference: original.Reference,
+		LotNumber: original.LotNumber,
+	}
+	switch original.Type {
+	case TransactionTypeInbound:
+		if original.ToLocation == nil {
+			return nil, nil, ErrTransactionNotReversible
+		}
+		reversal.Type = TransactionTypeOutbound
+		location := *original.ToLocation
+		reversal.FromLocation = &location
+		reversal.UnitCost = nil
+		return reversal, []reversalLeg{{*original.ToLocation, -original.Quantity}}, nil
+	case TransactionTypeOutbound:
+		if original.FromLocation == nil {
+			return nil, nil, ErrTransactionNotReversible
+		}
+		reversal.Type = TransactionTypeInbound
+		location := *original.FromLocation
+		reversal.ToLocation = &location
+		return reversal, []reversalLeg{{*original.FromLocation, original.Quantity}}, nil
+	case TransactionTypeTransfer:
+		if original.FromLocation == nil || original.ToLocation == nil {
+			return nil, nil, ErrTransactionNotReversible
+		}
+		reversal.Type = TransactionTypeTransfer
+		from, to := *original.ToLocation, *original.FromLocation
+		reversal.FromLocation, reversal.ToLocation = &from, &to
+		return reversal, []reversalLeg{
+			{*original.ToLocation, -original.Quantity},
+			{*original.FromLocation, original.Quantity},
+		}, nil
+	case TransactionTypeAdjust, TransactionTypeStocktake:
+		if original.ToLocation == nil {
+			return nil, nil, ErrTransactionNotReversible
+		}
+		reversal.Type = original.Type
+		location := *original.ToLocation
+		reversal.ToLocation = &location
+		reversal.Quantity = -original.Quantity
+		return reversal, []reversalLeg{{*original.ToLocation, -original.Quantity}}, nil
+	default:
+		return nil, nil, ErrTransactionNotReversible
+	}
+}
+
+// validateReversal validates the transaction ID and reason of a reversal
+// 取消のトランザクションIDと理由を検証
+func validateReversal(txID, reason string) error {
+	if txID == "" {
+		return NewValidationError("transaction_id", "トランザクションIDが指定されていません", "")
+	}
+	if reason == "" {
+		return NewValidationError("reason", "取消の理由が指定されていません", "")
+	}
+	if utf8.RuneCountInString(reason) > maxReversalReasonLength {
+		return NewValidationError("reason", "取消の理由が長すぎます", "")
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/auth"
+)
+
+// anonymousUserID is recorded as the operator when authentication is disabled
+// 認証を無効にしている場合に操作者として記録するユーザーID
+const anonymousUserID = "grpc_api"
+
+// AuthConfig configures the authentication of gRPC callers
+// gRPCの呼び出し元の認証設定
+type AuthConfig struct {
+	Authenticator    auth.Authenticator // 呼び出し元の認証（nilの場合は認証せず anonymousUserID として記録）
+	AllowUnscopedJWT bool               // inventory:* のスコープを含まないJWTに全ての操作を許可する
+}
+
+// adminMethods are the RPCs requiring the admin scope
+// inventory:admin スコープを必要とするRPC
+var adminMethods = map[string]bool{
+	"InventoryService/ArchiveTransactions": true,
+}
+
+// requiredScope returns the scope required to call an RPC of this package's services
+// このパッケージのサービスのRPCの呼び出しに必要なスコープを返す
+//
+// 照会（Get・List・Search で始まるRPC）は inventory:read、adminMethods は inventory:admin、
+// それ以外の更新系のRPCは inventory:write を必要とします。
+func requiredScope(fullMethod string) string {
+	name := fullMethod[strings.LastIndex(fullMethod, ".")+1:]
+	_, method, _ := strings.Cut(name, "/")
+	switch {
+	case adminMethods[name]:
+		return auth.ScopeAdmin
+	case strings.HasPrefix(method, "Get"), strings.HasPrefix(method, "List"), strings.HasPrefix(method, "Search"):
+		return auth.ScopeRead
+	default:
+		return auth.ScopeWrite
+	}
+}
+
+// authenticates reports whether calls of an RPC are authenticated
+// RPCの呼び出しを認証するかを返す
+//
+// このパッケージのサービスのみを認証し、ヘルスチェックとサーバーリフレクションは認証しません。
+func authenticates(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+string(schema.Package())+".")
+}
+
+// authenticate authenticates the caller of an RPC and returns a context carrying the user
+// RPCの呼び出し元を認証し、ユーザーを保持するコンテキストを返す
+//
+// メタデータの authorization（Bearer トークン）・x-api-key をREST APIと同じ認証で検証します。
+// 認証に失敗した場合は Unauthenticated、スコープが不足している場合は PermissionDenied を返します。
+// 認証を無効にしている場合は全ての呼び出しを anonymousUserID の操作として扱います。
+func authenticate(ctx context.Context, cfg AuthConfig, fullMethod string, logger *zap.Logger) (context.Context, error) {
+	if !authenticates(fullMethod) {
+		return ctx, nil
+	}
+	if cfg.Authenticator == nil {
+		return auth.WithUser(ctx, &auth.User{ID: anonymousUserID}), nil
+	}
+
+	user, err := cfg.Authenticator.Authenticate(credentialsRequest(ctx))
+	if err != nil {
+		if errors.Is(err, auth.ErrNoCredentials) {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		logger.Warn("認証に失敗しました", zap.String("method", fullMethod), zap.Error(err))
+		return nil, status.Error(codes.Unauthenticated, auth.ErrInvalidToken.Error())
+	}
+	if scope := requiredScope(fullMethod); !user.Permits(scope, cfg.AllowUnscopedJWT) {
+		logger.Warn("スコープが不足しています",
+			zap.String("auth_method", user.Method),
+			zap.String("user_id", user.ID),
+			zap.String("method", fullMethod),
+			zap.String("required_scope", scope),
+		)
+		return nil, status.Error(codes.PermissionDenied, fmt.Sprintf("この操作には %s スコープが必要です", scope))
+	}
+	return auth.WithUser(ctx, user), nil
+}
+
+// credentialsRequest returns an HTTP request carrying the credentials of the incoming metadata
+// 受信したメタデータの認証情報を持つHTTPリクエストを返す（auth.Authenticator で検証するため）
+func credentialsRequest(ctx context.Context) *http.Request {
+	header := make(http.Header)
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, key := range []string{"Authorization", auth.APIKeyHeader} {
+			for _, value := range md.Get(key) {
+				header.Add(key, value)
+			}
+		}
+	}
+	return (&http.Request{Method: http.MethodPost, Header: header}).WithContext(ctx)
+}
+
+// UnaryAuthInterceptor authenticates the callers of unary RPCs
+// 単項RPCの呼び出し元を認証するインターセプター
+func UnaryAuthInterceptor(cfg AuthConfig, logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, cfg, info.FullMethod, logger)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthInterceptor authenticates the callers of streaming RPCs
+// ストリーミングRPCの呼び出し元を認証するインターセプター
+func StreamAuthInterceptor(cfg AuthConfig, logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(stream.Context(), cfg, info.FullMethod, logger)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// authenticatedStream is a server stream whose context carries the authenticated user
+// 認証済みのユーザーを保持するコンテキストを返すサーバーストリーム
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
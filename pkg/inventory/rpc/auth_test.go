@@ -0,0 +1,137 @@
+package rpc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/auth"
+)
+
+// TestRequiredScope はRPCごとに必要なスコープのテスト
+func TestRequiredScope(t *testing.T) {
+	tests := []struct {
+		method   string
+		expected string
+	}{
+		{inventoryService + "GetStock", auth.ScopeRead},
+		{inventoryService + "SearchHistoryByMetadata", auth.ScopeRead},
+		{itemService + "ListItems", auth.ScopeRead},
+		{inventoryService + "Add", auth.ScopeWrite},
+		{inventoryService + "ResolveAlert", auth.ScopeWrite},
+		{lotService + "NotifyExpiringLots", auth.ScopeWrite},
+		{inventoryService + "ArchiveTransactions", auth.ScopeAdmin},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			assert.Equal(t, tt.expected, requiredScope(tt.method))
+		})
+	}
+
+	assert.True(t, authenticates(inventoryService+"Add"))
+	assert.False(t, authenticates("/grpc.health.v1.Health/Check"), "ヘルスチェックは認証しない")
+}
+
+// TestAuthInterceptors はメタデータのAPIキーによる認証とスコープの検証のテスト
+func TestAuthInterceptors(t *testing.T) {
+	keys := auth.NewAPIKeys(auth.NewMemoryAPIKeyStore())
+	issue := func(scope string) context.Context {
+		_, plaintext, err := keys.Issue(context.Background(), scope, []string{scope}, nil)
+		require.NoError(t, err)
+		return metadata.AppendToOutgoingContext(context.Background(), "x-api-key", plaintext)
+	}
+	readCtx := issue(auth.ScopeRead)
+	writeCtx := issue(auth.ScopeWrite)
+	adminCtx := issue(auth.ScopeAdmin)
+
+	client, _ := newTestClient(t, AuthConfig{Authenticator: keys})
+
+	t.Run("認証情報がない場合は単項RPC・ストリーミングRPCともに失敗する", func(t *testing.T) {
+		_, err := client.GetItem(context.Background(), "ITEM-1")
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		_, err = client.ListItems(context.Background(), 0, 10)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("登録されていないAPIキーは認証に失敗する", func(t *testing.T) {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "zai_unknown")
+		_, err := client.GetItem(ctx, "ITEM-1")
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("readスコープは照会のみ実行できる", func(t *testing.T) {
+		_, err := client.GetItem(readCtx, "ITEM-1")
+		assert.ErrorIs(t, err, inventory.ErrItemNotFound, "認証後に照会が実行される")
+		_, err = client.ListItems(readCtx, 0, 10)
+		assert.NoError(t, err)
+
+		err = client.CreateItem(readCtx, &inventory.Item{ID: "ITEM-1", Name: "テスト商品"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("writeスコープは更新できるが管理操作は実行できない", func(t *testing.T) {
+		require.NoError(t, client.CreateItem(writeCtx, &inventory.Item{ID: "ITEM-1", Name: "テスト商品"}))
+
+		_, err := client.ArchiveTransactions(writeCtx, time.Now().AddDate(-1, 0, 0))
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("adminスコープは管理操作を実行できる", func(t *testing.T) {
+		_, err := client.ArchiveTransactions(adminCtx, time.Now().AddDate(-1, 0, 0))
+		assert.NotEqual(t, codes.PermissionDenied, status.Code(err))
+		assert.NotEqual(t, codes.Unauthenticated, status.Code(err))
+	})
+}
+
+// testJWT はテスト用に共有鍵で署名した inventory:write スコープのJWTを作成する
+func testJWT(t *testing.T, secret, subject string) string {
+	t.Helper()
+
+	header, err := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(map[string]interface{}{
+		"sub":   subject,
+		"scope": auth.ScopeWrite,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	})
+	require.NoError(t, err)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// TestAuthInterceptors_RecordsUser は認証を設定した場合に未認証の在庫操作を拒否し、
+// 認証済みのユーザーを操作者として記録するテスト
+func TestAuthInterceptors_RecordsUser(t *testing.T) {
+	verifier, err := auth.NewJWTVerifier(auth.JWTConfig{HMACSecret: "test-secret"})
+	require.NoError(t, err)
+	client, _ := newTestClient(t, AuthConfig{Authenticator: verifier})
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+testJWT(t, "test-secret", "user-1"))
+
+	require.NoError(t, client.CreateItem(ctx, &inventory.Item{ID: "ITEM-1", Name: "テスト商品"}))
+	require.NoError(t, client.CreateLocation(ctx, &inventory.Location{ID: "LOC-A", Name: "ロケーションA", IsActive: true}))
+
+	err = client.Add(context.Background(), "ITEM-1", "LOC-A", 10, "PO-1")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	forged := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+testJWT(t, "other-secret", "user-1"))
+	err = client.Add(forged, "ITEM-1", "LOC-A", 10, "PO-1")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	require.NoError(t, client.Add(ctx, "ITEM-1", "LOC-A", 10, "PO-1"))
+	stock, err := client.GetStock(ctx, "ITEM-1", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), stock.Quantity)
+	assert.Equal(t, "user-1", stock.UpdatedBy)
+}
@@ -0,0 +1,418 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// Service names of inventory.proto
+// inventory.proto のサービス名
+const (
+	inventoryService = "/zaigoframework.inventory.v1.InventoryService/"
+	itemService      = "/zaigoframework.inventory.v1.ItemService/"
+	locationService  = "/zaigoframework.inventory.v1.LocationService/"
+	lotService       = "/zaigoframework.inventory.v1.LotService/"
+)
+
+// Client calls the gRPC API with the same interfaces as the inventory managers
+// 在庫マネージャーと同じインターフェースでgRPC APIを呼び出すクライアント
+//
+// Goのサービスは inventory.Manager の代わりにClientを使用することで、
+// 手書きのRESTクライアントなしに型付きで在庫を操作できます。
+// マネージャーが返すエラー（inventory.ErrItemNotFound など）は errors.Is で判定できます。
+type Client struct {
+	conn grpc.ClientConnInterface
+}
+
+var _ Manager = (*Client)(nil)
+
+// NewClient creates a client on an established connection
+// 確立済みの接続でクライアントを作成
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{conn: conn}
+}
+
+// invoke calls a unary RPC
+// 単項RPCを呼び出す
+func (c *Client) invoke(ctx context.Context, method string, req, resp interface{}) error {
+	return fromStatus(c.conn.Invoke(ctx, method, req, resp, grpc.ForceCodec(Codec())))
+}
+
+// stream calls a server streaming RPC, passing each response to fn
+// サーバーストリーミングRPCを呼び出し、各レスポンスをfnに渡す
+func (c *Client) stream(ctx context.Context, method string, req interface{}, newResp func() interface{}, fn func(interface{}) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, method, grpc.ForceCodec(Codec()))
+	if err != nil {
+		return fromStatus(err)
+	}
+	if err := stream.SendMsg(req); err != nil {
+		return fromStatus(err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		return fromStatus(err)
+	}
+	for {
+		resp := newResp()
+		if err := stream.RecvMsg(resp); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fromStatus(err)
+		}
+		if err := fn(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// collect calls a server streaming RPC and returns all responses
+// サーバーストリーミングRPCを呼び出し、すべてのレスポンスを返す
+func collect[T any](ctx context.Context, c *Client, method string, req interface{}) ([]T, error) {
+	records := make([]T, 0)
+	err := c.stream(ctx, method, req, func() interface{} { return new(T) }, func(resp interface{}) error {
+		records = append(records, *resp.(*T))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Add adds stock to a location
+// 在庫を追加
+func (c *Client) Add(ctx context.Context, itemID, locationID string, quantity int64, reference string) error {
+	req := &StockRequest{ItemID: itemID, LocationID: locationID, Quantity: quantity, Reference: reference}
+	return c.invoke(ctx, inventoryService+"Add", req, &Empty{})
+}
+
+// Remove removes stock from a location
+// 在庫を削除
+func (c *Client) Remove(ctx context.Context, itemID, locationID string, quantity int64, reference string) error {
+	req := &StockRequest{ItemID: itemID, LocationID: locationID, Quantity: quantity, Reference: reference}
+	return c.invoke(ctx, inventoryService+"Remove", req, &Empty{})
+}
+
+// Transfer moves stock between locations
+// ロケーション間で在庫を移動
+func (c *Client) Transfer(ctx context.Context, itemID, fromLocationID, toLocationID string, quantity int64, reference string) error {
+	req := &TransferRequest{ItemID: itemID, FromLocationID: fromLocationID, ToLocationID: toLocationID, Quantity: quantity, Reference: reference}
+	return c.invoke(ctx, inventoryService+"Transfer", req, &Empty{})
+}
+
+// Adjust sets the stock quantity of a location
+// 在庫数量を調整
+func (c *Client) Adjust(ctx context.Context, itemID, locationID string, newQuantity int64, reference string) error {
+	req := &AdjustRequest{ItemID: itemID, LocationID: locationID, NewQuantity: newQuantity, Reference: reference}
+	return c.invoke(ctx, inventoryService+"Adjust", req, &Empty{})
+}
+
+// GetStock gets the stock of an item at a location
+// 在庫を取得
+func (c *Client) GetStock(ctx context.Context, itemID, locationID string) (*inventory.Stock, error) {
+	stock := &inventory.Stock{}
+	if err := c.invoke(ctx, inventoryService+"GetStock", &GetStockRequest{ItemID: itemID, LocationID: locationID}, stock); err != nil {
+		return nil, err
+	}
+	return stock, nil
+}
+
+// GetTotalStock gets the total stock of an item across locations
+// 全ロケーションの総在庫を取得
+func (c *Client) GetTotalStock(ctx context.Context, itemID string) (int64, error) {
+	var total TotalStock
+	if err := c.invoke(ctx, inventoryService+"GetTotalStock", &GetTotalStockRequest{ItemID: itemID}, &total); err != nil {
+		return 0, err
+	}
+	return total.Total, nil
+}
+
+// GetStockByLocation gets all stock records of a location
+// ロケーションのすべての在庫を取得
+func (c *Client) GetStockByLocation(ctx context.Context, locationID string) ([]inventory.Stock, error) {
+	return collect[inventory.Stock](ctx, c, inventoryService+"GetStockByLocation", &GetStockByLocationRequest{LocationID: locationID})
+}
+
+// ForEachStockByLocation streams the stock records of a location to fn
+// ロケーションの在庫を1件ずつfnに渡す
+func (c *Client) ForEachStockByLocation(ctx context.Context, locationID string, fn func(stock inventory.Stock) error) error {
+	return c.stream(ctx, inventoryService+"GetStockByLocation", &GetStockByLocationRequest{LocationID: locationID},
+		func() interface{} { return &inventory.Stock{} },
+		func(resp interface{}) error { return fn(*resp.(*inventory.Stock)) },
+	)
+}
+
+// GetTransaction gets a transaction record
+// トランザクション記録を取得
+func (c *Client) GetTransaction(ctx context.Context, txID string) (*inventory.Transaction, error) {
+	tx := &inventory.Transaction{}
+	if err := c.invoke(ctx, inventoryService+"GetTransaction", &GetTransactionRequest{ID: txID}, tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// GetHistory gets the transaction history of an item
+// 商品のトランザクション履歴を取得
+func (c *Client) GetHistory(ctx context.Context, itemID string, limit int) ([]inventory.Transaction, error) {
+	return collect[inventory.Transaction](ctx, c, inventoryService+"GetHistory", &GetHistoryRequest{ItemID: itemID, Limit: limit})
+}
+
+// GetHistoryPage gets a page of the transaction history of an item
+// 商品のトランザクション履歴を1ページ取得
+func (c *Client) GetHistoryPage(ctx context.Context, itemID string, after *inventory.HistoryCursor, limit int) (*inventory.TransactionPage, error) {
+	page := &inventory.TransactionPage{}
+	req := &GetHistoryPageRequest{ItemID: itemID, Cursor: after, Limit: limit}
+	if err := c.invoke(ctx, inventoryService+"GetHistoryPage", req, page); err != nil {
+		return nil, err
+	}
+	if page.Transactions == nil {
+		page.Transactions = make([]inventory.Transaction, 0)
+	}
+	return page, nil
+}
+
+// GetHistoryByLocation gets the transaction history of a location
+// ロケーションのトランザクション履歴を取得
+func (c *Client) GetHistoryByLocation(ctx context.Context, locationID string, limit int) ([]inventory.Transaction, error) {
+	return collect[inventory.Transaction](ctx, c, inventoryService+"GetHistoryByLocation", &GetHistoryByLocationRequest{LocationID: locationID, Limit: limit})
+}
+
+// GetHistoryByDateRange gets the transaction history of an item in a date range
+// 日付範囲の商品のトランザクション履歴を取得
+func (c *Client) GetHistoryByDateRange(ctx context.Context, itemID string, from, to time.Time) ([]inventory.Transaction, error) {
+	return collect[inventory.Transaction](ctx, c, inventoryService+"GetHistoryByDateRange", &GetHistoryByDateRangeRequest{ItemID: itemID, From: from, To: to})
+}
+
+// GetHistoryByReference gets the transactions with a reference
+// 参照番号のトランザクションを取得
+func (c *Client) GetHistoryByReference(ctx context.Context, reference string) ([]inventory.Transaction, error) {
+	return collect[inventory.Transaction](ctx, c, inventoryService+"GetHistoryByReference", &GetHistoryByReferenceRequest{Reference: reference})
+}
+
+// SearchHistoryByMetadata searches transactions by a metadata key and value
+// メタデータのキーと値でトランザクションを検索
+func (c *Client) SearchHistoryByMetadata(ctx context.Context, key, value string, limit int) ([]inventory.Transaction, error) {
+	return collect[inventory.Transaction](ctx, c, inventoryService+"SearchHistoryByMetadata", &SearchHistoryByMetadataRequest{Key: key, Value: value, Limit: limit})
+}
+
+// ArchiveTransactions archives the transactions created before a time
+// 指定日時より前のトランザクションをアーカイブ
+func (c *Client) ArchiveTransactions(ctx context.Context, before time.Time) (int64, error) {
+	var resp ArchiveTransactionsResponse
+	if err := c.invoke(ctx, inventoryService+"ArchiveTransactions", &ArchiveTransactionsRequest{Before: before}, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Archived, nil
+}
+
+// ExecuteBatch executes a batch of inventory operations
+// バッチ在庫操作を実行
+func (c *Client) ExecuteBatch(ctx context.Context, operations []inventory.InventoryOperation) (*inventory.BatchOperation, error) {
+	return c.executeBatch(ctx, operations, false)
+}
+
+// ExecuteBatchAtomic executes a batch of inventory operations in a single transaction
+// 単一トランザクション内でバッチ在庫操作を実行
+func (c *Client) ExecuteBatchAtomic(ctx context.Context, operations []inventory.InventoryOperation) (*inventory.BatchOperation, error) {
+	return c.executeBatch(ctx, operations, true)
+}
+
+// executeBatch calls ExecuteBatch with the atomic flag
+// アトミック指定付きでExecuteBatchを呼び出す
+func (c *Client) executeBatch(ctx context.Context, operations []inventory.InventoryOperation, atomic bool) (*inventory.BatchOperation, error) {
+	batch := &inventory.BatchOperation{}
+	if err := c.invoke(ctx, inventoryService+"ExecuteBatch", &ExecuteBatchRequest{Operations: operations, Atomic: atomic}, batch); err != nil {
+		return nil, err
+	}
+	if batch.Errors == nil {
+		batch.Errors = make([]inventory.BatchOperationError, 0)
+	}
+	return batch, nil
+}
+
+// GetBatchStatus gets the status of a batch operation
+// バッチ操作のステータスを取得
+func (c *Client) GetBatchStatus(ctx context.Context, batchID string) (*inventory.BatchOperation, error) {
+	batch := &inventory.BatchOperation{}
+	if err := c.invoke(ctx, inventoryService+"GetBatchStatus", &GetBatchStatusRequest{BatchID: batchID}, batch); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// Reserve reserves stock
+// 在庫を予約
+func (c *Client) Reserve(ctx context.Context, itemID, locationID string, quantity int64, reference string) error {
+	req := &StockRequest{ItemID: itemID, LocationID: locationID, Quantity: quantity, Reference: reference}
+	return c.invoke(ctx, inventoryService+"Reserve", req, &Empty{})
+}
+
+// ReleaseReservation releases reserved stock
+// 予約を解除
+func (c *Client) ReleaseReservation(ctx context.Context, itemID, locationID string, quantity int64, reference string) error {
+	req := &StockRequest{ItemID: itemID, LocationID: locationID, Quantity: quantity, Reference: reference}
+	return c.invoke(ctx, inventoryService+"ReleaseReservation", req, &Empty{})
+}
+
+// GetAlerts gets the active alerts of a location
+// ロケーションのアラートを取得
+func (c *Client) GetAlerts(ctx context.Context, locationID string) ([]inventory.StockAlert, error) {
+	return collect[inventory.StockAlert](ctx, c, inventoryService+"GetAlerts", &GetAlertsRequest{LocationID: locationID})
+}
+
+// ResolveAlert resolves an alert
+// アラートを解決
+func (c *Client) ResolveAlert(ctx context.Context, alertID string) error {
+	return c.invoke(ctx, inventoryService+"ResolveAlert", &ResolveAlertRequest{AlertID: alertID}, &Empty{})
+}
+
+// CreateItem creates an item, updating it with the stored values
+// 商品を作成（作成日時などの保存された値でitemを更新する）
+func (c *Client) CreateItem(ctx context.Context, item *inventory.Item) error {
+	return c.invoke(ctx, itemService+"CreateItem", item, item)
+}
+
+// GetItem gets an item
+// 商品を取得
+func (c *Client) GetItem(ctx context.Context, itemID string) (*inventory.Item, error) {
+	item := &inventory.Item{}
+	if err := c.invoke(ctx, itemService+"GetItem", &GetItemRequest{ID: itemID}, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// UpdateItem updates an item, updating it with the stored values
+// 商品を更新（更新日時などの保存された値でitemを更新する）
+func (c *Client) UpdateItem(ctx context.Context, item *inventory.Item) error {
+	return c.invoke(ctx, itemService+"UpdateItem", item, item)
+}
+
+// DeleteItem deletes an item
+// 商品を削除
+func (c *Client) DeleteItem(ctx context.Context, itemID string) error {
+	return c.invoke(ctx, itemService+"DeleteItem", &DeleteItemRequest{ID: itemID}, &Empty{})
+}
+
+// ListItems lists items
+// 商品一覧を取得
+func (c *Client) ListItems(ctx context.Context, offset, limit int) ([]inventory.Item, error) {
+	return collect[inventory.Item](ctx, c, itemService+"ListItems", &ListItemsRequest{Offset: offset, Limit: limit})
+}
+
+// SearchItems searches items
+// 商品を検索
+func (c *Client) SearchItems(ctx context.Context, query string) ([]inventory.Item, error) {
+	return collect[inventory.Item](ctx, c, itemService+"SearchItems", &SearchItemsRequest{Query: query})
+}
+
+// CreateLocation creates a location, updating it with the stored values
+// ロケーションを作成（作成日時などの保存された値でlocationを更新する）
+func (c *Client) CreateLocation(ctx context.Context, location *inventory.Location) error {
+	return c.invoke(ctx, locationService+"CreateLocation", location, location)
+}
+
+// GetLocation gets a location
+// ロケーションを取得
+func (c *Client) GetLocation(ctx context.Context, locationID string) (*inventory.Location, error) {
+	location := &inventory.Location{}
+	if err := c.invoke(ctx, locationService+"GetLocation", &GetLocationRequest{ID: locationID}, location); err != nil {
+		return nil, err
+	}
+	return location, nil
+}
+
+// UpdateLocation updates a location, updating it with the stored values
+// ロケーションを更新（更新日時などの保存された値でlocationを更新する）
+func (c *Client) UpdateLocation(ctx context.Context, location *inventory.Location) error {
+	return c.invoke(ctx, locationService+"UpdateLocation", location, location)
+}
+
+// DeleteLocation deletes a location
+// ロケーションを削除
+func (c *Client) DeleteLocation(ctx context.Context, locationID string) error {
+	return c.invoke(ctx, locationService+"DeleteLocation", &DeleteLocationRequest{ID: locationID}, &Empty{})
+}
+
+// ListLocations lists locations
+// ロケーション一覧を取得
+func (c *Client) ListLocations(ctx context.Context, offset, limit int) ([]inventory.Location, error) {
+	return collect[inventory.Location](ctx, c, locationService+"ListLocations", &ListLocationsRequest{Offset: offset, Limit: limit})
+}
+
+// CreateLot creates a lot, updating it with the stored values
+// ロットを作成（IDなどの保存された値でlotを更新する）
+func (c *Client) CreateLot(ctx context.Context, lot *inventory.Lot) error {
+	return c.invoke(ctx, lotService+"CreateLot", lot, lot)
+}
+
+// GetLot gets a lot
+// ロットを取得
+func (c *Client) GetLot(ctx context.Context, lotID string) (*inventory.Lot, error) {
+	lot := &inventory.Lot{}
+	if err := c.invoke(ctx, lotService+"GetLot", &GetLotRequest{ID: lotID}, lot); err != nil {
+		return nil, err
+	}
+	return lot, nil
+}
+
+// UpdateLot updates a lot, updating it with the stored values
+// ロットを更新（保存された値でlotを更新する）
+func (c *Client) UpdateLot(ctx context.Context, lot *inventory.Lot) error {
+	return c.invoke(ctx, lotService+"UpdateLot", lot, lot)
+}
+
+// DeleteLot deletes a lot
+// ロットを削除
+func (c *Client) DeleteLot(ctx context.Context, lotID string) error {
+	return c.invoke(ctx, lotService+"DeleteLot", &DeleteLotRequest{ID: lotID}, &Empty{})
+}
+
+// AdjustLotQuantity adjusts the quantity of a lot by delta
+// ロットの数量を増減
+func (c *Client) AdjustLotQuantity(ctx context.Context, lotID string, delta int64, reference string) (*inventory.Lot, error) {
+	lot := &inventory.Lot{}
+	req := &AdjustLotQuantityRequest{ID: lotID, Delta: delta, Reference: reference}
+	if err := c.invoke(ctx, lotService+"AdjustLotQuantity", req, lot); err != nil {
+		return nil, err
+	}
+	return lot, nil
+}
+
+// GetLotsByItem gets the lots of an item
+// 商品のロットを取得
+func (c *Client) GetLotsByItem(ctx context.Context, itemID string) ([]inventory.Lot, error) {
+	return collect[inventory.Lot](ctx, c, lotService+"GetLotsByItem", &GetLotsByItemRequest{ItemID: itemID})
+}
+
+// GetExpiringLots gets the lots expiring within a duration
+// 指定期間内に期限切れになるロットを取得
+func (c *Client) GetExpiringLots(ctx context.Context, within time.Duration) ([]inventory.Lot, error) {
+	return collect[inventory.Lot](ctx, c, lotService+"GetExpiringLots", &GetExpiringLotsRequest{Within: within})
+}
+
+// NotifyExpiringLots publishes events for the lots expiring within a duration
+// 指定期間内に期限切れになるロットのイベントを発行
+func (c *Client) NotifyExpiringLots(ctx context.Context, within time.Duration) (int, error) {
+	var resp NotifyExpiringLotsResponse
+	if err := c.invoke(ctx, lotService+"NotifyExpiringLots", &NotifyExpiringLotsRequest{Within: within}, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Notified, nil
+}
+
+// GetExpiredLots gets the expired lots
+// 期限切れのロットを取得
+func (c *Client) GetExpiredLots(ctx context.Context) ([]inventory.Lot, error) {
+	return collect[inventory.Lot](ctx, c, lotService+"GetExpiredLots", &Empty{})
+}
@@ -0,0 +1,395 @@
+package rpc
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// messageDescriptors and goTypes index messageTypes in both directions
+// messageTypesの双方向の索引（Goの型 → 記述子、記述子の完全修飾名 → Goの型）
+var messageDescriptors, goTypes = indexMessageTypes(schema)
+
+// indexMessageTypes resolves each registered Go type to its message descriptor
+// 登録されたGoの型をメッセージ記述子に対応付ける
+func indexMessageTypes(file protoreflect.FileDescriptor) (map[reflect.Type]protoreflect.MessageDescriptor, map[protoreflect.FullName]reflect.Type) {
+	descriptors := make(map[reflect.Type]protoreflect.MessageDescriptor, len(messageTypes))
+	types := make(map[protoreflect.FullName]reflect.Type, len(messageTypes))
+	for name, value := range messageTypes {
+		md := file.Messages().ByName(protoreflect.Name(name))
+		if md == nil {
+			panic(fmt.Sprintf("inventory.proto にメッセージ %s が定義されていません", name))
+		}
+		t := reflect.TypeOf(value)
+		descriptors[t] = md
+		types[md.FullName()] = t
+	}
+	return descriptors, types
+}
+
+// Codec returns the gRPC codec that encodes the Go types of this package as protocol buffers
+// このパッケージのGoの型をProtocol Buffersとしてエンコードするgrpcのコーデックを返す
+//
+// サーバーでは grpc.ForceServerCodec、クライアントでは grpc.ForceCodec に指定します（NewServerOptions と Client が指定します）。
+func Codec() encoding.Codec {
+	return codec{}
+}
+
+// codec converts registered Go structs to and from dynamic protobuf messages
+// 登録されたGoの構造体と動的なprotobufメッセージを相互に変換する
+//
+// 構造体のフィールドはJSONタグの名前で同名のprotoフィールドに対応付けられます。
+// protoに定義されていないフィールドは送受信されません。
+type codec struct{}
+
+// Name returns the content subtype of the codec
+// コーデックのコンテンツサブタイプを返す
+func (codec) Name() string {
+	return "proto"
+}
+
+// Marshal encodes a pointer to a registered Go struct
+// 登録されたGoの構造体へのポインターをエンコード
+//
+// 生成コードのメッセージ（ヘルスチェックやサーバーリフレクションのサービス）はそのままエンコードします。
+func (codec) Marshal(v interface{}) ([]byte, error) {
+	if m, ok := v.(proto.Message); ok {
+		return proto.Marshal(m)
+	}
+	rv, md, err := messageValue(v)
+	if err != nil {
+		return nil, err
+	}
+	msg := dynamicpb.NewMessage(md)
+	if err := encodeMessage(msg, rv); err != nil {
+		return nil, fmt.Errorf("%s のエンコードに失敗しました: %w", md.Name(), err)
+	}
+	return proto.Marshal(msg)
+}
+
+// Unmarshal decodes data into a pointer to a registered Go struct
+// データを登録されたGoの構造体へのポインターにデコード
+func (codec) Unmarshal(data []byte, v interface{}) error {
+	if m, ok := v.(proto.Message); ok {
+		return proto.Unmarshal(data, m)
+	}
+	rv, md, err := messageValue(v)
+	if err != nil {
+		return err
+	}
+	msg := dynamicpb.NewMessage(md)
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return fmt.Errorf("%s のデコードに失敗しました: %w", md.Name(), err)
+	}
+	rv.Set(reflect.Zero(rv.Type()))
+	if err := decodeMessage(msg, rv); err != nil {
+		return fmt.Errorf("%s のデコードに失敗しました: %w", md.Name(), err)
+	}
+	return nil
+}
+
+// messageValue returns the struct v points to and its message descriptor
+// vが指す構造体とそのメッセージ記述子を返す
+func messageValue(v interface{}) (reflect.Value, protoreflect.MessageDescriptor, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return reflect.Value{}, nil, fmt.Errorf("メッセージは構造体へのポインターである必要があります: %T", v)
+	}
+	md, ok := messageDescriptors[rv.Type().Elem()]
+	if !ok {
+		return reflect.Value{}, nil, fmt.Errorf("inventory.proto に対応しない型です: %T", v)
+	}
+	return rv.Elem(), md, nil
+}
+
+// fieldName returns the proto field name of a struct field, or "" if it is not serialized
+// 構造体フィールドに対応するprotoフィールド名を返す（対象外の場合は空）
+func fieldName(sf reflect.StructField) string {
+	if sf.PkgPath != "" {
+		return ""
+	}
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// encodeMessage copies the fields of the struct rv into msg
+// 構造体rvのフィールドをmsgにコピー
+func encodeMessage(msg protoreflect.Message, rv reflect.Value) error {
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < rv.NumField(); i++ {
+		name := fieldName(rv.Type().Field(i))
+		if name == "" {
+			continue
+		}
+		fd := fields.ByName(protoreflect.Name(name))
+		if fd == nil {
+			continue
+		}
+		if err := encodeField(msg, fd, rv.Field(i)); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// encodeField sets field fd of msg from v, leaving nil pointers and zero times unset
+// vからmsgのフィールドを設定（nilのポインターとゼロ値の日時は未設定のままにする）
+func encodeField(msg protoreflect.Message, fd protoreflect.FieldDescriptor, v reflect.Value) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch {
+	case fd.IsMap():
+		if v.Kind() != reflect.Map {
+			return fmt.Errorf("mapではない型です: %s", v.Type())
+		}
+		m := msg.Mutable(fd).Map()
+		iter := v.MapRange()
+		for iter.Next() {
+			key, err := scalarValue(fd.MapKey(), iter.Key())
+			if err != nil {
+				return err
+			}
+			value, err := scalarValue(fd.MapValue(), iter.Value())
+			if err != nil {
+				return err
+			}
+			m.Set(key.MapKey(), value)
+		}
+	case fd.IsList():
+		if v.Kind() != reflect.Slice {
+			return fmt.Errorf("スライスではない型です: %s", v.Type())
+		}
+		list := msg.Mutable(fd).List()
+		for i := 0; i < v.Len(); i++ {
+			if fd.Kind() == protoreflect.MessageKind {
+				element := list.NewElement()
+				if err := encodeMessageValue(element.Message(), v.Index(i)); err != nil {
+					return err
+				}
+				list.Append(element)
+				continue
+			}
+			value, err := scalarValue(fd, v.Index(i))
+			if err != nil {
+				return err
+			}
+			list.Append(value)
+		}
+	case fd.Kind() == protoreflect.MessageKind:
+		if v.Type() == timeType && v.Interface().(time.Time).IsZero() {
+			return nil
+		}
+		return encodeMessageValue(msg.Mutable(fd).Message(), v)
+	default:
+		value, err := scalarValue(fd, v)
+		if err != nil {
+			return err
+		}
+		msg.Set(fd, value)
+	}
+	return nil
+}
+
+// encodeMessageValue fills a message from a struct, time.Time or time.Duration
+// 構造体・time.Time・time.Durationからメッセージを設定
+func encodeMessageValue(msg protoreflect.Message, v reflect.Value) error {
+	fields := msg.Descriptor().Fields()
+	switch msg.Descriptor().FullName() {
+	case "google.protobuf.Timestamp":
+		if v.Type() != timeType {
+			return fmt.Errorf("Timestampに対応しない型です: %s", v.Type())
+		}
+		t := v.Interface().(time.Time)
+		msg.Set(fields.ByName("seconds"), protoreflect.ValueOfInt64(t.Unix()))
+		msg.Set(fields.ByName("nanos"), protoreflect.ValueOfInt32(int32(t.Nanosecond())))
+	case "google.protobuf.Duration":
+		if v.Type() != durationType {
+			return fmt.Errorf("Durationに対応しない型です: %s", v.Type())
+		}
+		d := time.Duration(v.Int())
+		msg.Set(fields.ByName("seconds"), protoreflect.ValueOfInt64(int64(d/time.Second)))
+		msg.Set(fields.ByName("nanos"), protoreflect.ValueOfInt32(int32(d%time.Second)))
+	default:
+		if v.Kind() != reflect.Struct {
+			return fmt.Errorf("%s に対応しない型です: %s", msg.Descriptor().Name(), v.Type())
+		}
+		return encodeMessage(msg, v)
+	}
+	return nil
+}
+
+// scalarValue converts a Go scalar to the proto value of field fd
+// Goのスカラー値をフィールドのprotoの値に変換
+func scalarValue(fd protoreflect.FieldDescriptor, v reflect.Value) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		if v.Kind() == reflect.String {
+			return protoreflect.ValueOfString(v.String()), nil
+		}
+	case protoreflect.BoolKind:
+		if v.Kind() == reflect.Bool {
+			return protoreflect.ValueOfBool(v.Bool()), nil
+		}
+	case protoreflect.Int64Kind:
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return protoreflect.ValueOfInt64(v.Int()), nil
+		}
+	case protoreflect.Int32Kind:
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return protoreflect.ValueOfInt32(int32(v.Int())), nil
+		}
+	case protoreflect.DoubleKind:
+		if v.Kind() == reflect.Float64 || v.Kind() == reflect.Float32 {
+			return protoreflect.ValueOfFloat64(v.Float()), nil
+		}
+	}
+	return protoreflect.Value{}, fmt.Errorf("%s に対応しない型です: %s", fd.Kind(), v.Type())
+}
+
+// decodeMessage copies the populated fields of msg into the struct rv
+// msgの設定済みフィールドを構造体rvにコピー
+func decodeMessage(msg protoreflect.Message, rv reflect.Value) error {
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < rv.NumField(); i++ {
+		name := fieldName(rv.Type().Field(i))
+		if name == "" {
+			continue
+		}
+		fd := fields.ByName(protoreflect.Name(name))
+		if fd == nil || !msg.Has(fd) {
+			continue
+		}
+		if err := decodeField(fd, msg.Get(fd), rv.Field(i)); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// decodeField stores the value of field fd into dst, allocating pointers as needed
+// フィールドの値をdstに格納（ポインターは必要に応じて割り当てる）
+func decodeField(fd protoreflect.FieldDescriptor, value protoreflect.Value, dst reflect.Value) error {
+	if dst.Kind() == reflect.Ptr {
+		ptr := reflect.New(dst.Type().Elem())
+		if err := decodeField(fd, value, ptr.Elem()); err != nil {
+			return err
+		}
+		dst.Set(ptr)
+		return nil
+	}
+
+	switch {
+	case fd.IsMap():
+		if dst.Kind() != reflect.Map {
+			return fmt.Errorf("mapではない型です: %s", dst.Type())
+		}
+		m := reflect.MakeMapWithSize(dst.Type(), value.Map().Len())
+		var err error
+		value.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			key := reflect.New(dst.Type().Key()).Elem()
+			element := reflect.New(dst.Type().Elem()).Elem()
+			if err = decodeScalar(k.Value(), key); err != nil {
+				return false
+			}
+			if err = decodeScalar(v, element); err != nil {
+				return false
+			}
+			m.SetMapIndex(key, element)
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		dst.Set(m)
+	case fd.IsList():
+		if dst.Kind() != reflect.Slice {
+			return fmt.Errorf("スライスではない型です: %s", dst.Type())
+		}
+		list := value.List()
+		s := reflect.MakeSlice(dst.Type(), list.Len(), list.Len())
+		for i := 0; i < list.Len(); i++ {
+			var err error
+			if fd.Kind() == protoreflect.MessageKind {
+				err = decodeMessageValue(list.Get(i).Message(), s.Index(i))
+			} else {
+				err = decodeScalar(list.Get(i), s.Index(i))
+			}
+			if err != nil {
+				return err
+			}
+		}
+		dst.Set(s)
+	case fd.Kind() == protoreflect.MessageKind:
+		return decodeMessageValue(value.Message(), dst)
+	default:
+		return decodeScalar(value, dst)
+	}
+	return nil
+}
+
+// decodeMessageValue stores a message into a struct, time.Time or time.Duration
+// メッセージを構造体・time.Time・time.Durationに格納
+func decodeMessageValue(msg protoreflect.Message, dst reflect.Value) error {
+	fields := msg.Descriptor().Fields()
+	switch msg.Descriptor().FullName() {
+	case "google.protobuf.Timestamp":
+		if dst.Type() != timeType {
+			return fmt.Errorf("Timestampに対応しない型です: %s", dst.Type())
+		}
+		seconds := msg.Get(fields.ByName("seconds")).Int()
+		nanos := msg.Get(fields.ByName("nanos")).Int()
+		dst.Set(reflect.ValueOf(time.Unix(seconds, nanos).UTC()))
+	case "google.protobuf.Duration":
+		if dst.Type() != durationType {
+			return fmt.Errorf("Durationに対応しない型です: %s", dst.Type())
+		}
+		seconds := msg.Get(fields.ByName("seconds")).Int()
+		nanos := msg.Get(fields.ByName("nanos")).Int()
+		dst.SetInt(int64(time.Duration(seconds)*time.Second + time.Duration(nanos)))
+	default:
+		if dst.Kind() != reflect.Struct {
+			return fmt.Errorf("%s に対応しない型です: %s", msg.Descriptor().Name(), dst.Type())
+		}
+		return decodeMessage(msg, dst)
+	}
+	return nil
+}
+
+// decodeScalar stores a proto scalar into a Go scalar
+// protoのスカラー値をGoのスカラー値に格納
+func decodeScalar(value protoreflect.Value, dst reflect.Value) error {
+	switch dst.Kind() {
+	case reflect.String:
+		dst.SetString(value.String())
+	case reflect.Bool:
+		dst.SetBool(value.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		dst.SetInt(value.Int())
+	case reflect.Float32, reflect.Float64:
+		dst.SetFloat(value.Float())
+	default:
+		return fmt.Errorf("対応しない型です: %s", dst.Type())
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// sentinelCodes maps the inventory sentinel errors to gRPC status codes
+// 在庫パッケージのエラーとgRPCステータスコードの対応
+//
+// Clientはステータスのメッセージがこれらのエラーと一致する場合に元のエラーに戻すため、
+// 呼び出し側は errors.Is(err, inventory.ErrItemNotFound) などで判定できます。
+var sentinelCodes = []struct {
+	err  error
+	code codes.Code
+}{
+	{inventory.ErrItemNotFound, codes.NotFound},
+	{inventory.ErrLocationNotFound, codes.NotFound},
+	{inventory.ErrStockNotFound, codes.NotFound},
+	{inventory.ErrTransactionNotFound, codes.NotFound},
+	{inventory.ErrLotNotFound, codes.NotFound},
+	{inventory.ErrLotStockNotFound, codes.NotFound},
+	{inventory.ErrUnitNotFound, codes.NotFound},
+	{inventory.ErrUnitConversionNotFound, codes.NotFound},
+	{inventory.ErrBOMNotFound, codes.NotFound},
+	{inventory.ErrAssemblyNotFound, codes.NotFound},
+	{inventory.ErrSupplierNotFound, codes.NotFound},
+	{inventory.ErrSupplierItemNotFound, codes.NotFound},
+	{inventory.ErrPurchaseOrderNotFound, codes.NotFound},
+	{inventory.ErrSalesOrderNotFound, codes.NotFound},
+	{inventory.ErrReturnNotFound, codes.NotFound},
+	{inventory.ErrReservationNotFound, codes.NotFound},
+	{inventory.ErrBackorderNotFound, codes.NotFound},
+	{inventory.ErrReorderPointNotFound, codes.NotFound},
+	{inventory.ErrAlertRuleNotFound, codes.NotFound},
+	{inventory.ErrSnapshotNotFound, codes.NotFound},
+	{inventory.ErrStocktakeNotFound, codes.NotFound},
+	{inventory.ErrAdjustmentNotFound, codes.NotFound},
+	{inventory.ErrInspectionNotFound, codes.NotFound},
+	{inventory.ErrAlertNotFound, codes.NotFound},
+	{inventory.ErrBatchNotFound, codes.NotFound},
+	{inventory.ErrDuplicateItem, codes.AlreadyExists},
+	{inventory.ErrDuplicateLocation, codes.AlreadyExists},
+	{inventory.ErrDuplicateUnit, codes.AlreadyExists},
+	{inventory.ErrDuplicateSupplier, codes.AlreadyExists},
+	{inventory.ErrUnitInUse, codes.FailedPrecondition},
+	{inventory.ErrItemInUse, codes.FailedPrecondition},
+	{inventory.ErrNegativeQuantity, codes.InvalidArgument},
+	{inventory.ErrInvalidReference, codes.InvalidArgument},
+	{inventory.ErrInsufficientStock, codes.FailedPrecondition},
+	{inventory.ErrInsufficientLotStock, codes.FailedPrecondition},
+	{inventory.ErrInsufficientConsignmentStock, codes.FailedPrecondition},
+	{inventory.ErrInsufficientReservation, codes.FailedPrecondition},
+	{inventory.ErrLocationCapacityExceeded, codes.FailedPrecondition},
+	{inventory.ErrExpiredLot, codes.FailedPrecondition},
+	{inventory.ErrPreconditionFailed, codes.FailedPrecondition},
+	{inventory.ErrReservationNotActive, codes.FailedPrecondition},
+	{inventory.ErrBackorderNotPending, codes.FailedPrecondition},
+	{inventory.ErrAlertNotActive, codes.FailedPrecondition},
+	{inventory.ErrAlertAlreadyAcknowledged, codes.FailedPrecondition},
+	{inventory.ErrStocktakeStatus, codes.FailedPrecondition},
+	{inventory.ErrPurchaseOrderStatus, codes.FailedPrecondition},
+	{inventory.ErrReceiptExceedsOrder, codes.FailedPrecondition},
+	{inventory.ErrSalesOrderStatus, codes.FailedPrecondition},
+	{inventory.ErrReturnStatus, codes.FailedPrecondition},
+	{inventory.ErrReturnQuantityExceeded, codes.FailedPrecondition},
+	{inventory.ErrAdjustmentNotPending, codes.FailedPrecondition},
+	{inventory.ErrTransactionNotReversible, codes.FailedPrecondition},
+	{inventory.ErrTransactionAlreadyReversed, codes.FailedPrecondition},
+	{inventory.ErrInspectionNotQuarantined, codes.FailedPrecondition},
+	{inventory.ErrVersionMismatch, codes.Aborted},
+}
+
+// errorCode returns the gRPC status code for an error returned by the managers
+// マネージャーが返したエラーのgRPCステータスコードを返す
+func errorCode(err error) codes.Code {
+	for _, sentinel := range sentinelCodes {
+		if errors.Is(err, sentinel.err) {
+			return sentinel.code
+		}
+	}
+
+	var validationErr *inventory.ValidationError
+	var businessErr *inventory.BusinessRuleError
+	var concurrencyErr *inventory.ConcurrencyError
+	switch {
+	case errors.As(err, &validationErr):
+		return codes.InvalidArgument
+	case errors.As(err, &businessErr):
+		return codes.FailedPrecondition
+	case errors.As(err, &concurrencyErr):
+		return codes.Aborted
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	default:
+		return codes.Internal
+	}
+}
+
+// fromStatus converts a status error back to the inventory sentinel error it was created from
+// ステータスエラーを元の在庫パッケージのエラーに戻す（該当しない場合はそのまま返す）
+func fromStatus(err error) error {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() == codes.OK {
+		return err
+	}
+	for _, sentinel := range sentinelCodes {
+		if st.Code() == sentinel.code && st.Message() == sentinel.err.Error() {
+			return sentinel.err
+		}
+	}
+	return err
+}
@@ -0,0 +1,290 @@
+package rpc
+
+import (
+	"time"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// Empty is the empty request and response message
+// 空のリクエスト・レスポンス
+type Empty struct{}
+
+// StockRequest is the request of Add, Remove, Reserve and ReleaseReservation
+// 追加・削除・予約・予約解除のリクエスト
+type StockRequest struct {
+	ItemID     string `json:"item_id"`
+	LocationID string `json:"location_id"`
+	Quantity   int64  `json:"quantity"`
+	Reference  string `json:"reference"`
+}
+
+// TransferRequest is the request of Transfer
+// 移動のリクエスト
+type TransferRequest struct {
+	ItemID         string `json:"item_id"`
+	FromLocationID string `json:"from_location_id"`
+	ToLocationID   string `json:"to_location_id"`
+	Quantity       int64  `json:"quantity"`
+	Reference      string `json:"reference"`
+}
+
+// AdjustRequest is the request of Adjust
+// 調整のリクエスト
+type AdjustRequest struct {
+	ItemID      string `json:"item_id"`
+	LocationID  string `json:"location_id"`
+	NewQuantity int64  `json:"new_quantity"`
+	Reference   string `json:"reference"`
+}
+
+// GetStockRequest is the request of GetStock
+// 在庫取得のリクエスト
+type GetStockRequest struct {
+	ItemID     string `json:"item_id"`
+	LocationID string `json:"location_id"`
+}
+
+// GetTotalStockRequest is the request of GetTotalStock
+// 総在庫取得のリクエスト
+type GetTotalStockRequest struct {
+	ItemID string `json:"item_id"`
+}
+
+// TotalStock is the response of GetTotalStock
+// 総在庫取得のレスポンス
+type TotalStock struct {
+	ItemID string `json:"item_id"`
+	Total  int64  `json:"total"`
+}
+
+// GetStockByLocationRequest is the request of GetStockByLocation
+// ロケーション別在庫取得のリクエスト
+type GetStockByLocationRequest struct {
+	LocationID string `json:"location_id"`
+}
+
+// GetTransactionRequest is the request of GetTransaction
+// トランザクション取得のリクエスト
+type GetTransactionRequest struct {
+	ID string `json:"id"`
+}
+
+// GetHistoryRequest is the request of GetHistory
+// 履歴取得のリクエスト
+type GetHistoryRequest struct {
+	ItemID string `json:"item_id"`
+	Limit  int    `json:"limit"`
+}
+
+// GetHistoryPageRequest is the request of GetHistoryPage
+// 履歴ページ取得のリクエスト
+type GetHistoryPageRequest struct {
+	ItemID string                   `json:"item_id"`
+	Cursor *inventory.HistoryCursor `json:"cursor"`
+	Limit  int                      `json:"limit"`
+}
+
+// GetHistoryByLocationRequest is the request of GetHistoryByLocation
+// ロケーション別履歴取得のリクエスト
+type GetHistoryByLocationRequest struct {
+	LocationID string `json:"location_id"`
+	Limit      int    `json:"limit"`
+}
+
+// GetHistoryByDateRangeRequest is the request of GetHistoryByDateRange
+// 日付範囲の履歴取得のリクエスト
+type GetHistoryByDateRangeRequest struct {
+	ItemID string    `json:"item_id"`
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+}
+
+// GetHistoryByReferenceRequest is the request of GetHistoryByReference
+// 参照番号別履歴取得のリクエスト
+type GetHistoryByReferenceRequest struct {
+	Reference string `json:"reference"`
+}
+
+// SearchHistoryByMetadataRequest is the request of SearchHistoryByMetadata
+// メタデータによる履歴検索のリクエスト
+type SearchHistoryByMetadataRequest struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Limit int    `json:"limit"`
+}
+
+// ArchiveTransactionsRequest is the request of ArchiveTransactions
+// トランザクションアーカイブのリクエスト
+type ArchiveTransactionsRequest struct {
+	Before time.Time `json:"before"`
+}
+
+// ArchiveTransactionsResponse is the response of ArchiveTransactions
+// トランザクションアーカイブのレスポンス
+type ArchiveTransactionsResponse struct {
+	Archived int64 `json:"archived"`
+}
+
+// ExecuteBatchRequest is the request of ExecuteBatch
+// バッチ実行のリクエスト
+type ExecuteBatchRequest struct {
+	Operations []inventory.InventoryOperation `json:"operations"`
+	Atomic     bool                           `json:"atomic"` // trueの場合はExecuteBatchAtomicで実行
+}
+
+// GetBatchStatusRequest is the request of GetBatchStatus
+// バッチステータス取得のリクエスト
+type GetBatchStatusRequest struct {
+	BatchID string `json:"batch_id"`
+}
+
+// GetAlertsRequest is the request of GetAlerts
+// アラート取得のリクエスト
+type GetAlertsRequest struct {
+	LocationID string `json:"location_id"`
+}
+
+// ResolveAlertRequest is the request of ResolveAlert
+// アラート解決のリクエスト
+type ResolveAlertRequest struct {
+	AlertID string `json:"alert_id"`
+}
+
+// GetItemRequest is the request of GetItem
+// 商品取得のリクエスト
+type GetItemRequest struct {
+	ID string `json:"id"`
+}
+
+// DeleteItemRequest is the request of DeleteItem
+// 商品削除のリクエスト
+type DeleteItemRequest struct {
+	ID string `json:"id"`
+}
+
+// ListItemsRequest is the request of ListItems
+// 商品一覧のリクエスト
+type ListItemsRequest struct {
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+// SearchItemsRequest is the request of SearchItems
+// 商品検索のリクエスト
+type SearchItemsRequest struct {
+	Query string `json:"query"`
+}
+
+// GetLocationRequest is the request of GetLocation
+// ロケーション取得のリクエスト
+type GetLocationRequest struct {
+	ID string `json:"id"`
+}
+
+// DeleteLocationRequest is the request of DeleteLocation
+// ロケーション削除のリクエスト
+type DeleteLocationRequest struct {
+	ID string `json:"id"`
+}
+
+// ListLocationsRequest is the request of ListLocations
+// ロケーション一覧のリクエスト
+type ListLocationsRequest struct {
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+// GetLotRequest is the request of GetLot
+// ロット取得のリクエスト
+type GetLotRequest struct {
+	ID string `json:"id"`
+}
+
+// DeleteLotRequest is the request of DeleteLot
+// ロット削除のリクエスト
+type DeleteLotRequest struct {
+	ID string `json:"id"`
+}
+
+// AdjustLotQuantityRequest is the request of AdjustLotQuantity
+// ロット数量調整のリクエスト
+type AdjustLotQuantityRequest struct {
+	ID        string `json:"id"`
+	Delta     int64  `json:"delta"`
+	Reference string `json:"reference"`
+}
+
+// GetLotsByItemRequest is the request of GetLotsByItem
+// 商品別ロット取得のリクエスト
+type GetLotsByItemRequest struct {
+	ItemID string `json:"item_id"`
+}
+
+// GetExpiringLotsRequest is the request of GetExpiringLots
+// 期限切れ間近ロット取得のリクエスト
+type GetExpiringLotsRequest struct {
+	Within time.Duration `json:"within"`
+}
+
+// NotifyExpiringLotsRequest is the request of NotifyExpiringLots
+// 期限切れ間近ロット通知のリクエスト
+type NotifyExpiringLotsRequest struct {
+	Within time.Duration `json:"within"`
+}
+
+// NotifyExpiringLotsResponse is the response of NotifyExpiringLots
+// 期限切れ間近ロット通知のレスポンス
+type NotifyExpiringLotsResponse struct {
+	Notified int `json:"notified"`
+}
+
+// messageTypes maps the top-level messages of inventory.proto to their Go types
+// inventory.proto のメッセージとGoの型の対応
+//
+// リクエスト・レスポンスに使用するメッセージのみ登録します。入れ子のメッセージはフィールドの型から変換されます。
+var messageTypes = map[string]interface{}{
+	"Item":                           inventory.Item{},
+	"Location":                       inventory.Location{},
+	"Stock":                          inventory.Stock{},
+	"Transaction":                    inventory.Transaction{},
+	"TransactionPage":                inventory.TransactionPage{},
+	"Lot":                            inventory.Lot{},
+	"StockAlert":                     inventory.StockAlert{},
+	"BatchOperation":                 inventory.BatchOperation{},
+	"Empty":                          Empty{},
+	"StockRequest":                   StockRequest{},
+	"TransferRequest":                TransferRequest{},
+	"AdjustRequest":                  AdjustRequest{},
+	"GetStockRequest":                GetStockRequest{},
+	"GetTotalStockRequest":           GetTotalStockRequest{},
+	"TotalStock":                     TotalStock{},
+	"GetStockByLocationRequest":      GetStockByLocationRequest{},
+	"GetTransactionRequest":          GetTransactionRequest{},
+	"GetHistoryRequest":              GetHistoryRequest{},
+	"GetHistoryPageRequest":          GetHistoryPageRequest{},
+	"GetHistoryByLocationRequest":    GetHistoryByLocationRequest{},
+	"GetHistoryByDateRangeRequest":   GetHistoryByDateRangeRequest{},
+	"GetHistoryByReferenceRequest":   GetHistoryByReferenceRequest{},
+	"SearchHistoryByMetadataRequest": SearchHistoryByMetadataRequest{},
+	"ArchiveTransactionsRequest":     ArchiveTransactionsRequest{},
+	"ArchiveTransactionsResponse":    ArchiveTransactionsResponse{},
+	"ExecuteBatchRequest":            ExecuteBatchRequest{},
+	"GetBatchStatusRequest":          GetBatchStatusRequest{},
+	"GetAlertsRequest":               GetAlertsRequest{},
+	"ResolveAlertRequest":            ResolveAlertRequest{},
+	"GetItemRequest":                 GetItemRequest{},
+	"DeleteItemRequest":              DeleteItemRequest{},
+	"ListItemsRequest":               ListItemsRequest{},
+	"SearchItemsRequest":             SearchItemsRequest{},
+	"GetLocationRequest":             GetLocationRequest{},
+	"DeleteLocationRequest":          DeleteLocationRequest{},
+	"ListLocationsRequest":           ListLocationsRequest{},
+	"GetLotRequest":                  GetLotRequest{},
+	"DeleteLotRequest":               DeleteLotRequest{},
+	"AdjustLotQuantityRequest":       AdjustLotQuantityRequest{},
+	"GetLotsByItemRequest":           GetLotsByItemRequest{},
+	"GetExpiringLotsRequest":         GetExpiringLotsRequest{},
+	"NotifyExpiringLotsRequest":      NotifyExpiringLotsRequest{},
+	"NotifyExpiringLotsResponse":     NotifyExpiringLotsResponse{},
+}
@@ -0,0 +1,535 @@
+// Package rpc provides the gRPC API of the inventory managers
+// 在庫マネージャーのgRPC APIを提供
+//
+// APIの定義は inventory.proto です。他言語のクライアントは protoc でこのファイルからスタブを生成してください。
+// Goのサーバーとクライアント（Client）は生成コードを使用せず、埋め込んだ inventory.proto から
+// 実行時に記述子を構築し、在庫パッケージの構造体とProtocol Buffersのワイヤー形式を直接変換します。
+package rpc
+
+import (
+	_ "embed"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	_ "google.golang.org/protobuf/types/known/durationpb"  // google/protobuf/duration.proto を登録
+	_ "google.golang.org/protobuf/types/known/timestamppb" // google/protobuf/timestamp.proto を登録
+)
+
+// protoFileName is the name the schema is registered under
+// スキーマの登録名
+const protoFileName = "zaigoframework/inventory/v1/inventory.proto"
+
+//go:embed inventory.proto
+var protoSource string
+
+// schema is the file descriptor built from inventory.proto
+// inventory.proto から構築したファイル記述子
+var schema = mustLoadSchema()
+
+// mustLoadSchema builds the descriptor of the embedded inventory.proto and registers it globally
+// 埋め込んだ inventory.proto の記述子を構築してグローバルに登録
+func mustLoadSchema() protoreflect.FileDescriptor {
+	file, err := loadSchema(protoFileName, protoSource)
+	if err != nil {
+		panic(fmt.Sprintf("inventory.proto の読み込みに失敗しました: %v", err))
+	}
+
+	// サーバーリフレクション（grpcurl など）からスキーマを参照できるよう登録する
+	if err := protoregistry.GlobalFiles.RegisterFile(file); err != nil {
+		panic(fmt.Sprintf("inventory.proto の登録に失敗しました: %v", err))
+	}
+	return file
+}
+
+// loadSchema parses a proto3 source and builds its file descriptor
+// proto3のソースを解析してファイル記述子を構築
+func loadSchema(name, source string) (protoreflect.FileDescriptor, error) {
+	tokens, err := tokenizeProto(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &protoParser{tokens: tokens}
+	fd, err := p.parseFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return protodesc.NewFile(fd, protoregistry.GlobalFiles)
+}
+
+// protoToken is a token of a proto source with its line number
+// protoソースのトークンと行番号
+type protoToken struct {
+	text string
+	line int
+}
+
+// tokenizeProto splits a proto source into tokens, dropping comments
+// protoソースをトークンに分割（コメントは除去）
+func tokenizeProto(source string) ([]protoToken, error) {
+	var tokens []protoToken
+	line := 1
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '\n':
+			line++
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			j := i + 2
+			for j+1 < len(runes) && !(runes[j] == '*' && runes[j+1] == '/') {
+				if runes[j] == '\n' {
+					line++
+				}
+				j++
+			}
+			if j+1 >= len(runes) {
+				return nil, fmt.Errorf("%d行目: コメントが閉じられていません", line)
+			}
+			i = j + 2
+		case r == '"':
+			j := i + 1
+			for j < len(runes) && runes[j] != '"' {
+				j++
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("%d行目: 文字列が閉じられていません", line)
+			}
+			tokens = append(tokens, protoToken{text: string(runes[i : j+1]), line: line})
+			i = j + 1
+		case isProtoIdentRune(r):
+			j := i
+			for j < len(runes) && isProtoIdentRune(runes[j]) {
+				j++
+			}
+			tokens = append(tokens, protoToken{text: string(runes[i:j]), line: line})
+			i = j
+		case strings.ContainsRune("{}()<>=;,", r):
+			tokens = append(tokens, protoToken{text: string(r), line: line})
+			i++
+		default:
+			return nil, fmt.Errorf("%d行目: 不正な文字 %q", line, r)
+		}
+	}
+	return tokens, nil
+}
+
+// isProtoIdentRune reports whether r can be part of an identifier, number or full name
+// 識別子・数値・完全修飾名に使用できる文字かを判定
+func isProtoIdentRune(r rune) bool {
+	return r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// protoScalarTypes maps proto scalar type names to descriptor types
+// protoのスカラー型名と記述子の型の対応
+var protoScalarTypes = map[string]descriptorpb.FieldDescriptorProto_Type{
+	"double":   descriptorpb.FieldDescriptorProto_TYPE_DOUBLE,
+	"float":    descriptorpb.FieldDescriptorProto_TYPE_FLOAT,
+	"int32":    descriptorpb.FieldDescriptorProto_TYPE_INT32,
+	"int64":    descriptorpb.FieldDescriptorProto_TYPE_INT64,
+	"uint32":   descriptorpb.FieldDescriptorProto_TYPE_UINT32,
+	"uint64":   descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+	"sint32":   descriptorpb.FieldDescriptorProto_TYPE_SINT32,
+	"sint64":   descriptorpb.FieldDescriptorProto_TYPE_SINT64,
+	"fixed32":  descriptorpb.FieldDescriptorProto_TYPE_FIXED32,
+	"fixed64":  descriptorpb.FieldDescriptorProto_TYPE_FIXED64,
+	"sfixed32": descriptorpb.FieldDescriptorProto_TYPE_SFIXED32,
+	"sfixed64": descriptorpb.FieldDescriptorProto_TYPE_SFIXED64,
+	"bool":     descriptorpb.FieldDescriptorProto_TYPE_BOOL,
+	"string":   descriptorpb.FieldDescriptorProto_TYPE_STRING,
+	"bytes":    descriptorpb.FieldDescriptorProto_TYPE_BYTES,
+}
+
+// protoParser parses the subset of proto3 used by inventory.proto
+// inventory.proto で使用するproto3のサブセットを解析する
+//
+// 対応しているのは syntax・package・import・option・message（入れ子なし）・service で、
+// フィールドはスカラー型・メッセージ型・map と repeated・optional のラベルです。enum・oneof には対応していません。
+type protoParser struct {
+	tokens []protoToken
+	pos    int
+	pkg    string
+}
+
+// next returns the next token, or an error at the end of the source
+// 次のトークンを返す（ソースの終わりの場合はエラー）
+func (p *protoParser) next() (string, error) {
+	if p.pos >= len(p.tokens) {
+		return "", fmt.Errorf("予期しないファイルの終わりです")
+	}
+	tok := p.tokens[p.pos]
+	p.pos++
+	return tok.text, nil
+}
+
+// peek returns the next token without consuming it
+// 次のトークンを消費せずに返す
+func (p *protoParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos].text
+}
+
+// expect consumes the next token and checks that it is want
+// 次のトークンを消費し、wantであることを確認
+func (p *protoParser) expect(want string) error {
+	got, err := p.next()
+	if err != nil {
+		return err
+	}
+	if got != want {
+		return p.errorf("%q が必要ですが %q でした", want, got)
+	}
+	return nil
+}
+
+// errorf returns an error annotated with the line of the last token
+// 直前のトークンの行番号を付けたエラーを返す
+func (p *protoParser) errorf(format string, args ...interface{}) error {
+	line := 0
+	if p.pos > 0 && p.pos <= len(p.tokens) {
+		line = p.tokens[p.pos-1].line
+	}
+	return fmt.Errorf("%d行目: %s", line, fmt.Sprintf(format, args...))
+}
+
+// str consumes a string literal and returns its contents
+// 文字列リテラルを消費して内容を返す
+func (p *protoParser) str() (string, error) {
+	tok, err := p.next()
+	if err != nil {
+		return "", err
+	}
+	if len(tok) < 2 || tok[0] != '"' {
+		return "", p.errorf("文字列が必要ですが %q でした", tok)
+	}
+	return tok[1 : len(tok)-1], nil
+}
+
+// parseFile parses the whole source into a file descriptor proto
+// ソース全体をファイル記述子に変換
+func (p *protoParser) parseFile(name string) (*descriptorpb.FileDescriptorProto, error) {
+	fd := &descriptorpb.FileDescriptorProto{Name: proto.String(name)}
+	for p.pos < len(p.tokens) {
+		keyword, _ := p.next()
+		switch keyword {
+		case "syntax":
+			if err := p.expect("="); err != nil {
+				return nil, err
+			}
+			syntax, err := p.str()
+			if err != nil {
+				return nil, err
+			}
+			if syntax != "proto3" {
+				return nil, p.errorf("proto3 のみ対応しています: %s", syntax)
+			}
+			fd.Syntax = proto.String(syntax)
+		case "package":
+			pkg, err := p.next()
+			if err != nil {
+				return nil, err
+			}
+			p.pkg = pkg
+			fd.Package = proto.String(pkg)
+		case "import":
+			dep, err := p.str()
+			if err != nil {
+				return nil, err
+			}
+			fd.Dependency = append(fd.Dependency, dep)
+		case "option":
+			option, err := p.next()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("="); err != nil {
+				return nil, err
+			}
+			value, err := p.str()
+			if err != nil {
+				return nil, err
+			}
+			if option == "go_package" {
+				fd.Options = &descriptorpb.FileOptions{GoPackage: proto.String(value)}
+			}
+		case "message":
+			msg, err := p.parseMessage()
+			if err != nil {
+				return nil, err
+			}
+			fd.MessageType = append(fd.MessageType, msg)
+			continue
+		case "service":
+			svc, err := p.parseService()
+			if err != nil {
+				return nil, err
+			}
+			fd.Service = append(fd.Service, svc)
+			continue
+		default:
+			return nil, p.errorf("未対応の定義です: %s", keyword)
+		}
+		if err := p.expect(";"); err != nil {
+			return nil, err
+		}
+	}
+	return fd, nil
+}
+
+// parseMessage parses a message definition after the message keyword
+// messageキーワード以降のメッセージ定義を解析
+func (p *protoParser) parseMessage() (*descriptorpb.DescriptorProto, error) {
+	name, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	msg := &descriptorpb.DescriptorProto{Name: proto.String(name)}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	for p.peek() != "}" {
+		field := &descriptorpb.FieldDescriptorProto{
+			Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		}
+		optional := false
+		typeName, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		switch typeName {
+		case "repeated":
+			field.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+			typeName, err = p.next()
+		case "optional":
+			optional = true
+			typeName, err = p.next()
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var entry *descriptorpb.DescriptorProto
+		if typeName == "map" {
+			entry, err = p.parseMapEntry()
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			p.setFieldType(field, typeName)
+		}
+
+		fieldName, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		field.Name = proto.String(fieldName)
+		if err := p.expect("="); err != nil {
+			return nil, err
+		}
+		numberText, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		number, err := strconv.ParseInt(numberText, 10, 32)
+		if err != nil {
+			return nil, p.errorf("フィールド番号が不正です: %s", numberText)
+		}
+		field.Number = proto.Int32(int32(number))
+		if err := p.expect(";"); err != nil {
+			return nil, err
+		}
+
+		if entry != nil {
+			// map<K, V> は入れ子のエントリーメッセージのrepeatedフィールドとして表現される
+			entry.Name = proto.String(mapEntryName(fieldName))
+			msg.NestedType = append(msg.NestedType, entry)
+			field.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+			field.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+			field.TypeName = proto.String("." + p.pkg + "." + name + "." + entry.GetName())
+		}
+		if optional {
+			// proto3のoptionalは合成oneofとして表現される
+			field.Proto3Optional = proto.Bool(true)
+			field.OneofIndex = proto.Int32(int32(len(msg.OneofDecl)))
+			msg.OneofDecl = append(msg.OneofDecl, &descriptorpb.OneofDescriptorProto{Name: proto.String("_" + fieldName)})
+		}
+		msg.Field = append(msg.Field, field)
+	}
+	p.pos++ // "}"
+	return msg, nil
+}
+
+// parseMapEntry parses <K, V> after the map keyword into a map entry message
+// mapキーワードに続く <K, V> をエントリーメッセージに変換
+func (p *protoParser) parseMapEntry() (*descriptorpb.DescriptorProto, error) {
+	if err := p.expect("<"); err != nil {
+		return nil, err
+	}
+	keyType, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(","); err != nil {
+		return nil, err
+	}
+	valueType, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(">"); err != nil {
+		return nil, err
+	}
+
+	key := &descriptorpb.FieldDescriptorProto{
+		Name:   proto.String("key"),
+		Number: proto.Int32(1),
+		Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+	}
+	value := &descriptorpb.FieldDescriptorProto{
+		Name:   proto.String("value"),
+		Number: proto.Int32(2),
+		Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+	}
+	p.setFieldType(key, keyType)
+	p.setFieldType(value, valueType)
+	return &descriptorpb.DescriptorProto{
+		Field:   []*descriptorpb.FieldDescriptorProto{key, value},
+		Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+	}, nil
+}
+
+// setFieldType sets the type of field from a scalar or message type name
+// スカラー型またはメッセージ型の名前からフィールドの型を設定
+func (p *protoParser) setFieldType(field *descriptorpb.FieldDescriptorProto, typeName string) {
+	if scalar, ok := protoScalarTypes[typeName]; ok {
+		field.Type = scalar.Enum()
+		return
+	}
+	field.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+	field.TypeName = proto.String(p.qualify(typeName))
+}
+
+// qualify returns the fully qualified name of a message type
+// メッセージ型の完全修飾名を返す
+func (p *protoParser) qualify(typeName string) string {
+	if strings.Contains(typeName, ".") {
+		return "." + strings.TrimPrefix(typeName, ".")
+	}
+	return "." + p.pkg + "." + typeName
+}
+
+// parseService parses a service definition after the service keyword
+// serviceキーワード以降のサービス定義を解析
+func (p *protoParser) parseService() (*descriptorpb.ServiceDescriptorProto, error) {
+	name, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	svc := &descriptorpb.ServiceDescriptorProto{Name: proto.String(name)}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	for p.peek() != "}" {
+		if err := p.expect("rpc"); err != nil {
+			return nil, err
+		}
+		methodName, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		method := &descriptorpb.MethodDescriptorProto{Name: proto.String(methodName)}
+
+		input, clientStreaming, err := p.parseRPCType()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("returns"); err != nil {
+			return nil, err
+		}
+		output, serverStreaming, err := p.parseRPCType()
+		if err != nil {
+			return nil, err
+		}
+		method.InputType = proto.String(p.qualify(input))
+		method.OutputType = proto.String(p.qualify(output))
+		if clientStreaming {
+			method.ClientStreaming = proto.Bool(true)
+		}
+		if serverStreaming {
+			method.ServerStreaming = proto.Bool(true)
+		}
+
+		// 本体のないオプションブロック {} も受け付ける
+		if p.peek() == "{" {
+			p.pos++
+			if err := p.expect("}"); err != nil {
+				return nil, err
+			}
+		} else if err := p.expect(";"); err != nil {
+			return nil, err
+		}
+		svc.Method = append(svc.Method, method)
+	}
+	p.pos++ // "}"
+	return svc, nil
+}
+
+// parseRPCType parses ([stream] Type) of an rpc definition
+// rpc定義の ([stream] 型) を解析
+func (p *protoParser) parseRPCType() (string, bool, error) {
+	if err := p.expect("("); err != nil {
+		return "", false, err
+	}
+	typeName, err := p.next()
+	if err != nil {
+		return "", false, err
+	}
+	streaming := false
+	if typeName == "stream" {
+		streaming = true
+		if typeName, err = p.next(); err != nil {
+			return "", false, err
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return "", false, err
+	}
+	return typeName, streaming, nil
+}
+
+// mapEntryName returns the entry message name protoc generates for a map field
+// protocがmapフィールドに生成するエントリーメッセージ名を返す（metadata → MetadataEntry）
+func mapEntryName(fieldName string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range fieldName {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	b.WriteString("Entry")
+	return b.String()
+}
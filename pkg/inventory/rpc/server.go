@@ -0,0 +1,425 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// Manager is the set of managers exposed by the gRPC API
+// gRPC APIで公開するマネージャーの集合
+//
+// inventory.Manager と Client のどちらもこのインターフェースを実装します。
+type Manager interface {
+	inventory.InventoryManager
+	inventory.ItemManager
+	inventory.LocationManager
+	inventory.LotManager
+}
+
+var _ Manager = (*inventory.Manager)(nil)
+
+// unaryHandler handles a unary RPC with its decoded request
+// デコード済みのリクエストで単項RPCを処理する
+type unaryHandler func(ctx context.Context, req interface{}) (interface{}, error)
+
+// streamHandler handles a server streaming RPC, sending each response with send
+// サーバーストリーミングRPCを処理し、各レスポンスをsendで送信する
+type streamHandler func(ctx context.Context, req interface{}, send func(interface{}) error) error
+
+// methodHandler is the handler type of grpc.MethodDesc
+// grpc.MethodDescのハンドラーの型
+type methodHandler = func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error)
+
+// Server serves the services of inventory.proto backed by a Manager
+// Managerを使用して inventory.proto のサービスを提供する
+//
+// 操作者はUnaryAuthInterceptor・StreamAuthInterceptorがコンテキストに設定したユーザーとして記録します。
+type Server struct {
+	manager Manager
+	logger  *zap.Logger
+}
+
+// NewServer creates a gRPC server for manager
+// マネージャーのgRPCサーバーを作成
+func NewServer(manager Manager, logger *zap.Logger) *Server {
+	return &Server{manager: manager, logger: logger}
+}
+
+// NewServerOptions returns the options a grpc.Server needs to serve this package's services
+// このパッケージのサービスを提供するgrpc.Serverに必要なオプションを返す
+func NewServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{grpc.ForceServerCodec(Codec())}
+}
+
+// Register registers all services of inventory.proto with registrar
+// inventory.proto のすべてのサービスを登録
+//
+// protoのRPCとハンドラーが一致しない場合はエラーを返します。
+func (s *Server) Register(registrar grpc.ServiceRegistrar) error {
+	handlers := s.handlers()
+	services := schema.Services()
+	descs := make([]*grpc.ServiceDesc, 0, services.Len())
+	for i := 0; i < services.Len(); i++ {
+		desc, err := s.serviceDesc(services.Get(i), handlers)
+		if err != nil {
+			return err
+		}
+		descs = append(descs, desc)
+	}
+	for name := range handlers {
+		return fmt.Errorf("inventory.proto に定義されていないRPCのハンドラーです: %s", name)
+	}
+
+	for _, desc := range descs {
+		registrar.RegisterService(desc, s)
+	}
+	return nil
+}
+
+// serviceDesc builds the grpc service description of sd, consuming its handlers
+// サービス記述子からgrpcのサービス定義を作成（使用したハンドラーはhandlersから削除する）
+func (s *Server) serviceDesc(sd protoreflect.ServiceDescriptor, handlers map[string]interface{}) (*grpc.ServiceDesc, error) {
+	desc := &grpc.ServiceDesc{
+		ServiceName: string(sd.FullName()),
+		HandlerType: (*interface{})(nil),
+		Metadata:    protoFileName,
+	}
+
+	methods := sd.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		md := methods.Get(i)
+		name := string(sd.Name()) + "/" + string(md.Name())
+		fullMethod := "/" + string(sd.FullName()) + "/" + string(md.Name())
+
+		reqType, ok := goTypes[md.Input().FullName()]
+		if !ok {
+			return nil, fmt.Errorf("%s のリクエスト %s に対応するGoの型がありません", name, md.Input().Name())
+		}
+		if _, ok := goTypes[md.Output().FullName()]; !ok {
+			return nil, fmt.Errorf("%s のレスポンス %s に対応するGoの型がありません", name, md.Output().Name())
+		}
+		handler, ok := handlers[name]
+		if !ok {
+			return nil, fmt.Errorf("%s のハンドラーがありません", name)
+		}
+		delete(handlers, name)
+
+		switch h := handler.(type) {
+		case unaryHandler:
+			if md.IsStreamingClient() || md.IsStreamingServer() {
+				return nil, fmt.Errorf("%s はストリーミングRPCです", name)
+			}
+			desc.Methods = append(desc.Methods, grpc.MethodDesc{
+				MethodName: string(md.Name()),
+				Handler:    s.unary(fullMethod, reqType, h),
+			})
+		case streamHandler:
+			if md.IsStreamingClient() || !md.IsStreamingServer() {
+				return nil, fmt.Errorf("%s はサーバーストリーミングRPCではありません", name)
+			}
+			desc.Streams = append(desc.Streams, grpc.StreamDesc{
+				StreamName:    string(md.Name()),
+				Handler:       s.stream(fullMethod, reqType, h),
+				ServerStreams: true,
+			})
+		}
+	}
+	return desc, nil
+}
+
+// unary adapts a unaryHandler to a grpc method handler
+// unaryHandlerをgrpcのメソッドハンドラーに変換
+func (s *Server) unary(fullMethod string, reqType reflect.Type, h unaryHandler) methodHandler {
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		req := reflect.New(reqType).Interface()
+		if err := dec(req); err != nil {
+			return nil, err
+		}
+		call := func(ctx context.Context, req interface{}) (interface{}, error) {
+			resp, err := h(ctx, req)
+			if err != nil {
+				return nil, s.statusError(fullMethod, err)
+			}
+			return resp, nil
+		}
+		if interceptor == nil {
+			return call(ctx, req)
+		}
+		return interceptor(ctx, req, &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod}, call)
+	}
+}
+
+// stream adapts a streamHandler to a grpc stream handler
+// streamHandlerをgrpcのストリームハンドラーに変換
+func (s *Server) stream(fullMethod string, reqType reflect.Type, h streamHandler) grpc.StreamHandler {
+	return func(srv interface{}, stream grpc.ServerStream) error {
+		req := reflect.New(reqType).Interface()
+		if err := stream.RecvMsg(req); err != nil {
+			return err
+		}
+		if err := h(stream.Context(), req, stream.SendMsg); err != nil {
+			return s.statusError(fullMethod, err)
+		}
+		return nil
+	}
+}
+
+// statusError converts a manager error to a gRPC status error
+// マネージャーのエラーをgRPCのステータスエラーに変換
+func (s *Server) statusError(fullMethod string, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	code := errorCode(err)
+	if code == codes.Internal {
+		s.logger.Error("gRPCリクエストの処理に失敗しました", zap.String("method", fullMethod), zap.Error(err))
+	}
+	return status.Error(code, err.Error())
+}
+
+// sendEach sends each record of a list result
+// 一覧の結果を1件ずつ送信
+func sendEach[T any](send func(interface{}) error, records []T, err error) error {
+	if err != nil {
+		return err
+	}
+	for i := range records {
+		if err := send(&records[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// empty returns the Empty response of an RPC that only reports an error
+// エラーのみを返すRPCの空のレスポンスを返す
+func empty(err error) (interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	return &Empty{}, nil
+}
+
+// handlers returns the handler of each RPC keyed by "Service/Method"
+// 各RPCのハンドラーを "サービス/メソッド" をキーとして返す
+func (s *Server) handlers() map[string]interface{} {
+	m := s.manager
+	return map[string]interface{}{
+		// 在庫操作
+		"InventoryService/Add": unaryHandler(func(ctx context.Context, req interface{}) (interface{}, error) {
+			r := req.(*StockRequest)
+			return empty(m.Add(ctx, r.ItemID, r.LocationID, r.Quantity, r.Reference))
+		}),
+		"InventoryService/Remove": unaryHandler(func(ctx context.Context, req interface{}) (interface{}, error) {
+			r := req.(*StockRequest)
+			return empty(m.Remove(ctx, r.ItemID, r.LocationID, r.Quantity, r.Reference))
+		}),
+		"InventoryService/Transfer": unaryHandler(func(ctx context.Context, req interface{}) (interface{}, error) {
+			r := req.(*TransferRequest)
+			return empty(m.Transfer(ctx, r.ItemID, r.FromLocationID, r.ToLocationID, r.Quantity, r.Reference))
+		}),
+		"InventoryService/Adjust": unaryHandler(func(ctx context.Context, req interface{}) (interface{}, error) {
+			r := req.(*AdjustRequest)
+			return empty(m.Adjust(ctx, r.ItemID, r.LocationID, r.NewQuantity, r.Reference))
+		}),
+		"InventoryService/GetStock": unaryHandler(func(ctx context.Context, req interface{}) (interface{}, error) {
+			r := req.(*GetStockRequest)
+			return m.GetStock(ctx, r.ItemID, r.LocationID)
+		}),
+		"InventoryService/GetTotalStock": unaryHandler(func(ctx context.Context, req interface{}) (interface{}, error) {
+			r := req.(*GetTotalStockRequest)
+			total, err := m.GetTotalStock(ctx, r.ItemID)
+			if err != nil {
+				return nil, err
+			}
+			return &TotalStock{ItemID: r.ItemID, Total: total}, nil
+		}),
+		"InventoryService/GetStockByLocation": streamHandler(func(ctx context.Context, req interface{}, send func(interface{}) error) error {
+			r := req.(*GetStockByLocationRequest)
+			return m.ForEachStockByLocation(ctx, r.LocationID, func(stock inventory.Stock) error {
+				return send(&stock)
+			})
+		}),
+		"InventoryService/GetTransaction": unaryHandler(func(ctx context.Context, req interface{}) (interface{}, error) {
+			r := req.(*GetTransactionRequest)
+			return m.GetTransaction(ctx, r.ID)
+		}),
+		"InventoryService/GetHistory": streamHandler(func(ctx context.Context, req interface{}, send func(interface{}) error) error {
+			r := req.(*GetHistoryRequest)
+			transactions, err := m.GetHistory(ctx, r.ItemID, r.Limit)
+			return sendEach(send, transactions, err)
+		}),
+		"InventoryService/GetHistoryPage": unaryHandler(func(ctx context.Context, req interface{}) (interface{}, error) {
+			r := req.(*GetHistoryPageRequest)
+			return m.GetHistoryPage(ctx, r.ItemID, r.Cursor, r.Limit)
+		}),
+		"InventoryService/GetHistoryByLocation": streamHandler(func(ctx context.Context, req interface{}, send func(interface{}) error) error {
+			r := req.(*GetHistoryByLocationRequest)
+			transactions, err := m.GetHistoryByLocation(ctx, r.LocationID, r.Limit)
+			return sendEach(send, transactions, err)
+		}),
+		"InventoryService/GetHistoryByDateRange": streamHandler(func(ctx context.Context, req interface{}, send func(interface{}) error) error {
+			r := req.(*GetHistoryByDateRangeRequest)
+			transactions, err := m.GetHistoryByDateRange(ctx, r.ItemID, r.From, r.To)
+			return sendEach(send, transactions, err)
+		}),
+		"InventoryService/GetHistoryByReference": streamHandler(func(ctx context.Context, req interface{}, send func(interface{}) error) error {
+			r := req.(*GetHistoryByReferenceRequest)
+			transactions, err := m.GetHistoryByReference(ctx, r.Reference)
+			return sendEach(send, transactions, err)
+		}),
+		"InventoryService/SearchHistoryByMetadata": streamHandler(func(ctx context.Context, req interface{}, send func(interface{}) error) error {
+			r := req.(*SearchHistoryByMetadataRequest)
+			transactions, err := m.SearchHistoryByMetadata(ctx, r.Key, r.Value, r.Limit)
+			return sendEach(send, transactions, err)
+		}),
+		"InventoryService/ArchiveTransactions": unaryHandler(func(ctx context.Context, req interface{}) (interface{}, error) {
+			r := req.(*ArchiveTransactionsRequest)
+			archived, err := m.ArchiveTransactions(ctx, r.Before)
+			if err != nil {
+				return nil, err
+			}
+			return &ArchiveTransactionsResponse{Archived: archived}, nil
+		}),
+		"InventoryService/ExecuteBatch": unaryHandler(func(ctx context.Context, req interface{}) (interface{}, error) {
+			r := req.(*ExecuteBatchRequest)
+			if r.Atomic {
+				return m.ExecuteBatchAtomic(ctx, r.Operations)
+			}
+			return m.ExecuteBatch(ctx, r.Operations)
+		}),
+		"InventoryService/GetBatchStatus": unaryHandler(func(ctx context.Context, req interface{}) (interface{}, error) {
+			r := req.(*GetBatchStatusRequest)
+			return m.GetBatchStatus(ctx, r.BatchID)
+		}),
+		"InventoryService/Reserve": unaryHandler(func(ctx context.Context, req interface{}) (interface{}, error) {
+			r := req.(*StockRequest)
+			return empty(m.Reserve(ctx, r.ItemID, r.LocationID, r.Quantity, r.Reference))
+		}),
+		"InventoryService/ReleaseReservation": unaryHandler(func(ctx context.Context, req interface{}) (interface{}, error) {
+			r := req.(*StockRequest)
+			return empty(m.ReleaseReservation(ctx, r.ItemID, r.LocationID, r.Quantity, r.Reference))
+		}),
+		"InventoryService/GetAlerts": streamHandler(func(ctx context.Context, req interface{}, send func(interface{}) error) error {
+			r := req.(*GetAlertsRequest)
+			alerts, err := m.GetAlerts(ctx, r.LocationID)
+			return sendEach(send, alerts, err)
+		}),
+		"InventoryService/ResolveAlert": unaryHandler(func(ctx context.Context, req interface{}) (interface{}, error) {
+			r := req.(*ResolveAlertRequest)
+			return empty(m.ResolveAlert(ctx, r.AlertID))
+		}),
+
+		// 商品管理
+		"ItemService/CreateItem": unaryHandler(func(ctx context.Context, req interface{}) (interface{}, error) {
+			item := req.(*inventory.Item)
+			if err := m.CreateItem(ctx, item); err != nil {
+				return nil, err
+			}
+			return item, nil
+		}),
+		"ItemService/GetItem": unaryHandler(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return m.GetItem(ctx, req.(*GetItemRequest).ID)
+		}),
+		"ItemService/UpdateItem": unaryHandler(func(ctx context.Context, req interface{}) (interface{}, error) {
+			item := req.(*inventory.Item)
+			if err := m.UpdateItem(ctx, item); err != nil {
+				return nil, err
+			}
+			return item, nil
+		}),
+		"ItemService/DeleteItem": unaryHandler(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return empty(m.DeleteItem(ctx, req.(*DeleteItemRequest).ID))
+		}),
+		"ItemService/ListItems": streamHandler(func(ctx context.Context, req interface{}, send func(interface{}) error) error {
+			r := req.(*ListItemsRequest)
+			items, err := m.ListItems(ctx, r.Offset, r.Limit)
+			return sendEach(send, items, err)
+		}),
+		"ItemService/SearchItems": streamHandler(func(ctx context.Context, req interface{}, send func(interface{}) error) error {
+			items, err := m.SearchItems(ctx, req.(*SearchItemsRequest).Query)
+			return sendEach(send, items, err)
+		}),
+
+		// ロケーション管理
+		"LocationService/CreateLocation": unaryHandler(func(ctx context.Context, req interface{}) (interface{}, error) {
+			location := req.(*inventory.Location)
+			if err := m.CreateLocation(ctx, location); err != nil {
+				return nil, err
+			}
+			return location, nil
+		}),
+		"LocationService/GetLocation": unaryHandler(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return m.GetLocation(ctx, req.(*GetLocationRequest).ID)
+		}),
+		"LocationService/UpdateLocation": unaryHandler(func(ctx context.Context, req interface{}) (interface{}, error) {
+			location := req.(*inventory.Location)
+			if err := m.UpdateLocation(ctx, location); err != nil {
+				return nil, err
+			}
+			return location, nil
+		}),
+		"LocationService/DeleteLocation": unaryHandler(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return empty(m.DeleteLocation(ctx, req.(*DeleteLocationRequest).ID))
+		}),
+		"LocationService/ListLocations": streamHandler(func(ctx context.Context, req interface{}, send func(interface{}) error) error {
+			r := req.(*ListLocationsRequest)
+			locations, err := m.ListLocations(ctx, r.Offset, r.Limit)
+			return sendEach(send, locations, err)
+		}),
+
+		// ロット管理
+		"LotService/CreateLot": unaryHandler(func(ctx context.Context, req interface{}) (interface{}, error) {
+			lot := req.(*inventory.Lot)
+			if err := m.CreateLot(ctx, lot); err != nil {
+				return nil, err
+			}
+			return lot, nil
+		}),
+		"LotService/GetLot": unaryHandler(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return m.GetLot(ctx, req.(*GetLotRequest).ID)
+		}),
+		"LotService/UpdateLot": unaryHandler(func(ctx context.Context, req interface{}) (interface{}, error) {
+			lot := req.(*inventory.Lot)
+			if err := m.UpdateLot(ctx, lot); err != nil {
+				return nil, err
+			}
+			return lot, nil
+		}),
+		"LotService/DeleteLot": unaryHandler(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return empty(m.DeleteLot(ctx, req.(*DeleteLotRequest).ID))
+		}),
+		"LotService/AdjustLotQuantity": unaryHandler(func(ctx context.Context, req interface{}) (interface{}, error) {
+			r := req.(*AdjustLotQuantityRequest)
+			return m.AdjustLotQuantity(ctx, r.ID, r.Delta, r.Reference)
+		}),
+		"LotService/GetLotsByItem": streamHandler(func(ctx context.Context, req interface{}, send func(interface{}) error) error {
+			lots, err := m.GetLotsByItem(ctx, req.(*GetLotsByItemRequest).ItemID)
+			return sendEach(send, lots, err)
+		}),
+		"LotService/GetExpiringLots": streamHandler(func(ctx context.Context, req interface{}, send func(interface{}) error) error {
+			lots, err := m.GetExpiringLots(ctx, req.(*GetExpiringLotsRequest).Within)
+			return sendEach(send, lots, err)
+		}),
+		"LotService/NotifyExpiringLots": unaryHandler(func(ctx context.Context, req interface{}) (interface{}, error) {
+			notified, err := m.NotifyExpiringLots(ctx, req.(*NotifyExpiringLotsRequest).Within)
+			if err != nil {
+				return nil, err
+			}
+			return &NotifyExpiringLotsResponse{Notified: notified}, nil
+		}),
+		"LotService/GetExpiredLots": streamHandler(func(ctx context.Context, req interface{}, send func(interface{}) error) error {
+			lots, err := m.GetExpiredLots(ctx)
+			return sendEach(send, lots, err)
+		}),
+	}
+}
@@ -0,0 +1,243 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/storage"
+)
+
+// newTestClient はメモリストレージのマネージャーを提供するサーバーに接続したクライアントを作成
+// （呼び出し元は authConfig で認証する）
+func newTestClient(t *testing.T, authConfig AuthConfig) (*Client, *grpc.ClientConn) {
+	t.Helper()
+
+	manager := inventory.NewManager(storage.NewMemoryStorage(), nil, zap.NewNop(), nil)
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(append(NewServerOptions(),
+		grpc.ChainUnaryInterceptor(UnaryAuthInterceptor(authConfig, zap.NewNop())),
+		grpc.ChainStreamInterceptor(StreamAuthInterceptor(authConfig, zap.NewNop())),
+	)...)
+	require.NoError(t, NewServer(manager, zap.NewNop()).Register(server))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return NewClient(conn), conn
+}
+
+// TestClient_StockOperations はクライアント経由での在庫操作と照会のテスト
+func TestClient_StockOperations(t *testing.T) {
+	client, _ := newTestClient(t, AuthConfig{})
+	ctx := context.Background()
+
+	item := &inventory.Item{ID: "ITEM-1", Name: "テスト商品", UnitCost: 120.5}
+	require.NoError(t, client.CreateItem(ctx, item))
+	require.NoError(t, client.CreateLocation(ctx, &inventory.Location{ID: "LOC-A", Name: "ロケーションA", IsActive: true}))
+	require.NoError(t, client.CreateLocation(ctx, &inventory.Location{ID: "LOC-B", Name: "ロケーションB", IsActive: true}))
+
+	got, err := client.GetItem(ctx, "ITEM-1")
+	require.NoError(t, err)
+	assert.Equal(t, "テスト商品", got.Name)
+	assert.Equal(t, 120.5, got.UnitCost)
+
+	require.NoError(t, client.Add(ctx, "ITEM-1", "LOC-A", 10, "PO-1"))
+	require.NoError(t, client.Transfer(ctx, "ITEM-1", "LOC-A", "LOC-B", 3, "TR-1"))
+	require.NoError(t, client.Reserve(ctx, "ITEM-1", "LOC-A", 2, "SO-1"))
+
+	stock, err := client.GetStock(ctx, "ITEM-1", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), stock.Quantity)
+	assert.Equal(t, int64(2), stock.Reserved)
+	assert.Equal(t, int64(5), stock.Available)
+	assert.Equal(t, anonymousUserID, stock.UpdatedBy)
+	assert.False(t, stock.UpdatedAt.IsZero())
+
+	total, err := client.GetTotalStock(ctx, "ITEM-1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), total)
+
+	// ストリーミングで取得
+	stocks, err := client.GetStockByLocation(ctx, "LOC-B")
+	require.NoError(t, err)
+	require.Len(t, stocks, 1)
+	assert.Equal(t, int64(3), stocks[0].Quantity)
+
+	history, err := client.GetHistory(ctx, "ITEM-1", 10)
+	require.NoError(t, err)
+	// 入庫・移動・予約のトランザクション
+	require.Len(t, history, 3)
+	var transfer inventory.Transaction
+	for _, tx := range history {
+		if tx.Type == inventory.TransactionTypeTransfer {
+			transfer = tx
+		}
+	}
+	require.NotNil(t, transfer.FromLocation)
+	require.NotNil(t, transfer.ToLocation)
+	assert.Equal(t, "LOC-A", *transfer.FromLocation)
+	assert.Equal(t, "LOC-B", *transfer.ToLocation)
+
+	page, err := client.GetHistoryPage(ctx, "ITEM-1", nil, 1)
+	require.NoError(t, err)
+	assert.Len(t, page.Transactions, 1)
+	require.NotNil(t, page.NextCursor)
+	page, err = client.GetHistoryPage(ctx, "ITEM-1", page.NextCursor, 1)
+	require.NoError(t, err)
+	assert.Len(t, page.Transactions, 1)
+}
+
+// TestClient_ExecuteBatch はバッチ操作の結果が操作ごとのエラーを含めて返るテスト
+func TestClient_ExecuteBatch(t *testing.T) {
+	client, _ := newTestClient(t, AuthConfig{})
+	ctx := context.Background()
+
+	require.NoError(t, client.CreateItem(ctx, &inventory.Item{ID: "ITEM-1", Name: "テスト商品"}))
+	require.NoError(t, client.CreateLocation(ctx, &inventory.Location{ID: "LOC-A", Name: "ロケーションA"}))
+
+	toLocation := "LOC-X"
+	batch, err := client.ExecuteBatch(ctx, []inventory.InventoryOperation{
+		{Type: inventory.OperationTypeAdd, ItemID: "ITEM-1", LocationID: "LOC-A", Quantity: 5, Reference: "B-1"},
+		{Type: inventory.OperationTypeTransfer, ItemID: "ITEM-1", LocationID: "LOC-A", ToLocationID: &toLocation, Quantity: 1, Reference: "B-1"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, inventory.BatchStatusFailed, batch.Status)
+	assert.Equal(t, 1, batch.SuccessCount)
+	assert.Equal(t, 1, batch.FailureCount)
+	require.Len(t, batch.Operations, 2)
+	require.NotNil(t, batch.Operations[1].ToLocationID)
+	assert.Equal(t, "LOC-X", *batch.Operations[1].ToLocationID)
+	require.Len(t, batch.Errors, 1)
+	assert.Equal(t, 1, batch.Errors[0].OperationIndex)
+	assert.NotNil(t, batch.CompletedAt)
+}
+
+// TestClient_Errors はマネージャーのエラーがステータスコードと元のエラーとして返るテスト
+func TestClient_Errors(t *testing.T) {
+	client, conn := newTestClient(t, AuthConfig{})
+	ctx := context.Background()
+
+	_, err := client.GetItem(ctx, "MISSING")
+	assert.ErrorIs(t, err, inventory.ErrItemNotFound)
+
+	require.NoError(t, client.CreateItem(ctx, &inventory.Item{ID: "ITEM-1", Name: "テスト商品"}))
+	require.NoError(t, client.CreateLocation(ctx, &inventory.Location{ID: "LOC-A", Name: "ロケーションA"}))
+	err = client.Remove(ctx, "ITEM-1", "LOC-A", 1, "SO-1")
+	assert.Error(t, err)
+
+	// 生成したスタブと同じく、ステータスコードで判定できる
+	err = conn.Invoke(ctx, itemService+"GetItem", &GetItemRequest{ID: "MISSING"}, &inventory.Item{}, grpc.ForceCodec(Codec()))
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+// TestCodec_RoundTrip はポインター・map・日時を含む構造体の変換のテスト
+func TestCodec_RoundTrip(t *testing.T) {
+	from := "LOC-A"
+	unitCost := 99.5
+	expiry := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	tx := &inventory.Transaction{
+		ID:           "TX-1",
+		Type:         inventory.TransactionTypeOutbound,
+		ItemID:       "ITEM-1",
+		FromLocation: &from,
+		Quantity:     -4,
+		UnitCost:     &unitCost,
+		ExpiryDate:   &expiry,
+		Metadata:     map[string]string{"order_id": "SO-1"},
+		CreatedAt:    time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC),
+		CreatedBy:    "tester",
+	}
+
+	data, err := Codec().Marshal(tx)
+	require.NoError(t, err)
+	var decoded inventory.Transaction
+	require.NoError(t, Codec().Unmarshal(data, &decoded))
+	assert.Equal(t, *tx, decoded)
+
+	var request GetExpiringLotsRequest
+	data, err = Codec().Marshal(&GetExpiringLotsRequest{Within: 36 * time.Hour})
+	require.NoError(t, err)
+	require.NoError(t, Codec().Unmarshal(data, &request))
+	assert.Equal(t, 36*time.Hour, request.Within)
+
+	_, err = Codec().Marshal(&struct{ ID string }{})
+	assert.Error(t, err)
+
+	// 生成コードのメッセージ（ヘルスチェックなど）はそのまま変換される
+	data, err = Codec().Marshal(durationpb.New(time.Minute))
+	require.NoError(t, err)
+	var duration durationpb.Duration
+	require.NoError(t, Codec().Unmarshal(data, &duration))
+	assert.Equal(t, time.Minute, duration.AsDuration())
+}
+
+// TestMessageTypes_MatchProto は登録したGoの型のフィールドがすべてprotoに定義されているテスト
+//
+// 在庫パッケージの構造体にフィールドを追加した場合は inventory.proto にも追加してください。
+func TestMessageTypes_MatchProto(t *testing.T) {
+	var check func(reflect.Type, protoreflect.MessageDescriptor)
+	check = func(goType reflect.Type, md protoreflect.MessageDescriptor) {
+		for i := 0; i < goType.NumField(); i++ {
+			name := fieldName(goType.Field(i))
+			if name == "" {
+				continue
+			}
+			fd := md.Fields().ByName(protoreflect.Name(name))
+			if !assert.NotNil(t, fd, "%s.%s が inventory.proto にありません", md.Name(), name) {
+				continue
+			}
+
+			fieldType := goType.Field(i).Type
+			for fieldType.Kind() == reflect.Ptr || fieldType.Kind() == reflect.Slice {
+				fieldType = fieldType.Elem()
+			}
+			if fd.Kind() == protoreflect.MessageKind && !fd.IsMap() && fieldType.Kind() == reflect.Struct && fieldType != timeType {
+				check(fieldType, fd.Message())
+			}
+		}
+	}
+
+	for goType, md := range messageDescriptors {
+		check(goType, md)
+	}
+}
+
+// TestLoadSchema_Errors はprotoの構文エラーのテスト
+func TestLoadSchema_Errors(t *testing.T) {
+	_, err := loadSchema("broken.proto", `syntax = "proto3"; package test.v1; message A { string id = ; }`)
+	assert.Error(t, err)
+
+	_, err = loadSchema("unknown.proto", `syntax = "proto3"; package test.v1; message A { Missing m = 1; }`)
+	assert.Error(t, err)
+
+	file, err := loadSchema("ok.proto", `syntax = "proto3"; package test.v1;
+		/* ブロック
+		   コメント */
+		message A { optional string id = 1; map<string, int64> counts = 2; }
+		service S { rpc Get(A) returns (stream A) {} }`)
+	require.NoError(t, err)
+	a := file.Messages().ByName("A")
+	assert.True(t, a.Fields().ByName("id").HasPresence())
+	assert.True(t, a.Fields().ByName("counts").IsMap())
+	assert.True(t, file.Services().ByName("S").Methods().ByName("Get").IsStreamingServer())
+}
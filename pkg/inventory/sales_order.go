@@ -0,0 +1,610 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var _ SalesOrderManager = (*Manager)(nil)
+
+// salesOrderShipment is the stock change of one line of a confirmed shipment
+// 出荷確定した受注の1行の在庫の変化
+type salesOrderShipment struct {
+	stock       *Stock
+	oldQuantity int64
+	tx          *Transaction
+}
+
+// CreateSalesOrder validates and creates an open sales order
+// 受注を検証して受付済み（open）として作成
+//
+// LocationID（出荷元）・Reference（任意）・Customer（任意）・Note（任意）・Lines を指定します。
+// ID・状態・バージョン・受付日時・受付者は設定され、Reference を省略した場合は受注IDを設定します。
+// 受注の作成では在庫を引き当てません。AllocateSalesOrder で引き当てます。
+func (m *Manager) CreateSalesOrder(ctx context.Context, order *SalesOrder) (err error) {
+	ctx, finish := m.startOperation(ctx, "create_sales_order", attrLocationID.String(order.LocationID), attrReference.String(order.Reference))
+	defer finish(&err)
+
+	if err := ValidateSalesOrder(order); err != nil {
+		return err
+	}
+	if _, err := m.storage.GetLocation(ctx, order.LocationID); err != nil {
+		if errors.Is(err, ErrLocationNotFound) {
+			return ErrLocationNotFound
+		}
+		return NewStorageError("get_location", "ロケーション取得に失敗しました", err)
+	}
+	for _, line := range order.Lines {
+		if _, err := m.storage.GetItem(ctx, line.ItemID); err != nil {
+			if errors.Is(err, ErrItemNotFound) {
+				return ErrItemNotFound
+			}
+			return NewStorageError("get_item", "商品取得に失敗しました", err)
+		}
+	}
+
+	order.ID = NewSalesOrderID()
+	order.Status = SalesOrderStatusOpen
+	order.Version = 1
+	order.CreatedAt = time.Now()
+	order.CreatedBy = m.getUserFromContext(ctx)
+	order.ClosedAt, order.ClosedBy = nil, ""
+	if order.Reference == "" {
+		order.Reference = order.ID
+	}
+	for i := range order.Lines {
+		line := &order.Lines[i]
+		line.SalesOrderID = order.ID
+		line.AllocatedQuantity, line.ShippedQuantity, line.ShortQuantity = 0, 0, 0
+	}
+
+	if err := m.storage.CreateSalesOrder(ctx, order); err != nil {
+		return NewStorageError("create_sales_order", "受注の作成に失敗しました", err)
+	}
+
+	m.log(ctx).Info("受注作成完了",
+		zap.String("sales_order_id", order.ID),
+		zap.String("reference", order.Reference),
+		zap.String("location_id", order.LocationID),
+		zap.Int("lines", len(order.Lines)),
+	)
+	return nil
+}
+
+// GetSalesOrder retrieves a sales order with its lines
+// 受注を行とともに取得
+func (m *Manager) GetSalesOrder(ctx context.Context, orderID string) (*SalesOrder, error) {
+	if orderID == "" {
+		return nil, NewValidationError("sales_order_id", "受注IDが指定されていません", "")
+	}
+
+	order, err := m.storage.GetSalesOrder(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, ErrSalesOrderNotFound) {
+			return nil, ErrSalesOrderNotFound
+		}
+		return nil, NewStorageError("get_sales_order", "受注の取得に失敗しました", err)
+	}
+	return order, nil
+}
+
+// ListSalesOrders lists sales orders matching a filter, newest first, without their lines
+// 条件に一致する受注を受付日時の新しい順に取得（行は含まない）
+func (m *Manager) ListSalesOrders(ctx context.Context, filter SalesOrderFilter) ([]SalesOrder, error) {
+	if err := validateOffsetLimit(filter.Offset, filter.Limit); err != nil {
+		return nil, err
+	}
+	switch filter.Status {
+	case "", SalesOrderStatusOpen, SalesOrderStatusAllocated, SalesOrderStatusShipped, SalesOrderStatusCancelled:
+	default:
+		return nil, NewValidationError("status", "受注の状態が正しくありません", string(filter.Status))
+	}
+
+	orders, err := m.storage.ListSalesOrders(ctx, filter)
+	if err != nil {
+		return nil, NewStorageError("list_sales_orders", "受注一覧の取得に失敗しました", err)
+	}
+	return orders, nil
+}
+
+// AllocateSalesOrder reserves stock at the ship-from location for the unallocated quantity of every line
+// 受注の各行の未引当の数量を出荷元のロケーションの在庫から予約して引き当て
+//
+// 予約は受注IDを参照番号とし、有効期限は設定しません。受注の全ての予約は一つのトランザクションで作成します。
+// 利用可能数が不足する行がある場合、allowPartial が false なら ErrInsufficientStock を返して何も引き当てず、
+// true なら利用可能な数量だけ引き当てます（引当済みの数量は再度の AllocateSalesOrder で追加できます）。
+// 全ての行を引き当てると allocated、それ以外は open になります。
+// 解除・期限切れになった予約は引当数量から除かれ、再度の引当の対象になります。
+// 出荷確定済み・取消済みの受注は ErrSalesOrderStatus を返します。
+func (m *Manager) AllocateSalesOrder(ctx context.Context, orderID string, allowPartial bool) (_ *SalesOrder, err error) {
+	ctx, finish := m.startOperation(ctx, "allocate_sales_order", attrSalesOrderID.String(orderID))
+	defer finish(&err)
+
+	if orderID == "" {
+		return nil, NewValidationError("sales_order_id", "受注IDが指定されていません", "")
+	}
+
+	var (
+		allocated    *SalesOrder
+		reservations []*Reservation
+		stocks       []*Stock
+		txs          []*Transaction
+	)
+	err = m.withReservationTx(ctx, func(lm *Manager) error {
+		order, err := lm.getOpenSalesOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		held, err := lm.activeSalesOrderReservations(ctx, order.ID)
+		if err != nil {
+			return err
+		}
+
+		reservations, stocks, txs = nil, nil, nil
+		now := time.Now()
+		user := lm.getUserFromContext(ctx)
+		complete := true
+		for i := range order.Lines {
+			line := &order.Lines[i]
+			line.AllocatedQuantity = reservedQuantity(held[line.ItemID])
+			outstanding := line.Unallocated()
+			if outstanding <= 0 {
+				continue
+			}
+
+			var available int64
+			stock, err := lm.getStockForWrite(ctx, line.ItemID, order.LocationID)
+			switch {
+			case errors.Is(err, ErrStockNotFound):
+			case err != nil:
+				return NewStorageError("get_stock", "在庫取得に失敗しました", err)
+			default:
+				// 受注の販売チャネルが予約できる数量（他のチャネルに確保された数量を除く）
+				if available, err = lm.reservableQuantity(ctx, stock, order.Channel); err != nil {
+					return err
+				}
+			}
+			quantity := outstanding
+			if available < quantity {
+				if !allowPartial {
+					return ErrInsufficientStock
+				}
+				complete = false
+				quantity = available
+			}
+			if quantity <= 0 {
+				continue
+			}
+
+			reservation := &Reservation{
+				ID:         NewReservationID(),
+				ItemID:     line.ItemID,
+				LocationID: order.LocationID,
+				Quantity:   quantity,
+				Reference:  order.ID,
+				Status:     ReservationStatusActive,
+				CreatedAt:  now,
+				CreatedBy:  user,
+				Channel:    order.Channel,
+			}
+			updated, tx, err := lm.holdReservation(ctx, reservation, now)
+			if err != nil {
+				return err
+			}
+			line.AllocatedQuantity += quantity
+			reservations = append(reservations, reservation)
+			stocks = append(stocks, updated)
+			txs = append(txs, tx)
+		}
+
+		order.Status = SalesOrderStatusOpen
+		if complete {
+			order.Status = SalesOrderStatusAllocated
+		}
+		if err := lm.updateSalesOrder(ctx, order); err != nil {
+			return err
+		}
+		allocated = order
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range reservations {
+		m.publishReservationCreated(ctx, reservations[i], stocks[i], txs[i])
+	}
+
+	m.log(ctx).Info("受注の引当完了",
+		zap.String("sales_order_id", allocated.ID),
+		zap.String("status", string(allocated.Status)),
+		zap.Int("reservations", len(reservations)),
+	)
+	return allocated, nil
+}
+
+// ShipSalesOrder confirms the shipment of a sales order, converting its reservations to outbound transactions
+// 受注の出荷を確定し、引当の予約を出庫に変換
+//
+// lines は商品ごとの出荷数量で、nil の場合は引当済みの数量を全て出荷します。lines に含まれない商品は出荷しません。
+// 商品ごとに出荷数量を在庫数量から出庫（outbound、参照番号は注文番号、metadata.sales_order_id に受注ID）し、
+// 引当の予約を古い順に出荷数量まで fulfilled（一部のみ出荷する予約は数量を出荷数量に減らす）、残りを解除します。
+// 出荷しなかった数量は行の欠品数量として記録し、受注は shipped になります（追加の出荷はできません）。
+// これらは一つのトランザクションで行います。出荷数量が引当済みの数量を超える場合は ErrInsufficientReservation、
+// 出荷確定済み・取消済みの受注は ErrSalesOrderStatus を返します。
+func (m *Manager) ShipSalesOrder(ctx context.Context, orderID string, lines []ShipmentLine) (_ *SalesOrder, err error) {
+	ctx, finish := m.startOperation(ctx, "ship_sales_order", attrSalesOrderID.String(orderID))
+	defer finish(&err)
+
+	if orderID == "" {
+		return nil, NewValidationError("sales_order_id", "受注IDが指定されていません", "")
+	}
+	if err := ValidateShipmentLines(lines); err != nil {
+		return nil, err
+	}
+
+	var (
+		shipped   *SalesOrder
+		shipments []salesOrderShipment
+	)
+	err = m.withReservationTx(ctx, func(lm *Manager) error {
+		order, err := lm.getOpenSalesOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		held, err := lm.activeSalesOrderReservations(ctx, order.ID)
+		if err != nil {
+			return err
+		}
+
+		// 商品ごとの出荷数量を引当済みの数量と比較
+		quantities := make(map[string]int64, len(order.Lines))
+		for _, line := range order.Lines {
+			quantities[line.ItemID] = 0
+			if lines == nil {
+				quantities[line.ItemID] = reservedQuantity(held[line.ItemID])
+			}
+		}
+		for _, line := range lines {
+			if _, ok := quantities[line.ItemID]; !ok {
+				return NewValidationError("item_id", "受注にない商品が指定されています", line.ItemID)
+			}
+			if line.Quantity > reservedQuantity(held[line.ItemID]) {
+				return ErrInsufficientReservation
+			}
+			quantities[line.ItemID] = line.Quantity
+		}
+		var total int64
+		for _, quantity := range quantities {
+			total += quantity
+		}
+		if total <= 0 {
+			return NewValidationError("lines", "出荷数量の合計は正の値である必要があります", "0")
+		}
+
+		shipments = nil
+		now := time.Now()
+		for i := range order.Lines {
+			line := &order.Lines[i]
+			quantity := quantities[line.ItemID]
+			reserved := reservedQuantity(held[line.ItemID])
+			shipment, err := lm.shipSalesOrderLine(ctx, order, line.ItemID, quantity, held[line.ItemID], now)
+			if err != nil {
+				return err
+			}
+			if shipment != nil {
+				shipments = append(shipments, *shipment)
+			}
+			line.AllocatedQuantity = reserved
+			line.ShippedQuantity = quantity
+			line.ShortQuantity = line.Quantity - quantity
+		}
+
+		order.Status = SalesOrderStatusShipped
+		order.ClosedAt = &now
+		order.ClosedBy = lm.getUserFromContext(ctx)
+		if err := lm.updateSalesOrder(ctx, order); err != nil {
+			return err
+		}
+		shipped = order
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, shipment := range shipments {
+		stock := shipment.stock
+		if m.publisher != nil && shipment.tx != nil {
+			var unitCost float64
+			if item, err := m.storage.GetItem(ctx, stock.ItemID); err == nil {
+				unitCost = item.UnitCost
+			}
+			event := m.newStockChangedEvent(ctx, stock, shipment.oldQuantity, unitCost, "sales_order_shipment", shipped.Reference, shipment.tx.ID)
+			if err := m.publisher.PublishStockChanged(ctx, event); err != nil {
+				m.log(ctx).Error("出荷イベント発行に失敗しました", zap.Error(err))
+			}
+		}
+		m.checkLowStock(ctx, stock.ItemID, stock.LocationID, stock.Quantity)
+		m.evaluateAlertRules(ctx, stock)
+	}
+
+	var quantity, short int64
+	for _, line := range shipped.Lines {
+		quantity += line.ShippedQuantity
+		short += line.ShortQuantity
+	}
+	m.log(ctx).Info("受注の出荷確定完了",
+		zap.String("sales_order_id", shipped.ID),
+		zap.String("reference", shipped.Reference),
+		zap.String("location_id", shipped.LocationID),
+		zap.Int64("quantity", quantity),
+		zap.Int64("short", short),
+	)
+	return shipped, nil
+}
+
+// CancelSalesOrder cancels a sales order that has not been shipped and releases its reservations
+// 出荷確定していない受注を取消し、引当の予約を解除
+func (m *Manager) CancelSalesOrder(ctx context.Context, orderID string) (_ *SalesOrder, err error) {
+	ctx, finish := m.startOperation(ctx, "cancel_sales_order", attrSalesOrderID.String(orderID))
+	defer finish(&err)
+
+	if orderID == "" {
+		return nil, NewValidationError("sales_order_id", "受注IDが指定されていません", "")
+	}
+
+	var (
+		cancelled *SalesOrder
+		released  []*Reservation
+		stocks    []*Stock
+		txs       []*Transaction
+	)
+	err = m.withReservationTx(ctx, func(lm *Manager) error {
+		order, err := lm.getOpenSalesOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		held, err := lm.activeSalesOrderReservations(ctx, order.ID)
+		if err != nil {
+			return err
+		}
+
+		released, stocks, txs = nil, nil, nil
+		now := time.Now()
+		for i := range order.Lines {
+			line := &order.Lines[i]
+			for _, reservation := range held[line.ItemID] {
+				stock, tx, err := lm.endReservation(ctx, reservation, ReservationStatusReleased, now)
+				if err != nil {
+					if errors.Is(err, ErrReservationNotActive) {
+						// 同時に解除・期限切れになった予約はリトライで除外する
+						return ErrVersionMismatch
+					}
+					return err
+				}
+				released = append(released, reservation)
+				stocks = append(stocks, stock)
+				txs = append(txs, tx)
+			}
+			line.AllocatedQuantity = 0
+		}
+
+		order.Status = SalesOrderStatusCancelled
+		order.ClosedAt = &now
+		order.ClosedBy = lm.getUserFromContext(ctx)
+		if err := lm.updateSalesOrder(ctx, order); err != nil {
+			return err
+		}
+		cancelled = order
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range released {
+		m.publishReservationEnded(ctx, released[i], stocks[i], txs[i])
+	}
+
+	m.log(ctx).Info("受注取消完了",
+		zap.String("sales_order_id", cancelled.ID),
+		zap.Int("released_reservations", len(released)),
+	)
+	return cancelled, nil
+}
+
+// GetSalesOrderStockStatus reports what is allocated to a sales order and whether the rest could be allocated now
+// 受注の引当状況と、未引当の数量を出荷元の現在の利用可能数で引き当てられるかを取得
+//
+// 引当数量は受注の有効な予約の合計です。出荷確定済み・取消済みの受注は未引当の数量を0とします。
+func (m *Manager) GetSalesOrderStockStatus(ctx context.Context, orderID string) (*SalesOrderStockStatus, error) {
+	order, err := m.GetSalesOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	held, err := m.activeSalesOrderReservations(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	closed := order.Status == SalesOrderStatusShipped || order.Status == SalesOrderStatusCancelled
+	status := &SalesOrderStockStatus{
+		SalesOrderID:   order.ID,
+		Status:         order.Status,
+		LocationID:     order.LocationID,
+		FullyAllocated: true,
+		Fulfillable:    true,
+		Lines:          make([]SalesOrderLineStockStatus, 0, len(order.Lines)),
+	}
+	for _, line := range order.Lines {
+		lineStatus := SalesOrderLineStockStatus{
+			ItemID:    line.ItemID,
+			Quantity:  line.Quantity,
+			Allocated: reservedQuantity(held[line.ItemID]),
+			Shipped:   line.ShippedQuantity,
+			Short:     line.ShortQuantity,
+		}
+		if !closed {
+			lineStatus.Unallocated = line.Quantity - lineStatus.Allocated
+		}
+
+		stock, err := m.storage.GetStock(ctx, line.ItemID, order.LocationID)
+		switch {
+		case errors.Is(err, ErrStockNotFound):
+		case err != nil:
+			return nil, NewStorageError("get_stock", "在庫取得に失敗しました", err)
+		default:
+			lineStatus.Available = stock.Available
+		}
+
+		if lineStatus.Unallocated > 0 {
+			status.FullyAllocated = false
+			if lineStatus.Unallocated > lineStatus.Available {
+				status.Fulfillable = false
+			}
+		}
+		status.Lines = append(status.Lines, lineStatus)
+	}
+	return status, nil
+}
+
+// shipSalesOrderLine ships one line of a sales order from the reservations held for it
+// 受注の1行を引当の予約から出荷（トランザクション内で呼び出すこと）
+//
+// 在庫数量から出荷数量を、予約数量から予約の合計を減算し、出庫と解除した数量の予約解除のトランザクションを記録します。
+// 予約も出荷もない行は在庫を変更せず nil を返します。
+func (m *Manager) shipSalesOrderLine(ctx context.Context, order *SalesOrder, itemID string, quantity int64, reservations []*Reservation, now time.Time) (*salesOrderShipment, error) {
+	reserved := reservedQuantity(reservations)
+	if reserved == 0 {
+		return nil, nil
+	}
+
+	stock, err := m.getStockForWrite(ctx, itemID, order.LocationID)
+	if err != nil {
+		return nil, NewStorageError("get_stock", "在庫取得に失敗しました", err)
+	}
+	if stock.Reserved < reserved || stock.Quantity < quantity {
+		return nil, ErrInsufficientReservation
+	}
+
+	// 出荷しない数量は予約を解除して利用可能数に戻す
+	shipment := &salesOrderShipment{stock: stock, oldQuantity: stock.Quantity}
+	stock.Quantity -= quantity
+	stock.Reserved -= reserved
+	stock.Version++
+	stock.UpdatedAt = now
+	stock.UpdatedBy = m.getUserFromContext(ctx)
+	stock.CalculateAvailable()
+	if err := m.storage.UpdateStock(ctx, stock); err != nil {
+		return nil, NewStorageError("update_stock", "在庫更新に失敗しました", err)
+	}
+
+	remaining := quantity
+	for _, reservation := range reservations {
+		switch {
+		case remaining >= reservation.Quantity:
+			reservation.Status = ReservationStatusFulfilled
+			remaining -= reservation.Quantity
+		case remaining > 0:
+			reservation.Quantity = remaining
+			reservation.Status = ReservationStatusFulfilled
+			remaining = 0
+		default:
+			reservation.Status = ReservationStatusReleased
+		}
+		reservation.ReleasedAt = &now
+		if err := m.storage.UpdateReservation(ctx, reservation); err != nil {
+			if errors.Is(err, ErrReservationNotActive) {
+				// 同時に解除・期限切れになった予約はリトライで除外する
+				return nil, ErrVersionMismatch
+			}
+			return nil, NewStorageError("update_reservation", "予約の更新に失敗しました", err)
+		}
+	}
+
+	if quantity > 0 {
+		locationID := order.LocationID
+		shipment.tx = &Transaction{
+			ID:           NewTransactionID(),
+			Type:         TransactionTypeOutbound,
+			ItemID:       itemID,
+			FromLocation: &locationID,
+			Quantity:     quantity,
+			Reference:    order.Reference,
+			Metadata:     transactionMetadata(ctx, channelMetadata(order.Channel, map[string]string{MetadataSalesOrderID: order.ID})),
+			CreatedAt:    now,
+			CreatedBy:    stock.UpdatedBy,
+		}
+		if err := m.storage.CreateTransaction(ctx, shipment.tx); err != nil {
+			return nil, NewStorageError("create_transaction", "トランザクション記録に失敗しました", err)
+		}
+	}
+	if unshipped := reserved - quantity; unshipped > 0 {
+		if _, err := m.recordReservationTransaction(ctx, TransactionTypeRelease, "", order.Channel, itemID, order.LocationID, unshipped, order.ID, now); err != nil {
+			return nil, err
+		}
+	}
+	return shipment, nil
+}
+
+// getOpenSalesOrder retrieves a sales order that has not been shipped or cancelled
+// 出荷確定・取消していない受注を取得
+func (m *Manager) getOpenSalesOrder(ctx context.Context, orderID string) (*SalesOrder, error) {
+	order, err := m.GetSalesOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != SalesOrderStatusOpen && order.Status != SalesOrderStatusAllocated {
+		return nil, ErrSalesOrderStatus
+	}
+	return order, nil
+}
+
+// activeSalesOrderReservations returns the active reservations of a sales order by item, oldest first
+// 受注の有効な予約を商品ごとに作成日時の古い順で返す
+func (m *Manager) activeSalesOrderReservations(ctx context.Context, orderID string) (map[string][]*Reservation, error) {
+	reservations, err := m.storage.ListReservations(ctx, ReservationFilter{Reference: orderID, Status: ReservationStatusActive})
+	if err != nil {
+		return nil, NewStorageError("list_reservations", "予約一覧の取得に失敗しました", err)
+	}
+
+	sort.Slice(reservations, func(i, j int) bool {
+		if !reservations[i].CreatedAt.Equal(reservations[j].CreatedAt) {
+			return reservations[i].CreatedAt.Before(reservations[j].CreatedAt)
+		}
+		return reservations[i].ID < reservations[j].ID
+	})
+	held := make(map[string][]*Reservation)
+	for i := range reservations {
+		held[reservations[i].ItemID] = append(held[reservations[i].ItemID], &reservations[i])
+	}
+	return held, nil
+}
+
+// reservedQuantity returns the total quantity of reservations
+// 予約の数量の合計を返す
+func reservedQuantity(reservations []*Reservation) int64 {
+	var total int64
+	for _, reservation := range reservations {
+		total += reservation.Quantity
+	}
+	return total
+}
+
+// updateSalesOrder increments the version of a sales order and saves it
+// 受注のバージョンを進めて保存（他の操作と競合した場合は ErrVersionMismatch でリトライ）
+func (m *Manager) updateSalesOrder(ctx context.Context, order *SalesOrder) error {
+	order.Version++
+	if err := m.storage.UpdateSalesOrder(ctx, order); err != nil {
+		if errors.Is(err, ErrSalesOrderNotFound) || errors.Is(err, ErrVersionMismatch) {
+			return err
+		}
+		return NewStorageError("update_sales_order", "受注の更新に失敗しました", err)
+	}
+	return nil
+}
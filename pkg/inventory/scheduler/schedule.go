@@ -0,0 +1,179 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule computes the run times of a job
+// ジョブの実行日時を計算するスケジュール
+type Schedule interface {
+	// Next returns the first run time after t
+	// t より後の最初の実行日時を返す
+	Next(t time.Time) time.Time
+}
+
+// Every returns a schedule that runs every interval
+// interval ごとに実行するスケジュールを返す
+func Every(interval time.Duration) Schedule {
+	return every(interval)
+}
+
+// every runs a job at a fixed interval from the previous run
+// 前回の実行から一定間隔で実行するスケジュール
+type every time.Duration
+
+func (e every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+// Parse parses a schedule spec
+// スケジュールの指定を解析
+//
+// 次の形式を指定できます。cron式は呼び出し時のタイムゾーン（time.Local）で評価します。
+//   - "@every 15m"（time.ParseDuration の形式の間隔）
+//   - "@hourly"・"@daily"（"@midnight"）・"@weekly"・"@monthly"
+//   - 5項目のcron式 "分 時 日 月 曜日"（*・数値・範囲 1-5・リスト 1,15・間隔 */10 に対応。曜日の0は日曜日）
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "@every ") {
+		interval, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("スケジュールの間隔が無効です: %s", spec)
+		}
+		return Every(interval), nil
+	}
+
+	switch spec {
+	case "@hourly":
+		spec = "0 * * * *"
+	case "@daily", "@midnight":
+		spec = "0 0 * * *"
+	case "@weekly":
+		spec = "0 0 * * 0"
+	case "@monthly":
+		spec = "0 0 1 * *"
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron式は5項目（分 時 日 月 曜日）で指定してください: %s", spec)
+	}
+	var c cron
+	var err error
+	if c.minute, err = parseField(fields[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("cron式の分が無効です: %w", err)
+	}
+	if c.hour, err = parseField(fields[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("cron式の時が無効です: %w", err)
+	}
+	if c.dom, err = parseField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("cron式の日が無効です: %w", err)
+	}
+	if c.month, err = parseField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("cron式の月が無効です: %w", err)
+	}
+	if c.dow, err = parseField(fields[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("cron式の曜日が無効です: %w", err)
+	}
+	// 7も日曜日として扱う
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+	c.domAny = strings.HasPrefix(fields[2], "*")
+	c.dowAny = strings.HasPrefix(fields[4], "*")
+	return c, nil
+}
+
+// cron is a parsed five-field cron expression; each field is a bit set of the allowed values
+// 解析した5項目のcron式（各項目は許可する値のビット集合）
+type cron struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+}
+
+// maxCronSearch bounds the search for the next run of an expression that never matches (e.g. 31 February)
+// 一致しない式（2月31日など）の次回実行日時を探索する上限
+const maxCronSearch = 5 * 366 * 24 * time.Hour
+
+// Next returns the first minute after t matching the expression, or the zero time if none exists
+// t より後で式に一致する最初の分を返す（存在しない場合はゼロ値）
+func (c cron) Next(t time.Time) time.Time {
+	next := t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxCronSearch)
+	for next.Before(limit) {
+		switch {
+		case c.month&(1<<uint(next.Month())) == 0:
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, next.Location())
+		case !c.dayMatches(next):
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, next.Location())
+		case c.hour&(1<<uint(next.Hour())) == 0:
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, next.Location())
+		case c.minute&(1<<uint(next.Minute())) == 0:
+			next = next.Add(time.Minute)
+		default:
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// dayMatches applies the cron rule that day of month and day of week match either when both are restricted
+// 日と曜日の両方を指定した場合はどちらかに一致すれば実行する（cronの規則）
+func (c cron) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domAny || c.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// parseField parses one comma-separated cron field into a bit set
+// カンマ区切りのcron式の1項目をビット集合に変換
+func parseField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		i := strings.Index(part, "/")
+		if i >= 0 {
+			var err error
+			rangePart = part[:i]
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("間隔が無効です: %s", part)
+			}
+		}
+
+		low, high := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err1, err2 error
+			low, err1 = strconv.Atoi(bounds[0])
+			high, err2 = strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil {
+				return 0, fmt.Errorf("範囲が無効です: %s", part)
+			}
+		default:
+			value, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("値が無効です: %s", part)
+			}
+			low = value
+			// "5/15" は5から上限まで15ごと
+			if i < 0 {
+				high = value
+			}
+		}
+		if low < min || high > max || low > high {
+			return 0, fmt.Errorf("値は%d〜%dの範囲で指定してください: %s", min, max, part)
+		}
+		for v := low; v <= high; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
@@ -0,0 +1,275 @@
+// Package scheduler runs periodic inventory jobs such as expiry scans, low stock sweeps and stock snapshots
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrJobNotFound is returned when no job is registered with a name
+	// 指定した名前のジョブが登録されていない場合のエラー
+	ErrJobNotFound = errors.New("ジョブが見つかりません")
+
+	// ErrJobRunning is returned when triggering a job that is running or already triggered
+	// 実行中・実行待ちのジョブを実行しようとした場合のエラー
+	ErrJobRunning = errors.New("ジョブは実行中です")
+
+	// ErrSchedulerStopped is returned when triggering a job of a scheduler that is not running
+	// 開始前・停止後のスケジューラーのジョブを実行しようとした場合のエラー
+	ErrSchedulerStopped = errors.New("スケジューラーは停止しています")
+)
+
+// JobFunc runs a job once and returns the number of records it processed
+// ジョブを1回実行し、処理したレコード数（作成したアラート数など）を返す関数
+type JobFunc func(ctx context.Context) (int, error)
+
+// JobStatus is the schedule and last result of a job
+// ジョブのスケジュールと前回の実行結果
+type JobStatus struct {
+	Name           string     `json:"name"`                       // ジョブ名
+	Schedule       string     `json:"schedule"`                   // スケジュールの指定
+	Running        bool       `json:"running"`                    // 実行中か
+	NextRunAt      *time.Time `json:"next_run_at,omitempty"`      // 次回の実行予定日時
+	LastStartedAt  *time.Time `json:"last_started_at,omitempty"`  // 前回の開始日時
+	LastFinishedAt *time.Time `json:"last_finished_at,omitempty"` // 前回の終了日時
+	LastDurationMs int64      `json:"last_duration_ms"`           // 前回の所要時間（ミリ秒）
+	LastProcessed  int        `json:"last_processed"`             // 前回処理したレコード数
+	LastError      string     `json:"last_error,omitempty"`       // 前回失敗した場合のエラー
+	Runs           int64      `json:"runs"`                       // 起動後の実行回数
+	Failures       int64      `json:"failures"`                   // 起動後の失敗回数
+}
+
+// job is a registered job and its status
+// 登録したジョブと実行状況
+type job struct {
+	name     string
+	schedule Schedule
+	run      JobFunc
+	trigger  chan struct{} // 手動実行の要求（Trigger）
+
+	mu     sync.Mutex // statusを保護
+	status JobStatus
+}
+
+// Scheduler runs registered jobs on their schedules
+// 登録したジョブをスケジュールに従って実行するスケジューラー
+//
+// ジョブごとにゴルーチンで実行し、同じジョブが重複して実行されることはありません。
+// 間隔指定（"@every"）のスケジュールは前回の終了から次回までの間隔です。
+// 実行状況はプロセス内でのみ保持するため、複数のプロセスで同じジョブを実行しないでください。
+type Scheduler struct {
+	logger *zap.Logger
+
+	mu      sync.Mutex // jobs・実行状態を保護
+	jobs    []*job
+	running bool
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// New creates a scheduler without jobs
+// ジョブのないスケジューラーを作成
+func New(logger *zap.Logger) *Scheduler {
+	return &Scheduler{logger: logger}
+}
+
+// Add registers a job with a schedule spec (see Parse)
+// スケジュールの指定（Parse を参照）でジョブを登録
+//
+// 開始後は登録できません。
+func (s *Scheduler) Add(name, spec string, run JobFunc) error {
+	schedule, err := Parse(spec)
+	if err != nil {
+		return fmt.Errorf("ジョブ %s: %w", name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running {
+		return fmt.Errorf("ジョブ %s: 開始後のスケジューラーには登録できません", name)
+	}
+	for _, j := range s.jobs {
+		if j.name == name {
+			return fmt.Errorf("ジョブ %s は既に登録されています", name)
+		}
+	}
+	s.jobs = append(s.jobs, &job{
+		name:     name,
+		schedule: schedule,
+		run:      run,
+		trigger:  make(chan struct{}, 1),
+		status:   JobStatus{Name: name, Schedule: spec},
+	})
+	return nil
+}
+
+// Start starts running the registered jobs
+// 登録したジョブの実行を開始
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.running = true
+	s.cancel = cancel
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, j)
+	}
+}
+
+// Stop cancels running jobs and waits for them to return
+// 実行中のジョブをキャンセルし、終了を待機
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	if s.running {
+		s.running = false
+		s.cancel()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// Trigger runs a job now without changing its schedule
+// スケジュールを変更せずにジョブを今すぐ実行
+//
+// 実行の完了は待ちません。結果は Status で確認します。
+func (s *Scheduler) Trigger(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.running {
+		return ErrSchedulerStopped
+	}
+
+	for _, j := range s.jobs {
+		if j.name != name {
+			continue
+		}
+		j.mu.Lock()
+		running := j.status.Running
+		j.mu.Unlock()
+		if running {
+			return ErrJobRunning
+		}
+		select {
+		case j.trigger <- struct{}{}:
+			return nil
+		default:
+			return ErrJobRunning
+		}
+	}
+	return ErrJobNotFound
+}
+
+// Status returns the status of every job in registration order
+// 全てのジョブの実行状況を登録順に返す
+func (s *Scheduler) Status() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]JobStatus, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		j.mu.Lock()
+		statuses = append(statuses, j.status)
+		j.mu.Unlock()
+	}
+	return statuses
+}
+
+// loop runs a job on its schedule until ctx is cancelled
+// ctxがキャンセルされるまでスケジュールに従ってジョブを実行
+func (s *Scheduler) loop(ctx context.Context, j *job) {
+	defer s.wg.Done()
+
+	for {
+		next := j.schedule.Next(time.Now())
+		var fire <-chan time.Time
+		var timer *time.Timer
+		if !next.IsZero() {
+			timer = time.NewTimer(time.Until(next))
+			fire = timer.C
+		}
+		j.mu.Lock()
+		j.status.NextRunAt = nil
+		if !next.IsZero() {
+			j.status.NextRunAt = &next
+		}
+		j.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		case <-fire:
+		case <-j.trigger:
+			if timer != nil {
+				timer.Stop()
+			}
+		}
+		s.runJob(ctx, j)
+	}
+}
+
+// runJob runs a job once and records its result
+// ジョブを1回実行して結果を記録
+func (s *Scheduler) runJob(ctx context.Context, j *job) {
+	started := time.Now()
+	j.mu.Lock()
+	j.status.Running = true
+	j.status.LastStartedAt = &started
+	j.mu.Unlock()
+
+	processed, err := s.call(ctx, j)
+
+	finished := time.Now()
+	j.mu.Lock()
+	j.status.Running = false
+	j.status.LastFinishedAt = &finished
+	j.status.LastDurationMs = finished.Sub(started).Milliseconds()
+	j.status.LastProcessed = processed
+	j.status.LastError = ""
+	j.status.Runs++
+	if err != nil {
+		j.status.LastError = err.Error()
+		j.status.Failures++
+	}
+	j.mu.Unlock()
+
+	switch {
+	case err != nil && ctx.Err() == nil:
+		s.logger.Warn("定期ジョブの実行に失敗しました",
+			zap.String("job", j.name),
+			zap.Duration("duration", finished.Sub(started)),
+			zap.Error(err),
+		)
+	case err == nil:
+		s.logger.Debug("定期ジョブ実行完了",
+			zap.String("job", j.name),
+			zap.Int("processed", processed),
+			zap.Duration("duration", finished.Sub(started)),
+		)
+	}
+}
+
+// call runs the job function, turning a panic into an error so that one job cannot stop the others
+// ジョブの関数を実行（1つのジョブが他のジョブを停止させないよう、パニックはエラーとして記録）
+func (s *Scheduler) call(ctx context.Context, j *job) (processed int, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("ジョブがパニックしました: %v", p)
+		}
+	}()
+	return j.run(ctx)
+}
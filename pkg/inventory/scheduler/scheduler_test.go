@@ -0,0 +1,169 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestParse はスケジュールの指定の解析と次回実行日時のテスト
+func TestParse(t *testing.T) {
+	// 2024-01-10（水）09:30:15
+	base := time.Date(2024, 1, 10, 9, 30, 15, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"@every 15m", base.Add(15 * time.Minute)},
+		{"@hourly", time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"*/20 * * * *", time.Date(2024, 1, 10, 9, 40, 0, 0, time.UTC)},
+		{"0 6 * * *", time.Date(2024, 1, 11, 6, 0, 0, 0, time.UTC)},
+		{"30 2 * * 1-5", time.Date(2024, 1, 11, 2, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)},
+		{"0 12 1,15 * *", time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)},
+		// 日と曜日の両方を指定した場合はどちらかに一致
+		{"0 0 13 * 5", time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := Parse(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, schedule.Next(base))
+		})
+	}
+
+	// 一致しない式は次回の実行日時がない
+	never, err := Parse("0 0 31 2 *")
+	require.NoError(t, err)
+	assert.True(t, never.Next(base).IsZero())
+
+	for _, spec := range []string{"", "@every", "@every -1m", "@yearly", "* * * *", "60 * * * *", "* 24 * * *", "0 0 0 * *", "0 0 * 13 *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		_, err := Parse(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+// waitFor polls cond until it holds or the timeout expires
+// 条件が成立するまで待機
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("条件が成立しませんでした")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// TestScheduler_RunsJobs はスケジュールに従ったジョブの実行と実行状況のテスト
+func TestScheduler_RunsJobs(t *testing.T) {
+	s := New(zap.NewNop())
+
+	var runs atomic.Int32
+	require.NoError(t, s.Add("sweep", "@every 10ms", func(ctx context.Context) (int, error) {
+		return int(runs.Add(1)), nil
+	}))
+	require.NoError(t, s.Add("failing", "@every 10ms", func(ctx context.Context) (int, error) {
+		return 0, errors.New("集計に失敗しました")
+	}))
+	require.NoError(t, s.Add("panicking", "@every 10ms", func(ctx context.Context) (int, error) {
+		panic("予期しないエラー")
+	}))
+	assert.Error(t, s.Add("sweep", "@hourly", nil), "同じ名前のジョブは登録できない")
+	assert.Error(t, s.Add("invalid", "every 10ms", nil))
+
+	s.Start()
+	assert.Error(t, s.Add("late", "@hourly", nil), "開始後は登録できない")
+	waitFor(t, func() bool {
+		for _, status := range s.Status() {
+			if status.Runs < 2 {
+				return false
+			}
+		}
+		return true
+	})
+	s.Stop()
+
+	statuses := s.Status()
+	require.Len(t, statuses, 3)
+	assert.Equal(t, "sweep", statuses[0].Name)
+	assert.Equal(t, "@every 10ms", statuses[0].Schedule)
+	assert.Zero(t, statuses[0].Failures)
+	assert.Empty(t, statuses[0].LastError)
+	assert.Positive(t, statuses[0].LastProcessed)
+	assert.NotNil(t, statuses[0].LastFinishedAt)
+	assert.NotNil(t, statuses[0].NextRunAt)
+
+	assert.Equal(t, statuses[1].Runs, statuses[1].Failures)
+	assert.Equal(t, "集計に失敗しました", statuses[1].LastError)
+	assert.Contains(t, statuses[2].LastError, "パニック")
+}
+
+// TestScheduler_Trigger はジョブの手動実行のテスト
+func TestScheduler_Trigger(t *testing.T) {
+	s := New(zap.NewNop())
+
+	release := make(chan struct{})
+	var runs atomic.Int32
+	require.NoError(t, s.Add("snapshot", "@daily", func(ctx context.Context) (int, error) {
+		runs.Add(1)
+		select {
+		case <-release:
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+		return 42, nil
+	}))
+
+	assert.ErrorIs(t, s.Trigger("snapshot"), ErrSchedulerStopped)
+
+	s.Start()
+	defer s.Stop()
+
+	assert.ErrorIs(t, s.Trigger("unknown"), ErrJobNotFound)
+	require.NoError(t, s.Trigger("snapshot"))
+	waitFor(t, func() bool { return s.Status()[0].Running })
+	assert.ErrorIs(t, s.Trigger("snapshot"), ErrJobRunning)
+
+	close(release)
+	waitFor(t, func() bool { return s.Status()[0].Runs == 1 })
+	status := s.Status()[0]
+	assert.False(t, status.Running)
+	assert.Equal(t, 42, status.LastProcessed)
+	assert.Equal(t, int32(1), runs.Load())
+	// 手動実行はスケジュールを変更しない
+	require.NotNil(t, status.NextRunAt)
+	assert.True(t, status.NextRunAt.After(time.Now()))
+}
+
+// TestScheduler_StopCancelsRunningJob は停止時に実行中のジョブをキャンセルするテスト
+func TestScheduler_StopCancelsRunningJob(t *testing.T) {
+	s := New(zap.NewNop())
+
+	started := make(chan struct{})
+	require.NoError(t, s.Add("slow", "@every 1ms", func(ctx context.Context) (int, error) {
+		close(started)
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}))
+
+	s.Start()
+	<-started
+	s.Stop()
+
+	status := s.Status()[0]
+	assert.False(t, status.Running)
+	assert.Equal(t, context.Canceled.Error(), status.LastError)
+}
@@ -0,0 +1,67 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var _ SnapshotManager = (*Manager)(nil)
+
+// TakeStockSnapshot copies every stock record into a new snapshot
+// 全ての在庫記録を複製してスナップショットを作成
+//
+// 月末在庫や在庫推移の集計用に、定期ジョブ（stock_snapshot）から呼び出すことを想定しています。
+func (m *Manager) TakeStockSnapshot(ctx context.Context) (_ *StockSnapshot, err error) {
+	ctx, finish := m.startOperation(ctx, "take_stock_snapshot")
+	defer finish(&err)
+
+	snapshot := &StockSnapshot{
+		ID:      NewSnapshotID(),
+		TakenAt: time.Now(),
+		TakenBy: m.getUserFromContext(ctx),
+	}
+	if err := m.storage.CreateStockSnapshot(ctx, snapshot); err != nil {
+		return nil, NewStorageError("create_stock_snapshot", "在庫スナップショットの作成に失敗しました", err)
+	}
+
+	m.log(ctx).Info("在庫スナップショット作成完了",
+		zap.String("snapshot_id", snapshot.ID),
+		zap.Int("stocks", snapshot.StockCount),
+		zap.Int64("total_quantity", snapshot.TotalQuantity),
+	)
+	return snapshot, nil
+}
+
+// ListStockSnapshots lists stock snapshots, newest first
+// スナップショットを取得日時の新しい順に取得
+func (m *Manager) ListStockSnapshots(ctx context.Context, offset, limit int) ([]StockSnapshot, error) {
+	if err := validateOffsetLimit(offset, limit); err != nil {
+		return nil, err
+	}
+
+	snapshots, err := m.storage.ListStockSnapshots(ctx, offset, limit)
+	if err != nil {
+		return nil, NewStorageError("list_stock_snapshots", "在庫スナップショット一覧の取得に失敗しました", err)
+	}
+	return snapshots, nil
+}
+
+// GetStockSnapshotLines retrieves the stock records of a snapshot, optionally narrowed to a location
+// スナップショットの在庫記録を取得（locationIDが空の場合は全ロケーション）
+func (m *Manager) GetStockSnapshotLines(ctx context.Context, snapshotID, locationID string) ([]StockSnapshotLine, error) {
+	if snapshotID == "" {
+		return nil, NewValidationError("snapshot_id", "スナップショットIDが指定されていません", "")
+	}
+
+	lines, err := m.storage.GetStockSnapshotLines(ctx, snapshotID, locationID)
+	if err != nil {
+		if errors.Is(err, ErrSnapshotNotFound) {
+			return nil, ErrSnapshotNotFound
+		}
+		return nil, NewStorageError("get_stock_snapshot_lines", "在庫スナップショットの在庫記録の取得に失敗しました", err)
+	}
+	return lines, nil
+}
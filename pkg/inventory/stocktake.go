@@ -0,0 +1,504 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// stocktakeAppliedNote is the note recorded on the discrepancy alerts resolved by ApplyStocktake
+// ApplyStocktake で解決した棚卸差異アラートに記録するメモ
+const stocktakeAppliedNote = "棚卸差異を調整しました"
+
+var _ StocktakeManager = (*Manager)(nil)
+
+// CreateStocktake creates the count sheet of a location
+// ロケーションの棚卸（集計表）を作成
+//
+// LocationID・ABCClass（任意）・Reference（任意）を指定します。作成時のロケーションの在庫記録
+// （ABCClass を指定した場合は CalculateABCClassification でそのクラスに分類した商品のみ）を行とし、
+// 帳簿在庫数量に現在の在庫数量を設定します。ID・状態・作成日時・作成者は設定されます。
+func (m *Manager) CreateStocktake(ctx context.Context, stocktake *Stocktake) (err error) {
+	ctx, finish := m.startOperation(ctx, "create_stocktake", attrLocationID.String(stocktake.LocationID), attrReference.String(stocktake.Reference))
+	defer finish(&err)
+
+	if err := ValidateLocationID(stocktake.LocationID); err != nil {
+		return err
+	}
+	switch stocktake.ABCClass {
+	case "", "A", "B", "C":
+	default:
+		return NewValidationError("abc_class", "ABCクラスは A・B・C のいずれかを指定してください", stocktake.ABCClass)
+	}
+	if err := ValidateReference(stocktake.Reference); err != nil {
+		return err
+	}
+	if _, err := m.storage.GetLocation(ctx, stocktake.LocationID); err != nil {
+		if errors.Is(err, ErrLocationNotFound) {
+			return ErrLocationNotFound
+		}
+		return NewStorageError("get_location", "ロケーション取得に失敗しました", err)
+	}
+
+	var classes map[string]string
+	if stocktake.ABCClass != "" {
+		classes, err = NewAnalyticsEngine(m.storage, m.logger).CalculateABCClassification(ctx, stocktake.LocationID)
+		if err != nil {
+			return err
+		}
+	}
+
+	stocktake.ID = NewStocktakeID()
+	stocktake.Status = StocktakeStatusOpen
+	stocktake.CreatedAt = time.Now()
+	stocktake.CreatedBy = m.getUserFromContext(ctx)
+	stocktake.SubmittedAt, stocktake.SubmittedBy = nil, ""
+	stocktake.ClosedAt, stocktake.ClosedBy = nil, ""
+	if stocktake.Reference == "" {
+		stocktake.Reference = stocktake.ID
+	}
+
+	stocktake.Lines = make([]StocktakeLine, 0)
+	err = m.storage.ForEachStockByLocation(ctx, stocktake.LocationID, func(stock Stock) error {
+		if classes != nil && classes[stock.ItemID] != stocktake.ABCClass {
+			return nil
+		}
+		stocktake.Lines = append(stocktake.Lines, StocktakeLine{
+			StocktakeID: stocktake.ID,
+			ItemID:      stock.ItemID,
+			ExpectedQty: stock.Quantity,
+		})
+		return nil
+	})
+	if err != nil {
+		return NewStorageError("list_stock_by_location", "ロケーション在庫取得に失敗しました", err)
+	}
+
+	if err := m.storage.CreateStocktake(ctx, stocktake); err != nil {
+		return NewStorageError("create_stocktake", "棚卸作成に失敗しました", err)
+	}
+
+	m.log(ctx).Info("棚卸作成完了",
+		zap.String("stocktake_id", stocktake.ID),
+		zap.String("location_id", stocktake.LocationID),
+		zap.String("abc_class", stocktake.ABCClass),
+		zap.Int("lines", len(stocktake.Lines)),
+	)
+	return nil
+}
+
+// GetStocktake retrieves a stocktake with its lines
+// 棚卸を行とともに取得
+func (m *Manager) GetStocktake(ctx context.Context, stocktakeID string) (*Stocktake, error) {
+	if stocktakeID == "" {
+		return nil, NewValidationError("stocktake_id", "棚卸IDが指定されていません", "")
+	}
+
+	stocktake, err := m.storage.GetStocktake(ctx, stocktakeID)
+	if err != nil {
+		if errors.Is(err, ErrStocktakeNotFound) {
+			return nil, ErrStocktakeNotFound
+		}
+		return nil, NewStorageError("get_stocktake", "棚卸の取得に失敗しました", err)
+	}
+	return stocktake, nil
+}
+
+// ListStocktakes lists stocktakes matching a filter, newest first, without their lines
+// 条件に一致する棚卸を作成日時の新しい順に取得（行は含まない）
+func (m *Manager) ListStocktakes(ctx context.Context, filter StocktakeFilter) ([]Stocktake, error) {
+	if err := validateOffsetLimit(filter.Offset, filter.Limit); err != nil {
+		return nil, err
+	}
+	switch filter.Status {
+	case "", StocktakeStatusOpen, StocktakeStatusSubmitted, StocktakeStatusApplied, StocktakeStatusCancelled:
+	default:
+		return nil, NewValidationError("status", "棚卸の状態が正しくありません", string(filter.Status))
+	}
+
+	stocktakes, err := m.storage.ListStocktakes(ctx, filter)
+	if err != nil {
+		return nil, NewStorageError("list_stocktakes", "棚卸一覧の取得に失敗しました", err)
+	}
+	return stocktakes, nil
+}
+
+// RecordStocktakeCounts records counted quantities on an open stocktake
+// 集計中の棚卸に実数を記録
+//
+// 帳簿在庫数量は記録時点の在庫数量に更新し、差異（実数 - 帳簿在庫数量）を計算します。
+// 集計表にない商品（作成後に入庫した商品など）は行を追加します。同じ商品を再度記録した場合は上書きします。
+func (m *Manager) RecordStocktakeCounts(ctx context.Context, stocktakeID string, counts []StocktakeCount) (_ *Stocktake, err error) {
+	ctx, finish := m.startOperation(ctx, "record_stocktake_counts", attrStocktakeID.String(stocktakeID))
+	defer finish(&err)
+
+	if len(counts) == 0 {
+		return nil, NewValidationError("counts", "実数が指定されていません", "")
+	}
+	seen := make(map[string]bool, len(counts))
+	for _, count := range counts {
+		if err := ValidateItemID(count.ItemID); err != nil {
+			return nil, err
+		}
+		if count.CountedQty < 0 {
+			return nil, NewValidationError("counted_qty", "実数は0以上で指定してください", fmt.Sprintf("%d", count.CountedQty))
+		}
+		if seen[count.ItemID] {
+			return nil, NewValidationError("item_id", "同じ商品の実数が複数指定されています", count.ItemID)
+		}
+		seen[count.ItemID] = true
+	}
+
+	stocktake, err := m.GetStocktake(ctx, stocktakeID)
+	if err != nil {
+		return nil, err
+	}
+	if stocktake.Status != StocktakeStatusOpen {
+		return nil, ErrStocktakeStatus
+	}
+
+	now := time.Now()
+	userID := m.getUserFromContext(ctx)
+	lines := make([]StocktakeLine, 0, len(counts))
+	for _, count := range counts {
+		if _, err := m.storage.GetItem(ctx, count.ItemID); err != nil {
+			if errors.Is(err, ErrItemNotFound) {
+				return nil, ErrItemNotFound
+			}
+			return nil, NewStorageError("get_item", "商品取得に失敗しました", err)
+		}
+
+		var expected int64
+		stock, err := m.storage.GetStock(WithPrimaryRead(ctx), count.ItemID, stocktake.LocationID)
+		switch {
+		case err == nil:
+			expected = stock.Quantity
+		case !errors.Is(err, ErrStockNotFound):
+			return nil, NewStorageError("get_stock", "在庫取得に失敗しました", err)
+		}
+
+		counted := count.CountedQty
+		countedAt := now
+		lines = append(lines, StocktakeLine{
+			StocktakeID: stocktake.ID,
+			ItemID:      count.ItemID,
+			ExpectedQty: expected,
+			CountedQty:  &counted,
+			Variance:    counted - expected,
+			CountedAt:   &countedAt,
+			CountedBy:   userID,
+		})
+	}
+
+	update := *stocktake
+	update.Lines = lines
+	if err := m.storage.UpdateStocktake(ctx, &update, StocktakeStatusOpen); err != nil {
+		if errors.Is(err, ErrStocktakeNotFound) || errors.Is(err, ErrStocktakeStatus) {
+			return nil, err
+		}
+		return nil, NewStorageError("update_stocktake", "棚卸の更新に失敗しました", err)
+	}
+
+	m.log(ctx).Info("棚卸の実数記録完了",
+		zap.String("stocktake_id", stocktake.ID),
+		zap.Int("counts", len(lines)),
+	)
+	return m.GetStocktake(ctx, stocktake.ID)
+}
+
+// SubmitStocktake fixes the variances of an open stocktake and raises a discrepancy alert for each of them
+// 集計中の棚卸の差異を確定し、差異のある行ごとに棚卸差異アラートを作成
+//
+// 実数を記録していない行は調整の対象外です。
+func (m *Manager) SubmitStocktake(ctx context.Context, stocktakeID string) (_ *Stocktake, err error) {
+	ctx, finish := m.startOperation(ctx, "submit_stocktake", attrStocktakeID.String(stocktakeID))
+	defer finish(&err)
+
+	stocktake, err := m.GetStocktake(ctx, stocktakeID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	update := *stocktake
+	update.Status = StocktakeStatusSubmitted
+	update.SubmittedAt = &now
+	update.SubmittedBy = m.getUserFromContext(ctx)
+	update.Lines = nil
+	if err := m.updateStocktakeStatus(ctx, &update, StocktakeStatusOpen); err != nil {
+		return nil, err
+	}
+
+	alerts := 0
+	for _, line := range stocktake.Lines {
+		if !line.Counted() || line.Variance == 0 {
+			continue
+		}
+		alert := &StockAlert{
+			ID:         NewTransactionID(),
+			Type:       AlertTypeDiscrepancy,
+			ItemID:     line.ItemID,
+			LocationID: stocktake.LocationID,
+			CurrentQty: *line.CountedQty,
+			Threshold:  line.ExpectedQty,
+			Message:    fmt.Sprintf("棚卸 %s: 商品 %s のロケーション %s の実数 (%d) が帳簿在庫数量 (%d) と %+d 異なります", stocktake.ID, line.ItemID, stocktake.LocationID, *line.CountedQty, line.ExpectedQty, line.Variance),
+			IsActive:   true,
+			CreatedAt:  now,
+			Severity:   AlertSeverityWarning,
+		}
+		if err := m.storage.CreateAlert(ctx, alert); err != nil {
+			m.log(ctx).Error("棚卸差異アラート作成に失敗しました",
+				zap.String("stocktake_id", stocktake.ID),
+				zap.String("item_id", line.ItemID),
+				zap.Error(err),
+			)
+			continue
+		}
+		m.publishEvent(ctx, EventTypeAlertCreated, line.ItemID, stocktake.LocationID, alert)
+		alerts++
+	}
+
+	m.log(ctx).Info("棚卸の差異確定完了",
+		zap.String("stocktake_id", stocktake.ID),
+		zap.Int("discrepancies", alerts),
+	)
+	return m.GetStocktake(ctx, stocktake.ID)
+}
+
+// ApplyStocktake adjusts the stock by the approved variances of a submitted stocktake in a single transaction
+// 差異確定済みの棚卸の承認した差異を単一トランザクションで在庫に反映
+//
+// itemIDs が空の場合は差異のある全ての行を承認します。在庫数量には差異を加算し、
+// 棚卸調整（stocktake）のトランザクションに差分を記録します。反映後は承認した商品の棚卸差異アラートを解決します。
+func (m *Manager) ApplyStocktake(ctx context.Context, stocktakeID string, itemIDs []string) (_ *Stocktake, err error) {
+	ctx, finish := m.startOperation(ctx, "apply_stocktake", attrStocktakeID.String(stocktakeID))
+	defer finish(&err)
+
+	stocktake, err := m.GetStocktake(ctx, stocktakeID)
+	if err != nil {
+		return nil, err
+	}
+	if stocktake.Status != StocktakeStatusSubmitted {
+		return nil, ErrStocktakeStatus
+	}
+	approved, err := approvedStocktakeLines(stocktake, itemIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		events *bufferedPublisher
+		stocks []*Stock
+	)
+	err = m.retryOnConflict(ctx, func() error {
+		events = &bufferedPublisher{}
+		stocks = stocks[:0]
+		return m.storage.WithinTx(ctx, func(txStorage Storage) error {
+			txManager := m.withStorage(txStorage, events)
+			update := *stocktake
+			update.Lines = make([]StocktakeLine, 0, len(approved))
+			for _, line := range approved {
+				stock, err := txManager.applyStocktakeLine(ctx, stocktake, line)
+				if err != nil {
+					return err
+				}
+				if stock != nil {
+					stocks = append(stocks, stock)
+				}
+				line.Approved = true
+				update.Lines = append(update.Lines, line)
+			}
+
+			now := time.Now()
+			update.Status = StocktakeStatusApplied
+			update.ClosedAt = &now
+			update.ClosedBy = m.getUserFromContext(ctx)
+			return txManager.updateStocktakeStatus(ctx, &update, StocktakeStatusSubmitted)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if m.publisher != nil {
+		events.flush(ctx, m.publisher, m.logger)
+	}
+	for _, stock := range stocks {
+		m.evaluateAlertRules(ctx, stock)
+	}
+	for _, line := range approved {
+		m.resolveDiscrepancyAlerts(ctx, line.ItemID, stocktake.LocationID)
+	}
+
+	m.log(ctx).Info("棚卸差異の調整完了",
+		zap.String("stocktake_id", stocktake.ID),
+		zap.String("location_id", stocktake.LocationID),
+		zap.Int("lines", len(approved)),
+	)
+	return m.GetStocktake(ctx, stocktake.ID)
+}
+
+// CancelStocktake cancels an open or submitted stocktake without adjusting the stock
+// 集計中・差異確定済みの棚卸を在庫を調整せずに取消
+func (m *Manager) CancelStocktake(ctx context.Context, stocktakeID string) (_ *Stocktake, err error) {
+	ctx, finish := m.startOperation(ctx, "cancel_stocktake", attrStocktakeID.String(stocktakeID))
+	defer finish(&err)
+
+	stocktake, err := m.GetStocktake(ctx, stocktakeID)
+	if err != nil {
+		return nil, err
+	}
+	if stocktake.Status != StocktakeStatusOpen && stocktake.Status != StocktakeStatusSubmitted {
+		return nil, ErrStocktakeStatus
+	}
+
+	now := time.Now()
+	update := *stocktake
+	update.Status = StocktakeStatusCancelled
+	update.ClosedAt = &now
+	update.ClosedBy = m.getUserFromContext(ctx)
+	update.Lines = nil
+	if err := m.updateStocktakeStatus(ctx, &update, stocktake.Status); err != nil {
+		return nil, err
+	}
+
+	m.log(ctx).Info("棚卸取消完了", zap.String("stocktake_id", stocktake.ID))
+	return m.GetStocktake(ctx, stocktake.ID)
+}
+
+// approvedStocktakeLines selects the lines approved for adjustment
+// 調整を承認する行を選択
+//
+// itemIDs が空の場合は実数を記録した差異のある行、指定した場合はその商品の行（実数の記録が必要）を返します。
+func approvedStocktakeLines(stocktake *Stocktake, itemIDs []string) ([]StocktakeLine, error) {
+	lines := make([]StocktakeLine, 0)
+	if len(itemIDs) == 0 {
+		for _, line := range stocktake.Lines {
+			if line.Counted() && line.Variance != 0 {
+				lines = append(lines, line)
+			}
+		}
+		return lines, nil
+	}
+
+	index := make(map[string]int, len(stocktake.Lines))
+	for i, line := range stocktake.Lines {
+		index[line.ItemID] = i
+	}
+	seen := make(map[string]bool, len(itemIDs))
+	for _, itemID := range itemIDs {
+		i, ok := index[itemID]
+		if !ok {
+			return nil, NewValidationError("item_ids", "棚卸にない商品が指定されています", itemID)
+		}
+		if !stocktake.Lines[i].Counted() {
+			return nil, NewValidationError("item_ids", "実数を記録していない商品は承認できません", itemID)
+		}
+		if seen[itemID] {
+			continue
+		}
+		seen[itemID] = true
+		lines = append(lines, stocktake.Lines[i])
+	}
+	return lines, nil
+}
+
+// applyStocktakeLine adds the variance of a line to the stock and records a stocktake transaction
+// 行の差異を在庫数量に加算し、棚卸調整のトランザクションを記録（差異がない場合は何もしない）
+func (m *Manager) applyStocktakeLine(ctx context.Context, stocktake *Stocktake, line StocktakeLine) (*Stock, error) {
+	if line.Variance == 0 {
+		return nil, nil
+	}
+
+	item, err := m.storage.GetItem(ctx, line.ItemID)
+	if err != nil {
+		if errors.Is(err, ErrItemNotFound) {
+			return nil, ErrItemNotFound
+		}
+		return nil, NewStorageError("get_item", "商品取得に失敗しました", err)
+	}
+
+	stock, _, err := m.applyStockDelta(ctx, item, stocktake.LocationID, line.Variance, TransactionTypeStocktake, "stocktake", stocktake.Reference, map[string]string{"stocktake_id": stocktake.ID})
+	return stock, err
+}
+
+// applyStockDelta adds delta to a stock record and records the difference as a transaction of txType
+// 在庫数量に delta を加算し、差分を txType のトランザクションとして記録
+//
+// 在庫記録がない場合は作成します。在庫変更イベントの発行者には changeType を通知します。
+// 棚卸や承認済みの在庫調整など、トランザクションスコープ内で差分を反映する操作で使用します。
+func (m *Manager) applyStockDelta(ctx context.Context, item *Item, locationID string, delta int64, txType TransactionType, changeType, reference string, metadata map[string]string) (*Stock, *Transaction, error) {
+	var current int64
+	stock, err := m.getStockForWrite(ctx, item.ID, locationID)
+	switch {
+	case err == nil:
+		current = stock.Quantity
+	case !errors.Is(err, ErrStockNotFound):
+		return nil, nil, NewStorageError("get_stock", "在庫取得に失敗しました", err)
+	}
+	newQuantity := current + delta
+	if newQuantity < 0 && !m.config.AllowNegativeStock {
+		return nil, nil, NewValidationError("quantity", "調整後の在庫が負になります", fmt.Sprintf("%s: %d", item.ID, newQuantity))
+	}
+
+	oldQuantity, stock, err := m.setStockQuantity(ctx, item.ID, locationID, newQuantity)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tx := &Transaction{
+		ID:         NewTransactionID(),
+		Type:       txType,
+		ItemID:     item.ID,
+		ToLocation: &locationID,
+		Quantity:   newQuantity - oldQuantity, // 差分を記録
+		Reference:  reference,
+		Metadata:   transactionMetadata(ctx, metadata),
+		CreatedAt:  time.Now(),
+		CreatedBy:  m.getUserFromContext(ctx),
+	}
+	if err := m.storage.CreateTransaction(ctx, tx); err != nil {
+		return nil, nil, NewStorageError("create_transaction", "調整トランザクション記録に失敗しました", err)
+	}
+
+	if m.publisher != nil {
+		event := m.newStockChangedEvent(ctx, stock, oldQuantity, item.UnitCost, changeType, reference, tx.ID)
+		if err := m.publisher.PublishStockChanged(ctx, event); err != nil {
+			m.log(ctx).Error("調整イベント発行に失敗しました", zap.String("change_type", changeType), zap.Error(err))
+		}
+	}
+	return stock, tx, nil
+}
+
+// updateStocktakeStatus updates a stocktake expected to be in status from
+// 状態がfromの棚卸を更新
+func (m *Manager) updateStocktakeStatus(ctx context.Context, stocktake *Stocktake, from StocktakeStatus) error {
+	if err := m.storage.UpdateStocktake(ctx, stocktake, from); err != nil {
+		if errors.Is(err, ErrStocktakeNotFound) || errors.Is(err, ErrStocktakeStatus) {
+			return err
+		}
+		return NewStorageError("update_stocktake", "棚卸の更新に失敗しました", err)
+	}
+	return nil
+}
+
+// resolveDiscrepancyAlerts resolves the active discrepancy alerts of a stock record
+// 在庫記録のアクティブな棚卸差異アラートを解決
+//
+// 解決の失敗は調整自体を失敗させず、ログに記録するのみです。
+func (m *Manager) resolveDiscrepancyAlerts(ctx context.Context, itemID, locationID string) {
+	active := true
+	alerts, err := m.storage.ListAlerts(ctx, AlertFilter{Type: AlertTypeDiscrepancy, Active: &active, ItemID: itemID, LocationID: locationID, Limit: alertSweepPageSize})
+	if err != nil {
+		m.log(ctx).Warn("棚卸差異アラートの取得に失敗しました", zap.String("item_id", itemID), zap.Error(err))
+		return
+	}
+	for _, alert := range alerts {
+		if _, err := m.ResolveAlertWithNote(ctx, alert.ID, stocktakeAppliedNote); err != nil && !errors.Is(err, ErrAlertNotActive) {
+			m.log(ctx).Warn("棚卸差異アラートの解決に失敗しました", zap.String("alert_id", alert.ID), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,1374 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// storageMetrics holds the Prometheus collectors for storage operations
+// ストレージ操作用のPrometheusコレクター
+type storageMetrics struct {
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+	rows     *prometheus.HistogramVec
+}
+
+// InstrumentedStorage wraps a Storage and records Prometheus metrics per method
+// Storageをラップし、メソッドごとにPrometheusメトリクスを記録するデコレーター
+//
+// 記録するメトリクス:
+//   - zai_inventory_storage_operation_duration_seconds: メソッドごとの処理時間
+//   - zai_inventory_storage_operation_errors_total: メソッドごとのエラー数
+//   - zai_inventory_storage_rows_returned: 一覧系メソッドが返した件数
+type InstrumentedStorage struct {
+	next    inventory.Storage
+	metrics *storageMetrics
+}
+
+var _ inventory.Storage = (*InstrumentedStorage)(nil)
+
+// NewInstrumentedStorage creates a Storage decorator that records Prometheus metrics
+// Prometheusメトリクスを記録するStorageデコレーターを作成
+//
+// registererがnilの場合はprometheus.DefaultRegistererに登録します。
+// 同じメトリクスが登録済みの場合は既存のコレクターを再利用します。
+func NewInstrumentedStorage(next inventory.Storage, registerer prometheus.Registerer) (*InstrumentedStorage, error) {
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+
+	metrics := &storageMetrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "zai_inventory",
+			Subsystem: "storage",
+			Name:      "operation_duration_seconds",
+			Help:      "ストレージ操作の処理時間（秒）",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "zai_inventory",
+			Subsystem: "storage",
+			Name:      "operation_errors_total",
+			Help:      "ストレージ操作のエラー数",
+		}, []string{"method"}),
+		rows: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "zai_inventory",
+			Subsystem: "storage",
+			Name:      "rows_returned",
+			Help:      "ストレージ操作が返した行数",
+			Buckets:   prometheus.ExponentialBuckets(1, 4, 8),
+		}, []string{"method"}),
+	}
+
+	var err error
+	if metrics.duration, err = registerCollector(registerer, metrics.duration); err != nil {
+		return nil, err
+	}
+	if metrics.errors, err = registerCollector(registerer, metrics.errors); err != nil {
+		return nil, err
+	}
+	if metrics.rows, err = registerCollector(registerer, metrics.rows); err != nil {
+		return nil, err
+	}
+
+	return &InstrumentedStorage{
+		next:    next,
+		metrics: metrics,
+	}, nil
+}
+
+// registerCollector registers a collector, reusing an existing one if already registered
+// コレクターを登録（登録済みの場合は既存のコレクターを返す）
+func registerCollector[T prometheus.Collector](registerer prometheus.Registerer, collector T) (T, error) {
+	if err := registerer.Register(collector); err != nil {
+		var already prometheus.AlreadyRegisteredError
+		if errors.As(err, &already) {
+			if existing, ok := already.ExistingCollector.(T); ok {
+				return existing, nil
+			}
+		}
+		return collector, err
+	}
+	return collector, nil
+}
+
+// observe records the duration and error status of a storage call
+// ストレージ呼び出しの処理時間とエラー状況を記録
+func (s *InstrumentedStorage) observe(method string, start time.Time, err error) {
+	s.metrics.duration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		s.metrics.errors.WithLabelValues(method).Inc()
+	}
+}
+
+// observeRows records the number of rows returned by a successful storage call
+// 成功したストレージ呼び出しの返却件数を記録
+func (s *InstrumentedStorage) observeRows(method string, start time.Time, rows int, err error) {
+	s.observe(method, start, err)
+	if err == nil {
+		s.metrics.rows.WithLabelValues(method).Observe(float64(rows))
+	}
+}
+
+// WithinTx runs fn inside a transaction, instrumenting the calls made on txStorage
+// トランザクション内でfnを実行（txStorageへの呼び出しも計測対象）
+func (s *InstrumentedStorage) WithinTx(ctx context.Context, fn func(txStorage inventory.Storage) error) error {
+	start := time.Now()
+	err := s.next.WithinTx(ctx, func(txStorage inventory.Storage) error {
+		return fn(&InstrumentedStorage{next: txStorage, metrics: s.metrics})
+	})
+	s.observe("WithinTx", start, err)
+	return err
+}
+
+// CreateStock creates a new stock record
+// 新しい在庫記録を作成
+func (s *InstrumentedStorage) CreateStock(ctx context.Context, stock *inventory.Stock) error {
+	start := time.Now()
+	err := s.next.CreateStock(ctx, stock)
+	s.observe("CreateStock", start, err)
+	return err
+}
+
+// UpdateStock updates an existing stock record
+// 既存の在庫記録を更新
+func (s *InstrumentedStorage) UpdateStock(ctx context.Context, stock *inventory.Stock) error {
+	start := time.Now()
+	err := s.next.UpdateStock(ctx, stock)
+	s.observe("UpdateStock", start, err)
+	return err
+}
+
+// CreateOrUpdateStocks upserts multiple stock records
+// 複数の在庫記録を一括作成・更新
+func (s *InstrumentedStorage) CreateOrUpdateStocks(ctx context.Context, stocks []inventory.Stock) error {
+	start := time.Now()
+	err := s.next.CreateOrUpdateStocks(ctx, stocks)
+	s.observe("CreateOrUpdateStocks", start, err)
+	return err
+}
+
+// BulkImport inserts items, stocks and transactions in bulk
+// 商品・在庫・トランザクションを一括挿入
+func (s *InstrumentedStorage) BulkImport(ctx context.Context, data *inventory.BulkImportData) error {
+	start := time.Now()
+	err := s.next.BulkImport(ctx, data)
+	s.observeRows("BulkImport", start, data.Len(), err)
+	return err
+}
+
+// GetStock retrieves stock information
+// 在庫情報を取得
+func (s *InstrumentedStorage) GetStock(ctx context.Context, itemID, locationID string) (*inventory.Stock, error) {
+	start := time.Now()
+	stock, err := s.next.GetStock(ctx, itemID, locationID)
+	s.observe("GetStock", start, err)
+	return stock, err
+}
+
+// GetStocks retrieves the stock of many item and location pairs
+// 複数の商品とロケーションの組の在庫情報を取得
+func (s *InstrumentedStorage) GetStocks(ctx context.Context, keys []inventory.StockKey) ([]inventory.Stock, error) {
+	start := time.Now()
+	stocks, err := s.next.GetStocks(ctx, keys)
+	s.observeRows("GetStocks", start, len(stocks), err)
+	return stocks, err
+}
+
+// GetStockForUpdate retrieves stock information with a row lock
+// 行ロック付きで在庫情報を取得
+func (s *InstrumentedStorage) GetStockForUpdate(ctx context.Context, itemID, locationID string) (*inventory.Stock, error) {
+	start := time.Now()
+	stock, err := s.next.GetStockForUpdate(ctx, itemID, locationID)
+	s.observe("GetStockForUpdate", start, err)
+	return stock, err
+}
+
+// ListStockByLocation retrieves all stock at a location
+// ロケーションの全在庫を取得
+func (s *InstrumentedStorage) ListStockByLocation(ctx context.Context, locationID string) ([]inventory.Stock, error) {
+	start := time.Now()
+	stocks, err := s.next.ListStockByLocation(ctx, locationID)
+	s.observeRows("ListStockByLocation", start, len(stocks), err)
+	return stocks, err
+}
+
+// ListStockByItem retrieves the stock of an item at every location
+// 商品の全ロケーションの在庫を取得
+func (s *InstrumentedStorage) ListStockByItem(ctx context.Context, itemID string) ([]inventory.Stock, error) {
+	start := time.Now()
+	stocks, err := s.next.ListStockByItem(ctx, itemID)
+	s.observeRows("ListStockByItem", start, len(stocks), err)
+	return stocks, err
+}
+
+// ForEachStockByLocation streams stock at a location
+// ロケーションの在庫を1件ずつ走査
+func (s *InstrumentedStorage) ForEachStockByLocation(ctx context.Context, locationID string, fn func(stock inventory.Stock) error) error {
+	start := time.Now()
+	rows := 0
+	err := s.next.ForEachStockByLocation(ctx, locationID, func(stock inventory.Stock) error {
+		rows++
+		return fn(stock)
+	})
+	s.observeRows("ForEachStockByLocation", start, rows, err)
+	return err
+}
+
+// GetTotalStockByItem retrieves total stock for an item across all locations
+// 商品の全ロケーション合計在庫を取得
+func (s *InstrumentedStorage) GetTotalStockByItem(ctx context.Context, itemID string) (int64, error) {
+	start := time.Now()
+	total, err := s.next.GetTotalStockByItem(ctx, itemID)
+	s.observe("GetTotalStockByItem", start, err)
+	return total, err
+}
+
+// GetTotalStockByLocation retrieves total stock of all items at a location
+// ロケーションの全商品合計在庫を取得
+func (s *InstrumentedStorage) GetTotalStockByLocation(ctx context.Context, locationID string) (int64, error) {
+	start := time.Now()
+	total, err := s.next.GetTotalStockByLocation(ctx, locationID)
+	s.observe("GetTotalStockByLocation", start, err)
+	return total, err
+}
+
+// CreateTransaction creates a new transaction record
+// 新しいトランザクション記録を作成
+func (s *InstrumentedStorage) CreateTransaction(ctx context.Context, tx *inventory.Transaction) error {
+	start := time.Now()
+	err := s.next.CreateTransaction(ctx, tx)
+	s.observe("CreateTransaction", start, err)
+	return err
+}
+
+// GetTransactionByID retrieves a transaction record by ID
+// IDでトランザクション記録を取得
+func (s *InstrumentedStorage) GetTransactionByID(ctx context.Context, txID string) (*inventory.Transaction, error) {
+	start := time.Now()
+	tx, err := s.next.GetTransactionByID(ctx, txID)
+	s.observe("GetTransactionByID", start, err)
+	return tx, err
+}
+
+// GetTransactionHistory retrieves transaction history for an item
+// 商品のトランザクション履歴を取得
+func (s *InstrumentedStorage) GetTransactionHistory(ctx context.Context, itemID string, limit int) ([]inventory.Transaction, error) {
+	start := time.Now()
+	txs, err := s.next.GetTransactionHistory(ctx, itemID, limit)
+	s.observeRows("GetTransactionHistory", start, len(txs), err)
+	return txs, err
+}
+
+// GetTransactionHistoryAfter retrieves transaction history older than the cursor
+// カーソルより古いトランザクション履歴を取得
+func (s *InstrumentedStorage) GetTransactionHistoryAfter(ctx context.Context, itemID string, after *inventory.HistoryCursor, limit int) ([]inventory.Transaction, error) {
+	start := time.Now()
+	txs, err := s.next.GetTransactionHistoryAfter(ctx, itemID, after, limit)
+	s.observeRows("GetTransactionHistoryAfter", start, len(txs), err)
+	return txs, err
+}
+
+// GetTransactionHistoryByLocation retrieves transaction history for a location
+// ロケーションのトランザクション履歴を取得
+func (s *InstrumentedStorage) GetTransactionHistoryByLocation(ctx context.Context, locationID string, limit int) ([]inventory.Transaction, error) {
+	start := time.Now()
+	txs, err := s.next.GetTransactionHistoryByLocation(ctx, locationID, limit)
+	s.observeRows("GetTransactionHistoryByLocation", start, len(txs), err)
+	return txs, err
+}
+
+// GetTransactionsByReference retrieves transactions by reference number
+// 参照番号でトランザクションを取得
+func (s *InstrumentedStorage) GetTransactionsByReference(ctx context.Context, reference string) ([]inventory.Transaction, error) {
+	start := time.Now()
+	txs, err := s.next.GetTransactionsByReference(ctx, reference)
+	s.observeRows("GetTransactionsByReference", start, len(txs), err)
+	return txs, err
+}
+
+// SearchTransactionsByMetadata retrieves transactions by metadata key and value
+// メタデータのキーと値でトランザクションを検索
+func (s *InstrumentedStorage) SearchTransactionsByMetadata(ctx context.Context, key, value string, limit int) ([]inventory.Transaction, error) {
+	start := time.Now()
+	txs, err := s.next.SearchTransactionsByMetadata(ctx, key, value, limit)
+	s.observeRows("SearchTransactionsByMetadata", start, len(txs), err)
+	return txs, err
+}
+
+// ForEachTransactionInRange streams transactions in a time range
+// 期間内のトランザクションを1件ずつ走査
+func (s *InstrumentedStorage) ForEachTransactionInRange(ctx context.Context, from, to time.Time, fn func(tx inventory.Transaction) error) error {
+	start := time.Now()
+	rows := 0
+	err := s.next.ForEachTransactionInRange(ctx, from, to, func(tx inventory.Transaction) error {
+		rows++
+		return fn(tx)
+	})
+	s.observeRows("ForEachTransactionInRange", start, rows, err)
+	return err
+}
+
+// ArchiveTransactions moves old transactions into the archive
+// 古いトランザクションをアーカイブへ移動
+func (s *InstrumentedStorage) ArchiveTransactions(ctx context.Context, before time.Time) (int64, error) {
+	start := time.Now()
+	archived, err := s.next.ArchiveTransactions(ctx, before)
+	s.observe("ArchiveTransactions", start, err)
+	return archived, err
+}
+
+// GetTransactionHistoryByDateRange retrieves transaction history within a date range
+// 日付範囲内のトランザクション履歴を取得
+func (s *InstrumentedStorage) GetTransactionHistoryByDateRange(ctx context.Context, itemID string, from, to time.Time) ([]inventory.Transaction, error) {
+	start := time.Now()
+	txs, err := s.next.GetTransactionHistoryByDateRange(ctx, itemID, from, to)
+	s.observeRows("GetTransactionHistoryByDateRange", start, len(txs), err)
+	return txs, err
+}
+
+// CreateItem creates a new item
+// 新しい商品を作成
+func (s *InstrumentedStorage) CreateItem(ctx context.Context, item *inventory.Item) error {
+	start := time.Now()
+	err := s.next.CreateItem(ctx, item)
+	s.observe("CreateItem", start, err)
+	return err
+}
+
+// GetItem retrieves an item by ID
+// IDで商品を取得
+func (s *InstrumentedStorage) GetItem(ctx context.Context, itemID string) (*inventory.Item, error) {
+	start := time.Now()
+	item, err := s.next.GetItem(ctx, itemID)
+	s.observe("GetItem", start, err)
+	return item, err
+}
+
+// UpdateItem updates an existing item
+// 既存の商品を更新
+func (s *InstrumentedStorage) UpdateItem(ctx context.Context, item *inventory.Item) error {
+	start := time.Now()
+	err := s.next.UpdateItem(ctx, item)
+	s.observe("UpdateItem", start, err)
+	return err
+}
+
+// DeleteItem deletes an item
+// 商品を削除
+func (s *InstrumentedStorage) DeleteItem(ctx context.Context, itemID string) error {
+	start := time.Now()
+	err := s.next.DeleteItem(ctx, itemID)
+	s.observe("DeleteItem", start, err)
+	return err
+}
+
+// ListItems retrieves items with pagination
+// ページネーション付きで商品一覧を取得
+func (s *InstrumentedStorage) ListItems(ctx context.Context, offset, limit int) ([]inventory.Item, error) {
+	start := time.Now()
+	items, err := s.next.ListItems(ctx, offset, limit)
+	s.observeRows("ListItems", start, len(items), err)
+	return items, err
+}
+
+// ListItemsAfter retrieves items matching a filter after the cursor
+// 条件に一致する商品をカーソルより後から取得
+func (s *InstrumentedStorage) ListItemsAfter(ctx context.Context, filter inventory.ItemFilter, after *inventory.PageCursor, limit int) ([]inventory.Item, error) {
+	start := time.Now()
+	items, err := s.next.ListItemsAfter(ctx, filter, after, limit)
+	s.observeRows("ListItemsAfter", start, len(items), err)
+	return items, err
+}
+
+// SearchItems searches items by query
+// クエリで商品を検索
+func (s *InstrumentedStorage) SearchItems(ctx context.Context, query string) ([]inventory.Item, error) {
+	start := time.Now()
+	items, err := s.next.SearchItems(ctx, query)
+	s.observeRows("SearchItems", start, len(items), err)
+	return items, err
+}
+
+// CreateLocation creates a new location
+// 新しいロケーションを作成
+func (s *InstrumentedStorage) CreateLocation(ctx context.Context, location *inventory.Location) error {
+	start := time.Now()
+	err := s.next.CreateLocation(ctx, location)
+	s.observe("CreateLocation", start, err)
+	return err
+}
+
+// GetLocation retrieves a location by ID
+// IDでロケーションを取得
+func (s *InstrumentedStorage) GetLocation(ctx context.Context, locationID string) (*inventory.Location, error) {
+	start := time.Now()
+	location, err := s.next.GetLocation(ctx, locationID)
+	s.observe("GetLocation", start, err)
+	return location, err
+}
+
+// UpdateLocation updates an existing location
+// 既存のロケーションを更新
+func (s *InstrumentedStorage) UpdateLocation(ctx context.Context, location *inventory.Location) error {
+	start := time.Now()
+	err := s.next.UpdateLocation(ctx, location)
+	s.observe("UpdateLocation", start, err)
+	return err
+}
+
+// DeleteLocation deletes a location
+// ロケーションを削除
+func (s *InstrumentedStorage) DeleteLocation(ctx context.Context, locationID string) error {
+	start := time.Now()
+	err := s.next.DeleteLocation(ctx, locationID)
+	s.observe("DeleteLocation", start, err)
+	return err
+}
+
+// ListLocations retrieves locations with pagination
+// ページネーション付きでロケーション一覧を取得
+func (s *InstrumentedStorage) ListLocations(ctx context.Context, offset, limit int) ([]inventory.Location, error) {
+	start := time.Now()
+	locations, err := s.next.ListLocations(ctx, offset, limit)
+	s.observeRows("ListLocations", start, len(locations), err)
+	return locations, err
+}
+
+// ListLocationsAfter retrieves locations older than the cursor
+// カーソルより古いロケーションを取得
+func (s *InstrumentedStorage) ListLocationsAfter(ctx context.Context, after *inventory.PageCursor, limit int) ([]inventory.Location, error) {
+	start := time.Now()
+	locations, err := s.next.ListLocationsAfter(ctx, after, limit)
+	s.observeRows("ListLocationsAfter", start, len(locations), err)
+	return locations, err
+}
+
+// CreateUnit registers a new unit of measure
+// 新しい単位を登録
+func (s *InstrumentedStorage) CreateUnit(ctx context.Context, unit *inventory.UnitOfMeasure) error {
+	start := time.Now()
+	err := s.next.CreateUnit(ctx, unit)
+	s.observe("CreateUnit", start, err)
+	return err
+}
+
+// GetUnit retrieves a unit of measure by code
+// コードで単位を取得
+func (s *InstrumentedStorage) GetUnit(ctx context.Context, code string) (*inventory.UnitOfMeasure, error) {
+	start := time.Now()
+	unit, err := s.next.GetUnit(ctx, code)
+	s.observe("GetUnit", start, err)
+	return unit, err
+}
+
+// ListUnits lists all units of measure
+// 全ての単位を取得
+func (s *InstrumentedStorage) ListUnits(ctx context.Context) ([]inventory.UnitOfMeasure, error) {
+	start := time.Now()
+	units, err := s.next.ListUnits(ctx)
+	s.observeRows("ListUnits", start, len(units), err)
+	return units, err
+}
+
+// DeleteUnit deletes a unit of measure
+// 単位を削除
+func (s *InstrumentedStorage) DeleteUnit(ctx context.Context, code string) error {
+	start := time.Now()
+	err := s.next.DeleteUnit(ctx, code)
+	s.observe("DeleteUnit", start, err)
+	return err
+}
+
+// SetUnitConversion registers or updates the conversion of an item's unit of measure
+// 商品の単位の換算を登録・更新
+func (s *InstrumentedStorage) SetUnitConversion(ctx context.Context, conversion *inventory.UnitConversion) error {
+	start := time.Now()
+	err := s.next.SetUnitConversion(ctx, conversion)
+	s.observe("SetUnitConversion", start, err)
+	return err
+}
+
+// GetUnitConversion retrieves the conversion of an item's unit of measure
+// 商品の単位の換算を取得
+func (s *InstrumentedStorage) GetUnitConversion(ctx context.Context, itemID, uom string) (*inventory.UnitConversion, error) {
+	start := time.Now()
+	conversion, err := s.next.GetUnitConversion(ctx, itemID, uom)
+	s.observe("GetUnitConversion", start, err)
+	return conversion, err
+}
+
+// ListUnitConversions lists the unit conversions of an item
+// 商品の単位の換算を取得
+func (s *InstrumentedStorage) ListUnitConversions(ctx context.Context, itemID string) ([]inventory.UnitConversion, error) {
+	start := time.Now()
+	conversions, err := s.next.ListUnitConversions(ctx, itemID)
+	s.observeRows("ListUnitConversions", start, len(conversions), err)
+	return conversions, err
+}
+
+// DeleteUnitConversion deletes the conversion of an item's unit of measure
+// 商品の単位の換算を削除
+func (s *InstrumentedStorage) DeleteUnitConversion(ctx context.Context, itemID, uom string) error {
+	start := time.Now()
+	err := s.next.DeleteUnitConversion(ctx, itemID, uom)
+	s.observe("DeleteUnitConversion", start, err)
+	return err
+}
+
+// SetBillOfMaterials registers or replaces the bill of materials of a kit
+// キットの部品表を登録
+func (s *InstrumentedStorage) SetBillOfMaterials(ctx context.Context, bom *inventory.BillOfMaterials) error {
+	start := time.Now()
+	err := s.next.SetBillOfMaterials(ctx, bom)
+	s.observe("SetBillOfMaterials", start, err)
+	return err
+}
+
+// GetBillOfMaterials retrieves the bill of materials of a kit
+// キットの部品表を取得
+func (s *InstrumentedStorage) GetBillOfMaterials(ctx context.Context, kitItemID string) (*inventory.BillOfMaterials, error) {
+	start := time.Now()
+	bom, err := s.next.GetBillOfMaterials(ctx, kitItemID)
+	s.observe("GetBillOfMaterials", start, err)
+	return bom, err
+}
+
+// ListBillsOfMaterials lists bills of materials
+// 部品表一覧を取得
+func (s *InstrumentedStorage) ListBillsOfMaterials(ctx context.Context, offset, limit int) ([]inventory.BillOfMaterials, error) {
+	start := time.Now()
+	boms, err := s.next.ListBillsOfMaterials(ctx, offset, limit)
+	s.observeRows("ListBillsOfMaterials", start, len(boms), err)
+	return boms, err
+}
+
+// DeleteBillOfMaterials deletes the bill of materials of a kit
+// キットの部品表を削除
+func (s *InstrumentedStorage) DeleteBillOfMaterials(ctx context.Context, kitItemID string) error {
+	start := time.Now()
+	err := s.next.DeleteBillOfMaterials(ctx, kitItemID)
+	s.observe("DeleteBillOfMaterials", start, err)
+	return err
+}
+
+// CreateAssembly records a kit assembly or disassembly
+// キットの組立・分解を記録
+func (s *InstrumentedStorage) CreateAssembly(ctx context.Context, assembly *inventory.Assembly) error {
+	start := time.Now()
+	err := s.next.CreateAssembly(ctx, assembly)
+	s.observe("CreateAssembly", start, err)
+	return err
+}
+
+// GetAssembly retrieves a kit assembly or disassembly by ID
+// IDでキットの組立・分解を取得
+func (s *InstrumentedStorage) GetAssembly(ctx context.Context, assemblyID string) (*inventory.Assembly, error) {
+	start := time.Now()
+	assembly, err := s.next.GetAssembly(ctx, assemblyID)
+	s.observe("GetAssembly", start, err)
+	return assembly, err
+}
+
+// ListAssemblies lists kit assemblies and disassemblies matching a filter
+// 条件に一致するキットの組立・分解を取得
+func (s *InstrumentedStorage) ListAssemblies(ctx context.Context, filter inventory.AssemblyFilter) ([]inventory.Assembly, error) {
+	start := time.Now()
+	assemblies, err := s.next.ListAssemblies(ctx, filter)
+	s.observeRows("ListAssemblies", start, len(assemblies), err)
+	return assemblies, err
+}
+
+// CreateSupplier creates a new supplier
+// 新しい仕入先を作成
+func (s *InstrumentedStorage) CreateSupplier(ctx context.Context, supplier *inventory.Supplier) error {
+	start := time.Now()
+	err := s.next.CreateSupplier(ctx, supplier)
+	s.observe("CreateSupplier", start, err)
+	return err
+}
+
+// GetSupplier retrieves a supplier by ID
+// IDで仕入先を取得
+func (s *InstrumentedStorage) GetSupplier(ctx context.Context, supplierID string) (*inventory.Supplier, error) {
+	start := time.Now()
+	supplier, err := s.next.GetSupplier(ctx, supplierID)
+	s.observe("GetSupplier", start, err)
+	return supplier, err
+}
+
+// UpdateSupplier updates an existing supplier
+// 既存の仕入先を更新
+func (s *InstrumentedStorage) UpdateSupplier(ctx context.Context, supplier *inventory.Supplier) error {
+	start := time.Now()
+	err := s.next.UpdateSupplier(ctx, supplier)
+	s.observe("UpdateSupplier", start, err)
+	return err
+}
+
+// DeleteSupplier deletes a supplier and the purchasing terms of its items
+// 仕入先を仕入条件とともに削除
+func (s *InstrumentedStorage) DeleteSupplier(ctx context.Context, supplierID string) error {
+	start := time.Now()
+	err := s.next.DeleteSupplier(ctx, supplierID)
+	s.observe("DeleteSupplier", start, err)
+	return err
+}
+
+// ListSuppliers lists suppliers
+// 仕入先一覧を取得
+func (s *InstrumentedStorage) ListSuppliers(ctx context.Context, offset, limit int) ([]inventory.Supplier, error) {
+	start := time.Now()
+	suppliers, err := s.next.ListSuppliers(ctx, offset, limit)
+	s.observeRows("ListSuppliers", start, len(suppliers), err)
+	return suppliers, err
+}
+
+// SetSupplierItem registers or updates the purchasing terms of an item from a supplier
+// 仕入先の商品の仕入条件を登録
+func (s *InstrumentedStorage) SetSupplierItem(ctx context.Context, supplierItem *inventory.SupplierItem) error {
+	start := time.Now()
+	err := s.next.SetSupplierItem(ctx, supplierItem)
+	s.observe("SetSupplierItem", start, err)
+	return err
+}
+
+// GetSupplierItem retrieves the purchasing terms of an item from a supplier
+// 仕入先の商品の仕入条件を取得
+func (s *InstrumentedStorage) GetSupplierItem(ctx context.Context, supplierID, itemID string) (*inventory.SupplierItem, error) {
+	start := time.Now()
+	supplierItem, err := s.next.GetSupplierItem(ctx, supplierID, itemID)
+	s.observe("GetSupplierItem", start, err)
+	return supplierItem, err
+}
+
+// DeleteSupplierItem deletes the purchasing terms of an item from a supplier
+// 仕入先の商品の仕入条件を削除
+func (s *InstrumentedStorage) DeleteSupplierItem(ctx context.Context, supplierID, itemID string) error {
+	start := time.Now()
+	err := s.next.DeleteSupplierItem(ctx, supplierID, itemID)
+	s.observe("DeleteSupplierItem", start, err)
+	return err
+}
+
+// ListSupplierItems lists the purchasing terms matching a filter
+// 条件に一致する仕入条件を取得
+func (s *InstrumentedStorage) ListSupplierItems(ctx context.Context, filter inventory.SupplierItemFilter) ([]inventory.SupplierItem, error) {
+	start := time.Now()
+	supplierItems, err := s.next.ListSupplierItems(ctx, filter)
+	s.observeRows("ListSupplierItems", start, len(supplierItems), err)
+	return supplierItems, err
+}
+
+// CreatePurchaseOrder creates a purchase order with its lines
+// 発注と行を作成
+func (s *InstrumentedStorage) CreatePurchaseOrder(ctx context.Context, order *inventory.PurchaseOrder) error {
+	start := time.Now()
+	err := s.next.CreatePurchaseOrder(ctx, order)
+	s.observe("CreatePurchaseOrder", start, err)
+	return err
+}
+
+// GetPurchaseOrder retrieves a purchase order with its lines
+// 発注を行とともに取得
+func (s *InstrumentedStorage) GetPurchaseOrder(ctx context.Context, orderID string) (*inventory.PurchaseOrder, error) {
+	start := time.Now()
+	order, err := s.next.GetPurchaseOrder(ctx, orderID)
+	s.observe("GetPurchaseOrder", start, err)
+	return order, err
+}
+
+// ListPurchaseOrders lists purchase orders matching a filter
+// 条件に一致する発注を取得
+func (s *InstrumentedStorage) ListPurchaseOrders(ctx context.Context, filter inventory.PurchaseOrderFilter) ([]inventory.PurchaseOrder, error) {
+	start := time.Now()
+	orders, err := s.next.ListPurchaseOrders(ctx, filter)
+	s.observeRows("ListPurchaseOrders", start, len(orders), err)
+	return orders, err
+}
+
+// UpdatePurchaseOrder updates the status of a purchase order in status from
+// 状態がfromの発注の状態を更新
+func (s *InstrumentedStorage) UpdatePurchaseOrder(ctx context.Context, order *inventory.PurchaseOrder, from inventory.PurchaseOrderStatus) error {
+	start := time.Now()
+	err := s.next.UpdatePurchaseOrder(ctx, order, from)
+	s.observe("UpdatePurchaseOrder", start, err)
+	return err
+}
+
+// ReceivePurchaseOrderLine adds a received quantity to a purchase order line
+// 発注の行の入荷済み数量を加算
+func (s *InstrumentedStorage) ReceivePurchaseOrderLine(ctx context.Context, orderID, itemID string, quantity int64) (*inventory.PurchaseOrderLine, error) {
+	start := time.Now()
+	line, err := s.next.ReceivePurchaseOrderLine(ctx, orderID, itemID, quantity)
+	s.observe("ReceivePurchaseOrderLine", start, err)
+	return line, err
+}
+
+// CreateGoodsReceipt records a goods receipt against a purchase order
+// 発注に対する入荷を記録
+func (s *InstrumentedStorage) CreateGoodsReceipt(ctx context.Context, receipt *inventory.GoodsReceipt) error {
+	start := time.Now()
+	err := s.next.CreateGoodsReceipt(ctx, receipt)
+	s.observe("CreateGoodsReceipt", start, err)
+	return err
+}
+
+// ListGoodsReceipts lists the goods receipts of a purchase order
+// 発注に対する入荷を取得
+func (s *InstrumentedStorage) ListGoodsReceipts(ctx context.Context, orderID string) ([]inventory.GoodsReceipt, error) {
+	start := time.Now()
+	receipts, err := s.next.ListGoodsReceipts(ctx, orderID)
+	s.observeRows("ListGoodsReceipts", start, len(receipts), err)
+	return receipts, err
+}
+
+// CreateSalesOrder creates a sales order with its lines
+// 受注と行を作成
+func (s *InstrumentedStorage) CreateSalesOrder(ctx context.Context, order *inventory.SalesOrder) error {
+	start := time.Now()
+	err := s.next.CreateSalesOrder(ctx, order)
+	s.observe("CreateSalesOrder", start, err)
+	return err
+}
+
+// GetSalesOrder retrieves a sales order with its lines
+// 受注を行とともに取得
+func (s *InstrumentedStorage) GetSalesOrder(ctx context.Context, orderID string) (*inventory.SalesOrder, error) {
+	start := time.Now()
+	order, err := s.next.GetSalesOrder(ctx, orderID)
+	s.observe("GetSalesOrder", start, err)
+	return order, err
+}
+
+// ListSalesOrders lists sales orders matching a filter
+// 条件に一致する受注を取得
+func (s *InstrumentedStorage) ListSalesOrders(ctx context.Context, filter inventory.SalesOrderFilter) ([]inventory.SalesOrder, error) {
+	start := time.Now()
+	orders, err := s.next.ListSalesOrders(ctx, filter)
+	s.observeRows("ListSalesOrders", start, len(orders), err)
+	return orders, err
+}
+
+// UpdateSalesOrder updates a sales order with optimistic locking
+// 受注を楽観的ロックで更新
+func (s *InstrumentedStorage) UpdateSalesOrder(ctx context.Context, order *inventory.SalesOrder) error {
+	start := time.Now()
+	err := s.next.UpdateSalesOrder(ctx, order)
+	s.observe("UpdateSalesOrder", start, err)
+	return err
+}
+
+// CreateReturn creates a return with its lines
+// 返品と行を作成
+func (s *InstrumentedStorage) CreateReturn(ctx context.Context, rma *inventory.ReturnAuthorization) error {
+	start := time.Now()
+	err := s.next.CreateReturn(ctx, rma)
+	s.observe("CreateReturn", start, err)
+	return err
+}
+
+// GetReturn retrieves a return with its lines
+// 返品を行とともに取得
+func (s *InstrumentedStorage) GetReturn(ctx context.Context, returnID string) (*inventory.ReturnAuthorization, error) {
+	start := time.Now()
+	rma, err := s.next.GetReturn(ctx, returnID)
+	s.observe("GetReturn", start, err)
+	return rma, err
+}
+
+// ListReturns lists returns matching a filter
+// 条件に一致する返品を取得
+func (s *InstrumentedStorage) ListReturns(ctx context.Context, filter inventory.ReturnFilter) ([]inventory.ReturnAuthorization, error) {
+	start := time.Now()
+	returns, err := s.next.ListReturns(ctx, filter)
+	s.observeRows("ListReturns", start, len(returns), err)
+	return returns, err
+}
+
+// UpdateReturn updates a return with optimistic locking
+// 返品を楽観的ロックで更新
+func (s *InstrumentedStorage) UpdateReturn(ctx context.Context, rma *inventory.ReturnAuthorization) error {
+	start := time.Now()
+	err := s.next.UpdateReturn(ctx, rma)
+	s.observe("UpdateReturn", start, err)
+	return err
+}
+
+// CreateReturnDisposition records a disposition of returned units
+// 返品の処分を記録
+func (s *InstrumentedStorage) CreateReturnDisposition(ctx context.Context, disposition *inventory.ReturnDisposition) error {
+	start := time.Now()
+	err := s.next.CreateReturnDisposition(ctx, disposition)
+	s.observe("CreateReturnDisposition", start, err)
+	return err
+}
+
+// ListReturnDispositions lists the dispositions of a return
+// 返品の処分を取得
+func (s *InstrumentedStorage) ListReturnDispositions(ctx context.Context, returnID string) ([]inventory.ReturnDisposition, error) {
+	start := time.Now()
+	dispositions, err := s.next.ListReturnDispositions(ctx, returnID)
+	s.observeRows("ListReturnDispositions", start, len(dispositions), err)
+	return dispositions, err
+}
+
+// CreateLot creates a new lot
+// 新しいロットを作成
+func (s *InstrumentedStorage) CreateLot(ctx context.Context, lot *inventory.Lot) error {
+	start := time.Now()
+	err := s.next.CreateLot(ctx, lot)
+	s.observe("CreateLot", start, err)
+	return err
+}
+
+// GetLot retrieves a lot by ID
+// IDでロットを取得
+func (s *InstrumentedStorage) GetLot(ctx context.Context, lotID string) (*inventory.Lot, error) {
+	start := time.Now()
+	lot, err := s.next.GetLot(ctx, lotID)
+	s.observe("GetLot", start, err)
+	return lot, err
+}
+
+// UpdateLot updates a lot
+// ロットを更新
+func (s *InstrumentedStorage) UpdateLot(ctx context.Context, lot *inventory.Lot) error {
+	start := time.Now()
+	err := s.next.UpdateLot(ctx, lot)
+	s.observe("UpdateLot", start, err)
+	return err
+}
+
+// DeleteLot deletes a lot
+// ロットを削除
+func (s *InstrumentedStorage) DeleteLot(ctx context.Context, lotID string) error {
+	start := time.Now()
+	err := s.next.DeleteLot(ctx, lotID)
+	s.observe("DeleteLot", start, err)
+	return err
+}
+
+// AdjustLotQuantity adds delta to a lot's quantity
+// ロットの数量を増減
+func (s *InstrumentedStorage) AdjustLotQuantity(ctx context.Context, lotID string, delta int64) (*inventory.Lot, error) {
+	start := time.Now()
+	lot, err := s.next.AdjustLotQuantity(ctx, lotID, delta)
+	s.observe("AdjustLotQuantity", start, err)
+	return lot, err
+}
+
+// GetLotsByItem retrieves all lots for an item
+// 商品の全ロットを取得
+func (s *InstrumentedStorage) GetLotsByItem(ctx context.Context, itemID string) ([]inventory.Lot, error) {
+	start := time.Now()
+	lots, err := s.next.GetLotsByItem(ctx, itemID)
+	s.observeRows("GetLotsByItem", start, len(lots), err)
+	return lots, err
+}
+
+// GetExpiringLots retrieves lots expiring within the given duration
+// 指定期間内に期限切れになるロットを取得
+func (s *InstrumentedStorage) GetExpiringLots(ctx context.Context, within time.Duration) ([]inventory.Lot, error) {
+	start := time.Now()
+	lots, err := s.next.GetExpiringLots(ctx, within)
+	s.observeRows("GetExpiringLots", start, len(lots), err)
+	return lots, err
+}
+
+// GetExpiredLots retrieves lots that have already expired
+// 期限切れのロットを取得
+func (s *InstrumentedStorage) GetExpiredLots(ctx context.Context) ([]inventory.Lot, error) {
+	start := time.Now()
+	lots, err := s.next.GetExpiredLots(ctx)
+	s.observeRows("GetExpiredLots", start, len(lots), err)
+	return lots, err
+}
+
+// GetLotByNumber retrieves the oldest lot of an item with a lot number
+// 商品のロット番号のロットを取得
+func (s *InstrumentedStorage) GetLotByNumber(ctx context.Context, itemID, lotNumber string) (*inventory.Lot, error) {
+	start := time.Now()
+	lot, err := s.next.GetLotByNumber(ctx, itemID, lotNumber)
+	s.observe("GetLotByNumber", start, err)
+	return lot, err
+}
+
+// AdjustLotStock adds delta to the quantity of a lot at a location
+// ロットのロケーションの在庫数量を増減
+func (s *InstrumentedStorage) AdjustLotStock(ctx context.Context, lot *inventory.Lot, locationID string, delta int64) (*inventory.LotStock, error) {
+	start := time.Now()
+	lotStock, err := s.next.AdjustLotStock(ctx, lot, locationID, delta)
+	s.observe("AdjustLotStock", start, err)
+	return lotStock, err
+}
+
+// GetLotStock retrieves the stock of a lot at a location
+// ロットのロケーションの在庫を取得
+func (s *InstrumentedStorage) GetLotStock(ctx context.Context, lotID, locationID string) (*inventory.LotStock, error) {
+	start := time.Now()
+	lotStock, err := s.next.GetLotStock(ctx, lotID, locationID)
+	s.observe("GetLotStock", start, err)
+	return lotStock, err
+}
+
+// ListLotStocks lists lot stocks matching a filter
+// 条件に一致するロット在庫を取得
+func (s *InstrumentedStorage) ListLotStocks(ctx context.Context, filter inventory.LotStockFilter) ([]inventory.LotStock, error) {
+	start := time.Now()
+	lotStocks, err := s.next.ListLotStocks(ctx, filter)
+	s.observeRows("ListLotStocks", start, len(lotStocks), err)
+	return lotStocks, err
+}
+
+// AdjustConsignmentStock adds delta to the consignment stock of an owner
+// 所有者の委託在庫の数量を増減
+func (s *InstrumentedStorage) AdjustConsignmentStock(ctx context.Context, ownerID, itemID, locationID string, delta int64) (*inventory.ConsignmentStock, error) {
+	start := time.Now()
+	consignment, err := s.next.AdjustConsignmentStock(ctx, ownerID, itemID, locationID, delta)
+	s.observe("AdjustConsignmentStock", start, err)
+	return consignment, err
+}
+
+// ListConsignmentStocks lists consignment stocks matching a filter
+// 条件に一致する委託在庫を取得
+func (s *InstrumentedStorage) ListConsignmentStocks(ctx context.Context, filter inventory.ConsignmentStockFilter) ([]inventory.ConsignmentStock, error) {
+	start := time.Now()
+	consignments, err := s.next.ListConsignmentStocks(ctx, filter)
+	s.observeRows("ListConsignmentStocks", start, len(consignments), err)
+	return consignments, err
+}
+
+// CreateAlert creates a new alert
+// 新しいアラートを作成
+func (s *InstrumentedStorage) CreateAlert(ctx context.Context, alert *inventory.StockAlert) error {
+	start := time.Now()
+	err := s.next.CreateAlert(ctx, alert)
+	s.observe("CreateAlert", start, err)
+	return err
+}
+
+// GetActiveAlerts retrieves active alerts for a location
+// ロケーションのアクティブなアラートを取得
+func (s *InstrumentedStorage) GetActiveAlerts(ctx context.Context, locationID string) ([]inventory.StockAlert, error) {
+	start := time.Now()
+	alerts, err := s.next.GetActiveAlerts(ctx, locationID)
+	s.observeRows("GetActiveAlerts", start, len(alerts), err)
+	return alerts, err
+}
+
+// ListAlerts lists alerts of all locations matching a filter, newest first
+// 条件に一致するアラートを全ロケーションから作成日時の新しい順に取得（解決済みを含む）
+func (s *InstrumentedStorage) ListAlerts(ctx context.Context, filter inventory.AlertFilter) ([]inventory.StockAlert, error) {
+	start := time.Now()
+	alerts, err := s.next.ListAlerts(ctx, filter)
+	s.observe("ListAlerts", start, err)
+	return alerts, err
+}
+
+// GetAlert retrieves an alert by ID
+// IDでアラートを取得
+func (s *InstrumentedStorage) GetAlert(ctx context.Context, alertID string) (*inventory.StockAlert, error) {
+	start := time.Now()
+	alert, err := s.next.GetAlert(ctx, alertID)
+	s.observe("GetAlert", start, err)
+	return alert, err
+}
+
+// ResolveAlert marks an alert as resolved
+// アラートを解決済みにする
+func (s *InstrumentedStorage) ResolveAlert(ctx context.Context, alertID, resolvedBy, note string) error {
+	start := time.Now()
+	err := s.next.ResolveAlert(ctx, alertID, resolvedBy, note)
+	s.observe("ResolveAlert", start, err)
+	return err
+}
+
+// AcknowledgeAlert marks an alert as acknowledged
+// アラートを確認済みにする
+func (s *InstrumentedStorage) AcknowledgeAlert(ctx context.Context, alertID, acknowledgedBy, note string) error {
+	start := time.Now()
+	err := s.next.AcknowledgeAlert(ctx, alertID, acknowledgedBy, note)
+	s.observe("AcknowledgeAlert", start, err)
+	return err
+}
+
+// GetLatestRuleAlert retrieves the latest alert an alert rule raised for a stock record
+// アラートルールが在庫記録に対して最後に作成したアラートを取得
+func (s *InstrumentedStorage) GetLatestRuleAlert(ctx context.Context, ruleID, itemID, locationID string) (*inventory.StockAlert, error) {
+	start := time.Now()
+	alert, err := s.next.GetLatestRuleAlert(ctx, ruleID, itemID, locationID)
+	s.observe("GetLatestRuleAlert", start, err)
+	return alert, err
+}
+
+// CreateAlertRule creates a new alert rule
+// 新しいアラートルールを作成
+func (s *InstrumentedStorage) CreateAlertRule(ctx context.Context, rule *inventory.AlertRule) error {
+	start := time.Now()
+	err := s.next.CreateAlertRule(ctx, rule)
+	s.observe("CreateAlertRule", start, err)
+	return err
+}
+
+// GetAlertRule retrieves an alert rule by ID
+// IDでアラートルールを取得
+func (s *InstrumentedStorage) GetAlertRule(ctx context.Context, ruleID string) (*inventory.AlertRule, error) {
+	start := time.Now()
+	rule, err := s.next.GetAlertRule(ctx, ruleID)
+	s.observe("GetAlertRule", start, err)
+	return rule, err
+}
+
+// UpdateAlertRule replaces the condition, scope and state of an alert rule
+// アラートルールを更新
+func (s *InstrumentedStorage) UpdateAlertRule(ctx context.Context, rule *inventory.AlertRule) error {
+	start := time.Now()
+	err := s.next.UpdateAlertRule(ctx, rule)
+	s.observe("UpdateAlertRule", start, err)
+	return err
+}
+
+// DeleteAlertRule deletes an alert rule
+// アラートルールを削除
+func (s *InstrumentedStorage) DeleteAlertRule(ctx context.Context, ruleID string) error {
+	start := time.Now()
+	err := s.next.DeleteAlertRule(ctx, ruleID)
+	s.observe("DeleteAlertRule", start, err)
+	return err
+}
+
+// ListAlertRules lists alert rules matching a filter
+// 条件に一致するアラートルールを取得
+func (s *InstrumentedStorage) ListAlertRules(ctx context.Context, filter inventory.AlertRuleFilter) ([]inventory.AlertRule, error) {
+	start := time.Now()
+	rules, err := s.next.ListAlertRules(ctx, filter)
+	s.observe("ListAlertRules", start, err)
+	return rules, err
+}
+
+// GetAlertRulesForStock retrieves the enabled alert rules whose scope covers a stock record
+// 在庫記録を対象範囲に含む有効なアラートルールを取得
+func (s *InstrumentedStorage) GetAlertRulesForStock(ctx context.Context, itemID, locationID string) ([]inventory.AlertRule, error) {
+	start := time.Now()
+	rules, err := s.next.GetAlertRulesForStock(ctx, itemID, locationID)
+	s.observe("GetAlertRulesForStock", start, err)
+	return rules, err
+}
+
+// CreateReservation creates a new reservation
+// 新しい予約を作成
+func (s *InstrumentedStorage) CreateReservation(ctx context.Context, reservation *inventory.Reservation) error {
+	start := time.Now()
+	err := s.next.CreateReservation(ctx, reservation)
+	s.observe("CreateReservation", start, err)
+	return err
+}
+
+// GetReservation retrieves a reservation by ID
+// IDで予約を取得
+func (s *InstrumentedStorage) GetReservation(ctx context.Context, reservationID string) (*inventory.Reservation, error) {
+	start := time.Now()
+	reservation, err := s.next.GetReservation(ctx, reservationID)
+	s.observe("GetReservation", start, err)
+	return reservation, err
+}
+
+// UpdateReservation updates an active reservation
+// 有効な予約を更新
+func (s *InstrumentedStorage) UpdateReservation(ctx context.Context, reservation *inventory.Reservation) error {
+	start := time.Now()
+	err := s.next.UpdateReservation(ctx, reservation)
+	s.observe("UpdateReservation", start, err)
+	return err
+}
+
+// ListReservations lists reservations matching a filter
+// 条件に一致する予約を取得
+func (s *InstrumentedStorage) ListReservations(ctx context.Context, filter inventory.ReservationFilter) ([]inventory.Reservation, error) {
+	start := time.Now()
+	reservations, err := s.next.ListReservations(ctx, filter)
+	s.observe("ListReservations", start, err)
+	return reservations, err
+}
+
+// GetExpiredReservations retrieves active reservations that expired before a time
+// 有効期限を過ぎた有効な予約を取得
+func (s *InstrumentedStorage) GetExpiredReservations(ctx context.Context, before time.Time, limit int) ([]inventory.Reservation, error) {
+	start := time.Now()
+	reservations, err := s.next.GetExpiredReservations(ctx, before, limit)
+	s.observe("GetExpiredReservations", start, err)
+	return reservations, err
+}
+
+// SetChannelAllocation creates, updates or deletes a sales channel allocation
+// 販売チャネルの確保数量を作成・更新・削除
+func (s *InstrumentedStorage) SetChannelAllocation(ctx context.Context, allocation *inventory.ChannelAllocation) error {
+	start := time.Now()
+	err := s.next.SetChannelAllocation(ctx, allocation)
+	s.observe("SetChannelAllocation", start, err)
+	return err
+}
+
+// ListChannelAllocations lists the sales channel allocations of an item at a location
+// 商品のロケーションの販売チャネルの確保を取得
+func (s *InstrumentedStorage) ListChannelAllocations(ctx context.Context, itemID, locationID string) ([]inventory.ChannelAllocation, error) {
+	start := time.Now()
+	allocations, err := s.next.ListChannelAllocations(ctx, itemID, locationID)
+	s.observeRows("ListChannelAllocations", start, len(allocations), err)
+	return allocations, err
+}
+
+// CreateBackorder creates a new backorder
+// 新しいバックオーダーを作成
+func (s *InstrumentedStorage) CreateBackorder(ctx context.Context, backorder *inventory.Backorder) error {
+	start := time.Now()
+	err := s.next.CreateBackorder(ctx, backorder)
+	s.observe("CreateBackorder", start, err)
+	return err
+}
+
+// GetBackorder retrieves a backorder by ID
+// IDでバックオーダーを取得
+func (s *InstrumentedStorage) GetBackorder(ctx context.Context, backorderID string) (*inventory.Backorder, error) {
+	start := time.Now()
+	backorder, err := s.next.GetBackorder(ctx, backorderID)
+	s.observe("GetBackorder", start, err)
+	return backorder, err
+}
+
+// UpdateBackorder updates a pending backorder
+// 入荷待ちのバックオーダーを更新
+func (s *InstrumentedStorage) UpdateBackorder(ctx context.Context, backorder *inventory.Backorder) error {
+	start := time.Now()
+	err := s.next.UpdateBackorder(ctx, backorder)
+	s.observe("UpdateBackorder", start, err)
+	return err
+}
+
+// ListBackorders lists backorders matching a filter
+// 条件に一致するバックオーダーを取得
+func (s *InstrumentedStorage) ListBackorders(ctx context.Context, filter inventory.BackorderFilter) ([]inventory.Backorder, error) {
+	start := time.Now()
+	backorders, err := s.next.ListBackorders(ctx, filter)
+	s.observe("ListBackorders", start, err)
+	return backorders, err
+}
+
+// SaveReorderPoint creates or overwrites the reorder point of an item at a location
+// 商品・ロケーションの発注点を作成または上書き
+func (s *InstrumentedStorage) SaveReorderPoint(ctx context.Context, reorderPoint *inventory.ReorderPoint) error {
+	start := time.Now()
+	err := s.next.SaveReorderPoint(ctx, reorderPoint)
+	s.observe("SaveReorderPoint", start, err)
+	return err
+}
+
+// GetReorderPoint retrieves the reorder point of an item at a location
+// 商品・ロケーションの発注点を取得
+func (s *InstrumentedStorage) GetReorderPoint(ctx context.Context, itemID, locationID string) (*inventory.ReorderPoint, error) {
+	start := time.Now()
+	reorderPoint, err := s.next.GetReorderPoint(ctx, itemID, locationID)
+	s.observe("GetReorderPoint", start, err)
+	return reorderPoint, err
+}
+
+// DeleteReorderPoint deletes the reorder point of an item at a location
+// 商品・ロケーションの発注点を削除
+func (s *InstrumentedStorage) DeleteReorderPoint(ctx context.Context, itemID, locationID string) error {
+	start := time.Now()
+	err := s.next.DeleteReorderPoint(ctx, itemID, locationID)
+	s.observe("DeleteReorderPoint", start, err)
+	return err
+}
+
+// ListReorderPoints lists reorder points matching a filter
+// 条件に一致する発注点を取得
+func (s *InstrumentedStorage) ListReorderPoints(ctx context.Context, filter inventory.ReorderPointFilter) ([]inventory.ReorderPoint, error) {
+	start := time.Now()
+	reorderPoints, err := s.next.ListReorderPoints(ctx, filter)
+	s.observe("ListReorderPoints", start, err)
+	return reorderPoints, err
+}
+
+// CreateStockSnapshot copies every stock record into a new snapshot
+// 全ての在庫記録を複製してスナップショットを作成
+func (s *InstrumentedStorage) CreateStockSnapshot(ctx context.Context, snapshot *inventory.StockSnapshot) error {
+	start := time.Now()
+	err := s.next.CreateStockSnapshot(ctx, snapshot)
+	s.observe("CreateStockSnapshot", start, err)
+	return err
+}
+
+// ListStockSnapshots lists stock snapshots, newest first
+// スナップショットを取得日時の新しい順に取得
+func (s *InstrumentedStorage) ListStockSnapshots(ctx context.Context, offset, limit int) ([]inventory.StockSnapshot, error) {
+	start := time.Now()
+	snapshots, err := s.next.ListStockSnapshots(ctx, offset, limit)
+	s.observe("ListStockSnapshots", start, err)
+	return snapshots, err
+}
+
+// GetStockSnapshotLines retrieves the stock records copied into a snapshot
+// スナップショットの在庫記録を取得
+func (s *InstrumentedStorage) GetStockSnapshotLines(ctx context.Context, snapshotID, locationID string) ([]inventory.StockSnapshotLine, error) {
+	start := time.Now()
+	lines, err := s.next.GetStockSnapshotLines(ctx, snapshotID, locationID)
+	s.observe("GetStockSnapshotLines", start, err)
+	return lines, err
+}
+
+// CreateStocktake creates a stocktake with its lines
+// 棚卸と行を作成
+func (s *InstrumentedStorage) CreateStocktake(ctx context.Context, stocktake *inventory.Stocktake) error {
+	start := time.Now()
+	err := s.next.CreateStocktake(ctx, stocktake)
+	s.observe("CreateStocktake", start, err)
+	return err
+}
+
+// GetStocktake retrieves a stocktake with its lines
+// 棚卸を行とともに取得
+func (s *InstrumentedStorage) GetStocktake(ctx context.Context, stocktakeID string) (*inventory.Stocktake, error) {
+	start := time.Now()
+	stocktake, err := s.next.GetStocktake(ctx, stocktakeID)
+	s.observe("GetStocktake", start, err)
+	return stocktake, err
+}
+
+// ListStocktakes lists stocktakes matching a filter
+// 条件に一致する棚卸を取得
+func (s *InstrumentedStorage) ListStocktakes(ctx context.Context, filter inventory.StocktakeFilter) ([]inventory.Stocktake, error) {
+	start := time.Now()
+	stocktakes, err := s.next.ListStocktakes(ctx, filter)
+	s.observe("ListStocktakes", start, err)
+	return stocktakes, err
+}
+
+// UpdateStocktake updates the status and lines of a stocktake in status from
+// 状態がfromの棚卸の状態・行を更新
+func (s *InstrumentedStorage) UpdateStocktake(ctx context.Context, stocktake *inventory.Stocktake, from inventory.StocktakeStatus) error {
+	start := time.Now()
+	err := s.next.UpdateStocktake(ctx, stocktake, from)
+	s.observe("UpdateStocktake", start, err)
+	return err
+}
+
+// CreateAdjustment creates a stock adjustment
+// 在庫調整を作成
+func (s *InstrumentedStorage) CreateAdjustment(ctx context.Context, adjustment *inventory.Adjustment) error {
+	start := time.Now()
+	err := s.next.CreateAdjustment(ctx, adjustment)
+	s.observe("CreateAdjustment", start, err)
+	return err
+}
+
+// GetAdjustment retrieves a stock adjustment by ID
+// IDで在庫調整を取得
+func (s *InstrumentedStorage) GetAdjustment(ctx context.Context, adjustmentID string) (*inventory.Adjustment, error) {
+	start := time.Now()
+	adjustment, err := s.next.GetAdjustment(ctx, adjustmentID)
+	s.observe("GetAdjustment", start, err)
+	return adjustment, err
+}
+
+// ListAdjustments lists stock adjustments matching a filter
+// 条件に一致する在庫調整を取得
+func (s *InstrumentedStorage) ListAdjustments(ctx context.Context, filter inventory.AdjustmentFilter) ([]inventory.Adjustment, error) {
+	start := time.Now()
+	adjustments, err := s.next.ListAdjustments(ctx, filter)
+	s.observe("ListAdjustments", start, err)
+	return adjustments, err
+}
+
+// UpdateAdjustment records the decision on a stock adjustment in status from
+// 状態がfromの在庫調整に承認・却下の結果を記録
+func (s *InstrumentedStorage) UpdateAdjustment(ctx context.Context, adjustment *inventory.Adjustment, from inventory.AdjustmentStatus) error {
+	start := time.Now()
+	err := s.next.UpdateAdjustment(ctx, adjustment, from)
+	s.observe("UpdateAdjustment", start, err)
+	return err
+}
+
+// CreateInspection creates a receiving inspection
+// 入荷検品を作成
+func (s *InstrumentedStorage) CreateInspection(ctx context.Context, inspection *inventory.Inspection) error {
+	start := time.Now()
+	err := s.next.CreateInspection(ctx, inspection)
+	s.observe("CreateInspection", start, err)
+	return err
+}
+
+// GetInspection retrieves a receiving inspection by ID
+// IDで入荷検品を取得
+func (s *InstrumentedStorage) GetInspection(ctx context.Context, inspectionID string) (*inventory.Inspection, error) {
+	start := time.Now()
+	inspection, err := s.next.GetInspection(ctx, inspectionID)
+	s.observe("GetInspection", start, err)
+	return inspection, err
+}
+
+// ListInspections lists receiving inspections matching a filter
+// 条件に一致する入荷検品を取得
+func (s *InstrumentedStorage) ListInspections(ctx context.Context, filter inventory.InspectionFilter) ([]inventory.Inspection, error) {
+	start := time.Now()
+	inspections, err := s.next.ListInspections(ctx, filter)
+	s.observe("ListInspections", start, err)
+	return inspections, err
+}
+
+// UpdateInspection records the disposition of a receiving inspection in status from
+// 状態がfromの入荷検品に合否の判定を記録
+func (s *InstrumentedStorage) UpdateInspection(ctx context.Context, inspection *inventory.Inspection, from inventory.InspectionStatus) error {
+	start := time.Now()
+	err := s.next.UpdateInspection(ctx, inspection, from)
+	s.observe("UpdateInspection", start, err)
+	return err
+}
+
+// SaveBatchOperation creates or overwrites the record of a batch operation
+// バッチ操作の記録を作成または上書き
+func (s *InstrumentedStorage) SaveBatchOperation(ctx context.Context, batch *inventory.BatchOperation) error {
+	start := time.Now()
+	err := s.next.SaveBatchOperation(ctx, batch)
+	s.observe("SaveBatchOperation", start, err)
+	return err
+}
+
+// GetBatchOperation retrieves a batch operation by ID
+// IDでバッチ操作を取得
+func (s *InstrumentedStorage) GetBatchOperation(ctx context.Context, batchID string) (*inventory.BatchOperation, error) {
+	start := time.Now()
+	batch, err := s.next.GetBatchOperation(ctx, batchID)
+	s.observe("GetBatchOperation", start, err)
+	return batch, err
+}
+
+// GetInventorySummary aggregates the whole inventory
+// 在庫全体を集計
+func (s *InstrumentedStorage) GetInventorySummary(ctx context.Context, lowStockThreshold int64) (*inventory.InventorySummary, error) {
+	start := time.Now()
+	summary, err := s.next.GetInventorySummary(ctx, lowStockThreshold)
+	s.observe("GetInventorySummary", start, err)
+	return summary, err
+}
+
+// Ping checks the underlying storage health
+// 下位ストレージの健全性を確認
+func (s *InstrumentedStorage) Ping(ctx context.Context) error {
+	start := time.Now()
+	err := s.next.Ping(ctx)
+	s.observe("Ping", start, err)
+	return err
+}
+
+// Close closes the underlying storage
+// 下位ストレージを閉じる
+func (s *InstrumentedStorage) Close() error {
+	return s.next.Close()
+}
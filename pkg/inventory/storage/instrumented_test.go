@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// TestInstrumentedStorage はストレージ操作のメトリクスの記録のテスト
+func TestInstrumentedStorage(t *testing.T) {
+	ctx := context.Background()
+	registry := prometheus.NewRegistry()
+	store, err := NewInstrumentedStorage(newTestMemoryStorage(t), registry)
+	require.NoError(t, err)
+
+	// 成功した呼び出しは処理時間のみ、失敗した呼び出しはエラー数も記録する
+	now := time.Now()
+	require.NoError(t, store.CreateStock(ctx, &inventory.Stock{ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 5, Available: 5, UpdatedAt: now}))
+	_, err = store.GetStock(ctx, "TEST-ITEM", "LOC-B")
+	assert.ErrorIs(t, err, inventory.ErrStockNotFound)
+	assert.Equal(t, 0.0, testutil.ToFloat64(store.metrics.errors.WithLabelValues("CreateStock")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(store.metrics.errors.WithLabelValues("GetStock")))
+
+	// 一覧系は返却件数も記録する
+	stocks, err := store.ListStockByLocation(ctx, "LOC-A")
+	require.NoError(t, err)
+	assert.Len(t, stocks, 1)
+
+	// トランザクション内の呼び出しも同じコレクターで計測する
+	require.NoError(t, store.WithinTx(ctx, func(tx inventory.Storage) error {
+		_, err := tx.GetStock(ctx, "TEST-ITEM", "LOC-A")
+		return err
+	}))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	counts := make(map[string]map[string]uint64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			if metric.GetHistogram() == nil {
+				continue
+			}
+			if counts[family.GetName()] == nil {
+				counts[family.GetName()] = make(map[string]uint64)
+			}
+			counts[family.GetName()][metric.GetLabel()[0].GetValue()] = metric.GetHistogram().GetSampleCount()
+		}
+	}
+	durations := counts["zai_inventory_storage_operation_duration_seconds"]
+	assert.Equal(t, uint64(1), durations["CreateStock"])
+	assert.Equal(t, uint64(2), durations["GetStock"], "トランザクション内の呼び出しを含む")
+	assert.Equal(t, uint64(1), durations["ListStockByLocation"])
+	assert.Equal(t, uint64(1), durations["WithinTx"])
+	assert.Equal(t, map[string]uint64{"ListStockByLocation": 1}, counts["zai_inventory_storage_rows_returned"])
+
+	// 同じレジストリに再度登録しても既存のコレクターを再利用する
+	again, err := NewInstrumentedStorage(NewMemoryStorage(), registry)
+	require.NoError(t, err)
+	assert.Same(t, store.metrics.errors, again.metrics.errors)
+}
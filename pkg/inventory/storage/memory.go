@@ -0,0 +1,3339 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// MemoryStorage implements the Storage interface with in-memory maps
+// インメモリのマップを使用したStorageインターフェースの実装
+//
+// 単体テストやデモ用途を想定しており、PostgreSQLなしで在庫管理機能を利用できます。
+// すべての操作はミューテックスで保護されており、複数のゴルーチンから安全に呼び出せます。
+// 返却値はコピーであるため、呼び出し側で変更しても内部状態には影響しません。
+type MemoryStorage struct {
+	mu           sync.RWMutex
+	items        map[string]inventory.Item
+	locations    map[string]inventory.Location
+	stocks       map[stockKey]inventory.Stock
+	transactions []inventory.Transaction
+	archived     []inventory.Transaction // ArchiveTransactionsで移動したトランザクション
+	lots         map[string]inventory.Lot
+	lotStocks    map[lotStockKey]inventory.LotStock
+	consigned    map[consignmentKey]inventory.ConsignmentStock // 所有者・商品・ロケーションごとの委託在庫
+	channels     map[channelKey]inventory.ChannelAllocation    // 商品・ロケーション・販売チャネルごとの確保
+	units        map[string]inventory.UnitOfMeasure
+	conversions  map[unitConversionKey]inventory.UnitConversion
+	alerts       map[string]inventory.StockAlert
+	batches      map[string]inventory.BatchOperation
+	reservations map[string]inventory.Reservation
+	backorders   map[string]inventory.Backorder
+	reorder      map[stockKey]inventory.ReorderPoint
+	alertRules   map[string]inventory.AlertRule
+	snapshots    map[string]inventory.StockSnapshot
+	snapshotRows map[string][]inventory.StockSnapshotLine // スナップショットIDごとの在庫記録
+	stocktakes   map[string]inventory.Stocktake           // 行を含む棚卸
+	adjustments  map[string]inventory.Adjustment
+	inspections  map[string]inventory.Inspection
+	boms         map[string]inventory.BillOfMaterials // キットの商品IDごとの部品表
+	assemblies   map[string]inventory.Assembly
+	suppliers    map[string]inventory.Supplier
+	sourcing     map[supplierItemKey]inventory.SupplierItem // 仕入先・商品ごとの仕入条件
+	purchases    map[string]inventory.PurchaseOrder         // 行を含む発注
+	receipts     map[string][]inventory.GoodsReceipt        // 発注IDごとの入荷（入荷日時の古い順）
+	sales        map[string]inventory.SalesOrder            // 行を含む受注
+	returns      map[string]inventory.ReturnAuthorization   // 行を含む返品
+	dispositions map[string][]inventory.ReturnDisposition   // 返品IDごとの処分（処分日時の古い順）
+}
+
+// stockKey identifies a stock record by item and location
+// 商品とロケーションで在庫記録を識別するキー
+type stockKey struct {
+	itemID     string
+	locationID string
+}
+
+// lotStockKey identifies a lot stock record by lot and location
+// ロットとロケーションでロット在庫を識別するキー
+type lotStockKey struct {
+	lotID      string
+	locationID string
+}
+
+// consignmentKey identifies a consignment stock record by owner, item and location
+// 所有者・商品・ロケーションで委託在庫を識別するキー
+type consignmentKey struct {
+	ownerID    string
+	itemID     string
+	locationID string
+}
+
+// channelKey identifies a sales channel allocation by item, location and channel
+// 商品・ロケーション・販売チャネルで販売チャネルの確保を識別するキー
+type channelKey struct {
+	itemID     string
+	locationID string
+	channel    string
+}
+
+// supplierItemKey identifies the purchasing terms of an item by supplier and item
+// 仕入先と商品で仕入条件を識別するキー
+type supplierItemKey struct {
+	supplierID string
+	itemID     string
+}
+
+// unitConversionKey identifies a unit conversion by item and unit of measure
+// 商品と単位で単位の換算を識別するキー
+type unitConversionKey struct {
+	itemID string
+	uom    string
+}
+
+var _ inventory.Storage = (*MemoryStorage)(nil)
+
+// NewMemoryStorage creates a new in-memory storage instance
+// 新しいインメモリストレージインスタンスを作成
+//
+// 組み込みの単位（inventory.DefaultUnitsOfMeasure）は登録済みの状態で作成します。
+func NewMemoryStorage() *MemoryStorage {
+	s := &MemoryStorage{
+		items:       make(map[string]inventory.Item),
+		locations:   make(map[string]inventory.Location),
+		stocks:      make(map[stockKey]inventory.Stock),
+		lots:        make(map[string]inventory.Lot),
+		lotStocks:   make(map[lotStockKey]inventory.LotStock),
+		consigned:   make(map[consignmentKey]inventory.ConsignmentStock),
+		channels:    make(map[channelKey]inventory.ChannelAllocation),
+		units:       make(map[string]inventory.UnitOfMeasure, len(inventory.DefaultUnitsOfMeasure)),
+		conversions: make(map[unitConversionKey]inventory.UnitConversion),
+		alerts:      make(map[string]inventory.StockAlert),
+		batches:     make(map[string]inventory.BatchOperation),
+
+		reservations: make(map[string]inventory.Reservation),
+		backorders:   make(map[string]inventory.Backorder),
+		reorder:      make(map[stockKey]inventory.ReorderPoint),
+		alertRules:   make(map[string]inventory.AlertRule),
+		snapshots:    make(map[string]inventory.StockSnapshot),
+		snapshotRows: make(map[string][]inventory.StockSnapshotLine),
+		stocktakes:   make(map[string]inventory.Stocktake),
+		adjustments:  make(map[string]inventory.Adjustment),
+		inspections:  make(map[string]inventory.Inspection),
+		boms:         make(map[string]inventory.BillOfMaterials),
+		assemblies:   make(map[string]inventory.Assembly),
+		suppliers:    make(map[string]inventory.Supplier),
+		sourcing:     make(map[supplierItemKey]inventory.SupplierItem),
+		purchases:    make(map[string]inventory.PurchaseOrder),
+		receipts:     make(map[string][]inventory.GoodsReceipt),
+		sales:        make(map[string]inventory.SalesOrder),
+		returns:      make(map[string]inventory.ReturnAuthorization),
+		dispositions: make(map[string][]inventory.ReturnDisposition),
+	}
+
+	now := time.Now()
+	for _, unit := range inventory.DefaultUnitsOfMeasure {
+		unit.CreatedAt = now
+		s.units[unit.Code] = unit
+	}
+	return s
+}
+
+// WithinTx runs fn against a snapshot and applies it only when fn succeeds
+// スナップショットに対してfnを実行し、成功した場合のみ変更を反映
+//
+// 実行中はストレージ全体をロックするため、他のゴルーチンからの操作はコミットまで待機します。
+// fnの中では必ず引数のtxStorageを使用してください（元のストレージを呼ぶとデッドロックします）。
+func (s *MemoryStorage) WithinTx(ctx context.Context, fn func(txStorage inventory.Storage) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	txStorage := s.snapshot()
+	if err := fn(txStorage); err != nil {
+		return err
+	}
+
+	s.items = txStorage.items
+	s.locations = txStorage.locations
+	s.stocks = txStorage.stocks
+	s.transactions = txStorage.transactions
+	s.archived = txStorage.archived
+	s.lots = txStorage.lots
+	s.lotStocks = txStorage.lotStocks
+	s.consigned = txStorage.consigned
+	s.channels = txStorage.channels
+	s.units = txStorage.units
+	s.conversions = txStorage.conversions
+	s.alerts = txStorage.alerts
+	s.batches = txStorage.batches
+	s.reservations = txStorage.reservations
+	s.backorders = txStorage.backorders
+	s.reorder = txStorage.reorder
+	s.alertRules = txStorage.alertRules
+	s.snapshots = txStorage.snapshots
+	s.snapshotRows = txStorage.snapshotRows
+	s.stocktakes = txStorage.stocktakes
+	s.adjustments = txStorage.adjustments
+	s.inspections = txStorage.inspections
+	s.boms = txStorage.boms
+	s.assemblies = txStorage.assemblies
+	s.suppliers = txStorage.suppliers
+	s.sourcing = txStorage.sourcing
+	s.purchases = txStorage.purchases
+	s.receipts = txStorage.receipts
+	s.sales = txStorage.sales
+	s.returns = txStorage.returns
+	s.dispositions = txStorage.dispositions
+
+	return nil
+}
+
+// CreateStock creates a new stock record
+// 新しい在庫記録を作成
+func (s *MemoryStorage) CreateStock(ctx context.Context, stock *inventory.Stock) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := stockKey{itemID: stock.ItemID, locationID: stock.LocationID}
+	if _, exists := s.stocks[key]; exists {
+		return fmt.Errorf("在庫記録は既に存在します")
+	}
+
+	record := *stock
+	record.CalculateAvailable()
+	s.stocks[key] = record
+
+	return nil
+}
+
+// UpdateStock updates an existing stock record using optimistic locking
+// 楽観的ロックを使用して既存の在庫記録を更新
+func (s *MemoryStorage) UpdateStock(ctx context.Context, stock *inventory.Stock) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := stockKey{itemID: stock.ItemID, locationID: stock.LocationID}
+	current, exists := s.stocks[key]
+	if !exists || current.Version != stock.Version-1 {
+		return inventory.ErrVersionMismatch
+	}
+
+	record := *stock
+	record.CalculateAvailable()
+	s.stocks[key] = record
+
+	return nil
+}
+
+// CreateOrUpdateStocks upserts multiple stock records
+// 複数の在庫記録を一括作成・更新
+func (s *MemoryStorage) CreateOrUpdateStocks(ctx context.Context, stocks []inventory.Stock) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, stock := range stocks {
+		key := stockKey{itemID: stock.ItemID, locationID: stock.LocationID}
+
+		stock.Version = 1
+		if current, exists := s.stocks[key]; exists {
+			stock.Version = current.Version + 1
+		}
+		if stock.UpdatedAt.IsZero() {
+			stock.UpdatedAt = now
+		}
+		stock.CalculateAvailable()
+
+		s.stocks[key] = stock
+	}
+
+	return nil
+}
+
+// BulkImport inserts items, stocks and transactions in a single transaction
+// 商品・在庫・トランザクションを単一のトランザクション内で一括挿入
+func (s *MemoryStorage) BulkImport(ctx context.Context, data *inventory.BulkImportData) error {
+	return s.WithinTx(ctx, func(txStorage inventory.Storage) error {
+		for i := range data.Items {
+			if err := txStorage.CreateItem(ctx, &data.Items[i]); err != nil {
+				return err
+			}
+		}
+		for i := range data.Stocks {
+			if err := txStorage.CreateStock(ctx, &data.Stocks[i]); err != nil {
+				return err
+			}
+		}
+		for i := range data.Transactions {
+			if err := txStorage.CreateTransaction(ctx, &data.Transactions[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetStock retrieves stock information for an item at a location
+// 指定ロケーションの商品在庫情報を取得
+func (s *MemoryStorage) GetStock(ctx context.Context, itemID, locationID string) (*inventory.Stock, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stock, exists := s.stocks[stockKey{itemID: itemID, locationID: locationID}]
+	if !exists {
+		return nil, inventory.ErrStockNotFound
+	}
+
+	return &stock, nil
+}
+
+// GetStocks retrieves the stock of many item and location pairs
+// 複数の商品とロケーションの組の在庫情報を取得
+func (s *MemoryStorage) GetStocks(ctx context.Context, keys []inventory.StockKey) ([]inventory.Stock, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stocks := make([]inventory.Stock, 0, len(keys))
+	for _, key := range keys {
+		if stock, exists := s.stocks[stockKey{itemID: key.ItemID, locationID: key.LocationID}]; exists {
+			stocks = append(stocks, stock)
+		}
+	}
+
+	return stocks, nil
+}
+
+// GetStockForUpdate retrieves stock information for update
+// 更新用に在庫情報を取得
+//
+// WithinTxの実行中はストレージ全体がロックされているため、GetStockと同じ動作になります。
+func (s *MemoryStorage) GetStockForUpdate(ctx context.Context, itemID, locationID string) (*inventory.Stock, error) {
+	return s.GetStock(ctx, itemID, locationID)
+}
+
+// ListStockByLocation retrieves all stock at a specific location
+// 指定ロケーションのすべての在庫を取得
+func (s *MemoryStorage) ListStockByLocation(ctx context.Context, locationID string) ([]inventory.Stock, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var stocks []inventory.Stock
+	for key, stock := range s.stocks {
+		if key.locationID == locationID {
+			stocks = append(stocks, stock)
+		}
+	}
+
+	sort.Slice(stocks, func(i, j int) bool {
+		return stocks[i].ItemID < stocks[j].ItemID
+	})
+
+	return stocks, nil
+}
+
+// ListStockByItem retrieves the stock of an item at every location
+// 商品の全ロケーションの在庫を取得
+func (s *MemoryStorage) ListStockByItem(ctx context.Context, itemID string) ([]inventory.Stock, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var stocks []inventory.Stock
+	for key, stock := range s.stocks {
+		if key.itemID == itemID {
+			stocks = append(stocks, stock)
+		}
+	}
+
+	sort.Slice(stocks, func(i, j int) bool {
+		return stocks[i].LocationID < stocks[j].LocationID
+	})
+
+	return stocks, nil
+}
+
+// ForEachStockByLocation streams stock at a specific location to fn
+// 指定ロケーションの在庫を1件ずつfnに渡す
+//
+// fnの中からストレージを呼び出せるよう、ロックを解放してからfnを呼び出します。
+func (s *MemoryStorage) ForEachStockByLocation(ctx context.Context, locationID string, fn func(stock inventory.Stock) error) error {
+	stocks, err := s.ListStockByLocation(ctx, locationID)
+	if err != nil {
+		return err
+	}
+
+	for _, stock := range stocks {
+		if err := fn(stock); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetTotalStockByItem retrieves total stock quantity for an item across all locations
+// 商品の全ロケーションでの合計在庫数を取得
+func (s *MemoryStorage) GetTotalStockByItem(ctx context.Context, itemID string) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var total int64
+	for key, stock := range s.stocks {
+		if key.itemID == itemID {
+			total += stock.Quantity
+		}
+	}
+
+	return total, nil
+}
+
+// GetTotalStockByLocation retrieves total stock quantity of all items at a location
+// ロケーションの全商品の合計在庫数を取得
+func (s *MemoryStorage) GetTotalStockByLocation(ctx context.Context, locationID string) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var total int64
+	for key, stock := range s.stocks {
+		if key.locationID == locationID {
+			total += stock.Quantity
+		}
+	}
+
+	return total, nil
+}
+
+// CreateTransaction creates a new transaction record
+// 新しいトランザクション記録を作成
+func (s *MemoryStorage) CreateTransaction(ctx context.Context, tx *inventory.Transaction) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.transactions = append(s.transactions, copyTransaction(*tx))
+
+	return nil
+}
+
+// GetTransactionByID retrieves a single transaction record by ID
+// IDでトランザクション記録を取得
+func (s *MemoryStorage) GetTransactionByID(ctx context.Context, txID string) (*inventory.Transaction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for i := range s.transactions {
+		if s.transactions[i].ID == txID {
+			result := copyTransaction(s.transactions[i])
+			return &result, nil
+		}
+	}
+
+	return nil, inventory.ErrTransactionNotFound
+}
+
+// GetTransactionHistory retrieves transaction history for an item
+// 商品のトランザクション履歴を取得
+func (s *MemoryStorage) GetTransactionHistory(ctx context.Context, itemID string, limit int) ([]inventory.Transaction, error) {
+	return s.filterTransactions(limit, func(tx *inventory.Transaction) bool {
+		return tx.ItemID == itemID
+	}), nil
+}
+
+// GetTransactionHistoryAfter retrieves transaction history older than the cursor
+// カーソルより古いトランザクション履歴を取得
+func (s *MemoryStorage) GetTransactionHistoryAfter(ctx context.Context, itemID string, after *inventory.HistoryCursor, limit int) ([]inventory.Transaction, error) {
+	return s.filterTransactions(limit, func(tx *inventory.Transaction) bool {
+		if tx.ItemID != itemID {
+			return false
+		}
+		if after == nil {
+			return true
+		}
+		return tx.CreatedAt.Before(after.AfterCreatedAt) ||
+			(tx.CreatedAt.Equal(after.AfterCreatedAt) && tx.ID < after.AfterID)
+	}), nil
+}
+
+// GetTransactionHistoryByLocation retrieves transaction history for a location
+// ロケーションのトランザクション履歴を取得
+func (s *MemoryStorage) GetTransactionHistoryByLocation(ctx context.Context, locationID string, limit int) ([]inventory.Transaction, error) {
+	return s.filterTransactions(limit, func(tx *inventory.Transaction) bool {
+		return (tx.FromLocation != nil && *tx.FromLocation == locationID) ||
+			(tx.ToLocation != nil && *tx.ToLocation == locationID)
+	}), nil
+}
+
+// GetTransactionHistoryByDateRange retrieves transaction history for an item within a date range
+// 商品の指定日付範囲のトランザクション履歴を取得
+func (s *MemoryStorage) GetTransactionHistoryByDateRange(ctx context.Context, itemID string, from, to time.Time) ([]inventory.Transaction, error) {
+	return s.filterTransactions(0, func(tx *inventory.Transaction) bool {
+		return tx.ItemID == itemID && !tx.CreatedAt.Before(from) && !tx.CreatedAt.After(to)
+	}), nil
+}
+
+// ForEachTransactionInRange streams transactions created in [from, to) to fn in chronological order
+// from以上to未満に作成されたトランザクションを古い順に1件ずつfnに渡す
+//
+// fnの中からストレージを呼び出せるよう、ロックを解放してからfnを呼び出します。
+func (s *MemoryStorage) ForEachTransactionInRange(ctx context.Context, from, to time.Time, fn func(tx inventory.Transaction) error) error {
+	transactions := s.filterTransactions(0, func(tx *inventory.Transaction) bool {
+		return !tx.CreatedAt.Before(from) && (to.IsZero() || tx.CreatedAt.Before(to))
+	})
+
+	// filterTransactionsは新しい順のため逆順に走査する
+	for i := len(transactions) - 1; i >= 0; i-- {
+		if err := fn(transactions[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetTransactionsByReference retrieves all transactions with the given reference number
+// 参照番号が一致するすべてのトランザクションを取得
+func (s *MemoryStorage) GetTransactionsByReference(ctx context.Context, reference string) ([]inventory.Transaction, error) {
+	return s.filterTransactions(0, func(tx *inventory.Transaction) bool {
+		return tx.Reference == reference
+	}), nil
+}
+
+// SearchTransactionsByMetadata retrieves transactions whose metadata key matches value
+// メタデータのキーと値が一致するトランザクションを取得
+func (s *MemoryStorage) SearchTransactionsByMetadata(ctx context.Context, key, value string, limit int) ([]inventory.Transaction, error) {
+	return s.filterTransactions(limit, func(tx *inventory.Transaction) bool {
+		v, ok := tx.Metadata[key]
+		return ok && v == value
+	}), nil
+}
+
+// ArchiveTransactions moves transactions created before the cutoff into the archive
+// 指定日時より前のトランザクションをアーカイブへ移動
+func (s *MemoryStorage) ArchiveTransactions(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	remaining := s.transactions[:0]
+	var archived int64
+	for _, tx := range s.transactions {
+		if tx.CreatedAt.Before(before) {
+			s.archived = append(s.archived, tx)
+			archived++
+			continue
+		}
+		remaining = append(remaining, tx)
+	}
+	s.transactions = remaining
+
+	return archived, nil
+}
+
+// CreateItem creates a new item
+// 新しい商品を作成
+func (s *MemoryStorage) CreateItem(ctx context.Context, item *inventory.Item) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.items[item.ID]; exists {
+		return inventory.ErrDuplicateItem
+	}
+	s.items[item.ID] = *item
+
+	return nil
+}
+
+// GetItem retrieves an item by ID
+// IDで商品を取得
+func (s *MemoryStorage) GetItem(ctx context.Context, itemID string) (*inventory.Item, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	item, exists := s.items[itemID]
+	if !exists {
+		return nil, inventory.ErrItemNotFound
+	}
+
+	return &item, nil
+}
+
+// UpdateItem updates an existing item
+// 既存の商品を更新
+func (s *MemoryStorage) UpdateItem(ctx context.Context, item *inventory.Item) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, exists := s.items[item.ID]
+	if !exists {
+		return inventory.ErrItemNotFound
+	}
+	if expected, ok := inventory.ExpectedUpdatedAt(ctx); ok && !current.UpdatedAt.Equal(expected) {
+		return inventory.ErrPreconditionFailed
+	}
+
+	record := *item
+	record.CreatedAt = current.CreatedAt
+	s.items[item.ID] = record
+
+	return nil
+}
+
+// DeleteItem deletes an item by ID
+// IDで商品を削除（PostgreSQLのON DELETE CASCADEと同様に関連データも削除）
+func (s *MemoryStorage) DeleteItem(ctx context.Context, itemID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.items[itemID]; !exists {
+		return inventory.ErrItemNotFound
+	}
+	for kitItemID, bom := range s.boms {
+		if kitItemID == itemID {
+			continue
+		}
+		for _, component := range bom.Components {
+			if component.ItemID == itemID {
+				return inventory.ErrItemInUse
+			}
+		}
+	}
+	delete(s.items, itemID)
+	delete(s.boms, itemID)
+
+	for key := range s.stocks {
+		if key.itemID == itemID {
+			delete(s.stocks, key)
+		}
+	}
+	for id, lot := range s.lots {
+		if lot.ItemID == itemID {
+			delete(s.lots, id)
+		}
+	}
+	for key, lotStock := range s.lotStocks {
+		if lotStock.ItemID == itemID {
+			delete(s.lotStocks, key)
+		}
+	}
+	for key := range s.consigned {
+		if key.itemID == itemID {
+			delete(s.consigned, key)
+		}
+	}
+	for key := range s.channels {
+		if key.itemID == itemID {
+			delete(s.channels, key)
+		}
+	}
+	for key := range s.conversions {
+		if key.itemID == itemID {
+			delete(s.conversions, key)
+		}
+	}
+	for key := range s.sourcing {
+		if key.itemID == itemID {
+			delete(s.sourcing, key)
+		}
+	}
+	for id, alert := range s.alerts {
+		if alert.ItemID == itemID {
+			delete(s.alerts, id)
+		}
+	}
+
+	remaining := s.transactions[:0]
+	for _, tx := range s.transactions {
+		if tx.ItemID != itemID {
+			remaining = append(remaining, tx)
+		}
+	}
+	s.transactions = remaining
+
+	return nil
+}
+
+// ListItems retrieves items with pagination
+// ページネーション付きで商品一覧を取得
+func (s *MemoryStorage) ListItems(ctx context.Context, offset, limit int) ([]inventory.Item, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	items := make([]inventory.Item, 0, len(s.items))
+	for _, item := range s.items {
+		items = append(items, item)
+	}
+
+	// PostgreSQL実装と同じく作成日時の降順
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].CreatedAt.Equal(items[j].CreatedAt) {
+			return items[i].ID < items[j].ID
+		}
+		return items[i].CreatedAt.After(items[j].CreatedAt)
+	})
+
+	return paginate(items, offset, limit), nil
+}
+
+// ListItemsAfter retrieves items matching a filter after the cursor
+// 条件に一致する商品をカーソルより後から取得（キーセットページネーション）
+func (s *MemoryStorage) ListItemsAfter(ctx context.Context, filter inventory.ItemFilter, after *inventory.PageCursor, limit int) ([]inventory.Item, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	items := make([]inventory.Item, 0, len(s.items))
+	for _, item := range s.items {
+		if matchItem(filter, item) {
+			items = append(items, item)
+		}
+	}
+
+	// PostgreSQL実装と同じく並び替えの項目、IDの順
+	sort.Slice(items, func(i, j int) bool {
+		return compareSortKeys(filter, itemSortValue(filter, items[i]), items[i].ID, itemSortValue(filter, items[j]), items[j].ID) < 0
+	})
+
+	page := make([]inventory.Item, 0, limit)
+	for _, item := range items {
+		if after != nil {
+			afterValue := after.AfterValue
+			if afterValue == nil {
+				afterValue = after.AfterCreatedAt
+			}
+			if compareSortKeys(filter, itemSortValue(filter, item), item.ID, afterValue, after.AfterID) <= 0 {
+				continue
+			}
+		}
+		if len(page) == limit {
+			break
+		}
+		page = append(page, item)
+	}
+	return page, nil
+}
+
+// matchItem reports whether an item matches a filter
+// 商品が条件に一致するかを返す
+func matchItem(filter inventory.ItemFilter, item inventory.Item) bool {
+	if filter.Category != "" && item.Category != filter.Category {
+		return false
+	}
+	if filter.SKUPrefix != "" && !strings.HasPrefix(item.SKU, filter.SKUPrefix) {
+		return false
+	}
+	if filter.MinCost != nil && item.UnitCost < *filter.MinCost {
+		return false
+	}
+	if filter.MaxCost != nil && item.UnitCost > *filter.MaxCost {
+		return false
+	}
+	return true
+}
+
+// itemSortValue returns the value of the sort field of an item
+// 商品の並び替えの項目の値を返す
+func itemSortValue(filter inventory.ItemFilter, item inventory.Item) interface{} {
+	switch filter.Sort() {
+	case inventory.ItemSortName:
+		return item.Name
+	case inventory.ItemSortSKU:
+		return item.SKU
+	case inventory.ItemSortUnitCost:
+		return item.UnitCost
+	default:
+		return item.CreatedAt
+	}
+}
+
+// compareSortKeys compares (value, id) pairs in the order of the filter
+// (値, ID) の組を条件の並び順で比較（aが先の場合は負の値を返す）
+func compareSortKeys(filter inventory.ItemFilter, a interface{}, aID string, b interface{}, bID string) int {
+	c := 0
+	switch a := a.(type) {
+	case string:
+		c = strings.Compare(a, b.(string))
+	case float64:
+		if b := b.(float64); a < b {
+			c = -1
+		} else if a > b {
+			c = 1
+		}
+	case time.Time:
+		c = a.Compare(b.(time.Time))
+	}
+	if c == 0 {
+		c = strings.Compare(aID, bID)
+	}
+	if !filter.Ascending {
+		c = -c
+	}
+	return c
+}
+
+// SearchItems searches for items by query string (case-insensitive)
+// クエリ文字列で商品を検索（大文字小文字を区別しない）
+func (s *MemoryStorage) SearchItems(ctx context.Context, query string) ([]inventory.Item, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	q := strings.ToLower(query)
+	var items []inventory.Item
+	for _, item := range s.items {
+		if strings.Contains(strings.ToLower(item.Name), q) ||
+			strings.Contains(strings.ToLower(item.SKU), q) ||
+			strings.Contains(strings.ToLower(item.Description), q) ||
+			strings.Contains(strings.ToLower(item.Category), q) {
+			items = append(items, item)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+
+	return items, nil
+}
+
+// CreateLocation creates a new location
+// 新しいロケーションを作成
+func (s *MemoryStorage) CreateLocation(ctx context.Context, location *inventory.Location) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.locations[location.ID]; exists {
+		return inventory.ErrDuplicateLocation
+	}
+	s.locations[location.ID] = *location
+
+	return nil
+}
+
+// GetLocation retrieves a location by ID
+// IDでロケーションを取得
+func (s *MemoryStorage) GetLocation(ctx context.Context, locationID string) (*inventory.Location, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	location, exists := s.locations[locationID]
+	if !exists {
+		return nil, inventory.ErrLocationNotFound
+	}
+
+	return &location, nil
+}
+
+// UpdateLocation updates an existing location
+// 既存のロケーションを更新
+func (s *MemoryStorage) UpdateLocation(ctx context.Context, location *inventory.Location) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, exists := s.locations[location.ID]
+	if !exists {
+		return inventory.ErrLocationNotFound
+	}
+	if expected, ok := inventory.ExpectedUpdatedAt(ctx); ok && !current.UpdatedAt.Equal(expected) {
+		return inventory.ErrPreconditionFailed
+	}
+
+	record := *location
+	record.CreatedAt = current.CreatedAt
+	s.locations[location.ID] = record
+
+	return nil
+}
+
+// DeleteLocation deletes a location by ID
+// IDでロケーションを削除（在庫・アラートも削除し、履歴のロケーション参照はnilにする）
+func (s *MemoryStorage) DeleteLocation(ctx context.Context, locationID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.locations[locationID]; !exists {
+		return inventory.ErrLocationNotFound
+	}
+	delete(s.locations, locationID)
+
+	for key := range s.stocks {
+		if key.locationID == locationID {
+			delete(s.stocks, key)
+		}
+	}
+	for key := range s.lotStocks {
+		if key.locationID == locationID {
+			delete(s.lotStocks, key)
+		}
+	}
+	for key := range s.consigned {
+		if key.locationID == locationID {
+			delete(s.consigned, key)
+		}
+	}
+	for key := range s.channels {
+		if key.locationID == locationID {
+			delete(s.channels, key)
+		}
+	}
+	for id, alert := range s.alerts {
+		if alert.LocationID == locationID {
+			delete(s.alerts, id)
+		}
+	}
+	for i := range s.transactions {
+		tx := &s.transactions[i]
+		if tx.FromLocation != nil && *tx.FromLocation == locationID {
+			tx.FromLocation = nil
+		}
+		if tx.ToLocation != nil && *tx.ToLocation == locationID {
+			tx.ToLocation = nil
+		}
+	}
+
+	return nil
+}
+
+// ListLocations retrieves locations with pagination
+// ページネーション付きでロケーション一覧を取得
+func (s *MemoryStorage) ListLocations(ctx context.Context, offset, limit int) ([]inventory.Location, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	locations := make([]inventory.Location, 0, len(s.locations))
+	for _, location := range s.locations {
+		locations = append(locations, location)
+	}
+
+	sort.Slice(locations, func(i, j int) bool {
+		if locations[i].CreatedAt.Equal(locations[j].CreatedAt) {
+			return locations[i].ID < locations[j].ID
+		}
+		return locations[i].CreatedAt.After(locations[j].CreatedAt)
+	})
+
+	return paginate(locations, offset, limit), nil
+}
+
+// ListLocationsAfter retrieves locations older than the cursor
+// カーソルより古いロケーションを取得（キーセットページネーション）
+func (s *MemoryStorage) ListLocationsAfter(ctx context.Context, after *inventory.PageCursor, limit int) ([]inventory.Location, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	locations := make([]inventory.Location, 0, len(s.locations))
+	for _, location := range s.locations {
+		locations = append(locations, location)
+	}
+
+	return pageAfter(locations, after, limit, func(location inventory.Location) (time.Time, string) {
+		return location.CreatedAt, location.ID
+	}), nil
+}
+
+// CreateUnit registers a new unit of measure
+// 新しい単位を登録
+func (s *MemoryStorage) CreateUnit(ctx context.Context, unit *inventory.UnitOfMeasure) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.units[unit.Code]; exists {
+		return inventory.ErrDuplicateUnit
+	}
+	s.units[unit.Code] = *unit
+	return nil
+}
+
+// GetUnit retrieves a unit of measure by code
+// コードで単位を取得
+func (s *MemoryStorage) GetUnit(ctx context.Context, code string) (*inventory.UnitOfMeasure, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	unit, exists := s.units[code]
+	if !exists {
+		return nil, inventory.ErrUnitNotFound
+	}
+	return &unit, nil
+}
+
+// ListUnits lists all units of measure ordered by code
+// 全ての単位をコード順に取得
+func (s *MemoryStorage) ListUnits(ctx context.Context) ([]inventory.UnitOfMeasure, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	units := make([]inventory.UnitOfMeasure, 0, len(s.units))
+	for _, unit := range s.units {
+		units = append(units, unit)
+	}
+	sort.Slice(units, func(i, j int) bool { return units[i].Code < units[j].Code })
+	return units, nil
+}
+
+// DeleteUnit deletes a unit of measure that no item or conversion uses
+// 商品の基本単位や換算で使用されていない単位を削除
+func (s *MemoryStorage) DeleteUnit(ctx context.Context, code string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.units[code]; !exists {
+		return inventory.ErrUnitNotFound
+	}
+	for _, item := range s.items {
+		if item.BaseUoM == code || (item.BaseUoM == "" && code == inventory.UoMEach) {
+			return inventory.ErrUnitInUse
+		}
+	}
+	for key := range s.conversions {
+		if key.uom == code {
+			return inventory.ErrUnitInUse
+		}
+	}
+	delete(s.units, code)
+	return nil
+}
+
+// SetUnitConversion registers or updates the conversion factor of an item's unit of measure
+// 商品の単位の換算係数を登録（登録済みの場合は更新）
+func (s *MemoryStorage) SetUnitConversion(ctx context.Context, conversion *inventory.UnitConversion) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.items[conversion.ItemID]; !exists {
+		return inventory.ErrItemNotFound
+	}
+	if _, exists := s.units[conversion.UoM]; !exists {
+		return inventory.ErrUnitNotFound
+	}
+	s.conversions[unitConversionKey{itemID: conversion.ItemID, uom: conversion.UoM}] = *conversion
+	return nil
+}
+
+// GetUnitConversion retrieves the conversion of an item's unit of measure
+// 商品の単位の換算を取得
+func (s *MemoryStorage) GetUnitConversion(ctx context.Context, itemID, uom string) (*inventory.UnitConversion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	conversion, exists := s.conversions[unitConversionKey{itemID: itemID, uom: uom}]
+	if !exists {
+		return nil, inventory.ErrUnitConversionNotFound
+	}
+	return &conversion, nil
+}
+
+// ListUnitConversions lists the unit conversions of an item ordered by unit of measure
+// 商品の単位の換算を単位コード順に取得
+func (s *MemoryStorage) ListUnitConversions(ctx context.Context, itemID string) ([]inventory.UnitConversion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	conversions := make([]inventory.UnitConversion, 0)
+	for key, conversion := range s.conversions {
+		if key.itemID == itemID {
+			conversions = append(conversions, conversion)
+		}
+	}
+	sort.Slice(conversions, func(i, j int) bool { return conversions[i].UoM < conversions[j].UoM })
+	return conversions, nil
+}
+
+// DeleteUnitConversion deletes the conversion of an item's unit of measure
+// 商品の単位の換算を削除
+func (s *MemoryStorage) DeleteUnitConversion(ctx context.Context, itemID, uom string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := unitConversionKey{itemID: itemID, uom: uom}
+	if _, exists := s.conversions[key]; !exists {
+		return inventory.ErrUnitConversionNotFound
+	}
+	delete(s.conversions, key)
+	return nil
+}
+
+// SetBillOfMaterials registers or replaces the bill of materials of a kit
+// キットの部品表を登録（登録済みの場合は構成品を置換）
+func (s *MemoryStorage) SetBillOfMaterials(ctx context.Context, bom *inventory.BillOfMaterials) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.items[bom.KitItemID]; !exists {
+		return inventory.ErrItemNotFound
+	}
+	for _, component := range bom.Components {
+		if _, exists := s.items[component.ItemID]; !exists {
+			return inventory.ErrItemNotFound
+		}
+	}
+	record := copyBillOfMaterials(*bom)
+	sort.Slice(record.Components, func(i, j int) bool {
+		return record.Components[i].ItemID < record.Components[j].ItemID
+	})
+	s.boms[bom.KitItemID] = record
+	return nil
+}
+
+// GetBillOfMaterials retrieves the bill of materials of a kit
+// キットの部品表を取得
+func (s *MemoryStorage) GetBillOfMaterials(ctx context.Context, kitItemID string) (*inventory.BillOfMaterials, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	bom, exists := s.boms[kitItemID]
+	if !exists {
+		return nil, inventory.ErrBOMNotFound
+	}
+	record := copyBillOfMaterials(bom)
+	return &record, nil
+}
+
+// ListBillsOfMaterials lists bills of materials ordered by kit item ID
+// 部品表一覧をキットの商品ID順に取得
+func (s *MemoryStorage) ListBillsOfMaterials(ctx context.Context, offset, limit int) ([]inventory.BillOfMaterials, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	boms := make([]inventory.BillOfMaterials, 0, len(s.boms))
+	for _, bom := range s.boms {
+		boms = append(boms, copyBillOfMaterials(bom))
+	}
+	sort.Slice(boms, func(i, j int) bool { return boms[i].KitItemID < boms[j].KitItemID })
+	return paginate(boms, offset, limit), nil
+}
+
+// DeleteBillOfMaterials deletes the bill of materials of a kit
+// キットの部品表を削除
+func (s *MemoryStorage) DeleteBillOfMaterials(ctx context.Context, kitItemID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.boms[kitItemID]; !exists {
+		return inventory.ErrBOMNotFound
+	}
+	delete(s.boms, kitItemID)
+	return nil
+}
+
+// CreateAssembly records a kit assembly or disassembly
+// キットの組立・分解を記録
+func (s *MemoryStorage) CreateAssembly(ctx context.Context, assembly *inventory.Assembly) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.assemblies[assembly.ID]; exists {
+		return fmt.Errorf("組立 %s は既に存在します", assembly.ID)
+	}
+	s.assemblies[assembly.ID] = copyAssembly(*assembly)
+	return nil
+}
+
+// GetAssembly retrieves a kit assembly or disassembly by ID
+// IDでキットの組立・分解を取得
+func (s *MemoryStorage) GetAssembly(ctx context.Context, assemblyID string) (*inventory.Assembly, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	assembly, exists := s.assemblies[assemblyID]
+	if !exists {
+		return nil, inventory.ErrAssemblyNotFound
+	}
+	record := copyAssembly(assembly)
+	return &record, nil
+}
+
+// ListAssemblies lists kit assemblies and disassemblies matching a filter, newest first
+// 条件に一致するキットの組立・分解を作成日時の新しい順に取得
+func (s *MemoryStorage) ListAssemblies(ctx context.Context, filter inventory.AssemblyFilter) ([]inventory.Assembly, error) {
+	s.mu.RLock()
+	assemblies := make([]inventory.Assembly, 0)
+	for _, assembly := range s.assemblies {
+		if filter.KitItemID != "" && assembly.KitItemID != filter.KitItemID {
+			continue
+		}
+		if filter.LocationID != "" && assembly.LocationID != filter.LocationID {
+			continue
+		}
+		assemblies = append(assemblies, copyAssembly(assembly))
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(assemblies, func(i, j int) bool {
+		if !assemblies[i].CreatedAt.Equal(assemblies[j].CreatedAt) {
+			return assemblies[i].CreatedAt.After(assemblies[j].CreatedAt)
+		}
+		return assemblies[i].ID > assemblies[j].ID
+	})
+	return paginate(assemblies, filter.Offset, filter.Limit), nil
+}
+
+// CreateSupplier creates a new supplier
+// 新しい仕入先を作成
+func (s *MemoryStorage) CreateSupplier(ctx context.Context, supplier *inventory.Supplier) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.suppliers[supplier.ID]; exists {
+		return inventory.ErrDuplicateSupplier
+	}
+	s.suppliers[supplier.ID] = *supplier
+	return nil
+}
+
+// GetSupplier retrieves a supplier by ID
+// IDで仕入先を取得
+func (s *MemoryStorage) GetSupplier(ctx context.Context, supplierID string) (*inventory.Supplier, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	supplier, exists := s.suppliers[supplierID]
+	if !exists {
+		return nil, inventory.ErrSupplierNotFound
+	}
+	return &supplier, nil
+}
+
+// UpdateSupplier updates an existing supplier
+// 既存の仕入先を更新
+func (s *MemoryStorage) UpdateSupplier(ctx context.Context, supplier *inventory.Supplier) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, exists := s.suppliers[supplier.ID]
+	if !exists {
+		return inventory.ErrSupplierNotFound
+	}
+	record := *supplier
+	record.CreatedAt = current.CreatedAt
+	s.suppliers[supplier.ID] = record
+	return nil
+}
+
+// DeleteSupplier deletes a supplier and the purchasing terms of its items
+// 仕入先を仕入条件とともに削除
+func (s *MemoryStorage) DeleteSupplier(ctx context.Context, supplierID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.suppliers[supplierID]; !exists {
+		return inventory.ErrSupplierNotFound
+	}
+	delete(s.suppliers, supplierID)
+	for key := range s.sourcing {
+		if key.supplierID == supplierID {
+			delete(s.sourcing, key)
+		}
+	}
+	return nil
+}
+
+// ListSuppliers lists suppliers ordered by ID
+// 仕入先一覧をID順に取得
+func (s *MemoryStorage) ListSuppliers(ctx context.Context, offset, limit int) ([]inventory.Supplier, error) {
+	s.mu.RLock()
+	suppliers := make([]inventory.Supplier, 0, len(s.suppliers))
+	for _, supplier := range s.suppliers {
+		suppliers = append(suppliers, supplier)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(suppliers, func(i, j int) bool { return suppliers[i].ID < suppliers[j].ID })
+	return paginate(suppliers, offset, limit), nil
+}
+
+// SetSupplierItem registers or updates the purchasing terms of an item from a supplier
+// 仕入先の商品の仕入条件を登録（登録済みの場合は更新）
+func (s *MemoryStorage) SetSupplierItem(ctx context.Context, supplierItem *inventory.SupplierItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.suppliers[supplierItem.SupplierID]; !exists {
+		return inventory.ErrSupplierNotFound
+	}
+	if _, exists := s.items[supplierItem.ItemID]; !exists {
+		return inventory.ErrItemNotFound
+	}
+	// 優先仕入先は商品ごとに1件
+	if supplierItem.IsPreferred {
+		for key, other := range s.sourcing {
+			if key.itemID == supplierItem.ItemID && other.IsPreferred {
+				other.IsPreferred = false
+				s.sourcing[key] = other
+			}
+		}
+	}
+	s.sourcing[supplierItemKey{supplierID: supplierItem.SupplierID, itemID: supplierItem.ItemID}] = *supplierItem
+	return nil
+}
+
+// GetSupplierItem retrieves the purchasing terms of an item from a supplier
+// 仕入先の商品の仕入条件を取得
+func (s *MemoryStorage) GetSupplierItem(ctx context.Context, supplierID, itemID string) (*inventory.SupplierItem, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	supplierItem, exists := s.sourcing[supplierItemKey{supplierID: supplierID, itemID: itemID}]
+	if !exists {
+		return nil, inventory.ErrSupplierItemNotFound
+	}
+	return &supplierItem, nil
+}
+
+// DeleteSupplierItem deletes the purchasing terms of an item from a supplier
+// 仕入先の商品の仕入条件を削除
+func (s *MemoryStorage) DeleteSupplierItem(ctx context.Context, supplierID, itemID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := supplierItemKey{supplierID: supplierID, itemID: itemID}
+	if _, exists := s.sourcing[key]; !exists {
+		return inventory.ErrSupplierItemNotFound
+	}
+	delete(s.sourcing, key)
+	return nil
+}
+
+// ListSupplierItems lists the purchasing terms matching a filter, ordered by supplier and item ID
+// 条件に一致する仕入条件を仕入先ID・商品ID順に取得
+func (s *MemoryStorage) ListSupplierItems(ctx context.Context, filter inventory.SupplierItemFilter) ([]inventory.SupplierItem, error) {
+	s.mu.RLock()
+	supplierItems := make([]inventory.SupplierItem, 0)
+	for key, supplierItem := range s.sourcing {
+		if filter.SupplierID != "" && key.supplierID != filter.SupplierID {
+			continue
+		}
+		if filter.ItemID != "" && key.itemID != filter.ItemID {
+			continue
+		}
+		supplierItems = append(supplierItems, supplierItem)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(supplierItems, func(i, j int) bool {
+		if supplierItems[i].SupplierID != supplierItems[j].SupplierID {
+			return supplierItems[i].SupplierID < supplierItems[j].SupplierID
+		}
+		return supplierItems[i].ItemID < supplierItems[j].ItemID
+	})
+	return paginate(supplierItems, filter.Offset, filter.Limit), nil
+}
+
+// CreatePurchaseOrder creates a purchase order with its lines
+// 発注と行を作成
+func (s *MemoryStorage) CreatePurchaseOrder(ctx context.Context, order *inventory.PurchaseOrder) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.purchases[order.ID]; exists {
+		return fmt.Errorf("発注 %s は既に存在します", order.ID)
+	}
+	record := copyPurchaseOrder(*order)
+	sort.Slice(record.Lines, func(i, j int) bool { return record.Lines[i].ItemID < record.Lines[j].ItemID })
+	s.purchases[order.ID] = record
+	return nil
+}
+
+// GetPurchaseOrder retrieves a purchase order with its lines
+// 発注を行とともに取得
+func (s *MemoryStorage) GetPurchaseOrder(ctx context.Context, orderID string) (*inventory.PurchaseOrder, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	order, exists := s.purchases[orderID]
+	if !exists {
+		return nil, inventory.ErrPurchaseOrderNotFound
+	}
+	record := copyPurchaseOrder(order)
+	return &record, nil
+}
+
+// ListPurchaseOrders lists purchase orders matching a filter, newest first, without their lines
+// 条件に一致する発注を作成日時の新しい順に取得（行は含まない）
+func (s *MemoryStorage) ListPurchaseOrders(ctx context.Context, filter inventory.PurchaseOrderFilter) ([]inventory.PurchaseOrder, error) {
+	s.mu.RLock()
+	orders := make([]inventory.PurchaseOrder, 0)
+	for _, order := range s.purchases {
+		if filter.SupplierID != "" && order.SupplierID != filter.SupplierID {
+			continue
+		}
+		if filter.LocationID != "" && order.LocationID != filter.LocationID {
+			continue
+		}
+		if filter.Status != "" && order.Status != filter.Status {
+			continue
+		}
+		record := copyPurchaseOrder(order)
+		record.Lines = nil
+		orders = append(orders, record)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(orders, func(i, j int) bool {
+		if !orders[i].CreatedAt.Equal(orders[j].CreatedAt) {
+			return orders[i].CreatedAt.After(orders[j].CreatedAt)
+		}
+		return orders[i].ID > orders[j].ID
+	})
+	return paginate(orders, filter.Offset, filter.Limit), nil
+}
+
+// UpdatePurchaseOrder updates the status, timestamps and users of a purchase order in status from
+// 状態がfromの発注の状態・日時・担当者を更新
+func (s *MemoryStorage) UpdatePurchaseOrder(ctx context.Context, order *inventory.PurchaseOrder, from inventory.PurchaseOrderStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, exists := s.purchases[order.ID]
+	if !exists {
+		return inventory.ErrPurchaseOrderNotFound
+	}
+	if current.Status != from {
+		return inventory.ErrPurchaseOrderStatus
+	}
+
+	current.Status = order.Status
+	current.ApprovedAt = copyTime(order.ApprovedAt)
+	current.ApprovedBy = order.ApprovedBy
+	current.SentAt = copyTime(order.SentAt)
+	current.SentBy = order.SentBy
+	current.ClosedAt = copyTime(order.ClosedAt)
+	current.ClosedBy = order.ClosedBy
+	s.purchases[order.ID] = current
+	return nil
+}
+
+// ReceivePurchaseOrderLine adds a received quantity to a purchase order line
+// 発注の行の入荷済み数量を加算
+func (s *MemoryStorage) ReceivePurchaseOrderLine(ctx context.Context, orderID, itemID string, quantity int64) (*inventory.PurchaseOrderLine, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, exists := s.purchases[orderID]
+	if !exists {
+		return nil, inventory.ErrPurchaseOrderNotFound
+	}
+	for i, line := range order.Lines {
+		if line.ItemID != itemID {
+			continue
+		}
+		if line.ReceivedQuantity+quantity > line.Quantity {
+			return nil, inventory.ErrReceiptExceedsOrder
+		}
+		// 行のスライスはスナップショットと共有しないよう、コピーしてから更新する
+		updated := copyPurchaseOrder(order)
+		updated.Lines[i].ReceivedQuantity += quantity
+		s.purchases[orderID] = updated
+		record := updated.Lines[i]
+		return &record, nil
+	}
+	return nil, inventory.ErrPurchaseOrderNotFound
+}
+
+// CreateGoodsReceipt records a goods receipt against a purchase order
+// 発注に対する入荷を記録
+func (s *MemoryStorage) CreateGoodsReceipt(ctx context.Context, receipt *inventory.GoodsReceipt) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.receipts[receipt.PurchaseOrderID] {
+		if existing.ID == receipt.ID {
+			return fmt.Errorf("入荷 %s は既に存在します", receipt.ID)
+		}
+	}
+	s.receipts[receipt.PurchaseOrderID] = append(s.receipts[receipt.PurchaseOrderID], copyGoodsReceipt(*receipt))
+	return nil
+}
+
+// ListGoodsReceipts lists the goods receipts of a purchase order, oldest first
+// 発注に対する入荷を入荷日時の古い順に取得
+func (s *MemoryStorage) ListGoodsReceipts(ctx context.Context, orderID string) ([]inventory.GoodsReceipt, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	receipts := make([]inventory.GoodsReceipt, 0, len(s.receipts[orderID]))
+	for _, receipt := range s.receipts[orderID] {
+		receipts = append(receipts, copyGoodsReceipt(receipt))
+	}
+	return receipts, nil
+}
+
+// CreateSalesOrder creates a sales order with its lines
+// 受注と行を作成
+func (s *MemoryStorage) CreateSalesOrder(ctx context.Context, order *inventory.SalesOrder) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.sales[order.ID]; exists {
+		return fmt.Errorf("受注 %s は既に存在します", order.ID)
+	}
+	record := copySalesOrder(*order)
+	sort.Slice(record.Lines, func(i, j int) bool { return record.Lines[i].ItemID < record.Lines[j].ItemID })
+	s.sales[order.ID] = record
+	return nil
+}
+
+// GetSalesOrder retrieves a sales order with its lines
+// 受注を行とともに取得
+func (s *MemoryStorage) GetSalesOrder(ctx context.Context, orderID string) (*inventory.SalesOrder, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	order, exists := s.sales[orderID]
+	if !exists {
+		return nil, inventory.ErrSalesOrderNotFound
+	}
+	record := copySalesOrder(order)
+	return &record, nil
+}
+
+// ListSalesOrders lists sales orders matching a filter, newest first, without their lines
+// 条件に一致する受注を受付日時の新しい順に取得（行は含まない）
+func (s *MemoryStorage) ListSalesOrders(ctx context.Context, filter inventory.SalesOrderFilter) ([]inventory.SalesOrder, error) {
+	s.mu.RLock()
+	orders := make([]inventory.SalesOrder, 0)
+	for _, order := range s.sales {
+		if filter.LocationID != "" && order.LocationID != filter.LocationID {
+			continue
+		}
+		if filter.Reference != "" && order.Reference != filter.Reference {
+			continue
+		}
+		if filter.Status != "" && order.Status != filter.Status {
+			continue
+		}
+		record := copySalesOrder(order)
+		record.Lines = nil
+		orders = append(orders, record)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(orders, func(i, j int) bool {
+		if !orders[i].CreatedAt.Equal(orders[j].CreatedAt) {
+			return orders[i].CreatedAt.After(orders[j].CreatedAt)
+		}
+		return orders[i].ID > orders[j].ID
+	})
+	return paginate(orders, filter.Offset, filter.Limit), nil
+}
+
+// UpdateSalesOrder updates the status and line quantities of a sales order with optimistic locking
+// 受注の状態と行の数量を楽観的ロックで更新
+func (s *MemoryStorage) UpdateSalesOrder(ctx context.Context, order *inventory.SalesOrder) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, exists := s.sales[order.ID]
+	if !exists {
+		return inventory.ErrSalesOrderNotFound
+	}
+	if current.Version != order.Version-1 {
+		return inventory.ErrVersionMismatch
+	}
+
+	// 行のスライスはスナップショットと共有しないよう、コピーしてから更新する
+	updated := copySalesOrder(current)
+	updated.Status = order.Status
+	updated.Version = order.Version
+	updated.ClosedAt = copyTime(order.ClosedAt)
+	updated.ClosedBy = order.ClosedBy
+	for _, line := range order.Lines {
+		for i := range updated.Lines {
+			if updated.Lines[i].ItemID == line.ItemID {
+				updated.Lines[i].AllocatedQuantity = line.AllocatedQuantity
+				updated.Lines[i].ShippedQuantity = line.ShippedQuantity
+				updated.Lines[i].ShortQuantity = line.ShortQuantity
+			}
+		}
+	}
+	s.sales[order.ID] = updated
+	return nil
+}
+
+// CreateReturn creates a return with its lines
+// 返品と行を作成
+func (s *MemoryStorage) CreateReturn(ctx context.Context, rma *inventory.ReturnAuthorization) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.returns[rma.ID]; exists {
+		return fmt.Errorf("返品 %s は既に存在します", rma.ID)
+	}
+	record := copyReturn(*rma)
+	sort.Slice(record.Lines, func(i, j int) bool { return record.Lines[i].ItemID < record.Lines[j].ItemID })
+	s.returns[rma.ID] = record
+	return nil
+}
+
+// GetReturn retrieves a return with its lines
+// 返品を行とともに取得
+func (s *MemoryStorage) GetReturn(ctx context.Context, returnID string) (*inventory.ReturnAuthorization, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rma, exists := s.returns[returnID]
+	if !exists {
+		return nil, inventory.ErrReturnNotFound
+	}
+	record := copyReturn(rma)
+	return &record, nil
+}
+
+// ListReturns lists returns matching a filter, newest first, without their lines
+// 条件に一致する返品を受付日時の新しい順に取得（行は含まない）
+func (s *MemoryStorage) ListReturns(ctx context.Context, filter inventory.ReturnFilter) ([]inventory.ReturnAuthorization, error) {
+	s.mu.RLock()
+	returns := make([]inventory.ReturnAuthorization, 0)
+	for _, rma := range s.returns {
+		if filter.SalesOrderID != "" && rma.SalesOrderID != filter.SalesOrderID {
+			continue
+		}
+		if filter.LocationID != "" && rma.LocationID != filter.LocationID {
+			continue
+		}
+		if filter.Status != "" && rma.Status != filter.Status {
+			continue
+		}
+		record := copyReturn(rma)
+		record.Lines = nil
+		returns = append(returns, record)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(returns, func(i, j int) bool {
+		if !returns[i].CreatedAt.Equal(returns[j].CreatedAt) {
+			return returns[i].CreatedAt.After(returns[j].CreatedAt)
+		}
+		return returns[i].ID > returns[j].ID
+	})
+	return paginate(returns, filter.Offset, filter.Limit), nil
+}
+
+// UpdateReturn updates the status and line quantities of a return with optimistic locking
+// 返品の状態と行の数量を楽観的ロックで更新
+func (s *MemoryStorage) UpdateReturn(ctx context.Context, rma *inventory.ReturnAuthorization) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, exists := s.returns[rma.ID]
+	if !exists {
+		return inventory.ErrReturnNotFound
+	}
+	if current.Version != rma.Version-1 {
+		return inventory.ErrVersionMismatch
+	}
+
+	// 行のスライスはスナップショットと共有しないよう、コピーしてから更新する
+	updated := copyReturn(current)
+	updated.Status = rma.Status
+	updated.Version = rma.Version
+	updated.ClosedAt = copyTime(rma.ClosedAt)
+	updated.ClosedBy = rma.ClosedBy
+	for _, line := range rma.Lines {
+		for i := range updated.Lines {
+			if updated.Lines[i].ItemID == line.ItemID {
+				updated.Lines[i].ReceivedQuantity = line.ReceivedQuantity
+				updated.Lines[i].RestockedQuantity = line.RestockedQuantity
+				updated.Lines[i].RefurbishedQuantity = line.RefurbishedQuantity
+				updated.Lines[i].ScrappedQuantity = line.ScrappedQuantity
+			}
+		}
+	}
+	s.returns[rma.ID] = updated
+	return nil
+}
+
+// CreateReturnDisposition records a disposition of returned units
+// 返品の処分を記録
+func (s *MemoryStorage) CreateReturnDisposition(ctx context.Context, disposition *inventory.ReturnDisposition) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.dispositions[disposition.ReturnID] {
+		if existing.ID == disposition.ID {
+			return fmt.Errorf("返品の処分 %s は既に存在します", disposition.ID)
+		}
+	}
+	s.dispositions[disposition.ReturnID] = append(s.dispositions[disposition.ReturnID], *disposition)
+	return nil
+}
+
+// ListReturnDispositions lists the dispositions of a return, oldest first
+// 返品の処分を処分日時の古い順に取得
+func (s *MemoryStorage) ListReturnDispositions(ctx context.Context, returnID string) ([]inventory.ReturnDisposition, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append(make([]inventory.ReturnDisposition, 0, len(s.dispositions[returnID])), s.dispositions[returnID]...), nil
+}
+
+// CreateLot creates a new lot record
+// 新しいロット記録を作成
+func (s *MemoryStorage) CreateLot(ctx context.Context, lot *inventory.Lot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.lots[lot.ID]; exists {
+		return fmt.Errorf("ロットは既に存在します: %s", lot.ID)
+	}
+	s.lots[lot.ID] = copyLot(*lot)
+
+	return nil
+}
+
+// GetLot retrieves a lot by ID
+// IDでロットを取得
+func (s *MemoryStorage) GetLot(ctx context.Context, lotID string) (*inventory.Lot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	lot, exists := s.lots[lotID]
+	if !exists {
+		return nil, inventory.ErrLotNotFound
+	}
+
+	result := copyLot(lot)
+	return &result, nil
+}
+
+// UpdateLot updates an existing lot
+// 既存のロットを更新
+func (s *MemoryStorage) UpdateLot(ctx context.Context, lot *inventory.Lot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, exists := s.lots[lot.ID]
+	if !exists {
+		return inventory.ErrLotNotFound
+	}
+
+	record := copyLot(*lot)
+	record.ItemID = current.ItemID
+	record.CreatedAt = current.CreatedAt
+	s.lots[lot.ID] = record
+
+	return nil
+}
+
+// DeleteLot deletes a lot by ID
+// IDでロットを削除
+func (s *MemoryStorage) DeleteLot(ctx context.Context, lotID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.lots[lotID]; !exists {
+		return inventory.ErrLotNotFound
+	}
+	delete(s.lots, lotID)
+	for key := range s.lotStocks {
+		if key.lotID == lotID {
+			delete(s.lotStocks, key)
+		}
+	}
+
+	return nil
+}
+
+// AdjustLotQuantity atomically adds delta to a lot's quantity
+// ロットの数量をアトミックにdelta分だけ増減
+func (s *MemoryStorage) AdjustLotQuantity(ctx context.Context, lotID string, delta int64) (*inventory.Lot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lot, exists := s.lots[lotID]
+	if !exists {
+		return nil, inventory.ErrLotNotFound
+	}
+	if lot.Quantity+delta < 0 {
+		return nil, inventory.ErrInsufficientStock
+	}
+
+	lot.Quantity += delta
+	s.lots[lotID] = lot
+
+	result := copyLot(lot)
+	return &result, nil
+}
+
+// GetLotsByItem retrieves all lots for a specific item
+// 指定商品のすべてのロットを取得
+func (s *MemoryStorage) GetLotsByItem(ctx context.Context, itemID string) ([]inventory.Lot, error) {
+	lots := s.filterLots(func(lot *inventory.Lot) bool {
+		return lot.ItemID == itemID
+	})
+
+	sort.Slice(lots, func(i, j int) bool {
+		return lots[i].CreatedAt.After(lots[j].CreatedAt)
+	})
+
+	return lots, nil
+}
+
+// GetExpiringLots retrieves lots that are expiring within the specified duration
+// 指定期間内に期限切れになるロットを取得
+func (s *MemoryStorage) GetExpiringLots(ctx context.Context, within time.Duration) ([]inventory.Lot, error) {
+	threshold := time.Now().Add(within)
+	lots := s.filterLots(func(lot *inventory.Lot) bool {
+		return lot.ExpiryDate != nil && !lot.ExpiryDate.After(threshold)
+	})
+
+	sortLotsByExpiry(lots)
+	return lots, nil
+}
+
+// GetExpiredLots retrieves lots that have already expired
+// 既に期限切れになったロットを取得
+func (s *MemoryStorage) GetExpiredLots(ctx context.Context) ([]inventory.Lot, error) {
+	now := time.Now()
+	lots := s.filterLots(func(lot *inventory.Lot) bool {
+		return lot.ExpiryDate != nil && lot.ExpiryDate.Before(now)
+	})
+
+	sortLotsByExpiry(lots)
+	return lots, nil
+}
+
+// GetLotByNumber retrieves the oldest lot of an item with a lot number
+// 商品のロット番号のロット（複数ある場合は最も古いロット）を取得
+func (s *MemoryStorage) GetLotByNumber(ctx context.Context, itemID, lotNumber string) (*inventory.Lot, error) {
+	lots := s.filterLots(func(lot *inventory.Lot) bool {
+		return lot.ItemID == itemID && lot.Number == lotNumber
+	})
+	if len(lots) == 0 {
+		return nil, inventory.ErrLotNotFound
+	}
+
+	sort.Slice(lots, func(i, j int) bool {
+		if !lots[i].CreatedAt.Equal(lots[j].CreatedAt) {
+			return lots[i].CreatedAt.Before(lots[j].CreatedAt)
+		}
+		return lots[i].ID < lots[j].ID
+	})
+	return &lots[0], nil
+}
+
+// AdjustLotStock adds delta to the quantity of a lot at a location, creating the record if needed
+// ロットのロケーションの在庫数量をdelta分だけ増減（記録がない場合は作成）
+func (s *MemoryStorage) AdjustLotStock(ctx context.Context, lot *inventory.Lot, locationID string, delta int64) (*inventory.LotStock, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, exists := s.lots[lot.ID]
+	if !exists {
+		return nil, inventory.ErrLotNotFound
+	}
+	key := lotStockKey{lotID: lot.ID, locationID: locationID}
+	lotStock, exists := s.lotStocks[key]
+	if !exists {
+		lotStock = inventory.LotStock{LotID: lot.ID, ItemID: current.ItemID, LocationID: locationID}
+	}
+	if lotStock.Quantity+delta < 0 {
+		return nil, inventory.ErrInsufficientLotStock
+	}
+
+	lotStock.Quantity += delta
+	lotStock.UpdatedAt = time.Now()
+	s.lotStocks[key] = lotStock
+
+	lotStock.LotNumber = current.Number
+	return &lotStock, nil
+}
+
+// GetLotStock retrieves the stock of a lot at a location
+// ロットのロケーションの在庫を取得
+func (s *MemoryStorage) GetLotStock(ctx context.Context, lotID, locationID string) (*inventory.LotStock, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	lotStock, exists := s.lotStocks[lotStockKey{lotID: lotID, locationID: locationID}]
+	if !exists {
+		return nil, inventory.ErrLotStockNotFound
+	}
+	lotStock.LotNumber = s.lots[lotID].Number
+	return &lotStock, nil
+}
+
+// ListLotStocks lists lot stocks matching a filter ordered by lot number and location
+// 条件に一致するロット在庫をロット番号・ロケーションIDの昇順で取得
+func (s *MemoryStorage) ListLotStocks(ctx context.Context, filter inventory.LotStockFilter) ([]inventory.LotStock, error) {
+	s.mu.RLock()
+	lotStocks := make([]inventory.LotStock, 0)
+	for _, lotStock := range s.lotStocks {
+		if filter.ItemID != "" && lotStock.ItemID != filter.ItemID {
+			continue
+		}
+		if filter.LotID != "" && lotStock.LotID != filter.LotID {
+			continue
+		}
+		if filter.LocationID != "" && lotStock.LocationID != filter.LocationID {
+			continue
+		}
+		if lotStock.Quantity == 0 && !filter.IncludeEmpty {
+			continue
+		}
+		lotStock.LotNumber = s.lots[lotStock.LotID].Number
+		lotStocks = append(lotStocks, lotStock)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(lotStocks, func(i, j int) bool {
+		a, b := lotStocks[i], lotStocks[j]
+		if a.LotNumber != b.LotNumber {
+			return a.LotNumber < b.LotNumber
+		}
+		if a.LotID != b.LotID {
+			return a.LotID < b.LotID
+		}
+		return a.LocationID < b.LocationID
+	})
+	return paginate(lotStocks, filter.Offset, filter.Limit), nil
+}
+
+// AdjustConsignmentStock adds delta to the consignment stock of an owner, creating the record if needed
+// 所有者の委託在庫の数量をdelta分だけ増減（記録がない場合は作成）
+func (s *MemoryStorage) AdjustConsignmentStock(ctx context.Context, ownerID, itemID, locationID string, delta int64) (*inventory.ConsignmentStock, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := consignmentKey{ownerID: ownerID, itemID: itemID, locationID: locationID}
+	consignment, exists := s.consigned[key]
+	if !exists {
+		consignment = inventory.ConsignmentStock{OwnerID: ownerID, ItemID: itemID, LocationID: locationID}
+	}
+	if consignment.Quantity+delta < 0 {
+		return nil, inventory.ErrInsufficientConsignmentStock
+	}
+
+	consignment.Quantity += delta
+	consignment.UpdatedAt = time.Now()
+	s.consigned[key] = consignment
+	return &consignment, nil
+}
+
+// ListConsignmentStocks lists consignment stocks matching a filter ordered by owner, item and location
+// 条件に一致する委託在庫を所有者ID・商品ID・ロケーションIDの昇順で取得
+func (s *MemoryStorage) ListConsignmentStocks(ctx context.Context, filter inventory.ConsignmentStockFilter) ([]inventory.ConsignmentStock, error) {
+	s.mu.RLock()
+	consignments := make([]inventory.ConsignmentStock, 0)
+	for _, consignment := range s.consigned {
+		if filter.OwnerID != "" && consignment.OwnerID != filter.OwnerID {
+			continue
+		}
+		if filter.ItemID != "" && consignment.ItemID != filter.ItemID {
+			continue
+		}
+		if filter.LocationID != "" && consignment.LocationID != filter.LocationID {
+			continue
+		}
+		if consignment.Quantity == 0 && !filter.IncludeEmpty {
+			continue
+		}
+		consignments = append(consignments, consignment)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(consignments, func(i, j int) bool {
+		a, b := consignments[i], consignments[j]
+		if a.OwnerID != b.OwnerID {
+			return a.OwnerID < b.OwnerID
+		}
+		if a.ItemID != b.ItemID {
+			return a.ItemID < b.ItemID
+		}
+		return a.LocationID < b.LocationID
+	})
+	return paginate(consignments, filter.Offset, filter.Limit), nil
+}
+
+// CreateAlert creates a new stock alert
+// 新しい在庫アラートを作成
+func (s *MemoryStorage) CreateAlert(ctx context.Context, alert *inventory.StockAlert) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.alerts[alert.ID] = *alert
+
+	return nil
+}
+
+// GetActiveAlerts retrieves active alerts for a location
+// ロケーションのアクティブアラートを取得
+func (s *MemoryStorage) GetActiveAlerts(ctx context.Context, locationID string) ([]inventory.StockAlert, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var alerts []inventory.StockAlert
+	for _, alert := range s.alerts {
+		if alert.LocationID == locationID && alert.IsActive {
+			alerts = append(alerts, alert)
+		}
+	}
+
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].CreatedAt.After(alerts[j].CreatedAt)
+	})
+
+	return alerts, nil
+}
+
+// ListAlerts lists alerts of all locations matching a filter, newest first
+// 条件に一致するアラートを全ロケーションから作成日時の新しい順に取得（解決済みを含む）
+func (s *MemoryStorage) ListAlerts(ctx context.Context, filter inventory.AlertFilter) ([]inventory.StockAlert, error) {
+	s.mu.RLock()
+	alerts := make([]inventory.StockAlert, 0)
+	for _, alert := range s.alerts {
+		if (filter.Type == "" || alert.Type == filter.Type) &&
+			(filter.Severity == "" || alert.Severity == filter.Severity) &&
+			(filter.Active == nil || alert.IsActive == *filter.Active) &&
+			(filter.Acknowledged == nil || (alert.AcknowledgedAt != nil) == *filter.Acknowledged) &&
+			(filter.ItemID == "" || alert.ItemID == filter.ItemID) &&
+			(filter.LocationID == "" || alert.LocationID == filter.LocationID) &&
+			(filter.CreatedAfter.IsZero() || !alert.CreatedAt.Before(filter.CreatedAfter)) &&
+			(filter.CreatedBefore.IsZero() || alert.CreatedAt.Before(filter.CreatedBefore)) {
+			alerts = append(alerts, alert)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(alerts, func(i, j int) bool {
+		if !alerts[i].CreatedAt.Equal(alerts[j].CreatedAt) {
+			return alerts[i].CreatedAt.After(alerts[j].CreatedAt)
+		}
+		return alerts[i].ID > alerts[j].ID
+	})
+
+	if filter.Offset >= len(alerts) {
+		return []inventory.StockAlert{}, nil
+	}
+	alerts = alerts[filter.Offset:]
+	if filter.Limit > 0 && filter.Limit < len(alerts) {
+		alerts = alerts[:filter.Limit]
+	}
+	return alerts, nil
+}
+
+// GetAlert retrieves an alert by ID, including resolved alerts
+// IDでアラートを取得（解決済みを含む）
+func (s *MemoryStorage) GetAlert(ctx context.Context, alertID string) (*inventory.StockAlert, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	alert, exists := s.alerts[alertID]
+	if !exists {
+		return nil, inventory.ErrAlertNotFound
+	}
+
+	return &alert, nil
+}
+
+// ResolveAlert resolves an alert by setting it inactive
+// アラートを非アクティブにして解決
+func (s *MemoryStorage) ResolveAlert(ctx context.Context, alertID, resolvedBy, note string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	alert, exists := s.alerts[alertID]
+	if !exists {
+		return inventory.ErrAlertNotFound
+	}
+	if !alert.IsActive {
+		return inventory.ErrAlertNotActive
+	}
+
+	now := time.Now()
+	alert.IsActive = false
+	alert.ResolvedAt = &now
+	alert.ResolvedBy = resolvedBy
+	alert.ResolveNote = note
+	s.alerts[alertID] = alert
+
+	return nil
+}
+
+// AcknowledgeAlert marks an active alert as acknowledged
+// アクティブなアラートを確認済みにする
+func (s *MemoryStorage) AcknowledgeAlert(ctx context.Context, alertID, acknowledgedBy, note string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	alert, exists := s.alerts[alertID]
+	if !exists {
+		return inventory.ErrAlertNotFound
+	}
+	if !alert.IsActive {
+		return inventory.ErrAlertNotActive
+	}
+	if alert.AcknowledgedAt != nil {
+		return inventory.ErrAlertAlreadyAcknowledged
+	}
+
+	now := time.Now()
+	alert.AcknowledgedAt = &now
+	alert.AcknowledgedBy = acknowledgedBy
+	alert.AcknowledgeNote = note
+	s.alerts[alertID] = alert
+
+	return nil
+}
+
+// GetLatestRuleAlert retrieves the latest alert an alert rule raised for a stock record
+// アラートルールが在庫記録に対して最後に作成したアラートを取得（解決済みを含む）
+func (s *MemoryStorage) GetLatestRuleAlert(ctx context.Context, ruleID, itemID, locationID string) (*inventory.StockAlert, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var latest *inventory.StockAlert
+	for _, alert := range s.alerts {
+		if alert.RuleID != ruleID || alert.ItemID != itemID || alert.LocationID != locationID {
+			continue
+		}
+		if latest == nil || alert.CreatedAt.After(latest.CreatedAt) {
+			alert := alert
+			latest = &alert
+		}
+	}
+	if latest == nil {
+		return nil, inventory.ErrAlertNotFound
+	}
+	return latest, nil
+}
+
+// CreateAlertRule creates a new alert rule
+// 新しいアラートルールを作成
+func (s *MemoryStorage) CreateAlertRule(ctx context.Context, rule *inventory.AlertRule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.alertRules[rule.ID] = *rule
+	return nil
+}
+
+// GetAlertRule retrieves an alert rule by ID
+// IDでアラートルールを取得
+func (s *MemoryStorage) GetAlertRule(ctx context.Context, ruleID string) (*inventory.AlertRule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rule, exists := s.alertRules[ruleID]
+	if !exists {
+		return nil, inventory.ErrAlertRuleNotFound
+	}
+	return &rule, nil
+}
+
+// UpdateAlertRule replaces the condition, scope and state of an alert rule
+// アラートルールの条件・対象範囲・有効状態を更新
+func (s *MemoryStorage) UpdateAlertRule(ctx context.Context, rule *inventory.AlertRule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, exists := s.alertRules[rule.ID]
+	if !exists {
+		return inventory.ErrAlertRuleNotFound
+	}
+	updated := *rule
+	updated.CreatedAt = existing.CreatedAt
+	s.alertRules[rule.ID] = updated
+	return nil
+}
+
+// DeleteAlertRule deletes an alert rule
+// アラートルールを削除
+func (s *MemoryStorage) DeleteAlertRule(ctx context.Context, ruleID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.alertRules[ruleID]; !exists {
+		return inventory.ErrAlertRuleNotFound
+	}
+	delete(s.alertRules, ruleID)
+	return nil
+}
+
+// ListAlertRules lists alert rules matching a filter, oldest first
+// 条件に一致するアラートルールを作成日時の昇順で取得
+func (s *MemoryStorage) ListAlertRules(ctx context.Context, filter inventory.AlertRuleFilter) ([]inventory.AlertRule, error) {
+	s.mu.RLock()
+	rules := make([]inventory.AlertRule, 0)
+	for _, rule := range s.alertRules {
+		if (filter.ItemID == "" || rule.ItemID == filter.ItemID) &&
+			(filter.LocationID == "" || rule.LocationID == filter.LocationID) &&
+			(!filter.EnabledOnly || rule.Enabled) {
+			rules = append(rules, rule)
+		}
+	}
+	s.mu.RUnlock()
+
+	sortAlertRules(rules)
+	if filter.Offset >= len(rules) {
+		return []inventory.AlertRule{}, nil
+	}
+	rules = rules[filter.Offset:]
+	if filter.Limit > 0 && filter.Limit < len(rules) {
+		rules = rules[:filter.Limit]
+	}
+	return rules, nil
+}
+
+// GetAlertRulesForStock retrieves the enabled alert rules whose scope covers a stock record
+// 在庫記録を対象範囲に含む有効なアラートルールを取得
+func (s *MemoryStorage) GetAlertRulesForStock(ctx context.Context, itemID, locationID string) ([]inventory.AlertRule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stock := inventory.Stock{ItemID: itemID, LocationID: locationID}
+	rules := make([]inventory.AlertRule, 0)
+	for _, rule := range s.alertRules {
+		if rule.Enabled && rule.Covers(stock) {
+			rules = append(rules, rule)
+		}
+	}
+	sortAlertRules(rules)
+	return rules, nil
+}
+
+// sortAlertRules sorts alert rules oldest first, breaking ties by ID
+// アラートルールを作成日時の昇順（同時刻はID順）に並べ替え
+func sortAlertRules(rules []inventory.AlertRule) {
+	sort.Slice(rules, func(i, j int) bool {
+		if !rules[i].CreatedAt.Equal(rules[j].CreatedAt) {
+			return rules[i].CreatedAt.Before(rules[j].CreatedAt)
+		}
+		return rules[i].ID < rules[j].ID
+	})
+}
+
+// CreateReservation creates a new reservation
+// 新しい予約を作成
+func (s *MemoryStorage) CreateReservation(ctx context.Context, reservation *inventory.Reservation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.reservations[reservation.ID]; exists {
+		return fmt.Errorf("予約は既に存在します: %s", reservation.ID)
+	}
+	s.reservations[reservation.ID] = copyReservation(*reservation)
+
+	return nil
+}
+
+// GetReservation retrieves a reservation by ID
+// IDで予約を取得
+func (s *MemoryStorage) GetReservation(ctx context.Context, reservationID string) (*inventory.Reservation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	reservation, exists := s.reservations[reservationID]
+	if !exists {
+		return nil, inventory.ErrReservationNotFound
+	}
+
+	reservation = copyReservation(reservation)
+	return &reservation, nil
+}
+
+// UpdateReservation updates an active reservation
+// 有効な予約を更新
+func (s *MemoryStorage) UpdateReservation(ctx context.Context, reservation *inventory.Reservation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, exists := s.reservations[reservation.ID]
+	if !exists {
+		return inventory.ErrReservationNotFound
+	}
+	if current.Status != inventory.ReservationStatusActive {
+		return inventory.ErrReservationNotActive
+	}
+
+	current.Quantity = reservation.Quantity
+	current.Status = reservation.Status
+	current.ExpiresAt = reservation.ExpiresAt
+	current.ReleasedAt = reservation.ReleasedAt
+	s.reservations[reservation.ID] = copyReservation(current)
+
+	return nil
+}
+
+// ListReservations lists reservations matching a filter, newest first
+// 条件に一致する予約を作成日時の降順で取得
+func (s *MemoryStorage) ListReservations(ctx context.Context, filter inventory.ReservationFilter) ([]inventory.Reservation, error) {
+	reservations := s.filterReservations(func(reservation *inventory.Reservation) bool {
+		return (filter.ItemID == "" || reservation.ItemID == filter.ItemID) &&
+			(filter.LocationID == "" || reservation.LocationID == filter.LocationID) &&
+			(filter.Status == "" || reservation.Status == filter.Status) &&
+			(filter.Reference == "" || reservation.Reference == filter.Reference) &&
+			(filter.Channel == "" || reservation.Channel == filter.Channel)
+	})
+
+	sort.Slice(reservations, func(i, j int) bool {
+		if !reservations[i].CreatedAt.Equal(reservations[j].CreatedAt) {
+			return reservations[i].CreatedAt.After(reservations[j].CreatedAt)
+		}
+		return reservations[i].ID > reservations[j].ID
+	})
+
+	if filter.Offset >= len(reservations) {
+		return []inventory.Reservation{}, nil
+	}
+	reservations = reservations[filter.Offset:]
+	if filter.Limit > 0 && filter.Limit < len(reservations) {
+		reservations = reservations[:filter.Limit]
+	}
+	return reservations, nil
+}
+
+// GetExpiredReservations retrieves active reservations that expired at or before a time
+// 有効期限がbefore以前の有効な予約を取得
+func (s *MemoryStorage) GetExpiredReservations(ctx context.Context, before time.Time, limit int) ([]inventory.Reservation, error) {
+	reservations := s.filterReservations(func(reservation *inventory.Reservation) bool {
+		return reservation.Status == inventory.ReservationStatusActive &&
+			reservation.ExpiresAt != nil && !reservation.ExpiresAt.After(before)
+	})
+
+	sort.Slice(reservations, func(i, j int) bool {
+		return reservations[i].ExpiresAt.Before(*reservations[j].ExpiresAt)
+	})
+
+	if limit > 0 && limit < len(reservations) {
+		reservations = reservations[:limit]
+	}
+	return reservations, nil
+}
+
+// SetChannelAllocation creates or updates the sales channel allocation of an item at a location, deleting it when the quantity is 0
+// 商品のロケーションの販売チャネルの確保数量を作成・更新（数量が0の場合は削除）
+func (s *MemoryStorage) SetChannelAllocation(ctx context.Context, allocation *inventory.ChannelAllocation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := channelKey{itemID: allocation.ItemID, locationID: allocation.LocationID, channel: allocation.Channel}
+	if allocation.Quantity == 0 {
+		delete(s.channels, key)
+		return nil
+	}
+	s.channels[key] = *allocation
+	return nil
+}
+
+// ListChannelAllocations lists the sales channel allocations of an item at a location ordered by channel
+// 商品のロケーションの販売チャネルの確保をチャネル名の昇順で取得
+func (s *MemoryStorage) ListChannelAllocations(ctx context.Context, itemID, locationID string) ([]inventory.ChannelAllocation, error) {
+	s.mu.RLock()
+	allocations := make([]inventory.ChannelAllocation, 0)
+	for key, allocation := range s.channels {
+		if key.itemID == itemID && key.locationID == locationID {
+			allocations = append(allocations, allocation)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(allocations, func(i, j int) bool {
+		return allocations[i].Channel < allocations[j].Channel
+	})
+	return allocations, nil
+}
+
+// filterReservations returns copies of the reservations matching fn
+// fnに一致する予約のコピーを返す
+func (s *MemoryStorage) filterReservations(fn func(reservation *inventory.Reservation) bool) []inventory.Reservation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	reservations := make([]inventory.Reservation, 0)
+	for _, reservation := range s.reservations {
+		if fn(&reservation) {
+			reservations = append(reservations, copyReservation(reservation))
+		}
+	}
+	return reservations
+}
+
+// CreateBackorder creates a new backorder
+// 新しいバックオーダーを作成
+func (s *MemoryStorage) CreateBackorder(ctx context.Context, backorder *inventory.Backorder) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.backorders[backorder.ID]; exists {
+		return fmt.Errorf("バックオーダーは既に存在します: %s", backorder.ID)
+	}
+	s.backorders[backorder.ID] = copyBackorder(*backorder)
+
+	return nil
+}
+
+// GetBackorder retrieves a backorder by ID
+// IDでバックオーダーを取得
+func (s *MemoryStorage) GetBackorder(ctx context.Context, backorderID string) (*inventory.Backorder, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	backorder, exists := s.backorders[backorderID]
+	if !exists {
+		return nil, inventory.ErrBackorderNotFound
+	}
+
+	backorder = copyBackorder(backorder)
+	return &backorder, nil
+}
+
+// UpdateBackorder updates a pending backorder
+// 入荷待ちのバックオーダーを更新
+func (s *MemoryStorage) UpdateBackorder(ctx context.Context, backorder *inventory.Backorder) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, exists := s.backorders[backorder.ID]
+	if !exists {
+		return inventory.ErrBackorderNotFound
+	}
+	if current.Status != inventory.BackorderStatusPending {
+		return inventory.ErrBackorderNotPending
+	}
+
+	current.Status = backorder.Status
+	current.ReservationID = backorder.ReservationID
+	current.ClosedAt = backorder.ClosedAt
+	s.backorders[backorder.ID] = copyBackorder(current)
+
+	return nil
+}
+
+// SaveReorderPoint creates or overwrites the reorder point of an item at a location
+// 商品・ロケーションの発注点を作成または上書き
+func (s *MemoryStorage) SaveReorderPoint(ctx context.Context, reorderPoint *inventory.ReorderPoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reorder[stockKey{reorderPoint.ItemID, reorderPoint.LocationID}] = *reorderPoint
+	return nil
+}
+
+// GetReorderPoint retrieves the reorder point of an item at a location
+// 商品・ロケーションの発注点を取得
+func (s *MemoryStorage) GetReorderPoint(ctx context.Context, itemID, locationID string) (*inventory.ReorderPoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	reorderPoint, exists := s.reorder[stockKey{itemID, locationID}]
+	if !exists {
+		return nil, inventory.ErrReorderPointNotFound
+	}
+	return &reorderPoint, nil
+}
+
+// DeleteReorderPoint deletes the reorder point of an item at a location
+// 商品・ロケーションの発注点を削除
+func (s *MemoryStorage) DeleteReorderPoint(ctx context.Context, itemID, locationID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := stockKey{itemID, locationID}
+	if _, exists := s.reorder[key]; !exists {
+		return inventory.ErrReorderPointNotFound
+	}
+	delete(s.reorder, key)
+	return nil
+}
+
+// ListReorderPoints lists reorder points matching a filter, ordered by item and location
+// 条件に一致する発注点を商品ID・ロケーションIDの昇順で取得
+func (s *MemoryStorage) ListReorderPoints(ctx context.Context, filter inventory.ReorderPointFilter) ([]inventory.ReorderPoint, error) {
+	s.mu.RLock()
+	reorderPoints := make([]inventory.ReorderPoint, 0)
+	for key, reorderPoint := range s.reorder {
+		if (filter.ItemID == "" || key.itemID == filter.ItemID) &&
+			(filter.LocationID == "" || key.locationID == filter.LocationID) {
+			reorderPoints = append(reorderPoints, reorderPoint)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(reorderPoints, func(i, j int) bool {
+		if reorderPoints[i].ItemID != reorderPoints[j].ItemID {
+			return reorderPoints[i].ItemID < reorderPoints[j].ItemID
+		}
+		return reorderPoints[i].LocationID < reorderPoints[j].LocationID
+	})
+
+	if filter.Offset >= len(reorderPoints) {
+		return []inventory.ReorderPoint{}, nil
+	}
+	reorderPoints = reorderPoints[filter.Offset:]
+	if filter.Limit > 0 && filter.Limit < len(reorderPoints) {
+		reorderPoints = reorderPoints[:filter.Limit]
+	}
+	return reorderPoints, nil
+}
+
+// ListBackorders lists backorders matching a filter, oldest first
+// 条件に一致するバックオーダーを作成日時の昇順で取得
+func (s *MemoryStorage) ListBackorders(ctx context.Context, filter inventory.BackorderFilter) ([]inventory.Backorder, error) {
+	s.mu.RLock()
+	backorders := make([]inventory.Backorder, 0)
+	for _, backorder := range s.backorders {
+		if (filter.ItemID == "" || backorder.ItemID == filter.ItemID) &&
+			(filter.LocationID == "" || backorder.LocationID == filter.LocationID) &&
+			(filter.Status == "" || backorder.Status == filter.Status) &&
+			(filter.Reference == "" || backorder.Reference == filter.Reference) {
+			backorders = append(backorders, copyBackorder(backorder))
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(backorders, func(i, j int) bool {
+		if !backorders[i].CreatedAt.Equal(backorders[j].CreatedAt) {
+			return backorders[i].CreatedAt.Before(backorders[j].CreatedAt)
+		}
+		return backorders[i].ID < backorders[j].ID
+	})
+
+	if filter.Offset >= len(backorders) {
+		return []inventory.Backorder{}, nil
+	}
+	backorders = backorders[filter.Offset:]
+	if filter.Limit > 0 && filter.Limit < len(backorders) {
+		backorders = backorders[:filter.Limit]
+	}
+	return backorders, nil
+}
+
+// CreateStockSnapshot copies every stock record into a new snapshot
+// 全ての在庫記録を複製してスナップショットを作成
+func (s *MemoryStorage) CreateStockSnapshot(ctx context.Context, snapshot *inventory.StockSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.snapshots[snapshot.ID]; exists {
+		return fmt.Errorf("在庫スナップショット %s は既に存在します", snapshot.ID)
+	}
+	lines := make([]inventory.StockSnapshotLine, 0, len(s.stocks))
+	snapshot.StockCount, snapshot.TotalQuantity, snapshot.TotalReserved = 0, 0, 0
+	for _, stock := range s.stocks {
+		lines = append(lines, inventory.StockSnapshotLine{
+			SnapshotID: snapshot.ID,
+			ItemID:     stock.ItemID,
+			LocationID: stock.LocationID,
+			Quantity:   stock.Quantity,
+			Reserved:   stock.Reserved,
+		})
+		snapshot.StockCount++
+		snapshot.TotalQuantity += stock.Quantity
+		snapshot.TotalReserved += stock.Reserved
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		if lines[i].ItemID != lines[j].ItemID {
+			return lines[i].ItemID < lines[j].ItemID
+		}
+		return lines[i].LocationID < lines[j].LocationID
+	})
+
+	s.snapshots[snapshot.ID] = *snapshot
+	s.snapshotRows[snapshot.ID] = lines
+	return nil
+}
+
+// ListStockSnapshots lists stock snapshots, newest first
+// スナップショットを取得日時の新しい順に取得
+func (s *MemoryStorage) ListStockSnapshots(ctx context.Context, offset, limit int) ([]inventory.StockSnapshot, error) {
+	s.mu.RLock()
+	snapshots := make([]inventory.StockSnapshot, 0, len(s.snapshots))
+	for _, snapshot := range s.snapshots {
+		snapshots = append(snapshots, snapshot)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		if !snapshots[i].TakenAt.Equal(snapshots[j].TakenAt) {
+			return snapshots[i].TakenAt.After(snapshots[j].TakenAt)
+		}
+		return snapshots[i].ID > snapshots[j].ID
+	})
+
+	if offset >= len(snapshots) {
+		return []inventory.StockSnapshot{}, nil
+	}
+	snapshots = snapshots[offset:]
+	if limit > 0 && limit < len(snapshots) {
+		snapshots = snapshots[:limit]
+	}
+	return snapshots, nil
+}
+
+// GetStockSnapshotLines retrieves the stock records copied into a snapshot
+// スナップショットの在庫記録を商品ID・ロケーションIDの昇順で取得
+func (s *MemoryStorage) GetStockSnapshotLines(ctx context.Context, snapshotID, locationID string) ([]inventory.StockSnapshotLine, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, exists := s.snapshots[snapshotID]; !exists {
+		return nil, inventory.ErrSnapshotNotFound
+	}
+	lines := make([]inventory.StockSnapshotLine, 0)
+	for _, line := range s.snapshotRows[snapshotID] {
+		if locationID == "" || line.LocationID == locationID {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+// CreateStocktake creates a stocktake with its lines
+// 棚卸と行を作成
+func (s *MemoryStorage) CreateStocktake(ctx context.Context, stocktake *inventory.Stocktake) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.stocktakes[stocktake.ID]; exists {
+		return fmt.Errorf("棚卸 %s は既に存在します", stocktake.ID)
+	}
+	record := copyStocktake(*stocktake)
+	sortStocktakeLines(record.Lines)
+	s.stocktakes[stocktake.ID] = record
+	return nil
+}
+
+// GetStocktake retrieves a stocktake with its lines
+// 棚卸を行とともに取得
+func (s *MemoryStorage) GetStocktake(ctx context.Context, stocktakeID string) (*inventory.Stocktake, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stocktake, exists := s.stocktakes[stocktakeID]
+	if !exists {
+		return nil, inventory.ErrStocktakeNotFound
+	}
+	record := copyStocktake(stocktake)
+	return &record, nil
+}
+
+// ListStocktakes lists stocktakes matching a filter, newest first, without their lines
+// 条件に一致する棚卸を作成日時の新しい順に取得（行は含まない）
+func (s *MemoryStorage) ListStocktakes(ctx context.Context, filter inventory.StocktakeFilter) ([]inventory.Stocktake, error) {
+	s.mu.RLock()
+	stocktakes := make([]inventory.Stocktake, 0)
+	for _, stocktake := range s.stocktakes {
+		if filter.LocationID != "" && stocktake.LocationID != filter.LocationID {
+			continue
+		}
+		if filter.Status != "" && stocktake.Status != filter.Status {
+			continue
+		}
+		record := copyStocktake(stocktake)
+		record.Lines = nil
+		stocktakes = append(stocktakes, record)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(stocktakes, func(i, j int) bool {
+		if !stocktakes[i].CreatedAt.Equal(stocktakes[j].CreatedAt) {
+			return stocktakes[i].CreatedAt.After(stocktakes[j].CreatedAt)
+		}
+		return stocktakes[i].ID > stocktakes[j].ID
+	})
+
+	if filter.Offset >= len(stocktakes) {
+		return []inventory.Stocktake{}, nil
+	}
+	stocktakes = stocktakes[filter.Offset:]
+	if filter.Limit > 0 && filter.Limit < len(stocktakes) {
+		stocktakes = stocktakes[:filter.Limit]
+	}
+	return stocktakes, nil
+}
+
+// UpdateStocktake updates the status of a stocktake in status from and saves the given lines
+// 状態がfromの棚卸の状態を更新し、指定した行を作成または上書き
+func (s *MemoryStorage) UpdateStocktake(ctx context.Context, stocktake *inventory.Stocktake, from inventory.StocktakeStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, exists := s.stocktakes[stocktake.ID]
+	if !exists {
+		return inventory.ErrStocktakeNotFound
+	}
+	if current.Status != from {
+		return inventory.ErrStocktakeStatus
+	}
+
+	updated := copyStocktake(current)
+	updated.Status = stocktake.Status
+	updated.SubmittedAt = copyTime(stocktake.SubmittedAt)
+	updated.SubmittedBy = stocktake.SubmittedBy
+	updated.ClosedAt = copyTime(stocktake.ClosedAt)
+	updated.ClosedBy = stocktake.ClosedBy
+
+	index := make(map[string]int, len(updated.Lines))
+	for i, line := range updated.Lines {
+		index[line.ItemID] = i
+	}
+	for _, line := range copyStocktake(*stocktake).Lines {
+		line.StocktakeID = stocktake.ID
+		if i, ok := index[line.ItemID]; ok {
+			updated.Lines[i] = line
+		} else {
+			updated.Lines = append(updated.Lines, line)
+		}
+	}
+	sortStocktakeLines(updated.Lines)
+	s.stocktakes[stocktake.ID] = updated
+	return nil
+}
+
+// CreateAdjustment creates a stock adjustment
+// 在庫調整を作成
+func (s *MemoryStorage) CreateAdjustment(ctx context.Context, adjustment *inventory.Adjustment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.adjustments[adjustment.ID]; exists {
+		return fmt.Errorf("在庫調整 %s は既に存在します", adjustment.ID)
+	}
+	s.adjustments[adjustment.ID] = copyAdjustment(*adjustment)
+	return nil
+}
+
+// GetAdjustment retrieves a stock adjustment by ID
+// IDで在庫調整を取得
+func (s *MemoryStorage) GetAdjustment(ctx context.Context, adjustmentID string) (*inventory.Adjustment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	adjustment, exists := s.adjustments[adjustmentID]
+	if !exists {
+		return nil, inventory.ErrAdjustmentNotFound
+	}
+	record := copyAdjustment(adjustment)
+	return &record, nil
+}
+
+// ListAdjustments lists stock adjustments matching a filter, newest first
+// 条件に一致する在庫調整を申請日時の新しい順に取得
+func (s *MemoryStorage) ListAdjustments(ctx context.Context, filter inventory.AdjustmentFilter) ([]inventory.Adjustment, error) {
+	s.mu.RLock()
+	adjustments := make([]inventory.Adjustment, 0)
+	for _, adjustment := range s.adjustments {
+		if filter.ItemID != "" && adjustment.ItemID != filter.ItemID {
+			continue
+		}
+		if filter.LocationID != "" && adjustment.LocationID != filter.LocationID {
+			continue
+		}
+		if filter.Status != "" && adjustment.Status != filter.Status {
+			continue
+		}
+		if filter.Reason != "" && adjustment.Reason != filter.Reason {
+			continue
+		}
+		adjustments = append(adjustments, copyAdjustment(adjustment))
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(adjustments, func(i, j int) bool {
+		if !adjustments[i].RequestedAt.Equal(adjustments[j].RequestedAt) {
+			return adjustments[i].RequestedAt.After(adjustments[j].RequestedAt)
+		}
+		return adjustments[i].ID > adjustments[j].ID
+	})
+
+	if filter.Offset >= len(adjustments) {
+		return []inventory.Adjustment{}, nil
+	}
+	adjustments = adjustments[filter.Offset:]
+	if filter.Limit > 0 && filter.Limit < len(adjustments) {
+		adjustments = adjustments[:filter.Limit]
+	}
+	return adjustments, nil
+}
+
+// UpdateAdjustment records the decision on a stock adjustment in status from
+// 状態がfromの在庫調整に承認・却下の結果を記録
+func (s *MemoryStorage) UpdateAdjustment(ctx context.Context, adjustment *inventory.Adjustment, from inventory.AdjustmentStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, exists := s.adjustments[adjustment.ID]
+	if !exists {
+		return inventory.ErrAdjustmentNotFound
+	}
+	if current.Status != from {
+		return inventory.ErrAdjustmentNotPending
+	}
+
+	current.Status = adjustment.Status
+	current.NewQuantity = adjustment.NewQuantity
+	current.TransactionID = adjustment.TransactionID
+	current.DecidedAt = copyTime(adjustment.DecidedAt)
+	current.DecidedBy = adjustment.DecidedBy
+	current.DecisionNote = adjustment.DecisionNote
+	s.adjustments[adjustment.ID] = current
+	return nil
+}
+
+// CreateInspection creates a receiving inspection
+// 入荷検品を作成
+func (s *MemoryStorage) CreateInspection(ctx context.Context, inspection *inventory.Inspection) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.inspections[inspection.ID]; exists {
+		return fmt.Errorf("入荷検品 %s は既に存在します", inspection.ID)
+	}
+	s.inspections[inspection.ID] = copyInspection(*inspection)
+	return nil
+}
+
+// GetInspection retrieves a receiving inspection by ID
+// IDで入荷検品を取得
+func (s *MemoryStorage) GetInspection(ctx context.Context, inspectionID string) (*inventory.Inspection, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	inspection, exists := s.inspections[inspectionID]
+	if !exists {
+		return nil, inventory.ErrInspectionNotFound
+	}
+	record := copyInspection(inspection)
+	return &record, nil
+}
+
+// ListInspections lists receiving inspections matching a filter, newest first
+// 条件に一致する入荷検品を入荷日時の新しい順に取得
+func (s *MemoryStorage) ListInspections(ctx context.Context, filter inventory.InspectionFilter) ([]inventory.Inspection, error) {
+	s.mu.RLock()
+	inspections := make([]inventory.Inspection, 0)
+	for _, inspection := range s.inspections {
+		if filter.ItemID != "" && inspection.ItemID != filter.ItemID {
+			continue
+		}
+		if filter.LocationID != "" && inspection.LocationID != filter.LocationID {
+			continue
+		}
+		if filter.Status != "" && inspection.Status != filter.Status {
+			continue
+		}
+		inspections = append(inspections, copyInspection(inspection))
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(inspections, func(i, j int) bool {
+		if !inspections[i].ReceivedAt.Equal(inspections[j].ReceivedAt) {
+			return inspections[i].ReceivedAt.After(inspections[j].ReceivedAt)
+		}
+		return inspections[i].ID > inspections[j].ID
+	})
+
+	if filter.Offset >= len(inspections) {
+		return []inventory.Inspection{}, nil
+	}
+	inspections = inspections[filter.Offset:]
+	if filter.Limit > 0 && filter.Limit < len(inspections) {
+		inspections = inspections[:filter.Limit]
+	}
+	return inspections, nil
+}
+
+// UpdateInspection records the disposition of a receiving inspection in status from
+// 状態がfromの入荷検品に合否の判定を記録
+func (s *MemoryStorage) UpdateInspection(ctx context.Context, inspection *inventory.Inspection, from inventory.InspectionStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, exists := s.inspections[inspection.ID]
+	if !exists {
+		return inventory.ErrInspectionNotFound
+	}
+	if current.Status != from {
+		return inventory.ErrInspectionNotQuarantined
+	}
+
+	current.Status = inspection.Status
+	current.Reason = inspection.Reason
+	current.TransactionID = inspection.TransactionID
+	current.InspectedAt = copyTime(inspection.InspectedAt)
+	current.InspectedBy = inspection.InspectedBy
+	current.InspectionNote = inspection.InspectionNote
+	s.inspections[inspection.ID] = current
+	return nil
+}
+
+// SaveBatchOperation creates or overwrites the record of a batch operation
+// バッチ操作の記録を作成または上書き
+func (s *MemoryStorage) SaveBatchOperation(ctx context.Context, batch *inventory.BatchOperation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.batches[batch.ID] = copyBatch(*batch)
+
+	return nil
+}
+
+// GetBatchOperation retrieves a batch operation by ID
+// IDでバッチ操作を取得
+func (s *MemoryStorage) GetBatchOperation(ctx context.Context, batchID string) (*inventory.BatchOperation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	batch, exists := s.batches[batchID]
+	if !exists {
+		return nil, inventory.ErrBatchNotFound
+	}
+
+	batch = copyBatch(batch)
+	return &batch, nil
+}
+
+// GetInventorySummary aggregates the whole inventory
+// 在庫全体を集計
+func (s *MemoryStorage) GetInventorySummary(ctx context.Context, lowStockThreshold int64) (*inventory.InventorySummary, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	summary := &inventory.InventorySummary{TotalSKUs: int64(len(s.items))}
+	lowStock := make(map[string]int64, len(s.locations))
+	for id := range s.locations {
+		lowStock[id] = 0
+	}
+	// 評価額は委託在庫を除いた自社所有の在庫で計算する
+	consigned := make(map[stockKey]int64)
+	for key, consignment := range s.consigned {
+		consigned[stockKey{itemID: key.itemID, locationID: key.locationID}] += consignment.Quantity
+	}
+	for key, stock := range s.stocks {
+		summary.TotalUnits += stock.Quantity
+		if item, exists := s.items[key.itemID]; exists {
+			summary.TotalValue += float64(stock.Quantity-consigned[key]) * item.UnitCost
+		}
+		threshold := lowStockThreshold
+		if reorderPoint, exists := s.reorder[key]; exists {
+			threshold = reorderPoint.ReorderPointQty
+		}
+		if stock.Quantity <= threshold {
+			lowStock[key.locationID]++
+		}
+	}
+	for _, alert := range s.alerts {
+		if alert.IsActive {
+			summary.ActiveAlerts++
+		}
+	}
+
+	summary.LowStock = make([]inventory.LocationLowStock, 0, len(lowStock))
+	for id, count := range lowStock {
+		summary.LowStock = append(summary.LowStock, inventory.LocationLowStock{LocationID: id, Items: count})
+	}
+	sort.Slice(summary.LowStock, func(i, j int) bool {
+		return summary.LowStock[i].LocationID < summary.LowStock[j].LocationID
+	})
+
+	return summary, nil
+}
+
+// Ping always succeeds for the in-memory storage
+// インメモリストレージでは常に成功
+func (s *MemoryStorage) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Close is a no-op for the in-memory storage
+// インメモリストレージでは何もしない
+func (s *MemoryStorage) Close() error {
+	return nil
+}
+
+// ヘルパー関数
+
+// filterTransactions returns matching transactions, newest first
+// 条件に一致するトランザクションを (作成日時, ID) の降順で返す（limitが0以下の場合は全件）
+func (s *MemoryStorage) filterTransactions(limit int, match func(tx *inventory.Transaction) bool) []inventory.Transaction {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var transactions []inventory.Transaction
+	for i := range s.transactions {
+		if match(&s.transactions[i]) {
+			transactions = append(transactions, copyTransaction(s.transactions[i]))
+		}
+	}
+
+	sort.SliceStable(transactions, func(i, j int) bool {
+		if !transactions[i].CreatedAt.Equal(transactions[j].CreatedAt) {
+			return transactions[i].CreatedAt.After(transactions[j].CreatedAt)
+		}
+		return transactions[i].ID > transactions[j].ID
+	})
+
+	if limit > 0 && len(transactions) > limit {
+		transactions = transactions[:limit]
+	}
+
+	return transactions
+}
+
+// filterLots returns copies of the lots that match the predicate
+// 条件に一致するロットのコピーを返す
+func (s *MemoryStorage) filterLots(match func(lot *inventory.Lot) bool) []inventory.Lot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var lots []inventory.Lot
+	for _, lot := range s.lots {
+		if match(&lot) {
+			lots = append(lots, copyLot(lot))
+		}
+	}
+
+	return lots
+}
+
+// sortLotsByExpiry sorts lots by expiry date ascending
+// ロットを有効期限の昇順にソート
+func sortLotsByExpiry(lots []inventory.Lot) {
+	sort.Slice(lots, func(i, j int) bool {
+		return lots[i].ExpiryDate.Before(*lots[j].ExpiryDate)
+	})
+}
+
+// paginate applies offset/limit to a slice
+// スライスにoffset/limitを適用
+func paginate[T any](records []T, offset, limit int) []T {
+	if offset >= len(records) {
+		return []T{}
+	}
+	records = records[offset:]
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+	return records
+}
+
+// pageAfter sorts records by (created_at, id) descending and returns up to limit records after the cursor
+// レコードを (作成日時, ID) の降順に並べ、カーソルより後の最大limit件を返す
+func pageAfter[T any](records []T, after *inventory.PageCursor, limit int, key func(T) (time.Time, string)) []T {
+	sort.Slice(records, func(i, j int) bool {
+		ti, idi := key(records[i])
+		tj, idj := key(records[j])
+		if ti.Equal(tj) {
+			return idi > idj
+		}
+		return ti.After(tj)
+	})
+
+	page := make([]T, 0, limit)
+	for _, record := range records {
+		if after != nil {
+			createdAt, id := key(record)
+			if createdAt.After(after.AfterCreatedAt) || (createdAt.Equal(after.AfterCreatedAt) && id >= after.AfterID) {
+				continue
+			}
+		}
+		if len(page) == limit {
+			break
+		}
+		page = append(page, record)
+	}
+	return page
+}
+
+// snapshot returns an independent copy of the current state
+// 現在の状態の独立したコピーを返す（呼び出し側でロックを保持すること）
+func (s *MemoryStorage) snapshot() *MemoryStorage {
+	clone := NewMemoryStorage()
+	for id, item := range s.items {
+		clone.items[id] = item
+	}
+	for id, location := range s.locations {
+		clone.locations[id] = location
+	}
+	for key, stock := range s.stocks {
+		clone.stocks[key] = stock
+	}
+	clone.transactions = make([]inventory.Transaction, 0, len(s.transactions))
+	for _, tx := range s.transactions {
+		clone.transactions = append(clone.transactions, copyTransaction(tx))
+	}
+	clone.archived = make([]inventory.Transaction, 0, len(s.archived))
+	for _, tx := range s.archived {
+		clone.archived = append(clone.archived, copyTransaction(tx))
+	}
+	for id, lot := range s.lots {
+		clone.lots[id] = copyLot(lot)
+	}
+	for key, lotStock := range s.lotStocks {
+		clone.lotStocks[key] = lotStock
+	}
+	for key, consignment := range s.consigned {
+		clone.consigned[key] = consignment
+	}
+	for key, allocation := range s.channels {
+		clone.channels[key] = allocation
+	}
+	// 削除した組み込みの単位が復元されないよう、作成時に登録された単位を置き換える
+	clone.units = make(map[string]inventory.UnitOfMeasure, len(s.units))
+	for code, unit := range s.units {
+		clone.units[code] = unit
+	}
+	for key, conversion := range s.conversions {
+		clone.conversions[key] = conversion
+	}
+	for id, alert := range s.alerts {
+		clone.alerts[id] = alert
+	}
+	for id, batch := range s.batches {
+		clone.batches[id] = copyBatch(batch)
+	}
+	for id, reservation := range s.reservations {
+		clone.reservations[id] = copyReservation(reservation)
+	}
+	for id, backorder := range s.backorders {
+		clone.backorders[id] = copyBackorder(backorder)
+	}
+	for key, reorderPoint := range s.reorder {
+		clone.reorder[key] = reorderPoint
+	}
+	for id, rule := range s.alertRules {
+		clone.alertRules[id] = rule
+	}
+	// スナップショットの在庫記録は作成後に変更しないため、スライスを共有する
+	for id, snapshot := range s.snapshots {
+		clone.snapshots[id] = snapshot
+		clone.snapshotRows[id] = s.snapshotRows[id]
+	}
+	for id, stocktake := range s.stocktakes {
+		clone.stocktakes[id] = copyStocktake(stocktake)
+	}
+	for id, adjustment := range s.adjustments {
+		clone.adjustments[id] = copyAdjustment(adjustment)
+	}
+	for id, inspection := range s.inspections {
+		clone.inspections[id] = copyInspection(inspection)
+	}
+	for kitItemID, bom := range s.boms {
+		clone.boms[kitItemID] = copyBillOfMaterials(bom)
+	}
+	for id, assembly := range s.assemblies {
+		clone.assemblies[id] = copyAssembly(assembly)
+	}
+	for id, supplier := range s.suppliers {
+		clone.suppliers[id] = supplier
+	}
+	for key, supplierItem := range s.sourcing {
+		clone.sourcing[key] = supplierItem
+	}
+	for id, order := range s.purchases {
+		clone.purchases[id] = copyPurchaseOrder(order)
+	}
+	// 入荷は作成後に変更しないため、各入荷の行は共有する
+	for orderID, receipts := range s.receipts {
+		clone.receipts[orderID] = append([]inventory.GoodsReceipt(nil), receipts...)
+	}
+	for id, order := range s.sales {
+		clone.sales[id] = copySalesOrder(order)
+	}
+	for id, rma := range s.returns {
+		clone.returns[id] = copyReturn(rma)
+	}
+	// 処分は作成後に変更しないため共有する
+	for returnID, dispositions := range s.dispositions {
+		clone.dispositions[returnID] = append([]inventory.ReturnDisposition(nil), dispositions...)
+	}
+	return clone
+}
+
+// copyStocktake deep-copies the lines and pointer fields of a stocktake
+// 棚卸の行・ポインタフィールドをディープコピー
+func copyStocktake(stocktake inventory.Stocktake) inventory.Stocktake {
+	stocktake.SubmittedAt = copyTime(stocktake.SubmittedAt)
+	stocktake.ClosedAt = copyTime(stocktake.ClosedAt)
+	if stocktake.Lines != nil {
+		lines := make([]inventory.StocktakeLine, len(stocktake.Lines))
+		for i, line := range stocktake.Lines {
+			if line.CountedQty != nil {
+				counted := *line.CountedQty
+				line.CountedQty = &counted
+			}
+			line.CountedAt = copyTime(line.CountedAt)
+			lines[i] = line
+		}
+		stocktake.Lines = lines
+	}
+	return stocktake
+}
+
+// copyAdjustment deep-copies the pointer fields of a stock adjustment
+// 在庫調整のポインタフィールドをディープコピー
+func copyAdjustment(adjustment inventory.Adjustment) inventory.Adjustment {
+	adjustment.DecidedAt = copyTime(adjustment.DecidedAt)
+	return adjustment
+}
+
+// copyInspection deep-copies the pointer fields of a receiving inspection
+// 入荷検品のポインタフィールドをディープコピー
+func copyInspection(inspection inventory.Inspection) inventory.Inspection {
+	inspection.InspectedAt = copyTime(inspection.InspectedAt)
+	return inspection
+}
+
+// copyBillOfMaterials deep-copies the components of a bill of materials
+// 部品表の構成品をディープコピー
+func copyBillOfMaterials(bom inventory.BillOfMaterials) inventory.BillOfMaterials {
+	bom.Components = append([]inventory.BOMComponent(nil), bom.Components...)
+	return bom
+}
+
+// copyAssembly deep-copies the components of an assembly
+// 組立・分解の構成品をディープコピー
+func copyAssembly(assembly inventory.Assembly) inventory.Assembly {
+	assembly.Components = append([]inventory.BOMComponent(nil), assembly.Components...)
+	assembly.Transactions = nil
+	return assembly
+}
+
+// copyPurchaseOrder deep-copies the pointer fields and lines of a purchase order
+// 発注のポインタフィールドと行をディープコピー
+func copyPurchaseOrder(order inventory.PurchaseOrder) inventory.PurchaseOrder {
+	order.ExpectedAt = copyTime(order.ExpectedAt)
+	order.ApprovedAt = copyTime(order.ApprovedAt)
+	order.SentAt = copyTime(order.SentAt)
+	order.ClosedAt = copyTime(order.ClosedAt)
+	if order.Lines != nil {
+		order.Lines = append([]inventory.PurchaseOrderLine(nil), order.Lines...)
+	}
+	return order
+}
+
+// copyGoodsReceipt deep-copies the lines of a goods receipt
+// 入荷の行をディープコピー
+func copyGoodsReceipt(receipt inventory.GoodsReceipt) inventory.GoodsReceipt {
+	lines := make([]inventory.GoodsReceiptLine, len(receipt.Lines))
+	for i, line := range receipt.Lines {
+		line.ExpiryDate = copyTime(line.ExpiryDate)
+		lines[i] = line
+	}
+	receipt.Lines = lines
+	return receipt
+}
+
+// copySalesOrder deep-copies the pointer fields and lines of a sales order
+// 受注のポインタフィールドと行をディープコピー
+func copySalesOrder(order inventory.SalesOrder) inventory.SalesOrder {
+	order.ClosedAt = copyTime(order.ClosedAt)
+	if order.Lines != nil {
+		order.Lines = append([]inventory.SalesOrderLine(nil), order.Lines...)
+	}
+	return order
+}
+
+// copyReturn deep-copies the pointer fields and lines of a return
+// 返品のポインタフィールドと行をディープコピー
+func copyReturn(rma inventory.ReturnAuthorization) inventory.ReturnAuthorization {
+	rma.ClosedAt = copyTime(rma.ClosedAt)
+	if rma.Lines != nil {
+		rma.Lines = append([]inventory.ReturnLine(nil), rma.Lines...)
+	}
+	return rma
+}
+
+// sortStocktakeLines sorts the lines of a stocktake by item ID
+// 棚卸の行を商品IDの昇順に並べ替え
+func sortStocktakeLines(lines []inventory.StocktakeLine) {
+	sort.Slice(lines, func(i, j int) bool {
+		return lines[i].ItemID < lines[j].ItemID
+	})
+}
+
+// copyTransaction deep-copies pointer and map fields of a transaction
+// トランザクションのポインタ・マップフィールドをディープコピー
+func copyTransaction(tx inventory.Transaction) inventory.Transaction {
+	if tx.FromLocation != nil {
+		from := *tx.FromLocation
+		tx.FromLocation = &from
+	}
+	if tx.ToLocation != nil {
+		to := *tx.ToLocation
+		tx.ToLocation = &to
+	}
+	if tx.UnitCost != nil {
+		cost := *tx.UnitCost
+		tx.UnitCost = &cost
+	}
+	if tx.LotNumber != nil {
+		lotNumber := *tx.LotNumber
+		tx.LotNumber = &lotNumber
+	}
+	if tx.ExpiryDate != nil {
+		expiry := *tx.ExpiryDate
+		tx.ExpiryDate = &expiry
+	}
+	if tx.Metadata != nil {
+		metadata := make(map[string]string, len(tx.Metadata))
+		for k, v := range tx.Metadata {
+			metadata[k] = v
+		}
+		tx.Metadata = metadata
+	}
+	return tx
+}
+
+// copyLot deep-copies pointer fields of a lot
+// ロットのポインタフィールドをディープコピー
+func copyLot(lot inventory.Lot) inventory.Lot {
+	if lot.ExpiryDate != nil {
+		expiry := *lot.ExpiryDate
+		lot.ExpiryDate = &expiry
+	}
+	return lot
+}
+
+// copyReservation deep-copies pointer fields of a reservation
+// 予約のポインタフィールドをディープコピー
+func copyReservation(reservation inventory.Reservation) inventory.Reservation {
+	if reservation.ExpiresAt != nil {
+		expiresAt := *reservation.ExpiresAt
+		reservation.ExpiresAt = &expiresAt
+	}
+	if reservation.ReleasedAt != nil {
+		releasedAt := *reservation.ReleasedAt
+		reservation.ReleasedAt = &releasedAt
+	}
+	return reservation
+}
+
+// copyBackorder copies the pointer fields of a backorder
+// バックオーダーのポインタフィールドをコピー
+func copyBackorder(backorder inventory.Backorder) inventory.Backorder {
+	if backorder.ClosedAt != nil {
+		closedAt := *backorder.ClosedAt
+		backorder.ClosedAt = &closedAt
+	}
+	return backorder
+}
+
+// copyBatch deep-copies the slices and pointer fields of a batch operation
+// バッチ操作のスライス・ポインタフィールドをディープコピー
+func copyBatch(batch inventory.BatchOperation) inventory.BatchOperation {
+	batch.Operations = append(make([]inventory.InventoryOperation, 0, len(batch.Operations)), batch.Operations...)
+	for i, op := range batch.Operations {
+		if op.ToLocationID != nil {
+			to := *op.ToLocationID
+			batch.Operations[i].ToLocationID = &to
+		}
+	}
+	batch.Errors = append(make([]inventory.BatchOperationError, 0, len(batch.Errors)), batch.Errors...)
+	batch.Results = append(make([]inventory.BatchOperationResult, 0, len(batch.Results)), batch.Results...)
+	if batch.CompletedAt != nil {
+		completedAt := *batch.CompletedAt
+		batch.CompletedAt = &completedAt
+	}
+	return batch
+}
+
+// copyTime copies an optional time
+// 任意の日時をコピー
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	copied := *t
+	return &copied
+}
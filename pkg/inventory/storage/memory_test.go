@@ -0,0 +1,3203 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// newTestMemoryStorage はテスト用の商品とロケーションを登録済みのストレージを作成
+func newTestMemoryStorage(t *testing.T) *MemoryStorage {
+	t.Helper()
+
+	ctx := context.Background()
+	store := NewMemoryStorage()
+	now := time.Now()
+
+	require.NoError(t, store.CreateItem(ctx, &inventory.Item{ID: "TEST-ITEM", Name: "テスト商品", CreatedAt: now, UpdatedAt: now}))
+	require.NoError(t, store.CreateLocation(ctx, &inventory.Location{ID: "LOC-A", Name: "ロケーションA", CreatedAt: now, UpdatedAt: now}))
+	require.NoError(t, store.CreateLocation(ctx, &inventory.Location{ID: "LOC-B", Name: "ロケーションB", CreatedAt: now, UpdatedAt: now}))
+
+	return store
+}
+
+// TestManager_ConditionalUpdateConcurrent は更新日時を想定した商品・ロケーションの同時更新のテスト
+func TestManager_ConditionalUpdateConcurrent(t *testing.T) {
+	ctx := context.Background()
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{DefaultLocation: "LOC-A"})
+
+	item, err := manager.GetItem(ctx, "TEST-ITEM")
+	require.NoError(t, err)
+	location, err := manager.GetLocation(ctx, "LOC-A")
+	require.NoError(t, err)
+
+	// 同じ更新日時を確認した複数のクライアントのうち、更新できるのは1つのみ
+	const writers = 8
+	var wg sync.WaitGroup
+	itemErrs := make([]error, writers)
+	locationErrs := make([]error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			updatedItem := *item
+			updatedItem.Name = fmt.Sprintf("商品%d", i)
+			itemErrs[i] = manager.UpdateItem(inventory.WithExpectedUpdatedAt(ctx, item.UpdatedAt), &updatedItem)
+			updatedLocation := *location
+			updatedLocation.Name = fmt.Sprintf("ロケーション%d", i)
+			locationErrs[i] = manager.UpdateLocation(inventory.WithExpectedUpdatedAt(ctx, location.UpdatedAt), &updatedLocation)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, errs := range [][]error{itemErrs, locationErrs} {
+		succeeded := 0
+		for _, err := range errs {
+			if err == nil {
+				succeeded++
+				continue
+			}
+			assert.ErrorIs(t, err, inventory.ErrPreconditionFailed)
+		}
+		assert.Equal(t, 1, succeeded)
+	}
+
+	// 古い更新日時での更新は失敗し、条件なしの更新と存在しない商品は従来どおり
+	stale := *item
+	assert.ErrorIs(t, manager.UpdateItem(inventory.WithExpectedUpdatedAt(ctx, item.UpdatedAt), &stale), inventory.ErrPreconditionFailed)
+	require.NoError(t, manager.UpdateItem(ctx, &stale))
+	current, err := manager.GetItem(ctx, "TEST-ITEM")
+	require.NoError(t, err)
+	require.NoError(t, manager.UpdateItem(inventory.WithExpectedUpdatedAt(ctx, current.UpdatedAt), &stale))
+	assert.ErrorIs(t, store.UpdateItem(inventory.WithExpectedUpdatedAt(ctx, item.UpdatedAt), &inventory.Item{ID: "NO-ITEM", Name: "なし"}), inventory.ErrItemNotFound)
+}
+
+// TestMemoryStorage_UpdateStockVersionMismatch は楽観的ロックのテスト
+func TestMemoryStorage_UpdateStockVersionMismatch(t *testing.T) {
+	store := newTestMemoryStorage(t)
+	ctx := context.Background()
+
+	stock := &inventory.Stock{ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 10, Version: 1}
+	require.NoError(t, store.CreateStock(ctx, stock))
+
+	// 正しいバージョンでの更新は成功
+	stock.Quantity = 20
+	stock.Version = 2
+	assert.NoError(t, store.UpdateStock(ctx, stock))
+
+	// 古いバージョンでの更新は失敗
+	stale := &inventory.Stock{ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 5, Version: 2}
+	assert.Equal(t, inventory.ErrVersionMismatch, store.UpdateStock(ctx, stale))
+
+	current, err := store.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(20), current.Quantity)
+	assert.Equal(t, int64(20), current.Available)
+}
+
+// TestMemoryStorage_WithManager はマネージャー経由での在庫操作のテスト
+func TestMemoryStorage_WithManager(t *testing.T) {
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), nil)
+	ctx := context.Background()
+
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 100, "PO-001"))
+	require.NoError(t, manager.Transfer(ctx, "TEST-ITEM", "LOC-A", "LOC-B", 40, "TR-001"))
+	assert.Equal(t, inventory.ErrInsufficientStock, manager.Remove(ctx, "TEST-ITEM", "LOC-A", 100, "SO-001"))
+
+	stockA, err := manager.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(60), stockA.Quantity)
+
+	total, err := manager.GetTotalStock(ctx, "TEST-ITEM")
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), total)
+
+	// 移動は1件の移動トランザクションとして記録される
+	history, err := manager.GetHistoryByLocation(ctx, "LOC-B", 10)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, inventory.TransactionTypeTransfer, history[0].Type)
+}
+
+// failingTransactionStorage はトランザクション内のトランザクション記録の作成を失敗させるテスト用のストレージ
+type failingTransactionStorage struct {
+	inventory.Storage
+}
+
+func (s *failingTransactionStorage) WithinTx(ctx context.Context, fn func(txStorage inventory.Storage) error) error {
+	return s.Storage.WithinTx(ctx, func(txStorage inventory.Storage) error {
+		return fn(&failingTransactionStorage{Storage: txStorage})
+	})
+}
+
+func (s *failingTransactionStorage) CreateTransaction(ctx context.Context, tx *inventory.Transaction) error {
+	return errors.New("トランザクションの書き込みに失敗しました")
+}
+
+// TestManager_TransferAtomic は移動の各処理が単一のトランザクションで実行されることのテスト
+func TestManager_TransferAtomic(t *testing.T) {
+	store := newTestMemoryStorage(t)
+	ctx := context.Background()
+	require.NoError(t, inventory.NewManager(store, nil, zap.NewNop(), nil).Add(ctx, "TEST-ITEM", "LOC-A", 10, "INIT"))
+
+	// 移動の記録に失敗した場合は移動元の減算・移動先の加算も取り消し、イベントも発行しない
+	publisher := &recordingPublisher{}
+	failing := inventory.NewManager(&failingTransactionStorage{Storage: store}, publisher, zap.NewNop(), nil)
+	var storageErr *inventory.StorageError
+	assert.ErrorAs(t, failing.Transfer(ctx, "TEST-ITEM", "LOC-A", "LOC-B", 4, "TR-FAIL"), &storageErr)
+
+	stock, err := store.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), stock.Quantity)
+	_, err = store.GetStock(ctx, "TEST-ITEM", "LOC-B")
+	assert.ErrorIs(t, err, inventory.ErrStockNotFound)
+	assert.Empty(t, publisher.changes)
+	assert.Empty(t, publisher.transfers)
+
+	// 成功した移動はコミット後にイベントを発行する
+	manager := inventory.NewManager(store, publisher, zap.NewNop(), nil)
+	require.NoError(t, manager.Transfer(ctx, "TEST-ITEM", "LOC-A", "LOC-B", 4, "TR-OK"))
+	stock, err = store.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), stock.Quantity)
+	stock, err = store.GetStock(ctx, "TEST-ITEM", "LOC-B")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), stock.Quantity)
+	require.Len(t, publisher.transfers, 1)
+	assert.Equal(t, "TR-OK", publisher.transfers[0].Reference)
+}
+
+// TestMemoryStorage_ExecuteBatchAtomicRollback はアトミックバッチのロールバックのテスト
+func TestMemoryStorage_ExecuteBatchAtomicRollback(t *testing.T) {
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), nil)
+	ctx := context.Background()
+
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 10, "PO-001"))
+
+	// 2件目の出庫が在庫不足で失敗するため、1件目の入庫もロールバックされる
+	batch, err := manager.ExecuteBatchAtomic(ctx, []inventory.InventoryOperation{
+		{Type: inventory.OperationTypeAdd, ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 5, Reference: "BATCH-001"},
+		{Type: inventory.OperationTypeRemove, ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 100, Reference: "BATCH-002"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, inventory.BatchStatusFailed, batch.Status)
+	assert.Equal(t, 0, batch.SuccessCount)
+	assert.Equal(t, 2, batch.FailureCount)
+	require.Len(t, batch.Errors, 1)
+	assert.Equal(t, 1, batch.Errors[0].OperationIndex)
+
+	stock, err := manager.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), stock.Quantity)
+
+	history, err := manager.GetHistory(ctx, "TEST-ITEM", 10)
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+
+	// 全操作が成功した場合はコミットされる
+	batch, err = manager.ExecuteBatchAtomic(ctx, []inventory.InventoryOperation{
+		{Type: inventory.OperationTypeAdd, ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 5, Reference: "BATCH-003"},
+		{Type: inventory.OperationTypeRemove, ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 3, Reference: "BATCH-004"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, inventory.BatchStatusCompleted, batch.Status)
+
+	stock, err = manager.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), stock.Quantity)
+}
+
+// TestMemoryStorage_PessimisticLocking は悲観的ロックでの同時更新のテスト
+func TestMemoryStorage_PessimisticLocking(t *testing.T) {
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{
+		DefaultLocation: "LOC-A",
+		LockingStrategy: inventory.LockingStrategyPessimistic,
+	})
+	ctx := context.Background()
+
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 1, "PO-001"))
+
+	// 同一在庫への同時更新がバージョン競合なしですべて成功する
+	const workers = 20
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			errs <- manager.Add(ctx, "TEST-ITEM", "LOC-A", 1, "PO-002")
+		}()
+	}
+	for i := 0; i < workers; i++ {
+		assert.NoError(t, <-errs)
+	}
+
+	require.NoError(t, manager.Reserve(ctx, "TEST-ITEM", "LOC-A", 5, "SO-001"))
+
+	stock, err := manager.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(workers+1), stock.Quantity)
+	assert.Equal(t, int64(5), stock.Reserved)
+}
+
+// TestMemoryStorage_HistoryPagination はキーセットページネーションのテスト
+func TestMemoryStorage_HistoryPagination(t *testing.T) {
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), nil)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 1, "PO-001"))
+	}
+
+	seen := make(map[string]bool)
+	var cursor *inventory.HistoryCursor
+	pages := 0
+	for {
+		page, err := manager.GetHistoryPage(ctx, "TEST-ITEM", cursor, 2)
+		require.NoError(t, err)
+		pages++
+		for _, tx := range page.Transactions {
+			assert.False(t, seen[tx.ID], "同じトランザクションが複数ページに含まれています")
+			seen[tx.ID] = true
+		}
+		if page.NextCursor == nil {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	assert.Equal(t, 3, pages)
+	assert.Len(t, seen, 5)
+}
+
+// TestManager_AdjustExpectedVersion は想定したバージョンの在庫のみを調整するテスト
+func TestManager_AdjustExpectedVersion(t *testing.T) {
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), nil)
+	ctx := context.Background()
+
+	// 在庫が存在しない場合
+	err := manager.Adjust(inventory.WithExpectedVersion(ctx, 1), "TEST-ITEM", "LOC-A", 10, "ADJ-1")
+	assert.ErrorIs(t, err, inventory.ErrPreconditionFailed)
+
+	require.NoError(t, manager.Adjust(ctx, "TEST-ITEM", "LOC-A", 10, "ADJ-1"))
+	stock, err := manager.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+
+	require.NoError(t, manager.Adjust(inventory.WithExpectedVersion(ctx, stock.Version), "TEST-ITEM", "LOC-A", 20, "ADJ-2"))
+
+	// 古いバージョンでの調整は上書きしない
+	err = manager.Adjust(inventory.WithExpectedVersion(ctx, stock.Version), "TEST-ITEM", "LOC-A", 30, "ADJ-3")
+	assert.ErrorIs(t, err, inventory.ErrPreconditionFailed)
+
+	stock, err = manager.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(20), stock.Quantity)
+	assert.Equal(t, int64(2), stock.Version)
+}
+
+// TestManager_GetStocks は在庫の一括取得のテスト
+func TestManager_GetStocks(t *testing.T) {
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), nil)
+	ctx := context.Background()
+
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 10, "IN-1"))
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-B", 20, "IN-2"))
+
+	// 指定した順序で返し、重複と存在しない組は除く
+	stocks, err := manager.GetStocks(ctx, []inventory.StockKey{
+		{ItemID: "TEST-ITEM", LocationID: "LOC-B"},
+		{ItemID: "TEST-ITEM", LocationID: "LOC-C"},
+		{ItemID: "TEST-ITEM", LocationID: "LOC-A"},
+		{ItemID: "TEST-ITEM", LocationID: "LOC-B"},
+	})
+	require.NoError(t, err)
+	require.Len(t, stocks, 2)
+	assert.Equal(t, "LOC-B", stocks[0].LocationID)
+	assert.Equal(t, int64(20), stocks[0].Quantity)
+	assert.Equal(t, "LOC-A", stocks[1].LocationID)
+
+	stocks, err = manager.GetStocks(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, stocks)
+
+	_, err = manager.GetStocks(ctx, []inventory.StockKey{{ItemID: "TEST-ITEM"}})
+	var validationErr *inventory.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	_, err = manager.GetStocks(ctx, make([]inventory.StockKey, 501))
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+// TestManager_GetSummary は在庫全体の集計のテスト
+func TestManager_GetSummary(t *testing.T) {
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), nil)
+	ctx := context.Background()
+
+	now := time.Now()
+	require.NoError(t, store.CreateItem(ctx, &inventory.Item{ID: "ITEM-2", Name: "商品2", UnitCost: 2.5, CreatedAt: now, UpdatedAt: now}))
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 5, "IN-1"))
+	require.NoError(t, manager.Add(ctx, "ITEM-2", "LOC-A", 100, "IN-2"))
+	require.NoError(t, manager.Add(ctx, "ITEM-2", "LOC-B", 20, "IN-3"))
+	// 閾値（10）以下になるため低在庫アラートが作成される
+	require.NoError(t, manager.Remove(ctx, "ITEM-2", "LOC-B", 12, "OUT-1"))
+	require.NoError(t, store.CreateAlert(ctx, &inventory.StockAlert{ID: "ALERT-OLD", ItemID: "ITEM-2", LocationID: "LOC-A", IsActive: false, CreatedAt: now}))
+
+	summary, err := manager.GetSummary(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), summary.TotalSKUs)
+	assert.Equal(t, int64(113), summary.TotalUnits)
+	assert.InDelta(t, 270.0, summary.TotalValue, 0.0001)
+	assert.Equal(t, int64(1), summary.ActiveAlerts)
+	assert.Equal(t, int64(10), summary.LowStockThreshold)
+	assert.Equal(t, []inventory.LocationLowStock{
+		{LocationID: "LOC-A", Items: 1},
+		{LocationID: "LOC-B", Items: 1},
+	}, summary.LowStock)
+	assert.False(t, summary.GeneratedAt.IsZero())
+}
+
+// TestManager_ImportItems は商品の取り込みのテスト
+func TestManager_ImportItems(t *testing.T) {
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), nil)
+	ctx := context.Background()
+
+	rows := []inventory.ItemImportRow{
+		{Line: 2, Item: inventory.Item{ID: "ITEM-1", Name: "商品1", SKU: "SKU-1", UnitCost: 100}},
+		{Line: 3, Item: inventory.Item{ID: "ITEM-2", Name: "商品2", SKU: "SKU-1"}},
+		{Line: 4, Item: inventory.Item{ID: "ITEM-1", Name: "商品1（重複）"}},
+		{Line: 5, Item: inventory.Item{ID: "TEST-ITEM", Name: "既存の商品"}},
+		{Line: 6, Item: inventory.Item{ID: "ITEM-3", Name: ""}},
+	}
+
+	// 1行でもエラーがあれば何も登録しない
+	result, err := manager.ImportItems(ctx, rows, false)
+	require.NoError(t, err)
+	assert.Equal(t, 5, result.Rows)
+	assert.Zero(t, result.Imported)
+	require.Len(t, result.Errors, 4)
+	assert.Equal(t, 3, result.Errors[0].Line)
+	assert.Equal(t, "sku", result.Errors[0].Field)
+	assert.Equal(t, 4, result.Errors[1].Line)
+	assert.Equal(t, 5, result.Errors[2].Line)
+	assert.Equal(t, 6, result.Errors[3].Line)
+	assert.Equal(t, "name", result.Errors[3].Field)
+	_, err = store.GetItem(ctx, "ITEM-1")
+	assert.ErrorIs(t, err, inventory.ErrItemNotFound)
+
+	// dryRunでは検証のみ行う
+	result, err = manager.ImportItems(ctx, rows[:1], true)
+	require.NoError(t, err)
+	assert.Empty(t, result.Errors)
+	assert.Zero(t, result.Imported)
+	_, err = store.GetItem(ctx, "ITEM-1")
+	assert.ErrorIs(t, err, inventory.ErrItemNotFound)
+
+	result, err = manager.ImportItems(ctx, rows[:1], false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Imported)
+	item, err := store.GetItem(ctx, "ITEM-1")
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, item.UnitCost)
+	assert.False(t, item.CreatedAt.IsZero())
+}
+
+// TestManager_ImportOpeningBalances は期首残高の取り込みのテスト
+func TestManager_ImportOpeningBalances(t *testing.T) {
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), nil)
+	ctx := context.Background()
+
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-B", 3, "IN-1"))
+
+	unitCost := 50.0
+	rows := []inventory.OpeningBalanceRow{
+		{Line: 2, ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 10, UnitCost: &unitCost},
+		{Line: 3, ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 5},
+		{Line: 4, ItemID: "UNKNOWN", LocationID: "LOC-A", Quantity: 1},
+		{Line: 5, ItemID: "TEST-ITEM", LocationID: "LOC-C", Quantity: 1},
+		{Line: 6, ItemID: "TEST-ITEM", LocationID: "LOC-B", Quantity: 1},
+		{Line: 7, ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: -1},
+	}
+
+	result, err := manager.ImportOpeningBalances(ctx, rows, false)
+	require.NoError(t, err)
+	assert.Zero(t, result.Imported)
+	lines := make([]int, 0, len(result.Errors))
+	for _, rowErr := range result.Errors {
+		lines = append(lines, rowErr.Line)
+	}
+	assert.ElementsMatch(t, []int{3, 4, 5, 6, 7}, lines)
+	_, err = store.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	assert.ErrorIs(t, err, inventory.ErrStockNotFound)
+
+	result, err = manager.ImportOpeningBalances(ctx, rows[:1], false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Imported)
+	stock, err := store.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), stock.Quantity)
+	assert.Equal(t, int64(10), stock.Available)
+
+	// 入庫トランザクションを記録するため台帳と一致する
+	history, err := store.GetTransactionsByReference(ctx, inventory.OpeningBalanceReference)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, inventory.TransactionTypeInbound, history[0].Type)
+	report, err := manager.CheckStockConsistency(ctx, false)
+	require.NoError(t, err)
+	assert.Empty(t, report.Divergences)
+}
+
+// TestMemoryStorage_ItemPagination は商品一覧のキーセットページネーションのテスト
+func TestMemoryStorage_ItemPagination(t *testing.T) {
+	store := NewMemoryStorage()
+	manager := inventory.NewManager(store, nil, zap.NewNop(), nil)
+	ctx := context.Background()
+
+	// 作成日時が同じ商品はIDの降順で並ぶ
+	created := time.Now()
+	for i := 0; i < 5; i++ {
+		require.NoError(t, store.CreateItem(ctx, &inventory.Item{ID: fmt.Sprintf("ITEM-%d", i), Name: "商品", CreatedAt: created}))
+	}
+
+	var ids []string
+	var cursor *inventory.PageCursor
+	for {
+		page, err := manager.ListItemsPage(ctx, inventory.ItemFilter{}, cursor, 2)
+		require.NoError(t, err)
+		for _, item := range page.Items {
+			ids = append(ids, item.ID)
+		}
+		if page.NextCursor == nil {
+			break
+		}
+		// カーソルは不透明な文字列として往復させる
+		cursor, err = inventory.DecodePageCursor(page.NextCursor.Encode())
+		require.NoError(t, err)
+
+		// ページの取得中に追加・削除があってもずれない
+		if len(ids) == 2 {
+			require.NoError(t, store.CreateItem(ctx, &inventory.Item{ID: "ITEM-NEW", Name: "新商品", CreatedAt: created.Add(time.Second)}))
+			require.NoError(t, store.DeleteItem(ctx, "ITEM-4"))
+		}
+	}
+
+	assert.Equal(t, []string{"ITEM-4", "ITEM-3", "ITEM-2", "ITEM-1", "ITEM-0"}, ids)
+
+	_, err := inventory.DecodePageCursor("not-a-cursor")
+	assert.Error(t, err)
+}
+
+// TestMemoryStorage_ItemFilter は商品一覧の絞り込み・並び替えのテスト
+func TestMemoryStorage_ItemFilter(t *testing.T) {
+	store := NewMemoryStorage()
+	manager := inventory.NewManager(store, nil, zap.NewNop(), nil)
+	ctx := context.Background()
+
+	created := time.Now()
+	for i, item := range []inventory.Item{
+		{ID: "ITEM-1", Name: "ボルト", SKU: "HW-001", Category: "hardware", UnitCost: 10},
+		{ID: "ITEM-2", Name: "ナット", SKU: "HW-002", Category: "hardware", UnitCost: 5},
+		{ID: "ITEM-3", Name: "ワッシャー", SKU: "HW_003", Category: "hardware", UnitCost: 5},
+		{ID: "ITEM-4", Name: "ドライバー", SKU: "TL-001", Category: "tools", UnitCost: 30},
+	} {
+		item.CreatedAt = created.Add(time.Duration(i) * time.Second)
+		require.NoError(t, store.CreateItem(ctx, &item))
+	}
+	cost := func(v float64) *float64 { return &v }
+
+	// 全ページを辿ってIDを返す
+	list := func(filter inventory.ItemFilter) []string {
+		var ids []string
+		var cursor *inventory.PageCursor
+		for {
+			page, err := manager.ListItemsPage(ctx, filter, cursor, 1)
+			require.NoError(t, err)
+			for _, item := range page.Items {
+				ids = append(ids, item.ID)
+			}
+			if page.NextCursor == nil {
+				return ids
+			}
+			cursor, err = inventory.DecodePageCursor(page.NextCursor.Encode())
+			require.NoError(t, err)
+		}
+	}
+
+	assert.Equal(t, []string{"ITEM-3", "ITEM-2", "ITEM-1"}, list(inventory.ItemFilter{Category: "hardware"}))
+	assert.Equal(t, []string{"ITEM-2", "ITEM-1"}, list(inventory.ItemFilter{SKUPrefix: "HW-"}))
+	assert.Equal(t, []string{"ITEM-3", "ITEM-2", "ITEM-1"}, list(inventory.ItemFilter{MinCost: cost(5), MaxCost: cost(10)}))
+	// 単価が同じ商品はIDの順
+	assert.Equal(t, []string{"ITEM-2", "ITEM-3", "ITEM-1", "ITEM-4"}, list(inventory.ItemFilter{SortBy: inventory.ItemSortUnitCost, Ascending: true}))
+	assert.Equal(t, []string{"ITEM-4", "ITEM-1", "ITEM-3", "ITEM-2"}, list(inventory.ItemFilter{SortBy: inventory.ItemSortUnitCost}))
+	assert.Equal(t, []string{"ITEM-4", "ITEM-3", "ITEM-2", "ITEM-1"}, list(inventory.ItemFilter{SortBy: inventory.ItemSortSKU}))
+	assert.Equal(t, []string{"ITEM-1", "ITEM-2", "ITEM-3", "ITEM-4"}, list(inventory.ItemFilter{Ascending: true}))
+
+	// 並び順の異なるカーソル・不正な条件
+	page, err := manager.ListItemsPage(ctx, inventory.ItemFilter{SortBy: inventory.ItemSortName}, nil, 1)
+	require.NoError(t, err)
+	_, err = manager.ListItemsPage(ctx, inventory.ItemFilter{}, page.NextCursor, 1)
+	assert.Error(t, err)
+	_, err = manager.ListItemsPage(ctx, inventory.ItemFilter{SortBy: "description"}, nil, 1)
+	assert.Error(t, err)
+	_, err = manager.ListItemsPage(ctx, inventory.ItemFilter{MinCost: cost(10), MaxCost: cost(5)}, nil, 1)
+	assert.Error(t, err)
+}
+
+// TestMemoryStorage_ForEachStockByLocation は在庫のストリーミング走査のテスト
+func TestMemoryStorage_ForEachStockByLocation(t *testing.T) {
+	store := newTestMemoryStorage(t)
+	ctx := context.Background()
+
+	for _, itemID := range []string{"ITEM-C", "ITEM-A", "ITEM-B"} {
+		require.NoError(t, store.CreateStock(ctx, &inventory.Stock{ItemID: itemID, LocationID: "LOC-A", Quantity: 1, Version: 1}))
+	}
+	require.NoError(t, store.CreateStock(ctx, &inventory.Stock{ItemID: "ITEM-A", LocationID: "LOC-B", Quantity: 1, Version: 1}))
+
+	var itemIDs []string
+	require.NoError(t, store.ForEachStockByLocation(ctx, "LOC-A", func(stock inventory.Stock) error {
+		itemIDs = append(itemIDs, stock.ItemID)
+		return nil
+	}))
+	assert.Equal(t, []string{"ITEM-A", "ITEM-B", "ITEM-C"}, itemIDs)
+
+	// fnのエラーで走査が中断される
+	stop := errors.New("stop")
+	visited := 0
+	err := store.ForEachStockByLocation(ctx, "LOC-A", func(stock inventory.Stock) error {
+		visited++
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, visited)
+}
+
+// TestMemoryStorage_LotCorrections はロットの更新・数量調整・削除のテスト
+func TestMemoryStorage_LotCorrections(t *testing.T) {
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), nil)
+	ctx := context.Background()
+
+	require.NoError(t, manager.CreateLot(ctx, &inventory.Lot{ID: "LOT-1", Number: "L001", ItemID: "TEST-ITEM", Quantity: 10, UnitCost: 100, CreatedAt: time.Now()}))
+
+	require.NoError(t, manager.UpdateLot(ctx, &inventory.Lot{ID: "LOT-1", Number: "L001-A", Quantity: 12, UnitCost: 110}))
+	lot, err := manager.GetLot(ctx, "LOT-1")
+	require.NoError(t, err)
+	assert.Equal(t, "L001-A", lot.Number)
+	assert.Equal(t, "TEST-ITEM", lot.ItemID)
+
+	lot, err = manager.AdjustLotQuantity(ctx, "LOT-1", -5, "ADJ-001")
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), lot.Quantity)
+
+	// 数量が負になる調整は拒否され、トランザクションも記録されない
+	_, err = manager.AdjustLotQuantity(ctx, "LOT-1", -8, "ADJ-002")
+	assert.Equal(t, inventory.ErrInsufficientStock, err)
+
+	history, err := manager.GetHistory(ctx, "TEST-ITEM", 10)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, int64(-5), history[0].Quantity)
+	assert.Equal(t, "L001-A", *history[0].LotNumber)
+
+	require.NoError(t, manager.DeleteLot(ctx, "LOT-1"))
+	assert.Equal(t, inventory.ErrLotNotFound, manager.DeleteLot(ctx, "LOT-1"))
+}
+
+// TestMemoryStorage_SearchTransactionsByMetadata はメタデータ検索のテスト
+func TestMemoryStorage_SearchTransactionsByMetadata(t *testing.T) {
+	store := newTestMemoryStorage(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	for i, channel := range []string{"web", "store", "web"} {
+		require.NoError(t, store.CreateTransaction(ctx, &inventory.Transaction{
+			ID:        fmt.Sprintf("TX-%d", i),
+			Type:      inventory.TransactionTypeOutbound,
+			ItemID:    "TEST-ITEM",
+			Quantity:  1,
+			Metadata:  map[string]string{"order_channel": channel},
+			CreatedAt: now.Add(time.Duration(i) * time.Second),
+		}))
+	}
+
+	txs, err := store.SearchTransactionsByMetadata(ctx, "order_channel", "web", 10)
+	require.NoError(t, err)
+	require.Len(t, txs, 2)
+	assert.Equal(t, "TX-2", txs[0].ID)
+	assert.Equal(t, "TX-0", txs[1].ID)
+
+	txs, err = store.SearchTransactionsByMetadata(ctx, "order_channel", "phone", 10)
+	require.NoError(t, err)
+	assert.Empty(t, txs)
+}
+
+// TestMemoryStorage_GetTransactionByID はIDによるトランザクション取得のテスト
+func TestMemoryStorage_GetTransactionByID(t *testing.T) {
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), nil)
+	ctx := context.Background()
+
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 10, "PO-001"))
+	history, err := manager.GetHistory(ctx, "TEST-ITEM", 1)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+
+	tx, err := manager.GetTransaction(ctx, history[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "PO-001", tx.Reference)
+
+	_, err = manager.GetTransaction(ctx, "UNKNOWN")
+	assert.Equal(t, inventory.ErrTransactionNotFound, err)
+}
+
+// TestMemoryStorage_ArchiveTransactions はトランザクションのアーカイブのテスト
+func TestMemoryStorage_ArchiveTransactions(t *testing.T) {
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), nil)
+	ctx := context.Background()
+	now := time.Now()
+
+	for i, createdAt := range []time.Time{now.AddDate(-2, 0, 0), now.AddDate(-1, 0, 0), now} {
+		require.NoError(t, store.CreateTransaction(ctx, &inventory.Transaction{
+			ID:        fmt.Sprintf("TX-%d", i),
+			Type:      inventory.TransactionTypeInbound,
+			ItemID:    "TEST-ITEM",
+			Quantity:  1,
+			CreatedAt: createdAt,
+		}))
+	}
+
+	archived, err := manager.ArchiveTransactions(ctx, now.AddDate(0, -6, 0))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), archived)
+
+	history, err := manager.GetHistory(ctx, "TEST-ITEM", 10)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "TX-2", history[0].ID)
+
+	// 未来の日時は指定できない
+	_, err = manager.ArchiveTransactions(ctx, now.Add(time.Hour))
+	assert.Error(t, err)
+}
+
+// TestMemoryStorage_BulkImport は一括取り込みのテスト
+func TestMemoryStorage_BulkImport(t *testing.T) {
+	store := newTestMemoryStorage(t)
+	ctx := context.Background()
+	now := time.Now()
+	to := "LOC-A"
+
+	err := store.BulkImport(ctx, &inventory.BulkImportData{
+		Items:  []inventory.Item{{ID: "BULK-ITEM", Name: "一括商品", CreatedAt: now, UpdatedAt: now}},
+		Stocks: []inventory.Stock{{ItemID: "BULK-ITEM", LocationID: "LOC-A", Quantity: 100, Reserved: 10, Version: 1, UpdatedAt: now}},
+		Transactions: []inventory.Transaction{{
+			ID: "BULK-TX", Type: inventory.TransactionTypeInbound, ItemID: "BULK-ITEM", ToLocation: &to, Quantity: 100, CreatedAt: now,
+		}},
+	})
+	require.NoError(t, err)
+
+	stock, err := store.GetStock(ctx, "BULK-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(90), stock.Available)
+
+	tx, err := store.GetTransactionByID(ctx, "BULK-TX")
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), tx.Quantity)
+
+	// 重複がある場合は全体がロールバックされる
+	err = store.BulkImport(ctx, &inventory.BulkImportData{
+		Items: []inventory.Item{
+			{ID: "BULK-ITEM-2", Name: "一括商品2", CreatedAt: now, UpdatedAt: now},
+			{ID: "TEST-ITEM", Name: "重複", CreatedAt: now, UpdatedAt: now},
+		},
+	})
+	assert.ErrorIs(t, err, inventory.ErrDuplicateItem)
+
+	_, err = store.GetItem(ctx, "BULK-ITEM-2")
+	assert.Error(t, err)
+}
+
+// recordingPublisher は発行されたイベントを記録するテスト用の発行者
+type recordingPublisher struct {
+	changes   []inventory.StockChangedEvent
+	transfers []inventory.ItemTransferredEvent
+}
+
+func (p *recordingPublisher) PublishStockChanged(ctx context.Context, event inventory.StockChangedEvent) error {
+	p.changes = append(p.changes, event)
+	return nil
+}
+
+func (p *recordingPublisher) PublishLowStockAlert(ctx context.Context, event inventory.LowStockAlertEvent) error {
+	return nil
+}
+
+func (p *recordingPublisher) PublishItemTransferred(ctx context.Context, event inventory.ItemTransferredEvent) error {
+	p.transfers = append(p.transfers, event)
+	return nil
+}
+
+// TestManager_ReplayEvents はトランザクション台帳からのイベント再生のテスト
+func TestManager_ReplayEvents(t *testing.T) {
+	store := newTestMemoryStorage(t)
+	ctx := context.Background()
+
+	// 台帳を直接作成して作成日時を固定する
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	locA, locB := "LOC-A", "LOC-B"
+	for _, tx := range []inventory.Transaction{
+		{ID: "TX-1", Type: inventory.TransactionTypeInbound, ItemID: "TEST-ITEM", ToLocation: &locA, Quantity: 100, CreatedAt: base},
+		{ID: "TX-2", Type: inventory.TransactionTypeOutbound, ItemID: "TEST-ITEM", FromLocation: &locA, Quantity: 30, CreatedAt: base.Add(time.Hour)},
+		{ID: "TX-3", Type: inventory.TransactionTypeTransfer, ItemID: "TEST-ITEM", FromLocation: &locA, ToLocation: &locB, Quantity: 20, CreatedAt: base.Add(2 * time.Hour)},
+		{ID: "TX-4", Type: inventory.TransactionTypeAdjust, ItemID: "TEST-ITEM", ToLocation: &locB, Quantity: -5, CreatedAt: base.Add(3 * time.Hour)},
+		{ID: "TX-5", Type: inventory.TransactionTypeInbound, ItemID: "TEST-ITEM", ToLocation: &locA, Quantity: 1, CreatedAt: base.Add(4 * time.Hour)},
+	} {
+		tx := tx
+		require.NoError(t, store.CreateTransaction(ctx, &tx))
+	}
+
+	publisher := &recordingPublisher{}
+	manager := inventory.NewManager(store, publisher, zap.NewNop(), nil)
+
+	// TX-2からTX-4までを再生（TX-1は残高の算出のみに使用）
+	result, err := manager.ReplayEvents(ctx, base.Add(time.Hour), base.Add(4*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Transactions)
+	assert.Equal(t, 5, result.Events)
+	assert.Equal(t, "TX-4", result.LastTransactionID)
+
+	require.Len(t, publisher.changes, 4)
+	assert.Equal(t, "TX-2", publisher.changes[0].TransactionID)
+	assert.Equal(t, int64(100), publisher.changes[0].OldQuantity)
+	assert.Equal(t, int64(70), publisher.changes[0].NewQuantity)
+	assert.Equal(t, int64(-30), publisher.changes[0].Delta)
+	assert.Nil(t, publisher.changes[0].Available)
+	assert.Equal(t, base.Add(time.Hour), publisher.changes[0].Timestamp)
+	assert.Equal(t, int64(50), publisher.changes[1].NewQuantity)
+	assert.Equal(t, "LOC-B", publisher.changes[2].LocationID)
+	assert.Equal(t, int64(20), publisher.changes[2].NewQuantity)
+	assert.Equal(t, int64(15), publisher.changes[3].NewQuantity)
+
+	require.Len(t, publisher.transfers, 1)
+	assert.Equal(t, "TX-3", publisher.transfers[0].TransactionID)
+
+	_, err = manager.ReplayEvents(ctx, base, base)
+	assert.Error(t, err)
+}
+
+// TestManager_CheckStockConsistency は台帳との整合性チェックと修復のテスト
+func TestManager_CheckStockConsistency(t *testing.T) {
+	store := newTestMemoryStorage(t)
+	ctx := context.Background()
+
+	publisher := &recordingPublisher{}
+	manager := inventory.NewManager(store, publisher, zap.NewNop(), nil)
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 100, "PO-1"))
+	require.NoError(t, manager.Transfer(ctx, "TEST-ITEM", "LOC-A", "LOC-B", 30, "TR-1"))
+
+	report, err := manager.CheckStockConsistency(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Transactions)
+	assert.Equal(t, 2, report.Stocks)
+	assert.Empty(t, report.Divergences)
+
+	// 台帳を伴わない在庫の変更と、在庫記録を伴わない台帳の記録で不一致を作る
+	stock, err := store.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	stock.Quantity = 65
+	stock.Version++
+	require.NoError(t, store.UpdateStock(ctx, stock))
+	locB := "LOC-B"
+	require.NoError(t, store.CreateTransaction(ctx, &inventory.Transaction{
+		ID: "TX-ORPHAN", Type: inventory.TransactionTypeInbound, ItemID: "ITEM-2", ToLocation: &locB, Quantity: 5, CreatedAt: time.Now(),
+	}))
+
+	report, err = manager.CheckStockConsistency(ctx, false)
+	require.NoError(t, err)
+	require.Len(t, report.Divergences, 2)
+	assert.Equal(t, "LOC-A", report.Divergences[0].LocationID)
+	assert.Equal(t, int64(70), report.Divergences[0].LedgerQuantity)
+	assert.Equal(t, int64(65), report.Divergences[0].StockQuantity)
+	assert.Equal(t, int64(-5), report.Divergences[0].Difference)
+	assert.False(t, report.Divergences[0].MissingStock)
+	assert.Equal(t, "ITEM-2", report.Divergences[1].ItemID)
+	assert.True(t, report.Divergences[1].MissingStock)
+	assert.Equal(t, int64(5), report.Divergences[1].LedgerQuantity)
+	assert.Equal(t, 0, report.Repaired)
+
+	// 修復すると在庫記録が台帳の数量になり、在庫変更イベントが発行される
+	report, err = manager.CheckStockConsistency(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Repaired)
+	assert.True(t, report.Divergences[0].Repaired)
+
+	stock, err = store.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(70), stock.Quantity)
+	created, err := store.GetStock(ctx, "ITEM-2", "LOC-B")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), created.Quantity)
+
+	last := publisher.changes[len(publisher.changes)-1]
+	assert.Equal(t, "repair", last.ChangeType)
+	assert.Equal(t, int64(5), last.Delta)
+
+	report, err = manager.CheckStockConsistency(ctx, false)
+	require.NoError(t, err)
+	assert.Empty(t, report.Divergences)
+}
+
+// TestMemoryStorage_BatchStatus はバッチ操作の記録とステータス取得のテスト
+func TestMemoryStorage_BatchStatus(t *testing.T) {
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), nil)
+	ctx := context.Background()
+
+	// 一部の操作が失敗したバッチ
+	batch, err := manager.ExecuteBatch(ctx, []inventory.InventoryOperation{
+		{Type: inventory.OperationTypeAdd, ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 10, Reference: "BATCH-001"},
+		{Type: inventory.OperationTypeRemove, ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 50, Reference: "BATCH-001"},
+	})
+	require.NoError(t, err)
+
+	status, err := manager.GetBatchStatus(ctx, batch.ID)
+	require.NoError(t, err)
+	assert.Equal(t, inventory.BatchStatusFailed, status.Status)
+	assert.False(t, status.Atomic)
+	assert.Len(t, status.Operations, 2)
+	assert.Equal(t, 1, status.SuccessCount)
+	assert.Equal(t, 1, status.FailureCount)
+	require.Len(t, status.Results, 2)
+	assert.Equal(t, inventory.OperationResultSucceeded, status.Results[0].Status)
+	assert.Equal(t, inventory.OperationResultFailed, status.Results[1].Status)
+	assert.NotEmpty(t, status.Results[1].Error)
+	require.Len(t, status.Errors, 1)
+	assert.Equal(t, 1, status.Errors[0].OperationIndex)
+	assert.NotNil(t, status.CompletedAt)
+
+	// アトミックバッチは失敗した操作以外もロールバック済みとして記録する
+	batch, err = manager.ExecuteBatchAtomic(ctx, []inventory.InventoryOperation{
+		{Type: inventory.OperationTypeAdd, ItemID: "TEST-ITEM", LocationID: "LOC-B", Quantity: 5, Reference: "BATCH-002"},
+		{Type: inventory.OperationTypeRemove, ItemID: "TEST-ITEM", LocationID: "LOC-B", Quantity: 50, Reference: "BATCH-002"},
+		{Type: inventory.OperationTypeAdd, ItemID: "TEST-ITEM", LocationID: "LOC-B", Quantity: 5, Reference: "BATCH-002"},
+	})
+	require.NoError(t, err)
+
+	status, err = manager.GetBatchStatus(ctx, batch.ID)
+	require.NoError(t, err)
+	assert.True(t, status.Atomic)
+	assert.Equal(t, inventory.BatchStatusFailed, status.Status)
+	assert.Equal(t, 3, status.FailureCount)
+	require.Len(t, status.Results, 3)
+	assert.Equal(t, inventory.OperationResultRolledBack, status.Results[0].Status)
+	assert.Equal(t, inventory.OperationResultFailed, status.Results[1].Status)
+	assert.Equal(t, inventory.OperationResultRolledBack, status.Results[2].Status)
+
+	// 返却値を変更しても保存された記録には影響しない
+	status.Results[0].Status = inventory.OperationResultSucceeded
+	stored, err := store.GetBatchOperation(ctx, batch.ID)
+	require.NoError(t, err)
+	assert.Equal(t, inventory.OperationResultRolledBack, stored.Results[0].Status)
+
+	_, err = manager.GetBatchStatus(ctx, "UNKNOWN")
+	assert.ErrorIs(t, err, inventory.ErrBatchNotFound)
+}
+
+// blockingBatchStorage は最初のバッチの実行開始を release が閉じられるまで止めるストレージ
+type blockingBatchStorage struct {
+	inventory.Storage
+	started chan struct{}
+	release chan struct{}
+	blocked bool
+}
+
+func (s *blockingBatchStorage) SaveBatchOperation(ctx context.Context, batch *inventory.BatchOperation) error {
+	if batch.Status == inventory.BatchStatusRunning && !s.blocked {
+		s.blocked = true
+		close(s.started)
+		<-s.release
+	}
+	return s.Storage.SaveBatchOperation(ctx, batch)
+}
+
+// TestBatchQueue_SubmitAndCancel は非同期バッチの実行・進捗・取り消しのテスト
+func TestBatchQueue_SubmitAndCancel(t *testing.T) {
+	store := &blockingBatchStorage{Storage: newTestMemoryStorage(t), started: make(chan struct{}), release: make(chan struct{})}
+	manager := inventory.NewManager(store, nil, zap.NewNop(), nil)
+	queue := inventory.NewBatchQueue(manager, inventory.BatchQueueConfig{Workers: 1, QueueSize: 10, ProgressInterval: 1})
+	ctx := context.Background()
+
+	// ワーカーが1つのため、最初のバッチの実行中は後続のバッチが待機する
+	first, err := queue.Submit(ctx, []inventory.InventoryOperation{
+		{Type: inventory.OperationTypeAdd, ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 10, Reference: "ASYNC-001"},
+		{Type: inventory.OperationTypeRemove, ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 4, Reference: "ASYNC-001"},
+	}, false)
+	require.NoError(t, err)
+	assert.Equal(t, inventory.BatchStatusPending, first.Status)
+	<-store.started
+
+	second, err := queue.Submit(ctx, []inventory.InventoryOperation{
+		{Type: inventory.OperationTypeAdd, ItemID: "TEST-ITEM", LocationID: "LOC-B", Quantity: 5, Reference: "ASYNC-002"},
+		{Type: inventory.OperationTypeAdd, ItemID: "TEST-ITEM", LocationID: "LOC-B", Quantity: 5, Reference: "ASYNC-002"},
+	}, false)
+	require.NoError(t, err)
+
+	status, err := manager.GetBatchStatus(ctx, second.ID)
+	require.NoError(t, err)
+	assert.Equal(t, inventory.BatchStatusPending, status.Status)
+	assert.Equal(t, 0, status.Progress)
+
+	// 実行前のバッチの取り消しは全操作を cancelled として記録する
+	cancelled, err := queue.Cancel(ctx, second.ID)
+	require.NoError(t, err)
+	assert.Equal(t, inventory.BatchStatusCancelled, cancelled.Status)
+	assert.Equal(t, 2, cancelled.CancelledCount)
+	assert.Equal(t, 100, cancelled.Progress)
+	for _, result := range cancelled.Results {
+		assert.Equal(t, inventory.OperationResultCancelled, result.Status)
+	}
+
+	close(store.release)
+	require.Eventually(t, func() bool {
+		status, err := manager.GetBatchStatus(ctx, first.ID)
+		return err == nil && status.Status == inventory.BatchStatusCompleted
+	}, time.Second, 10*time.Millisecond)
+
+	status, err = manager.GetBatchStatus(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 100, status.Progress)
+	assert.Equal(t, 2, status.SuccessCount)
+	assert.NotNil(t, status.CompletedAt)
+
+	stock, err := store.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), stock.Quantity)
+	_, err = store.GetStock(ctx, "TEST-ITEM", "LOC-B")
+	assert.ErrorIs(t, err, inventory.ErrStockNotFound)
+
+	// 完了済みのバッチは取り消せない
+	_, err = queue.Cancel(ctx, first.ID)
+	assert.ErrorIs(t, err, inventory.ErrBatchNotCancellable)
+	_, err = queue.Cancel(ctx, "UNKNOWN")
+	assert.ErrorIs(t, err, inventory.ErrBatchNotFound)
+
+	require.NoError(t, queue.Close())
+	_, err = queue.Submit(ctx, nil, false)
+	assert.ErrorIs(t, err, inventory.ErrBatchQueueClosed)
+}
+
+// TestManager_DryRun はドライランで検証のみ行い、何も保存しないことのテスト
+func TestManager_DryRun(t *testing.T) {
+	store := newTestMemoryStorage(t)
+	publisher := &recordingPublisher{}
+	manager := inventory.NewManager(store, publisher, zap.NewNop(), nil)
+	ctx := context.Background()
+
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 20, "INIT"))
+	publisher.changes = nil
+
+	// 単一の操作は適用した場合の在庫の変化を返す
+	toLocation := "LOC-B"
+	result, err := manager.DryRunOperation(ctx, inventory.InventoryOperation{
+		Type: inventory.OperationTypeTransfer, ItemID: "TEST-ITEM", LocationID: "LOC-A", ToLocationID: &toLocation, Quantity: 15, Reference: "DRY-001",
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	require.Len(t, result.Changes, 2)
+	assert.Equal(t, inventory.StockChange{ItemID: "TEST-ITEM", LocationID: "LOC-A", OldQuantity: 20, NewQuantity: 5, Available: 5, LowStock: true}, result.Changes[0])
+	assert.Equal(t, inventory.StockChange{ItemID: "TEST-ITEM", LocationID: "LOC-B", OldQuantity: 0, NewQuantity: 15, Available: 15}, result.Changes[1])
+
+	// 失敗する操作は実際の操作と同じエラーを返す
+	_, err = manager.DryRunOperation(ctx, inventory.InventoryOperation{Type: inventory.OperationTypeRemove, ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 50, Reference: "DRY-002"})
+	assert.ErrorIs(t, err, inventory.ErrInsufficientStock)
+	_, err = manager.DryRunOperation(ctx, inventory.InventoryOperation{Type: inventory.OperationTypeAdd, ItemID: "UNKNOWN", LocationID: "LOC-A", Quantity: 1, Reference: "DRY-003"})
+	assert.ErrorIs(t, err, inventory.ErrItemNotFound)
+
+	// バッチは先行する操作を適用した状態に対して検証する
+	operations := []inventory.InventoryOperation{
+		{Type: inventory.OperationTypeRemove, ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 15, Reference: "DRY-004"},
+		{Type: inventory.OperationTypeRemove, ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 10, Reference: "DRY-004"},
+		{Type: inventory.OperationTypeAdjust, ItemID: "TEST-ITEM", LocationID: "LOC-B", Quantity: 8, Reference: "DRY-004"},
+	}
+	result, err = manager.DryRunBatch(ctx, operations, false)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, inventory.OperationResultSucceeded, result.Results[0].Status)
+	assert.Equal(t, inventory.OperationResultFailed, result.Results[1].Status)
+	assert.Equal(t, inventory.OperationResultSucceeded, result.Results[2].Status)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, 1, result.Errors[0].OperationIndex)
+	require.Len(t, result.Changes, 2)
+	assert.Equal(t, 0, result.Changes[0].OperationIndex)
+	assert.Equal(t, int64(5), result.Changes[0].NewQuantity)
+	assert.Equal(t, 2, result.Changes[1].OperationIndex)
+	assert.Equal(t, int64(8), result.Changes[1].NewQuantity)
+
+	// アトミックなバッチは最初の失敗で中断し、何も適用しない
+	result, err = manager.DryRunBatch(ctx, operations, true)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, inventory.OperationResultRolledBack, result.Results[0].Status)
+	assert.Equal(t, inventory.OperationResultFailed, result.Results[1].Status)
+	assert.Equal(t, inventory.OperationResultRolledBack, result.Results[2].Status)
+	assert.Empty(t, result.Changes)
+
+	// 在庫・トランザクション・アラートは変更されず、イベントも発行されない
+	stock, err := store.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(20), stock.Quantity)
+	_, err = store.GetStock(ctx, "TEST-ITEM", "LOC-B")
+	assert.ErrorIs(t, err, inventory.ErrStockNotFound)
+	history, err := store.GetTransactionHistory(ctx, "TEST-ITEM", 10)
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+	alerts, err := store.GetActiveAlerts(ctx, "LOC-A")
+	require.NoError(t, err)
+	assert.Empty(t, alerts)
+	assert.Empty(t, publisher.changes)
+	assert.Empty(t, publisher.transfers)
+}
+
+func TestManager_Reservations(t *testing.T) {
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), nil)
+	ctx := context.Background()
+
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 100, "INIT"))
+
+	// 予約を作成すると予約数量に加算される
+	reservation := &inventory.Reservation{ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 30, Reference: "ORDER-001"}
+	require.NoError(t, manager.CreateReservation(ctx, reservation))
+	assert.NotEmpty(t, reservation.ID)
+	assert.Equal(t, inventory.ReservationStatusActive, reservation.Status)
+
+	stock, err := manager.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(30), stock.Reserved)
+	assert.Equal(t, int64(70), stock.Available)
+
+	err = manager.CreateReservation(ctx, &inventory.Reservation{ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 80, Reference: "ORDER-002"})
+	assert.ErrorIs(t, err, inventory.ErrInsufficientStock)
+
+	// IDで解除すると予約数量から減算され、二重の解除は拒否される
+	released, err := manager.ReleaseReservationByID(ctx, reservation.ID)
+	require.NoError(t, err)
+	assert.Equal(t, inventory.ReservationStatusReleased, released.Status)
+	assert.NotNil(t, released.ReleasedAt)
+	_, err = manager.ReleaseReservationByID(ctx, reservation.ID)
+	assert.ErrorIs(t, err, inventory.ErrReservationNotActive)
+	_, err = manager.ReleaseReservationByID(ctx, "UNKNOWN")
+	assert.ErrorIs(t, err, inventory.ErrReservationNotFound)
+
+	stock, err = manager.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), stock.Reserved)
+
+	// 有効期限を過ぎた予約は期限切れになり、確保した在庫が解放される
+	expiresAt := time.Now().Add(20 * time.Millisecond)
+	expiring := &inventory.Reservation{ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 10, Reference: "ORDER-003", ExpiresAt: &expiresAt}
+	require.NoError(t, manager.CreateReservation(ctx, expiring))
+	expired, err := manager.ExpireReservations(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, expired)
+
+	time.Sleep(30 * time.Millisecond)
+	expired, err = manager.ExpireReservations(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, expired)
+
+	got, err := manager.GetReservation(ctx, expiring.ID)
+	require.NoError(t, err)
+	assert.Equal(t, inventory.ReservationStatusExpired, got.Status)
+	stock, err = manager.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), stock.Reserved)
+
+	// 数量指定の解除は参照番号が一致する予約から解除し、記録と予約数量を一致させる
+	require.NoError(t, manager.Reserve(ctx, "TEST-ITEM", "LOC-A", 20, "ORDER-004"))
+	require.NoError(t, manager.Reserve(ctx, "TEST-ITEM", "LOC-A", 15, "ORDER-005"))
+	require.NoError(t, manager.ReleaseReservation(ctx, "TEST-ITEM", "LOC-A", 25, "ORDER-005"))
+
+	active, err := manager.ListReservations(ctx, inventory.ReservationFilter{ItemID: "TEST-ITEM", Status: inventory.ReservationStatusActive, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+	assert.Equal(t, "ORDER-004", active[0].Reference)
+	assert.Equal(t, int64(10), active[0].Quantity)
+
+	stock, err = manager.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), stock.Reserved)
+
+	all, err := manager.ListReservations(ctx, inventory.ReservationFilter{ItemID: "TEST-ITEM", Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, all, 4)
+	_, err = manager.ListReservations(ctx, inventory.ReservationFilter{Status: "unknown", Limit: 10})
+	var validationErr *inventory.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestManager_ReservationsByReference(t *testing.T) {
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), nil)
+	ctx := context.Background()
+
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 50, "INIT"))
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-B", 50, "INIT"))
+	require.NoError(t, manager.Reserve(ctx, "TEST-ITEM", "LOC-A", 10, "ORDER-001"))
+	require.NoError(t, manager.Reserve(ctx, "TEST-ITEM", "LOC-A", 5, "ORDER-001"))
+	require.NoError(t, manager.Reserve(ctx, "TEST-ITEM", "LOC-B", 7, "ORDER-001"))
+	require.NoError(t, manager.Reserve(ctx, "TEST-ITEM", "LOC-A", 3, "ORDER-002"))
+
+	reservations, err := manager.GetReservationsByReference(ctx, "ORDER-001")
+	require.NoError(t, err)
+	assert.Len(t, reservations, 3)
+
+	// 注文のキャンセルではその注文で確保した数量だけを解放する
+	released, err := manager.ReleaseReservationsByReference(ctx, "ORDER-001")
+	require.NoError(t, err)
+	require.Len(t, released, 3)
+	for _, reservation := range released {
+		assert.Equal(t, inventory.ReservationStatusReleased, reservation.Status)
+	}
+
+	stock, err := manager.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), stock.Reserved)
+	stock, err = manager.GetStock(ctx, "TEST-ITEM", "LOC-B")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), stock.Reserved)
+
+	// 再実行しても解除済みの予約は解放しない
+	released, err = manager.ReleaseReservationsByReference(ctx, "ORDER-001")
+	require.NoError(t, err)
+	assert.Empty(t, released)
+
+	_, err = manager.ReleaseReservationsByReference(ctx, "")
+	var validationErr *inventory.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestManager_FulfillReservation(t *testing.T) {
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), nil)
+	ctx := context.Background()
+
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 50, "INIT"))
+	reservation := &inventory.Reservation{ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 10, Reference: "ORDER-001"}
+	require.NoError(t, manager.CreateReservation(ctx, reservation))
+
+	tx, err := manager.FulfillReservation(ctx, reservation.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, inventory.TransactionTypeOutbound, tx.Type)
+	assert.Equal(t, int64(10), tx.Quantity)
+	assert.Equal(t, "ORDER-001", tx.Reference)
+	assert.Equal(t, reservation.ID, tx.Metadata[inventory.MetadataReservationID])
+
+	// 在庫数量と予約数量を同時に減らすため、利用可能数は変わらない
+	stock, err := manager.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(40), stock.Quantity)
+	assert.Equal(t, int64(0), stock.Reserved)
+	assert.Equal(t, int64(40), stock.Available)
+
+	fulfilled, err := manager.GetReservation(ctx, reservation.ID)
+	require.NoError(t, err)
+	assert.Equal(t, inventory.ReservationStatusFulfilled, fulfilled.Status)
+	assert.NotNil(t, fulfilled.ReleasedAt)
+
+	// 出庫トランザクションは一件だけ記録する
+	history, err := manager.GetHistory(ctx, "TEST-ITEM", 10)
+	require.NoError(t, err)
+	var outbound int
+	for _, h := range history {
+		if h.Type == inventory.TransactionTypeOutbound {
+			outbound++
+		}
+	}
+	assert.Equal(t, 1, outbound)
+
+	_, err = manager.FulfillReservation(ctx, reservation.ID, "SHIP-001")
+	assert.ErrorIs(t, err, inventory.ErrReservationNotActive)
+
+	_, err = manager.FulfillReservation(ctx, "missing", "")
+	assert.ErrorIs(t, err, inventory.ErrReservationNotFound)
+}
+
+func TestManager_ReservationTransactions(t *testing.T) {
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), nil)
+	ctx := context.Background()
+
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 50, "INIT"))
+	require.NoError(t, manager.Reserve(ctx, "TEST-ITEM", "LOC-A", 10, "ORDER-001"))
+	require.NoError(t, manager.ReleaseReservation(ctx, "TEST-ITEM", "LOC-A", 4, "ORDER-001"))
+	reservation := &inventory.Reservation{ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 5, Reference: "ORDER-002"}
+	require.NoError(t, manager.CreateReservation(ctx, reservation))
+	_, err := manager.ReleaseReservationByID(ctx, reservation.ID)
+	require.NoError(t, err)
+
+	history, err := manager.GetHistory(ctx, "TEST-ITEM", 10)
+	require.NoError(t, err)
+	counts := make(map[inventory.TransactionType]int)
+	for _, tx := range history {
+		counts[tx.Type]++
+		switch tx.Type {
+		case inventory.TransactionTypeReserve:
+			require.NotNil(t, tx.FromLocation)
+			assert.Equal(t, "LOC-A", *tx.FromLocation)
+			assert.NotEmpty(t, tx.Metadata[inventory.MetadataReservationID])
+		case inventory.TransactionTypeRelease:
+			require.NotNil(t, tx.ToLocation)
+			assert.Equal(t, "LOC-A", *tx.ToLocation)
+		}
+	}
+	assert.Equal(t, 2, counts[inventory.TransactionTypeReserve])
+	assert.Equal(t, 2, counts[inventory.TransactionTypeRelease])
+
+	// 予約・予約解除のトランザクションは台帳の数量に影響しない
+	report, err := manager.CheckStockConsistency(ctx, false)
+	require.NoError(t, err)
+	assert.Empty(t, report.Divergences)
+}
+
+func TestManager_Backorders(t *testing.T) {
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{EnableBackorders: true})
+	ctx := context.Background()
+
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 5, "INIT"))
+
+	// 要求しない場合は従来どおり在庫不足を返す
+	err := manager.Remove(ctx, "TEST-ITEM", "LOC-A", 8, "ORDER-000")
+	assert.ErrorIs(t, err, inventory.ErrInsufficientStock)
+	var backordered *inventory.BackorderedError
+	assert.False(t, errors.As(err, &backordered))
+
+	backorderCtx := inventory.WithBackorder(ctx)
+	err = manager.Remove(backorderCtx, "TEST-ITEM", "LOC-A", 8, "ORDER-001")
+	require.ErrorAs(t, err, &backordered)
+	assert.ErrorIs(t, err, inventory.ErrInsufficientStock)
+	first := backordered.Backorder
+	assert.Equal(t, inventory.BackorderStatusPending, first.Status)
+	assert.Equal(t, int64(8), first.Quantity)
+
+	err = manager.Reserve(backorderCtx, "TEST-ITEM", "LOC-A", 20, "ORDER-002")
+	require.ErrorAs(t, err, &backordered)
+	second := backordered.Backorder
+	err = manager.Reserve(backorderCtx, "TEST-ITEM", "LOC-A", 6, "ORDER-003")
+	require.ErrorAs(t, err, &backordered)
+	third := backordered.Backorder
+
+	// 入荷で最も古いバックオーダーを予約に変換し、次に満たないものがあればそこで終了する
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 10, "PO-001"))
+
+	allocated, err := manager.GetBackorder(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, inventory.BackorderStatusAllocated, allocated.Status)
+	require.NotEmpty(t, allocated.ReservationID)
+	assert.NotNil(t, allocated.ClosedAt)
+
+	reservation, err := manager.GetReservation(ctx, allocated.ReservationID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), reservation.Quantity)
+	assert.Equal(t, "ORDER-001", reservation.Reference)
+
+	stock, err := manager.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(15), stock.Quantity)
+	assert.Equal(t, int64(8), stock.Reserved)
+
+	pending, err := manager.ListBackorders(ctx, inventory.BackorderFilter{Status: inventory.BackorderStatusPending, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, second.ID, pending[0].ID)
+	assert.Equal(t, third.ID, pending[1].ID)
+
+	// 取り消したバックオーダーは引当の対象外になり、再度取り消せない
+	cancelled, err := manager.CancelBackorder(ctx, second.ID)
+	require.NoError(t, err)
+	assert.Equal(t, inventory.BackorderStatusCancelled, cancelled.Status)
+	_, err = manager.CancelBackorder(ctx, second.ID)
+	assert.ErrorIs(t, err, inventory.ErrBackorderNotPending)
+
+	result, err := manager.AllocateBackorders(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, third.ID, result[0].ID)
+
+	_, err = manager.GetBackorder(ctx, "missing")
+	assert.ErrorIs(t, err, inventory.ErrBackorderNotFound)
+}
+
+func TestManager_BackorderOnEmptyLocation(t *testing.T) {
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{EnableBackorders: true})
+	ctx := inventory.WithBackorder(context.Background())
+
+	// 一度も入庫していないロケーションでも、在庫不足として予約・出庫のバックオーダーを作成する
+	var backordered *inventory.BackorderedError
+	err := manager.Reserve(ctx, "TEST-ITEM", "LOC-B", 3, "ORDER-001")
+	require.ErrorAs(t, err, &backordered)
+	assert.ErrorIs(t, err, inventory.ErrInsufficientStock)
+	reserve := backordered.Backorder
+	assert.Equal(t, inventory.BackorderStatusPending, reserve.Status)
+	assert.Equal(t, int64(3), reserve.Quantity)
+
+	err = manager.Remove(ctx, "TEST-ITEM", "LOC-B", 2, "ORDER-002")
+	require.ErrorAs(t, err, &backordered)
+	assert.NotEqual(t, reserve.ID, backordered.Backorder.ID)
+
+	// 入荷で予約に変換される
+	require.NoError(t, manager.Add(context.Background(), "TEST-ITEM", "LOC-B", 10, "PO-001"))
+	allocated, err := manager.GetBackorder(context.Background(), reserve.ID)
+	require.NoError(t, err)
+	assert.Equal(t, inventory.BackorderStatusAllocated, allocated.Status)
+}
+
+func TestManager_ReorderPoints(t *testing.T) {
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{LowStockThreshold: 10})
+	ctx := context.Background()
+
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 100, "INIT"))
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-B", 100, "INIT"))
+
+	err := manager.SetReorderPoint(ctx, &inventory.ReorderPoint{ItemID: "TEST-ITEM", LocationID: "LOC-A", ReorderPointQty: 50, MinQty: 20, MaxQty: 10})
+	var validationErr *inventory.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "max_qty", validationErr.Field)
+
+	err = manager.SetReorderPoint(ctx, &inventory.ReorderPoint{ItemID: "MISSING", LocationID: "LOC-A", ReorderPointQty: 50})
+	assert.ErrorIs(t, err, inventory.ErrItemNotFound)
+
+	require.NoError(t, manager.SetReorderPoint(ctx, &inventory.ReorderPoint{ItemID: "TEST-ITEM", LocationID: "LOC-A", ReorderPointQty: 50, MinQty: 20, MaxQty: 200}))
+
+	// 発注点を設定した在庫は発注点以下でアラートを作成する
+	require.NoError(t, manager.Remove(ctx, "TEST-ITEM", "LOC-A", 60, "ORDER-001"))
+	alerts, err := manager.GetAlerts(ctx, "LOC-A")
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, int64(50), alerts[0].Threshold)
+	assert.Equal(t, int64(40), alerts[0].CurrentQty)
+	assert.Contains(t, alerts[0].Message, "160")
+
+	// 設定していない在庫は全体の閾値で判定する
+	require.NoError(t, manager.Remove(ctx, "TEST-ITEM", "LOC-B", 60, "ORDER-002"))
+	alerts, err = manager.GetAlerts(ctx, "LOC-B")
+	require.NoError(t, err)
+	assert.Empty(t, alerts)
+
+	summary, err := manager.GetSummary(ctx)
+	require.NoError(t, err)
+	lowStock := make(map[string]int64)
+	for _, location := range summary.LowStock {
+		lowStock[location.LocationID] = location.Items
+	}
+	assert.Equal(t, int64(1), lowStock["LOC-A"])
+	assert.Equal(t, int64(0), lowStock["LOC-B"])
+
+	reorderPoints, err := manager.ListReorderPoints(ctx, inventory.ReorderPointFilter{ItemID: "TEST-ITEM", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, reorderPoints, 1)
+	assert.Equal(t, "LOC-A", reorderPoints[0].LocationID)
+
+	require.NoError(t, manager.DeleteReorderPoint(ctx, "TEST-ITEM", "LOC-A"))
+	_, err = manager.GetReorderPoint(ctx, "TEST-ITEM", "LOC-A")
+	assert.ErrorIs(t, err, inventory.ErrReorderPointNotFound)
+	assert.ErrorIs(t, manager.DeleteReorderPoint(ctx, "TEST-ITEM", "LOC-A"), inventory.ErrReorderPointNotFound)
+}
+
+// TestManager_LocationCapacity は容量超過時の扱いと過剰在庫アラートのテスト
+func TestManager_LocationCapacity(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	newStore := func(t *testing.T) *MemoryStorage {
+		store := newTestMemoryStorage(t)
+		require.NoError(t, store.CreateItem(ctx, &inventory.Item{ID: "OTHER-ITEM", Name: "別の商品", CreatedAt: now, UpdatedAt: now}))
+		require.NoError(t, store.UpdateLocation(ctx, &inventory.Location{ID: "LOC-B", Name: "ロケーションB", Capacity: 100, CreatedAt: now, UpdatedAt: now}))
+		return store
+	}
+
+	t.Run("enforce", func(t *testing.T) {
+		store := newStore(t)
+		manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{CapacityPolicy: inventory.CapacityPolicyEnforce})
+
+		require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-B", 60, "INIT"))
+		// 容量は商品をまたいだ合計在庫数で判定する
+		assert.ErrorIs(t, manager.Add(ctx, "OTHER-ITEM", "LOC-B", 50, "INIT"), inventory.ErrLocationCapacityExceeded)
+		require.NoError(t, manager.Add(ctx, "OTHER-ITEM", "LOC-B", 40, "INIT"))
+
+		require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 10, "INIT"))
+		assert.ErrorIs(t, manager.Transfer(ctx, "TEST-ITEM", "LOC-A", "LOC-B", 1, "MOVE-001"), inventory.ErrLocationCapacityExceeded)
+
+		// 拒否した移動は移動元も変更しない
+		stock, err := manager.GetStock(ctx, "TEST-ITEM", "LOC-A")
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), stock.Quantity)
+		total, err := store.GetTotalStockByLocation(ctx, "LOC-B")
+		require.NoError(t, err)
+		assert.Equal(t, int64(100), total)
+	})
+
+	t.Run("warn", func(t *testing.T) {
+		store := newStore(t)
+		manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{})
+
+		require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-B", 100, "INIT"))
+		alerts, err := manager.GetAlerts(ctx, "LOC-B")
+		require.NoError(t, err)
+		assert.Empty(t, alerts)
+
+		require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 10, "INIT"))
+		require.NoError(t, manager.Transfer(ctx, "TEST-ITEM", "LOC-A", "LOC-B", 5, "MOVE-001"))
+		alerts, err = manager.GetAlerts(ctx, "LOC-B")
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+		assert.Equal(t, inventory.AlertTypeOverStock, alerts[0].Type)
+		assert.Equal(t, int64(105), alerts[0].CurrentQty)
+		assert.Equal(t, int64(100), alerts[0].Threshold)
+	})
+
+	t.Run("ignore", func(t *testing.T) {
+		store := newStore(t)
+		manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{CapacityPolicy: inventory.CapacityPolicyIgnore})
+
+		require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-B", 150, "INIT"))
+		alerts, err := manager.GetAlerts(ctx, "LOC-B")
+		require.NoError(t, err)
+		assert.Empty(t, alerts)
+	})
+
+	t.Run("max_qty", func(t *testing.T) {
+		store := newStore(t)
+		manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{CapacityPolicy: inventory.CapacityPolicyIgnore})
+
+		require.NoError(t, manager.SetReorderPoint(ctx, &inventory.ReorderPoint{ItemID: "TEST-ITEM", LocationID: "LOC-A", ReorderPointQty: 5, MaxQty: 50}))
+		require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 50, "INIT"))
+		alerts, err := manager.GetAlerts(ctx, "LOC-A")
+		require.NoError(t, err)
+		assert.Empty(t, alerts)
+
+		require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 1, "INIT"))
+		alerts, err = manager.GetAlerts(ctx, "LOC-A")
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+		assert.Equal(t, inventory.AlertTypeOverStock, alerts[0].Type)
+		assert.Equal(t, int64(51), alerts[0].CurrentQty)
+		assert.Equal(t, int64(50), alerts[0].Threshold)
+	})
+}
+
+// TestManager_AlertRules はアラートルールの評価・重複防止・クールダウンのテスト
+func TestManager_AlertRules(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("stock_change", func(t *testing.T) {
+		store := newTestMemoryStorage(t)
+		manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{DisableLowStockCheck: true})
+
+		rule := &inventory.AlertRule{
+			Name:       "LOC-A 利用可能数",
+			Metric:     inventory.AlertMetricAvailable,
+			Comparator: inventory.AlertComparatorLT,
+			Threshold:  20,
+			LocationID: "LOC-A",
+			Severity:   inventory.AlertSeverityCritical,
+			Enabled:    true,
+		}
+		require.NoError(t, manager.CreateAlertRule(ctx, rule))
+		require.NotEmpty(t, rule.ID)
+
+		require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 50, "INIT"))
+		alerts, err := manager.GetAlerts(ctx, "LOC-A")
+		require.NoError(t, err)
+		assert.Empty(t, alerts)
+
+		// 予約で利用可能数が閾値を下回る
+		require.NoError(t, manager.Reserve(ctx, "TEST-ITEM", "LOC-A", 35, "ORDER-001"))
+		alerts, err = manager.GetAlerts(ctx, "LOC-A")
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+		assert.Equal(t, rule.ID, alerts[0].RuleID)
+		assert.Equal(t, inventory.AlertSeverityCritical, alerts[0].Severity)
+		assert.Equal(t, inventory.AlertTypeLowStock, alerts[0].Type)
+		assert.Equal(t, int64(15), alerts[0].CurrentQty)
+		assert.Equal(t, int64(20), alerts[0].Threshold)
+
+		// アクティブなアラートがある間は再作成しない
+		require.NoError(t, manager.Remove(ctx, "TEST-ITEM", "LOC-A", 5, "OUT-001"))
+		alerts, err = manager.GetAlerts(ctx, "LOC-A")
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+
+		// 対象範囲外のロケーションでは評価しない
+		require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-B", 1, "INIT"))
+		alerts, err = manager.GetAlerts(ctx, "LOC-B")
+		require.NoError(t, err)
+		assert.Empty(t, alerts)
+	})
+
+	t.Run("cooldown", func(t *testing.T) {
+		store := newTestMemoryStorage(t)
+		manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{DisableLowStockCheck: true})
+
+		cooling := &inventory.AlertRule{Name: "過剰在庫", Metric: inventory.AlertMetricQuantity, Comparator: inventory.AlertComparatorGTE, Threshold: 100, Severity: inventory.AlertSeverityWarning, CooldownSeconds: 3600, Enabled: true}
+		immediate := &inventory.AlertRule{Name: "在庫あり", Metric: inventory.AlertMetricQuantity, Comparator: inventory.AlertComparatorGT, Threshold: 0, Severity: inventory.AlertSeverityInfo, Enabled: true}
+		require.NoError(t, manager.CreateAlertRule(ctx, cooling))
+		require.NoError(t, manager.CreateAlertRule(ctx, immediate))
+
+		require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 100, "INIT"))
+		alerts, err := manager.GetAlerts(ctx, "LOC-A")
+		require.NoError(t, err)
+		require.Len(t, alerts, 2)
+		for _, alert := range alerts {
+			assert.Equal(t, inventory.AlertTypeOverStock, alert.Type)
+			require.NoError(t, manager.ResolveAlert(ctx, alert.ID))
+		}
+
+		// 解決後もクールダウン中のルールはアラートを作成しない
+		require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 1, "INIT"))
+		alerts, err = manager.GetAlerts(ctx, "LOC-A")
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+		assert.Equal(t, immediate.ID, alerts[0].RuleID)
+	})
+
+	t.Run("evaluate", func(t *testing.T) {
+		store := newTestMemoryStorage(t)
+		manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{DisableLowStockCheck: true})
+
+		require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 5, "INIT"))
+		require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-B", 50, "INIT"))
+
+		// ルールの作成前からの在庫は定期的な評価で検出する
+		rule := &inventory.AlertRule{Name: "低在庫", Metric: inventory.AlertMetricQuantity, Comparator: inventory.AlertComparatorLTE, Threshold: 10, ItemID: "TEST-ITEM", Severity: inventory.AlertSeverityWarning, Enabled: true}
+		require.NoError(t, manager.CreateAlertRule(ctx, rule))
+		created, err := manager.EvaluateAlertRules(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, created)
+
+		created, err = manager.EvaluateAlertRules(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, created)
+
+		// 無効にしたルールは評価しない
+		rule.Enabled = false
+		require.NoError(t, manager.UpdateAlertRule(ctx, rule))
+		require.NoError(t, manager.Remove(ctx, "TEST-ITEM", "LOC-B", 45, "OUT-001"))
+		alerts, err := manager.GetAlerts(ctx, "LOC-B")
+		require.NoError(t, err)
+		assert.Empty(t, alerts)
+
+		rules, err := manager.ListAlertRules(ctx, inventory.AlertRuleFilter{EnabledOnly: true, Limit: 10})
+		require.NoError(t, err)
+		assert.Empty(t, rules)
+		require.NoError(t, manager.DeleteAlertRule(ctx, rule.ID))
+		_, err = manager.GetAlertRule(ctx, rule.ID)
+		assert.ErrorIs(t, err, inventory.ErrAlertRuleNotFound)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		store := newTestMemoryStorage(t)
+		manager := inventory.NewManager(store, nil, zap.NewNop(), nil)
+
+		err := manager.CreateAlertRule(ctx, &inventory.AlertRule{Name: "不正", Metric: "unknown", Comparator: inventory.AlertComparatorLT, Severity: inventory.AlertSeverityInfo})
+		var validationErr *inventory.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		err = manager.CreateAlertRule(ctx, &inventory.AlertRule{Name: "存在しない商品", Metric: inventory.AlertMetricQuantity, Comparator: inventory.AlertComparatorLT, ItemID: "MISSING", Severity: inventory.AlertSeverityInfo})
+		assert.ErrorIs(t, err, inventory.ErrItemNotFound)
+		assert.ErrorIs(t, manager.UpdateAlertRule(ctx, &inventory.AlertRule{ID: "MISSING", Name: "更新", Metric: inventory.AlertMetricQuantity, Comparator: inventory.AlertComparatorLT, Severity: inventory.AlertSeverityInfo}), inventory.ErrAlertRuleNotFound)
+	})
+}
+
+func TestManager_AlertAcknowledgement(t *testing.T) {
+	ctx := context.Background()
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{LowStockThreshold: 10})
+
+	// 閾値以下は warning、欠品は critical
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 20, "INIT"))
+	require.NoError(t, manager.Remove(ctx, "TEST-ITEM", "LOC-A", 15, "OUT-001"))
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-B", 5, "INIT"))
+	require.NoError(t, manager.Remove(ctx, "TEST-ITEM", "LOC-B", 5, "OUT-002"))
+
+	alerts, err := manager.GetAlerts(ctx, "LOC-A")
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	warning := alerts[0]
+	assert.Equal(t, inventory.AlertSeverityWarning, warning.Severity)
+	assert.Nil(t, warning.AcknowledgedAt)
+
+	alerts, err = manager.GetAlerts(ctx, "LOC-B")
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	critical := alerts[0]
+	assert.Equal(t, inventory.AlertSeverityCritical, critical.Severity)
+
+	t.Run("acknowledge", func(t *testing.T) {
+		userCtx := context.WithValue(ctx, "user_id", "operator-1")
+		alert, err := manager.AcknowledgeAlert(userCtx, warning.ID, "補充を手配済み")
+		require.NoError(t, err)
+		require.NotNil(t, alert.AcknowledgedAt)
+		assert.Equal(t, "operator-1", alert.AcknowledgedBy)
+		assert.Equal(t, "補充を手配済み", alert.AcknowledgeNote)
+		assert.True(t, alert.IsActive, "確認してもアラートはアクティブのまま")
+
+		_, err = manager.AcknowledgeAlert(ctx, warning.ID, "")
+		assert.ErrorIs(t, err, inventory.ErrAlertAlreadyAcknowledged)
+	})
+
+	t.Run("resolve", func(t *testing.T) {
+		alert, err := manager.ResolveAlertWithNote(ctx, warning.ID, "入荷済み")
+		require.NoError(t, err)
+		assert.False(t, alert.IsActive)
+		require.NotNil(t, alert.ResolvedAt)
+		assert.Equal(t, "system", alert.ResolvedBy)
+		assert.Equal(t, "入荷済み", alert.ResolveNote)
+		assert.Equal(t, "operator-1", alert.AcknowledgedBy)
+
+		_, err = manager.AcknowledgeAlert(ctx, warning.ID, "")
+		assert.ErrorIs(t, err, inventory.ErrAlertNotActive)
+		_, err = manager.ResolveAlertWithNote(ctx, warning.ID, "")
+		assert.ErrorIs(t, err, inventory.ErrAlertNotActive)
+
+		// 確認していないアラートも解決できる
+		require.NoError(t, manager.ResolveAlert(ctx, critical.ID))
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		_, err := manager.AcknowledgeAlert(ctx, "NO-SUCH-ALERT", "")
+		assert.ErrorIs(t, err, inventory.ErrAlertNotFound)
+		assert.ErrorIs(t, manager.ResolveAlert(ctx, "NO-SUCH-ALERT"), inventory.ErrAlertNotFound)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		_, err := manager.AcknowledgeAlert(ctx, critical.ID, strings.Repeat("あ", 2001))
+		var validationErr *inventory.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+}
+
+func TestManager_ListAlerts(t *testing.T) {
+	ctx := context.Background()
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{})
+
+	base := time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC)
+	resolvedAt := base.Add(time.Hour)
+	for _, alert := range []inventory.StockAlert{
+		{ID: "ALERT-1", Type: inventory.AlertTypeLowStock, ItemID: "ITEM-1", LocationID: "LOC-A", Severity: inventory.AlertSeverityWarning, IsActive: true, CreatedAt: base},
+		{ID: "ALERT-2", Type: inventory.AlertTypeLowStock, ItemID: "ITEM-2", LocationID: "LOC-B", Severity: inventory.AlertSeverityCritical, IsActive: true, CreatedAt: base.AddDate(0, 0, 1)},
+		{ID: "ALERT-3", Type: inventory.AlertTypeOverStock, ItemID: "ITEM-1", LocationID: "LOC-B", Severity: inventory.AlertSeverityWarning, IsActive: false, CreatedAt: base.AddDate(0, 0, 2), ResolvedAt: &resolvedAt},
+		{ID: "ALERT-4", Type: inventory.AlertTypeExpiring, ItemID: "ITEM-3", LocationID: "LOC-C", Severity: inventory.AlertSeverityInfo, IsActive: true, CreatedAt: base.AddDate(0, 0, 3)},
+	} {
+		alert := alert
+		require.NoError(t, store.CreateAlert(ctx, &alert))
+	}
+	require.NoError(t, store.AcknowledgeAlert(ctx, "ALERT-2", "operator-1", ""))
+
+	active, resolved, acknowledged := true, false, true
+	tests := []struct {
+		name   string
+		filter inventory.AlertFilter
+		want   []string
+	}{
+		{"all", inventory.AlertFilter{Limit: 20}, []string{"ALERT-4", "ALERT-3", "ALERT-2", "ALERT-1"}},
+		{"page", inventory.AlertFilter{Offset: 1, Limit: 2}, []string{"ALERT-3", "ALERT-2"}},
+		{"type", inventory.AlertFilter{Type: inventory.AlertTypeLowStock, Limit: 20}, []string{"ALERT-2", "ALERT-1"}},
+		{"severity", inventory.AlertFilter{Severity: inventory.AlertSeverityWarning, Limit: 20}, []string{"ALERT-3", "ALERT-1"}},
+		{"active", inventory.AlertFilter{Active: &active, Limit: 20}, []string{"ALERT-4", "ALERT-2", "ALERT-1"}},
+		{"resolved", inventory.AlertFilter{Active: &resolved, Limit: 20}, []string{"ALERT-3"}},
+		{"acknowledged", inventory.AlertFilter{Acknowledged: &acknowledged, Limit: 20}, []string{"ALERT-2"}},
+		{"item", inventory.AlertFilter{ItemID: "ITEM-1", Limit: 20}, []string{"ALERT-3", "ALERT-1"}},
+		{"location", inventory.AlertFilter{LocationID: "LOC-B", Limit: 20}, []string{"ALERT-3", "ALERT-2"}},
+		{"date_range", inventory.AlertFilter{CreatedAfter: base.AddDate(0, 0, 1), CreatedBefore: base.AddDate(0, 0, 3), Limit: 20}, []string{"ALERT-3", "ALERT-2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts, err := manager.ListAlerts(ctx, tt.filter)
+			require.NoError(t, err)
+			ids := make([]string, len(alerts))
+			for i, alert := range alerts {
+				ids[i] = alert.ID
+			}
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+
+	t.Run("validation", func(t *testing.T) {
+		for _, filter := range []inventory.AlertFilter{
+			{Limit: 0},
+			{Type: "unknown", Limit: 20},
+			{Severity: "urgent", Limit: 20},
+			{CreatedAfter: base, CreatedBefore: base, Limit: 20},
+		} {
+			_, err := manager.ListAlerts(ctx, filter)
+			var validationErr *inventory.ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+		}
+	})
+}
+
+// TestManager_SweepLowStock は定期的な低在庫の検出とアラートのタイムアウトのテスト
+func TestManager_SweepLowStock(t *testing.T) {
+	ctx := context.Background()
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{LowStockThreshold: 10, AlertTimeout: time.Hour})
+
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 100, "INIT"))
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-B", 50, "INIT"))
+
+	// 在庫操作を伴わない発注点の引き上げは定期的な検出でのみアラートになる
+	require.NoError(t, manager.SetReorderPoint(ctx, &inventory.ReorderPoint{ItemID: "TEST-ITEM", LocationID: "LOC-A", ReorderPointQty: 150}))
+	alerts, err := manager.GetAlerts(ctx, "LOC-A")
+	require.NoError(t, err)
+	assert.Empty(t, alerts)
+
+	created, err := manager.SweepLowStock(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, created)
+	alerts, err = manager.GetAlerts(ctx, "LOC-A")
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, int64(150), alerts[0].Threshold)
+	alerts, err = manager.GetAlerts(ctx, "LOC-B")
+	require.NoError(t, err)
+	assert.Empty(t, alerts)
+
+	// アクティブなアラートのある在庫には重複して作成しない
+	created, err = manager.SweepLowStock(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, created)
+
+	// タイムアウトしたアラートのみ解決する
+	resolved, err := manager.ResolveTimedOutAlerts(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, resolved)
+
+	stale := inventory.StockAlert{ID: "ALERT-STALE", Type: inventory.AlertTypeOverStock, ItemID: "TEST-ITEM", LocationID: "LOC-B", Severity: inventory.AlertSeverityWarning, IsActive: true, CreatedAt: time.Now().Add(-2 * time.Hour)}
+	require.NoError(t, store.CreateAlert(ctx, &stale))
+	resolved, err = manager.ResolveTimedOutAlerts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, resolved)
+
+	alert, err := store.GetAlert(ctx, "ALERT-STALE")
+	require.NoError(t, err)
+	assert.False(t, alert.IsActive)
+	assert.NotEmpty(t, alert.ResolveNote)
+	alerts, err = manager.GetAlerts(ctx, "LOC-A")
+	require.NoError(t, err)
+	assert.Len(t, alerts, 1)
+}
+
+// TestManager_StockSnapshots は在庫スナップショットの作成と取得のテスト
+func TestManager_StockSnapshots(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "user_id", "scheduler")
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), nil)
+
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 100, "INIT"))
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-B", 30, "INIT"))
+	require.NoError(t, manager.Reserve(ctx, "TEST-ITEM", "LOC-A", 20, "ORDER-001"))
+
+	first, err := manager.TakeStockSnapshot(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "scheduler", first.TakenBy)
+	assert.Equal(t, 2, first.StockCount)
+	assert.Equal(t, int64(130), first.TotalQuantity)
+	assert.Equal(t, int64(20), first.TotalReserved)
+
+	// 作成後の在庫の変更はスナップショットに影響しない
+	require.NoError(t, manager.Remove(ctx, "TEST-ITEM", "LOC-B", 10, "ORDER-002"))
+	second, err := manager.TakeStockSnapshot(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(120), second.TotalQuantity)
+
+	lines, err := manager.GetStockSnapshotLines(ctx, first.ID, "")
+	require.NoError(t, err)
+	require.Len(t, lines, 2)
+	assert.Equal(t, "LOC-A", lines[0].LocationID)
+	assert.Equal(t, int64(100), lines[0].Quantity)
+	assert.Equal(t, int64(20), lines[0].Reserved)
+	assert.Equal(t, int64(30), lines[1].Quantity)
+
+	lines, err = manager.GetStockSnapshotLines(ctx, second.ID, "LOC-B")
+	require.NoError(t, err)
+	require.Len(t, lines, 1)
+	assert.Equal(t, int64(20), lines[0].Quantity)
+
+	_, err = manager.GetStockSnapshotLines(ctx, "MISSING", "")
+	assert.ErrorIs(t, err, inventory.ErrSnapshotNotFound)
+
+	snapshots, err := manager.ListStockSnapshots(ctx, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, second.ID, snapshots[0].ID)
+	assert.Equal(t, first.ID, snapshots[1].ID)
+
+	snapshots, err = manager.ListStockSnapshots(ctx, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, first.ID, snapshots[0].ID)
+}
+
+// TestManager_Stocktake は棚卸の作成・実数の記録・差異の確定・調整のテスト
+func TestManager_Stocktake(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "user_id", "counter")
+	store := newTestMemoryStorage(t)
+	now := time.Now()
+	require.NoError(t, store.CreateItem(ctx, &inventory.Item{ID: "ITEM-2", Name: "商品2", CreatedAt: now, UpdatedAt: now}))
+	require.NoError(t, store.CreateItem(ctx, &inventory.Item{ID: "ITEM-3", Name: "商品3", CreatedAt: now, UpdatedAt: now}))
+	manager := inventory.NewManager(store, nil, zap.NewNop(), nil)
+
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 100, "INIT"))
+	require.NoError(t, manager.Add(ctx, "ITEM-2", "LOC-A", 50, "INIT"))
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-B", 10, "INIT"))
+
+	_, err := manager.GetStocktake(ctx, "MISSING")
+	assert.ErrorIs(t, err, inventory.ErrStocktakeNotFound)
+	assert.ErrorIs(t, manager.CreateStocktake(ctx, &inventory.Stocktake{LocationID: "MISSING"}), inventory.ErrLocationNotFound)
+	var validationErr *inventory.ValidationError
+	assert.ErrorAs(t, manager.CreateStocktake(ctx, &inventory.Stocktake{LocationID: "LOC-A", ABCClass: "D"}), &validationErr)
+
+	stocktake := &inventory.Stocktake{LocationID: "LOC-A"}
+	require.NoError(t, manager.CreateStocktake(ctx, stocktake))
+	assert.Equal(t, inventory.StocktakeStatusOpen, stocktake.Status)
+	assert.Equal(t, stocktake.ID, stocktake.Reference)
+	assert.Equal(t, "counter", stocktake.CreatedBy)
+	require.Len(t, stocktake.Lines, 2)
+
+	// 記録時点の在庫数量を帳簿在庫数量とし、集計表にない商品は行を追加する
+	require.NoError(t, manager.Remove(ctx, "ITEM-2", "LOC-A", 5, "ORDER-001"))
+	counted, err := manager.RecordStocktakeCounts(ctx, stocktake.ID, []inventory.StocktakeCount{
+		{ItemID: "TEST-ITEM", CountedQty: 97},
+		{ItemID: "ITEM-2", CountedQty: 45},
+		{ItemID: "ITEM-3", CountedQty: 4},
+	})
+	require.NoError(t, err)
+	require.Len(t, counted.Lines, 3)
+	lines := make(map[string]inventory.StocktakeLine)
+	for _, line := range counted.Lines {
+		require.True(t, line.Counted())
+		lines[line.ItemID] = line
+	}
+	assert.Equal(t, int64(-3), lines["TEST-ITEM"].Variance)
+	assert.Equal(t, int64(45), lines["ITEM-2"].ExpectedQty)
+	assert.Zero(t, lines["ITEM-2"].Variance)
+	assert.Equal(t, int64(4), lines["ITEM-3"].Variance)
+	assert.Equal(t, "counter", lines["ITEM-3"].CountedBy)
+
+	_, err = manager.RecordStocktakeCounts(ctx, stocktake.ID, []inventory.StocktakeCount{{ItemID: "MISSING", CountedQty: 1}})
+	assert.ErrorIs(t, err, inventory.ErrItemNotFound)
+	_, err = manager.ApplyStocktake(ctx, stocktake.ID, nil)
+	assert.ErrorIs(t, err, inventory.ErrStocktakeStatus, "確定前は調整できない")
+
+	// 差異のある行ごとに棚卸差異アラートを作成する
+	submitted, err := manager.SubmitStocktake(ctx, stocktake.ID)
+	require.NoError(t, err)
+	assert.Equal(t, inventory.StocktakeStatusSubmitted, submitted.Status)
+	require.NotNil(t, submitted.SubmittedAt)
+	active := true
+	alerts, err := manager.ListAlerts(ctx, inventory.AlertFilter{Type: inventory.AlertTypeDiscrepancy, Active: &active, Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, alerts, 2)
+
+	_, err = manager.RecordStocktakeCounts(ctx, stocktake.ID, []inventory.StocktakeCount{{ItemID: "TEST-ITEM", CountedQty: 1}})
+	assert.ErrorIs(t, err, inventory.ErrStocktakeStatus, "確定後は実数を記録できない")
+	_, err = manager.ApplyStocktake(ctx, stocktake.ID, []string{"ITEM-9"})
+	assert.ErrorAs(t, err, &validationErr)
+
+	// 確定後の入出庫に影響されないよう、在庫数量には差異を加算する
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 10, "RECEIPT-001"))
+	applied, err := manager.ApplyStocktake(ctx, stocktake.ID, []string{"TEST-ITEM"})
+	require.NoError(t, err)
+	assert.Equal(t, inventory.StocktakeStatusApplied, applied.Status)
+	for _, line := range applied.Lines {
+		assert.Equal(t, line.ItemID == "TEST-ITEM", line.Approved, line.ItemID)
+	}
+
+	stock, err := manager.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(107), stock.Quantity)
+	_, err = manager.GetStock(ctx, "ITEM-3", "LOC-A")
+	assert.ErrorIs(t, err, inventory.ErrStockNotFound, "承認していない差異は反映しない")
+
+	history, err := manager.GetHistory(ctx, "TEST-ITEM", 1)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, inventory.TransactionTypeStocktake, history[0].Type)
+	assert.Equal(t, int64(-3), history[0].Quantity)
+	assert.Equal(t, stocktake.ID, history[0].Metadata["stocktake_id"])
+
+	// 反映した商品の棚卸差異アラートのみ解決する
+	alerts, err = manager.ListAlerts(ctx, inventory.AlertFilter{Type: inventory.AlertTypeDiscrepancy, Active: &active, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "ITEM-3", alerts[0].ItemID)
+
+	_, err = manager.CancelStocktake(ctx, stocktake.ID)
+	assert.ErrorIs(t, err, inventory.ErrStocktakeStatus)
+
+	// ABCクラスを指定した棚卸・取消
+	classB := &inventory.Stocktake{LocationID: "LOC-B", ABCClass: "B"}
+	require.NoError(t, manager.CreateStocktake(ctx, classB))
+	assert.Empty(t, classB.Lines)
+	cancelled, err := manager.CancelStocktake(ctx, classB.ID)
+	require.NoError(t, err)
+	assert.Equal(t, inventory.StocktakeStatusCancelled, cancelled.Status)
+
+	stocktakes, err := manager.ListStocktakes(ctx, inventory.StocktakeFilter{LocationID: "LOC-A", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, stocktakes, 1)
+	assert.Empty(t, stocktakes[0].Lines)
+	stocktakes, err = manager.ListStocktakes(ctx, inventory.StocktakeFilter{Status: inventory.StocktakeStatusCancelled, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, stocktakes, 1)
+	assert.Equal(t, classB.ID, stocktakes[0].ID)
+}
+
+// TestManager_AdjustmentApproval は理由コード付きの在庫調整と調整額による承認のテスト
+func TestManager_AdjustmentApproval(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "user_id", "clerk")
+	store := newTestMemoryStorage(t)
+	now := time.Now()
+	require.NoError(t, store.CreateItem(ctx, &inventory.Item{ID: "COSTLY-ITEM", Name: "高額商品", UnitCost: 1000, CreatedAt: now, UpdatedAt: now}))
+	manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{
+		DefaultLocation:             "LOC-A",
+		AdjustmentApprovalThreshold: 5000,
+	})
+	require.NoError(t, manager.Add(ctx, "COSTLY-ITEM", "LOC-A", 20, "INIT"))
+
+	var validationErr *inventory.ValidationError
+	assert.ErrorAs(t, manager.RequestAdjustment(ctx, &inventory.Adjustment{ItemID: "COSTLY-ITEM", LocationID: "LOC-A", NewQuantity: 19}), &validationErr, "理由コードは必須")
+	assert.ErrorAs(t, manager.RequestAdjustment(ctx, &inventory.Adjustment{ItemID: "COSTLY-ITEM", LocationID: "LOC-A", NewQuantity: 19, Reason: inventory.AdjustmentReasonOther}), &validationErr, "other はメモが必要")
+
+	// 閾値以下の調整は即時に反映し、トランザクションに理由コードを記録する
+	small := &inventory.Adjustment{ItemID: "COSTLY-ITEM", LocationID: "LOC-A", NewQuantity: 17, Reason: inventory.AdjustmentReasonDamage}
+	require.NoError(t, manager.RequestAdjustment(ctx, small))
+	assert.Equal(t, inventory.AdjustmentStatusApplied, small.Status)
+	assert.Equal(t, int64(-3), small.Delta)
+	assert.Equal(t, 3000.0, small.Value)
+	require.NotEmpty(t, small.TransactionID)
+	stock, err := manager.GetStock(ctx, "COSTLY-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(17), stock.Quantity)
+	history, err := manager.SearchHistoryByMetadata(ctx, inventory.MetadataAdjustmentID, small.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, string(inventory.AdjustmentReasonDamage), history[0].Metadata[inventory.MetadataReasonCode])
+
+	// 閾値を超える調整は在庫を変更せずに承認待ちにする
+	large := &inventory.Adjustment{ItemID: "COSTLY-ITEM", LocationID: "LOC-A", NewQuantity: 10, Reason: inventory.AdjustmentReasonShrinkage}
+	require.NoError(t, manager.RequestAdjustment(ctx, large))
+	assert.Equal(t, inventory.AdjustmentStatusPending, large.Status)
+	assert.Equal(t, 7000.0, large.Value)
+	assert.Empty(t, large.TransactionID)
+	stock, err = manager.GetStock(ctx, "COSTLY-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(17), stock.Quantity)
+
+	pending, err := manager.ListAdjustments(ctx, inventory.AdjustmentFilter{Status: inventory.AdjustmentStatusPending, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, large.ID, pending[0].ID)
+
+	// 承認時は申請後の在庫の変動に関わらず申請時の調整数量を反映する
+	require.NoError(t, manager.Add(ctx, "COSTLY-ITEM", "LOC-A", 1, "INBOUND"))
+	approverCtx := context.WithValue(context.Background(), "user_id", "manager")
+	approved, err := manager.ApproveAdjustment(approverCtx, large.ID, "棚卸で確認済み")
+	require.NoError(t, err)
+	assert.Equal(t, inventory.AdjustmentStatusApplied, approved.Status)
+	assert.Equal(t, int64(11), approved.NewQuantity)
+	assert.Equal(t, "manager", approved.DecidedBy)
+	assert.Equal(t, "棚卸で確認済み", approved.DecisionNote)
+	require.NotNil(t, approved.DecidedAt)
+	require.NotEmpty(t, approved.TransactionID)
+	stock, err = manager.GetStock(ctx, "COSTLY-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), stock.Quantity)
+
+	_, err = manager.ApproveAdjustment(approverCtx, large.ID, "")
+	assert.ErrorIs(t, err, inventory.ErrAdjustmentNotPending, "二重に承認できない")
+	_, err = manager.RejectAdjustment(approverCtx, "MISSING", "")
+	assert.ErrorIs(t, err, inventory.ErrAdjustmentNotFound)
+
+	// 却下した調整は在庫を変更しない
+	rejectedReq := &inventory.Adjustment{ItemID: "COSTLY-ITEM", LocationID: "LOC-A", NewQuantity: 30, Reason: inventory.AdjustmentReasonFound}
+	require.NoError(t, manager.RequestAdjustment(ctx, rejectedReq))
+	require.Equal(t, inventory.AdjustmentStatusPending, rejectedReq.Status)
+	rejected, err := manager.RejectAdjustment(approverCtx, rejectedReq.ID, "再確認してください")
+	require.NoError(t, err)
+	assert.Equal(t, inventory.AdjustmentStatusRejected, rejected.Status)
+	stock, err = manager.GetStock(ctx, "COSTLY-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), stock.Quantity)
+
+	adjustments, err := manager.ListAdjustments(ctx, inventory.AdjustmentFilter{ItemID: "COSTLY-ITEM", Reason: inventory.AdjustmentReasonFound, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, adjustments, 1)
+	assert.Equal(t, rejectedReq.ID, adjustments[0].ID)
+	_, err = manager.ListAdjustments(ctx, inventory.AdjustmentFilter{Status: "unknown"})
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+// TestManager_ReverseTransaction はトランザクションの取消のテスト
+func TestManager_ReverseTransaction(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "user_id", "operator")
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{DefaultLocation: "LOC-A"})
+
+	transactionByReference := func(reference string) inventory.Transaction {
+		t.Helper()
+		history, err := manager.GetHistoryByReference(ctx, reference)
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		return history[0]
+	}
+	quantity := func(locationID string) int64 {
+		t.Helper()
+		stock, err := manager.GetStock(ctx, "TEST-ITEM", locationID)
+		require.NoError(t, err)
+		return stock.Quantity
+	}
+
+	// 誤った入庫を同じロケーションからの出庫で取り消す
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 100, "PO-001"))
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 30, "PO-WRONG"))
+	inbound := transactionByReference("PO-WRONG")
+
+	var validationErr *inventory.ValidationError
+	_, err := manager.ReverseTransaction(ctx, inbound.ID, "")
+	assert.ErrorAs(t, err, &validationErr, "理由は必須")
+	_, err = manager.ReverseTransaction(ctx, "MISSING", "誤入庫")
+	assert.ErrorIs(t, err, inventory.ErrTransactionNotFound)
+
+	reversal, err := manager.ReverseTransaction(ctx, inbound.ID, "誤入庫")
+	require.NoError(t, err)
+	assert.Equal(t, inventory.TransactionTypeOutbound, reversal.Type)
+	require.NotNil(t, reversal.FromLocation)
+	assert.Equal(t, "LOC-A", *reversal.FromLocation)
+	assert.Equal(t, int64(30), reversal.Quantity)
+	assert.Equal(t, "PO-WRONG", reversal.Reference)
+	assert.Equal(t, "operator", reversal.CreatedBy)
+	assert.Equal(t, int64(100), quantity("LOC-A"))
+
+	// 取消は元のトランザクションIDで照会できる
+	linked, err := manager.SearchHistoryByMetadata(ctx, inventory.MetadataReversalOf, inbound.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, linked, 1)
+	assert.Equal(t, reversal.ID, linked[0].ID)
+	assert.Equal(t, "誤入庫", linked[0].Metadata[inventory.MetadataReversalReason])
+
+	_, err = manager.ReverseTransaction(ctx, inbound.ID, "誤入庫")
+	assert.ErrorIs(t, err, inventory.ErrTransactionAlreadyReversed, "二重に取り消せない")
+	_, err = manager.ReverseTransaction(ctx, reversal.ID, "取消の取消")
+	assert.ErrorIs(t, err, inventory.ErrTransactionNotReversible, "取消自体は取り消せない")
+
+	// 移動は移動元と移動先を入れ替えて取り消す
+	require.NoError(t, manager.Transfer(ctx, "TEST-ITEM", "LOC-A", "LOC-B", 40, "TR-001"))
+	transfer := transactionByReference("TR-001")
+	reversal, err = manager.ReverseTransaction(ctx, transfer.ID, "移動先の誤り")
+	require.NoError(t, err)
+	assert.Equal(t, inventory.TransactionTypeTransfer, reversal.Type)
+	assert.Equal(t, "LOC-B", *reversal.FromLocation)
+	assert.Equal(t, "LOC-A", *reversal.ToLocation)
+	assert.Equal(t, int64(100), quantity("LOC-A"))
+	assert.Equal(t, int64(0), quantity("LOC-B"))
+
+	// 出庫は同じロケーションへの入庫で取り消す
+	require.NoError(t, manager.Remove(ctx, "TEST-ITEM", "LOC-A", 10, "SO-001"))
+	outbound := transactionByReference("SO-001")
+	_, err = manager.ReverseTransaction(ctx, outbound.ID, "出荷取りやめ")
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), quantity("LOC-A"))
+
+	// 出庫済みの在庫の入庫は取り消せない
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-B", 5, "PO-002"))
+	require.NoError(t, manager.Remove(ctx, "TEST-ITEM", "LOC-B", 5, "SO-002"))
+	_, err = manager.ReverseTransaction(ctx, transactionByReference("PO-002").ID, "誤入庫")
+	assert.ErrorIs(t, err, inventory.ErrInsufficientStock)
+
+	// 予約は取り消せない
+	require.NoError(t, manager.Reserve(ctx, "TEST-ITEM", "LOC-A", 5, "RSV-001"))
+	_, err = manager.ReverseTransaction(ctx, transactionByReference("RSV-001").ID, "予約の誤り")
+	assert.ErrorIs(t, err, inventory.ErrTransactionNotReversible)
+}
+
+// TestManager_InspectionWorkflow は入荷検品（隔離・品質検査）のテスト
+func TestManager_InspectionWorkflow(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "user_id", "receiver")
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{DefaultLocation: "LOC-A"})
+
+	var validationErr *inventory.ValidationError
+	assert.ErrorAs(t, manager.ReceiveForInspection(ctx, &inventory.Inspection{ItemID: "TEST-ITEM", LocationID: "LOC-A"}), &validationErr, "数量は正の値")
+	assert.ErrorIs(t, manager.ReceiveForInspection(ctx, &inventory.Inspection{ItemID: "MISSING", LocationID: "LOC-A", Quantity: 1}), inventory.ErrItemNotFound)
+
+	// 検品待ちの入荷は在庫に含めない
+	passing := &inventory.Inspection{ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 40, Reference: "ASN-001"}
+	require.NoError(t, manager.ReceiveForInspection(ctx, passing))
+	assert.Equal(t, inventory.InspectionStatusQuarantined, passing.Status)
+	assert.Equal(t, "receiver", passing.ReceivedBy)
+	_, err := manager.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	assert.ErrorIs(t, err, inventory.ErrStockNotFound)
+
+	failing := &inventory.Inspection{ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 5}
+	require.NoError(t, manager.ReceiveForInspection(ctx, failing))
+	assert.Equal(t, failing.ID, failing.Reference, "参照番号の省略時は検品ID")
+
+	quarantined, err := manager.ListInspections(ctx, inventory.InspectionFilter{Status: inventory.InspectionStatusQuarantined, Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, quarantined, 2)
+
+	// 合格した入荷は入庫トランザクションで在庫に加算する
+	inspectorCtx := context.WithValue(context.Background(), "user_id", "inspector")
+	passed, err := manager.PassInspection(inspectorCtx, passing.ID, "外観検査OK")
+	require.NoError(t, err)
+	assert.Equal(t, inventory.InspectionStatusPassed, passed.Status)
+	assert.Equal(t, "inspector", passed.InspectedBy)
+	require.NotNil(t, passed.InspectedAt)
+	require.NotEmpty(t, passed.TransactionID)
+	stock, err := manager.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(40), stock.Quantity)
+	tx, err := manager.GetTransaction(ctx, passed.TransactionID)
+	require.NoError(t, err)
+	assert.Equal(t, inventory.TransactionTypeInbound, tx.Type)
+	assert.Equal(t, passing.ID, tx.Metadata[inventory.MetadataInspectionID])
+
+	_, err = manager.PassInspection(inspectorCtx, passing.ID, "")
+	assert.ErrorIs(t, err, inventory.ErrInspectionNotQuarantined, "二重に入庫しない")
+
+	// 不合格の入荷は理由コードを記録して廃棄し、在庫は変更しない
+	_, err = manager.FailInspection(inspectorCtx, failing.ID, "", "")
+	assert.ErrorAs(t, err, &validationErr, "理由コードは必須")
+	_, err = manager.FailInspection(inspectorCtx, failing.ID, inventory.AdjustmentReasonOther, "")
+	assert.ErrorAs(t, err, &validationErr, "other はメモが必要")
+	failed, err := manager.FailInspection(inspectorCtx, failing.ID, inventory.AdjustmentReasonDamage, "外箱破損")
+	require.NoError(t, err)
+	assert.Equal(t, inventory.InspectionStatusFailed, failed.Status)
+	assert.Equal(t, inventory.AdjustmentReasonDamage, failed.Reason)
+	assert.Empty(t, failed.TransactionID)
+	stock, err = manager.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(40), stock.Quantity)
+
+	_, err = manager.PassInspection(inspectorCtx, failing.ID, "")
+	assert.ErrorIs(t, err, inventory.ErrInspectionNotQuarantined)
+	_, err = manager.GetInspection(ctx, "MISSING")
+	assert.ErrorIs(t, err, inventory.ErrInspectionNotFound)
+
+	quarantined, err = manager.ListInspections(ctx, inventory.InspectionFilter{Status: inventory.InspectionStatusQuarantined, Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, quarantined)
+	_, err = manager.ListInspections(ctx, inventory.InspectionFilter{Status: "unknown"})
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+// TestManager_Allocation は複数ロケーションからの引当のテスト
+func TestManager_Allocation(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "user_id", "allocator")
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{DefaultLocation: "LOC-A"})
+	now := time.Now()
+
+	coordinate := func(v float64) *float64 { return &v }
+	for _, location := range []*inventory.Location{
+		{ID: "WH-TOKYO", Name: "東京倉庫", Priority: 1, Latitude: coordinate(35.68), Longitude: coordinate(139.76), IsActive: true},
+		{ID: "WH-OSAKA", Name: "大阪倉庫", Priority: 2, Latitude: coordinate(34.69), Longitude: coordinate(135.50), IsActive: true},
+		{ID: "WH-SAPPORO", Name: "札幌倉庫", Priority: 3, Latitude: coordinate(43.06), Longitude: coordinate(141.35), IsActive: true},
+		{ID: "WH-CLOSED", Name: "閉鎖倉庫", Priority: 0},
+	} {
+		location.CreatedAt, location.UpdatedAt = now, now
+		require.NoError(t, store.CreateLocation(ctx, location))
+	}
+	for locationID, quantity := range map[string]int64{"WH-TOKYO": 30, "WH-OSAKA": 50, "WH-SAPPORO": 100, "WH-CLOSED": 500} {
+		require.NoError(t, store.CreateStock(ctx, &inventory.Stock{ItemID: "TEST-ITEM", LocationID: locationID, Quantity: quantity, Available: quantity, Version: 1, UpdatedAt: now}))
+	}
+
+	lines := func(allocation *inventory.Allocation) map[string]int64 {
+		result := make(map[string]int64)
+		for _, line := range allocation.Lines {
+			result[line.LocationID] = line.Quantity
+		}
+		return result
+	}
+
+	// 優先順位の高いロケーションから順に割り当て、非アクティブなロケーションは使わない
+	plan, err := manager.PlanAllocation(ctx, inventory.AllocationRequest{ItemID: "TEST-ITEM", Quantity: 60})
+	require.NoError(t, err)
+	assert.Equal(t, inventory.AllocationStrategyPriority, plan.Strategy)
+	assert.Equal(t, map[string]int64{"WH-TOKYO": 30, "WH-OSAKA": 30}, lines(plan))
+	assert.Equal(t, "WH-TOKYO", plan.Lines[0].LocationID)
+	assert.Equal(t, int64(60), plan.Allocated)
+	assert.Zero(t, plan.Shortfall)
+
+	// 配送先（京都）から近い順
+	kyoto := &inventory.GeoPoint{Latitude: 35.01, Longitude: 135.77}
+	plan, err = manager.PlanAllocation(ctx, inventory.AllocationRequest{ItemID: "TEST-ITEM", Quantity: 60, Strategy: inventory.AllocationStrategyProximity, Destination: kyoto})
+	require.NoError(t, err)
+	assert.Equal(t, "WH-OSAKA", plan.Lines[0].LocationID)
+	assert.Equal(t, map[string]int64{"WH-OSAKA": 50, "WH-TOKYO": 10}, lines(plan))
+	require.NotNil(t, plan.Lines[0].DistanceKm)
+	assert.InDelta(t, 45, *plan.Lines[0].DistanceKm, 10)
+
+	// 利用可能数の多い順
+	plan, err = manager.PlanAllocation(ctx, inventory.AllocationRequest{ItemID: "TEST-ITEM", Quantity: 60, Strategy: inventory.AllocationStrategyStockLevel})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"WH-SAPPORO": 60}, lines(plan))
+
+	// 1か所で全数を確保できる場合は分割しない
+	plan, err = manager.PlanAllocation(ctx, inventory.AllocationRequest{ItemID: "TEST-ITEM", Quantity: 40, PreferSingleLocation: true})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"WH-OSAKA": 40}, lines(plan))
+
+	// ロケーション数の上限と部分引当
+	_, err = manager.PlanAllocation(ctx, inventory.AllocationRequest{ItemID: "TEST-ITEM", Quantity: 60, MaxLocations: 1})
+	assert.ErrorIs(t, err, inventory.ErrInsufficientStock)
+	plan, err = manager.PlanAllocation(ctx, inventory.AllocationRequest{ItemID: "TEST-ITEM", Quantity: 60, MaxLocations: 1, AllowPartial: true})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"WH-TOKYO": 30}, lines(plan))
+	assert.Equal(t, int64(30), plan.Shortfall)
+
+	// 候補のロケーションの指定
+	plan, err = manager.PlanAllocation(ctx, inventory.AllocationRequest{ItemID: "TEST-ITEM", Quantity: 60, LocationIDs: []string{"WH-SAPPORO", "WH-CLOSED"}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"WH-SAPPORO": 60}, lines(plan))
+
+	_, err = manager.PlanAllocation(ctx, inventory.AllocationRequest{ItemID: "TEST-ITEM", Quantity: 1000})
+	assert.ErrorIs(t, err, inventory.ErrInsufficientStock)
+	_, err = manager.PlanAllocation(ctx, inventory.AllocationRequest{ItemID: "MISSING", Quantity: 1})
+	assert.ErrorIs(t, err, inventory.ErrItemNotFound)
+	var validationErr *inventory.ValidationError
+	_, err = manager.PlanAllocation(ctx, inventory.AllocationRequest{ItemID: "TEST-ITEM", Quantity: 1, Strategy: inventory.AllocationStrategyProximity})
+	assert.ErrorAs(t, err, &validationErr, "proximity は配送先が必要")
+
+	// 引当はロケーションごとに予約を作成する
+	allocation, err := manager.Allocate(ctx, inventory.AllocationRequest{ItemID: "TEST-ITEM", Quantity: 60, Reference: "SO-001"})
+	require.NoError(t, err)
+	require.Len(t, allocation.Lines, 2)
+	for _, line := range allocation.Lines {
+		require.NotEmpty(t, line.ReservationID)
+		reservation, err := manager.GetReservation(ctx, line.ReservationID)
+		require.NoError(t, err)
+		assert.Equal(t, line.LocationID, reservation.LocationID)
+		assert.Equal(t, line.Quantity, reservation.Quantity)
+		assert.Equal(t, "SO-001", reservation.Reference)
+		assert.Equal(t, "allocator", reservation.CreatedBy)
+		stock, err := manager.GetStock(ctx, "TEST-ITEM", line.LocationID)
+		require.NoError(t, err)
+		assert.Equal(t, line.Quantity, stock.Reserved)
+	}
+
+	// 予約済みの数量は次の引当の対象にならない
+	plan, err = manager.PlanAllocation(ctx, inventory.AllocationRequest{ItemID: "TEST-ITEM", Quantity: 60})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"WH-OSAKA": 20, "WH-SAPPORO": 40}, lines(plan))
+}
+
+// TestManager_RemoveWithLotPicking はロットを消費する在庫削除のテスト
+func TestManager_RemoveWithLotPicking(t *testing.T) {
+	ctx := context.Background()
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{DefaultLocation: "LOC-A"})
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 100, "PO-001"))
+
+	now := time.Now()
+	expiry := func(d time.Duration) *time.Time {
+		date := now.Add(d)
+		return &date
+	}
+	for _, lot := range []*inventory.Lot{
+		{ID: "lot-1", Number: "L1", Quantity: 10, ExpiryDate: expiry(10 * 24 * time.Hour), CreatedAt: now.Add(-3 * time.Hour)},
+		{ID: "lot-2", Number: "L2", Quantity: 10, ExpiryDate: expiry(2 * 24 * time.Hour), CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "lot-3", Number: "L3", Quantity: 10, CreatedAt: now.Add(-time.Hour)},
+		{ID: "lot-x", Number: "LX", Quantity: 50, ExpiryDate: expiry(-24 * time.Hour), CreatedAt: now.Add(-4 * time.Hour)},
+	} {
+		lot.ItemID = "TEST-ITEM"
+		require.NoError(t, store.CreateLot(ctx, lot))
+	}
+	lastTransaction := func(reference string) inventory.Transaction {
+		t.Helper()
+		txs, err := manager.GetHistoryByReference(ctx, reference)
+		require.NoError(t, err)
+		require.Len(t, txs, 1)
+		return txs[0]
+	}
+	lotQuantity := func(lotID string) int64 {
+		t.Helper()
+		lot, err := manager.GetLot(ctx, lotID)
+		require.NoError(t, err)
+		return lot.Quantity
+	}
+
+	// 既定（fefo）は有効期限の早い順に消費し、期限切れのロットは消費しない
+	require.NoError(t, manager.Remove(inventory.WithLotPicking(ctx, ""), "TEST-ITEM", "LOC-A", 15, "SO-001"))
+	tx := lastTransaction("SO-001")
+	assert.Equal(t, "L2:10,L1:5", tx.Metadata[inventory.MetadataLotPicks])
+	assert.Equal(t, string(inventory.LotPickingFEFO), tx.Metadata[inventory.MetadataLotStrategy])
+	assert.Nil(t, tx.LotNumber, "複数のロットを消費した場合はロット番号を記録しない")
+	assert.Equal(t, int64(0), lotQuantity("lot-2"))
+	assert.Equal(t, int64(5), lotQuantity("lot-1"))
+	assert.Equal(t, int64(50), lotQuantity("lot-x"))
+
+	// fifo は作成日時の古い順
+	require.NoError(t, manager.Remove(inventory.WithLotPicking(ctx, inventory.LotPickingFIFO), "TEST-ITEM", "LOC-A", 6, "SO-002"))
+	assert.Equal(t, "L1:5,L3:1", lastTransaction("SO-002").Metadata[inventory.MetadataLotPicks])
+
+	// lifo は作成日時の新しい順。1つのロットのみ消費した場合はロット番号を記録する
+	require.NoError(t, manager.Remove(inventory.WithLotPicking(ctx, inventory.LotPickingLIFO), "TEST-ITEM", "LOC-A", 2, "SO-003"))
+	tx = lastTransaction("SO-003")
+	require.NotNil(t, tx.LotNumber)
+	assert.Equal(t, "L3", *tx.LotNumber)
+	assert.Equal(t, int64(7), lotQuantity("lot-3"))
+
+	// 有効期限内のロットが不足する場合は在庫もロットも変更しない
+	err := manager.Remove(inventory.WithLotPicking(ctx, ""), "TEST-ITEM", "LOC-A", 8, "SO-004")
+	assert.ErrorIs(t, err, inventory.ErrInsufficientLotStock)
+	stock, err := manager.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(77), stock.Quantity)
+	assert.Equal(t, int64(7), lotQuantity("lot-3"))
+
+	var validationErr *inventory.ValidationError
+	assert.ErrorAs(t, manager.Remove(inventory.WithLotPicking(ctx, "random"), "TEST-ITEM", "LOC-A", 1, "SO-005"), &validationErr)
+
+	// ロットを指定しない削除はロットを変更しない
+	require.NoError(t, manager.Remove(ctx, "TEST-ITEM", "LOC-A", 1, "SO-006"))
+	assert.Empty(t, lastTransaction("SO-006").Metadata[inventory.MetadataLotPicks])
+	assert.Equal(t, int64(7), lotQuantity("lot-3"))
+}
+
+// TestManager_LotStockPerLocation はロット番号を指定した在庫操作によるロケーション別のロット在庫のテスト
+func TestManager_LotStockPerLocation(t *testing.T) {
+	ctx := context.Background()
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{DefaultLocation: "LOC-A"})
+	withLot := func(number string) context.Context {
+		return inventory.WithLotNumber(ctx, number)
+	}
+	lotStock := func(lotID, locationID string) int64 {
+		t.Helper()
+		lotStock, err := manager.GetLotStock(ctx, lotID, locationID)
+		require.NoError(t, err)
+		return lotStock.Quantity
+	}
+
+	// 追加はロットがない場合に作成し、ロットの数量と追加先のロット在庫を増やす
+	require.NoError(t, manager.Add(withLot("L1"), "TEST-ITEM", "LOC-A", 30, "PO-001"))
+	require.NoError(t, manager.Add(withLot("L1"), "TEST-ITEM", "LOC-B", 20, "PO-002"))
+	require.NoError(t, manager.Add(withLot("L2"), "TEST-ITEM", "LOC-A", 5, "PO-003"))
+	lots, err := manager.GetLotsByItem(ctx, "TEST-ITEM")
+	require.NoError(t, err)
+	require.Len(t, lots, 2, "同じロット番号の追加はロットを作成しない")
+	l1, err := store.GetLotByNumber(ctx, "TEST-ITEM", "L1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), l1.Quantity)
+	assert.Equal(t, int64(30), lotStock(l1.ID, "LOC-A"))
+	assert.Equal(t, int64(20), lotStock(l1.ID, "LOC-B"))
+	txs, err := manager.GetHistoryByReference(ctx, "PO-001")
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+	require.NotNil(t, txs[0].LotNumber)
+	assert.Equal(t, "L1", *txs[0].LotNumber)
+
+	// 移動はロットの数量を変えずにロット在庫を移す
+	require.NoError(t, manager.Transfer(withLot("L1"), "TEST-ITEM", "LOC-A", "LOC-B", 10, "TR-001"))
+	assert.Equal(t, int64(20), lotStock(l1.ID, "LOC-A"))
+	assert.Equal(t, int64(30), lotStock(l1.ID, "LOC-B"))
+	l1, err = manager.GetLot(ctx, l1.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), l1.Quantity)
+
+	// 削除はロットの数量と削除元のロット在庫を減らす
+	require.NoError(t, manager.Remove(withLot("L1"), "TEST-ITEM", "LOC-B", 25, "SO-001"))
+	assert.Equal(t, int64(5), lotStock(l1.ID, "LOC-B"))
+	l1, err = manager.GetLot(ctx, l1.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(25), l1.Quantity)
+
+	// ロケーションのロット在庫が不足する場合は在庫もロットも変更しない
+	assert.ErrorIs(t, manager.Remove(withLot("L2"), "TEST-ITEM", "LOC-B", 1, "SO-002"), inventory.ErrInsufficientLotStock)
+	assert.ErrorIs(t, manager.Transfer(withLot("L2"), "TEST-ITEM", "LOC-A", "LOC-B", 6, "TR-002"), inventory.ErrInsufficientLotStock)
+	assert.ErrorIs(t, manager.Remove(withLot("L9"), "TEST-ITEM", "LOC-A", 1, "SO-003"), inventory.ErrLotNotFound)
+	stock, err := manager.GetStock(ctx, "TEST-ITEM", "LOC-B")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), stock.Quantity)
+	assert.Equal(t, int64(5), lotStock(l1.ID, "LOC-B"))
+
+	// ロットの消費順序との併用はできない
+	var validationErr *inventory.ValidationError
+	assert.ErrorAs(t, manager.Remove(inventory.WithLotPicking(withLot("L1"), ""), "TEST-ITEM", "LOC-A", 1, "SO-004"), &validationErr)
+
+	// ロット在庫のあるロケーションでのロットの消費は、そのロケーションのロット在庫のみから消費する
+	require.NoError(t, manager.Remove(inventory.WithLotPicking(ctx, inventory.LotPickingFIFO), "TEST-ITEM", "LOC-B", 5, "SO-005"))
+	assert.Equal(t, int64(0), lotStock(l1.ID, "LOC-B"))
+	assert.Equal(t, int64(20), lotStock(l1.ID, "LOC-A"))
+
+	// 照会
+	l2, err := store.GetLotByNumber(ctx, "TEST-ITEM", "L2")
+	require.NoError(t, err)
+	lotStocks, err := manager.ListLotStocks(ctx, inventory.LotStockFilter{ItemID: "TEST-ITEM", Limit: 20})
+	require.NoError(t, err)
+	require.Len(t, lotStocks, 2, "数量0のロット在庫は含めない")
+	assert.Equal(t, []string{"L1", "L2"}, []string{lotStocks[0].LotNumber, lotStocks[1].LotNumber})
+	lotStocks, err = manager.ListLotStocks(ctx, inventory.LotStockFilter{LotID: l1.ID, IncludeEmpty: true, Limit: 20})
+	require.NoError(t, err)
+	assert.Len(t, lotStocks, 2)
+	lotStocks, err = manager.ListLotStocks(ctx, inventory.LotStockFilter{LocationID: "LOC-A", Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, lotStocks, 1)
+	assert.Equal(t, l2.ID, lotStocks[0].LotID)
+	assert.Equal(t, int64(0), lotStock(l2.ID, "LOC-B"), "ロット在庫がない場合は数量0")
+	_, err = manager.GetLotStock(ctx, "missing", "LOC-A")
+	assert.ErrorIs(t, err, inventory.ErrLotNotFound)
+}
+
+// TestManager_UnitsOfMeasure は単位の換算と小数の数量のテスト
+func TestManager_UnitsOfMeasure(t *testing.T) {
+	ctx := context.Background()
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{DefaultLocation: "LOC-A"})
+	var validationErr *inventory.ValidationError
+	quantity := func(itemID, locationID string) int64 {
+		t.Helper()
+		stock, err := manager.GetStock(ctx, itemID, locationID)
+		require.NoError(t, err)
+		return stock.Quantity
+	}
+
+	// 1箱 = 12個 の換算で箱単位の追加を個数に換算し、指定した単位と数量を記録する
+	require.NoError(t, manager.SetUnitConversion(ctx, &inventory.UnitConversion{ItemID: "TEST-ITEM", UoM: inventory.UoMBox, Factor: 12}))
+	require.NoError(t, manager.Add(inventory.WithUoM(ctx, inventory.UoMBox), "TEST-ITEM", "LOC-A", 2, "PO-001"))
+	assert.Equal(t, int64(24), quantity("TEST-ITEM", "LOC-A"))
+	txs, err := manager.GetHistoryByReference(ctx, "PO-001")
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+	assert.Equal(t, int64(24), txs[0].Quantity)
+	assert.Equal(t, inventory.UoMBox, txs[0].Metadata[inventory.MetadataUoM])
+	assert.Equal(t, "2", txs[0].Metadata[inventory.MetadataUoMQuantity])
+	require.NoError(t, manager.Transfer(inventory.WithUoM(ctx, inventory.UoMBox), "TEST-ITEM", "LOC-A", "LOC-B", 1, "TR-001"))
+	assert.Equal(t, int64(12), quantity("TEST-ITEM", "LOC-B"))
+	require.NoError(t, manager.Remove(inventory.WithUoM(ctx, inventory.UoMEach), "TEST-ITEM", "LOC-B", 5, "SO-001"))
+	assert.Equal(t, int64(7), quantity("TEST-ITEM", "LOC-B"))
+
+	// 小数は単位の桁数までで、換算後の数量は基本単位の桁数で表せる必要がある
+	parsed, err := manager.ParseQuantity(ctx, "TEST-ITEM", "", "3")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), parsed)
+	_, err = manager.ParseQuantity(ctx, "TEST-ITEM", inventory.UoMBox, "0.5")
+	assert.ErrorAs(t, err, &validationErr)
+	require.NoError(t, manager.SetUnitConversion(ctx, &inventory.UnitConversion{ItemID: "TEST-ITEM", UoM: inventory.UoMKilogram, Factor: 2}))
+	parsed, err = manager.ParseQuantity(ctx, "TEST-ITEM", inventory.UoMKilogram, "0.5")
+	require.NoError(t, err)
+	assert.Equal(t, int64(500), parsed)
+	converted, err := manager.ConvertQuantity(ctx, "TEST-ITEM", inventory.UoMKilogram, parsed)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), converted)
+	assert.ErrorAs(t, manager.Add(inventory.WithUoM(ctx, inventory.UoMKilogram), "TEST-ITEM", "LOC-A", 250, "PO-002"), &validationErr)
+
+	// 換算のない単位・基本単位の換算
+	assert.ErrorIs(t, manager.Add(inventory.WithUoM(ctx, inventory.UoMLiter), "TEST-ITEM", "LOC-A", 1, "PO-003"), inventory.ErrUnitConversionNotFound)
+	assert.ErrorAs(t, manager.SetUnitConversion(ctx, &inventory.UnitConversion{ItemID: "TEST-ITEM", UoM: inventory.UoMEach, Factor: 1}), &validationErr)
+	assert.Equal(t, int64(12), quantity("TEST-ITEM", "LOC-A"))
+
+	// kg を基本単位とする商品は 1.25kg を 1250 で保持する
+	flour := &inventory.Item{ID: "FLOUR", Name: "小麦粉", BaseUoM: inventory.UoMKilogram}
+	require.NoError(t, manager.CreateItem(ctx, flour))
+	require.NoError(t, manager.SetUnitConversion(ctx, &inventory.UnitConversion{ItemID: "FLOUR", UoM: inventory.UoMBox, Factor: 0.5}))
+	require.NoError(t, manager.Add(inventory.WithUoM(ctx, inventory.UoMBox), "FLOUR", "LOC-A", 3, "PO-004"))
+	assert.Equal(t, int64(1500), quantity("FLOUR", "LOC-A"))
+	parsed, err = manager.ParseQuantity(ctx, "FLOUR", "", "1.25")
+	require.NoError(t, err)
+	require.NoError(t, manager.Remove(ctx, "FLOUR", "LOC-A", parsed, "SO-002"))
+	assert.Equal(t, int64(250), quantity("FLOUR", "LOC-A"))
+
+	// 在庫がある商品の基本単位は変更できない
+	flour.BaseUoM = inventory.UoMEach
+	assert.ErrorAs(t, manager.UpdateItem(ctx, flour), &validationErr)
+	assert.ErrorIs(t, manager.CreateItem(ctx, &inventory.Item{ID: "WATER", Name: "水", BaseUoM: "gallon"}), inventory.ErrUnitNotFound)
+
+	// 単位の作成と、使用中の単位の削除
+	require.NoError(t, manager.CreateUnit(ctx, &inventory.UnitOfMeasure{Code: "case", Name: "ケース"}))
+	assert.ErrorIs(t, manager.CreateUnit(ctx, &inventory.UnitOfMeasure{Code: "case", Name: "ケース"}), inventory.ErrDuplicateUnit)
+	assert.ErrorAs(t, manager.CreateUnit(ctx, &inventory.UnitOfMeasure{Code: "mg", Name: "ミリグラム", Decimals: 7}), &validationErr)
+	assert.ErrorIs(t, manager.DeleteUnit(ctx, inventory.UoMBox), inventory.ErrUnitInUse)
+	assert.ErrorIs(t, manager.DeleteUnit(ctx, inventory.UoMKilogram), inventory.ErrUnitInUse)
+	require.NoError(t, manager.DeleteUnit(ctx, "case"))
+	assert.ErrorIs(t, manager.DeleteUnitConversion(ctx, "TEST-ITEM", inventory.UoMLiter), inventory.ErrUnitConversionNotFound)
+	conversions, err := manager.ListUnitConversions(ctx, "TEST-ITEM")
+	require.NoError(t, err)
+	assert.Len(t, conversions, 2)
+}
+
+// TestManager_Kitting は部品表とキットの組立・分解のテスト
+func TestManager_Kitting(t *testing.T) {
+	ctx := context.Background()
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{DefaultLocation: "LOC-A"})
+	var validationErr *inventory.ValidationError
+	quantity := func(itemID string) int64 {
+		t.Helper()
+		stock, err := manager.GetStock(ctx, itemID, "LOC-A")
+		if errors.Is(err, inventory.ErrStockNotFound) {
+			return 0
+		}
+		require.NoError(t, err)
+		return stock.Quantity
+	}
+
+	require.NoError(t, manager.CreateItem(ctx, &inventory.Item{ID: "KIT", Name: "キット"}))
+	require.NoError(t, manager.CreateItem(ctx, &inventory.Item{ID: "BOLT", Name: "ボルト"}))
+
+	// 部品表の検証と登録（構成品は商品ID順に保存）
+	assert.ErrorAs(t, manager.SetBillOfMaterials(ctx, &inventory.BillOfMaterials{KitItemID: "KIT", Components: []inventory.BOMComponent{{ItemID: "KIT", Quantity: 1}}}), &validationErr)
+	assert.ErrorAs(t, manager.SetBillOfMaterials(ctx, &inventory.BillOfMaterials{KitItemID: "KIT", Components: []inventory.BOMComponent{{ItemID: "BOLT", Quantity: 1}, {ItemID: "BOLT", Quantity: 2}}}), &validationErr)
+	assert.ErrorIs(t, manager.SetBillOfMaterials(ctx, &inventory.BillOfMaterials{KitItemID: "KIT", Components: []inventory.BOMComponent{{ItemID: "MISSING", Quantity: 1}}}), inventory.ErrItemNotFound)
+	_, err := manager.Assemble(ctx, "KIT", "LOC-A", 1, "WO-000")
+	assert.ErrorIs(t, err, inventory.ErrBOMNotFound)
+	require.NoError(t, manager.SetBillOfMaterials(ctx, &inventory.BillOfMaterials{KitItemID: "KIT", Components: []inventory.BOMComponent{
+		{ItemID: "TEST-ITEM", Quantity: 1},
+		{ItemID: "BOLT", Quantity: 4},
+	}}))
+	bom, err := manager.GetBillOfMaterials(ctx, "KIT")
+	require.NoError(t, err)
+	require.Len(t, bom.Components, 2)
+	assert.Equal(t, "BOLT", bom.Components[0].ItemID)
+
+	// 構成品が一つでも不足する場合は在庫を変更しない
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 10, "PO-001"))
+	require.NoError(t, manager.Add(ctx, "BOLT", "LOC-A", 30, "PO-002"))
+	_, err = manager.Assemble(ctx, "KIT", "LOC-A", 8, "WO-001")
+	assert.ErrorIs(t, err, inventory.ErrInsufficientStock)
+	assert.Equal(t, int64(10), quantity("TEST-ITEM"))
+	assert.Equal(t, int64(30), quantity("BOLT"))
+	assert.Equal(t, int64(0), quantity("KIT"))
+
+	// 組立は構成品を消費してキットを作成し、商品ごとに assembly のトランザクションを記録する
+	assembled, err := manager.Assemble(ctx, "KIT", "LOC-A", 5, "WO-002")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), quantity("TEST-ITEM"))
+	assert.Equal(t, int64(10), quantity("BOLT"))
+	assert.Equal(t, int64(5), quantity("KIT"))
+	assert.Equal(t, []inventory.BOMComponent{{ItemID: "BOLT", Quantity: 20}, {ItemID: "TEST-ITEM", Quantity: 5}}, assembled.Components)
+	require.Len(t, assembled.Transactions, 3)
+
+	saved, err := manager.GetAssembly(ctx, assembled.ID)
+	require.NoError(t, err)
+	require.Len(t, saved.Transactions, 3)
+	deltas := make(map[string]int64)
+	for _, tx := range saved.Transactions {
+		assert.Equal(t, inventory.TransactionTypeAssembly, tx.Type)
+		assert.Equal(t, "WO-002", tx.Reference)
+		assert.Equal(t, string(inventory.AssemblyTypeAssemble), tx.Metadata[inventory.MetadataAssemblyType])
+		deltas[tx.ItemID] = tx.Quantity
+	}
+	assert.Equal(t, map[string]int64{"BOLT": -20, "KIT": 5, "TEST-ITEM": -5}, deltas)
+	_, err = manager.ReverseTransaction(ctx, saved.Transactions[0].ID, "誤組立")
+	assert.ErrorIs(t, err, inventory.ErrTransactionNotReversible)
+
+	// 分解はキットを減らして構成品を在庫に戻す
+	disassembled, err := manager.Disassemble(ctx, "KIT", "LOC-A", 2, "WO-003")
+	require.NoError(t, err)
+	assert.Equal(t, inventory.AssemblyTypeDisassemble, disassembled.Type)
+	assert.Equal(t, int64(7), quantity("TEST-ITEM"))
+	assert.Equal(t, int64(18), quantity("BOLT"))
+	assert.Equal(t, int64(3), quantity("KIT"))
+	_, err = manager.Disassemble(ctx, "KIT", "LOC-A", 4, "WO-004")
+	assert.ErrorIs(t, err, inventory.ErrInsufficientStock)
+
+	assemblies, err := manager.ListAssemblies(ctx, inventory.AssemblyFilter{KitItemID: "KIT", Limit: 20})
+	require.NoError(t, err)
+	require.Len(t, assemblies, 2)
+	assert.Equal(t, disassembled.ID, assemblies[0].ID)
+	_, err = manager.GetAssembly(ctx, "MISSING")
+	assert.ErrorIs(t, err, inventory.ErrAssemblyNotFound)
+
+	// 台帳の assembly のトランザクションから在庫数量を再計算できる
+	report, err := manager.CheckStockConsistency(ctx, false)
+	require.NoError(t, err)
+	assert.Empty(t, report.Divergences)
+
+	// 構成品として使用中の商品は削除できず、キットを削除すると部品表も削除する
+	assert.ErrorIs(t, store.DeleteItem(ctx, "BOLT"), inventory.ErrItemInUse)
+	require.NoError(t, store.DeleteItem(ctx, "KIT"))
+	_, err = manager.GetBillOfMaterials(ctx, "KIT")
+	assert.ErrorIs(t, err, inventory.ErrBOMNotFound)
+	require.NoError(t, store.DeleteItem(ctx, "BOLT"))
+}
+
+// TestManager_Suppliers は仕入先と仕入条件のテスト
+func TestManager_Suppliers(t *testing.T) {
+	ctx := context.Background()
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{DefaultLocation: "LOC-A"})
+	var validationErr *inventory.ValidationError
+
+	// 仕入先の作成・更新
+	require.NoError(t, manager.CreateSupplier(ctx, &inventory.Supplier{ID: "SUP-A", Name: "仕入先A", Email: "a@example.com", IsActive: true}))
+	require.NoError(t, manager.CreateSupplier(ctx, &inventory.Supplier{ID: "SUP-B", Name: "仕入先B", IsActive: true}))
+	assert.ErrorIs(t, manager.CreateSupplier(ctx, &inventory.Supplier{ID: "SUP-A", Name: "仕入先A"}), inventory.ErrDuplicateSupplier)
+	assert.ErrorAs(t, manager.CreateSupplier(ctx, &inventory.Supplier{ID: "SUP-C", Name: "仕入先C", Email: "invalid"}), &validationErr)
+	require.NoError(t, manager.UpdateSupplier(ctx, &inventory.Supplier{ID: "SUP-B", Name: "仕入先B（改）"}))
+	supplier, err := manager.GetSupplier(ctx, "SUP-B")
+	require.NoError(t, err)
+	assert.Equal(t, "仕入先B（改）", supplier.Name)
+	assert.False(t, supplier.IsActive)
+	assert.False(t, supplier.CreatedAt.IsZero())
+	assert.ErrorIs(t, manager.UpdateSupplier(ctx, &inventory.Supplier{ID: "SUP-X", Name: "なし"}), inventory.ErrSupplierNotFound)
+
+	// 仕入条件の設定と、優先仕入先は商品ごとに1件
+	require.NoError(t, manager.SetSupplierItem(ctx, &inventory.SupplierItem{SupplierID: "SUP-A", ItemID: "TEST-ITEM", SupplierSKU: "A-001", UnitCost: 120, LeadTimeDays: 7, MinOrderQuantity: 10, IsPreferred: true}))
+	require.NoError(t, manager.SetSupplierItem(ctx, &inventory.SupplierItem{SupplierID: "SUP-B", ItemID: "TEST-ITEM", UnitCost: 110, LeadTimeDays: 14, IsPreferred: true}))
+	supplierItem, err := manager.GetSupplierItem(ctx, "SUP-A", "TEST-ITEM")
+	require.NoError(t, err)
+	assert.Equal(t, "A-001", supplierItem.SupplierSKU)
+	assert.False(t, supplierItem.IsPreferred)
+	assert.ErrorIs(t, manager.SetSupplierItem(ctx, &inventory.SupplierItem{SupplierID: "SUP-X", ItemID: "TEST-ITEM"}), inventory.ErrSupplierNotFound)
+	assert.ErrorIs(t, manager.SetSupplierItem(ctx, &inventory.SupplierItem{SupplierID: "SUP-A", ItemID: "NO-ITEM"}), inventory.ErrItemNotFound)
+	assert.ErrorAs(t, manager.SetSupplierItem(ctx, &inventory.SupplierItem{SupplierID: "SUP-A", ItemID: "TEST-ITEM", LeadTimeDays: 400}), &validationErr)
+
+	// 商品の仕入先一覧・仕入先の取扱商品一覧
+	itemSuppliers, err := manager.ListSupplierItems(ctx, inventory.SupplierItemFilter{ItemID: "TEST-ITEM", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, itemSuppliers, 2)
+	assert.Equal(t, "SUP-A", itemSuppliers[0].SupplierID)
+	assert.True(t, itemSuppliers[1].IsPreferred)
+	_, err = manager.ListSupplierItems(ctx, inventory.SupplierItemFilter{SupplierID: "SUP-X", Limit: 10})
+	assert.ErrorIs(t, err, inventory.ErrSupplierNotFound)
+
+	// 仕入条件・仕入先の削除
+	require.NoError(t, manager.DeleteSupplierItem(ctx, "SUP-B", "TEST-ITEM"))
+	assert.ErrorIs(t, manager.DeleteSupplierItem(ctx, "SUP-B", "TEST-ITEM"), inventory.ErrSupplierItemNotFound)
+	require.NoError(t, manager.DeleteSupplier(ctx, "SUP-A"))
+	_, err = manager.GetSupplierItem(ctx, "SUP-A", "TEST-ITEM")
+	assert.ErrorIs(t, err, inventory.ErrSupplierItemNotFound)
+	suppliers, err := manager.ListSuppliers(ctx, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, suppliers, 1)
+	assert.Equal(t, "SUP-B", suppliers[0].ID)
+}
+
+func TestManager_PurchaseOrders(t *testing.T) {
+	ctx := context.Background()
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{DefaultLocation: "LOC-A"})
+	var validationErr *inventory.ValidationError
+
+	require.NoError(t, manager.CreateItem(ctx, &inventory.Item{ID: "BOLT", Name: "ボルト", UnitCost: 5}))
+	require.NoError(t, manager.CreateSupplier(ctx, &inventory.Supplier{ID: "SUP-A", Name: "仕入先A", IsActive: true}))
+	require.NoError(t, manager.CreateSupplier(ctx, &inventory.Supplier{ID: "SUP-X", Name: "取引停止"}))
+	require.NoError(t, manager.SetSupplierItem(ctx, &inventory.SupplierItem{SupplierID: "SUP-A", ItemID: "TEST-ITEM", UnitCost: 120, MinOrderQuantity: 10}))
+
+	// 発注の作成（仕入条件の最小発注数量・仕入単価、無効な仕入先）
+	lines := func(quantity int64) []inventory.PurchaseOrderLine {
+		return []inventory.PurchaseOrderLine{{ItemID: "TEST-ITEM", Quantity: quantity}, {ItemID: "BOLT", Quantity: 50}}
+	}
+	assert.ErrorAs(t, manager.CreatePurchaseOrder(ctx, &inventory.PurchaseOrder{SupplierID: "SUP-A", LocationID: "LOC-A", Lines: lines(5)}), &validationErr)
+	assert.ErrorAs(t, manager.CreatePurchaseOrder(ctx, &inventory.PurchaseOrder{SupplierID: "SUP-X", LocationID: "LOC-A", Lines: lines(10)}), &validationErr)
+	assert.ErrorIs(t, manager.CreatePurchaseOrder(ctx, &inventory.PurchaseOrder{SupplierID: "SUP-Z", LocationID: "LOC-A", Lines: lines(10)}), inventory.ErrSupplierNotFound)
+	order := &inventory.PurchaseOrder{SupplierID: "SUP-A", LocationID: "LOC-A", Lines: lines(10)}
+	require.NoError(t, manager.CreatePurchaseOrder(ctx, order))
+	assert.Equal(t, inventory.PurchaseOrderStatusDraft, order.Status)
+	assert.Equal(t, order.ID, order.Reference)
+	order, err := manager.GetPurchaseOrder(ctx, order.ID)
+	require.NoError(t, err)
+	require.Len(t, order.Lines, 2)
+	assert.Equal(t, "BOLT", order.Lines[0].ItemID)
+	assert.Equal(t, 5.0, order.Lines[0].UnitCost)
+	assert.Equal(t, 120.0, order.Lines[1].UnitCost)
+
+	// 送付前は入荷できず、承認・送付は順序どおりにのみ行える
+	receipt := &inventory.GoodsReceipt{Lines: []inventory.GoodsReceiptLine{{ItemID: "TEST-ITEM", Quantity: 4}}}
+	_, err = manager.ReceivePurchaseOrder(ctx, order.ID, receipt)
+	assert.ErrorIs(t, err, inventory.ErrPurchaseOrderStatus)
+	_, err = manager.SendPurchaseOrder(ctx, order.ID)
+	assert.ErrorIs(t, err, inventory.ErrPurchaseOrderStatus)
+	order, err = manager.ApprovePurchaseOrder(ctx, order.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, order.ApprovedAt)
+	order, err = manager.SendPurchaseOrder(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, inventory.PurchaseOrderStatusSent, order.Status)
+
+	// 一部入荷（ロットごとに2行、単価は発注単価を補完）
+	expiry := time.Now().Add(30 * 24 * time.Hour).Truncate(time.Second)
+	receipt = &inventory.GoodsReceipt{Reference: "DN-1", Lines: []inventory.GoodsReceiptLine{
+		{ItemID: "TEST-ITEM", Quantity: 4, LotNumber: "LOT-1", ExpiryDate: &expiry},
+		{ItemID: "TEST-ITEM", Quantity: 2, LotNumber: "LOT-2", UnitCost: 100},
+	}}
+	receipt, err = manager.ReceivePurchaseOrder(ctx, order.ID, receipt)
+	require.NoError(t, err)
+	require.NotEmpty(t, receipt.Lines[0].TransactionID)
+	tx, err := manager.GetTransaction(ctx, receipt.Lines[0].TransactionID)
+	require.NoError(t, err)
+	assert.Equal(t, inventory.TransactionTypeInbound, tx.Type)
+	assert.Equal(t, order.Reference, tx.Reference)
+	require.NotNil(t, tx.UnitCost)
+	assert.Equal(t, 120.0, *tx.UnitCost)
+	assert.Equal(t, order.ID, tx.Metadata[inventory.MetadataPurchaseOrderID])
+	assert.Equal(t, receipt.ID, tx.Metadata[inventory.MetadataGoodsReceiptID])
+	stock, err := manager.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), stock.Quantity)
+	lot, err := store.GetLotByNumber(ctx, "TEST-ITEM", "LOT-1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), lot.Quantity)
+	require.NotNil(t, lot.ExpiryDate)
+	assert.True(t, expiry.Equal(*lot.ExpiryDate))
+	lot, err = store.GetLotByNumber(ctx, "TEST-ITEM", "LOT-2")
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, lot.UnitCost)
+	order, err = manager.GetPurchaseOrder(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, inventory.PurchaseOrderStatusPartiallyReceived, order.Status)
+	assert.Equal(t, int64(6), order.Lines[1].ReceivedQuantity)
+
+	// 発注残の超過・発注にない商品は在庫を変えずに拒否
+	_, err = manager.ReceivePurchaseOrder(ctx, order.ID, &inventory.GoodsReceipt{Lines: []inventory.GoodsReceiptLine{{ItemID: "TEST-ITEM", Quantity: 5}}})
+	assert.ErrorIs(t, err, inventory.ErrReceiptExceedsOrder)
+	require.NoError(t, manager.CreateItem(ctx, &inventory.Item{ID: "NUT", Name: "ナット"}))
+	_, err = manager.ReceivePurchaseOrder(ctx, order.ID, &inventory.GoodsReceipt{Lines: []inventory.GoodsReceiptLine{{ItemID: "NUT", Quantity: 1}}})
+	assert.ErrorAs(t, err, &validationErr)
+	stock, err = manager.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), stock.Quantity)
+
+	// 残りを入荷すると全数入荷になり、取消はできない
+	_, err = manager.ReceivePurchaseOrder(ctx, order.ID, &inventory.GoodsReceipt{Lines: []inventory.GoodsReceiptLine{{ItemID: "TEST-ITEM", Quantity: 4}, {ItemID: "BOLT", Quantity: 50}}})
+	require.NoError(t, err)
+	order, err = manager.GetPurchaseOrder(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, inventory.PurchaseOrderStatusReceived, order.Status)
+	assert.NotNil(t, order.ClosedAt)
+	_, err = manager.CancelPurchaseOrder(ctx, order.ID)
+	assert.ErrorIs(t, err, inventory.ErrPurchaseOrderStatus)
+	receipts, err := manager.ListGoodsReceipts(ctx, order.ID)
+	require.NoError(t, err)
+	require.Len(t, receipts, 2)
+	assert.Equal(t, "DN-1", receipts[0].Reference)
+
+	// 取消と一覧の絞り込み
+	other := &inventory.PurchaseOrder{SupplierID: "SUP-A", LocationID: "LOC-B", Lines: []inventory.PurchaseOrderLine{{ItemID: "BOLT", Quantity: 1}}}
+	require.NoError(t, manager.CreatePurchaseOrder(ctx, other))
+	other, err = manager.CancelPurchaseOrder(ctx, other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, inventory.PurchaseOrderStatusCancelled, other.Status)
+	orders, err := manager.ListPurchaseOrders(ctx, inventory.PurchaseOrderFilter{Status: inventory.PurchaseOrderStatusReceived, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	assert.Equal(t, order.ID, orders[0].ID)
+	assert.Empty(t, orders[0].Lines)
+	_, err = manager.GetPurchaseOrder(ctx, "PO-NONE")
+	assert.ErrorIs(t, err, inventory.ErrPurchaseOrderNotFound)
+}
+
+func TestManager_SalesOrders(t *testing.T) {
+	ctx := context.Background()
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{DefaultLocation: "LOC-A"})
+	var validationErr *inventory.ValidationError
+
+	require.NoError(t, manager.CreateItem(ctx, &inventory.Item{ID: "BOLT", Name: "ボルト"}))
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 10, "IN-1"))
+	require.NoError(t, manager.Add(ctx, "BOLT", "LOC-A", 3, "IN-2"))
+
+	// 受注の作成（重複した商品・存在しない商品）
+	lines := []inventory.SalesOrderLine{{ItemID: "TEST-ITEM", Quantity: 6}, {ItemID: "BOLT", Quantity: 5}}
+	assert.ErrorAs(t, manager.CreateSalesOrder(ctx, &inventory.SalesOrder{LocationID: "LOC-A", Lines: []inventory.SalesOrderLine{{ItemID: "BOLT", Quantity: 1}, {ItemID: "BOLT", Quantity: 2}}}), &validationErr)
+	assert.ErrorIs(t, manager.CreateSalesOrder(ctx, &inventory.SalesOrder{LocationID: "LOC-A", Lines: []inventory.SalesOrderLine{{ItemID: "NONE", Quantity: 1}}}), inventory.ErrItemNotFound)
+	order := &inventory.SalesOrder{LocationID: "LOC-A", Reference: "SO-1", Lines: lines}
+	require.NoError(t, manager.CreateSalesOrder(ctx, order))
+	assert.Equal(t, inventory.SalesOrderStatusOpen, order.Status)
+
+	// 在庫不足の行がある場合、部分引当を許可しなければ何も引き当てない
+	_, err := manager.AllocateSalesOrder(ctx, order.ID, false)
+	assert.ErrorIs(t, err, inventory.ErrInsufficientStock)
+	stock, err := manager.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), stock.Reserved)
+
+	// 部分引当（BOLT は3のみ）と在庫状況
+	order, err = manager.AllocateSalesOrder(ctx, order.ID, true)
+	require.NoError(t, err)
+	assert.Equal(t, inventory.SalesOrderStatusOpen, order.Status)
+	assert.Equal(t, int64(3), order.Lines[0].AllocatedQuantity)
+	assert.Equal(t, int64(6), order.Lines[1].AllocatedQuantity)
+	status, err := manager.GetSalesOrderStockStatus(ctx, order.ID)
+	require.NoError(t, err)
+	assert.False(t, status.FullyAllocated)
+	assert.False(t, status.Fulfillable)
+	assert.Equal(t, int64(2), status.Lines[0].Unallocated)
+	assert.Equal(t, int64(0), status.Lines[0].Available)
+	reservations, err := manager.GetReservationsByReference(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Len(t, reservations, 2)
+
+	// 入荷後に残りを引き当てると allocated になる
+	require.NoError(t, manager.Add(ctx, "BOLT", "LOC-A", 5, "IN-3"))
+	status, err = manager.GetSalesOrderStockStatus(ctx, order.ID)
+	require.NoError(t, err)
+	assert.True(t, status.Fulfillable)
+	order, err = manager.AllocateSalesOrder(ctx, order.ID, false)
+	require.NoError(t, err)
+	assert.Equal(t, inventory.SalesOrderStatusAllocated, order.Status)
+	assert.Equal(t, int64(5), order.Lines[0].AllocatedQuantity)
+
+	// 引当数量を超える出荷・受注にない商品は拒否
+	_, err = manager.ShipSalesOrder(ctx, order.ID, []inventory.ShipmentLine{{ItemID: "TEST-ITEM", Quantity: 7}})
+	assert.ErrorIs(t, err, inventory.ErrInsufficientReservation)
+	_, err = manager.ShipSalesOrder(ctx, order.ID, []inventory.ShipmentLine{{ItemID: "NUT", Quantity: 1}})
+	assert.ErrorAs(t, err, &validationErr)
+
+	// 欠品出荷（TEST-ITEM は4のみ出荷、BOLT は出荷しない）
+	order, err = manager.ShipSalesOrder(ctx, order.ID, []inventory.ShipmentLine{{ItemID: "TEST-ITEM", Quantity: 4}})
+	require.NoError(t, err)
+	assert.Equal(t, inventory.SalesOrderStatusShipped, order.Status)
+	assert.NotNil(t, order.ClosedAt)
+	assert.Equal(t, int64(0), order.Lines[0].ShippedQuantity)
+	assert.Equal(t, int64(5), order.Lines[0].ShortQuantity)
+	assert.Equal(t, int64(4), order.Lines[1].ShippedQuantity)
+	assert.Equal(t, int64(2), order.Lines[1].ShortQuantity)
+	stock, err = manager.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), stock.Quantity)
+	assert.Equal(t, int64(0), stock.Reserved)
+	stock, err = manager.GetStock(ctx, "BOLT", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), stock.Quantity)
+	assert.Equal(t, int64(0), stock.Reserved)
+	txs, err := manager.SearchHistoryByMetadata(ctx, inventory.MetadataSalesOrderID, order.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+	assert.Equal(t, inventory.TransactionTypeOutbound, txs[0].Type)
+	assert.Equal(t, "SO-1", txs[0].Reference)
+	assert.Equal(t, int64(4), txs[0].Quantity)
+	reservations, err = manager.ListReservations(ctx, inventory.ReservationFilter{Reference: order.ID, Status: inventory.ReservationStatusActive, Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, reservations)
+	_, err = manager.AllocateSalesOrder(ctx, order.ID, true)
+	assert.ErrorIs(t, err, inventory.ErrSalesOrderStatus)
+
+	// 取消は引当の予約を解除する
+	other := &inventory.SalesOrder{LocationID: "LOC-A", Lines: []inventory.SalesOrderLine{{ItemID: "TEST-ITEM", Quantity: 2}}}
+	require.NoError(t, manager.CreateSalesOrder(ctx, other))
+	_, err = manager.AllocateSalesOrder(ctx, other.ID, false)
+	require.NoError(t, err)
+	other, err = manager.CancelSalesOrder(ctx, other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, inventory.SalesOrderStatusCancelled, other.Status)
+	stock, err = manager.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), stock.Reserved)
+	_, err = manager.ShipSalesOrder(ctx, other.ID, nil)
+	assert.ErrorIs(t, err, inventory.ErrSalesOrderStatus)
+
+	orders, err := manager.ListSalesOrders(ctx, inventory.SalesOrderFilter{Status: inventory.SalesOrderStatusShipped, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	assert.Equal(t, order.ID, orders[0].ID)
+	assert.Empty(t, orders[0].Lines)
+	_, err = manager.GetSalesOrder(ctx, "SO-NONE")
+	assert.ErrorIs(t, err, inventory.ErrSalesOrderNotFound)
+}
+
+// TestManager_Returns は返品（RMA）の到着・検品待ち・処分のテスト
+func TestManager_Returns(t *testing.T) {
+	ctx := context.Background()
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{DefaultLocation: "LOC-A"})
+	var validationErr *inventory.ValidationError
+
+	require.NoError(t, manager.CreateItem(ctx, &inventory.Item{ID: "TEST-ITEM-R", Name: "テスト商品（再整備品）"}))
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 10, "IN-1"))
+
+	// 出荷確定した受注からの返品
+	order := &inventory.SalesOrder{LocationID: "LOC-A", Reference: "SO-1", Customer: "顧客A", Lines: []inventory.SalesOrderLine{{ItemID: "TEST-ITEM", Quantity: 6}}}
+	require.NoError(t, manager.CreateSalesOrder(ctx, order))
+	_, err := manager.AllocateSalesOrder(ctx, order.ID, false)
+	require.NoError(t, err)
+	rma := &inventory.ReturnAuthorization{LocationID: "LOC-A", SalesOrderID: order.ID, Lines: []inventory.ReturnLine{{ItemID: "TEST-ITEM", Quantity: 5}}}
+	assert.ErrorAs(t, manager.CreateReturn(ctx, rma), &validationErr)
+	_, err = manager.ShipSalesOrder(ctx, order.ID, nil)
+	require.NoError(t, err)
+	rma.Lines[0].Quantity = 7
+	assert.ErrorAs(t, manager.CreateReturn(ctx, rma), &validationErr)
+	rma.Lines[0].Quantity = 5
+	rma.Reference = "RMA-1"
+	require.NoError(t, manager.CreateReturn(ctx, rma))
+	assert.Equal(t, inventory.ReturnStatusAuthorized, rma.Status)
+	assert.Equal(t, "顧客A", rma.Customer)
+
+	// 到着した数量は検品待ちで在庫に含めない
+	_, err = manager.ReceiveReturn(ctx, rma.ID, []inventory.ReturnReceiptLine{{ItemID: "TEST-ITEM", Quantity: 6}})
+	assert.ErrorIs(t, err, inventory.ErrReturnQuantityExceeded)
+	rma, err = manager.ReceiveReturn(ctx, rma.ID, []inventory.ReturnReceiptLine{{ItemID: "TEST-ITEM", Quantity: 4}})
+	require.NoError(t, err)
+	assert.Equal(t, inventory.ReturnStatusAuthorized, rma.Status)
+	assert.Equal(t, int64(4), rma.Lines[0].Pending())
+	stock, err := manager.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), stock.Quantity)
+
+	// 処分（再入庫・再整備・廃棄）
+	_, err = manager.DispositionReturn(ctx, rma.ID, &inventory.ReturnDisposition{ItemID: "TEST-ITEM", Quantity: 5, Type: inventory.ReturnDispositionRestock})
+	assert.ErrorIs(t, err, inventory.ErrReturnQuantityExceeded)
+	_, err = manager.DispositionReturn(ctx, rma.ID, &inventory.ReturnDisposition{ItemID: "TEST-ITEM", Quantity: 1, Type: inventory.ReturnDispositionScrap})
+	assert.ErrorAs(t, err, &validationErr)
+	restock, err := manager.DispositionReturn(ctx, rma.ID, &inventory.ReturnDisposition{ItemID: "TEST-ITEM", Quantity: 2, Type: inventory.ReturnDispositionRestock})
+	require.NoError(t, err)
+	assert.NotEmpty(t, restock.TransactionID)
+	assert.Equal(t, "LOC-A", restock.LocationID)
+	refurbish, err := manager.DispositionReturn(ctx, rma.ID, &inventory.ReturnDisposition{ItemID: "TEST-ITEM", Quantity: 1, Type: inventory.ReturnDispositionRefurbish, TargetItemID: "TEST-ITEM-R", LocationID: "LOC-B"})
+	require.NoError(t, err)
+	scrap, err := manager.DispositionReturn(ctx, rma.ID, &inventory.ReturnDisposition{ItemID: "TEST-ITEM", Quantity: 1, Type: inventory.ReturnDispositionScrap, Reason: inventory.AdjustmentReasonDamage})
+	require.NoError(t, err)
+	assert.Empty(t, scrap.TransactionID)
+	stock, err = manager.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), stock.Quantity)
+	stock, err = manager.GetStock(ctx, "TEST-ITEM-R", "LOC-B")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stock.Quantity)
+	txs, err := manager.SearchHistoryByMetadata(ctx, inventory.MetadataReturnID, rma.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, txs, 2)
+	for _, tx := range txs {
+		assert.Equal(t, inventory.TransactionTypeInbound, tx.Type)
+		assert.Equal(t, "RMA-1", tx.Reference)
+	}
+	assert.ElementsMatch(t, []string{restock.TransactionID, refurbish.TransactionID}, []string{txs[0].ID, txs[1].ID})
+
+	// 未到着の数量が残る間は完了せず、残りの到着と処分で完了する
+	rma, err = manager.GetReturn(ctx, rma.ID)
+	require.NoError(t, err)
+	assert.Equal(t, inventory.ReturnStatusAuthorized, rma.Status)
+	assert.Equal(t, int64(2), rma.Lines[0].RestockedQuantity)
+	assert.Equal(t, int64(1), rma.Lines[0].RefurbishedQuantity)
+	assert.Equal(t, int64(1), rma.Lines[0].ScrappedQuantity)
+	rma, err = manager.ReceiveReturn(ctx, rma.ID, []inventory.ReturnReceiptLine{{ItemID: "TEST-ITEM", Quantity: 1}})
+	require.NoError(t, err)
+	assert.Equal(t, inventory.ReturnStatusReceived, rma.Status)
+	_, err = manager.CancelReturn(ctx, rma.ID)
+	assert.ErrorIs(t, err, inventory.ErrReturnStatus)
+	_, err = manager.DispositionReturn(ctx, rma.ID, &inventory.ReturnDisposition{ItemID: "TEST-ITEM", Quantity: 1, Type: inventory.ReturnDispositionRestock})
+	require.NoError(t, err)
+	rma, err = manager.GetReturn(ctx, rma.ID)
+	require.NoError(t, err)
+	assert.Equal(t, inventory.ReturnStatusCompleted, rma.Status)
+	assert.NotNil(t, rma.ClosedAt)
+	dispositions, err := manager.ListReturnDispositions(ctx, rma.ID)
+	require.NoError(t, err)
+	assert.Len(t, dispositions, 4)
+	_, err = manager.ReceiveReturn(ctx, rma.ID, []inventory.ReturnReceiptLine{{ItemID: "TEST-ITEM", Quantity: 1}})
+	assert.ErrorIs(t, err, inventory.ErrReturnStatus)
+
+	// 受注のない返品の取消
+	other := &inventory.ReturnAuthorization{LocationID: "LOC-B", Lines: []inventory.ReturnLine{{ItemID: "TEST-ITEM", Quantity: 3}}}
+	require.NoError(t, manager.CreateReturn(ctx, other))
+	assert.Equal(t, other.ID, other.Reference)
+	other, err = manager.CancelReturn(ctx, other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, inventory.ReturnStatusCancelled, other.Status)
+
+	returns, err := manager.ListReturns(ctx, inventory.ReturnFilter{SalesOrderID: order.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, returns, 1)
+	assert.Equal(t, rma.ID, returns[0].ID)
+	assert.Empty(t, returns[0].Lines)
+	_, err = manager.GetReturn(ctx, "RMA-NONE")
+	assert.ErrorIs(t, err, inventory.ErrReturnNotFound)
+}
+
+func TestManager_Consignment(t *testing.T) {
+	ctx := context.Background()
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{DefaultLocation: "LOC-A"})
+	var validationErr *inventory.ValidationError
+
+	require.NoError(t, manager.UpdateItem(ctx, &inventory.Item{ID: "TEST-ITEM", Name: "テスト商品", UnitCost: 100}))
+	require.NoError(t, manager.CreateSupplier(ctx, &inventory.Supplier{ID: "SUP-A", Name: "仕入先A", IsActive: true}))
+
+	// 所有者は登録済みの仕入先であること
+	assert.ErrorIs(t, manager.Add(inventory.WithOwner(ctx, "SUP-X"), "TEST-ITEM", "LOC-A", 5, "CONS-0"), inventory.ErrSupplierNotFound)
+	assert.ErrorAs(t, manager.Add(inventory.WithOwner(ctx, "BAD ID"), "TEST-ITEM", "LOC-A", 5, "CONS-0"), &validationErr)
+
+	// 自社所有の在庫と委託在庫を同じロケーションで保管
+	consigned := inventory.WithOwner(ctx, "SUP-A")
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 4, "IN-1"))
+	require.NoError(t, manager.Add(consigned, "TEST-ITEM", "LOC-A", 6, "CONS-1"))
+	ownership, err := manager.GetStockOwnership(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), ownership.Quantity)
+	assert.Equal(t, int64(4), ownership.OwnedQuantity)
+	assert.Equal(t, int64(6), ownership.ConsignedQuantity)
+	require.Len(t, ownership.Consignments, 1)
+	assert.Equal(t, "SUP-A", ownership.Consignments[0].OwnerID)
+
+	// 評価は自社所有の数量のみ
+	value, err := manager.CalculateValue(ctx, "TEST-ITEM", "LOC-A", inventory.ValuationMethodStandard)
+	require.NoError(t, err)
+	assert.InDelta(t, 400.0, value, 0.001)
+	summary, err := manager.GetSummary(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), summary.TotalUnits)
+	assert.InDelta(t, 400.0, summary.TotalValue, 0.001, "集計の評価額も委託在庫を除く")
+
+	// 委託在庫の移動と消費
+	require.NoError(t, manager.Transfer(consigned, "TEST-ITEM", "LOC-A", "LOC-B", 2, "CONS-MOVE"))
+	assert.ErrorIs(t, manager.Remove(consigned, "TEST-ITEM", "LOC-A", 5, "CONS-OUT"), inventory.ErrInsufficientConsignmentStock)
+	require.NoError(t, manager.Remove(consigned, "TEST-ITEM", "LOC-A", 3, "CONS-OUT"))
+	stock, err := manager.GetStock(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), stock.Quantity)
+
+	consignments, err := manager.ListConsignmentStocks(ctx, inventory.ConsignmentStockFilter{OwnerID: "SUP-A", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, consignments, 2)
+	assert.Equal(t, "LOC-A", consignments[0].LocationID)
+	assert.Equal(t, int64(1), consignments[0].Quantity)
+	assert.Equal(t, "LOC-B", consignments[1].LocationID)
+	assert.Equal(t, int64(2), consignments[1].Quantity)
+	_, err = manager.ListConsignmentStocks(ctx, inventory.ConsignmentStockFilter{Offset: -1, Limit: 10})
+	assert.ErrorAs(t, err, &validationErr)
+
+	// 所有者はトランザクションのメタデータで検索できる
+	txs, err := store.SearchTransactionsByMetadata(ctx, inventory.MetadataOwnerID, "SUP-A", 10)
+	require.NoError(t, err)
+	assert.Len(t, txs, 3)
+
+	// 在庫がすべて委託在庫の場合は自社所有の数量は0
+	ownership, err = manager.GetStockOwnership(ctx, "TEST-ITEM", "LOC-B")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), ownership.OwnedQuantity)
+	assert.Equal(t, int64(2), ownership.ConsignedQuantity)
+
+	// 所有者を指定しない出庫・移動・調整は委託在庫を減らせない
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-B", 2, "IN-2"))
+	require.NoError(t, manager.Add(consigned, "TEST-ITEM", "LOC-B", 6, "CONS-2"))
+	assert.ErrorIs(t, manager.Remove(ctx, "TEST-ITEM", "LOC-B", 9, "OUT-1"), inventory.ErrInsufficientStock)
+	assert.ErrorIs(t, manager.Transfer(ctx, "TEST-ITEM", "LOC-B", "LOC-A", 3, "MOVE-1"), inventory.ErrInsufficientStock)
+	assert.ErrorIs(t, manager.Adjust(ctx, "TEST-ITEM", "LOC-B", 7, "ADJ-1"), inventory.ErrInsufficientStock)
+	require.NoError(t, manager.Remove(ctx, "TEST-ITEM", "LOC-B", 2, "OUT-1"))
+	assert.ErrorIs(t, manager.Remove(ctx, "TEST-ITEM", "LOC-B", 1, "OUT-2"), inventory.ErrInsufficientStock)
+	ownership, err = manager.GetStockOwnership(ctx, "TEST-ITEM", "LOC-B")
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), ownership.Quantity)
+	assert.Equal(t, int64(0), ownership.OwnedQuantity)
+	assert.Equal(t, int64(8), ownership.ConsignedQuantity)
+	require.NoError(t, manager.Remove(consigned, "TEST-ITEM", "LOC-B", 8, "CONS-OUT-2"))
+
+	// 委託在庫が在庫数量を超える不整合は0に丸めずエラーにする
+	_, err = store.AdjustConsignmentStock(ctx, "SUP-A", "TEST-ITEM", "LOC-A", 5)
+	require.NoError(t, err)
+	_, err = manager.GetStockOwnership(ctx, "TEST-ITEM", "LOC-A")
+	assert.ErrorIs(t, err, inventory.ErrConsignmentStockMismatch)
+	_, err = manager.CalculateValue(ctx, "TEST-ITEM", "LOC-A", inventory.ValuationMethodStandard)
+	assert.ErrorIs(t, err, inventory.ErrConsignmentStockMismatch)
+}
+
+// TestManager_SalesChannels は販売チャネルごとの確保数量と予約のテスト
+func TestManager_SalesChannels(t *testing.T) {
+	ctx := context.Background()
+	store := newTestMemoryStorage(t)
+	manager := inventory.NewManager(store, nil, zap.NewNop(), &inventory.Config{DefaultLocation: "LOC-A"})
+	var validationErr *inventory.ValidationError
+
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-A", 10, "IN-1"))
+
+	// 確保の設定（無効なチャネル名・負の数量・存在しないロケーション）
+	assert.ErrorAs(t, manager.SetChannelAllocation(ctx, &inventory.ChannelAllocation{ItemID: "TEST-ITEM", LocationID: "LOC-A", Channel: "web shop", Quantity: 1}), &validationErr)
+	assert.ErrorAs(t, manager.SetChannelAllocation(ctx, &inventory.ChannelAllocation{ItemID: "TEST-ITEM", LocationID: "LOC-A", Channel: "web", Quantity: -1}), &validationErr)
+	assert.ErrorIs(t, manager.SetChannelAllocation(ctx, &inventory.ChannelAllocation{ItemID: "TEST-ITEM", LocationID: "LOC-X", Channel: "web", Quantity: 1}), inventory.ErrLocationNotFound)
+	require.NoError(t, manager.SetChannelAllocation(ctx, &inventory.ChannelAllocation{ItemID: "TEST-ITEM", LocationID: "LOC-A", Channel: "web", Quantity: 4}))
+	require.NoError(t, manager.SetChannelAllocation(ctx, &inventory.ChannelAllocation{ItemID: "TEST-ITEM", LocationID: "LOC-A", Channel: "retail", Quantity: 3}))
+
+	channels, err := manager.GetChannelAvailability(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), channels.SharedAvailable)
+	require.Len(t, channels.Channels, 2)
+	assert.Equal(t, "retail", channels.Channels[0].Channel)
+	assert.Equal(t, int64(6), channels.Channels[0].Available)
+	assert.Equal(t, "web", channels.Channels[1].Channel)
+	assert.Equal(t, int64(7), channels.Channels[1].Available)
+
+	// チャネルを指定しない予約は共有の在庫のみから確保する
+	assert.ErrorIs(t, manager.CreateReservation(ctx, &inventory.Reservation{ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 4, Reference: "POS-1"}), inventory.ErrInsufficientStock)
+	require.NoError(t, manager.CreateReservation(ctx, &inventory.Reservation{ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 3, Reference: "POS-1"}))
+
+	// 他のチャネルに確保された数量は予約できない
+	assert.ErrorIs(t, manager.CreateReservation(ctx, &inventory.Reservation{ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 5, Reference: "WEB-1", Channel: "web"}), inventory.ErrInsufficientStock)
+	web := &inventory.Reservation{ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 4, Reference: "WEB-1", Channel: "web"}
+	require.NoError(t, manager.CreateReservation(ctx, web))
+
+	// 受注の引当は受注の販売チャネルから確保する
+	order := &inventory.SalesOrder{LocationID: "LOC-A", Reference: "SO-1", Channel: "retail", Lines: []inventory.SalesOrderLine{{ItemID: "TEST-ITEM", Quantity: 3}}}
+	require.NoError(t, manager.CreateSalesOrder(ctx, order))
+	order, err = manager.AllocateSalesOrder(ctx, order.ID, false)
+	require.NoError(t, err)
+	assert.Equal(t, inventory.SalesOrderStatusAllocated, order.Status)
+
+	channels, err = manager.GetChannelAvailability(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), channels.Available)
+	assert.Equal(t, int64(0), channels.SharedAvailable)
+	assert.Equal(t, int64(3), channels.Channels[0].Reserved)
+	assert.Equal(t, int64(4), channels.Channels[1].Reserved)
+
+	reservations, err := manager.ListReservations(ctx, inventory.ReservationFilter{Channel: "web", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, reservations, 1)
+	assert.Equal(t, web.ID, reservations[0].ID)
+
+	// 予約の出庫はトランザクションのメタデータに販売チャネルを記録する
+	_, err = manager.FulfillReservation(ctx, web.ID, "")
+	require.NoError(t, err)
+	txs, err := store.SearchTransactionsByMetadata(ctx, inventory.MetadataChannel, "web", 10)
+	require.NoError(t, err)
+	assert.Len(t, txs, 2)
+
+	// 数量0で確保を削除
+	require.NoError(t, manager.SetChannelAllocation(ctx, &inventory.ChannelAllocation{ItemID: "TEST-ITEM", LocationID: "LOC-A", Channel: "web", Quantity: 0}))
+	allocations, err := manager.ListChannelAllocations(ctx, "TEST-ITEM", "LOC-A")
+	require.NoError(t, err)
+	require.Len(t, allocations, 1)
+	assert.Equal(t, "retail", allocations[0].Channel)
+
+	// チャネルを指定しない出庫・移動・調整は確保された数量を減らせない
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "LOC-B", 10, "IN-2"))
+	require.NoError(t, manager.SetChannelAllocation(ctx, &inventory.ChannelAllocation{ItemID: "TEST-ITEM", LocationID: "LOC-B", Channel: "web", Quantity: 8}))
+	assert.ErrorIs(t, manager.CreateReservation(ctx, &inventory.Reservation{ItemID: "TEST-ITEM", LocationID: "LOC-B", Quantity: 5, Reference: "POS-2"}), inventory.ErrInsufficientStock)
+	assert.ErrorIs(t, manager.Remove(ctx, "TEST-ITEM", "LOC-B", 9, "OUT-1"), inventory.ErrInsufficientStock)
+	assert.ErrorIs(t, manager.Transfer(ctx, "TEST-ITEM", "LOC-B", "LOC-A", 3, "MOVE-1"), inventory.ErrInsufficientStock)
+	assert.ErrorIs(t, manager.Adjust(ctx, "TEST-ITEM", "LOC-B", 7, "ADJ-1"), inventory.ErrInsufficientStock)
+	require.NoError(t, manager.Remove(ctx, "TEST-ITEM", "LOC-B", 2, "OUT-1"))
+	channels, err = manager.GetChannelAvailability(ctx, "TEST-ITEM", "LOC-B")
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), channels.Available)
+	assert.Equal(t, int64(0), channels.SharedAvailable)
+	require.Len(t, channels.Channels, 1)
+	assert.Equal(t, int64(8), channels.Channels[0].Available)
+
+	// 確保数量を減らすと出庫できる
+	require.NoError(t, manager.SetChannelAllocation(ctx, &inventory.ChannelAllocation{ItemID: "TEST-ITEM", LocationID: "LOC-B", Channel: "web", Quantity: 5}))
+	require.NoError(t, manager.Remove(ctx, "TEST-ITEM", "LOC-B", 3, "OUT-2"))
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/lib/pq"
@@ -17,13 +19,151 @@ import (
 // PostgreSQLStorage implements the Storage interface using PostgreSQL
 // PostgreSQLを使用したStorageインターフェースの実装
 type PostgreSQLStorage struct {
-	db     *sql.DB
-	logger *zap.Logger
+	db              *sql.DB
+	replicas        []*sql.DB           // 読み取り専用レプリカ（未設定の場合はdbから読み取る）
+	replicaIdx      *atomic.Uint32      // レプリカ選択用のラウンドロビンカウンタ
+	conn            querier             // クエリ実行先（通常はdb、トランザクション内ではtx）
+	tx              *sql.Tx             // トランザクションスコープの場合のみ設定
+	stmts           *preparedStatements // プライマリで準備済みの頻出クエリ
+	replicaGetStock []*sql.Stmt         // 各レプリカで準備済みのGetStockクエリ（replicasと同じ順序）
+	logger          *zap.Logger
 }
 
+// Hot queries prepared once at startup and reused for every call
+// 起動時に一度だけ準備して再利用する頻出クエリ
+const (
+	getStockQuery = `
+		SELECT item_id, location_id, quantity, reserved, available, version, updated_at, updated_by
+		FROM stocks 
+		WHERE item_id = $1 AND location_id = $2`
+
+	updateStockQuery = `
+		UPDATE stocks 
+		SET quantity = $3, reserved = $4, available = $5, version = $6, updated_at = $7, updated_by = $8
+		WHERE item_id = $1 AND location_id = $2 AND version = $9`
+
+	createTransactionQuery = `
+		INSERT INTO transactions (id, type, item_id, from_location, to_location, quantity, unit_cost, reference, lot_number, expiry_date, metadata, created_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+)
+
+// preparedStatements holds the prepared hot queries of the primary
+// プライマリで準備済みの頻出クエリ
+type preparedStatements struct {
+	getStock          *sql.Stmt
+	updateStock       *sql.Stmt
+	createTransaction *sql.Stmt
+}
+
+// querier is the subset of *sql.DB and *sql.Tx used to run queries
+// *sql.DBと*sql.Txに共通するクエリ実行メソッド
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+var _ inventory.Storage = (*PostgreSQLStorage)(nil)
+
 // NewPostgreSQLStorage creates a new PostgreSQL storage instance
 // 新しいPostgreSQLストレージインスタンスを作成
 func NewPostgreSQLStorage(dsn string, logger *zap.Logger) (*PostgreSQLStorage, error) {
+	return NewPostgreSQLStorageWithReplicas(dsn, nil, logger)
+}
+
+// NewPostgreSQLStorageWithReplicas creates a PostgreSQL storage that routes pure reads to replicas
+// 読み取り専用クエリをレプリカに振り分けるPostgreSQLストレージインスタンスを作成
+//
+// 書き込みとトランザクションは常にwriteDSNのプライマリで実行されます。
+// 在庫照会・履歴・分析系の読み取りはreadDSNsのレプリカにラウンドロビンで振り分けます。
+// readDSNsが空の場合はNewPostgreSQLStorageと同じ動作になります。
+func NewPostgreSQLStorageWithReplicas(writeDSN string, readDSNs []string, logger *zap.Logger) (*PostgreSQLStorage, error) {
+	db, err := openDB(writeDSN)
+	if err != nil {
+		return nil, err
+	}
+
+	replicas := make([]*sql.DB, 0, len(readDSNs))
+	for _, dsn := range readDSNs {
+		replica, err := openDB(dsn)
+		if err != nil {
+			for _, opened := range replicas {
+				opened.Close()
+			}
+			db.Close()
+			return nil, fmt.Errorf("読み取りレプリカへの接続に失敗しました: %w", err)
+		}
+		replicas = append(replicas, replica)
+	}
+
+	storage := &PostgreSQLStorage{
+		db:         db,
+		replicas:   replicas,
+		replicaIdx: new(atomic.Uint32),
+		conn:       db,
+		logger:     logger,
+	}
+
+	if err := storage.prepareStatements(context.Background()); err != nil {
+		storage.Close()
+		return nil, err
+	}
+
+	return storage, nil
+}
+
+// prepareStatements prepares the hot queries on the primary and each replica
+// 頻出クエリをプライマリと各レプリカで準備
+//
+// パース・実行計画作成のコストを呼び出しごとに払わないよう、接続プール単位で一度だけ準備します。
+// 失敗した場合も準備済みのステートメントはCloseで解放されます。
+func (s *PostgreSQLStorage) prepareStatements(ctx context.Context) error {
+	s.stmts = &preparedStatements{}
+
+	var err error
+	if s.stmts.getStock, err = s.db.PrepareContext(ctx, getStockQuery); err != nil {
+		return fmt.Errorf("在庫取得クエリの準備に失敗しました: %w", err)
+	}
+	if s.stmts.updateStock, err = s.db.PrepareContext(ctx, updateStockQuery); err != nil {
+		return fmt.Errorf("在庫更新クエリの準備に失敗しました: %w", err)
+	}
+	if s.stmts.createTransaction, err = s.db.PrepareContext(ctx, createTransactionQuery); err != nil {
+		return fmt.Errorf("トランザクション記録作成クエリの準備に失敗しました: %w", err)
+	}
+
+	for _, replica := range s.replicas {
+		stmt, err := replica.PrepareContext(ctx, getStockQuery)
+		if err != nil {
+			return fmt.Errorf("読み取りレプリカでの在庫取得クエリの準備に失敗しました: %w", err)
+		}
+		s.replicaGetStock = append(s.replicaGetStock, stmt)
+	}
+
+	return nil
+}
+
+// close releases all prepared statements
+// 準備済みのステートメントをすべて解放
+func (p *preparedStatements) close() {
+	for _, stmt := range []*sql.Stmt{p.getStock, p.updateStock, p.createTransaction} {
+		if stmt != nil {
+			stmt.Close()
+		}
+	}
+}
+
+// stmt returns the prepared statement bound to the current transaction, if any
+// トランザクションスコープ内であれば、そのトランザクションに紐付けたステートメントを返す
+func (s *PostgreSQLStorage) stmt(ctx context.Context, stmt *sql.Stmt) *sql.Stmt {
+	if s.tx != nil {
+		return s.tx.StmtContext(ctx, stmt)
+	}
+	return stmt
+}
+
+// openDB opens a connection pool and verifies connectivity
+// 接続プールを作成して接続を確認
+func openDB(dsn string) (*sql.DB, error) {
 	db, err := sql.Open("postgres", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("データベース接続に失敗しました: %w", err)
@@ -31,30 +171,119 @@ func NewPostgreSQLStorage(dsn string, logger *zap.Logger) (*PostgreSQLStorage, e
 
 	// 接続テスト
 	if err := db.Ping(); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("データベースpingに失敗しました: %w", err)
 	}
 
-	// 接続プール設定
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(10)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	DefaultPoolConfig().apply(db)
 
-	storage := &PostgreSQLStorage{
-		db:     db,
-		logger: logger,
+	return db, nil
+}
+
+// PoolConfig holds connection pool settings
+// 接続プール設定
+type PoolConfig struct {
+	MaxOpenConns    int           // 最大接続数（0で無制限）
+	MaxIdleConns    int           // 最大アイドル接続数
+	ConnMaxLifetime time.Duration // 接続の最大存続時間（0で無制限）
+}
+
+// DefaultPoolConfig returns the pool settings used when none are configured
+// 設定されていない場合に使用する接続プール設定を返す
+func DefaultPoolConfig() PoolConfig {
+	return PoolConfig{
+		MaxOpenConns:    25,
+		MaxIdleConns:    10,
+		ConnMaxLifetime: 5 * time.Minute,
 	}
+}
 
-	return storage, nil
+// apply sets the pool settings on db
+// 接続プール設定をdbに適用
+func (c PoolConfig) apply(db *sql.DB) {
+	db.SetMaxOpenConns(c.MaxOpenConns)
+	db.SetMaxIdleConns(c.MaxIdleConns)
+	db.SetConnMaxLifetime(c.ConnMaxLifetime)
+}
+
+// ConfigurePool applies pool settings to the primary and every replica
+// プライマリと全レプリカに接続プール設定を適用
+//
+// インスタンスサイズに合わせて接続数を調整する場合に使用します。
+// 各レプリカはプライマリと同じ設定で個別のプールを持ちます。
+func (s *PostgreSQLStorage) ConfigurePool(cfg PoolConfig) {
+	cfg.apply(s.db)
+	for _, replica := range s.replicas {
+		cfg.apply(replica)
+	}
+}
+
+// reader returns the querier used for pure reads
+// 読み取り専用クエリの実行先を返す
+//
+// トランザクション内、レプリカ未設定、またはinventory.WithPrimaryReadで
+// プライマリ読み取りが指定された場合はプライマリを使用します。
+func (s *PostgreSQLStorage) reader(ctx context.Context) querier {
+	if i := s.replicaIndex(ctx); i >= 0 {
+		return s.replicas[i]
+	}
+	return s.conn
+}
+
+// replicaIndex picks the replica for a pure read, or returns -1 to use the primary
+// 読み取り専用クエリを実行するレプリカを選択（プライマリを使用する場合は-1）
+func (s *PostgreSQLStorage) replicaIndex(ctx context.Context) int {
+	if s.tx != nil || len(s.replicas) == 0 || inventory.IsPrimaryRead(ctx) {
+		return -1
+	}
+	n := s.replicaIdx.Add(1)
+	return int(n-1) % len(s.replicas)
 }
 
-// Begin starts a new database transaction
-// 新しいデータベーストランザクションを開始
-func (s *PostgreSQLStorage) Begin(ctx context.Context) (*sql.Tx, error) {
+// WithinTx runs fn inside a single database transaction
+// 単一のデータベーストランザクション内でfnを実行
+//
+// fnがエラーを返すかパニックした場合はロールバックし、それ以外はコミットします。
+// 既にトランザクションスコープ内で呼ばれた場合は同じトランザクションを使用します。
+func (s *PostgreSQLStorage) WithinTx(ctx context.Context, fn func(txStorage inventory.Storage) error) error {
+	if s.tx != nil {
+		return fn(s)
+	}
+
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("トランザクション開始に失敗しました: %w", err)
+		return fmt.Errorf("トランザクション開始に失敗しました: %w", err)
 	}
-	return tx, nil
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	txStorage := &PostgreSQLStorage{
+		db:         s.db,
+		replicas:   s.replicas,
+		replicaIdx: s.replicaIdx,
+		conn:       tx,
+		tx:         tx,
+		stmts:      s.stmts,
+		logger:     s.logger,
+	}
+
+	if err := fn(txStorage); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			s.logger.Error("トランザクションのロールバックに失敗しました", zap.Error(rbErr))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションのコミットに失敗しました: %w", err)
+	}
+
+	return nil
 }
 
 // CreateStock creates a new stock record
@@ -64,7 +293,7 @@ func (s *PostgreSQLStorage) CreateStock(ctx context.Context, stock *inventory.St
 		INSERT INTO stocks (item_id, location_id, quantity, reserved, available, version, updated_at, updated_by)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
 
-	_, err := s.db.ExecContext(ctx, query,
+	_, err := s.conn.ExecContext(ctx, query,
 		stock.ItemID,
 		stock.LocationID,
 		stock.Quantity,
@@ -88,12 +317,7 @@ func (s *PostgreSQLStorage) CreateStock(ctx context.Context, stock *inventory.St
 // UpdateStock updates an existing stock record
 // 既存の在庫記録を更新
 func (s *PostgreSQLStorage) UpdateStock(ctx context.Context, stock *inventory.Stock) error {
-	query := `
-		UPDATE stocks 
-		SET quantity = $3, reserved = $4, available = $5, version = $6, updated_at = $7, updated_by = $8
-		WHERE item_id = $1 AND location_id = $2 AND version = $9`
-
-	result, err := s.db.ExecContext(ctx, query,
+	result, err := s.stmt(ctx, s.stmts.updateStock).ExecContext(ctx,
 		stock.ItemID,
 		stock.LocationID,
 		stock.Quantity,
@@ -121,16 +345,265 @@ func (s *PostgreSQLStorage) UpdateStock(ctx context.Context, stock *inventory.St
 	return nil
 }
 
+// stockUpsertChunkSize is the number of rows per multi-row upsert statement
+// 一括アップサート1文あたりの行数（PostgreSQLのパラメータ上限65535に収まる値）
+const stockUpsertChunkSize = 1000
+
+// CreateOrUpdateStocks upserts stock records with multi-row INSERT ... ON CONFLICT
+// 複数行の INSERT ... ON CONFLICT で在庫記録を一括作成・更新
+//
+// 同一の商品・ロケーションが複数含まれる場合は後のものが優先されます。
+// 全チャンクは単一のトランザクションで実行されます。
+func (s *PostgreSQLStorage) CreateOrUpdateStocks(ctx context.Context, stocks []inventory.Stock) error {
+	rows := dedupeStocks(stocks)
+	if len(rows) == 0 {
+		return nil
+	}
+
+	return s.WithinTx(ctx, func(txStorage inventory.Storage) error {
+		tx := txStorage.(*PostgreSQLStorage)
+		for start := 0; start < len(rows); start += stockUpsertChunkSize {
+			end := start + stockUpsertChunkSize
+			if end > len(rows) {
+				end = len(rows)
+			}
+			if err := tx.upsertStockChunk(ctx, rows[start:end]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// upsertStockChunk executes a single multi-row upsert statement
+// 1文の複数行アップサートを実行
+func (s *PostgreSQLStorage) upsertStockChunk(ctx context.Context, stocks []inventory.Stock) error {
+	const columns = 7
+
+	var query strings.Builder
+	query.WriteString(`
+		INSERT INTO stocks (item_id, location_id, quantity, reserved, available, version, updated_at, updated_by)
+		VALUES `)
+
+	args := make([]interface{}, 0, len(stocks)*columns)
+	for i, stock := range stocks {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		base := i * columns
+		fmt.Fprintf(&query, "($%d, $%d, $%d, $%d, $%d, 1, $%d, $%d)",
+			base+1, base+2, base+3, base+4, base+5, base+6, base+7)
+
+		updatedAt := stock.UpdatedAt
+		if updatedAt.IsZero() {
+			updatedAt = time.Now()
+		}
+		args = append(args,
+			stock.ItemID,
+			stock.LocationID,
+			stock.Quantity,
+			stock.Reserved,
+			stock.Quantity-stock.Reserved,
+			updatedAt,
+			stock.UpdatedBy,
+		)
+	}
+
+	query.WriteString(`
+		ON CONFLICT (item_id, location_id) DO UPDATE SET
+			quantity = EXCLUDED.quantity,
+			reserved = EXCLUDED.reserved,
+			available = EXCLUDED.available,
+			version = stocks.version + 1,
+			updated_at = EXCLUDED.updated_at,
+			updated_by = EXCLUDED.updated_by`)
+
+	if _, err := s.conn.ExecContext(ctx, query.String(), args...); err != nil {
+		return fmt.Errorf("在庫記録の一括登録に失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// dedupeStocks removes duplicate item/location pairs keeping the last occurrence
+// 同一の商品・ロケーションの重複を除去（後のものを優先、順序は維持）
+func dedupeStocks(stocks []inventory.Stock) []inventory.Stock {
+	index := make(map[stockKey]int, len(stocks))
+	result := make([]inventory.Stock, 0, len(stocks))
+	for _, stock := range stocks {
+		key := stockKey{itemID: stock.ItemID, locationID: stock.LocationID}
+		if i, exists := index[key]; exists {
+			result[i] = stock
+			continue
+		}
+		index[key] = len(result)
+		result = append(result, stock)
+	}
+	return result
+}
+
+// BulkImport loads items, stocks and transactions with COPY
+// COPYで商品・在庫・トランザクションを一括取り込み
+//
+// 1行ずつのINSERTではなくpq.CopyInを使用し、全テーブルを単一のトランザクションで取り込みます。
+// COPYは既存行を更新しないため、重複がある場合は全体がロールバックされます。
+func (s *PostgreSQLStorage) BulkImport(ctx context.Context, data *inventory.BulkImportData) error {
+	return s.WithinTx(ctx, func(txStorage inventory.Storage) error {
+		tx := txStorage.(*PostgreSQLStorage).tx
+
+		err := copyIn(ctx, tx, "items",
+			[]string{"id", "name", "sku", "description", "category", "unit_cost", "base_uom", "created_at", "updated_at"},
+			len(data.Items), func(i int) ([]interface{}, error) {
+				item := data.Items[i]
+				baseUoM := item.BaseUoM
+				if baseUoM == "" {
+					baseUoM = inventory.UoMEach
+				}
+				return []interface{}{item.ID, item.Name, item.SKU, item.Description, item.Category, item.UnitCost, baseUoM, item.CreatedAt, item.UpdatedAt}, nil
+			})
+		if err != nil {
+			return err
+		}
+
+		err = copyIn(ctx, tx, "stocks",
+			[]string{"item_id", "location_id", "quantity", "reserved", "available", "version", "updated_at", "updated_by"},
+			len(data.Stocks), func(i int) ([]interface{}, error) {
+				stock := data.Stocks[i]
+				stock.CalculateAvailable()
+				return []interface{}{stock.ItemID, stock.LocationID, stock.Quantity, stock.Reserved, stock.Available, stock.Version, stock.UpdatedAt, stock.UpdatedBy}, nil
+			})
+		if err != nil {
+			return err
+		}
+
+		return copyIn(ctx, tx, "transactions",
+			[]string{"id", "type", "item_id", "from_location", "to_location", "quantity", "unit_cost", "reference", "lot_number", "expiry_date", "metadata", "created_at", "created_by"},
+			len(data.Transactions), func(i int) ([]interface{}, error) {
+				t := data.Transactions[i]
+				metadataJSON, err := json.Marshal(t.Metadata)
+				if err != nil {
+					return nil, fmt.Errorf("メタデータのJSON変換に失敗しました: %w", err)
+				}
+				// []byteはCOPYでbyteaとして送られるため、JSONBには文字列で渡す
+				return []interface{}{t.ID, t.Type, t.ItemID, t.FromLocation, t.ToLocation, t.Quantity, t.UnitCost, t.Reference, t.LotNumber, t.ExpiryDate, string(metadataJSON), t.CreatedAt, t.CreatedBy}, nil
+			})
+	})
+}
+
+// copyIn streams n rows into table with COPY FROM STDIN
+// COPY FROM STDINでn行をテーブルに流し込む
+func copyIn(ctx context.Context, tx *sql.Tx, table string, columns []string, n int, row func(i int) ([]interface{}, error)) error {
+	if n == 0 {
+		return nil
+	}
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn(table, columns...))
+	if err != nil {
+		return fmt.Errorf("%sの一括取り込み開始に失敗しました: %w", table, err)
+	}
+	defer stmt.Close()
+
+	for i := 0; i < n; i++ {
+		values, err := row(i)
+		if err != nil {
+			return err
+		}
+		if _, err := stmt.ExecContext(ctx, values...); err != nil {
+			return fmt.Errorf("%sの一括取り込みに失敗しました: %w", table, err)
+		}
+	}
+
+	// 引数なしのExecでバッファをフラッシュし、COPYを完了させる
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return fmt.Errorf("%sの一括取り込みで重複するレコードがあります: %w", table, err)
+		}
+		return fmt.Errorf("%sの一括取り込みに失敗しました: %w", table, err)
+	}
+
+	return nil
+}
+
 // GetStock retrieves stock information for an item at a location
 // 指定ロケーションの商品在庫情報を取得
 func (s *PostgreSQLStorage) GetStock(ctx context.Context, itemID, locationID string) (*inventory.Stock, error) {
+	stmt := s.stmt(ctx, s.stmts.getStock)
+	if i := s.replicaIndex(ctx); i >= 0 {
+		stmt = s.replicaGetStock[i]
+	}
+
+	return scanStock(stmt.QueryRowContext(ctx, itemID, locationID))
+}
+
+// GetStocks retrieves the stock of many item and location pairs in one query
+// 複数の商品とロケーションの組の在庫情報を1回のクエリで取得
+func (s *PostgreSQLStorage) GetStocks(ctx context.Context, keys []inventory.StockKey) ([]inventory.Stock, error) {
+	itemIDs := make([]string, len(keys))
+	locationIDs := make([]string, len(keys))
+	for i, key := range keys {
+		itemIDs[i] = key.ItemID
+		locationIDs[i] = key.LocationID
+	}
+
+	// 主キー (item_id, location_id) で組ごとに検索する
+	query := `
+		SELECT s.item_id, s.location_id, s.quantity, s.reserved, s.available, s.version, s.updated_at, s.updated_by
+		FROM stocks s
+		JOIN unnest($1::text[], $2::text[]) AS k(item_id, location_id)
+			ON s.item_id = k.item_id AND s.location_id = k.location_id`
+
+	rows, err := s.reader(ctx).QueryContext(ctx, query, pq.Array(itemIDs), pq.Array(locationIDs))
+	if err != nil {
+		return nil, fmt.Errorf("在庫一括取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var stocks []inventory.Stock
+	for rows.Next() {
+		var stock inventory.Stock
+		err := rows.Scan(
+			&stock.ItemID,
+			&stock.LocationID,
+			&stock.Quantity,
+			&stock.Reserved,
+			&stock.Available,
+			&stock.Version,
+			&stock.UpdatedAt,
+			&stock.UpdatedBy,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("在庫スキャンに失敗しました: %w", err)
+		}
+		stocks = append(stocks, stock)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("在庫スキャンに失敗しました: %w", err)
+	}
+
+	return stocks, nil
+}
+
+// GetStockForUpdate retrieves stock information and locks the row until the transaction ends
+// 在庫情報を取得し、トランザクション終了まで行ロックを保持
+func (s *PostgreSQLStorage) GetStockForUpdate(ctx context.Context, itemID, locationID string) (*inventory.Stock, error) {
+	if s.tx == nil {
+		return nil, fmt.Errorf("行ロックはトランザクション内でのみ取得できます")
+	}
+
 	query := `
 		SELECT item_id, location_id, quantity, reserved, available, version, updated_at, updated_by
 		FROM stocks 
-		WHERE item_id = $1 AND location_id = $2`
+		WHERE item_id = $1 AND location_id = $2
+		FOR UPDATE`
+
+	return scanStock(s.conn.QueryRowContext(ctx, query, itemID, locationID))
+}
 
+// scanStock scans the result of a single-row stock query
+// 単一行の在庫クエリの結果を読み取る
+func scanStock(row *sql.Row) (*inventory.Stock, error) {
 	stock := &inventory.Stock{}
-	err := s.db.QueryRowContext(ctx, query, itemID, locationID).Scan(
+	err := row.Scan(
 		&stock.ItemID,
 		&stock.LocationID,
 		&stock.Quantity,
@@ -160,7 +633,7 @@ func (s *PostgreSQLStorage) ListStockByLocation(ctx context.Context, locationID
 		WHERE location_id = $1
 		ORDER BY item_id`
 
-	rows, err := s.db.QueryContext(ctx, query, locationID)
+	rows, err := s.reader(ctx).QueryContext(ctx, query, locationID)
 	if err != nil {
 		return nil, fmt.Errorf("ロケーション在庫取得に失敗しました: %w", err)
 	}
@@ -188,13 +661,95 @@ func (s *PostgreSQLStorage) ListStockByLocation(ctx context.Context, locationID
 	return stocks, nil
 }
 
+// ListStockByItem retrieves the stock of an item at every location
+// 商品の全ロケーションの在庫を取得
+func (s *PostgreSQLStorage) ListStockByItem(ctx context.Context, itemID string) ([]inventory.Stock, error) {
+	query := `
+		SELECT item_id, location_id, quantity, reserved, available, version, updated_at, updated_by
+		FROM stocks
+		WHERE item_id = $1
+		ORDER BY location_id`
+
+	rows, err := s.reader(ctx).QueryContext(ctx, query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("商品在庫取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var stocks []inventory.Stock
+	for rows.Next() {
+		var stock inventory.Stock
+		err := rows.Scan(
+			&stock.ItemID,
+			&stock.LocationID,
+			&stock.Quantity,
+			&stock.Reserved,
+			&stock.Available,
+			&stock.Version,
+			&stock.UpdatedAt,
+			&stock.UpdatedBy,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("在庫スキャンに失敗しました: %w", err)
+		}
+		stocks = append(stocks, stock)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("在庫スキャンに失敗しました: %w", err)
+	}
+
+	return stocks, nil
+}
+
+// ForEachStockByLocation streams stock at a specific location to fn
+// 指定ロケーションの在庫を1件ずつfnに渡す
+func (s *PostgreSQLStorage) ForEachStockByLocation(ctx context.Context, locationID string, fn func(stock inventory.Stock) error) error {
+	query := `
+		SELECT item_id, location_id, quantity, reserved, available, version, updated_at, updated_by
+		FROM stocks 
+		WHERE location_id = $1
+		ORDER BY item_id`
+
+	rows, err := s.reader(ctx).QueryContext(ctx, query, locationID)
+	if err != nil {
+		return fmt.Errorf("ロケーション在庫取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var stock inventory.Stock
+		err := rows.Scan(
+			&stock.ItemID,
+			&stock.LocationID,
+			&stock.Quantity,
+			&stock.Reserved,
+			&stock.Available,
+			&stock.Version,
+			&stock.UpdatedAt,
+			&stock.UpdatedBy,
+		)
+		if err != nil {
+			return fmt.Errorf("在庫スキャンに失敗しました: %w", err)
+		}
+		if err := fn(stock); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("ロケーション在庫取得に失敗しました: %w", err)
+	}
+
+	return nil
+}
+
 // GetTotalStockByItem retrieves total stock quantity for an item across all locations
 // 商品の全ロケーションでの合計在庫数を取得
 func (s *PostgreSQLStorage) GetTotalStockByItem(ctx context.Context, itemID string) (int64, error) {
 	query := `SELECT COALESCE(SUM(quantity), 0) FROM stocks WHERE item_id = $1`
 
 	var totalStock int64
-	err := s.db.QueryRowContext(ctx, query, itemID).Scan(&totalStock)
+	err := s.reader(ctx).QueryRowContext(ctx, query, itemID).Scan(&totalStock)
 	if err != nil {
 		return 0, fmt.Errorf("合計在庫数取得に失敗しました: %w", err)
 	}
@@ -202,6 +757,20 @@ func (s *PostgreSQLStorage) GetTotalStockByItem(ctx context.Context, itemID stri
 	return totalStock, nil
 }
 
+// GetTotalStockByLocation retrieves total stock quantity of all items at a location
+// ロケーションの全商品の合計在庫数を取得
+func (s *PostgreSQLStorage) GetTotalStockByLocation(ctx context.Context, locationID string) (int64, error) {
+	query := `SELECT COALESCE(SUM(quantity), 0) FROM stocks WHERE location_id = $1`
+
+	var totalStock int64
+	err := s.reader(ctx).QueryRowContext(ctx, query, locationID).Scan(&totalStock)
+	if err != nil {
+		return 0, fmt.Errorf("ロケーション合計在庫数取得に失敗しました: %w", err)
+	}
+
+	return totalStock, nil
+}
+
 // CreateTransaction creates a new transaction record
 // 新しいトランザクション記録を作成
 func (s *PostgreSQLStorage) CreateTransaction(ctx context.Context, tx *inventory.Transaction) error {
@@ -210,11 +779,7 @@ func (s *PostgreSQLStorage) CreateTransaction(ctx context.Context, tx *inventory
 		return fmt.Errorf("メタデータのJSON変換に失敗しました: %w", err)
 	}
 
-	query := `
-		INSERT INTO transactions (id, type, item_id, from_location, to_location, quantity, unit_cost, reference, lot_number, expiry_date, metadata, created_at, created_by)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
-
-	_, err = s.db.ExecContext(ctx, query,
+	_, err = s.stmt(ctx, s.stmts.createTransaction).ExecContext(ctx,
 		tx.ID,
 		tx.Type,
 		tx.ItemID,
@@ -237,57 +802,106 @@ func (s *PostgreSQLStorage) CreateTransaction(ctx context.Context, tx *inventory
 	return nil
 }
 
-// GetTransactionHistory retrieves transaction history for an item
-// 商品のトランザクション履歴を取得
-func (s *PostgreSQLStorage) GetTransactionHistory(ctx context.Context, itemID string, limit int) ([]inventory.Transaction, error) {
-	query := `
+// EnsureTransactionPartitions creates monthly transaction partitions ahead of time
+// トランザクションテーブルの月次パーティションを事前作成
+//
+// fromを含む月から months か月分のパーティションを作成します（既存のパーティションはそのまま）。
+// 未作成の月のデータはデフォルトパーティションに入り、後からその月のパーティションを
+// 作成できなくなるため、起動時や定期ジョブで先の月まで作成しておきます。
+// デフォルトパーティションに行がある場合は、パーティションを作成したうえでエラーを返します。
+func (s *PostgreSQLStorage) EnsureTransactionPartitions(ctx context.Context, from time.Time, months int) error {
+	var stranded int64
+	if err := s.conn.QueryRowContext(ctx, `SELECT COUNT(*) FROM transactions_default`).Scan(&stranded); err != nil {
+		return fmt.Errorf("トランザクションのデフォルトパーティションの確認に失敗しました: %w", err)
+	}
+
+	month := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < months; i++ {
+		if _, err := s.conn.ExecContext(ctx, `SELECT create_transactions_partition($1)`, month.AddDate(0, i, 0)); err != nil {
+			return fmt.Errorf("トランザクションパーティション作成に失敗しました: %w", err)
+		}
+	}
+
+	if stranded > 0 {
+		return fmt.Errorf("トランザクションのデフォルトパーティションに%d件の行があります（該当する月のパーティションを作成して移動してください）", stranded)
+	}
+	return nil
+}
+
+// GetTransactionByID retrieves a single transaction record by ID
+// IDでトランザクション記録を取得
+func (s *PostgreSQLStorage) GetTransactionByID(ctx context.Context, txID string) (*inventory.Transaction, error) {
+	query := `
+		SELECT id, type, item_id, from_location, to_location, quantity, unit_cost, reference, lot_number, expiry_date, metadata, created_at, created_by
+		FROM transactions 
+		WHERE id = $1`
+
+	rows, err := s.reader(ctx).QueryContext(ctx, query, txID)
+	if err != nil {
+		return nil, fmt.Errorf("トランザクション取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	transactions, err := s.scanTransactions(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(transactions) == 0 {
+		return nil, inventory.ErrTransactionNotFound
+	}
+
+	return &transactions[0], nil
+}
+
+// GetTransactionHistory retrieves transaction history for an item
+// 商品のトランザクション履歴を取得
+func (s *PostgreSQLStorage) GetTransactionHistory(ctx context.Context, itemID string, limit int) ([]inventory.Transaction, error) {
+	query := `
 		SELECT id, type, item_id, from_location, to_location, quantity, unit_cost, reference, lot_number, expiry_date, metadata, created_at, created_by
 		FROM transactions 
 		WHERE item_id = $1
-		ORDER BY created_at DESC
+		ORDER BY created_at DESC, id DESC
 		LIMIT $2`
 
-	rows, err := s.db.QueryContext(ctx, query, itemID, limit)
+	rows, err := s.reader(ctx).QueryContext(ctx, query, itemID, limit)
 	if err != nil {
 		return nil, fmt.Errorf("トランザクション履歴取得に失敗しました: %w", err)
 	}
 	defer rows.Close()
 
-	var transactions []inventory.Transaction
-	for rows.Next() {
-		var tx inventory.Transaction
-		var metadataJSON []byte
-
-		err := rows.Scan(
-			&tx.ID,
-			&tx.Type,
-			&tx.ItemID,
-			&tx.FromLocation,
-			&tx.ToLocation,
-			&tx.Quantity,
-			&tx.UnitCost,
-			&tx.Reference,
-			&tx.LotNumber,
-			&tx.ExpiryDate,
-			&metadataJSON,
-			&tx.CreatedAt,
-			&tx.CreatedBy,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("トランザクションスキャンに失敗しました: %w", err)
-		}
-
-		// メタデータのデシリアライズ
-		if len(metadataJSON) > 0 {
-			if err := json.Unmarshal(metadataJSON, &tx.Metadata); err != nil {
-				s.logger.Warn("メタデータのパースに失敗しました", zap.Error(err))
-			}
-		}
+	return s.scanTransactions(rows)
+}
 
-		transactions = append(transactions, tx)
+// GetTransactionHistoryAfter retrieves transaction history older than the cursor
+// カーソルより古いトランザクション履歴を取得（キーセットページネーション）
+func (s *PostgreSQLStorage) GetTransactionHistoryAfter(ctx context.Context, itemID string, after *inventory.HistoryCursor, limit int) ([]inventory.Transaction, error) {
+	var rows *sql.Rows
+	var err error
+
+	if after == nil {
+		query := `
+			SELECT id, type, item_id, from_location, to_location, quantity, unit_cost, reference, lot_number, expiry_date, metadata, created_at, created_by
+			FROM transactions 
+			WHERE item_id = $1
+			ORDER BY created_at DESC, id DESC
+			LIMIT $2`
+		rows, err = s.reader(ctx).QueryContext(ctx, query, itemID, limit)
+	} else {
+		// 行値比較だけではパーティションプルーニングが効かないため、created_atの上限を別条件でも指定する
+		query := `
+			SELECT id, type, item_id, from_location, to_location, quantity, unit_cost, reference, lot_number, expiry_date, metadata, created_at, created_by
+			FROM transactions 
+			WHERE item_id = $1 AND created_at <= $2 AND (created_at, id) < ($2, $3)
+			ORDER BY created_at DESC, id DESC
+			LIMIT $4`
+		rows, err = s.reader(ctx).QueryContext(ctx, query, itemID, after.AfterCreatedAt, after.AfterID, limit)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("トランザクション履歴取得に失敗しました: %w", err)
 	}
+	defer rows.Close()
 
-	return transactions, nil
+	return s.scanTransactions(rows)
 }
 
 // GetTransactionHistoryByLocation retrieves transaction history for a location
@@ -300,47 +914,85 @@ func (s *PostgreSQLStorage) GetTransactionHistoryByLocation(ctx context.Context,
 		ORDER BY created_at DESC
 		LIMIT $2`
 
-	rows, err := s.db.QueryContext(ctx, query, locationID, limit)
+	rows, err := s.reader(ctx).QueryContext(ctx, query, locationID, limit)
 	if err != nil {
 		return nil, fmt.Errorf("ロケーショントランザクション履歴取得に失敗しました: %w", err)
 	}
 	defer rows.Close()
 
-	var transactions []inventory.Transaction
-	for rows.Next() {
-		var tx inventory.Transaction
-		var metadataJSON []byte
+	return s.scanTransactions(rows)
+}
 
-		err := rows.Scan(
-			&tx.ID,
-			&tx.Type,
-			&tx.ItemID,
-			&tx.FromLocation,
-			&tx.ToLocation,
-			&tx.Quantity,
-			&tx.UnitCost,
-			&tx.Reference,
-			&tx.LotNumber,
-			&tx.ExpiryDate,
-			&metadataJSON,
-			&tx.CreatedAt,
-			&tx.CreatedBy,
+// GetTransactionsByReference retrieves all transactions with the given reference number
+// 参照番号が一致するすべてのトランザクションを取得
+func (s *PostgreSQLStorage) GetTransactionsByReference(ctx context.Context, reference string) ([]inventory.Transaction, error) {
+	query := `
+		SELECT id, type, item_id, from_location, to_location, quantity, unit_cost, reference, lot_number, expiry_date, metadata, created_at, created_by
+		FROM transactions 
+		WHERE reference = $1
+		ORDER BY created_at DESC, id DESC`
+
+	rows, err := s.reader(ctx).QueryContext(ctx, query, reference)
+	if err != nil {
+		return nil, fmt.Errorf("参照番号によるトランザクション取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	return s.scanTransactions(rows)
+}
+
+// SearchTransactionsByMetadata retrieves transactions whose metadata key matches value
+// メタデータのキーと値が一致するトランザクションを取得
+//
+// JSONBの包含演算子（@>）を使用するため、metadataのGINインデックスが利用されます。
+func (s *PostgreSQLStorage) SearchTransactionsByMetadata(ctx context.Context, key, value string, limit int) ([]inventory.Transaction, error) {
+	filter, err := json.Marshal(map[string]string{key: value})
+	if err != nil {
+		return nil, fmt.Errorf("メタデータ条件のシリアライズに失敗しました: %w", err)
+	}
+
+	query := `
+		SELECT id, type, item_id, from_location, to_location, quantity, unit_cost, reference, lot_number, expiry_date, metadata, created_at, created_by
+		FROM transactions 
+		WHERE metadata @> $1::jsonb
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2`
+
+	rows, err := s.reader(ctx).QueryContext(ctx, query, string(filter), limit)
+	if err != nil {
+		return nil, fmt.Errorf("メタデータによるトランザクション検索に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	return s.scanTransactions(rows)
+}
+
+// ArchiveTransactions moves transactions created before the cutoff into the archive table
+// 指定日時より前のトランザクションをアーカイブテーブルへ移動
+//
+// 削除と挿入を1つの文で行うため、途中で失敗してもトランザクションが失われることはありません。
+func (s *PostgreSQLStorage) ArchiveTransactions(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		WITH moved AS (
+			DELETE FROM transactions
+			WHERE created_at < $1
+			RETURNING id, type, item_id, from_location, to_location, quantity, unit_cost, reference, lot_number, expiry_date, metadata, created_at, created_by
 		)
-		if err != nil {
-			return nil, fmt.Errorf("トランザクションスキャンに失敗しました: %w", err)
-		}
+		INSERT INTO transactions_archive (id, type, item_id, from_location, to_location, quantity, unit_cost, reference, lot_number, expiry_date, metadata, created_at, created_by)
+		SELECT id, type, item_id, from_location, to_location, quantity, unit_cost, reference, lot_number, expiry_date, metadata, created_at, created_by
+		FROM moved`
 
-		// メタデータのデシリアライズ
-		if len(metadataJSON) > 0 {
-			if err := json.Unmarshal(metadataJSON, &tx.Metadata); err != nil {
-				s.logger.Warn("メタデータのパースに失敗しました", zap.Error(err))
-			}
-		}
+	result, err := s.conn.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("トランザクションのアーカイブに失敗しました: %w", err)
+	}
 
-		transactions = append(transactions, tx)
+	archived, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("アーカイブ件数の取得に失敗しました: %w", err)
 	}
 
-	return transactions, nil
+	return archived, nil
 }
 
 // GetTransactionHistoryByDateRange retrieves transaction history for an item within a date range
@@ -352,63 +1004,121 @@ func (s *PostgreSQLStorage) GetTransactionHistoryByDateRange(ctx context.Context
 		WHERE item_id = $1 AND created_at >= $2 AND created_at <= $3
 		ORDER BY created_at DESC`
 
-	rows, err := s.db.QueryContext(ctx, query, itemID, from, to)
+	rows, err := s.reader(ctx).QueryContext(ctx, query, itemID, from, to)
 	if err != nil {
 		return nil, fmt.Errorf("日付範囲トランザクション履歴取得に失敗しました: %w", err)
 	}
 	defer rows.Close()
 
-	var transactions []inventory.Transaction
-	for rows.Next() {
-		var tx inventory.Transaction
-		var metadataJSON []byte
+	return s.scanTransactions(rows)
+}
 
-		err := rows.Scan(
-			&tx.ID,
-			&tx.Type,
-			&tx.ItemID,
-			&tx.FromLocation,
-			&tx.ToLocation,
-			&tx.Quantity,
-			&tx.UnitCost,
-			&tx.Reference,
-			&tx.LotNumber,
-			&tx.ExpiryDate,
-			&metadataJSON,
-			&tx.CreatedAt,
-			&tx.CreatedBy,
-		)
+// ForEachTransactionInRange streams transactions created in [from, to) to fn in chronological order
+// from以上to未満に作成されたトランザクションを古い順に1件ずつfnに渡す
+func (s *PostgreSQLStorage) ForEachTransactionInRange(ctx context.Context, from, to time.Time, fn func(tx inventory.Transaction) error) error {
+	query := `
+		SELECT id, type, item_id, from_location, to_location, quantity, unit_cost, reference, lot_number, expiry_date, metadata, created_at, created_by
+		FROM transactions
+		WHERE created_at >= $1 AND ($2::timestamp IS NULL OR created_at < $2)
+		ORDER BY created_at, id`
+
+	var until interface{}
+	if !to.IsZero() {
+		until = to
+	}
+
+	rows, err := s.reader(ctx).QueryContext(ctx, query, from, until)
+	if err != nil {
+		return fmt.Errorf("期間指定トランザクション取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		tx, err := s.scanTransaction(rows)
 		if err != nil {
-			return nil, fmt.Errorf("トランザクションスキャンに失敗しました: %w", err)
+			return err
 		}
-
-		// メタデータのデシリアライズ
-		if len(metadataJSON) > 0 {
-			if err := json.Unmarshal(metadataJSON, &tx.Metadata); err != nil {
-				s.logger.Warn("メタデータのパースに失敗しました", zap.Error(err))
-			}
+		if err := fn(tx); err != nil {
+			return err
 		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("トランザクション読み取りに失敗しました: %w", err)
+	}
+
+	return nil
+}
 
+// scanTransactions reads all transaction rows from a query result
+// クエリ結果からトランザクション行をすべて読み取る
+func (s *PostgreSQLStorage) scanTransactions(rows *sql.Rows) ([]inventory.Transaction, error) {
+	var transactions []inventory.Transaction
+	for rows.Next() {
+		tx, err := s.scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
 		transactions = append(transactions, tx)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("トランザクション読み取りに失敗しました: %w", err)
+	}
+
 	return transactions, nil
 }
 
+// scanTransaction reads the current transaction row
+// 現在のトランザクション行を読み取る
+func (s *PostgreSQLStorage) scanTransaction(rows *sql.Rows) (inventory.Transaction, error) {
+	var tx inventory.Transaction
+	var metadataJSON []byte
+
+	err := rows.Scan(
+		&tx.ID,
+		&tx.Type,
+		&tx.ItemID,
+		&tx.FromLocation,
+		&tx.ToLocation,
+		&tx.Quantity,
+		&tx.UnitCost,
+		&tx.Reference,
+		&tx.LotNumber,
+		&tx.ExpiryDate,
+		&metadataJSON,
+		&tx.CreatedAt,
+		&tx.CreatedBy,
+	)
+	if err != nil {
+		return tx, fmt.Errorf("トランザクションスキャンに失敗しました: %w", err)
+	}
+
+	// メタデータのデシリアライズ
+	if len(metadataJSON) > 0 {
+		if err := json.Unmarshal(metadataJSON, &tx.Metadata); err != nil {
+			s.logger.Warn("メタデータのパースに失敗しました", zap.Error(err))
+		}
+	}
+
+	return tx, nil
+}
+
 // CreateItem creates a new item
 // 新しい商品を作成
 func (s *PostgreSQLStorage) CreateItem(ctx context.Context, item *inventory.Item) error {
 	query := `
-		INSERT INTO items (id, name, sku, description, category, unit_cost, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+		INSERT INTO items (id, name, sku, description, category, unit_cost, base_uom, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, COALESCE(NULLIF($7, ''), 'each'), $8, $9)`
 
-	_, err := s.db.ExecContext(ctx, query,
+	_, err := s.conn.ExecContext(ctx, query,
 		item.ID,
 		item.Name,
 		item.SKU,
 		item.Description,
 		item.Category,
 		item.UnitCost,
+		item.BaseUoM,
 		item.CreatedAt,
 		item.UpdatedAt,
 	)
@@ -427,18 +1137,19 @@ func (s *PostgreSQLStorage) CreateItem(ctx context.Context, item *inventory.Item
 // IDで商品を取得
 func (s *PostgreSQLStorage) GetItem(ctx context.Context, itemID string) (*inventory.Item, error) {
 	query := `
-		SELECT id, name, sku, description, category, unit_cost, created_at, updated_at
+		SELECT id, name, sku, description, category, unit_cost, base_uom, created_at, updated_at
 		FROM items 
 		WHERE id = $1`
 
 	item := &inventory.Item{}
-	err := s.db.QueryRowContext(ctx, query, itemID).Scan(
+	err := s.conn.QueryRowContext(ctx, query, itemID).Scan(
 		&item.ID,
 		&item.Name,
 		&item.SKU,
 		&item.Description,
 		&item.Category,
 		&item.UnitCost,
+		&item.BaseUoM,
 		&item.CreatedAt,
 		&item.UpdatedAt,
 	)
@@ -455,20 +1166,25 @@ func (s *PostgreSQLStorage) GetItem(ctx context.Context, itemID string) (*invent
 
 // UpdateItem updates an existing item
 // 既存の商品を更新
+//
+// inventory.WithExpectedUpdatedAt のコンテキストでは、更新日時が一致する場合のみ更新します。
 func (s *PostgreSQLStorage) UpdateItem(ctx context.Context, item *inventory.Item) error {
 	query := `
 		UPDATE items 
-		SET name = $2, sku = $3, description = $4, category = $5, unit_cost = $6, updated_at = $7
-		WHERE id = $1`
+		SET name = $2, sku = $3, description = $4, category = $5, unit_cost = $6,
+			base_uom = COALESCE(NULLIF($7, ''), 'each'), updated_at = $8
+		WHERE id = $1 AND ($9::timestamptz IS NULL OR updated_at = $9)`
 
-	result, err := s.db.ExecContext(ctx, query,
+	result, err := s.conn.ExecContext(ctx, query,
 		item.ID,
 		item.Name,
 		item.SKU,
 		item.Description,
 		item.Category,
 		item.UnitCost,
+		item.BaseUoM,
 		item.UpdatedAt,
+		expectedUpdatedAtArg(ctx),
 	)
 
 	if err != nil {
@@ -481,7 +1197,7 @@ func (s *PostgreSQLStorage) UpdateItem(ctx context.Context, item *inventory.Item
 	}
 
 	if rowsAffected == 0 {
-		return inventory.ErrItemNotFound
+		return s.unmatchedUpdateError(ctx, "items", item.ID, inventory.ErrItemNotFound)
 	}
 
 	return nil
@@ -492,8 +1208,12 @@ func (s *PostgreSQLStorage) UpdateItem(ctx context.Context, item *inventory.Item
 func (s *PostgreSQLStorage) DeleteItem(ctx context.Context, itemID string) error {
 	query := `DELETE FROM items WHERE id = $1`
 
-	result, err := s.db.ExecContext(ctx, query, itemID)
+	result, err := s.conn.ExecContext(ctx, query, itemID)
 	if err != nil {
+		// 部品表の構成品の外部キー（ON DELETE RESTRICT）に違反する場合は使用中
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return inventory.ErrItemInUse
+		}
 		return fmt.Errorf("商品削除に失敗しました: %w", err)
 	}
 
@@ -513,17 +1233,95 @@ func (s *PostgreSQLStorage) DeleteItem(ctx context.Context, itemID string) error
 // ページネーション付きで商品一覧を取得
 func (s *PostgreSQLStorage) ListItems(ctx context.Context, offset, limit int) ([]inventory.Item, error) {
 	query := `
-		SELECT id, name, sku, description, category, unit_cost, created_at, updated_at
+		SELECT id, name, sku, description, category, unit_cost, base_uom, created_at, updated_at
 		FROM items 
 		ORDER BY created_at DESC
 		OFFSET $1 LIMIT $2`
 
-	rows, err := s.db.QueryContext(ctx, query, offset, limit)
+	rows, err := s.conn.QueryContext(ctx, query, offset, limit)
 	if err != nil {
 		return nil, fmt.Errorf("商品一覧取得に失敗しました: %w", err)
 	}
 	defer rows.Close()
 
+	return scanItems(rows)
+}
+
+// ListItemsAfter retrieves items matching a filter after the cursor
+// 条件に一致する商品をカーソルより後から取得（キーセットページネーション）
+func (s *PostgreSQLStorage) ListItemsAfter(ctx context.Context, filter inventory.ItemFilter, after *inventory.PageCursor, limit int) ([]inventory.Item, error) {
+	var conditions []string
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.Category != "" {
+		conditions = append(conditions, "category = "+arg(filter.Category))
+	}
+	if filter.SKUPrefix != "" {
+		// idx_items_sku_pattern を使用する前方一致
+		conditions = append(conditions, "sku LIKE "+arg(escapeLike(filter.SKUPrefix)+"%"))
+	}
+	if filter.MinCost != nil {
+		conditions = append(conditions, "unit_cost >= "+arg(*filter.MinCost))
+	}
+	if filter.MaxCost != nil {
+		conditions = append(conditions, "unit_cost <= "+arg(*filter.MaxCost))
+	}
+
+	// 並び替えの項目は定数から選ぶため、列名を直接埋め込んでも安全
+	column := "created_at"
+	switch filter.Sort() {
+	case inventory.ItemSortName:
+		column = "name"
+	case inventory.ItemSortSKU:
+		column = "sku"
+	case inventory.ItemSortUnitCost:
+		column = "unit_cost"
+	}
+	direction, operator := "DESC", "<"
+	if filter.Ascending {
+		direction, operator = "ASC", ">"
+	}
+	if after != nil {
+		var value interface{} = after.AfterCreatedAt
+		if after.AfterValue != nil {
+			value = after.AfterValue
+		}
+		conditions = append(conditions, fmt.Sprintf("(%s, id) %s (%s, %s)", column, operator, arg(value), arg(after.AfterID)))
+	}
+
+	query := `
+		SELECT id, name, sku, description, category, unit_cost, base_uom, created_at, updated_at
+		FROM items`
+	if len(conditions) > 0 {
+		query += `
+		WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += fmt.Sprintf(`
+		ORDER BY %s %s, id %s
+		LIMIT %s`, column, direction, direction, arg(limit))
+
+	rows, err := s.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("商品一覧取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	return scanItems(rows)
+}
+
+// escapeLike escapes the wildcard characters of a LIKE pattern
+// LIKEパターンのワイルドカード文字をエスケープ
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// scanItems scans item rows
+// 商品の行を読み取る
+func scanItems(rows *sql.Rows) ([]inventory.Item, error) {
 	var items []inventory.Item
 	for rows.Next() {
 		var item inventory.Item
@@ -534,6 +1332,7 @@ func (s *PostgreSQLStorage) ListItems(ctx context.Context, offset, limit int) ([
 			&item.Description,
 			&item.Category,
 			&item.UnitCost,
+			&item.BaseUoM,
 			&item.CreatedAt,
 			&item.UpdatedAt,
 		)
@@ -542,6 +1341,9 @@ func (s *PostgreSQLStorage) ListItems(ctx context.Context, offset, limit int) ([
 		}
 		items = append(items, item)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("商品スキャンに失敗しました: %w", err)
+	}
 
 	return items, nil
 }
@@ -550,13 +1352,13 @@ func (s *PostgreSQLStorage) ListItems(ctx context.Context, offset, limit int) ([
 // クエリ文字列で商品を検索
 func (s *PostgreSQLStorage) SearchItems(ctx context.Context, query string) ([]inventory.Item, error) {
 	sqlQuery := `
-		SELECT id, name, sku, description, category, unit_cost, created_at, updated_at
+		SELECT id, name, sku, description, category, unit_cost, base_uom, created_at, updated_at
 		FROM items 
 		WHERE name ILIKE $1 OR sku ILIKE $1 OR description ILIKE $1 OR category ILIKE $1
 		ORDER BY name`
 
 	searchPattern := "%" + query + "%"
-	rows, err := s.db.QueryContext(ctx, sqlQuery, searchPattern)
+	rows, err := s.conn.QueryContext(ctx, sqlQuery, searchPattern)
 	if err != nil {
 		return nil, fmt.Errorf("商品検索に失敗しました: %w", err)
 	}
@@ -572,6 +1374,7 @@ func (s *PostgreSQLStorage) SearchItems(ctx context.Context, query string) ([]in
 			&item.Description,
 			&item.Category,
 			&item.UnitCost,
+			&item.BaseUoM,
 			&item.CreatedAt,
 			&item.UpdatedAt,
 		)
@@ -588,16 +1391,19 @@ func (s *PostgreSQLStorage) SearchItems(ctx context.Context, query string) ([]in
 // 新しいロケーションを作成
 func (s *PostgreSQLStorage) CreateLocation(ctx context.Context, location *inventory.Location) error {
 	query := `
-		INSERT INTO locations (id, name, type, address, capacity, is_active, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+		INSERT INTO locations (id, name, type, address, capacity, is_active, priority, latitude, longitude, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
 
-	_, err := s.db.ExecContext(ctx, query,
+	_, err := s.conn.ExecContext(ctx, query,
 		location.ID,
 		location.Name,
 		location.Type,
 		location.Address,
 		location.Capacity,
 		location.IsActive,
+		location.Priority,
+		location.Latitude,
+		location.Longitude,
 		location.CreatedAt,
 		location.UpdatedAt,
 	)
@@ -616,18 +1422,21 @@ func (s *PostgreSQLStorage) CreateLocation(ctx context.Context, location *invent
 // IDでロケーションを取得
 func (s *PostgreSQLStorage) GetLocation(ctx context.Context, locationID string) (*inventory.Location, error) {
 	query := `
-		SELECT id, name, type, address, capacity, is_active, created_at, updated_at
+		SELECT id, name, type, address, capacity, is_active, priority, latitude, longitude, created_at, updated_at
 		FROM locations 
 		WHERE id = $1`
 
 	location := &inventory.Location{}
-	err := s.db.QueryRowContext(ctx, query, locationID).Scan(
+	err := s.conn.QueryRowContext(ctx, query, locationID).Scan(
 		&location.ID,
 		&location.Name,
 		&location.Type,
 		&location.Address,
 		&location.Capacity,
 		&location.IsActive,
+		&location.Priority,
+		&location.Latitude,
+		&location.Longitude,
 		&location.CreatedAt,
 		&location.UpdatedAt,
 	)
@@ -644,20 +1453,26 @@ func (s *PostgreSQLStorage) GetLocation(ctx context.Context, locationID string)
 
 // UpdateLocation updates an existing location
 // 既存のロケーションを更新
+//
+// inventory.WithExpectedUpdatedAt のコンテキストでは、更新日時が一致する場合のみ更新します。
 func (s *PostgreSQLStorage) UpdateLocation(ctx context.Context, location *inventory.Location) error {
 	query := `
 		UPDATE locations 
-		SET name = $2, type = $3, address = $4, capacity = $5, is_active = $6, updated_at = $7
-		WHERE id = $1`
+		SET name = $2, type = $3, address = $4, capacity = $5, is_active = $6, priority = $7, latitude = $8, longitude = $9, updated_at = $10
+		WHERE id = $1 AND ($11::timestamptz IS NULL OR updated_at = $11)`
 
-	result, err := s.db.ExecContext(ctx, query,
+	result, err := s.conn.ExecContext(ctx, query,
 		location.ID,
 		location.Name,
 		location.Type,
 		location.Address,
 		location.Capacity,
 		location.IsActive,
+		location.Priority,
+		location.Latitude,
+		location.Longitude,
 		location.UpdatedAt,
+		expectedUpdatedAtArg(ctx),
 	)
 
 	if err != nil {
@@ -670,18 +1485,44 @@ func (s *PostgreSQLStorage) UpdateLocation(ctx context.Context, location *invent
 	}
 
 	if rowsAffected == 0 {
-		return inventory.ErrLocationNotFound
+		return s.unmatchedUpdateError(ctx, "locations", location.ID, inventory.ErrLocationNotFound)
 	}
 
 	return nil
 }
 
+// expectedUpdatedAtArg returns the update time expected by the context as a query argument (NULL when the update is unconditional)
+// コンテキストが想定する更新日時をクエリの引数として返す（条件なしの更新ではNULL）
+func expectedUpdatedAtArg(ctx context.Context) sql.NullTime {
+	updatedAt, ok := inventory.ExpectedUpdatedAt(ctx)
+	return sql.NullTime{Time: updatedAt, Valid: ok}
+}
+
+// unmatchedUpdateError returns the error of a conditional update that matched no rows
+// 条件付きの更新で対象の行がなかった場合のエラーを返す
+//
+// 更新日時を想定した更新で行が存在する場合は他の更新が先に行われたため ErrPreconditionFailed、
+// それ以外は notFound を返します。
+func (s *PostgreSQLStorage) unmatchedUpdateError(ctx context.Context, table, id string, notFound error) error {
+	if _, ok := inventory.ExpectedUpdatedAt(ctx); !ok {
+		return notFound
+	}
+	var exists bool
+	if err := s.conn.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM `+table+` WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return fmt.Errorf("存在確認に失敗しました: %w", err)
+	}
+	if exists {
+		return inventory.ErrPreconditionFailed
+	}
+	return notFound
+}
+
 // DeleteLocation deletes a location by ID
 // IDでロケーションを削除
 func (s *PostgreSQLStorage) DeleteLocation(ctx context.Context, locationID string) error {
 	query := `DELETE FROM locations WHERE id = $1`
 
-	result, err := s.db.ExecContext(ctx, query, locationID)
+	result, err := s.conn.ExecContext(ctx, query, locationID)
 	if err != nil {
 		return fmt.Errorf("ロケーション削除に失敗しました: %w", err)
 	}
@@ -702,17 +1543,53 @@ func (s *PostgreSQLStorage) DeleteLocation(ctx context.Context, locationID strin
 // ページネーション付きでロケーション一覧を取得
 func (s *PostgreSQLStorage) ListLocations(ctx context.Context, offset, limit int) ([]inventory.Location, error) {
 	query := `
-		SELECT id, name, type, address, capacity, is_active, created_at, updated_at
+		SELECT id, name, type, address, capacity, is_active, priority, latitude, longitude, created_at, updated_at
 		FROM locations 
 		ORDER BY created_at DESC
 		OFFSET $1 LIMIT $2`
 
-	rows, err := s.db.QueryContext(ctx, query, offset, limit)
+	rows, err := s.conn.QueryContext(ctx, query, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ロケーション一覧取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	return scanLocations(rows)
+}
+
+// ListLocationsAfter retrieves locations older than the cursor
+// カーソルより古いロケーションを取得（キーセットページネーション）
+func (s *PostgreSQLStorage) ListLocationsAfter(ctx context.Context, after *inventory.PageCursor, limit int) ([]inventory.Location, error) {
+	var rows *sql.Rows
+	var err error
+
+	if after == nil {
+		query := `
+			SELECT id, name, type, address, capacity, is_active, priority, latitude, longitude, created_at, updated_at
+			FROM locations
+			ORDER BY created_at DESC, id DESC
+			LIMIT $1`
+		rows, err = s.conn.QueryContext(ctx, query, limit)
+	} else {
+		query := `
+			SELECT id, name, type, address, capacity, is_active, priority, latitude, longitude, created_at, updated_at
+			FROM locations
+			WHERE (created_at, id) < ($1, $2)
+			ORDER BY created_at DESC, id DESC
+			LIMIT $3`
+		rows, err = s.conn.QueryContext(ctx, query, after.AfterCreatedAt, after.AfterID, limit)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("ロケーション一覧取得に失敗しました: %w", err)
 	}
 	defer rows.Close()
 
+	return scanLocations(rows)
+}
+
+// scanLocations scans location rows
+// ロケーションの行を読み取る
+func scanLocations(rows *sql.Rows) ([]inventory.Location, error) {
 	var locations []inventory.Location
 	for rows.Next() {
 		var location inventory.Location
@@ -723,6 +1600,9 @@ func (s *PostgreSQLStorage) ListLocations(ctx context.Context, offset, limit int
 			&location.Address,
 			&location.Capacity,
 			&location.IsActive,
+			&location.Priority,
+			&location.Latitude,
+			&location.Longitude,
 			&location.CreatedAt,
 			&location.UpdatedAt,
 		)
@@ -731,272 +1611,3970 @@ func (s *PostgreSQLStorage) ListLocations(ctx context.Context, offset, limit int
 		}
 		locations = append(locations, location)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ロケーションスキャンに失敗しました: %w", err)
+	}
 
 	return locations, nil
 }
 
-// CreateLot creates a new lot record
-// 新しいロット記録を作成
-func (s *PostgreSQLStorage) CreateLot(ctx context.Context, lot *inventory.Lot) error {
+// CreateUnit registers a new unit of measure
+// 新しい単位を登録
+func (s *PostgreSQLStorage) CreateUnit(ctx context.Context, unit *inventory.UnitOfMeasure) error {
 	query := `
-		INSERT INTO lots (id, number, item_id, quantity, unit_cost, expiry_date, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)`
-
-	_, err := s.db.ExecContext(ctx, query,
-		lot.ID,
-		lot.Number,
-		lot.ItemID,
-		lot.Quantity,
-		lot.UnitCost,
-		lot.ExpiryDate,
-		lot.CreatedAt,
-	)
+		INSERT INTO units_of_measure (code, name, decimals, created_at)
+		VALUES ($1, $2, $3, $4)`
 
-	if err != nil {
-		return fmt.Errorf("ロット作成に失敗しました: %w", err)
+	if _, err := s.conn.ExecContext(ctx, query, unit.Code, unit.Name, unit.Decimals, unit.CreatedAt); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return inventory.ErrDuplicateUnit
+		}
+		return fmt.Errorf("単位の登録に失敗しました: %w", err)
 	}
-
 	return nil
 }
 
-// GetLot retrieves a lot by ID
-// IDでロットを取得
-func (s *PostgreSQLStorage) GetLot(ctx context.Context, lotID string) (*inventory.Lot, error) {
+// GetUnit retrieves a unit of measure by code
+// コードで単位を取得
+func (s *PostgreSQLStorage) GetUnit(ctx context.Context, code string) (*inventory.UnitOfMeasure, error) {
 	query := `
-		SELECT id, number, item_id, quantity, unit_cost, expiry_date, created_at
-		FROM lots 
-		WHERE id = $1`
-
-	lot := &inventory.Lot{}
-	err := s.db.QueryRowContext(ctx, query, lotID).Scan(
-		&lot.ID,
-		&lot.Number,
-		&lot.ItemID,
-		&lot.Quantity,
-		&lot.UnitCost,
-		&lot.ExpiryDate,
-		&lot.CreatedAt,
-	)
+		SELECT code, name, decimals, created_at
+		FROM units_of_measure
+		WHERE code = $1`
 
+	unit := &inventory.UnitOfMeasure{}
+	err := s.reader(ctx).QueryRowContext(ctx, query, code).Scan(&unit.Code, &unit.Name, &unit.Decimals, &unit.CreatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, inventory.ErrLotNotFound
+			return nil, inventory.ErrUnitNotFound
 		}
-		return nil, fmt.Errorf("ロット取得に失敗しました: %w", err)
+		return nil, fmt.Errorf("単位の取得に失敗しました: %w", err)
 	}
-
-	return lot, nil
+	return unit, nil
 }
 
-// GetLotsByItem retrieves all lots for a specific item
-// 指定商品のすべてのロットを取得
-func (s *PostgreSQLStorage) GetLotsByItem(ctx context.Context, itemID string) ([]inventory.Lot, error) {
+// ListUnits lists all units of measure ordered by code
+// 全ての単位をコード順に取得
+func (s *PostgreSQLStorage) ListUnits(ctx context.Context) ([]inventory.UnitOfMeasure, error) {
 	query := `
-		SELECT id, number, item_id, quantity, unit_cost, expiry_date, created_at
-		FROM lots 
-		WHERE item_id = $1
-		ORDER BY created_at DESC`
+		SELECT code, name, decimals, created_at
+		FROM units_of_measure
+		ORDER BY code`
 
-	rows, err := s.db.QueryContext(ctx, query, itemID)
+	rows, err := s.reader(ctx).QueryContext(ctx, query)
 	if err != nil {
-		return nil, fmt.Errorf("商品ロット取得に失敗しました: %w", err)
+		return nil, fmt.Errorf("単位一覧の取得に失敗しました: %w", err)
 	}
 	defer rows.Close()
 
-	var lots []inventory.Lot
+	units := make([]inventory.UnitOfMeasure, 0)
 	for rows.Next() {
-		var lot inventory.Lot
-		err := rows.Scan(
-			&lot.ID,
-			&lot.Number,
-			&lot.ItemID,
-			&lot.Quantity,
-			&lot.UnitCost,
-			&lot.ExpiryDate,
-			&lot.CreatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("ロットスキャンに失敗しました: %w", err)
+		var unit inventory.UnitOfMeasure
+		if err := rows.Scan(&unit.Code, &unit.Name, &unit.Decimals, &unit.CreatedAt); err != nil {
+			return nil, fmt.Errorf("単位スキャンに失敗しました: %w", err)
 		}
-		lots = append(lots, lot)
+		units = append(units, unit)
 	}
-
-	return lots, nil
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("単位スキャンに失敗しました: %w", err)
+	}
+	return units, nil
 }
 
-// GetExpiringLots retrieves lots that are expiring within the specified duration
-// 指定期間内に期限切れになるロットを取得
-func (s *PostgreSQLStorage) GetExpiringLots(ctx context.Context, within time.Duration) ([]inventory.Lot, error) {
-	expiryThreshold := time.Now().Add(within)
-	query := `
-		SELECT id, number, item_id, quantity, unit_cost, expiry_date, created_at
-		FROM lots 
-		WHERE expiry_date IS NOT NULL AND expiry_date <= $1
-		ORDER BY expiry_date ASC`
-
-	rows, err := s.db.QueryContext(ctx, query, expiryThreshold)
+// DeleteUnit deletes a unit of measure that no item or conversion uses
+// 商品の基本単位や換算で使用されていない単位を削除
+func (s *PostgreSQLStorage) DeleteUnit(ctx context.Context, code string) error {
+	result, err := s.conn.ExecContext(ctx, `DELETE FROM units_of_measure WHERE code = $1`, code)
 	if err != nil {
-		return nil, fmt.Errorf("期限切れ間近ロット取得に失敗しました: %w", err)
+		// 商品・換算の外部キー（ON DELETE RESTRICT）に違反する場合は使用中
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return inventory.ErrUnitInUse
+		}
+		return fmt.Errorf("単位の削除に失敗しました: %w", err)
 	}
-	defer rows.Close()
 
-	var lots []inventory.Lot
-	for rows.Next() {
-		var lot inventory.Lot
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("削除行数の取得に失敗しました: %w", err)
+	}
+	if rowsAffected == 0 {
+		return inventory.ErrUnitNotFound
+	}
+	return nil
+}
+
+// SetUnitConversion registers or updates the conversion factor of an item's unit of measure
+// 商品の単位の換算係数を登録（登録済みの場合は更新）
+func (s *PostgreSQLStorage) SetUnitConversion(ctx context.Context, conversion *inventory.UnitConversion) error {
+	query := `
+		INSERT INTO item_unit_conversions (item_id, uom, factor, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (item_id, uom) DO UPDATE
+		SET factor = EXCLUDED.factor, updated_at = EXCLUDED.updated_at`
+
+	_, err := s.conn.ExecContext(ctx, query, conversion.ItemID, conversion.UoM, conversion.Factor, conversion.UpdatedAt)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			if pqErr.Constraint == "item_unit_conversions_item_id_fkey" {
+				return inventory.ErrItemNotFound
+			}
+			return inventory.ErrUnitNotFound
+		}
+		return fmt.Errorf("単位の換算の登録に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// GetUnitConversion retrieves the conversion of an item's unit of measure
+// 商品の単位の換算を取得
+func (s *PostgreSQLStorage) GetUnitConversion(ctx context.Context, itemID, uom string) (*inventory.UnitConversion, error) {
+	query := `
+		SELECT item_id, uom, factor, updated_at
+		FROM item_unit_conversions
+		WHERE item_id = $1 AND uom = $2`
+
+	conversion := &inventory.UnitConversion{}
+	err := s.conn.QueryRowContext(ctx, query, itemID, uom).Scan(&conversion.ItemID, &conversion.UoM, &conversion.Factor, &conversion.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrUnitConversionNotFound
+		}
+		return nil, fmt.Errorf("単位の換算の取得に失敗しました: %w", err)
+	}
+	return conversion, nil
+}
+
+// ListUnitConversions lists the unit conversions of an item ordered by unit of measure
+// 商品の単位の換算を単位コード順に取得
+func (s *PostgreSQLStorage) ListUnitConversions(ctx context.Context, itemID string) ([]inventory.UnitConversion, error) {
+	query := `
+		SELECT item_id, uom, factor, updated_at
+		FROM item_unit_conversions
+		WHERE item_id = $1
+		ORDER BY uom`
+
+	rows, err := s.reader(ctx).QueryContext(ctx, query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("単位の換算一覧の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	conversions := make([]inventory.UnitConversion, 0)
+	for rows.Next() {
+		var conversion inventory.UnitConversion
+		if err := rows.Scan(&conversion.ItemID, &conversion.UoM, &conversion.Factor, &conversion.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("単位の換算スキャンに失敗しました: %w", err)
+		}
+		conversions = append(conversions, conversion)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("単位の換算スキャンに失敗しました: %w", err)
+	}
+	return conversions, nil
+}
+
+// DeleteUnitConversion deletes the conversion of an item's unit of measure
+// 商品の単位の換算を削除
+func (s *PostgreSQLStorage) DeleteUnitConversion(ctx context.Context, itemID, uom string) error {
+	result, err := s.conn.ExecContext(ctx, `DELETE FROM item_unit_conversions WHERE item_id = $1 AND uom = $2`, itemID, uom)
+	if err != nil {
+		return fmt.Errorf("単位の換算の削除に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("削除行数の取得に失敗しました: %w", err)
+	}
+	if rowsAffected == 0 {
+		return inventory.ErrUnitConversionNotFound
+	}
+	return nil
+}
+
+// SetBillOfMaterials registers or replaces the bill of materials of a kit in a single transaction
+// キットの部品表を単一トランザクションで登録（登録済みの場合は構成品を置換）
+func (s *PostgreSQLStorage) SetBillOfMaterials(ctx context.Context, bom *inventory.BillOfMaterials) error {
+	return s.WithinTx(ctx, func(txStorage inventory.Storage) error {
+		conn := txStorage.(*PostgreSQLStorage).conn
+
+		query := `
+			INSERT INTO bills_of_materials (kit_item_id, updated_at)
+			VALUES ($1, $2)
+			ON CONFLICT (kit_item_id) DO UPDATE SET updated_at = EXCLUDED.updated_at`
+		if _, err := conn.ExecContext(ctx, query, bom.KitItemID, bom.UpdatedAt); err != nil {
+			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+				return inventory.ErrItemNotFound
+			}
+			return fmt.Errorf("部品表の登録に失敗しました: %w", err)
+		}
+
+		if _, err := conn.ExecContext(ctx, `DELETE FROM bom_components WHERE kit_item_id = $1`, bom.KitItemID); err != nil {
+			return fmt.Errorf("部品表の構成品の削除に失敗しました: %w", err)
+		}
+		for _, component := range bom.Components {
+			_, err := conn.ExecContext(ctx,
+				`INSERT INTO bom_components (kit_item_id, component_item_id, quantity) VALUES ($1, $2, $3)`,
+				bom.KitItemID, component.ItemID, component.Quantity)
+			if err != nil {
+				if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+					return inventory.ErrItemNotFound
+				}
+				return fmt.Errorf("部品表の構成品の登録に失敗しました: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetBillOfMaterials retrieves the bill of materials of a kit with its components ordered by item ID
+// キットの部品表を構成品（商品IDの昇順）とともに取得
+func (s *PostgreSQLStorage) GetBillOfMaterials(ctx context.Context, kitItemID string) (*inventory.BillOfMaterials, error) {
+	db := s.reader(ctx)
+
+	bom := &inventory.BillOfMaterials{}
+	err := db.QueryRowContext(ctx, `SELECT kit_item_id, updated_at FROM bills_of_materials WHERE kit_item_id = $1`, kitItemID).
+		Scan(&bom.KitItemID, &bom.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrBOMNotFound
+		}
+		return nil, fmt.Errorf("部品表の取得に失敗しました: %w", err)
+	}
+
+	components, err := loadBOMComponents(ctx, db, []string{kitItemID})
+	if err != nil {
+		return nil, err
+	}
+	bom.Components = components[kitItemID]
+	return bom, nil
+}
+
+// ListBillsOfMaterials lists bills of materials ordered by kit item ID
+// 部品表一覧をキットの商品ID順に取得
+func (s *PostgreSQLStorage) ListBillsOfMaterials(ctx context.Context, offset, limit int) ([]inventory.BillOfMaterials, error) {
+	db := s.reader(ctx)
+
+	query := `
+		SELECT kit_item_id, updated_at
+		FROM bills_of_materials
+		ORDER BY kit_item_id
+		OFFSET $1 LIMIT $2`
+
+	rows, err := db.QueryContext(ctx, query, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("部品表一覧の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	boms := make([]inventory.BillOfMaterials, 0)
+	kitItemIDs := make([]string, 0)
+	for rows.Next() {
+		var bom inventory.BillOfMaterials
+		if err := rows.Scan(&bom.KitItemID, &bom.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("部品表スキャンに失敗しました: %w", err)
+		}
+		boms = append(boms, bom)
+		kitItemIDs = append(kitItemIDs, bom.KitItemID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("部品表スキャンに失敗しました: %w", err)
+	}
+	if len(boms) == 0 {
+		return boms, nil
+	}
+
+	components, err := loadBOMComponents(ctx, db, kitItemIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range boms {
+		boms[i].Components = components[boms[i].KitItemID]
+	}
+	return boms, nil
+}
+
+// DeleteBillOfMaterials deletes the bill of materials of a kit and its components
+// キットの部品表を構成品とともに削除
+func (s *PostgreSQLStorage) DeleteBillOfMaterials(ctx context.Context, kitItemID string) error {
+	result, err := s.conn.ExecContext(ctx, `DELETE FROM bills_of_materials WHERE kit_item_id = $1`, kitItemID)
+	if err != nil {
+		return fmt.Errorf("部品表の削除に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("削除行数の取得に失敗しました: %w", err)
+	}
+	if rowsAffected == 0 {
+		return inventory.ErrBOMNotFound
+	}
+	return nil
+}
+
+// loadBOMComponents loads the components of kits keyed by kit item ID, ordered by component item ID
+// キットごとの構成品を構成品の商品IDの昇順で取得
+func loadBOMComponents(ctx context.Context, db querier, kitItemIDs []string) (map[string][]inventory.BOMComponent, error) {
+	query := `
+		SELECT kit_item_id, component_item_id, quantity
+		FROM bom_components
+		WHERE kit_item_id = ANY($1)
+		ORDER BY kit_item_id, component_item_id`
+
+	rows, err := db.QueryContext(ctx, query, pq.Array(kitItemIDs))
+	if err != nil {
+		return nil, fmt.Errorf("部品表の構成品の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	components := make(map[string][]inventory.BOMComponent, len(kitItemIDs))
+	for _, kitItemID := range kitItemIDs {
+		components[kitItemID] = make([]inventory.BOMComponent, 0)
+	}
+	for rows.Next() {
+		var kitItemID string
+		var component inventory.BOMComponent
+		if err := rows.Scan(&kitItemID, &component.ItemID, &component.Quantity); err != nil {
+			return nil, fmt.Errorf("部品表の構成品スキャンに失敗しました: %w", err)
+		}
+		components[kitItemID] = append(components[kitItemID], component)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("部品表の構成品スキャンに失敗しました: %w", err)
+	}
+	return components, nil
+}
+
+// assemblyColumns are the columns of the assemblies table in the order scanAssembly reads them
+// scanAssembly が読み取る順序の assemblies テーブルの列
+const assemblyColumns = `id, type, kit_item_id, location_id, quantity, reference, components, created_at, created_by`
+
+// CreateAssembly records a kit assembly or disassembly
+// キットの組立・分解を記録
+//
+// 構成品ごとの合計数量はJSONBで保存します。
+func (s *PostgreSQLStorage) CreateAssembly(ctx context.Context, assembly *inventory.Assembly) error {
+	components, err := json.Marshal(assembly.Components)
+	if err != nil {
+		return fmt.Errorf("組立のJSON変換に失敗しました: %w", err)
+	}
+
+	query := `
+		INSERT INTO assemblies (` + assemblyColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7::jsonb, $8, $9)`
+	_, err = s.conn.ExecContext(ctx, query,
+		assembly.ID,
+		assembly.Type,
+		assembly.KitItemID,
+		assembly.LocationID,
+		assembly.Quantity,
+		assembly.Reference,
+		string(components),
+		assembly.CreatedAt,
+		assembly.CreatedBy,
+	)
+	if err != nil {
+		return fmt.Errorf("組立の記録に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// GetAssembly retrieves a kit assembly or disassembly by ID
+// IDでキットの組立・分解を取得
+func (s *PostgreSQLStorage) GetAssembly(ctx context.Context, assemblyID string) (*inventory.Assembly, error) {
+	assembly := &inventory.Assembly{}
+	err := scanAssembly(s.reader(ctx).QueryRowContext(ctx, `SELECT `+assemblyColumns+` FROM assemblies WHERE id = $1`, assemblyID), assembly)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrAssemblyNotFound
+		}
+		return nil, fmt.Errorf("組立の取得に失敗しました: %w", err)
+	}
+	return assembly, nil
+}
+
+// ListAssemblies lists kit assemblies and disassemblies matching a filter, newest first
+// 条件に一致するキットの組立・分解を作成日時の新しい順に取得
+func (s *PostgreSQLStorage) ListAssemblies(ctx context.Context, filter inventory.AssemblyFilter) ([]inventory.Assembly, error) {
+	query := `
+		SELECT ` + assemblyColumns + `
+		FROM assemblies
+		WHERE ($1 = '' OR kit_item_id = $1) AND ($2 = '' OR location_id = $2)
+		ORDER BY created_at DESC, id DESC
+		OFFSET $3`
+	args := []interface{}{filter.KitItemID, filter.LocationID, filter.Offset}
+	if filter.Limit > 0 {
+		query += ` LIMIT $4`
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.reader(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("組立一覧の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	assemblies := make([]inventory.Assembly, 0)
+	for rows.Next() {
+		var assembly inventory.Assembly
+		if err := scanAssembly(rows, &assembly); err != nil {
+			return nil, fmt.Errorf("組立スキャンに失敗しました: %w", err)
+		}
+		assemblies = append(assemblies, assembly)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("組立スキャンに失敗しました: %w", err)
+	}
+	return assemblies, nil
+}
+
+// scanAssembly scans a row of assemblyColumns into an assembly
+// assemblyColumns の行を組立に読み込む
+func scanAssembly(row rowScanner, assembly *inventory.Assembly) error {
+	var components []byte
+	err := row.Scan(
+		&assembly.ID,
+		&assembly.Type,
+		&assembly.KitItemID,
+		&assembly.LocationID,
+		&assembly.Quantity,
+		&assembly.Reference,
+		&components,
+		&assembly.CreatedAt,
+		&assembly.CreatedBy,
+	)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(components, &assembly.Components); err != nil {
+		return fmt.Errorf("組立のJSON解析に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// supplierColumns are the columns of the suppliers table in the order scanSupplier reads them
+// scanSupplier が読み取る順序の suppliers テーブルの列
+const supplierColumns = `id, name, contact_name, email, phone, address, is_active, created_at, updated_at`
+
+// supplierItemColumns are the columns of the supplier_items table in the order scanSupplierItem reads them
+// scanSupplierItem が読み取る順序の supplier_items テーブルの列
+const supplierItemColumns = `supplier_id, item_id, supplier_sku, unit_cost, lead_time_days, min_order_quantity, is_preferred, updated_at`
+
+// CreateSupplier creates a new supplier
+// 新しい仕入先を作成
+func (s *PostgreSQLStorage) CreateSupplier(ctx context.Context, supplier *inventory.Supplier) error {
+	query := `
+		INSERT INTO suppliers (` + supplierColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	_, err := s.conn.ExecContext(ctx, query,
+		supplier.ID,
+		supplier.Name,
+		supplier.ContactName,
+		supplier.Email,
+		supplier.Phone,
+		supplier.Address,
+		supplier.IsActive,
+		supplier.CreatedAt,
+		supplier.UpdatedAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return inventory.ErrDuplicateSupplier
+		}
+		return fmt.Errorf("仕入先作成に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// GetSupplier retrieves a supplier by ID
+// IDで仕入先を取得
+func (s *PostgreSQLStorage) GetSupplier(ctx context.Context, supplierID string) (*inventory.Supplier, error) {
+	supplier := &inventory.Supplier{}
+	err := scanSupplier(s.reader(ctx).QueryRowContext(ctx, `SELECT `+supplierColumns+` FROM suppliers WHERE id = $1`, supplierID), supplier)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrSupplierNotFound
+		}
+		return nil, fmt.Errorf("仕入先取得に失敗しました: %w", err)
+	}
+	return supplier, nil
+}
+
+// UpdateSupplier updates an existing supplier
+// 既存の仕入先を更新
+func (s *PostgreSQLStorage) UpdateSupplier(ctx context.Context, supplier *inventory.Supplier) error {
+	query := `
+		UPDATE suppliers
+		SET name = $2, contact_name = $3, email = $4, phone = $5, address = $6, is_active = $7, updated_at = $8
+		WHERE id = $1`
+
+	result, err := s.conn.ExecContext(ctx, query,
+		supplier.ID,
+		supplier.Name,
+		supplier.ContactName,
+		supplier.Email,
+		supplier.Phone,
+		supplier.Address,
+		supplier.IsActive,
+		supplier.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("仕入先更新に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+	if rowsAffected == 0 {
+		return inventory.ErrSupplierNotFound
+	}
+	return nil
+}
+
+// DeleteSupplier deletes a supplier and, by ON DELETE CASCADE, the purchasing terms of its items
+// 仕入先を削除（ON DELETE CASCADE で仕入条件も削除）
+func (s *PostgreSQLStorage) DeleteSupplier(ctx context.Context, supplierID string) error {
+	result, err := s.conn.ExecContext(ctx, `DELETE FROM suppliers WHERE id = $1`, supplierID)
+	if err != nil {
+		return fmt.Errorf("仕入先削除に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("削除行数の取得に失敗しました: %w", err)
+	}
+	if rowsAffected == 0 {
+		return inventory.ErrSupplierNotFound
+	}
+	return nil
+}
+
+// ListSuppliers lists suppliers ordered by ID
+// 仕入先一覧をID順に取得
+func (s *PostgreSQLStorage) ListSuppliers(ctx context.Context, offset, limit int) ([]inventory.Supplier, error) {
+	query := `
+		SELECT ` + supplierColumns + `
+		FROM suppliers
+		ORDER BY id
+		OFFSET $1 LIMIT $2`
+
+	rows, err := s.reader(ctx).QueryContext(ctx, query, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("仕入先一覧取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	suppliers := make([]inventory.Supplier, 0)
+	for rows.Next() {
+		var supplier inventory.Supplier
+		if err := scanSupplier(rows, &supplier); err != nil {
+			return nil, fmt.Errorf("仕入先スキャンに失敗しました: %w", err)
+		}
+		suppliers = append(suppliers, supplier)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("仕入先スキャンに失敗しました: %w", err)
+	}
+	return suppliers, nil
+}
+
+// SetSupplierItem registers or updates the purchasing terms of an item from a supplier in a single transaction
+// 仕入先の商品の仕入条件を単一トランザクションで登録（登録済みの場合は更新）
+//
+// 優先仕入先に指定した場合は、同じ商品の他の仕入先の優先指定を先に解除します。
+func (s *PostgreSQLStorage) SetSupplierItem(ctx context.Context, supplierItem *inventory.SupplierItem) error {
+	return s.WithinTx(ctx, func(txStorage inventory.Storage) error {
+		conn := txStorage.(*PostgreSQLStorage).conn
+
+		if supplierItem.IsPreferred {
+			_, err := conn.ExecContext(ctx,
+				`UPDATE supplier_items SET is_preferred = FALSE WHERE item_id = $1 AND supplier_id <> $2 AND is_preferred`,
+				supplierItem.ItemID, supplierItem.SupplierID)
+			if err != nil {
+				return fmt.Errorf("優先仕入先の解除に失敗しました: %w", err)
+			}
+		}
+
+		query := `
+			INSERT INTO supplier_items (` + supplierItemColumns + `)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (supplier_id, item_id) DO UPDATE
+			SET supplier_sku = EXCLUDED.supplier_sku, unit_cost = EXCLUDED.unit_cost,
+				lead_time_days = EXCLUDED.lead_time_days, min_order_quantity = EXCLUDED.min_order_quantity,
+				is_preferred = EXCLUDED.is_preferred, updated_at = EXCLUDED.updated_at`
+		_, err := conn.ExecContext(ctx, query,
+			supplierItem.SupplierID,
+			supplierItem.ItemID,
+			supplierItem.SupplierSKU,
+			supplierItem.UnitCost,
+			supplierItem.LeadTimeDays,
+			supplierItem.MinOrderQuantity,
+			supplierItem.IsPreferred,
+			supplierItem.UpdatedAt,
+		)
+		if err != nil {
+			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+				if pqErr.Constraint == "supplier_items_item_id_fkey" {
+					return inventory.ErrItemNotFound
+				}
+				return inventory.ErrSupplierNotFound
+			}
+			return fmt.Errorf("仕入条件の登録に失敗しました: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetSupplierItem retrieves the purchasing terms of an item from a supplier
+// 仕入先の商品の仕入条件を取得
+func (s *PostgreSQLStorage) GetSupplierItem(ctx context.Context, supplierID, itemID string) (*inventory.SupplierItem, error) {
+	query := `SELECT ` + supplierItemColumns + ` FROM supplier_items WHERE supplier_id = $1 AND item_id = $2`
+
+	supplierItem := &inventory.SupplierItem{}
+	err := scanSupplierItem(s.reader(ctx).QueryRowContext(ctx, query, supplierID, itemID), supplierItem)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrSupplierItemNotFound
+		}
+		return nil, fmt.Errorf("仕入条件の取得に失敗しました: %w", err)
+	}
+	return supplierItem, nil
+}
+
+// DeleteSupplierItem deletes the purchasing terms of an item from a supplier
+// 仕入先の商品の仕入条件を削除
+func (s *PostgreSQLStorage) DeleteSupplierItem(ctx context.Context, supplierID, itemID string) error {
+	result, err := s.conn.ExecContext(ctx, `DELETE FROM supplier_items WHERE supplier_id = $1 AND item_id = $2`, supplierID, itemID)
+	if err != nil {
+		return fmt.Errorf("仕入条件の削除に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("削除行数の取得に失敗しました: %w", err)
+	}
+	if rowsAffected == 0 {
+		return inventory.ErrSupplierItemNotFound
+	}
+	return nil
+}
+
+// ListSupplierItems lists the purchasing terms matching a filter, ordered by supplier and item ID
+// 条件に一致する仕入条件を仕入先ID・商品ID順に取得
+func (s *PostgreSQLStorage) ListSupplierItems(ctx context.Context, filter inventory.SupplierItemFilter) ([]inventory.SupplierItem, error) {
+	query := `
+		SELECT ` + supplierItemColumns + `
+		FROM supplier_items
+		WHERE ($1 = '' OR supplier_id = $1) AND ($2 = '' OR item_id = $2)
+		ORDER BY supplier_id, item_id
+		OFFSET $3`
+	args := []interface{}{filter.SupplierID, filter.ItemID, filter.Offset}
+	if filter.Limit > 0 {
+		query += ` LIMIT $4`
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.reader(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("仕入条件一覧の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	supplierItems := make([]inventory.SupplierItem, 0)
+	for rows.Next() {
+		var supplierItem inventory.SupplierItem
+		if err := scanSupplierItem(rows, &supplierItem); err != nil {
+			return nil, fmt.Errorf("仕入条件スキャンに失敗しました: %w", err)
+		}
+		supplierItems = append(supplierItems, supplierItem)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("仕入条件スキャンに失敗しました: %w", err)
+	}
+	return supplierItems, nil
+}
+
+// scanSupplier scans a row of supplierColumns into a supplier
+// supplierColumns の行を仕入先に読み込む
+func scanSupplier(row rowScanner, supplier *inventory.Supplier) error {
+	return row.Scan(
+		&supplier.ID,
+		&supplier.Name,
+		&supplier.ContactName,
+		&supplier.Email,
+		&supplier.Phone,
+		&supplier.Address,
+		&supplier.IsActive,
+		&supplier.CreatedAt,
+		&supplier.UpdatedAt,
+	)
+}
+
+// scanSupplierItem scans a row of supplierItemColumns into the purchasing terms of an item
+// supplierItemColumns の行を仕入条件に読み込む
+func scanSupplierItem(row rowScanner, supplierItem *inventory.SupplierItem) error {
+	return row.Scan(
+		&supplierItem.SupplierID,
+		&supplierItem.ItemID,
+		&supplierItem.SupplierSKU,
+		&supplierItem.UnitCost,
+		&supplierItem.LeadTimeDays,
+		&supplierItem.MinOrderQuantity,
+		&supplierItem.IsPreferred,
+		&supplierItem.UpdatedAt,
+	)
+}
+
+// purchaseOrderColumns are the columns scanned into a purchase order header
+// 発注のヘッダーとして読み取る列
+const purchaseOrderColumns = `id, supplier_id, location_id, reference, status, expected_at, note, created_at, created_by,
+	approved_at, COALESCE(approved_by, ''), sent_at, COALESCE(sent_by, ''), closed_at, COALESCE(closed_by, '')`
+
+// CreatePurchaseOrder creates a purchase order with its lines
+// 発注と行を作成
+func (s *PostgreSQLStorage) CreatePurchaseOrder(ctx context.Context, order *inventory.PurchaseOrder) error {
+	return s.WithinTx(ctx, func(txStorage inventory.Storage) error {
+		conn := txStorage.(*PostgreSQLStorage).conn
+
+		query := `
+			INSERT INTO purchase_orders (id, supplier_id, location_id, reference, status, expected_at, note, created_at, created_by,
+				approved_at, approved_by, sent_at, sent_by, closed_at, closed_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''), $12, NULLIF($13, ''), $14, NULLIF($15, ''))`
+		_, err := conn.ExecContext(ctx, query,
+			order.ID,
+			order.SupplierID,
+			order.LocationID,
+			order.Reference,
+			order.Status,
+			order.ExpectedAt,
+			order.Note,
+			order.CreatedAt,
+			order.CreatedBy,
+			order.ApprovedAt,
+			order.ApprovedBy,
+			order.SentAt,
+			order.SentBy,
+			order.ClosedAt,
+			order.ClosedBy,
+		)
+		if err != nil {
+			return fmt.Errorf("発注作成に失敗しました: %w", err)
+		}
+
+		lineQuery := `
+			INSERT INTO purchase_order_lines (purchase_order_id, item_id, quantity, received_quantity, unit_cost)
+			VALUES ($1, $2, $3, $4, $5)`
+		for _, line := range order.Lines {
+			if _, err := conn.ExecContext(ctx, lineQuery, order.ID, line.ItemID, line.Quantity, line.ReceivedQuantity, line.UnitCost); err != nil {
+				return fmt.Errorf("発注の行の保存に失敗しました: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetPurchaseOrder retrieves a purchase order with its lines ordered by item ID
+// 発注を行（商品IDの昇順）とともに取得
+func (s *PostgreSQLStorage) GetPurchaseOrder(ctx context.Context, orderID string) (*inventory.PurchaseOrder, error) {
+	db := s.reader(ctx)
+
+	var order inventory.PurchaseOrder
+	err := scanPurchaseOrder(db.QueryRowContext(ctx, `SELECT `+purchaseOrderColumns+` FROM purchase_orders WHERE id = $1`, orderID), &order)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrPurchaseOrderNotFound
+		}
+		return nil, fmt.Errorf("発注取得に失敗しました: %w", err)
+	}
+
+	query := `
+		SELECT purchase_order_id, item_id, quantity, received_quantity, unit_cost
+		FROM purchase_order_lines
+		WHERE purchase_order_id = $1
+		ORDER BY item_id ASC`
+
+	rows, err := db.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("発注の行の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	order.Lines = make([]inventory.PurchaseOrderLine, 0)
+	for rows.Next() {
+		var line inventory.PurchaseOrderLine
+		if err := scanPurchaseOrderLine(rows, &line); err != nil {
+			return nil, fmt.Errorf("発注の行スキャンに失敗しました: %w", err)
+		}
+		order.Lines = append(order.Lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("発注の行スキャンに失敗しました: %w", err)
+	}
+
+	return &order, nil
+}
+
+// ListPurchaseOrders lists purchase orders matching a filter, newest first, without their lines
+// 条件に一致する発注を作成日時の新しい順に取得（行は含まない）
+func (s *PostgreSQLStorage) ListPurchaseOrders(ctx context.Context, filter inventory.PurchaseOrderFilter) ([]inventory.PurchaseOrder, error) {
+	query := `
+		SELECT ` + purchaseOrderColumns + `
+		FROM purchase_orders
+		WHERE ($1 = '' OR supplier_id = $1) AND ($2 = '' OR location_id = $2) AND ($3 = '' OR status = $3)
+		ORDER BY created_at DESC, id DESC
+		OFFSET $4`
+	args := []interface{}{filter.SupplierID, filter.LocationID, string(filter.Status), filter.Offset}
+	if filter.Limit > 0 {
+		query += ` LIMIT $5`
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.reader(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("発注一覧の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	orders := make([]inventory.PurchaseOrder, 0)
+	for rows.Next() {
+		var order inventory.PurchaseOrder
+		if err := scanPurchaseOrder(rows, &order); err != nil {
+			return nil, fmt.Errorf("発注スキャンに失敗しました: %w", err)
+		}
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("発注スキャンに失敗しました: %w", err)
+	}
+
+	return orders, nil
+}
+
+// UpdatePurchaseOrder updates the status, timestamps and users of a purchase order in status from
+// 状態がfromの発注の状態・日時・担当者を更新
+//
+// 承認・送付・入荷・取消の競合で二重に処理しないよう、状態がfromの発注のみをWHERE句で更新し、
+// 0件更新の場合は発注の有無で ErrPurchaseOrderNotFound と ErrPurchaseOrderStatus を区別します。
+func (s *PostgreSQLStorage) UpdatePurchaseOrder(ctx context.Context, order *inventory.PurchaseOrder, from inventory.PurchaseOrderStatus) error {
+	query := `
+		UPDATE purchase_orders
+		SET status = $3, approved_at = $4, approved_by = NULLIF($5, ''), sent_at = $6, sent_by = NULLIF($7, ''),
+			closed_at = $8, closed_by = NULLIF($9, '')
+		WHERE id = $1 AND status = $2`
+	result, err := s.conn.ExecContext(ctx, query,
+		order.ID,
+		from,
+		order.Status,
+		order.ApprovedAt,
+		order.ApprovedBy,
+		order.SentAt,
+		order.SentBy,
+		order.ClosedAt,
+		order.ClosedBy,
+	)
+	if err != nil {
+		return fmt.Errorf("発注更新に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+	if rowsAffected == 0 {
+		var exists bool
+		err := s.conn.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM purchase_orders WHERE id = $1)`, order.ID).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("発注取得に失敗しました: %w", err)
+		}
+		if !exists {
+			return inventory.ErrPurchaseOrderNotFound
+		}
+		return inventory.ErrPurchaseOrderStatus
+	}
+	return nil
+}
+
+// ReceivePurchaseOrderLine adds a received quantity to a purchase order line
+// 発注の行の入荷済み数量を加算
+//
+// 同じ発注への入荷・取消を直列化するため、発注をトランザクション終了までロック（SELECT ... FOR UPDATE）します。
+// 発注数量を超える加算は行わず、0件更新の場合は行の有無で ErrPurchaseOrderNotFound と ErrReceiptExceedsOrder を区別します。
+func (s *PostgreSQLStorage) ReceivePurchaseOrderLine(ctx context.Context, orderID, itemID string, quantity int64) (*inventory.PurchaseOrderLine, error) {
+	var locked string
+	err := s.conn.QueryRowContext(ctx, `SELECT id FROM purchase_orders WHERE id = $1 FOR UPDATE`, orderID).Scan(&locked)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrPurchaseOrderNotFound
+		}
+		return nil, fmt.Errorf("発注のロックに失敗しました: %w", err)
+	}
+
+	query := `
+		UPDATE purchase_order_lines
+		SET received_quantity = received_quantity + $3
+		WHERE purchase_order_id = $1 AND item_id = $2 AND received_quantity + $3 <= quantity
+		RETURNING purchase_order_id, item_id, quantity, received_quantity, unit_cost`
+
+	line := &inventory.PurchaseOrderLine{}
+	err = scanPurchaseOrderLine(s.conn.QueryRowContext(ctx, query, orderID, itemID, quantity), line)
+	if err == nil {
+		return line, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("発注の入荷数量の更新に失敗しました: %w", err)
+	}
+
+	var exists bool
+	err = s.conn.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM purchase_order_lines WHERE purchase_order_id = $1 AND item_id = $2)`, orderID, itemID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("発注の行の取得に失敗しました: %w", err)
+	}
+	if !exists {
+		return nil, inventory.ErrPurchaseOrderNotFound
+	}
+	return nil, inventory.ErrReceiptExceedsOrder
+}
+
+// goodsReceiptColumns are the columns of the goods_receipts table in the order scanGoodsReceipt reads them
+// scanGoodsReceipt が読み取る順序の goods_receipts テーブルの列
+const goodsReceiptColumns = `id, purchase_order_id, location_id, reference, lines, received_at, received_by`
+
+// CreateGoodsReceipt records a goods receipt against a purchase order
+// 発注に対する入荷を記録
+//
+// 入荷した行はJSONBで保存します。
+func (s *PostgreSQLStorage) CreateGoodsReceipt(ctx context.Context, receipt *inventory.GoodsReceipt) error {
+	lines, err := json.Marshal(receipt.Lines)
+	if err != nil {
+		return fmt.Errorf("入荷のJSON変換に失敗しました: %w", err)
+	}
+
+	query := `
+		INSERT INTO goods_receipts (` + goodsReceiptColumns + `)
+		VALUES ($1, $2, $3, $4, $5::jsonb, $6, $7)`
+	_, err = s.conn.ExecContext(ctx, query,
+		receipt.ID,
+		receipt.PurchaseOrderID,
+		receipt.LocationID,
+		receipt.Reference,
+		string(lines),
+		receipt.ReceivedAt,
+		receipt.ReceivedBy,
+	)
+	if err != nil {
+		return fmt.Errorf("入荷の記録に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// ListGoodsReceipts lists the goods receipts of a purchase order, oldest first
+// 発注に対する入荷を入荷日時の古い順に取得
+func (s *PostgreSQLStorage) ListGoodsReceipts(ctx context.Context, orderID string) ([]inventory.GoodsReceipt, error) {
+	query := `
+		SELECT ` + goodsReceiptColumns + `
+		FROM goods_receipts
+		WHERE purchase_order_id = $1
+		ORDER BY received_at ASC, id ASC`
+
+	rows, err := s.reader(ctx).QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("入荷一覧の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	receipts := make([]inventory.GoodsReceipt, 0)
+	for rows.Next() {
+		var receipt inventory.GoodsReceipt
+		if err := scanGoodsReceipt(rows, &receipt); err != nil {
+			return nil, fmt.Errorf("入荷スキャンに失敗しました: %w", err)
+		}
+		receipts = append(receipts, receipt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("入荷スキャンに失敗しました: %w", err)
+	}
+	return receipts, nil
+}
+
+// scanPurchaseOrder scans the purchaseOrderColumns of a row into a purchase order
+// 行の purchaseOrderColumns を発注に読み取る
+func scanPurchaseOrder(row rowScanner, order *inventory.PurchaseOrder) error {
+	return row.Scan(
+		&order.ID,
+		&order.SupplierID,
+		&order.LocationID,
+		&order.Reference,
+		&order.Status,
+		&order.ExpectedAt,
+		&order.Note,
+		&order.CreatedAt,
+		&order.CreatedBy,
+		&order.ApprovedAt,
+		&order.ApprovedBy,
+		&order.SentAt,
+		&order.SentBy,
+		&order.ClosedAt,
+		&order.ClosedBy,
+	)
+}
+
+// scanPurchaseOrderLine scans a row of a purchase order line
+// 発注の行を読み取る
+func scanPurchaseOrderLine(row rowScanner, line *inventory.PurchaseOrderLine) error {
+	return row.Scan(
+		&line.PurchaseOrderID,
+		&line.ItemID,
+		&line.Quantity,
+		&line.ReceivedQuantity,
+		&line.UnitCost,
+	)
+}
+
+// scanGoodsReceipt scans a row of goodsReceiptColumns into a goods receipt
+// goodsReceiptColumns の行を入荷に読み込む
+func scanGoodsReceipt(row rowScanner, receipt *inventory.GoodsReceipt) error {
+	var lines []byte
+	err := row.Scan(
+		&receipt.ID,
+		&receipt.PurchaseOrderID,
+		&receipt.LocationID,
+		&receipt.Reference,
+		&lines,
+		&receipt.ReceivedAt,
+		&receipt.ReceivedBy,
+	)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(lines, &receipt.Lines); err != nil {
+		return fmt.Errorf("入荷のJSON解析に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// salesOrderColumns are the columns scanned into a sales order header
+// 受注のヘッダーとして読み取る列
+const salesOrderColumns = `id, reference, location_id, customer, status, note, version, created_at, created_by,
+	closed_at, COALESCE(closed_by, ''), channel`
+
+// CreateSalesOrder creates a sales order with its lines
+// 受注と行を作成
+func (s *PostgreSQLStorage) CreateSalesOrder(ctx context.Context, order *inventory.SalesOrder) error {
+	return s.WithinTx(ctx, func(txStorage inventory.Storage) error {
+		conn := txStorage.(*PostgreSQLStorage).conn
+
+		query := `
+			INSERT INTO sales_orders (id, reference, location_id, customer, status, note, version, created_at, created_by,
+				closed_at, closed_by, channel)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''), $12)`
+		_, err := conn.ExecContext(ctx, query,
+			order.ID,
+			order.Reference,
+			order.LocationID,
+			order.Customer,
+			order.Status,
+			order.Note,
+			order.Version,
+			order.CreatedAt,
+			order.CreatedBy,
+			order.ClosedAt,
+			order.ClosedBy,
+			order.Channel,
+		)
+		if err != nil {
+			return fmt.Errorf("受注作成に失敗しました: %w", err)
+		}
+
+		lineQuery := `
+			INSERT INTO sales_order_lines (sales_order_id, item_id, quantity, allocated_quantity, shipped_quantity, short_quantity)
+			VALUES ($1, $2, $3, $4, $5, $6)`
+		for _, line := range order.Lines {
+			_, err := conn.ExecContext(ctx, lineQuery,
+				order.ID, line.ItemID, line.Quantity, line.AllocatedQuantity, line.ShippedQuantity, line.ShortQuantity)
+			if err != nil {
+				return fmt.Errorf("受注の行の保存に失敗しました: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetSalesOrder retrieves a sales order with its lines ordered by item ID
+// 受注を行（商品IDの昇順）とともに取得
+func (s *PostgreSQLStorage) GetSalesOrder(ctx context.Context, orderID string) (*inventory.SalesOrder, error) {
+	db := s.reader(ctx)
+
+	var order inventory.SalesOrder
+	err := scanSalesOrder(db.QueryRowContext(ctx, `SELECT `+salesOrderColumns+` FROM sales_orders WHERE id = $1`, orderID), &order)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrSalesOrderNotFound
+		}
+		return nil, fmt.Errorf("受注取得に失敗しました: %w", err)
+	}
+
+	query := `
+		SELECT sales_order_id, item_id, quantity, allocated_quantity, shipped_quantity, short_quantity
+		FROM sales_order_lines
+		WHERE sales_order_id = $1
+		ORDER BY item_id ASC`
+
+	rows, err := db.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("受注の行の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	order.Lines = make([]inventory.SalesOrderLine, 0)
+	for rows.Next() {
+		var line inventory.SalesOrderLine
+		err := rows.Scan(
+			&line.SalesOrderID,
+			&line.ItemID,
+			&line.Quantity,
+			&line.AllocatedQuantity,
+			&line.ShippedQuantity,
+			&line.ShortQuantity,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("受注の行スキャンに失敗しました: %w", err)
+		}
+		order.Lines = append(order.Lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("受注の行スキャンに失敗しました: %w", err)
+	}
+
+	return &order, nil
+}
+
+// ListSalesOrders lists sales orders matching a filter, newest first, without their lines
+// 条件に一致する受注を受付日時の新しい順に取得（行は含まない）
+func (s *PostgreSQLStorage) ListSalesOrders(ctx context.Context, filter inventory.SalesOrderFilter) ([]inventory.SalesOrder, error) {
+	query := `
+		SELECT ` + salesOrderColumns + `
+		FROM sales_orders
+		WHERE ($1 = '' OR location_id = $1) AND ($2 = '' OR reference = $2) AND ($3 = '' OR status = $3)
+		ORDER BY created_at DESC, id DESC
+		OFFSET $4`
+	args := []interface{}{filter.LocationID, filter.Reference, string(filter.Status), filter.Offset}
+	if filter.Limit > 0 {
+		query += ` LIMIT $5`
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.reader(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("受注一覧の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	orders := make([]inventory.SalesOrder, 0)
+	for rows.Next() {
+		var order inventory.SalesOrder
+		if err := scanSalesOrder(rows, &order); err != nil {
+			return nil, fmt.Errorf("受注スキャンに失敗しました: %w", err)
+		}
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("受注スキャンに失敗しました: %w", err)
+	}
+
+	return orders, nil
+}
+
+// UpdateSalesOrder updates the status and line quantities of a sales order with optimistic locking
+// 受注の状態と行の数量を楽観的ロックで更新
+//
+// 保存済みのバージョンが order.Version-1 の受注のみをWHERE句で更新し、
+// 0件更新の場合は受注の有無で ErrSalesOrderNotFound と ErrVersionMismatch を区別します。
+func (s *PostgreSQLStorage) UpdateSalesOrder(ctx context.Context, order *inventory.SalesOrder) error {
+	return s.WithinTx(ctx, func(txStorage inventory.Storage) error {
+		conn := txStorage.(*PostgreSQLStorage).conn
+
+		query := `
+			UPDATE sales_orders
+			SET status = $2, version = $3, closed_at = $4, closed_by = NULLIF($5, '')
+			WHERE id = $1 AND version = $3 - 1`
+		result, err := conn.ExecContext(ctx, query, order.ID, order.Status, order.Version, order.ClosedAt, order.ClosedBy)
+		if err != nil {
+			return fmt.Errorf("受注更新に失敗しました: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+		}
+		if rowsAffected == 0 {
+			var exists bool
+			err := conn.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM sales_orders WHERE id = $1)`, order.ID).Scan(&exists)
+			if err != nil {
+				return fmt.Errorf("受注取得に失敗しました: %w", err)
+			}
+			if !exists {
+				return inventory.ErrSalesOrderNotFound
+			}
+			return inventory.ErrVersionMismatch
+		}
+
+		lineQuery := `
+			UPDATE sales_order_lines
+			SET allocated_quantity = $3, shipped_quantity = $4, short_quantity = $5
+			WHERE sales_order_id = $1 AND item_id = $2`
+		for _, line := range order.Lines {
+			_, err := conn.ExecContext(ctx, lineQuery,
+				order.ID, line.ItemID, line.AllocatedQuantity, line.ShippedQuantity, line.ShortQuantity)
+			if err != nil {
+				return fmt.Errorf("受注の行の更新に失敗しました: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// scanSalesOrder scans the salesOrderColumns of a row into a sales order
+// 行の salesOrderColumns を受注に読み取る
+func scanSalesOrder(row rowScanner, order *inventory.SalesOrder) error {
+	return row.Scan(
+		&order.ID,
+		&order.Reference,
+		&order.LocationID,
+		&order.Customer,
+		&order.Status,
+		&order.Note,
+		&order.Version,
+		&order.CreatedAt,
+		&order.CreatedBy,
+		&order.ClosedAt,
+		&order.ClosedBy,
+		&order.Channel,
+	)
+}
+
+// returnColumns are the columns scanned into a return header
+// 返品のヘッダーとして読み取る列
+const returnColumns = `id, reference, COALESCE(sales_order_id, ''), customer, location_id, reason, status, note, version,
+	created_at, created_by, closed_at, COALESCE(closed_by, '')`
+
+// CreateReturn creates a return with its lines
+// 返品と行を作成
+func (s *PostgreSQLStorage) CreateReturn(ctx context.Context, rma *inventory.ReturnAuthorization) error {
+	return s.WithinTx(ctx, func(txStorage inventory.Storage) error {
+		conn := txStorage.(*PostgreSQLStorage).conn
+
+		query := `
+			INSERT INTO returns (id, reference, sales_order_id, customer, location_id, reason, status, note, version,
+				created_at, created_by, closed_at, closed_by)
+			VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7, $8, $9, $10, $11, $12, NULLIF($13, ''))`
+		_, err := conn.ExecContext(ctx, query,
+			rma.ID,
+			rma.Reference,
+			rma.SalesOrderID,
+			rma.Customer,
+			rma.LocationID,
+			rma.Reason,
+			rma.Status,
+			rma.Note,
+			rma.Version,
+			rma.CreatedAt,
+			rma.CreatedBy,
+			rma.ClosedAt,
+			rma.ClosedBy,
+		)
+		if err != nil {
+			return fmt.Errorf("返品作成に失敗しました: %w", err)
+		}
+
+		lineQuery := `
+			INSERT INTO return_lines (return_id, item_id, quantity, received_quantity, restocked_quantity,
+				refurbished_quantity, scrapped_quantity)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`
+		for _, line := range rma.Lines {
+			_, err := conn.ExecContext(ctx, lineQuery, rma.ID, line.ItemID, line.Quantity, line.ReceivedQuantity,
+				line.RestockedQuantity, line.RefurbishedQuantity, line.ScrappedQuantity)
+			if err != nil {
+				return fmt.Errorf("返品の行の保存に失敗しました: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetReturn retrieves a return with its lines ordered by item ID
+// 返品を行（商品IDの昇順）とともに取得
+func (s *PostgreSQLStorage) GetReturn(ctx context.Context, returnID string) (*inventory.ReturnAuthorization, error) {
+	db := s.reader(ctx)
+
+	var rma inventory.ReturnAuthorization
+	err := scanReturn(db.QueryRowContext(ctx, `SELECT `+returnColumns+` FROM returns WHERE id = $1`, returnID), &rma)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrReturnNotFound
+		}
+		return nil, fmt.Errorf("返品取得に失敗しました: %w", err)
+	}
+
+	query := `
+		SELECT return_id, item_id, quantity, received_quantity, restocked_quantity, refurbished_quantity, scrapped_quantity
+		FROM return_lines
+		WHERE return_id = $1
+		ORDER BY item_id ASC`
+
+	rows, err := db.QueryContext(ctx, query, returnID)
+	if err != nil {
+		return nil, fmt.Errorf("返品の行の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	rma.Lines = make([]inventory.ReturnLine, 0)
+	for rows.Next() {
+		var line inventory.ReturnLine
+		err := rows.Scan(
+			&line.ReturnID,
+			&line.ItemID,
+			&line.Quantity,
+			&line.ReceivedQuantity,
+			&line.RestockedQuantity,
+			&line.RefurbishedQuantity,
+			&line.ScrappedQuantity,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("返品の行スキャンに失敗しました: %w", err)
+		}
+		rma.Lines = append(rma.Lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("返品の行スキャンに失敗しました: %w", err)
+	}
+
+	return &rma, nil
+}
+
+// ListReturns lists returns matching a filter, newest first, without their lines
+// 条件に一致する返品を受付日時の新しい順に取得（行は含まない）
+func (s *PostgreSQLStorage) ListReturns(ctx context.Context, filter inventory.ReturnFilter) ([]inventory.ReturnAuthorization, error) {
+	query := `
+		SELECT ` + returnColumns + `
+		FROM returns
+		WHERE ($1 = '' OR sales_order_id = $1) AND ($2 = '' OR location_id = $2) AND ($3 = '' OR status = $3)
+		ORDER BY created_at DESC, id DESC
+		OFFSET $4`
+	args := []interface{}{filter.SalesOrderID, filter.LocationID, string(filter.Status), filter.Offset}
+	if filter.Limit > 0 {
+		query += ` LIMIT $5`
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.reader(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("返品一覧の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	returns := make([]inventory.ReturnAuthorization, 0)
+	for rows.Next() {
+		var rma inventory.ReturnAuthorization
+		if err := scanReturn(rows, &rma); err != nil {
+			return nil, fmt.Errorf("返品スキャンに失敗しました: %w", err)
+		}
+		returns = append(returns, rma)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("返品スキャンに失敗しました: %w", err)
+	}
+
+	return returns, nil
+}
+
+// UpdateReturn updates the status and line quantities of a return with optimistic locking
+// 返品の状態と行の数量を楽観的ロックで更新
+//
+// 保存済みのバージョンが rma.Version-1 の返品のみをWHERE句で更新し、
+// 0件更新の場合は返品の有無で ErrReturnNotFound と ErrVersionMismatch を区別します。
+func (s *PostgreSQLStorage) UpdateReturn(ctx context.Context, rma *inventory.ReturnAuthorization) error {
+	return s.WithinTx(ctx, func(txStorage inventory.Storage) error {
+		conn := txStorage.(*PostgreSQLStorage).conn
+
+		query := `
+			UPDATE returns
+			SET status = $2, version = $3, closed_at = $4, closed_by = NULLIF($5, '')
+			WHERE id = $1 AND version = $3 - 1`
+		result, err := conn.ExecContext(ctx, query, rma.ID, rma.Status, rma.Version, rma.ClosedAt, rma.ClosedBy)
+		if err != nil {
+			return fmt.Errorf("返品更新に失敗しました: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+		}
+		if rowsAffected == 0 {
+			var exists bool
+			err := conn.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM returns WHERE id = $1)`, rma.ID).Scan(&exists)
+			if err != nil {
+				return fmt.Errorf("返品取得に失敗しました: %w", err)
+			}
+			if !exists {
+				return inventory.ErrReturnNotFound
+			}
+			return inventory.ErrVersionMismatch
+		}
+
+		lineQuery := `
+			UPDATE return_lines
+			SET received_quantity = $3, restocked_quantity = $4, refurbished_quantity = $5, scrapped_quantity = $6
+			WHERE return_id = $1 AND item_id = $2`
+		for _, line := range rma.Lines {
+			_, err := conn.ExecContext(ctx, lineQuery, rma.ID, line.ItemID, line.ReceivedQuantity,
+				line.RestockedQuantity, line.RefurbishedQuantity, line.ScrappedQuantity)
+			if err != nil {
+				return fmt.Errorf("返品の行の更新に失敗しました: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// scanReturn scans the returnColumns of a row into a return
+// 行の returnColumns を返品に読み取る
+func scanReturn(row rowScanner, rma *inventory.ReturnAuthorization) error {
+	return row.Scan(
+		&rma.ID,
+		&rma.Reference,
+		&rma.SalesOrderID,
+		&rma.Customer,
+		&rma.LocationID,
+		&rma.Reason,
+		&rma.Status,
+		&rma.Note,
+		&rma.Version,
+		&rma.CreatedAt,
+		&rma.CreatedBy,
+		&rma.ClosedAt,
+		&rma.ClosedBy,
+	)
+}
+
+// returnDispositionColumns are the columns scanned into a return disposition
+// 返品の処分として読み取る列
+const returnDispositionColumns = `id, return_id, item_id, quantity, disposition, COALESCE(target_item_id, ''),
+	COALESCE(location_id, ''), COALESCE(reason_code, ''), note, COALESCE(transaction_id, ''), created_at, created_by`
+
+// CreateReturnDisposition records a disposition of returned units
+// 返品の処分を記録
+func (s *PostgreSQLStorage) CreateReturnDisposition(ctx context.Context, disposition *inventory.ReturnDisposition) error {
+	query := `
+		INSERT INTO return_dispositions (id, return_id, item_id, quantity, disposition, target_item_id, location_id,
+			reason_code, note, transaction_id, created_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''), NULLIF($8, ''), $9, NULLIF($10, ''), $11, $12)`
+	_, err := s.conn.ExecContext(ctx, query,
+		disposition.ID,
+		disposition.ReturnID,
+		disposition.ItemID,
+		disposition.Quantity,
+		disposition.Type,
+		disposition.TargetItemID,
+		disposition.LocationID,
+		disposition.Reason,
+		disposition.Note,
+		disposition.TransactionID,
+		disposition.CreatedAt,
+		disposition.CreatedBy,
+	)
+	if err != nil {
+		return fmt.Errorf("返品の処分の記録に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// ListReturnDispositions lists the dispositions of a return, oldest first
+// 返品の処分を処分日時の古い順に取得
+func (s *PostgreSQLStorage) ListReturnDispositions(ctx context.Context, returnID string) ([]inventory.ReturnDisposition, error) {
+	query := `
+		SELECT ` + returnDispositionColumns + `
+		FROM return_dispositions
+		WHERE return_id = $1
+		ORDER BY created_at ASC, id ASC`
+
+	rows, err := s.reader(ctx).QueryContext(ctx, query, returnID)
+	if err != nil {
+		return nil, fmt.Errorf("返品の処分一覧の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	dispositions := make([]inventory.ReturnDisposition, 0)
+	for rows.Next() {
+		var disposition inventory.ReturnDisposition
+		err := rows.Scan(
+			&disposition.ID,
+			&disposition.ReturnID,
+			&disposition.ItemID,
+			&disposition.Quantity,
+			&disposition.Type,
+			&disposition.TargetItemID,
+			&disposition.LocationID,
+			&disposition.Reason,
+			&disposition.Note,
+			&disposition.TransactionID,
+			&disposition.CreatedAt,
+			&disposition.CreatedBy,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("返品の処分スキャンに失敗しました: %w", err)
+		}
+		dispositions = append(dispositions, disposition)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("返品の処分スキャンに失敗しました: %w", err)
+	}
+	return dispositions, nil
+}
+
+// CreateLot creates a new lot record
+// 新しいロット記録を作成
+func (s *PostgreSQLStorage) CreateLot(ctx context.Context, lot *inventory.Lot) error {
+	query := `
+		INSERT INTO lots (id, number, item_id, quantity, unit_cost, expiry_date, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err := s.conn.ExecContext(ctx, query,
+		lot.ID,
+		lot.Number,
+		lot.ItemID,
+		lot.Quantity,
+		lot.UnitCost,
+		lot.ExpiryDate,
+		lot.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("ロット作成に失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// GetLot retrieves a lot by ID
+// IDでロットを取得
+func (s *PostgreSQLStorage) GetLot(ctx context.Context, lotID string) (*inventory.Lot, error) {
+	query := `
+		SELECT id, number, item_id, quantity, unit_cost, expiry_date, created_at
+		FROM lots 
+		WHERE id = $1`
+
+	lot := &inventory.Lot{}
+	err := s.conn.QueryRowContext(ctx, query, lotID).Scan(
+		&lot.ID,
+		&lot.Number,
+		&lot.ItemID,
+		&lot.Quantity,
+		&lot.UnitCost,
+		&lot.ExpiryDate,
+		&lot.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrLotNotFound
+		}
+		return nil, fmt.Errorf("ロット取得に失敗しました: %w", err)
+	}
+
+	return lot, nil
+}
+
+// UpdateLot updates an existing lot
+// 既存のロットを更新
+func (s *PostgreSQLStorage) UpdateLot(ctx context.Context, lot *inventory.Lot) error {
+	query := `
+		UPDATE lots 
+		SET number = $2, quantity = $3, unit_cost = $4, expiry_date = $5
+		WHERE id = $1`
+
+	result, err := s.conn.ExecContext(ctx, query,
+		lot.ID,
+		lot.Number,
+		lot.Quantity,
+		lot.UnitCost,
+		lot.ExpiryDate,
+	)
+
+	if err != nil {
+		return fmt.Errorf("ロット更新に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return inventory.ErrLotNotFound
+	}
+
+	return nil
+}
+
+// DeleteLot deletes a lot by ID
+// IDでロットを削除
+func (s *PostgreSQLStorage) DeleteLot(ctx context.Context, lotID string) error {
+	query := `DELETE FROM lots WHERE id = $1`
+
+	result, err := s.conn.ExecContext(ctx, query, lotID)
+	if err != nil {
+		return fmt.Errorf("ロット削除に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("削除行数の取得に失敗しました: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return inventory.ErrLotNotFound
+	}
+
+	return nil
+}
+
+// AdjustLotQuantity atomically adds delta to a lot's quantity
+// ロットの数量をアトミックにdelta分だけ増減
+//
+// 数量が負になる更新はWHERE句で除外し、0件更新の場合はロットの有無で
+// ErrLotNotFoundとErrInsufficientStockを区別します。
+func (s *PostgreSQLStorage) AdjustLotQuantity(ctx context.Context, lotID string, delta int64) (*inventory.Lot, error) {
+	query := `
+		UPDATE lots 
+		SET quantity = quantity + $2
+		WHERE id = $1 AND quantity + $2 >= 0
+		RETURNING id, number, item_id, quantity, unit_cost, expiry_date, created_at`
+
+	lot := &inventory.Lot{}
+	err := s.conn.QueryRowContext(ctx, query, lotID, delta).Scan(
+		&lot.ID,
+		&lot.Number,
+		&lot.ItemID,
+		&lot.Quantity,
+		&lot.UnitCost,
+		&lot.ExpiryDate,
+		&lot.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			if _, getErr := s.GetLot(ctx, lotID); getErr != nil {
+				return nil, getErr
+			}
+			return nil, inventory.ErrInsufficientStock
+		}
+		return nil, fmt.Errorf("ロット数量調整に失敗しました: %w", err)
+	}
+
+	return lot, nil
+}
+
+// GetLotsByItem retrieves all lots for a specific item
+// 指定商品のすべてのロットを取得
+func (s *PostgreSQLStorage) GetLotsByItem(ctx context.Context, itemID string) ([]inventory.Lot, error) {
+	query := `
+		SELECT id, number, item_id, quantity, unit_cost, expiry_date, created_at
+		FROM lots 
+		WHERE item_id = $1
+		ORDER BY created_at DESC`
+
+	rows, err := s.conn.QueryContext(ctx, query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("商品ロット取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var lots []inventory.Lot
+	for rows.Next() {
+		var lot inventory.Lot
+		err := rows.Scan(
+			&lot.ID,
+			&lot.Number,
+			&lot.ItemID,
+			&lot.Quantity,
+			&lot.UnitCost,
+			&lot.ExpiryDate,
+			&lot.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("ロットスキャンに失敗しました: %w", err)
+		}
+		lots = append(lots, lot)
+	}
+
+	return lots, nil
+}
+
+// GetExpiringLots retrieves lots that are expiring within the specified duration
+// 指定期間内に期限切れになるロットを取得
+func (s *PostgreSQLStorage) GetExpiringLots(ctx context.Context, within time.Duration) ([]inventory.Lot, error) {
+	expiryThreshold := time.Now().Add(within)
+	query := `
+		SELECT id, number, item_id, quantity, unit_cost, expiry_date, created_at
+		FROM lots 
+		WHERE expiry_date IS NOT NULL AND expiry_date <= $1
+		ORDER BY expiry_date ASC`
+
+	rows, err := s.conn.QueryContext(ctx, query, expiryThreshold)
+	if err != nil {
+		return nil, fmt.Errorf("期限切れ間近ロット取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var lots []inventory.Lot
+	for rows.Next() {
+		var lot inventory.Lot
+		err := rows.Scan(
+			&lot.ID,
+			&lot.Number,
+			&lot.ItemID,
+			&lot.Quantity,
+			&lot.UnitCost,
+			&lot.ExpiryDate,
+			&lot.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("ロットスキャンに失敗しました: %w", err)
+		}
+		lots = append(lots, lot)
+	}
+
+	return lots, nil
+}
+
+// GetExpiredLots retrieves lots that have already expired
+// 既に期限切れになったロットを取得
+func (s *PostgreSQLStorage) GetExpiredLots(ctx context.Context) ([]inventory.Lot, error) {
+	now := time.Now()
+	query := `
+		SELECT id, number, item_id, quantity, unit_cost, expiry_date, created_at
+		FROM lots 
+		WHERE expiry_date IS NOT NULL AND expiry_date < $1
+		ORDER BY expiry_date ASC`
+
+	rows, err := s.conn.QueryContext(ctx, query, now)
+	if err != nil {
+		return nil, fmt.Errorf("期限切れロット取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var lots []inventory.Lot
+	for rows.Next() {
+		var lot inventory.Lot
+		err := rows.Scan(
+			&lot.ID,
+			&lot.Number,
+			&lot.ItemID,
+			&lot.Quantity,
+			&lot.UnitCost,
+			&lot.ExpiryDate,
+			&lot.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("ロットスキャンに失敗しました: %w", err)
+		}
+		lots = append(lots, lot)
+	}
+
+	return lots, nil
+}
+
+// GetLotByNumber retrieves the oldest lot of an item with a lot number
+// 商品のロット番号のロット（複数ある場合は最も古いロット）を取得
+func (s *PostgreSQLStorage) GetLotByNumber(ctx context.Context, itemID, lotNumber string) (*inventory.Lot, error) {
+	query := `
+		SELECT id, number, item_id, quantity, unit_cost, expiry_date, created_at
+		FROM lots
+		WHERE item_id = $1 AND number = $2
+		ORDER BY created_at, id
+		LIMIT 1`
+
+	lot := &inventory.Lot{}
+	err := s.conn.QueryRowContext(ctx, query, itemID, lotNumber).Scan(
+		&lot.ID,
+		&lot.Number,
+		&lot.ItemID,
+		&lot.Quantity,
+		&lot.UnitCost,
+		&lot.ExpiryDate,
+		&lot.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrLotNotFound
+		}
+		return nil, fmt.Errorf("ロット取得に失敗しました: %w", err)
+	}
+
+	return lot, nil
+}
+
+// lotStockColumns are the columns scanned by scanLotStock
+// scanLotStock で読み取るロット在庫の列
+const lotStockColumns = `ls.lot_id, l.number, ls.item_id, ls.location_id, ls.quantity, ls.updated_at`
+
+// scanLotStock scans a row of lotStockColumns into a lot stock
+// lotStockColumns の行をロット在庫に読み取る
+func scanLotStock(row rowScanner, lotStock *inventory.LotStock) error {
+	return row.Scan(
+		&lotStock.LotID,
+		&lotStock.LotNumber,
+		&lotStock.ItemID,
+		&lotStock.LocationID,
+		&lotStock.Quantity,
+		&lotStock.UpdatedAt,
+	)
+}
+
+// AdjustLotStock adds delta to the quantity of a lot at a location, creating the record if needed
+// ロットのロケーションの在庫数量をdelta分だけ増減（記録がない場合は作成）
+//
+// 減算は数量が負にならない場合のみWHERE句で更新し、同時の出庫でも数量が負になりません。
+func (s *PostgreSQLStorage) AdjustLotStock(ctx context.Context, lot *inventory.Lot, locationID string, delta int64) (*inventory.LotStock, error) {
+	var query string
+	if delta >= 0 {
+		query = `
+			INSERT INTO lot_stocks (lot_id, item_id, location_id, quantity, updated_at)
+			VALUES ($1, $2, $3, $4, NOW())
+			ON CONFLICT (lot_id, location_id) DO UPDATE
+			SET quantity = lot_stocks.quantity + EXCLUDED.quantity, updated_at = NOW()
+			RETURNING lot_id, item_id, location_id, quantity, updated_at`
+	} else {
+		query = `
+			UPDATE lot_stocks
+			SET quantity = quantity + $4, updated_at = NOW()
+			WHERE lot_id = $1 AND item_id = $2 AND location_id = $3 AND quantity + $4 >= 0
+			RETURNING lot_id, item_id, location_id, quantity, updated_at`
+	}
+
+	lotStock := &inventory.LotStock{LotNumber: lot.Number}
+	err := s.conn.QueryRowContext(ctx, query, lot.ID, lot.ItemID, locationID, delta).Scan(
+		&lotStock.LotID,
+		&lotStock.ItemID,
+		&lotStock.LocationID,
+		&lotStock.Quantity,
+		&lotStock.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrInsufficientLotStock
+		}
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return nil, inventory.ErrLotNotFound
+		}
+		return nil, fmt.Errorf("ロット在庫の更新に失敗しました: %w", err)
+	}
+
+	return lotStock, nil
+}
+
+// GetLotStock retrieves the stock of a lot at a location
+// ロットのロケーションの在庫を取得
+func (s *PostgreSQLStorage) GetLotStock(ctx context.Context, lotID, locationID string) (*inventory.LotStock, error) {
+	query := `
+		SELECT ` + lotStockColumns + `
+		FROM lot_stocks ls
+		JOIN lots l ON l.id = ls.lot_id
+		WHERE ls.lot_id = $1 AND ls.location_id = $2`
+
+	lotStock := &inventory.LotStock{}
+	if err := scanLotStock(s.reader(ctx).QueryRowContext(ctx, query, lotID, locationID), lotStock); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrLotStockNotFound
+		}
+		return nil, fmt.Errorf("ロット在庫の取得に失敗しました: %w", err)
+	}
+	return lotStock, nil
+}
+
+// ListLotStocks lists lot stocks matching a filter ordered by lot number and location
+// 条件に一致するロット在庫をロット番号・ロケーションIDの昇順で取得
+func (s *PostgreSQLStorage) ListLotStocks(ctx context.Context, filter inventory.LotStockFilter) ([]inventory.LotStock, error) {
+	query := `
+		SELECT ` + lotStockColumns + `
+		FROM lot_stocks ls
+		JOIN lots l ON l.id = ls.lot_id
+		WHERE ($1 = '' OR ls.item_id = $1) AND ($2 = '' OR ls.lot_id = $2) AND ($3 = '' OR ls.location_id = $3)
+			AND ($4 OR ls.quantity <> 0)
+		ORDER BY l.number, ls.lot_id, ls.location_id
+		OFFSET $5`
+	args := []interface{}{filter.ItemID, filter.LotID, filter.LocationID, filter.IncludeEmpty, filter.Offset}
+	if filter.Limit > 0 {
+		query += ` LIMIT $6`
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.reader(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ロット在庫一覧の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	lotStocks := make([]inventory.LotStock, 0)
+	for rows.Next() {
+		var lotStock inventory.LotStock
+		if err := scanLotStock(rows, &lotStock); err != nil {
+			return nil, fmt.Errorf("ロット在庫スキャンに失敗しました: %w", err)
+		}
+		lotStocks = append(lotStocks, lotStock)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ロット在庫スキャンに失敗しました: %w", err)
+	}
+
+	return lotStocks, nil
+}
+
+// consignmentStockColumns are the columns scanned by scanConsignmentStock
+// scanConsignmentStock で読み取る委託在庫の列
+const consignmentStockColumns = `owner_id, item_id, location_id, quantity, updated_at`
+
+// scanConsignmentStock scans a row selected with consignmentStockColumns
+// consignmentStockColumns で選択した行を読み取る
+func scanConsignmentStock(row rowScanner, consignment *inventory.ConsignmentStock) error {
+	return row.Scan(
+		&consignment.OwnerID,
+		&consignment.ItemID,
+		&consignment.LocationID,
+		&consignment.Quantity,
+		&consignment.UpdatedAt,
+	)
+}
+
+// AdjustConsignmentStock adds delta to the consignment stock of an owner, creating the record if needed
+// 所有者の委託在庫の数量をdelta分だけ増減（記録がない場合は作成）
+//
+// 減算は数量が負にならない場合のみWHERE句で更新し、同時の出庫でも数量が負になりません。
+func (s *PostgreSQLStorage) AdjustConsignmentStock(ctx context.Context, ownerID, itemID, locationID string, delta int64) (*inventory.ConsignmentStock, error) {
+	var query string
+	if delta >= 0 {
+		query = `
+			INSERT INTO consignment_stocks (owner_id, item_id, location_id, quantity, updated_at)
+			VALUES ($1, $2, $3, $4, NOW())
+			ON CONFLICT (owner_id, item_id, location_id) DO UPDATE
+			SET quantity = consignment_stocks.quantity + EXCLUDED.quantity, updated_at = NOW()
+			RETURNING ` + consignmentStockColumns
+	} else {
+		query = `
+			UPDATE consignment_stocks
+			SET quantity = quantity + $4, updated_at = NOW()
+			WHERE owner_id = $1 AND item_id = $2 AND location_id = $3 AND quantity + $4 >= 0
+			RETURNING ` + consignmentStockColumns
+	}
+
+	consignment := &inventory.ConsignmentStock{}
+	if err := scanConsignmentStock(s.conn.QueryRowContext(ctx, query, ownerID, itemID, locationID, delta), consignment); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrInsufficientConsignmentStock
+		}
+		return nil, fmt.Errorf("委託在庫の更新に失敗しました: %w", err)
+	}
+
+	return consignment, nil
+}
+
+// ListConsignmentStocks lists consignment stocks matching a filter ordered by owner, item and location
+// 条件に一致する委託在庫を所有者ID・商品ID・ロケーションIDの昇順で取得
+func (s *PostgreSQLStorage) ListConsignmentStocks(ctx context.Context, filter inventory.ConsignmentStockFilter) ([]inventory.ConsignmentStock, error) {
+	query := `
+		SELECT ` + consignmentStockColumns + `
+		FROM consignment_stocks
+		WHERE ($1 = '' OR owner_id = $1) AND ($2 = '' OR item_id = $2) AND ($3 = '' OR location_id = $3)
+			AND ($4 OR quantity <> 0)
+		ORDER BY owner_id, item_id, location_id
+		OFFSET $5`
+	args := []interface{}{filter.OwnerID, filter.ItemID, filter.LocationID, filter.IncludeEmpty, filter.Offset}
+	if filter.Limit > 0 {
+		query += ` LIMIT $6`
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.reader(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("委託在庫一覧の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	consignments := make([]inventory.ConsignmentStock, 0)
+	for rows.Next() {
+		var consignment inventory.ConsignmentStock
+		if err := scanConsignmentStock(rows, &consignment); err != nil {
+			return nil, fmt.Errorf("委託在庫スキャンに失敗しました: %w", err)
+		}
+		consignments = append(consignments, consignment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("委託在庫スキャンに失敗しました: %w", err)
+	}
+
+	return consignments, nil
+}
+
+// alertColumns are the columns scanned by scanAlert
+// scanAlert で読み取るアラートの列
+const alertColumns = `id, type, item_id, location_id, current_qty, threshold, message, is_active, created_at, resolved_at,
+	COALESCE(rule_id, ''), COALESCE(severity, ''), acknowledged_at, COALESCE(acknowledged_by, ''), COALESCE(acknowledge_note, ''),
+	COALESCE(resolved_by, ''), COALESCE(resolve_note, '')`
+
+// CreateAlert creates a new stock alert
+// 新しい在庫アラートを作成
+func (s *PostgreSQLStorage) CreateAlert(ctx context.Context, alert *inventory.StockAlert) error {
+	query := `
+		INSERT INTO stock_alerts (id, type, item_id, location_id, current_qty, threshold, message, is_active, created_at, rule_id, severity)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''), NULLIF($11, ''))`
+
+	_, err := s.conn.ExecContext(ctx, query,
+		alert.ID,
+		alert.Type,
+		alert.ItemID,
+		alert.LocationID,
+		alert.CurrentQty,
+		alert.Threshold,
+		alert.Message,
+		alert.IsActive,
+		alert.CreatedAt,
+		alert.RuleID,
+		alert.Severity,
+	)
+
+	if err != nil {
+		return fmt.Errorf("アラート作成に失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// GetActiveAlerts retrieves active alerts for a location
+// ロケーションのアクティブアラートを取得
+func (s *PostgreSQLStorage) GetActiveAlerts(ctx context.Context, locationID string) ([]inventory.StockAlert, error) {
+	query := `SELECT ` + alertColumns + `
+		FROM stock_alerts 
+		WHERE location_id = $1 AND is_active = true
+		ORDER BY created_at DESC`
+
+	rows, err := s.conn.QueryContext(ctx, query, locationID)
+	if err != nil {
+		return nil, fmt.Errorf("アラート取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []inventory.StockAlert
+	for rows.Next() {
+		alert, err := scanAlert(rows)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+// ListAlerts lists alerts of all locations matching a filter, newest first
+// 条件に一致するアラートを全ロケーションから作成日時の新しい順に取得（解決済みを含む）
+func (s *PostgreSQLStorage) ListAlerts(ctx context.Context, filter inventory.AlertFilter) ([]inventory.StockAlert, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if filter.Type != "" {
+		args = append(args, filter.Type)
+		conditions = append(conditions, fmt.Sprintf("type = $%d", len(args)))
+	}
+	if filter.Severity != "" {
+		args = append(args, filter.Severity)
+		conditions = append(conditions, fmt.Sprintf("severity = $%d", len(args)))
+	}
+	if filter.Active != nil {
+		args = append(args, *filter.Active)
+		conditions = append(conditions, fmt.Sprintf("is_active = $%d", len(args)))
+	}
+	if filter.Acknowledged != nil {
+		if *filter.Acknowledged {
+			conditions = append(conditions, "acknowledged_at IS NOT NULL")
+		} else {
+			conditions = append(conditions, "acknowledged_at IS NULL")
+		}
+	}
+	if filter.ItemID != "" {
+		args = append(args, filter.ItemID)
+		conditions = append(conditions, fmt.Sprintf("item_id = $%d", len(args)))
+	}
+	if filter.LocationID != "" {
+		args = append(args, filter.LocationID)
+		conditions = append(conditions, fmt.Sprintf("location_id = $%d", len(args)))
+	}
+	if !filter.CreatedAfter.IsZero() {
+		args = append(args, filter.CreatedAfter)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+	if !filter.CreatedBefore.IsZero() {
+		args = append(args, filter.CreatedBefore)
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+
+	query := `SELECT ` + alertColumns + ` FROM stock_alerts`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY created_at DESC, id DESC`
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	// ダッシュボード向けの一覧のためレプリカから読み取る
+	rows, err := s.reader(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("アラート一覧取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	alerts := make([]inventory.StockAlert, 0)
+	for rows.Next() {
+		alert, err := scanAlert(rows)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, alert)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("アラート一覧取得に失敗しました: %w", err)
+	}
+
+	return alerts, nil
+}
+
+// GetAlert retrieves an alert by ID, including resolved alerts
+// IDでアラートを取得（解決済みを含む）
+func (s *PostgreSQLStorage) GetAlert(ctx context.Context, alertID string) (*inventory.StockAlert, error) {
+	query := `SELECT ` + alertColumns + ` FROM stock_alerts WHERE id = $1`
+
+	return s.queryAlert(ctx, query, alertID)
+}
+
+// ResolveAlert resolves an active alert by setting it inactive
+// アクティブなアラートを非アクティブにして解決
+func (s *PostgreSQLStorage) ResolveAlert(ctx context.Context, alertID, resolvedBy, note string) error {
+	now := time.Now()
+	query := `
+		UPDATE stock_alerts 
+		SET is_active = false, resolved_at = $2, resolved_by = $3, resolve_note = NULLIF($4, '')
+		WHERE id = $1 AND is_active = true`
+
+	result, err := s.conn.ExecContext(ctx, query, alertID, now, resolvedBy, note)
+	if err != nil {
+		return fmt.Errorf("アラート解決に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return s.alertUpdateConflict(ctx, alertID)
+	}
+
+	return nil
+}
+
+// AcknowledgeAlert marks an active alert as acknowledged
+// アクティブなアラートを確認済みにする
+func (s *PostgreSQLStorage) AcknowledgeAlert(ctx context.Context, alertID, acknowledgedBy, note string) error {
+	now := time.Now()
+	query := `
+		UPDATE stock_alerts
+		SET acknowledged_at = $2, acknowledged_by = $3, acknowledge_note = NULLIF($4, '')
+		WHERE id = $1 AND is_active = true AND acknowledged_at IS NULL`
+
+	result, err := s.conn.ExecContext(ctx, query, alertID, now, acknowledgedBy, note)
+	if err != nil {
+		return fmt.Errorf("アラートの確認に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return s.alertUpdateConflict(ctx, alertID)
+	}
+
+	return nil
+}
+
+// alertUpdateConflict explains why a conditional update of an alert matched no row
+// アラートの条件付き更新が行に一致しなかった理由をエラーとして返す
+func (s *PostgreSQLStorage) alertUpdateConflict(ctx context.Context, alertID string) error {
+	alert, err := s.GetAlert(ctx, alertID)
+	if err != nil {
+		return err
+	}
+	if !alert.IsActive {
+		return inventory.ErrAlertNotActive
+	}
+	return inventory.ErrAlertAlreadyAcknowledged
+}
+
+// GetLatestRuleAlert retrieves the latest alert an alert rule raised for a stock record
+// アラートルールが在庫記録に対して最後に作成したアラートを取得（解決済みを含む）
+//
+// 在庫の更新直後に重複・クールダウンを判定するため、プライマリから読み取ります。
+func (s *PostgreSQLStorage) GetLatestRuleAlert(ctx context.Context, ruleID, itemID, locationID string) (*inventory.StockAlert, error) {
+	query := `SELECT ` + alertColumns + `
+		FROM stock_alerts
+		WHERE rule_id = $1 AND item_id = $2 AND location_id = $3
+		ORDER BY created_at DESC
+		LIMIT 1`
+
+	return s.queryAlert(ctx, query, ruleID, itemID, locationID)
+}
+
+// queryAlert runs a query selecting alertColumns from the primary and scans the first row
+// alertColumns を選択するクエリをプライマリで実行し、最初の行を読み取る（行がない場合はErrAlertNotFound）
+func (s *PostgreSQLStorage) queryAlert(ctx context.Context, query string, args ...interface{}) (*inventory.StockAlert, error) {
+	rows, err := s.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("アラート取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("アラート取得に失敗しました: %w", err)
+		}
+		return nil, inventory.ErrAlertNotFound
+	}
+	alert, err := scanAlert(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &alert, nil
+}
+
+// scanAlert scans a row selected with alertColumns
+// alertColumns で選択した行をアラートとして読み取る
+func scanAlert(rows *sql.Rows) (inventory.StockAlert, error) {
+	var alert inventory.StockAlert
+	err := rows.Scan(
+		&alert.ID,
+		&alert.Type,
+		&alert.ItemID,
+		&alert.LocationID,
+		&alert.CurrentQty,
+		&alert.Threshold,
+		&alert.Message,
+		&alert.IsActive,
+		&alert.CreatedAt,
+		&alert.ResolvedAt,
+		&alert.RuleID,
+		&alert.Severity,
+		&alert.AcknowledgedAt,
+		&alert.AcknowledgedBy,
+		&alert.AcknowledgeNote,
+		&alert.ResolvedBy,
+		&alert.ResolveNote,
+	)
+	if err != nil {
+		return inventory.StockAlert{}, fmt.Errorf("アラートスキャンに失敗しました: %w", err)
+	}
+
+	return alert, nil
+}
+
+// alertRuleColumns are the columns scanned by scanAlertRule
+// scanAlertRule で読み取るアラートルールの列
+const alertRuleColumns = `id, name, metric, comparator, threshold, COALESCE(item_id, ''), COALESCE(location_id, ''), severity, cooldown_seconds, enabled, created_at, updated_at, COALESCE(updated_by, '')`
+
+// CreateAlertRule creates a new alert rule
+// 新しいアラートルールを作成
+func (s *PostgreSQLStorage) CreateAlertRule(ctx context.Context, rule *inventory.AlertRule) error {
+	query := `
+		INSERT INTO alert_rules (id, name, metric, comparator, threshold, item_id, location_id, severity, cooldown_seconds, enabled, created_at, updated_at, updated_by)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''), $8, $9, $10, $11, $12, $13)`
+
+	_, err := s.conn.ExecContext(ctx, query,
+		rule.ID,
+		rule.Name,
+		rule.Metric,
+		rule.Comparator,
+		rule.Threshold,
+		rule.ItemID,
+		rule.LocationID,
+		rule.Severity,
+		rule.CooldownSeconds,
+		rule.Enabled,
+		rule.CreatedAt,
+		rule.UpdatedAt,
+		rule.UpdatedBy,
+	)
+	if err != nil {
+		return fmt.Errorf("アラートルールの作成に失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// GetAlertRule retrieves an alert rule by ID
+// IDでアラートルールを取得
+func (s *PostgreSQLStorage) GetAlertRule(ctx context.Context, ruleID string) (*inventory.AlertRule, error) {
+	query := `SELECT ` + alertRuleColumns + ` FROM alert_rules WHERE id = $1`
+
+	rows, err := s.reader(ctx).QueryContext(ctx, query, ruleID)
+	if err != nil {
+		return nil, fmt.Errorf("アラートルールの取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("アラートルールの取得に失敗しました: %w", err)
+		}
+		return nil, inventory.ErrAlertRuleNotFound
+	}
+	rule, err := scanAlertRule(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+// UpdateAlertRule replaces the condition, scope and state of an alert rule
+// アラートルールの条件・対象範囲・有効状態を更新
+func (s *PostgreSQLStorage) UpdateAlertRule(ctx context.Context, rule *inventory.AlertRule) error {
+	query := `
+		UPDATE alert_rules
+		SET name = $2, metric = $3, comparator = $4, threshold = $5, item_id = NULLIF($6, ''), location_id = NULLIF($7, ''),
+			severity = $8, cooldown_seconds = $9, enabled = $10, updated_at = $11, updated_by = $12
+		WHERE id = $1`
+
+	result, err := s.conn.ExecContext(ctx, query,
+		rule.ID,
+		rule.Name,
+		rule.Metric,
+		rule.Comparator,
+		rule.Threshold,
+		rule.ItemID,
+		rule.LocationID,
+		rule.Severity,
+		rule.CooldownSeconds,
+		rule.Enabled,
+		rule.UpdatedAt,
+		rule.UpdatedBy,
+	)
+	if err != nil {
+		return fmt.Errorf("アラートルールの更新に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+	if rowsAffected == 0 {
+		return inventory.ErrAlertRuleNotFound
+	}
+
+	return nil
+}
+
+// DeleteAlertRule deletes an alert rule
+// アラートルールを削除
+func (s *PostgreSQLStorage) DeleteAlertRule(ctx context.Context, ruleID string) error {
+	query := `DELETE FROM alert_rules WHERE id = $1`
+
+	result, err := s.conn.ExecContext(ctx, query, ruleID)
+	if err != nil {
+		return fmt.Errorf("アラートルールの削除に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("削除行数の取得に失敗しました: %w", err)
+	}
+	if rowsAffected == 0 {
+		return inventory.ErrAlertRuleNotFound
+	}
+
+	return nil
+}
+
+// ListAlertRules lists alert rules matching a filter, oldest first
+// 条件に一致するアラートルールを作成日時の昇順で取得
+func (s *PostgreSQLStorage) ListAlertRules(ctx context.Context, filter inventory.AlertRuleFilter) ([]inventory.AlertRule, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if filter.ItemID != "" {
+		args = append(args, filter.ItemID)
+		conditions = append(conditions, fmt.Sprintf("item_id = $%d", len(args)))
+	}
+	if filter.LocationID != "" {
+		args = append(args, filter.LocationID)
+		conditions = append(conditions, fmt.Sprintf("location_id = $%d", len(args)))
+	}
+	if filter.EnabledOnly {
+		conditions = append(conditions, "enabled = true")
+	}
+
+	query := `SELECT ` + alertRuleColumns + ` FROM alert_rules`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY created_at ASC, id ASC`
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	return s.queryAlertRules(ctx, s.reader(ctx), query, args...)
+}
+
+// GetAlertRulesForStock retrieves the enabled alert rules whose scope covers a stock record
+// 在庫記録を対象範囲に含む有効なアラートルールを取得
+//
+// 在庫の更新直後に判定するため、プライマリから読み取ります。
+func (s *PostgreSQLStorage) GetAlertRulesForStock(ctx context.Context, itemID, locationID string) ([]inventory.AlertRule, error) {
+	query := `SELECT ` + alertRuleColumns + ` FROM alert_rules
+		WHERE enabled = true
+			AND (item_id IS NULL OR item_id = $1)
+			AND (location_id IS NULL OR location_id = $2)
+		ORDER BY created_at ASC, id ASC`
+
+	return s.queryAlertRules(ctx, s.conn, query, itemID, locationID)
+}
+
+// queryAlertRules runs a query selecting alertRuleColumns and scans every row
+// alertRuleColumns を選択するクエリを実行し、全ての行を読み取る
+func (s *PostgreSQLStorage) queryAlertRules(ctx context.Context, db querier, query string, args ...interface{}) ([]inventory.AlertRule, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("アラートルール一覧の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	rules := make([]inventory.AlertRule, 0)
+	for rows.Next() {
+		rule, err := scanAlertRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("アラートルールスキャンに失敗しました: %w", err)
+	}
+
+	return rules, nil
+}
+
+// scanAlertRule scans a row selected with alertRuleColumns
+// alertRuleColumns で選択した行をアラートルールとして読み取る
+func scanAlertRule(rows *sql.Rows) (inventory.AlertRule, error) {
+	var rule inventory.AlertRule
+	err := rows.Scan(
+		&rule.ID,
+		&rule.Name,
+		&rule.Metric,
+		&rule.Comparator,
+		&rule.Threshold,
+		&rule.ItemID,
+		&rule.LocationID,
+		&rule.Severity,
+		&rule.CooldownSeconds,
+		&rule.Enabled,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+		&rule.UpdatedBy,
+	)
+	if err != nil {
+		return inventory.AlertRule{}, fmt.Errorf("アラートルールスキャンに失敗しました: %w", err)
+	}
+
+	return rule, nil
+}
+
+// reservationColumns are the columns scanned by scanReservation
+// scanReservation で読み取る予約の列
+const reservationColumns = `id, item_id, location_id, quantity, COALESCE(reference, ''), status, expires_at, created_at, COALESCE(created_by, ''), released_at, channel`
+
+// CreateReservation creates a new reservation
+// 新しい予約を作成
+func (s *PostgreSQLStorage) CreateReservation(ctx context.Context, reservation *inventory.Reservation) error {
+	query := `
+		INSERT INTO reservations (id, item_id, location_id, quantity, reference, status, expires_at, created_at, created_by, released_at, channel)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	_, err := s.conn.ExecContext(ctx, query,
+		reservation.ID,
+		reservation.ItemID,
+		reservation.LocationID,
+		reservation.Quantity,
+		reservation.Reference,
+		reservation.Status,
+		reservation.ExpiresAt,
+		reservation.CreatedAt,
+		reservation.CreatedBy,
+		reservation.ReleasedAt,
+		reservation.Channel,
+	)
+	if err != nil {
+		return fmt.Errorf("予約作成に失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// GetReservation retrieves a reservation by ID
+// IDで予約を取得
+func (s *PostgreSQLStorage) GetReservation(ctx context.Context, reservationID string) (*inventory.Reservation, error) {
+	query := `SELECT ` + reservationColumns + ` FROM reservations WHERE id = $1`
+
+	rows, err := s.conn.QueryContext(ctx, query, reservationID)
+	if err != nil {
+		return nil, fmt.Errorf("予約取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("予約取得に失敗しました: %w", err)
+		}
+		return nil, inventory.ErrReservationNotFound
+	}
+	reservation, err := scanReservation(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reservation, nil
+}
+
+// UpdateReservation updates an active reservation
+// 有効な予約を更新
+//
+// 解除・期限切れの競合で二重に在庫を解放しないよう、有効な予約のみをWHERE句で更新し、
+// 0件更新の場合は予約の有無で ErrReservationNotFound と ErrReservationNotActive を区別します。
+func (s *PostgreSQLStorage) UpdateReservation(ctx context.Context, reservation *inventory.Reservation) error {
+	query := `
+		UPDATE reservations
+		SET quantity = $2, status = $3, expires_at = $4, released_at = $5
+		WHERE id = $1 AND status = 'active'`
+
+	result, err := s.conn.ExecContext(ctx, query,
+		reservation.ID,
+		reservation.Quantity,
+		reservation.Status,
+		reservation.ExpiresAt,
+		reservation.ReleasedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("予約更新に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		if _, err := s.GetReservation(ctx, reservation.ID); err != nil {
+			return err
+		}
+		return inventory.ErrReservationNotActive
+	}
+
+	return nil
+}
+
+// ListReservations lists reservations matching a filter, newest first
+// 条件に一致する予約を作成日時の降順で取得
+func (s *PostgreSQLStorage) ListReservations(ctx context.Context, filter inventory.ReservationFilter) ([]inventory.Reservation, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if filter.ItemID != "" {
+		args = append(args, filter.ItemID)
+		conditions = append(conditions, fmt.Sprintf("item_id = $%d", len(args)))
+	}
+	if filter.LocationID != "" {
+		args = append(args, filter.LocationID)
+		conditions = append(conditions, fmt.Sprintf("location_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.Reference != "" {
+		args = append(args, filter.Reference)
+		conditions = append(conditions, fmt.Sprintf("reference = $%d", len(args)))
+	}
+	if filter.Channel != "" {
+		args = append(args, filter.Channel)
+		conditions = append(conditions, fmt.Sprintf("channel = $%d", len(args)))
+	}
+
+	query := `SELECT ` + reservationColumns + ` FROM reservations`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY created_at DESC, id DESC`
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	rows, err := s.reader(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("予約一覧取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	return scanReservations(rows)
+}
+
+// GetExpiredReservations retrieves active reservations that expired at or before a time
+// 有効期限がbefore以前の有効な予約を取得
+//
+// 期限切れの処理は直後に予約を更新するため、プライマリから読み取ります。
+func (s *PostgreSQLStorage) GetExpiredReservations(ctx context.Context, before time.Time, limit int) ([]inventory.Reservation, error) {
+	query := `SELECT ` + reservationColumns + `
+		FROM reservations
+		WHERE status = 'active' AND expires_at IS NOT NULL AND expires_at <= $1
+		ORDER BY expires_at ASC`
+	args := []interface{}{before}
+	if limit > 0 {
+		query += ` LIMIT $2`
+		args = append(args, limit)
+	}
+
+	rows, err := s.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("期限切れ予約取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	return scanReservations(rows)
+}
+
+// SetChannelAllocation creates or updates the sales channel allocation of an item at a location, deleting it when the quantity is 0
+// 商品のロケーションの販売チャネルの確保数量を作成・更新（数量が0の場合は削除）
+func (s *PostgreSQLStorage) SetChannelAllocation(ctx context.Context, allocation *inventory.ChannelAllocation) error {
+	if allocation.Quantity == 0 {
+		query := `DELETE FROM channel_allocations WHERE item_id = $1 AND location_id = $2 AND channel = $3`
+		if _, err := s.conn.ExecContext(ctx, query, allocation.ItemID, allocation.LocationID, allocation.Channel); err != nil {
+			return fmt.Errorf("販売チャネルの確保の削除に失敗しました: %w", err)
+		}
+		return nil
+	}
+
+	query := `
+		INSERT INTO channel_allocations (item_id, location_id, channel, quantity, updated_at, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (item_id, location_id, channel) DO UPDATE
+		SET quantity = EXCLUDED.quantity, updated_at = EXCLUDED.updated_at, updated_by = EXCLUDED.updated_by`
+	_, err := s.conn.ExecContext(ctx, query,
+		allocation.ItemID,
+		allocation.LocationID,
+		allocation.Channel,
+		allocation.Quantity,
+		allocation.UpdatedAt,
+		allocation.UpdatedBy,
+	)
+	if err != nil {
+		return fmt.Errorf("販売チャネルの確保の保存に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// ListChannelAllocations lists the sales channel allocations of an item at a location ordered by channel
+// 商品のロケーションの販売チャネルの確保をチャネル名の昇順で取得
+//
+// 予約の可否の判定で予約と同じトランザクションから読み取るため、プライマリから読み取ります。
+func (s *PostgreSQLStorage) ListChannelAllocations(ctx context.Context, itemID, locationID string) ([]inventory.ChannelAllocation, error) {
+	query := `
+		SELECT item_id, location_id, channel, quantity, updated_at, updated_by
+		FROM channel_allocations
+		WHERE item_id = $1 AND location_id = $2
+		ORDER BY channel`
+
+	rows, err := s.conn.QueryContext(ctx, query, itemID, locationID)
+	if err != nil {
+		return nil, fmt.Errorf("販売チャネルの確保の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	allocations := make([]inventory.ChannelAllocation, 0)
+	for rows.Next() {
+		var allocation inventory.ChannelAllocation
+		if err := rows.Scan(
+			&allocation.ItemID,
+			&allocation.LocationID,
+			&allocation.Channel,
+			&allocation.Quantity,
+			&allocation.UpdatedAt,
+			&allocation.UpdatedBy,
+		); err != nil {
+			return nil, fmt.Errorf("販売チャネルの確保のスキャンに失敗しました: %w", err)
+		}
+		allocations = append(allocations, allocation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("販売チャネルの確保のスキャンに失敗しました: %w", err)
+	}
+
+	return allocations, nil
+}
+
+// scanReservations scans every row into reservations
+// 全ての行を予約として読み取る
+func scanReservations(rows *sql.Rows) ([]inventory.Reservation, error) {
+	reservations := make([]inventory.Reservation, 0)
+	for rows.Next() {
+		reservation, err := scanReservation(rows)
+		if err != nil {
+			return nil, err
+		}
+		reservations = append(reservations, reservation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("予約スキャンに失敗しました: %w", err)
+	}
+
+	return reservations, nil
+}
+
+// scanReservation scans a row selected with reservationColumns
+// reservationColumns で選択した行を予約として読み取る
+func scanReservation(rows *sql.Rows) (inventory.Reservation, error) {
+	var reservation inventory.Reservation
+	err := rows.Scan(
+		&reservation.ID,
+		&reservation.ItemID,
+		&reservation.LocationID,
+		&reservation.Quantity,
+		&reservation.Reference,
+		&reservation.Status,
+		&reservation.ExpiresAt,
+		&reservation.CreatedAt,
+		&reservation.CreatedBy,
+		&reservation.ReleasedAt,
+		&reservation.Channel,
+	)
+	if err != nil {
+		return inventory.Reservation{}, fmt.Errorf("予約スキャンに失敗しました: %w", err)
+	}
+
+	return reservation, nil
+}
+
+// backorderColumns are the columns scanned by scanBackorder
+// scanBackorder で読み取るバックオーダーの列
+const backorderColumns = `id, item_id, location_id, quantity, COALESCE(reference, ''), status, COALESCE(reservation_id, ''), created_at, COALESCE(created_by, ''), closed_at`
+
+// CreateBackorder creates a new backorder
+// 新しいバックオーダーを作成
+func (s *PostgreSQLStorage) CreateBackorder(ctx context.Context, backorder *inventory.Backorder) error {
+	query := `
+		INSERT INTO backorders (id, item_id, location_id, quantity, reference, status, reservation_id, created_at, created_by, closed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9, $10)`
+
+	_, err := s.conn.ExecContext(ctx, query,
+		backorder.ID,
+		backorder.ItemID,
+		backorder.LocationID,
+		backorder.Quantity,
+		backorder.Reference,
+		backorder.Status,
+		backorder.ReservationID,
+		backorder.CreatedAt,
+		backorder.CreatedBy,
+		backorder.ClosedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("バックオーダー作成に失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// GetBackorder retrieves a backorder by ID
+// IDでバックオーダーを取得
+func (s *PostgreSQLStorage) GetBackorder(ctx context.Context, backorderID string) (*inventory.Backorder, error) {
+	query := `SELECT ` + backorderColumns + ` FROM backorders WHERE id = $1`
+
+	rows, err := s.conn.QueryContext(ctx, query, backorderID)
+	if err != nil {
+		return nil, fmt.Errorf("バックオーダー取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("バックオーダー取得に失敗しました: %w", err)
+		}
+		return nil, inventory.ErrBackorderNotFound
+	}
+	backorder, err := scanBackorder(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &backorder, nil
+}
+
+// UpdateBackorder updates a pending backorder
+// 入荷待ちのバックオーダーを更新
+//
+// 引当・取消の競合で二重に予約を作成しないよう、入荷待ちのバックオーダーのみをWHERE句で更新し、
+// 0件更新の場合はバックオーダーの有無で ErrBackorderNotFound と ErrBackorderNotPending を区別します。
+func (s *PostgreSQLStorage) UpdateBackorder(ctx context.Context, backorder *inventory.Backorder) error {
+	query := `
+		UPDATE backorders
+		SET status = $2, reservation_id = NULLIF($3, ''), closed_at = $4
+		WHERE id = $1 AND status = 'pending'`
+
+	result, err := s.conn.ExecContext(ctx, query,
+		backorder.ID,
+		backorder.Status,
+		backorder.ReservationID,
+		backorder.ClosedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("バックオーダー更新に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		if _, err := s.GetBackorder(ctx, backorder.ID); err != nil {
+			return err
+		}
+		return inventory.ErrBackorderNotPending
+	}
+
+	return nil
+}
+
+// ListBackorders lists backorders matching a filter, oldest first
+// 条件に一致するバックオーダーを作成日時の昇順で取得
+//
+// 入荷待ちのバックオーダーは引当の直前に読み取るため、プライマリから読み取ります。
+func (s *PostgreSQLStorage) ListBackorders(ctx context.Context, filter inventory.BackorderFilter) ([]inventory.Backorder, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if filter.ItemID != "" {
+		args = append(args, filter.ItemID)
+		conditions = append(conditions, fmt.Sprintf("item_id = $%d", len(args)))
+	}
+	if filter.LocationID != "" {
+		args = append(args, filter.LocationID)
+		conditions = append(conditions, fmt.Sprintf("location_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.Reference != "" {
+		args = append(args, filter.Reference)
+		conditions = append(conditions, fmt.Sprintf("reference = $%d", len(args)))
+	}
+
+	query := `SELECT ` + backorderColumns + ` FROM backorders`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY created_at ASC, id ASC`
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	rows, err := s.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("バックオーダー一覧取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	backorders := make([]inventory.Backorder, 0)
+	for rows.Next() {
+		backorder, err := scanBackorder(rows)
+		if err != nil {
+			return nil, err
+		}
+		backorders = append(backorders, backorder)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("バックオーダースキャンに失敗しました: %w", err)
+	}
+
+	return backorders, nil
+}
+
+// reorderPointColumns are the columns scanned by scanReorderPoint
+// scanReorderPoint で読み取る発注点の列
+const reorderPointColumns = `item_id, location_id, reorder_point, min_qty, max_qty, updated_at, COALESCE(updated_by, '')`
+
+// SaveReorderPoint creates or overwrites the reorder point of an item at a location
+// 商品・ロケーションの発注点を作成または上書き
+func (s *PostgreSQLStorage) SaveReorderPoint(ctx context.Context, reorderPoint *inventory.ReorderPoint) error {
+	query := `
+		INSERT INTO reorder_points (item_id, location_id, reorder_point, min_qty, max_qty, updated_at, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (item_id, location_id) DO UPDATE SET
+			reorder_point = EXCLUDED.reorder_point,
+			min_qty = EXCLUDED.min_qty,
+			max_qty = EXCLUDED.max_qty,
+			updated_at = EXCLUDED.updated_at,
+			updated_by = EXCLUDED.updated_by`
+
+	_, err := s.conn.ExecContext(ctx, query,
+		reorderPoint.ItemID,
+		reorderPoint.LocationID,
+		reorderPoint.ReorderPointQty,
+		reorderPoint.MinQty,
+		reorderPoint.MaxQty,
+		reorderPoint.UpdatedAt,
+		reorderPoint.UpdatedBy,
+	)
+	if err != nil {
+		return fmt.Errorf("発注点の保存に失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// GetReorderPoint retrieves the reorder point of an item at a location
+// 商品・ロケーションの発注点を取得
+//
+// 在庫の更新直後に低在庫を判定するため、プライマリから読み取ります。
+func (s *PostgreSQLStorage) GetReorderPoint(ctx context.Context, itemID, locationID string) (*inventory.ReorderPoint, error) {
+	query := `SELECT ` + reorderPointColumns + ` FROM reorder_points WHERE item_id = $1 AND location_id = $2`
+
+	rows, err := s.conn.QueryContext(ctx, query, itemID, locationID)
+	if err != nil {
+		return nil, fmt.Errorf("発注点の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("発注点の取得に失敗しました: %w", err)
+		}
+		return nil, inventory.ErrReorderPointNotFound
+	}
+	reorderPoint, err := scanReorderPoint(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reorderPoint, nil
+}
+
+// DeleteReorderPoint deletes the reorder point of an item at a location
+// 商品・ロケーションの発注点を削除
+func (s *PostgreSQLStorage) DeleteReorderPoint(ctx context.Context, itemID, locationID string) error {
+	query := `DELETE FROM reorder_points WHERE item_id = $1 AND location_id = $2`
+
+	result, err := s.conn.ExecContext(ctx, query, itemID, locationID)
+	if err != nil {
+		return fmt.Errorf("発注点の削除に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("削除行数の取得に失敗しました: %w", err)
+	}
+	if rowsAffected == 0 {
+		return inventory.ErrReorderPointNotFound
+	}
+
+	return nil
+}
+
+// ListReorderPoints lists reorder points matching a filter, ordered by item and location
+// 条件に一致する発注点を商品ID・ロケーションIDの昇順で取得
+func (s *PostgreSQLStorage) ListReorderPoints(ctx context.Context, filter inventory.ReorderPointFilter) ([]inventory.ReorderPoint, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if filter.ItemID != "" {
+		args = append(args, filter.ItemID)
+		conditions = append(conditions, fmt.Sprintf("item_id = $%d", len(args)))
+	}
+	if filter.LocationID != "" {
+		args = append(args, filter.LocationID)
+		conditions = append(conditions, fmt.Sprintf("location_id = $%d", len(args)))
+	}
+
+	query := `SELECT ` + reorderPointColumns + ` FROM reorder_points`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY item_id ASC, location_id ASC`
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	rows, err := s.reader(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("発注点一覧の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	reorderPoints := make([]inventory.ReorderPoint, 0)
+	for rows.Next() {
+		reorderPoint, err := scanReorderPoint(rows)
+		if err != nil {
+			return nil, err
+		}
+		reorderPoints = append(reorderPoints, reorderPoint)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("発注点スキャンに失敗しました: %w", err)
+	}
+
+	return reorderPoints, nil
+}
+
+// scanReorderPoint scans a row selected with reorderPointColumns
+// reorderPointColumns で選択した行を発注点として読み取る
+func scanReorderPoint(rows *sql.Rows) (inventory.ReorderPoint, error) {
+	var reorderPoint inventory.ReorderPoint
+	err := rows.Scan(
+		&reorderPoint.ItemID,
+		&reorderPoint.LocationID,
+		&reorderPoint.ReorderPointQty,
+		&reorderPoint.MinQty,
+		&reorderPoint.MaxQty,
+		&reorderPoint.UpdatedAt,
+		&reorderPoint.UpdatedBy,
+	)
+	if err != nil {
+		return inventory.ReorderPoint{}, fmt.Errorf("発注点スキャンに失敗しました: %w", err)
+	}
+
+	return reorderPoint, nil
+}
+
+// scanBackorder scans a row selected with backorderColumns
+// backorderColumns で選択した行をバックオーダーとして読み取る
+func scanBackorder(rows *sql.Rows) (inventory.Backorder, error) {
+	var backorder inventory.Backorder
+	err := rows.Scan(
+		&backorder.ID,
+		&backorder.ItemID,
+		&backorder.LocationID,
+		&backorder.Quantity,
+		&backorder.Reference,
+		&backorder.Status,
+		&backorder.ReservationID,
+		&backorder.CreatedAt,
+		&backorder.CreatedBy,
+		&backorder.ClosedAt,
+	)
+	if err != nil {
+		return inventory.Backorder{}, fmt.Errorf("バックオーダースキャンに失敗しました: %w", err)
+	}
+
+	return backorder, nil
+}
+
+// CreateStockSnapshot copies every stock record into a new snapshot
+// 全ての在庫記録を複製してスナップショットを作成
+//
+// 在庫記録の複製は単一の INSERT ... SELECT で行うため、取得中の在庫操作による不整合は生じません。
+func (s *PostgreSQLStorage) CreateStockSnapshot(ctx context.Context, snapshot *inventory.StockSnapshot) error {
+	return s.WithinTx(ctx, func(txStorage inventory.Storage) error {
+		conn := txStorage.(*PostgreSQLStorage).conn
+
+		_, err := conn.ExecContext(ctx,
+			`INSERT INTO stock_snapshots (id, taken_at, taken_by) VALUES ($1, $2, $3)`,
+			snapshot.ID, snapshot.TakenAt, snapshot.TakenBy)
+		if err != nil {
+			return fmt.Errorf("在庫スナップショットの作成に失敗しました: %w", err)
+		}
+
+		query := `
+			WITH lines AS (
+				INSERT INTO stock_snapshot_lines (snapshot_id, item_id, location_id, quantity, reserved)
+				SELECT $1, item_id, location_id, quantity, reserved FROM stocks
+				RETURNING quantity, reserved
+			)
+			UPDATE stock_snapshots
+			SET stock_count = totals.stock_count,
+			    total_quantity = totals.total_quantity,
+			    total_reserved = totals.total_reserved
+			FROM (
+				SELECT COUNT(*) AS stock_count,
+				       COALESCE(SUM(quantity), 0) AS total_quantity,
+				       COALESCE(SUM(reserved), 0) AS total_reserved
+				FROM lines
+			) totals
+			WHERE stock_snapshots.id = $1
+			RETURNING stock_snapshots.stock_count, stock_snapshots.total_quantity, stock_snapshots.total_reserved`
+		err = conn.QueryRowContext(ctx, query, snapshot.ID).Scan(
+			&snapshot.StockCount,
+			&snapshot.TotalQuantity,
+			&snapshot.TotalReserved,
+		)
+		if err != nil {
+			return fmt.Errorf("在庫記録の複製に失敗しました: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListStockSnapshots lists stock snapshots, newest first
+// スナップショットを取得日時の新しい順に取得
+func (s *PostgreSQLStorage) ListStockSnapshots(ctx context.Context, offset, limit int) ([]inventory.StockSnapshot, error) {
+	query := `
+		SELECT id, taken_at, taken_by, stock_count, total_quantity, total_reserved
+		FROM stock_snapshots
+		ORDER BY taken_at DESC, id DESC
+		LIMIT $1 OFFSET $2`
+
+	rows, err := s.reader(ctx).QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("在庫スナップショット一覧の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := make([]inventory.StockSnapshot, 0)
+	for rows.Next() {
+		var snapshot inventory.StockSnapshot
 		err := rows.Scan(
-			&lot.ID,
-			&lot.Number,
-			&lot.ItemID,
-			&lot.Quantity,
-			&lot.UnitCost,
-			&lot.ExpiryDate,
-			&lot.CreatedAt,
+			&snapshot.ID,
+			&snapshot.TakenAt,
+			&snapshot.TakenBy,
+			&snapshot.StockCount,
+			&snapshot.TotalQuantity,
+			&snapshot.TotalReserved,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("ロットスキャンに失敗しました: %w", err)
+			return nil, fmt.Errorf("在庫スナップショットスキャンに失敗しました: %w", err)
 		}
-		lots = append(lots, lot)
+		snapshots = append(snapshots, snapshot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("在庫スナップショットスキャンに失敗しました: %w", err)
 	}
 
-	return lots, nil
+	return snapshots, nil
 }
 
-// GetExpiredLots retrieves lots that have already expired
-// 既に期限切れになったロットを取得
-func (s *PostgreSQLStorage) GetExpiredLots(ctx context.Context) ([]inventory.Lot, error) {
-	now := time.Now()
+// GetStockSnapshotLines retrieves the stock records copied into a snapshot
+// スナップショットの在庫記録を商品ID・ロケーションIDの昇順で取得
+func (s *PostgreSQLStorage) GetStockSnapshotLines(ctx context.Context, snapshotID, locationID string) ([]inventory.StockSnapshotLine, error) {
+	db := s.reader(ctx)
+
+	var exists bool
+	err := db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM stock_snapshots WHERE id = $1)`, snapshotID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("在庫スナップショット取得に失敗しました: %w", err)
+	}
+	if !exists {
+		return nil, inventory.ErrSnapshotNotFound
+	}
+
 	query := `
-		SELECT id, number, item_id, quantity, unit_cost, expiry_date, created_at
-		FROM lots 
-		WHERE expiry_date IS NOT NULL AND expiry_date < $1
-		ORDER BY expiry_date ASC`
+		SELECT snapshot_id, item_id, location_id, quantity, reserved
+		FROM stock_snapshot_lines
+		WHERE snapshot_id = $1 AND ($2 = '' OR location_id = $2)
+		ORDER BY item_id ASC, location_id ASC`
 
-	rows, err := s.db.QueryContext(ctx, query, now)
+	rows, err := db.QueryContext(ctx, query, snapshotID, locationID)
 	if err != nil {
-		return nil, fmt.Errorf("期限切れロット取得に失敗しました: %w", err)
+		return nil, fmt.Errorf("在庫スナップショットの在庫記録の取得に失敗しました: %w", err)
 	}
 	defer rows.Close()
 
-	var lots []inventory.Lot
+	lines := make([]inventory.StockSnapshotLine, 0)
 	for rows.Next() {
-		var lot inventory.Lot
+		var line inventory.StockSnapshotLine
+		if err := rows.Scan(&line.SnapshotID, &line.ItemID, &line.LocationID, &line.Quantity, &line.Reserved); err != nil {
+			return nil, fmt.Errorf("在庫スナップショットの在庫記録スキャンに失敗しました: %w", err)
+		}
+		lines = append(lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("在庫スナップショットの在庫記録スキャンに失敗しました: %w", err)
+	}
+
+	return lines, nil
+}
+
+// stocktakeColumns are the columns scanned into a stocktake header
+// 棚卸のヘッダーとして読み取る列
+const stocktakeColumns = `id, location_id, COALESCE(abc_class, ''), reference, status, created_at, created_by,
+	submitted_at, COALESCE(submitted_by, ''), closed_at, COALESCE(closed_by, '')`
+
+// CreateStocktake creates a stocktake with its lines
+// 棚卸と行を作成
+func (s *PostgreSQLStorage) CreateStocktake(ctx context.Context, stocktake *inventory.Stocktake) error {
+	return s.WithinTx(ctx, func(txStorage inventory.Storage) error {
+		conn := txStorage.(*PostgreSQLStorage).conn
+
+		query := `
+			INSERT INTO stocktakes (id, location_id, abc_class, reference, status, created_at, created_by,
+				submitted_at, submitted_by, closed_at, closed_by)
+			VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7, $8, NULLIF($9, ''), $10, NULLIF($11, ''))`
+		_, err := conn.ExecContext(ctx, query,
+			stocktake.ID,
+			stocktake.LocationID,
+			stocktake.ABCClass,
+			stocktake.Reference,
+			stocktake.Status,
+			stocktake.CreatedAt,
+			stocktake.CreatedBy,
+			stocktake.SubmittedAt,
+			stocktake.SubmittedBy,
+			stocktake.ClosedAt,
+			stocktake.ClosedBy,
+		)
+		if err != nil {
+			return fmt.Errorf("棚卸作成に失敗しました: %w", err)
+		}
+
+		return saveStocktakeLines(ctx, conn, stocktake.ID, stocktake.Lines)
+	})
+}
+
+// GetStocktake retrieves a stocktake with its lines ordered by item ID
+// 棚卸を行（商品IDの昇順）とともに取得
+func (s *PostgreSQLStorage) GetStocktake(ctx context.Context, stocktakeID string) (*inventory.Stocktake, error) {
+	db := s.reader(ctx)
+
+	var stocktake inventory.Stocktake
+	err := scanStocktake(db.QueryRowContext(ctx, `SELECT `+stocktakeColumns+` FROM stocktakes WHERE id = $1`, stocktakeID), &stocktake)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrStocktakeNotFound
+		}
+		return nil, fmt.Errorf("棚卸取得に失敗しました: %w", err)
+	}
+
+	query := `
+		SELECT stocktake_id, item_id, expected_qty, counted_qty, variance, counted_at, COALESCE(counted_by, ''), approved
+		FROM stocktake_lines
+		WHERE stocktake_id = $1
+		ORDER BY item_id ASC`
+
+	rows, err := db.QueryContext(ctx, query, stocktakeID)
+	if err != nil {
+		return nil, fmt.Errorf("棚卸の行の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	stocktake.Lines = make([]inventory.StocktakeLine, 0)
+	for rows.Next() {
+		var (
+			line    inventory.StocktakeLine
+			counted sql.NullInt64
+		)
 		err := rows.Scan(
-			&lot.ID,
-			&lot.Number,
-			&lot.ItemID,
-			&lot.Quantity,
-			&lot.UnitCost,
-			&lot.ExpiryDate,
-			&lot.CreatedAt,
+			&line.StocktakeID,
+			&line.ItemID,
+			&line.ExpectedQty,
+			&counted,
+			&line.Variance,
+			&line.CountedAt,
+			&line.CountedBy,
+			&line.Approved,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("ロットスキャンに失敗しました: %w", err)
+			return nil, fmt.Errorf("棚卸の行スキャンに失敗しました: %w", err)
 		}
-		lots = append(lots, lot)
+		if counted.Valid {
+			qty := counted.Int64
+			line.CountedQty = &qty
+		}
+		stocktake.Lines = append(stocktake.Lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("棚卸の行スキャンに失敗しました: %w", err)
 	}
 
-	return lots, nil
+	return &stocktake, nil
 }
 
-// CreateAlert creates a new stock alert
-// 新しい在庫アラートを作成
-func (s *PostgreSQLStorage) CreateAlert(ctx context.Context, alert *inventory.StockAlert) error {
+// ListStocktakes lists stocktakes matching a filter, newest first, without their lines
+// 条件に一致する棚卸を作成日時の新しい順に取得（行は含まない）
+func (s *PostgreSQLStorage) ListStocktakes(ctx context.Context, filter inventory.StocktakeFilter) ([]inventory.Stocktake, error) {
 	query := `
-		INSERT INTO stock_alerts (id, type, item_id, location_id, current_qty, threshold, message, is_active, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+		SELECT ` + stocktakeColumns + `
+		FROM stocktakes
+		WHERE ($1 = '' OR location_id = $1) AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC, id DESC
+		OFFSET $3`
+	args := []interface{}{filter.LocationID, string(filter.Status), filter.Offset}
+	if filter.Limit > 0 {
+		query += ` LIMIT $4`
+		args = append(args, filter.Limit)
+	}
 
-	_, err := s.db.ExecContext(ctx, query,
-		alert.ID,
-		alert.Type,
-		alert.ItemID,
-		alert.LocationID,
-		alert.CurrentQty,
-		alert.Threshold,
-		alert.Message,
-		alert.IsActive,
-		alert.CreatedAt,
+	rows, err := s.reader(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("棚卸一覧の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	stocktakes := make([]inventory.Stocktake, 0)
+	for rows.Next() {
+		var stocktake inventory.Stocktake
+		if err := scanStocktake(rows, &stocktake); err != nil {
+			return nil, fmt.Errorf("棚卸スキャンに失敗しました: %w", err)
+		}
+		stocktakes = append(stocktakes, stocktake)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("棚卸スキャンに失敗しました: %w", err)
+	}
+
+	return stocktakes, nil
+}
+
+// UpdateStocktake updates the status of a stocktake in status from and saves the given lines
+// 状態がfromの棚卸の状態を更新し、指定した行を作成または上書き
+//
+// 確定・調整・取消の競合で二重に処理しないよう、状態がfromの棚卸のみをWHERE句で更新し、
+// 0件更新の場合は棚卸の有無で ErrStocktakeNotFound と ErrStocktakeStatus を区別します。
+func (s *PostgreSQLStorage) UpdateStocktake(ctx context.Context, stocktake *inventory.Stocktake, from inventory.StocktakeStatus) error {
+	return s.WithinTx(ctx, func(txStorage inventory.Storage) error {
+		conn := txStorage.(*PostgreSQLStorage).conn
+
+		query := `
+			UPDATE stocktakes
+			SET status = $3, submitted_at = $4, submitted_by = NULLIF($5, ''), closed_at = $6, closed_by = NULLIF($7, '')
+			WHERE id = $1 AND status = $2`
+		result, err := conn.ExecContext(ctx, query,
+			stocktake.ID,
+			from,
+			stocktake.Status,
+			stocktake.SubmittedAt,
+			stocktake.SubmittedBy,
+			stocktake.ClosedAt,
+			stocktake.ClosedBy,
+		)
+		if err != nil {
+			return fmt.Errorf("棚卸更新に失敗しました: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+		}
+		if rowsAffected == 0 {
+			var exists bool
+			err := conn.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM stocktakes WHERE id = $1)`, stocktake.ID).Scan(&exists)
+			if err != nil {
+				return fmt.Errorf("棚卸取得に失敗しました: %w", err)
+			}
+			if !exists {
+				return inventory.ErrStocktakeNotFound
+			}
+			return inventory.ErrStocktakeStatus
+		}
+
+		return saveStocktakeLines(ctx, conn, stocktake.ID, stocktake.Lines)
+	})
+}
+
+// saveStocktakeLines creates or overwrites the lines of a stocktake
+// 棚卸の行を作成または上書き
+func saveStocktakeLines(ctx context.Context, conn querier, stocktakeID string, lines []inventory.StocktakeLine) error {
+	query := `
+		INSERT INTO stocktake_lines (stocktake_id, item_id, expected_qty, counted_qty, variance, counted_at, counted_by, approved)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8)
+		ON CONFLICT (stocktake_id, item_id) DO UPDATE
+		SET expected_qty = EXCLUDED.expected_qty,
+		    counted_qty = EXCLUDED.counted_qty,
+		    variance = EXCLUDED.variance,
+		    counted_at = EXCLUDED.counted_at,
+		    counted_by = EXCLUDED.counted_by,
+		    approved = EXCLUDED.approved`
+
+	for _, line := range lines {
+		_, err := conn.ExecContext(ctx, query,
+			stocktakeID,
+			line.ItemID,
+			line.ExpectedQty,
+			line.CountedQty,
+			line.Variance,
+			line.CountedAt,
+			line.CountedBy,
+			line.Approved,
+		)
+		if err != nil {
+			return fmt.Errorf("棚卸の行の保存に失敗しました: %w", err)
+		}
+	}
+	return nil
+}
+
+// rowScanner is a *sql.Row or *sql.Rows
+// *sql.Row と *sql.Rows の共通のインターフェース
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanStocktake scans the stocktakeColumns of a row into a stocktake
+// 行の stocktakeColumns を棚卸に読み取る
+func scanStocktake(row rowScanner, stocktake *inventory.Stocktake) error {
+	return row.Scan(
+		&stocktake.ID,
+		&stocktake.LocationID,
+		&stocktake.ABCClass,
+		&stocktake.Reference,
+		&stocktake.Status,
+		&stocktake.CreatedAt,
+		&stocktake.CreatedBy,
+		&stocktake.SubmittedAt,
+		&stocktake.SubmittedBy,
+		&stocktake.ClosedAt,
+		&stocktake.ClosedBy,
 	)
+}
+
+// adjustmentColumns are the columns scanned into a stock adjustment
+// 在庫調整として読み取る列
+const adjustmentColumns = `id, item_id, location_id, old_quantity, new_quantity, delta, value, reason_code, note, reference,
+	status, COALESCE(transaction_id, ''), requested_at, requested_by, decided_at, COALESCE(decided_by, ''), decision_note`
 
+// CreateAdjustment creates a stock adjustment
+// 在庫調整を作成
+func (s *PostgreSQLStorage) CreateAdjustment(ctx context.Context, adjustment *inventory.Adjustment) error {
+	query := `
+		INSERT INTO adjustments (id, item_id, location_id, old_quantity, new_quantity, delta, value, reason_code, note, reference,
+			status, transaction_id, requested_at, requested_by, decided_at, decided_by, decision_note)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12, ''), $13, $14, $15, NULLIF($16, ''), $17)`
+
+	_, err := s.conn.ExecContext(ctx, query,
+		adjustment.ID,
+		adjustment.ItemID,
+		adjustment.LocationID,
+		adjustment.OldQuantity,
+		adjustment.NewQuantity,
+		adjustment.Delta,
+		adjustment.Value,
+		adjustment.Reason,
+		adjustment.Note,
+		adjustment.Reference,
+		adjustment.Status,
+		adjustment.TransactionID,
+		adjustment.RequestedAt,
+		adjustment.RequestedBy,
+		adjustment.DecidedAt,
+		adjustment.DecidedBy,
+		adjustment.DecisionNote,
+	)
 	if err != nil {
-		return fmt.Errorf("アラート作成に失敗しました: %w", err)
+		return fmt.Errorf("在庫調整作成に失敗しました: %w", err)
 	}
-
 	return nil
 }
 
-// GetActiveAlerts retrieves active alerts for a location
-// ロケーションのアクティブアラートを取得
-func (s *PostgreSQLStorage) GetActiveAlerts(ctx context.Context, locationID string) ([]inventory.StockAlert, error) {
+// GetAdjustment retrieves a stock adjustment by ID
+// IDで在庫調整を取得
+func (s *PostgreSQLStorage) GetAdjustment(ctx context.Context, adjustmentID string) (*inventory.Adjustment, error) {
+	var adjustment inventory.Adjustment
+	err := scanAdjustment(s.reader(ctx).QueryRowContext(ctx, `SELECT `+adjustmentColumns+` FROM adjustments WHERE id = $1`, adjustmentID), &adjustment)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrAdjustmentNotFound
+		}
+		return nil, fmt.Errorf("在庫調整取得に失敗しました: %w", err)
+	}
+	return &adjustment, nil
+}
+
+// ListAdjustments lists stock adjustments matching a filter, newest first
+// 条件に一致する在庫調整を申請日時の新しい順に取得
+func (s *PostgreSQLStorage) ListAdjustments(ctx context.Context, filter inventory.AdjustmentFilter) ([]inventory.Adjustment, error) {
 	query := `
-		SELECT id, type, item_id, location_id, current_qty, threshold, message, is_active, created_at, resolved_at
-		FROM stock_alerts 
-		WHERE location_id = $1 AND is_active = true
-		ORDER BY created_at DESC`
+		SELECT ` + adjustmentColumns + `
+		FROM adjustments
+		WHERE ($1 = '' OR item_id = $1) AND ($2 = '' OR location_id = $2)
+		  AND ($3 = '' OR status = $3) AND ($4 = '' OR reason_code = $4)
+		ORDER BY requested_at DESC, id DESC
+		OFFSET $5`
+	args := []interface{}{filter.ItemID, filter.LocationID, string(filter.Status), string(filter.Reason), filter.Offset}
+	if filter.Limit > 0 {
+		query += ` LIMIT $6`
+		args = append(args, filter.Limit)
+	}
 
-	rows, err := s.db.QueryContext(ctx, query, locationID)
+	rows, err := s.reader(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("アラート取得に失敗しました: %w", err)
+		return nil, fmt.Errorf("在庫調整一覧の取得に失敗しました: %w", err)
 	}
 	defer rows.Close()
 
-	var alerts []inventory.StockAlert
+	adjustments := make([]inventory.Adjustment, 0)
 	for rows.Next() {
-		var alert inventory.StockAlert
-		err := rows.Scan(
-			&alert.ID,
-			&alert.Type,
-			&alert.ItemID,
-			&alert.LocationID,
-			&alert.CurrentQty,
-			&alert.Threshold,
-			&alert.Message,
-			&alert.IsActive,
-			&alert.CreatedAt,
-			&alert.ResolvedAt,
-		)
+		var adjustment inventory.Adjustment
+		if err := scanAdjustment(rows, &adjustment); err != nil {
+			return nil, fmt.Errorf("在庫調整スキャンに失敗しました: %w", err)
+		}
+		adjustments = append(adjustments, adjustment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("在庫調整スキャンに失敗しました: %w", err)
+	}
+
+	return adjustments, nil
+}
+
+// UpdateAdjustment records the decision on a stock adjustment in status from
+// 状態がfromの在庫調整に承認・却下の結果を記録
+//
+// 承認・却下の競合で二重に処理しないよう、状態がfromの在庫調整のみをWHERE句で更新し、
+// 0件更新の場合は在庫調整の有無で ErrAdjustmentNotFound と ErrAdjustmentNotPending を区別します。
+func (s *PostgreSQLStorage) UpdateAdjustment(ctx context.Context, adjustment *inventory.Adjustment, from inventory.AdjustmentStatus) error {
+	query := `
+		UPDATE adjustments
+		SET status = $3, new_quantity = $4, transaction_id = NULLIF($5, ''), decided_at = $6, decided_by = NULLIF($7, ''), decision_note = $8
+		WHERE id = $1 AND status = $2`
+	result, err := s.conn.ExecContext(ctx, query,
+		adjustment.ID,
+		from,
+		adjustment.Status,
+		adjustment.NewQuantity,
+		adjustment.TransactionID,
+		adjustment.DecidedAt,
+		adjustment.DecidedBy,
+		adjustment.DecisionNote,
+	)
+	if err != nil {
+		return fmt.Errorf("在庫調整更新に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+	if rowsAffected == 0 {
+		var exists bool
+		err := s.conn.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM adjustments WHERE id = $1)`, adjustment.ID).Scan(&exists)
 		if err != nil {
-			return nil, fmt.Errorf("アラートスキャンに失敗しました: %w", err)
+			return fmt.Errorf("在庫調整取得に失敗しました: %w", err)
 		}
-		alerts = append(alerts, alert)
+		if !exists {
+			return inventory.ErrAdjustmentNotFound
+		}
+		return inventory.ErrAdjustmentNotPending
 	}
+	return nil
+}
 
-	return alerts, nil
+// scanAdjustment scans the adjustmentColumns of a row into a stock adjustment
+// 行の adjustmentColumns を在庫調整に読み取る
+func scanAdjustment(row rowScanner, adjustment *inventory.Adjustment) error {
+	return row.Scan(
+		&adjustment.ID,
+		&adjustment.ItemID,
+		&adjustment.LocationID,
+		&adjustment.OldQuantity,
+		&adjustment.NewQuantity,
+		&adjustment.Delta,
+		&adjustment.Value,
+		&adjustment.Reason,
+		&adjustment.Note,
+		&adjustment.Reference,
+		&adjustment.Status,
+		&adjustment.TransactionID,
+		&adjustment.RequestedAt,
+		&adjustment.RequestedBy,
+		&adjustment.DecidedAt,
+		&adjustment.DecidedBy,
+		&adjustment.DecisionNote,
+	)
 }
 
-// ResolveAlert resolves an alert by setting it inactive
-// アラートを非アクティブにして解決
-func (s *PostgreSQLStorage) ResolveAlert(ctx context.Context, alertID string) error {
-	now := time.Now()
+// inspectionColumns are the columns scanned into a receiving inspection
+// 入荷検品として読み取る列
+const inspectionColumns = `id, item_id, location_id, quantity, reference, note, status, COALESCE(reason_code, ''),
+	COALESCE(transaction_id, ''), received_at, received_by, inspected_at, COALESCE(inspected_by, ''), inspection_note`
+
+// CreateInspection creates a receiving inspection
+// 入荷検品を作成
+func (s *PostgreSQLStorage) CreateInspection(ctx context.Context, inspection *inventory.Inspection) error {
 	query := `
-		UPDATE stock_alerts 
-		SET is_active = false, resolved_at = $2
-		WHERE id = $1`
+		INSERT INTO inspections (id, item_id, location_id, quantity, reference, note, status, reason_code,
+			transaction_id, received_at, received_by, inspected_at, inspected_by, inspection_note)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''), $10, $11, $12, NULLIF($13, ''), $14)`
+
+	_, err := s.conn.ExecContext(ctx, query,
+		inspection.ID,
+		inspection.ItemID,
+		inspection.LocationID,
+		inspection.Quantity,
+		inspection.Reference,
+		inspection.Note,
+		inspection.Status,
+		inspection.Reason,
+		inspection.TransactionID,
+		inspection.ReceivedAt,
+		inspection.ReceivedBy,
+		inspection.InspectedAt,
+		inspection.InspectedBy,
+		inspection.InspectionNote,
+	)
+	if err != nil {
+		return fmt.Errorf("入荷検品作成に失敗しました: %w", err)
+	}
+	return nil
+}
 
-	result, err := s.db.ExecContext(ctx, query, alertID, now)
+// GetInspection retrieves a receiving inspection by ID
+// IDで入荷検品を取得
+func (s *PostgreSQLStorage) GetInspection(ctx context.Context, inspectionID string) (*inventory.Inspection, error) {
+	var inspection inventory.Inspection
+	err := scanInspection(s.reader(ctx).QueryRowContext(ctx, `SELECT `+inspectionColumns+` FROM inspections WHERE id = $1`, inspectionID), &inspection)
 	if err != nil {
-		return fmt.Errorf("アラート解決に失敗しました: %w", err)
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrInspectionNotFound
+		}
+		return nil, fmt.Errorf("入荷検品取得に失敗しました: %w", err)
+	}
+	return &inspection, nil
+}
+
+// ListInspections lists receiving inspections matching a filter, newest first
+// 条件に一致する入荷検品を入荷日時の新しい順に取得
+func (s *PostgreSQLStorage) ListInspections(ctx context.Context, filter inventory.InspectionFilter) ([]inventory.Inspection, error) {
+	query := `
+		SELECT ` + inspectionColumns + `
+		FROM inspections
+		WHERE ($1 = '' OR item_id = $1) AND ($2 = '' OR location_id = $2) AND ($3 = '' OR status = $3)
+		ORDER BY received_at DESC, id DESC
+		OFFSET $4`
+	args := []interface{}{filter.ItemID, filter.LocationID, string(filter.Status), filter.Offset}
+	if filter.Limit > 0 {
+		query += ` LIMIT $5`
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.reader(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("入荷検品一覧の取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	inspections := make([]inventory.Inspection, 0)
+	for rows.Next() {
+		var inspection inventory.Inspection
+		if err := scanInspection(rows, &inspection); err != nil {
+			return nil, fmt.Errorf("入荷検品スキャンに失敗しました: %w", err)
+		}
+		inspections = append(inspections, inspection)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("入荷検品スキャンに失敗しました: %w", err)
+	}
+
+	return inspections, nil
+}
+
+// UpdateInspection records the disposition of a receiving inspection in status from
+// 状態がfromの入荷検品に合否の判定を記録
+//
+// 合否の判定の競合で二重に入庫しないよう、状態がfromの入荷検品のみをWHERE句で更新し、
+// 0件更新の場合は入荷検品の有無で ErrInspectionNotFound と ErrInspectionNotQuarantined を区別します。
+func (s *PostgreSQLStorage) UpdateInspection(ctx context.Context, inspection *inventory.Inspection, from inventory.InspectionStatus) error {
+	query := `
+		UPDATE inspections
+		SET status = $3, reason_code = NULLIF($4, ''), transaction_id = NULLIF($5, ''), inspected_at = $6,
+			inspected_by = NULLIF($7, ''), inspection_note = $8
+		WHERE id = $1 AND status = $2`
+	result, err := s.conn.ExecContext(ctx, query,
+		inspection.ID,
+		from,
+		inspection.Status,
+		inspection.Reason,
+		inspection.TransactionID,
+		inspection.InspectedAt,
+		inspection.InspectedBy,
+		inspection.InspectionNote,
+	)
+	if err != nil {
+		return fmt.Errorf("入荷検品更新に失敗しました: %w", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
 	}
-
 	if rowsAffected == 0 {
-		return fmt.Errorf("アラートが見つかりません: %s", alertID)
+		var exists bool
+		err := s.conn.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM inspections WHERE id = $1)`, inspection.ID).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("入荷検品取得に失敗しました: %w", err)
+		}
+		if !exists {
+			return inventory.ErrInspectionNotFound
+		}
+		return inventory.ErrInspectionNotQuarantined
+	}
+	return nil
+}
+
+// scanInspection scans the inspectionColumns of a row into a receiving inspection
+// 行の inspectionColumns を入荷検品に読み取る
+func scanInspection(row rowScanner, inspection *inventory.Inspection) error {
+	return row.Scan(
+		&inspection.ID,
+		&inspection.ItemID,
+		&inspection.LocationID,
+		&inspection.Quantity,
+		&inspection.Reference,
+		&inspection.Note,
+		&inspection.Status,
+		&inspection.Reason,
+		&inspection.TransactionID,
+		&inspection.ReceivedAt,
+		&inspection.ReceivedBy,
+		&inspection.InspectedAt,
+		&inspection.InspectedBy,
+		&inspection.InspectionNote,
+	)
+}
+
+// SaveBatchOperation creates or overwrites the record of a batch operation
+// バッチ操作の記録を作成または上書き
+//
+// 操作・操作ごとの結果・エラーはJSONBで保存します。
+func (s *PostgreSQLStorage) SaveBatchOperation(ctx context.Context, batch *inventory.BatchOperation) error {
+	operations, err := json.Marshal(batch.Operations)
+	if err != nil {
+		return fmt.Errorf("バッチ操作のJSON変換に失敗しました: %w", err)
+	}
+	results, err := json.Marshal(batch.Results)
+	if err != nil {
+		return fmt.Errorf("バッチ操作のJSON変換に失敗しました: %w", err)
+	}
+	errs, err := json.Marshal(batch.Errors)
+	if err != nil {
+		return fmt.Errorf("バッチ操作のJSON変換に失敗しました: %w", err)
+	}
+
+	query := `
+		INSERT INTO batch_operations (id, status, atomic, operations, results, errors, success_count, failure_count, cancelled_count, progress, created_at, completed_at)
+		VALUES ($1, $2, $3, $4::jsonb, $5::jsonb, $6::jsonb, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (id) DO UPDATE SET
+			status = EXCLUDED.status,
+			results = EXCLUDED.results,
+			errors = EXCLUDED.errors,
+			success_count = EXCLUDED.success_count,
+			failure_count = EXCLUDED.failure_count,
+			cancelled_count = EXCLUDED.cancelled_count,
+			progress = EXCLUDED.progress,
+			completed_at = EXCLUDED.completed_at`
+
+	_, err = s.conn.ExecContext(ctx, query,
+		batch.ID,
+		batch.Status,
+		batch.Atomic,
+		string(operations),
+		string(results),
+		string(errs),
+		batch.SuccessCount,
+		batch.FailureCount,
+		batch.CancelledCount,
+		batch.Progress,
+		batch.CreatedAt,
+		batch.CompletedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("バッチ操作の保存に失敗しました: %w", err)
 	}
 
 	return nil
 }
 
+// GetBatchOperation retrieves a batch operation by ID
+// IDでバッチ操作を取得
+//
+// 実行中のバッチの状態をポーリングするため、レプリカの遅延の影響を受けないようプライマリから読み取ります。
+func (s *PostgreSQLStorage) GetBatchOperation(ctx context.Context, batchID string) (*inventory.BatchOperation, error) {
+	query := `
+		SELECT id, status, atomic, operations, results, errors, success_count, failure_count, cancelled_count, progress, created_at, completed_at
+		FROM batch_operations
+		WHERE id = $1`
+
+	batch := &inventory.BatchOperation{}
+	var operations, results, errs []byte
+	err := s.conn.QueryRowContext(ctx, query, batchID).Scan(
+		&batch.ID,
+		&batch.Status,
+		&batch.Atomic,
+		&operations,
+		&results,
+		&errs,
+		&batch.SuccessCount,
+		&batch.FailureCount,
+		&batch.CancelledCount,
+		&batch.Progress,
+		&batch.CreatedAt,
+		&batch.CompletedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, inventory.ErrBatchNotFound
+		}
+		return nil, fmt.Errorf("バッチ操作の取得に失敗しました: %w", err)
+	}
+
+	if err := json.Unmarshal(operations, &batch.Operations); err != nil {
+		return nil, fmt.Errorf("バッチ操作のJSON解析に失敗しました: %w", err)
+	}
+	if err := json.Unmarshal(results, &batch.Results); err != nil {
+		return nil, fmt.Errorf("バッチ操作のJSON解析に失敗しました: %w", err)
+	}
+	if err := json.Unmarshal(errs, &batch.Errors); err != nil {
+		return nil, fmt.Errorf("バッチ操作のJSON解析に失敗しました: %w", err)
+	}
+
+	return batch, nil
+}
+
+// GetInventorySummary aggregates the whole inventory with aggregate queries
+// 集計クエリで在庫全体を集計
+func (s *PostgreSQLStorage) GetInventorySummary(ctx context.Context, lowStockThreshold int64) (*inventory.InventorySummary, error) {
+	db := s.reader(ctx)
+
+	// 評価額は委託在庫を除いた自社所有の在庫で計算する
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM items),
+			(SELECT COALESCE(SUM(quantity), 0) FROM stocks),
+			(SELECT COALESCE(SUM((s.quantity - COALESCE(c.quantity, 0)) * COALESCE(i.unit_cost, 0)), 0)
+				FROM stocks s
+				JOIN items i ON i.id = s.item_id
+				LEFT JOIN (
+					SELECT item_id, location_id, SUM(quantity) AS quantity
+					FROM consignment_stocks
+					GROUP BY item_id, location_id
+				) c ON c.item_id = s.item_id AND c.location_id = s.location_id),
+			(SELECT COUNT(*) FROM stock_alerts WHERE is_active = true)`
+
+	summary := &inventory.InventorySummary{}
+	err := db.QueryRowContext(ctx, query).Scan(
+		&summary.TotalSKUs,
+		&summary.TotalUnits,
+		&summary.TotalValue,
+		&summary.ActiveAlerts,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("在庫集計に失敗しました: %w", err)
+	}
+
+	// 在庫のないロケーションも0件として含める。発注点を設定した在庫は発注点で判定する
+	lowStockQuery := `
+		SELECT l.id, COUNT(s.item_id)
+		FROM locations l
+		LEFT JOIN (stocks s
+			LEFT JOIN reorder_points rp ON rp.item_id = s.item_id AND rp.location_id = s.location_id)
+			ON s.location_id = l.id AND s.quantity <= COALESCE(rp.reorder_point, $1)
+		GROUP BY l.id
+		ORDER BY l.id`
+
+	rows, err := db.QueryContext(ctx, lowStockQuery, lowStockThreshold)
+	if err != nil {
+		return nil, fmt.Errorf("低在庫の集計に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	summary.LowStock = make([]inventory.LocationLowStock, 0)
+	for rows.Next() {
+		var lowStock inventory.LocationLowStock
+		if err := rows.Scan(&lowStock.LocationID, &lowStock.Items); err != nil {
+			return nil, fmt.Errorf("低在庫の集計結果の読み取りに失敗しました: %w", err)
+		}
+		summary.LowStock = append(summary.LowStock, lowStock)
+	}
+
+	return summary, rows.Err()
+}
+
 // Ping checks database connectivity
 // データベース接続をチェック
 func (s *PostgreSQLStorage) Ping(ctx context.Context) error {
-	return s.db.PingContext(ctx)
+	if err := s.db.PingContext(ctx); err != nil {
+		return err
+	}
+	for _, replica := range s.replicas {
+		if err := replica.PingContext(ctx); err != nil {
+			return fmt.Errorf("読み取りレプリカのpingに失敗しました: %w", err)
+		}
+	}
+	return nil
+}
+
+// DB returns the primary connection pool
+// プライマリの接続プールを返す
+//
+// 同じデータベースに別のテーブルを持つ機能（Webhookなど）が接続プールを共有するために使用します。
+func (s *PostgreSQLStorage) DB() *sql.DB {
+	return s.db
 }
 
 // Close closes the database connection
 // データベース接続を閉じる
+//
+// トランザクションスコープ内では接続を閉じません。
 func (s *PostgreSQLStorage) Close() error {
+	if s.tx != nil {
+		return nil
+	}
+	if s.stmts != nil {
+		s.stmts.close()
+	}
+	for _, stmt := range s.replicaGetStock {
+		stmt.Close()
+	}
+	for _, replica := range s.replicas {
+		if err := replica.Close(); err != nil {
+			s.logger.Error("読み取りレプリカのクローズに失敗しました", zap.Error(err))
+		}
+	}
 	return s.db.Close()
 }
@@ -0,0 +1,278 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// fakeDriver はクエリを記録するテスト用のSQLドライバー（接続先ごとに状態を持つ）
+type fakeDriver struct {
+	mu  sync.Mutex
+	dbs map[string]*fakeDB
+}
+
+// fakeDB はテスト用ドライバーの接続先の状態
+type fakeDB struct {
+	prepared    []string       // 準備したクエリ
+	executed    []string       // 実行したクエリ（準備済みのステートメントを含む）
+	closedStmts int            // 閉じたステートメントの数
+	partitions  []driver.Value // create_transactions_partition の引数
+	defaultRows int64          // transactions_default の行数
+	failExec    bool           // 更新系のクエリを失敗させるか
+}
+
+var testDriver = &fakeDriver{dbs: make(map[string]*fakeDB)}
+
+func init() {
+	sql.Register("fake", testDriver)
+}
+
+// openFakeDB はテスト用ドライバーの接続先を作成して接続する
+func openFakeDB(t *testing.T, name string) (*sql.DB, *fakeDB) {
+	t.Helper()
+
+	state := &fakeDB{}
+	testDriver.mu.Lock()
+	testDriver.dbs[t.Name()+"/"+name] = state
+	testDriver.mu.Unlock()
+
+	db, err := sql.Open("fake", t.Name()+"/"+name)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db, state
+}
+
+// snapshot は接続先の状態をロックして読み取る
+func (d *fakeDriver) snapshot(state *fakeDB) fakeDB {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return *state
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	state, ok := d.dbs[name]
+	if !ok {
+		return nil, errors.New("unknown database: " + name)
+	}
+	return &fakeConn{driver: d, db: state}, nil
+}
+
+type fakeConn struct {
+	driver *fakeDriver
+	db     *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	c.driver.mu.Lock()
+	defer c.driver.mu.Unlock()
+	c.db.prepared = append(c.db.prepared, query)
+	return &fakeStmt{conn: c, query: query}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.driver.mu.Lock()
+	defer c.driver.mu.Unlock()
+	c.db.executed = append(c.db.executed, query)
+	if c.db.failExec {
+		return nil, errors.New("partition constraint violated")
+	}
+	if strings.Contains(query, "create_transactions_partition") {
+		c.db.partitions = append(c.db.partitions, args[0].Value)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.driver.mu.Lock()
+	defer c.driver.mu.Unlock()
+	c.db.executed = append(c.db.executed, query)
+	switch {
+	case strings.Contains(query, "transactions_default"):
+		return &fakeRows{columns: []string{"count"}, values: [][]driver.Value{{c.db.defaultRows}}}, nil
+	case strings.Contains(query, "FROM stocks"):
+		return &fakeRows{
+			columns: []string{"item_id", "location_id", "quantity", "reserved", "available", "version", "updated_at", "updated_by"},
+			values:  [][]driver.Value{{args[0].Value, args[1].Value, int64(7), int64(0), int64(7), int64(1), time.Now(), "user"}},
+		}, nil
+	default:
+		return nil, errors.New("unexpected query: " + query)
+	}
+}
+
+// fakeStmt は準備済みのステートメント（実行は接続に委譲する）
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error {
+	s.conn.driver.mu.Lock()
+	defer s.conn.driver.mu.Unlock()
+	s.conn.db.closedStmts++
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.conn.ExecContext(context.Background(), s.query, namedValues(args))
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.conn.QueryContext(context.Background(), s.query, namedValues(args))
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
+
+// fakeRows は固定の結果を返す
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+// newFakeStorage はテスト用ドライバーのプライマリとレプリカに接続したストレージを作成
+func newFakeStorage(t *testing.T, replicas int) (*PostgreSQLStorage, *fakeDB, []*fakeDB) {
+	t.Helper()
+
+	db, primary := openFakeDB(t, "primary")
+	storage := &PostgreSQLStorage{db: db, replicaIdx: new(atomic.Uint32), conn: db, logger: zap.NewNop()}
+	var states []*fakeDB
+	for i := 0; i < replicas; i++ {
+		replica, state := openFakeDB(t, "replica"+string(rune('0'+i)))
+		storage.replicas = append(storage.replicas, replica)
+		states = append(states, state)
+	}
+	return storage, primary, states
+}
+
+// TestEnsureTransactionPartitions は月次パーティションの作成のテスト
+func TestEnsureTransactionPartitions(t *testing.T) {
+	ctx := context.Background()
+	from := time.Date(2024, 11, 20, 15, 0, 0, 0, time.FixedZone("JST", 9*60*60))
+
+	t.Run("fromを含む月から指定した月数を作成", func(t *testing.T) {
+		store, primary, _ := newFakeStorage(t, 0)
+		require.NoError(t, store.EnsureTransactionPartitions(ctx, from, 4))
+		assert.Equal(t, []driver.Value{
+			time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		}, testDriver.snapshot(primary).partitions)
+	})
+
+	t.Run("デフォルトパーティションに行がある場合は作成したうえでエラー", func(t *testing.T) {
+		store, primary, _ := newFakeStorage(t, 0)
+		primary.defaultRows = 3
+		err := store.EnsureTransactionPartitions(ctx, from, 2)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "3件")
+		assert.Len(t, testDriver.snapshot(primary).partitions, 2)
+	})
+
+	t.Run("パーティションの作成に失敗した場合はエラー", func(t *testing.T) {
+		store, primary, _ := newFakeStorage(t, 0)
+		primary.failExec = true
+		assert.Error(t, store.EnsureTransactionPartitions(ctx, from, 2))
+		assert.Empty(t, testDriver.snapshot(primary).partitions)
+	})
+}
+
+// TestPreparedStatements は頻出クエリの準備と再利用のテスト
+func TestPreparedStatements(t *testing.T) {
+	ctx := context.Background()
+	store, primary, replicas := newFakeStorage(t, 2)
+	require.NoError(t, store.prepareStatements(ctx))
+
+	// プライマリで頻出クエリを、各レプリカで在庫取得クエリを1回ずつ準備する
+	assert.Equal(t, []string{getStockQuery, updateStockQuery, createTransactionQuery}, testDriver.snapshot(primary).prepared)
+	for _, replica := range replicas {
+		assert.Equal(t, []string{getStockQuery}, testDriver.snapshot(replica).prepared)
+	}
+
+	// 呼び出しごとに準備し直さず、在庫取得はレプリカに振り分ける
+	for i := 0; i < 4; i++ {
+		stock, err := store.GetStock(ctx, "ITEM-1", "LOC-A")
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), stock.Quantity)
+	}
+	stock, err := store.GetStock(inventory.WithPrimaryRead(ctx), "ITEM-1", "LOC-A")
+	require.NoError(t, err)
+	assert.Equal(t, "LOC-A", stock.LocationID)
+	require.NoError(t, store.UpdateStock(ctx, stock))
+	require.NoError(t, store.CreateTransaction(ctx, &inventory.Transaction{ID: "TX-1", Type: inventory.TransactionTypeInbound, ItemID: "ITEM-1", Quantity: 1, CreatedAt: time.Now()}))
+
+	assert.Len(t, testDriver.snapshot(primary).prepared, 3)
+	assert.Equal(t, []string{getStockQuery, updateStockQuery, createTransactionQuery}, testDriver.snapshot(primary).executed)
+	for _, replica := range replicas {
+		assert.Len(t, testDriver.snapshot(replica).prepared, 1)
+		assert.Equal(t, []string{getStockQuery, getStockQuery}, testDriver.snapshot(replica).executed)
+	}
+
+	// Closeで準備済みのステートメントを解放する
+	require.NoError(t, store.Close())
+	assert.Equal(t, 3, testDriver.snapshot(primary).closedStmts)
+	for _, replica := range replicas {
+		assert.Equal(t, 1, testDriver.snapshot(replica).closedStmts)
+	}
+}
+
+// TestConfigurePool は接続プール設定の適用のテスト
+func TestConfigurePool(t *testing.T) {
+	store, _, _ := newFakeStorage(t, 2)
+
+	// 既定の設定
+	DefaultPoolConfig().apply(store.db)
+	assert.Equal(t, 25, store.db.Stats().MaxOpenConnections)
+
+	// プライマリと全てのレプリカにそれぞれ適用する
+	store.ConfigurePool(PoolConfig{MaxOpenConns: 5, MaxIdleConns: 2, ConnMaxLifetime: time.Minute})
+	for _, db := range append([]*sql.DB{store.db}, store.replicas...) {
+		assert.Equal(t, 5, db.Stats().MaxOpenConnections)
+	}
+
+	// 0は無制限
+	store.ConfigurePool(PoolConfig{})
+	assert.Equal(t, 0, store.replicas[1].Stats().MaxOpenConnections)
+}
@@ -0,0 +1,1370 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// tracerName is the instrumentation name used for storage spans
+// ストレージスパンで使用する計装名
+const tracerName = "github.com/nemonet1337/zaiGoFramework/pkg/inventory/storage"
+
+// Span attribute keys - スパン属性キー
+var (
+	attrOperation     = attribute.Key("db.operation")
+	attrItemID        = attribute.Key("inventory.item_id")
+	attrLocationID    = attribute.Key("inventory.location_id")
+	attrLotID         = attribute.Key("inventory.lot_id")
+	attrAlertID       = attribute.Key("inventory.alert_id")
+	attrRows          = attribute.Key("inventory.rows_returned")
+	attrMetaKey       = attribute.Key("inventory.metadata_key")
+	attrReference     = attribute.Key("inventory.reference")
+	attrTxID          = attribute.Key("inventory.transaction_id")
+	attrBatchID       = attribute.Key("inventory.batch_id")
+	attrReservationID = attribute.Key("inventory.reservation_id")
+	attrBackorderID   = attribute.Key("inventory.backorder_id")
+	attrAlertRuleID   = attribute.Key("inventory.alert_rule_id")
+	attrSnapshotID    = attribute.Key("inventory.snapshot_id")
+	attrStocktakeID   = attribute.Key("inventory.stocktake_id")
+	attrAdjustmentID  = attribute.Key("inventory.adjustment_id")
+	attrInspectionID  = attribute.Key("inventory.inspection_id")
+	attrUoM           = attribute.Key("inventory.uom")
+	attrAssemblyID    = attribute.Key("inventory.assembly_id")
+	attrSupplierID    = attribute.Key("inventory.supplier_id")
+	attrPurchaseOrder = attribute.Key("inventory.purchase_order_id")
+	attrSalesOrderID  = attribute.Key("inventory.sales_order_id")
+	attrReturnID      = attribute.Key("inventory.return_id")
+	attrOwnerID       = attribute.Key("inventory.owner_id")
+	attrChannel       = attribute.Key("inventory.channel")
+)
+
+// TracingStorage wraps a Storage and creates an OpenTelemetry span per method call
+// Storageをラップし、メソッド呼び出しごとにOpenTelemetryスパンを作成するデコレーター
+//
+// 親スパンはcontextから引き継がれるため、HTTPハンドラーからマネージャー、SQLまでを
+// 一つのトレースとして追跡できます。
+type TracingStorage struct {
+	next   inventory.Storage
+	tracer trace.Tracer
+}
+
+var _ inventory.Storage = (*TracingStorage)(nil)
+
+// NewTracingStorage creates a Storage decorator that records OpenTelemetry spans
+// OpenTelemetryスパンを記録するStorageデコレーターを作成
+//
+// providerがnilの場合はグローバルのTracerProviderを使用します。
+func NewTracingStorage(next inventory.Storage, provider trace.TracerProvider) *TracingStorage {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+
+	return &TracingStorage{
+		next:   next,
+		tracer: provider.Tracer(tracerName),
+	}
+}
+
+// startSpan starts a child span for a storage method
+// ストレージメソッド用の子スパンを開始
+func (s *TracingStorage) startSpan(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, attrOperation.String(method))
+	return s.tracer.Start(ctx, "storage."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// endSpan records the error status and ends the span
+// エラー状況を記録してスパンを終了
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// endSpanWithRows records the returned row count and ends the span
+// 返却件数を記録してスパンを終了
+func endSpanWithRows(span trace.Span, rows int, err error) {
+	if err == nil {
+		span.SetAttributes(attrRows.Int(rows))
+	}
+	endSpan(span, err)
+}
+
+// WithinTx runs fn inside a transaction, tracing the calls made on txStorage
+// トランザクション内でfnを実行（txStorageへの呼び出しもトレース対象）
+func (s *TracingStorage) WithinTx(ctx context.Context, fn func(txStorage inventory.Storage) error) error {
+	ctx, span := s.startSpan(ctx, "WithinTx")
+	err := s.next.WithinTx(ctx, func(txStorage inventory.Storage) error {
+		return fn(&TracingStorage{next: txStorage, tracer: s.tracer})
+	})
+	endSpan(span, err)
+	return err
+}
+
+// CreateStock creates a new stock record
+// 新しい在庫記録を作成
+func (s *TracingStorage) CreateStock(ctx context.Context, stock *inventory.Stock) error {
+	ctx, span := s.startSpan(ctx, "CreateStock", attrItemID.String(stock.ItemID), attrLocationID.String(stock.LocationID))
+	err := s.next.CreateStock(ctx, stock)
+	endSpan(span, err)
+	return err
+}
+
+// UpdateStock updates an existing stock record
+// 既存の在庫記録を更新
+func (s *TracingStorage) UpdateStock(ctx context.Context, stock *inventory.Stock) error {
+	ctx, span := s.startSpan(ctx, "UpdateStock", attrItemID.String(stock.ItemID), attrLocationID.String(stock.LocationID))
+	err := s.next.UpdateStock(ctx, stock)
+	endSpan(span, err)
+	return err
+}
+
+// CreateOrUpdateStocks upserts multiple stock records
+// 複数の在庫記録を一括作成・更新
+func (s *TracingStorage) CreateOrUpdateStocks(ctx context.Context, stocks []inventory.Stock) error {
+	ctx, span := s.startSpan(ctx, "CreateOrUpdateStocks", attrRows.Int(len(stocks)))
+	err := s.next.CreateOrUpdateStocks(ctx, stocks)
+	endSpan(span, err)
+	return err
+}
+
+// BulkImport inserts items, stocks and transactions in bulk
+// 商品・在庫・トランザクションを一括挿入
+func (s *TracingStorage) BulkImport(ctx context.Context, data *inventory.BulkImportData) error {
+	ctx, span := s.startSpan(ctx, "BulkImport", attrRows.Int(data.Len()))
+	err := s.next.BulkImport(ctx, data)
+	endSpan(span, err)
+	return err
+}
+
+// GetStock retrieves stock information
+// 在庫情報を取得
+func (s *TracingStorage) GetStock(ctx context.Context, itemID, locationID string) (*inventory.Stock, error) {
+	ctx, span := s.startSpan(ctx, "GetStock", attrItemID.String(itemID), attrLocationID.String(locationID))
+	stock, err := s.next.GetStock(ctx, itemID, locationID)
+	endSpan(span, err)
+	return stock, err
+}
+
+// GetStocks retrieves the stock of many item and location pairs
+// 複数の商品とロケーションの組の在庫情報を取得
+func (s *TracingStorage) GetStocks(ctx context.Context, keys []inventory.StockKey) ([]inventory.Stock, error) {
+	ctx, span := s.startSpan(ctx, "GetStocks")
+	stocks, err := s.next.GetStocks(ctx, keys)
+	endSpanWithRows(span, len(stocks), err)
+	return stocks, err
+}
+
+// GetStockForUpdate retrieves stock information with a row lock
+// 行ロック付きで在庫情報を取得
+func (s *TracingStorage) GetStockForUpdate(ctx context.Context, itemID, locationID string) (*inventory.Stock, error) {
+	ctx, span := s.startSpan(ctx, "GetStockForUpdate", attrItemID.String(itemID), attrLocationID.String(locationID))
+	stock, err := s.next.GetStockForUpdate(ctx, itemID, locationID)
+	endSpan(span, err)
+	return stock, err
+}
+
+// ListStockByLocation retrieves all stock at a location
+// ロケーションの全在庫を取得
+func (s *TracingStorage) ListStockByLocation(ctx context.Context, locationID string) ([]inventory.Stock, error) {
+	ctx, span := s.startSpan(ctx, "ListStockByLocation", attrLocationID.String(locationID))
+	stocks, err := s.next.ListStockByLocation(ctx, locationID)
+	endSpanWithRows(span, len(stocks), err)
+	return stocks, err
+}
+
+// ListStockByItem retrieves the stock of an item at every location
+// 商品の全ロケーションの在庫を取得
+func (s *TracingStorage) ListStockByItem(ctx context.Context, itemID string) ([]inventory.Stock, error) {
+	ctx, span := s.startSpan(ctx, "ListStockByItem", attrItemID.String(itemID))
+	stocks, err := s.next.ListStockByItem(ctx, itemID)
+	endSpanWithRows(span, len(stocks), err)
+	return stocks, err
+}
+
+// ForEachStockByLocation streams stock at a location
+// ロケーションの在庫を1件ずつ走査
+func (s *TracingStorage) ForEachStockByLocation(ctx context.Context, locationID string, fn func(stock inventory.Stock) error) error {
+	ctx, span := s.startSpan(ctx, "ForEachStockByLocation", attrLocationID.String(locationID))
+	rows := 0
+	err := s.next.ForEachStockByLocation(ctx, locationID, func(stock inventory.Stock) error {
+		rows++
+		return fn(stock)
+	})
+	endSpanWithRows(span, rows, err)
+	return err
+}
+
+// GetTotalStockByItem retrieves total stock for an item across all locations
+// 商品の全ロケーション合計在庫を取得
+func (s *TracingStorage) GetTotalStockByItem(ctx context.Context, itemID string) (int64, error) {
+	ctx, span := s.startSpan(ctx, "GetTotalStockByItem", attrItemID.String(itemID))
+	total, err := s.next.GetTotalStockByItem(ctx, itemID)
+	endSpan(span, err)
+	return total, err
+}
+
+// GetTotalStockByLocation retrieves total stock of all items at a location
+// ロケーションの全商品合計在庫を取得
+func (s *TracingStorage) GetTotalStockByLocation(ctx context.Context, locationID string) (int64, error) {
+	ctx, span := s.startSpan(ctx, "GetTotalStockByLocation", attrLocationID.String(locationID))
+	total, err := s.next.GetTotalStockByLocation(ctx, locationID)
+	endSpan(span, err)
+	return total, err
+}
+
+// CreateTransaction creates a new transaction record
+// 新しいトランザクション記録を作成
+func (s *TracingStorage) CreateTransaction(ctx context.Context, tx *inventory.Transaction) error {
+	attrs := []attribute.KeyValue{attrItemID.String(tx.ItemID)}
+	if tx.ToLocation != nil {
+		attrs = append(attrs, attrLocationID.String(*tx.ToLocation))
+	} else if tx.FromLocation != nil {
+		attrs = append(attrs, attrLocationID.String(*tx.FromLocation))
+	}
+
+	ctx, span := s.startSpan(ctx, "CreateTransaction", attrs...)
+	err := s.next.CreateTransaction(ctx, tx)
+	endSpan(span, err)
+	return err
+}
+
+// GetTransactionByID retrieves a transaction record by ID
+// IDでトランザクション記録を取得
+func (s *TracingStorage) GetTransactionByID(ctx context.Context, txID string) (*inventory.Transaction, error) {
+	ctx, span := s.startSpan(ctx, "GetTransactionByID", attrTxID.String(txID))
+	tx, err := s.next.GetTransactionByID(ctx, txID)
+	endSpan(span, err)
+	return tx, err
+}
+
+// GetTransactionHistory retrieves transaction history for an item
+// 商品のトランザクション履歴を取得
+func (s *TracingStorage) GetTransactionHistory(ctx context.Context, itemID string, limit int) ([]inventory.Transaction, error) {
+	ctx, span := s.startSpan(ctx, "GetTransactionHistory", attrItemID.String(itemID))
+	txs, err := s.next.GetTransactionHistory(ctx, itemID, limit)
+	endSpanWithRows(span, len(txs), err)
+	return txs, err
+}
+
+// GetTransactionHistoryAfter retrieves transaction history older than the cursor
+// カーソルより古いトランザクション履歴を取得
+func (s *TracingStorage) GetTransactionHistoryAfter(ctx context.Context, itemID string, after *inventory.HistoryCursor, limit int) ([]inventory.Transaction, error) {
+	ctx, span := s.startSpan(ctx, "GetTransactionHistoryAfter", attrItemID.String(itemID))
+	txs, err := s.next.GetTransactionHistoryAfter(ctx, itemID, after, limit)
+	endSpanWithRows(span, len(txs), err)
+	return txs, err
+}
+
+// GetTransactionHistoryByLocation retrieves transaction history for a location
+// ロケーションのトランザクション履歴を取得
+func (s *TracingStorage) GetTransactionHistoryByLocation(ctx context.Context, locationID string, limit int) ([]inventory.Transaction, error) {
+	ctx, span := s.startSpan(ctx, "GetTransactionHistoryByLocation", attrLocationID.String(locationID))
+	txs, err := s.next.GetTransactionHistoryByLocation(ctx, locationID, limit)
+	endSpanWithRows(span, len(txs), err)
+	return txs, err
+}
+
+// GetTransactionsByReference retrieves transactions by reference number
+// 参照番号でトランザクションを取得
+func (s *TracingStorage) GetTransactionsByReference(ctx context.Context, reference string) ([]inventory.Transaction, error) {
+	ctx, span := s.startSpan(ctx, "GetTransactionsByReference", attrReference.String(reference))
+	txs, err := s.next.GetTransactionsByReference(ctx, reference)
+	endSpanWithRows(span, len(txs), err)
+	return txs, err
+}
+
+// SearchTransactionsByMetadata retrieves transactions by metadata key and value
+// メタデータのキーと値でトランザクションを検索
+func (s *TracingStorage) SearchTransactionsByMetadata(ctx context.Context, key, value string, limit int) ([]inventory.Transaction, error) {
+	ctx, span := s.startSpan(ctx, "SearchTransactionsByMetadata", attrMetaKey.String(key))
+	txs, err := s.next.SearchTransactionsByMetadata(ctx, key, value, limit)
+	endSpanWithRows(span, len(txs), err)
+	return txs, err
+}
+
+// ForEachTransactionInRange streams transactions in a time range
+// 期間内のトランザクションを1件ずつ走査
+func (s *TracingStorage) ForEachTransactionInRange(ctx context.Context, from, to time.Time, fn func(tx inventory.Transaction) error) error {
+	ctx, span := s.startSpan(ctx, "ForEachTransactionInRange")
+	rows := 0
+	err := s.next.ForEachTransactionInRange(ctx, from, to, func(tx inventory.Transaction) error {
+		rows++
+		return fn(tx)
+	})
+	endSpanWithRows(span, rows, err)
+	return err
+}
+
+// ArchiveTransactions moves old transactions into the archive
+// 古いトランザクションをアーカイブへ移動
+func (s *TracingStorage) ArchiveTransactions(ctx context.Context, before time.Time) (int64, error) {
+	ctx, span := s.startSpan(ctx, "ArchiveTransactions")
+	archived, err := s.next.ArchiveTransactions(ctx, before)
+	endSpan(span, err)
+	return archived, err
+}
+
+// GetTransactionHistoryByDateRange retrieves transaction history within a date range
+// 日付範囲内のトランザクション履歴を取得
+func (s *TracingStorage) GetTransactionHistoryByDateRange(ctx context.Context, itemID string, from, to time.Time) ([]inventory.Transaction, error) {
+	ctx, span := s.startSpan(ctx, "GetTransactionHistoryByDateRange", attrItemID.String(itemID))
+	txs, err := s.next.GetTransactionHistoryByDateRange(ctx, itemID, from, to)
+	endSpanWithRows(span, len(txs), err)
+	return txs, err
+}
+
+// CreateItem creates a new item
+// 新しい商品を作成
+func (s *TracingStorage) CreateItem(ctx context.Context, item *inventory.Item) error {
+	ctx, span := s.startSpan(ctx, "CreateItem", attrItemID.String(item.ID))
+	err := s.next.CreateItem(ctx, item)
+	endSpan(span, err)
+	return err
+}
+
+// GetItem retrieves an item by ID
+// IDで商品を取得
+func (s *TracingStorage) GetItem(ctx context.Context, itemID string) (*inventory.Item, error) {
+	ctx, span := s.startSpan(ctx, "GetItem", attrItemID.String(itemID))
+	item, err := s.next.GetItem(ctx, itemID)
+	endSpan(span, err)
+	return item, err
+}
+
+// UpdateItem updates an existing item
+// 既存の商品を更新
+func (s *TracingStorage) UpdateItem(ctx context.Context, item *inventory.Item) error {
+	ctx, span := s.startSpan(ctx, "UpdateItem", attrItemID.String(item.ID))
+	err := s.next.UpdateItem(ctx, item)
+	endSpan(span, err)
+	return err
+}
+
+// DeleteItem deletes an item
+// 商品を削除
+func (s *TracingStorage) DeleteItem(ctx context.Context, itemID string) error {
+	ctx, span := s.startSpan(ctx, "DeleteItem", attrItemID.String(itemID))
+	err := s.next.DeleteItem(ctx, itemID)
+	endSpan(span, err)
+	return err
+}
+
+// ListItems retrieves items with pagination
+// ページネーション付きで商品一覧を取得
+func (s *TracingStorage) ListItems(ctx context.Context, offset, limit int) ([]inventory.Item, error) {
+	ctx, span := s.startSpan(ctx, "ListItems")
+	items, err := s.next.ListItems(ctx, offset, limit)
+	endSpanWithRows(span, len(items), err)
+	return items, err
+}
+
+// ListItemsAfter retrieves items matching a filter after the cursor
+// 条件に一致する商品をカーソルより後から取得
+func (s *TracingStorage) ListItemsAfter(ctx context.Context, filter inventory.ItemFilter, after *inventory.PageCursor, limit int) ([]inventory.Item, error) {
+	ctx, span := s.startSpan(ctx, "ListItemsAfter")
+	items, err := s.next.ListItemsAfter(ctx, filter, after, limit)
+	endSpanWithRows(span, len(items), err)
+	return items, err
+}
+
+// SearchItems searches items by query
+// クエリで商品を検索
+func (s *TracingStorage) SearchItems(ctx context.Context, query string) ([]inventory.Item, error) {
+	ctx, span := s.startSpan(ctx, "SearchItems")
+	items, err := s.next.SearchItems(ctx, query)
+	endSpanWithRows(span, len(items), err)
+	return items, err
+}
+
+// CreateLocation creates a new location
+// 新しいロケーションを作成
+func (s *TracingStorage) CreateLocation(ctx context.Context, location *inventory.Location) error {
+	ctx, span := s.startSpan(ctx, "CreateLocation", attrLocationID.String(location.ID))
+	err := s.next.CreateLocation(ctx, location)
+	endSpan(span, err)
+	return err
+}
+
+// GetLocation retrieves a location by ID
+// IDでロケーションを取得
+func (s *TracingStorage) GetLocation(ctx context.Context, locationID string) (*inventory.Location, error) {
+	ctx, span := s.startSpan(ctx, "GetLocation", attrLocationID.String(locationID))
+	location, err := s.next.GetLocation(ctx, locationID)
+	endSpan(span, err)
+	return location, err
+}
+
+// UpdateLocation updates an existing location
+// 既存のロケーションを更新
+func (s *TracingStorage) UpdateLocation(ctx context.Context, location *inventory.Location) error {
+	ctx, span := s.startSpan(ctx, "UpdateLocation", attrLocationID.String(location.ID))
+	err := s.next.UpdateLocation(ctx, location)
+	endSpan(span, err)
+	return err
+}
+
+// DeleteLocation deletes a location
+// ロケーションを削除
+func (s *TracingStorage) DeleteLocation(ctx context.Context, locationID string) error {
+	ctx, span := s.startSpan(ctx, "DeleteLocation", attrLocationID.String(locationID))
+	err := s.next.DeleteLocation(ctx, locationID)
+	endSpan(span, err)
+	return err
+}
+
+// ListLocations retrieves locations with pagination
+// ページネーション付きでロケーション一覧を取得
+func (s *TracingStorage) ListLocations(ctx context.Context, offset, limit int) ([]inventory.Location, error) {
+	ctx, span := s.startSpan(ctx, "ListLocations")
+	locations, err := s.next.ListLocations(ctx, offset, limit)
+	endSpanWithRows(span, len(locations), err)
+	return locations, err
+}
+
+// ListLocationsAfter retrieves locations older than the cursor
+// カーソルより古いロケーションを取得
+func (s *TracingStorage) ListLocationsAfter(ctx context.Context, after *inventory.PageCursor, limit int) ([]inventory.Location, error) {
+	ctx, span := s.startSpan(ctx, "ListLocationsAfter")
+	locations, err := s.next.ListLocationsAfter(ctx, after, limit)
+	endSpanWithRows(span, len(locations), err)
+	return locations, err
+}
+
+// CreateUnit registers a new unit of measure
+// 新しい単位を登録
+func (s *TracingStorage) CreateUnit(ctx context.Context, unit *inventory.UnitOfMeasure) error {
+	ctx, span := s.startSpan(ctx, "CreateUnit", attrUoM.String(unit.Code))
+	err := s.next.CreateUnit(ctx, unit)
+	endSpan(span, err)
+	return err
+}
+
+// GetUnit retrieves a unit of measure by code
+// コードで単位を取得
+func (s *TracingStorage) GetUnit(ctx context.Context, code string) (*inventory.UnitOfMeasure, error) {
+	ctx, span := s.startSpan(ctx, "GetUnit", attrUoM.String(code))
+	unit, err := s.next.GetUnit(ctx, code)
+	endSpan(span, err)
+	return unit, err
+}
+
+// ListUnits lists all units of measure
+// 全ての単位を取得
+func (s *TracingStorage) ListUnits(ctx context.Context) ([]inventory.UnitOfMeasure, error) {
+	ctx, span := s.startSpan(ctx, "ListUnits")
+	units, err := s.next.ListUnits(ctx)
+	endSpanWithRows(span, len(units), err)
+	return units, err
+}
+
+// DeleteUnit deletes a unit of measure
+// 単位を削除
+func (s *TracingStorage) DeleteUnit(ctx context.Context, code string) error {
+	ctx, span := s.startSpan(ctx, "DeleteUnit", attrUoM.String(code))
+	err := s.next.DeleteUnit(ctx, code)
+	endSpan(span, err)
+	return err
+}
+
+// SetUnitConversion registers or updates the conversion of an item's unit of measure
+// 商品の単位の換算を登録・更新
+func (s *TracingStorage) SetUnitConversion(ctx context.Context, conversion *inventory.UnitConversion) error {
+	ctx, span := s.startSpan(ctx, "SetUnitConversion", attrItemID.String(conversion.ItemID), attrUoM.String(conversion.UoM))
+	err := s.next.SetUnitConversion(ctx, conversion)
+	endSpan(span, err)
+	return err
+}
+
+// GetUnitConversion retrieves the conversion of an item's unit of measure
+// 商品の単位の換算を取得
+func (s *TracingStorage) GetUnitConversion(ctx context.Context, itemID, uom string) (*inventory.UnitConversion, error) {
+	ctx, span := s.startSpan(ctx, "GetUnitConversion", attrItemID.String(itemID), attrUoM.String(uom))
+	conversion, err := s.next.GetUnitConversion(ctx, itemID, uom)
+	endSpan(span, err)
+	return conversion, err
+}
+
+// ListUnitConversions lists the unit conversions of an item
+// 商品の単位の換算を取得
+func (s *TracingStorage) ListUnitConversions(ctx context.Context, itemID string) ([]inventory.UnitConversion, error) {
+	ctx, span := s.startSpan(ctx, "ListUnitConversions", attrItemID.String(itemID))
+	conversions, err := s.next.ListUnitConversions(ctx, itemID)
+	endSpanWithRows(span, len(conversions), err)
+	return conversions, err
+}
+
+// DeleteUnitConversion deletes the conversion of an item's unit of measure
+// 商品の単位の換算を削除
+func (s *TracingStorage) DeleteUnitConversion(ctx context.Context, itemID, uom string) error {
+	ctx, span := s.startSpan(ctx, "DeleteUnitConversion", attrItemID.String(itemID), attrUoM.String(uom))
+	err := s.next.DeleteUnitConversion(ctx, itemID, uom)
+	endSpan(span, err)
+	return err
+}
+
+// SetBillOfMaterials registers or replaces the bill of materials of a kit
+// キットの部品表を登録
+func (s *TracingStorage) SetBillOfMaterials(ctx context.Context, bom *inventory.BillOfMaterials) error {
+	ctx, span := s.startSpan(ctx, "SetBillOfMaterials", attrItemID.String(bom.KitItemID))
+	err := s.next.SetBillOfMaterials(ctx, bom)
+	endSpan(span, err)
+	return err
+}
+
+// GetBillOfMaterials retrieves the bill of materials of a kit
+// キットの部品表を取得
+func (s *TracingStorage) GetBillOfMaterials(ctx context.Context, kitItemID string) (*inventory.BillOfMaterials, error) {
+	ctx, span := s.startSpan(ctx, "GetBillOfMaterials", attrItemID.String(kitItemID))
+	bom, err := s.next.GetBillOfMaterials(ctx, kitItemID)
+	endSpan(span, err)
+	return bom, err
+}
+
+// ListBillsOfMaterials lists bills of materials
+// 部品表一覧を取得
+func (s *TracingStorage) ListBillsOfMaterials(ctx context.Context, offset, limit int) ([]inventory.BillOfMaterials, error) {
+	ctx, span := s.startSpan(ctx, "ListBillsOfMaterials")
+	boms, err := s.next.ListBillsOfMaterials(ctx, offset, limit)
+	endSpanWithRows(span, len(boms), err)
+	return boms, err
+}
+
+// DeleteBillOfMaterials deletes the bill of materials of a kit
+// キットの部品表を削除
+func (s *TracingStorage) DeleteBillOfMaterials(ctx context.Context, kitItemID string) error {
+	ctx, span := s.startSpan(ctx, "DeleteBillOfMaterials", attrItemID.String(kitItemID))
+	err := s.next.DeleteBillOfMaterials(ctx, kitItemID)
+	endSpan(span, err)
+	return err
+}
+
+// CreateAssembly records a kit assembly or disassembly
+// キットの組立・分解を記録
+func (s *TracingStorage) CreateAssembly(ctx context.Context, assembly *inventory.Assembly) error {
+	ctx, span := s.startSpan(ctx, "CreateAssembly", attrAssemblyID.String(assembly.ID), attrItemID.String(assembly.KitItemID), attrLocationID.String(assembly.LocationID))
+	err := s.next.CreateAssembly(ctx, assembly)
+	endSpan(span, err)
+	return err
+}
+
+// GetAssembly retrieves a kit assembly or disassembly by ID
+// IDでキットの組立・分解を取得
+func (s *TracingStorage) GetAssembly(ctx context.Context, assemblyID string) (*inventory.Assembly, error) {
+	ctx, span := s.startSpan(ctx, "GetAssembly", attrAssemblyID.String(assemblyID))
+	assembly, err := s.next.GetAssembly(ctx, assemblyID)
+	endSpan(span, err)
+	return assembly, err
+}
+
+// ListAssemblies lists kit assemblies and disassemblies matching a filter
+// 条件に一致するキットの組立・分解を取得
+func (s *TracingStorage) ListAssemblies(ctx context.Context, filter inventory.AssemblyFilter) ([]inventory.Assembly, error) {
+	ctx, span := s.startSpan(ctx, "ListAssemblies", attrItemID.String(filter.KitItemID), attrLocationID.String(filter.LocationID))
+	assemblies, err := s.next.ListAssemblies(ctx, filter)
+	endSpanWithRows(span, len(assemblies), err)
+	return assemblies, err
+}
+
+// CreateSupplier creates a new supplier
+// 新しい仕入先を作成
+func (s *TracingStorage) CreateSupplier(ctx context.Context, supplier *inventory.Supplier) error {
+	ctx, span := s.startSpan(ctx, "CreateSupplier", attrSupplierID.String(supplier.ID))
+	err := s.next.CreateSupplier(ctx, supplier)
+	endSpan(span, err)
+	return err
+}
+
+// GetSupplier retrieves a supplier by ID
+// IDで仕入先を取得
+func (s *TracingStorage) GetSupplier(ctx context.Context, supplierID string) (*inventory.Supplier, error) {
+	ctx, span := s.startSpan(ctx, "GetSupplier", attrSupplierID.String(supplierID))
+	supplier, err := s.next.GetSupplier(ctx, supplierID)
+	endSpan(span, err)
+	return supplier, err
+}
+
+// UpdateSupplier updates an existing supplier
+// 既存の仕入先を更新
+func (s *TracingStorage) UpdateSupplier(ctx context.Context, supplier *inventory.Supplier) error {
+	ctx, span := s.startSpan(ctx, "UpdateSupplier", attrSupplierID.String(supplier.ID))
+	err := s.next.UpdateSupplier(ctx, supplier)
+	endSpan(span, err)
+	return err
+}
+
+// DeleteSupplier deletes a supplier and the purchasing terms of its items
+// 仕入先を仕入条件とともに削除
+func (s *TracingStorage) DeleteSupplier(ctx context.Context, supplierID string) error {
+	ctx, span := s.startSpan(ctx, "DeleteSupplier", attrSupplierID.String(supplierID))
+	err := s.next.DeleteSupplier(ctx, supplierID)
+	endSpan(span, err)
+	return err
+}
+
+// ListSuppliers lists suppliers
+// 仕入先一覧を取得
+func (s *TracingStorage) ListSuppliers(ctx context.Context, offset, limit int) ([]inventory.Supplier, error) {
+	ctx, span := s.startSpan(ctx, "ListSuppliers")
+	suppliers, err := s.next.ListSuppliers(ctx, offset, limit)
+	endSpanWithRows(span, len(suppliers), err)
+	return suppliers, err
+}
+
+// SetSupplierItem registers or updates the purchasing terms of an item from a supplier
+// 仕入先の商品の仕入条件を登録
+func (s *TracingStorage) SetSupplierItem(ctx context.Context, supplierItem *inventory.SupplierItem) error {
+	ctx, span := s.startSpan(ctx, "SetSupplierItem", attrSupplierID.String(supplierItem.SupplierID), attrItemID.String(supplierItem.ItemID))
+	err := s.next.SetSupplierItem(ctx, supplierItem)
+	endSpan(span, err)
+	return err
+}
+
+// GetSupplierItem retrieves the purchasing terms of an item from a supplier
+// 仕入先の商品の仕入条件を取得
+func (s *TracingStorage) GetSupplierItem(ctx context.Context, supplierID, itemID string) (*inventory.SupplierItem, error) {
+	ctx, span := s.startSpan(ctx, "GetSupplierItem", attrSupplierID.String(supplierID), attrItemID.String(itemID))
+	supplierItem, err := s.next.GetSupplierItem(ctx, supplierID, itemID)
+	endSpan(span, err)
+	return supplierItem, err
+}
+
+// DeleteSupplierItem deletes the purchasing terms of an item from a supplier
+// 仕入先の商品の仕入条件を削除
+func (s *TracingStorage) DeleteSupplierItem(ctx context.Context, supplierID, itemID string) error {
+	ctx, span := s.startSpan(ctx, "DeleteSupplierItem", attrSupplierID.String(supplierID), attrItemID.String(itemID))
+	err := s.next.DeleteSupplierItem(ctx, supplierID, itemID)
+	endSpan(span, err)
+	return err
+}
+
+// ListSupplierItems lists the purchasing terms matching a filter
+// 条件に一致する仕入条件を取得
+func (s *TracingStorage) ListSupplierItems(ctx context.Context, filter inventory.SupplierItemFilter) ([]inventory.SupplierItem, error) {
+	ctx, span := s.startSpan(ctx, "ListSupplierItems", attrSupplierID.String(filter.SupplierID), attrItemID.String(filter.ItemID))
+	supplierItems, err := s.next.ListSupplierItems(ctx, filter)
+	endSpanWithRows(span, len(supplierItems), err)
+	return supplierItems, err
+}
+
+// CreatePurchaseOrder creates a purchase order with its lines
+// 発注と行を作成
+func (s *TracingStorage) CreatePurchaseOrder(ctx context.Context, order *inventory.PurchaseOrder) error {
+	ctx, span := s.startSpan(ctx, "CreatePurchaseOrder", attrPurchaseOrder.String(order.ID), attrSupplierID.String(order.SupplierID))
+	err := s.next.CreatePurchaseOrder(ctx, order)
+	endSpan(span, err)
+	return err
+}
+
+// GetPurchaseOrder retrieves a purchase order with its lines
+// 発注を行とともに取得
+func (s *TracingStorage) GetPurchaseOrder(ctx context.Context, orderID string) (*inventory.PurchaseOrder, error) {
+	ctx, span := s.startSpan(ctx, "GetPurchaseOrder", attrPurchaseOrder.String(orderID))
+	order, err := s.next.GetPurchaseOrder(ctx, orderID)
+	endSpan(span, err)
+	return order, err
+}
+
+// ListPurchaseOrders lists purchase orders matching a filter
+// 条件に一致する発注を取得
+func (s *TracingStorage) ListPurchaseOrders(ctx context.Context, filter inventory.PurchaseOrderFilter) ([]inventory.PurchaseOrder, error) {
+	ctx, span := s.startSpan(ctx, "ListPurchaseOrders", attrSupplierID.String(filter.SupplierID), attrLocationID.String(filter.LocationID))
+	orders, err := s.next.ListPurchaseOrders(ctx, filter)
+	endSpanWithRows(span, len(orders), err)
+	return orders, err
+}
+
+// UpdatePurchaseOrder updates the status of a purchase order in status from
+// 状態がfromの発注の状態を更新
+func (s *TracingStorage) UpdatePurchaseOrder(ctx context.Context, order *inventory.PurchaseOrder, from inventory.PurchaseOrderStatus) error {
+	ctx, span := s.startSpan(ctx, "UpdatePurchaseOrder", attrPurchaseOrder.String(order.ID))
+	err := s.next.UpdatePurchaseOrder(ctx, order, from)
+	endSpan(span, err)
+	return err
+}
+
+// ReceivePurchaseOrderLine adds a received quantity to a purchase order line
+// 発注の行の入荷済み数量を加算
+func (s *TracingStorage) ReceivePurchaseOrderLine(ctx context.Context, orderID, itemID string, quantity int64) (*inventory.PurchaseOrderLine, error) {
+	ctx, span := s.startSpan(ctx, "ReceivePurchaseOrderLine", attrPurchaseOrder.String(orderID), attrItemID.String(itemID))
+	line, err := s.next.ReceivePurchaseOrderLine(ctx, orderID, itemID, quantity)
+	endSpan(span, err)
+	return line, err
+}
+
+// CreateGoodsReceipt records a goods receipt against a purchase order
+// 発注に対する入荷を記録
+func (s *TracingStorage) CreateGoodsReceipt(ctx context.Context, receipt *inventory.GoodsReceipt) error {
+	ctx, span := s.startSpan(ctx, "CreateGoodsReceipt", attrPurchaseOrder.String(receipt.PurchaseOrderID), attrLocationID.String(receipt.LocationID))
+	err := s.next.CreateGoodsReceipt(ctx, receipt)
+	endSpan(span, err)
+	return err
+}
+
+// ListGoodsReceipts lists the goods receipts of a purchase order
+// 発注に対する入荷を取得
+func (s *TracingStorage) ListGoodsReceipts(ctx context.Context, orderID string) ([]inventory.GoodsReceipt, error) {
+	ctx, span := s.startSpan(ctx, "ListGoodsReceipts", attrPurchaseOrder.String(orderID))
+	receipts, err := s.next.ListGoodsReceipts(ctx, orderID)
+	endSpanWithRows(span, len(receipts), err)
+	return receipts, err
+}
+
+// CreateSalesOrder creates a sales order with its lines
+// 受注と行を作成
+func (s *TracingStorage) CreateSalesOrder(ctx context.Context, order *inventory.SalesOrder) error {
+	ctx, span := s.startSpan(ctx, "CreateSalesOrder", attrSalesOrderID.String(order.ID), attrLocationID.String(order.LocationID))
+	err := s.next.CreateSalesOrder(ctx, order)
+	endSpan(span, err)
+	return err
+}
+
+// GetSalesOrder retrieves a sales order with its lines
+// 受注を行とともに取得
+func (s *TracingStorage) GetSalesOrder(ctx context.Context, orderID string) (*inventory.SalesOrder, error) {
+	ctx, span := s.startSpan(ctx, "GetSalesOrder", attrSalesOrderID.String(orderID))
+	order, err := s.next.GetSalesOrder(ctx, orderID)
+	endSpan(span, err)
+	return order, err
+}
+
+// ListSalesOrders lists sales orders matching a filter
+// 条件に一致する受注を取得
+func (s *TracingStorage) ListSalesOrders(ctx context.Context, filter inventory.SalesOrderFilter) ([]inventory.SalesOrder, error) {
+	ctx, span := s.startSpan(ctx, "ListSalesOrders", attrLocationID.String(filter.LocationID))
+	orders, err := s.next.ListSalesOrders(ctx, filter)
+	endSpanWithRows(span, len(orders), err)
+	return orders, err
+}
+
+// UpdateSalesOrder updates a sales order with optimistic locking
+// 受注を楽観的ロックで更新
+func (s *TracingStorage) UpdateSalesOrder(ctx context.Context, order *inventory.SalesOrder) error {
+	ctx, span := s.startSpan(ctx, "UpdateSalesOrder", attrSalesOrderID.String(order.ID))
+	err := s.next.UpdateSalesOrder(ctx, order)
+	endSpan(span, err)
+	return err
+}
+
+// CreateReturn creates a return with its lines
+// 返品と行を作成
+func (s *TracingStorage) CreateReturn(ctx context.Context, rma *inventory.ReturnAuthorization) error {
+	ctx, span := s.startSpan(ctx, "CreateReturn", attrReturnID.String(rma.ID), attrLocationID.String(rma.LocationID))
+	err := s.next.CreateReturn(ctx, rma)
+	endSpan(span, err)
+	return err
+}
+
+// GetReturn retrieves a return with its lines
+// 返品を行とともに取得
+func (s *TracingStorage) GetReturn(ctx context.Context, returnID string) (*inventory.ReturnAuthorization, error) {
+	ctx, span := s.startSpan(ctx, "GetReturn", attrReturnID.String(returnID))
+	rma, err := s.next.GetReturn(ctx, returnID)
+	endSpan(span, err)
+	return rma, err
+}
+
+// ListReturns lists returns matching a filter
+// 条件に一致する返品を取得
+func (s *TracingStorage) ListReturns(ctx context.Context, filter inventory.ReturnFilter) ([]inventory.ReturnAuthorization, error) {
+	ctx, span := s.startSpan(ctx, "ListReturns", attrLocationID.String(filter.LocationID))
+	returns, err := s.next.ListReturns(ctx, filter)
+	endSpanWithRows(span, len(returns), err)
+	return returns, err
+}
+
+// UpdateReturn updates a return with optimistic locking
+// 返品を楽観的ロックで更新
+func (s *TracingStorage) UpdateReturn(ctx context.Context, rma *inventory.ReturnAuthorization) error {
+	ctx, span := s.startSpan(ctx, "UpdateReturn", attrReturnID.String(rma.ID))
+	err := s.next.UpdateReturn(ctx, rma)
+	endSpan(span, err)
+	return err
+}
+
+// CreateReturnDisposition records a disposition of returned units
+// 返品の処分を記録
+func (s *TracingStorage) CreateReturnDisposition(ctx context.Context, disposition *inventory.ReturnDisposition) error {
+	ctx, span := s.startSpan(ctx, "CreateReturnDisposition", attrReturnID.String(disposition.ReturnID), attrItemID.String(disposition.ItemID))
+	err := s.next.CreateReturnDisposition(ctx, disposition)
+	endSpan(span, err)
+	return err
+}
+
+// ListReturnDispositions lists the dispositions of a return
+// 返品の処分を取得
+func (s *TracingStorage) ListReturnDispositions(ctx context.Context, returnID string) ([]inventory.ReturnDisposition, error) {
+	ctx, span := s.startSpan(ctx, "ListReturnDispositions", attrReturnID.String(returnID))
+	dispositions, err := s.next.ListReturnDispositions(ctx, returnID)
+	endSpanWithRows(span, len(dispositions), err)
+	return dispositions, err
+}
+
+// CreateLot creates a new lot
+// 新しいロットを作成
+func (s *TracingStorage) CreateLot(ctx context.Context, lot *inventory.Lot) error {
+	ctx, span := s.startSpan(ctx, "CreateLot", attrLotID.String(lot.ID), attrItemID.String(lot.ItemID))
+	err := s.next.CreateLot(ctx, lot)
+	endSpan(span, err)
+	return err
+}
+
+// GetLot retrieves a lot by ID
+// IDでロットを取得
+func (s *TracingStorage) GetLot(ctx context.Context, lotID string) (*inventory.Lot, error) {
+	ctx, span := s.startSpan(ctx, "GetLot", attrLotID.String(lotID))
+	lot, err := s.next.GetLot(ctx, lotID)
+	endSpan(span, err)
+	return lot, err
+}
+
+// UpdateLot updates a lot
+// ロットを更新
+func (s *TracingStorage) UpdateLot(ctx context.Context, lot *inventory.Lot) error {
+	ctx, span := s.startSpan(ctx, "UpdateLot", attrLotID.String(lot.ID))
+	err := s.next.UpdateLot(ctx, lot)
+	endSpan(span, err)
+	return err
+}
+
+// DeleteLot deletes a lot
+// ロットを削除
+func (s *TracingStorage) DeleteLot(ctx context.Context, lotID string) error {
+	ctx, span := s.startSpan(ctx, "DeleteLot", attrLotID.String(lotID))
+	err := s.next.DeleteLot(ctx, lotID)
+	endSpan(span, err)
+	return err
+}
+
+// AdjustLotQuantity adds delta to a lot's quantity
+// ロットの数量を増減
+func (s *TracingStorage) AdjustLotQuantity(ctx context.Context, lotID string, delta int64) (*inventory.Lot, error) {
+	ctx, span := s.startSpan(ctx, "AdjustLotQuantity", attrLotID.String(lotID))
+	lot, err := s.next.AdjustLotQuantity(ctx, lotID, delta)
+	endSpan(span, err)
+	return lot, err
+}
+
+// GetLotsByItem retrieves all lots for an item
+// 商品の全ロットを取得
+func (s *TracingStorage) GetLotsByItem(ctx context.Context, itemID string) ([]inventory.Lot, error) {
+	ctx, span := s.startSpan(ctx, "GetLotsByItem", attrItemID.String(itemID))
+	lots, err := s.next.GetLotsByItem(ctx, itemID)
+	endSpanWithRows(span, len(lots), err)
+	return lots, err
+}
+
+// GetExpiringLots retrieves lots expiring within the given duration
+// 指定期間内に期限切れになるロットを取得
+func (s *TracingStorage) GetExpiringLots(ctx context.Context, within time.Duration) ([]inventory.Lot, error) {
+	ctx, span := s.startSpan(ctx, "GetExpiringLots")
+	lots, err := s.next.GetExpiringLots(ctx, within)
+	endSpanWithRows(span, len(lots), err)
+	return lots, err
+}
+
+// GetExpiredLots retrieves lots that have already expired
+// 期限切れのロットを取得
+func (s *TracingStorage) GetExpiredLots(ctx context.Context) ([]inventory.Lot, error) {
+	ctx, span := s.startSpan(ctx, "GetExpiredLots")
+	lots, err := s.next.GetExpiredLots(ctx)
+	endSpanWithRows(span, len(lots), err)
+	return lots, err
+}
+
+// GetLotByNumber retrieves the oldest lot of an item with a lot number
+// 商品のロット番号のロットを取得
+func (s *TracingStorage) GetLotByNumber(ctx context.Context, itemID, lotNumber string) (*inventory.Lot, error) {
+	ctx, span := s.startSpan(ctx, "GetLotByNumber", attrItemID.String(itemID))
+	lot, err := s.next.GetLotByNumber(ctx, itemID, lotNumber)
+	endSpan(span, err)
+	return lot, err
+}
+
+// AdjustLotStock adds delta to the quantity of a lot at a location
+// ロットのロケーションの在庫数量を増減
+func (s *TracingStorage) AdjustLotStock(ctx context.Context, lot *inventory.Lot, locationID string, delta int64) (*inventory.LotStock, error) {
+	ctx, span := s.startSpan(ctx, "AdjustLotStock", attrLotID.String(lot.ID), attrLocationID.String(locationID))
+	lotStock, err := s.next.AdjustLotStock(ctx, lot, locationID, delta)
+	endSpan(span, err)
+	return lotStock, err
+}
+
+// GetLotStock retrieves the stock of a lot at a location
+// ロットのロケーションの在庫を取得
+func (s *TracingStorage) GetLotStock(ctx context.Context, lotID, locationID string) (*inventory.LotStock, error) {
+	ctx, span := s.startSpan(ctx, "GetLotStock", attrLotID.String(lotID), attrLocationID.String(locationID))
+	lotStock, err := s.next.GetLotStock(ctx, lotID, locationID)
+	endSpan(span, err)
+	return lotStock, err
+}
+
+// ListLotStocks lists lot stocks matching a filter
+// 条件に一致するロット在庫を取得
+func (s *TracingStorage) ListLotStocks(ctx context.Context, filter inventory.LotStockFilter) ([]inventory.LotStock, error) {
+	ctx, span := s.startSpan(ctx, "ListLotStocks")
+	lotStocks, err := s.next.ListLotStocks(ctx, filter)
+	endSpanWithRows(span, len(lotStocks), err)
+	return lotStocks, err
+}
+
+// AdjustConsignmentStock adds delta to the consignment stock of an owner
+// 所有者の委託在庫の数量を増減
+func (s *TracingStorage) AdjustConsignmentStock(ctx context.Context, ownerID, itemID, locationID string, delta int64) (*inventory.ConsignmentStock, error) {
+	ctx, span := s.startSpan(ctx, "AdjustConsignmentStock",
+		attrOwnerID.String(ownerID), attrItemID.String(itemID), attrLocationID.String(locationID))
+	consignment, err := s.next.AdjustConsignmentStock(ctx, ownerID, itemID, locationID, delta)
+	endSpan(span, err)
+	return consignment, err
+}
+
+// ListConsignmentStocks lists consignment stocks matching a filter
+// 条件に一致する委託在庫を取得
+func (s *TracingStorage) ListConsignmentStocks(ctx context.Context, filter inventory.ConsignmentStockFilter) ([]inventory.ConsignmentStock, error) {
+	ctx, span := s.startSpan(ctx, "ListConsignmentStocks")
+	consignments, err := s.next.ListConsignmentStocks(ctx, filter)
+	endSpanWithRows(span, len(consignments), err)
+	return consignments, err
+}
+
+// CreateAlert creates a new alert
+// 新しいアラートを作成
+func (s *TracingStorage) CreateAlert(ctx context.Context, alert *inventory.StockAlert) error {
+	ctx, span := s.startSpan(ctx, "CreateAlert",
+		attrAlertID.String(alert.ID), attrItemID.String(alert.ItemID), attrLocationID.String(alert.LocationID))
+	err := s.next.CreateAlert(ctx, alert)
+	endSpan(span, err)
+	return err
+}
+
+// GetActiveAlerts retrieves active alerts for a location
+// ロケーションのアクティブなアラートを取得
+func (s *TracingStorage) GetActiveAlerts(ctx context.Context, locationID string) ([]inventory.StockAlert, error) {
+	ctx, span := s.startSpan(ctx, "GetActiveAlerts", attrLocationID.String(locationID))
+	alerts, err := s.next.GetActiveAlerts(ctx, locationID)
+	endSpanWithRows(span, len(alerts), err)
+	return alerts, err
+}
+
+// ListAlerts lists alerts of all locations matching a filter, newest first
+// 条件に一致するアラートを全ロケーションから作成日時の新しい順に取得（解決済みを含む）
+func (s *TracingStorage) ListAlerts(ctx context.Context, filter inventory.AlertFilter) ([]inventory.StockAlert, error) {
+	ctx, span := s.startSpan(ctx, "ListAlerts", attrItemID.String(filter.ItemID), attrLocationID.String(filter.LocationID))
+	alerts, err := s.next.ListAlerts(ctx, filter)
+	endSpanWithRows(span, len(alerts), err)
+	return alerts, err
+}
+
+// GetAlert retrieves an alert by ID
+// IDでアラートを取得
+func (s *TracingStorage) GetAlert(ctx context.Context, alertID string) (*inventory.StockAlert, error) {
+	ctx, span := s.startSpan(ctx, "GetAlert", attrAlertID.String(alertID))
+	alert, err := s.next.GetAlert(ctx, alertID)
+	endSpan(span, err)
+	return alert, err
+}
+
+// ResolveAlert marks an alert as resolved
+// アラートを解決済みにする
+func (s *TracingStorage) ResolveAlert(ctx context.Context, alertID, resolvedBy, note string) error {
+	ctx, span := s.startSpan(ctx, "ResolveAlert", attrAlertID.String(alertID))
+	err := s.next.ResolveAlert(ctx, alertID, resolvedBy, note)
+	endSpan(span, err)
+	return err
+}
+
+// AcknowledgeAlert marks an alert as acknowledged
+// アラートを確認済みにする
+func (s *TracingStorage) AcknowledgeAlert(ctx context.Context, alertID, acknowledgedBy, note string) error {
+	ctx, span := s.startSpan(ctx, "AcknowledgeAlert", attrAlertID.String(alertID))
+	err := s.next.AcknowledgeAlert(ctx, alertID, acknowledgedBy, note)
+	endSpan(span, err)
+	return err
+}
+
+// GetLatestRuleAlert retrieves the latest alert an alert rule raised for a stock record
+// アラートルールが在庫記録に対して最後に作成したアラートを取得
+func (s *TracingStorage) GetLatestRuleAlert(ctx context.Context, ruleID, itemID, locationID string) (*inventory.StockAlert, error) {
+	ctx, span := s.startSpan(ctx, "GetLatestRuleAlert", attrAlertRuleID.String(ruleID), attrItemID.String(itemID), attrLocationID.String(locationID))
+	alert, err := s.next.GetLatestRuleAlert(ctx, ruleID, itemID, locationID)
+	endSpan(span, err)
+	return alert, err
+}
+
+// CreateAlertRule creates a new alert rule
+// 新しいアラートルールを作成
+func (s *TracingStorage) CreateAlertRule(ctx context.Context, rule *inventory.AlertRule) error {
+	ctx, span := s.startSpan(ctx, "CreateAlertRule", attrAlertRuleID.String(rule.ID))
+	err := s.next.CreateAlertRule(ctx, rule)
+	endSpan(span, err)
+	return err
+}
+
+// GetAlertRule retrieves an alert rule by ID
+// IDでアラートルールを取得
+func (s *TracingStorage) GetAlertRule(ctx context.Context, ruleID string) (*inventory.AlertRule, error) {
+	ctx, span := s.startSpan(ctx, "GetAlertRule", attrAlertRuleID.String(ruleID))
+	rule, err := s.next.GetAlertRule(ctx, ruleID)
+	endSpan(span, err)
+	return rule, err
+}
+
+// UpdateAlertRule replaces the condition, scope and state of an alert rule
+// アラートルールを更新
+func (s *TracingStorage) UpdateAlertRule(ctx context.Context, rule *inventory.AlertRule) error {
+	ctx, span := s.startSpan(ctx, "UpdateAlertRule", attrAlertRuleID.String(rule.ID))
+	err := s.next.UpdateAlertRule(ctx, rule)
+	endSpan(span, err)
+	return err
+}
+
+// DeleteAlertRule deletes an alert rule
+// アラートルールを削除
+func (s *TracingStorage) DeleteAlertRule(ctx context.Context, ruleID string) error {
+	ctx, span := s.startSpan(ctx, "DeleteAlertRule", attrAlertRuleID.String(ruleID))
+	err := s.next.DeleteAlertRule(ctx, ruleID)
+	endSpan(span, err)
+	return err
+}
+
+// ListAlertRules lists alert rules matching a filter
+// 条件に一致するアラートルールを取得
+func (s *TracingStorage) ListAlertRules(ctx context.Context, filter inventory.AlertRuleFilter) ([]inventory.AlertRule, error) {
+	ctx, span := s.startSpan(ctx, "ListAlertRules", attrItemID.String(filter.ItemID), attrLocationID.String(filter.LocationID))
+	rules, err := s.next.ListAlertRules(ctx, filter)
+	endSpanWithRows(span, len(rules), err)
+	return rules, err
+}
+
+// GetAlertRulesForStock retrieves the enabled alert rules whose scope covers a stock record
+// 在庫記録を対象範囲に含む有効なアラートルールを取得
+func (s *TracingStorage) GetAlertRulesForStock(ctx context.Context, itemID, locationID string) ([]inventory.AlertRule, error) {
+	ctx, span := s.startSpan(ctx, "GetAlertRulesForStock", attrItemID.String(itemID), attrLocationID.String(locationID))
+	rules, err := s.next.GetAlertRulesForStock(ctx, itemID, locationID)
+	endSpanWithRows(span, len(rules), err)
+	return rules, err
+}
+
+// CreateReservation creates a new reservation
+// 新しい予約を作成
+func (s *TracingStorage) CreateReservation(ctx context.Context, reservation *inventory.Reservation) error {
+	ctx, span := s.startSpan(ctx, "CreateReservation", attrReservationID.String(reservation.ID), attrItemID.String(reservation.ItemID), attrLocationID.String(reservation.LocationID))
+	err := s.next.CreateReservation(ctx, reservation)
+	endSpan(span, err)
+	return err
+}
+
+// GetReservation retrieves a reservation by ID
+// IDで予約を取得
+func (s *TracingStorage) GetReservation(ctx context.Context, reservationID string) (*inventory.Reservation, error) {
+	ctx, span := s.startSpan(ctx, "GetReservation", attrReservationID.String(reservationID))
+	reservation, err := s.next.GetReservation(ctx, reservationID)
+	endSpan(span, err)
+	return reservation, err
+}
+
+// UpdateReservation updates an active reservation
+// 有効な予約を更新
+func (s *TracingStorage) UpdateReservation(ctx context.Context, reservation *inventory.Reservation) error {
+	ctx, span := s.startSpan(ctx, "UpdateReservation", attrReservationID.String(reservation.ID))
+	err := s.next.UpdateReservation(ctx, reservation)
+	endSpan(span, err)
+	return err
+}
+
+// ListReservations lists reservations matching a filter
+// 条件に一致する予約を取得
+func (s *TracingStorage) ListReservations(ctx context.Context, filter inventory.ReservationFilter) ([]inventory.Reservation, error) {
+	ctx, span := s.startSpan(ctx, "ListReservations", attrItemID.String(filter.ItemID), attrLocationID.String(filter.LocationID))
+	reservations, err := s.next.ListReservations(ctx, filter)
+	endSpanWithRows(span, len(reservations), err)
+	return reservations, err
+}
+
+// GetExpiredReservations retrieves active reservations that expired before a time
+// 有効期限を過ぎた有効な予約を取得
+func (s *TracingStorage) GetExpiredReservations(ctx context.Context, before time.Time, limit int) ([]inventory.Reservation, error) {
+	ctx, span := s.startSpan(ctx, "GetExpiredReservations")
+	reservations, err := s.next.GetExpiredReservations(ctx, before, limit)
+	endSpanWithRows(span, len(reservations), err)
+	return reservations, err
+}
+
+// SetChannelAllocation creates, updates or deletes a sales channel allocation
+// 販売チャネルの確保数量を作成・更新・削除
+func (s *TracingStorage) SetChannelAllocation(ctx context.Context, allocation *inventory.ChannelAllocation) error {
+	ctx, span := s.startSpan(ctx, "SetChannelAllocation",
+		attrItemID.String(allocation.ItemID), attrLocationID.String(allocation.LocationID), attrChannel.String(allocation.Channel))
+	err := s.next.SetChannelAllocation(ctx, allocation)
+	endSpan(span, err)
+	return err
+}
+
+// ListChannelAllocations lists the sales channel allocations of an item at a location
+// 商品のロケーションの販売チャネルの確保を取得
+func (s *TracingStorage) ListChannelAllocations(ctx context.Context, itemID, locationID string) ([]inventory.ChannelAllocation, error) {
+	ctx, span := s.startSpan(ctx, "ListChannelAllocations", attrItemID.String(itemID), attrLocationID.String(locationID))
+	allocations, err := s.next.ListChannelAllocations(ctx, itemID, locationID)
+	endSpanWithRows(span, len(allocations), err)
+	return allocations, err
+}
+
+// CreateBackorder creates a new backorder
+// 新しいバックオーダーを作成
+func (s *TracingStorage) CreateBackorder(ctx context.Context, backorder *inventory.Backorder) error {
+	ctx, span := s.startSpan(ctx, "CreateBackorder", attrBackorderID.String(backorder.ID), attrItemID.String(backorder.ItemID), attrLocationID.String(backorder.LocationID))
+	err := s.next.CreateBackorder(ctx, backorder)
+	endSpan(span, err)
+	return err
+}
+
+// GetBackorder retrieves a backorder by ID
+// IDでバックオーダーを取得
+func (s *TracingStorage) GetBackorder(ctx context.Context, backorderID string) (*inventory.Backorder, error) {
+	ctx, span := s.startSpan(ctx, "GetBackorder", attrBackorderID.String(backorderID))
+	backorder, err := s.next.GetBackorder(ctx, backorderID)
+	endSpan(span, err)
+	return backorder, err
+}
+
+// UpdateBackorder updates a pending backorder
+// 入荷待ちのバックオーダーを更新
+func (s *TracingStorage) UpdateBackorder(ctx context.Context, backorder *inventory.Backorder) error {
+	ctx, span := s.startSpan(ctx, "UpdateBackorder", attrBackorderID.String(backorder.ID))
+	err := s.next.UpdateBackorder(ctx, backorder)
+	endSpan(span, err)
+	return err
+}
+
+// ListBackorders lists backorders matching a filter
+// 条件に一致するバックオーダーを取得
+func (s *TracingStorage) ListBackorders(ctx context.Context, filter inventory.BackorderFilter) ([]inventory.Backorder, error) {
+	ctx, span := s.startSpan(ctx, "ListBackorders", attrItemID.String(filter.ItemID), attrLocationID.String(filter.LocationID))
+	backorders, err := s.next.ListBackorders(ctx, filter)
+	endSpanWithRows(span, len(backorders), err)
+	return backorders, err
+}
+
+// SaveReorderPoint creates or overwrites the reorder point of an item at a location
+// 商品・ロケーションの発注点を作成または上書き
+func (s *TracingStorage) SaveReorderPoint(ctx context.Context, reorderPoint *inventory.ReorderPoint) error {
+	ctx, span := s.startSpan(ctx, "SaveReorderPoint", attrItemID.String(reorderPoint.ItemID), attrLocationID.String(reorderPoint.LocationID))
+	err := s.next.SaveReorderPoint(ctx, reorderPoint)
+	endSpan(span, err)
+	return err
+}
+
+// GetReorderPoint retrieves the reorder point of an item at a location
+// 商品・ロケーションの発注点を取得
+func (s *TracingStorage) GetReorderPoint(ctx context.Context, itemID, locationID string) (*inventory.ReorderPoint, error) {
+	ctx, span := s.startSpan(ctx, "GetReorderPoint", attrItemID.String(itemID), attrLocationID.String(locationID))
+	reorderPoint, err := s.next.GetReorderPoint(ctx, itemID, locationID)
+	endSpan(span, err)
+	return reorderPoint, err
+}
+
+// DeleteReorderPoint deletes the reorder point of an item at a location
+// 商品・ロケーションの発注点を削除
+func (s *TracingStorage) DeleteReorderPoint(ctx context.Context, itemID, locationID string) error {
+	ctx, span := s.startSpan(ctx, "DeleteReorderPoint", attrItemID.String(itemID), attrLocationID.String(locationID))
+	err := s.next.DeleteReorderPoint(ctx, itemID, locationID)
+	endSpan(span, err)
+	return err
+}
+
+// ListReorderPoints lists reorder points matching a filter
+// 条件に一致する発注点を取得
+func (s *TracingStorage) ListReorderPoints(ctx context.Context, filter inventory.ReorderPointFilter) ([]inventory.ReorderPoint, error) {
+	ctx, span := s.startSpan(ctx, "ListReorderPoints", attrItemID.String(filter.ItemID), attrLocationID.String(filter.LocationID))
+	reorderPoints, err := s.next.ListReorderPoints(ctx, filter)
+	endSpanWithRows(span, len(reorderPoints), err)
+	return reorderPoints, err
+}
+
+// CreateStockSnapshot copies every stock record into a new snapshot
+// 全ての在庫記録を複製してスナップショットを作成
+func (s *TracingStorage) CreateStockSnapshot(ctx context.Context, snapshot *inventory.StockSnapshot) error {
+	ctx, span := s.startSpan(ctx, "CreateStockSnapshot", attrSnapshotID.String(snapshot.ID))
+	err := s.next.CreateStockSnapshot(ctx, snapshot)
+	endSpan(span, err)
+	return err
+}
+
+// ListStockSnapshots lists stock snapshots, newest first
+// スナップショットを取得日時の新しい順に取得
+func (s *TracingStorage) ListStockSnapshots(ctx context.Context, offset, limit int) ([]inventory.StockSnapshot, error) {
+	ctx, span := s.startSpan(ctx, "ListStockSnapshots")
+	snapshots, err := s.next.ListStockSnapshots(ctx, offset, limit)
+	endSpanWithRows(span, len(snapshots), err)
+	return snapshots, err
+}
+
+// GetStockSnapshotLines retrieves the stock records copied into a snapshot
+// スナップショットの在庫記録を取得
+func (s *TracingStorage) GetStockSnapshotLines(ctx context.Context, snapshotID, locationID string) ([]inventory.StockSnapshotLine, error) {
+	ctx, span := s.startSpan(ctx, "GetStockSnapshotLines", attrSnapshotID.String(snapshotID), attrLocationID.String(locationID))
+	lines, err := s.next.GetStockSnapshotLines(ctx, snapshotID, locationID)
+	endSpanWithRows(span, len(lines), err)
+	return lines, err
+}
+
+// CreateStocktake creates a stocktake with its lines
+// 棚卸と行を作成
+func (s *TracingStorage) CreateStocktake(ctx context.Context, stocktake *inventory.Stocktake) error {
+	ctx, span := s.startSpan(ctx, "CreateStocktake", attrStocktakeID.String(stocktake.ID), attrLocationID.String(stocktake.LocationID))
+	err := s.next.CreateStocktake(ctx, stocktake)
+	endSpan(span, err)
+	return err
+}
+
+// GetStocktake retrieves a stocktake with its lines
+// 棚卸を行とともに取得
+func (s *TracingStorage) GetStocktake(ctx context.Context, stocktakeID string) (*inventory.Stocktake, error) {
+	ctx, span := s.startSpan(ctx, "GetStocktake", attrStocktakeID.String(stocktakeID))
+	stocktake, err := s.next.GetStocktake(ctx, stocktakeID)
+	endSpan(span, err)
+	return stocktake, err
+}
+
+// ListStocktakes lists stocktakes matching a filter
+// 条件に一致する棚卸を取得
+func (s *TracingStorage) ListStocktakes(ctx context.Context, filter inventory.StocktakeFilter) ([]inventory.Stocktake, error) {
+	ctx, span := s.startSpan(ctx, "ListStocktakes", attrLocationID.String(filter.LocationID))
+	stocktakes, err := s.next.ListStocktakes(ctx, filter)
+	endSpanWithRows(span, len(stocktakes), err)
+	return stocktakes, err
+}
+
+// UpdateStocktake updates the status and lines of a stocktake in status from
+// 状態がfromの棚卸の状態・行を更新
+func (s *TracingStorage) UpdateStocktake(ctx context.Context, stocktake *inventory.Stocktake, from inventory.StocktakeStatus) error {
+	ctx, span := s.startSpan(ctx, "UpdateStocktake", attrStocktakeID.String(stocktake.ID))
+	err := s.next.UpdateStocktake(ctx, stocktake, from)
+	endSpan(span, err)
+	return err
+}
+
+// CreateAdjustment creates a stock adjustment
+// 在庫調整を作成
+func (s *TracingStorage) CreateAdjustment(ctx context.Context, adjustment *inventory.Adjustment) error {
+	ctx, span := s.startSpan(ctx, "CreateAdjustment", attrAdjustmentID.String(adjustment.ID), attrItemID.String(adjustment.ItemID), attrLocationID.String(adjustment.LocationID))
+	err := s.next.CreateAdjustment(ctx, adjustment)
+	endSpan(span, err)
+	return err
+}
+
+// GetAdjustment retrieves a stock adjustment by ID
+// IDで在庫調整を取得
+func (s *TracingStorage) GetAdjustment(ctx context.Context, adjustmentID string) (*inventory.Adjustment, error) {
+	ctx, span := s.startSpan(ctx, "GetAdjustment", attrAdjustmentID.String(adjustmentID))
+	adjustment, err := s.next.GetAdjustment(ctx, adjustmentID)
+	endSpan(span, err)
+	return adjustment, err
+}
+
+// ListAdjustments lists stock adjustments matching a filter
+// 条件に一致する在庫調整を取得
+func (s *TracingStorage) ListAdjustments(ctx context.Context, filter inventory.AdjustmentFilter) ([]inventory.Adjustment, error) {
+	ctx, span := s.startSpan(ctx, "ListAdjustments", attrItemID.String(filter.ItemID), attrLocationID.String(filter.LocationID))
+	adjustments, err := s.next.ListAdjustments(ctx, filter)
+	endSpanWithRows(span, len(adjustments), err)
+	return adjustments, err
+}
+
+// UpdateAdjustment records the decision on a stock adjustment in status from
+// 状態がfromの在庫調整に承認・却下の結果を記録
+func (s *TracingStorage) UpdateAdjustment(ctx context.Context, adjustment *inventory.Adjustment, from inventory.AdjustmentStatus) error {
+	ctx, span := s.startSpan(ctx, "UpdateAdjustment", attrAdjustmentID.String(adjustment.ID))
+	err := s.next.UpdateAdjustment(ctx, adjustment, from)
+	endSpan(span, err)
+	return err
+}
+
+// CreateInspection creates a receiving inspection
+// 入荷検品を作成
+func (s *TracingStorage) CreateInspection(ctx context.Context, inspection *inventory.Inspection) error {
+	ctx, span := s.startSpan(ctx, "CreateInspection", attrInspectionID.String(inspection.ID), attrItemID.String(inspection.ItemID), attrLocationID.String(inspection.LocationID))
+	err := s.next.CreateInspection(ctx, inspection)
+	endSpan(span, err)
+	return err
+}
+
+// GetInspection retrieves a receiving inspection by ID
+// IDで入荷検品を取得
+func (s *TracingStorage) GetInspection(ctx context.Context, inspectionID string) (*inventory.Inspection, error) {
+	ctx, span := s.startSpan(ctx, "GetInspection", attrInspectionID.String(inspectionID))
+	inspection, err := s.next.GetInspection(ctx, inspectionID)
+	endSpan(span, err)
+	return inspection, err
+}
+
+// ListInspections lists receiving inspections matching a filter
+// 条件に一致する入荷検品を取得
+func (s *TracingStorage) ListInspections(ctx context.Context, filter inventory.InspectionFilter) ([]inventory.Inspection, error) {
+	ctx, span := s.startSpan(ctx, "ListInspections", attrItemID.String(filter.ItemID), attrLocationID.String(filter.LocationID))
+	inspections, err := s.next.ListInspections(ctx, filter)
+	endSpanWithRows(span, len(inspections), err)
+	return inspections, err
+}
+
+// UpdateInspection records the disposition of a receiving inspection in status from
+// 状態がfromの入荷検品に合否の判定を記録
+func (s *TracingStorage) UpdateInspection(ctx context.Context, inspection *inventory.Inspection, from inventory.InspectionStatus) error {
+	ctx, span := s.startSpan(ctx, "UpdateInspection", attrInspectionID.String(inspection.ID))
+	err := s.next.UpdateInspection(ctx, inspection, from)
+	endSpan(span, err)
+	return err
+}
+
+// SaveBatchOperation creates or overwrites the record of a batch operation
+// バッチ操作の記録を作成または上書き
+func (s *TracingStorage) SaveBatchOperation(ctx context.Context, batch *inventory.BatchOperation) error {
+	ctx, span := s.startSpan(ctx, "SaveBatchOperation", attrBatchID.String(batch.ID))
+	err := s.next.SaveBatchOperation(ctx, batch)
+	endSpan(span, err)
+	return err
+}
+
+// GetBatchOperation retrieves a batch operation by ID
+// IDでバッチ操作を取得
+func (s *TracingStorage) GetBatchOperation(ctx context.Context, batchID string) (*inventory.BatchOperation, error) {
+	ctx, span := s.startSpan(ctx, "GetBatchOperation", attrBatchID.String(batchID))
+	batch, err := s.next.GetBatchOperation(ctx, batchID)
+	endSpan(span, err)
+	return batch, err
+}
+
+// GetInventorySummary aggregates the whole inventory
+// 在庫全体を集計
+func (s *TracingStorage) GetInventorySummary(ctx context.Context, lowStockThreshold int64) (*inventory.InventorySummary, error) {
+	ctx, span := s.startSpan(ctx, "GetInventorySummary")
+	summary, err := s.next.GetInventorySummary(ctx, lowStockThreshold)
+	endSpan(span, err)
+	return summary, err
+}
+
+// Ping checks the underlying storage health
+// 下位ストレージの健全性を確認
+func (s *TracingStorage) Ping(ctx context.Context) error {
+	ctx, span := s.startSpan(ctx, "Ping")
+	err := s.next.Ping(ctx)
+	endSpan(span, err)
+	return err
+}
+
+// Close closes the underlying storage
+// 下位ストレージを閉じる
+func (s *TracingStorage) Close() error {
+	return s.next.Close()
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+)
+
+// TestTracingStorage はストレージ操作のスパンの記録のテスト
+func TestTracingStorage(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	store := NewTracingStorage(newTestMemoryStorage(t), provider)
+
+	// 親スパンを引き継ぐ
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "handler")
+	require.NoError(t, store.CreateStock(ctx, &inventory.Stock{ItemID: "TEST-ITEM", LocationID: "LOC-A", Quantity: 5, Available: 5, UpdatedAt: time.Now()}))
+	_, err := store.GetStock(ctx, "TEST-ITEM", "LOC-B")
+	assert.ErrorIs(t, err, inventory.ErrStockNotFound)
+	_, err = store.ListStockByLocation(ctx, "LOC-A")
+	require.NoError(t, err)
+	require.NoError(t, store.WithinTx(ctx, func(tx inventory.Storage) error {
+		_, err := tx.GetStock(ctx, "TEST-ITEM", "LOC-A")
+		return err
+	}))
+	parent.End()
+
+	spans := make(map[string][]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = append(spans[span.Name()], span)
+	}
+
+	create := spans["storage.CreateStock"]
+	require.Len(t, create, 1)
+	assert.Equal(t, trace.SpanKindClient, create[0].SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), create[0].Parent().SpanID())
+	assert.Contains(t, create[0].Attributes(), attribute.String("db.operation", "CreateStock"))
+	assert.Contains(t, create[0].Attributes(), attribute.String("inventory.item_id", "TEST-ITEM"))
+	assert.Contains(t, create[0].Attributes(), attribute.String("inventory.location_id", "LOC-A"))
+	assert.Equal(t, codes.Unset, create[0].Status().Code)
+
+	// 失敗した呼び出しはエラーを記録する
+	get := spans["storage.GetStock"]
+	require.Len(t, get, 2)
+	assert.Equal(t, codes.Error, get[0].Status().Code)
+	require.Len(t, get[0].Events(), 1)
+	assert.Equal(t, "exception", get[0].Events()[0].Name)
+
+	// 一覧系は返却件数を記録する
+	list := spans["storage.ListStockByLocation"]
+	require.Len(t, list, 1)
+	assert.Contains(t, list[0].Attributes(), attribute.Int("inventory.rows_returned", 1))
+
+	// トランザクション内の呼び出しもトレースする
+	tx := spans["storage.WithinTx"]
+	require.Len(t, tx, 1)
+	assert.Equal(t, codes.Unset, get[1].Status().Code)
+	assert.Equal(t, parent.SpanContext().SpanID(), tx[0].Parent().SpanID())
+}
@@ -0,0 +1,139 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var _ SupplierManager = (*Manager)(nil)
+
+// CreateSupplier validates and creates a new supplier
+// 仕入先を検証して作成
+//
+// 作成日時・更新日時が未設定の場合は現在時刻を設定します。重複するIDの場合は ErrDuplicateSupplier を返します。
+func (m *Manager) CreateSupplier(ctx context.Context, supplier *Supplier) error {
+	if err := ValidateSupplier(supplier); err != nil {
+		return err
+	}
+	stampCreated(&supplier.CreatedAt, &supplier.UpdatedAt)
+	if err := m.storage.CreateSupplier(ctx, supplier); err != nil {
+		return err
+	}
+
+	m.log(ctx).Info("仕入先作成完了", zap.String("supplier_id", supplier.ID))
+	return nil
+}
+
+// GetSupplier gets a supplier by ID
+// IDで仕入先を取得
+func (m *Manager) GetSupplier(ctx context.Context, supplierID string) (*Supplier, error) {
+	if err := ValidateSupplierID(supplierID); err != nil {
+		return nil, err
+	}
+	return m.storage.GetSupplier(ctx, supplierID)
+}
+
+// UpdateSupplier validates and updates an existing supplier
+// 既存の仕入先を検証して更新
+//
+// 更新日時は現在時刻に更新し、作成日時は変更しません。
+func (m *Manager) UpdateSupplier(ctx context.Context, supplier *Supplier) error {
+	if err := ValidateSupplier(supplier); err != nil {
+		return err
+	}
+	existing, err := m.storage.GetSupplier(ctx, supplier.ID)
+	if err != nil {
+		return err
+	}
+	supplier.CreatedAt = existing.CreatedAt
+	supplier.UpdatedAt = time.Now().Truncate(time.Microsecond)
+	return m.storage.UpdateSupplier(ctx, supplier)
+}
+
+// DeleteSupplier deletes a supplier and the purchasing terms of its items
+// 仕入先を仕入条件とともに削除
+func (m *Manager) DeleteSupplier(ctx context.Context, supplierID string) error {
+	if err := ValidateSupplierID(supplierID); err != nil {
+		return err
+	}
+	if err := m.storage.DeleteSupplier(ctx, supplierID); err != nil {
+		return err
+	}
+
+	m.log(ctx).Info("仕入先削除完了", zap.String("supplier_id", supplierID))
+	return nil
+}
+
+// ListSuppliers lists suppliers ordered by ID with pagination
+// ページネーション付きで仕入先一覧をID順に取得
+func (m *Manager) ListSuppliers(ctx context.Context, offset, limit int) ([]Supplier, error) {
+	if err := validateOffsetLimit(offset, limit); err != nil {
+		return nil, err
+	}
+	return m.storage.ListSuppliers(ctx, offset, limit)
+}
+
+// SetSupplierItem creates or replaces the purchasing terms of an item from a supplier
+// 仕入先の商品の仕入条件を作成または置換
+//
+// 仕入先と商品が存在する必要があります。IsPreferred を指定した場合は、同じ商品の他の仕入先の優先指定を解除します。
+func (m *Manager) SetSupplierItem(ctx context.Context, supplierItem *SupplierItem) error {
+	if err := ValidateSupplierItem(supplierItem); err != nil {
+		return err
+	}
+	if _, err := m.storage.GetSupplier(ctx, supplierItem.SupplierID); err != nil {
+		return err
+	}
+	if _, err := m.storage.GetItem(ctx, supplierItem.ItemID); err != nil {
+		return err
+	}
+	supplierItem.UpdatedAt = time.Now().Truncate(time.Microsecond)
+	return m.storage.SetSupplierItem(ctx, supplierItem)
+}
+
+// GetSupplierItem gets the purchasing terms of an item from a supplier
+// 仕入先の商品の仕入条件を取得
+func (m *Manager) GetSupplierItem(ctx context.Context, supplierID, itemID string) (*SupplierItem, error) {
+	if err := ValidateSupplierID(supplierID); err != nil {
+		return nil, err
+	}
+	if err := ValidateItemID(itemID); err != nil {
+		return nil, err
+	}
+	return m.storage.GetSupplierItem(ctx, supplierID, itemID)
+}
+
+// DeleteSupplierItem deletes the purchasing terms of an item from a supplier
+// 仕入先の商品の仕入条件を削除
+func (m *Manager) DeleteSupplierItem(ctx context.Context, supplierID, itemID string) error {
+	if err := ValidateSupplierID(supplierID); err != nil {
+		return err
+	}
+	if err := ValidateItemID(itemID); err != nil {
+		return err
+	}
+	return m.storage.DeleteSupplierItem(ctx, supplierID, itemID)
+}
+
+// ListSupplierItems lists the purchasing terms matching a filter, ordered by supplier and item ID
+// 条件に一致する仕入条件を仕入先ID・商品ID順に取得
+//
+// 仕入先を指定した場合は仕入先の取扱商品、商品を指定した場合は商品の仕入先の一覧になります。
+func (m *Manager) ListSupplierItems(ctx context.Context, filter SupplierItemFilter) ([]SupplierItem, error) {
+	if err := validateOffsetLimit(filter.Offset, filter.Limit); err != nil {
+		return nil, err
+	}
+	if filter.SupplierID != "" {
+		if _, err := m.storage.GetSupplier(ctx, filter.SupplierID); err != nil {
+			return nil, err
+		}
+	}
+	if filter.ItemID != "" {
+		if _, err := m.storage.GetItem(ctx, filter.ItemID); err != nil {
+			return nil, err
+		}
+	}
+	return m.storage.ListSupplierItems(ctx, filter)
+}
@@ -0,0 +1,70 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation name used for manager spans
+// マネージャーのスパンで使用する計装名
+const tracerName = "github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+
+// Span attribute keys - スパン属性キー
+var (
+	attrOperation     = attribute.Key("inventory.operation")
+	attrItemID        = attribute.Key("inventory.item_id")
+	attrLocationID    = attribute.Key("inventory.location_id")
+	attrToLocation    = attribute.Key("inventory.to_location_id")
+	attrQuantity      = attribute.Key("inventory.quantity")
+	attrReference     = attribute.Key("inventory.reference")
+	attrOperations    = attribute.Key("inventory.batch_operations")
+	attrReservationID = attribute.Key("inventory.reservation_id")
+	attrBackorderID   = attribute.Key("inventory.backorder_id")
+	attrStocktakeID   = attribute.Key("inventory.stocktake_id")
+	attrAdjustmentID  = attribute.Key("inventory.adjustment_id")
+	attrTransactionID = attribute.Key("inventory.transaction_id")
+	attrInspectionID  = attribute.Key("inventory.inspection_id")
+	attrPurchaseOrder = attribute.Key("inventory.purchase_order_id")
+	attrSalesOrderID  = attribute.Key("inventory.sales_order_id")
+	attrReturnID      = attribute.Key("inventory.return_id")
+)
+
+// startSpan starts a child span of the span in ctx
+// contextのスパンの子スパンを開始
+//
+// TracerProvider はグローバルの設定（otel.SetTracerProvider）を使用します。未設定の場合は記録しません。
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan records the error status and ends the span
+// エラー状況を記録してスパンを終了
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// startOperation starts the span of a stock operation and returns the function that ends it
+// 在庫操作のスパンを開始し、終了する関数を返す
+//
+// 終了時にはスパンにエラーを記録し、設定された通知先（Config.Observer）に結果を報告します。
+// 使用例: ctx, finish := m.startOperation(ctx, "add"); defer finish(&err)
+func (m *Manager) startOperation(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, func(err *error)) {
+	start := time.Now()
+	attrs = append(attrs, attrOperation.String(operation))
+	ctx, span := startSpan(ctx, "inventory."+operation, attrs...)
+	return ctx, func(err *error) {
+		endSpan(span, *err)
+		if m.config.Observer != nil {
+			m.config.Observer.ObserveOperation(operation, time.Since(start), *err)
+		}
+	}
+}
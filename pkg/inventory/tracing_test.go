@@ -0,0 +1,84 @@
+package inventory
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// spanRecorder records the spans of every test in this package
+var spanRecorder = tracetest.NewSpanRecorder()
+
+// spanCtx は在庫操作のスパン内でストレージが呼び出されていることを検証するマッチャー
+var spanCtx = mock.MatchedBy(func(ctx context.Context) bool { return trace.SpanContextFromContext(ctx).IsValid() })
+
+func TestMain(m *testing.M) {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)))
+	os.Exit(m.Run())
+}
+
+func TestManager_AddSpans(t *testing.T) {
+	mockStorage := new(MockStorage)
+	manager := NewManager(mockStorage, nil, zap.NewNop(), nil)
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "POST /api/v1/inventory/add")
+
+	mockStorage.On("GetItem", spanCtx, "TEST-ITEM").Return(&Item{ID: "TEST-ITEM"}, nil)
+	mockStorage.On("GetLocation", spanCtx, "TEST-LOC").Return(&Location{ID: "TEST-LOC"}, nil)
+	mockStorage.On("GetStock", primaryReadCtx, "TEST-ITEM", "TEST-LOC").Return(nil, ErrStockNotFound)
+	mockStorage.On("CreateStock", spanCtx, mock.AnythingOfType("*inventory.Stock")).Return(nil)
+	mockStorage.On("CreateTransaction", spanCtx, mock.AnythingOfType("*inventory.Transaction")).Return(nil)
+	mockStorage.On("GetReorderPoint", spanCtx, "TEST-ITEM", "TEST-LOC").Return(nil, ErrReorderPointNotFound)
+	mockStorage.On("GetAlertRulesForStock", spanCtx, "TEST-ITEM", "TEST-LOC").Return([]AlertRule{}, nil)
+
+	require.NoError(t, manager.Add(ctx, "TEST-ITEM", "TEST-LOC", 10, "REF-001"))
+	parent.End()
+
+	// 操作のスパンはリクエストのスパンの子、検証のスパンは操作のスパンの子になる
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range spanRecorder.Ended() {
+		if span.SpanContext().TraceID() == parent.SpanContext().TraceID() {
+			spans[span.Name()] = span
+		}
+	}
+	require.Contains(t, spans, "inventory.add")
+	require.Contains(t, spans, "inventory.validate")
+	assert.Equal(t, parent.SpanContext().SpanID(), spans["inventory.add"].Parent().SpanID())
+	assert.Equal(t, spans["inventory.add"].SpanContext().SpanID(), spans["inventory.validate"].Parent().SpanID())
+	assert.Contains(t, spans["inventory.add"].Attributes(), attrItemID.String("TEST-ITEM"))
+}
+
+func TestManager_OperationObserver(t *testing.T) {
+	mockStorage := new(MockStorage)
+	observer := &recordingObserver{}
+	manager := NewManager(mockStorage, nil, zap.NewNop(), &Config{Observer: observer})
+
+	err := manager.Add(context.Background(), "TEST-ITEM", "TEST-LOC", 0, "REF-001")
+	require.Error(t, err)
+
+	// 失敗した操作もエラーとともに報告し、失敗のスパンを記録する
+	require.Len(t, observer.operations, 1)
+	assert.Equal(t, "add", observer.operations[0])
+	assert.Equal(t, err, observer.errs[0])
+}
+
+// recordingObserver はテスト用の OperationObserver
+type recordingObserver struct {
+	operations []string
+	errs       []error
+}
+
+func (o *recordingObserver) ObserveOperation(operation string, _ time.Duration, err error) {
+	o.operations = append(o.operations, operation)
+	o.errs = append(o.errs, err)
+}
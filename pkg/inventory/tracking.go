@@ -134,7 +134,7 @@ func (tm *TrackingManager) TrackInventoryMovement(ctx context.Context, txType Tr
 		LotNumber:    lotNumber,
 		CreatedAt:    time.Now(),
 		CreatedBy:    tm.getUserFromContext(ctx),
-		Metadata:     make(map[string]string),
+		Metadata:     transactionMetadata(ctx, make(map[string]string)),
 	}
 
 	// 追加のメタデータを設定
@@ -212,6 +212,7 @@ func (tm *TrackingManager) CreateExpiryAlert(ctx context.Context, lotID string,
 		Message:    fmt.Sprintf("ロット %s が %d 日後に期限切れになります", lot.Number, daysUntilExpiry),
 		IsActive:   true,
 		CreatedAt:  time.Now(),
+		Severity:   AlertSeverityWarning,
 	}
 
 	if err := tm.storage.CreateAlert(ctx, alert); err != nil {
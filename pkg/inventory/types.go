@@ -16,10 +16,506 @@ type Item struct {
 	Description string    `json:"description" db:"description"` // 商品説明
 	Category    string    `json:"category" db:"category"`       // カテゴリ
 	UnitCost    float64   `json:"unit_cost" db:"unit_cost"`     // 単価
+	BaseUoM     string    `json:"base_uom" db:"base_uom"`       // 基本単位（在庫数量の単位、空の場合は each）
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`   // 作成日時
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`   // 更新日時
 }
 
+// Built-in units of measure
+// 組み込みの単位
+const (
+	UoMEach     = "each"  // 個（既定の基本単位）
+	UoMBox      = "box"   // 箱
+	UoMKilogram = "kg"    // キログラム
+	UoMLiter    = "liter" // リットル
+)
+
+// MaxUoMDecimals is the largest number of decimal places a unit of measure can have
+// 単位に指定できる小数点以下の桁数の上限
+const MaxUoMDecimals = 6
+
+// UnitOfMeasure is a unit quantities are counted in
+// 数量を数える単位を表現
+//
+// 数量は 10^-Decimals 単位の整数で保持します。例えば Decimals が3の kg を基本単位とする商品の
+// 在庫数量 1500 は 1.5kg を表します。
+type UnitOfMeasure struct {
+	Code      string    `json:"code" db:"code"`             // 単位コード（each・box・kg・liter など）
+	Name      string    `json:"name" db:"name"`             // 単位名
+	Decimals  int       `json:"decimals" db:"decimals"`     // 小数点以下の桁数（0〜6）
+	CreatedAt time.Time `json:"created_at" db:"created_at"` // 作成日時
+}
+
+// UnitConversion is the number of base units of an item in one unit of another unit of measure
+// 商品の別の単位1単位あたりの基本単位の数量（換算係数）を表現
+type UnitConversion struct {
+	ItemID    string    `json:"item_id" db:"item_id"`       // 商品ID
+	UoM       string    `json:"uom" db:"uom"`               // 換算元の単位
+	Factor    float64   `json:"factor" db:"factor"`         // 1単位あたりの基本単位の数量（例: 1箱 = 12個 なら 12）
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"` // 更新日時
+}
+
+// DefaultUnitsOfMeasure are the units of measure every storage starts with
+// ストレージに最初から登録されている組み込みの単位（PostgreSQLはマイグレーションで登録）
+var DefaultUnitsOfMeasure = []UnitOfMeasure{
+	{Code: UoMEach, Name: "個", Decimals: 0},
+	{Code: UoMBox, Name: "箱", Decimals: 0},
+	{Code: UoMKilogram, Name: "キログラム", Decimals: 3},
+	{Code: UoMLiter, Name: "リットル", Decimals: 3},
+}
+
+// MaxBOMComponents is the largest number of components a bill of materials can have
+// 部品表に指定できる構成品の数の上限
+const MaxBOMComponents = 100
+
+// BillOfMaterials lists the components one unit of a kit is assembled from
+// キット1単位を組み立てる構成品を表現（部品表）
+type BillOfMaterials struct {
+	KitItemID  string         `json:"kit_item_id" db:"kit_item_id"` // キットの商品ID
+	Components []BOMComponent `json:"components"`                   // 構成品（商品IDの昇順）
+	UpdatedAt  time.Time      `json:"updated_at" db:"updated_at"`   // 更新日時
+}
+
+// BOMComponent is the quantity of a component in one unit of a kit
+// キット1単位あたりの構成品の数量を表現
+type BOMComponent struct {
+	ItemID   string `json:"item_id" db:"component_item_id"` // 構成品の商品ID
+	Quantity int64  `json:"quantity" db:"quantity"`         // キット1単位あたりの数量（構成品の基本単位）
+}
+
+// AssemblyType defines whether an assembly builds or breaks down kits
+// キットの組立・分解の区別を定義
+type AssemblyType string
+
+const (
+	AssemblyTypeAssemble    AssemblyType = "assemble"    // 組立（構成品を消費してキットを作成）
+	AssemblyTypeDisassemble AssemblyType = "disassemble" // 分解（キットを消費して構成品に戻す）
+)
+
+// Assembly records a kit assembly or disassembly and the stock movements it made
+// キットの組立・分解と、その在庫移動を記録
+//
+// 構成品とキットの在庫の増減は、組立IDを metadata.assembly_id に持つ assembly（TransactionTypeAssembly）の
+// トランザクションとして差分で記録します。Components は組立時点の部品表から計算した構成品ごとの合計数量です。
+type Assembly struct {
+	ID           string         `json:"id" db:"id"`                   // 組立ID
+	Type         AssemblyType   `json:"type" db:"type"`               // 組立・分解
+	KitItemID    string         `json:"kit_item_id" db:"kit_item_id"` // キットの商品ID
+	LocationID   string         `json:"location_id" db:"location_id"` // ロケーションID
+	Quantity     int64          `json:"quantity" db:"quantity"`       // キットの数量
+	Reference    string         `json:"reference" db:"reference"`     // 参照番号
+	Components   []BOMComponent `json:"components"`                   // 消費・作成した構成品の合計数量
+	Transactions []Transaction  `json:"transactions,omitempty"`       // 記録したトランザクション（取得時のみ）
+	CreatedAt    time.Time      `json:"created_at" db:"created_at"`   // 作成日時
+	CreatedBy    string         `json:"created_by" db:"created_by"`   // 作成者
+}
+
+// AssemblyFilter specifies the conditions for listing assemblies
+// 組立・分解の一覧の検索条件
+type AssemblyFilter struct {
+	KitItemID  string // キットの商品ID（空の場合は全て）
+	LocationID string // ロケーションID（空の場合は全て）
+	Offset     int    // 取得開始位置
+	Limit      int    // 取得件数
+}
+
+// Supplier represents a vendor that items are purchased from
+// 商品の仕入先を表現
+type Supplier struct {
+	ID          string    `json:"id" db:"id"`                     // 仕入先ID
+	Name        string    `json:"name" db:"name"`                 // 仕入先名
+	ContactName string    `json:"contact_name" db:"contact_name"` // 担当者名
+	Email       string    `json:"email" db:"email"`               // メールアドレス
+	Phone       string    `json:"phone" db:"phone"`               // 電話番号
+	Address     string    `json:"address" db:"address"`           // 住所
+	IsActive    bool      `json:"is_active" db:"is_active"`       // アクティブ状態
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`     // 作成日時
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`     // 更新日時
+}
+
+// SupplierItem holds the purchasing terms of an item from a supplier
+// 仕入先ごとの商品の仕入条件を表現
+type SupplierItem struct {
+	SupplierID       string    `json:"supplier_id" db:"supplier_id"`               // 仕入先ID
+	ItemID           string    `json:"item_id" db:"item_id"`                       // 商品ID
+	SupplierSKU      string    `json:"supplier_sku" db:"supplier_sku"`             // 仕入先の品番
+	UnitCost         float64   `json:"unit_cost" db:"unit_cost"`                   // 仕入単価
+	LeadTimeDays     int       `json:"lead_time_days" db:"lead_time_days"`         // 発注から入荷までの日数
+	MinOrderQuantity int64     `json:"min_order_quantity" db:"min_order_quantity"` // 最小発注数量（MOQ、0の場合は制限なし）
+	IsPreferred      bool      `json:"is_preferred" db:"is_preferred"`             // 優先仕入先（商品ごとに1件）
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`                 // 更新日時
+}
+
+// SupplierItemFilter specifies the conditions for listing supplier-item links
+// 仕入先の商品の一覧の検索条件
+type SupplierItemFilter struct {
+	SupplierID string // 仕入先ID（空の場合は全て）
+	ItemID     string // 商品ID（空の場合は全て）
+	Offset     int    // 取得開始位置
+	Limit      int    // 取得件数
+}
+
+// PurchaseOrderStatus defines the status of a purchase order
+// 発注の状態を定義
+type PurchaseOrderStatus string
+
+const (
+	PurchaseOrderStatusDraft             PurchaseOrderStatus = "draft"              // 作成済み（承認待ち）
+	PurchaseOrderStatusApproved          PurchaseOrderStatus = "approved"           // 承認済み（送付待ち）
+	PurchaseOrderStatusSent              PurchaseOrderStatus = "sent"               // 仕入先に送付済み（入荷待ち）
+	PurchaseOrderStatusPartiallyReceived PurchaseOrderStatus = "partially_received" // 一部入荷
+	PurchaseOrderStatusReceived          PurchaseOrderStatus = "received"           // 全数入荷済み
+	PurchaseOrderStatusCancelled         PurchaseOrderStatus = "cancelled"          // 取消済み
+)
+
+// MaxPurchaseOrderLines is the largest number of lines a purchase order can have
+// 発注に指定できる行の数の上限
+const MaxPurchaseOrderLines = 500
+
+// PurchaseOrder is an order of items from a supplier, received into a location
+// 仕入先への商品の発注（入荷先のロケーションに入荷する）
+//
+// draft（作成済み）→ approved（承認済み）→ sent（送付済み）の順に進み、入荷を記録すると
+// partially_received（一部入荷）、全ての行の発注数量を入荷すると received（全数入荷済み）になります。
+// 行（Lines）は GetPurchaseOrder でのみ取得し、一覧では省略します。
+type PurchaseOrder struct {
+	ID         string              `json:"id" db:"id"`                             // 発注ID
+	SupplierID string              `json:"supplier_id" db:"supplier_id"`           // 仕入先ID
+	LocationID string              `json:"location_id" db:"location_id"`           // 入荷先のロケーションID
+	Reference  string              `json:"reference" db:"reference"`               // 発注書番号（入荷のトランザクションに記録）
+	Status     PurchaseOrderStatus `json:"status" db:"status"`                     // 状態
+	ExpectedAt *time.Time          `json:"expected_at" db:"expected_at"`           // 入荷予定日
+	Note       string              `json:"note,omitempty" db:"note"`               // メモ
+	CreatedAt  time.Time           `json:"created_at" db:"created_at"`             // 作成日時
+	CreatedBy  string              `json:"created_by" db:"created_by"`             // 作成者
+	ApprovedAt *time.Time          `json:"approved_at" db:"approved_at"`           // 承認日時
+	ApprovedBy string              `json:"approved_by,omitempty" db:"approved_by"` // 承認したユーザー
+	SentAt     *time.Time          `json:"sent_at" db:"sent_at"`                   // 送付日時
+	SentBy     string              `json:"sent_by,omitempty" db:"sent_by"`         // 送付したユーザー
+	ClosedAt   *time.Time          `json:"closed_at" db:"closed_at"`               // 全数入荷・取消の日時
+	ClosedBy   string              `json:"closed_by,omitempty" db:"closed_by"`     // 全数入荷を記録・取消したユーザー
+	Lines      []PurchaseOrderLine `json:"lines,omitempty" db:"-"`                 // 行（商品IDの昇順）
+}
+
+// PurchaseOrderLine is the ordered and received quantity of one item in a purchase order
+// 発注の1商品の発注数量と入荷数量
+type PurchaseOrderLine struct {
+	PurchaseOrderID  string  `json:"purchase_order_id" db:"purchase_order_id"` // 発注ID
+	ItemID           string  `json:"item_id" db:"item_id"`                     // 商品ID
+	Quantity         int64   `json:"quantity" db:"quantity"`                   // 発注数量（基本単位）
+	ReceivedQuantity int64   `json:"received_quantity" db:"received_quantity"` // 入荷済み数量
+	UnitCost         float64 `json:"unit_cost" db:"unit_cost"`                 // 発注単価
+}
+
+// Outstanding returns the quantity of the line that has not been received yet
+// 行の未入荷の数量（発注残）を返す
+func (l *PurchaseOrderLine) Outstanding() int64 {
+	return l.Quantity - l.ReceivedQuantity
+}
+
+// PurchaseOrderFilter narrows the purchase orders returned by ListPurchaseOrders
+// ListPurchaseOrders で取得する発注の絞り込み条件
+type PurchaseOrderFilter struct {
+	SupplierID string              // 仕入先ID（空の場合は絞り込まない）
+	LocationID string              // 入荷先のロケーションID（空の場合は絞り込まない）
+	Status     PurchaseOrderStatus // 状態（空の場合は絞り込まない）
+	Offset     int                 // 取得開始位置
+	Limit      int                 // 取得件数の上限
+}
+
+// GoodsReceipt records stock received against a purchase order
+// 発注に対する入荷の記録
+//
+// 行ごとに入荷先のロケーションへの入庫（inbound）のトランザクションを記録し、トランザクションの
+// metadata.purchase_order_id・metadata.goods_receipt_id に発注IDと入荷IDを記録します。
+type GoodsReceipt struct {
+	ID              string             `json:"id" db:"id"`                               // 入荷ID
+	PurchaseOrderID string             `json:"purchase_order_id" db:"purchase_order_id"` // 発注ID
+	LocationID      string             `json:"location_id" db:"location_id"`             // 入荷先のロケーションID
+	Reference       string             `json:"reference" db:"reference"`                 // 参照番号（納品書番号など）
+	Lines           []GoodsReceiptLine `json:"lines"`                                    // 入荷した行
+	ReceivedAt      time.Time          `json:"received_at" db:"received_at"`             // 入荷日時
+	ReceivedBy      string             `json:"received_by" db:"received_by"`             // 入荷を記録したユーザー
+}
+
+// GoodsReceiptLine is the quantity of one item received with its lot and cost
+// 入荷した1商品の数量とロット・単価
+type GoodsReceiptLine struct {
+	ItemID        string     `json:"item_id"`                  // 商品ID
+	Quantity      int64      `json:"quantity"`                 // 入荷数量（基本単位）
+	UnitCost      float64    `json:"unit_cost"`                // 仕入単価（0の場合は発注単価）
+	LotNumber     string     `json:"lot_number,omitempty"`     // ロット番号（空の場合はロットを記録しない）
+	ExpiryDate    *time.Time `json:"expiry_date,omitempty"`    // ロットの有効期限
+	TransactionID string     `json:"transaction_id,omitempty"` // 記録した入庫のトランザクションID
+}
+
+// SalesOrderStatus defines the status of a sales order
+// 受注の状態を定義
+type SalesOrderStatus string
+
+const (
+	SalesOrderStatusOpen      SalesOrderStatus = "open"      // 受付済み（未引当・一部引当）
+	SalesOrderStatusAllocated SalesOrderStatus = "allocated" // 全ての行を引当済み（出荷待ち）
+	SalesOrderStatusShipped   SalesOrderStatus = "shipped"   // 出荷確定済み（欠品出荷を含む）
+	SalesOrderStatusCancelled SalesOrderStatus = "cancelled" // 取消済み
+)
+
+// MaxSalesOrderLines is the largest number of lines a sales order can have
+// 受注に指定できる行の数の上限
+const MaxSalesOrderLines = 500
+
+// SalesOrder is an order from a customer, shipped from a location
+// 出荷元のロケーションから出荷する顧客からの受注
+//
+// AllocateSalesOrder で行ごとに在庫を予約（参照番号は受注ID）して引き当て、ShipSalesOrder で
+// 予約した在庫を出庫して出荷を確定します。出荷しなかった数量は欠品（ShortQuantity）として記録し、予約を解除します。
+// 行（Lines）は GetSalesOrder でのみ取得し、一覧では省略します。
+type SalesOrder struct {
+	ID         string           `json:"id" db:"id"`                         // 受注ID（引当の予約の参照番号）
+	Reference  string           `json:"reference" db:"reference"`           // 注文番号（出荷のトランザクションに記録）
+	LocationID string           `json:"location_id" db:"location_id"`       // 出荷元のロケーションID
+	Customer   string           `json:"customer,omitempty" db:"customer"`   // 顧客名・顧客コード
+	Status     SalesOrderStatus `json:"status" db:"status"`                 // 状態
+	Note       string           `json:"note,omitempty" db:"note"`           // メモ
+	Version    int64            `json:"version" db:"version"`               // 楽観的ロック用のバージョン
+	CreatedAt  time.Time        `json:"created_at" db:"created_at"`         // 受付日時
+	CreatedBy  string           `json:"created_by" db:"created_by"`         // 受付したユーザー
+	ClosedAt   *time.Time       `json:"closed_at" db:"closed_at"`           // 出荷確定・取消の日時
+	ClosedBy   string           `json:"closed_by,omitempty" db:"closed_by"` // 出荷を確定・取消したユーザー
+	Channel    string           `json:"channel,omitempty" db:"channel"`     // 販売チャネル（引当の予約のチャネル）
+	Lines      []SalesOrderLine `json:"lines,omitempty" db:"-"`             // 行（商品IDの昇順）
+}
+
+// SalesOrderLine is the ordered, allocated and shipped quantity of one item in a sales order
+// 受注の1商品の受注数量・引当数量・出荷数量
+type SalesOrderLine struct {
+	SalesOrderID      string `json:"sales_order_id" db:"sales_order_id"`         // 受注ID
+	ItemID            string `json:"item_id" db:"item_id"`                       // 商品ID
+	Quantity          int64  `json:"quantity" db:"quantity"`                     // 受注数量（基本単位）
+	AllocatedQuantity int64  `json:"allocated_quantity" db:"allocated_quantity"` // 引当（予約）数量
+	ShippedQuantity   int64  `json:"shipped_quantity" db:"shipped_quantity"`     // 出荷数量
+	ShortQuantity     int64  `json:"short_quantity" db:"short_quantity"`         // 欠品数量（出荷確定時に出荷しなかった数量）
+}
+
+// Unallocated returns the quantity of the line that has not been allocated yet
+// 行の未引当の数量を返す
+func (l *SalesOrderLine) Unallocated() int64 {
+	return l.Quantity - l.AllocatedQuantity
+}
+
+// SalesOrderFilter narrows the sales orders returned by ListSalesOrders
+// ListSalesOrders で取得する受注の絞り込み条件
+type SalesOrderFilter struct {
+	LocationID string           // 出荷元のロケーションID（空の場合は絞り込まない）
+	Reference  string           // 注文番号（空の場合は絞り込まない）
+	Status     SalesOrderStatus // 状態（空の場合は絞り込まない）
+	Offset     int              // 取得開始位置
+	Limit      int              // 取得件数の上限
+}
+
+// ShipmentLine is the quantity of one item shipped when confirming the shipment of a sales order
+// 受注の出荷確定で出荷する1商品の数量
+type ShipmentLine struct {
+	ItemID   string `json:"item_id"`  // 商品ID
+	Quantity int64  `json:"quantity"` // 出荷数量（0の場合は全数欠品）
+}
+
+// SalesOrderStockStatus is the stock status of a sales order: what is allocated and what could be allocated now
+// 受注の在庫状況（引当済みの数量と、現在の利用可能数で引き当てられる数量）
+type SalesOrderStockStatus struct {
+	SalesOrderID   string                      `json:"sales_order_id"`  // 受注ID
+	Status         SalesOrderStatus            `json:"status"`          // 受注の状態
+	LocationID     string                      `json:"location_id"`     // 出荷元のロケーションID
+	FullyAllocated bool                        `json:"fully_allocated"` // 全ての行を引当済み
+	Fulfillable    bool                        `json:"fulfillable"`     // 未引当の数量を現在の利用可能数で全て引き当てられる
+	Lines          []SalesOrderLineStockStatus `json:"lines"`           // 行ごとの在庫状況（商品IDの昇順）
+}
+
+// SalesOrderLineStockStatus is the stock status of one line of a sales order
+// 受注の1行の在庫状況
+type SalesOrderLineStockStatus struct {
+	ItemID      string `json:"item_id"`     // 商品ID
+	Quantity    int64  `json:"quantity"`    // 受注数量
+	Allocated   int64  `json:"allocated"`   // 引当数量
+	Shipped     int64  `json:"shipped"`     // 出荷数量
+	Short       int64  `json:"short"`       // 欠品数量
+	Unallocated int64  `json:"unallocated"` // 未引当の数量
+	Available   int64  `json:"available"`   // 出荷元の現在の利用可能数
+}
+
+// ReturnStatus defines the status of a return (RMA)
+// 返品（RMA）の状態を定義
+type ReturnStatus string
+
+const (
+	ReturnStatusAuthorized ReturnStatus = "authorized" // 返品受付済み（返品の到着待ち・一部到着）
+	ReturnStatusReceived   ReturnStatus = "received"   // 全数到着（検品・処分待ちを含む）
+	ReturnStatusCompleted  ReturnStatus = "completed"  // 全数到着し、全て処分済み
+	ReturnStatusCancelled  ReturnStatus = "cancelled"  // 取消済み（未到着の数量は受け付けない）
+)
+
+// ReturnDispositionType defines what is done with returned units after inspection
+// 検品した返品の処分方法を定義
+type ReturnDispositionType string
+
+const (
+	ReturnDispositionRestock   ReturnDispositionType = "restock"   // 再入庫（返品先のロケーションの在庫に戻す）
+	ReturnDispositionRefurbish ReturnDispositionType = "refurbish" // 再整備（再整備品の商品・ロケーションの在庫に入庫）
+	ReturnDispositionScrap     ReturnDispositionType = "scrap"     // 廃棄（在庫に戻さない）
+)
+
+// MaxReturnLines is the largest number of lines a return can have
+// 返品に指定できる行の数の上限
+const MaxReturnLines = 500
+
+// ReturnAuthorization is a return merchandise authorization (RMA) for units sent back by a customer
+// 顧客から返送される商品の返品（RMA）
+//
+// ReceiveReturn で到着した数量を検品待ちとして記録し（在庫には含めない）、DispositionReturn で
+// 再入庫・再整備・廃棄に処分します。SalesOrderID を指定した場合は出荷確定済みの受注の出荷数量まで返品できます。
+// 行（Lines）は GetReturn でのみ取得し、一覧では省略します。
+type ReturnAuthorization struct {
+	ID           string       `json:"id" db:"id"`                                   // 返品ID
+	Reference    string       `json:"reference" db:"reference"`                     // RMA番号（処分のトランザクションに記録）
+	SalesOrderID string       `json:"sales_order_id,omitempty" db:"sales_order_id"` // 返品元の受注ID
+	Customer     string       `json:"customer,omitempty" db:"customer"`             // 顧客名・顧客コード
+	LocationID   string       `json:"location_id" db:"location_id"`                 // 返品を受け入れるロケーションID
+	Reason       string       `json:"reason,omitempty" db:"reason"`                 // 返品理由
+	Status       ReturnStatus `json:"status" db:"status"`                           // 状態
+	Note         string       `json:"note,omitempty" db:"note"`                     // メモ
+	Version      int64        `json:"version" db:"version"`                         // 楽観的ロック用のバージョン
+	CreatedAt    time.Time    `json:"created_at" db:"created_at"`                   // 受付日時
+	CreatedBy    string       `json:"created_by" db:"created_by"`                   // 受付したユーザー
+	ClosedAt     *time.Time   `json:"closed_at" db:"closed_at"`                     // 完了・取消の日時
+	ClosedBy     string       `json:"closed_by,omitempty" db:"closed_by"`           // 完了・取消したユーザー
+	Lines        []ReturnLine `json:"lines,omitempty" db:"-"`                       // 行（商品IDの昇順）
+}
+
+// ReturnLine is the authorized, received and dispositioned quantity of one item in a return
+// 返品の1商品の返品許可数量・到着数量・処分数量
+type ReturnLine struct {
+	ReturnID            string `json:"return_id" db:"return_id"`                       // 返品ID
+	ItemID              string `json:"item_id" db:"item_id"`                           // 商品ID
+	Quantity            int64  `json:"quantity" db:"quantity"`                         // 返品許可数量
+	ReceivedQuantity    int64  `json:"received_quantity" db:"received_quantity"`       // 到着数量
+	RestockedQuantity   int64  `json:"restocked_quantity" db:"restocked_quantity"`     // 再入庫した数量
+	RefurbishedQuantity int64  `json:"refurbished_quantity" db:"refurbished_quantity"` // 再整備した数量
+	ScrappedQuantity    int64  `json:"scrapped_quantity" db:"scrapped_quantity"`       // 廃棄した数量
+}
+
+// Pending returns the received quantity of the line that has not been dispositioned yet
+// 行の到着済みで処分していない（検品待ちの）数量を返す
+func (l *ReturnLine) Pending() int64 {
+	return l.ReceivedQuantity - l.RestockedQuantity - l.RefurbishedQuantity - l.ScrappedQuantity
+}
+
+// ReturnFilter narrows the returns returned by ListReturns
+// ListReturns で取得する返品の絞り込み条件
+type ReturnFilter struct {
+	SalesOrderID string       // 受注ID（空の場合は絞り込まない）
+	LocationID   string       // ロケーションID（空の場合は絞り込まない）
+	Status       ReturnStatus // 状態（空の場合は絞り込まない）
+	Offset       int          // 取得開始位置
+	Limit        int          // 取得件数の上限
+}
+
+// ReturnReceiptLine is the quantity of one item that arrived for a return
+// 返品で到着した1商品の数量
+type ReturnReceiptLine struct {
+	ItemID   string `json:"item_id"`  // 商品ID
+	Quantity int64  `json:"quantity"` // 到着数量
+}
+
+// ReturnDisposition records what was done with inspected returned units
+// 検品した返品の処分の記録
+//
+// restock は返品の商品を返品先のロケーションに、refurbish は TargetItemID（省略時は返品の商品）を
+// LocationID（省略時は返品先のロケーション）に入庫（inbound）します。scrap は在庫を変更せず理由コードを記録します。
+type ReturnDisposition struct {
+	ID            string                `json:"id" db:"id"`                                   // 処分ID
+	ReturnID      string                `json:"return_id" db:"return_id"`                     // 返品ID
+	ItemID        string                `json:"item_id" db:"item_id"`                         // 返品の商品ID
+	Quantity      int64                 `json:"quantity" db:"quantity"`                       // 処分した数量
+	Type          ReturnDispositionType `json:"disposition" db:"disposition"`                 // 処分方法
+	TargetItemID  string                `json:"target_item_id,omitempty" db:"target_item_id"` // 入庫した商品ID（restock・refurbish）
+	LocationID    string                `json:"location_id,omitempty" db:"location_id"`       // 入庫したロケーションID（restock・refurbish）
+	Reason        AdjustmentReason      `json:"reason_code,omitempty" db:"reason_code"`       // 廃棄の理由コード（scrap）
+	Note          string                `json:"note,omitempty" db:"note"`                     // メモ
+	TransactionID string                `json:"transaction_id,omitempty" db:"transaction_id"` // 入庫したトランザクションのID
+	CreatedAt     time.Time             `json:"created_at" db:"created_at"`                   // 処分日時
+	CreatedBy     string                `json:"created_by" db:"created_by"`                   // 処分したユーザー
+}
+
+// ConsignmentStock is the quantity of supplier-owned consignment stock of an item held at a location
+// ロケーションで預かっている商品の仕入先所有の委託在庫の数量
+//
+// 所有者（仕入先）を指定した在庫追加・削除・移動（WithOwner）で更新します。
+// 在庫（Stock）の数量は自社所有と委託在庫の合計で、委託在庫は在庫評価から除きます。
+type ConsignmentStock struct {
+	OwnerID    string    `json:"owner_id" db:"owner_id"`       // 所有者（仕入先ID）
+	ItemID     string    `json:"item_id" db:"item_id"`         // 商品ID
+	LocationID string    `json:"location_id" db:"location_id"` // ロケーションID
+	Quantity   int64     `json:"quantity" db:"quantity"`       // 数量
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`   // 更新日時
+}
+
+// ConsignmentStockFilter narrows the consignment stocks returned by ListConsignmentStocks
+// ListConsignmentStocks で取得する委託在庫の絞り込み条件
+type ConsignmentStockFilter struct {
+	OwnerID      string // 所有者（仕入先ID、空の場合は絞り込まない）
+	ItemID       string // 商品ID（空の場合は絞り込まない）
+	LocationID   string // ロケーションID（空の場合は絞り込まない）
+	IncludeEmpty bool   // 数量が0の委託在庫も含める
+	Offset       int    // 取得開始位置
+	Limit        int    // 取得件数の上限
+}
+
+// StockOwnership splits the stock of an item at a location into owned and consignment stock
+// 商品のロケーションの在庫を自社所有と委託在庫に分けた内訳
+type StockOwnership struct {
+	ItemID            string             `json:"item_id"`            // 商品ID
+	LocationID        string             `json:"location_id"`        // ロケーションID
+	Quantity          int64              `json:"quantity"`           // 在庫数量（自社所有と委託在庫の合計）
+	OwnedQuantity     int64              `json:"owned_quantity"`     // 自社所有の数量（在庫数量から委託在庫を除いた数量）
+	ConsignedQuantity int64              `json:"consigned_quantity"` // 委託在庫の数量の合計
+	Consignments      []ConsignmentStock `json:"consignments"`       // 所有者ごとの委託在庫（所有者IDの昇順）
+}
+
+// ChannelAllocation earmarks stock of an item at a location for a sales channel
+// 商品のロケーションの在庫を販売チャネル向けに確保する数量
+//
+// 確保した数量はチャネルの予約で優先的に使用し、他のチャネルやチャネルを指定しない予約は使用できません。
+// 確保数量を超えるチャネルの予約は、どのチャネルにも確保されていない共有の在庫から確保します。
+type ChannelAllocation struct {
+	ItemID     string    `json:"item_id" db:"item_id"`         // 商品ID
+	LocationID string    `json:"location_id" db:"location_id"` // ロケーションID
+	Channel    string    `json:"channel" db:"channel"`         // 販売チャネル（web・retail・wholesale など）
+	Quantity   int64     `json:"quantity" db:"quantity"`       // 確保数量
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`   // 更新日時
+	UpdatedBy  string    `json:"updated_by" db:"updated_by"`   // 更新したユーザー
+}
+
+// ChannelAvailability is the availability of the stock of an item at a location for a sales channel
+// 商品のロケーションの在庫の販売チャネルごとの利用可能数
+type ChannelAvailability struct {
+	Channel   string `json:"channel"`   // 販売チャネル
+	Allocated int64  `json:"allocated"` // 確保数量
+	Reserved  int64  `json:"reserved"`  // チャネルの有効な予約の数量
+	Available int64  `json:"available"` // チャネルが予約できる数量（確保数量の残りと共有の利用可能数量の合計）
+}
+
+// StockChannels splits the available stock of an item at a location by sales channel
+// 商品のロケーションの利用可能な在庫を販売チャネルごとに分けた内訳
+type StockChannels struct {
+	ItemID          string                `json:"item_id"`          // 商品ID
+	LocationID      string                `json:"location_id"`      // ロケーションID
+	Quantity        int64                 `json:"quantity"`         // 在庫数量
+	Reserved        int64                 `json:"reserved"`         // 予約数量
+	Available       int64                 `json:"available"`        // 利用可能数量
+	SharedAvailable int64                 `json:"shared_available"` // どのチャネルにも確保されていない利用可能数量（チャネルを指定しない予約が使用できる数量）
+	Channels        []ChannelAvailability `json:"channels"`         // 確保または予約があるチャネル（チャネル名の昇順）
+}
+
 // Location represents a storage location or warehouse
 // 保管場所または倉庫を表現
 type Location struct {
@@ -29,6 +525,9 @@ type Location struct {
 	Address   string    `json:"address" db:"address"`       // 住所
 	Capacity  int64     `json:"capacity" db:"capacity"`     // 最大収容量
 	IsActive  bool      `json:"is_active" db:"is_active"`   // アクティブ状態
+	Priority  int       `json:"priority" db:"priority"`     // 引当の優先順位（小さいほど優先、既定0）
+	Latitude  *float64  `json:"latitude" db:"latitude"`     // 緯度（近さによる引当に使用、任意）
+	Longitude *float64  `json:"longitude" db:"longitude"`   // 経度（近さによる引当に使用、任意）
 	CreatedAt time.Time `json:"created_at" db:"created_at"` // 作成日時
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"` // 更新日時
 }
@@ -46,6 +545,13 @@ type Stock struct {
 	UpdatedBy  string    `json:"updated_by" db:"updated_by"`   // 更新者
 }
 
+// StockKey identifies the stock record of an item at a location
+// 商品とロケーションの組で在庫記録を識別
+type StockKey struct {
+	ItemID     string `json:"item_id"`     // 商品ID
+	LocationID string `json:"location_id"` // ロケーションID
+}
+
 // Transaction represents an inventory movement record
 // 在庫移動記録を表現
 type Transaction struct {
@@ -64,15 +570,35 @@ type Transaction struct {
 	CreatedBy    string            `json:"created_by" db:"created_by"`       // 作成者
 }
 
+// HistoryCursor identifies a position in transaction history for keyset pagination
+// キーセットページネーション用のトランザクション履歴上の位置を表現
+//
+// 履歴は (CreatedAt, ID) の降順で並ぶため、このカーソルより古いレコードが次のページになります。
+type HistoryCursor struct {
+	AfterCreatedAt time.Time `json:"after_created_at"` // このレコードの作成日時
+	AfterID        string    `json:"after_id"`         // このレコードのトランザクションID
+}
+
+// TransactionPage represents a page of transaction history
+// トランザクション履歴の1ページを表現
+type TransactionPage struct {
+	Transactions []Transaction `json:"transactions"`          // トランザクション一覧（新しい順）
+	NextCursor   *HistoryCursor `json:"next_cursor,omitempty"` // 次ページのカーソル（最終ページの場合はnil）
+}
+
 // TransactionType defines the type of inventory movement
 // 在庫移動のタイプを定義
 type TransactionType string
 
 const (
-	TransactionTypeInbound  TransactionType = "inbound"  // 入庫
-	TransactionTypeOutbound TransactionType = "outbound" // 出庫
-	TransactionTypeTransfer TransactionType = "transfer" // 移動
-	TransactionTypeAdjust   TransactionType = "adjust"   // 調整
+	TransactionTypeInbound   TransactionType = "inbound"   // 入庫
+	TransactionTypeOutbound  TransactionType = "outbound"  // 出庫
+	TransactionTypeTransfer  TransactionType = "transfer"  // 移動
+	TransactionTypeAdjust    TransactionType = "adjust"    // 調整
+	TransactionTypeReserve   TransactionType = "reserve"   // 予約（在庫数量は変わらず、利用可能数が減る）
+	TransactionTypeRelease   TransactionType = "release"   // 予約解除・期限切れ（在庫数量は変わらず、利用可能数が増える）
+	TransactionTypeStocktake TransactionType = "stocktake" // 棚卸差異の調整（差分を記録）
+	TransactionTypeAssembly  TransactionType = "assembly"  // キットの組立・分解による構成品・キットの増減（差分を記録）
 )
 
 // Lot represents a batch of items with the same characteristics
@@ -87,19 +613,312 @@ type Lot struct {
 	CreatedAt  time.Time  `json:"created_at" db:"created_at"`   // 作成日時
 }
 
+// LotStock is the quantity of a lot held at a location
+// ロットのロケーションごとの在庫数量を表現
+//
+// ロット番号を指定した在庫追加・削除・移動（WithLotNumber）で更新します。
+type LotStock struct {
+	LotID      string    `json:"lot_id" db:"lot_id"`           // ロットID
+	LotNumber  string    `json:"lot_number" db:"lot_number"`   // ロット番号
+	ItemID     string    `json:"item_id" db:"item_id"`         // 商品ID
+	LocationID string    `json:"location_id" db:"location_id"` // ロケーションID
+	Quantity   int64     `json:"quantity" db:"quantity"`       // 数量
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`   // 更新日時
+}
+
+// LotStockFilter narrows the lot stocks returned by ListLotStocks
+// ListLotStocks で取得するロット在庫の絞り込み条件
+type LotStockFilter struct {
+	ItemID       string // 商品ID（空の場合は絞り込まない）
+	LotID        string // ロットID（空の場合は絞り込まない）
+	LocationID   string // ロケーションID（空の場合は絞り込まない）
+	IncludeEmpty bool   // 数量が0のロット在庫も含める
+	Offset       int    // 取得開始位置
+	Limit        int    // 取得件数の上限
+}
+
+// LotPickingStrategy defines the order in which a remove consumes the lots of an item
+// 出庫（Remove）で商品のロットを消費する順序を定義
+type LotPickingStrategy string
+
+const (
+	LotPickingFEFO LotPickingStrategy = "fefo" // 有効期限の早い順（先入先出より期限を優先、期限のないロットは最後）
+	LotPickingFIFO LotPickingStrategy = "fifo" // 作成日時の古い順（先入先出）
+	LotPickingLIFO LotPickingStrategy = "lifo" // 作成日時の新しい順（後入先出）
+)
+
+// LotPick is the quantity a remove consumed from a lot
+// 出庫で1つのロットから消費した数量
+type LotPick struct {
+	LotID      string     `json:"lot_id"`                // ロットID
+	LotNumber  string     `json:"lot_number"`            // ロット番号
+	Quantity   int64      `json:"quantity"`              // 消費した数量
+	ExpiryDate *time.Time `json:"expiry_date,omitempty"` // ロットの有効期限
+}
+
+// Reservation is a hold of stock for an order or another reference
+// 注文などの参照番号のために確保した在庫を表現
+//
+// 有効な予約の数量の合計は在庫の予約数量（Stock.Reserved）に含まれます。
+// 有効期限を過ぎた予約は ExpireReservations で期限切れとなり、確保した在庫が解放されます。
+type Reservation struct {
+	ID         string            `json:"id" db:"id"`                   // 予約ID
+	ItemID     string            `json:"item_id" db:"item_id"`         // 商品ID
+	LocationID string            `json:"location_id" db:"location_id"` // ロケーションID
+	Quantity   int64             `json:"quantity" db:"quantity"`       // 予約数量
+	Reference  string            `json:"reference" db:"reference"`     // 参照番号（注文番号など）
+	Status     ReservationStatus `json:"status" db:"status"`           // 状態
+	ExpiresAt  *time.Time        `json:"expires_at" db:"expires_at"`   // 有効期限（nilの場合は期限なし）
+	CreatedAt  time.Time         `json:"created_at" db:"created_at"`   // 作成日時
+	CreatedBy  string            `json:"created_by" db:"created_by"`   // 作成者
+	ReleasedAt *time.Time        `json:"released_at" db:"released_at"` // 解除・期限切れの日時
+	Channel    string            `json:"channel" db:"channel"`         // 販売チャネル（空の場合は共有の在庫のみから予約）
+}
+
+// ReservationStatus defines the status of a reservation
+// 予約の状態を定義
+type ReservationStatus string
+
+const (
+	ReservationStatusActive    ReservationStatus = "active"    // 有効（在庫を確保中）
+	ReservationStatusReleased  ReservationStatus = "released"  // 解除済み
+	ReservationStatusExpired   ReservationStatus = "expired"   // 期限切れ
+	ReservationStatusFulfilled ReservationStatus = "fulfilled" // 出庫済み
+)
+
+// ReservationFilter narrows the reservations returned by ListReservations
+// ListReservations で取得する予約の絞り込み条件
+type ReservationFilter struct {
+	ItemID     string            // 商品ID（空の場合は絞り込まない）
+	LocationID string            // ロケーションID（空の場合は絞り込まない）
+	Status     ReservationStatus // 状態（空の場合は絞り込まない）
+	Reference  string            // 参照番号（空の場合は絞り込まない）
+	Channel    string            // 販売チャネル（空の場合は絞り込まない）
+	Offset     int               // 取得開始位置
+	Limit      int               // 取得件数の上限
+}
+
+// Backorder is a request for stock that was not available when it was made
+// 在庫不足のため受け付けた取り寄せ（バックオーダー）を表現
+//
+// 在庫不足で失敗した Remove・Reserve から作成され、Add で同じ商品・ロケーションに在庫が入荷すると
+// 古い順に予約として引き当てられます。引当で作成した予約は FulfillReservation で出庫します。
+type Backorder struct {
+	ID            string          `json:"id" db:"id"`                                   // バックオーダーID
+	ItemID        string          `json:"item_id" db:"item_id"`                         // 商品ID
+	LocationID    string          `json:"location_id" db:"location_id"`                 // ロケーションID
+	Quantity      int64           `json:"quantity" db:"quantity"`                       // 数量
+	Reference     string          `json:"reference" db:"reference"`                     // 参照番号（注文番号など）
+	Status        BackorderStatus `json:"status" db:"status"`                           // 状態
+	ReservationID string          `json:"reservation_id,omitempty" db:"reservation_id"` // 引当で作成した予約ID
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`                   // 作成日時
+	CreatedBy     string          `json:"created_by" db:"created_by"`                   // 作成者
+	ClosedAt      *time.Time      `json:"closed_at" db:"closed_at"`                     // 引当・取消の日時
+}
+
+// BackorderStatus defines the status of a backorder
+// バックオーダーの状態を定義
+type BackorderStatus string
+
+const (
+	BackorderStatusPending   BackorderStatus = "pending"   // 入荷待ち
+	BackorderStatusAllocated BackorderStatus = "allocated" // 引当済み（予約を作成）
+	BackorderStatusCancelled BackorderStatus = "cancelled" // 取消済み
+)
+
+// BackorderFilter narrows the backorders returned by ListBackorders
+// ListBackorders で取得するバックオーダーの絞り込み条件
+type BackorderFilter struct {
+	ItemID     string          // 商品ID（空の場合は絞り込まない）
+	LocationID string          // ロケーションID（空の場合は絞り込まない）
+	Status     BackorderStatus // 状態（空の場合は絞り込まない）
+	Reference  string          // 参照番号（空の場合は絞り込まない）
+	Offset     int             // 取得開始位置
+	Limit      int             // 取得件数の上限
+}
+
+// ReorderPoint holds the replenishment levels of an item at a location
+// ロケーションごとの商品の発注点・最小/最大在庫数
+//
+// 設定した在庫は、Config.LowStockThreshold の代わりに ReorderPointQty を低在庫アラートの閾値に使用します。
+type ReorderPoint struct {
+	ItemID          string    `json:"item_id" db:"item_id"`             // 商品ID
+	LocationID      string    `json:"location_id" db:"location_id"`     // ロケーションID
+	ReorderPointQty int64     `json:"reorder_point" db:"reorder_point"` // 発注点（在庫数量がこの値以下で低在庫アラート）
+	MinQty          int64     `json:"min_qty" db:"min_qty"`             // 最小在庫数（安全在庫）
+	MaxQty          int64     `json:"max_qty" db:"max_qty"`             // 最大在庫数（発注数の目安、0の場合は上限なし）
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`       // 更新日時
+	UpdatedBy       string    `json:"updated_by" db:"updated_by"`       // 更新者
+}
+
+// ReorderQuantity returns the quantity to order to refill a stock up to MaxQty
+// 在庫数量を最大在庫数まで補充する発注数を返す（最大在庫数が未設定、または補充が不要な場合は0）
+func (r *ReorderPoint) ReorderQuantity(quantity int64) int64 {
+	if r.MaxQty <= 0 || quantity >= r.MaxQty {
+		return 0
+	}
+	return r.MaxQty - quantity
+}
+
+// ReorderPointFilter narrows the reorder points returned by ListReorderPoints
+// ListReorderPoints で取得する発注点の絞り込み条件
+type ReorderPointFilter struct {
+	ItemID     string // 商品ID（空の場合は絞り込まない）
+	LocationID string // ロケーションID（空の場合は絞り込まない）
+	Offset     int    // 取得開始位置
+	Limit      int    // 取得件数の上限
+}
+
+// AlertRule is a configurable condition on stock records that raises alerts
+// 在庫記録に対する設定可能なアラートの条件
+//
+// 対象範囲（ItemID・LocationID）の在庫記録で Metric の値が Comparator・Threshold の条件を満たすと
+// アラートを作成します。同じ在庫記録のアラートがアクティブな間、および前回の作成から CooldownSeconds 秒の間は
+// 再作成しません。
+type AlertRule struct {
+	ID              string          `json:"id" db:"id"`                             // ルールID
+	Name            string          `json:"name" db:"name"`                         // ルール名
+	Metric          AlertMetric     `json:"metric" db:"metric"`                     // 判定する在庫の値
+	Comparator      AlertComparator `json:"comparator" db:"comparator"`             // 比較方法
+	Threshold       int64           `json:"threshold" db:"threshold"`               // 閾値
+	ItemID          string          `json:"item_id,omitempty" db:"item_id"`         // 対象の商品ID（空の場合は全商品）
+	LocationID      string          `json:"location_id,omitempty" db:"location_id"` // 対象のロケーションID（空の場合は全ロケーション）
+	Severity        AlertSeverity   `json:"severity" db:"severity"`                 // 重要度
+	CooldownSeconds int64           `json:"cooldown_seconds" db:"cooldown_seconds"` // 同じ在庫記録でアラートを再作成しない秒数
+	Enabled         bool            `json:"enabled" db:"enabled"`                   // 有効なルールのみ評価する
+	CreatedAt       time.Time       `json:"created_at" db:"created_at"`             // 作成日時
+	UpdatedAt       time.Time       `json:"updated_at" db:"updated_at"`             // 更新日時
+	UpdatedBy       string          `json:"updated_by" db:"updated_by"`             // 更新者
+}
+
+// AlertMetric defines the stock value an alert rule compares
+// アラートルールで判定する在庫の値を定義
+type AlertMetric string
+
+const (
+	AlertMetricQuantity  AlertMetric = "quantity"  // 在庫数量
+	AlertMetricAvailable AlertMetric = "available" // 利用可能数
+	AlertMetricReserved  AlertMetric = "reserved"  // 予約済み数量
+)
+
+// AlertComparator defines how an alert rule compares the metric with its threshold
+// アラートルールで値と閾値を比較する方法を定義
+type AlertComparator string
+
+const (
+	AlertComparatorLT  AlertComparator = "lt"  // 閾値未満
+	AlertComparatorLTE AlertComparator = "lte" // 閾値以下
+	AlertComparatorGT  AlertComparator = "gt"  // 閾値超過
+	AlertComparatorGTE AlertComparator = "gte" // 閾値以上
+)
+
+// AlertSeverity defines the severity of an alert
+// アラートの重要度を定義
+type AlertSeverity string
+
+const (
+	AlertSeverityInfo     AlertSeverity = "info"     // 情報
+	AlertSeverityWarning  AlertSeverity = "warning"  // 警告
+	AlertSeverityCritical AlertSeverity = "critical" // 重大
+)
+
+// Covers reports whether a stock record is within the scope of the rule
+// 在庫記録がルールの対象範囲に含まれるか
+func (r *AlertRule) Covers(stock Stock) bool {
+	return (r.ItemID == "" || r.ItemID == stock.ItemID) && (r.LocationID == "" || r.LocationID == stock.LocationID)
+}
+
+// Value returns the metric of the rule for a stock record
+// 在庫記録のルールで判定する値を返す
+func (r *AlertRule) Value(stock Stock) int64 {
+	switch r.Metric {
+	case AlertMetricAvailable:
+		return stock.Available
+	case AlertMetricReserved:
+		return stock.Reserved
+	default:
+		return stock.Quantity
+	}
+}
+
+// Triggered reports whether a stock record meets the condition of the rule
+// 在庫記録がルールの条件を満たすか
+func (r *AlertRule) Triggered(stock Stock) bool {
+	value := r.Value(stock)
+	switch r.Comparator {
+	case AlertComparatorLT:
+		return value < r.Threshold
+	case AlertComparatorLTE:
+		return value <= r.Threshold
+	case AlertComparatorGT:
+		return value > r.Threshold
+	case AlertComparatorGTE:
+		return value >= r.Threshold
+	default:
+		return false
+	}
+}
+
+// AlertType returns the type of the alerts the rule raises (low stock for lower bounds, over stock for upper bounds)
+// ルールが作成するアラートの種別を返す（下限の条件は低在庫、上限の条件は過剰在庫）
+func (r *AlertRule) AlertType() AlertType {
+	if r.Comparator == AlertComparatorGT || r.Comparator == AlertComparatorGTE {
+		return AlertTypeOverStock
+	}
+	return AlertTypeLowStock
+}
+
+// Cooldown returns the period during which the rule does not raise another alert for the same stock record
+// 同じ在庫記録でアラートを再作成しない期間を返す
+func (r *AlertRule) Cooldown() time.Duration {
+	return time.Duration(r.CooldownSeconds) * time.Second
+}
+
+// AlertRuleFilter narrows the alert rules returned by ListAlertRules
+// ListAlertRules で取得するアラートルールの絞り込み条件
+type AlertRuleFilter struct {
+	ItemID      string // 対象の商品ID（空の場合は絞り込まない）
+	LocationID  string // 対象のロケーションID（空の場合は絞り込まない）
+	EnabledOnly bool   // 有効なルールのみ取得する
+	Offset      int    // 取得開始位置
+	Limit       int    // 取得件数の上限（0の場合は全件）
+}
+
 // StockAlert represents low stock or other inventory alerts
 // 低在庫やその他の在庫アラートを表現
 type StockAlert struct {
-	ID         string      `json:"id" db:"id"`                   // アラートID
-	Type       AlertType   `json:"type" db:"type"`               // アラートタイプ
-	ItemID     string      `json:"item_id" db:"item_id"`         // 商品ID
-	LocationID string      `json:"location_id" db:"location_id"` // ロケーションID
-	CurrentQty int64       `json:"current_qty" db:"current_qty"` // 現在数量
-	Threshold  int64       `json:"threshold" db:"threshold"`     // 閾値
-	Message    string      `json:"message" db:"message"`         // メッセージ
-	IsActive   bool        `json:"is_active" db:"is_active"`     // アクティブ状態
-	CreatedAt  time.Time   `json:"created_at" db:"created_at"`   // 作成日時
-	ResolvedAt *time.Time  `json:"resolved_at" db:"resolved_at"` // 解決日時
+	ID              string        `json:"id" db:"id"`                                       // アラートID
+	Type            AlertType     `json:"type" db:"type"`                                   // アラートタイプ
+	ItemID          string        `json:"item_id" db:"item_id"`                             // 商品ID
+	LocationID      string        `json:"location_id" db:"location_id"`                     // ロケーションID
+	CurrentQty      int64         `json:"current_qty" db:"current_qty"`                     // 現在数量
+	Threshold       int64         `json:"threshold" db:"threshold"`                         // 閾値
+	Message         string        `json:"message" db:"message"`                             // メッセージ
+	IsActive        bool          `json:"is_active" db:"is_active"`                         // アクティブ状態
+	CreatedAt       time.Time     `json:"created_at" db:"created_at"`                       // 作成日時
+	ResolvedAt      *time.Time    `json:"resolved_at" db:"resolved_at"`                     // 解決日時
+	RuleID          string        `json:"rule_id,omitempty" db:"rule_id"`                   // 作成したアラートルールのID（組み込みの判定の場合は空）
+	Severity        AlertSeverity `json:"severity" db:"severity"`                           // 重要度
+	AcknowledgedAt  *time.Time    `json:"acknowledged_at" db:"acknowledged_at"`             // 確認日時（未確認の場合はnull）
+	AcknowledgedBy  string        `json:"acknowledged_by,omitempty" db:"acknowledged_by"`   // 確認したユーザー
+	AcknowledgeNote string        `json:"acknowledge_note,omitempty" db:"acknowledge_note"` // 確認時のメモ
+	ResolvedBy      string        `json:"resolved_by,omitempty" db:"resolved_by"`           // 解決したユーザー
+	ResolveNote     string        `json:"resolve_note,omitempty" db:"resolve_note"`         // 解決時のメモ
+}
+
+// AlertFilter narrows the alerts returned by ListAlerts
+// ListAlerts で取得するアラートの絞り込み条件（全ロケーションが対象）
+type AlertFilter struct {
+	Type          AlertType     // アラートタイプ（空の場合は絞り込まない）
+	Severity      AlertSeverity // 重要度（空の場合は絞り込まない）
+	Active        *bool         // true はアクティブ、false は解決済みのアラートのみ（nilの場合は絞り込まない）
+	Acknowledged  *bool         // true は確認済み、false は未確認のアラートのみ（nilの場合は絞り込まない）
+	ItemID        string        // 商品ID（空の場合は絞り込まない）
+	LocationID    string        // ロケーションID（空の場合は絞り込まない）
+	CreatedAfter  time.Time     // この日時以降に作成したアラートのみ（ゼロ値の場合は絞り込まない）
+	CreatedBefore time.Time     // この日時より前に作成したアラートのみ（ゼロ値の場合は絞り込まない）
+	Offset        int           // 取得開始位置
+	Limit         int           // 取得件数の上限
 }
 
 // AlertType defines types of inventory alerts
@@ -114,6 +933,25 @@ const (
 	AlertTypeDiscrepancy AlertType = "discrepancy"  // 棚卸差異
 )
 
+// InventorySummary is an aggregate overview of the whole inventory
+// 在庫全体の集計（ダッシュボード用）
+type InventorySummary struct {
+	TotalSKUs         int64              `json:"total_skus"`            // 商品数
+	TotalUnits        int64              `json:"total_units"`           // 総在庫数
+	TotalValue        float64            `json:"total_value"`           // 総評価額（自社所有の在庫数×単価。委託在庫を除く）
+	ActiveAlerts      int64              `json:"active_alerts"`         // アクティブなアラート数
+	LowStockThreshold int64              `json:"low_stock_threshold"`   // 低在庫閾値（発注点を設定していない在庫に適用）
+	LowStock          []LocationLowStock `json:"low_stock_by_location"` // ロケーション別の低在庫数
+	GeneratedAt       time.Time          `json:"generated_at"`          // 集計日時
+}
+
+// LocationLowStock is the number of low stock items at a location
+// ロケーションごとの低在庫の商品数
+type LocationLowStock struct {
+	LocationID string `json:"location_id"` // ロケーションID
+	Items      int64  `json:"items"`       // 数量が閾値（発注点）以下の商品数
+}
+
 // BatchOperation represents a batch inventory operation
 // バッチ在庫操作を表現
 type BatchOperation struct {
@@ -123,10 +961,34 @@ type BatchOperation struct {
 	SuccessCount int                     `json:"success_count"` // 成功数
 	FailureCount int                     `json:"failure_count"` // 失敗数
 	Errors      []BatchOperationError    `json:"errors"`       // エラーリスト
+	CancelledCount int                   `json:"cancelled_count"` // 取り消した（実行しなかった）操作数
+	Progress    int                      `json:"progress"`     // 終了した操作の割合（0〜100）
+	Results     []BatchOperationResult   `json:"results"`      // 操作ごとの結果（Operations と同じ順序）
+	Atomic      bool                     `json:"atomic"`       // ExecuteBatchAtomicで実行された場合はtrue
 	CreatedAt   time.Time                `json:"created_at"`   // 作成日時
 	CompletedAt *time.Time               `json:"completed_at"` // 完了日時
 }
 
+// BatchOperationResult is the outcome of a single operation of a batch
+// バッチ内の単一の操作の結果
+type BatchOperationResult struct {
+	OperationIndex int                   `json:"operation_index"` // 操作インデックス
+	Status         OperationResultStatus `json:"status"`          // 結果
+	Error          string                `json:"error,omitempty"` // エラーメッセージ（失敗した場合）
+}
+
+// OperationResultStatus defines the outcome of an operation in a batch
+// バッチ内の操作の結果を定義
+type OperationResultStatus string
+
+const (
+	OperationResultPending    OperationResultStatus = "pending"     // 未実行
+	OperationResultSucceeded  OperationResultStatus = "succeeded"   // 成功
+	OperationResultFailed     OperationResultStatus = "failed"      // 失敗
+	OperationResultRolledBack OperationResultStatus = "rolled_back" // アトミックバッチの失敗により適用されなかった
+	OperationResultCancelled  OperationResultStatus = "cancelled"   // バッチの取り消しにより実行しなかった
+)
+
 // InventoryOperation represents a single inventory operation
 // 単一の在庫操作を表現
 type InventoryOperation struct {
@@ -154,16 +1016,287 @@ const (
 type BatchStatus string
 
 const (
-	BatchStatusPending   BatchStatus = "pending"   // 処理中
+	BatchStatusPending   BatchStatus = "pending"   // 処理中（非同期実行の場合は実行待ち）
+	BatchStatusRunning   BatchStatus = "running"   // 非同期実行中
 	BatchStatusCompleted BatchStatus = "completed" // 完了
 	BatchStatusFailed    BatchStatus = "failed"    // 失敗
+	BatchStatusCancelled BatchStatus = "cancelled" // 取り消し（一部の操作は実行済みの場合がある）
 )
 
 // BatchOperationError represents an error in batch processing
 // バッチ処理でのエラーを表現
 type BatchOperationError struct {
-	OperationIndex int    `json:"operation_index"` // 操作インデックス
-	Error          string `json:"error"`           // エラーメッセージ
+	OperationIndex int           `json:"operation_index"`       // 操作インデックス
+	Type           OperationType `json:"type,omitempty"`        // 操作タイプ
+	ItemID         string        `json:"item_id,omitempty"`     // 商品ID
+	LocationID     string        `json:"location_id,omitempty"` // ロケーションID
+	Error          string        `json:"error"`                 // エラーメッセージ
+}
+
+// BulkImportData holds the records loaded by Storage.BulkImport
+// Storage.BulkImportで一括取り込みするレコードを保持
+//
+// 外部キーの依存順（商品 → 在庫 → トランザクション）で取り込まれます。
+type BulkImportData struct {
+	Items        []Item        `json:"items"`        // 商品
+	Stocks       []Stock       `json:"stocks"`       // 在庫
+	Transactions []Transaction `json:"transactions"` // トランザクション
+}
+
+// Len returns the total number of records to import
+// 取り込むレコードの総数を返す
+func (d *BulkImportData) Len() int {
+	return len(d.Items) + len(d.Stocks) + len(d.Transactions)
+}
+
+// StockSnapshot is a point-in-time copy of every stock record
+// 全ての在庫記録のある時点の複製（月末在庫や在庫推移の集計用）
+//
+// 複製した在庫記録（StockSnapshotLine）は GetStockSnapshotLines で取得します。
+type StockSnapshot struct {
+	ID            string    `json:"id" db:"id"`                         // スナップショットID
+	TakenAt       time.Time `json:"taken_at" db:"taken_at"`             // 取得日時
+	TakenBy       string    `json:"taken_by" db:"taken_by"`             // 取得者
+	StockCount    int       `json:"stock_count" db:"stock_count"`       // 在庫記録数
+	TotalQuantity int64     `json:"total_quantity" db:"total_quantity"` // 総在庫数量
+	TotalReserved int64     `json:"total_reserved" db:"total_reserved"` // 総予約済み数量
+}
+
+// StockSnapshotLine is the copy of one stock record in a snapshot
+// スナップショットに複製した1件の在庫記録
+type StockSnapshotLine struct {
+	SnapshotID string `json:"snapshot_id" db:"snapshot_id"` // スナップショットID
+	ItemID     string `json:"item_id" db:"item_id"`         // 商品ID
+	LocationID string `json:"location_id" db:"location_id"` // ロケーションID
+	Quantity   int64  `json:"quantity" db:"quantity"`       // 在庫数量
+	Reserved   int64  `json:"reserved" db:"reserved"`       // 予約済み数量
+}
+
+// Stocktake is a count sheet of the stock records at a location
+// ロケーションの在庫記録の棚卸（実地棚卸の集計表）
+//
+// 作成時の在庫記録（ABCClass を指定した場合はそのクラスの商品のみ）を行として持ち、
+// open（集計中）→ submitted（差異確定）→ applied（調整済み）の順に進みます。
+// 行（Lines）は GetStocktake でのみ取得し、一覧では省略します。
+type Stocktake struct {
+	ID          string          `json:"id" db:"id"`                               // 棚卸ID
+	LocationID  string          `json:"location_id" db:"location_id"`             // ロケーションID
+	ABCClass    string          `json:"abc_class,omitempty" db:"abc_class"`       // 対象のABCクラス（空の場合は全商品）
+	Reference   string          `json:"reference" db:"reference"`                 // 参照番号（調整トランザクションに記録）
+	Status      StocktakeStatus `json:"status" db:"status"`                       // 状態
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`               // 作成日時
+	CreatedBy   string          `json:"created_by" db:"created_by"`               // 作成者
+	SubmittedAt *time.Time      `json:"submitted_at" db:"submitted_at"`           // 差異確定日時
+	SubmittedBy string          `json:"submitted_by,omitempty" db:"submitted_by"` // 差異を確定したユーザー
+	ClosedAt    *time.Time      `json:"closed_at" db:"closed_at"`                 // 調整・取消の日時
+	ClosedBy    string          `json:"closed_by,omitempty" db:"closed_by"`       // 調整を承認・取消したユーザー
+	Lines       []StocktakeLine `json:"lines,omitempty" db:"-"`                   // 行（商品IDの昇順）
+}
+
+// StocktakeStatus defines the status of a stocktake
+// 棚卸の状態を定義
+type StocktakeStatus string
+
+const (
+	StocktakeStatusOpen      StocktakeStatus = "open"      // 集計中（実数を記録できる）
+	StocktakeStatusSubmitted StocktakeStatus = "submitted" // 差異確定（承認待ち）
+	StocktakeStatusApplied   StocktakeStatus = "applied"   // 承認した差異を在庫に反映済み
+	StocktakeStatusCancelled StocktakeStatus = "cancelled" // 取消済み
+)
+
+// StocktakeLine is the count of one item in a stocktake
+// 棚卸の1商品の集計
+//
+// ExpectedQty は実数を記録した時点の在庫数量（記録前は作成時の在庫数量）で、Variance は CountedQty - ExpectedQty です。
+// 記録後の入出庫は差異に影響しないよう、調整では在庫数量に Variance を加算します。
+type StocktakeLine struct {
+	StocktakeID string     `json:"stocktake_id" db:"stocktake_id"`       // 棚卸ID
+	ItemID      string     `json:"item_id" db:"item_id"`                 // 商品ID
+	ExpectedQty int64      `json:"expected_qty" db:"expected_qty"`       // 帳簿在庫数量
+	CountedQty  *int64     `json:"counted_qty" db:"counted_qty"`         // 実数（未記録の場合はnull）
+	Variance    int64      `json:"variance" db:"variance"`               // 差異（実数 - 帳簿在庫数量）
+	CountedAt   *time.Time `json:"counted_at" db:"counted_at"`           // 実数の記録日時
+	CountedBy   string     `json:"counted_by,omitempty" db:"counted_by"` // 実数を記録したユーザー
+	Approved    bool       `json:"approved" db:"approved"`               // 調整を承認して在庫に反映したか
+}
+
+// Counted reports whether the counted quantity of the line was recorded
+// 実数が記録されているかを判定
+func (l *StocktakeLine) Counted() bool {
+	return l.CountedQty != nil
+}
+
+// StocktakeCount is a counted quantity recorded on a stocktake
+// 棚卸に記録する実数
+type StocktakeCount struct {
+	ItemID     string `json:"item_id"`     // 商品ID（集計表にない商品は行を追加する）
+	CountedQty int64  `json:"counted_qty"` // 実数
+}
+
+// StocktakeFilter narrows the stocktakes returned by ListStocktakes
+// ListStocktakes で取得する棚卸の絞り込み条件
+type StocktakeFilter struct {
+	LocationID string          // ロケーションID（空の場合は絞り込まない）
+	Status     StocktakeStatus // 状態（空の場合は絞り込まない）
+	Offset     int             // 取得開始位置
+	Limit      int             // 取得件数の上限
+}
+
+// AdjustmentReason is the reason code of a stock adjustment
+// 在庫調整の理由コード
+type AdjustmentReason string
+
+const (
+	AdjustmentReasonDamage          AdjustmentReason = "damage"           // 破損
+	AdjustmentReasonShrinkage       AdjustmentReason = "shrinkage"        // 紛失・盗難などによる減耗
+	AdjustmentReasonCountCorrection AdjustmentReason = "count_correction" // 数え直しによる数量の訂正
+	AdjustmentReasonExpired         AdjustmentReason = "expired"          // 期限切れによる廃棄
+	AdjustmentReasonFound           AdjustmentReason = "found"            // 所在不明だった在庫の発見
+	AdjustmentReasonOther           AdjustmentReason = "other"            // その他（メモが必要）
+)
+
+// Adjustment is a stock adjustment with its reason code and approval status
+// 理由コードと承認状況を持つ在庫調整
+//
+// 調整額（Value）が Config.AdjustmentApprovalThreshold を超える調整は pending（承認待ち）として記録し、
+// 承認されるまで在庫に反映しません。
+type Adjustment struct {
+	ID            string           `json:"id" db:"id"`                                   // 在庫調整ID
+	ItemID        string           `json:"item_id" db:"item_id"`                         // 商品ID
+	LocationID    string           `json:"location_id" db:"location_id"`                 // ロケーションID
+	OldQuantity   int64            `json:"old_quantity" db:"old_quantity"`               // 申請時の在庫数量
+	NewQuantity   int64            `json:"new_quantity" db:"new_quantity"`               // 調整後の在庫数量
+	Delta         int64            `json:"delta" db:"delta"`                             // 調整数量（NewQuantity - OldQuantity）
+	Value         float64          `json:"value" db:"value"`                             // 調整額（調整数量の絶対値 × 商品の単価）
+	Reason        AdjustmentReason `json:"reason_code" db:"reason_code"`                 // 理由コード
+	Note          string           `json:"note,omitempty" db:"note"`                     // メモ
+	Reference     string           `json:"reference" db:"reference"`                     // 参照番号
+	Status        AdjustmentStatus `json:"status" db:"status"`                           // 状態
+	TransactionID string           `json:"transaction_id,omitempty" db:"transaction_id"` // 在庫に反映した調整トランザクションのID
+	RequestedAt   time.Time        `json:"requested_at" db:"requested_at"`               // 申請日時
+	RequestedBy   string           `json:"requested_by" db:"requested_by"`               // 申請者
+	DecidedAt     *time.Time       `json:"decided_at" db:"decided_at"`                   // 承認・却下の日時
+	DecidedBy     string           `json:"decided_by,omitempty" db:"decided_by"`         // 承認・却下したユーザー
+	DecisionNote  string           `json:"decision_note,omitempty" db:"decision_note"`   // 承認・却下時のメモ
+}
+
+// AdjustmentStatus defines the status of a stock adjustment
+// 在庫調整の状態を定義
+type AdjustmentStatus string
+
+const (
+	AdjustmentStatusPending  AdjustmentStatus = "pending"  // 承認待ち
+	AdjustmentStatusApplied  AdjustmentStatus = "applied"  // 在庫に反映済み
+	AdjustmentStatusRejected AdjustmentStatus = "rejected" // 却下
+)
+
+// AdjustmentFilter narrows the adjustments returned by ListAdjustments
+// ListAdjustments で取得する在庫調整の絞り込み条件
+type AdjustmentFilter struct {
+	ItemID     string           // 商品ID（空の場合は絞り込まない）
+	LocationID string           // ロケーションID（空の場合は絞り込まない）
+	Status     AdjustmentStatus // 状態（空の場合は絞り込まない）
+	Reason     AdjustmentReason // 理由コード（空の場合は絞り込まない）
+	Offset     int              // 取得開始位置
+	Limit      int              // 取得件数の上限
+}
+
+// InspectionStatus defines the status of a receiving inspection
+// 入荷検品の状態を定義
+type InspectionStatus string
+
+const (
+	InspectionStatusQuarantined InspectionStatus = "quarantined" // 検品待ち（隔離中、在庫に含めない）
+	InspectionStatusPassed      InspectionStatus = "passed"      // 合格（在庫に入庫済み）
+	InspectionStatusFailed      InspectionStatus = "failed"      // 不合格（廃棄）
+)
+
+// Inspection is received stock held in quarantine until it passes or fails QC inspection
+// 品質検査（QC）の合否が決まるまで隔離する入荷
+//
+// 検品待ちの数量は在庫（Stock）に含めず、利用可能数・引当の対象になりません。合格した場合は
+// 入庫（inbound）のトランザクションで在庫に加算し、不合格の場合は理由コードを記録して廃棄します。
+type Inspection struct {
+	ID             string           `json:"id" db:"id"`                                     // 検品ID
+	ItemID         string           `json:"item_id" db:"item_id"`                           // 商品ID
+	LocationID     string           `json:"location_id" db:"location_id"`                   // 入荷したロケーションID
+	Quantity       int64            `json:"quantity" db:"quantity"`                         // 入荷数量
+	Reference      string           `json:"reference" db:"reference"`                       // 参照番号（入荷伝票番号など）
+	Note           string           `json:"note,omitempty" db:"note"`                       // 入荷時のメモ
+	Status         InspectionStatus `json:"status" db:"status"`                             // 状態
+	Reason         AdjustmentReason `json:"reason_code,omitempty" db:"reason_code"`         // 不合格（廃棄）の理由コード
+	TransactionID  string           `json:"transaction_id,omitempty" db:"transaction_id"`   // 合格時に在庫に入庫したトランザクションのID
+	ReceivedAt     time.Time        `json:"received_at" db:"received_at"`                   // 入荷日時
+	ReceivedBy     string           `json:"received_by" db:"received_by"`                   // 入荷を記録したユーザー
+	InspectedAt    *time.Time       `json:"inspected_at" db:"inspected_at"`                 // 合否の判定日時
+	InspectedBy    string           `json:"inspected_by,omitempty" db:"inspected_by"`       // 合否を判定したユーザー
+	InspectionNote string           `json:"inspection_note,omitempty" db:"inspection_note"` // 合否の判定時のメモ
+}
+
+// InspectionFilter narrows the inspections returned by ListInspections
+// ListInspections で取得する入荷検品の絞り込み条件
+type InspectionFilter struct {
+	ItemID     string           // 商品ID（空の場合は絞り込まない）
+	LocationID string           // ロケーションID（空の場合は絞り込まない）
+	Status     InspectionStatus // 状態（空の場合は絞り込まない）
+	Offset     int              // 取得開始位置
+	Limit      int              // 取得件数の上限
+}
+
+// AllocationStrategy defines how the allocation engine ranks the locations to source from
+// 引当で在庫を確保するロケーションの順位付けの方式を定義
+type AllocationStrategy string
+
+const (
+	AllocationStrategyPriority   AllocationStrategy = "priority"    // ロケーションの優先順位（Location.Priority）の昇順
+	AllocationStrategyProximity  AllocationStrategy = "proximity"   // 配送先からの距離の昇順（位置のないロケーションは最後）
+	AllocationStrategyStockLevel AllocationStrategy = "stock_level" // 利用可能数の降順（確保するロケーション数を最小化）
+)
+
+// GeoPoint is a point given by latitude and longitude
+// 緯度・経度で表す地点
+type GeoPoint struct {
+	Latitude  float64 `json:"latitude"`  // 緯度
+	Longitude float64 `json:"longitude"` // 経度
+}
+
+// AllocationRequest asks the allocation engine to source a quantity of an item across locations
+// 商品の数量を複数のロケーションから確保する引当の要求
+type AllocationRequest struct {
+	ItemID               string             // 商品ID
+	Quantity             int64              // 引当数量
+	Reference            string             // 参照番号（注文番号など、予約に記録）
+	Strategy             AllocationStrategy // 順位付けの方式（空の場合は priority）
+	LocationIDs          []string           // 候補のロケーションID（空の場合は全てのアクティブなロケーション）
+	Destination          *GeoPoint          // 配送先（proximity では必須、他の方式では同順位の並び替えに使用）
+	MaxLocations         int                // 確保するロケーション数の上限（0の場合は無制限）
+	PreferSingleLocation bool               // 全数を確保できるロケーションがある場合は分割しない
+	AllowPartial         bool               // 不足する場合に確保できた数量のみ引き当てる（false の場合は ErrInsufficientStock）
+	ExpiresAt            *time.Time         // 予約の有効期限（nilの場合は ReservationTTL）
+	Channel              string             // 販売チャネル（空の場合は共有の利用可能数量のみから確保）
+}
+
+// AllocationLine is the quantity an allocation sources from a location
+// 引当で1つのロケーションから確保する数量
+type AllocationLine struct {
+	LocationID    string   `json:"location_id"`              // ロケーションID
+	Quantity      int64    `json:"quantity"`                 // 確保する数量
+	Available     int64    `json:"available"`                // 引当前の利用可能数
+	Priority      int      `json:"priority"`                 // ロケーションの優先順位
+	DistanceKm    *float64 `json:"distance_km,omitempty"`    // 配送先からの距離（km、配送先・位置がない場合は省略）
+	ReservationID string   `json:"reservation_id,omitempty"` // 作成した予約のID（計画のみの場合は省略）
+}
+
+// Allocation is the result of sourcing a quantity of an item across locations
+// 複数のロケーションからの引当の結果
+type Allocation struct {
+	ItemID    string             `json:"item_id"`   // 商品ID
+	Reference string             `json:"reference"` // 参照番号
+	Strategy  AllocationStrategy `json:"strategy"`  // 順位付けの方式
+	Requested int64              `json:"requested"` // 要求した数量
+	Allocated int64              `json:"allocated"` // 確保した数量
+	Shortfall int64              `json:"shortfall"` // 不足数量（AllowPartial の場合のみ0以外）
+	Lines     []AllocationLine   `json:"lines"`     // ロケーションごとの確保数量（順位の順）
 }
 
 // NewTransactionID generates a new transaction ID
@@ -178,6 +1311,90 @@ func NewBatchID() string {
 	return uuid.New().String()
 }
 
+// NewReservationID generates a new reservation ID
+// 新しい予約IDを生成
+func NewReservationID() string {
+	return uuid.New().String()
+}
+
+// NewBackorderID generates a new backorder ID
+// 新しいバックオーダーIDを生成
+func NewBackorderID() string {
+	return uuid.New().String()
+}
+
+// NewAlertRuleID generates a new alert rule ID
+// 新しいアラートルールIDを生成
+func NewAlertRuleID() string {
+	return uuid.New().String()
+}
+
+// NewSnapshotID generates a new stock snapshot ID
+// 新しい在庫スナップショットIDを生成
+func NewSnapshotID() string {
+	return uuid.New().String()
+}
+
+// NewAdjustmentID generates a new adjustment ID
+// 新しい在庫調整IDを生成
+func NewAdjustmentID() string {
+	return uuid.New().String()
+}
+
+// NewInspectionID generates a new inspection ID
+// 新しい検品IDを生成
+func NewInspectionID() string {
+	return uuid.New().String()
+}
+
+// NewStocktakeID generates a new stocktake ID
+// 新しい棚卸IDを生成
+func NewStocktakeID() string {
+	return uuid.New().String()
+}
+
+// NewLotID generates a new lot ID
+// 新しいロットIDを生成
+func NewLotID() string {
+	return uuid.New().String()
+}
+
+// NewAssemblyID generates a new kit assembly ID
+// 新しい組立IDを生成
+func NewAssemblyID() string {
+	return uuid.New().String()
+}
+
+// NewPurchaseOrderID generates a new purchase order ID
+// 新しい発注IDを生成
+func NewPurchaseOrderID() string {
+	return uuid.New().String()
+}
+
+// NewGoodsReceiptID generates a new goods receipt ID
+// 新しい入荷IDを生成
+func NewGoodsReceiptID() string {
+	return uuid.New().String()
+}
+
+// NewSalesOrderID generates a new sales order ID
+// 新しい受注IDを生成
+func NewSalesOrderID() string {
+	return uuid.New().String()
+}
+
+// NewReturnID generates a new return ID
+// 新しい返品IDを生成
+func NewReturnID() string {
+	return uuid.New().String()
+}
+
+// NewReturnDispositionID generates a new return disposition ID
+// 新しい返品の処分IDを生成
+func NewReturnDispositionID() string {
+	return uuid.New().String()
+}
+
 // Calculate available quantity (total - reserved)
 // 利用可能数量を計算（総数量 - 予約済み数量）
 func (s *Stock) CalculateAvailable() {
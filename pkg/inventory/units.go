@@ -0,0 +1,301 @@
+package inventory
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var _ UnitManager = (*Manager)(nil)
+
+// uomKey is the context key that names the unit the quantity of an add, remove or transfer is given in
+// 在庫追加・削除・移動の数量の単位を指定するコンテキストキー
+type uomKey struct{}
+
+// convertedQuantityKey is the context key of the quantity as the caller specified it before conversion
+// 基本単位に換算する前の呼び出し元が指定した数量を保持するコンテキストキー
+type convertedQuantityKey struct{}
+
+// convertedQuantity is the unit and quantity the caller specified
+// 呼び出し元が指定した単位と数量
+type convertedQuantity struct {
+	uom      string
+	quantity int64
+}
+
+// MetadataUoM is the transaction metadata key of the unit the caller specified the quantity in
+// 呼び出し元が数量を指定した単位を記録するトランザクションのメタデータキー
+const MetadataUoM = "uom"
+
+// MetadataUoMQuantity is the transaction metadata key of the quantity before conversion to the base unit
+// 基本単位に換算する前の数量（指定した単位の 10^-Decimals 単位の整数）を記録するトランザクションのメタデータキー
+const MetadataUoMQuantity = "uom_quantity"
+
+// WithUoM returns a context that makes Add, Remove and Transfer take the quantity in the given unit
+// Add・Remove・Transfer が数量を指定した単位で受け取るコンテキストを返す
+//
+// 数量は単位の 10^-Decimals 単位の整数で指定します（ParseQuantity で小数の文字列から変換できます）。
+// 数量は ConvertQuantity で商品の基本単位に換算してから在庫に反映し、指定した単位と数量を
+// トランザクションのメタデータ MetadataUoM・MetadataUoMQuantity に記録します。
+func WithUoM(ctx context.Context, uom string) context.Context {
+	return context.WithValue(ctx, uomKey{}, uom)
+}
+
+// requestedUoM returns the unit requested by the context, or an empty string
+// コンテキストが指定する単位を返す（指定がない場合は空文字列）
+func requestedUoM(ctx context.Context) string {
+	uom, _ := ctx.Value(uomKey{}).(string)
+	return uom
+}
+
+// CreateUnit validates and creates a unit of measure
+// 単位を検証して作成
+func (m *Manager) CreateUnit(ctx context.Context, unit *UnitOfMeasure) error {
+	if err := ValidateUnitOfMeasure(unit); err != nil {
+		return err
+	}
+	if unit.CreatedAt.IsZero() {
+		unit.CreatedAt = time.Now().Truncate(time.Microsecond)
+	}
+	return m.storage.CreateUnit(ctx, unit)
+}
+
+// GetUnit gets a unit of measure by code
+// コードで単位を取得
+func (m *Manager) GetUnit(ctx context.Context, code string) (*UnitOfMeasure, error) {
+	if err := ValidateUnitCode(code); err != nil {
+		return nil, err
+	}
+	return m.storage.GetUnit(ctx, code)
+}
+
+// ListUnits lists all units of measure
+// 全ての単位を取得
+func (m *Manager) ListUnits(ctx context.Context) ([]UnitOfMeasure, error) {
+	return m.storage.ListUnits(ctx)
+}
+
+// DeleteUnit deletes a unit of measure
+// 単位を削除
+//
+// 商品の基本単位または換算に使用されている単位は削除できず、ErrUnitInUse を返します。
+func (m *Manager) DeleteUnit(ctx context.Context, code string) error {
+	if err := ValidateUnitCode(code); err != nil {
+		return err
+	}
+	return m.storage.DeleteUnit(ctx, code)
+}
+
+// SetUnitConversion creates or replaces the conversion of an item's unit to its base unit
+// 商品の単位から基本単位への換算を作成または置換
+func (m *Manager) SetUnitConversion(ctx context.Context, conversion *UnitConversion) error {
+	if err := ValidateUnitConversion(conversion); err != nil {
+		return err
+	}
+	item, err := m.storage.GetItem(ctx, conversion.ItemID)
+	if err != nil {
+		return err
+	}
+	if conversion.UoM == baseUoM(item) {
+		return NewValidationError("uom", "基本単位の換算は設定できません", conversion.UoM)
+	}
+	if _, err := m.storage.GetUnit(ctx, conversion.UoM); err != nil {
+		return err
+	}
+	conversion.UpdatedAt = time.Now().Truncate(time.Microsecond)
+	return m.storage.SetUnitConversion(ctx, conversion)
+}
+
+// ListUnitConversions lists the unit conversions of an item
+// 商品の単位の換算を取得
+func (m *Manager) ListUnitConversions(ctx context.Context, itemID string) ([]UnitConversion, error) {
+	if err := ValidateItemID(itemID); err != nil {
+		return nil, err
+	}
+	if _, err := m.storage.GetItem(ctx, itemID); err != nil {
+		return nil, err
+	}
+	return m.storage.ListUnitConversions(ctx, itemID)
+}
+
+// DeleteUnitConversion deletes the conversion of an item's unit
+// 商品の単位の換算を削除
+func (m *Manager) DeleteUnitConversion(ctx context.Context, itemID, uom string) error {
+	if err := ValidateItemID(itemID); err != nil {
+		return err
+	}
+	if err := ValidateUnitCode(uom); err != nil {
+		return err
+	}
+	return m.storage.DeleteUnitConversion(ctx, itemID, uom)
+}
+
+// ParseQuantity parses a decimal quantity given in a unit into an integer in that unit's decimals
+// 単位で指定した小数の数量を、その単位の 10^-Decimals 単位の整数に変換
+//
+// uom が空の場合は商品の基本単位として解釈します。例えば Decimals が3の kg で "1.5" は 1500 です。
+// 単位の桁数を超える小数や0以下の値は ErrValidation を返します。
+func (m *Manager) ParseQuantity(ctx context.Context, itemID, uom, value string) (int64, error) {
+	if err := ValidateItemID(itemID); err != nil {
+		return 0, err
+	}
+	item, err := m.storage.GetItem(ctx, itemID)
+	if err != nil {
+		return 0, err
+	}
+	if uom == "" {
+		uom = baseUoM(item)
+	}
+	unit, err := m.storage.GetUnit(ctx, uom)
+	if err != nil {
+		return 0, err
+	}
+
+	value = strings.TrimSpace(value)
+	parsed, ok := new(big.Rat).SetString(value)
+	if !ok || strings.ContainsAny(value, "/eE") {
+		return 0, NewValidationError("quantity", "数量の形式が正しくありません", value)
+	}
+	if parsed.Sign() <= 0 {
+		return 0, NewValidationError("quantity", "数量は正の値である必要があります", value)
+	}
+	scaled := parsed.Mul(parsed, new(big.Rat).SetInt(pow10(unit.Decimals)))
+	if !scaled.IsInt() {
+		return 0, NewValidationError("quantity", "数量の小数点以下の桁数が単位の桁数を超えています", value)
+	}
+	if !scaled.Num().IsInt64() {
+		return 0, NewValidationError("quantity", "数量が大きすぎます", value)
+	}
+	return scaled.Num().Int64(), nil
+}
+
+// ConvertQuantity converts a quantity given in a unit to the item's base unit
+// 単位で指定した数量を商品の基本単位の数量に換算
+//
+// 数量は各単位の 10^-Decimals 単位の整数です。uom が空または基本単位の場合はそのまま返します。
+// 換算が登録されていない場合は ErrUnitConversionNotFound、換算後の数量が基本単位の桁数で
+// 表せない場合は ErrValidation を返します。
+func (m *Manager) ConvertQuantity(ctx context.Context, itemID, uom string, quantity int64) (int64, error) {
+	if err := ValidateItemID(itemID); err != nil {
+		return 0, err
+	}
+	item, err := m.storage.GetItem(ctx, itemID)
+	if err != nil {
+		return 0, err
+	}
+	base := baseUoM(item)
+	if uom == "" || uom == base {
+		return quantity, nil
+	}
+
+	conversion, err := m.storage.GetUnitConversion(ctx, itemID, uom)
+	if err != nil {
+		return 0, err
+	}
+	from, err := m.storage.GetUnit(ctx, uom)
+	if err != nil {
+		return 0, err
+	}
+	to, err := m.storage.GetUnit(ctx, base)
+	if err != nil {
+		return 0, err
+	}
+
+	// 換算係数は10進数の表記から有理数にし、浮動小数点の誤差を数量に持ち込まない
+	factor, ok := new(big.Rat).SetString(strconv.FormatFloat(conversion.Factor, 'f', -1, 64))
+	if !ok {
+		return 0, NewValidationError("factor", "換算係数は正の値である必要があります", strconv.FormatFloat(conversion.Factor, 'g', -1, 64))
+	}
+	converted := new(big.Rat).Mul(new(big.Rat).SetInt64(quantity), factor)
+	if to.Decimals >= from.Decimals {
+		converted.Mul(converted, new(big.Rat).SetInt(pow10(to.Decimals-from.Decimals)))
+	} else {
+		converted.Quo(converted, new(big.Rat).SetInt(pow10(from.Decimals-to.Decimals)))
+	}
+	if !converted.IsInt() {
+		return 0, NewValidationError("quantity", "換算後の数量が基本単位の桁数で表せません", strconv.FormatInt(quantity, 10))
+	}
+	if !converted.Num().IsInt64() {
+		return 0, NewValidationError("quantity", "数量が大きすぎます", strconv.FormatInt(quantity, 10))
+	}
+	return converted.Num().Int64(), nil
+}
+
+// toBaseQuantity converts the quantity of an add, remove or transfer from the unit requested by the context
+// 在庫追加・削除・移動の数量をコンテキストが指定する単位から基本単位に換算
+//
+// 単位の指定がない場合は数量をそのまま返します。換算した場合は指定された単位と数量を
+// unitMetadata で記録できるようにしたコンテキストを返します。
+func (m *Manager) toBaseQuantity(ctx context.Context, itemID string, quantity int64) (context.Context, int64, error) {
+	uom := requestedUoM(ctx)
+	if uom == "" {
+		return ctx, quantity, nil
+	}
+	if err := ValidateUnitCode(uom); err != nil {
+		return ctx, 0, err
+	}
+	converted, err := m.ConvertQuantity(ctx, itemID, uom, quantity)
+	if err != nil {
+		return ctx, 0, err
+	}
+	if converted <= 0 {
+		return ctx, 0, NewValidationError("quantity", "数量は正の値である必要があります", strconv.FormatInt(converted, 10))
+	}
+	return context.WithValue(ctx, convertedQuantityKey{}, convertedQuantity{uom: uom, quantity: quantity}), converted, nil
+}
+
+// unitMetadata adds the unit and quantity the caller specified to transaction metadata
+// 呼び出し元が指定した単位と数量をトランザクションのメタデータに追加
+func unitMetadata(ctx context.Context, metadata map[string]string) map[string]string {
+	converted, ok := ctx.Value(convertedQuantityKey{}).(convertedQuantity)
+	if !ok {
+		return metadata
+	}
+	if metadata == nil {
+		metadata = make(map[string]string, 2)
+	}
+	metadata[MetadataUoM] = converted.uom
+	metadata[MetadataUoMQuantity] = strconv.FormatInt(converted.quantity, 10)
+	return metadata
+}
+
+// checkBaseUoM verifies that an item's base unit exists and is not changed while the item has stock
+// 商品の基本単位が存在し、在庫がある商品の基本単位を変更しないことを確認
+func (m *Manager) checkBaseUoM(ctx context.Context, item *Item, existing *Item) error {
+	if existing != nil && baseUoM(existing) == baseUoM(item) {
+		return nil
+	}
+	if _, err := m.storage.GetUnit(ctx, baseUoM(item)); err != nil {
+		return err
+	}
+	if existing == nil {
+		return nil
+	}
+	stocks, err := m.storage.ListStockByItem(ctx, item.ID)
+	if err != nil {
+		return NewStorageError("list_stock", "在庫一覧の取得に失敗しました", err)
+	}
+	for _, stock := range stocks {
+		if stock.Quantity != 0 {
+			return NewValidationError("base_uom", "在庫がある商品の基本単位は変更できません", item.BaseUoM)
+		}
+	}
+	return nil
+}
+
+// baseUoM returns the base unit of an item
+// 商品の基本単位を返す（未設定の場合は each）
+func baseUoM(item *Item) string {
+	if item.BaseUoM == "" {
+		return UoMEach
+	}
+	return item.BaseUoM
+}
+
+// pow10 returns 10 to the power of n
+// 10のn乗を返す
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
@@ -2,8 +2,10 @@ package inventory
 
 import (
 	"fmt"
+	"math"
 	"regexp"
 	"strings"
+	"time"
 	"unicode"
 )
 
@@ -137,6 +139,31 @@ func ValidateLotNumber(lotNumber string) error {
 	return nil
 }
 
+// ValidateUnitCode 単位コードをバリデーション
+func ValidateUnitCode(code string) error {
+	if code == "" {
+		return NewValidationError("uom", "単位コードが空です", code)
+	}
+	if len(code) > 32 {
+		return NewValidationError("uom", "単位コードが長すぎます", code)
+	}
+	// 英数字、ハイフン、アンダースコア、ドットのみ許可
+	validPattern := regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+	if !validPattern.MatchString(code) {
+		return NewValidationError("uom", "単位コードに無効な文字が含まれています", code)
+	}
+	return nil
+}
+
+// ValidateLotPickingStrategy ロットの消費順序をバリデーション
+func ValidateLotPickingStrategy(strategy LotPickingStrategy) error {
+	switch strategy {
+	case LotPickingFEFO, LotPickingFIFO, LotPickingLIFO:
+		return nil
+	}
+	return NewValidationError("lot_strategy", "ロットの消費順序が正しくありません", string(strategy))
+}
+
 // ValidateUnitCost 単価をバリデーション
 func ValidateUnitCost(unitCost float64) error {
 	if unitCost < 0 {
@@ -190,38 +217,43 @@ func ValidateUserID(userID string) error {
 }
 
 // ValidateTransactionType トランザクション種別をバリデーション
-func ValidateTransactionType(transactionType string) error {
-	validTypes := map[string]bool{
-		TransactionTypeInbound:  true,
-		TransactionTypeOutbound: true,
-		TransactionTypeTransfer: true,
-		TransactionTypeAdjust:   true,
+func ValidateTransactionType(transactionType TransactionType) error {
+	validTypes := map[TransactionType]bool{
+		TransactionTypeInbound:   true,
+		TransactionTypeOutbound:  true,
+		TransactionTypeTransfer:  true,
+		TransactionTypeAdjust:    true,
+		TransactionTypeReserve:   true,
+		TransactionTypeRelease:   true,
+		TransactionTypeStocktake: true,
+		TransactionTypeAssembly:  true,
 	}
 	
 	if !validTypes[transactionType] {
-		return NewValidationError("transaction_type", "無効なトランザクション種別です", transactionType)
+		return NewValidationError("transaction_type", "無効なトランザクション種別です", string(transactionType))
 	}
 	return nil
 }
 
 // ValidateAlertType アラート種別をバリデーション
-func ValidateAlertType(alertType string) error {
-	validTypes := map[string]bool{
+func ValidateAlertType(alertType AlertType) error {
+	validTypes := map[AlertType]bool{
 		AlertTypeLowStock:    true,
-		AlertTypeExpiry:      true,
-		AlertTypeOverstock:   true,
-		AlertTypeSystemError: true,
+		AlertTypeOverStock:   true,
+		AlertTypeExpiring:    true,
+		AlertTypeExpired:     true,
+		AlertTypeDiscrepancy: true,
 	}
 	
 	if !validTypes[alertType] {
-		return NewValidationError("alert_type", "無効なアラート種別です", alertType)
+		return NewValidationError("alert_type", "無効なアラート種別です", string(alertType))
 	}
 	return nil
 }
 
 // ValidateOperationType オペレーション種別をバリデーション
-func ValidateOperationType(operationType string) error {
-	validTypes := map[string]bool{
+func ValidateOperationType(operationType OperationType) error {
+	validTypes := map[OperationType]bool{
 		OperationTypeAdd:      true,
 		OperationTypeRemove:   true,
 		OperationTypeTransfer: true,
@@ -229,7 +261,7 @@ func ValidateOperationType(operationType string) error {
 	}
 	
 	if !validTypes[operationType] {
-		return NewValidationError("operation_type", "無効なオペレーション種別です", operationType)
+		return NewValidationError("operation_type", "無効なオペレーション種別です", string(operationType))
 	}
 	return nil
 }
@@ -258,6 +290,11 @@ func ValidateItem(item *Item) error {
 	if err := ValidateUnitCost(item.UnitCost); err != nil {
 		return err
 	}
+	if item.BaseUoM != "" {
+		if err := ValidateUnitCode(item.BaseUoM); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -277,7 +314,38 @@ func ValidateLocation(location *Location) error {
 	if err := ValidateCapacity(location.Capacity); err != nil {
 		return err
 	}
+	if err := ValidateLocationPriority(location.Priority); err != nil {
+		return err
+	}
+	if err := ValidateCoordinates(location.Latitude, location.Longitude); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ValidateLocationPriority ロケーションの引当の優先順位をバリデーション
+func ValidateLocationPriority(priority int) error {
+	if priority < 0 || priority > 9999 {
+		return NewValidationError("priority", "優先順位は0〜9999の範囲で指定してください", fmt.Sprintf("%d", priority))
+	}
+	return nil
+}
 
+// ValidateCoordinates 緯度・経度をバリデーション（両方を指定するか両方を省略する）
+func ValidateCoordinates(latitude, longitude *float64) error {
+	if (latitude == nil) != (longitude == nil) {
+		return NewValidationError("latitude", "緯度と経度は両方を指定してください", "")
+	}
+	if latitude == nil {
+		return nil
+	}
+	if *latitude < -90 || *latitude > 90 {
+		return NewValidationError("latitude", "緯度は-90〜90の範囲で指定してください", fmt.Sprintf("%g", *latitude))
+	}
+	if *longitude < -180 || *longitude > 180 {
+		return NewValidationError("longitude", "経度は-180〜180の範囲で指定してください", fmt.Sprintf("%g", *longitude))
+	}
 	return nil
 }
 
@@ -366,8 +434,8 @@ func ValidateTransaction(tx *Transaction) error {
 	}
 
 	// ロット番号の確認（任意フィールド）
-	if tx.LotNumber != "" {
-		if err := ValidateLotNumber(tx.LotNumber); err != nil {
+	if tx.LotNumber != nil {
+		if err := ValidateLotNumber(*tx.LotNumber); err != nil {
 			return err
 		}
 	}
@@ -411,6 +479,698 @@ func ValidateStockAlert(alert *StockAlert) error {
 	return nil
 }
 
+// ValidateReorderPoint 発注点をバリデーション
+func ValidateReorderPoint(reorderPoint *ReorderPoint) error {
+	if reorderPoint == nil {
+		return NewValidationError("reorder_point", "発注点が指定されていません", "nil")
+	}
+
+	if err := ValidateItemID(reorderPoint.ItemID); err != nil {
+		return err
+	}
+	if err := ValidateLocationID(reorderPoint.LocationID); err != nil {
+		return err
+	}
+	levels := []struct {
+		field string
+		value int64
+	}{
+		{"reorder_point", reorderPoint.ReorderPointQty},
+		{"min_qty", reorderPoint.MinQty},
+		{"max_qty", reorderPoint.MaxQty},
+	}
+	for _, level := range levels {
+		if level.value < 0 {
+			return NewValidationError(level.field, "閾値は0以上である必要があります", fmt.Sprintf("%d", level.value))
+		}
+		if level.value > 999999999 {
+			return NewValidationError(level.field, "閾値が有効範囲を超えています", fmt.Sprintf("%d", level.value))
+		}
+	}
+
+	// 最大在庫数は0（上限なし）または最小在庫数・発注点以上
+	if reorderPoint.MaxQty > 0 && reorderPoint.MaxQty < reorderPoint.MinQty {
+		return NewValidationError("max_qty", "最大在庫数は最小在庫数以上である必要があります", fmt.Sprintf("%d", reorderPoint.MaxQty))
+	}
+	if reorderPoint.MaxQty > 0 && reorderPoint.MaxQty < reorderPoint.ReorderPointQty {
+		return NewValidationError("max_qty", "最大在庫数は発注点以上である必要があります", fmt.Sprintf("%d", reorderPoint.MaxQty))
+	}
+
+	return nil
+}
+
+// ValidateAlertSeverity アラートの重要度をバリデーション
+func ValidateAlertSeverity(severity AlertSeverity) error {
+	switch severity {
+	case AlertSeverityInfo, AlertSeverityWarning, AlertSeverityCritical:
+		return nil
+	default:
+		return NewValidationError("severity", "無効な重要度です", string(severity))
+	}
+}
+
+// ValidateAlertFilter アラート一覧の絞り込み条件をバリデーション
+func ValidateAlertFilter(filter AlertFilter) error {
+	if filter.Type != "" {
+		if err := ValidateAlertType(filter.Type); err != nil {
+			return err
+		}
+	}
+	if filter.Severity != "" {
+		if err := ValidateAlertSeverity(filter.Severity); err != nil {
+			return err
+		}
+	}
+	if !filter.CreatedAfter.IsZero() && !filter.CreatedBefore.IsZero() && !filter.CreatedAfter.Before(filter.CreatedBefore) {
+		return NewValidationError("to", "終了日時は開始日時より後である必要があります", filter.CreatedBefore.Format(time.RFC3339))
+	}
+	return validateOffsetLimit(filter.Offset, filter.Limit)
+}
+
+// ValidateAlertNote アラートの確認・解決時のメモをバリデーション
+func ValidateAlertNote(note string) error {
+	if note == "" {
+		return nil // メモは任意
+	}
+	if len(note) > 2000 {
+		return NewValidationError("note", "メモが長すぎます", note)
+	}
+	return nil
+}
+
+// ValidateAdjustmentReason 在庫調整の理由コードをバリデーション
+func ValidateAdjustmentReason(reason AdjustmentReason) error {
+	switch reason {
+	case AdjustmentReasonDamage, AdjustmentReasonShrinkage, AdjustmentReasonCountCorrection,
+		AdjustmentReasonExpired, AdjustmentReasonFound, AdjustmentReasonOther:
+		return nil
+	case "":
+		return NewValidationError("reason_code", "理由コードが指定されていません", "")
+	default:
+		return NewValidationError("reason_code", "無効な理由コードです", string(reason))
+	}
+}
+
+// ValidateAdjustment 在庫調整の申請をバリデーション
+func ValidateAdjustment(adjustment *Adjustment, allowNegative bool) error {
+	if adjustment == nil {
+		return NewValidationError("adjustment", "在庫調整が指定されていません", "")
+	}
+	if err := ValidateItemID(adjustment.ItemID); err != nil {
+		return err
+	}
+	if err := ValidateLocationID(adjustment.LocationID); err != nil {
+		return err
+	}
+	if adjustment.NewQuantity < 0 && !allowNegative {
+		return NewValidationError("quantity", "負の在庫は許可されていません", fmt.Sprintf("%d", adjustment.NewQuantity))
+	}
+	if err := ValidateAdjustmentReason(adjustment.Reason); err != nil {
+		return err
+	}
+	if adjustment.Reason == AdjustmentReasonOther && strings.TrimSpace(adjustment.Note) == "" {
+		return NewValidationError("note", "理由コードが other の場合はメモが必要です", "")
+	}
+	if len(adjustment.Note) > 2000 {
+		return NewValidationError("note", "メモが長すぎます", adjustment.Note)
+	}
+	return ValidateReference(adjustment.Reference)
+}
+
+// ValidateInspection 入荷検品（隔離する入荷）をバリデーション
+func ValidateInspection(inspection *Inspection) error {
+	if inspection == nil {
+		return NewValidationError("inspection", "入荷検品が指定されていません", "")
+	}
+	if err := ValidateItemID(inspection.ItemID); err != nil {
+		return err
+	}
+	if err := ValidateLocationID(inspection.LocationID); err != nil {
+		return err
+	}
+	if inspection.Quantity <= 0 {
+		return NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", inspection.Quantity))
+	}
+	if err := ValidateQuantity(inspection.Quantity, false); err != nil {
+		return err
+	}
+	if err := ValidateAlertNote(inspection.Note); err != nil {
+		return err
+	}
+	return ValidateReference(inspection.Reference)
+}
+
+// ValidateUnitOfMeasure 単位をバリデーション
+func ValidateUnitOfMeasure(unit *UnitOfMeasure) error {
+	if unit == nil {
+		return NewValidationError("unit", "単位が指定されていません", "nil")
+	}
+	if err := ValidateUnitCode(unit.Code); err != nil {
+		return err
+	}
+	if strings.TrimSpace(unit.Name) == "" {
+		return NewValidationError("name", "単位名が空です", unit.Name)
+	}
+	if len(unit.Name) > 255 {
+		return NewValidationError("name", "単位名が長すぎます", unit.Name)
+	}
+	if unit.Decimals < 0 || unit.Decimals > MaxUoMDecimals {
+		return NewValidationError("decimals", "小数点以下の桁数は0〜6で指定してください", fmt.Sprintf("%d", unit.Decimals))
+	}
+	return nil
+}
+
+// ValidateUnitConversion 単位の換算をバリデーション
+func ValidateUnitConversion(conversion *UnitConversion) error {
+	if conversion == nil {
+		return NewValidationError("unit_conversion", "単位の換算が指定されていません", "nil")
+	}
+	if err := ValidateItemID(conversion.ItemID); err != nil {
+		return err
+	}
+	if err := ValidateUnitCode(conversion.UoM); err != nil {
+		return err
+	}
+	if !(conversion.Factor > 0) || math.IsInf(conversion.Factor, 0) {
+		return NewValidationError("factor", "換算係数は正の値である必要があります", fmt.Sprintf("%g", conversion.Factor))
+	}
+	return nil
+}
+
+// ValidateBillOfMaterials 部品表をバリデーション
+func ValidateBillOfMaterials(bom *BillOfMaterials) error {
+	if bom == nil {
+		return NewValidationError("bill_of_materials", "部品表が指定されていません", "nil")
+	}
+	if err := ValidateItemID(bom.KitItemID); err != nil {
+		return err
+	}
+	if len(bom.Components) == 0 {
+		return NewValidationError("components", "構成品が指定されていません", "")
+	}
+	if len(bom.Components) > MaxBOMComponents {
+		return NewValidationError("components", "構成品の数が上限を超えています", fmt.Sprintf("%d", len(bom.Components)))
+	}
+
+	seen := make(map[string]bool, len(bom.Components))
+	for _, component := range bom.Components {
+		if err := ValidateItemID(component.ItemID); err != nil {
+			return err
+		}
+		if component.ItemID == bom.KitItemID {
+			return NewValidationError("components", "キット自身は構成品に指定できません", component.ItemID)
+		}
+		if seen[component.ItemID] {
+			return NewValidationError("components", "同じ構成品が複数指定されています", component.ItemID)
+		}
+		seen[component.ItemID] = true
+		if component.Quantity <= 0 {
+			return NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", component.Quantity))
+		}
+	}
+	return nil
+}
+
+// ValidateSupplierID 仕入先IDの形式をバリデーション
+func ValidateSupplierID(supplierID string) error {
+	if supplierID == "" {
+		return NewValidationError("supplier_id", "仕入先IDが空です", supplierID)
+	}
+	if len(supplierID) > 255 {
+		return NewValidationError("supplier_id", "仕入先IDが長すぎます", supplierID)
+	}
+	// 英数字、ハイフン、アンダースコアのみ許可
+	validPattern := regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	if !validPattern.MatchString(supplierID) {
+		return NewValidationError("supplier_id", "仕入先IDに無効な文字が含まれています", supplierID)
+	}
+	return nil
+}
+
+// ValidateSupplier 仕入先全体をバリデーション
+func ValidateSupplier(supplier *Supplier) error {
+	if supplier == nil {
+		return NewValidationError("supplier", "仕入先が指定されていません", "nil")
+	}
+	if err := ValidateSupplierID(supplier.ID); err != nil {
+		return err
+	}
+	if strings.TrimSpace(supplier.Name) == "" {
+		return NewValidationError("name", "仕入先名が空です", supplier.Name)
+	}
+	if len(supplier.Name) > 500 {
+		return NewValidationError("name", "仕入先名が長すぎます", supplier.Name)
+	}
+	if len(supplier.ContactName) > 255 {
+		return NewValidationError("contact_name", "担当者名が長すぎます", supplier.ContactName)
+	}
+	if supplier.Email != "" && (len(supplier.Email) > 255 || !IsValidEmail(supplier.Email)) {
+		return NewValidationError("email", "メールアドレスの形式が正しくありません", supplier.Email)
+	}
+	if len(supplier.Phone) > 50 {
+		return NewValidationError("phone", "電話番号が長すぎます", supplier.Phone)
+	}
+	if len(supplier.Address) > 2000 {
+		return NewValidationError("address", "住所が長すぎます", supplier.Address)
+	}
+	return nil
+}
+
+// ValidateSupplierItem 仕入先の商品の仕入条件をバリデーション
+func ValidateSupplierItem(supplierItem *SupplierItem) error {
+	if supplierItem == nil {
+		return NewValidationError("supplier_item", "仕入先の商品が指定されていません", "nil")
+	}
+	if err := ValidateSupplierID(supplierItem.SupplierID); err != nil {
+		return err
+	}
+	if err := ValidateItemID(supplierItem.ItemID); err != nil {
+		return err
+	}
+	if len(supplierItem.SupplierSKU) > 255 {
+		return NewValidationError("supplier_sku", "仕入先の品番が長すぎます", supplierItem.SupplierSKU)
+	}
+	if err := ValidateUnitCost(supplierItem.UnitCost); err != nil {
+		return err
+	}
+	if supplierItem.LeadTimeDays < 0 || supplierItem.LeadTimeDays > 365 {
+		return NewValidationError("lead_time_days", "リードタイムは0〜365日で指定してください", fmt.Sprintf("%d", supplierItem.LeadTimeDays))
+	}
+	if supplierItem.MinOrderQuantity < 0 {
+		return NewValidationError("min_order_quantity", "最小発注数量は0以上である必要があります", fmt.Sprintf("%d", supplierItem.MinOrderQuantity))
+	}
+	if supplierItem.MinOrderQuantity > 999999999 {
+		return NewValidationError("min_order_quantity", "最小発注数量が有効範囲を超えています", fmt.Sprintf("%d", supplierItem.MinOrderQuantity))
+	}
+	return nil
+}
+
+// ValidatePurchaseOrder 発注をバリデーション
+func ValidatePurchaseOrder(order *PurchaseOrder) error {
+	if order == nil {
+		return NewValidationError("purchase_order", "発注が指定されていません", "nil")
+	}
+	if err := ValidateSupplierID(order.SupplierID); err != nil {
+		return err
+	}
+	if err := ValidateLocationID(order.LocationID); err != nil {
+		return err
+	}
+	if err := ValidateReference(order.Reference); err != nil {
+		return err
+	}
+	if err := ValidateAlertNote(order.Note); err != nil {
+		return err
+	}
+	if len(order.Lines) == 0 {
+		return NewValidationError("lines", "発注の行が指定されていません", "")
+	}
+	if len(order.Lines) > MaxPurchaseOrderLines {
+		return NewValidationError("lines", "発注の行の数が上限を超えています", fmt.Sprintf("%d", len(order.Lines)))
+	}
+
+	seen := make(map[string]bool, len(order.Lines))
+	for _, line := range order.Lines {
+		if err := ValidateItemID(line.ItemID); err != nil {
+			return err
+		}
+		if seen[line.ItemID] {
+			return NewValidationError("item_id", "同じ商品が複数指定されています", line.ItemID)
+		}
+		seen[line.ItemID] = true
+		if line.Quantity <= 0 {
+			return NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", line.Quantity))
+		}
+		if err := ValidateQuantity(line.Quantity, false); err != nil {
+			return err
+		}
+		if err := ValidateUnitCost(line.UnitCost); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateGoodsReceipt 発注に対する入荷をバリデーション
+func ValidateGoodsReceipt(receipt *GoodsReceipt) error {
+	if receipt == nil {
+		return NewValidationError("goods_receipt", "入荷が指定されていません", "nil")
+	}
+	if err := ValidateReference(receipt.Reference); err != nil {
+		return err
+	}
+	if len(receipt.Lines) == 0 {
+		return NewValidationError("lines", "入荷の行が指定されていません", "")
+	}
+	if len(receipt.Lines) > MaxPurchaseOrderLines {
+		return NewValidationError("lines", "発注の行の数が上限を超えています", fmt.Sprintf("%d", len(receipt.Lines)))
+	}
+
+	for _, line := range receipt.Lines {
+		if err := ValidateItemID(line.ItemID); err != nil {
+			return err
+		}
+		if line.Quantity <= 0 {
+			return NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", line.Quantity))
+		}
+		if err := ValidateQuantity(line.Quantity, false); err != nil {
+			return err
+		}
+		if err := ValidateUnitCost(line.UnitCost); err != nil {
+			return err
+		}
+		if line.LotNumber != "" {
+			if err := ValidateLotNumber(line.LotNumber); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateSalesOrder 受注をバリデーション
+func ValidateSalesOrder(order *SalesOrder) error {
+	if order == nil {
+		return NewValidationError("sales_order", "受注が指定されていません", "nil")
+	}
+	if err := ValidateLocationID(order.LocationID); err != nil {
+		return err
+	}
+	if err := ValidateReference(order.Reference); err != nil {
+		return err
+	}
+	if order.Channel != "" {
+		if err := ValidateChannel(order.Channel); err != nil {
+			return err
+		}
+	}
+	if len(order.Customer) > 255 {
+		return NewValidationError("customer", "顧客が長すぎます", order.Customer)
+	}
+	if err := ValidateAlertNote(order.Note); err != nil {
+		return err
+	}
+	if len(order.Lines) == 0 {
+		return NewValidationError("lines", "受注の行が指定されていません", "")
+	}
+	if len(order.Lines) > MaxSalesOrderLines {
+		return NewValidationError("lines", "受注の行の数が上限を超えています", fmt.Sprintf("%d", len(order.Lines)))
+	}
+
+	seen := make(map[string]bool, len(order.Lines))
+	for _, line := range order.Lines {
+		if err := ValidateItemID(line.ItemID); err != nil {
+			return err
+		}
+		if seen[line.ItemID] {
+			return NewValidationError("item_id", "同じ商品が複数指定されています", line.ItemID)
+		}
+		seen[line.ItemID] = true
+		if line.Quantity <= 0 {
+			return NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", line.Quantity))
+		}
+		if err := ValidateQuantity(line.Quantity, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateShipmentLines 受注の出荷確定の行をバリデーション
+func ValidateShipmentLines(lines []ShipmentLine) error {
+	if len(lines) > MaxSalesOrderLines {
+		return NewValidationError("lines", "受注の行の数が上限を超えています", fmt.Sprintf("%d", len(lines)))
+	}
+
+	seen := make(map[string]bool, len(lines))
+	for _, line := range lines {
+		if err := ValidateItemID(line.ItemID); err != nil {
+			return err
+		}
+		if seen[line.ItemID] {
+			return NewValidationError("item_id", "同じ商品が複数指定されています", line.ItemID)
+		}
+		seen[line.ItemID] = true
+		if err := ValidateQuantity(line.Quantity, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateReturn 返品（RMA）をバリデーション
+func ValidateReturn(rma *ReturnAuthorization) error {
+	if rma == nil {
+		return NewValidationError("return", "返品が指定されていません", "nil")
+	}
+	if err := ValidateLocationID(rma.LocationID); err != nil {
+		return err
+	}
+	if err := ValidateReference(rma.Reference); err != nil {
+		return err
+	}
+	if len(rma.Customer) > 255 {
+		return NewValidationError("customer", "顧客が長すぎます", rma.Customer)
+	}
+	if len(rma.Reason) > 500 {
+		return NewValidationError("reason", "返品理由が長すぎます", rma.Reason)
+	}
+	if err := ValidateAlertNote(rma.Note); err != nil {
+		return err
+	}
+	if len(rma.Lines) == 0 {
+		return NewValidationError("lines", "返品の行が指定されていません", "")
+	}
+	if len(rma.Lines) > MaxReturnLines {
+		return NewValidationError("lines", "返品の行の数が上限を超えています", fmt.Sprintf("%d", len(rma.Lines)))
+	}
+
+	seen := make(map[string]bool, len(rma.Lines))
+	for _, line := range rma.Lines {
+		if err := ValidateItemID(line.ItemID); err != nil {
+			return err
+		}
+		if seen[line.ItemID] {
+			return NewValidationError("item_id", "同じ商品が複数指定されています", line.ItemID)
+		}
+		seen[line.ItemID] = true
+		if line.Quantity <= 0 {
+			return NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", line.Quantity))
+		}
+		if err := ValidateQuantity(line.Quantity, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateReturnReceiptLines 返品の到着の行をバリデーション
+func ValidateReturnReceiptLines(lines []ReturnReceiptLine) error {
+	if len(lines) == 0 {
+		return NewValidationError("lines", "返品の行が指定されていません", "")
+	}
+	if len(lines) > MaxReturnLines {
+		return NewValidationError("lines", "返品の行の数が上限を超えています", fmt.Sprintf("%d", len(lines)))
+	}
+
+	seen := make(map[string]bool, len(lines))
+	for _, line := range lines {
+		if err := ValidateItemID(line.ItemID); err != nil {
+			return err
+		}
+		if seen[line.ItemID] {
+			return NewValidationError("item_id", "同じ商品が複数指定されています", line.ItemID)
+		}
+		seen[line.ItemID] = true
+		if line.Quantity <= 0 {
+			return NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", line.Quantity))
+		}
+		if err := ValidateQuantity(line.Quantity, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateReturnDisposition 返品の処分をバリデーション
+func ValidateReturnDisposition(disposition *ReturnDisposition) error {
+	if disposition == nil {
+		return NewValidationError("disposition", "返品の処分が指定されていません", "nil")
+	}
+	if err := ValidateItemID(disposition.ItemID); err != nil {
+		return err
+	}
+	if disposition.Quantity <= 0 {
+		return NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", disposition.Quantity))
+	}
+	if err := ValidateQuantity(disposition.Quantity, false); err != nil {
+		return err
+	}
+	if err := ValidateAlertNote(disposition.Note); err != nil {
+		return err
+	}
+
+	switch disposition.Type {
+	case ReturnDispositionRestock, ReturnDispositionRefurbish, ReturnDispositionScrap:
+	default:
+		return NewValidationError("disposition", "返品の処分方法が正しくありません", string(disposition.Type))
+	}
+	if disposition.Type != ReturnDispositionRefurbish && (disposition.TargetItemID != "" || disposition.LocationID != "") {
+		return NewValidationError("disposition", "再整備以外の処分では商品・ロケーションを指定できません", string(disposition.Type))
+	}
+	if disposition.TargetItemID != "" {
+		if err := ValidateItemID(disposition.TargetItemID); err != nil {
+			return err
+		}
+	}
+	if disposition.LocationID != "" {
+		if err := ValidateLocationID(disposition.LocationID); err != nil {
+			return err
+		}
+	}
+	if disposition.Type != ReturnDispositionScrap {
+		if disposition.Reason != "" {
+			return NewValidationError("reason_code", "廃棄以外の処分では理由コードを指定できません", string(disposition.Reason))
+		}
+		return nil
+	}
+	if err := ValidateAdjustmentReason(disposition.Reason); err != nil {
+		return err
+	}
+	if disposition.Reason == AdjustmentReasonOther && strings.TrimSpace(disposition.Note) == "" {
+		return NewValidationError("note", "理由コードが other の場合はメモが必要です", "")
+	}
+	return nil
+}
+
+// ValidateChannel 販売チャネルの形式をバリデーション
+func ValidateChannel(channel string) error {
+	if channel == "" {
+		return NewValidationError("channel", "販売チャネルが空です", channel)
+	}
+	if len(channel) > 64 {
+		return NewValidationError("channel", "販売チャネルが長すぎます", channel)
+	}
+	// 英数字、ハイフン、アンダースコア、ドットのみ許可
+	validPattern := regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+	if !validPattern.MatchString(channel) {
+		return NewValidationError("channel", "販売チャネルに無効な文字が含まれています", channel)
+	}
+	return nil
+}
+
+// ValidateChannelAllocation 販売チャネルの確保をバリデーション
+func ValidateChannelAllocation(allocation *ChannelAllocation) error {
+	if allocation == nil {
+		return NewValidationError("channel_allocation", "販売チャネルの確保が指定されていません", "nil")
+	}
+	if err := ValidateItemID(allocation.ItemID); err != nil {
+		return err
+	}
+	if err := ValidateLocationID(allocation.LocationID); err != nil {
+		return err
+	}
+	if err := ValidateChannel(allocation.Channel); err != nil {
+		return err
+	}
+	if allocation.Quantity < 0 {
+		return NewValidationError("quantity", "確保数量は0以上である必要があります", fmt.Sprintf("%d", allocation.Quantity))
+	}
+	return ValidateQuantity(allocation.Quantity, false)
+}
+
+// ValidateAllocationRequest 複数ロケーションからの引当の要求をバリデーション
+func ValidateAllocationRequest(request *AllocationRequest) error {
+	if request == nil {
+		return NewValidationError("allocation", "引当の要求が指定されていません", "")
+	}
+	if err := ValidateItemID(request.ItemID); err != nil {
+		return err
+	}
+	if request.Quantity <= 0 {
+		return NewValidationError("quantity", "数量は正の値である必要があります", fmt.Sprintf("%d", request.Quantity))
+	}
+	if err := ValidateQuantity(request.Quantity, false); err != nil {
+		return err
+	}
+	if request.Channel != "" {
+		if err := ValidateChannel(request.Channel); err != nil {
+			return err
+		}
+	}
+	switch request.Strategy {
+	case "", AllocationStrategyPriority, AllocationStrategyProximity, AllocationStrategyStockLevel:
+	default:
+		return NewValidationError("strategy", "引当の方式が正しくありません", string(request.Strategy))
+	}
+	if request.Destination != nil {
+		if err := ValidateCoordinates(&request.Destination.Latitude, &request.Destination.Longitude); err != nil {
+			return err
+		}
+	} else if request.Strategy == AllocationStrategyProximity {
+		return NewValidationError("destination", "proximity の引当には配送先が必要です", "")
+	}
+	if request.MaxLocations < 0 {
+		return NewValidationError("max_locations", "ロケーション数の上限は0以上で指定してください", fmt.Sprintf("%d", request.MaxLocations))
+	}
+	for _, locationID := range request.LocationIDs {
+		if err := ValidateLocationID(locationID); err != nil {
+			return err
+		}
+	}
+	return ValidateReference(request.Reference)
+}
+
+// ValidateAlertRule アラートルールをバリデーション
+func ValidateAlertRule(rule *AlertRule) error {
+	if rule == nil {
+		return NewValidationError("alert_rule", "アラートルールが指定されていません", "nil")
+	}
+
+	if strings.TrimSpace(rule.Name) == "" {
+		return NewValidationError("name", "ルール名が空です", rule.Name)
+	}
+	if len(rule.Name) > 255 {
+		return NewValidationError("name", "ルール名が長すぎます", rule.Name)
+	}
+	switch rule.Metric {
+	case AlertMetricQuantity, AlertMetricAvailable, AlertMetricReserved:
+	default:
+		return NewValidationError("metric", "無効な判定値です", string(rule.Metric))
+	}
+	switch rule.Comparator {
+	case AlertComparatorLT, AlertComparatorLTE, AlertComparatorGT, AlertComparatorGTE:
+	default:
+		return NewValidationError("comparator", "無効な比較方法です", string(rule.Comparator))
+	}
+	if rule.Threshold < 0 {
+		return NewValidationError("threshold", "閾値は0以上である必要があります", fmt.Sprintf("%d", rule.Threshold))
+	}
+	if rule.Threshold > 999999999 {
+		return NewValidationError("threshold", "閾値が有効範囲を超えています", fmt.Sprintf("%d", rule.Threshold))
+	}
+	if err := ValidateAlertSeverity(rule.Severity); err != nil {
+		return err
+	}
+	if rule.CooldownSeconds < 0 {
+		return NewValidationError("cooldown_seconds", "クールダウンは0以上である必要があります", fmt.Sprintf("%d", rule.CooldownSeconds))
+	}
+
+	// 対象範囲は省略可能（空の場合は全商品・全ロケーション）
+	if rule.ItemID != "" {
+		if err := ValidateItemID(rule.ItemID); err != nil {
+			return err
+		}
+	}
+	if rule.LocationID != "" {
+		if err := ValidateLocationID(rule.LocationID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // IsASCII 文字列がASCII文字のみかをチェック
 func IsASCII(s string) bool {
 	for _, r := range s {
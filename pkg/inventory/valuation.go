@@ -27,6 +27,8 @@ func NewValuationEngine(storage Storage, logger *zap.Logger) *ValuationEngineImp
 
 // CalculateValue calculates inventory value using specified method
 // 指定された方法で在庫価値を計算
+//
+// 仕入先所有の委託在庫は自社の資産ではないため、評価対象から除外します。
 func (v *ValuationEngineImpl) CalculateValue(ctx context.Context, itemID, locationID string, method ValuationMethod) (float64, error) {
 	// 現在の在庫を取得
 	stock, err := v.storage.GetStock(ctx, itemID, locationID)
@@ -38,16 +40,25 @@ func (v *ValuationEngineImpl) CalculateValue(ctx context.Context, itemID, locati
 		return 0, nil
 	}
 
+	// 委託在庫を除いた自社所有の数量を評価
+	quantity, err := ownedQuantity(ctx, v.storage, stock)
+	if err != nil {
+		return 0, err
+	}
+	if quantity <= 0 {
+		return 0, nil
+	}
+
 	// 評価方法に応じて計算
 	switch method {
 	case ValuationMethodFIFO:
-		return v.calculateFIFO(ctx, itemID, locationID, stock.Quantity)
+		return v.calculateFIFO(ctx, itemID, locationID, quantity)
 	case ValuationMethodLIFO:
-		return v.calculateLIFO(ctx, itemID, locationID, stock.Quantity)
+		return v.calculateLIFO(ctx, itemID, locationID, quantity)
 	case ValuationMethodAverage:
-		return v.calculateAverage(ctx, itemID, locationID, stock.Quantity)
+		return v.calculateAverage(ctx, itemID, locationID, quantity)
 	case ValuationMethodStandard:
-		return v.calculateStandard(ctx, itemID, stock.Quantity)
+		return v.calculateStandard(ctx, itemID, quantity)
 	default:
 		return 0, fmt.Errorf("未対応の評価方法です: %s", method)
 	}
@@ -94,7 +105,7 @@ func (v *ValuationEngineImpl) GetAverageCost(ctx context.Context, itemID string)
 	totalQuantity := int64(0)
 
 	for _, tx := range transactions {
-		if tx.Type == TransactionTypeInbound && tx.UnitCost != nil && *tx.UnitCost > 0 {
+		if tx.Type == TransactionTypeInbound && tx.UnitCost != nil && *tx.UnitCost > 0 && tx.Metadata[MetadataOwnerID] == "" {
 			totalCost += *tx.UnitCost * float64(tx.Quantity)
 			totalQuantity += tx.Quantity
 		}
@@ -179,6 +190,10 @@ func (v *ValuationEngineImpl) getInboundTransactions(ctx context.Context, itemID
 
 	var inboundTransactions []Transaction
 	for _, tx := range allTransactions {
+		// 委託在庫の入庫・移動は自社の原価ではないため除外
+		if tx.Metadata[MetadataOwnerID] != "" {
+			continue
+		}
 		// 指定ロケーションへの入庫または移動を対象
 		if (tx.Type == TransactionTypeInbound && tx.ToLocation != nil && *tx.ToLocation == locationID) ||
 			(tx.Type == TransactionTypeTransfer && tx.ToLocation != nil && *tx.ToLocation == locationID) {
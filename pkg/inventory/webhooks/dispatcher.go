@@ -0,0 +1,330 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory"
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/events"
+)
+
+// DispatcherConfig holds delivery settings
+// 配信設定
+type DispatcherConfig struct {
+	Workers              int           // 配信ワーカー数
+	QueueSize            int           // 配信待ちキューの長さ
+	MaxAttempts          int           // 1件あたりの最大試行回数
+	BaseDelay            time.Duration // 再試行の初回待機時間（試行ごとに倍増）
+	MaxDelay             time.Duration // 再試行の最大待機時間
+	Timeout              time.Duration // 1回のHTTPリクエストのタイムアウト
+	AllowPrivateNetworks bool          // ループバック・リンクローカル・プライベートアドレスへの配信を許可する（社内の受信先・テスト用）
+}
+
+// DefaultDispatcherConfig returns the settings used for zero values
+// 未設定の項目に使用する配信設定を返す
+func DefaultDispatcherConfig() DispatcherConfig {
+	return DispatcherConfig{
+		Workers:     4,
+		QueueSize:   1000,
+		MaxAttempts: 5,
+		BaseDelay:   time.Second,
+		MaxDelay:    time.Minute,
+		Timeout:     10 * time.Second,
+	}
+}
+
+// payload is the JSON body posted to subscribers
+// サブスクライバーへPOSTするJSON本文
+type payload struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	Timestamp time.Time       `json:"timestamp"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// job is a pending delivery of one event to one subscription
+// 1件のイベントを1つのサブスクリプションへ配信するジョブ
+type job struct {
+	sub Subscription
+	msg events.Message
+}
+
+// Dispatcher delivers events to matching subscriptions in the background
+// 購読しているサブスクリプションへイベントをバックグラウンドで配信
+//
+// events.Senderを実装しているため、events.NewPublisherと組み合わせてManagerのイベント発行者として使用します。
+// Sendは配信をキューに積むだけで、在庫操作のレイテンシーには影響しません。
+// 2xx以外の応答や接続エラーは指数バックオフで再試行し、すべての試行を配信ログに記録します。
+type Dispatcher struct {
+	store  Store
+	cfg    DispatcherConfig
+	client *http.Client
+	logger *zap.Logger
+
+	mu     sync.RWMutex // closedとキューのクローズを保護
+	closed bool
+	queue  chan job
+	stop   chan struct{}
+	wg     sync.WaitGroup
+}
+
+var _ events.Sender = (*Dispatcher)(nil)
+
+// NewDispatcher creates a dispatcher and starts its workers
+// 配信者を作成してワーカーを開始
+func NewDispatcher(store Store, cfg DispatcherConfig, logger *zap.Logger) *Dispatcher {
+	defaults := DefaultDispatcherConfig()
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaults.Workers
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaults.QueueSize
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaults.MaxAttempts
+	}
+	if cfg.BaseDelay <= 0 {
+		cfg.BaseDelay = defaults.BaseDelay
+	}
+	if cfg.MaxDelay <= 0 {
+		cfg.MaxDelay = defaults.MaxDelay
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaults.Timeout
+	}
+
+	d := &Dispatcher{
+		store:  store,
+		cfg:    cfg,
+		client: newHTTPClient(cfg),
+		logger: logger,
+		queue:  make(chan job, cfg.QueueSize),
+		stop:   make(chan struct{}),
+	}
+
+	for i := 0; i < cfg.Workers; i++ {
+		d.wg.Add(1)
+		go d.work()
+	}
+
+	return d
+}
+
+// newHTTPClient creates the HTTP client used for deliveries
+// 配信に使用するHTTPクライアントを作成
+//
+// サブスクリプションのURLは任意に登録できるため、内部のサービス（メタデータサーバーなど）へ送信しないよう
+// 名前解決後に接続するアドレスを検証します。リダイレクト先は検証できないため従わず、3xxの応答は配信失敗とします。
+func newHTTPClient(cfg DispatcherConfig) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !cfg.AllowPrivateNetworks {
+		dialer := &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			Control:   checkDialAddress,
+		}
+		transport.DialContext = dialer.DialContext
+		// プロキシ経由では配信先のアドレスを検証できないため使用しない
+		transport.Proxy = nil
+	}
+	return &http.Client{
+		Timeout:   cfg.Timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// checkDialAddress refuses connections to non-public addresses
+// 公開されていないアドレスへの接続を拒否する（net.Dialer.Control）
+func checkDialAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !publicAddress(ip) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	return nil
+}
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598)
+// キャリアグレードNATのアドレス範囲（RFC 6598）
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// publicAddress reports whether ip is a public unicast address
+// 公開されたユニキャストアドレスかを返す
+//
+// ループバック・リンクローカル（169.254.169.254 などのメタデータサーバーを含む）・プライベート・
+// 未指定・マルチキャストのアドレスは公開されていないものとします。
+func publicAddress(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsPrivate() && !ip.IsUnspecified() && !ip.IsMulticast() && !sharedAddressSpace.Contains(ip)
+}
+
+// Send queues msg for every active subscription that matches its type
+// イベントタイプを購読している有効なサブスクリプションごとに配信をキューに積む
+func (d *Dispatcher) Send(ctx context.Context, msg events.Message) error {
+	subs, err := d.store.ListSubscriptions(ctx)
+	if err != nil {
+		return fmt.Errorf("Webhookサブスクリプションの取得に失敗しました: %w", err)
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		return fmt.Errorf("Webhook配信は停止しています")
+	}
+
+	for _, sub := range subs {
+		if !sub.Matches(msg.Type) {
+			continue
+		}
+		select {
+		case d.queue <- job{sub: sub, msg: msg}:
+		default:
+			return fmt.Errorf("Webhook配信キューが満杯です（サブスクリプション %s）", sub.ID)
+		}
+	}
+
+	return nil
+}
+
+// Close stops accepting deliveries and waits for queued ones to finish
+// 新しい配信の受付を停止し、キュー内の配信の完了を待機
+//
+// 再試行の待機中のジョブは待機を打ち切って終了します。
+func (d *Dispatcher) Close() error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.stop)
+		close(d.queue)
+	}
+	d.mu.Unlock()
+
+	d.wg.Wait()
+	return nil
+}
+
+// work processes queued deliveries until the queue is closed
+// キューが閉じられるまで配信ジョブを処理
+func (d *Dispatcher) work() {
+	defer d.wg.Done()
+	for j := range d.queue {
+		d.deliver(j)
+	}
+}
+
+// deliver posts one event to one subscription, retrying with exponential backoff
+// 1件のイベントを1つのサブスクリプションへ送信し、失敗時は指数バックオフで再試行
+func (d *Dispatcher) deliver(j job) {
+	// CloudEvents形式の場合はエンベロープをそのまま送信する
+	body := j.msg.Body
+	if j.msg.ContentType != events.ContentTypeCloudEvents {
+		var err error
+		body, err = json.Marshal(payload{
+			ID:        j.msg.ID,
+			Type:      j.msg.Type,
+			Timestamp: j.msg.Timestamp,
+			Data:      j.msg.Body,
+		})
+		if err != nil {
+			d.logger.Error("Webhook本文のJSON変換に失敗しました", zap.Error(err))
+			return
+		}
+	}
+
+	delay := d.cfg.BaseDelay
+	for attempt := 1; attempt <= d.cfg.MaxAttempts; attempt++ {
+		delivery := d.post(j, body, attempt)
+		if err := d.store.RecordDelivery(context.Background(), delivery); err != nil {
+			d.logger.Error("Webhook配信ログの記録に失敗しました", zap.Error(err))
+		}
+		if delivery.Success {
+			return
+		}
+
+		if attempt == d.cfg.MaxAttempts {
+			d.logger.Warn("Webhook配信を断念しました",
+				zap.String("subscription_id", j.sub.ID),
+				zap.String("event_id", j.msg.ID),
+				zap.Int("attempts", attempt),
+				zap.String("error", delivery.Error),
+			)
+			return
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-d.stop:
+			return
+		}
+		delay *= 2
+		if delay > d.cfg.MaxDelay {
+			delay = d.cfg.MaxDelay
+		}
+	}
+}
+
+// post sends a single signed request and returns the delivery record
+// 署名付きリクエストを1回送信し、配信記録を返す
+func (d *Dispatcher) post(j job, body []byte, attempt int) *Delivery {
+	now := time.Now()
+	delivery := &Delivery{
+		ID:             inventory.NewTransactionID(),
+		SubscriptionID: j.sub.ID,
+		EventID:        j.msg.ID,
+		EventType:      j.msg.Type,
+		Attempt:        attempt,
+		CreatedAt:      now,
+	}
+
+	req, err := http.NewRequest(http.MethodPost, j.sub.URL, bytes.NewReader(body))
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	contentType := events.ContentTypeJSON
+	if j.msg.ContentType == events.ContentTypeCloudEvents {
+		contentType = events.ContentTypeCloudEvents
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", "zaiGoFramework-Webhook/1.0")
+	req.Header.Set("X-Webhook-ID", j.msg.ID)
+	req.Header.Set("X-Webhook-Event", j.msg.Type)
+	req.Header.Set("X-Webhook-Schema-Version", strconv.Itoa(j.msg.SchemaVersion))
+	req.Header.Set("X-Webhook-Attempt", strconv.Itoa(attempt))
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set("X-Webhook-Signature", Sign(j.sub.Secret, now, body))
+
+	resp, err := d.client.Do(req)
+	delivery.DurationMs = time.Since(now).Milliseconds()
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	defer resp.Body.Close()
+	// 接続を再利用できるよう本文を読み捨てる
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	delivery.StatusCode = resp.StatusCode
+	delivery.Success = resp.StatusCode >= 200 && resp.StatusCode < 300
+	if !delivery.Success {
+		delivery.Error = fmt.Sprintf("予期しないステータスコード: %d", resp.StatusCode)
+	}
+
+	return delivery
+}
@@ -0,0 +1,149 @@
+package webhooks
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// MemoryStore implements Store with in-memory maps
+// インメモリのマップを使用したStoreの実装
+//
+// 単体テストやデモ用途を想定しています。返却値はコピーです。
+type MemoryStore struct {
+	mu            sync.RWMutex
+	subscriptions map[string]Subscription
+	deliveries    []Delivery
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates a new in-memory store
+// 新しいインメモリストアを作成
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		subscriptions: make(map[string]Subscription),
+	}
+}
+
+// CreateSubscription creates a new subscription
+// 新しいサブスクリプションを作成
+func (s *MemoryStore) CreateSubscription(ctx context.Context, sub *Subscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.subscriptions[sub.ID] = copySubscription(*sub)
+	return nil
+}
+
+// GetSubscription retrieves a subscription by ID
+// IDでサブスクリプションを取得
+func (s *MemoryStore) GetSubscription(ctx context.Context, id string) (*Subscription, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sub, exists := s.subscriptions[id]
+	if !exists {
+		return nil, ErrSubscriptionNotFound
+	}
+	sub = copySubscription(sub)
+	return &sub, nil
+}
+
+// UpdateSubscription updates URL, event types, active flag and optionally the secret
+// URL・イベントタイプ・有効状態（指定時はシークレットも）を更新
+func (s *MemoryStore) UpdateSubscription(ctx context.Context, sub *Subscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, exists := s.subscriptions[sub.ID]
+	if !exists {
+		return ErrSubscriptionNotFound
+	}
+
+	current.URL = sub.URL
+	current.EventTypes = append([]string(nil), sub.EventTypes...)
+	current.Active = sub.Active
+	current.UpdatedAt = sub.UpdatedAt
+	if sub.Secret != "" {
+		current.Secret = sub.Secret
+	}
+	s.subscriptions[sub.ID] = current
+
+	return nil
+}
+
+// DeleteSubscription deletes a subscription and its delivery log
+// サブスクリプションと配信ログを削除
+func (s *MemoryStore) DeleteSubscription(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.subscriptions[id]; !exists {
+		return ErrSubscriptionNotFound
+	}
+	delete(s.subscriptions, id)
+
+	kept := s.deliveries[:0]
+	for _, delivery := range s.deliveries {
+		if delivery.SubscriptionID != id {
+			kept = append(kept, delivery)
+		}
+	}
+	s.deliveries = kept
+
+	return nil
+}
+
+// ListSubscriptions retrieves all subscriptions ordered by creation time
+// 全てのサブスクリプションを作成順に取得
+func (s *MemoryStore) ListSubscriptions(ctx context.Context) ([]Subscription, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	subs := make([]Subscription, 0, len(s.subscriptions))
+	for _, sub := range s.subscriptions {
+		subs = append(subs, copySubscription(sub))
+	}
+	sort.Slice(subs, func(i, j int) bool {
+		if !subs[i].CreatedAt.Equal(subs[j].CreatedAt) {
+			return subs[i].CreatedAt.Before(subs[j].CreatedAt)
+		}
+		return subs[i].ID < subs[j].ID
+	})
+
+	return subs, nil
+}
+
+// RecordDelivery records a delivery attempt
+// 配信試行を記録
+func (s *MemoryStore) RecordDelivery(ctx context.Context, delivery *Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deliveries = append(s.deliveries, *delivery)
+	return nil
+}
+
+// ListDeliveries retrieves the delivery log of a subscription, newest first
+// サブスクリプションの配信ログを新しい順に取得
+func (s *MemoryStore) ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]Delivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var deliveries []Delivery
+	for i := len(s.deliveries) - 1; i >= 0 && len(deliveries) < limit; i-- {
+		if s.deliveries[i].SubscriptionID == subscriptionID {
+			deliveries = append(deliveries, s.deliveries[i])
+		}
+	}
+
+	return deliveries, nil
+}
+
+// copySubscription returns a copy that does not share the event type slice
+// イベントタイプのスライスを共有しないコピーを返す
+func copySubscription(sub Subscription) Subscription {
+	sub.EventTypes = append([]string(nil), sub.EventTypes...)
+	return sub
+}
@@ -0,0 +1,230 @@
+package webhooks
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// PostgresStore implements Store using the webhook_subscriptions and webhook_deliveries tables
+// webhook_subscriptions・webhook_deliveriesテーブルを使用したStoreの実装
+type PostgresStore struct {
+	db *sql.DB
+}
+
+var _ Store = (*PostgresStore)(nil)
+
+// NewPostgresStore creates a store on an existing connection pool
+// 既存の接続プールを使用するストアを作成
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// CreateSubscription creates a new subscription
+// 新しいサブスクリプションを作成
+func (s *PostgresStore) CreateSubscription(ctx context.Context, sub *Subscription) error {
+	query := `
+		INSERT INTO webhook_subscriptions (id, url, event_types, secret, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err := s.db.ExecContext(ctx, query,
+		sub.ID,
+		sub.URL,
+		pq.Array(sub.EventTypes),
+		sub.Secret,
+		sub.Active,
+		sub.CreatedAt,
+		sub.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("Webhookサブスクリプション作成に失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// GetSubscription retrieves a subscription by ID
+// IDでサブスクリプションを取得
+func (s *PostgresStore) GetSubscription(ctx context.Context, id string) (*Subscription, error) {
+	query := `
+		SELECT id, url, event_types, secret, active, created_at, updated_at
+		FROM webhook_subscriptions
+		WHERE id = $1`
+
+	sub := &Subscription{}
+	err := s.db.QueryRowContext(ctx, query, id).Scan(
+		&sub.ID,
+		&sub.URL,
+		pq.Array(&sub.EventTypes),
+		&sub.Secret,
+		&sub.Active,
+		&sub.CreatedAt,
+		&sub.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("Webhookサブスクリプション取得に失敗しました: %w", err)
+	}
+
+	return sub, nil
+}
+
+// UpdateSubscription updates URL, event types, active flag and optionally the secret
+// URL・イベントタイプ・有効状態（指定時はシークレットも）を更新
+func (s *PostgresStore) UpdateSubscription(ctx context.Context, sub *Subscription) error {
+	query := `
+		UPDATE webhook_subscriptions
+		SET url = $2, event_types = $3, active = $4, updated_at = $5,
+		    secret = COALESCE(NULLIF($6, ''), secret)
+		WHERE id = $1`
+
+	result, err := s.db.ExecContext(ctx, query,
+		sub.ID,
+		sub.URL,
+		pq.Array(sub.EventTypes),
+		sub.Active,
+		sub.UpdatedAt,
+		sub.Secret,
+	)
+	if err != nil {
+		return fmt.Errorf("Webhookサブスクリプション更新に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("更新行数の取得に失敗しました: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrSubscriptionNotFound
+	}
+
+	return nil
+}
+
+// DeleteSubscription deletes a subscription; its delivery log is removed by cascade
+// サブスクリプションを削除（配信ログはカスケード削除）
+func (s *PostgresStore) DeleteSubscription(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("Webhookサブスクリプション削除に失敗しました: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("削除行数の取得に失敗しました: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrSubscriptionNotFound
+	}
+
+	return nil
+}
+
+// ListSubscriptions retrieves all subscriptions ordered by creation time
+// 全てのサブスクリプションを作成順に取得
+func (s *PostgresStore) ListSubscriptions(ctx context.Context) ([]Subscription, error) {
+	query := `
+		SELECT id, url, event_types, secret, active, created_at, updated_at
+		FROM webhook_subscriptions
+		ORDER BY created_at, id`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("Webhookサブスクリプション一覧取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []Subscription
+	for rows.Next() {
+		var sub Subscription
+		if err := rows.Scan(
+			&sub.ID,
+			&sub.URL,
+			pq.Array(&sub.EventTypes),
+			&sub.Secret,
+			&sub.Active,
+			&sub.CreatedAt,
+			&sub.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("Webhookサブスクリプション読み取りに失敗しました: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Webhookサブスクリプション読み取りに失敗しました: %w", err)
+	}
+
+	return subs, nil
+}
+
+// RecordDelivery records a delivery attempt
+// 配信試行を記録
+func (s *PostgresStore) RecordDelivery(ctx context.Context, delivery *Delivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (id, subscription_id, event_id, event_type, attempt, status_code, success, error, duration_ms, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	_, err := s.db.ExecContext(ctx, query,
+		delivery.ID,
+		delivery.SubscriptionID,
+		delivery.EventID,
+		delivery.EventType,
+		delivery.Attempt,
+		delivery.StatusCode,
+		delivery.Success,
+		delivery.Error,
+		delivery.DurationMs,
+		delivery.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("Webhook配信ログ記録に失敗しました: %w", err)
+	}
+
+	return nil
+}
+
+// ListDeliveries retrieves the delivery log of a subscription, newest first
+// サブスクリプションの配信ログを新しい順に取得
+func (s *PostgresStore) ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]Delivery, error) {
+	query := `
+		SELECT id, subscription_id, event_id, event_type, attempt, status_code, success, error, duration_ms, created_at
+		FROM webhook_deliveries
+		WHERE subscription_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2`
+
+	rows, err := s.db.QueryContext(ctx, query, subscriptionID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("Webhook配信ログ取得に失敗しました: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []Delivery
+	for rows.Next() {
+		var delivery Delivery
+		if err := rows.Scan(
+			&delivery.ID,
+			&delivery.SubscriptionID,
+			&delivery.EventID,
+			&delivery.EventType,
+			&delivery.Attempt,
+			&delivery.StatusCode,
+			&delivery.Success,
+			&delivery.Error,
+			&delivery.DurationMs,
+			&delivery.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("Webhook配信ログ読み取りに失敗しました: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Webhook配信ログ読み取りに失敗しました: %w", err)
+	}
+
+	return deliveries, nil
+}
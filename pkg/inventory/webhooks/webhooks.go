@@ -0,0 +1,134 @@
+// Package webhooks delivers inventory events to subscribed HTTP endpoints
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/events"
+)
+
+// Errors returned by Store implementations
+// Store実装が返すエラー
+var (
+	ErrSubscriptionNotFound = errors.New("Webhookサブスクリプションが見つかりません")
+)
+
+// ErrForbiddenAddress is recorded when a delivery would connect to a non-public address
+// 公開されていないアドレスへの配信を拒否した場合に記録するエラー
+var ErrForbiddenAddress = errors.New("配信先が公開されていないアドレスのため送信しません")
+
+// Subscription represents an endpoint subscribed to inventory events
+// 在庫イベントを購読するエンドポイントを表現
+type Subscription struct {
+	ID         string    `json:"id"`               // サブスクリプションID
+	URL        string    `json:"url"`              // 配信先URL
+	EventTypes []string  `json:"event_types"`      // 購読するイベントタイプ（空または "*" で全イベント）
+	Secret     string    `json:"secret,omitempty"` // 署名用シークレット（作成時のみ返却）
+	Active     bool      `json:"active"`           // 有効状態
+	CreatedAt  time.Time `json:"created_at"`       // 作成日時
+	UpdatedAt  time.Time `json:"updated_at"`       // 更新日時
+}
+
+// Delivery records a single delivery attempt
+// 1回の配信試行の記録
+type Delivery struct {
+	ID             string    `json:"id"`                    // 配信記録ID
+	SubscriptionID string    `json:"subscription_id"`       // サブスクリプションID
+	EventID        string    `json:"event_id"`              // イベントID
+	EventType      string    `json:"event_type"`            // イベントタイプ
+	Attempt        int       `json:"attempt"`               // 試行回数（1始まり）
+	StatusCode     int       `json:"status_code,omitempty"` // HTTPステータスコード（接続エラー時は0）
+	Success        bool      `json:"success"`               // 2xxが返ったか
+	Error          string    `json:"error,omitempty"`       // エラー内容
+	DurationMs     int64     `json:"duration_ms"`           // 所要時間（ミリ秒）
+	CreatedAt      time.Time `json:"created_at"`            // 試行日時
+}
+
+// Store persists subscriptions and the delivery log
+// サブスクリプションと配信ログを永続化
+type Store interface {
+	// 新しいサブスクリプションを作成します
+	CreateSubscription(ctx context.Context, sub *Subscription) error
+	// 指定されたIDのサブスクリプションを取得します。存在しない場合はErrSubscriptionNotFoundを返します
+	GetSubscription(ctx context.Context, id string) (*Subscription, error)
+	// URL・イベントタイプ・有効状態を更新します（シークレットは空の場合は変更しません）
+	UpdateSubscription(ctx context.Context, sub *Subscription) error
+	// 指定されたIDのサブスクリプションと配信ログを削除します
+	DeleteSubscription(ctx context.Context, id string) error
+	// 全てのサブスクリプションを作成順に取得します
+	ListSubscriptions(ctx context.Context) ([]Subscription, error)
+	// 配信試行を記録します
+	RecordDelivery(ctx context.Context, delivery *Delivery) error
+	// 指定されたサブスクリプションの配信ログを新しい順に取得します
+	ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]Delivery, error)
+}
+
+// Validate checks the subscription URL and event types
+// サブスクリプションのURLとイベントタイプを検証
+func (s *Subscription) Validate() error {
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("無効な配信先URLです: %s", s.URL)
+	}
+	for _, eventType := range s.EventTypes {
+		if eventType != "*" && !events.IsKnownType(eventType) {
+			return fmt.Errorf("不明なイベントタイプです: %s", eventType)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the subscription receives events of eventType
+// サブスクリプションがイベントタイプを購読しているかを判定
+func (s *Subscription) Matches(eventType string) bool {
+	if !s.Active {
+		return false
+	}
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range s.EventTypes {
+		if t == "*" || t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// NewSecret generates a random signing secret
+// ランダムな署名用シークレットを生成
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("シークレットの生成に失敗しました: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Sign computes the signature sent in the X-Webhook-Signature header
+// X-Webhook-Signatureヘッダーで送信する署名を計算
+//
+// 署名は "sha256=" に続けて、"<タイムスタンプ>.<リクエスト本文>" のHMAC-SHA256を16進数で表したものです。
+// 受信側は同じ計算を行い、hmac.Equalで比較してください。
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature produced by Sign
+// Signで作成された署名を検証
+func Verify(secret string, timestamp time.Time, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
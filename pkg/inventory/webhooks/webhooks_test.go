@@ -0,0 +1,222 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/nemonet1337/zaiGoFramework/pkg/inventory/events"
+)
+
+// TestSignAndVerify は署名と検証のテスト
+func TestSignAndVerify(t *testing.T) {
+	ts := time.Unix(1700000000, 0)
+	body := []byte(`{"id":"EV-1"}`)
+
+	signature := Sign("secret", ts, body)
+	assert.Contains(t, signature, "sha256=")
+	assert.True(t, Verify("secret", ts, body, signature))
+	assert.False(t, Verify("other", ts, body, signature))
+	assert.False(t, Verify("secret", ts.Add(time.Second), body, signature))
+	assert.False(t, Verify("secret", ts, []byte(`{"id":"EV-2"}`), signature))
+}
+
+// TestSubscription_ValidateAndMatches はサブスクリプションの検証と購読判定のテスト
+func TestSubscription_ValidateAndMatches(t *testing.T) {
+	sub := Subscription{URL: "https://example.com/hook", EventTypes: []string{events.TypeStockChanged}, Active: true}
+	require.NoError(t, sub.Validate())
+	assert.True(t, sub.Matches(events.TypeStockChanged))
+	assert.False(t, sub.Matches(events.TypeLowStockAlert))
+
+	sub.EventTypes = nil
+	assert.True(t, sub.Matches(events.TypeLowStockAlert))
+
+	sub.Active = false
+	assert.False(t, sub.Matches(events.TypeStockChanged))
+
+	assert.Error(t, (&Subscription{URL: "ftp://example.com"}).Validate())
+	assert.Error(t, (&Subscription{URL: "https://example.com", EventTypes: []string{"unknown"}}).Validate())
+}
+
+// TestDispatcher_RetriesAndRecordsDeliveries は再試行と配信ログのテスト
+func TestDispatcher_RetriesAndRecordsDeliveries(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests int
+		received = make(chan struct{})
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		unix, _ := strconv.ParseInt(r.Header.Get("X-Webhook-Timestamp"), 10, 64)
+		assert.True(t, Verify("secret", time.Unix(unix, 0), body, r.Header.Get("X-Webhook-Signature")))
+		assert.Equal(t, events.TypeStockChanged, r.Header.Get("X-Webhook-Event"))
+
+		var p payload
+		assert.NoError(t, json.Unmarshal(body, &p))
+		assert.Equal(t, "EV-1", p.ID)
+
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		// 1回目は失敗させて再試行させる
+		if requests == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		close(received)
+	}))
+	defer srv.Close()
+
+	store := NewMemoryStore()
+	ctx := context.Background()
+	require.NoError(t, store.CreateSubscription(ctx, &Subscription{
+		ID: "WH-1", URL: srv.URL, EventTypes: []string{events.TypeStockChanged}, Secret: "secret", Active: true,
+	}))
+	require.NoError(t, store.CreateSubscription(ctx, &Subscription{
+		ID: "WH-2", URL: srv.URL, EventTypes: []string{events.TypeLowStockAlert}, Secret: "secret", Active: true,
+	}))
+
+	dispatcher := NewDispatcher(store, DispatcherConfig{
+		Workers:   1,
+		BaseDelay: 10 * time.Millisecond,
+		MaxDelay:  20 * time.Millisecond,
+		// テストサーバーはループバックアドレスで待ち受ける
+		AllowPrivateNetworks: true,
+	}, zap.NewNop())
+
+	require.NoError(t, dispatcher.Send(ctx, events.Message{
+		ID: "EV-1", Type: events.TypeStockChanged, ItemID: "ITEM-1", Body: []byte(`{"item_id":"ITEM-1"}`), Timestamp: time.Now(),
+	}))
+
+	select {
+	case <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Webhookが配信されませんでした")
+	}
+	require.NoError(t, dispatcher.Close())
+
+	deliveries, err := store.ListDeliveries(ctx, "WH-1", 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	assert.True(t, deliveries[0].Success)
+	assert.Equal(t, 2, deliveries[0].Attempt)
+	assert.False(t, deliveries[1].Success)
+	assert.Equal(t, http.StatusInternalServerError, deliveries[1].StatusCode)
+
+	// 購読していないサブスクリプションには配信されない
+	deliveries, err = store.ListDeliveries(ctx, "WH-2", 10)
+	require.NoError(t, err)
+	assert.Empty(t, deliveries)
+
+	assert.Error(t, dispatcher.Send(ctx, events.Message{ID: "EV-2", Type: events.TypeStockChanged}))
+}
+
+// TestPublicAddress は配信を許可するアドレスの判定のテスト
+func TestPublicAddress(t *testing.T) {
+	tests := []struct {
+		ip       string
+		expected bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"10.0.0.1", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"fd00::1", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"::ffff:127.0.0.1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			assert.Equal(t, tt.expected, publicAddress(net.ParseIP(tt.ip)))
+		})
+	}
+}
+
+// deliverOnce は1回のみ試行するディスパッチャーで配信し、配信ログを返す
+func deliverOnce(t *testing.T, url string, allowPrivate bool) Delivery {
+	t.Helper()
+
+	store := NewMemoryStore()
+	ctx := context.Background()
+	require.NoError(t, store.CreateSubscription(ctx, &Subscription{ID: "WH-1", URL: url, Secret: "secret", Active: true}))
+	dispatcher := NewDispatcher(store, DispatcherConfig{Workers: 1, MaxAttempts: 1, AllowPrivateNetworks: allowPrivate}, zap.NewNop())
+	require.NoError(t, dispatcher.Send(ctx, events.Message{ID: "EV-1", Type: events.TypeStockChanged, Body: []byte(`{}`), Timestamp: time.Now()}))
+	require.NoError(t, dispatcher.Close())
+
+	deliveries, err := store.ListDeliveries(ctx, "WH-1", 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	return deliveries[0]
+}
+
+// TestDispatcher_RefusesPrivateAddresses は名前解決後の接続先が公開されていないアドレスの場合に送信しないテスト
+func TestDispatcher_RefusesPrivateAddresses(t *testing.T) {
+	var requested bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+	}))
+	defer srv.Close()
+
+	// ホスト名で指定した場合も接続するアドレスで判定する
+	url := strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)
+	delivery := deliverOnce(t, url, false)
+	assert.False(t, delivery.Success)
+	assert.Contains(t, delivery.Error, ErrForbiddenAddress.Error())
+	assert.False(t, requested)
+}
+
+// TestDispatcher_DoesNotFollowRedirects はリダイレクトに従わず配信失敗とするテスト
+func TestDispatcher_DoesNotFollowRedirects(t *testing.T) {
+	var redirected bool
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected = true
+	}))
+	defer target.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer srv.Close()
+
+	delivery := deliverOnce(t, srv.URL, true)
+	assert.False(t, delivery.Success)
+	assert.Equal(t, http.StatusTemporaryRedirect, delivery.StatusCode)
+	assert.False(t, redirected)
+}
+
+// TestMemoryStore_UpdateKeepsSecret はシークレット省略時の更新のテスト
+func TestMemoryStore_UpdateKeepsSecret(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	require.NoError(t, store.CreateSubscription(ctx, &Subscription{ID: "WH-1", URL: "https://example.com", Secret: "secret", Active: true}))
+
+	require.NoError(t, store.UpdateSubscription(ctx, &Subscription{ID: "WH-1", URL: "https://example.org", Active: false}))
+	sub, err := store.GetSubscription(ctx, "WH-1")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.org", sub.URL)
+	assert.Equal(t, "secret", sub.Secret)
+	assert.False(t, sub.Active)
+
+	assert.ErrorIs(t, store.UpdateSubscription(ctx, &Subscription{ID: "missing"}), ErrSubscriptionNotFound)
+	require.NoError(t, store.DeleteSubscription(ctx, "WH-1"))
+	_, err = store.GetSubscription(ctx, "WH-1")
+	assert.ErrorIs(t, err, ErrSubscriptionNotFound)
+}